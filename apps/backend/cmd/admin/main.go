@@ -0,0 +1,369 @@
+// Command admin runs data maintenance tasks against the realty-core database through
+// the same service layer as the API.
+//
+// Usage:
+//
+//	admin <command> [flags]
+//
+// Configuration is read from the environment (and a .env file) like the API server.
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"realty-core/internal/backup"
+	"realty-core/internal/config"
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/search"
+	"realty-core/internal/service"
+)
+
+// command is an admin subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"create-admin-user", "create an administrator account", createAdminUser},
+	{"reindex-search", "rebuild the search index of every property", reindexSearch},
+	{"recalculate-slugs", "regenerate property slugs that no longer match their title", recalculateSlugs},
+	{"sanitize-descriptions", "sanitize property descriptions stored before sanitization", sanitizeDescriptions},
+	{"backfill-price-per-m2", "fill the missing price per m² of properties", backfillPricePerM2},
+	{"purge-soft-deleted", "permanently delete long deactivated users and agencies", purgeSoftDeleted},
+	{"cache-flush", "flush the caches of a running API server", cacheFlush},
+	{"backup-verify", "restore the latest dump to a temporary schema and check it", backupVerify},
+}
+
+func main() {
+	_ = godotenv.Load()
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "help" {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatalf("%s: %v", cmd.name, err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: admin <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'admin <command> -h' for the flags of a command.")
+}
+
+// app holds the services shared by the database commands
+type app struct {
+	cfg         *config.Config
+	db          *sql.DB
+	indexer     *search.Indexer
+	maintenance *service.MaintenanceService
+}
+
+// openApp connects to the database and wires the services like the API server does
+func openApp() (*app, error) {
+	cfg := config.LoadConfig()
+	if _, err := cfg.LoadSecrets(); err != nil {
+		return nil, err
+	}
+	db, err := repository.ConnectDatabase(cfg.Database.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.New(os.Stdout, "[admin] ", log.LstdFlags)
+	propertyRepo := repository.NewPostgreSQLPropertyRepository(db)
+	properties := service.NewPropertyService(propertyRepo, repository.NewPostgreSQLImageRepository(db))
+	users := service.NewUserService(repository.NewUserRepository(db), repository.NewAgencyRepository(db), logger)
+
+	a := &app{cfg: cfg, db: db}
+	if !strings.EqualFold(cfg.Search.Backend, search.BackendPostgres) && cfg.Search.Backend != "" {
+		index, err := search.NewIndex(search.Config{
+			Backend:           cfg.Search.Backend,
+			MeilisearchURL:    cfg.Search.MeilisearchURL,
+			MeilisearchAPIKey: cfg.Search.MeilisearchAPIKey,
+			IndexName:         cfg.Search.IndexName,
+		}, propertyRepo)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		a.indexer = search.NewIndexer(index, cfg.Search.IndexQueueSize)
+		properties.SetSearchIndexer(a.indexer)
+	}
+
+	a.maintenance = service.NewMaintenanceService(repository.NewPostgreSQLMaintenanceRepository(db), properties, users, logger)
+	return a, nil
+}
+
+// close releases the database connection
+func (a *app) close() {
+	a.db.Close()
+}
+
+// withApp runs fn with the wired services
+func withApp(fn func(a *app) error) error {
+	a, err := openApp()
+	if err != nil {
+		return err
+	}
+	defer a.close()
+	return fn(a)
+}
+
+func createAdminUser(args []string) error {
+	flags := flag.NewFlagSet("create-admin-user", flag.ExitOnError)
+	email := flags.String("email", "", "email of the administrator (required)")
+	firstName := flags.String("first-name", "", "first name (required)")
+	lastName := flags.String("last-name", "", "last name (required)")
+	phone := flags.String("phone", "", "phone number (required)")
+	cedula := flags.String("cedula", "", "Ecuadorian national ID (required)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin create-admin-user -email ... -first-name ... -last-name ... -phone ... -cedula ...")
+		fmt.Fprintln(os.Stderr, "The password is read from ADMIN_PASSWORD or, when unset, from the first line of stdin.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	password, err := readPassword(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	return withApp(func(a *app) error {
+		user, err := a.maintenance.CreateAdminUser(*firstName, *lastName, *email, *phone, *cedula, password)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created admin user %s (%s)\n", user.Email, user.ID)
+		return nil
+	})
+}
+
+// readPassword returns ADMIN_PASSWORD, or the first line of in so passwords stay out of
+// the shell history
+func readPassword(in io.Reader) (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("error reading password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password is required")
+	}
+	return password, nil
+}
+
+func reindexSearch(args []string) error {
+	flags := flag.NewFlagSet("reindex-search", flag.ExitOnError)
+	flags.Parse(args)
+
+	return withApp(func(a *app) error {
+		started := time.Now()
+		count, err := a.maintenance.ReindexSearch()
+		if err != nil {
+			return err
+		}
+		backend := a.cfg.Search.Backend
+		if backend == "" {
+			backend = search.BackendPostgres
+		}
+		fmt.Printf("Reindexed %d properties in %s (%s)\n", count, backend, time.Since(started).Round(time.Millisecond))
+		return nil
+	})
+}
+
+func recalculateSlugs(args []string) error {
+	flags := flag.NewFlagSet("recalculate-slugs", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report the slugs that would change without updating them")
+	flags.Parse(args)
+
+	return withApp(func(a *app) error {
+		report, err := a.maintenance.RecalculateSlugs(*dryRun)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+	})
+}
+
+func sanitizeDescriptions(args []string) error {
+	flags := flag.NewFlagSet("sanitize-descriptions", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report the descriptions that would change without updating them")
+	flags.Parse(args)
+
+	return withApp(func(a *app) error {
+		report, err := a.maintenance.SanitizeDescriptions(*dryRun)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+	})
+}
+
+func backfillPricePerM2(args []string) error {
+	flags := flag.NewFlagSet("backfill-price-per-m2", flag.ExitOnError)
+	flags.Parse(args)
+
+	return withApp(func(a *app) error {
+		count, err := a.maintenance.BackfillPricePerM2()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Filled the price per m2 of %d properties\n", count)
+		return nil
+	})
+}
+
+func purgeSoftDeleted(args []string) error {
+	flags := flag.NewFlagSet("purge-soft-deleted", flag.ExitOnError)
+	olderThan := flags.Duration("older-than", domain.DefaultPurgeRetention, "only purge accounts deactivated longer than this ago")
+	dryRun := flags.Bool("dry-run", false, "report what would be purged without deleting")
+	flags.Parse(args)
+
+	return withApp(func(a *app) error {
+		report, err := a.maintenance.PurgeDeactivated(*olderThan, *dryRun)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+	})
+}
+
+// backupVerify restores the latest dump and checks it, failing when the dump is broken
+// so cron and CI alert on it; results are also reported by /api/monitoring/backups
+func backupVerify(args []string) error {
+	flags := flag.NewFlagSet("backup-verify", flag.ExitOnError)
+	dir := flags.String("dir", "", "directory of the dumps (default BACKUP_DIR)")
+	keepSchema := flags.Bool("keep-schema", false, "keep the restored schema for inspection instead of dropping it")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin backup-verify [-dir DIR] [-keep-schema]")
+		fmt.Fprintln(os.Stderr, "The dump is restored with psql (and pg_restore for custom format dumps) into a")
+		fmt.Fprintln(os.Stderr, "backup_verify_* schema of the database, so they must be installed.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	return withApp(func(a *app) error {
+		cfg := a.cfg.Backup
+		if *dir != "" {
+			cfg.Dir = *dir
+		}
+		restorer := backup.NewPsqlRestorer(a.cfg.Database.URL, cfg.PsqlPath, cfg.PgRestorePath, cfg.RestoreTimeout)
+		verifier := service.NewBackupVerificationService(repository.NewPostgreSQLBackupRepository(a.db), restorer,
+			service.BackupVerificationConfig{
+				Dir:         cfg.Dir,
+				MaxAge:      cfg.MaxAge,
+				MinRowRatio: cfg.MinRowRatio,
+				KeepSchema:  *keepSchema,
+			}, log.New(os.Stderr, "[admin] ", log.LstdFlags))
+
+		verification, err := verifier.Verify(context.Background())
+		if verification != nil {
+			if err := printJSON(verification); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if !verification.Passed() {
+			return fmt.Errorf("backup %s failed verification", verification.Dump.Name)
+		}
+		return nil
+	})
+}
+
+// cacheFlush asks a running server to flush its caches; they live in the API process,
+// so this is the only command that goes through HTTP instead of the database
+func cacheFlush(args []string) error {
+	flags := flag.NewFlagSet("cache-flush", flag.ExitOnError)
+	apiURL := flags.String("url", envOr("ADMIN_API_URL", "http://localhost:"+envOr("PORT", "8080")), "base URL of the API server")
+	cache := flags.String("cache", "", "cache to flush: properties or images (default all)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: admin cache-flush [-url URL] [-cache properties|images]")
+		fmt.Fprintln(os.Stderr, "An admin access token is read from ADMIN_API_TOKEN.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	token := os.Getenv("ADMIN_API_TOKEN")
+	if token == "" {
+		return fmt.Errorf("ADMIN_API_TOKEN is required")
+	}
+
+	endpoint := strings.TrimRight(*apiURL, "/") + "/api/admin/maintenance/cache/flush"
+	if *cache != "" {
+		endpoint += "?cache=" + *cache
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting API server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	fmt.Println(strings.TrimSpace(string(body)))
+	return nil
+}
+
+func printJSON(data interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -2,10 +2,14 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // TokenPair represents access and refresh tokens
@@ -14,6 +18,7 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
 	TokenType    string `json:"token_type"`
+	SessionID    string `json:"session_id"`
 }
 
 // Claims represents JWT claims with user information
@@ -22,18 +27,29 @@ type Claims struct {
 	Email    string `json:"email"`
 	Role     string `json:"role"`
 	AgencyID string `json:"agency_id,omitempty"`
+	// SessionID names the session the token was issued for, so revoking the session
+	// stops its access tokens too
+	SessionID string `json:"sid,omitempty"`
+	// TenantID names the tenant the user signed in to; requests resolved to another
+	// tenant are refused with the token
+	TenantID string `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // RefreshClaims represents refresh token claims
 type RefreshClaims struct {
-	UserID string `json:"user_id"`
+	UserID   string `json:"user_id"`
+	TenantID string `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // JWTManager handles JWT token operations
 type JWTManager struct {
-	secretKey        []byte
+	keysMutex        sync.RWMutex
+	signingKeyID     string
+	keys             map[string][]byte // verification keys by key ID
+	signingKeys      map[string]*SigningKey // asymmetric keys by key ID
+	currentKey       *SigningKey            // signs new tokens instead of the HMAC key when set
 	accessTokenTTL   time.Duration
 	refreshTokenTTL  time.Duration
 	issuer           string
@@ -43,16 +59,166 @@ type JWTManager struct {
 // NewJWTManager creates a new JWT manager
 func NewJWTManager(secretKey string, accessTTL, refreshTTL time.Duration, issuer string) *JWTManager {
 	return &JWTManager{
-		secretKey:        []byte(secretKey),
+		signingKeyID:     DefaultKeyID,
+		keys:             map[string][]byte{DefaultKeyID: []byte(secretKey)},
+		accessTokenTTL:   accessTTL,
+		refreshTokenTTL:  refreshTTL,
+		issuer:           issuer,
+		blacklistedTokens: make(map[string]bool),
+	}
+}
+
+// NewJWTManagerWithKeys creates a JWT manager that signs with the signing key of a
+// key set and accepts tokens signed with any of its keys
+func NewJWTManagerWithKeys(keys KeySet, accessTTL, refreshTTL time.Duration, issuer string) (*JWTManager, error) {
+	manager := &JWTManager{
 		accessTokenTTL:   accessTTL,
 		refreshTokenTTL:  refreshTTL,
 		issuer:           issuer,
 		blacklistedTokens: make(map[string]bool),
 	}
+	if err := manager.SetKeys(keys); err != nil {
+		return nil, err
+	}
+	return manager, nil
+}
+
+// SetKeys replaces the signing and verification keys, e.g. when the JWT secret is
+// rotated in the secrets manager. Tokens signed with a key left out of the set stop
+// validating.
+func (j *JWTManager) SetKeys(keys KeySet) error {
+	if err := keys.Validate(); err != nil {
+		return err
+	}
+
+	verificationKeys := make(map[string][]byte, len(keys.Keys))
+	for id, key := range keys.Keys {
+		verificationKeys[id] = []byte(key)
+	}
+
+	j.keysMutex.Lock()
+	defer j.keysMutex.Unlock()
+	j.signingKeyID = keys.SigningKeyID
+	j.keys = verificationKeys
+	return nil
+}
+
+// SetSigningKeys replaces the asymmetric signing keys. New tokens are signed with the
+// newest key that is not retired; retired keys keep verifying tokens until they
+// expire. Without a current key tokens are signed with the HMAC key set again.
+func (j *JWTManager) SetSigningKeys(keys []*SigningKey) error {
+	signingKeys := make(map[string]*SigningKey, len(keys))
+	var current *SigningKey
+	for _, key := range keys {
+		if key == nil || key.ID == "" || key.PrivateKey == nil || !IsAsymmetricAlgorithm(key.Algorithm) {
+			return errors.New("invalid signing key")
+		}
+		signingKeys[key.ID] = key
+		if key.RetiredAt == nil && (current == nil || key.CreatedAt.After(current.CreatedAt)) {
+			current = key
+		}
+	}
+
+	j.keysMutex.Lock()
+	defer j.keysMutex.Unlock()
+	j.signingKeys = signingKeys
+	j.currentKey = current
+	return nil
+}
+
+// SigningKeyID returns the ID of the key new tokens are signed with
+func (j *JWTManager) SigningKeyID() string {
+	j.keysMutex.RLock()
+	defer j.keysMutex.RUnlock()
+	if j.currentKey != nil {
+		return j.currentKey.ID
+	}
+	return j.signingKeyID
 }
 
-// GenerateTokenPair creates access and refresh tokens for a user
-func (j *JWTManager) GenerateTokenPair(userID, email, role, agencyID string) (*TokenPair, error) {
+// JWKS returns the public keys that verify tokens, newest first. HMAC keys are never
+// published, so the set is empty while tokens are signed with HS256.
+func (j *JWTManager) JWKS() JWKSet {
+	j.keysMutex.RLock()
+	defer j.keysMutex.RUnlock()
+
+	now := time.Now()
+	keys := make([]*SigningKey, 0, len(j.signingKeys))
+	for _, key := range j.signingKeys {
+		if key.IsValidAt(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a].CreatedAt.After(keys[b].CreatedAt) })
+
+	set := JWKSet{Keys: make([]JWK, len(keys))}
+	for i, key := range keys {
+		set.Keys[i] = key.PublicJWK()
+	}
+	return set
+}
+
+// sign signs claims with the current signing key and names it in the kid header
+func (j *JWTManager) sign(claims jwt.Claims) (string, error) {
+	j.keysMutex.RLock()
+	current := j.currentKey
+	keyID, key := j.signingKeyID, j.keys[j.signingKeyID]
+	j.keysMutex.RUnlock()
+
+	if current != nil {
+		token := jwt.NewWithClaims(current.signingMethod(), claims)
+		token.Header["kid"] = current.ID
+		return token.SignedString(current.PrivateKey)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(key)
+}
+
+// verificationKey returns the key named by a token's kid header. HMAC tokens without
+// kid predate key IDs and were signed with the DefaultKeyID key; asymmetric tokens
+// must name a key of their algorithm that has not expired.
+func (j *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	j.keysMutex.RLock()
+	defer j.keysMutex.RUnlock()
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		keyID := DefaultKeyID
+		if kid != "" {
+			keyID = kid
+		}
+		key, ok := j.keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %s", keyID)
+		}
+		return key, nil
+	case *jwt.SigningMethodEd25519, *jwt.SigningMethodRSA:
+		key, ok := j.signingKeys[kid]
+		if !ok || key.Algorithm != token.Method.Alg() {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+		if !key.IsValidAt(time.Now()) {
+			return nil, fmt.Errorf("signing key has expired: %s", kid)
+		}
+		return key.PrivateKey.Public(), nil
+	default:
+		return nil, errors.New("unexpected signing method")
+	}
+}
+
+// GenerateTokenPair creates access and refresh tokens for a user of a tenant, starting
+// a new session. The tenant is empty in single-tenant installs.
+func (j *JWTManager) GenerateTokenPair(userID, email, role, agencyID, tenantID string) (*TokenPair, error) {
+	return j.generateTokenPair(userID, email, role, agencyID, tenantID, uuid.New().String())
+}
+
+// generateTokenPair creates access and refresh tokens for a session. The session ID
+// is the ID (jti) of the refresh token and is kept across refreshes.
+func (j *JWTManager) generateTokenPair(userID, email, role, agencyID, tenantID, sessionID string) (*TokenPair, error) {
 	now := time.Now()
 	
 	// Create access token claims
@@ -61,6 +227,8 @@ func (j *JWTManager) GenerateTokenPair(userID, email, role, agencyID string) (*T
 		Email:    email,
 		Role:     role,
 		AgencyID: agencyID,
+		SessionID: sessionID,
+		TenantID: tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.accessTokenTTL)),
@@ -72,8 +240,10 @@ func (j *JWTManager) GenerateTokenPair(userID, email, role, agencyID string) (*T
 	
 	// Create refresh token claims
 	refreshClaims := &RefreshClaims{
-		UserID: userID,
+		UserID:   userID,
+		TenantID: tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.refreshTokenTTL)),
 			NotBefore: jwt.NewNumericDate(now),
@@ -83,15 +253,13 @@ func (j *JWTManager) GenerateTokenPair(userID, email, role, agencyID string) (*T
 	}
 	
 	// Generate access token
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(j.secretKey)
+	accessTokenString, err := j.sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
 	
 	// Generate refresh token
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString(j.secretKey)
+	refreshTokenString, err := j.sign(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +269,7 @@ func (j *JWTManager) GenerateTokenPair(userID, email, role, agencyID string) (*T
 		RefreshToken: refreshTokenString,
 		ExpiresIn:    int64(j.accessTokenTTL.Seconds()),
 		TokenType:    "Bearer",
+		SessionID:    sessionID,
 	}, nil
 }
 
@@ -111,12 +280,7 @@ func (j *JWTManager) ValidateAccessToken(tokenString string) (*Claims, error) {
 		return nil, errors.New("token is blacklisted")
 	}
 	
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey)
 	
 	if err != nil {
 		return nil, err
@@ -140,12 +304,7 @@ func (j *JWTManager) ValidateRefreshToken(tokenString string) (*RefreshClaims, e
 		return nil, errors.New("refresh token is blacklisted")
 	}
 	
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, j.verificationKey)
 	
 	if err != nil {
 		return nil, err
@@ -162,7 +321,8 @@ func (j *JWTManager) ValidateRefreshToken(tokenString string) (*RefreshClaims, e
 	return nil, errors.New("invalid refresh token")
 }
 
-// RefreshAccessToken creates a new access token using a valid refresh token
+// RefreshAccessToken creates a new access token using a valid refresh token, in the
+// tenant the refresh token was issued in
 func (j *JWTManager) RefreshAccessToken(refreshTokenString string, email, role, agencyID string) (*TokenPair, error) {
 	// Validate refresh token
 	refreshClaims, err := j.ValidateRefreshToken(refreshTokenString)
@@ -170,8 +330,18 @@ func (j *JWTManager) RefreshAccessToken(refreshTokenString string, email, role,
 		return nil, err
 	}
 	
-	// Generate new token pair
-	return j.GenerateTokenPair(refreshClaims.UserID, email, role, agencyID)
+	// Generate new token pair for the same session; tokens issued before sessions
+	// existed start a new one
+	sessionID := refreshClaims.ID
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	return j.generateTokenPair(refreshClaims.UserID, email, role, agencyID, refreshClaims.TenantID, sessionID)
+}
+
+// RefreshTokenTTL returns how long refresh tokens, and so sessions, last
+func (j *JWTManager) RefreshTokenTTL() time.Duration {
+	return j.refreshTokenTTL
 }
 
 // BlacklistToken adds a token to the blacklist
@@ -210,6 +380,8 @@ type TokenInfo struct {
 	Email    string
 	Role     string
 	AgencyID string
+	SessionID string
+	TenantID string
 	IsValid  bool
 }
 
@@ -225,6 +397,8 @@ func (j *JWTManager) ParseTokenInfo(tokenString string) *TokenInfo {
 		Email:    claims.Email,
 		Role:     claims.Role,
 		AgencyID: claims.AgencyID,
+		SessionID: claims.SessionID,
+		TenantID: claims.TenantID,
 		IsValid:  true,
 	}
 }
@@ -236,9 +410,7 @@ func (j *JWTManager) CleanupExpiredTokens() {
 	
 	for tokenString := range j.blacklistedTokens {
 		// Parse token to check expiration
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return j.secretKey, nil
-		})
+		token, err := jwt.Parse(tokenString, j.verificationKey)
 		
 		if err != nil || !token.Valid {
 			delete(j.blacklistedTokens, tokenString)
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeySet(t *testing.T) {
+	keys, err := ParseKeySet("plain-secret")
+	require.NoError(t, err)
+	assert.Equal(t, SingleKeySet("plain-secret"), keys)
+
+	keys, err = ParseKeySet(`{"signing_key_id":"2025-08","keys":{"2025-08":"new","2025-05":"old"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08", keys.SigningKeyID)
+	assert.Len(t, keys.Keys, 2)
+
+	_, err = ParseKeySet(`{"signing_key_id":"2025-09","keys":{"2025-08":"new"}}`)
+	assert.ErrorContains(t, err, "signing key 2025-09 not found")
+
+	_, err = ParseKeySet(`{"signing_key_id":"2025-08","keys":{"2025-08":""}}`)
+	assert.ErrorContains(t, err, "cannot be empty")
+
+	_, err = ParseKeySet(`{"signing_key_id":`)
+	assert.ErrorContains(t, err, "invalid JWT key set")
+}
+
+func TestJWTManager_KeyIDHeader(t *testing.T) {
+	manager, err := NewJWTManagerWithKeys(KeySet{SigningKeyID: "2025-08", Keys: map[string]string{"2025-08": "new"}}, time.Minute, time.Hour, "test")
+	require.NoError(t, err)
+
+	pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "agency-1", "")
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(pair.AccessToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08", token.Header["kid"])
+
+	claims, err := manager.ValidateAccessToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	_, err = manager.ValidateRefreshToken(pair.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTManager_RefreshKeepsSession(t *testing.T) {
+	manager := NewJWTManager("secret", time.Minute, time.Hour, "test")
+
+	pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.SessionID)
+
+	claims, err := manager.ValidateRefreshToken(pair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, pair.SessionID, claims.ID)
+	assert.Equal(t, pair.SessionID, manager.ParseTokenInfo(pair.AccessToken).SessionID, "access tokens name their session")
+
+	refreshed, err := manager.RefreshAccessToken(pair.RefreshToken, "user@example.com", "agent", "")
+	require.NoError(t, err)
+	assert.Equal(t, pair.SessionID, refreshed.SessionID)
+
+	other, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+	assert.NotEqual(t, pair.SessionID, other.SessionID, "every login starts a session")
+}
+
+func TestJWTManager_TenantClaim(t *testing.T) {
+	manager := NewJWTManager("secret", time.Minute, time.Hour, "test")
+
+	pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "tenant-a")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-a", manager.ParseTokenInfo(pair.AccessToken).TenantID)
+
+	refreshed, err := manager.RefreshAccessToken(pair.RefreshToken, "user@example.com", "agent", "")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-a", manager.ParseTokenInfo(refreshed.AccessToken).TenantID, "refreshed tokens keep their tenant")
+}
+
+func TestJWTManager_SetKeysRotation(t *testing.T) {
+	manager := NewJWTManager("old-secret", time.Minute, time.Hour, "test")
+	legacy := signWithoutKeyID(t, "old-secret")
+
+	oldPair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+
+	// Rotate: sign with the new key, keep the old one for verification
+	require.NoError(t, manager.SetKeys(KeySet{SigningKeyID: "2025-08", Keys: map[string]string{"2025-08": "new-secret", DefaultKeyID: "old-secret"}}))
+	assert.Equal(t, "2025-08", manager.SigningKeyID())
+
+	newPair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+
+	for _, token := range []string{oldPair.AccessToken, newPair.AccessToken, legacy} {
+		_, err := manager.ValidateAccessToken(token)
+		assert.NoError(t, err)
+	}
+
+	// Retire the old key
+	require.NoError(t, manager.SetKeys(KeySet{SigningKeyID: "2025-08", Keys: map[string]string{"2025-08": "new-secret"}}))
+
+	_, err = manager.ValidateAccessToken(oldPair.AccessToken)
+	assert.ErrorContains(t, err, "unknown signing key")
+	_, err = manager.ValidateAccessToken(legacy)
+	assert.Error(t, err)
+	_, err = manager.ValidateAccessToken(newPair.AccessToken)
+	assert.NoError(t, err)
+
+	assert.Error(t, manager.SetKeys(KeySet{SigningKeyID: "missing"}))
+	assert.Equal(t, "2025-08", manager.SigningKeyID(), "invalid key sets are not applied")
+}
+
+func TestJWTManager_RejectsForgedKeyID(t *testing.T) {
+	manager := NewJWTManager("secret", time.Minute, time.Hour, "test")
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	token.Header["kid"] = DefaultKeyID
+	forged, err := token.SignedString([]byte("attacker"))
+	require.NoError(t, err)
+
+	_, err = manager.ValidateAccessToken(forged)
+	assert.Error(t, err)
+}
+
+// signWithoutKeyID signs an access token the way tokens were issued before key IDs
+func signWithoutKeyID(t *testing.T, secret string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
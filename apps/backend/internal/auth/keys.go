@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultKeyID identifies the signing key configured as a single secret. Tokens issued
+// before key IDs were introduced carry no kid and are verified with this key.
+const DefaultKeyID = "default"
+
+// KeySet holds the HMAC keys of the JWT manager by key ID. Tokens are signed with the
+// SigningKeyID key and verified with whichever key their kid header names, so a key
+// that was rotated out keeps validating the tokens it signed while it is listed.
+type KeySet struct {
+	SigningKeyID string            `json:"signing_key_id"`
+	Keys         map[string]string `json:"keys"`
+}
+
+// ParseKeySet parses the JWT secret. A JSON object such as
+//
+//	{"signing_key_id": "2025-08", "keys": {"2025-08": "...", "2025-05": "..."}}
+//
+// is a key set; any other value is a single key with DefaultKeyID.
+func ParseKeySet(secret string) (KeySet, error) {
+	trimmed := strings.TrimSpace(secret)
+	if !strings.HasPrefix(trimmed, "{") {
+		return SingleKeySet(secret), nil
+	}
+
+	var keys KeySet
+	if err := json.Unmarshal([]byte(trimmed), &keys); err != nil {
+		return KeySet{}, fmt.Errorf("invalid JWT key set: %w", err)
+	}
+	if err := keys.Validate(); err != nil {
+		return KeySet{}, err
+	}
+	return keys, nil
+}
+
+// SingleKeySet returns a key set with one key
+func SingleKeySet(secret string) KeySet {
+	return KeySet{SigningKeyID: DefaultKeyID, Keys: map[string]string{DefaultKeyID: secret}}
+}
+
+// Validate verifies the signing key is part of the set and no key is empty
+func (k KeySet) Validate() error {
+	if k.SigningKeyID == "" {
+		return errors.New("invalid JWT key set: signing key ID is required")
+	}
+	if _, ok := k.Keys[k.SigningKeyID]; !ok {
+		return fmt.Errorf("invalid JWT key set: signing key %s not found", k.SigningKeyID)
+	}
+	for id, key := range k.Keys {
+		if id == "" || key == "" {
+			return errors.New("invalid JWT key set: key IDs and keys cannot be empty")
+		}
+	}
+	return nil
+}
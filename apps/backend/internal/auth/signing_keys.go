@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Signing algorithms. HS256 signs with the shared secret of the key set; EdDSA and
+// RS256 sign with generated key pairs whose public keys are published as a JWKS.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+const rsaKeyBits = 2048
+
+// SigningKey is an asymmetric key pair tokens are signed with. Once a newer key
+// replaces it the key is retired, and it keeps verifying the tokens it signed until
+// its grace period ends at ExpiresAt.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	CreatedAt  time.Time
+	RetiredAt  *time.Time
+	ExpiresAt  *time.Time
+}
+
+// JWK is the public part of a signing key in JSON Web Key format (RFC 7517, RFC 8037)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// IsAsymmetricAlgorithm verifies if an algorithm signs with generated key pairs
+func IsAsymmetricAlgorithm(algorithm string) bool {
+	return algorithm == AlgorithmEdDSA || algorithm == AlgorithmRS256
+}
+
+// GenerateSigningKey creates a new key pair for an asymmetric algorithm
+func GenerateSigningKey(algorithm string, now time.Time) (*SigningKey, error) {
+	var privateKey crypto.Signer
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+		}
+		privateKey = key
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		privateKey = key
+	default:
+		return nil, fmt.Errorf("invalid signing algorithm: %s", algorithm)
+	}
+
+	return &SigningKey{
+		ID:         now.UTC().Format("20060102") + "-" + uuid.New().String()[:8],
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		CreatedAt:  now,
+	}, nil
+}
+
+// IsValidAt verifies the key still verifies tokens at a time
+func (k *SigningKey) IsValidAt(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// signingMethod returns the JWT signing method of the key
+func (k *SigningKey) signingMethod() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// PublicJWK returns the public key as a JWK
+func (k *SigningKey) PublicJWK() JWK {
+	jwk := JWK{KeyID: k.ID, Algorithm: k.Algorithm, Use: "sig"}
+	switch public := k.PrivateKey.Public().(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	}
+	return jwk
+}
+
+// PublicKeyPEM returns the public key as a PKIX PEM block
+func (k *SigningKey) PublicKeyPEM() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.PrivateKey.Public())
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// SealPrivateKey encrypts a private key for storage with AES-256-GCM under a key
+// derived from secret
+func SealPrivateKey(key crypto.Signer, secret string) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	gcm, err := keyCipher(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, der, nil)), nil
+}
+
+// OpenPrivateKey decrypts a private key sealed with SealPrivateKey
+func OpenPrivateKey(sealed, secret string) (crypto.Signer, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sealed private key: %w", err)
+	}
+
+	gcm, err := keyCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed private key is too short")
+	}
+	der, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt private key: wrong key encryption key?")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot sign")
+	}
+	return signer, nil
+}
+
+// keyCipher returns the AES-GCM cipher for a key encryption secret
+func keyCipher(secret string) (cipher.AEAD, error) {
+	if secret == "" {
+		return nil, errors.New("key encryption key is required")
+	}
+	sum := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
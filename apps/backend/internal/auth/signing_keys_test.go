@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTManager_AsymmetricSigning(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmEdDSA, AlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			manager := NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+			hmacPair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+			require.NoError(t, err)
+
+			key, err := GenerateSigningKey(algorithm, time.Now())
+			require.NoError(t, err)
+			require.NoError(t, manager.SetSigningKeys([]*SigningKey{key}))
+			assert.Equal(t, key.ID, manager.SigningKeyID())
+
+			pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+			require.NoError(t, err)
+
+			token, _, err := jwt.NewParser().ParseUnverified(pair.AccessToken, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, algorithm, token.Method.Alg())
+			assert.Equal(t, key.ID, token.Header["kid"])
+
+			_, err = manager.ValidateAccessToken(pair.AccessToken)
+			assert.NoError(t, err)
+			_, err = manager.ValidateRefreshToken(pair.RefreshToken)
+			assert.NoError(t, err)
+			_, err = manager.ValidateAccessToken(hmacPair.AccessToken)
+			assert.NoError(t, err, "tokens signed before the switch keep validating")
+
+			jwks := manager.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, key.ID, jwks.Keys[0].KeyID)
+			assert.Equal(t, algorithm, jwks.Keys[0].Algorithm)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+		})
+	}
+}
+
+func TestJWTManager_SigningKeyGracePeriod(t *testing.T) {
+	manager := NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	now := time.Now()
+
+	oldKey, err := GenerateSigningKey(AlgorithmEdDSA, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, manager.SetSigningKeys([]*SigningKey{oldKey}))
+	oldPair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+
+	// Rotate: the old key keeps verifying during its grace period
+	newKey, err := GenerateSigningKey(AlgorithmEdDSA, now)
+	require.NoError(t, err)
+	expiresAt := now.Add(time.Hour)
+	oldKey.RetiredAt, oldKey.ExpiresAt = &now, &expiresAt
+	require.NoError(t, manager.SetSigningKeys([]*SigningKey{oldKey, newKey}))
+	assert.Equal(t, newKey.ID, manager.SigningKeyID())
+
+	_, err = manager.ValidateAccessToken(oldPair.AccessToken)
+	assert.NoError(t, err)
+	assert.Len(t, manager.JWKS().Keys, 2)
+	assert.Equal(t, newKey.ID, manager.JWKS().Keys[0].KeyID, "newest key first")
+
+	// Grace period over
+	expired := now.Add(-time.Second)
+	oldKey.ExpiresAt = &expired
+	_, err = manager.ValidateAccessToken(oldPair.AccessToken)
+	assert.ErrorContains(t, err, "signing key has expired")
+	assert.Len(t, manager.JWKS().Keys, 1)
+}
+
+func TestJWTManager_RejectsAlgorithmConfusion(t *testing.T) {
+	manager := NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	key, err := GenerateSigningKey(AlgorithmEdDSA, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, manager.SetSigningKeys([]*SigningKey{key}))
+
+	// An HS256 token naming the asymmetric key, signed with its public key bytes
+	publicKey := key.PrivateKey.Public().(ed25519.PublicKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	token.Header["kid"] = key.ID
+	forged, err := token.SignedString([]byte(publicKey))
+	require.NoError(t, err)
+
+	_, err = manager.ValidateAccessToken(forged)
+	assert.ErrorContains(t, err, "unknown signing key")
+}
+
+func TestSealPrivateKey(t *testing.T) {
+	key, err := GenerateSigningKey(AlgorithmEdDSA, time.Now())
+	require.NoError(t, err)
+
+	sealed, err := SealPrivateKey(key.PrivateKey, "key-encryption-key")
+	require.NoError(t, err)
+
+	opened, err := OpenPrivateKey(sealed, "key-encryption-key")
+	require.NoError(t, err)
+	assert.Equal(t, key.PrivateKey, opened)
+
+	_, err = OpenPrivateKey(sealed, "another-key")
+	assert.ErrorContains(t, err, "failed to decrypt private key")
+
+	_, err = SealPrivateKey(key.PrivateKey, "")
+	assert.Error(t, err)
+}
+
+func TestSigningKey_PublicJWK(t *testing.T) {
+	key, err := GenerateSigningKey(AlgorithmEdDSA, time.Now())
+	require.NoError(t, err)
+
+	jwk := key.PublicJWK()
+	assert.Equal(t, "OKP", jwk.KeyType)
+	assert.Equal(t, "Ed25519", jwk.Curve)
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(key.PrivateKey.Public().(ed25519.PublicKey)), x)
+
+	rsaKey, err := GenerateSigningKey(AlgorithmRS256, time.Now())
+	require.NoError(t, err)
+	jwk = rsaKey.PublicJWK()
+	assert.Equal(t, "RSA", jwk.KeyType)
+	assert.Equal(t, "AQAB", jwk.Exponent)
+	assert.NotEmpty(t, jwk.Modulus)
+
+	_, err = GenerateSigningKey(AlgorithmHS256, time.Now())
+	assert.ErrorContains(t, err, "invalid signing algorithm")
+}
@@ -0,0 +1,95 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDump = `SET client_encoding = 'UTF8';
+SELECT pg_catalog.set_config('search_path', '', false);
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+COMMENT ON EXTENSION pg_trgm IS 'text similarity measurement';
+CREATE TYPE public.user_role AS ENUM (
+    'admin',
+    'buyer'
+);
+ALTER TYPE public.user_role OWNER TO realty;
+CREATE TABLE public.properties (
+    id uuid NOT NULL,
+    title character varying(255) NOT NULL,
+    owner_role public.user_role
+);
+CREATE TABLE public."images" (
+    id uuid NOT NULL,
+    property_id uuid NOT NULL
+);
+COPY public.properties (id, title, owner_role) FROM stdin;
+6f1c	Casa en public.properties y CREATE TABLE public.fake	admin
+\.
+CREATE INDEX idx_properties_title_trgm ON public.properties USING gin (title public.gin_trgm_ops);
+ALTER TABLE ONLY public."images"
+    ADD CONSTRAINT images_property_id_fkey FOREIGN KEY (property_id) REFERENCES public.properties(id);
+GRANT ALL ON TABLE public.properties TO readonly;
+`
+
+func TestDumpFormat(t *testing.T) {
+	assert.Equal(t, FormatGzip, DumpFormat("realty-20250916.sql.gz"))
+	assert.Equal(t, FormatPlain, DumpFormat("realty.SQL"))
+	assert.Equal(t, FormatCustom, DumpFormat("realty.dump"))
+	assert.Equal(t, FormatCustom, DumpFormat("realty.backup"))
+	assert.Equal(t, "", DumpFormat("realty.tar"))
+}
+
+func TestLatestDump(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for name, age := range map[string]time.Duration{
+		"realty-20250914.sql.gz": 48 * time.Hour,
+		"realty-20250915.dump":   24 * time.Hour,
+		"notes.txt":              0,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+
+	dump, err := LatestDump(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "realty-20250915.dump", dump.Name)
+	assert.Equal(t, FormatCustom, dump.Format)
+	assert.Equal(t, filepath.Join(dir, "realty-20250915.dump"), dump.Path)
+
+	_, err = LatestDump(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestRewriteSchema(t *testing.T) {
+	objects, err := DefinedObjects(strings.NewReader(sampleDump))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"user_role": true, "properties": true, "images": true}, objects,
+		"COPY rows do not define objects")
+
+	var out bytes.Buffer
+	require.NoError(t, RewriteSchema(strings.NewReader(sampleDump), &out, "backup_verify_1", objects))
+	restored := out.String()
+
+	assert.Contains(t, restored, `CREATE TABLE "backup_verify_1".properties (`)
+	assert.Contains(t, restored, `CREATE TABLE "backup_verify_1"."images" (`)
+	assert.Contains(t, restored, `owner_role "backup_verify_1".user_role`)
+	assert.Contains(t, restored, `COPY "backup_verify_1".properties (id, title, owner_role) FROM stdin;`)
+	assert.Contains(t, restored, "Casa en public.properties y CREATE TABLE public.fake", "COPY rows are copied verbatim")
+	assert.Contains(t, restored, `ON "backup_verify_1".properties USING gin (title public.gin_trgm_ops)`,
+		"extension objects stay in public")
+	assert.Contains(t, restored, `REFERENCES "backup_verify_1".properties(id)`)
+
+	assert.NotContains(t, restored, "CREATE EXTENSION")
+	assert.NotContains(t, restored, "OWNER TO")
+	assert.NotContains(t, restored, "GRANT ")
+}
@@ -0,0 +1,102 @@
+// Package backup restores PostgreSQL dumps into temporary schemas so they can be
+// verified before they are needed.
+package backup
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"realty-core/internal/domain"
+)
+
+// Dump formats
+const (
+	FormatPlain  = "plain"  // pg_dump -Fp, .sql
+	FormatGzip   = "gzip"   // pg_dump -Fp | gzip, .sql.gz
+	FormatCustom = "custom" // pg_dump -Fc, .dump or .backup
+)
+
+// DumpFormat returns the format of a dump file from its name, or "" when it is not a dump
+func DumpFormat(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".sql.gz"):
+		return FormatGzip
+	case strings.HasSuffix(name, ".sql"):
+		return FormatPlain
+	case strings.HasSuffix(name, ".dump"), strings.HasSuffix(name, ".backup"):
+		return FormatCustom
+	}
+	return ""
+}
+
+// LatestDump returns the most recently modified dump in dir
+func LatestDump(dir string) (*domain.BackupDump, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var dumps []domain.BackupDump
+	for _, entry := range entries {
+		format := DumpFormat(entry.Name())
+		if entry.IsDir() || format == "" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+		dumps = append(dumps, domain.BackupDump{
+			Path:       filepath.Join(dir, entry.Name()),
+			Name:       entry.Name(),
+			Format:     format,
+			SizeBytes:  info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+	}
+	if len(dumps) == 0 {
+		return nil, fmt.Errorf("no dump found in %s", dir)
+	}
+
+	sort.Slice(dumps, func(i, j int) bool {
+		if !dumps[i].ModifiedAt.Equal(dumps[j].ModifiedAt) {
+			return dumps[i].ModifiedAt.After(dumps[j].ModifiedAt)
+		}
+		return dumps[i].Name > dumps[j].Name
+	})
+	return &dumps[0], nil
+}
+
+// gzipFile closes both the gzip stream and its file
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// openPlainSQL opens a plain or gzipped dump as SQL text
+func openPlainSQL(dump *domain.BackupDump) (io.ReadCloser, error) {
+	file, err := os.Open(dump.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dump: %w", err)
+	}
+	if dump.Format != FormatGzip {
+		return file, nil
+	}
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read gzipped dump: %w", err)
+	}
+	return &gzipFile{Reader: reader, file: file}, nil
+}
@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// maxRestoreOutput bounds the psql and pg_restore output kept for error messages
+const maxRestoreOutput = 8 * 1024
+
+// Restorer restores a dump into a new schema of the live database
+type Restorer interface {
+	Restore(ctx context.Context, dump *domain.BackupDump, schema string) error
+}
+
+// PsqlRestorer restores dumps with the psql and pg_restore binaries. Custom format
+// dumps are converted to SQL by pg_restore; every dump is restored by psql in a single
+// transaction, so a dump that cannot be restored leaves nothing behind.
+type PsqlRestorer struct {
+	databaseURL   string
+	psqlPath      string
+	pgRestorePath string
+	timeout       time.Duration
+}
+
+// NewPsqlRestorer creates a restorer into the database at databaseURL
+func NewPsqlRestorer(databaseURL, psqlPath, pgRestorePath string, timeout time.Duration) *PsqlRestorer {
+	if psqlPath == "" {
+		psqlPath = "psql"
+	}
+	if pgRestorePath == "" {
+		pgRestorePath = "pg_restore"
+	}
+	if timeout <= 0 {
+		timeout = time.Hour
+	}
+	return &PsqlRestorer{
+		databaseURL:   databaseURL,
+		psqlPath:      psqlPath,
+		pgRestorePath: pgRestorePath,
+		timeout:       timeout,
+	}
+}
+
+// Restore creates schema and restores the public schema of dump into it
+func (r *PsqlRestorer) Restore(ctx context.Context, dump *domain.BackupDump, schema string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// The dump is read twice: once for the objects it defines, once to restore them
+	source, err := r.openSQL(ctx, dump)
+	if err != nil {
+		return err
+	}
+	objects, err := DefinedObjects(source)
+	closeErr := source.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("dump %s defines no objects in the public schema", dump.Name)
+	}
+
+	source, err = r.openSQL(ctx, dump)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	input, writer := io.Pipe()
+	go func() {
+		_, err := io.WriteString(writer, "CREATE SCHEMA "+quoteIdentifier(schema)+";\n")
+		if err == nil {
+			err = RewriteSchema(source, writer, schema, objects)
+		}
+		writer.CloseWithError(err)
+	}()
+
+	cmd := exec.CommandContext(ctx, r.psqlPath, "--no-psqlrc", "--quiet", "--single-transaction",
+		"--set", "ON_ERROR_STOP=1", "--dbname", r.databaseURL, "--file", "-")
+	cmd.Stdin = input
+	output := &limitedBuffer{limit: maxRestoreOutput}
+	cmd.Stdout = io.Discard
+	cmd.Stderr = output
+	if err := cmd.Run(); err != nil {
+		input.CloseWithError(err)
+		return fmt.Errorf("failed to restore %s: %w: %s", dump.Name, err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+// openSQL opens a dump as SQL text
+func (r *PsqlRestorer) openSQL(ctx context.Context, dump *domain.BackupDump) (io.ReadCloser, error) {
+	if dump.Format != FormatCustom {
+		return openPlainSQL(dump)
+	}
+
+	cmd := exec.CommandContext(ctx, r.pgRestorePath, "--schema", "public", "--no-owner", "--no-privileges",
+		"--file", "-", dump.Path)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run pg_restore: %w", err)
+	}
+	stderr := &limitedBuffer{limit: maxRestoreOutput}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run pg_restore: %w", err)
+	}
+	return &commandOutput{ReadCloser: stdout, cmd: cmd, stderr: stderr}, nil
+}
+
+// commandOutput is the output of a running command; closing it waits for the command
+type commandOutput struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *limitedBuffer
+}
+
+func (c *commandOutput) Close() error {
+	io.Copy(io.Discard, c.ReadCloser)
+	if err := c.cmd.Wait(); err != nil {
+		return fmt.Errorf("pg_restore failed: %w: %s", err, strings.TrimSpace(c.stderr.String()))
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
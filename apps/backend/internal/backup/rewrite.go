@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// maxDumpLine bounds the lines of a dump, which COPY rows of long descriptions and
+// function bodies may make long
+const maxDumpLine = 64 * 1024 * 1024
+
+var (
+	// definedObject matches the statements creating the objects of a dump
+	definedObject = regexp.MustCompile(`^CREATE (?:UNLOGGED )?(?:TABLE|SEQUENCE|VIEW|MATERIALIZED VIEW|TYPE|DOMAIN|FUNCTION|PROCEDURE|AGGREGATE) public\.("?)([A-Za-z0-9_$]+)`)
+	// qualifiedName matches the public.name references of a dump
+	qualifiedName = regexp.MustCompile(`\bpublic\.("?)([A-Za-z0-9_$]+)("?)`)
+	// copyStart matches the COPY statements followed by rows until a \. line
+	copyStart = regexp.MustCompile(`^COPY .* FROM stdin;$`)
+)
+
+// skippedPrefixes are the statements left out of a restore: extensions and the public
+// schema are shared with the live database, and ownership and grants are not verified
+var skippedPrefixes = []string{
+	"CREATE EXTENSION ", "COMMENT ON EXTENSION ", "CREATE SCHEMA public", "ALTER SCHEMA public",
+	"COMMENT ON SCHEMA public", "GRANT ", "REVOKE ", "ALTER DEFAULT PRIVILEGES ",
+	"CREATE PUBLICATION ", "CREATE EVENT TRIGGER ",
+}
+
+// DefinedObjects returns the names of the tables, sequences, views, types and functions
+// a plain dump creates in the public schema
+func DefinedObjects(r io.Reader) (map[string]bool, error) {
+	names := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxDumpLine)
+	inCopy := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case inCopy:
+			inCopy = line != `\.`
+		case copyStart.MatchString(line):
+			inCopy = true
+		default:
+			if match := definedObject.FindStringSubmatch(line); match != nil {
+				names[match[2]] = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+	return names, nil
+}
+
+// RewriteSchema copies a plain dump from r to w with the references to the given
+// objects of the public schema moved to schema. Extension objects, such as trigram
+// operator classes, keep pointing at public. COPY rows are copied verbatim.
+func RewriteSchema(r io.Reader, w io.Writer, schema string, objects map[string]bool) error {
+	target := quoteIdentifier(schema)
+	rewrite := func(line string) string {
+		return qualifiedName.ReplaceAllStringFunc(line, func(reference string) string {
+			match := qualifiedName.FindStringSubmatch(reference)
+			if !objects[match[2]] || match[1] != match[3] {
+				return reference
+			}
+			return target + "." + match[1] + match[2] + match[3]
+		})
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxDumpLine)
+	out := bufio.NewWriter(w)
+	inCopy := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case inCopy:
+			inCopy = line != `\.`
+		case copyStart.MatchString(line):
+			inCopy = true
+			line = rewrite(line)
+		case skipped(line):
+			continue
+		default:
+			line = rewrite(line)
+		}
+		if _, err := out.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("failed to write dump: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read dump: %w", err)
+	}
+	return out.Flush()
+}
+
+// skipped reports whether a statement is left out of a restore
+func skipped(line string) bool {
+	if strings.HasPrefix(line, "ALTER ") && strings.Contains(line, " OWNER TO ") {
+		return true
+	}
+	for _, prefix := range skippedPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteIdentifier quotes a PostgreSQL identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
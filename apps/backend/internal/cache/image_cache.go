@@ -202,6 +202,16 @@ func (c *ImageCache) SetVariant(imageID string, width, height, quality int, form
 	c.Set(key, data, contentType)
 }
 
+// GetPreset retrieves the rendition of an image in a preset, identified by its key
+func (c *ImageCache) GetPreset(imageID, presetKey string) ([]byte, string, bool) {
+	return c.Get(c.generatePresetKey(imageID, presetKey))
+}
+
+// SetPreset stores the rendition of an image in a preset, identified by its key
+func (c *ImageCache) SetPreset(imageID, presetKey string, data []byte, contentType string) {
+	c.Set(c.generatePresetKey(imageID, presetKey), data, contentType)
+}
+
 // InvalidateImage removes all cached variants for an image
 func (c *ImageCache) InvalidateImage(imageID string) int {
 	if !c.enabled {
@@ -333,6 +343,11 @@ func (c *ImageCache) generateVariantKey(imageID string, width, height, quality i
 	return fmt.Sprintf("%s_variant_%dx%d_q%d_%s", imageID, width, height, quality, format)
 }
 
+// generatePresetKey creates a cache key for preset renditions
+func (c *ImageCache) generatePresetKey(imageID, presetKey string) string {
+	return fmt.Sprintf("%s_preset_%s", imageID, presetKey)
+}
+
 // cleanupRoutine runs periodic cleanup
 func (c *ImageCache) cleanupRoutine(interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -368,6 +383,8 @@ type ImageCacheInterface interface {
 	SetThumbnail(imageID string, size int, data []byte, contentType string)
 	GetVariant(imageID string, width, height, quality int, format string) ([]byte, string, bool)
 	SetVariant(imageID string, width, height, quality int, format string, data []byte, contentType string)
+	GetPreset(imageID, presetKey string) ([]byte, string, bool)
+	SetPreset(imageID, presetKey string, data []byte, contentType string)
 	InvalidateImage(imageID string) int
 	Stats() ImageCacheStats
 	IsEnabled() bool
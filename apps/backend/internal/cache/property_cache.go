@@ -24,13 +24,14 @@ type PropertyCacheEntry struct {
 // PropertyCache wraps LRU cache with property-specific functionality
 type PropertyCache struct {
 	lru            CacheInterface
-	mutex          sync.RWMutex
+	mutex          *sync.RWMutex // shared with the views of tenants
 	enabled        bool
 	defaultTTL     time.Duration
 	searchTTL      time.Duration // Shorter TTL for search results
 	statisticsTTL  time.Duration // Longer TTL for statistics
-	stats          PropertyCacheStats
+	stats          *PropertyCacheStats
 	logger         *log.Logger
+	namespace      string // prefixes the keys of the view of a tenant
 }
 
 // PropertyCacheStats represents property cache statistics
@@ -64,7 +65,7 @@ type PropertyCacheConfig struct {
 // NewPropertyCache creates a new property cache instance
 func NewPropertyCache(config PropertyCacheConfig) *PropertyCache {
 	if !config.Enabled {
-		return &PropertyCache{enabled: false}
+		return &PropertyCache{enabled: false, mutex: &sync.RWMutex{}, stats: &PropertyCacheStats{}}
 	}
 
 	// Set defaults
@@ -88,6 +89,8 @@ func NewPropertyCache(config PropertyCacheConfig) *PropertyCache {
 
 	return &PropertyCache{
 		lru:           lru,
+		mutex:         &sync.RWMutex{},
+		stats:         &PropertyCacheStats{},
 		enabled:       true,
 		defaultTTL:    config.DefaultTTL,
 		searchTTL:     config.SearchTTL,
@@ -96,13 +99,87 @@ func NewPropertyCache(config PropertyCacheConfig) *PropertyCache {
 	}
 }
 
+// ForTenant returns a view of the cache for a tenant. The view shares the entries and
+// statistics of the cache, but the properties, searches and statistics it stores are
+// kept apart from those of other tenants. Invalidations still clear every tenant. An
+// empty tenant returns the cache itself.
+func (pc *PropertyCache) ForTenant(tenantID string) *PropertyCache {
+	if tenantID == "" || !pc.enabled {
+		return pc
+	}
+
+	defaultTTL, searchTTL, statisticsTTL := pc.TTLs()
+	return &PropertyCache{
+		lru:           pc.lru,
+		mutex:         pc.mutex,
+		stats:         pc.stats,
+		enabled:       true,
+		defaultTTL:    defaultTTL,
+		searchTTL:     searchTTL,
+		statisticsTTL: statisticsTTL,
+		logger:        pc.logger,
+		namespace:     "tenant:" + tenantID + ":",
+	}
+}
+
+// key builds the key of an entry in the namespace of the cache
+func (pc *PropertyCache) key(format string, args ...interface{}) string {
+	return pc.namespace + fmt.Sprintf(format, args...)
+}
+
+// unnamespaced strips the namespace of a tenant from a key
+func unnamespaced(key string) string {
+	if !strings.HasPrefix(key, "tenant:") {
+		return key
+	}
+	if i := strings.Index(key[len("tenant:"):], ":"); i >= 0 {
+		return key[len("tenant:")+i+1:]
+	}
+	return key
+}
+
+// SetTTLs changes the TTLs of entries cached from now on; zero keeps the current TTL.
+// Entries already cached keep the TTL they were stored with.
+func (pc *PropertyCache) SetTTLs(defaultTTL, searchTTL, statisticsTTL time.Duration) {
+	if !pc.enabled {
+		return
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if defaultTTL > 0 {
+		pc.defaultTTL = defaultTTL
+	}
+	if searchTTL > 0 {
+		pc.searchTTL = searchTTL
+	}
+	if statisticsTTL > 0 {
+		pc.statisticsTTL = statisticsTTL
+	}
+}
+
+// TTLs returns the default, search and statistics TTLs
+func (pc *PropertyCache) TTLs() (defaultTTL, searchTTL, statisticsTTL time.Duration) {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+	return pc.defaultTTL, pc.searchTTL, pc.statisticsTTL
+}
+
+// ttl reads one of the TTLs, which can change at runtime
+func (pc *PropertyCache) ttl(field *time.Duration) time.Duration {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+	return *field
+}
+
 // GetProperty retrieves a cached property by ID
 func (pc *PropertyCache) GetProperty(id string) (*domain.Property, bool) {
 	if !pc.enabled {
 		return nil, false
 	}
 
-	key := fmt.Sprintf("property:%s", id)
+	key := pc.key("property:%s", id)
 	if value, found := pc.lru.Get(key); found {
 		if property, ok := value.(*domain.Property); ok {
 			pc.incrementHits()
@@ -126,10 +203,10 @@ func (pc *PropertyCache) SetProperty(property *domain.Property) {
 		return
 	}
 
-	key := fmt.Sprintf("property:%s", property.ID)
+	key := pc.key("property:%s", property.ID)
 	size := pc.estimatePropertySize(property)
 	
-	pc.lru.Set(key, property, size)
+	pc.lru.SetWithTTL(key, property, size, pc.ttl(&pc.defaultTTL))
 	
 	if pc.logger != nil {
 		pc.logger.Printf("Property cached: %s (size: %d bytes)", property.ID, size)
@@ -142,7 +219,7 @@ func (pc *PropertyCache) GetSearchResults(query string, limit int) ([]repository
 		return nil, false
 	}
 
-	key := fmt.Sprintf("search:%s:limit:%d", query, limit)
+	key := pc.key("search:%s:limit:%d", query, limit)
 	if value, found := pc.lru.Get(key); found {
 		if results, ok := value.([]repository.PropertySearchResult); ok {
 			pc.mutex.Lock()
@@ -172,11 +249,11 @@ func (pc *PropertyCache) SetSearchResults(query string, limit int, results []rep
 		return
 	}
 
-	key := fmt.Sprintf("search:%s:limit:%d", query, limit)
+	key := pc.key("search:%s:limit:%d", query, limit)
 	size := pc.estimateSearchResultsSize(results)
 	
 	// Use shorter TTL for search results
-	pc.lru.SetWithTTL(key, results, size, pc.searchTTL)
+	pc.lru.SetWithTTL(key, results, size, pc.ttl(&pc.searchTTL))
 	
 	if pc.logger != nil {
 		pc.logger.Printf("Search results cached: %s (count: %d, size: %d bytes)", 
@@ -184,13 +261,63 @@ func (pc *PropertyCache) SetSearchResults(query string, limit int, results []rep
 	}
 }
 
+// GetFacets retrieves cached facet counts for a search key
+func (pc *PropertyCache) GetFacets(key string) (*domain.SearchFacets, bool) {
+	if !pc.enabled {
+		return nil, false
+	}
+
+	cacheKey := pc.key("facets:%s", key)
+	if value, found := pc.lru.Get(cacheKey); found {
+		if facets, ok := value.(*domain.SearchFacets); ok {
+			pc.mutex.Lock()
+			pc.stats.SearchHits++
+			pc.mutex.Unlock()
+
+			if pc.logger != nil {
+				pc.logger.Printf("Facets cache HIT: %s", key)
+			}
+			return facets, true
+		}
+	}
+
+	pc.mutex.Lock()
+	pc.stats.SearchMisses++
+	pc.mutex.Unlock()
+
+	if pc.logger != nil {
+		pc.logger.Printf("Facets cache MISS: %s", key)
+	}
+	return nil, false
+}
+
+// SetFacets stores facet counts for a search key in cache
+func (pc *PropertyCache) SetFacets(key string, facets *domain.SearchFacets) {
+	if !pc.enabled || facets == nil {
+		return
+	}
+
+	cacheKey := pc.key("facets:%s", key)
+	size := int64(1024)
+	if data, err := json.Marshal(facets); err == nil {
+		size = int64(len(data))
+	}
+
+	// Facets follow search results, use the search TTL
+	pc.lru.SetWithTTL(cacheKey, facets, size, pc.ttl(&pc.searchTTL))
+
+	if pc.logger != nil {
+		pc.logger.Printf("Facets cached: %s (size: %d bytes)", key, size)
+	}
+}
+
 // GetFilterResults retrieves cached filter results
 func (pc *PropertyCache) GetFilterResults(province string, minPrice, maxPrice float64) ([]domain.Property, bool) {
 	if !pc.enabled {
 		return nil, false
 	}
 
-	key := fmt.Sprintf("filter:province:%s:price:%.0f-%.0f", province, minPrice, maxPrice)
+	key := pc.key("filter:province:%s:price:%.0f-%.0f", province, minPrice, maxPrice)
 	if value, found := pc.lru.Get(key); found {
 		if properties, ok := value.([]domain.Property); ok {
 			pc.mutex.Lock()
@@ -220,10 +347,10 @@ func (pc *PropertyCache) SetFilterResults(province string, minPrice, maxPrice fl
 		return
 	}
 
-	key := fmt.Sprintf("filter:province:%s:price:%.0f-%.0f", province, minPrice, maxPrice)
+	key := pc.key("filter:province:%s:price:%.0f-%.0f", province, minPrice, maxPrice)
 	size := pc.estimatePropertiesSize(properties)
 	
-	pc.lru.Set(key, properties, size)
+	pc.lru.SetWithTTL(key, properties, size, pc.ttl(&pc.defaultTTL))
 	
 	if pc.logger != nil {
 		pc.logger.Printf("Filter results cached: %s (count: %d, size: %d bytes)", 
@@ -237,7 +364,7 @@ func (pc *PropertyCache) GetStatistics(key string) (map[string]interface{}, bool
 		return nil, false
 	}
 
-	cacheKey := fmt.Sprintf("stats:%s", key)
+	cacheKey := pc.key("stats:%s", key)
 	if value, found := pc.lru.Get(cacheKey); found {
 		if stats, ok := value.(map[string]interface{}); ok {
 			pc.mutex.Lock()
@@ -267,11 +394,11 @@ func (pc *PropertyCache) SetStatistics(key string, stats map[string]interface{})
 		return
 	}
 
-	cacheKey := fmt.Sprintf("stats:%s", key)
+	cacheKey := pc.key("stats:%s", key)
 	size := pc.estimateStatsSize(stats)
 	
 	// Use longer TTL for statistics
-	pc.lru.SetWithTTL(cacheKey, stats, size, pc.statisticsTTL)
+	pc.lru.SetWithTTL(cacheKey, stats, size, pc.ttl(&pc.statisticsTTL))
 	
 	if pc.logger != nil {
 		pc.logger.Printf("Statistics cached: %s (size: %d bytes)", key, size)
@@ -284,8 +411,13 @@ func (pc *PropertyCache) InvalidateProperty(id string) {
 		return
 	}
 
+	// The property may be cached by the views of tenants as well
 	key := fmt.Sprintf("property:%s", id)
-	pc.lru.Delete(key)
+	for _, cached := range pc.lru.Keys() {
+		if unnamespaced(cached) == key {
+			pc.lru.Delete(cached)
+		}
+	}
 	
 	// Also invalidate related caches that might contain this property
 	pc.InvalidateSearchResults() // Clear search cache when properties change
@@ -305,7 +437,8 @@ func (pc *PropertyCache) InvalidateSearchResults() {
 	// Get all keys and remove search-related ones
 	keys := pc.lru.Keys()
 	for _, key := range keys {
-		if strings.HasPrefix(key, "search:") || strings.HasPrefix(key, "filter:") {
+		bare := unnamespaced(key)
+		if strings.HasPrefix(bare, "search:") || strings.HasPrefix(bare, "filter:") || strings.HasPrefix(bare, "facets:") {
 			pc.lru.Delete(key)
 		}
 	}
@@ -324,7 +457,7 @@ func (pc *PropertyCache) InvalidateStatistics() {
 	// Get all keys and remove stats-related ones
 	keys := pc.lru.Keys()
 	for _, key := range keys {
-		if strings.HasPrefix(key, "stats:") {
+		if strings.HasPrefix(unnamespaced(key), "stats:") {
 			pc.lru.Delete(key)
 		}
 	}
@@ -352,7 +485,7 @@ func (pc *PropertyCache) GetStats() PropertyCacheStats {
 	listTotal := pc.stats.ListHits + pc.stats.ListMisses
 	filterTotal := pc.stats.FilterHits + pc.stats.FilterMisses
 
-	stats := pc.stats
+	stats := *pc.stats
 	stats.CacheStats = baseStats
 	
 	if searchTotal > 0 {
@@ -381,7 +514,7 @@ func (pc *PropertyCache) Clear() {
 	
 	// Reset stats
 	pc.mutex.Lock()
-	pc.stats = PropertyCacheStats{}
+	*pc.stats = PropertyCacheStats{}
 	pc.mutex.Unlock()
 	
 	if pc.logger != nil {
@@ -389,6 +522,19 @@ func (pc *PropertyCache) Clear() {
 	}
 }
 
+// InvalidateAll removes every entry, keeping the statistics of the cache
+func (pc *PropertyCache) InvalidateAll() {
+	if !pc.enabled {
+		return
+	}
+
+	pc.lru.Clear()
+	
+	if pc.logger != nil {
+		pc.logger.Printf("Property cache invalidated")
+	}
+}
+
 // IsEnabled returns whether cache is enabled
 func (pc *PropertyCache) IsEnabled() bool {
 	return pc.enabled
@@ -219,6 +219,76 @@ func TestPropertyCache_FilterResults(t *testing.T) {
 	}
 }
 
+func TestPropertyCache_Facets(t *testing.T) {
+	cache := NewPropertyCache(PropertyCacheConfig{
+		Enabled:    true,
+		Capacity:   100,
+		DefaultTTL: 5 * time.Minute,
+	})
+
+	facets := domain.NewSearchFacets()
+	facets.Add(domain.FacetProvince, "Pichincha", 12)
+
+	if _, found := cache.GetFacets("pichincha"); found {
+		t.Error("Expected cache miss, but got hit")
+	}
+
+	cache.SetFacets("pichincha", facets)
+	cached, found := cache.GetFacets("pichincha")
+	if !found {
+		t.Fatal("Expected cache hit, but got miss")
+	}
+	if len(cached.Provinces) != 1 || cached.Provinces[0].Count != 12 {
+		t.Errorf("Unexpected cached facets: %+v", cached.Provinces)
+	}
+
+	// Facets are cleared together with search results
+	cache.InvalidateSearchResults()
+	if _, found := cache.GetFacets("pichincha"); found {
+		t.Error("Expected facets to be invalidated")
+	}
+}
+
+func TestPropertyCache_ForTenant(t *testing.T) {
+	cache := NewPropertyCache(PropertyCacheConfig{
+		Enabled:    true,
+		Capacity:   100,
+		DefaultTTL: 5 * time.Minute,
+	})
+	acme := cache.ForTenant("acme")
+	beta := cache.ForTenant("beta")
+
+	property := &domain.Property{ID: "acme-property", Title: "Casa en Cumbayá"}
+	acme.SetProperty(property)
+	acme.SetFacets("quito", domain.NewSearchFacets())
+
+	if _, found := acme.GetProperty("acme-property"); !found {
+		t.Fatal("Expected cache hit in the tenant, but got miss")
+	}
+	if _, found := beta.GetProperty("acme-property"); found {
+		t.Error("Expected other tenants to miss the property")
+	}
+	if _, found := cache.GetProperty("acme-property"); found {
+		t.Error("Expected the unscoped cache to miss the property")
+	}
+	if _, found := beta.GetFacets("quito"); found {
+		t.Error("Expected other tenants to miss the facets")
+	}
+	if stats := cache.GetStats(); stats.Hits != 1 || stats.Misses != 3 {
+		t.Errorf("Expected views to share the statistics, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+
+	// Invalidations reach every tenant
+	cache.InvalidateSearchResults()
+	if _, found := acme.GetFacets("quito"); found {
+		t.Error("Expected the facets of the tenant to be invalidated")
+	}
+	cache.InvalidateProperty("acme-property")
+	if _, found := acme.GetProperty("acme-property"); found {
+		t.Error("Expected the property of the tenant to be invalidated")
+	}
+}
+
 func TestPropertyCache_Statistics(t *testing.T) {
 	cache := NewPropertyCache(PropertyCacheConfig{
 		Enabled:       true,
@@ -402,4 +472,23 @@ func TestPropertyCache_TTL(t *testing.T) {
 	if _, found := cache.GetSearchResults("ttl-test", 10); found {
 		t.Log("Search results still cached after TTL - this is acceptable")
 	}
-}
\ No newline at end of file
+}
+func TestPropertyCache_SetTTLs(t *testing.T) {
+	cache := NewPropertyCache(PropertyCacheConfig{Enabled: true, Capacity: 100})
+
+	cache.SetTTLs(10*time.Minute, 0, 30*time.Minute)
+
+	defaultTTL, searchTTL, statisticsTTL := cache.TTLs()
+	if defaultTTL != 10*time.Minute || statisticsTTL != 30*time.Minute {
+		t.Errorf("Expected updated TTLs, got default %v and statistics %v", defaultTTL, statisticsTTL)
+	}
+	if searchTTL != time.Minute {
+		t.Errorf("Expected a zero TTL to keep the search TTL, got %v", searchTTL)
+	}
+
+	// Entries cached after the change still work
+	cache.SetProperty(&domain.Property{ID: "ttl-change"})
+	if _, found := cache.GetProperty("ttl-change"); !found {
+		t.Error("Expected property to be cached with the new TTL")
+	}
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PropertyChangesChannel is the channel the database notifies property changes on, see
+// migration 072
+const PropertyChangesChannel = "property_changes"
+
+// PropertyChange is a change of properties notified by the database
+type PropertyChange struct {
+	Op  string   `json:"op"` // insert, update or delete
+	IDs []string `json:"ids,omitempty"`
+	// All is set for changes of too many properties to list, and after reconnecting,
+	// when notifications may have been missed
+	All bool `json:"all,omitempty"`
+}
+
+// PropertyChangeListener invalidates cached properties changed by any instance or by
+// scripts, as the database notifies them, rather than when their TTL expires
+type PropertyChangeListener struct {
+	cache  *PropertyCache
+	logger *log.Logger
+
+	mutex     sync.RWMutex
+	listeners []func(PropertyChange)
+
+	listener *pq.Listener
+	done     chan struct{}
+}
+
+// NewPropertyChangeListener creates a listener invalidating a property cache
+func NewPropertyChangeListener(cache *PropertyCache, logger *log.Logger) *PropertyChangeListener {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &PropertyChangeListener{cache: cache, logger: logger}
+}
+
+// OnChange registers a function called with every change after the cache is
+// invalidated, e.g. to invalidate response caches too
+func (l *PropertyChangeListener) OnChange(fn func(PropertyChange)) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.listeners = append(l.listeners, fn)
+}
+
+// Start listens on PropertyChangesChannel of a database until Stop is called. The
+// connection is re-established after failures; changes notified while it was down are
+// unknown, so everything is invalidated once it is back.
+func (l *PropertyChangeListener) Start(databaseURL string) error {
+	listener := pq.NewListener(databaseURL, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			l.logger.Printf("Property change listener: %v", err)
+		}
+	})
+	if err := listener.Listen(PropertyChangesChannel); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to listen for property changes: %w", err)
+	}
+
+	l.listener = listener
+	l.done = make(chan struct{})
+	go l.run(listener.Notify, listener.Ping, l.done)
+	return nil
+}
+
+// Stop stops listening
+func (l *PropertyChangeListener) Stop() error {
+	if l.listener == nil {
+		return nil
+	}
+	close(l.done)
+	err := l.listener.Close()
+	l.listener = nil
+	return err
+}
+
+// run applies notifications until done; a nil notification means the connection was
+// re-established. The connection is pinged when idle so failures are noticed.
+func (l *PropertyChangeListener) run(notifications <-chan *pq.Notification, ping func() error, done <-chan struct{}) {
+	idle := time.NewTicker(90 * time.Second)
+	defer idle.Stop()
+
+	for {
+		select {
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			if notification == nil {
+				l.Apply(PropertyChange{Op: "reconnect", All: true})
+				continue
+			}
+			l.HandleNotification(notification.Extra)
+		case <-idle.C:
+			go ping()
+		case <-done:
+			return
+		}
+	}
+}
+
+// HandleNotification applies the payload of a notification; payloads that cannot be
+// parsed invalidate everything
+func (l *PropertyChangeListener) HandleNotification(payload string) {
+	var change PropertyChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		l.logger.Printf("Invalid property change notification %q: %v", payload, err)
+		change = PropertyChange{Op: "unknown", All: true}
+	}
+	l.Apply(change)
+}
+
+// Apply invalidates the cache entries of a change. Every change invalidates searches
+// and statistics, which may include the changed properties.
+func (l *PropertyChangeListener) Apply(change PropertyChange) {
+	if change.All {
+		l.cache.InvalidateAll()
+	} else {
+		for _, id := range change.IDs {
+			l.cache.InvalidateProperty(id)
+		}
+		l.cache.InvalidateSearchResults()
+		l.cache.InvalidateStatistics()
+	}
+
+	l.mutex.RLock()
+	listeners := l.listeners
+	l.mutex.RUnlock()
+	for _, fn := range listeners {
+		fn(change)
+	}
+}
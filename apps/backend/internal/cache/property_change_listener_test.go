@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+func newListenerTestCache() *PropertyCache {
+	propertyCache := NewPropertyCache(PropertyCacheConfig{Enabled: true, Capacity: 100})
+	for _, id := range []string{"prop-1", "prop-2"} {
+		propertyCache.SetProperty(&domain.Property{ID: id, Title: "Casa " + id})
+	}
+	propertyCache.SetStatistics("summary", map[string]interface{}{"total": 2})
+	return propertyCache
+}
+
+func TestPropertyChangeListener_InvalidatesNotifiedProperties(t *testing.T) {
+	propertyCache := newListenerTestCache()
+	listener := NewPropertyChangeListener(propertyCache, nil)
+	var changes []PropertyChange
+	listener.OnChange(func(change PropertyChange) { changes = append(changes, change) })
+
+	listener.HandleNotification(`{"op": "update", "ids": ["prop-1"]}`)
+
+	_, found := propertyCache.GetProperty("prop-1")
+	assert.False(t, found)
+	_, found = propertyCache.GetProperty("prop-2")
+	assert.True(t, found)
+	_, found = propertyCache.GetStatistics("summary")
+	assert.False(t, found)
+	assert.Equal(t, []PropertyChange{{Op: "update", IDs: []string{"prop-1"}}}, changes)
+}
+
+func TestPropertyChangeListener_InvalidatesEverything(t *testing.T) {
+	for _, payload := range []string{`{"op": "update", "all": true}`, `not json`} {
+		propertyCache := newListenerTestCache()
+		NewPropertyChangeListener(propertyCache, nil).HandleNotification(payload)
+
+		_, found := propertyCache.GetProperty("prop-2")
+		assert.False(t, found, payload)
+	}
+}
+
+func TestPropertyChangeListener_RunInvalidatesAfterReconnecting(t *testing.T) {
+	propertyCache := newListenerTestCache()
+	listener := NewPropertyChangeListener(propertyCache, nil)
+	applied := make(chan PropertyChange, 2)
+	listener.OnChange(func(change PropertyChange) { applied <- change })
+
+	notifications := make(chan *pq.Notification)
+	done := make(chan struct{})
+	go listener.run(notifications, func() error { return nil }, done)
+	defer close(done)
+
+	notifications <- &pq.Notification{Channel: PropertyChangesChannel, Extra: `{"op": "delete", "ids": ["prop-1"]}`}
+	notifications <- nil
+
+	for _, want := range []PropertyChange{{Op: "delete", IDs: []string{"prop-1"}}, {Op: "reconnect", All: true}} {
+		select {
+		case change := <-applied:
+			assert.Equal(t, want, change)
+		case <-time.After(time.Second):
+			t.Fatal("notification not applied")
+		}
+	}
+	_, found := propertyCache.GetProperty("prop-2")
+	assert.False(t, found)
+}
@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redisDefaultPort = "6379"
+
+// redisKeyPrefix namespaces the keys of the micro-cache
+const redisKeyPrefix = "realty:microcache:"
+
+// RedisResponseStore keeps responses in Redis over its RESP protocol so every instance
+// shares entries and invalidations. The connection is opened on first use and reopened
+// after failures; commands are serialized on it.
+type RedisResponseStore struct {
+	address  string
+	useTLS   bool
+	host     string
+	username string
+	password string
+	database int
+	timeout  time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisResponseStore validates a redis:// or rediss:// URL, e.g.
+// redis://:password@localhost:6379/2; the connection is opened on first use
+func NewRedisResponseStore(redisURL string, timeout time.Duration) (*RedisResponseStore, error) {
+	parsed, err := url.Parse(redisURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Redis URL: %s", redisURL)
+	}
+	if parsed.Scheme != "redis" && parsed.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid Redis URL: scheme must be redis:// or rediss://")
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		address = net.JoinHostPort(parsed.Hostname(), redisDefaultPort)
+	}
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	store := &RedisResponseStore{
+		address: address,
+		useTLS:  parsed.Scheme == "rediss",
+		host:    parsed.Hostname(),
+		timeout: timeout,
+	}
+	if parsed.User != nil {
+		store.username = parsed.User.Username()
+		store.password, _ = parsed.User.Password()
+	}
+	if db := strings.Trim(parsed.Path, "/"); db != "" {
+		if store.database, err = strconv.Atoi(db); err != nil || store.database < 0 {
+			return nil, fmt.Errorf("invalid Redis URL: database must be a number")
+		}
+	}
+	return store, nil
+}
+
+// Get returns a stored entry
+func (s *RedisResponseStore) Get(key string) ([]byte, bool, error) {
+	reply, err := s.do("GET", redisKeyPrefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected Redis reply to GET: %v", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores an entry for ttl
+func (s *RedisResponseStore) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := s.do("SET", redisKeyPrefix+key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Generation returns the current generation of a group
+func (s *RedisResponseStore) Generation(group string) (int64, error) {
+	reply, err := s.do("GET", redisKeyPrefix+"generation:"+group)
+	if err != nil || reply == nil {
+		return 0, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return 0, fmt.Errorf("unexpected Redis reply to GET: %v", reply)
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// Bump starts a new generation of a group
+func (s *RedisResponseStore) Bump(group string) error {
+	_, err := s.do("INCR", redisKeyPrefix+"generation:"+group)
+	return err
+}
+
+// Close closes the connection
+func (s *RedisResponseStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeConn()
+	return nil
+}
+
+// do runs a command, reconnecting once if the connection was lost
+func (s *RedisResponseStore) do(args ...string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if err := s.dial(); err != nil {
+				return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+			}
+		}
+		reply, err := s.command(args...)
+		if err == nil {
+			return reply, nil
+		}
+		if _, isRedisErr := err.(redisError); isRedisErr {
+			return nil, err
+		}
+		lastErr = err
+		s.closeConn()
+	}
+	return nil, fmt.Errorf("failed to run Redis %s: %w", args[0], lastErr)
+}
+
+// dial opens the connection, authenticating and selecting the database
+func (s *RedisResponseStore) dial() error {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	if s.useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.address, &tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", s.address)
+	}
+	if err != nil {
+		return err
+	}
+	s.conn, s.reader = conn, bufio.NewReader(conn)
+
+	if s.password != "" {
+		args := []string{"AUTH", s.password}
+		if s.username != "" {
+			args = []string{"AUTH", s.username, s.password}
+		}
+		if _, err := s.command(args...); err != nil {
+			s.closeConn()
+			return err
+		}
+	}
+	if s.database != 0 {
+		if _, err := s.command("SELECT", strconv.Itoa(s.database)); err != nil {
+			s.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+// command writes a command as an array of bulk strings and reads its reply
+func (s *RedisResponseStore) command(args ...string) (interface{}, error) {
+	s.conn.SetDeadline(time.Now().Add(s.timeout))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(s.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return s.readReply()
+}
+
+// redisError is an error reply of the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "Redis error: " + string(e)
+}
+
+// readReply reads a simple string, error, integer or bulk string reply; nil bulk
+// strings are returned as nil
+func (s *RedisResponseStore) readReply() (interface{}, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis bulk length: %s", line)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(s.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:length], nil
+	default:
+		return nil, fmt.Errorf("unsupported Redis reply: %s", line)
+	}
+}
+
+// closeConn drops the connection so the next command reconnects
+func (s *RedisResponseStore) closeConn() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.reader = nil, nil
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response store backends
+const (
+	ResponseStoreMemory = "memory"
+	ResponseStoreRedis  = "redis"
+)
+
+// ResponseStore keeps the HTTP responses of the micro-cache. Entries are grouped;
+// bumping the generation of a group invalidates every entry keyed with the previous
+// one, which is how writes invalidate the list endpoints they affect.
+type ResponseStore interface {
+	// Get returns a stored entry
+	Get(key string) ([]byte, bool, error)
+
+	// Set stores an entry for ttl
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Generation returns the current generation of a group
+	Generation(group string) (int64, error)
+
+	// Bump starts a new generation of a group
+	Bump(group string) error
+}
+
+// MemoryResponseStore keeps responses in an LRU cache of the instance. Generations are
+// local too, so writes only invalidate the instance serving them; use the Redis store
+// when several instances serve the API.
+type MemoryResponseStore struct {
+	entries     *LRUCache
+	mutex       sync.RWMutex
+	generations map[string]int64
+}
+
+// NewMemoryResponseStore creates a memory store holding up to capacity responses
+// totalling maxSizeBytes for at most maxTTL
+func NewMemoryResponseStore(capacity int, maxSizeBytes int64, maxTTL time.Duration) *MemoryResponseStore {
+	return &MemoryResponseStore{
+		entries:     NewLRUCache(capacity, maxSizeBytes, maxTTL),
+		generations: make(map[string]int64),
+	}
+}
+
+// Get returns a stored entry
+func (s *MemoryResponseStore) Get(key string) ([]byte, bool, error) {
+	value, ok := s.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return value.([]byte), true, nil
+}
+
+// Set stores an entry for ttl
+func (s *MemoryResponseStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.entries.SetWithTTL(key, value, int64(len(value)), ttl)
+	return nil
+}
+
+// Generation returns the current generation of a group
+func (s *MemoryResponseStore) Generation(group string) (int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.generations[group], nil
+}
+
+// Bump starts a new generation of a group
+func (s *MemoryResponseStore) Bump(group string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.generations[group]++
+	return nil
+}
+
+// NewResponseStore creates the response store of a backend; redisURL is only used by
+// the Redis backend
+func NewResponseStore(backend, redisURL string, timeout time.Duration, capacity int, maxSizeBytes int64, maxTTL time.Duration) (ResponseStore, error) {
+	switch strings.ToLower(backend) {
+	case "", ResponseStoreMemory:
+		return NewMemoryResponseStore(capacity, maxSizeBytes, maxTTL), nil
+	case ResponseStoreRedis:
+		return NewRedisResponseStore(redisURL, timeout)
+	default:
+		return nil, fmt.Errorf("unsupported response store backend: %s", backend)
+	}
+}
@@ -0,0 +1,80 @@
+package captcha
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVerifier(t *testing.T, provider string, handler http.HandlerFunc) *SiteverifyVerifier {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	verifier, err := NewSiteverifyVerifier(provider, "secret", 0.5, time.Second)
+	require.NoError(t, err)
+	verifier.endpoint = server.URL
+	return verifier
+}
+
+func TestSiteverifyVerifier_Verify(t *testing.T) {
+	verifier := newTestVerifier(t, ProviderTurnstile, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "190.152.10.4", r.PostForm.Get("remoteip"))
+
+		if r.PostForm.Get("response") == "solved" {
+			fmt.Fprint(w, `{"success":true,"hostname":"example.com","error-codes":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":false,"error-codes":["invalid-input-response"]}`)
+	})
+
+	result, err := verifier.Verify("solved", "190.152.10.4")
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "example.com", result.Hostname)
+
+	result, err = verifier.Verify("forged", "190.152.10.4")
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, []string{"invalid-input-response"}, result.ErrorCodes)
+}
+
+func TestSiteverifyVerifier_RecaptchaScore(t *testing.T) {
+	verifier := newTestVerifier(t, ProviderRecaptcha, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		score := map[string]string{"human": "0.9", "bot": "0.1"}[r.PostForm.Get("response")]
+		fmt.Fprintf(w, `{"success":true,"score":%s}`, score)
+	})
+
+	result, err := verifier.Verify("human", "")
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+
+	result, err = verifier.Verify("bot", "")
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.ErrorCodes, "score-too-low")
+}
+
+func TestSiteverifyVerifier_ProviderError(t *testing.T) {
+	verifier := newTestVerifier(t, ProviderHCaptcha, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := verifier.Verify("solved", "")
+	assert.ErrorContains(t, err, "status 502")
+
+	_, err = NewSiteverifyVerifier("captchaco", "secret", 0, 0)
+	assert.Error(t, err)
+	_, err = NewSiteverifyVerifier(ProviderHCaptcha, "", 0, 0)
+	assert.Error(t, err)
+	assert.True(t, IsValidProvider(ProviderNone))
+	assert.True(t, IsValidEndpoint(EndpointInquiry))
+	assert.False(t, IsValidEndpoint("login"))
+}
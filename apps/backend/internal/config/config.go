@@ -1,12 +1,33 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"realty-core/internal/auth"
+	"realty-core/internal/cache"
+	"realty-core/internal/captcha"
+	"realty-core/internal/domain"
+	"realty-core/internal/einvoice"
+	"realty-core/internal/gcal"
 	"realty-core/internal/logging"
+	"realty-core/internal/middleware"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/pii"
+	"realty-core/internal/processors"
+	"realty-core/internal/repository"
+	"realty-core/internal/routing"
+	"realty-core/internal/scheduler"
+	"realty-core/internal/secrets"
+	"realty-core/internal/security"
+	"realty-core/internal/sms"
+	"realty-core/internal/storage"
+	"realty-core/internal/translation"
+	"realty-core/internal/voice"
 )
 
 // Config holds all application configuration
@@ -17,7 +38,37 @@ type Config struct {
 	Logging  LoggingConfig
 	Security SecurityConfig
 	Image    ImageConfig
+	Video    VideoConfig
+	Document DocumentConfig
 	JWT      JWTConfig
+	Search   SearchConfig
+	Agency   AgencyConfig
+	Listing  ListingConfig
+	Currency CurrencyConfig
+	Routing  RoutingConfig
+	Translation TranslationConfig
+	Calendar CalendarConfig
+	CallMasking CallMaskingConfig
+	Payments PaymentsConfig
+	Invoicing InvoicingConfig
+	SMTP     SMTPConfig
+	Alerting AlertingConfig
+	Metrics  MetricsConfig
+	LoadShedding LoadSheddingConfig
+	MicroCache MicroCacheConfig
+	ScraperGuard ScraperGuardConfig
+	Secrets  SecretsConfig
+	Tenancy  TenancyConfig
+	Webhook  WebhookConfig
+	Outbox   OutboxConfig
+	EventBus EventBusConfig
+	Scheduler SchedulerConfig
+	PublicAPI PublicAPIConfig
+	Widget   WidgetConfig
+	Retention RetentionConfig
+	Backup   BackupConfig
+	Startup  StartupConfig
+	CORS     CORSConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -27,7 +78,7 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
 	MaxHeaderBytes  int
-	CORSOrigins     []string
+	CORSOrigins     []string // see CORSConfig; * allows any origin without credentials
 	Environment     string // development, staging, production
 }
 
@@ -38,6 +89,16 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+	// CountPolicies sets how paginated endpoints count their totals, see
+	// repository.ParseCountPolicies
+	CountPolicies   string
+	// PreparedStatements runs the hot property queries as prepared statements; disable
+	// it behind poolers in transaction mode such as PgBouncer
+	PreparedStatements bool
+	// PoolMonitorInterval is how often the connection pool is sampled into metrics, and
+	// PoolWaitWarning the average wait for a connection that warns of an exhausted pool
+	PoolMonitorInterval time.Duration
+	PoolWaitWarning     time.Duration
 }
 
 // CacheConfig holds caching configuration
@@ -47,6 +108,12 @@ type CacheConfig struct {
 	MaxSizeBytes    int64
 	TTL             time.Duration
 	CleanupInterval time.Duration
+	PropertyTTL     time.Duration // property cache entries; reloadable
+	SearchTTL       time.Duration // cached search results and facets; reloadable
+	StatisticsTTL   time.Duration // cached statistics; reloadable
+	// InvalidationListen invalidates cached properties changed by other instances as the
+	// database notifies them
+	InvalidationListen bool
 }
 
 // LoggingConfig holds logging configuration
@@ -65,6 +132,35 @@ type SecurityConfig struct {
 	RateLimitPerMinute  int
 	MaxUploadSizeMB     int
 	AllowedImageTypes   []string
+	GeoIPProvider       string        // none, maxmind
+	GeoIPEndpoint       string        // MaxMind web service, GeoIP2 or GeoLite2 City
+	GeoIPAccountID      string
+	GeoIPLicenseKey     string
+	GeoIPTimeout        time.Duration
+	GeoIPCacheTTL       time.Duration
+	BlockedCountries    []string      // ISO 3166-1 alpha-2 codes denied access; reloadable
+	CaptchaProvider     string        // none, recaptcha, hcaptcha, turnstile
+	CaptchaSecret       string
+	CaptchaMinScore     float64       // lowest accepted reCAPTCHA v3 score
+	CaptchaTimeout      time.Duration
+	CaptchaEndpoints    []string      // registration, password_reset, inquiry
+	CaptchaBypassRoles  []string      // authenticated roles that skip the challenge
+	PIIEncryptionKey    string        // plain secret or JSON key set, see pii.ParseKeySet; empty disables
+	EmailChangeTTL      time.Duration // how long an email change can be confirmed
+	EmailChangeConfirmURL string      // page receiving the token sent to the new address
+	EmailChangeCancelURL  string      // page receiving the token sent to the current address
+	SMSProvider         string        // none, log, twilio
+	TwilioAccountSID    string
+	TwilioAuthToken     string
+	TwilioFromNumber    string        // Twilio number or messaging service SID
+	SMSTimeout          time.Duration
+	CSP                 string        // enforced Content-Security-Policy; empty sends none
+	CSPReportOnly       string        // policy only reported, to try before enforcing it
+	CSPReportURI        string        // where violations are reported; empty disables reporting
+	CSPRoutes           string        // per-route policies, see middleware.ParseCSPRoutes
+	HSTSMaxAge          time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload         bool
 }
 
 // ImageConfig holds image processing configuration
@@ -75,6 +171,78 @@ type ImageConfig struct {
 	Quality         int
 	ThumbnailSizes  []int
 	AllowedFormats  []string
+	UploadConcurrency int // parallel workers for batch uploads
+	MaxConcurrentDecodes int           // images decoded at once by an instance
+	DecodeMemoryMB       int           // estimated memory of the pixels decoded at once
+	MaxMegapixels        int           // largest image accepted for processing
+	DecodeWait           time.Duration // wait for decoding capacity before answering 429
+	StorageBackend    string // local, s3
+	ServePrefix       string        // URL path stored image files are served under
+	ServeCacheMaxAge  time.Duration // how long browsers and CDNs reuse served image files
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PublicBaseURL   string
+	S3UsePathStyle    bool
+	UploadURLTTL      time.Duration // validity of presigned direct upload URLs
+	PreserveOrientation bool        // keep EXIF orientation when stripping photo metadata
+	ModerationProvider  string      // none, http
+	ModerationURL       string      // classifier endpoint for the http provider
+	ModerationFlagThreshold       float64
+	ModerationQuarantineThreshold float64
+	AnalysisProvider      string        // none, http
+	AnalysisURL           string        // scene classifier endpoint for the http provider
+	AnalysisTimeout       time.Duration
+	AnalysisBatchSize     int           // photos analyzed per image-analysis job run
+	AnalysisMinConfidence float64       // labels below it suggest nothing
+	CDNProvider          string        // none, cloudfront, cloudflare
+	CDNBaseURL           string        // public CDN host image URLs are rewritten to
+	CDNSignURLs          bool
+	CDNSignedURLTTL      time.Duration
+	CDNDistributionID    string        // CloudFront
+	CDNKeyPairID         string        // CloudFront
+	CDNPrivateKeyPath    string        // CloudFront
+	CDNAccessKeyID       string        // CloudFront invalidations
+	CDNSecretAccessKey   string        // CloudFront invalidations
+	CDNZoneID            string        // Cloudflare
+	CDNAPIToken          string        // Cloudflare
+	CDNSigningKey        string        // Cloudflare token authentication secret
+	GCEnabled            bool          // scheduled orphaned file/record collection
+	GCInterval           time.Duration
+	GCRetention          time.Duration // minimum age of orphans before removal
+	GCDryRun             bool
+	WarmEnabled          bool          // pre-load renditions of featured and most viewed properties
+	WarmInterval         time.Duration
+	WarmFeaturedLimit    int
+	WarmTopViewed        int
+	WarmImagesPerProperty int
+	WarmPresets          []string      // image presets warmed besides THUMBNAIL_SIZES
+	Presets              string        // renditions served publicly as name:WIDTHxHEIGHT:quality:crop:format,...
+	MaxImagesPerProperty int           // limit for properties not managed by an agency
+	QuotaPlans           string        // agency plans as name:max_images:storage_mb,...
+	DefaultQuotaPlan     string
+}
+
+// VideoConfig holds property video upload and transcoding configuration
+type VideoConfig struct {
+	StoragePath      string
+	MaxSizeMB        int
+	MaxPerProperty   int
+	FFmpegPath       string
+	FFprobePath      string
+	Workers          int
+	QueueSize        int
+	TranscodeTimeout time.Duration
+	TempDir          string
+}
+
+// DocumentConfig holds property document upload configuration
+type DocumentConfig struct {
+	StoragePath    string // kept outside the public uploads directory; private documents are served by the API
+	MaxSizeMB      int
+	MaxPerProperty int
 }
 
 // JWTConfig holds JWT authentication configuration
@@ -83,6 +251,307 @@ type JWTConfig struct {
 	AccessTokenTTL   time.Duration
 	RefreshTokenTTL  time.Duration
 	Issuer           string
+	SigningAlgorithm   string        // HS256, EdDSA, RS256
+	KeyGracePeriod     time.Duration // how long a rotated-out key pair keeps verifying tokens
+	KeyEncryptionKey   string        // seals stored private keys
+	KeyRefreshInterval time.Duration // how often key pairs rotated by other instances are loaded
+}
+
+// SearchConfig holds search backend configuration
+type SearchConfig struct {
+	Backend           string // postgres, meilisearch
+	MeilisearchURL    string
+	MeilisearchAPIKey string
+	IndexName         string
+	IndexQueueSize    int
+}
+
+// AgencyConfig holds agency team management configuration
+type AgencyConfig struct {
+	InvitationTTL       time.Duration
+	InvitationAcceptURL string // page receiving the invitation token
+}
+
+// ListingConfig holds listing lifecycle configuration
+type ListingConfig struct {
+	FeaturedExpiryInterval  time.Duration // time between featured expiration runs
+	FeaturedDefaultDuration time.Duration // promotion length when none is requested
+	ExpiryInterval          time.Duration // time between stale listing expiration runs
+	ExpiryDays              int           // publication window of listings without an agency plan
+	StaleDays               int           // age from which agencies' stale listing report includes a listing
+	ShareURL                string        // public host serving /l/{code} short links
+	PropertyPageURL         string        // property page short links redirect to, slug appended
+
+	DescriptionContactPolicy   string   // off, flag, obfuscate or reject phones, emails and WhatsApp links
+	DescriptionProfanityPolicy string   // off, flag, obfuscate or reject profanity
+	DescriptionProfanityWords  []string // words the profanity policy looks for, accents ignored
+}
+
+// CurrencyConfig holds display currency conversion configuration
+type CurrencyConfig struct {
+	Enabled      bool
+	RatesURL     string        // ExchangeRate-API compatible endpoint, base currency appended
+	RatesTTL     time.Duration // how long fetched rates are served before refreshing
+	RatesTimeout time.Duration
+}
+
+// RoutingConfig holds the routing provider of commute estimates
+type RoutingConfig struct {
+	Provider string        // none, osrm, google
+	Endpoint string        // OSRM server or Google Distance Matrix API, provider default when empty
+	APIKey   string        // Google Maps API key
+	Timeout  time.Duration
+	CacheTTL time.Duration // how long routes are reused for the same points
+}
+
+// TranslationConfig holds the machine translation provider of listing content
+type TranslationConfig struct {
+	Provider  string        // none, deepl, libretranslate
+	Endpoint  string        // provider translate endpoint, provider default when empty
+	APIKey    string        // DeepL API key, or LibreTranslate key when the server requires one
+	Timeout   time.Duration
+	CacheTTL  time.Duration // how long translations of the same text are reused
+	OnPublish bool          // translate published listings missing a translation
+}
+
+// CalendarConfig holds the Google OAuth client agents connect their calendar with
+type CalendarConfig struct {
+	GoogleClientID     string        // calendar sync is disabled when empty
+	GoogleClientSecret string
+	GoogleRedirectURL  string        // OAuth callback, e.g. https://api.example.com/api/calendar/google/callback
+	Timeout            time.Duration
+}
+
+// CallMaskingConfig holds the Twilio proxy numbers buyers and agents call each other
+// through. Twilio requests are signed with TWILIO_AUTH_TOKEN.
+type CallMaskingConfig struct {
+	ProxyNumbers []string      // E.164 Twilio numbers lent to leads; calls are disabled when empty
+	SessionTTL   time.Duration // how long a lead keeps its proxy number
+	WebhookURL   string        // public URL of /api/calls, e.g. https://api.example.com/api/calls
+}
+
+// PaymentsConfig holds the payment gateway confirming rent payments
+type PaymentsConfig struct {
+	RentGatewaySecret string // signs gateway confirmations; they are rejected when empty
+}
+
+// InvoicingConfig holds the SRI electronic invoicing of the fees agencies pay
+type InvoicingConfig struct {
+	Provider           string // none, log, http
+	Endpoint           string // invoicing service signing documents and relaying them to the SRI
+	APIKey             string
+	Timeout            time.Duration
+	IssuerRUC          string
+	BusinessName       string // razón social
+	TradeName          string // nombre comercial
+	Address            string // dirección matriz
+	Establishment      string // 3-digit establishment code, e.g. 001
+	EmissionPoint      string // 3-digit emission point code, e.g. 001
+	Environment        string // 1 test, 2 production
+	RequiredAccounting bool   // obligado a llevar contabilidad
+	IVARate            float64
+	FeaturedDailyPrice float64 // price of a featured listing day before IVA
+}
+
+// SMTPConfig holds outgoing mail server configuration
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// AlertingConfig holds the anomaly rules on business metrics and the channels alerts
+// are sent to besides the log
+type AlertingConfig struct {
+	AnomalyRules    string // overrides of the default rules, see monitoring.ParseAnomalyRules
+	SlackWebhookURL string
+	EmailRecipients []string // alert emails are sent through the SMTP server
+	Timeout         time.Duration
+}
+
+// MetricsConfig holds the labels of request metrics. Agency and tenant labels are
+// optional since every agency adds series; limits fold the rest into "other".
+type MetricsConfig struct {
+	AgencyLabels    bool
+	MaxRouteLabels  int // distinct route templates
+	MaxAgencyLabels int // distinct agencies, and tenants
+}
+
+// LoadSheddingConfig holds the concurrency limits requests are shed past, answering
+// 503 with Retry-After. Health checks and auth routes are never shed.
+type LoadSheddingConfig struct {
+	Enabled       bool
+	MaxConcurrent int           // requests served at once on routes without a limit of their own
+	QueueTimeout  time.Duration // how long a request waits for a slot
+	RouteLimits   string        // see middleware.ParseRouteLimits
+	RetryAfter    time.Duration
+}
+
+// MicroCacheConfig holds the micro-cache of anonymous GETs on public list endpoints.
+// The memory backend only invalidates the instance serving a write; use Redis when
+// several instances serve the API.
+type MicroCacheConfig struct {
+	Enabled  bool
+	Backend  string        // memory or redis
+	TTL      time.Duration // 5-30s
+	RedisURL string        // redis:// or rediss:// URL
+	Timeout  time.Duration // Redis command timeout
+	Capacity int           // responses kept by the memory backend
+}
+
+// ScraperGuardConfig holds the anti-scraping friction of the public catalog, see
+// security.ScraperGuard. Limits count requests per client fingerprint and window.
+type ScraperGuardConfig struct {
+	Enabled         bool
+	Paths           []string      // catalog path prefixes guarded
+	SoftLimit       int           // requests per window served without delay
+	HardLimit       int           // requests per window past which requests get 429
+	Window          time.Duration
+	DelayStep       time.Duration // delay added for each request over the soft limit
+	MaxDelay        time.Duration
+	DeepPage        int           // page tokens are needed from a random page past this one; 0 disables
+	PageTokenSecret string        // signs page tokens; set it when several instances serve the API
+	PageTokenTTL    time.Duration
+	TrustedProxies  []string      // proxy addresses or CIDRs whose X-Forwarded-For entries identify clients
+}
+
+// TenancyConfig holds multi-tenancy configuration. When disabled every record belongs
+// to domain.DefaultTenantID and requests are not resolved to tenants.
+type TenancyConfig struct {
+	Enabled         bool
+	Header          string        // request header naming the tenant, checked before the host
+	DefaultTenant   string        // serves hosts no tenant claims; empty rejects them
+	RefreshInterval time.Duration // how often tenants changed by other instances are loaded
+}
+
+// WebhookConfig holds webhook delivery configuration
+type WebhookConfig struct {
+	Timeout      time.Duration // per delivery attempt
+	PollInterval time.Duration // time between runs delivering due events
+	BatchSize    int
+	MaxAttempts  int // attempts before a delivery is marked failed
+}
+
+// OutboxConfig holds transactional outbox configuration. When enabled, listing,
+// deal and application events are stored with the change and dispatched by a worker
+// instead of being published after the write.
+type OutboxConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int           // attempts before an event is marked failed
+	Retention    time.Duration // how long published events are kept
+}
+
+// EventBusConfig holds the message broker domain events are published to
+type EventBusConfig struct {
+	Backend       string // none, log, nats
+	SubjectPrefix string
+	Timeout       time.Duration
+	NATSURL       string
+	NATSToken     string
+	NATSName      string
+}
+
+// SchedulerConfig holds the scheduled job configuration. Schedules overrides the
+// default cron expression of jobs by name; "off" only runs a job when triggered.
+type SchedulerConfig struct {
+	Enabled   bool
+	Timezone  string
+	Schedules map[string]string
+}
+
+// PublicAPIConfig holds the configuration of the public read-only API. Tiers are
+// "name:per_minute:per_day,...", see domain.ParseAPIKeyTiers.
+type PublicAPIConfig struct {
+	Enabled            bool
+	Tiers              string
+	DefaultTier        string
+	KeyCacheTTL        time.Duration // how long a revoked key keeps working on other instances
+	UsageFlushInterval time.Duration // how often request counts are written
+}
+
+// WidgetConfig holds the configuration of the listing widgets agencies embed in their
+// own sites
+type WidgetConfig struct {
+	Enabled       bool
+	SigningSecret string        // signs embed tokens; rotating it invalidates every token
+	TokenTTL      time.Duration // how long embed tokens are valid
+	CacheMaxAge   time.Duration // how long browsers and CDNs reuse widget responses
+}
+
+// RetentionConfig holds how long old records are kept before the retention job prunes
+// them. Days holds the RETENTION_<TARGET>_DAYS set, by domain retention target; 0 keeps
+// a target forever.
+type RetentionConfig struct {
+	Enabled   bool
+	DryRun    bool // scheduled runs only report what they would delete
+	BatchSize int  // rows deleted per statement
+	Days      map[string]int
+}
+
+// BackupConfig holds the verification of the database dumps, restored by the admin
+// backup-verify command into a temporary schema of the live database
+type BackupConfig struct {
+	Dir            string        // directory pg_dump writes the dumps to
+	PsqlPath       string        // psql binary
+	PgRestorePath  string        // pg_restore binary, for custom format dumps
+	RestoreTimeout time.Duration // how long a restore may take
+	MaxAge         time.Duration // how long a passed verification vouches for the backups
+	MinRowRatio    float64       // share of the live rows of a table a dump must hold
+}
+
+// StartupConfig holds the checks an instance passes before reporting ready: schema
+// version, storage writes, cache and search index
+type StartupConfig struct {
+	Enabled      bool
+	GracePeriod  time.Duration // how long failing checks are retried before startup fails
+	Interval     time.Duration // wait between attempts
+	CheckTimeout time.Duration // how long a single check may take
+}
+
+// CORSConfig holds the cross-origin policy besides the origins of Server.CORSOrigins.
+// Lists left empty use the middleware defaults; Routes overrides the policy by path
+// prefix, see middleware.ParseCORSRoutes.
+type CORSConfig struct {
+	CredentialOrigins []string // origins allowed to send cookies and HTTP auth
+	Methods           []string
+	Headers           []string
+	ExposedHeaders    []string
+	MaxAge            time.Duration // how long browsers cache preflight responses
+	Routes            string
+}
+
+// SecretsConfig holds the secrets manager credentials are loaded from. Each *Ref names
+// a secret, optionally with #field for a field of a JSON secret; empty refs keep the
+// value from the environment.
+type SecretsConfig struct {
+	Backend         string // env, aws, vault, gcp
+	Timeout         time.Duration
+	RefreshInterval time.Duration // how often the JWT keys are re-read; 0 disables
+
+	AWSRegion          string
+	AWSEndpoint        string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+
+	VaultAddr      string
+	VaultToken     string
+	VaultMount     string
+	VaultNamespace string
+
+	GCPProject     string
+	GCPAccessToken string
+
+	JWTKeyRef              string // plain secret or JSON key set, see auth.ParseKeySet
+	JWTKeyEncryptionKeyRef string
+	DatabaseURLRef         string
+	SMTPUsernameRef        string
+	SMTPPasswordRef        string
+	PIIEncryptionKeyRef    string // plain secret or JSON key set, see pii.ParseKeySet
 }
 
 // LoadConfig loads configuration from environment variables with defaults
@@ -94,7 +563,7 @@ func LoadConfig() *Config {
 			WriteTimeout:    getEnvDuration("WRITE_TIMEOUT", 30*time.Second),
 			IdleTimeout:     getEnvDuration("IDLE_TIMEOUT", 120*time.Second),
 			MaxHeaderBytes:  getEnvInt("MAX_HEADER_BYTES", 1<<20), // 1MB
-			CORSOrigins:     getEnvList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+			CORSOrigins:     getEnvList("CORS_ALLOWED_ORIGINS", defaultCORSOrigins()),
 			Environment:     getEnv("ENVIRONMENT", "development"),
 		},
 		Database: DatabaseConfig{
@@ -103,6 +572,10 @@ func LoadConfig() *Config {
 			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 			ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			CountPolicies:   getEnv("DB_COUNT_POLICIES", "properties.list=estimate:10000;properties.filter=cached:30s;properties.search=cached:30s;properties.search_ranked=cached:30s;properties.advanced_search=cached:30s"),
+			PreparedStatements: getEnvBool("DB_PREPARED_STATEMENTS", true),
+			PoolMonitorInterval: getEnvDuration("DB_POOL_MONITOR_INTERVAL", 30*time.Second),
+			PoolWaitWarning:     getEnvDuration("DB_POOL_WAIT_WARNING", 50*time.Millisecond),
 		},
 		Cache: CacheConfig{
 			Enabled:         getEnvBool("CACHE_ENABLED", true),
@@ -110,6 +583,10 @@ func LoadConfig() *Config {
 			MaxSizeBytes:    int64(getEnvInt("CACHE_SIZE_MB", 100)) * 1024 * 1024,
 			TTL:             getEnvDuration("CACHE_TTL", 24*time.Hour),
 			CleanupInterval: getEnvDuration("CACHE_CLEANUP_INTERVAL", 10*time.Minute),
+			PropertyTTL:     getEnvDuration("CACHE_PROPERTY_TTL", 5*time.Minute),
+			SearchTTL:       getEnvDuration("CACHE_SEARCH_TTL", time.Minute),
+			StatisticsTTL:   getEnvDuration("CACHE_STATISTICS_TTL", 15*time.Minute),
+			InvalidationListen: getEnvBool("CACHE_INVALIDATION_LISTEN", true),
 		},
 		Logging: LoggingConfig{
 			Level:       logging.ParseLogLevel(getEnv("LOG_LEVEL", "INFO")),
@@ -124,6 +601,35 @@ func LoadConfig() *Config {
 			RateLimitPerMinute:  getEnvInt("RATE_LIMIT_PER_MINUTE", 100),
 			MaxUploadSizeMB:     getEnvInt("MAX_UPLOAD_SIZE_MB", 10),
 			AllowedImageTypes:   getEnvList("ALLOWED_IMAGE_TYPES", []string{"image/jpeg", "image/png", "image/webp"}),
+			GeoIPProvider:       strings.ToLower(getEnv("GEOIP_PROVIDER", "none")),
+			GeoIPEndpoint:       getEnv("GEOIP_MAXMIND_ENDPOINT", "https://geoip.maxmind.com/geoip/v2.1/city"),
+			GeoIPAccountID:      getEnv("GEOIP_MAXMIND_ACCOUNT_ID", ""),
+			GeoIPLicenseKey:     getEnv("GEOIP_MAXMIND_LICENSE_KEY", ""),
+			GeoIPTimeout:        getEnvDuration("GEOIP_TIMEOUT", 2*time.Second),
+			GeoIPCacheTTL:       getEnvDuration("GEOIP_CACHE_TTL", 24*time.Hour),
+			BlockedCountries:    domain.NormalizeCountryCodes(getEnvList("BLOCKED_COUNTRIES", []string{})),
+			CaptchaProvider:     strings.ToLower(getEnv("CAPTCHA_PROVIDER", captcha.ProviderNone)),
+			CaptchaSecret:       getEnv("CAPTCHA_SECRET", ""),
+			CaptchaMinScore:     getEnvFloat("CAPTCHA_MIN_SCORE", captcha.DefaultMinScore),
+			CaptchaTimeout:      getEnvDuration("CAPTCHA_TIMEOUT", 5*time.Second),
+			CaptchaEndpoints:    getEnvList("CAPTCHA_ENDPOINTS", captcha.DefaultEndpoints),
+			CaptchaBypassRoles:  getEnvList("CAPTCHA_BYPASS_ROLES", []string{string(domain.RoleAdmin), string(domain.RoleAgency), string(domain.RoleAgent)}),
+			PIIEncryptionKey:    getEnv("PII_ENCRYPTION_KEY", ""),
+			EmailChangeTTL:      getEnvDuration("EMAIL_CHANGE_TTL", domain.DefaultEmailChangeTTL),
+			EmailChangeConfirmURL: getEnv("EMAIL_CHANGE_CONFIRM_URL", "http://localhost:3000/account/email/confirm"),
+			EmailChangeCancelURL:  getEnv("EMAIL_CHANGE_CANCEL_URL", "http://localhost:3000/account/email/cancel"),
+			SMSProvider:         strings.ToLower(getEnv("SMS_PROVIDER", sms.ProviderNone)),
+			TwilioAccountSID:    getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:     getEnv("TWILIO_AUTH_TOKEN", ""),
+			TwilioFromNumber:    getEnv("TWILIO_FROM_NUMBER", ""),
+			SMSTimeout:          getEnvDuration("SMS_TIMEOUT", 10*time.Second),
+			CSP:                 getEnv("SECURITY_CSP", middleware.DefaultCSP),
+			CSPReportOnly:       getEnv("SECURITY_CSP_REPORT_ONLY", ""),
+			CSPReportURI:        getEnv("SECURITY_CSP_REPORT_URI", middleware.CSPReportPath),
+			CSPRoutes:           getEnv("SECURITY_CSP_ROUTES", middleware.DefaultCSPRoutes),
+			HSTSMaxAge:          getEnvDuration("SECURITY_HSTS_MAX_AGE", middleware.DefaultHSTSMaxAge),
+			HSTSIncludeSubdomains: getEnvBool("SECURITY_HSTS_INCLUDE_SUBDOMAINS", true),
+			HSTSPreload:         getEnvBool("SECURITY_HSTS_PRELOAD", false),
 		},
 		Image: ImageConfig{
 			StoragePath:    getEnv("IMAGE_STORAGE_PATH", "uploads/images"),
@@ -132,14 +638,319 @@ func LoadConfig() *Config {
 			Quality:        getEnvInt("IMAGE_QUALITY", 85),
 			ThumbnailSizes: getEnvIntList("THUMBNAIL_SIZES", []int{150, 300, 600}),
 			AllowedFormats: getEnvList("ALLOWED_IMAGE_FORMATS", []string{"jpeg", "jpg", "png", "webp"}),
+			UploadConcurrency: getEnvInt("IMAGE_UPLOAD_CONCURRENCY", 4),
+			MaxConcurrentDecodes: getEnvInt("IMAGE_MAX_CONCURRENT_DECODES", 4),
+			DecodeMemoryMB:       getEnvInt("IMAGE_DECODE_MEMORY_MB", 512),
+			MaxMegapixels:        getEnvInt("IMAGE_MAX_MEGAPIXELS", 100),
+			DecodeWait:           getEnvDuration("IMAGE_DECODE_WAIT", 10*time.Second),
+			StorageBackend:    strings.ToLower(getEnv("IMAGE_STORAGE_BACKEND", "local")),
+			ServePrefix:       getEnv("IMAGE_SERVE_PREFIX", "/uploads/images"),
+			ServeCacheMaxAge:  getEnvDuration("IMAGE_SERVE_CACHE_MAX_AGE", 24*time.Hour),
+			S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+			S3Region:          getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			S3PublicBaseURL:   getEnv("S3_PUBLIC_BASE_URL", ""),
+			S3UsePathStyle:    getEnvBool("S3_USE_PATH_STYLE", false),
+			UploadURLTTL:      getEnvDuration("IMAGE_UPLOAD_URL_TTL", 15*time.Minute),
+			PreserveOrientation: getEnvBool("IMAGE_PRESERVE_ORIENTATION", true),
+			ModerationProvider:  strings.ToLower(getEnv("IMAGE_MODERATION_PROVIDER", "none")),
+			ModerationURL:       getEnv("IMAGE_MODERATION_URL", ""),
+			ModerationFlagThreshold:       getEnvFloat("IMAGE_MODERATION_FLAG_THRESHOLD", 0.5),
+			ModerationQuarantineThreshold: getEnvFloat("IMAGE_MODERATION_QUARANTINE_THRESHOLD", 0.85),
+			AnalysisProvider:      strings.ToLower(getEnv("IMAGE_ANALYSIS_PROVIDER", "none")),
+			AnalysisURL:           getEnv("IMAGE_ANALYSIS_URL", ""),
+			AnalysisTimeout:       getEnvDuration("IMAGE_ANALYSIS_TIMEOUT", 15*time.Second),
+			AnalysisBatchSize:     getEnvInt("IMAGE_ANALYSIS_BATCH_SIZE", 50),
+			AnalysisMinConfidence: getEnvFloat("IMAGE_ANALYSIS_MIN_CONFIDENCE", 0.6),
+			CDNProvider:          strings.ToLower(getEnv("IMAGE_CDN_PROVIDER", "none")),
+			CDNBaseURL:           getEnv("IMAGE_CDN_BASE_URL", ""),
+			CDNSignURLs:          getEnvBool("IMAGE_CDN_SIGN_URLS", false),
+			CDNSignedURLTTL:      getEnvDuration("IMAGE_CDN_SIGNED_URL_TTL", time.Hour),
+			CDNDistributionID:    getEnv("CLOUDFRONT_DISTRIBUTION_ID", ""),
+			CDNKeyPairID:         getEnv("CLOUDFRONT_KEY_PAIR_ID", ""),
+			CDNPrivateKeyPath:    getEnv("CLOUDFRONT_PRIVATE_KEY_PATH", ""),
+			CDNAccessKeyID:       getEnv("CLOUDFRONT_ACCESS_KEY_ID", ""),
+			CDNSecretAccessKey:   getEnv("CLOUDFRONT_SECRET_ACCESS_KEY", ""),
+			CDNZoneID:            getEnv("CLOUDFLARE_ZONE_ID", ""),
+			CDNAPIToken:          getEnv("CLOUDFLARE_API_TOKEN", ""),
+			CDNSigningKey:        getEnv("CLOUDFLARE_SIGNING_KEY", ""),
+			GCEnabled:            getEnvBool("IMAGE_GC_ENABLED", true),
+			GCInterval:           getEnvDuration("IMAGE_GC_INTERVAL", 24*time.Hour),
+			GCRetention:          getEnvDuration("IMAGE_GC_RETENTION", 72*time.Hour),
+			GCDryRun:             getEnvBool("IMAGE_GC_DRY_RUN", false),
+			WarmEnabled:          getEnvBool("IMAGE_WARM_ENABLED", true),
+			WarmInterval:         getEnvDuration("IMAGE_WARM_INTERVAL", time.Hour),
+			WarmFeaturedLimit:    getEnvInt("IMAGE_WARM_FEATURED_LIMIT", 50),
+			WarmTopViewed:        getEnvInt("IMAGE_WARM_TOP_VIEWED", 50),
+			WarmImagesPerProperty: getEnvInt("IMAGE_WARM_IMAGES_PER_PROPERTY", 3),
+			WarmPresets:          getEnvList("IMAGE_WARM_PRESETS", []string{"thumb", "card", "hero"}),
+			Presets:              getEnv("IMAGE_PRESETS", domain.DefaultImagePresetsSpec),
+			MaxImagesPerProperty: getEnvInt("IMAGE_MAX_PER_PROPERTY", 50),
+			QuotaPlans:           getEnv("IMAGE_QUOTA_PLANS", "basic:20:1024,professional:50:10240,enterprise:100:102400"),
+			DefaultQuotaPlan:     strings.ToLower(getEnv("IMAGE_QUOTA_DEFAULT_PLAN", domain.DefaultImageQuotaPlan)),
+		},
+		Video: VideoConfig{
+			StoragePath:      getEnv("VIDEO_STORAGE_PATH", "uploads/videos"),
+			MaxSizeMB:        getEnvInt("VIDEO_MAX_SIZE_MB", 500),
+			MaxPerProperty:   getEnvInt("VIDEO_MAX_PER_PROPERTY", 5),
+			FFmpegPath:       getEnv("FFMPEG_PATH", "ffmpeg"),
+			FFprobePath:      getEnv("FFPROBE_PATH", "ffprobe"),
+			Workers:          getEnvInt("VIDEO_TRANSCODE_WORKERS", 1),
+			QueueSize:        getEnvInt("VIDEO_TRANSCODE_QUEUE_SIZE", 50),
+			TranscodeTimeout: getEnvDuration("VIDEO_TRANSCODE_TIMEOUT", 30*time.Minute),
+			TempDir:          getEnv("VIDEO_TEMP_DIR", os.TempDir()),
+		},
+		Document: DocumentConfig{
+			StoragePath:    getEnv("DOCUMENT_STORAGE_PATH", "storage/documents"),
+			MaxSizeMB:      getEnvInt("DOCUMENT_MAX_SIZE_MB", 20),
+			MaxPerProperty: getEnvInt("DOCUMENT_MAX_PER_PROPERTY", domain.MaxDocumentsPerProperty),
 		},
 		JWT: JWTConfig{
 			SecretKey:        getEnv("JWT_SECRET_KEY", "realty-core-jwt-secret-key-change-in-production-2025"),
 			AccessTokenTTL:   getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
 			RefreshTokenTTL:  getEnvDuration("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour), // 7 days
 			Issuer:           getEnv("JWT_ISSUER", "realty-core-api"),
+			SigningAlgorithm:   getEnv("JWT_SIGNING_ALGORITHM", "HS256"),
+			KeyGracePeriod:     getEnvDuration("JWT_KEY_GRACE_PERIOD", 7*24*time.Hour),
+			KeyEncryptionKey:   getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
+			KeyRefreshInterval: getEnvDuration("JWT_KEY_REFRESH_INTERVAL", time.Minute),
+		},
+		Search: SearchConfig{
+			Backend:           strings.ToLower(getEnv("SEARCH_BACKEND", "postgres")),
+			MeilisearchURL:    getEnv("MEILISEARCH_URL", "http://localhost:7700"),
+			MeilisearchAPIKey: getEnv("MEILISEARCH_API_KEY", ""),
+			IndexName:         getEnv("SEARCH_INDEX_NAME", "properties"),
+			IndexQueueSize:    getEnvInt("SEARCH_INDEX_QUEUE_SIZE", 1000),
+		},
+		Agency: AgencyConfig{
+			InvitationTTL:       getEnvDuration("AGENCY_INVITATION_TTL", 7*24*time.Hour),
+			InvitationAcceptURL: getEnv("AGENCY_INVITATION_ACCEPT_URL", "http://localhost:3000/invitations/accept"),
+		},
+		Listing: ListingConfig{
+			FeaturedExpiryInterval:  getEnvDuration("LISTING_FEATURED_EXPIRY_INTERVAL", 15*time.Minute),
+			FeaturedDefaultDuration: getEnvDuration("LISTING_FEATURED_DEFAULT_DURATION", domain.DefaultFeaturedDuration),
+			ExpiryInterval:          getEnvDuration("LISTING_EXPIRY_INTERVAL", time.Hour),
+			ExpiryDays:              getEnvInt("LISTING_EXPIRY_DAYS", domain.DefaultListingExpiryDays),
+			StaleDays:               getEnvInt("LISTING_STALE_DAYS", domain.DefaultStaleListingDays),
+			ShareURL:                getEnv("LISTING_SHARE_URL", "http://localhost:8080"),
+			PropertyPageURL:         getEnv("LISTING_PROPERTY_PAGE_URL", "http://localhost:3000/propiedades"),
+
+			DescriptionContactPolicy:   strings.ToLower(getEnv("LISTING_DESCRIPTION_CONTACT_POLICY", domain.DescriptionPolicyObfuscate)),
+			DescriptionProfanityPolicy: strings.ToLower(getEnv("LISTING_DESCRIPTION_PROFANITY_POLICY", domain.DescriptionPolicyFlag)),
+			DescriptionProfanityWords:  getEnvList("LISTING_DESCRIPTION_PROFANITY_WORDS", domain.DefaultProfanityWords),
+		},
+		Currency: CurrencyConfig{
+			Enabled:      getEnvBool("CURRENCY_CONVERSION_ENABLED", true),
+			RatesURL:     getEnv("EXCHANGE_RATES_URL", "https://open.er-api.com/v6/latest"),
+			RatesTTL:     getEnvDuration("EXCHANGE_RATES_TTL", 24*time.Hour),
+			RatesTimeout: getEnvDuration("EXCHANGE_RATES_TIMEOUT", 10*time.Second),
+		},
+		Routing: RoutingConfig{
+			Provider: strings.ToLower(getEnv("ROUTING_PROVIDER", routing.ProviderNone)),
+			Endpoint: getEnv("ROUTING_ENDPOINT", ""),
+			APIKey:   getEnv("ROUTING_GOOGLE_API_KEY", ""),
+			Timeout:  getEnvDuration("ROUTING_TIMEOUT", 5*time.Second),
+			CacheTTL: getEnvDuration("ROUTING_CACHE_TTL", routing.DefaultCacheTTL),
+		},
+		Translation: TranslationConfig{
+			Provider:  strings.ToLower(getEnv("TRANSLATION_PROVIDER", translation.ProviderNone)),
+			Endpoint:  getEnv("TRANSLATION_ENDPOINT", ""),
+			APIKey:    getEnv("TRANSLATION_API_KEY", ""),
+			Timeout:   getEnvDuration("TRANSLATION_TIMEOUT", 10*time.Second),
+			CacheTTL:  getEnvDuration("TRANSLATION_CACHE_TTL", translation.DefaultCacheTTL),
+			OnPublish: getEnvBool("TRANSLATION_ON_PUBLISH", true),
+		},
+		Calendar: CalendarConfig{
+			GoogleClientID:     getEnv("GOOGLE_CALENDAR_CLIENT_ID", ""),
+			GoogleClientSecret: getEnv("GOOGLE_CALENDAR_CLIENT_SECRET", ""),
+			GoogleRedirectURL:  getEnv("GOOGLE_CALENDAR_REDIRECT_URL", ""),
+			Timeout:            getEnvDuration("GOOGLE_CALENDAR_TIMEOUT", 15*time.Second),
+		},
+		CallMasking: CallMaskingConfig{
+			ProxyNumbers: getEnvList("CALL_MASKING_NUMBERS", []string{}),
+			SessionTTL:   getEnvDuration("CALL_MASKING_SESSION_TTL", domain.DefaultCallSessionTTL),
+			WebhookURL:   getEnv("CALL_MASKING_WEBHOOK_URL", ""),
+		},
+		Payments: PaymentsConfig{
+			RentGatewaySecret: getEnv("RENT_GATEWAY_SECRET", ""),
+		},
+		Invoicing: InvoicingConfig{
+			Provider:           strings.ToLower(getEnv("EINVOICE_PROVIDER", einvoice.ProviderNone)),
+			Endpoint:           getEnv("EINVOICE_ENDPOINT", ""),
+			APIKey:             getEnv("EINVOICE_API_KEY", ""),
+			Timeout:            getEnvDuration("EINVOICE_TIMEOUT", 15*time.Second),
+			IssuerRUC:          getEnv("EINVOICE_ISSUER_RUC", ""),
+			BusinessName:       getEnv("EINVOICE_BUSINESS_NAME", ""),
+			TradeName:          getEnv("EINVOICE_TRADE_NAME", ""),
+			Address:            getEnv("EINVOICE_ADDRESS", ""),
+			Establishment:      getEnv("EINVOICE_ESTABLISHMENT", "001"),
+			EmissionPoint:      getEnv("EINVOICE_EMISSION_POINT", "001"),
+			Environment:        getEnv("EINVOICE_ENVIRONMENT", domain.SRIEnvironmentTest),
+			RequiredAccounting: getEnvBool("EINVOICE_REQUIRED_ACCOUNTING", true),
+			IVARate:            getEnvFloat("EINVOICE_IVA_RATE", 15),
+			FeaturedDailyPrice: getEnvFloat("EINVOICE_FEATURED_DAILY_PRICE", 2),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnvInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "no-reply@realty-core.local"),
+		},
+		Alerting: AlertingConfig{
+			AnomalyRules:    getEnv("ALERT_ANOMALY_RULES", ""),
+			SlackWebhookURL: getEnv("ALERT_SLACK_WEBHOOK_URL", ""),
+			EmailRecipients: getEnvList("ALERT_EMAIL_RECIPIENTS", []string{}),
+			Timeout:         getEnvDuration("ALERT_NOTIFY_TIMEOUT", 10*time.Second),
+		},
+		Metrics: MetricsConfig{
+			AgencyLabels:    getEnvBool("METRICS_AGENCY_LABELS", false),
+			MaxRouteLabels:  getEnvInt("METRICS_MAX_ROUTE_LABELS", monitoring.DefaultMaxRouteLabels),
+			MaxAgencyLabels: getEnvInt("METRICS_MAX_AGENCY_LABELS", monitoring.DefaultMaxAgencyLabels),
+		},
+		LoadShedding: LoadSheddingConfig{
+			Enabled:       getEnvBool("LOAD_SHEDDING_ENABLED", true),
+			MaxConcurrent: getEnvInt("LOAD_SHEDDING_MAX_CONCURRENT", middleware.DefaultMaxConcurrentRequests),
+			QueueTimeout:  getEnvDuration("LOAD_SHEDDING_QUEUE_TIMEOUT", middleware.DefaultQueueTimeout),
+			RouteLimits:   getEnv("LOAD_SHEDDING_ROUTE_LIMITS", "/api/images=32:2s"),
+			RetryAfter:    getEnvDuration("LOAD_SHEDDING_RETRY_AFTER", 5*time.Second),
+		},
+		MicroCache: MicroCacheConfig{
+			Enabled:  getEnvBool("MICRO_CACHE_ENABLED", true),
+			Backend:  strings.ToLower(getEnv("MICRO_CACHE_BACKEND", cache.ResponseStoreMemory)),
+			TTL:      getEnvDuration("MICRO_CACHE_TTL", middleware.DefaultMicroCacheTTL),
+			RedisURL: getEnv("MICRO_CACHE_REDIS_URL", ""),
+			Timeout:  getEnvDuration("MICRO_CACHE_TIMEOUT", 500*time.Millisecond),
+			Capacity: getEnvInt("MICRO_CACHE_CAPACITY", 1000),
+		},
+		ScraperGuard: ScraperGuardConfig{
+			Enabled:         getEnvBool("SCRAPER_GUARD_ENABLED", true),
+			Paths:           getEnvList("SCRAPER_GUARD_PATHS", middleware.DefaultScraperGuardPaths),
+			SoftLimit:       getEnvInt("SCRAPER_GUARD_SOFT_LIMIT", 60),
+			HardLimit:       getEnvInt("SCRAPER_GUARD_HARD_LIMIT", 300),
+			Window:          getEnvDuration("SCRAPER_GUARD_WINDOW", time.Minute),
+			DelayStep:       getEnvDuration("SCRAPER_GUARD_DELAY_STEP", 100*time.Millisecond),
+			MaxDelay:        getEnvDuration("SCRAPER_GUARD_MAX_DELAY", 5*time.Second),
+			DeepPage:        getEnvInt("SCRAPER_GUARD_DEEP_PAGE", 10),
+			PageTokenSecret: getEnv("SCRAPER_PAGE_TOKEN_SECRET", ""),
+			PageTokenTTL:    getEnvDuration("SCRAPER_PAGE_TOKEN_TTL", 30*time.Minute),
+			TrustedProxies:  getEnvList("SCRAPER_GUARD_TRUSTED_PROXIES", nil),
+		},
+		Secrets: SecretsConfig{
+			Backend:            strings.ToLower(getEnv("SECRETS_BACKEND", "env")),
+			Timeout:            getEnvDuration("SECRETS_TIMEOUT", 10*time.Second),
+			RefreshInterval:    getEnvDuration("SECRETS_REFRESH_INTERVAL", 5*time.Minute),
+			AWSRegion:          getEnv("AWS_REGION", ""),
+			AWSEndpoint:        getEnv("SECRETS_AWS_ENDPOINT", ""),
+			AWSAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			AWSSessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+			VaultAddr:          getEnv("VAULT_ADDR", ""),
+			VaultToken:         getEnv("VAULT_TOKEN", ""),
+			VaultMount:         getEnv("SECRETS_VAULT_MOUNT", "secret"),
+			VaultNamespace:     getEnv("VAULT_NAMESPACE", ""),
+			GCPProject:         getEnv("GCP_PROJECT", ""),
+			GCPAccessToken:     getEnv("GCP_ACCESS_TOKEN", ""),
+			JWTKeyRef:          getEnv("SECRET_JWT_KEY_REF", ""),
+			JWTKeyEncryptionKeyRef: getEnv("SECRET_JWT_KEY_ENCRYPTION_KEY_REF", ""),
+			DatabaseURLRef:     getEnv("SECRET_DATABASE_URL_REF", ""),
+			SMTPUsernameRef:    getEnv("SECRET_SMTP_USERNAME_REF", ""),
+			SMTPPasswordRef:    getEnv("SECRET_SMTP_PASSWORD_REF", ""),
+			PIIEncryptionKeyRef: getEnv("SECRET_PII_ENCRYPTION_KEY_REF", ""),
+		},
+		Tenancy: TenancyConfig{
+			Enabled:         getEnvBool("MULTI_TENANCY_ENABLED", false),
+			Header:          getEnv("TENANT_HEADER", "X-Tenant-ID"),
+			DefaultTenant:   strings.ToLower(getEnv("TENANT_DEFAULT", domain.DefaultTenantID)),
+			RefreshInterval: getEnvDuration("TENANT_REFRESH_INTERVAL", time.Minute),
+		},
+		Webhook: WebhookConfig{
+			Timeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 15*time.Second),
+			BatchSize:    getEnvInt("WEBHOOK_BATCH_SIZE", 50),
+			MaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", domain.DefaultWebhookMaxAttempts),
+		},
+		Outbox: OutboxConfig{
+			Enabled:      getEnvBool("OUTBOX_ENABLED", true),
+			PollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", 5*time.Second),
+			BatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+			MaxAttempts:  getEnvInt("OUTBOX_MAX_ATTEMPTS", domain.DefaultOutboxMaxAttempts),
+			Retention:    getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
 		},
+		EventBus: EventBusConfig{
+			Backend:       strings.ToLower(getEnv("EVENT_BUS_BACKEND", "none")),
+			SubjectPrefix: getEnv("EVENT_BUS_SUBJECT_PREFIX", "realty"),
+			Timeout:       getEnvDuration("EVENT_BUS_TIMEOUT", 5*time.Second),
+			NATSURL:       getEnv("NATS_URL", "nats://localhost:4222"),
+			NATSToken:     getEnv("NATS_TOKEN", ""),
+			NATSName:      getEnv("NATS_CLIENT_NAME", "realty-core"),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:   getEnvBool("SCHEDULER_ENABLED", true),
+			Timezone:  getEnv("SCHEDULER_TIMEZONE", "America/Guayaquil"),
+			Schedules: getEnvSchedules("SCHEDULER_SCHEDULES"),
+		},
+		PublicAPI: PublicAPIConfig{
+			Enabled:            getEnvBool("PUBLIC_API_ENABLED", true),
+			Tiers:              getEnv("PUBLIC_API_TIERS", domain.DefaultAPIKeyTiersSpec),
+			DefaultTier:        strings.ToLower(getEnv("PUBLIC_API_DEFAULT_TIER", domain.DefaultAPIKeyTier)),
+			KeyCacheTTL:        getEnvDuration("PUBLIC_API_KEY_CACHE_TTL", time.Minute),
+			UsageFlushInterval: getEnvDuration("PUBLIC_API_USAGE_FLUSH_INTERVAL", 30*time.Second),
+		},
+		Widget: WidgetConfig{
+			Enabled:       getEnvBool("WIDGETS_ENABLED", true),
+			SigningSecret: getEnv("WIDGET_SIGNING_SECRET", ""),
+			TokenTTL:      getEnvDuration("WIDGET_TOKEN_TTL", 365*24*time.Hour),
+			CacheMaxAge:   getEnvDuration("WIDGET_CACHE_MAX_AGE", 5*time.Minute),
+		},
+		Retention: RetentionConfig{
+			Enabled:   getEnvBool("RETENTION_ENABLED", true),
+			DryRun:    getEnvBool("RETENTION_DRY_RUN", false),
+			BatchSize: getEnvInt("RETENTION_BATCH_SIZE", domain.DefaultRetentionBatchSize),
+			Days:      getEnvRetentionDays(),
+		},
+		Backup: BackupConfig{
+			Dir:            getEnv("BACKUP_DIR", "./backups"),
+			PsqlPath:       getEnv("BACKUP_PSQL_PATH", "psql"),
+			PgRestorePath:  getEnv("BACKUP_PG_RESTORE_PATH", "pg_restore"),
+			RestoreTimeout: getEnvDuration("BACKUP_RESTORE_TIMEOUT", time.Hour),
+			MaxAge:         getEnvDuration("BACKUP_MAX_AGE", domain.DefaultBackupMaxAge),
+			MinRowRatio:    getEnvFloat("BACKUP_MIN_ROW_RATIO", domain.DefaultBackupMinRowRatio),
+		},
+		Startup: StartupConfig{
+			Enabled:      getEnvBool("STARTUP_CHECKS_ENABLED", true),
+			GracePeriod:  getEnvDuration("STARTUP_GRACE_PERIOD", 2*time.Minute),
+			Interval:     getEnvDuration("STARTUP_CHECK_INTERVAL", 5*time.Second),
+			CheckTimeout: getEnvDuration("STARTUP_CHECK_TIMEOUT", 10*time.Second),
+		},
+		CORS: CORSConfig{
+			CredentialOrigins: getEnvList("CORS_CREDENTIAL_ORIGINS", defaultCORSCredentialOrigins()),
+			Methods:           getEnvList("CORS_ALLOWED_METHODS", nil),
+			Headers:           getEnvList("CORS_ALLOWED_HEADERS", nil),
+			ExposedHeaders:    getEnvList("CORS_EXPOSED_HEADERS", nil),
+			MaxAge:            getEnvDuration("CORS_MAX_AGE", middleware.DefaultCORSMaxAge),
+			Routes:            getEnv("CORS_ROUTES", middleware.DefaultCORSRoutes),
+		},
+	}
+}
+
+// defaultCORSOrigins allows any origin in development only; other environments list
+// their origins in CORS_ALLOWED_ORIGINS
+func defaultCORSOrigins() []string {
+	if strings.ToLower(getEnv("ENVIRONMENT", "development")) == "development" {
+		return []string{"*"}
 	}
+	return []string{}
+}
+
+// defaultCORSCredentialOrigins lets the local frontend send credentials in development
+func defaultCORSCredentialOrigins() []string {
+	if strings.ToLower(getEnv("ENVIRONMENT", "development")) == "development" {
+		return []string{"http://localhost:3000"}
+	}
+	return []string{}
 }
 
 // Helper functions for environment variable parsing
@@ -169,6 +980,15 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -201,6 +1021,32 @@ func getEnvIntList(key string, defaultValue []int) []int {
 	return defaultValue
 }
 
+// getEnvSchedules parses "name=cron expression" pairs separated by semicolons, since
+// cron expressions contain spaces and commas, e.g. "image-gc=0 4 * * *;featured-expiry=off"
+func getEnvSchedules(key string) map[string]string {
+	schedules := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv(key), ";") {
+		name, spec, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(name) != "" {
+			schedules[strings.TrimSpace(name)] = strings.TrimSpace(spec)
+		}
+	}
+	return schedules
+}
+
+// getEnvRetentionDays returns the retention periods set as RETENTION_<TARGET>_DAYS,
+// e.g. RETENTION_AUDIT_LOG_DAYS=730, by target; unset targets keep their default
+func getEnvRetentionDays() map[string]int {
+	days := map[string]int{}
+	for _, policy := range domain.DefaultRetentionPolicies() {
+		key := "RETENTION_" + strings.ToUpper(policy.Target) + "_DAYS"
+		if value := os.Getenv(key); value != "" {
+			days[policy.Target] = getEnvInt(key, policy.RetentionDays())
+		}
+	}
+	return days
+}
+
 // IsProduction returns true if running in production environment
 func (c *Config) IsProduction() bool {
 	return strings.ToLower(c.Server.Environment) == "production"
@@ -224,6 +1070,15 @@ func (c *Config) Validate() error {
 	if c.Database.URL == "" {
 		return &ConfigError{Field: "DATABASE_URL", Message: "Database URL is required"}
 	}
+	if _, err := repository.ParseCountPolicies(c.Database.CountPolicies); err != nil {
+		return &ConfigError{Field: "DB_COUNT_POLICIES", Message: err.Error()}
+	}
+	if c.Database.PoolMonitorInterval <= 0 {
+		return &ConfigError{Field: "DB_POOL_MONITOR_INTERVAL", Message: "Pool monitor interval must be positive"}
+	}
+	if c.Database.PoolWaitWarning <= 0 {
+		return &ConfigError{Field: "DB_POOL_WAIT_WARNING", Message: "Pool wait warning must be positive"}
+	}
 	
 	if c.Security.JWTSecret == "default-secret-change-in-production" && c.IsProduction() {
 		return &ConfigError{Field: "JWT_SECRET", Message: "JWT secret must be changed in production"}
@@ -233,6 +1088,432 @@ func (c *Config) Validate() error {
 		return &ConfigError{Field: "PORT", Message: "Server port is required"}
 	}
 	
+	switch c.Search.Backend {
+	case "postgres":
+	case "meilisearch":
+		if c.Search.MeilisearchURL == "" {
+			return &ConfigError{Field: "MEILISEARCH_URL", Message: "Meilisearch URL is required when SEARCH_BACKEND=meilisearch"}
+		}
+	default:
+		return &ConfigError{Field: "SEARCH_BACKEND", Message: "Search backend must be postgres or meilisearch"}
+	}
+
+	switch c.Image.StorageBackend {
+	case "local":
+	case "s3":
+		if c.Image.S3Bucket == "" {
+			return &ConfigError{Field: "S3_BUCKET", Message: "S3 bucket is required when IMAGE_STORAGE_BACKEND=s3"}
+		}
+		if c.Image.S3AccessKeyID == "" || c.Image.S3SecretAccessKey == "" {
+			return &ConfigError{Field: "S3_ACCESS_KEY_ID", Message: "S3 credentials are required when IMAGE_STORAGE_BACKEND=s3"}
+		}
+	default:
+		return &ConfigError{Field: "IMAGE_STORAGE_BACKEND", Message: "Image storage backend must be local or s3"}
+	}
+
+	switch c.Image.ModerationProvider {
+	case "none":
+	case "http":
+		if c.Image.ModerationURL == "" {
+			return &ConfigError{Field: "IMAGE_MODERATION_URL", Message: "Moderation URL is required when IMAGE_MODERATION_PROVIDER=http"}
+		}
+	default:
+		return &ConfigError{Field: "IMAGE_MODERATION_PROVIDER", Message: "Image moderation provider must be none or http"}
+	}
+
+	switch c.Image.AnalysisProvider {
+	case "none":
+	case "http":
+		if c.Image.AnalysisURL == "" {
+			return &ConfigError{Field: "IMAGE_ANALYSIS_URL", Message: "Analysis URL is required when IMAGE_ANALYSIS_PROVIDER=http"}
+		}
+	default:
+		return &ConfigError{Field: "IMAGE_ANALYSIS_PROVIDER", Message: "Image analysis provider must be none or http"}
+	}
+	if c.Image.AnalysisBatchSize <= 0 {
+		return &ConfigError{Field: "IMAGE_ANALYSIS_BATCH_SIZE", Message: "Image analysis batch size must be positive"}
+	}
+	if c.Image.AnalysisMinConfidence <= 0 || c.Image.AnalysisMinConfidence > 1 {
+		return &ConfigError{Field: "IMAGE_ANALYSIS_MIN_CONFIDENCE", Message: "Image analysis minimum confidence must be between 0 and 1"}
+	}
+
+	switch c.Image.CDNProvider {
+	case "none":
+	case "cloudfront", "cloudflare":
+		if c.Image.CDNBaseURL == "" {
+			return &ConfigError{Field: "IMAGE_CDN_BASE_URL", Message: "CDN base URL is required when IMAGE_CDN_PROVIDER is set"}
+		}
+		if c.Image.CDNSignURLs && c.Image.CDNProvider == "cloudfront" && (c.Image.CDNKeyPairID == "" || c.Image.CDNPrivateKeyPath == "") {
+			return &ConfigError{Field: "CLOUDFRONT_KEY_PAIR_ID", Message: "CloudFront key pair ID and private key are required for signed URLs"}
+		}
+		if c.Image.CDNSignURLs && c.Image.CDNProvider == "cloudflare" && c.Image.CDNSigningKey == "" {
+			return &ConfigError{Field: "CLOUDFLARE_SIGNING_KEY", Message: "Cloudflare signing key is required for signed URLs"}
+		}
+	default:
+		return &ConfigError{Field: "IMAGE_CDN_PROVIDER", Message: "Image CDN provider must be none, cloudfront or cloudflare"}
+	}
+
+	if c.Image.GCEnabled && (c.Image.GCInterval <= 0 || c.Image.GCRetention < time.Hour) {
+		return &ConfigError{Field: "IMAGE_GC_RETENTION", Message: "Image GC interval must be positive and retention at least 1h"}
+	}
+
+	if c.Image.MaxConcurrentDecodes <= 0 || c.Image.DecodeMemoryMB <= 0 || c.Image.MaxMegapixels <= 0 || c.Image.DecodeWait <= 0 {
+		return &ConfigError{Field: "IMAGE_DECODE_MEMORY_MB", Message: "Image decode concurrency, memory, megapixels and wait must be positive"}
+	}
+
+	presets, err := c.GetImagePresets()
+	if err != nil {
+		return &ConfigError{Field: "IMAGE_PRESETS", Message: err.Error()}
+	}
+	for _, name := range c.Image.WarmPresets {
+		if _, ok := presets[name]; !ok {
+			return &ConfigError{Field: "IMAGE_WARM_PRESETS", Message: fmt.Sprintf("Image preset %s is not defined in IMAGE_PRESETS", name)}
+		}
+	}
+
+	if c.Image.WarmEnabled && (c.Image.WarmInterval < time.Minute || c.Image.WarmFeaturedLimit < 0 || c.Image.WarmTopViewed < 0 || c.Image.WarmImagesPerProperty <= 0) {
+		return &ConfigError{Field: "IMAGE_WARM_INTERVAL", Message: "Image cache warming interval must be at least 1m, limits not negative and images per property positive"}
+	}
+
+	if c.Image.MaxImagesPerProperty <= 0 {
+		return &ConfigError{Field: "IMAGE_MAX_PER_PROPERTY", Message: "Max images per property must be positive"}
+	}
+
+	plans, err := c.GetImageQuotaPlans()
+	if err != nil {
+		return &ConfigError{Field: "IMAGE_QUOTA_PLANS", Message: err.Error()}
+	}
+	if _, ok := plans[c.Image.DefaultQuotaPlan]; !ok {
+		return &ConfigError{Field: "IMAGE_QUOTA_DEFAULT_PLAN", Message: "Default quota plan must be one of IMAGE_QUOTA_PLANS"}
+	}
+
+	if c.Agency.InvitationTTL < time.Hour {
+		return &ConfigError{Field: "AGENCY_INVITATION_TTL", Message: "Agency invitation TTL must be at least 1h"}
+	}
+
+	if c.Listing.FeaturedExpiryInterval <= 0 {
+		return &ConfigError{Field: "LISTING_FEATURED_EXPIRY_INTERVAL", Message: "Featured expiration interval must be positive"}
+	}
+
+	if c.Listing.FeaturedDefaultDuration <= 0 || c.Listing.FeaturedDefaultDuration > domain.MaxFeaturedDuration {
+		return &ConfigError{Field: "LISTING_FEATURED_DEFAULT_DURATION", Message: "Featured default duration must be positive and at most 365 days"}
+	}
+
+	if c.Listing.ExpiryInterval <= 0 || c.Listing.ExpiryDays <= 0 {
+		return &ConfigError{Field: "LISTING_EXPIRY_DAYS", Message: "Listing expiry interval and days must be positive"}
+	}
+
+	if c.Listing.StaleDays <= 0 {
+		return &ConfigError{Field: "LISTING_STALE_DAYS", Message: "Stale listing days must be positive"}
+	}
+
+	if !domain.IsValidDescriptionPolicyAction(c.Listing.DescriptionContactPolicy) {
+		return &ConfigError{Field: "LISTING_DESCRIPTION_CONTACT_POLICY", Message: "Description contact policy must be off, flag, obfuscate or reject"}
+	}
+
+	if !domain.IsValidDescriptionPolicyAction(c.Listing.DescriptionProfanityPolicy) {
+		return &ConfigError{Field: "LISTING_DESCRIPTION_PROFANITY_POLICY", Message: "Description profanity policy must be off, flag, obfuscate or reject"}
+	}
+
+	if c.Security.RateLimitPerMinute <= 0 {
+		return &ConfigError{Field: "RATE_LIMIT_PER_MINUTE", Message: "Rate limit must be positive"}
+	}
+
+	switch c.Security.GeoIPProvider {
+	case "none":
+		if len(c.Security.BlockedCountries) > 0 {
+			return &ConfigError{Field: "BLOCKED_COUNTRIES", Message: "Blocking countries requires a GEOIP_PROVIDER"}
+		}
+	case "maxmind":
+		if c.Security.GeoIPAccountID == "" || c.Security.GeoIPLicenseKey == "" {
+			return &ConfigError{Field: "GEOIP_MAXMIND_ACCOUNT_ID", Message: "MaxMind account ID and license key are required when GEOIP_PROVIDER=maxmind"}
+		}
+	default:
+		return &ConfigError{Field: "GEOIP_PROVIDER", Message: "GeoIP provider must be none or maxmind"}
+	}
+
+	if !captcha.IsValidProvider(c.Security.CaptchaProvider) {
+		return &ConfigError{Field: "CAPTCHA_PROVIDER", Message: "CAPTCHA provider must be none, recaptcha, hcaptcha or turnstile"}
+	}
+	if c.Security.CaptchaProvider != captcha.ProviderNone && c.Security.CaptchaSecret == "" {
+		return &ConfigError{Field: "CAPTCHA_SECRET", Message: "CAPTCHA secret is required when CAPTCHA_PROVIDER is set"}
+	}
+	for _, endpoint := range c.Security.CaptchaEndpoints {
+		if !captcha.IsValidEndpoint(strings.TrimSpace(endpoint)) {
+			return &ConfigError{Field: "CAPTCHA_ENDPOINTS", Message: "CAPTCHA endpoints must be registration, password_reset or inquiry"}
+		}
+	}
+
+	if c.Security.PIIEncryptionKey != "" {
+		if _, err := pii.ParseKeySet(c.Security.PIIEncryptionKey); err != nil {
+			return &ConfigError{Field: "PII_ENCRYPTION_KEY", Message: err.Error()}
+		}
+	}
+
+	if c.Security.EmailChangeTTL < 10*time.Minute {
+		return &ConfigError{Field: "EMAIL_CHANGE_TTL", Message: "Email change TTL must be at least 10m"}
+	}
+
+	if !sms.IsValidProvider(c.Security.SMSProvider) {
+		return &ConfigError{Field: "SMS_PROVIDER", Message: "SMS provider must be none, log or twilio"}
+	}
+	if c.Security.SMSProvider == sms.ProviderTwilio &&
+		(c.Security.TwilioAccountSID == "" || c.Security.TwilioAuthToken == "" || c.Security.TwilioFromNumber == "") {
+		return &ConfigError{Field: "TWILIO_ACCOUNT_SID", Message: "Twilio account SID, auth token and from number are required when SMS_PROVIDER=twilio"}
+	}
+
+	if c.Cache.PropertyTTL <= 0 || c.Cache.SearchTTL <= 0 || c.Cache.StatisticsTTL <= 0 {
+		return &ConfigError{Field: "CACHE_PROPERTY_TTL", Message: "Property, search and statistics cache TTLs must be positive"}
+	}
+
+	if c.Currency.Enabled && (c.Currency.RatesURL == "" || c.Currency.RatesTTL < time.Hour) {
+		return &ConfigError{Field: "EXCHANGE_RATES_URL", Message: "Exchange rates URL is required and rates TTL must be at least 1h"}
+	}
+
+	if !routing.IsValidProvider(c.Routing.Provider) {
+		return &ConfigError{Field: "ROUTING_PROVIDER", Message: "Routing provider must be none, osrm or google"}
+	}
+	if c.Routing.Provider == routing.ProviderGoogle && c.Routing.APIKey == "" {
+		return &ConfigError{Field: "ROUTING_GOOGLE_API_KEY", Message: "Google Maps API key is required when ROUTING_PROVIDER=google"}
+	}
+
+	if !translation.IsValidProvider(c.Translation.Provider) {
+		return &ConfigError{Field: "TRANSLATION_PROVIDER", Message: "Translation provider must be none, deepl or libretranslate"}
+	}
+	if c.Translation.Provider == translation.ProviderDeepL && c.Translation.APIKey == "" {
+		return &ConfigError{Field: "TRANSLATION_API_KEY", Message: "DeepL API key is required when TRANSLATION_PROVIDER=deepl"}
+	}
+
+	if c.Calendar.GoogleClientID != "" {
+		if c.Calendar.GoogleClientSecret == "" {
+			return &ConfigError{Field: "GOOGLE_CALENDAR_CLIENT_SECRET", Message: "Google Calendar client secret is required when GOOGLE_CALENDAR_CLIENT_ID is set"}
+		}
+		if redirect, err := url.Parse(c.Calendar.GoogleRedirectURL); err != nil || redirect.Scheme == "" || redirect.Host == "" {
+			return &ConfigError{Field: "GOOGLE_CALENDAR_REDIRECT_URL", Message: "Google Calendar redirect URL must be an absolute URL"}
+		}
+	}
+
+	if len(c.CallMasking.ProxyNumbers) > 0 {
+		for _, number := range c.CallMasking.ProxyNumbers {
+			if !voice.IsE164(number) {
+				return &ConfigError{Field: "CALL_MASKING_NUMBERS", Message: fmt.Sprintf("Proxy number %s must be in E.164 format, e.g. +593991234567", number)}
+			}
+		}
+		if c.Security.TwilioAuthToken == "" {
+			return &ConfigError{Field: "TWILIO_AUTH_TOKEN", Message: "Twilio auth token is required when CALL_MASKING_NUMBERS is set"}
+		}
+		if webhook, err := url.Parse(c.CallMasking.WebhookURL); err != nil || webhook.Scheme == "" || webhook.Host == "" {
+			return &ConfigError{Field: "CALL_MASKING_WEBHOOK_URL", Message: "Call masking webhook URL must be an absolute URL"}
+		}
+	}
+
+	if !einvoice.IsValidProvider(c.Invoicing.Provider) {
+		return &ConfigError{Field: "EINVOICE_PROVIDER", Message: "Electronic invoicing provider must be none, log or http"}
+	}
+	if c.Invoicing.Provider != einvoice.ProviderNone {
+		if err := c.GetInvoiceIssuer().Validate(); err != nil {
+			return &ConfigError{Field: "EINVOICE_ISSUER_RUC", Message: err.Error()}
+		}
+		if c.Invoicing.Provider == einvoice.ProviderHTTP && c.Invoicing.Endpoint == "" {
+			return &ConfigError{Field: "EINVOICE_ENDPOINT", Message: "Electronic invoicing endpoint is required when EINVOICE_PROVIDER=http"}
+		}
+		if c.Invoicing.FeaturedDailyPrice < 0 {
+			return &ConfigError{Field: "EINVOICE_FEATURED_DAILY_PRICE", Message: "Featured daily price cannot be negative"}
+		}
+	}
+
+	if _, _, err := monitoring.ParseAnomalyRules(c.Alerting.AnomalyRules); err != nil {
+		return &ConfigError{Field: "ALERT_ANOMALY_RULES", Message: err.Error()}
+	}
+	if len(c.Alerting.EmailRecipients) > 0 && c.SMTP.Host == "" {
+		return &ConfigError{Field: "SMTP_HOST", Message: "SMTP host is required when ALERT_EMAIL_RECIPIENTS is set"}
+	}
+
+	if c.Metrics.MaxRouteLabels <= 0 || c.Metrics.MaxAgencyLabels <= 0 {
+		return &ConfigError{Field: "METRICS_MAX_ROUTE_LABELS", Message: "Metric label limits must be positive"}
+	}
+
+	if c.LoadShedding.Enabled {
+		if c.LoadShedding.MaxConcurrent <= 0 || c.LoadShedding.QueueTimeout <= 0 {
+			return &ConfigError{Field: "LOAD_SHEDDING_MAX_CONCURRENT", Message: "Load shedding concurrency and queue timeout must be positive"}
+		}
+		if _, err := middleware.ParseRouteLimits(c.LoadShedding.RouteLimits); err != nil {
+			return &ConfigError{Field: "LOAD_SHEDDING_ROUTE_LIMITS", Message: err.Error()}
+		}
+	}
+
+	if c.MicroCache.Enabled {
+		if c.MicroCache.TTL < middleware.MinMicroCacheTTL || c.MicroCache.TTL > middleware.MaxMicroCacheTTL {
+			return &ConfigError{Field: "MICRO_CACHE_TTL", Message: "Micro-cache TTL must be between 5s and 30s"}
+		}
+		switch c.MicroCache.Backend {
+		case cache.ResponseStoreMemory:
+			if c.MicroCache.Capacity <= 0 {
+				return &ConfigError{Field: "MICRO_CACHE_CAPACITY", Message: "Micro-cache capacity must be positive"}
+			}
+		case cache.ResponseStoreRedis:
+			if c.MicroCache.RedisURL == "" {
+				return &ConfigError{Field: "MICRO_CACHE_REDIS_URL", Message: "Redis URL is required for the redis micro-cache backend"}
+			}
+		default:
+			return &ConfigError{Field: "MICRO_CACHE_BACKEND", Message: "Micro-cache backend must be memory or redis"}
+		}
+	}
+
+	if c.ScraperGuard.Enabled {
+		if c.ScraperGuard.SoftLimit <= 0 || c.ScraperGuard.HardLimit < c.ScraperGuard.SoftLimit {
+			return &ConfigError{Field: "SCRAPER_GUARD_HARD_LIMIT", Message: "Scraper guard limits must be positive and the hard limit at least the soft limit"}
+		}
+		if c.ScraperGuard.Window <= 0 || c.ScraperGuard.DelayStep < 0 || c.ScraperGuard.MaxDelay < 0 || c.ScraperGuard.DeepPage < 0 {
+			return &ConfigError{Field: "SCRAPER_GUARD_WINDOW", Message: "Scraper guard window must be positive and delays and deep page not negative"}
+		}
+		if c.ScraperGuard.MaxDelay > c.Server.WriteTimeout/2 && c.Server.WriteTimeout > 0 {
+			return &ConfigError{Field: "SCRAPER_GUARD_MAX_DELAY", Message: "Scraper guard max delay must be under half the server write timeout"}
+		}
+		if _, err := middleware.ParseTrustedProxies(c.ScraperGuard.TrustedProxies); err != nil {
+			return &ConfigError{Field: "SCRAPER_GUARD_TRUSTED_PROXIES", Message: err.Error()}
+		}
+	}
+
+	if _, err := c.GetJWTKeySet(); err != nil {
+		return &ConfigError{Field: "JWT_SECRET_KEY", Message: err.Error()}
+	}
+
+	switch c.JWT.SigningAlgorithm {
+	case auth.AlgorithmHS256:
+	case auth.AlgorithmEdDSA, auth.AlgorithmRS256:
+		if len(c.JWT.KeyEncryptionKey) < 32 {
+			return &ConfigError{Field: "JWT_KEY_ENCRYPTION_KEY", Message: "Key encryption key of at least 32 characters is required for EdDSA and RS256 signing"}
+		}
+		if c.JWT.KeyGracePeriod < c.JWT.AccessTokenTTL {
+			return &ConfigError{Field: "JWT_KEY_GRACE_PERIOD", Message: "Key grace period must be at least the access token TTL"}
+		}
+	default:
+		return &ConfigError{Field: "JWT_SIGNING_ALGORITHM", Message: "JWT signing algorithm must be HS256, EdDSA or RS256"}
+	}
+
+	switch c.Secrets.Backend {
+	case secrets.BackendEnv, secrets.BackendAWS, secrets.BackendVault, secrets.BackendGCP:
+	default:
+		return &ConfigError{Field: "SECRETS_BACKEND", Message: "Secrets backend must be env, aws, vault or gcp"}
+	}
+
+	if c.Tenancy.Enabled {
+		if c.Tenancy.Header == "" {
+			return &ConfigError{Field: "TENANT_HEADER", Message: "Tenant header is required when MULTI_TENANCY_ENABLED=true"}
+		}
+		if c.Tenancy.DefaultTenant != "" && !domain.IsValidTenantID(c.Tenancy.DefaultTenant) {
+			return &ConfigError{Field: "TENANT_DEFAULT", Message: "Default tenant must be a valid tenant ID or empty"}
+		}
+		if c.Tenancy.RefreshInterval <= 0 {
+			return &ConfigError{Field: "TENANT_REFRESH_INTERVAL", Message: "Tenant refresh interval must be positive"}
+		}
+	}
+
+	if c.Webhook.Timeout <= 0 || c.Webhook.PollInterval <= 0 || c.Webhook.BatchSize <= 0 || c.Webhook.MaxAttempts <= 0 {
+		return &ConfigError{Field: "WEBHOOK_TIMEOUT", Message: "Webhook timeout, poll interval, batch size and max attempts must be positive"}
+	}
+
+	if c.Outbox.Enabled && (c.Outbox.PollInterval <= 0 || c.Outbox.BatchSize <= 0 || c.Outbox.MaxAttempts <= 0 || c.Outbox.Retention <= 0) {
+		return &ConfigError{Field: "OUTBOX_POLL_INTERVAL", Message: "Outbox poll interval, batch size, max attempts and retention must be positive"}
+	}
+
+	switch c.EventBus.Backend {
+	case "none", "log":
+	case "nats":
+		if !strings.HasPrefix(c.EventBus.NATSURL, "nats://") && !strings.HasPrefix(c.EventBus.NATSURL, "tls://") {
+			return &ConfigError{Field: "NATS_URL", Message: "NATS URL must start with nats:// or tls://"}
+		}
+		if c.EventBus.Timeout <= 0 {
+			return &ConfigError{Field: "EVENT_BUS_TIMEOUT", Message: "Event bus timeout must be positive"}
+		}
+	default:
+		return &ConfigError{Field: "EVENT_BUS_BACKEND", Message: "Event bus backend must be none, log or nats"}
+	}
+
+	if _, err := time.LoadLocation(c.Scheduler.Timezone); err != nil {
+		return &ConfigError{Field: "SCHEDULER_TIMEZONE", Message: "Scheduler timezone must be an IANA timezone such as America/Guayaquil"}
+	}
+	for name, spec := range c.Scheduler.Schedules {
+		if spec == scheduler.ScheduleOff {
+			continue
+		}
+		if _, err := scheduler.Parse(spec); err != nil {
+			return &ConfigError{Field: "SCHEDULER_SCHEDULES", Message: fmt.Sprintf("Invalid schedule for job %s: %v", name, err)}
+		}
+	}
+
+	if c.PublicAPI.Enabled {
+		tiers, err := c.GetPublicAPITiers()
+		if err != nil {
+			return &ConfigError{Field: "PUBLIC_API_TIERS", Message: err.Error()}
+		}
+		if _, ok := tiers[c.PublicAPI.DefaultTier]; !ok {
+			return &ConfigError{Field: "PUBLIC_API_DEFAULT_TIER", Message: "Default tier must be one of PUBLIC_API_TIERS"}
+		}
+		if c.PublicAPI.KeyCacheTTL <= 0 || c.PublicAPI.UsageFlushInterval <= 0 {
+			return &ConfigError{Field: "PUBLIC_API_KEY_CACHE_TTL", Message: "Public API key cache TTL and usage flush interval must be positive"}
+		}
+	}
+
+	if c.Widget.Enabled {
+		if c.Widget.SigningSecret == "" && c.IsProduction() {
+			return &ConfigError{Field: "WIDGET_SIGNING_SECRET", Message: "Widget signing secret is required in production"}
+		}
+		if c.Widget.TokenTTL <= 0 || c.Widget.CacheMaxAge < 0 {
+			return &ConfigError{Field: "WIDGET_TOKEN_TTL", Message: "Widget token TTL must be positive and cache max age not negative"}
+		}
+	}
+
+	if c.Retention.Enabled {
+		if c.Retention.BatchSize <= 0 {
+			return &ConfigError{Field: "RETENTION_BATCH_SIZE", Message: "Retention batch size must be positive"}
+		}
+		if _, err := c.GetRetentionPolicies(); err != nil {
+			return &ConfigError{Field: "RETENTION_DAYS", Message: err.Error()}
+		}
+	}
+
+	if c.Backup.RestoreTimeout <= 0 || c.Backup.MaxAge <= 0 {
+		return &ConfigError{Field: "BACKUP_MAX_AGE", Message: "Backup restore timeout and max age must be positive"}
+	}
+
+	if c.Backup.MinRowRatio <= 0 || c.Backup.MinRowRatio > 1 {
+		return &ConfigError{Field: "BACKUP_MIN_ROW_RATIO", Message: "Backup min row ratio must be between 0 and 1"}
+	}
+
+	if c.Startup.Enabled && (c.Startup.GracePeriod <= 0 || c.Startup.Interval <= 0 || c.Startup.CheckTimeout <= 0) {
+		return &ConfigError{Field: "STARTUP_GRACE_PERIOD", Message: "Startup grace period, check interval and timeout must be positive"}
+	}
+
+	if _, err := c.GetSecurityHeaders(); err != nil {
+		return &ConfigError{Field: "SECURITY_CSP", Message: err.Error()}
+	}
+
+	if _, err := c.GetCORSConfig(); err != nil {
+		return &ConfigError{Field: "CORS_ROUTES", Message: err.Error()}
+	}
+
+	if c.IsProduction() {
+		for _, origin := range c.CORS.CredentialOrigins {
+			if !strings.HasPrefix(strings.TrimSpace(origin), "https://") {
+				return &ConfigError{Field: "CORS_CREDENTIAL_ORIGINS", Message: "Credential origins must use https in production"}
+			}
+		}
+	}
+
+	if c.Video.MaxSizeMB <= 0 {
+		return &ConfigError{Field: "VIDEO_MAX_SIZE_MB", Message: "Video max size must be positive"}
+	}
+
+	if c.Video.Workers <= 0 {
+		return &ConfigError{Field: "VIDEO_TRANSCODE_WORKERS", Message: "Video transcode workers must be positive"}
+	}
+
+	if c.Document.MaxSizeMB <= 0 || c.Document.MaxPerProperty <= 0 {
+		return &ConfigError{Field: "DOCUMENT_MAX_SIZE_MB", Message: "Document max size and max per property must be positive"}
+	}
+
 	return nil
 }
 
@@ -251,13 +1532,254 @@ func (c *Config) GetMaxUploadSizeBytes() int64 {
 	return int64(c.Security.MaxUploadSizeMB) * 1024 * 1024
 }
 
+// GetCDNConfig returns the CDN settings of the image configuration
+func (c *Config) GetCDNConfig() storage.CDNConfig {
+	return storage.CDNConfig{
+		Provider:        c.Image.CDNProvider,
+		BaseURL:         c.Image.CDNBaseURL,
+		SignURLs:        c.Image.CDNSignURLs,
+		SignedURLTTL:    c.Image.CDNSignedURLTTL,
+		DistributionID:  c.Image.CDNDistributionID,
+		KeyPairID:       c.Image.CDNKeyPairID,
+		PrivateKeyPath:  c.Image.CDNPrivateKeyPath,
+		AccessKeyID:     c.Image.CDNAccessKeyID,
+		SecretAccessKey: c.Image.CDNSecretAccessKey,
+		ZoneID:          c.Image.CDNZoneID,
+		APIToken:        c.Image.CDNAPIToken,
+		SigningKey:      c.Image.CDNSigningKey,
+	}
+}
+
+// GetImagePresets returns the renditions images can be requested in publicly
+func (c *Config) GetImagePresets() (map[string]domain.ImagePreset, error) {
+	return domain.ParseImagePresets(c.Image.Presets)
+}
+
+// GetImageDecodeLimiter returns the limiter of image decoding memory, to share between
+// the image processors of the instance
+func (c *Config) GetImageDecodeLimiter() *processors.DecodeLimiter {
+	return processors.NewDecodeLimiter(processors.DecodeLimiterConfig{
+		MaxConcurrent: c.Image.MaxConcurrentDecodes,
+		MemoryBytes:   int64(c.Image.DecodeMemoryMB) * 1024 * 1024,
+		MaxPixels:     int64(c.Image.MaxMegapixels) * 1_000_000,
+		Wait:          c.Image.DecodeWait,
+	})
+}
+
+// GetInvoiceIssuer returns the taxpayer issuing the electronic invoices
+func (c *Config) GetInvoiceIssuer() domain.InvoiceIssuer {
+	return domain.InvoiceIssuer{
+		RUC:                c.Invoicing.IssuerRUC,
+		BusinessName:       c.Invoicing.BusinessName,
+		TradeName:          c.Invoicing.TradeName,
+		Address:            c.Invoicing.Address,
+		Establishment:      c.Invoicing.Establishment,
+		EmissionPoint:      c.Invoicing.EmissionPoint,
+		Environment:        c.Invoicing.Environment,
+		RequiredAccounting: c.Invoicing.RequiredAccounting,
+		IVARate:            c.Invoicing.IVARate,
+	}
+}
+
+// GetGoogleCalendarClient returns the Google Calendar client agents connect their
+// calendar with, nil when calendar sync is disabled
+func (c *Config) GetGoogleCalendarClient() (*gcal.Client, error) {
+	if c.Calendar.GoogleClientID == "" {
+		return nil, nil
+	}
+	return gcal.NewClient(gcal.Config{
+		ClientID:     c.Calendar.GoogleClientID,
+		ClientSecret: c.Calendar.GoogleClientSecret,
+		RedirectURL:  c.Calendar.GoogleRedirectURL,
+		Timeout:      c.Calendar.Timeout,
+	})
+}
+
+// GetMicroCache returns the micro-cache of public list endpoints, nil when disabled
+func (c *Config) GetMicroCache() (*middleware.MicroCache, error) {
+	if !c.MicroCache.Enabled {
+		return nil, nil
+	}
+	store, err := cache.NewResponseStore(c.MicroCache.Backend, c.MicroCache.RedisURL, c.MicroCache.Timeout, c.MicroCache.Capacity, 50*1024*1024, c.MicroCache.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create micro-cache store: %w", err)
+	}
+	return middleware.NewMicroCache(store, c.MicroCache.TTL), nil
+}
+
+// GetSecurityHeaders returns the Content-Security-Policy, reporting and HSTS settings
+// of the security headers middleware
+func (c *Config) GetSecurityHeaders() (middleware.SecurityHeadersConfig, error) {
+	routes, err := middleware.ParseCSPRoutes(c.Security.CSPRoutes)
+	if err != nil {
+		return middleware.SecurityHeadersConfig{}, err
+	}
+	headers := middleware.SecurityHeadersConfig{
+		CSP:                   c.Security.CSP,
+		CSPReportOnly:         c.Security.CSPReportOnly,
+		ReportURI:             c.Security.CSPReportURI,
+		HSTSMaxAge:            c.Security.HSTSMaxAge,
+		HSTSIncludeSubdomains: c.Security.HSTSIncludeSubdomains,
+		HSTSPreload:           c.Security.HSTSPreload,
+		Routes:                routes,
+	}
+	if err := middleware.ValidateSecurityHeaders(headers); err != nil {
+		return middleware.SecurityHeadersConfig{}, err
+	}
+	return headers, nil
+}
+
+// GetCORSConfig returns the CORS policy of the API with its per-route overrides
+func (c *Config) GetCORSConfig() (middleware.CORSConfig, error) {
+	policy := middleware.CORSPolicy{
+		Origins:           c.Server.CORSOrigins,
+		CredentialOrigins: c.CORS.CredentialOrigins,
+		Methods:           c.CORS.Methods,
+		Headers:           c.CORS.Headers,
+		ExposedHeaders:    c.CORS.ExposedHeaders,
+		MaxAge:            c.CORS.MaxAge,
+	}
+	routes, err := middleware.ParseCORSRoutes(c.CORS.Routes, policy)
+	if err != nil {
+		return middleware.CORSConfig{}, err
+	}
+	config := middleware.CORSConfig{Default: policy, Routes: routes}
+	if _, err := middleware.NewCORSMiddleware(config); err != nil {
+		return middleware.CORSConfig{}, err
+	}
+	return config, nil
+}
+
+// GetScraperGuard returns the scraper guard of the public catalog with its middleware,
+// nil when disabled
+func (c *Config) GetScraperGuard() (*security.ScraperGuard, *middleware.ScraperMiddleware) {
+	if !c.ScraperGuard.Enabled {
+		return nil, nil
+	}
+	guard := security.NewScraperGuard(security.ScraperGuardConfig{
+		SoftLimit:       c.ScraperGuard.SoftLimit,
+		HardLimit:       c.ScraperGuard.HardLimit,
+		Window:          c.ScraperGuard.Window,
+		DelayStep:       c.ScraperGuard.DelayStep,
+		MaxDelay:        c.ScraperGuard.MaxDelay,
+		DeepPage:        c.ScraperGuard.DeepPage,
+		PageTokenSecret: c.ScraperGuard.PageTokenSecret,
+		PageTokenTTL:    c.ScraperGuard.PageTokenTTL,
+	})
+	scraperMiddleware := middleware.NewScraperMiddleware(guard, c.ScraperGuard.Paths)
+	proxies, _ := middleware.ParseTrustedProxies(c.ScraperGuard.TrustedProxies) // checked by Validate
+	scraperMiddleware.SetTrustedProxies(proxies)
+	return guard, scraperMiddleware
+}
+
+// GetPropertyChangeListener returns a listener invalidating a property cache on the
+// property changes notified by the database, already listening; nil when disabled
+func (c *Config) GetPropertyChangeListener(propertyCache *cache.PropertyCache) (*cache.PropertyChangeListener, error) {
+	if !c.Cache.Enabled || !c.Cache.InvalidationListen {
+		return nil, nil
+	}
+	listener := cache.NewPropertyChangeListener(propertyCache, nil)
+	if err := listener.Start(c.Database.URL); err != nil {
+		return nil, err
+	}
+	return listener, nil
+}
+
+// GetAlertNotifiers returns the Slack and email channels alerts are sent to, to add
+// to the alert manager along with the anomaly rules of Alerting.AnomalyRules
+func (c *Config) GetAlertNotifiers() ([]monitoring.AlertNotifier, error) {
+	notifiers := []monitoring.AlertNotifier{}
+	if c.Alerting.SlackWebhookURL != "" {
+		slack, err := monitoring.NewSlackNotifier(c.Alerting.SlackWebhookURL, c.Alerting.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, slack)
+	}
+	if len(c.Alerting.EmailRecipients) > 0 {
+		email, err := monitoring.NewEmailNotifier(monitoring.EmailSettings{
+			Host:     c.SMTP.Host,
+			Port:     c.SMTP.Port,
+			Username: c.SMTP.Username,
+			Password: c.SMTP.Password,
+			From:     c.SMTP.From,
+		}, c.Alerting.EmailRecipients)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, email)
+	}
+	return notifiers, nil
+}
+
+// GetJWTKeySet parses the JWT secret into signing and verification keys
+func (c *Config) GetJWTKeySet() (auth.KeySet, error) {
+	return auth.ParseKeySet(c.JWT.SecretKey)
+}
+
+// GetPublicAPITiers parses the rate tiers of public API keys
+func (c *Config) GetPublicAPITiers() (map[string]domain.APIKeyTier, error) {
+	return domain.ParseAPIKeyTiers(c.PublicAPI.Tiers)
+}
+
+// GetRetentionPolicies returns the retention of every target with the configured periods
+func (c *Config) GetRetentionPolicies() ([]domain.RetentionPolicy, error) {
+	return domain.RetentionPolicies(c.Retention.Days)
+}
+
+// GetImageQuotaPlans parses the configured agency image plans
+func (c *Config) GetImageQuotaPlans() (map[string]domain.ImageQuotaPlan, error) {
+	return domain.ParseImageQuotaPlans(c.Image.QuotaPlans)
+}
+
+// GetMaxVideoSizeBytes returns the maximum video upload size in bytes
+func (c *Config) GetMaxVideoSizeBytes() int64 {
+	return int64(c.Video.MaxSizeMB) * 1024 * 1024
+}
+
+// GetVideoStorageURL returns the public URL path for videos
+func (c *Config) GetVideoStorageURL() string {
+	return "/uploads/videos"
+}
+
+// GetMaxDocumentSizeBytes returns the maximum document upload size in bytes
+func (c *Config) GetMaxDocumentSizeBytes() int64 {
+	return int64(c.Document.MaxSizeMB) * 1024 * 1024
+}
+
+// GetDocumentStorageURL returns the public URL path for public documents
+func (c *Config) GetDocumentStorageURL() string {
+	return "/uploads/documents"
+}
+
 // GetImageStorageURL returns the public URL path for images
 func (c *Config) GetImageStorageURL() string {
 	return "/uploads/images"
 }
 
+// GetDescriptionPolicy returns the policy on contact information and profanity in
+// listing descriptions
+func (c *Config) GetDescriptionPolicy() domain.DescriptionPolicy {
+	return domain.DescriptionPolicy{
+		ContactInfo:    c.Listing.DescriptionContactPolicy,
+		Profanity:      c.Listing.DescriptionProfanityPolicy,
+		ProfanityWords: c.Listing.DescriptionProfanityWords,
+	}
+}
+
+// GetCountPolicies returns how paginated endpoints count their totals
+func (c *Config) GetCountPolicies() (map[string]repository.CountPolicy, error) {
+	return repository.ParseCountPolicies(c.Database.CountPolicies)
+}
+
 // GetDatabaseConnectionPoolConfig returns database connection pool configuration
 func (c *Config) GetDatabaseConnectionPoolConfig() (maxOpen, maxIdle int, maxLifetime, maxIdleTime time.Duration) {
 	return c.Database.MaxOpenConns, c.Database.MaxIdleConns, 
 		   c.Database.ConnMaxLifetime, c.Database.ConnMaxIdleTime
+}
+
+// GetDBPoolMonitorConfig returns how often the connection pool is sampled and the
+// average wait for a connection that warns of an exhausted pool
+func (c *Config) GetDBPoolMonitorConfig() (interval, waitWarning time.Duration) {
+	return c.Database.PoolMonitorInterval, c.Database.PoolWaitWarning
 }
\ No newline at end of file
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestConfig_ValidateCaptcha(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Security.CaptchaProvider = "turnstile"
+	assert.ErrorContains(t, cfg.Validate(), "CAPTCHA secret is required")
+
+	cfg.Security.CaptchaSecret = "secret"
+	cfg.Security.CaptchaEndpoints = []string{"registration", " inquiry"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Security.CaptchaEndpoints = []string{"login"}
+	assert.ErrorContains(t, cfg.Validate(), "CAPTCHA endpoints")
+}
+
+func TestConfig_ValidatePIIEncryptionKey(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Security.PIIEncryptionKey = "too-short"
+	assert.ErrorContains(t, cfg.Validate(), "at least 32 characters")
+
+	cfg.Security.PIIEncryptionKey = "0123456789abcdef0123456789abcdef"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Security.PIIEncryptionKey = `{"active_key_id": "2025-08", "keys": {"2025-05": "0123456789abcdef0123456789abcdef"}}`
+	assert.ErrorContains(t, cfg.Validate(), "active key 2025-08 not found")
+}
+
+func TestConfig_ValidateSMS(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Security.SMSProvider = "twilio"
+	assert.ErrorContains(t, cfg.Validate(), "Twilio account SID")
+
+	cfg.Security.TwilioAccountSID = "AC123"
+	cfg.Security.TwilioAuthToken = "token"
+	cfg.Security.TwilioFromNumber = "+15005550006"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Security.SMSProvider = "carrier-pigeon"
+	assert.ErrorContains(t, cfg.Validate(), "SMS provider must be")
+}
+
+func TestConfig_ValidateStaleDays(t *testing.T) {
+	cfg := LoadConfig()
+	assert.Equal(t, 60, cfg.Listing.StaleDays)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Listing.StaleDays = 0
+	assert.ErrorContains(t, cfg.Validate(), "Stale listing days must be positive")
+}
+
+func TestConfig_ValidateDescriptionPolicy(t *testing.T) {
+	cfg := LoadConfig()
+	assert.Equal(t, domain.DefaultDescriptionPolicy(), cfg.GetDescriptionPolicy())
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Listing.DescriptionContactPolicy = "hide"
+	assert.ErrorContains(t, cfg.Validate(), "Description contact policy must be")
+
+	cfg.Listing.DescriptionContactPolicy = domain.DescriptionPolicyReject
+	cfg.Listing.DescriptionProfanityPolicy = ""
+	assert.ErrorContains(t, cfg.Validate(), "Description profanity policy must be")
+}
+
+func TestConfig_ValidateRouting(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Routing.Provider = "google"
+	assert.ErrorContains(t, cfg.Validate(), "Google Maps API key")
+
+	cfg.Routing.APIKey = "key"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Routing.Provider = "mapquest"
+	assert.ErrorContains(t, cfg.Validate(), "Routing provider must be")
+}
+
+func TestConfig_ValidateTranslation(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Translation.Provider = "deepl"
+	assert.ErrorContains(t, cfg.Validate(), "DeepL API key")
+
+	cfg.Translation.APIKey = "key"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Translation.Provider = "babelfish"
+	assert.ErrorContains(t, cfg.Validate(), "Translation provider must be")
+}
+
+func TestConfig_ValidateImageAnalysis(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Image.AnalysisProvider = "http"
+	assert.ErrorContains(t, cfg.Validate(), "Analysis URL is required")
+
+	cfg.Image.AnalysisURL = "http://classifier:8080/labels"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Image.AnalysisMinConfidence = 1.5
+	assert.ErrorContains(t, cfg.Validate(), "minimum confidence")
+
+	cfg.Image.AnalysisMinConfidence = 0.6
+	cfg.Image.AnalysisProvider = "vision-api"
+	assert.ErrorContains(t, cfg.Validate(), "Image analysis provider must be")
+}
+
+func TestConfig_ValidateAlerting(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Alerting.AnomalyRules = "image_uploads_absent=off;quotes_drop=quotes_total drop 24h 0.4 20 warning"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Alerting.AnomalyRules = "quotes_drop=quotes_total drop 24h 2 20 warning"
+	assert.ErrorContains(t, cfg.Validate(), "drop threshold")
+
+	cfg.Alerting.AnomalyRules = ""
+	cfg.Alerting.SlackWebhookURL = "https://hooks.slack.com/services/T000/B000/XXXX"
+	cfg.Alerting.EmailRecipients = []string{"ops@example.com"}
+	notifiers, err := cfg.GetAlertNotifiers()
+	assert.NoError(t, err)
+	assert.Len(t, notifiers, 2)
+	assert.Equal(t, redactedValue, cfg.Redacted()["Alerting"]["SlackWebhookURL"])
+}
+
+func TestConfig_ValidateCORS(t *testing.T) {
+	cfg := LoadConfig()
+	cors, err := cfg.GetCORSConfig()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cors.Routes)
+
+	cfg.CORS.Routes = "/api/admin/=credentials:*"
+	assert.ErrorContains(t, cfg.Validate(), "credentials cannot be allowed for any origin")
+
+	cfg.CORS.Routes = ""
+	cfg.Server.Environment = "production"
+	cfg.Security.JWTSecret = "production-secret"
+	cfg.Widget.SigningSecret = "widget-secret"
+	cfg.CORS.CredentialOrigins = []string{"http://admin.realty.ec"}
+	assert.ErrorContains(t, cfg.Validate(), "must use https in production")
+}
+
+func TestConfig_ValidateSecurityHeaders(t *testing.T) {
+	cfg := LoadConfig()
+	headers, err := cfg.GetSecurityHeaders()
+	assert.NoError(t, err)
+	assert.Len(t, headers.Routes, 1)
+
+	cfg.Security.HSTSPreload = true
+	cfg.Security.HSTSIncludeSubdomains = false
+	assert.ErrorContains(t, cfg.Validate(), "invalid HSTS preload")
+
+	cfg.Security.HSTSPreload = false
+	cfg.Security.CSPRoutes = "docs=default-src 'self'"
+	assert.ErrorContains(t, cfg.Validate(), "invalid CSP route")
+}
+
+func TestConfig_ValidateGoogleCalendar(t *testing.T) {
+	cfg := LoadConfig()
+	client, err := cfg.GetGoogleCalendarClient()
+	require.NoError(t, err)
+	assert.Nil(t, client, "calendar sync is disabled without a client ID")
+
+	cfg.Calendar.GoogleClientID = "client-id.apps.googleusercontent.com"
+	assert.ErrorContains(t, cfg.Validate(), "Google Calendar client secret")
+
+	cfg.Calendar.GoogleClientSecret = "secret"
+	cfg.Calendar.GoogleRedirectURL = "/api/calendar/google/callback"
+	assert.ErrorContains(t, cfg.Validate(), "must be an absolute URL")
+
+	cfg.Calendar.GoogleRedirectURL = "https://api.example.com/api/calendar/google/callback"
+	assert.NoError(t, cfg.Validate())
+	client, err = cfg.GetGoogleCalendarClient()
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+func TestConfig_ValidateCallMasking(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.CallMasking.ProxyNumbers = []string{"0991110000"}
+	assert.ErrorContains(t, cfg.Validate(), "E.164")
+
+	cfg.CallMasking.ProxyNumbers = []string{"+593991110000", "+593991110001"}
+	cfg.Security.TwilioAuthToken = ""
+	assert.ErrorContains(t, cfg.Validate(), "Twilio auth token")
+
+	cfg.Security.TwilioAuthToken = "token"
+	cfg.CallMasking.WebhookURL = "/api/calls"
+	assert.ErrorContains(t, cfg.Validate(), "must be an absolute URL")
+
+	cfg.CallMasking.WebhookURL = "https://api.example.com/api/calls"
+	assert.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in the redacted configuration
+const redactedValue = "[REDACTED]"
+
+// dsnPassword matches the password of a key=value connection string
+var dsnPassword = regexp.MustCompile(`password=\S+`)
+
+// secretFieldMarkers identify configuration fields holding credentials; webhook URLs
+// such as Slack's embed their token
+var secretFieldMarkers = []string{"Secret", "Password", "Token", "APIKey", "AccessKey", "SigningKey", "EncryptionKey", "LicenseKey", "WebhookURL"}
+
+// Redacted returns the configuration by section and field with credentials masked and
+// passwords removed from URLs, for the admin config endpoint and logs
+func (c *Config) Redacted() map[string]map[string]interface{} {
+	redacted := map[string]map[string]interface{}{}
+	for name, value := range c.fields() {
+		section, field, _ := strings.Cut(name, ".")
+		if redacted[section] == nil {
+			redacted[section] = map[string]interface{}{}
+		}
+		redacted[section][field] = redactField(field, value)
+	}
+	return redacted
+}
+
+// fields flattens the configuration into "Section.Field" values
+func (c *Config) fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+		sectionName := root.Type().Field(i).Name
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			if !field.IsExported() {
+				continue
+			}
+			fields[sectionName+"."+field.Name] = section.Field(j).Interface()
+		}
+	}
+	return fields
+}
+
+// redactField masks secrets, strips URL credentials and formats durations
+func redactField(name string, value interface{}) interface{} {
+	if isSecretField(name) {
+		if s, ok := value.(string); ok && s == "" {
+			return ""
+		}
+		return redactedValue
+	}
+
+	switch v := value.(type) {
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	case string:
+		if strings.HasSuffix(name, "URL") {
+			return redactURL(v)
+		}
+	}
+	return value
+}
+
+// isSecretField reports whether a configuration field holds a credential
+func isSecretField(name string) bool {
+	for _, marker := range secretFieldMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL removes the password from a URL or key=value database connection string
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err == nil && parsed.User != nil {
+		return parsed.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(raw, "password=xxxxx")
+}
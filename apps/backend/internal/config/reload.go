@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// reloadableSetting is a setting that can change without a restart
+type reloadableSetting struct {
+	field string // "Section.Field", as in Redacted
+	apply func(dst, src *Config)
+}
+
+// reloadableSettings lists the settings applied on reload. Everything else needs a
+// restart: connections, storage backends and workers are built once at startup.
+var reloadableSettings = []reloadableSetting{
+	{"Security.RateLimitPerMinute", func(dst, src *Config) { dst.Security.RateLimitPerMinute = src.Security.RateLimitPerMinute }},
+	{"Security.BlockedCountries", func(dst, src *Config) { dst.Security.BlockedCountries = src.Security.BlockedCountries }},
+	{"Cache.PropertyTTL", func(dst, src *Config) { dst.Cache.PropertyTTL = src.Cache.PropertyTTL }},
+	{"Cache.SearchTTL", func(dst, src *Config) { dst.Cache.SearchTTL = src.Cache.SearchTTL }},
+	{"Cache.StatisticsTTL", func(dst, src *Config) { dst.Cache.StatisticsTTL = src.Cache.StatisticsTTL }},
+}
+
+// ReloadableSettings returns the settings that can change without a restart
+func ReloadableSettings() []string {
+	fields := make([]string, len(reloadableSettings))
+	for i, setting := range reloadableSettings {
+		fields[i] = setting.field
+	}
+	return fields
+}
+
+// ReloadResult reports what a configuration reload changed
+type ReloadResult struct {
+	ReloadedAt      time.Time `json:"reloaded_at"`
+	Trigger         string    `json:"trigger"` // signal, api
+	Applied         []string  `json:"applied"`
+	RequiresRestart []string  `json:"requires_restart"`
+}
+
+// Watcher re-reads the configuration on SIGHUP or on demand and applies the settings
+// that are safe to change at runtime. Components subscribe with OnReload, e.g.
+//
+//	watcher.OnReload(func(cfg *config.Config) {
+//		securityMiddleware.SetRateLimit(cfg.Security.RateLimitPerMinute)
+//		securityMiddleware.SetBlockedCountries(cfg.Security.BlockedCountries)
+//		propertyService.SetCacheTTLs(cfg.Cache.PropertyTTL, cfg.Cache.SearchTTL, cfg.Cache.StatisticsTTL)
+//	})
+type Watcher struct {
+	envFile string
+	logger  *log.Logger
+
+	mu         sync.RWMutex
+	current    *Config
+	lastReload *ReloadResult
+	listeners  []func(*Config)
+	reloading  sync.Mutex
+
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+// NewWatcher creates a watcher for the running configuration. When envFile is set it
+// is re-read on every reload, overriding the process environment.
+func NewWatcher(current *Config, envFile string, logger *log.Logger) *Watcher {
+	return &Watcher{
+		envFile: envFile,
+		logger:  logger,
+		current: current,
+	}
+}
+
+// Current returns the effective configuration
+func (w *Watcher) Current() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// LastReload returns the result of the last reload, or nil when none happened
+func (w *Watcher) LastReload() *ReloadResult {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastReload
+}
+
+// OnReload registers a function called with the new configuration after every reload
+// that changed a reloadable setting
+func (w *Watcher) OnReload(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+// Start reloads the configuration on every SIGHUP until Stop is called
+func (w *Watcher) Start() {
+	w.signals = make(chan os.Signal, 1)
+	w.done = make(chan struct{})
+	signal.Notify(w.signals, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-w.signals:
+				if _, err := w.Reload("signal"); err != nil {
+					w.logger.Printf("Configuration reload failed, keeping the current configuration: %v", err)
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching for SIGHUP
+func (w *Watcher) Stop() {
+	if w.signals == nil {
+		return
+	}
+	signal.Stop(w.signals)
+	close(w.done)
+	w.signals = nil
+}
+
+// Reload re-reads the configuration and applies the reloadable settings. An invalid
+// configuration is rejected as a whole; changed settings that need a restart are
+// reported but not applied.
+func (w *Watcher) Reload(trigger string) (*ReloadResult, error) {
+	w.reloading.Lock()
+	defer w.reloading.Unlock()
+
+	if w.envFile != "" {
+		if err := godotenv.Overload(w.envFile); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", w.envFile, err)
+		}
+	}
+
+	next := LoadConfig()
+	if _, err := next.LoadSecrets(); err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+
+	current := w.Current()
+	merged := *current
+	result := &ReloadResult{ReloadedAt: time.Now(), Trigger: trigger, Applied: []string{}, RequiresRestart: []string{}}
+
+	reloadable := map[string]bool{}
+	for _, setting := range reloadableSettings {
+		reloadable[setting.field] = true
+		setting.apply(&merged, next)
+	}
+
+	currentFields := current.fields()
+	for field, value := range next.fields() {
+		if reflect.DeepEqual(currentFields[field], value) {
+			continue
+		}
+		if reloadable[field] {
+			result.Applied = append(result.Applied, field)
+		} else {
+			result.RequiresRestart = append(result.RequiresRestart, field)
+		}
+	}
+	sort.Strings(result.Applied)
+	sort.Strings(result.RequiresRestart)
+
+	w.mu.Lock()
+	w.current = &merged
+	w.lastReload = result
+	listeners := append([]func(*Config){}, w.listeners...)
+	w.mu.Unlock()
+
+	if len(result.Applied) > 0 {
+		for _, listener := range listeners {
+			listener(&merged)
+		}
+	}
+
+	w.logger.Printf("Configuration reloaded (%s): applied %v, requires restart %v", trigger, result.Applied, result.RequiresRestart)
+	return result, nil
+}
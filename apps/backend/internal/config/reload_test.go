@@ -0,0 +1,82 @@
+package config
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "100")
+	t.Setenv("PORT", "8080")
+
+	watcher := NewWatcher(LoadConfig(), "", log.New(io.Discard, "", 0))
+	var applied *Config
+	watcher.OnReload(func(cfg *Config) { applied = cfg })
+
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "250")
+	t.Setenv("CACHE_SEARCH_TTL", "30s")
+	t.Setenv("PORT", "9090")
+
+	result, err := watcher.Reload("api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cache.SearchTTL", "Security.RateLimitPerMinute"}, result.Applied)
+	assert.Equal(t, []string{"Server.Port"}, result.RequiresRestart)
+
+	current := watcher.Current()
+	assert.Equal(t, 250, current.Security.RateLimitPerMinute)
+	assert.Equal(t, 30*time.Second, current.Cache.SearchTTL)
+	assert.Equal(t, "8080", current.Server.Port, "settings needing a restart are not applied")
+	assert.Same(t, current, applied)
+	assert.Same(t, result, watcher.LastReload())
+}
+
+func TestWatcher_ReloadRejectsInvalidConfig(t *testing.T) {
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "100")
+	watcher := NewWatcher(LoadConfig(), "", log.New(io.Discard, "", 0))
+
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "-5")
+	_, err := watcher.Reload("signal")
+	assert.Error(t, err)
+	assert.Equal(t, 100, watcher.Current().Security.RateLimitPerMinute)
+	assert.Nil(t, watcher.LastReload())
+}
+
+func TestWatcher_ReloadEnvFile(t *testing.T) {
+	t.Setenv("CACHE_PROPERTY_TTL", "5m")
+	watcher := NewWatcher(LoadConfig(), filepath.Join(t.TempDir(), ".env"), log.New(io.Discard, "", 0))
+
+	require.NoError(t, os.WriteFile(watcher.envFile, []byte("CACHE_PROPERTY_TTL=10m\n"), 0o600))
+
+	result, err := watcher.Reload("signal")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cache.PropertyTTL"}, result.Applied)
+	assert.Equal(t, 10*time.Minute, watcher.Current().Cache.PropertyTTL)
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := LoadConfig()
+	cfg.Database.URL = "postgresql://realty:s3cret@db:5432/inmobiliaria_db?sslmode=disable"
+	cfg.JWT.SecretKey = "jwt-secret"
+	cfg.Image.S3SecretAccessKey = ""
+	cfg.Search.MeilisearchAPIKey = "meili-key"
+	cfg.JWT.KeyEncryptionKey = "key-encryption-key"
+	cfg.Security.GeoIPLicenseKey = "maxmind-license"
+
+	redacted := cfg.Redacted()
+	assert.Equal(t, "postgresql://realty:xxxxx@db:5432/inmobiliaria_db?sslmode=disable", redacted["Database"]["URL"])
+	assert.Equal(t, redactedValue, redacted["JWT"]["SecretKey"])
+	assert.Equal(t, redactedValue, redacted["Search"]["MeilisearchAPIKey"])
+	assert.Equal(t, redactedValue, redacted["JWT"]["KeyEncryptionKey"])
+	assert.Equal(t, redactedValue, redacted["Security"]["GeoIPLicenseKey"])
+	assert.Equal(t, "", redacted["Image"]["S3SecretAccessKey"], "unset secrets show as unset")
+	assert.Equal(t, "5m0s", redacted["Cache"]["PropertyTTL"])
+
+	assert.Equal(t, "host=db password=xxxxx sslmode=disable", redactURL("host=db password=s3cret sslmode=disable"))
+}
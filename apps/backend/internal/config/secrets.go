@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+
+	"realty-core/internal/secrets"
+)
+
+// GetSecretsConfig returns the settings of the secrets backend
+func (c *Config) GetSecretsConfig() secrets.Config {
+	return secrets.Config{
+		Backend:            c.Secrets.Backend,
+		Timeout:            c.Secrets.Timeout,
+		AWSRegion:          c.Secrets.AWSRegion,
+		AWSEndpoint:        c.Secrets.AWSEndpoint,
+		AWSAccessKeyID:     c.Secrets.AWSAccessKeyID,
+		AWSSecretAccessKey: c.Secrets.AWSSecretAccessKey,
+		AWSSessionToken:    c.Secrets.AWSSessionToken,
+		VaultAddr:          c.Secrets.VaultAddr,
+		VaultToken:         c.Secrets.VaultToken,
+		VaultMount:         c.Secrets.VaultMount,
+		VaultNamespace:     c.Secrets.VaultNamespace,
+		GCPProject:         c.Secrets.GCPProject,
+		GCPAccessToken:     c.Secrets.GCPAccessToken,
+	}
+}
+
+// LoadSecrets replaces the JWT keys, database URL and SMTP credentials with the secrets
+// their refs name in the configured backend, and returns the backend so rotated
+// secrets can be watched. Call it after LoadConfig and before Validate.
+func (c *Config) LoadSecrets() (secrets.Provider, error) {
+	provider, err := secrets.NewProvider(c.GetSecretsConfig())
+	if err != nil {
+		return nil, &ConfigError{Field: "SECRETS_BACKEND", Message: err.Error()}
+	}
+	if err := c.ApplySecrets(provider); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// ApplySecrets reads every configured secret ref from provider
+func (c *Config) ApplySecrets(provider secrets.Provider) error {
+	targets := []struct {
+		field string
+		ref   string
+		value *string
+	}{
+		{"SECRET_JWT_KEY_REF", c.Secrets.JWTKeyRef, &c.JWT.SecretKey},
+		{"SECRET_JWT_KEY_ENCRYPTION_KEY_REF", c.Secrets.JWTKeyEncryptionKeyRef, &c.JWT.KeyEncryptionKey},
+		{"SECRET_DATABASE_URL_REF", c.Secrets.DatabaseURLRef, &c.Database.URL},
+		{"SECRET_SMTP_USERNAME_REF", c.Secrets.SMTPUsernameRef, &c.SMTP.Username},
+		{"SECRET_SMTP_PASSWORD_REF", c.Secrets.SMTPPasswordRef, &c.SMTP.Password},
+		{"SECRET_PII_ENCRYPTION_KEY_REF", c.Secrets.PIIEncryptionKeyRef, &c.Security.PIIEncryptionKey},
+	}
+
+	for _, target := range targets {
+		if target.ref == "" {
+			continue
+		}
+		value, err := secrets.Resolve(provider, target.ref)
+		if err != nil {
+			return &ConfigError{Field: target.field, Message: fmt.Sprintf("failed to load secret from %s: %v", provider.Name(), err)}
+		}
+		*target.value = value
+	}
+	return nil
+}
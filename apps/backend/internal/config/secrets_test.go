@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSecrets serves secrets from a map
+type stubSecrets map[string]string
+
+func (s stubSecrets) Name() string { return "stub" }
+
+func (s stubSecrets) GetSecret(name string) (string, error) {
+	if value, ok := s[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret not found: %s", name)
+}
+
+func TestConfig_ApplySecrets(t *testing.T) {
+	t.Setenv("SECRET_JWT_KEY_REF", "realty/jwt")
+	t.Setenv("SECRET_DATABASE_URL_REF", "realty/db#url")
+	t.Setenv("SECRET_SMTP_PASSWORD_REF", "realty/smtp#password")
+	t.Setenv("SMTP_USERNAME", "mailer")
+
+	cfg := LoadConfig()
+	err := cfg.ApplySecrets(stubSecrets{
+		"realty/jwt":  `{"signing_key_id":"2025-08","keys":{"2025-08":"new","default":"old"}}`,
+		"realty/db":   `{"url":"postgres://app:pw@db:5432/realty"}`,
+		"realty/smtp": `{"password":"s3cret"}`,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://app:pw@db:5432/realty", cfg.Database.URL)
+	assert.Equal(t, "mailer", cfg.SMTP.Username, "fields without a ref keep the environment value")
+	assert.Equal(t, "s3cret", cfg.SMTP.Password)
+	require.NoError(t, cfg.Validate())
+
+	keys, err := cfg.GetJWTKeySet()
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08", keys.SigningKeyID)
+
+	err = cfg.ApplySecrets(stubSecrets{})
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "SECRET_JWT_KEY_REF", configErr.Field)
+}
+
+func TestConfig_LoadSecretsEnvBackend(t *testing.T) {
+	t.Setenv("SECRET_DATABASE_URL_REF", "REALTY_TEST_DATABASE_URL")
+	t.Setenv("REALTY_TEST_DATABASE_URL", "postgres://secret-host/realty")
+
+	cfg := LoadConfig()
+	provider, err := cfg.LoadSecrets()
+	require.NoError(t, err)
+	assert.Equal(t, "env", provider.Name())
+	assert.Equal(t, "postgres://secret-host/realty", cfg.Database.URL)
+
+	cfg.Secrets.Backend = "keychain"
+	_, err = cfg.LoadSecrets()
+	assert.Error(t, err)
+}
+
+func TestConfig_ValidateJWTSigningAlgorithm(t *testing.T) {
+	cfg := LoadConfig()
+	require.NoError(t, cfg.Validate(), "HS256 needs no key encryption key")
+
+	cfg.JWT.SigningAlgorithm = "EdDSA"
+	assert.Error(t, cfg.Validate(), "stored key pairs must be sealed")
+
+	cfg.JWT.KeyEncryptionKey = "0123456789abcdef0123456789abcdef"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.JWT.KeyGracePeriod = time.Minute
+	assert.Error(t, cfg.Validate(), "retired keys must outlive the tokens they signed")
+
+	cfg.JWT.SigningAlgorithm = "ES256"
+	assert.Error(t, cfg.Validate())
+}
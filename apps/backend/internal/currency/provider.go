@@ -0,0 +1,107 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// Provider fetches the latest exchange rates of a base currency
+type Provider interface {
+	// Name identifies the provider in rate responses
+	Name() string
+
+	// FetchRates returns the latest rates from base to other currencies
+	FetchRates(base string) (*domain.ExchangeRates, error)
+}
+
+// HTTPProvider fetches rates from an ExchangeRate-API compatible endpoint
+// (e.g. https://open.er-api.com/v6/latest). The base currency is appended to the
+// endpoint and the server answers with:
+//
+//	{"result": "success", "base_code": "USD", "time_last_update_unix": 1755216000,
+//	 "rates": {"COP": 4025.5, "PEN": 3.55}}
+type HTTPProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPProvider creates a provider for a rates endpoint
+func NewHTTPProvider(endpoint string, timeout time.Duration) (*HTTPProvider, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("exchange rates endpoint is required")
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates endpoint: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &HTTPProvider{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (p *HTTPProvider) Name() string {
+	return "exchangerate-api"
+}
+
+// FetchRates requests the latest rates of base
+func (p *HTTPProvider) FetchRates(base string) (*domain.ExchangeRates, error) {
+	req, err := http.NewRequest(http.MethodGet, p.endpoint+"/"+url.PathEscape(base), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting exchange rates provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("exchange rates provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var body struct {
+		Result     string             `json:"result"`
+		ErrorType  string             `json:"error-type"`
+		BaseCode   string             `json:"base_code"`
+		LastUpdate int64              `json:"time_last_update_unix"`
+		Rates      map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error decoding exchange rates response: %w", err)
+	}
+	if body.Result != "" && body.Result != "success" {
+		return nil, fmt.Errorf("exchange rates provider returned %s: %s", body.Result, body.ErrorType)
+	}
+	if body.BaseCode != base || len(body.Rates) == 0 {
+		return nil, fmt.Errorf("exchange rates provider returned no rates for %s", base)
+	}
+
+	rates := &domain.ExchangeRates{
+		Base:      base,
+		Rates:     body.Rates,
+		Date:      time.Now().UTC(),
+		Provider:  p.Name(),
+		FetchedAt: time.Now().UTC(),
+	}
+	if body.LastUpdate > 0 {
+		rates.Date = time.Unix(body.LastUpdate, 0).UTC()
+	}
+	return rates, nil
+}
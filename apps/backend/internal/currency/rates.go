@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// Rate refresh intervals. Providers publish daily rates; after a failed refresh the
+// provider is not contacted again until the retry interval has passed.
+const (
+	DefaultRatesTTL    = 24 * time.Hour
+	ratesRetryInterval = 5 * time.Minute
+)
+
+// RateCache keeps the latest USD exchange rates of a provider in memory and refreshes
+// them when they are older than the TTL. When a refresh fails the previous rates keep
+// being served, so a provider outage only makes display prices a little stale.
+type RateCache struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	rates    *domain.ExchangeRates
+	failedAt time.Time
+}
+
+// NewRateCache creates a rate cache for a provider
+func NewRateCache(provider Provider, ttl time.Duration) (*RateCache, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("exchange rates provider is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultRatesTTL
+	}
+	return &RateCache{provider: provider, ttl: ttl, now: time.Now}, nil
+}
+
+// Rates returns the cached rates, fetching them when missing or expired
+func (c *RateCache) Rates() (*domain.ExchangeRates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.rates != nil && now.Sub(c.rates.FetchedAt) < c.ttl {
+		return c.rates, nil
+	}
+	if !c.failedAt.IsZero() && now.Sub(c.failedAt) < ratesRetryInterval {
+		return c.stale()
+	}
+
+	rates, err := c.provider.FetchRates(domain.BaseCurrency)
+	if err != nil {
+		c.failedAt = now
+		if c.rates != nil {
+			return c.rates, nil
+		}
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+
+	rates.FetchedAt = now
+	c.rates = rates
+	c.failedAt = time.Time{}
+	return rates, nil
+}
+
+// stale returns the previous rates while the provider is failing
+func (c *RateCache) stale() (*domain.ExchangeRates, error) {
+	if c.rates == nil {
+		return nil, fmt.Errorf("exchange rates are unavailable")
+	}
+	return c.rates, nil
+}
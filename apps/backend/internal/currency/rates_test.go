@@ -0,0 +1,108 @@
+package currency
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// stubProvider counts fetches and fails on demand
+type stubProvider struct {
+	fetches int
+	err     error
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) FetchRates(base string) (*domain.ExchangeRates, error) {
+	p.fetches++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &domain.ExchangeRates{Base: base, Rates: map[string]float64{"COP": 4000 + float64(p.fetches)}}, nil
+}
+
+func TestHTTPProvider_FetchRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v6/latest/USD", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"result":"success","base_code":"USD","time_last_update_unix":1755216000,"rates":{"USD":1,"COP":4025.5,"PEN":3.55}}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider(server.URL+"/v6/latest/", time.Second)
+	require.NoError(t, err)
+
+	rates, err := provider.FetchRates("USD")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rates.Base)
+	assert.Equal(t, 4025.5, rates.Rates["COP"])
+	assert.Equal(t, time.Unix(1755216000, 0).UTC(), rates.Date)
+
+	_, err = NewHTTPProvider("", 0)
+	assert.Error(t, err)
+}
+
+func TestHTTPProvider_ProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":"error","error-type":"unsupported-code"}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider(server.URL, time.Second)
+	require.NoError(t, err)
+
+	_, err = provider.FetchRates("USD")
+	assert.ErrorContains(t, err, "unsupported-code")
+}
+
+func TestRateCache_Rates(t *testing.T) {
+	provider := &stubProvider{}
+	cache, err := NewRateCache(provider, 24*time.Hour)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	rates, err := cache.Rates()
+	require.NoError(t, err)
+	assert.Equal(t, 4001.0, rates.Rates["COP"])
+
+	now = now.Add(time.Hour)
+	_, err = cache.Rates()
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.fetches, "rates are cached for the day")
+
+	now = now.Add(24 * time.Hour)
+	provider.err = fmt.Errorf("provider down")
+	rates, err = cache.Rates()
+	require.NoError(t, err, "stale rates are served while the provider fails")
+	assert.Equal(t, 4001.0, rates.Rates["COP"])
+
+	_, err = cache.Rates()
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.fetches, "a failing provider is not retried on every request")
+
+	now = now.Add(ratesRetryInterval)
+	provider.err = nil
+	rates, err = cache.Rates()
+	require.NoError(t, err)
+	assert.Equal(t, 4003.0, rates.Rates["COP"])
+}
+
+func TestRateCache_Unavailable(t *testing.T) {
+	cache, err := NewRateCache(&stubProvider{err: fmt.Errorf("provider down")}, 0)
+	require.NoError(t, err)
+
+	_, err = cache.Rates()
+	assert.ErrorContains(t, err, "failed to fetch exchange rates")
+	_, err = cache.Rates()
+	assert.ErrorContains(t, err, "unavailable")
+}
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of agency transactions
+const (
+	TransactionSubscription = "subscription"
+	TransactionFeatured     = "featured"
+	TransactionCommission   = "commission"
+)
+
+// Directions of agency transactions: commissions are income, platform fees expenses
+const (
+	TransactionIncome  = "income"
+	TransactionExpense = "expense"
+)
+
+// Accounting export formats: a plain CSV ledger, or journal entries laid out for import
+// into Contifico and other Ecuadorian accounting tools
+const (
+	ExportFormatCSV       = "csv"
+	ExportFormatContifico = "contifico"
+)
+
+// MaxExportDays bounds the period of an accounting export to two years
+const MaxExportDays = 2 * 366
+
+// IsValidExportFormat verifies if an accounting export format is supported
+func IsValidExportFormat(format string) bool {
+	return format == ExportFormatCSV || format == ExportFormatContifico
+}
+
+// AgencyTransaction is an entry of an agency's ledger
+type AgencyTransaction struct {
+	Date        time.Time `json:"date"`
+	Kind        string    `json:"kind"`
+	Direction   string    `json:"direction"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	// DocumentNumber and AuthorizationNumber identify the SRI invoice of platform fees
+	DocumentNumber      string  `json:"document_number,omitempty"`
+	AuthorizationNumber string  `json:"authorization_number,omitempty"`
+	Subtotal            float64 `json:"subtotal"`
+	IVA                 float64 `json:"iva"`
+	Total               float64 `json:"total"`
+}
+
+// AgencyLedger holds the transactions of an agency from From to the day before To
+type AgencyLedger struct {
+	AgencyID     string              `json:"agency_id"`
+	From         time.Time           `json:"from"`
+	To           time.Time           `json:"to"`
+	Transactions []AgencyTransaction `json:"transactions"`
+}
+
+// FileName suggests the name of an export of the ledger
+func (l *AgencyLedger) FileName(format string) string {
+	name := fmt.Sprintf("transacciones-%s-%s", l.From.Format(CalendarDateLayout), l.To.AddDate(0, 0, -1).Format(CalendarDateLayout))
+	if format == ExportFormatContifico {
+		name += "-contifico"
+	}
+	return name + ".csv"
+}
+
+// AccountingAccounts are the ledger accounts journal entries are booked to
+type AccountingAccounts struct {
+	Bank                string
+	Receivable          string
+	IVACredit           string
+	CommissionIncome    string
+	SubscriptionExpense string
+	AdvertisingExpense  string
+}
+
+// DefaultAccountingAccounts follows the chart of accounts of the Superintendencia de
+// Compañías used by most Ecuadorian small businesses
+var DefaultAccountingAccounts = AccountingAccounts{
+	Bank:                "1.1.01.02",
+	Receivable:          "1.1.02.05",
+	IVACredit:           "1.1.05.01",
+	CommissionIncome:    "4.1.01.02",
+	SubscriptionExpense: "5.2.02.12",
+	AdvertisingExpense:  "5.2.02.14",
+}
+
+// InvoiceTransaction turns a platform fee invoice into an expense of the agency
+func InvoiceTransaction(invoice ElectronicInvoice) AgencyTransaction {
+	kind := TransactionSubscription
+	if invoice.Source == InvoiceSourceFeatured {
+		kind = TransactionFeatured
+	}
+	descriptions := make([]string, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		descriptions = append(descriptions, line.Description)
+	}
+	return AgencyTransaction{
+		Date:                invoice.IssueDate,
+		Kind:                kind,
+		Direction:           TransactionExpense,
+		Reference:           invoice.ID,
+		Description:         strings.Join(descriptions, "; "),
+		DocumentNumber:      invoice.Number(),
+		AuthorizationNumber: invoice.AuthorizationNumber,
+		Subtotal:            invoice.Subtotal,
+		IVA:                 invoice.IVA,
+		Total:               invoice.Total,
+	}
+}
+
+// CommissionTransaction turns the commission of a deal into income of the agency. The
+// commission is exported as recorded; its IVA is declared on the agency's own invoices.
+func CommissionTransaction(deal Deal) AgencyTransaction {
+	operation := "venta"
+	if deal.Type == DealTypeRent {
+		operation = "arriendo"
+	}
+	return AgencyTransaction{
+		Date:        CalendarDate(deal.ClosingDate),
+		Kind:        TransactionCommission,
+		Direction:   TransactionIncome,
+		Reference:   deal.ID,
+		Description: fmt.Sprintf("Comisión %s %s%% sobre %s (propiedad %s)", operation, fmt.Sprint(deal.CommissionPercent), FormatMoney(deal.FinalPrice), deal.PropertyID),
+		Subtotal:    deal.CommissionAmount,
+		Total:       deal.CommissionAmount,
+	}
+}
+
+// SortTransactions orders transactions by date, then reference
+func SortTransactions(transactions []AgencyTransaction) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.Before(transactions[j].Date)
+		}
+		return transactions[i].Reference < transactions[j].Reference
+	})
+}
+
+// WriteTransactionsCSV writes transactions as a comma separated ledger with a header row
+func WriteTransactionsCSV(w io.Writer, transactions []AgencyTransaction) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "kind", "direction", "reference", "description",
+		"document_number", "authorization_number", "subtotal", "iva", "total"})
+	for _, t := range transactions {
+		writer.Write([]string{
+			t.Date.Format(CalendarDateLayout), t.Kind, t.Direction, t.Reference, csvText(t.Description),
+			t.DocumentNumber, t.AuthorizationNumber, sriAmount(t.Subtotal), sriAmount(t.IVA), sriAmount(t.Total),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteContificoJournal writes transactions as balanced journal entries, one row per
+// account movement: Fecha;Asiento;Cuenta;Descripción;Documento;Debe;Haber, with dates
+// as dd/mm/yyyy as Contifico's journal import expects
+func WriteContificoJournal(w io.Writer, transactions []AgencyTransaction, accounts AccountingAccounts) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	writer.Write([]string{"Fecha", "Asiento", "Cuenta", "Descripción", "Documento", "Debe", "Haber"})
+
+	for i, t := range transactions {
+		entry := fmt.Sprint(i + 1)
+		date := t.Date.Format(sriInvoiceDateLayout)
+		description := csvText(t.Description)
+		document := t.DocumentNumber
+		if document == "" {
+			document = t.Reference
+		}
+		row := func(account string, debit, credit float64) {
+			writer.Write([]string{date, entry, account, description, document, sriAmount(debit), sriAmount(credit)})
+		}
+
+		switch t.Direction {
+		case TransactionIncome:
+			row(accounts.Receivable, t.Total, 0)
+			row(accounts.CommissionIncome, 0, t.Total)
+		default:
+			expense := accounts.SubscriptionExpense
+			if t.Kind == TransactionFeatured {
+				expense = accounts.AdvertisingExpense
+			}
+			row(expense, t.Subtotal, 0)
+			if t.IVA > 0 {
+				row(accounts.IVACredit, t.IVA, 0)
+			}
+			row(accounts.Bank, 0, t.Total)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText keeps spreadsheets from evaluating text cells as formulas
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTransactions() []AgencyTransaction {
+	invoice := ElectronicInvoice{
+		ID: "inv-1", Source: InvoiceSourceFeatured, Establishment: "001", EmissionPoint: "001", Sequential: 7,
+		IssueDate: time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC), AuthorizationNumber: "0809202501",
+		Lines:    []InvoiceLine{{Code: "DESTACADO", Description: "Anuncio destacado: Casa (días)"}},
+		Subtotal: 14, IVA: 2.1, Total: 16.1,
+	}
+	deal := Deal{
+		ID: "deal-1", PropertyID: "prop-1", Type: DealTypeSale, FinalPrice: 120000,
+		CommissionPercent: 3, CommissionAmount: 3600, ClosingDate: time.Date(2025, 9, 1, 16, 30, 0, 0, time.UTC),
+	}
+	transactions := []AgencyTransaction{InvoiceTransaction(invoice), CommissionTransaction(deal)}
+	SortTransactions(transactions)
+	return transactions
+}
+
+func TestWriteTransactionsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTransactionsCSV(&buf, testTransactions()))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "date", rows[0][0])
+	assert.Equal(t, []string{"2025-09-01", "commission", "income", "deal-1", "Comisión venta 3% sobre $120,000.00 (propiedad prop-1)", "", "", "3600.00", "0.00", "3600.00"}, rows[1])
+	assert.Equal(t, []string{"2025-09-08", "featured", "expense", "inv-1", "Anuncio destacado: Casa (días)", "001-001-000000007", "0809202501", "14.00", "2.10", "16.10"}, rows[2])
+}
+
+func TestWriteContificoJournal(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteContificoJournal(&buf, testTransactions(), DefaultAccountingAccounts))
+
+	reader := csv.NewReader(&buf)
+	reader.Comma = ';'
+	rows, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	assert.Equal(t, []string{"Fecha", "Asiento", "Cuenta", "Descripción", "Documento", "Debe", "Haber"}, rows[0])
+	assert.Equal(t, "01/09/2025", rows[1][0])
+	assert.Equal(t, DefaultAccountingAccounts.CommissionIncome, rows[2][2])
+	assert.Equal(t, DefaultAccountingAccounts.AdvertisingExpense, rows[3][2])
+	assert.Equal(t, "001-001-000000007", rows[3][4])
+
+	// Every entry balances
+	balance := map[string]float64{}
+	for _, row := range rows[1:] {
+		debit, _ := strconv.ParseFloat(row[5], 64)
+		credit, _ := strconv.ParseFloat(row[6], 64)
+		balance[row[1]] += debit - credit
+	}
+	for entry, amount := range balance {
+		assert.InDelta(t, 0, amount, 0.001, "entry %s", entry)
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	assert.Equal(t, "'=HYPERLINK(\"x\")", csvText("=HYPERLINK(\"x\")"))
+	assert.Equal(t, "Plan pro", csvText("Plan pro"))
+}
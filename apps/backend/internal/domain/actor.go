@@ -0,0 +1,36 @@
+package domain
+
+// Actor is the authenticated user performing an operation, as carried in the JWT claims
+type Actor struct {
+	UserID   string
+	Role     UserRole
+	AgencyID string // agency of agency and agent users
+}
+
+// NewActor creates an actor from request claims
+func NewActor(userID, role, agencyID string) Actor {
+	return Actor{UserID: userID, Role: UserRole(role), AgencyID: agencyID}
+}
+
+// CanAdministerAgency reports whether the actor manages the agency's team and listings:
+// admins manage every agency and agency accounts manage their own. As in
+// User.CanManageProperty, an agency account may be identified by its user ID.
+func (a Actor) CanAdministerAgency(agencyID string) bool {
+	switch a.Role {
+	case RoleAdmin:
+		return true
+	case RoleAgency:
+		return agencyID != "" && (a.AgencyID == agencyID || a.UserID == agencyID)
+	default:
+		return false
+	}
+}
+
+// BelongsToAgency reports whether the actor may see the agency's account: admins see
+// every agency, agency accounts and agents their own
+func (a Actor) BelongsToAgency(agencyID string) bool {
+	if a.CanAdministerAgency(agencyID) {
+		return true
+	}
+	return a.Role == RoleAgent && agencyID != "" && a.AgencyID == agencyID
+}
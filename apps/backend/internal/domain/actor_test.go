@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor_CanAdministerAgency(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    Actor
+		agencyID string
+		expected bool
+	}{
+		{"admin manages any agency", NewActor("admin-1", "admin", ""), "agency-1", true},
+		{"agency manages its own agency", NewActor("user-1", "agency", "agency-1"), "agency-1", true},
+		{"agency identified by user ID", NewActor("agency-1", "agency", ""), "agency-1", true},
+		{"agency cannot manage another agency", NewActor("user-1", "agency", "agency-2"), "agency-1", false},
+		{"agents cannot administer their agency", NewActor("agent-1", "agent", "agency-1"), "agency-1", false},
+		{"empty agency", NewActor("user-1", "agency", ""), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.actor.CanAdministerAgency(tt.agencyID))
+		})
+	}
+}
+
+func TestActor_BelongsToAgency(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    Actor
+		agencyID string
+		expected bool
+	}{
+		{"admin sees any agency", NewActor("admin-1", "admin", ""), "agency-1", true},
+		{"agency sees its own agency", NewActor("user-1", "agency", "agency-1"), "agency-1", true},
+		{"agents see their agency", NewActor("agent-1", "agent", "agency-1"), "agency-1", true},
+		{"agents cannot see another agency", NewActor("agent-1", "agent", "agency-2"), "agency-1", false},
+		{"buyers see no agency", NewActor("buyer-1", "buyer", "agency-1"), "agency-1", false},
+		{"empty agency", NewActor("agent-1", "agent", ""), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.actor.BelongsToAgency(tt.agencyID))
+		})
+	}
+}
@@ -34,6 +34,7 @@ type Agency struct {
 	Description    *string           `json:"description" db:"description"`
 	Logo           *string           `json:"logo" db:"logo"`
 	LogoURL        *string           `json:"logo_url" db:"logo_url"`
+	LogoVariants   map[string]string `json:"logo_variants,omitempty"` // square sizes of the logo, see ProfileImageVariants
 	Status         AgencyStatus      `json:"status" db:"status"`
 	Active         bool              `json:"active" db:"active"`
 	OwnerID        string            `json:"owner_id" db:"owner_id"`   // User who owns/manages the agency
@@ -227,11 +228,15 @@ func (a *Agency) SetDescription(description string) error {
 func (a *Agency) SetLogo(logoURL string) error {
 	if logoURL == "" {
 		a.Logo = nil
+		a.LogoURL = nil
+		a.LogoVariants = nil
 		a.UpdatedAt = time.Now()
 		return nil
 	}
 
 	a.Logo = &logoURL
+	a.LogoURL = &logoURL
+	a.LogoVariants = ProfileImageVariants(&logoURL)
 	a.UpdatedAt = time.Now()
 	return nil
 }
@@ -433,6 +438,7 @@ type AgencyPerformance struct {
 	CommissionEarnings    float64 `json:"commission_earnings"`
 	ConversionRate        float64 `json:"conversion_rate"`
 	ResponseTime          float64 `json:"response_time"`
+	Subscription          *AgencySubscription `json:"subscription,omitempty"`
 }
 
 // AgencyWithAgents represents an agency with its agents
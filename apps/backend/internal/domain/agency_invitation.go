@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Agency invitation statuses
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// DefaultInvitationTTL is how long an invitation can be accepted
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
+// AgencyInvitation invites a person by email to join an agency as an agent.
+// Only the SHA-256 hash of the token is stored; the token itself is sent by email.
+type AgencyInvitation struct {
+	ID         string     `json:"id"`
+	AgencyID   string     `json:"agency_id"`
+	Email      string     `json:"email"`
+	Role       UserRole   `json:"role"`
+	TokenHash  string     `json:"-"`
+	Status     string     `json:"status"`
+	InvitedBy  string     `json:"invited_by"`
+	AcceptedBy *string    `json:"accepted_by,omitempty"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// NewAgencyInvitation creates a pending agent invitation and returns it with its token
+func NewAgencyInvitation(agencyID, email, invitedBy string, ttl time.Duration) (*AgencyInvitation, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if agencyID == "" {
+		return nil, "", fmt.Errorf("agency ID cannot be empty")
+	}
+	if err := validateEmail(email); err != nil {
+		return nil, "", err
+	}
+	if ttl <= 0 {
+		ttl = DefaultInvitationTTL
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &AgencyInvitation{
+		ID:        uuid.New().String(),
+		AgencyID:  agencyID,
+		Email:     email,
+		Role:      RoleAgent,
+		TokenHash: HashInvitationToken(token),
+		Status:    InvitationStatusPending,
+		InvitedBy: invitedBy,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, token, nil
+}
+
+// HashInvitationToken returns the stored form of an invitation token
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired reports whether a pending invitation is past its expiry
+func (i *AgencyInvitation) IsExpired(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.After(i.ExpiresAt)
+}
+
+// RefreshStatus marks a pending invitation past its expiry as expired
+func (i *AgencyInvitation) RefreshStatus(now time.Time) {
+	if i.IsExpired(now) {
+		i.Status = InvitationStatusExpired
+		i.UpdatedAt = now
+	}
+}
+
+// Accept records the user who accepted the invitation
+func (i *AgencyInvitation) Accept(userID string, now time.Time) error {
+	i.RefreshStatus(now)
+	if i.Status != InvitationStatusPending {
+		return fmt.Errorf("invitation is %s", i.Status)
+	}
+
+	i.Status = InvitationStatusAccepted
+	i.AcceptedBy = &userID
+	i.AcceptedAt = &now
+	i.UpdatedAt = now
+	return nil
+}
+
+// Revoke cancels a pending invitation
+func (i *AgencyInvitation) Revoke(now time.Time) error {
+	i.RefreshStatus(now)
+	if i.Status != InvitationStatusPending {
+		return fmt.Errorf("invitation is %s", i.Status)
+	}
+
+	i.Status = InvitationStatusRevoked
+	i.UpdatedAt = now
+	return nil
+}
+
+// IsValidInvitationStatus checks if an invitation status is valid
+func IsValidInvitationStatus(status string) bool {
+	switch status {
+	case InvitationStatusPending, InvitationStatusAccepted, InvitationStatusRevoked, InvitationStatusExpired:
+		return true
+	}
+	return false
+}
+
+// generateInvitationToken returns a random URL-safe token
+func generateInvitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAgencyInvitation(t *testing.T) {
+	invitation, token, err := NewAgencyInvitation("agency-1", " Agente@Example.com ", "owner-1", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "agente@example.com", invitation.Email)
+	assert.Equal(t, RoleAgent, invitation.Role)
+	assert.Equal(t, InvitationStatusPending, invitation.Status)
+	assert.Len(t, token, 64)
+	assert.Equal(t, HashInvitationToken(token), invitation.TokenHash)
+	assert.NotEqual(t, token, invitation.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(DefaultInvitationTTL), invitation.ExpiresAt, time.Minute)
+
+	_, _, err = NewAgencyInvitation("agency-1", "not-an-email", "owner-1", time.Hour)
+	assert.Error(t, err)
+	_, _, err = NewAgencyInvitation("", "agente@example.com", "owner-1", time.Hour)
+	assert.Error(t, err)
+}
+
+func TestAgencyInvitation_Lifecycle(t *testing.T) {
+	now := time.Now()
+
+	t.Run("accept pending invitation", func(t *testing.T) {
+		invitation, _, err := NewAgencyInvitation("agency-1", "agente@example.com", "owner-1", time.Hour)
+		require.NoError(t, err)
+
+		require.NoError(t, invitation.Accept("user-1", now))
+		assert.Equal(t, InvitationStatusAccepted, invitation.Status)
+		assert.Equal(t, "user-1", *invitation.AcceptedBy)
+
+		assert.Error(t, invitation.Accept("user-2", now))
+		assert.Error(t, invitation.Revoke(now))
+	})
+
+	t.Run("expired invitation cannot be accepted", func(t *testing.T) {
+		invitation, _, err := NewAgencyInvitation("agency-1", "agente@example.com", "owner-1", time.Hour)
+		require.NoError(t, err)
+
+		later := now.Add(2 * time.Hour)
+		assert.True(t, invitation.IsExpired(later))
+		assert.EqualError(t, invitation.Accept("user-1", later), "invitation is expired")
+		assert.Equal(t, InvitationStatusExpired, invitation.Status)
+	})
+
+	t.Run("revoke pending invitation", func(t *testing.T) {
+		invitation, _, err := NewAgencyInvitation("agency-1", "agente@example.com", "owner-1", time.Hour)
+		require.NoError(t, err)
+
+		require.NoError(t, invitation.Revoke(now))
+		assert.Equal(t, InvitationStatusRevoked, invitation.Status)
+		assert.False(t, invitation.IsExpired(now.Add(2*time.Hour)))
+	})
+}
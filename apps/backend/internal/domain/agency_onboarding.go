@@ -0,0 +1,69 @@
+package domain
+
+import "strings"
+
+// Agency onboarding steps, in the order the guided setup presents them
+const (
+	OnboardingStepLicense       = "license_set"
+	OnboardingStepLogo          = "logo_uploaded"
+	OnboardingStepAgentInvited  = "first_agent_invited"
+	OnboardingStepListing       = "first_listing_published"
+	OnboardingStepPaymentMethod = "payment_method_added"
+)
+
+// OnboardingStep is one step of the agency setup checklist
+type OnboardingStep struct {
+	Key       string `json:"key"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+}
+
+// AgencyActivity is the data of an agency the onboarding checklist is computed from
+type AgencyActivity struct {
+	InvitationsSent   int    `json:"invitations_sent"`
+	Agents            int    `json:"agents"`
+	PublishedListings int    `json:"published_listings"`
+	Plan              string `json:"plan"`
+}
+
+// AgencyOnboarding is the setup checklist of an agency. Progress is the percentage of
+// completed steps and NextStep the first one left, empty once the setup is done.
+type AgencyOnboarding struct {
+	AgencyID       string           `json:"agency_id"`
+	Steps          []OnboardingStep `json:"steps"`
+	CompletedSteps int              `json:"completed_steps"`
+	TotalSteps     int              `json:"total_steps"`
+	Progress       int              `json:"progress"`
+	Completed      bool             `json:"completed"`
+	NextStep       string           `json:"next_step,omitempty"`
+}
+
+// NewAgencyOnboarding computes the checklist of an agency from its profile and activity.
+// Payments are not stored yet, so an agency has a payment method once it is on a paid plan.
+func NewAgencyOnboarding(agency *Agency, activity AgencyActivity) *AgencyOnboarding {
+	plan, ok := DefaultSubscriptionPlans[activity.Plan]
+	paid := ok && plan.MonthlyPrice > 0
+
+	onboarding := &AgencyOnboarding{
+		AgencyID: agency.ID,
+		Steps: []OnboardingStep{
+			{Key: OnboardingStepLicense, Title: "Registrar la licencia", Completed: strings.TrimSpace(agency.LicenseNumber) != ""},
+			{Key: OnboardingStepLogo, Title: "Subir el logo", Completed: agency.LogoURL != nil && *agency.LogoURL != ""},
+			{Key: OnboardingStepAgentInvited, Title: "Invitar al primer agente", Completed: activity.InvitationsSent > 0 || activity.Agents > 0},
+			{Key: OnboardingStepListing, Title: "Publicar la primera propiedad", Completed: activity.PublishedListings > 0},
+			{Key: OnboardingStepPaymentMethod, Title: "Agregar un método de pago", Completed: paid},
+		},
+	}
+
+	for _, step := range onboarding.Steps {
+		if step.Completed {
+			onboarding.CompletedSteps++
+		} else if onboarding.NextStep == "" {
+			onboarding.NextStep = step.Key
+		}
+	}
+	onboarding.TotalSteps = len(onboarding.Steps)
+	onboarding.Progress = onboarding.CompletedSteps * 100 / onboarding.TotalSteps
+	onboarding.Completed = onboarding.CompletedSteps == onboarding.TotalSteps
+	return onboarding
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAgencyOnboarding(t *testing.T) {
+	agency := &Agency{ID: "agency-1", LicenseNumber: "1790012345001"}
+
+	onboarding := NewAgencyOnboarding(agency, AgencyActivity{Plan: PlanFree})
+	assert.Equal(t, 5, onboarding.TotalSteps)
+	assert.Equal(t, 1, onboarding.CompletedSteps)
+	assert.Equal(t, 20, onboarding.Progress)
+	assert.Equal(t, OnboardingStepLogo, onboarding.NextStep)
+	assert.False(t, onboarding.Completed)
+
+	logo := "/uploads/images/logos/agency-1/v1_512.png"
+	agency.LogoURL = &logo
+	onboarding = NewAgencyOnboarding(agency, AgencyActivity{Agents: 1, PublishedListings: 3, Plan: PlanPro})
+	assert.Equal(t, 100, onboarding.Progress)
+	assert.True(t, onboarding.Completed)
+	assert.Empty(t, onboarding.NextStep)
+}
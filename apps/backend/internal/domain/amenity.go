@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Amenity categories
+const (
+	AmenityCategoryUtilities = "utilities"
+	AmenityCategoryOutdoor   = "outdoor"
+	AmenityCategoryBuilding  = "building"
+	AmenityCategoryInterior  = "interior"
+	AmenityCategoryPolicy    = "policy"
+)
+
+// Amenity limits. Details are small structured extras, e.g. {"capacity_liters": 2000}
+// for a cistern or {"kva": 15} for a generator.
+const (
+	MaxPropertyAmenities     = 50
+	MaxAmenityDetailsSize    = 1024
+	MaxAmenityNameLength     = 100
+	MaxAmenitySearchCriteria = 10
+)
+
+// amenityCodePattern matches catalog codes such as "bbq_area"
+var amenityCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,39}$`)
+
+// Amenity is an entry of the admin-managed amenity catalog. The pool, garden, terrace,
+// balcony, security, elevator, air conditioning, garage and furnished flags stay
+// columns of properties; the catalog holds every other amenity.
+type Amenity struct {
+	Code      string    `json:"code"`
+	NameES    string    `json:"name_es"`
+	NameEN    string    `json:"name_en"`
+	Category  string    `json:"category"`
+	Icon      string    `json:"icon"`
+	Active    bool      `json:"active"`
+	SortOrder int       `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Name returns the name of the amenity in a locale, Spanish by default
+func (a *Amenity) Name(locale string) string {
+	if NormalizeLocale(locale) == LocaleEnglish && a.NameEN != "" {
+		return a.NameEN
+	}
+	return a.NameES
+}
+
+// Normalize trims the amenity before validation
+func (a *Amenity) Normalize() {
+	a.Code = NormalizeAmenityCode(a.Code)
+	a.NameES = strings.TrimSpace(a.NameES)
+	a.NameEN = strings.TrimSpace(a.NameEN)
+	a.Category = strings.ToLower(strings.TrimSpace(a.Category))
+	a.Icon = strings.TrimSpace(a.Icon)
+}
+
+// Validate checks the amenity
+func (a *Amenity) Validate() error {
+	if !amenityCodePattern.MatchString(a.Code) {
+		return fmt.Errorf("invalid amenity code: use 2 to 40 lowercase letters, digits or underscores")
+	}
+	if a.NameES == "" || a.NameEN == "" {
+		return fmt.Errorf("invalid amenity: Spanish and English names are required")
+	}
+	if len(a.NameES) > MaxAmenityNameLength || len(a.NameEN) > MaxAmenityNameLength {
+		return fmt.Errorf("invalid amenity: names must be at most %d characters", MaxAmenityNameLength)
+	}
+	if !IsValidAmenityCategory(a.Category) {
+		return fmt.Errorf("invalid amenity category: %s", a.Category)
+	}
+	return nil
+}
+
+// IsValidAmenityCategory verifies if an amenity category is valid
+func IsValidAmenityCategory(category string) bool {
+	switch category {
+	case AmenityCategoryUtilities, AmenityCategoryOutdoor, AmenityCategoryBuilding,
+		AmenityCategoryInterior, AmenityCategoryPolicy:
+		return true
+	}
+	return false
+}
+
+// NormalizeAmenityCode lowercases a code and joins its words with underscores, so
+// "Pet friendly" and "pet-friendly" both become "pet_friendly"
+func NormalizeAmenityCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(code)
+}
+
+// PropertyAmenity is an amenity of a property with its optional details
+type PropertyAmenity struct {
+	Code    string          `json:"code"`
+	Name    string          `json:"name,omitempty"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// ValidatePropertyAmenities normalizes the amenities of a property and checks them
+// against the active catalog. Details must be JSON objects.
+func ValidatePropertyAmenities(amenities []PropertyAmenity, catalog map[string]Amenity) error {
+	if len(amenities) > MaxPropertyAmenities {
+		return fmt.Errorf("invalid amenities: at most %d per property", MaxPropertyAmenities)
+	}
+
+	seen := make(map[string]bool, len(amenities))
+	for i := range amenities {
+		amenity := &amenities[i]
+		amenity.Code = NormalizeAmenityCode(amenity.Code)
+		entry, ok := catalog[amenity.Code]
+		if !ok || !entry.Active {
+			return fmt.Errorf("invalid amenity: %s is not in the catalog", amenity.Code)
+		}
+		if seen[amenity.Code] {
+			return fmt.Errorf("invalid amenities: %s is listed twice", amenity.Code)
+		}
+		seen[amenity.Code] = true
+
+		if len(amenity.Details) == 0 || string(amenity.Details) == "null" {
+			amenity.Details = json.RawMessage("{}")
+			continue
+		}
+		if len(amenity.Details) > MaxAmenityDetailsSize {
+			return fmt.Errorf("invalid amenity details for %s: at most %d bytes", amenity.Code, MaxAmenityDetailsSize)
+		}
+		var details map[string]interface{}
+		if err := json.Unmarshal(amenity.Details, &details); err != nil {
+			return fmt.Errorf("invalid amenity details for %s: must be a JSON object", amenity.Code)
+		}
+	}
+	return nil
+}
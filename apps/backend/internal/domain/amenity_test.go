@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmenity_Validate(t *testing.T) {
+	amenity := &Amenity{Code: " Pet-Friendly ", NameES: "Se aceptan mascotas", NameEN: "Pet friendly", Category: "Policy"}
+	amenity.Normalize()
+	require.NoError(t, amenity.Validate())
+	assert.Equal(t, "pet_friendly", amenity.Code)
+	assert.Equal(t, "Pet friendly", amenity.Name("en-US"))
+	assert.Equal(t, "Se aceptan mascotas", amenity.Name(""))
+
+	amenity.Category = "garden"
+	assert.ErrorContains(t, amenity.Validate(), "invalid amenity category")
+
+	assert.ErrorContains(t, (&Amenity{Code: "x", NameES: "X", NameEN: "X", Category: AmenityCategoryPolicy}).Validate(), "invalid amenity code")
+}
+
+func TestValidatePropertyAmenities(t *testing.T) {
+	catalog := map[string]Amenity{
+		"cistern":   {Code: "cistern", Active: true},
+		"generator": {Code: "generator", Active: true},
+		"sauna":     {Code: "sauna", Active: false},
+	}
+
+	amenities := []PropertyAmenity{
+		{Code: "Cistern", Details: json.RawMessage(`{"capacity_liters": 2000}`)},
+		{Code: "generator"},
+	}
+	require.NoError(t, ValidatePropertyAmenities(amenities, catalog))
+	assert.Equal(t, "cistern", amenities[0].Code)
+	assert.JSONEq(t, `{}`, string(amenities[1].Details))
+
+	assert.ErrorContains(t, ValidatePropertyAmenities([]PropertyAmenity{{Code: "sauna"}}, catalog), "not in the catalog")
+	assert.ErrorContains(t, ValidatePropertyAmenities([]PropertyAmenity{{Code: "cistern"}, {Code: "cistern"}}, catalog), "listed twice")
+	assert.ErrorContains(t, ValidatePropertyAmenities([]PropertyAmenity{{Code: "cistern", Details: json.RawMessage(`[1]`)}}, catalog), "JSON object")
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidAPIKey is returned for keys that do not exist or were revoked
+var ErrInvalidAPIKey = errors.New("invalid or revoked API key")
+
+// APIKeyPrefix starts every public API key, so leaked keys are easy to recognize
+const APIKeyPrefix = "rk_"
+
+// apiKeyPrefixLength is the length of the start of a key kept to identify it
+const apiKeyPrefixLength = 11
+
+// DefaultAPIKeyTiersSpec are the tiers used when none are configured
+const DefaultAPIKeyTiersSpec = "free:60:5000,research:120:20000,partner:600:200000"
+
+// DefaultAPIKeyTier is the tier of keys created without one
+const DefaultAPIKeyTier = "free"
+
+// Exhausted API key quota windows
+const (
+	APIKeyWindowMinute = "minute"
+	APIKeyWindowDay    = "day"
+)
+
+// APIKeyTier is a rate tier of the public API
+type APIKeyTier struct {
+	Name              string `json:"name"`
+	RequestsPerMinute int    `json:"requests_per_minute"`
+	RequestsPerDay    int64  `json:"requests_per_day"`
+}
+
+// APIKey authenticates a consumer of the public read-only API. Only the SHA-256 hash of
+// the key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	ContactEmail string     `json:"contact_email"`
+	AgencyID     *string    `json:"agency_id,omitempty"`
+	Prefix       string     `json:"prefix"`
+	KeyHash      string     `json:"-"`
+	Tier         string     `json:"tier"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NewAPIKey creates an API key and returns it with the key to hand to the consumer
+func NewAPIKey(name, contactEmail, tier, createdBy string) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	contactEmail = strings.ToLower(strings.TrimSpace(contactEmail))
+	if name == "" {
+		return nil, "", fmt.Errorf("invalid API key: name is required")
+	}
+	if err := validateEmail(contactEmail); err != nil {
+		return nil, "", fmt.Errorf("invalid API key: %w", err)
+	}
+	if tier == "" {
+		tier = DefaultAPIKeyTier
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := APIKeyPrefix + hex.EncodeToString(buf)
+
+	return &APIKey{
+		ID:           uuid.New().String(),
+		Name:         name,
+		ContactEmail: contactEmail,
+		Prefix:       key[:apiKeyPrefixLength],
+		KeyHash:      HashAPIKey(key),
+		Tier:         tier,
+		CreatedBy:    createdBy,
+		CreatedAt:    time.Now(),
+	}, key, nil
+}
+
+// HashAPIKey returns the SHA-256 hex digest an API key is stored as
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsActive reports whether the key has not been revoked
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil
+}
+
+// APIKeyQuota is the state of the quotas of a key after a request
+type APIKeyQuota struct {
+	Tier            string    `json:"tier"`
+	MinuteLimit     int       `json:"minute_limit"`
+	MinuteRemaining int       `json:"minute_remaining"`
+	MinuteResetAt   time.Time `json:"minute_reset_at"`
+	DailyLimit      int64     `json:"daily_limit"`
+	DailyRemaining  int64     `json:"daily_remaining"`
+	DailyResetAt    time.Time `json:"daily_reset_at"`
+	// Exceeded is the window whose quota rejected the request, empty when allowed
+	Exceeded string `json:"exceeded,omitempty"`
+}
+
+// Allowed reports whether the request was within the quotas
+func (q APIKeyQuota) Allowed() bool {
+	return q.Exceeded == ""
+}
+
+// RetryAt returns when a rejected request can be retried
+func (q APIKeyQuota) RetryAt() time.Time {
+	if q.Exceeded == APIKeyWindowDay {
+		return q.DailyResetAt
+	}
+	return q.MinuteResetAt
+}
+
+// APIUsageCounts meters the use of the public API: requests served, requests rejected
+// over a quota and bytes sent in the responses served
+type APIUsageCounts struct {
+	Requests         int64 `json:"requests"`
+	RejectedRequests int64 `json:"rejected_requests"`
+	BytesSent        int64 `json:"bytes_sent"`
+}
+
+// Add adds other counts to the counts
+func (c *APIUsageCounts) Add(other APIUsageCounts) {
+	c.Requests += other.Requests
+	c.RejectedRequests += other.RejectedRequests
+	c.BytesSent += other.BytesSent
+}
+
+// APIKeyUsage is the use a key made of the public API on a day
+type APIKeyUsage struct {
+	APIKeyID string    `json:"api_key_id"`
+	Day      time.Time `json:"day"`
+	APIUsageCounts
+}
+
+// ParseAPIKeyTiers parses tiers in the form "name:per_minute:per_day,...",
+// e.g. "free:60:5000,partner:600:200000"
+func ParseAPIKeyTiers(spec string) (map[string]APIKeyTier, error) {
+	tiers := make(map[string]APIKeyTier)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid API key tier %q: expected name:per_minute:per_day", entry)
+		}
+		tier := APIKeyTier{Name: strings.ToLower(strings.TrimSpace(parts[0]))}
+		if tier.Name == "" {
+			return nil, fmt.Errorf("invalid API key tier %q: name is required", entry)
+		}
+		if _, exists := tiers[tier.Name]; exists {
+			return nil, fmt.Errorf("API key tier %s is defined twice", tier.Name)
+		}
+
+		var err error
+		if tier.RequestsPerMinute, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil || tier.RequestsPerMinute <= 0 {
+			return nil, fmt.Errorf("invalid requests per minute for API key tier %s", tier.Name)
+		}
+		if tier.RequestsPerDay, err = strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64); err != nil || tier.RequestsPerDay <= 0 {
+			return nil, fmt.Errorf("invalid requests per day for API key tier %s", tier.Name)
+		}
+
+		tiers[tier.Name] = tier
+	}
+
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("no API key tiers defined")
+	}
+	return tiers, nil
+}
+
+// SortedAPIKeyTiers returns tiers ordered by requests per day
+func SortedAPIKeyTiers(tiers map[string]APIKeyTier) []APIKeyTier {
+	sorted := make([]APIKeyTier, 0, len(tiers))
+	for _, tier := range tiers {
+		sorted = append(sorted, tier)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].RequestsPerDay != sorted[j].RequestsPerDay {
+			return sorted[i].RequestsPerDay < sorted[j].RequestsPerDay
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
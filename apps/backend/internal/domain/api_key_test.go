@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, plaintext, err := NewAPIKey(" Portal Inmobiliario ", "API@Portal.ec", "", "admin-1")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(plaintext, APIKeyPrefix))
+	assert.Equal(t, plaintext[:len(key.Prefix)], key.Prefix)
+	assert.Equal(t, HashAPIKey(plaintext), key.KeyHash)
+	assert.NotContains(t, key.KeyHash, plaintext)
+	assert.Equal(t, "Portal Inmobiliario", key.Name)
+	assert.Equal(t, "api@portal.ec", key.ContactEmail)
+	assert.Equal(t, DefaultAPIKeyTier, key.Tier)
+	assert.True(t, key.IsActive())
+
+	_, other, err := NewAPIKey("Portal", "api@portal.ec", "partner", "admin-1")
+	require.NoError(t, err)
+	assert.NotEqual(t, plaintext, other)
+
+	_, _, err = NewAPIKey("", "api@portal.ec", "", "admin-1")
+	assert.ErrorContains(t, err, "name is required")
+	_, _, err = NewAPIKey("Portal", "not-an-email", "", "admin-1")
+	assert.ErrorContains(t, err, "invalid API key")
+}
+
+func TestParseAPIKeyTiers(t *testing.T) {
+	tiers, err := ParseAPIKeyTiers(DefaultAPIKeyTiersSpec)
+	require.NoError(t, err)
+	assert.Equal(t, APIKeyTier{Name: "free", RequestsPerMinute: 60, RequestsPerDay: 5000}, tiers["free"])
+
+	sorted := SortedAPIKeyTiers(tiers)
+	require.Len(t, sorted, 3)
+	assert.Equal(t, []string{"free", "research", "partner"}, []string{sorted[0].Name, sorted[1].Name, sorted[2].Name})
+
+	for _, spec := range []string{"", "free:60", "free:0:100", "free:60:-1", "free:60:100,FREE:10:10", ":60:100"} {
+		_, err := ParseAPIKeyTiers(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// APIUsageReport meters the use of the public API over a range of UTC days, inclusive,
+// per consumer and per day, for billing and abuse detection
+type APIUsageReport struct {
+	From      time.Time          `json:"from"`
+	To        time.Time          `json:"to"`
+	AgencyID  string             `json:"agency_id,omitempty"`
+	Totals    APIUsageCounts     `json:"totals"`
+	Consumers []APIConsumerUsage `json:"consumers"`
+	Days      []APIDailyUsage    `json:"days"`
+}
+
+// APIConsumerUsage is the use a key made of the public API over the range of a report.
+// QuotaExhaustedDays counts the days the key used its whole daily quota; rejected
+// requests on other days went over the per-minute limit.
+type APIConsumerUsage struct {
+	APIKeyID           string  `json:"api_key_id"`
+	Name               string  `json:"name"`
+	Prefix             string  `json:"prefix"`
+	AgencyID           *string `json:"agency_id,omitempty"`
+	Tier               string  `json:"tier"`
+	Revoked            bool    `json:"revoked"`
+	DailyLimit         int64   `json:"daily_limit"`
+	PeakDayRequests    int64   `json:"peak_day_requests"`
+	QuotaExhaustedDays int     `json:"quota_exhausted_days"`
+	APIUsageCounts
+}
+
+// APIDailyUsage is the use of the public API by every consumer of a report on a day
+type APIDailyUsage struct {
+	Day time.Time `json:"day"`
+	APIUsageCounts
+}
+
+// NewAPIUsageReport aggregates the daily usage of keys into a report. Consumers are the
+// active keys and the revoked keys used in the range, heaviest first; usage of keys not
+// given is left out.
+func NewAPIUsageReport(from, to time.Time, keys []APIKey, usage []APIKeyUsage, tiers map[string]APIKeyTier) *APIUsageReport {
+	report := &APIUsageReport{
+		From:      from,
+		To:        to,
+		Consumers: []APIConsumerUsage{},
+		Days:      []APIDailyUsage{},
+	}
+
+	consumers := make(map[string]*APIConsumerUsage, len(keys))
+	for _, key := range keys {
+		consumers[key.ID] = &APIConsumerUsage{
+			APIKeyID:   key.ID,
+			Name:       key.Name,
+			Prefix:     key.Prefix,
+			AgencyID:   key.AgencyID,
+			Tier:       key.Tier,
+			Revoked:    !key.IsActive(),
+			DailyLimit: tiers[key.Tier].RequestsPerDay,
+		}
+	}
+
+	days := make(map[time.Time]*APIDailyUsage)
+	for _, day := range usage {
+		consumer, ok := consumers[day.APIKeyID]
+		if !ok {
+			continue
+		}
+		consumer.APIUsageCounts.Add(day.APIUsageCounts)
+		if day.Requests > consumer.PeakDayRequests {
+			consumer.PeakDayRequests = day.Requests
+		}
+		if consumer.DailyLimit > 0 && day.Requests >= consumer.DailyLimit {
+			consumer.QuotaExhaustedDays++
+		}
+
+		total, ok := days[day.Day]
+		if !ok {
+			total = &APIDailyUsage{Day: day.Day}
+			days[day.Day] = total
+		}
+		total.APIUsageCounts.Add(day.APIUsageCounts)
+		report.Totals.Add(day.APIUsageCounts)
+	}
+
+	for _, consumer := range consumers {
+		if !consumer.Revoked || consumer.APIUsageCounts != (APIUsageCounts{}) {
+			report.Consumers = append(report.Consumers, *consumer)
+		}
+	}
+	sort.Slice(report.Consumers, func(i, j int) bool {
+		a, b := report.Consumers[i], report.Consumers[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		if a.BytesSent != b.BytesSent {
+			return a.BytesSent > b.BytesSent
+		}
+		return a.Name < b.Name
+	})
+
+	for _, day := range days {
+		report.Days = append(report.Days, *day)
+	}
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Day.Before(report.Days[j].Day)
+	})
+
+	return report
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIUsageReport(t *testing.T) {
+	tiers, err := ParseAPIKeyTiers("free:60:100,partner:600:1000")
+	require.NoError(t, err)
+
+	agencyID := "agency-1"
+	revokedAt := time.Date(2025, 9, 5, 0, 0, 0, 0, time.UTC)
+	keys := []APIKey{
+		{ID: "key-portal", Name: "Portal", Prefix: "rk_portal", AgencyID: &agencyID, Tier: "partner"},
+		{ID: "key-uni", Name: "Universidad", Prefix: "rk_uni", Tier: "free"},
+		{ID: "key-idle", Name: "Idle", Prefix: "rk_idle", Tier: "free"},
+		{ID: "key-old", Name: "Old", Prefix: "rk_old", Tier: "free", RevokedAt: &revokedAt},
+	}
+	day1 := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	usage := []APIKeyUsage{
+		{APIKeyID: "key-uni", Day: day2, APIUsageCounts: APIUsageCounts{Requests: 100, RejectedRequests: 40, BytesSent: 5000}},
+		{APIKeyID: "key-portal", Day: day1, APIUsageCounts: APIUsageCounts{Requests: 300, BytesSent: 90000}},
+		{APIKeyID: "key-uni", Day: day1, APIUsageCounts: APIUsageCounts{Requests: 20, RejectedRequests: 2, BytesSent: 1000}},
+		{APIKeyID: "key-unknown", Day: day1, APIUsageCounts: APIUsageCounts{Requests: 7}},
+	}
+
+	report := NewAPIUsageReport(day1, day2, keys, usage, tiers)
+
+	assert.Equal(t, APIUsageCounts{Requests: 420, RejectedRequests: 42, BytesSent: 96000}, report.Totals)
+	require.Len(t, report.Days, 2)
+	assert.Equal(t, day1, report.Days[0].Day)
+	assert.Equal(t, int64(320), report.Days[0].Requests)
+	assert.Equal(t, int64(100), report.Days[1].Requests)
+
+	require.Len(t, report.Consumers, 3, "revoked keys without usage are left out")
+	portal, uni, idle := report.Consumers[0], report.Consumers[1], report.Consumers[2]
+	assert.Equal(t, "key-portal", portal.APIKeyID)
+	assert.Equal(t, int64(1000), portal.DailyLimit)
+	assert.Equal(t, 0, portal.QuotaExhaustedDays)
+	assert.Equal(t, "agency-1", *portal.AgencyID)
+
+	assert.Equal(t, "key-uni", uni.APIKeyID)
+	assert.Equal(t, int64(120), uni.Requests)
+	assert.Equal(t, int64(42), uni.RejectedRequests)
+	assert.Equal(t, int64(100), uni.PeakDayRequests)
+	assert.Equal(t, 1, uni.QuotaExhaustedDays)
+
+	assert.Equal(t, "key-idle", idle.APIKeyID)
+	assert.Equal(t, APIUsageCounts{}, idle.APIUsageCounts)
+}
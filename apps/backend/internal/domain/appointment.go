@@ -0,0 +1,318 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Appointment kinds: a visit shows a property to a client, an open house shows it to
+// anyone who comes
+const (
+	AppointmentVisit     = "visit"
+	AppointmentOpenHouse = "open_house"
+)
+
+// Appointment statuses. Cancelled appointments stay in the calendar feed so subscribed
+// calendars remove them.
+const (
+	AppointmentScheduled = "scheduled"
+	AppointmentCancelled = "cancelled"
+)
+
+// Appointment time zones. Ecuador does not observe daylight saving time; the Galápagos
+// are an hour behind the mainland.
+const (
+	TimeZoneEcuador   = "America/Guayaquil"
+	TimeZoneGalapagos = "Pacific/Galapagos"
+)
+
+// Appointment limits
+const (
+	MaxAppointmentTitleLength = 150
+	MaxAppointmentNotesLength = 2000
+	MaxAppointmentDuration    = 12 * time.Hour
+	// CalendarFeedTokenPrefix marks calendar feed tokens, e.g. in leaked URLs
+	CalendarFeedTokenPrefix = "cal_"
+)
+
+// appointmentTimeZoneOffsets are the UTC offsets of the appointment time zones, used
+// when the system has no time zone database and to describe them in calendar feeds
+var appointmentTimeZoneOffsets = map[string]int{
+	TimeZoneEcuador:   -5 * 60 * 60,
+	TimeZoneGalapagos: -6 * 60 * 60,
+}
+
+// appointmentLayouts are the accepted formats of appointment times without an offset,
+// read as local times of the property
+var appointmentLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// AppointmentTimeZone returns the time zone of appointments at a property in a province
+func AppointmentTimeZone(province string) string {
+	if province == "Galápagos" || strings.EqualFold(province, "Galapagos") {
+		return TimeZoneGalapagos
+	}
+	return TimeZoneEcuador
+}
+
+// AppointmentLocation returns the location of an appointment time zone, falling back to
+// its fixed offset when the time zone database is not available
+func AppointmentLocation(timeZone string) *time.Location {
+	if location, err := time.LoadLocation(timeZone); err == nil {
+		return location
+	}
+	offset, ok := appointmentTimeZoneOffsets[timeZone]
+	if !ok {
+		timeZone, offset = TimeZoneEcuador, appointmentTimeZoneOffsets[TimeZoneEcuador]
+	}
+	return time.FixedZone(timeZone, offset)
+}
+
+// ParseAppointmentTime parses the time of an appointment, either with an offset such as
+// 2025-09-20T10:00:00-05:00 or as a local time of the property such as 2025-09-20T10:00
+func ParseAppointmentTime(value string, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range appointmentLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid appointment time %q: use 2006-01-02T15:04 or RFC 3339", value)
+}
+
+// Appointment is a visit or open house on the agenda of an agent
+type Appointment struct {
+	ID         string    `json:"id"`
+	AgentID    string    `json:"agent_id"`
+	PropertyID string    `json:"property_id"`
+	Kind       string    `json:"kind"`
+	Title      string    `json:"title"`
+	Location   string    `json:"location,omitempty"`
+	Notes      string    `json:"notes,omitempty"`
+	ClientName string    `json:"client_name,omitempty"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	TimeZone   string    `json:"time_zone"`
+	Status     string    `json:"status"`
+	Sequence   int       `json:"sequence"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NewAppointment schedules a visit or open house of a property on the agenda of an
+// agent, in the time zone of the property. Without a title it is named after the
+// property.
+func NewAppointment(agentID string, property *Property, kind, title, notes, clientName string, startsAt, endsAt time.Time, createdBy string, now time.Time) (*Appointment, error) {
+	appointment := &Appointment{
+		ID:         uuid.New().String(),
+		AgentID:    agentID,
+		PropertyID: property.ID,
+		Kind:       strings.ToLower(strings.TrimSpace(kind)),
+		Title:      strings.TrimSpace(title),
+		Location:   appointmentLocation(property),
+		Notes:      strings.TrimSpace(notes),
+		ClientName: strings.TrimSpace(clientName),
+		TimeZone:   AppointmentTimeZone(property.Province),
+		Status:     AppointmentScheduled,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	location := AppointmentLocation(appointment.TimeZone)
+	appointment.StartsAt = startsAt.In(location)
+	appointment.EndsAt = endsAt.In(location)
+
+	switch appointment.Kind {
+	case AppointmentVisit:
+		if appointment.Title == "" {
+			appointment.Title = "Visita: " + property.Title
+		}
+	case AppointmentOpenHouse:
+		if appointment.Title == "" {
+			appointment.Title = "Casa abierta: " + property.Title
+		}
+	default:
+		return nil, fmt.Errorf("invalid appointment kind: must be visit or open_house")
+	}
+	if utf8.RuneCountInString(appointment.Title) > MaxAppointmentTitleLength {
+		appointment.Title = strings.TrimSpace(string([]rune(appointment.Title)[:MaxAppointmentTitleLength-1])) + "…"
+	}
+	if utf8.RuneCountInString(appointment.Notes) > MaxAppointmentNotesLength {
+		return nil, fmt.Errorf("invalid appointment: notes must be at most %d characters", MaxAppointmentNotesLength)
+	}
+	if !appointment.EndsAt.After(appointment.StartsAt) {
+		return nil, fmt.Errorf("invalid appointment: must end after it starts")
+	}
+	if appointment.EndsAt.Sub(appointment.StartsAt) > MaxAppointmentDuration {
+		return nil, fmt.Errorf("invalid appointment: must last at most %s", MaxAppointmentDuration)
+	}
+	if appointment.StartsAt.Before(now) {
+		return nil, fmt.Errorf("invalid appointment: cannot be scheduled in the past")
+	}
+	return appointment, nil
+}
+
+// appointmentLocation returns the address of a property as calendars show it
+func appointmentLocation(property *Property) string {
+	var parts []string
+	if property.Address != nil && strings.TrimSpace(*property.Address) != "" {
+		parts = append(parts, strings.TrimSpace(*property.Address))
+	}
+	if property.Sector != nil && strings.TrimSpace(*property.Sector) != "" {
+		parts = append(parts, strings.TrimSpace(*property.Sector))
+	}
+	parts = append(parts, property.City, property.Province, "Ecuador")
+	return strings.Join(parts, ", ")
+}
+
+// Cancel cancels the appointment. Calendars see the change by its higher sequence.
+func (a *Appointment) Cancel(now time.Time) error {
+	if a.Status == AppointmentCancelled {
+		return fmt.Errorf("invalid appointment: already cancelled")
+	}
+	a.Status = AppointmentCancelled
+	a.Sequence++
+	a.UpdatedAt = now
+	return nil
+}
+
+// NewCalendarFeedToken generates the secret token of a calendar feed and returns it
+// with the hash it is stored as
+func NewCalendarFeedToken() (string, string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := CalendarFeedTokenPrefix + hex.EncodeToString(buf)
+	return token, HashAPIKey(token), nil
+}
+
+// AgentCalendar is the agenda of an agent as a calendar feed
+type AgentCalendar struct {
+	AgentID      string
+	Name         string
+	Appointments []Appointment
+}
+
+// RenderICalendar renders a calendar as an iCalendar (RFC 5545) document, with the
+// events in the time zones of their properties
+func RenderICalendar(calendar *AgentCalendar, now time.Time) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) {
+		writeICalLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Realty Core//Agenda de agentes//ES")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICalText(calendar.Name))
+	line("X-WR-TIMEZONE", TimeZoneEcuador)
+
+	timeZones := map[string]bool{TimeZoneEcuador: true}
+	for _, appointment := range calendar.Appointments {
+		timeZones[appointment.TimeZone] = true
+	}
+	names := make([]string, 0, len(timeZones))
+	for name := range timeZones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		offset := icalOffset(name)
+		line("BEGIN", "VTIMEZONE")
+		line("TZID", name)
+		line("BEGIN", "STANDARD")
+		line("DTSTART", "19700101T000000")
+		line("TZOFFSETFROM", offset)
+		line("TZOFFSETTO", offset)
+		line("TZNAME", offset[:3])
+		line("END", "STANDARD")
+		line("END", "VTIMEZONE")
+	}
+
+	const localLayout = "20060102T150405"
+	const utcLayout = "20060102T150405Z"
+	for _, appointment := range calendar.Appointments {
+		location := AppointmentLocation(appointment.TimeZone)
+		status := "CONFIRMED"
+		if appointment.Status == AppointmentCancelled {
+			status = "CANCELLED"
+		}
+		description := appointment.Notes
+		if appointment.ClientName != "" {
+			description = strings.TrimSpace("Cliente: " + appointment.ClientName + "\n" + description)
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", appointment.ID+"@realty-core")
+		line("DTSTAMP", now.UTC().Format(utcLayout))
+		line("DTSTART;TZID="+appointment.TimeZone, appointment.StartsAt.In(location).Format(localLayout))
+		line("DTEND;TZID="+appointment.TimeZone, appointment.EndsAt.In(location).Format(localLayout))
+		line("SUMMARY", escapeICalText(appointment.Title))
+		if appointment.Location != "" {
+			line("LOCATION", escapeICalText(appointment.Location))
+		}
+		if description != "" {
+			line("DESCRIPTION", escapeICalText(description))
+		}
+		if appointment.Kind == AppointmentOpenHouse {
+			line("CATEGORIES", "CASA ABIERTA")
+		} else {
+			line("CATEGORIES", "VISITA")
+		}
+		line("STATUS", status)
+		line("SEQUENCE", fmt.Sprintf("%d", appointment.Sequence))
+		line("CREATED", appointment.CreatedAt.UTC().Format(utcLayout))
+		line("LAST-MODIFIED", appointment.UpdatedAt.UTC().Format(utcLayout))
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+// icalOffset returns the UTC offset of a time zone as iCalendar writes it, e.g. -0500
+func icalOffset(timeZone string) string {
+	offset, ok := appointmentTimeZoneOffsets[timeZone]
+	if !ok {
+		_, offset = time.Now().In(AppointmentLocation(timeZone)).Zone()
+	}
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// escapeICalText escapes a TEXT value of an iCalendar property
+func escapeICalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// writeICalLine writes a content line, folded at 75 octets without splitting characters
+func writeICalLine(b *bytes.Buffer, line string) {
+	const maxOctets = 75
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > maxOctets {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppointmentTimeZone(t *testing.T) {
+	assert.Equal(t, TimeZoneGalapagos, AppointmentTimeZone("Galápagos"))
+	assert.Equal(t, TimeZoneEcuador, AppointmentTimeZone("Pichincha"))
+
+	galapagos := time.Date(2025, 9, 20, 10, 0, 0, 0, AppointmentLocation(TimeZoneGalapagos))
+	assert.Equal(t, 16, galapagos.UTC().Hour())
+	mainland := time.Date(2025, 9, 20, 10, 0, 0, 0, AppointmentLocation(TimeZoneEcuador))
+	assert.Equal(t, 15, mainland.UTC().Hour())
+}
+
+func TestParseAppointmentTime(t *testing.T) {
+	location := AppointmentLocation(TimeZoneGalapagos)
+
+	local, err := ParseAppointmentTime("2025-09-20T10:00", location)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 20, 16, 0, 0, 0, time.UTC), local.UTC())
+
+	withOffset, err := ParseAppointmentTime("2025-09-20T10:00:00-05:00", location)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 20, 15, 0, 0, 0, time.UTC), withOffset.UTC())
+
+	_, err = ParseAppointmentTime("20/09/2025", location)
+	assert.ErrorContains(t, err, "invalid appointment time")
+}
+
+func TestNewAppointment(t *testing.T) {
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	property := NewProperty("Casa frente al mar", "Casa en Puerto Ayora", "Galápagos", "Puerto Ayora", "house", 350000, "owner-1")
+	start := time.Date(2025, 9, 20, 15, 0, 0, 0, time.UTC)
+
+	appointment, err := NewAppointment("agent-1", property, "open_house", "", "", "", start, start.Add(2*time.Hour), "agent-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "Casa abierta: Casa frente al mar", appointment.Title)
+	assert.Equal(t, TimeZoneGalapagos, appointment.TimeZone)
+	assert.Equal(t, 9, appointment.StartsAt.Hour(), "times are kept in the property's time zone")
+	assert.Equal(t, "Puerto Ayora, Galápagos, Ecuador", appointment.Location)
+
+	_, err = NewAppointment("agent-1", property, "party", "", "", "", start, start.Add(time.Hour), "agent-1", now)
+	assert.ErrorContains(t, err, "invalid appointment kind")
+	_, err = NewAppointment("agent-1", property, "visit", "", "", "", start, start, "agent-1", now)
+	assert.ErrorContains(t, err, "must end after it starts")
+	_, err = NewAppointment("agent-1", property, "visit", "", "", "", now.Add(-time.Hour), now, "agent-1", now)
+	assert.ErrorContains(t, err, "in the past")
+
+	require.NoError(t, appointment.Cancel(now))
+	assert.Equal(t, 1, appointment.Sequence)
+	assert.Error(t, appointment.Cancel(now))
+}
+
+func TestRenderICalendar(t *testing.T) {
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	quito := NewProperty("Departamento", "Departamento en La Carolina", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	galapagos := NewProperty("Casa", "Casa en Puerto Ayora", "Galápagos", "Puerto Ayora", "house", 350000, "owner-1")
+	start := time.Date(2025, 9, 20, 15, 0, 0, 0, time.UTC)
+
+	visit, err := NewAppointment("agent-1", quito, "visit", "", "Traer llaves; portón, bodega", "Ana Torres", start, start.Add(time.Hour), "agent-1", now)
+	require.NoError(t, err)
+	openHouse, err := NewAppointment("agent-1", galapagos, "open_house", strings.Repeat("Casa abierta en la isla ", 5), "", "", start, start.Add(3*time.Hour), "agent-1", now)
+	require.NoError(t, err)
+	require.NoError(t, openHouse.Cancel(now))
+
+	body := string(RenderICalendar(&AgentCalendar{Name: "Agenda de María", Appointments: []Appointment{*visit, *openHouse}}, now))
+
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	assert.Contains(t, body, "TZID:America/Guayaquil\r\n")
+	assert.Contains(t, body, "TZID:Pacific/Galapagos\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nTZOFFSETFROM:-0600\r\n")
+	assert.Contains(t, body, "DTSTART;TZID=America/Guayaquil:20250920T100000\r\n")
+	assert.Contains(t, body, "DTSTART;TZID=Pacific/Galapagos:20250920T090000\r\n")
+	assert.Contains(t, body, `DESCRIPTION:Cliente: Ana Torres\nTraer llaves\; portón\, bodega`)
+	assert.Contains(t, body, "STATUS:CANCELLED\r\nSEQUENCE:1\r\n")
+
+	for _, line := range strings.Split(body, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Contains(t, body, "\r\n ", "long lines are folded")
+}
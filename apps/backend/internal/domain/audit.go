@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit entity types
+const (
+	AuditEntityProperty = "property"
+	AuditEntityAgency   = "agency"
+	AuditEntityUser     = "user"
+)
+
+// Audit actions
+const (
+	AuditActionAgentAssigned        = "property.agent_assigned"
+	AuditActionAgentUnassigned      = "property.agent_unassigned"
+	AuditActionPropertyReverted     = "property.reverted"
+	AuditActionListingsTransferred  = "agency.listings_transferred"
+	AuditActionPlanChanged          = "agency.plan_changed"
+	AuditActionEmailChangeRequested = "user.email_change_requested"
+	AuditActionEmailChanged         = "user.email_changed"
+	AuditActionEmailChangeCancelled = "user.email_change_cancelled"
+	AuditActionPhoneVerified        = "user.phone_verified"
+)
+
+// AuditEntry records who changed what
+type AuditEntry struct {
+	ID         string                 `json:"id"`
+	ActorID    string                 `json:"actor_id"`
+	ActorRole  UserRole               `json:"actor_role"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	AgencyID   *string                `json:"agency_id,omitempty"`
+	Details    map[string]interface{} `json:"details"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// NewAuditEntry creates an audit entry for an action of an actor
+func NewAuditEntry(actor Actor, action, entityType, entityID string, agencyID *string, details map[string]interface{}) *AuditEntry {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+
+	return &AuditEntry{
+		ID:         uuid.New().String(),
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		AgencyID:   agencyID,
+		Details:    details,
+		CreatedAt:  time.Now(),
+	}
+}
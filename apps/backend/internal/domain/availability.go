@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of unavailable date ranges: blocked by the listing's managers or booked by a
+// rental deal
+const (
+	AvailabilityBlocked = "blocked"
+	AvailabilityBooked  = "booked"
+)
+
+// Availability calendar limits
+const (
+	DefaultMinStayNights = 1
+	// MaxMinStayNights allows long-term rentals to require a year-long lease
+	MaxMinStayNights = 365
+	// MaxStayNights bounds stays and blocks to a three-year lease
+	MaxStayNights = 3 * 366
+	// MaxCalendarDays bounds the range of a calendar query
+	MaxCalendarDays    = 366
+	MaxBlockNoteLength = 500
+)
+
+// CalendarDateLayout is the format of calendar dates in requests, e.g. 2025-09-15
+const CalendarDateLayout = "2006-01-02"
+
+// CalendarDate truncates a time to its UTC day
+func CalendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseCalendarDate parses a date such as 2025-09-15
+func ParseCalendarDate(value string) (time.Time, error) {
+	date, err := time.Parse(CalendarDateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", value)
+	}
+	return date, nil
+}
+
+// StayPeriod is a range of nights from Start (check-in) to End (check-out, exclusive)
+type StayPeriod struct {
+	Start time.Time `json:"start_date"`
+	End   time.Time `json:"end_date"`
+}
+
+// NewStayPeriod creates a period of whole days between two dates
+func NewStayPeriod(start, end time.Time) (StayPeriod, error) {
+	period := StayPeriod{Start: CalendarDate(start), End: CalendarDate(end)}
+	if !period.End.After(period.Start) {
+		return StayPeriod{}, fmt.Errorf("invalid dates: end date must be after start date")
+	}
+	if period.Nights() > MaxStayNights {
+		return StayPeriod{}, fmt.Errorf("invalid dates: periods can span at most %d nights", MaxStayNights)
+	}
+	return period, nil
+}
+
+// Nights returns the number of nights of the period
+func (p StayPeriod) Nights() int {
+	return int(p.End.Sub(p.Start).Hours() / 24)
+}
+
+// Overlaps reports whether two periods share a night
+func (p StayPeriod) Overlaps(other StayPeriod) bool {
+	return p.Start.Before(other.End) && other.Start.Before(p.End)
+}
+
+// AvailabilitySettings holds the booking rules of a rental property
+type AvailabilitySettings struct {
+	PropertyID    string     `json:"property_id"`
+	MinStayNights int        `json:"min_stay_nights"`
+	UpdatedBy     string     `json:"updated_by,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultAvailabilitySettings returns the settings of a property that never set any
+func DefaultAvailabilitySettings(propertyID string) *AvailabilitySettings {
+	return &AvailabilitySettings{PropertyID: propertyID, MinStayNights: DefaultMinStayNights}
+}
+
+// Validate checks the settings
+func (s *AvailabilitySettings) Validate() error {
+	if s.MinStayNights < 1 || s.MinStayNights > MaxMinStayNights {
+		return fmt.Errorf("invalid minimum stay: must be between 1 and %d nights", MaxMinStayNights)
+	}
+	return nil
+}
+
+// AvailabilityBlock is a range of dates a property is not available
+type AvailabilityBlock struct {
+	ID         string `json:"id"`
+	PropertyID string `json:"property_id"`
+	StayPeriod
+	Kind      string    `json:"kind"`
+	DealID    *string   `json:"deal_id,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAvailabilityBlock creates a range of dates blocked by a listing's manager
+func NewAvailabilityBlock(propertyID string, period StayPeriod, note, createdBy string, now time.Time) (*AvailabilityBlock, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxBlockNoteLength {
+		return nil, fmt.Errorf("invalid note: must be at most %d characters", MaxBlockNoteLength)
+	}
+	return &AvailabilityBlock{
+		ID:         uuid.New().String(),
+		PropertyID: propertyID,
+		StayPeriod: period,
+		Kind:       AvailabilityBlocked,
+		Note:       note,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}, nil
+}
+
+// NewBooking creates the range of dates booked by a rental deal
+func NewBooking(propertyID, dealID string, period StayPeriod, createdBy string, now time.Time) *AvailabilityBlock {
+	return &AvailabilityBlock{
+		ID:         uuid.New().String(),
+		PropertyID: propertyID,
+		StayPeriod: period,
+		Kind:       AvailabilityBooked,
+		DealID:     &dealID,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}
+}
+
+// Public returns the block without the details only the listing's managers see
+func (b AvailabilityBlock) Public() AvailabilityBlock {
+	return AvailabilityBlock{ID: b.ID, PropertyID: b.PropertyID, StayPeriod: b.StayPeriod, Kind: b.Kind, CreatedAt: b.CreatedAt}
+}
+
+// CheckStay checks that a stay respects the minimum stay and overlaps no unavailable dates
+func CheckStay(period StayPeriod, settings *AvailabilitySettings, blocks []AvailabilityBlock) error {
+	if period.Nights() < settings.MinStayNights {
+		return fmt.Errorf("dates not available: the minimum stay is %d nights", settings.MinStayNights)
+	}
+	return CheckConflicts(period, blocks)
+}
+
+// CheckConflicts checks that a period overlaps none of the blocks
+func CheckConflicts(period StayPeriod, blocks []AvailabilityBlock) error {
+	for _, block := range blocks {
+		if period.Overlaps(block.StayPeriod) {
+			return fmt.Errorf("dates not available: %s from %s to %s",
+				block.Kind, block.Start.Format(CalendarDateLayout), block.End.Format(CalendarDateLayout))
+		}
+	}
+	return nil
+}
+
+// AvailabilityCalendar is the availability of a property over a range of dates
+type AvailabilityCalendar struct {
+	PropertyID    string              `json:"property_id"`
+	From          time.Time           `json:"from"`
+	To            time.Time           `json:"to"`
+	MinStayNights int                 `json:"min_stay_nights"`
+	Blocks        []AvailabilityBlock `json:"blocks"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(value string) time.Time {
+	date, _ := ParseCalendarDate(value)
+	return date
+}
+
+func TestNewStayPeriod(t *testing.T) {
+	period, err := NewStayPeriod(time.Date(2025, 10, 1, 15, 30, 0, 0, time.UTC), day("2025-10-05"))
+	require.NoError(t, err)
+	assert.Equal(t, day("2025-10-01"), period.Start, "times are truncated to their day")
+	assert.Equal(t, 4, period.Nights())
+
+	_, err = NewStayPeriod(day("2025-10-05"), day("2025-10-05"))
+	assert.ErrorContains(t, err, "end date must be after start date")
+
+	_, err = NewStayPeriod(day("2025-01-01"), day("2029-01-01"))
+	assert.ErrorContains(t, err, "at most")
+
+	_, err = ParseCalendarDate("05/10/2025")
+	assert.ErrorContains(t, err, "use YYYY-MM-DD")
+}
+
+func TestCheckStay(t *testing.T) {
+	holidays, err := NewStayPeriod(day("2025-12-20"), day("2026-01-03"))
+	require.NoError(t, err)
+	block, err := NewAvailabilityBlock("prop-1", holidays, "Owner holidays", "owner-1", time.Now())
+	require.NoError(t, err)
+	blocks := []AvailabilityBlock{*block}
+	settings := &AvailabilitySettings{PropertyID: "prop-1", MinStayNights: 3}
+
+	tests := []struct {
+		name       string
+		start, end string
+		err        string
+	}{
+		{"free dates", "2025-12-10", "2025-12-15", ""},
+		{"check-out on the first blocked day", "2025-12-15", "2025-12-20", ""},
+		{"check-in on the last blocked check-out", "2026-01-03", "2026-01-07", ""},
+		{"overlapping the block", "2025-12-18", "2025-12-22", "dates not available: blocked from 2025-12-20 to 2026-01-03"},
+		{"inside the block", "2025-12-24", "2025-12-28", "dates not available"},
+		{"shorter than the minimum stay", "2025-12-10", "2025-12-12", "minimum stay is 3 nights"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, err := NewStayPeriod(day(tt.start), day(tt.end))
+			require.NoError(t, err)
+			err = CheckStay(period, settings, blocks)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+
+	public := block.Public()
+	assert.Empty(t, public.Note)
+	assert.Empty(t, public.CreatedBy)
+	assert.Equal(t, holidays, public.StayPeriod)
+}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Backup verification results
+const (
+	BackupVerificationPassed = "passed"
+	BackupVerificationFailed = "failed"
+)
+
+// Backup health, as reported to monitoring
+const (
+	// BackupStatusOK means the latest verification passed within the max age
+	BackupStatusOK = "ok"
+	// BackupStatusFailing means the latest verification failed
+	BackupStatusFailing = "failing"
+	// BackupStatusStale means no verification passed within the max age, e.g. the
+	// verification stopped running or the dumps stopped being taken
+	BackupStatusStale = "stale"
+	// BackupStatusUnknown means no backup was ever verified
+	BackupStatusUnknown = "unknown"
+)
+
+// Kinds of backup checks
+const (
+	BackupCheckRestore     = "restore"
+	BackupCheckTables      = "tables"
+	BackupCheckRowCount    = "row_count"
+	BackupCheckForeignKey  = "foreign_key"
+	BackupCheckSampleQuery = "sample_query"
+)
+
+// Backup verification defaults
+const (
+	// DefaultBackupMaxAge is how long a passed verification vouches for the backups;
+	// daily dumps are verified daily, with a couple of hours of slack
+	DefaultBackupMaxAge = 26 * time.Hour
+	// DefaultBackupMinRowRatio is the share of the live rows of a table a dump must hold.
+	// Dumps are older than the live data, so they may hold fewer rows, but not far fewer.
+	DefaultBackupMinRowRatio = 0.5
+	// BackupVerifySchemaPrefix names the temporary schemas dumps are restored into
+	BackupVerifySchemaPrefix = "backup_verify_"
+)
+
+// BackupDump is a database dump file
+type BackupDump struct {
+	Path       string    `json:"-"`
+	Name       string    `json:"name"`
+	Format     string    `json:"format"` // plain, gzip or custom
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// BackupCheck is the result of one consistency check of a restored dump
+type BackupCheck struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// BackupTableCount is the row count of a table in the restored dump and in the live
+// database
+type BackupTableCount struct {
+	Table    string `json:"table"`
+	Restored int64  `json:"restored"`
+	Live     int64  `json:"live"`
+}
+
+// BackupForeignKey is a foreign key of a restored table
+type BackupForeignKey struct {
+	Name          string   `json:"name"`
+	Table         string   `json:"table"`
+	Columns       []string `json:"columns"`
+	ParentTable   string   `json:"parent_table"`
+	ParentColumns []string `json:"parent_columns"`
+}
+
+// BackupVerification is a run restoring the latest dump into a temporary schema and
+// checking the restored data
+type BackupVerification struct {
+	ID             string        `json:"id"`
+	Dump           BackupDump    `json:"dump"`
+	Schema         string        `json:"schema"`
+	Status         string        `json:"status"`
+	StartedAt      time.Time     `json:"started_at"`
+	FinishedAt     time.Time     `json:"finished_at"`
+	RestoreSeconds float64       `json:"restore_seconds"`
+	Tables         int           `json:"tables"`
+	Rows           int64         `json:"rows"`
+	Checks         []BackupCheck `json:"checks"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// NewBackupVerification starts the verification of a dump, restored into a schema named
+// after the start time
+func NewBackupVerification(dump BackupDump, now time.Time) *BackupVerification {
+	return &BackupVerification{
+		ID:        uuid.New().String(),
+		Dump:      dump,
+		Schema:    BackupVerifySchemaPrefix + now.UTC().Format("20060102150405"),
+		Status:    BackupVerificationPassed,
+		StartedAt: now,
+		Checks:    []BackupCheck{},
+	}
+}
+
+// AddCheck records a check; any failed check fails the verification
+func (v *BackupVerification) AddCheck(check BackupCheck) {
+	v.Checks = append(v.Checks, check)
+	if !check.Passed {
+		v.Status = BackupVerificationFailed
+	}
+}
+
+// Fail fails the verification with an error that stopped it
+func (v *BackupVerification) Fail(err error) {
+	v.Status = BackupVerificationFailed
+	v.Error = err.Error()
+}
+
+// Passed reports whether the verification passed
+func (v *BackupVerification) Passed() bool {
+	return v.Status == BackupVerificationPassed
+}
+
+// FailedChecks returns the names of the checks that failed
+func (v *BackupVerification) FailedChecks() []string {
+	var failed []string
+	for _, check := range v.Checks {
+		if !check.Passed {
+			failed = append(failed, check.Kind+":"+check.Name)
+		}
+	}
+	return failed
+}
+
+// CheckRowCounts checks that the dump holds every live table, with at least minRatio of
+// its live rows
+func (v *BackupVerification) CheckRowCounts(counts []BackupTableCount, liveTables []string, minRatio float64) {
+	restored := make(map[string]bool, len(counts))
+	for _, count := range counts {
+		restored[count.Table] = true
+		v.Tables++
+		v.Rows += count.Restored
+
+		check := BackupCheck{Kind: BackupCheckRowCount, Name: count.Table, Passed: true,
+			Detail: fmt.Sprintf("%d rows restored, %d live", count.Restored, count.Live)}
+		if count.Live > 0 && float64(count.Restored) < float64(count.Live)*minRatio {
+			check.Passed = false
+			check.Detail += fmt.Sprintf(", below %.0f%% of the live rows", minRatio*100)
+		}
+		v.AddCheck(check)
+	}
+
+	var missing []string
+	for _, table := range liveTables {
+		if !restored[table] {
+			missing = append(missing, table)
+		}
+	}
+	check := BackupCheck{Kind: BackupCheckTables, Name: "live_tables", Passed: len(missing) == 0,
+		Detail: fmt.Sprintf("%d tables restored", len(counts))}
+	if len(missing) > 0 {
+		check.Detail = "missing from the dump: " + strings.Join(missing, ", ")
+	}
+	v.AddCheck(check)
+}
+
+// BackupStatus sums up the health of the backups for monitoring
+type BackupStatus struct {
+	Status       string               `json:"status"`
+	Message      string               `json:"message"`
+	MaxAge       string               `json:"max_age"`
+	LastPassedAt *time.Time           `json:"last_passed_at,omitempty"`
+	Latest       *BackupVerification  `json:"latest,omitempty"`
+	Recent       []BackupVerification `json:"recent"`
+}
+
+// NewBackupStatus sums up recent verifications, newest first, at now
+func NewBackupStatus(recent []BackupVerification, maxAge time.Duration, now time.Time) *BackupStatus {
+	status := &BackupStatus{MaxAge: maxAge.String(), Recent: recent}
+	if status.Recent == nil {
+		status.Recent = []BackupVerification{}
+	}
+	for i := range recent {
+		if recent[i].Passed() {
+			passedAt := recent[i].FinishedAt
+			status.LastPassedAt = &passedAt
+			break
+		}
+	}
+
+	if len(recent) == 0 {
+		status.Status = BackupStatusUnknown
+		status.Message = "No backup has been verified"
+		return status
+	}
+	status.Latest = &recent[0]
+	switch {
+	case !status.Latest.Passed():
+		status.Status = BackupStatusFailing
+		status.Message = fmt.Sprintf("Verification of %s failed", status.Latest.Dump.Name)
+	case now.Sub(status.Latest.FinishedAt) > maxAge:
+		status.Status = BackupStatusStale
+		status.Message = fmt.Sprintf("No backup verified in the last %s", maxAge)
+	default:
+		status.Status = BackupStatusOK
+		status.Message = fmt.Sprintf("%s restored and verified", status.Latest.Dump.Name)
+	}
+	return status
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupVerification_CheckRowCounts(t *testing.T) {
+	now := time.Date(2025, 9, 16, 4, 0, 0, 0, time.UTC)
+	v := NewBackupVerification(BackupDump{Name: "realty.sql.gz"}, now)
+	assert.Equal(t, "backup_verify_20250916040000", v.Schema)
+
+	v.CheckRowCounts([]BackupTableCount{
+		{Table: "properties", Restored: 95, Live: 100},
+		{Table: "users", Restored: 0, Live: 0},
+	}, []string{"properties", "users"}, DefaultBackupMinRowRatio)
+	assert.True(t, v.Passed())
+	assert.Equal(t, 2, v.Tables)
+	assert.Equal(t, int64(95), v.Rows)
+
+	v.CheckRowCounts([]BackupTableCount{{Table: "images", Restored: 10, Live: 300}},
+		[]string{"images", "agencies"}, DefaultBackupMinRowRatio)
+	assert.False(t, v.Passed())
+	assert.Equal(t, []string{"row_count:images", "tables:live_tables"}, v.FailedChecks())
+}
+
+func TestNewBackupStatus(t *testing.T) {
+	now := time.Date(2025, 9, 16, 12, 0, 0, 0, time.UTC)
+	passed := BackupVerification{Dump: BackupDump{Name: "b.sql.gz"}, Status: BackupVerificationPassed, FinishedAt: now.Add(-2 * time.Hour)}
+	failed := BackupVerification{Dump: BackupDump{Name: "c.sql.gz"}, Status: BackupVerificationFailed, FinishedAt: now.Add(-time.Hour)}
+
+	status := NewBackupStatus(nil, DefaultBackupMaxAge, now)
+	assert.Equal(t, BackupStatusUnknown, status.Status)
+	assert.NotNil(t, status.Recent)
+
+	status = NewBackupStatus([]BackupVerification{passed}, DefaultBackupMaxAge, now)
+	assert.Equal(t, BackupStatusOK, status.Status)
+
+	status = NewBackupStatus([]BackupVerification{failed, passed}, DefaultBackupMaxAge, now)
+	assert.Equal(t, BackupStatusFailing, status.Status)
+	assert.Equal(t, passed.FinishedAt, *status.LastPassedAt)
+
+	status = NewBackupStatus([]BackupVerification{passed}, DefaultBackupMaxAge, now.Add(48*time.Hour))
+	assert.Equal(t, BackupStatusStale, status.Status)
+}
+
+func TestBackupVerification_Fail(t *testing.T) {
+	v := NewBackupVerification(BackupDump{}, time.Now())
+	v.Fail(fmt.Errorf("failed to list tables: connection reset"))
+	assert.False(t, v.Passed())
+	assert.Empty(t, v.FailedChecks())
+}
@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CalendarProviderGoogle is the calendar agents connect to sync their agenda
+const CalendarProviderGoogle = "google"
+
+// CalendarPrimary is the calendar of the connected account appointments are pushed to
+const CalendarPrimary = "primary"
+
+// Calendar connection statuses: pending until the agent grants access, revoked when the
+// provider no longer accepts the refresh token and the agent has to connect again
+const (
+	CalendarConnectionPending = "pending"
+	CalendarConnectionActive  = "active"
+	CalendarConnectionRevoked = "revoked"
+)
+
+// Calendar sync limits
+const (
+	// CalendarOAuthStateTTL is how long the agent has to grant access after starting
+	CalendarOAuthStateTTL = 15 * time.Minute
+	// CalendarSyncWindow is how far ahead appointments are pushed and busy times imported
+	CalendarSyncWindow = 180 * 24 * time.Hour
+	// CalendarOAuthStatePrefix marks the state of calendar authorizations
+	CalendarOAuthStatePrefix = "cst_"
+	// calendarTokenRefreshMargin refreshes access tokens about to expire
+	calendarTokenRefreshMargin = time.Minute
+	// calendarEventIDPrefix starts the IDs of pushed events; Google accepts the
+	// characters a-v and 0-9, which hex appointment IDs satisfy
+	calendarEventIDPrefix = "rc"
+)
+
+// CalendarToken is an OAuth token granting access to an agent's calendar
+type CalendarToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// CalendarConnection links an agent to the external calendar their appointments are
+// pushed to and their busy times imported from. Tokens and the sync state never leave
+// the server.
+type CalendarConnection struct {
+	ID             string     `json:"id"`
+	AgentID        string     `json:"agent_id"`
+	Provider       string     `json:"provider"`
+	CalendarID     string     `json:"calendar_id"`
+	CalendarName   string     `json:"calendar_name,omitempty"` // the account's email for primary calendars
+	Status         string     `json:"status"`
+	AccessToken    string     `json:"-"`
+	RefreshToken   string     `json:"-"`
+	TokenExpiresAt *time.Time `json:"-"`
+	SyncToken      string     `json:"-"` // resumes incremental imports; empty for a full import
+	StateHash      string     `json:"-"`
+	StateExpiresAt *time.Time `json:"-"`
+	LastSyncedAt   *time.Time `json:"last_synced_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// NewCalendarConnection creates the pending Google Calendar connection of an agent
+func NewCalendarConnection(id, agentID string, now time.Time) *CalendarConnection {
+	return &CalendarConnection{
+		ID:         id,
+		AgentID:    agentID,
+		Provider:   CalendarProviderGoogle,
+		CalendarID: CalendarPrimary,
+		Status:     CalendarConnectionPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// StartAuthorization generates the state that identifies the agent when the provider
+// redirects back after consent, and returns it. Only its hash is kept. A connected
+// calendar stays active until the new authorization completes.
+func (c *CalendarConnection) StartAuthorization(now time.Time) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate authorization state: %w", err)
+	}
+	state := CalendarOAuthStatePrefix + hex.EncodeToString(buf)
+	expiresAt := now.Add(CalendarOAuthStateTTL)
+	c.StateHash = HashAPIKey(state)
+	c.StateExpiresAt = &expiresAt
+	c.UpdatedAt = now
+	return state, nil
+}
+
+// Authorize stores the token granted after consent and activates the connection. The
+// next sync imports the calendar from scratch, as the account may have changed.
+func (c *CalendarConnection) Authorize(token *CalendarToken, now time.Time) error {
+	if c.StateExpiresAt == nil || now.After(*c.StateExpiresAt) {
+		return fmt.Errorf("invalid authorization state: it expired, connect the calendar again")
+	}
+	if token.RefreshToken == "" {
+		return fmt.Errorf("invalid authorization: no offline access was granted")
+	}
+	expiresAt := token.ExpiresAt
+	c.AccessToken = token.AccessToken
+	c.RefreshToken = token.RefreshToken
+	c.TokenExpiresAt = &expiresAt
+	c.SyncToken = ""
+	c.StateHash = ""
+	c.StateExpiresAt = nil
+	c.Status = CalendarConnectionActive
+	c.LastError = ""
+	c.UpdatedAt = now
+	return nil
+}
+
+// NeedsRefresh reports whether the access token expired or is about to
+func (c *CalendarConnection) NeedsRefresh(now time.Time) bool {
+	return c.AccessToken == "" || c.TokenExpiresAt == nil || !now.Add(calendarTokenRefreshMargin).Before(*c.TokenExpiresAt)
+}
+
+// Refreshed stores a refreshed access token. Providers may omit the refresh token, in
+// which case the current one is kept.
+func (c *CalendarConnection) Refreshed(token *CalendarToken, now time.Time) {
+	expiresAt := token.ExpiresAt
+	c.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		c.RefreshToken = token.RefreshToken
+	}
+	c.TokenExpiresAt = &expiresAt
+	c.UpdatedAt = now
+}
+
+// Revoke marks the connection as revoked and drops its tokens
+func (c *CalendarConnection) Revoke(reason string, now time.Time) {
+	c.Status = CalendarConnectionRevoked
+	c.AccessToken = ""
+	c.RefreshToken = ""
+	c.TokenExpiresAt = nil
+	c.SyncToken = ""
+	c.LastError = reason
+	c.UpdatedAt = now
+}
+
+// CalendarEventID returns the ID of the event an appointment is pushed as, so pushing
+// it again updates the same event
+func CalendarEventID(appointmentID string) string {
+	return calendarEventIDPrefix + strings.ToLower(strings.ReplaceAll(appointmentID, "-", ""))
+}
+
+// CalendarEvent is an event of an external calendar as an import reads it
+type CalendarEvent struct {
+	ExternalID    string
+	ETag          string
+	Cancelled     bool // deleted, or declined by the agent
+	Transparent   bool // marked as free, so it does not block the agent
+	StartsAt      time.Time
+	EndsAt        time.Time
+	AppointmentID string // set on events pushed from an appointment
+}
+
+// IsBusy reports whether the event blocks the agent's time. Pushed appointments are
+// already on the agenda and do not count.
+func (e CalendarEvent) IsBusy() bool {
+	return !e.Cancelled && !e.Transparent && e.AppointmentID == "" && e.EndsAt.After(e.StartsAt)
+}
+
+// CalendarEventPage is the result of an import: the events changed since the previous
+// sync token, or every event on a full import
+type CalendarEventPage struct {
+	Events        []CalendarEvent
+	NextSyncToken string
+	CalendarName  string
+}
+
+// CalendarEventLink records the event an appointment was pushed as, with the sequence
+// of the appointment pushed and the event's version after the push
+type CalendarEventLink struct {
+	ConnectionID  string    `json:"connection_id"`
+	AppointmentID string    `json:"appointment_id"`
+	ExternalID    string    `json:"external_id"`
+	ETag          string    `json:"-"`
+	Sequence      int       `json:"sequence"` // -1 forces a push, e.g. after the event was edited externally
+	PushedAt      time.Time `json:"pushed_at"`
+}
+
+// NeedsPush reports whether an appointment changed since it was pushed
+func (l *CalendarEventLink) NeedsPush(appointment *Appointment) bool {
+	return l == nil || l.Sequence != appointment.Sequence
+}
+
+// CalendarBusyBlock is a busy time imported from an agent's external calendar. Only the
+// times are kept, not what the agent is busy with.
+type CalendarBusyBlock struct {
+	ID           string    `json:"id"`
+	ConnectionID string    `json:"-"`
+	AgentID      string    `json:"agent_id"`
+	ExternalID   string    `json:"-"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CheckBusyConflicts checks that an appointment overlaps no busy time of the agent
+func CheckBusyConflicts(startsAt, endsAt time.Time, blocks []CalendarBusyBlock) error {
+	for _, block := range blocks {
+		if block.StartsAt.Before(endsAt) && startsAt.Before(block.EndsAt) {
+			location := startsAt.Location()
+			return fmt.Errorf("appointment conflicts with a busy time in the agent's calendar from %s to %s",
+				block.StartsAt.In(location).Format("2006-01-02 15:04"), block.EndsAt.In(location).Format("2006-01-02 15:04"))
+		}
+	}
+	return nil
+}
+
+// CalendarConflict is a scheduled appointment overlapping a busy time imported after it
+// was scheduled. The appointment stays; the agent decides which one to move.
+type CalendarConflict struct {
+	AppointmentID string    `json:"appointment_id"`
+	Title         string    `json:"title"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	BusyStartsAt  time.Time `json:"busy_starts_at"`
+	BusyEndsAt    time.Time `json:"busy_ends_at"`
+}
+
+// CalendarConflicts returns the scheduled appointments overlapping busy times
+func CalendarConflicts(appointments []Appointment, blocks []CalendarBusyBlock) []CalendarConflict {
+	conflicts := []CalendarConflict{}
+	for _, appointment := range appointments {
+		if appointment.Status != AppointmentScheduled {
+			continue
+		}
+		for _, block := range blocks {
+			if block.StartsAt.Before(appointment.EndsAt) && appointment.StartsAt.Before(block.EndsAt) {
+				conflicts = append(conflicts, CalendarConflict{
+					AppointmentID: appointment.ID,
+					Title:         appointment.Title,
+					StartsAt:      appointment.StartsAt,
+					EndsAt:        appointment.EndsAt,
+					BusyStartsAt:  block.StartsAt,
+					BusyEndsAt:    block.EndsAt,
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+// CalendarSyncReport summarizes a sync of an agent's calendar
+type CalendarSyncReport struct {
+	AgentID     string `json:"agent_id"`
+	FullImport  bool   `json:"full_import"`
+	Imported    int    `json:"imported"`    // busy times added or changed
+	Removed     int    `json:"removed"`     // busy times deleted or freed
+	Pushed      int    `json:"pushed"`      // appointments pushed as events
+	Deleted     int    `json:"deleted"`     // events of cancelled appointments deleted
+	Overwritten int    `json:"overwritten"` // pushed events edited in the calendar, pushed again
+}
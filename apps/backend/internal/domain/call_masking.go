@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Call session statuses: a session is closed when it expires and its proxy number can
+// be handed to other leads of the same buyer or agent
+const (
+	CallSessionActive = "active"
+	CallSessionClosed = "closed"
+)
+
+// Parties of a masked call
+const (
+	CallerBuyer = "buyer"
+	CallerAgent = "agent"
+)
+
+// Lead call statuses. Calls are in progress until the provider reports how they ended.
+const (
+	LeadCallInProgress = "in_progress"
+	LeadCallCompleted  = "completed"
+	LeadCallBusy       = "busy"
+	LeadCallNoAnswer   = "no_answer"
+	LeadCallFailed     = "failed"
+	LeadCallCanceled   = "canceled"
+)
+
+// Call masking limits
+const (
+	// DefaultCallSessionTTL is how long a proxy number connects a buyer and an agent
+	DefaultCallSessionTTL = 30 * 24 * time.Hour
+	// MaxMaskedCallDuration cuts masked calls that were left open
+	MaxMaskedCallDuration = 2 * time.Hour
+)
+
+// CallSession lends a proxy number to a lead so its buyer and agent can call each
+// other without seeing the other's number: calls to the proxy number from one of them
+// are forwarded to the other. A proxy number is used by one session per participant,
+// so the caller's number identifies the session. Real numbers never leave the server.
+type CallSession struct {
+	ID          string     `json:"id"`
+	LeadID      string     `json:"lead_id"`
+	AgencyID    string     `json:"agency_id"`
+	ProxyNumber string     `json:"proxy_number"`
+	BuyerID     string     `json:"buyer_id"`
+	BuyerPhone  string     `json:"-"`
+	AgentID     string     `json:"agent_id"`
+	AgentPhone  string     `json:"-"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+}
+
+// NewCallSession creates the call session of a lead between its buyer and agent. Their
+// phone numbers are converted to E.164, the format calls come from.
+func NewCallSession(lead *LeadAssignment, buyer, agent *User, proxyNumber string, ttl time.Duration, now time.Time) (*CallSession, error) {
+	if lead.AgentID == nil || *lead.AgentID != agent.ID {
+		return nil, fmt.Errorf("the lead is not assigned to agent %s", agent.ID)
+	}
+	if buyer.Phone == nil || *buyer.Phone == "" {
+		return nil, fmt.Errorf("the buyer has no phone number")
+	}
+	if agent.Phone == nil || *agent.Phone == "" {
+		return nil, fmt.Errorf("the agent has no phone number")
+	}
+	buyerPhone, err := PhoneE164(*buyer.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("the buyer's phone number is not valid: %w", err)
+	}
+	agentPhone, err := PhoneE164(*agent.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("the agent's phone number is not valid: %w", err)
+	}
+	if buyerPhone == agentPhone {
+		return nil, fmt.Errorf("the buyer and the agent have the same phone number")
+	}
+	if ttl <= 0 {
+		ttl = DefaultCallSessionTTL
+	}
+
+	return &CallSession{
+		ID:          uuid.New().String(),
+		LeadID:      lead.ID,
+		AgencyID:    lead.AgencyID,
+		ProxyNumber: proxyNumber,
+		BuyerID:     buyer.ID,
+		BuyerPhone:  buyerPhone,
+		AgentID:     agent.ID,
+		AgentPhone:  agentPhone,
+		Status:      CallSessionActive,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	}, nil
+}
+
+// IsActive reports whether the session still forwards calls
+func (s *CallSession) IsActive(now time.Time) bool {
+	return s.Status == CallSessionActive && now.Before(s.ExpiresAt)
+}
+
+// Close stops forwarding calls through the session
+func (s *CallSession) Close(now time.Time) {
+	s.Status = CallSessionClosed
+	s.ClosedAt = &now
+}
+
+// Forward returns who a call from caller is connected to: the agent when the buyer
+// calls and the buyer when the agent calls
+func (s *CallSession) Forward(caller string, now time.Time) (callerRole, callee string, err error) {
+	if !s.IsActive(now) {
+		return "", "", fmt.Errorf("the call session expired")
+	}
+	switch caller {
+	case s.BuyerPhone:
+		return CallerBuyer, s.AgentPhone, nil
+	case s.AgentPhone:
+		return CallerAgent, s.BuyerPhone, nil
+	}
+	return "", "", fmt.Errorf("the caller is not part of the call session")
+}
+
+// PickProxyNumber returns the first number of the pool not used by another active
+// session of the buyer or agent
+func PickProxyNumber(pool []string, busy []string) (string, error) {
+	used := make(map[string]bool, len(busy))
+	for _, number := range busy {
+		used[number] = true
+	}
+	for _, number := range pool {
+		if !used[number] {
+			return number, nil
+		}
+	}
+	return "", fmt.Errorf("all %d proxy numbers are in use by the buyer or the agent", len(pool))
+}
+
+// LeadCall is a call between the buyer and agent of a lead through its proxy number
+type LeadCall struct {
+	ID              string     `json:"id"`
+	SessionID       string     `json:"session_id"`
+	LeadID          string     `json:"lead_id"`
+	CallSID         string     `json:"call_sid"` // provider call ID
+	CallerRole      string     `json:"caller_role"`
+	Status          string     `json:"status"`
+	DurationSeconds int        `json:"duration_seconds"` // talk time, 0 when unanswered
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+}
+
+// NewLeadCall records a call placed through a session
+func NewLeadCall(session *CallSession, callSID, callerRole string, now time.Time) *LeadCall {
+	return &LeadCall{
+		ID:         uuid.New().String(),
+		SessionID:  session.ID,
+		LeadID:     session.LeadID,
+		CallSID:    callSID,
+		CallerRole: callerRole,
+		Status:     LeadCallInProgress,
+		StartedAt:  now,
+	}
+}
+
+// Finish records how a call ended from the provider's dial status, e.g. no-answer
+func (c *LeadCall) Finish(dialStatus string, durationSeconds int, now time.Time) error {
+	status := map[string]string{
+		"completed": LeadCallCompleted,
+		"answered":  LeadCallCompleted,
+		"busy":      LeadCallBusy,
+		"no-answer": LeadCallNoAnswer,
+		"failed":    LeadCallFailed,
+		"canceled":  LeadCallCanceled,
+	}[dialStatus]
+	if status == "" {
+		return fmt.Errorf("unknown call status: %s", dialStatus)
+	}
+	if durationSeconds < 0 || status != LeadCallCompleted {
+		durationSeconds = 0
+	}
+
+	c.Status = status
+	c.DurationSeconds = durationSeconds
+	c.EndedAt = &now
+	return nil
+}
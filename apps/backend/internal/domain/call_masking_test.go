@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallSession_Forward(t *testing.T) {
+	now := time.Date(2025, 9, 23, 10, 0, 0, 0, time.UTC)
+	lead := NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-1", LeadScore{}, now)
+	buyer := &User{ID: "buyer-1", Phone: stringPtr("0991234567")}
+	agent := &User{ID: "agent-1", Phone: stringPtr("0987654321")}
+
+	_, err := NewCallSession(lead, buyer, &User{ID: "agent-2", Phone: stringPtr("0987654321")}, "+593991110000", 0, now)
+	assert.ErrorContains(t, err, "not assigned")
+	_, err = NewCallSession(lead, &User{ID: "buyer-1"}, agent, "+593991110000", 0, now)
+	assert.ErrorContains(t, err, "buyer has no phone")
+
+	session, err := NewCallSession(lead, buyer, agent, "+593991110000", 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, "+593991234567", session.BuyerPhone)
+	assert.Equal(t, now.Add(DefaultCallSessionTTL), session.ExpiresAt)
+
+	role, callee, err := session.Forward("+593991234567", now)
+	require.NoError(t, err)
+	assert.Equal(t, CallerBuyer, role)
+	assert.Equal(t, "+593987654321", callee)
+	role, callee, err = session.Forward("+593987654321", now)
+	require.NoError(t, err)
+	assert.Equal(t, CallerAgent, role)
+	assert.Equal(t, "+593991234567", callee)
+
+	_, _, err = session.Forward("+593990000000", now)
+	assert.ErrorContains(t, err, "not part of the call session")
+	_, _, err = session.Forward("+593991234567", session.ExpiresAt)
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestPickProxyNumber(t *testing.T) {
+	pool := []string{"+593991110000", "+593991110001"}
+
+	number, err := PickProxyNumber(pool, []string{"+593991110000"})
+	require.NoError(t, err)
+	assert.Equal(t, "+593991110001", number)
+
+	_, err = PickProxyNumber(pool, pool)
+	assert.ErrorContains(t, err, "all 2 proxy numbers are in use")
+}
+
+func TestLeadCall_Finish(t *testing.T) {
+	now := time.Date(2025, 9, 23, 10, 0, 0, 0, time.UTC)
+	call := NewLeadCall(&CallSession{ID: "session-1", LeadID: "lead-1"}, "CA1", CallerBuyer, now)
+	assert.Equal(t, LeadCallInProgress, call.Status)
+
+	assert.ErrorContains(t, call.Finish("ringing", 0, now), "unknown call status")
+	require.NoError(t, call.Finish("no-answer", 12, now.Add(time.Minute)))
+	assert.Equal(t, LeadCallNoAnswer, call.Status)
+	assert.Equal(t, 0, call.DurationSeconds, "unanswered calls have no talk time")
+
+	require.NoError(t, call.Finish("completed", 95, now.Add(2*time.Minute)))
+	assert.Equal(t, 95, call.DurationSeconds)
+}
+
+func TestBuildLeadTimeline(t *testing.T) {
+	routed := time.Date(2025, 9, 20, 9, 0, 0, 0, time.UTC)
+	lead := NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-1", LeadScore{}, routed)
+	transitions := []LeadStageTransition{{ID: "t1", FromStage: LeadStageNew, ToStage: LeadStageContacted, CreatedAt: routed.Add(2 * time.Hour)}}
+	calls := []LeadCall{{ID: "c1", Status: LeadCallCompleted, StartedAt: routed.Add(time.Hour)}}
+
+	timeline := BuildLeadTimeline(lead, transitions, calls)
+	require.Len(t, timeline, 3)
+	assert.Equal(t, LeadTimelineRouted, timeline[0].Kind)
+	assert.Equal(t, LeadTimelineCall, timeline[1].Kind)
+	assert.Equal(t, "c1", timeline[1].Call.ID)
+	assert.Equal(t, LeadTimelineStage, timeline[2].Kind)
+	assert.Equal(t, "t1", timeline[2].Transition.ID)
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Commute modes
+const (
+	CommuteModeDriving = "driving"
+	CommuteModeTransit = "transit"
+)
+
+// GeoPoint is a WGS84 coordinate
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// ParseGeoPoint parses a "lat,lng" coordinate such as "-0.1807,-78.4678"
+func ParseGeoPoint(value string) (GeoPoint, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return GeoPoint{}, fmt.Errorf("invalid coordinates: use lat,lng")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return GeoPoint{}, fmt.Errorf("invalid coordinates: latitude is not a number")
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return GeoPoint{}, fmt.Errorf("invalid coordinates: longitude is not a number")
+	}
+
+	point := GeoPoint{Lat: lat, Lng: lng}
+	if err := point.Validate(); err != nil {
+		return GeoPoint{}, err
+	}
+	return point, nil
+}
+
+// Validate checks the coordinate ranges
+func (p GeoPoint) Validate() error {
+	if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("invalid coordinates: latitude must be within ±90 and longitude within ±180")
+	}
+	return nil
+}
+
+// String formats the point as "lat,lng"
+func (p GeoPoint) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lng, 'f', -1, 64)
+}
+
+// Rounded returns the point rounded to decimals places, e.g. 3 for about 110 meters,
+// so nearby destinations share cached routes
+func (p GeoPoint) Rounded(decimals int) GeoPoint {
+	factor := math.Pow(10, float64(decimals))
+	return GeoPoint{Lat: math.Round(p.Lat*factor) / factor, Lng: math.Round(p.Lng*factor) / factor}
+}
+
+// PropertyLocation returns the coordinates of a property, if it has them
+func PropertyLocation(property *Property) (GeoPoint, bool) {
+	if property.Latitude == nil || property.Longitude == nil {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Lat: *property.Latitude, Lng: *property.Longitude}, true
+}
+
+// RouteEstimate is the travel time and distance of a route in one mode
+type RouteEstimate struct {
+	Mode            string  `json:"mode"`
+	DurationSeconds int     `json:"duration_seconds"`
+	DurationMinutes int     `json:"duration_minutes"`
+	DistanceMeters  int     `json:"distance_meters"`
+	DistanceKm      float64 `json:"distance_km"`
+	Provider        string  `json:"provider"`
+}
+
+// NewRouteEstimate creates an estimate with its duration in whole minutes
+func NewRouteEstimate(mode string, durationSeconds, distanceMeters float64, provider string) *RouteEstimate {
+	return &RouteEstimate{
+		Mode:            mode,
+		DurationSeconds: int(math.Round(durationSeconds)),
+		DurationMinutes: int(math.Round(durationSeconds / 60)),
+		DistanceMeters:  int(math.Round(distanceMeters)),
+		DistanceKm:      math.Round(distanceMeters/100) / 10,
+		Provider:        provider,
+	}
+}
+
+// Commute holds the estimates from a property to a destination. A mode the routing
+// provider does not support, or cannot route, has no estimate.
+type Commute struct {
+	PropertyID string         `json:"property_id"`
+	From       GeoPoint       `json:"from"`
+	To         GeoPoint       `json:"to"`
+	Driving    *RouteEstimate `json:"driving,omitempty"`
+	Transit    *RouteEstimate `json:"transit,omitempty"`
+}
+
+// Minutes returns the commute in a mode, or the fastest mode when mode is empty. The
+// second value is false when there is no estimate.
+func (c *Commute) Minutes(mode string) (int, bool) {
+	switch mode {
+	case CommuteModeDriving:
+		if c.Driving != nil {
+			return c.Driving.DurationMinutes, true
+		}
+	case CommuteModeTransit:
+		if c.Transit != nil {
+			return c.Transit.DurationMinutes, true
+		}
+	case "":
+		driving, hasDriving := c.Minutes(CommuteModeDriving)
+		transit, hasTransit := c.Minutes(CommuteModeTransit)
+		switch {
+		case hasDriving && hasTransit:
+			return int(math.Min(float64(driving), float64(transit))), true
+		case hasDriving:
+			return driving, true
+		case hasTransit:
+			return transit, true
+		}
+	}
+	return 0, false
+}
+
+// IsValidCommuteMode verifies if a commute mode is supported
+func IsValidCommuteMode(mode string) bool {
+	return mode == CommuteModeDriving || mode == CommuteModeTransit
+}
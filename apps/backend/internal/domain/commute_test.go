@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeoPoint(t *testing.T) {
+	point, err := ParseGeoPoint("-2.1894, -79.8891")
+	require.NoError(t, err)
+	assert.Equal(t, GeoPoint{Lat: -2.1894, Lng: -79.8891}, point)
+	assert.Equal(t, "-2.1894,-79.8891", point.String())
+	assert.Equal(t, GeoPoint{Lat: -2.189, Lng: -79.889}, point.Rounded(3))
+
+	for _, value := range []string{"", "-2.1894", "abc,-79.8", "-95,-79.8", "-2.1,190"} {
+		_, err := ParseGeoPoint(value)
+		assert.ErrorContains(t, err, "invalid coordinates", value)
+	}
+}
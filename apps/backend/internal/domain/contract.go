@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Contract types
+const (
+	ContractTypeRental          = "rental"
+	ContractTypeSaleReservation = "sale_reservation"
+)
+
+// contractTemplateNamePattern restricts template names to slugs, e.g. "arriendo-estandar"
+var contractTemplateNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ContractTemplate is a version of a contract template written with Go text/template.
+// Templates are identified by name; each change creates a new version and one version
+// per name is active.
+type ContractTemplate struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Version   int       `json:"version"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Active    bool      `json:"active"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ContractParty is the owner, tenant or buyer named in a contract
+type ContractParty struct {
+	Name    string `json:"name"`
+	Cedula  string `json:"cedula,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// ContractTerms are the economic terms of a contract
+type ContractTerms struct {
+	MonthlyRent       float64   `json:"monthly_rent,omitempty"`
+	Deposit           float64   `json:"deposit,omitempty"`
+	DurationMonths    int       `json:"duration_months,omitempty"`
+	SalePrice         float64   `json:"sale_price,omitempty"`
+	ReservationAmount float64   `json:"reservation_amount,omitempty"`
+	StartDate         time.Time `json:"start_date"`
+}
+
+// ContractData is the data available to contract templates as {{.Property.Title}},
+// {{.Owner.Name}}, {{.Tenant.Name}}, {{.Terms.MonthlyRent}} and {{.Date}}
+type ContractData struct {
+	Property *Property
+	Owner    ContractParty
+	Tenant   ContractParty
+	Terms    ContractTerms
+	Date     time.Time
+}
+
+// contractTemplateFuncs are the helpers available to contract templates
+var contractTemplateFuncs = template.FuncMap{
+	"money": FormatMoney,
+	"date": func(t time.Time) string {
+		return t.Format("02/01/2006")
+	},
+	"upper": strings.ToUpper,
+	"deref": func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	},
+}
+
+// NewContractTemplate creates the first version of a template after validating that it renders
+func NewContractTemplate(name, contractType, title, body string) (*ContractTemplate, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !contractTemplateNamePattern.MatchString(name) || len(name) > 100 {
+		return nil, fmt.Errorf("template name must be a lowercase slug of at most 100 characters")
+	}
+	if contractType != ContractTypeRental && contractType != ContractTypeSaleReservation {
+		return nil, fmt.Errorf("contract type must be rental or sale_reservation")
+	}
+	title = strings.TrimSpace(title)
+	if title == "" || len(title) > 255 {
+		return nil, fmt.Errorf("template title is required and must be at most 255 characters")
+	}
+
+	tmpl := &ContractTemplate{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Type:      contractType,
+		Version:   1,
+		Title:     title,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Validate parses the template and renders it with sample data, so references to
+// unknown variables are rejected when the template is saved rather than when a
+// contract is generated
+func (t *ContractTemplate) Validate() error {
+	if strings.TrimSpace(t.Body) == "" {
+		return fmt.Errorf("template body cannot be empty")
+	}
+	_, err := t.Render(sampleContractData())
+	return err
+}
+
+// Render executes the template with the contract data
+func (t *ContractTemplate) Render(data ContractData) (string, error) {
+	tmpl, err := template.New(t.Name).Funcs(contractTemplateFuncs).Option("missingkey=error").Parse(t.Body)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// FormatMoney formats an amount in US dollars, e.g. $1,250.50
+func FormatMoney(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	s := fmt.Sprintf("%.2f", amount)
+	whole, cents := s[:len(s)-3], s[len(s)-2:]
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + "$" + b.String() + "." + cents
+}
+
+// sampleContractData fills every variable so templates can be validated
+func sampleContractData() ContractData {
+	sector := "La Carolina"
+	address := "Av. Amazonas N34-120"
+	rent := 800.0
+	return ContractData{
+		Property: &Property{
+			ID: "sample", Title: "Departamento de ejemplo", Price: 120000, Province: "Pichincha",
+			City: "Quito", Sector: &sector, Address: &address, Type: TypeApartment, RentPrice: &rent,
+			Bedrooms: 2, Bathrooms: 2, AreaM2: 85,
+		},
+		Owner:  ContractParty{Name: "Propietario", Cedula: "1700000000", Email: "owner@example.com", Phone: "0990000000", Address: "Quito"},
+		Tenant: ContractParty{Name: "Inquilino", Cedula: "1700000001", Email: "tenant@example.com", Phone: "0990000001", Address: "Quito"},
+		Terms: ContractTerms{
+			MonthlyRent: rent, Deposit: rent, DurationMonths: 12, SalePrice: 120000,
+			ReservationAmount: 5000, StartDate: time.Now(),
+		},
+		Date: time.Now(),
+	}
+}
+
+// GeneratedContractTitle is the document title of a contract generated from a template
+func GeneratedContractTitle(tmpl *ContractTemplate, tenant ContractParty) string {
+	title := fmt.Sprintf("%s (v%d)", tmpl.Title, tmpl.Version)
+	if tenant.Name != "" {
+		title += " - " + tenant.Name
+	}
+	for len(title) > 255 {
+		runes := []rune(title)
+		title = string(runes[:len(runes)-1])
+	}
+	return title
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContractTemplate(t *testing.T) {
+	tmpl, err := NewContractTemplate(" Arriendo-Corto ", ContractTypeRental, "Contrato de arriendo",
+		"{{.Owner.Name}} arrienda {{.Property.Title}} a {{.Tenant.Name}} por {{money .Terms.MonthlyRent}}")
+	require.NoError(t, err)
+	assert.Equal(t, "arriendo-corto", tmpl.Name)
+	assert.Equal(t, 1, tmpl.Version)
+
+	_, err = NewContractTemplate("arriendo corto", ContractTypeRental, "Contrato", "{{.Owner.Name}}")
+	assert.ErrorContains(t, err, "lowercase slug")
+
+	_, err = NewContractTemplate("arriendo", "lease", "Contrato", "{{.Owner.Name}}")
+	assert.ErrorContains(t, err, "rental or sale_reservation")
+
+	_, err = NewContractTemplate("arriendo", ContractTypeRental, "Contrato", "{{.Owner.Nombre}}")
+	assert.ErrorContains(t, err, "invalid template", "unknown variables are rejected when saving")
+
+	_, err = NewContractTemplate("arriendo", ContractTypeRental, "Contrato", "{{if .Owner.Name}}")
+	assert.ErrorContains(t, err, "invalid template")
+}
+
+func TestContractTemplate_Render(t *testing.T) {
+	tmpl, err := NewContractTemplate("arriendo", ContractTypeRental, "Contrato",
+		"{{upper .Tenant.Name}} paga {{money .Terms.MonthlyRent}} desde el {{date .Terms.StartDate}} en {{deref .Property.Sector}}")
+	require.NoError(t, err)
+
+	sector := "Urdesa"
+	text, err := tmpl.Render(ContractData{
+		Property: &Property{Title: "Casa", Sector: &sector},
+		Tenant:   ContractParty{Name: "María Pérez"},
+		Terms:    ContractTerms{MonthlyRent: 1250.5, StartDate: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "MARÍA PÉREZ paga $1,250.50 desde el 01/09/2025 en Urdesa", text)
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "$0.00", FormatMoney(0))
+	assert.Equal(t, "$800.00", FormatMoney(800))
+	assert.Equal(t, "$120,000.00", FormatMoney(120000))
+	assert.Equal(t, "$1,234,567.89", FormatMoney(1234567.891))
+	assert.Equal(t, "-$1,500.00", FormatMoney(-1500))
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CSP report dispositions: whether the violated policy blocked the resource or was only
+// reported, as Content-Security-Policy-Report-Only
+const (
+	CSPDispositionEnforce = "enforce"
+	CSPDispositionReport  = "report"
+)
+
+// CSP report limits
+const (
+	// MaxCSPReportBytes bounds the body of a report request
+	MaxCSPReportBytes = 64 * 1024
+	// MaxCSPReportsPerRequest bounds the reports taken from a Reporting API batch
+	MaxCSPReportsPerRequest = 20
+	// maxCSPFieldChars bounds the URIs and directives kept of a report
+	maxCSPFieldChars = 512
+)
+
+// CSPReport is a Content-Security-Policy violation reported by a browser
+type CSPReport struct {
+	DocumentURI        string `json:"document_uri"`
+	EffectiveDirective string `json:"effective_directive"`
+	BlockedURI         string `json:"blocked_uri"`
+	SourceFile         string `json:"source_file,omitempty"`
+	LineNumber         int    `json:"line_number,omitempty"`
+	Disposition        string `json:"disposition"`
+}
+
+// Fingerprint identifies the violation of a report, so every page view reporting it
+// counts towards the same violation
+func (r CSPReport) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		r.DocumentURI, r.EffectiveDirective, r.BlockedURI, r.SourceFile, fmt.Sprint(r.LineNumber), r.Disposition,
+	}, "\n")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// CSPViolation is a violation with how often it was reported
+type CSPViolation struct {
+	Fingerprint string `json:"fingerprint"`
+	CSPReport
+	Count       int64     `json:"count"`
+	UserAgent   string    `json:"user_agent,omitempty"` // of the latest report
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// legacyCSPReport is the body browsers send to report-uri, as application/csp-report
+type legacyCSPReport struct {
+	DocumentURI        string          `json:"document-uri"`
+	ViolatedDirective  string          `json:"violated-directive"`
+	EffectiveDirective string          `json:"effective-directive"`
+	BlockedURI         string          `json:"blocked-uri"`
+	SourceFile         string          `json:"source-file"`
+	LineNumber         json.RawMessage `json:"line-number"`
+	Disposition        string          `json:"disposition"`
+}
+
+// reportingAPIReport is an entry of the batches browsers send to report-to endpoints, as
+// application/reports+json
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string          `json:"documentURL"`
+		EffectiveDirective string          `json:"effectiveDirective"`
+		BlockedURL         string          `json:"blockedURL"`
+		SourceFile         string          `json:"sourceFile"`
+		LineNumber         json.RawMessage `json:"lineNumber"`
+		Disposition        string          `json:"disposition"`
+	} `json:"body"`
+}
+
+// ParseCSPReports parses a report-uri body or a Reporting API batch into normalized
+// reports. Entries of other report types and reports without a directive are skipped.
+func ParseCSPReports(body []byte) ([]CSPReport, error) {
+	trimmed := strings.TrimSpace(string(body))
+	reports := []CSPReport{}
+
+	if strings.HasPrefix(trimmed, "[") {
+		var batch []reportingAPIReport
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil, fmt.Errorf("invalid CSP report: %w", err)
+		}
+		for _, entry := range batch {
+			if entry.Type != "csp-violation" {
+				continue
+			}
+			report, ok := normalizeCSPReport(entry.Body.DocumentURL, entry.Body.EffectiveDirective, entry.Body.BlockedURL,
+				entry.Body.SourceFile, entry.Body.LineNumber, entry.Body.Disposition)
+			if ok {
+				reports = append(reports, report)
+			}
+			if len(reports) == MaxCSPReportsPerRequest {
+				break
+			}
+		}
+		return reports, nil
+	}
+
+	var legacy struct {
+		Report *legacyCSPReport `json:"csp-report"`
+	}
+	if err := json.Unmarshal(body, &legacy); err != nil {
+		return nil, fmt.Errorf("invalid CSP report: %w", err)
+	}
+	if legacy.Report == nil {
+		return nil, fmt.Errorf("invalid CSP report: csp-report is missing")
+	}
+	directive := legacy.Report.EffectiveDirective
+	if directive == "" {
+		// Older browsers only send the violated directive with its sources
+		directive, _, _ = strings.Cut(strings.TrimSpace(legacy.Report.ViolatedDirective), " ")
+	}
+	if report, ok := normalizeCSPReport(legacy.Report.DocumentURI, directive, legacy.Report.BlockedURI,
+		legacy.Report.SourceFile, legacy.Report.LineNumber, legacy.Report.Disposition); ok {
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// normalizeCSPReport builds a report from its fields, without the query strings and
+// fragments of its URIs, which hold tokens and make every report distinct
+func normalizeCSPReport(documentURI, directive, blockedURI, sourceFile string, lineNumber json.RawMessage, disposition string) (CSPReport, bool) {
+	directive = strings.ToLower(strings.TrimSpace(directive))
+	if directive == "" {
+		return CSPReport{}, false
+	}
+	if disposition != CSPDispositionReport {
+		disposition = CSPDispositionEnforce
+	}
+	var line int
+	json.Unmarshal(lineNumber, &line)
+	if line < 0 {
+		line = 0
+	}
+	return CSPReport{
+		DocumentURI:        normalizeCSPURI(documentURI),
+		EffectiveDirective: truncateRunes(directive, maxCSPFieldChars),
+		BlockedURI:         normalizeCSPURI(blockedURI),
+		SourceFile:         normalizeCSPURI(sourceFile),
+		LineNumber:         line,
+		Disposition:        disposition,
+	}, true
+}
+
+// normalizeCSPURI strips the query and fragment of a URI. Keywords such as inline and
+// eval are kept, and data: and blob: URIs are reduced to their scheme.
+func normalizeCSPURI(value string) string {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+	for _, scheme := range []string{"data", "blob", "filesystem"} {
+		if strings.HasPrefix(lower, scheme+":") {
+			return scheme
+		}
+	}
+	if parsed, err := url.Parse(value); err == nil && parsed.Scheme != "" {
+		parsed.RawQuery, parsed.Fragment, parsed.User = "", "", nil
+		value = parsed.String()
+	} else if i := strings.IndexAny(value, "?#"); i >= 0 {
+		value = value[:i]
+	}
+	return truncateRunes(value, maxCSPFieldChars)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSPReports_ReportURI(t *testing.T) {
+	body := `{"csp-report": {
+		"document-uri": "https://realty.ec/propiedades/casa?token=secret#fotos",
+		"violated-directive": "script-src-elem 'self'",
+		"blocked-uri": "https://evil.example/x.js?id=1",
+		"source-file": "https://realty.ec/app.js",
+		"line-number": 12,
+		"disposition": "report"
+	}}`
+
+	reports, err := ParseCSPReports([]byte(body))
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, CSPReport{
+		DocumentURI:        "https://realty.ec/propiedades/casa",
+		EffectiveDirective: "script-src-elem",
+		BlockedURI:         "https://evil.example/x.js",
+		SourceFile:         "https://realty.ec/app.js",
+		LineNumber:         12,
+		Disposition:        CSPDispositionReport,
+	}, reports[0])
+
+	// The same violation from another page view has the same fingerprint
+	other, err := ParseCSPReports([]byte(`{"csp-report": {"document-uri": "https://realty.ec/propiedades/casa?token=other",
+		"effective-directive": "script-src-elem", "blocked-uri": "https://evil.example/x.js", "source-file": "https://realty.ec/app.js",
+		"line-number": 12, "disposition": "report"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, reports[0].Fingerprint(), other[0].Fingerprint())
+
+	_, err = ParseCSPReports([]byte(`{"report": {}}`))
+	assert.ErrorContains(t, err, "invalid CSP report")
+	_, err = ParseCSPReports([]byte(`not json`))
+	assert.ErrorContains(t, err, "invalid CSP report")
+}
+
+func TestParseCSPReports_ReportingAPI(t *testing.T) {
+	body := `[
+		{"type": "csp-violation", "body": {"documentURL": "https://realty.ec/", "effectiveDirective": "img-src",
+			"blockedURL": "data:image/png;base64,AAAA", "disposition": "enforce"}},
+		{"type": "deprecation", "body": {"id": "x"}},
+		{"type": "csp-violation", "body": {"documentURL": "https://realty.ec/", "effectiveDirective": "script-src",
+			"blockedURL": "inline", "lineNumber": "7"}},
+		{"type": "csp-violation", "body": {"documentURL": "https://realty.ec/"}}
+	]`
+
+	reports, err := ParseCSPReports([]byte(body))
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "data", reports[0].BlockedURI)
+	assert.Equal(t, CSPDispositionEnforce, reports[0].Disposition)
+	assert.Equal(t, "inline", reports[1].BlockedURI)
+	assert.Equal(t, 0, reports[1].LineNumber)
+	assert.Equal(t, CSPDispositionEnforce, reports[1].Disposition)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Currencies prices can be displayed in. Prices are stored in USD, Ecuador's currency;
+// the others are display conversions for foreign buyers.
+const (
+	CurrencyUSD = "USD"
+	CurrencyCOP = "COP"
+	CurrencyPEN = "PEN"
+	CurrencyEUR = "EUR"
+
+	BaseCurrency = CurrencyUSD
+)
+
+// currencyDecimals holds the decimals display prices are rounded to by currency
+var currencyDecimals = map[string]int{
+	CurrencyUSD: 2,
+	CurrencyCOP: 0,
+	CurrencyPEN: 2,
+	CurrencyEUR: 2,
+}
+
+// ExchangeRates holds the value of one unit of Base in other currencies
+type ExchangeRates struct {
+	Base      string             `json:"base"`
+	Rates     map[string]float64 `json:"rates"`
+	Date      time.Time          `json:"date"`
+	Provider  string             `json:"provider"`
+	FetchedAt time.Time          `json:"fetched_at"`
+}
+
+// Rate returns the exchange rate from Base to currency
+func (r *ExchangeRates) Rate(currency string) (float64, error) {
+	if currency == r.Base {
+		return 1, nil
+	}
+	rate, ok := r.Rates[currency]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", currency)
+	}
+	return rate, nil
+}
+
+// DisplayPrice is a price converted for display. It is informative only: stored prices,
+// contracts and payments stay in USD.
+type DisplayPrice struct {
+	Currency  string    `json:"currency"`
+	Amount    float64   `json:"amount"`
+	Rate      float64   `json:"rate"`
+	RatesDate time.Time `json:"rates_date"`
+}
+
+// IsSupportedCurrency verifies if prices can be displayed in a currency
+func IsSupportedCurrency(currency string) bool {
+	_, ok := currencyDecimals[currency]
+	return ok
+}
+
+// NormalizeCurrency returns the ISO 4217 code of a supported currency, or "" when the
+// currency is not supported
+func NormalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if IsSupportedCurrency(currency) {
+		return currency
+	}
+	return ""
+}
+
+// SupportedCurrencies returns the currencies prices can be displayed in
+func SupportedCurrencies() []string {
+	return []string{CurrencyUSD, CurrencyCOP, CurrencyPEN, CurrencyEUR}
+}
+
+// ConvertPrice converts a USD price into currency, rounded to the currency's decimals
+func ConvertPrice(price float64, currency string, rates *ExchangeRates) (*DisplayPrice, error) {
+	currency = NormalizeCurrency(currency)
+	if currency == "" {
+		return nil, fmt.Errorf("unsupported currency")
+	}
+	if rates == nil || rates.Base != BaseCurrency {
+		return nil, fmt.Errorf("exchange rates must be based on %s", BaseCurrency)
+	}
+
+	rate, err := rates.Rate(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := math.Pow(10, float64(currencyDecimals[currency]))
+	return &DisplayPrice{
+		Currency:  currency,
+		Amount:    math.Round(price*rate*scale) / scale,
+		Rate:      rate,
+		RatesDate: rates.Date,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertPrice(t *testing.T) {
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+	rates := &ExchangeRates{
+		Base:  CurrencyUSD,
+		Rates: map[string]float64{"COP": 4025.537, "PEN": 3.5512, "EUR": 0.8573},
+		Date:  date,
+	}
+
+	price, err := ConvertPrice(125000, "cop", rates)
+	require.NoError(t, err)
+	assert.Equal(t, CurrencyCOP, price.Currency)
+	assert.Equal(t, 503192125.0, price.Amount, "pesos are shown without decimals")
+	assert.Equal(t, date, price.RatesDate)
+
+	price, err = ConvertPrice(99.99, "PEN", rates)
+	require.NoError(t, err)
+	assert.Equal(t, 355.08, price.Amount)
+
+	price, err = ConvertPrice(1500.5, "USD", rates)
+	require.NoError(t, err)
+	assert.Equal(t, 1500.5, price.Amount)
+
+	_, err = ConvertPrice(100, "BRL", rates)
+	assert.Error(t, err)
+
+	delete(rates.Rates, "EUR")
+	_, err = ConvertPrice(100, "EUR", rates)
+	assert.ErrorContains(t, err, "no exchange rate")
+}
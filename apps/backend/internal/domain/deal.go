@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Deal types
+const (
+	DealTypeSale = "sale"
+	DealTypeRent = "rent"
+)
+
+// Deal records a closed sale or rental of a property and the commission it earned
+type Deal struct {
+	ID         string  `json:"id"`
+	PropertyID string  `json:"property_id"`
+	AgencyID   *string `json:"agency_id,omitempty"`
+	AgentID    *string `json:"agent_id,omitempty"`
+	// ClientID is the buyer or tenant, who can then review the agent as a verified client
+	ClientID          *string   `json:"client_id,omitempty"`
+	Type              string    `json:"type"`
+	FinalPrice        float64   `json:"final_price"`
+	ClosingDate       time.Time `json:"closing_date"`
+	CommissionPercent float64   `json:"commission_percent"`
+	CommissionAmount  float64   `json:"commission_amount"`
+	Notes             string    `json:"notes,omitempty"`
+	RecordedBy        string    `json:"recorded_by"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// NewDeal creates a deal and computes its commission amount
+func NewDeal(propertyID, dealType string, finalPrice, commissionPercent float64, closingDate time.Time) (*Deal, error) {
+	if propertyID == "" {
+		return nil, fmt.Errorf("property ID cannot be empty")
+	}
+	if dealType != DealTypeSale && dealType != DealTypeRent {
+		return nil, fmt.Errorf("deal type must be sale or rent")
+	}
+	if finalPrice <= 0 {
+		return nil, fmt.Errorf("final price must be positive")
+	}
+	if commissionPercent < 0 || commissionPercent > 100 {
+		return nil, fmt.Errorf("commission must be between 0 and 100")
+	}
+	if closingDate.IsZero() {
+		closingDate = time.Now()
+	}
+	if closingDate.After(time.Now().Add(24 * time.Hour)) {
+		return nil, fmt.Errorf("closing date cannot be in the future")
+	}
+
+	return &Deal{
+		ID:                uuid.New().String(),
+		PropertyID:        propertyID,
+		Type:              dealType,
+		FinalPrice:        finalPrice,
+		ClosingDate:       closingDate,
+		CommissionPercent: commissionPercent,
+		CommissionAmount:  CalculateCommission(finalPrice, commissionPercent),
+		CreatedAt:         time.Now(),
+	}, nil
+}
+
+// CalculateCommission returns the commission of a price rounded to cents
+func CalculateCommission(price, percent float64) float64 {
+	return math.Round(price*percent) / 100
+}
+
+// PropertyStatus returns the listing status a property takes when the deal closes
+func (d *Deal) PropertyStatus() string {
+	if d.Type == DealTypeRent {
+		return StatusRented
+	}
+	return StatusSold
+}
+
+// DealFilter filters deal listings
+type DealFilter struct {
+	AgencyID   string
+	AgentID    string
+	ClientID   string
+	PropertyID string
+	Type       string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// AgentCommission is the commission earned by an agent in a period
+type AgentCommission struct {
+	AgentID          string  `json:"agent_id"`
+	AgentName        string  `json:"agent_name"`
+	Deals            int     `json:"deals"`
+	SalesValue       float64 `json:"sales_value"`
+	RentValue        float64 `json:"rent_value"`
+	CommissionAmount float64 `json:"commission_amount"`
+}
+
+// DealSummary aggregates the deals of an agency in a period
+type DealSummary struct {
+	AgencyID        string            `json:"agency_id"`
+	From            *time.Time        `json:"from,omitempty"`
+	To              *time.Time        `json:"to,omitempty"`
+	TotalDeals      int               `json:"total_deals"`
+	Sales           int               `json:"sales"`
+	Rentals         int               `json:"rentals"`
+	TotalSalesValue float64           `json:"total_sales_value"`
+	TotalRentValue  float64           `json:"total_rent_value"`
+	TotalCommission float64           `json:"total_commission"`
+	ByAgent         []AgentCommission `json:"by_agent"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeal(t *testing.T) {
+	closing := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	deal, err := NewDeal("prop-1", DealTypeSale, 185000, 3, closing)
+	require.NoError(t, err)
+	assert.NotEmpty(t, deal.ID)
+	assert.Equal(t, 5550.0, deal.CommissionAmount)
+	assert.Equal(t, closing, deal.ClosingDate)
+	assert.Equal(t, StatusSold, deal.PropertyStatus())
+
+	rental, err := NewDeal("prop-2", DealTypeRent, 850, 50, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 425.0, rental.CommissionAmount)
+	assert.False(t, rental.ClosingDate.IsZero())
+	assert.Equal(t, StatusRented, rental.PropertyStatus())
+
+	tests := []struct {
+		name     string
+		dealType string
+		price    float64
+		percent  float64
+		closing  time.Time
+		errText  string
+	}{
+		{"invalid type", "lease", 1000, 3, closing, "sale or rent"},
+		{"zero price", DealTypeSale, 0, 3, closing, "final price"},
+		{"negative commission", DealTypeSale, 1000, -1, closing, "commission"},
+		{"commission above 100", DealTypeSale, 1000, 101, closing, "commission"},
+		{"future closing", DealTypeSale, 1000, 3, time.Now().AddDate(0, 0, 3), "future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDeal("prop-1", tt.dealType, tt.price, tt.percent, tt.closing)
+			assert.ErrorContains(t, err, tt.errText)
+		})
+	}
+}
+
+func TestCalculateCommission(t *testing.T) {
+	assert.Equal(t, 3000.0, CalculateCommission(100000, 3))
+	assert.Equal(t, 3703.7, CalculateCommission(123456.78, 3))
+	assert.Equal(t, 0.0, CalculateCommission(100000, 0))
+}
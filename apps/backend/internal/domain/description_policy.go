@@ -0,0 +1,340 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Description policy actions. Each deployment picks one for contact information and
+// one for profanity.
+const (
+	DescriptionPolicyOff       = "off"
+	DescriptionPolicyFlag      = "flag"      // kept as written and queued for review
+	DescriptionPolicyObfuscate = "obfuscate" // masked and queued for review
+	DescriptionPolicyReject    = "reject"    // the listing is not saved
+)
+
+// Description finding kinds
+const (
+	DescriptionFindingPhone     = "phone"
+	DescriptionFindingEmail     = "email"
+	DescriptionFindingWhatsApp  = "whatsapp"
+	DescriptionFindingProfanity = "profanity"
+)
+
+// Description review statuses. Administrators uphold the policy, masking what it found,
+// or override it, restoring the description as written and exempting the listing from
+// the policy.
+const (
+	DescriptionReviewPending    = "pending"
+	DescriptionReviewUpheld     = "upheld"
+	DescriptionReviewOverridden = "overridden"
+)
+
+// ContactInfoMask replaces the contact information of masked descriptions
+const ContactInfoMask = "[contacto oculto]"
+
+// MaxDescriptionFindings bounds the findings kept of a description
+const MaxDescriptionFindings = 20
+
+// DefaultProfanityWords lists common Spanish profanity, matched as whole words without
+// regard to case or accents
+var DefaultProfanityWords = []string{
+	"cabron", "cabrona", "carajo", "chucha", "cojudo", "gonorrea", "hijueputa",
+	"huevon", "joder", "malparido", "marica", "maricon", "mierda", "pendejo", "pendeja",
+	"puta", "puto", "verga",
+}
+
+// Contact information patterns. Addresses spelled out to dodge filters, such as
+// "ventas arroba realty punto ec", are emails too.
+var (
+	descriptionPhonePattern = regexp.MustCompile(`(?i)(?:tel:)?(?:\+\s?)?\(?\d(?:[\s.\-()]{0,2}\d){6,14}`)
+	descriptionEmailPattern = regexp.MustCompile(`(?i)(?:mailto:)?[a-z0-9._%+\-]+` +
+		`(?:\s*@\s*|\s*[(\[]\s*(?:arroba|at)\s*[)\]]\s*|\s+arroba\s+)` +
+		`[a-z0-9\-]+(?:(?:\.|\s*[(\[]\s*(?:punto|dot)\s*[)\]]\s*|\s+punto\s+)[a-z0-9\-]+)*` +
+		`(?:\.|\s*[(\[]\s*(?:punto|dot)\s*[)\]]\s*|\s+punto\s+)[a-z]{2,}`)
+	descriptionWhatsAppPattern = regexp.MustCompile(`(?i)(?:https?://)?(?:wa\.me|api\.whatsapp\.com|chat\.whatsapp\.com|(?:www\.)?whatsapp\.com)(?:/[^\s"'<>]*)?`)
+	descriptionWordPattern     = regexp.MustCompile(`[\p{L}\p{N}]+`)
+)
+
+// DescriptionPolicy is what a deployment does with contact information and profanity in
+// listing descriptions. Marketplaces forbid contact information so leads stay in the
+// platform.
+type DescriptionPolicy struct {
+	ContactInfo    string // action on phones, emails and WhatsApp links
+	Profanity      string // action on ProfanityWords
+	ProfanityWords []string
+}
+
+// DefaultDescriptionPolicy masks contact information and flags profanity
+func DefaultDescriptionPolicy() DescriptionPolicy {
+	return DescriptionPolicy{
+		ContactInfo:    DescriptionPolicyObfuscate,
+		Profanity:      DescriptionPolicyFlag,
+		ProfanityWords: append([]string{}, DefaultProfanityWords...),
+	}
+}
+
+// Validate checks the policy actions
+func (p DescriptionPolicy) Validate() error {
+	if !IsValidDescriptionPolicyAction(p.ContactInfo) {
+		return fmt.Errorf("invalid contact info policy: %s", p.ContactInfo)
+	}
+	if !IsValidDescriptionPolicyAction(p.Profanity) {
+		return fmt.Errorf("invalid profanity policy: %s", p.Profanity)
+	}
+	return nil
+}
+
+// DescriptionFinding is contact information or profanity found in a description
+type DescriptionFinding struct {
+	Kind   string `json:"kind"`
+	Match  string `json:"match"`
+	Action string `json:"action"`
+}
+
+// descriptionMatch is a finding with its position in the description
+type descriptionMatch struct {
+	DescriptionFinding
+	start, end int
+}
+
+// Apply enforces the policy on a description, returning it with the obfuscated findings
+// masked, and the findings. A finding whose action is reject is an error.
+func (p DescriptionPolicy) Apply(text string) (string, []DescriptionFinding, error) {
+	matches := p.find(text, false)
+
+	rejected := []string{}
+	for _, match := range matches {
+		if match.Action == DescriptionPolicyReject && !containsString(rejected, match.Kind) {
+			rejected = append(rejected, match.Kind)
+		}
+	}
+	if len(rejected) > 0 {
+		return "", nil, fmt.Errorf("invalid description: %s not allowed in listing descriptions",
+			strings.Join(rejected, ", "))
+	}
+
+	masked := maskDescription(text, matches, func(match descriptionMatch) bool {
+		return match.Action == DescriptionPolicyObfuscate
+	})
+	return masked, descriptionFindings(matches), nil
+}
+
+// Mask masks everything the policy finds, whatever its action, as when administrators
+// uphold a review of a flagged description
+func (p DescriptionPolicy) Mask(text string) string {
+	return maskDescription(text, p.find(text, true), func(descriptionMatch) bool { return true })
+}
+
+// find returns the findings of a description in order, without overlaps. Kinds whose
+// action is off are skipped unless all is set.
+func (p DescriptionPolicy) find(text string, all bool) []descriptionMatch {
+	matches := []descriptionMatch{}
+	add := func(kind, action string, start, end int) {
+		for _, match := range matches {
+			if start < match.end && end > match.start {
+				return
+			}
+		}
+		matches = append(matches, descriptionMatch{
+			DescriptionFinding: DescriptionFinding{Kind: kind, Match: strings.TrimSpace(text[start:end]), Action: action},
+			start:              start,
+			end:                end,
+		})
+	}
+
+	if p.ContactInfo != DescriptionPolicyOff || all {
+		// WhatsApp links hold phone numbers, so they are found first
+		for _, loc := range descriptionWhatsAppPattern.FindAllStringIndex(text, -1) {
+			add(DescriptionFindingWhatsApp, p.ContactInfo, loc[0], loc[1])
+		}
+		for _, loc := range descriptionEmailPattern.FindAllStringIndex(text, -1) {
+			add(DescriptionFindingEmail, p.ContactInfo, loc[0], loc[1])
+		}
+		for _, loc := range descriptionPhonePattern.FindAllStringIndex(text, -1) {
+			if strings.HasSuffix(strings.TrimRight(text[:loc[0]], " "), "$") {
+				continue // prices
+			}
+			if end := phoneEnd(text[loc[0]:loc[1]]); end > 0 {
+				add(DescriptionFindingPhone, p.ContactInfo, loc[0], loc[0]+end)
+			}
+		}
+	}
+
+	if (p.Profanity != DescriptionPolicyOff || all) && len(p.ProfanityWords) > 0 {
+		words := make(map[string]bool, len(p.ProfanityWords))
+		for _, word := range p.ProfanityWords {
+			words[foldWord(word)] = true
+		}
+		for _, loc := range descriptionWordPattern.FindAllStringIndex(text, -1) {
+			if words[foldWord(text[loc[0]:loc[1]])] {
+				add(DescriptionFindingProfanity, p.Profanity, loc[0], loc[1])
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+	return matches
+}
+
+// phoneEnd returns the length of the Ecuadorian or international phone number at the
+// start of a candidate, or 0 when it is none, e.g. a price or an area. Candidates may
+// run into a following number, so mobile numbers are matched by prefix.
+func phoneEnd(candidate string) int {
+	digits := 0
+	ends := []int{} // byte length after each digit
+	for i := 0; i < len(candidate); i++ {
+		if candidate[i] >= '0' && candidate[i] <= '9' {
+			digits++
+			ends = append(ends, i+1)
+		}
+	}
+	number := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, candidate)
+
+	length := 0
+	switch {
+	case strings.HasPrefix(number, "5939") && digits >= 12:
+		length = 12 // +593 9X XXX XXXX
+	case strings.HasPrefix(number, "593") && digits == 11:
+		length = 11 // +593 2 XXX XXXX
+	case strings.HasPrefix(number, "09") && digits >= 10:
+		length = 10 // 09X XXX XXXX
+	case len(number) == 9 && number[0] == '0' && number[1] >= '2' && number[1] <= '7':
+		length = 9 // 0X XXX XXXX
+	case strings.Contains(candidate[:strings.IndexAny(candidate, "0123456789")], "+") && digits >= 10 && digits <= 13:
+		length = digits
+	}
+	if length == 0 {
+		return 0
+	}
+	return ends[length-1]
+}
+
+// maskDescription replaces the matches selected by mask
+func maskDescription(text string, matches []descriptionMatch, mask func(descriptionMatch) bool) string {
+	var out strings.Builder
+	last := 0
+	for _, match := range matches {
+		if !mask(match) {
+			continue
+		}
+		out.WriteString(text[last:match.start])
+		if match.Kind == DescriptionFindingProfanity {
+			first, size := utf8.DecodeRuneInString(match.Match)
+			out.WriteRune(first)
+			out.WriteString(strings.Repeat("*", utf8.RuneCountInString(match.Match[size:])))
+		} else {
+			out.WriteString(ContactInfoMask)
+		}
+		last = match.end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// descriptionFindings returns the distinct findings of matches, at most
+// MaxDescriptionFindings
+func descriptionFindings(matches []descriptionMatch) []DescriptionFinding {
+	findings := []DescriptionFinding{}
+	seen := map[DescriptionFinding]bool{}
+	for _, match := range matches {
+		if seen[match.DescriptionFinding] {
+			continue
+		}
+		seen[match.DescriptionFinding] = true
+		findings = append(findings, match.DescriptionFinding)
+		if len(findings) == MaxDescriptionFindings {
+			break
+		}
+	}
+	return findings
+}
+
+// foldWord lowercases a word and drops its accents
+func foldWord(word string) string {
+	return accentFolder.Replace(strings.ToLower(strings.TrimSpace(word)))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidDescriptionPolicyAction verifies if an action is a description policy action
+func IsValidDescriptionPolicyAction(action string) bool {
+	switch action {
+	case DescriptionPolicyOff, DescriptionPolicyFlag, DescriptionPolicyObfuscate, DescriptionPolicyReject:
+		return true
+	}
+	return false
+}
+
+// DescriptionReview is a listing description the policy flagged or masked, awaiting an
+// administrator. A listing has at most one review, for its latest description.
+type DescriptionReview struct {
+	PropertyID string               `json:"property_id"`
+	Findings   []DescriptionFinding `json:"findings"`
+	// Original is the description as written, before masking
+	Original   string     `json:"original"`
+	Status     string     `json:"status"`
+	ReviewedBy *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// DescriptionReviewFilter selects description reviews
+type DescriptionReviewFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// NewDescriptionReview queues a description for review
+func NewDescriptionReview(propertyID, original string, findings []DescriptionFinding, at time.Time) *DescriptionReview {
+	return &DescriptionReview{
+		PropertyID: propertyID,
+		Findings:   findings,
+		Original:   original,
+		Status:     DescriptionReviewPending,
+		CreatedAt:  at,
+	}
+}
+
+// Decide upholds or overrides the policy on a pending review
+func (r *DescriptionReview) Decide(override bool, reviewerID string, at time.Time) error {
+	if r.Status != DescriptionReviewPending {
+		return fmt.Errorf("invalid description review: already %s", r.Status)
+	}
+
+	r.Status = DescriptionReviewUpheld
+	if override {
+		r.Status = DescriptionReviewOverridden
+	}
+	r.ReviewedBy = &reviewerID
+	r.ReviewedAt = &at
+	return nil
+}
+
+// Exempt reports whether an administrator exempted the listing from the policy
+func (r *DescriptionReview) Exempt() bool {
+	return r != nil && r.Status == DescriptionReviewOverridden
+}
+
+// IsValidDescriptionReviewStatus verifies if a status is a description review status
+func IsValidDescriptionReviewStatus(status string) bool {
+	return status == DescriptionReviewPending || status == DescriptionReviewUpheld || status == DescriptionReviewOverridden
+}
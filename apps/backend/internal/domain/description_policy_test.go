@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionPolicy_ApplyContactInfo(t *testing.T) {
+	policy := DefaultDescriptionPolicy()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		kinds    []string
+	}{
+		{"mobile", "Llame al 099 123 4567 para visitas", "Llame al [contacto oculto] para visitas", []string{DescriptionFindingPhone}},
+		{"mobile with dashes", "Cel: 099-123-4567.", "Cel: [contacto oculto].", []string{DescriptionFindingPhone}},
+		{"international", "WhatsApp +593 99 123 4567", "WhatsApp [contacto oculto]", []string{DescriptionFindingPhone}},
+		{"landline", "Oficina (02) 234-5678", "Oficina [contacto oculto]", []string{DescriptionFindingPhone}},
+		{"followed by a number", "Cel 0991234567 3 dormitorios", "Cel [contacto oculto] 3 dormitorios", []string{DescriptionFindingPhone}},
+		{"email", "Escriba a ventas@realty.ec hoy", "Escriba a [contacto oculto] hoy", []string{DescriptionFindingEmail}},
+		{"spelled out email", "ventas arroba realty punto ec", "[contacto oculto]", []string{DescriptionFindingEmail}},
+		{"whatsapp link", "Chat: https://wa.me/593991234567?text=hola", "Chat: [contacto oculto]", []string{DescriptionFindingWhatsApp}},
+		{"links in html", `<a href="mailto:ventas@realty.ec">correo</a> <a href="tel:0991234567">llamar</a>`,
+			`<a href="[contacto oculto]">correo</a> <a href="[contacto oculto]">llamar</a>`,
+			[]string{DescriptionFindingEmail, DescriptionFindingPhone}},
+		{"prices and areas", "Precio $ 1.250.000, 320 m2, construida en 2015, 3 dormitorios", "", nil},
+		{"large price", "Precio: 1250000 negociable", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, findings, err := policy.Apply(tt.text)
+			require.NoError(t, err)
+
+			if tt.expected == "" {
+				assert.Equal(t, tt.text, masked)
+				assert.Empty(t, findings)
+				return
+			}
+			assert.Equal(t, tt.expected, masked)
+			kinds := []string{}
+			for _, finding := range findings {
+				kinds = append(kinds, finding.Kind)
+				assert.Equal(t, DescriptionPolicyObfuscate, finding.Action)
+			}
+			assert.Equal(t, tt.kinds, kinds)
+		})
+	}
+}
+
+func TestDescriptionPolicy_Actions(t *testing.T) {
+	text := "Casa de mierda? No: casa linda. Llame al 0991234567"
+
+	policy := DescriptionPolicy{ContactInfo: DescriptionPolicyFlag, Profanity: DescriptionPolicyObfuscate, ProfanityWords: DefaultProfanityWords}
+	masked, findings, err := policy.Apply(text)
+	require.NoError(t, err)
+	assert.Equal(t, "Casa de m*****? No: casa linda. Llame al 0991234567", masked)
+	assert.Equal(t, []DescriptionFinding{
+		{Kind: DescriptionFindingProfanity, Match: "mierda", Action: DescriptionPolicyObfuscate},
+		{Kind: DescriptionFindingPhone, Match: "0991234567", Action: DescriptionPolicyFlag},
+	}, findings)
+
+	// Upholding a review masks the flagged findings too
+	assert.Equal(t, "Casa de m*****? No: casa linda. Llame al [contacto oculto]", policy.Mask(text))
+
+	policy.ContactInfo = DescriptionPolicyReject
+	_, _, err = policy.Apply(text)
+	assert.EqualError(t, err, "invalid description: phone not allowed in listing descriptions")
+
+	policy = DescriptionPolicy{ContactInfo: DescriptionPolicyOff, Profanity: DescriptionPolicyOff, ProfanityWords: DefaultProfanityWords}
+	masked, findings, err = policy.Apply(text)
+	require.NoError(t, err)
+	assert.Equal(t, text, masked)
+	assert.Empty(t, findings)
+}
+
+func TestDescriptionPolicy_ProfanityWholeWords(t *testing.T) {
+	policy := DescriptionPolicy{ContactInfo: DescriptionPolicyOff, Profanity: DescriptionPolicyObfuscate, ProfanityWords: []string{"cabrón"}}
+
+	masked, findings, err := policy.Apply("CABRON, Cabrón y cabronazo")
+	require.NoError(t, err)
+	assert.Equal(t, "C*****, C***** y cabronazo", masked)
+	assert.Len(t, findings, 2)
+}
+
+func TestDescriptionPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultDescriptionPolicy().Validate())
+	assert.ErrorContains(t, DescriptionPolicy{ContactInfo: "hide", Profanity: DescriptionPolicyOff}.Validate(), "invalid contact info policy")
+	assert.ErrorContains(t, DescriptionPolicy{ContactInfo: DescriptionPolicyOff}.Validate(), "invalid profanity policy")
+}
+
+func TestDescriptionReview_Decide(t *testing.T) {
+	now := time.Now()
+	review := NewDescriptionReview("property-1", "Llame al 0991234567", nil, now)
+	assert.False(t, review.Exempt())
+
+	require.NoError(t, review.Decide(true, "admin-1", now))
+	assert.Equal(t, DescriptionReviewOverridden, review.Status)
+	assert.True(t, review.Exempt())
+	assert.ErrorContains(t, review.Decide(false, "admin-1", now), "already overridden")
+}
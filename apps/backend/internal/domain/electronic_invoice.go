@@ -0,0 +1,539 @@
+package domain
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources of electronic invoices: the fees agencies pay to the platform
+const (
+	InvoiceSourceSubscription = "subscription"
+	InvoiceSourceFeatured     = "featured"
+)
+
+// Electronic invoice statuses. Pending invoices have not reached the SRI yet; received
+// ones wait for its authorization.
+const (
+	InvoicePending    = "pending"
+	InvoiceReceived   = "received"
+	InvoiceAuthorized = "authorized"
+	InvoiceRejected   = "rejected"
+)
+
+// SRI environments
+const (
+	SRIEnvironmentTest       = "1"
+	SRIEnvironmentProduction = "2"
+)
+
+// MaxInvoiceAttempts bounds how many times an invoice is submitted or checked before
+// it is left for an administrator
+const MaxInvoiceAttempts = 20
+
+// SRI catalog codes used by the invoices of the platform
+const (
+	sriInvoiceDocument        = "01" // factura
+	sriNormalEmission         = "1"
+	sriBuyerRUC               = "04"
+	sriTaxIVA                 = "2"
+	sriPaymentFinancialSystem = "20" // otros con utilización del sistema financiero
+	sriInvoiceSchemaVersion   = "1.1.0"
+	sriInvoiceDateLayout      = "02/01/2006"
+	sriAccessKeyDateLayout    = "02012006"
+	sriAccessKeyLength        = 49
+	sriMaxSequential          = 999999999
+	sriMaxTextLength          = 300
+)
+
+// ivaRateCodes maps the IVA rates to their SRI percentage codes
+var ivaRateCodes = map[float64]string{0: "0", 5: "5", 12: "2", 14: "3", 15: "4"}
+
+// ecuadorTime is the mainland Ecuador time zone, which has no daylight saving time
+var ecuadorTime = time.FixedZone("ECT", -5*60*60)
+
+var (
+	rucPattern        = regexp.MustCompile(`^[0-9]{13}$`)
+	emissionPattern   = regexp.MustCompile(`^[0-9]{3}$`)
+	invoiceCodeFilter = regexp.MustCompile(`[^A-Za-z0-9-]`)
+)
+
+// InvoiceIssuer is the taxpayer issuing the invoices of the platform
+type InvoiceIssuer struct {
+	RUC                string
+	BusinessName       string
+	TradeName          string
+	Address            string
+	Establishment      string
+	EmissionPoint      string
+	Environment        string
+	RequiredAccounting bool
+	IVARate            float64
+}
+
+// Validate checks the issuer against the SRI formats
+func (i InvoiceIssuer) Validate() error {
+	if !rucPattern.MatchString(i.RUC) {
+		return fmt.Errorf("invalid issuer: RUC must be 13 digits")
+	}
+	if strings.TrimSpace(i.BusinessName) == "" || strings.TrimSpace(i.Address) == "" {
+		return fmt.Errorf("invalid issuer: business name and address are required")
+	}
+	if !emissionPattern.MatchString(i.Establishment) || !emissionPattern.MatchString(i.EmissionPoint) {
+		return fmt.Errorf("invalid issuer: establishment and emission point must be 3 digits")
+	}
+	if i.Environment != SRIEnvironmentTest && i.Environment != SRIEnvironmentProduction {
+		return fmt.Errorf("invalid issuer: environment must be 1 (test) or 2 (production)")
+	}
+	if _, ok := ivaRateCodes[i.IVARate]; !ok {
+		return fmt.Errorf("invalid issuer: unsupported IVA rate %v", i.IVARate)
+	}
+	return nil
+}
+
+// InvoiceBuyer identifies the agency an invoice is issued to
+type InvoiceBuyer struct {
+	IDType  string `json:"id_type"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// InvoiceBuyerFromAgency identifies an agency by its RUC
+func InvoiceBuyerFromAgency(agency *Agency) (InvoiceBuyer, error) {
+	ruc := strings.TrimSpace(agency.RUC)
+	if !rucPattern.MatchString(ruc) {
+		return InvoiceBuyer{}, fmt.Errorf("invalid buyer: agency %s has no valid RUC", agency.ID)
+	}
+	return InvoiceBuyer{
+		IDType:  sriBuyerRUC,
+		ID:      ruc,
+		Name:    strings.TrimSpace(agency.Name),
+		Email:   agency.Email,
+		Address: strings.TrimSpace(strings.Join(nonEmpty(agency.Address, agency.City), ", ")),
+	}, nil
+}
+
+// InvoiceLine is an item of an invoice, priced before IVA
+type InvoiceLine struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Subtotal    float64 `json:"subtotal"`
+}
+
+// ElectronicInvoice is an SRI invoice for a fee paid by an agency
+type ElectronicInvoice struct {
+	ID            string        `json:"id"`
+	AgencyID      string        `json:"agency_id"`
+	Source        string        `json:"source"`
+	SourceRef     string        `json:"source_ref,omitempty"`
+	Establishment string        `json:"establishment"`
+	EmissionPoint string        `json:"emission_point"`
+	Sequential    int           `json:"sequential"`
+	AccessKey     string        `json:"access_key"`
+	Environment   string        `json:"environment"`
+	IssueDate     time.Time     `json:"issue_date"`
+	Buyer         InvoiceBuyer  `json:"buyer"`
+	Lines         []InvoiceLine `json:"lines"`
+	Subtotal      float64       `json:"subtotal"`
+	IVARate       float64       `json:"iva_rate"`
+	IVA           float64       `json:"iva"`
+	Total         float64       `json:"total"`
+	Status        string        `json:"status"`
+	// AuthorizationNumber and AuthorizedAt are set once the SRI authorizes the invoice
+	AuthorizationNumber string     `json:"authorization_number,omitempty"`
+	AuthorizedAt        *time.Time `json:"authorized_at,omitempty"`
+	Messages            []string   `json:"messages,omitempty"`
+	XML                 string     `json:"-"`
+	AuthorizedXML       string     `json:"-"`
+	Attempts            int        `json:"attempts"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// NewElectronicInvoice creates a pending invoice with its access key and XML document.
+// sequential is the next number of the issuer's emission point.
+func NewElectronicInvoice(issuer InvoiceIssuer, agencyID, source, sourceRef string, buyer InvoiceBuyer, lines []InvoiceLine, sequential int, now time.Time) (*ElectronicInvoice, error) {
+	if source != InvoiceSourceSubscription && source != InvoiceSourceFeatured {
+		return nil, fmt.Errorf("invalid invoice source: %s", source)
+	}
+	if sequential < 1 || sequential > sriMaxSequential {
+		return nil, fmt.Errorf("invalid invoice sequential: %d", sequential)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("invalid invoice: at least one line is required")
+	}
+
+	invoice := &ElectronicInvoice{
+		ID:            uuid.New().String(),
+		AgencyID:      agencyID,
+		Source:        source,
+		SourceRef:     sourceRef,
+		Establishment: issuer.Establishment,
+		EmissionPoint: issuer.EmissionPoint,
+		Sequential:    sequential,
+		Environment:   issuer.Environment,
+		IssueDate:     CalendarDate(now.In(ecuadorTime)),
+		Buyer:         buyer,
+		Lines:         make([]InvoiceLine, 0, len(lines)),
+		IVARate:       issuer.IVARate,
+		Status:        InvoicePending,
+		Messages:      []string{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	for _, line := range lines {
+		line.Code = invoiceCodeFilter.ReplaceAllString(line.Code, "")
+		line.Description = truncateRunes(strings.TrimSpace(line.Description), sriMaxTextLength)
+		if line.Code == "" || line.Description == "" {
+			return nil, fmt.Errorf("invalid invoice line: code and description are required")
+		}
+		if line.Quantity <= 0 || line.UnitPrice <= 0 {
+			return nil, fmt.Errorf("invalid invoice line %s: quantity and price must be positive", line.Code)
+		}
+		line.Subtotal = roundCents(line.Quantity * line.UnitPrice)
+		invoice.Lines = append(invoice.Lines, line)
+		invoice.Subtotal += line.Subtotal
+	}
+	invoice.Subtotal = roundCents(invoice.Subtotal)
+	invoice.IVA = roundCents(invoice.Subtotal * issuer.IVARate / 100)
+	invoice.Total = roundCents(invoice.Subtotal + invoice.IVA)
+
+	invoice.AccessKey = AccessKey(invoice.IssueDate, sriInvoiceDocument, issuer.RUC, issuer.Environment,
+		issuer.Establishment+issuer.EmissionPoint, sequential, numericCode(invoice.ID))
+
+	document, err := BuildInvoiceXML(issuer, invoice)
+	if err != nil {
+		return nil, err
+	}
+	invoice.XML = string(document)
+	return invoice, nil
+}
+
+// Number returns the printed number of the invoice, e.g. 001-001-000000123
+func (i *ElectronicInvoice) Number() string {
+	return fmt.Sprintf("%s-%s-%09d", i.Establishment, i.EmissionPoint, i.Sequential)
+}
+
+// IsFinal reports whether the SRI authorized or rejected the invoice
+func (i *ElectronicInvoice) IsFinal() bool {
+	return i.Status == InvoiceAuthorized || i.Status == InvoiceRejected
+}
+
+// RecordReception records the answer of the SRI reception service. Returned documents
+// are rejected.
+func (i *ElectronicInvoice) RecordReception(received bool, messages []string, now time.Time) {
+	i.Attempts++
+	i.Messages = append([]string{}, messages...)
+	i.Status = InvoiceReceived
+	if !received {
+		i.Status = InvoiceRejected
+	}
+	i.UpdatedAt = now
+}
+
+// RecordAuthorization records an authorization or a rejection of the SRI. The invoice
+// stays received while the SRI is processing it.
+func (i *ElectronicInvoice) RecordAuthorization(authorized, rejected bool, number string, authorizedAt time.Time, document []byte, messages []string, now time.Time) {
+	i.Attempts++
+	i.Messages = append([]string{}, messages...)
+	switch {
+	case authorized:
+		if authorizedAt.IsZero() {
+			authorizedAt = now
+		}
+		if number == "" {
+			number = i.AccessKey
+		}
+		i.Status = InvoiceAuthorized
+		i.AuthorizationNumber = number
+		i.AuthorizedAt = &authorizedAt
+		i.AuthorizedXML = string(document)
+	case rejected:
+		i.Status = InvoiceRejected
+	}
+	i.UpdatedAt = now
+}
+
+// Document returns the authorized XML of the invoice, or the generated one until the
+// SRI returns it
+func (i *ElectronicInvoice) Document() string {
+	if i.AuthorizedXML != "" {
+		return i.AuthorizedXML
+	}
+	return i.XML
+}
+
+// AccessKey builds the 49-digit SRI access key (clave de acceso) of a document: its
+// issue date, type, issuer RUC, environment, series, sequential, numeric code and
+// emission type followed by a modulo 11 check digit
+func AccessKey(issueDate time.Time, documentType, ruc, environment, series string, sequential, code int) string {
+	key := fmt.Sprintf("%s%s%s%s%s%09d%08d%s",
+		issueDate.Format(sriAccessKeyDateLayout), documentType, ruc, environment, series, sequential, code, sriNormalEmission)
+	return key + AccessKeyCheckDigit(key)
+}
+
+// AccessKeyCheckDigit computes the modulo 11 check digit of the first 48 digits of an
+// access key, weighting them from 2 to 7 from right to left
+func AccessKeyCheckDigit(digits string) string {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 7 {
+			weight = 2
+		}
+	}
+	check := 11 - sum%11
+	switch check {
+	case 11:
+		check = 0
+	case 10:
+		check = 1
+	}
+	return fmt.Sprint(check)
+}
+
+// IsValidAccessKey checks the length and check digit of an access key
+func IsValidAccessKey(key string) bool {
+	if len(key) != sriAccessKeyLength {
+		return false
+	}
+	for _, c := range key {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return AccessKeyCheckDigit(key[:sriAccessKeyLength-1]) == key[sriAccessKeyLength-1:]
+}
+
+// numericCode derives the 8-digit numeric code of an access key from an invoice ID
+func numericCode(id string) int {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return int(h.Sum32() % 100000000)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// The factura document of the SRI electronic invoicing schema, version 1.1.0
+
+type sriInvoice struct {
+	XMLName        xml.Name           `xml:"factura"`
+	ID             string             `xml:"id,attr"`
+	Version        string             `xml:"version,attr"`
+	InfoTributaria sriTaxInfo         `xml:"infoTributaria"`
+	InfoFactura    sriInvoiceInfo     `xml:"infoFactura"`
+	Detalles       []sriInvoiceDetail `xml:"detalles>detalle"`
+	InfoAdicional  []sriAdditional    `xml:"infoAdicional>campoAdicional,omitempty"`
+}
+
+type sriTaxInfo struct {
+	Ambiente        string `xml:"ambiente"`
+	TipoEmision     string `xml:"tipoEmision"`
+	RazonSocial     string `xml:"razonSocial"`
+	NombreComercial string `xml:"nombreComercial,omitempty"`
+	RUC             string `xml:"ruc"`
+	ClaveAcceso     string `xml:"claveAcceso"`
+	CodDoc          string `xml:"codDoc"`
+	Estab           string `xml:"estab"`
+	PtoEmi          string `xml:"ptoEmi"`
+	Secuencial      string `xml:"secuencial"`
+	DirMatriz       string `xml:"dirMatriz"`
+}
+
+type sriInvoiceInfo struct {
+	FechaEmision                string        `xml:"fechaEmision"`
+	DirEstablecimiento          string        `xml:"dirEstablecimiento"`
+	ObligadoContabilidad        string        `xml:"obligadoContabilidad"`
+	TipoIdentificacionComprador string        `xml:"tipoIdentificacionComprador"`
+	RazonSocialComprador        string        `xml:"razonSocialComprador"`
+	IdentificacionComprador     string        `xml:"identificacionComprador"`
+	DireccionComprador          string        `xml:"direccionComprador,omitempty"`
+	TotalSinImpuestos           string        `xml:"totalSinImpuestos"`
+	TotalDescuento              string        `xml:"totalDescuento"`
+	TotalConImpuestos           []sriTotalTax `xml:"totalConImpuestos>totalImpuesto"`
+	Propina                     string        `xml:"propina"`
+	ImporteTotal                string        `xml:"importeTotal"`
+	Moneda                      string        `xml:"moneda"`
+	Pagos                       []sriPayment  `xml:"pagos>pago"`
+}
+
+type sriTotalTax struct {
+	Codigo           string `xml:"codigo"`
+	CodigoPorcentaje string `xml:"codigoPorcentaje"`
+	BaseImponible    string `xml:"baseImponible"`
+	Valor            string `xml:"valor"`
+}
+
+type sriPayment struct {
+	FormaPago string `xml:"formaPago"`
+	Total     string `xml:"total"`
+}
+
+type sriInvoiceDetail struct {
+	CodigoPrincipal        string   `xml:"codigoPrincipal"`
+	Descripcion            string   `xml:"descripcion"`
+	Cantidad               string   `xml:"cantidad"`
+	PrecioUnitario         string   `xml:"precioUnitario"`
+	Descuento              string   `xml:"descuento"`
+	PrecioTotalSinImpuesto string   `xml:"precioTotalSinImpuesto"`
+	Impuestos              []sriTax `xml:"impuestos>impuesto"`
+}
+
+type sriTax struct {
+	Codigo           string `xml:"codigo"`
+	CodigoPorcentaje string `xml:"codigoPorcentaje"`
+	Tarifa           string `xml:"tarifa"`
+	BaseImponible    string `xml:"baseImponible"`
+	Valor            string `xml:"valor"`
+}
+
+type sriAdditional struct {
+	Nombre string `xml:"nombre,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// BuildInvoiceXML renders the unsigned SRI factura document of an invoice
+func BuildInvoiceXML(issuer InvoiceIssuer, invoice *ElectronicInvoice) ([]byte, error) {
+	rateCode, ok := ivaRateCodes[invoice.IVARate]
+	if !ok {
+		return nil, fmt.Errorf("invalid invoice: unsupported IVA rate %v", invoice.IVARate)
+	}
+	accounting := "NO"
+	if issuer.RequiredAccounting {
+		accounting = "SI"
+	}
+
+	document := sriInvoice{
+		ID:      "comprobante",
+		Version: sriInvoiceSchemaVersion,
+		InfoTributaria: sriTaxInfo{
+			Ambiente:        invoice.Environment,
+			TipoEmision:     sriNormalEmission,
+			RazonSocial:     issuer.BusinessName,
+			NombreComercial: issuer.TradeName,
+			RUC:             issuer.RUC,
+			ClaveAcceso:     invoice.AccessKey,
+			CodDoc:          sriInvoiceDocument,
+			Estab:           invoice.Establishment,
+			PtoEmi:          invoice.EmissionPoint,
+			Secuencial:      fmt.Sprintf("%09d", invoice.Sequential),
+			DirMatriz:       issuer.Address,
+		},
+		InfoFactura: sriInvoiceInfo{
+			FechaEmision:                invoice.IssueDate.Format(sriInvoiceDateLayout),
+			DirEstablecimiento:          issuer.Address,
+			ObligadoContabilidad:        accounting,
+			TipoIdentificacionComprador: invoice.Buyer.IDType,
+			RazonSocialComprador:        invoice.Buyer.Name,
+			IdentificacionComprador:     invoice.Buyer.ID,
+			DireccionComprador:          invoice.Buyer.Address,
+			TotalSinImpuestos:           sriAmount(invoice.Subtotal),
+			TotalDescuento:              sriAmount(0),
+			TotalConImpuestos: []sriTotalTax{{
+				Codigo:           sriTaxIVA,
+				CodigoPorcentaje: rateCode,
+				BaseImponible:    sriAmount(invoice.Subtotal),
+				Valor:            sriAmount(invoice.IVA),
+			}},
+			Propina:      sriAmount(0),
+			ImporteTotal: sriAmount(invoice.Total),
+			Moneda:       "DOLAR",
+			Pagos:        []sriPayment{{FormaPago: sriPaymentFinancialSystem, Total: sriAmount(invoice.Total)}},
+		},
+	}
+
+	for _, line := range invoice.Lines {
+		document.Detalles = append(document.Detalles, sriInvoiceDetail{
+			CodigoPrincipal:        line.Code,
+			Descripcion:            line.Description,
+			Cantidad:               sriAmount(line.Quantity),
+			PrecioUnitario:         sriAmount(line.UnitPrice),
+			Descuento:              sriAmount(0),
+			PrecioTotalSinImpuesto: sriAmount(line.Subtotal),
+			Impuestos: []sriTax{{
+				Codigo:           sriTaxIVA,
+				CodigoPorcentaje: rateCode,
+				Tarifa:           fmt.Sprint(invoice.IVARate),
+				BaseImponible:    sriAmount(line.Subtotal),
+				Valor:            sriAmount(roundCents(line.Subtotal * invoice.IVARate / 100)),
+			}},
+		})
+	}
+	if invoice.Buyer.Email != "" {
+		document.InfoAdicional = append(document.InfoAdicional, sriAdditional{Nombre: "Email", Value: truncateRunes(invoice.Buyer.Email, sriMaxTextLength)})
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build invoice XML: %w", err)
+	}
+	return append([]byte(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"), data...), nil
+}
+
+func sriAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func truncateRunes(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+	return value
+}
+
+// InvoiceRIDEText lays out the printed representation (RIDE) of an invoice, in Spanish
+func InvoiceRIDEText(issuer InvoiceIssuer, invoice *ElectronicInvoice) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nRUC: %s\nDirección matriz: %s\n\n", issuer.BusinessName, issuer.RUC, issuer.Address)
+	fmt.Fprintf(&b, "FACTURA No. %s\n", invoice.Number())
+	fmt.Fprintf(&b, "Clave de acceso: %s\n", invoice.AccessKey)
+	if invoice.Status == InvoiceAuthorized {
+		fmt.Fprintf(&b, "Número de autorización: %s\n", invoice.AuthorizationNumber)
+		fmt.Fprintf(&b, "Fecha de autorización: %s\n", invoice.AuthorizedAt.In(ecuadorTime).Format("02/01/2006 15:04:05"))
+	} else {
+		b.WriteString("Comprobante pendiente de autorización del SRI\n")
+	}
+	environment := "PRUEBAS"
+	if invoice.Environment == SRIEnvironmentProduction {
+		environment = "PRODUCCIÓN"
+	}
+	fmt.Fprintf(&b, "Ambiente: %s\n\n", environment)
+
+	fmt.Fprintf(&b, "Cliente: %s\nRUC: %s\n", invoice.Buyer.Name, invoice.Buyer.ID)
+	if invoice.Buyer.Address != "" {
+		fmt.Fprintf(&b, "Dirección: %s\n", invoice.Buyer.Address)
+	}
+	fmt.Fprintf(&b, "Fecha de emisión: %s\n\n", invoice.IssueDate.Format(sriInvoiceDateLayout))
+
+	for _, line := range invoice.Lines {
+		fmt.Fprintf(&b, "%s  %s  %s x %s = %s\n", line.Code, line.Description,
+			sriAmount(line.Quantity), FormatMoney(line.UnitPrice), FormatMoney(line.Subtotal))
+	}
+	fmt.Fprintf(&b, "\nSubtotal: %s\nIVA %v%%: %s\nTotal: %s\n", FormatMoney(invoice.Subtotal),
+		invoice.IVARate, FormatMoney(invoice.IVA), FormatMoney(invoice.Total))
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testInvoiceIssuer() InvoiceIssuer {
+	return InvoiceIssuer{
+		RUC:           "1792146739001",
+		BusinessName:  "Realty Core S.A.",
+		Address:       "Av. Amazonas N34-120, Quito",
+		Establishment: "001",
+		EmissionPoint: "002",
+		Environment:   SRIEnvironmentTest,
+		IVARate:       15,
+	}
+}
+
+func TestAccessKeyCheckDigit(t *testing.T) {
+	// Example of the SRI technical sheet: weights 3,2,7,6,5,4,3,2 give 104, 11 - 104 % 11 = 6
+	assert.Equal(t, "6", AccessKeyCheckDigit("41261533"))
+
+	key := AccessKey(time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC), "01", "1792146739001", "1", "001002", 123, 12345678)
+	assert.Len(t, key, 49)
+	assert.Equal(t, "080920250117921467390011001002000000123123456781", key[:48])
+	assert.True(t, IsValidAccessKey(key))
+
+	tampered := key[:20] + "9" + key[21:]
+	if tampered != key {
+		assert.False(t, IsValidAccessKey(tampered))
+	}
+	assert.False(t, IsValidAccessKey("123"))
+}
+
+func TestNewElectronicInvoice(t *testing.T) {
+	issuer := testInvoiceIssuer()
+	require.NoError(t, issuer.Validate())
+
+	buyer, err := InvoiceBuyerFromAgency(&Agency{ID: "agency-1", Name: "Costa Inmobiliaria", RUC: "0991234567001", Email: "pagos@costa.ec", Address: "Malecón 100", City: "Guayaquil"})
+	require.NoError(t, err)
+	assert.Equal(t, "Malecón 100, Guayaquil", buyer.Address)
+
+	// 01:00 UTC on September 9 is still September 8 in Ecuador
+	now := time.Date(2025, 9, 9, 1, 0, 0, 0, time.UTC)
+	invoice, err := NewElectronicInvoice(issuer, "agency-1", InvoiceSourceFeatured, "prop-1", buyer, []InvoiceLine{
+		{Code: "FEAT-DAY", Description: "Destacado de anuncio (días)", Quantity: 7, UnitPrice: 1.99},
+	}, 42, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "001-002-000000042", invoice.Number())
+	assert.Equal(t, time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC), invoice.IssueDate)
+	assert.Equal(t, 13.93, invoice.Subtotal)
+	assert.Equal(t, 2.09, invoice.IVA)
+	assert.Equal(t, 16.02, invoice.Total)
+	assert.Equal(t, InvoicePending, invoice.Status)
+	assert.True(t, IsValidAccessKey(invoice.AccessKey))
+	assert.True(t, strings.HasPrefix(invoice.AccessKey, "08092025011792146739001"+"1"+"001002"+"000000042"))
+
+	var document struct {
+		ClaveAcceso string `xml:"infoTributaria>claveAcceso"`
+		Secuencial  string `xml:"infoTributaria>secuencial"`
+		Fecha       string `xml:"infoFactura>fechaEmision"`
+		Comprador   string `xml:"infoFactura>identificacionComprador"`
+		Porcentaje  string `xml:"infoFactura>totalConImpuestos>totalImpuesto>codigoPorcentaje"`
+		Total       string `xml:"infoFactura>importeTotal"`
+		Detalles    []struct {
+			Descripcion string `xml:"descripcion"`
+		} `xml:"detalles>detalle"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(invoice.XML), &document))
+	assert.Equal(t, invoice.AccessKey, document.ClaveAcceso)
+	assert.Equal(t, "000000042", document.Secuencial)
+	assert.Equal(t, "08/09/2025", document.Fecha)
+	assert.Equal(t, "0991234567001", document.Comprador)
+	assert.Equal(t, "4", document.Porcentaje)
+	assert.Equal(t, "16.02", document.Total)
+	require.Len(t, document.Detalles, 1)
+	assert.Equal(t, "Destacado de anuncio (días)", document.Detalles[0].Descripcion)
+}
+
+func TestNewElectronicInvoice_Invalid(t *testing.T) {
+	issuer := testInvoiceIssuer()
+	buyer := InvoiceBuyer{IDType: "04", ID: "0991234567001", Name: "Costa"}
+	line := InvoiceLine{Code: "PLAN-PRO", Description: "Plan pro", Quantity: 1, UnitPrice: 49}
+	now := time.Now()
+
+	_, err := NewElectronicInvoice(issuer, "agency-1", "donation", "", buyer, []InvoiceLine{line}, 1, now)
+	assert.Error(t, err)
+	_, err = NewElectronicInvoice(issuer, "agency-1", InvoiceSourceSubscription, "", buyer, nil, 1, now)
+	assert.Error(t, err)
+	_, err = NewElectronicInvoice(issuer, "agency-1", InvoiceSourceSubscription, "", buyer, []InvoiceLine{line}, 0, now)
+	assert.Error(t, err)
+	line.UnitPrice = 0
+	_, err = NewElectronicInvoice(issuer, "agency-1", InvoiceSourceSubscription, "", buyer, []InvoiceLine{line}, 1, now)
+	assert.Error(t, err)
+
+	_, err = InvoiceBuyerFromAgency(&Agency{ID: "agency-1", RUC: "123"})
+	assert.Error(t, err)
+
+	issuer.IVARate = 13.5
+	assert.Error(t, issuer.Validate())
+}
+
+func TestElectronicInvoice_RecordAuthorization(t *testing.T) {
+	now := time.Date(2025, 9, 8, 15, 0, 0, 0, time.UTC)
+	invoice := &ElectronicInvoice{AccessKey: "key", Status: InvoicePending, XML: "<factura/>"}
+
+	invoice.RecordReception(true, nil, now)
+	assert.Equal(t, InvoiceReceived, invoice.Status)
+	assert.False(t, invoice.IsFinal())
+
+	invoice.RecordAuthorization(false, false, "", time.Time{}, nil, []string{"EN PROCESO"}, now)
+	assert.Equal(t, InvoiceReceived, invoice.Status)
+	assert.Equal(t, "<factura/>", invoice.Document())
+
+	invoice.RecordAuthorization(true, false, "", time.Time{}, []byte("<autorizacion/>"), nil, now)
+	assert.Equal(t, InvoiceAuthorized, invoice.Status)
+	assert.Equal(t, "key", invoice.AuthorizationNumber)
+	assert.Equal(t, now, *invoice.AuthorizedAt)
+	assert.Equal(t, "<autorizacion/>", invoice.Document())
+	assert.Equal(t, 3, invoice.Attempts)
+
+	returned := &ElectronicInvoice{Status: InvoicePending}
+	returned.RecordReception(false, []string{"CLAVE ACCESO REGISTRADA"}, now)
+	assert.Equal(t, InvoiceRejected, returned.Status)
+	assert.True(t, returned.IsFinal())
+}
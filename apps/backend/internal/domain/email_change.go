@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email change statuses
+const (
+	EmailChangeStatusPending   = "pending"
+	EmailChangeStatusConfirmed = "confirmed"
+	EmailChangeStatusCancelled = "cancelled"
+	EmailChangeStatusExpired   = "expired"
+)
+
+// DefaultEmailChangeTTL is how long an email change can be confirmed
+const DefaultEmailChangeTTL = 24 * time.Hour
+
+// EmailChange is a request to change the email of an account. The new address
+// receives a link that confirms the change and the current address a link that
+// cancels it; the email only changes once the new address is confirmed. Only the
+// SHA-256 hashes of the tokens are stored.
+type EmailChange struct {
+	ID               string     `json:"id"`
+	UserID           string     `json:"user_id"`
+	OldEmail         string     `json:"old_email"`
+	NewEmail         string     `json:"new_email"`
+	ConfirmTokenHash string     `json:"-"`
+	CancelTokenHash  string     `json:"-"`
+	Status           string     `json:"status"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// NewEmailChange creates a pending email change and returns it with its confirm and
+// cancel tokens
+func NewEmailChange(userID, oldEmail, newEmail string, ttl time.Duration) (*EmailChange, string, string, error) {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if userID == "" {
+		return nil, "", "", fmt.Errorf("user ID cannot be empty")
+	}
+	if err := validateEmail(newEmail); err != nil {
+		return nil, "", "", err
+	}
+	if strings.EqualFold(newEmail, oldEmail) {
+		return nil, "", "", fmt.Errorf("new email must be different from the current email")
+	}
+	if ttl <= 0 {
+		ttl = DefaultEmailChangeTTL
+	}
+
+	confirmToken, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", "", err
+	}
+	cancelToken, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	now := time.Now()
+	return &EmailChange{
+		ID:               uuid.New().String(),
+		UserID:           userID,
+		OldEmail:         oldEmail,
+		NewEmail:         newEmail,
+		ConfirmTokenHash: HashInvitationToken(confirmToken),
+		CancelTokenHash:  HashInvitationToken(cancelToken),
+		Status:           EmailChangeStatusPending,
+		ExpiresAt:        now.Add(ttl),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}, confirmToken, cancelToken, nil
+}
+
+// IsExpired reports whether a pending change is past its expiry
+func (c *EmailChange) IsExpired(now time.Time) bool {
+	return c.Status == EmailChangeStatusPending && now.After(c.ExpiresAt)
+}
+
+// RefreshStatus marks a pending change past its expiry as expired
+func (c *EmailChange) RefreshStatus(now time.Time) {
+	if c.IsExpired(now) {
+		c.Status = EmailChangeStatusExpired
+		c.UpdatedAt = now
+	}
+}
+
+// Confirm records that the new address was verified
+func (c *EmailChange) Confirm(now time.Time) error {
+	c.RefreshStatus(now)
+	if c.Status != EmailChangeStatusPending {
+		return fmt.Errorf("email change is %s", c.Status)
+	}
+
+	c.Status = EmailChangeStatusConfirmed
+	c.ConfirmedAt = &now
+	c.UpdatedAt = now
+	return nil
+}
+
+// Cancel cancels a pending change
+func (c *EmailChange) Cancel(now time.Time) error {
+	c.RefreshStatus(now)
+	if c.Status != EmailChangeStatusPending {
+		return fmt.Errorf("email change is %s", c.Status)
+	}
+
+	c.Status = EmailChangeStatusCancelled
+	c.UpdatedAt = now
+	return nil
+}
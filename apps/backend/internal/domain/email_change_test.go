@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmailChange(t *testing.T) {
+	change, confirmToken, cancelToken, err := NewEmailChange("user-1", "ana@example.com", " Ana.New@Example.com", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "ana.new@example.com", change.NewEmail)
+	assert.Equal(t, HashInvitationToken(confirmToken), change.ConfirmTokenHash)
+	assert.Equal(t, HashInvitationToken(cancelToken), change.CancelTokenHash)
+	assert.NotEqual(t, confirmToken, cancelToken)
+	assert.Equal(t, EmailChangeStatusPending, change.Status)
+
+	_, _, _, err = NewEmailChange("user-1", "ana@example.com", "ANA@example.com", time.Hour)
+	assert.Error(t, err)
+	_, _, _, err = NewEmailChange("user-1", "ana@example.com", "not-an-email", time.Hour)
+	assert.Error(t, err)
+}
+
+func TestEmailChange_Transitions(t *testing.T) {
+	change, _, _, err := NewEmailChange("user-1", "ana@example.com", "new@example.com", time.Hour)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, change.Confirm(now))
+	assert.Equal(t, EmailChangeStatusConfirmed, change.Status)
+	assert.Error(t, change.Cancel(now))
+
+	expired, _, _, err := NewEmailChange("user-1", "ana@example.com", "new@example.com", time.Hour)
+	require.NoError(t, err)
+	assert.ErrorContains(t, expired.Confirm(now.Add(2*time.Hour)), "expired")
+	assert.Equal(t, EmailChangeStatusExpired, expired.Status)
+}
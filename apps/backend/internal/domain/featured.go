@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Featured promotion durations
+const (
+	DefaultFeaturedDuration = 30 * 24 * time.Hour
+	MaxFeaturedDuration     = 365 * 24 * time.Hour
+)
+
+// FeaturedStatus reports whether a property is featured and until when. A featured
+// property without FeaturedUntil is featured indefinitely.
+type FeaturedStatus struct {
+	PropertyID    string     `json:"property_id"`
+	Featured      bool       `json:"featured"`
+	FeaturedUntil *time.Time `json:"featured_until,omitempty"`
+}
+
+// IsActive reports whether the promotion is still running at now
+func (s FeaturedStatus) IsActive(now time.Time) bool {
+	return s.Featured && (s.FeaturedUntil == nil || s.FeaturedUntil.After(now))
+}
+
+// ExtendFeaturedUntil returns the end of a promotion extended by duration. A running
+// promotion is extended from its current end; an expired one is renewed from now.
+func ExtendFeaturedUntil(current *time.Time, now time.Time, duration time.Duration) (time.Time, error) {
+	if duration <= 0 {
+		return time.Time{}, fmt.Errorf("featured duration must be positive")
+	}
+	if duration > MaxFeaturedDuration {
+		return time.Time{}, fmt.Errorf("featured duration cannot exceed %d days", int(MaxFeaturedDuration.Hours()/24))
+	}
+
+	start := now
+	if current != nil && current.After(now) {
+		start = *current
+	}
+	until := start.Add(duration)
+	if until.Sub(now) > MaxFeaturedDuration {
+		return time.Time{}, fmt.Errorf("featured listings cannot be promoted more than %d days ahead", int(MaxFeaturedDuration.Hours()/24))
+	}
+	return until, nil
+}
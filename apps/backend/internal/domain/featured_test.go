@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendFeaturedUntil(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	until, err := ExtendFeaturedUntil(nil, now, week)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(week), until)
+
+	running := now.Add(3 * 24 * time.Hour)
+	until, err = ExtendFeaturedUntil(&running, now, week)
+	require.NoError(t, err)
+	assert.Equal(t, running.Add(week), until, "running promotions are extended from their end")
+
+	expired := now.Add(-time.Hour)
+	until, err = ExtendFeaturedUntil(&expired, now, week)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(week), until, "expired promotions are renewed from now")
+
+	_, err = ExtendFeaturedUntil(nil, now, 0)
+	assert.Error(t, err)
+
+	far := now.Add(MaxFeaturedDuration - time.Hour)
+	_, err = ExtendFeaturedUntil(&far, now, week)
+	assert.ErrorContains(t, err, "more than 365 days ahead")
+}
+
+func TestFeaturedStatus_IsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.True(t, FeaturedStatus{Featured: true}.IsActive(now))
+	assert.True(t, FeaturedStatus{Featured: true, FeaturedUntil: &future}.IsActive(now))
+	assert.False(t, FeaturedStatus{Featured: true, FeaturedUntil: &past}.IsActive(now))
+	assert.False(t, FeaturedStatus{Featured: false}.IsActive(now))
+}
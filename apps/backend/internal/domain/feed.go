@@ -0,0 +1,256 @@
+package domain
+
+import (
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Feed formats
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+)
+
+// Feed limits
+const (
+	DefaultFeedItems = 20
+	MaxFeedItems     = 50
+	// feedSummaryLength bounds the description included in each item
+	feedSummaryLength = 300
+)
+
+// feedPropertyTypeLabels names property types in Spanish, the language of listings
+var feedPropertyTypeLabels = map[string]string{
+	TypeHouse:      "Casa",
+	TypeApartment:  "Departamento",
+	TypeLand:       "Terreno",
+	TypeCommercial: "Local comercial",
+}
+
+// FeedPropertyTypeLabel returns the Spanish name feeds give a property type
+func FeedPropertyTypeLabel(propertyType string) string {
+	if label, ok := feedPropertyTypeLabels[propertyType]; ok {
+		return label
+	}
+	return propertyType
+}
+
+// PropertyFeed is a feed of new listings, rendered as RSS 2.0 or Atom
+type PropertyFeed struct {
+	Title       string
+	Description string
+	Link        string // site page the feed is about
+	SelfURL     string // URL the feed is read from
+	Updated     time.Time
+	Items       []PropertyFeedItem
+}
+
+// PropertyFeedItem is a listing in a feed
+type PropertyFeedItem struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Category  string
+	Image     *string
+	Published time.Time
+	Updated   time.Time
+}
+
+// NewPropertyFeedItem returns the feed item of a property linking to its page at link
+func NewPropertyFeedItem(p *Property, link string) PropertyFeedItem {
+	facts := []string{FeedPropertyTypeLabel(p.Type), p.City + ", " + p.Province}
+	if p.Bedrooms > 0 {
+		facts = append(facts, fmt.Sprintf("%d dormitorios", p.Bedrooms))
+	}
+	if p.Bathrooms > 0 {
+		facts = append(facts, fmt.Sprintf("%g baños", p.Bathrooms))
+	}
+	if p.AreaM2 > 0 {
+		facts = append(facts, fmt.Sprintf("%g m²", p.AreaM2))
+	}
+	facts = append(facts, fmt.Sprintf("$%.0f", p.Price))
+
+	summary := strings.Join(facts, " · ")
+	if description := strings.TrimSpace(p.Description); description != "" {
+		runes := []rune(description)
+		if len(runes) > feedSummaryLength {
+			description = strings.TrimSpace(string(runes[:feedSummaryLength])) + "…"
+		}
+		summary += "\n\n" + description
+	}
+
+	return PropertyFeedItem{
+		ID:        p.ID,
+		Title:     p.Title,
+		Link:      link,
+		Summary:   summary,
+		Category:  p.Type,
+		Image:     p.MainImage,
+		Published: p.CreatedAt,
+		Updated:   p.UpdatedAt,
+	}
+}
+
+// RenderPropertyFeed renders a feed as an XML document in the given format
+func RenderPropertyFeed(feed *PropertyFeed, format string) ([]byte, error) {
+	var document interface{}
+	switch format {
+	case FeedFormatRSS:
+		document = newRSSDocument(feed)
+	case FeedFormatAtom:
+		document = newAtomDocument(feed)
+	default:
+		return nil, fmt.Errorf("invalid feed format: %s", format)
+	}
+
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomXMLNS string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description string        `xml:"description"`
+	Category    string        `xml:"category,omitempty"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+func newRSSDocument(feed *PropertyFeed) rssDocument {
+	channel := rssChannel{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Description:   feed.Description,
+		Language:      "es-ec",
+		LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+		AtomLink:      atomLink{Href: feed.SelfURL, Rel: "self", Type: "application/rss+xml"},
+		Items:         make([]rssItem, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		rss := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: "false", Value: "property:" + item.ID},
+			Description: item.Summary,
+			Category:    item.Category,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		}
+		if item.Image != nil && *item.Image != "" {
+			rss.Enclosure = &rssEnclosure{URL: *item.Image, Type: feedImageType(*item.Image), Length: "0"}
+		}
+		channel.Items = append(channel.Items, rss)
+	}
+	return rssDocument{Version: "2.0", AtomXMLNS: "http://www.w3.org/2005/Atom", Channel: channel}
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Links     []atomLink    `xml:"link"`
+	Summary   string        `xml:"summary"`
+	Category  *atomCategory `xml:"category"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func newAtomDocument(feed *PropertyFeed) atomDocument {
+	document := atomDocument{
+		Lang:     "es-EC",
+		ID:       feed.SelfURL,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  feed.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:        "urn:uuid:" + item.ID,
+			Title:     item.Title,
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Summary:   item.Summary,
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+		}
+		if item.Category != "" {
+			entry.Category = &atomCategory{Term: item.Category}
+		}
+		if item.Image != nil && *item.Image != "" {
+			entry.Links = append(entry.Links, atomLink{Href: *item.Image, Rel: "enclosure", Type: feedImageType(*item.Image)})
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+	return document
+}
+
+// feedImageType returns the media type of an image by its extension, JPEG by default
+func feedImageType(imageURL string) string {
+	if u, err := url.Parse(imageURL); err == nil {
+		if mediaType := mime.TypeByExtension(strings.ToLower(path.Ext(u.Path))); strings.HasPrefix(mediaType, "image/") {
+			return mediaType
+		}
+	}
+	return "image/jpeg"
+}
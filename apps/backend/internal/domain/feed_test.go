@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPropertyFeed() *PropertyFeed {
+	image := "https://cdn.inmo.ec/images/prop-1/main.webp"
+	createdAt := time.Date(2025, 9, 12, 15, 30, 0, 0, time.UTC)
+	property := &Property{
+		ID: "prop-1", Slug: "casa-samborondon-prop-1", Title: "Casa en Samborondón & piscina",
+		Description: strings.Repeat("Amplia casa con jardín. ", 30), Price: 285000,
+		Province: "Guayas", City: "Samborondón", Type: TypeHouse, Bedrooms: 4, Bathrooms: 3.5, AreaM2: 320,
+		MainImage: &image, CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+	return &PropertyFeed{
+		Title:   "Nuevas propiedades: casa en Guayas",
+		Link:    "https://inmo.ec/propiedades?province=Guayas",
+		SelfURL: "https://api.inmo.ec/api/feeds/properties.rss?province=Guayas",
+		Updated: createdAt,
+		Items:   []PropertyFeedItem{NewPropertyFeedItem(property, "https://inmo.ec/propiedades/casa-samborondon-prop-1")},
+	}
+}
+
+func TestNewPropertyFeedItem(t *testing.T) {
+	item := testPropertyFeed().Items[0]
+	assert.True(t, strings.HasPrefix(item.Summary, "Casa · Samborondón, Guayas · 4 dormitorios · 3.5 baños · 320 m² · $285000"))
+	assert.True(t, strings.HasSuffix(item.Summary, "…"), "long descriptions are cut")
+	assert.Less(t, len([]rune(item.Summary)), 400)
+}
+
+func TestRenderPropertyFeed(t *testing.T) {
+	feed := testPropertyFeed()
+
+	rss, err := RenderPropertyFeed(feed, FeedFormatRSS)
+	require.NoError(t, err)
+	var parsedRSS struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title     string `xml:"title"`
+				Link      string `xml:"link"`
+				PubDate   string `xml:"pubDate"`
+				Enclosure struct {
+					Type string `xml:"type,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(rss, &parsedRSS))
+	require.Len(t, parsedRSS.Channel.Items, 1)
+	assert.Equal(t, "Casa en Samborondón & piscina", parsedRSS.Channel.Items[0].Title)
+	assert.Equal(t, "Fri, 12 Sep 2025 15:30:00 +0000", parsedRSS.Channel.Items[0].PubDate)
+	assert.Equal(t, "image/webp", parsedRSS.Channel.Items[0].Enclosure.Type)
+	assert.Contains(t, string(rss), `<atom:link href="https://api.inmo.ec/api/feeds/properties.rss?province=Guayas" rel="self"`)
+
+	atom, err := RenderPropertyFeed(feed, FeedFormatAtom)
+	require.NoError(t, err)
+	var parsedAtom struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID string `xml:"id"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(atom, &parsedAtom))
+	assert.Equal(t, "2025-09-12T15:30:00Z", parsedAtom.Updated)
+	require.Len(t, parsedAtom.Entries, 1)
+	assert.Equal(t, "urn:uuid:prop-1", parsedAtom.Entries[0].ID)
+
+	_, err = RenderPropertyFeed(feed, "json")
+	assert.ErrorContains(t, err, "invalid feed format")
+}
@@ -0,0 +1,360 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Honeytoken sources: where a canary listing is planted, so a hit tells which channel
+// leaked it
+const (
+	// HoneytokenSourceFeed canaries are planted in the RSS and Atom feeds
+	HoneytokenSourceFeed = "feed"
+	// HoneytokenSourcePublicAPI canaries are planted in public API search results, for
+	// every key or for a single one
+	HoneytokenSourcePublicAPI = "public_api"
+	// HoneytokenSourceManual canaries are never planted; their links are handed out by
+	// hand, e.g. to a partner under suspicion
+	HoneytokenSourceManual = "manual"
+)
+
+// Honeytoken limits
+const (
+	MaxActiveHoneytokens    = 20
+	MaxHoneytokenLabelChars = 120
+	// HoneytokenPlantPosition is the position canaries take in the results they are
+	// planted in, among real listings rather than first or last
+	HoneytokenPlantPosition = 2
+)
+
+// IsValidHoneytokenSource reports whether a honeytoken source is known
+func IsValidHoneytokenSource(source string) bool {
+	switch source {
+	case HoneytokenSourceFeed, HoneytokenSourcePublicAPI, HoneytokenSourceManual:
+		return true
+	}
+	return false
+}
+
+// Honeytoken is a synthetic "canary" listing. It is never stored as a property nor shown
+// by the site, so nobody browsing reaches its detail page: requests for it come from
+// scrapers that crawled the channel it was planted in, or from copies of that channel.
+type Honeytoken struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Label  string `json:"label"`
+	// Listing is the synthetic listing served as if it were real
+	Listing Property `json:"listing"`
+	// APIKeyID plants a public API canary in the results of that key only
+	APIKeyID  *string    `json:"api_key_id,omitempty"`
+	Active    bool       `json:"active"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+// NewHoneytoken creates a canary from a listing draft, which gets its own id and slug
+// and no owner, agency or agent
+func NewHoneytoken(source, label string, draft Property, apiKeyID *string, createdBy string, now time.Time) (*Honeytoken, error) {
+	if !IsValidHoneytokenSource(source) {
+		return nil, fmt.Errorf("invalid honeytoken source: %s", source)
+	}
+	label = strings.TrimSpace(label)
+	if label == "" || len([]rune(label)) > MaxHoneytokenLabelChars {
+		return nil, fmt.Errorf("invalid label: must be 1 to %d characters", MaxHoneytokenLabelChars)
+	}
+	if apiKeyID != nil && source != HoneytokenSourcePublicAPI {
+		return nil, fmt.Errorf("invalid api_key_id: only public_api honeytokens are planted per key")
+	}
+	if !draft.IsValid() {
+		return nil, fmt.Errorf("invalid listing: title, price, province, city and type are required")
+	}
+	if !IsValidProvince(draft.Province) {
+		return nil, fmt.Errorf("invalid province: %s", draft.Province)
+	}
+	if !IsValidPropertyType(draft.Type) {
+		return nil, fmt.Errorf("invalid property type: %s", draft.Type)
+	}
+
+	listing := draft
+	listing.ID = uuid.New().String()
+	listing.Slug = GenerateSlug(listing.Title, listing.ID)
+	listing.Status = StatusAvailable
+	if listing.LocationPrecision == "" {
+		listing.LocationPrecision = PrecisionApproximate
+	}
+	if listing.PropertyStatus == "" {
+		listing.PropertyStatus = PropertyStatusUsed
+	}
+	if listing.Images == nil {
+		listing.Images = []string{}
+	}
+	if listing.Tags == nil {
+		listing.Tags = []string{}
+	}
+	listing.OwnerID, listing.AgentID, listing.AgencyID = nil, nil, nil
+	listing.CreatedBy, listing.UpdatedBy, listing.RealEstateCompanyID = nil, nil, nil
+	listing.Featured = false
+	listing.ViewCount = 0
+	listing.CreatedAt = now
+	listing.UpdatedAt = now
+
+	return &Honeytoken{
+		ID:        uuid.New().String(),
+		Source:    source,
+		Label:     label,
+		Listing:   listing,
+		APIKeyID:  apiKeyID,
+		Active:    true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}, nil
+}
+
+// Retire stops planting and watching the canary; its hits are kept
+func (h *Honeytoken) Retire(now time.Time) {
+	h.Active = false
+	h.RetiredAt = &now
+}
+
+// PlantableIn reports whether the canary may be planted in results of the given source
+// and key filtered by filters. Results filtered by criteria the canary is not checked
+// against, such as a text query or an agency, never get it, so it cannot look out of
+// place.
+func (h *Honeytoken) PlantableIn(source, apiKeyID string, filters *PropertySearchFilters) bool {
+	if !h.Active || h.Source != source {
+		return false
+	}
+	if h.APIKeyID != nil && *h.APIKeyID != apiKeyID {
+		return false
+	}
+	if filters == nil {
+		return true
+	}
+	if filters.Query != "" || len(filters.Sectors) > 0 || len(filters.Tags) > 0 ||
+		filters.OwnerID != nil || filters.AgentID != nil || filters.AgencyID != nil || filters.CreatedBy != nil ||
+		filters.Featured != nil || filters.MinBathrooms != nil || filters.MaxBathrooms != nil ||
+		filters.MinArea != nil || filters.MaxArea != nil || filters.HasPool != nil || filters.HasGarden != nil ||
+		filters.HasTerrace != nil || filters.HasBalcony != nil || filters.HasSecurity != nil ||
+		filters.HasElevator != nil || filters.HasAirCondition != nil || filters.HasParking != nil ||
+		filters.MinParkingSpaces != nil || filters.Furnished != nil {
+		return false
+	}
+
+	listing := &h.Listing
+	if len(filters.Provinces) > 0 && !containsFold(filters.Provinces, listing.Province) {
+		return false
+	}
+	if len(filters.Cities) > 0 && !containsFold(filters.Cities, listing.City) {
+		return false
+	}
+	if len(filters.PropertyTypes) > 0 && !containsFold(filters.PropertyTypes, listing.Type) {
+		return false
+	}
+	if len(filters.Status) > 0 && !containsFold(filters.Status, listing.Status) {
+		return false
+	}
+	if filters.MinPrice != nil && listing.Price < *filters.MinPrice {
+		return false
+	}
+	if filters.MaxPrice != nil && listing.Price > *filters.MaxPrice {
+		return false
+	}
+	if filters.MinBedrooms != nil && listing.Bedrooms < *filters.MinBedrooms {
+		return false
+	}
+	if filters.MaxBedrooms != nil && listing.Bedrooms > *filters.MaxBedrooms {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether values holds value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// HoneytokenHit is a request for the detail of a canary listing
+type HoneytokenHit struct {
+	ID           string    `json:"id"`
+	HoneytokenID string    `json:"honeytoken_id"`
+	Path         string    `json:"path"`
+	ClientIP     string    `json:"client_ip"`
+	UserAgent    string    `json:"user_agent"`
+	Referer      string    `json:"referer,omitempty"`
+	Fingerprint  string    `json:"fingerprint"`
+	APIKeyID     string    `json:"api_key_id,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewHoneytokenHit creates a hit on a canary
+func NewHoneytokenHit(honeytokenID string, hit HoneytokenHit, now time.Time) *HoneytokenHit {
+	hit.ID = uuid.New().String()
+	hit.HoneytokenID = honeytokenID
+	hit.CreatedAt = now
+	return &hit
+}
+
+// Suspect identifies who made a hit: its API key, else its user, else its client
+// fingerprint
+func (h *HoneytokenHit) Suspect() string {
+	switch {
+	case h.APIKeyID != "":
+		return "api_key:" + h.APIKeyID
+	case h.UserID != "":
+		return "user:" + h.UserID
+	default:
+		return "client:" + h.Fingerprint
+	}
+}
+
+// HoneytokenSuspect sums up the hits of one suspected source
+type HoneytokenSuspect struct {
+	Suspect string `json:"suspect"`
+	// Sources are the channels of the canaries the suspect requested, which the suspect
+	// scraped or got a copy of
+	Sources    []string  `json:"sources"`
+	Hits       int       `json:"hits"`
+	Canaries   int       `json:"canaries"`
+	ClientIPs  []string  `json:"client_ips"`
+	UserAgents []string  `json:"user_agents"`
+	Referers   []string  `json:"referers,omitempty"`
+	FirstHit   time.Time `json:"first_hit"`
+	LastHit    time.Time `json:"last_hit"`
+}
+
+// HoneytokenSourceSummary sums up the hits on the canaries of one source
+type HoneytokenSourceSummary struct {
+	Source   string `json:"source"`
+	Canaries int    `json:"canaries"`
+	Hits     int    `json:"hits"`
+	Suspects int    `json:"suspects"`
+}
+
+// HoneytokenReport sums up the hits on canaries over a period, per source and per
+// suspect, most hits first
+type HoneytokenReport struct {
+	From     time.Time                 `json:"from"`
+	To       time.Time                 `json:"to"`
+	Hits     int                       `json:"hits"`
+	Sources  []HoneytokenSourceSummary `json:"sources"`
+	Suspects []HoneytokenSuspect       `json:"suspects"`
+}
+
+// NewHoneytokenReport builds the report of hits between from and to on the given
+// canaries; hits on unknown canaries are left out
+func NewHoneytokenReport(tokens []Honeytoken, hits []HoneytokenHit, from, to time.Time) *HoneytokenReport {
+	sourceOf := make(map[string]string, len(tokens))
+	sources := map[string]*HoneytokenSourceSummary{}
+	for _, token := range tokens {
+		sourceOf[token.ID] = token.Source
+		if sources[token.Source] == nil {
+			sources[token.Source] = &HoneytokenSourceSummary{Source: token.Source}
+		}
+		sources[token.Source].Canaries++
+	}
+
+	type suspectState struct {
+		summary                           *HoneytokenSuspect
+		sources, canaries, ips, uas, refs map[string]bool
+	}
+	suspects := map[string]*suspectState{}
+	sourceSuspects := map[string]map[string]bool{}
+	report := &HoneytokenReport{From: from, To: to, Sources: []HoneytokenSourceSummary{}, Suspects: []HoneytokenSuspect{}}
+
+	for _, hit := range hits {
+		source, ok := sourceOf[hit.HoneytokenID]
+		if !ok || hit.CreatedAt.Before(from) || !hit.CreatedAt.Before(to) {
+			continue
+		}
+		report.Hits++
+		sources[source].Hits++
+		key := hit.Suspect()
+		if sourceSuspects[source] == nil {
+			sourceSuspects[source] = map[string]bool{}
+		}
+		sourceSuspects[source][key] = true
+
+		state := suspects[key]
+		if state == nil {
+			state = &suspectState{
+				summary:  &HoneytokenSuspect{Suspect: key, FirstHit: hit.CreatedAt, LastHit: hit.CreatedAt},
+				sources:  map[string]bool{},
+				canaries: map[string]bool{},
+				ips:      map[string]bool{},
+				uas:      map[string]bool{},
+				refs:     map[string]bool{},
+			}
+			suspects[key] = state
+		}
+		summary := state.summary
+		summary.Hits++
+		if hit.CreatedAt.Before(summary.FirstHit) {
+			summary.FirstHit = hit.CreatedAt
+		}
+		if hit.CreatedAt.After(summary.LastHit) {
+			summary.LastHit = hit.CreatedAt
+		}
+		state.sources[source] = true
+		state.canaries[hit.HoneytokenID] = true
+		if hit.ClientIP != "" {
+			state.ips[hit.ClientIP] = true
+		}
+		if hit.UserAgent != "" {
+			state.uas[hit.UserAgent] = true
+		}
+		if hit.Referer != "" {
+			state.refs[hit.Referer] = true
+		}
+	}
+
+	for _, summary := range sources {
+		summary.Suspects = len(sourceSuspects[summary.Source])
+		report.Sources = append(report.Sources, *summary)
+	}
+	sort.Slice(report.Sources, func(i, j int) bool {
+		if report.Sources[i].Hits != report.Sources[j].Hits {
+			return report.Sources[i].Hits > report.Sources[j].Hits
+		}
+		return report.Sources[i].Source < report.Sources[j].Source
+	})
+
+	for _, state := range suspects {
+		summary := state.summary
+		summary.Sources = sortedKeys(state.sources)
+		summary.Canaries = len(state.canaries)
+		summary.ClientIPs = sortedKeys(state.ips)
+		summary.UserAgents = sortedKeys(state.uas)
+		if len(state.refs) > 0 {
+			summary.Referers = sortedKeys(state.refs)
+		}
+		report.Suspects = append(report.Suspects, *summary)
+	}
+	sort.Slice(report.Suspects, func(i, j int) bool {
+		if report.Suspects[i].Hits != report.Suspects[j].Hits {
+			return report.Suspects[i].Hits > report.Suspects[j].Hits
+		}
+		return report.Suspects[i].Suspect < report.Suspects[j].Suspect
+	})
+	return report
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
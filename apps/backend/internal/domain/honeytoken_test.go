@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func honeytokenDraft() Property {
+	owner := "owner-1"
+	return Property{
+		Title:    "Casa con jardín en Samborondón",
+		Price:    185000,
+		Province: "Guayas",
+		City:     "Samborondón",
+		Type:     "house",
+		Bedrooms: 3,
+		OwnerID:  &owner,
+	}
+}
+
+func TestNewHoneytoken(t *testing.T) {
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+	token, err := NewHoneytoken(HoneytokenSourceFeed, " Feed canary ", honeytokenDraft(), nil, "admin-1", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Feed canary", token.Label)
+	assert.True(t, token.Active)
+	assert.NotEmpty(t, token.Listing.ID)
+	assert.NotEqual(t, token.ID, token.Listing.ID)
+	assert.Contains(t, token.Listing.Slug, "casa-con-")
+	assert.Equal(t, StatusAvailable, token.Listing.Status)
+	assert.Nil(t, token.Listing.OwnerID, "canaries belong to nobody")
+	assert.Equal(t, now, token.Listing.CreatedAt)
+
+	_, err = NewHoneytoken("sitemap", "x", honeytokenDraft(), nil, "admin-1", now)
+	assert.Error(t, err)
+	key := "key-1"
+	_, err = NewHoneytoken(HoneytokenSourceFeed, "x", honeytokenDraft(), &key, "admin-1", now)
+	assert.Error(t, err, "only public API canaries are planted per key")
+	draft := honeytokenDraft()
+	draft.Province = "Atlantis"
+	_, err = NewHoneytoken(HoneytokenSourceManual, "x", draft, nil, "admin-1", now)
+	assert.Error(t, err)
+
+	token.Retire(now)
+	assert.False(t, token.Active)
+	assert.NotNil(t, token.RetiredAt)
+}
+
+func TestHoneytoken_PlantableIn(t *testing.T) {
+	key := "key-1"
+	token, err := NewHoneytoken(HoneytokenSourcePublicAPI, "Partner canary", honeytokenDraft(), &key, "admin-1", time.Now())
+	require.NoError(t, err)
+
+	minPrice, maxPrice := 100000.0, 150000.0
+	assert.True(t, token.PlantableIn(HoneytokenSourcePublicAPI, "key-1", nil))
+	assert.True(t, token.PlantableIn(HoneytokenSourcePublicAPI, "key-1", &PropertySearchFilters{
+		Provinces: []string{"guayas"}, PropertyTypes: []string{"house"}, MinPrice: &minPrice,
+	}))
+	assert.False(t, token.PlantableIn(HoneytokenSourcePublicAPI, "key-2", nil), "planted for one key only")
+	assert.False(t, token.PlantableIn(HoneytokenSourceFeed, "key-1", nil))
+	assert.False(t, token.PlantableIn(HoneytokenSourcePublicAPI, "key-1", &PropertySearchFilters{Cities: []string{"Quito"}}))
+	assert.False(t, token.PlantableIn(HoneytokenSourcePublicAPI, "key-1", &PropertySearchFilters{MaxPrice: &maxPrice}))
+	assert.False(t, token.PlantableIn(HoneytokenSourcePublicAPI, "key-1", &PropertySearchFilters{Query: "jardín"}),
+		"unchecked criteria keep canaries out")
+}
+
+func TestNewHoneytokenReport(t *testing.T) {
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+	feed := Honeytoken{ID: "t-feed", Source: HoneytokenSourceFeed}
+	api := Honeytoken{ID: "t-api", Source: HoneytokenSourcePublicAPI}
+	hits := []HoneytokenHit{
+		{HoneytokenID: "t-feed", Fingerprint: "fp-1", ClientIP: "203.0.113.7", UserAgent: "curl/8.0", CreatedAt: now},
+		{HoneytokenID: "t-api", Fingerprint: "fp-1", ClientIP: "203.0.113.8", UserAgent: "curl/8.0", CreatedAt: now.Add(time.Hour)},
+		{HoneytokenID: "t-feed", Fingerprint: "fp-1", ClientIP: "203.0.113.7", UserAgent: "curl/8.0", CreatedAt: now.Add(2 * time.Hour)},
+		{HoneytokenID: "t-api", APIKeyID: "key-1", Fingerprint: "fp-2", Referer: "https://copy.example", CreatedAt: now},
+		{HoneytokenID: "t-gone", Fingerprint: "fp-3", CreatedAt: now},
+		{HoneytokenID: "t-feed", Fingerprint: "fp-4", CreatedAt: now.Add(48 * time.Hour)},
+	}
+
+	report := NewHoneytokenReport([]Honeytoken{feed, api}, hits, now, now.Add(24*time.Hour))
+	assert.Equal(t, 4, report.Hits)
+	require.Len(t, report.Sources, 2)
+	assert.Equal(t, HoneytokenSourceSummary{Source: HoneytokenSourceFeed, Canaries: 1, Hits: 2, Suspects: 1}, report.Sources[0])
+	assert.Equal(t, HoneytokenSourceSummary{Source: HoneytokenSourcePublicAPI, Canaries: 1, Hits: 2, Suspects: 2}, report.Sources[1])
+
+	require.Len(t, report.Suspects, 2)
+	first := report.Suspects[0]
+	assert.Equal(t, "client:fp-1", first.Suspect)
+	assert.Equal(t, 3, first.Hits)
+	assert.Equal(t, 2, first.Canaries)
+	assert.Equal(t, []string{HoneytokenSourceFeed, HoneytokenSourcePublicAPI}, first.Sources)
+	assert.Equal(t, []string{"203.0.113.7", "203.0.113.8"}, first.ClientIPs)
+	assert.Equal(t, now, first.FirstHit)
+	assert.Equal(t, now.Add(2*time.Hour), first.LastHit)
+	assert.Equal(t, "api_key:key-1", report.Suspects[1].Suspect, "keyed requests are attributed to the key")
+	assert.Equal(t, []string{"https://copy.example"}, report.Suspects[1].Referers)
+}
@@ -23,6 +23,9 @@ type ImageInfo struct {
 	Format       string    `json:"format"`
 	Quality      int       `json:"quality"`
 	IsOptimized  bool      `json:"is_optimized"`
+	CapturedAt   *time.Time `json:"captured_at,omitempty"`
+	CameraMake   string    `json:"camera_make,omitempty"`
+	CameraModel  string    `json:"camera_model,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -34,6 +37,34 @@ type ImageUploadRequest struct {
 	SortOrder  int    `json:"sort_order"`
 }
 
+// ImageUpload is a single file received in a batch upload
+type ImageUpload struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// ImageUploadResult is the per-file outcome of a batch upload
+type ImageUploadResult struct {
+	Index      int        `json:"index"`
+	FileName   string     `json:"file_name"`
+	Size       int64      `json:"size"`
+	Success    bool       `json:"success"`
+	Error      string     `json:"error,omitempty"`
+	Image      *ImageInfo `json:"image,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+// BatchUploadResult summarizes a multi-file upload
+type BatchUploadResult struct {
+	PropertyID string              `json:"property_id"`
+	Total      int                 `json:"total"`
+	Succeeded  int                 `json:"succeeded"`
+	Failed     int                 `json:"failed"`
+	DurationMs int64               `json:"duration_ms"`
+	Results    []ImageUploadResult `json:"results"`
+}
+
 // ImageReorderRequest represents request for reordering images
 type ImageReorderRequest struct {
 	ImageIDs []string `json:"image_ids"`
@@ -49,6 +80,16 @@ type ProcessingOptions struct {
 	PreserveAspect bool    `json:"preserve_aspect"`
 }
 
+// ImageEXIF holds the photo metadata kept after EXIF is stripped from an upload.
+// GPS coordinates are never kept; HadLocation records that they were removed.
+type ImageEXIF struct {
+	CapturedAt  *time.Time `json:"captured_at,omitempty"`
+	CameraMake  string     `json:"camera_make,omitempty"`
+	CameraModel string     `json:"camera_model,omitempty"`
+	Orientation int        `json:"orientation"`
+	HadLocation bool       `json:"had_location"`
+}
+
 // ImageStats represents processing statistics
 type ImageStats struct {
 	OriginalSize     int64   `json:"original_size"`
@@ -76,6 +117,16 @@ func NewImageInfo(propertyID, fileName string) *ImageInfo {
 	}
 }
 
+// SetCaptureData records capture date and camera data extracted from EXIF
+func (img *ImageInfo) SetCaptureData(exif *ImageEXIF) {
+	if exif == nil {
+		return
+	}
+	img.CapturedAt = exif.CapturedAt
+	img.CameraMake = exif.CameraMake
+	img.CameraModel = exif.CameraModel
+}
+
 // UpdateMetadata updates image metadata
 func (img *ImageInfo) UpdateMetadata(altText string, sortOrder int) {
 	img.AltText = altText
@@ -267,6 +318,9 @@ const (
 	MediumSize        = 800
 	LargeSize         = 1200
 	MaxImagesPerProperty = 50
+	MaxBatchUploadFiles  = 20
+	MaxBatchUploadSize   = int64(100 * 1024 * 1024) // 100MB per request
+	DefaultUploadConcurrency = 4
 )
 
 // Supported MIME types
@@ -286,6 +340,22 @@ func GetFormatFromMimeType(mimeType string) string {
 	return ""
 }
 
+// GetMimeTypeFromFilename returns the MIME type for a supported image file name
+func GetMimeTypeFromFilename(filename string) string {
+	switch GetImageFormatFromFilename(filename) {
+	case "jpg":
+		return "image/jpeg"
+	case "png":
+		return "image/png"
+	case "webp":
+		return "image/webp"
+	case "avif":
+		return "image/avif"
+	default:
+		return ""
+	}
+}
+
 // IsSupportedMimeType checks if MIME type is supported
 func IsSupportedMimeType(mimeType string) bool {
 	_, exists := SupportedMimeTypes[strings.ToLower(mimeType)]
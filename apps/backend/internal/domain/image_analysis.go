@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Image analysis statuses. Uploaded photos are queued as pending and analyzed in
+// batches; analyses failing MaxImageAnalysisAttempts times are left failed.
+const (
+	ImageAnalysisPending  = "pending"
+	ImageAnalysisAnalyzed = "analyzed"
+	ImageAnalysisFailed   = "failed"
+)
+
+// Image analysis defaults
+const (
+	MaxImageAnalysisAttempts          = 3
+	DefaultImageAnalysisBatchSize     = 50
+	DefaultImageSuggestionConfidence  = 0.6
+	maxAttributeSuggestionImageIDs    = 5
+	imageAnalysisLabelConfidenceFloor = 0.01
+)
+
+// Attribute suggestion kinds
+const (
+	AttributeSuggestionKindFeature = "feature" // a flag of the property such as pool
+	AttributeSuggestionKindAmenity = "amenity" // an entry of the amenity catalog
+)
+
+// imageAttributeLabels maps the labels detected in photos to the property features and
+// catalog amenities they suggest
+var imageAttributeLabels = map[string]string{
+	"pool":             "pool",
+	"swimming_pool":    "pool",
+	"garden":           "garden",
+	"lawn":             "garden",
+	"backyard":         "garden",
+	"garage":           "garage",
+	"carport":          "garage",
+	"terrace":          "terrace",
+	"rooftop":          "terrace",
+	"balcony":          "balcony",
+	"elevator":         "elevator",
+	"air_conditioner":  "air_conditioning",
+	"air_conditioning": "air_conditioning",
+	"bbq":              "bbq_area",
+	"grill":            "bbq_area",
+	"gym":              "gym",
+	"playground":       "playground",
+	"fireplace":        "fireplace",
+}
+
+// mainImageLabelWeights rates how well a photo showing a label works as the main image
+// of a listing: buyers respond best to the facade and outdoor views
+var mainImageLabelWeights = map[string]float64{
+	"facade":      1,
+	"exterior":    0.9,
+	"aerial":      0.8,
+	"view":        0.7,
+	"pool":        0.7,
+	"living_room": 0.6,
+}
+
+// ImageLabel is a scene or object detected in a photo with its confidence (0-1)
+type ImageLabel struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ImageAnalysis is the outcome of analyzing an uploaded photo for property attributes
+type ImageAnalysis struct {
+	ImageID    string       `json:"image_id"`
+	PropertyID string       `json:"property_id"`
+	Status     string       `json:"status"`
+	Labels     []ImageLabel `json:"labels"`
+	Provider   string       `json:"provider,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Attempts   int          `json:"attempts"`
+	AnalyzedAt *time.Time   `json:"analyzed_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// NewImageAnalysis queues the analysis of a photo
+func NewImageAnalysis(imageID, propertyID string, at time.Time) *ImageAnalysis {
+	return &ImageAnalysis{
+		ImageID:    imageID,
+		PropertyID: propertyID,
+		Status:     ImageAnalysisPending,
+		Labels:     []ImageLabel{},
+		CreatedAt:  at,
+	}
+}
+
+// Complete records the labels a provider detected, most confident first. Label names
+// are lowercased with words joined by underscores, so "Swimming pool" matches
+// "swimming_pool".
+func (a *ImageAnalysis) Complete(provider string, labels []ImageLabel, at time.Time) {
+	normalized := []ImageLabel{}
+	for _, label := range labels {
+		name := strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(label.Name, "-", " "))), "_")
+		if name == "" || label.Confidence < imageAnalysisLabelConfidenceFloor {
+			continue
+		}
+		if label.Confidence > 1 {
+			label.Confidence = 1
+		}
+		normalized = append(normalized, ImageLabel{Name: name, Confidence: label.Confidence})
+	}
+	sort.SliceStable(normalized, func(i, j int) bool { return normalized[i].Confidence > normalized[j].Confidence })
+
+	a.Status = ImageAnalysisAnalyzed
+	a.Labels = normalized
+	a.Provider = provider
+	a.Error = ""
+	a.Attempts++
+	a.AnalyzedAt = &at
+}
+
+// Fail records a failed attempt; the analysis stays pending until it runs out of attempts
+func (a *ImageAnalysis) Fail(provider string, err error, at time.Time) {
+	a.Provider = provider
+	a.Error = err.Error()
+	a.Attempts++
+	a.AnalyzedAt = &at
+	if a.Attempts >= MaxImageAnalysisAttempts {
+		a.Status = ImageAnalysisFailed
+	}
+}
+
+// AttributeSuggestion is a property feature or catalog amenity seen in the listing's
+// photos, for the agent to confirm
+type AttributeSuggestion struct {
+	Attribute  string   `json:"attribute"` // property feature such as "pool", or amenity catalog code
+	Kind       string   `json:"kind"`
+	Confidence float64  `json:"confidence"`
+	ImageIDs   []string `json:"image_ids"` // photos showing it, most confident first
+}
+
+// MainImageSuggestion is the photo that would work best as the main image
+type MainImageSuggestion struct {
+	ImageID string  `json:"image_id"`
+	Label   string  `json:"label"`
+	Score   float64 `json:"score"`
+}
+
+// ImageSuggestions are the attributes and main image suggested by a listing's photos.
+// They are never applied automatically.
+type ImageSuggestions struct {
+	PropertyID string                `json:"property_id"`
+	Attributes []AttributeSuggestion `json:"attributes"`
+	MainImage  *MainImageSuggestion  `json:"main_image,omitempty"`
+	Analyzed   int                   `json:"analyzed"`
+	Pending    int                   `json:"pending"`
+}
+
+// SuggestImageAttributes turns the analyses of a listing's photos into suggestions.
+// Features the property already has, catalog amenities already set and labels below
+// minConfidence are left out, as is the main image when the current one is the best.
+func SuggestImageAttributes(property *Property, amenityCodes []string, mainImageID string, analyses []ImageAnalysis, minConfidence float64) *ImageSuggestions {
+	suggestions := &ImageSuggestions{PropertyID: property.ID, Attributes: []AttributeSuggestion{}}
+
+	features := propertyFeatures(property)
+	present := map[string]bool{}
+	for _, code := range amenityCodes {
+		present[code] = true
+	}
+	for feature, set := range features {
+		present[feature] = set
+	}
+
+	byAttribute := map[string]*AttributeSuggestion{}
+	var mainImage *MainImageSuggestion
+	for _, analysis := range analyses {
+		if analysis.Status != ImageAnalysisAnalyzed {
+			if analysis.Status == ImageAnalysisPending {
+				suggestions.Pending++
+			}
+			continue
+		}
+		suggestions.Analyzed++
+
+		for _, label := range analysis.Labels {
+			if label.Confidence < minConfidence {
+				continue
+			}
+			if weight, ok := mainImageLabelWeights[label.Name]; ok {
+				score := label.Confidence * weight
+				if mainImage == nil || score > mainImage.Score {
+					mainImage = &MainImageSuggestion{ImageID: analysis.ImageID, Label: label.Name, Score: score}
+				}
+			}
+
+			attribute, ok := imageAttributeLabels[label.Name]
+			if !ok || present[attribute] {
+				continue
+			}
+			suggestion := byAttribute[attribute]
+			if suggestion == nil {
+				kind := AttributeSuggestionKindAmenity
+				if _, isFeature := features[attribute]; isFeature {
+					kind = AttributeSuggestionKindFeature
+				}
+				suggestion = &AttributeSuggestion{Attribute: attribute, Kind: kind}
+				byAttribute[attribute] = suggestion
+			}
+			if label.Confidence > suggestion.Confidence {
+				suggestion.Confidence = label.Confidence
+				suggestion.ImageIDs = append([]string{analysis.ImageID}, suggestion.ImageIDs...)
+			} else {
+				suggestion.ImageIDs = append(suggestion.ImageIDs, analysis.ImageID)
+			}
+		}
+	}
+
+	for _, suggestion := range byAttribute {
+		suggestion.ImageIDs = uniqueStrings(suggestion.ImageIDs)
+		if len(suggestion.ImageIDs) > maxAttributeSuggestionImageIDs {
+			suggestion.ImageIDs = suggestion.ImageIDs[:maxAttributeSuggestionImageIDs]
+		}
+		suggestions.Attributes = append(suggestions.Attributes, *suggestion)
+	}
+	sort.Slice(suggestions.Attributes, func(i, j int) bool {
+		if suggestions.Attributes[i].Confidence != suggestions.Attributes[j].Confidence {
+			return suggestions.Attributes[i].Confidence > suggestions.Attributes[j].Confidence
+		}
+		return suggestions.Attributes[i].Attribute < suggestions.Attributes[j].Attribute
+	})
+
+	if mainImage != nil && mainImage.ImageID != mainImageID {
+		suggestions.MainImage = mainImage
+	}
+	return suggestions
+}
+
+// propertyFeatures returns the feature flags of a property photos can suggest
+func propertyFeatures(property *Property) map[string]bool {
+	return map[string]bool{
+		"pool":             property.Pool,
+		"garden":           property.Garden,
+		"garage":           property.Garage,
+		"terrace":          property.Terrace,
+		"balcony":          property.Balcony,
+		"elevator":         property.Elevator,
+		"air_conditioning": property.AirConditioning,
+	}
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
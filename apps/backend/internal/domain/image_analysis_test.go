@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageAnalysis_CompleteAndFail(t *testing.T) {
+	now := time.Now()
+	analysis := NewImageAnalysis("img-1", "prop-1", now)
+
+	analysis.Complete("stub", []ImageLabel{{Name: "Garden", Confidence: 0.4}, {Name: "Swimming Pool", Confidence: 1.2}, {Name: "noise", Confidence: 0}}, now)
+	assert.Equal(t, ImageAnalysisAnalyzed, analysis.Status)
+	assert.Equal(t, []ImageLabel{{Name: "swimming_pool", Confidence: 1}, {Name: "garden", Confidence: 0.4}}, analysis.Labels)
+
+	failing := NewImageAnalysis("img-2", "prop-1", now)
+	for i := 1; i < MaxImageAnalysisAttempts; i++ {
+		failing.Fail("stub", errors.New("timeout"), now)
+		assert.Equal(t, ImageAnalysisPending, failing.Status, "failed analyses are retried")
+	}
+	failing.Fail("stub", errors.New("timeout"), now)
+	assert.Equal(t, ImageAnalysisFailed, failing.Status)
+	assert.Equal(t, "timeout", failing.Error)
+}
+
+func TestSuggestImageAttributes(t *testing.T) {
+	property := NewProperty("Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.Garage = true
+
+	analyzed := func(imageID string, labels ...ImageLabel) ImageAnalysis {
+		analysis := NewImageAnalysis(imageID, "prop-1", time.Now())
+		analysis.Complete("stub", labels, time.Now())
+		return *analysis
+	}
+	analyses := []ImageAnalysis{
+		analyzed("img-1", ImageLabel{Name: "living_room", Confidence: 0.9}, ImageLabel{Name: "fireplace", Confidence: 0.7}),
+		analyzed("img-2", ImageLabel{Name: "facade", Confidence: 0.85}, ImageLabel{Name: "garage", Confidence: 0.9}, ImageLabel{Name: "lawn", Confidence: 0.65}),
+		analyzed("img-3", ImageLabel{Name: "pool", Confidence: 0.95}, ImageLabel{Name: "garden", Confidence: 0.8}, ImageLabel{Name: "gym", Confidence: 0.3}),
+		*NewImageAnalysis("img-4", "prop-1", time.Now()),
+	}
+
+	suggestions := SuggestImageAttributes(property, []string{"fireplace"}, "img-1", analyses, DefaultImageSuggestionConfidence)
+	assert.Equal(t, 3, suggestions.Analyzed)
+	assert.Equal(t, 1, suggestions.Pending)
+
+	require.Len(t, suggestions.Attributes, 2, "garage and fireplace are already set; the gym is too uncertain")
+	assert.Equal(t, AttributeSuggestion{Attribute: "pool", Kind: AttributeSuggestionKindFeature, Confidence: 0.95, ImageIDs: []string{"img-3"}}, suggestions.Attributes[0])
+	assert.Equal(t, AttributeSuggestion{Attribute: "garden", Kind: AttributeSuggestionKindFeature, Confidence: 0.8, ImageIDs: []string{"img-3", "img-2"}}, suggestions.Attributes[1])
+
+	require.NotNil(t, suggestions.MainImage)
+	assert.Equal(t, "img-2", suggestions.MainImage.ImageID)
+	assert.Equal(t, "facade", suggestions.MainImage.Label)
+
+	suggestions = SuggestImageAttributes(property, nil, "img-2", analyses, DefaultImageSuggestionConfidence)
+	assert.Nil(t, suggestions.MainImage, "the current main image is already the best")
+	assert.Equal(t, AttributeSuggestionKindAmenity, suggestions.Attributes[2].Kind)
+	assert.Equal(t, "fireplace", suggestions.Attributes[2].Attribute)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// ImageCacheWarmReport summarizes a run pre-loading the image cache with the renditions
+// of featured and most viewed properties
+type ImageCacheWarmReport struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Properties int       `json:"properties"` // featured and most viewed, without duplicates
+	Images     int       `json:"images"`
+	Renditions int       `json:"renditions"` // thumbnails and variants loaded into the cache
+	Failed     int       `json:"failed"`
+	Errors     []string  `json:"errors,omitempty"` // first errors of the run
+}
+
+// maxImageCacheWarmErrors bounds the errors kept in a report, as a broken storage fails
+// every rendition the same way
+const maxImageCacheWarmErrors = 10
+
+// Duration returns how long the run took
+func (r *ImageCacheWarmReport) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// AddError records a rendition that could not be loaded
+func (r *ImageCacheWarmReport) AddError(err error) {
+	r.Failed++
+	if len(r.Errors) < maxImageCacheWarmErrors {
+		r.Errors = append(r.Errors, err.Error())
+	}
+}
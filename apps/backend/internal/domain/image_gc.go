@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// DefaultImageGCRetention is how old an orphaned file or record must be before it is removed.
+// It keeps the collector away from uploads still being written.
+const DefaultImageGCRetention = 72 * time.Hour
+
+// ImageGCReport summarizes a reconciliation run between image storage and the database
+type ImageGCReport struct {
+	StartedAt          time.Time `json:"started_at"`
+	FinishedAt         time.Time `json:"finished_at"`
+	DryRun             bool      `json:"dry_run"`
+	FilesScanned       int       `json:"files_scanned"`
+	RecordsScanned     int       `json:"records_scanned"`
+	OrphanFiles        int       `json:"orphan_files"`         // files without an image record
+	OrphanFilesDeleted int       `json:"orphan_files_deleted"` // orphans past the retention window
+	BytesReclaimed     int64     `json:"bytes_reclaimed"`
+	MissingFiles       int       `json:"missing_files"`   // records whose original file is gone
+	RecordsDeleted     int       `json:"records_deleted"` // broken records past the retention window
+	Errors             []string  `json:"errors,omitempty"`
+}
+
+// Duration returns how long the run took
+func (r *ImageGCReport) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// AddError records a non-fatal error of the run
+func (r *ImageGCReport) AddError(err error) {
+	r.Errors = append(r.Errors, err.Error())
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Image moderation statuses
+const (
+	ModerationStatusApproved    = "approved"    // passed automatic or manual review
+	ModerationStatusPending     = "pending"     // scoring failed, waiting for manual review
+	ModerationStatusFlagged     = "flagged"     // visible, but queued for manual review
+	ModerationStatusQuarantined = "quarantined" // hidden from listings until reviewed
+)
+
+// ModerationQueueStatuses lists the statuses shown in the admin review queue
+var ModerationQueueStatuses = []string{ModerationStatusPending, ModerationStatusFlagged, ModerationStatusQuarantined}
+
+// ModerationLabel is a category detected by a moderation provider with its confidence (0-1)
+type ModerationLabel struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ImageModeration records the moderation outcome of an uploaded image
+type ImageModeration struct {
+	ImageID    string            `json:"image_id"`
+	PropertyID string            `json:"property_id"`
+	Status     string            `json:"status"`
+	Score      float64           `json:"score"`
+	Labels     []ModerationLabel `json:"labels"`
+	Provider   string            `json:"provider"`
+	Error      string            `json:"error,omitempty"`
+	ReviewedBy string            `json:"reviewed_by,omitempty"`
+	ReviewNote string            `json:"review_note,omitempty"`
+	ReviewedAt *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// NewImageModeration creates a moderation record for an image
+func NewImageModeration(imageID, propertyID, provider string) *ImageModeration {
+	now := time.Now()
+	return &ImageModeration{
+		ImageID:    imageID,
+		PropertyID: propertyID,
+		Status:     ModerationStatusPending,
+		Labels:     []ModerationLabel{},
+		Provider:   provider,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// IsHidden checks if the image must be hidden from public listings
+func (m *ImageModeration) IsHidden() bool {
+	return m.Status == ModerationStatusQuarantined
+}
+
+// InQueue checks if the image is waiting for manual review
+func (m *ImageModeration) InQueue() bool {
+	for _, status := range ModerationQueueStatuses {
+		if m.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Approve marks the image as reviewed and acceptable
+func (m *ImageModeration) Approve(reviewer, note string) error {
+	if !m.InQueue() {
+		return fmt.Errorf("image is not awaiting review: %s", m.Status)
+	}
+	now := time.Now()
+	m.Status = ModerationStatusApproved
+	m.ReviewedBy = reviewer
+	m.ReviewNote = note
+	m.ReviewedAt = &now
+	m.UpdatedAt = now
+	return nil
+}
+
+// IsValidModerationStatus checks if a moderation status is valid
+func IsValidModerationStatus(status string) bool {
+	switch status {
+	case ModerationStatusApproved, ModerationStatusPending, ModerationStatusFlagged, ModerationStatusQuarantined:
+		return true
+	}
+	return false
+}
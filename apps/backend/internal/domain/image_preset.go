@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Crop modes of image presets
+const (
+	ImageCropFit  = "fit"  // scale the whole image within the box, keeping its aspect ratio
+	ImageCropFill = "fill" // crop the center to the aspect ratio of the box and fill it
+)
+
+// ImagePreset is a named rendition of images. Only presets can be requested publicly,
+// so clients cannot fill the cache and the storage with arbitrary sizes.
+type ImagePreset struct {
+	Name    string `json:"name"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Quality int    `json:"quality"`
+	Crop    string `json:"crop"`
+	Format  string `json:"format"` // jpg or png
+}
+
+// DefaultImagePresetsSpec are the presets used when none are configured
+const DefaultImagePresetsSpec = "thumb:150x150:80:fill:jpg,card:480x360:80:fill:jpg,gallery:1600x1200:85:fit:jpg," +
+	"hero:1920x1080:85:fill:jpg,og-image:1200x630:85:fill:jpg"
+
+// Key identifies the rendition of a preset in caches and storage, so changing the
+// definition of a preset does not serve renditions of the previous one
+func (p ImagePreset) Key() string {
+	return fmt.Sprintf("%s_%dx%d_q%d_%s.%s", p.Name, p.Width, p.Height, p.Quality, p.Crop, p.Format)
+}
+
+// ContentType returns the MIME type of the renditions of the preset
+func (p ImagePreset) ContentType() string {
+	return GetMimeTypeFromFilename("preset." + p.Format)
+}
+
+// ParseImagePresets parses presets in the form "name:WIDTHxHEIGHT:quality:crop:format,...",
+// e.g. "card:480x360:80:fill:jpg,gallery:1600x1200:85:fit:jpg"
+func ParseImagePresets(spec string) (map[string]ImagePreset, error) {
+	presets := make(map[string]ImagePreset)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 5 {
+			return nil, fmt.Errorf("invalid image preset %q: expected name:WIDTHxHEIGHT:quality:crop:format", entry)
+		}
+		preset := ImagePreset{
+			Name:   strings.ToLower(strings.TrimSpace(parts[0])),
+			Crop:   strings.ToLower(strings.TrimSpace(parts[3])),
+			Format: strings.ToLower(strings.TrimSpace(parts[4])),
+		}
+		if preset.Name == "" {
+			return nil, fmt.Errorf("invalid image preset %q: name is required", entry)
+		}
+		if _, exists := presets[preset.Name]; exists {
+			return nil, fmt.Errorf("image preset %s is defined twice", preset.Name)
+		}
+
+		width, height, ok := strings.Cut(strings.ToLower(strings.TrimSpace(parts[1])), "x")
+		var err error
+		if preset.Width, err = strconv.Atoi(width); !ok || err != nil || preset.Width <= 0 || preset.Width > MaxImageWidth {
+			return nil, fmt.Errorf("invalid size for image preset %s", preset.Name)
+		}
+		if preset.Height, err = strconv.Atoi(height); err != nil || preset.Height <= 0 || preset.Height > MaxImageHeight {
+			return nil, fmt.Errorf("invalid size for image preset %s", preset.Name)
+		}
+		if preset.Quality, err = strconv.Atoi(strings.TrimSpace(parts[2])); err != nil || preset.Quality < 1 || preset.Quality > 100 {
+			return nil, fmt.Errorf("invalid quality for image preset %s", preset.Name)
+		}
+		if preset.Crop != ImageCropFit && preset.Crop != ImageCropFill {
+			return nil, fmt.Errorf("invalid crop for image preset %s: must be fit or fill", preset.Name)
+		}
+		if preset.Format == "jpeg" {
+			preset.Format = "jpg"
+		}
+		if preset.Format != "jpg" && preset.Format != "png" {
+			return nil, fmt.Errorf("invalid format for image preset %s: must be jpg or png", preset.Name)
+		}
+
+		presets[preset.Name] = preset
+	}
+
+	if len(presets) == 0 {
+		return nil, fmt.Errorf("no image presets defined")
+	}
+	return presets, nil
+}
+
+// SortedImagePresets returns presets ordered by name
+func SortedImagePresets(presets map[string]ImagePreset) []ImagePreset {
+	sorted := make([]ImagePreset, 0, len(presets))
+	for _, preset := range presets {
+		sorted = append(sorted, preset)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImagePresets(t *testing.T) {
+	presets, err := ParseImagePresets(DefaultImagePresetsSpec)
+	require.NoError(t, err)
+	assert.Len(t, presets, 5)
+	assert.Equal(t, ImagePreset{Name: "og-image", Width: 1200, Height: 630, Quality: 85, Crop: ImageCropFill, Format: "jpg"}, presets["og-image"])
+	assert.Equal(t, "og-image_1200x630_q85_fill.jpg", presets["og-image"].Key())
+	assert.Equal(t, "image/jpeg", presets["og-image"].ContentType())
+
+	presets, err = ParseImagePresets(" Logo:300X100:90:fit:PNG , banner:1000x200:80:fill:jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", presets["logo"].ContentType())
+	assert.Equal(t, "jpg", presets["banner"].Format)
+	assert.Equal(t, []string{"banner", "logo"}, []string{SortedImagePresets(presets)[0].Name, SortedImagePresets(presets)[1].Name})
+
+	for _, spec := range []string{
+		"",
+		"card:480x360:80:fill",
+		"card:480:80:fill:jpg",
+		"card:0x360:80:fill:jpg",
+		"card:480x360:0:fill:jpg",
+		"card:480x360:80:stretch:jpg",
+		"card:480x360:80:fill:webp",
+		"card:480x360:80:fill:jpg,card:100x100:80:fill:jpg",
+	} {
+		_, err := ParseImagePresets(spec)
+		assert.Error(t, err, spec)
+	}
+}
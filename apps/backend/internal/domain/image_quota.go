@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultImageQuotaPlan is the plan of agencies without an explicit one
+const DefaultImageQuotaPlan = "basic"
+
+// ImageQuotaPlan limits the images an agency can keep
+type ImageQuotaPlan struct {
+	Name                 string `json:"name"`
+	MaxImagesPerProperty int    `json:"max_images_per_property"`
+	MaxStorageBytes      int64  `json:"max_storage_bytes"`
+}
+
+// DefaultImageQuotaPlans are used when no plans are configured
+var DefaultImageQuotaPlans = map[string]ImageQuotaPlan{
+	"basic":        {Name: "basic", MaxImagesPerProperty: 20, MaxStorageBytes: 1 << 30},         // 1GB
+	"professional": {Name: "professional", MaxImagesPerProperty: 50, MaxStorageBytes: 10 << 30}, // 10GB
+	"enterprise":   {Name: "enterprise", MaxImagesPerProperty: 100, MaxStorageBytes: 100 << 30}, // 100GB
+}
+
+// ParseImageQuotaPlans parses plans in the form "name:maxImagesPerProperty:storageMB,...",
+// e.g. "basic:20:1024,professional:50:10240"
+func ParseImageQuotaPlans(spec string) (map[string]ImageQuotaPlan, error) {
+	plans := make(map[string]ImageQuotaPlan)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid quota plan %q: expected name:max_images:storage_mb", entry)
+		}
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		maxImages, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || maxImages <= 0 {
+			return nil, fmt.Errorf("invalid max images for quota plan %s", name)
+		}
+		storageMB, err := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		if err != nil || storageMB <= 0 {
+			return nil, fmt.Errorf("invalid storage for quota plan %s", name)
+		}
+
+		plans[name] = ImageQuotaPlan{Name: name, MaxImagesPerProperty: maxImages, MaxStorageBytes: storageMB * 1024 * 1024}
+	}
+
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("no quota plans defined")
+	}
+	return plans, nil
+}
+
+// ImageStorageUsage is the image storage an agency currently uses
+type ImageStorageUsage struct {
+	ImageCount int   `json:"image_count"`
+	UsedBytes  int64 `json:"used_bytes"`
+}
+
+// ImageQuota reports an agency's image plan and usage
+type ImageQuota struct {
+	AgencyID             string  `json:"agency_id"`
+	Plan                 string  `json:"plan"`
+	MaxImagesPerProperty int     `json:"max_images_per_property"`
+	MaxStorageBytes      int64   `json:"max_storage_bytes"`
+	UsedBytes            int64   `json:"used_bytes"`
+	RemainingBytes       int64   `json:"remaining_bytes"`
+	ImageCount           int     `json:"image_count"`
+	UsedPercent          float64 `json:"used_percent"`
+}
+
+// NewImageQuota combines a plan with the current usage
+func NewImageQuota(agencyID string, plan ImageQuotaPlan, usage ImageStorageUsage) *ImageQuota {
+	quota := &ImageQuota{
+		AgencyID:             agencyID,
+		Plan:                 plan.Name,
+		MaxImagesPerProperty: plan.MaxImagesPerProperty,
+		MaxStorageBytes:      plan.MaxStorageBytes,
+		UsedBytes:            usage.UsedBytes,
+		ImageCount:           usage.ImageCount,
+	}
+	if remaining := plan.MaxStorageBytes - usage.UsedBytes; remaining > 0 {
+		quota.RemainingBytes = remaining
+	}
+	if plan.MaxStorageBytes > 0 {
+		quota.UsedPercent = float64(usage.UsedBytes) / float64(plan.MaxStorageBytes) * 100
+	}
+	return quota
+}
+
+// Allows reports whether additional bytes fit in the remaining storage
+func (q *ImageQuota) Allows(bytes int64) bool {
+	return q.UsedBytes+bytes <= q.MaxStorageBytes
+}
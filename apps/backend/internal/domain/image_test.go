@@ -626,4 +626,29 @@ func TestConstants(t *testing.T) {
 	assert.NotEmpty(t, SupportedMimeTypes)
 	assert.Contains(t, SupportedMimeTypes, "image/jpeg")
 	assert.Contains(t, SupportedMimeTypes, "image/png")
-}
\ No newline at end of file
+}
+func TestParseImageQuotaPlans(t *testing.T) {
+	plans, err := ParseImageQuotaPlans("Basic:20:1024, professional:50:10240")
+	assert.NoError(t, err)
+	assert.Len(t, plans, 2)
+	assert.Equal(t, ImageQuotaPlan{Name: "basic", MaxImagesPerProperty: 20, MaxStorageBytes: 1024 * 1024 * 1024}, plans["basic"])
+
+	for _, spec := range []string{"", "basic:20", "basic:x:1024", "basic:20:0"} {
+		_, err := ParseImageQuotaPlans(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestNewImageQuota(t *testing.T) {
+	plan := ImageQuotaPlan{Name: "basic", MaxImagesPerProperty: 20, MaxStorageBytes: 1000}
+
+	quota := NewImageQuota("agency-1", plan, ImageStorageUsage{ImageCount: 4, UsedBytes: 900})
+	assert.Equal(t, int64(100), quota.RemainingBytes)
+	assert.Equal(t, 90.0, quota.UsedPercent)
+	assert.True(t, quota.Allows(100))
+	assert.False(t, quota.Allows(101))
+
+	over := NewImageQuota("agency-1", plan, ImageStorageUsage{UsedBytes: 1200})
+	assert.Equal(t, int64(0), over.RemainingBytes)
+	assert.False(t, over.Allows(1))
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Inventory report periods
+const (
+	InventoryPeriodWeekly  = "weekly"
+	InventoryPeriodMonthly = "monthly"
+)
+
+// Inventory report limits
+const (
+	DefaultInventorySnapshots = 12
+	MaxInventorySnapshots     = 104
+)
+
+// InventorySnapshot summarizes the inventory of an agency over a week or month. Active
+// listings are counted at the end of the period, or when the snapshot was computed for
+// the current one. Days on market go from publication to closing; the discount compares
+// the list price with the final price of sales.
+type InventorySnapshot struct {
+	AgencyID           string    `json:"agency_id"`
+	Period             string    `json:"period"`
+	PeriodStart        time.Time `json:"period_start"`
+	PeriodEnd          time.Time `json:"period_end"`
+	ActiveListings     int       `json:"active_listings"`
+	NewListings        int       `json:"new_listings"`
+	SoldListings       int       `json:"sold_listings"`
+	RentedListings     int       `json:"rented_listings"`
+	AvgDaysOnMarket    float64   `json:"avg_days_on_market"`
+	AvgDiscountPercent float64   `json:"avg_discount_percent"`
+	ComputedAt         time.Time `json:"computed_at"`
+}
+
+// IsValidInventoryPeriod verifies if inventory reports can be grouped by a period
+func IsValidInventoryPeriod(period string) bool {
+	return period == InventoryPeriodWeekly || period == InventoryPeriodMonthly
+}
+
+// InventoryPeriodBounds returns the start and end of the period containing t, in UTC.
+// Weeks start on Monday.
+func InventoryPeriodBounds(period string, t time.Time) (time.Time, time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case InventoryPeriodWeekly:
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7), nil
+	case InventoryPeriodMonthly:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", period)
+	}
+}
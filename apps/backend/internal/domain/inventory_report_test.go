@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryPeriodBounds(t *testing.T) {
+	// Sunday 31 August 2025 belongs to the week starting Monday 25 August
+	sunday := time.Date(2025, 8, 31, 22, 30, 0, 0, time.UTC)
+
+	start, end, err := InventoryPeriodBounds(InventoryPeriodWeekly, sunday)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = InventoryPeriodBounds(InventoryPeriodMonthly, sunday)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = InventoryPeriodBounds("daily", sunday)
+	assert.ErrorContains(t, err, "invalid period")
+}
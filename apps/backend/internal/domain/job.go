@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job run statuses
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// Job run triggers
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// MaxJobRunMessageLength caps the summary and error stored for a run
+const MaxJobRunMessageLength = 1000
+
+// JobRun records one execution of a scheduled job
+type JobRun struct {
+	ID          string     `json:"id"`
+	JobName     string     `json:"job_name"`
+	Trigger     string     `json:"trigger"`
+	TriggeredBy string     `json:"triggered_by,omitempty"` // admin who triggered a manual run
+	Instance    string     `json:"instance"`               // host that ran the job
+	Status      string     `json:"status"`
+	Summary     string     `json:"summary,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+}
+
+// NewJobRun starts a run of a job
+func NewJobRun(jobName, trigger, triggeredBy, instance string, at time.Time) *JobRun {
+	return &JobRun{
+		ID:          uuid.New().String(),
+		JobName:     jobName,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Instance:    instance,
+		Status:      JobRunRunning,
+		StartedAt:   at,
+	}
+}
+
+// Finish records the outcome of the run
+func (r *JobRun) Finish(summary string, runErr error, at time.Time) {
+	r.Status = JobRunSucceeded
+	r.Summary = truncateJobMessage(summary)
+	if runErr != nil {
+		r.Status = JobRunFailed
+		r.Error = truncateJobMessage(runErr.Error())
+	}
+	r.FinishedAt = &at
+	r.DurationMs = at.Sub(r.StartedAt).Milliseconds()
+}
+
+// truncateJobMessage caps a message at MaxJobRunMessageLength bytes
+func truncateJobMessage(message string) string {
+	if len(message) > MaxJobRunMessageLength {
+		return message[:MaxJobRunMessageLength]
+	}
+	return message
+}
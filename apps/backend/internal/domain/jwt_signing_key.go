@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// JWTSigningKey is a stored JWT signing key pair. The private key is sealed with the
+// key encryption key and never leaves the API; the public key is published in the JWKS.
+type JWTSigningKey struct {
+	ID               string     `json:"id"`
+	Algorithm        string     `json:"algorithm"`
+	PublicKey        string     `json:"public_key"` // PKIX PEM
+	SealedPrivateKey string     `json:"-"`
+	CreatedAt        time.Time  `json:"created_at"`
+	RetiredAt        *time.Time `json:"retired_at,omitempty"` // replaced by a newer key
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"` // end of the grace period of a retired key
+}
+
+// IsCurrent verifies if the key signs new tokens
+func (k *JWTSigningKey) IsCurrent() bool {
+	return k.RetiredAt == nil
+}
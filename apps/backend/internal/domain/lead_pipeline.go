@@ -0,0 +1,304 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pipeline stage kinds. Leads in won and lost stages are closed.
+const (
+	PipelineStageOpen = "open"
+	PipelineStageWon  = "won"
+	PipelineStageLost = "lost"
+)
+
+// Lead stages of the default pipeline, used until an agency customizes its own
+const (
+	LeadStageNew            = "new"
+	LeadStageContacted      = "contacted"
+	LeadStageVisitScheduled = "visit_scheduled"
+	LeadStageNegotiating    = "negotiating"
+	LeadStageWon            = "won"
+	LeadStageLost           = "lost"
+)
+
+// Pipeline limits
+const (
+	MinPipelineStages  = 2
+	MaxPipelineStages  = 20
+	MaxLeadBoardLeads  = 500 // most recent leads placed on a board
+	maxStageNameLength = 50
+
+	DefaultStageAnalyticsDays = 90
+)
+
+var pipelineStageKeySeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// PipelineStage is a column of an agency's lead pipeline
+type PipelineStage struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// LeadPipeline is the ordered list of stages an agency moves its leads through. New
+// leads enter the first stage.
+type LeadPipeline struct {
+	AgencyID  string          `json:"agency_id"`
+	Stages    []PipelineStage `json:"stages"`
+	Custom    bool            `json:"custom"` // false while the agency uses the default pipeline
+	UpdatedAt *time.Time      `json:"updated_at,omitempty"`
+}
+
+// DefaultLeadPipeline returns the pipeline of agencies that did not customize theirs
+func DefaultLeadPipeline(agencyID string) *LeadPipeline {
+	return &LeadPipeline{
+		AgencyID: agencyID,
+		Stages: []PipelineStage{
+			{Key: LeadStageNew, Name: "Nuevo", Kind: PipelineStageOpen},
+			{Key: LeadStageContacted, Name: "Contactado", Kind: PipelineStageOpen},
+			{Key: LeadStageVisitScheduled, Name: "Visita agendada", Kind: PipelineStageOpen},
+			{Key: LeadStageNegotiating, Name: "Negociación", Kind: PipelineStageOpen},
+			{Key: LeadStageWon, Name: "Ganado", Kind: PipelineStageWon},
+			{Key: LeadStageLost, Name: "Perdido", Kind: PipelineStageLost},
+		},
+	}
+}
+
+// NewLeadPipeline creates a custom pipeline after validating its stages. Stage keys
+// default to the name, lowercased with words joined by underscores.
+func NewLeadPipeline(agencyID string, stages []PipelineStage, now time.Time) (*LeadPipeline, error) {
+	if len(stages) < MinPipelineStages || len(stages) > MaxPipelineStages {
+		return nil, fmt.Errorf("a pipeline needs between %d and %d stages", MinPipelineStages, MaxPipelineStages)
+	}
+
+	normalized := make([]PipelineStage, 0, len(stages))
+	seen := map[string]bool{}
+	for i, stage := range stages {
+		stage.Name = strings.TrimSpace(stage.Name)
+		if stage.Name == "" || len([]rune(stage.Name)) > maxStageNameLength {
+			return nil, fmt.Errorf("stage %d needs a name of at most %d characters", i+1, maxStageNameLength)
+		}
+		key := stage.Key
+		if strings.TrimSpace(key) == "" {
+			key = stage.Name
+		}
+		stage.Key = pipelineStageKey(key)
+		if stage.Key == "" {
+			return nil, fmt.Errorf("stage %d has an invalid key", i+1)
+		}
+		if seen[stage.Key] {
+			return nil, fmt.Errorf("stage key %s is repeated", stage.Key)
+		}
+		seen[stage.Key] = true
+
+		if stage.Kind == "" {
+			stage.Kind = PipelineStageOpen
+		}
+		switch stage.Kind {
+		case PipelineStageOpen, PipelineStageWon, PipelineStageLost:
+		default:
+			return nil, fmt.Errorf("stage %s has an unknown kind: %s", stage.Key, stage.Kind)
+		}
+		normalized = append(normalized, stage)
+	}
+	if normalized[0].Kind != PipelineStageOpen {
+		return nil, fmt.Errorf("the first stage must be open, as new leads enter it")
+	}
+
+	return &LeadPipeline{AgencyID: agencyID, Stages: normalized, Custom: true, UpdatedAt: &now}, nil
+}
+
+// pipelineStageKey lowercases a stage key, folds accents and joins its words with
+// underscores
+func pipelineStageKey(key string) string {
+	key = accentFolder.Replace(strings.ToLower(strings.TrimSpace(key)))
+	return strings.Trim(pipelineStageKeySeparators.ReplaceAllString(key, "_"), "_")
+}
+
+// EntryStage returns the stage new leads enter
+func (p *LeadPipeline) EntryStage() string {
+	return p.Stages[0].Key
+}
+
+// Stage returns the stage with the given key
+func (p *LeadPipeline) Stage(key string) (*PipelineStage, bool) {
+	for i := range p.Stages {
+		if p.Stages[i].Key == key {
+			return &p.Stages[i], true
+		}
+	}
+	return nil, false
+}
+
+// ColumnOf returns the stage a lead is shown in: leads in a stage the pipeline no
+// longer has are shown in the entry stage
+func (p *LeadPipeline) ColumnOf(stage string) string {
+	if _, ok := p.Stage(stage); ok {
+		return stage
+	}
+	return p.EntryStage()
+}
+
+// LeadStageTransition records a lead moving between pipeline stages, with how long it
+// stayed in the stage it left
+type LeadStageTransition struct {
+	ID              string    `json:"id"`
+	LeadID          string    `json:"lead_id"`
+	AgencyID        string    `json:"agency_id"`
+	FromStage       string    `json:"from_stage"`
+	ToStage         string    `json:"to_stage"`
+	ActorID         string    `json:"actor_id"`
+	DurationSeconds int64     `json:"duration_seconds"` // time spent in FromStage
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// MoveToStage moves a lead to a stage of its agency's pipeline and returns the
+// transition to record
+func (a *LeadAssignment) MoveToStage(pipeline *LeadPipeline, stage, actorID string, at time.Time) (*LeadStageTransition, error) {
+	if _, ok := pipeline.Stage(stage); !ok {
+		return nil, fmt.Errorf("unknown pipeline stage: %s", stage)
+	}
+	from := pipeline.ColumnOf(a.Stage)
+	if from == stage && from == a.Stage {
+		return nil, fmt.Errorf("lead is already in stage %s", stage)
+	}
+
+	transition := &LeadStageTransition{
+		ID:              uuid.New().String(),
+		LeadID:          a.ID,
+		AgencyID:        a.AgencyID,
+		FromStage:       a.Stage,
+		ToStage:         stage,
+		ActorID:         actorID,
+		DurationSeconds: int64(math.Max(at.Sub(a.StageEnteredAt).Seconds(), 0)),
+		CreatedAt:       at,
+	}
+	a.Stage = stage
+	a.StageEnteredAt = at
+	return transition, nil
+}
+
+// LeadBoardColumn is a stage of a kanban board with its most recent leads
+type LeadBoardColumn struct {
+	Stage PipelineStage    `json:"stage"`
+	Leads []LeadAssignment `json:"leads"`
+	Total int              `json:"total"` // leads in the stage, including those not listed
+}
+
+// LeadBoard groups the leads of an agency by pipeline stage
+type LeadBoard struct {
+	AgencyID string            `json:"agency_id"`
+	Columns  []LeadBoardColumn `json:"columns"`
+}
+
+// BuildLeadBoard places leads in the columns of a pipeline. counts are the leads per
+// stage, so columns report their totals even when not every lead is listed.
+func BuildLeadBoard(pipeline *LeadPipeline, leads []LeadAssignment, counts map[string]int) *LeadBoard {
+	board := &LeadBoard{AgencyID: pipeline.AgencyID, Columns: make([]LeadBoardColumn, len(pipeline.Stages))}
+	index := map[string]int{}
+	for i, stage := range pipeline.Stages {
+		board.Columns[i] = LeadBoardColumn{Stage: stage, Leads: []LeadAssignment{}}
+		index[stage.Key] = i
+	}
+	for _, lead := range leads {
+		column := &board.Columns[index[pipeline.ColumnOf(lead.Stage)]]
+		column.Leads = append(column.Leads, lead)
+	}
+	for stage, count := range counts {
+		board.Columns[index[pipeline.ColumnOf(stage)]].Total += count
+	}
+	return board
+}
+
+// StageDurationStats summarizes how long leads stay in a pipeline stage
+type StageDurationStats struct {
+	Stage        string  `json:"stage"`
+	Name         string  `json:"name"`
+	Current      int     `json:"current"` // leads in the stage now
+	Exits        int     `json:"exits"`   // leads that left the stage in the period
+	AverageHours float64 `json:"average_hours"`
+	MedianHours  float64 `json:"median_hours"`
+}
+
+// LeadStageAnalytics reports how long an agency's leads stay in each pipeline stage
+type LeadStageAnalytics struct {
+	AgencyID string               `json:"agency_id"`
+	AgentID  string               `json:"agent_id,omitempty"`
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Stages   []StageDurationStats `json:"stages"`
+}
+
+// StageDurationAnalytics computes the time leads spent in each stage of a pipeline
+// from the transitions out of it
+func StageDurationAnalytics(pipeline *LeadPipeline, transitions []LeadStageTransition, counts map[string]int) []StageDurationStats {
+	durations := map[string][]float64{}
+	for _, transition := range transitions {
+		stage := pipeline.ColumnOf(transition.FromStage)
+		durations[stage] = append(durations[stage], float64(transition.DurationSeconds)/3600)
+	}
+	current := map[string]int{}
+	for stage, count := range counts {
+		current[pipeline.ColumnOf(stage)] += count
+	}
+
+	stats := make([]StageDurationStats, len(pipeline.Stages))
+	for i, stage := range pipeline.Stages {
+		hours := durations[stage.Key]
+		stats[i] = StageDurationStats{Stage: stage.Key, Name: stage.Name, Current: current[stage.Key], Exits: len(hours)}
+		if len(hours) == 0 {
+			continue
+		}
+
+		sort.Float64s(hours)
+		total := 0.0
+		for _, h := range hours {
+			total += h
+		}
+		median := hours[len(hours)/2]
+		if len(hours)%2 == 0 {
+			median = (hours[len(hours)/2-1] + hours[len(hours)/2]) / 2
+		}
+		stats[i].AverageHours = math.Round(total/float64(len(hours))*10) / 10
+		stats[i].MedianHours = math.Round(median*10) / 10
+	}
+	return stats
+}
+
+// Lead timeline entry kinds
+const (
+	LeadTimelineRouted = "routed"
+	LeadTimelineStage  = "stage_change"
+	LeadTimelineCall   = "call"
+)
+
+// LeadTimelineEntry is an event in the history of a lead
+type LeadTimelineEntry struct {
+	Kind       string               `json:"kind"`
+	At         time.Time            `json:"at"`
+	Transition *LeadStageTransition `json:"transition,omitempty"`
+	Call       *LeadCall            `json:"call,omitempty"`
+}
+
+// BuildLeadTimeline merges the routing, stage changes and calls of a lead, oldest first
+func BuildLeadTimeline(lead *LeadAssignment, transitions []LeadStageTransition, calls []LeadCall) []LeadTimelineEntry {
+	timeline := make([]LeadTimelineEntry, 0, 1+len(transitions)+len(calls))
+	timeline = append(timeline, LeadTimelineEntry{Kind: LeadTimelineRouted, At: lead.CreatedAt})
+	for i := range transitions {
+		timeline = append(timeline, LeadTimelineEntry{Kind: LeadTimelineStage, At: transitions[i].CreatedAt, Transition: &transitions[i]})
+	}
+	for i := range calls {
+		timeline = append(timeline, LeadTimelineEntry{Kind: LeadTimelineCall, At: calls[i].StartedAt, Call: &calls[i]})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+	return timeline
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLeadPipeline(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewLeadPipeline("agency-1", []PipelineStage{{Name: "Nuevo"}}, now)
+	assert.ErrorContains(t, err, "between")
+	_, err = NewLeadPipeline("agency-1", []PipelineStage{{Name: "Ganado", Kind: PipelineStageWon}, {Name: "Nuevo"}}, now)
+	assert.ErrorContains(t, err, "first stage must be open")
+	_, err = NewLeadPipeline("agency-1", []PipelineStage{{Name: "Nuevo"}, {Key: "nuevo", Name: "Otro"}}, now)
+	assert.ErrorContains(t, err, "repeated")
+
+	pipeline, err := NewLeadPipeline("agency-1", []PipelineStage{
+		{Name: "Primer contacto"},
+		{Name: "Visita técnica"},
+		{Key: "closed-won", Name: "Cerrado", Kind: PipelineStageWon},
+	}, now)
+	require.NoError(t, err)
+	assert.True(t, pipeline.Custom)
+	assert.Equal(t, "primer_contacto", pipeline.EntryStage())
+	assert.Equal(t, "visita_tecnica", pipeline.Stages[1].Key)
+	assert.Equal(t, PipelineStageOpen, pipeline.Stages[1].Kind)
+	assert.Equal(t, "closed_won", pipeline.Stages[2].Key)
+	assert.Equal(t, "primer_contacto", pipeline.ColumnOf(LeadStageNew), "stages the pipeline lacks show in the entry stage")
+}
+
+func TestLeadAssignment_MoveToStage(t *testing.T) {
+	created := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+	pipeline := DefaultLeadPipeline("agency-1")
+	lead := NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-1", LeadScore{}, created)
+
+	_, err := lead.MoveToStage(pipeline, "closing", "agent-1", created)
+	assert.ErrorContains(t, err, "unknown pipeline stage")
+	_, err = lead.MoveToStage(pipeline, LeadStageNew, "agent-1", created)
+	assert.ErrorContains(t, err, "already in stage")
+
+	transition, err := lead.MoveToStage(pipeline, LeadStageContacted, "agent-1", created.Add(26*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, LeadStageNew, transition.FromStage)
+	assert.Equal(t, int64(26*3600), transition.DurationSeconds)
+	assert.Equal(t, LeadStageContacted, lead.Stage)
+	assert.Equal(t, created.Add(26*time.Hour), lead.StageEnteredAt)
+}
+
+func TestBuildLeadBoardAndAnalytics(t *testing.T) {
+	pipeline := DefaultLeadPipeline("agency-1")
+	leads := []LeadAssignment{
+		{ID: "lead-1", Stage: LeadStageNew},
+		{ID: "lead-2", Stage: LeadStageContacted},
+		{ID: "lead-3", Stage: "removed_stage"},
+	}
+	board := BuildLeadBoard(pipeline, leads, map[string]int{LeadStageNew: 4, LeadStageContacted: 1, "removed_stage": 1})
+	require.Len(t, board.Columns, len(pipeline.Stages))
+	assert.Len(t, board.Columns[0].Leads, 2)
+	assert.Equal(t, 5, board.Columns[0].Total)
+	assert.Equal(t, 1, board.Columns[1].Total)
+	assert.Empty(t, board.Columns[5].Leads)
+
+	stats := StageDurationAnalytics(pipeline, []LeadStageTransition{
+		{FromStage: LeadStageNew, DurationSeconds: 3600},
+		{FromStage: LeadStageNew, DurationSeconds: 3 * 3600},
+		{FromStage: LeadStageNew, DurationSeconds: 8 * 3600},
+		{FromStage: LeadStageContacted, DurationSeconds: 48 * 3600},
+	}, map[string]int{LeadStageNew: 4})
+	assert.Equal(t, StageDurationStats{Stage: LeadStageNew, Name: "Nuevo", Current: 4, Exits: 3, AverageHours: 4, MedianHours: 3}, stats[0])
+	assert.Equal(t, 48.0, stats[1].MedianHours)
+	assert.Zero(t, stats[2].Exits)
+}
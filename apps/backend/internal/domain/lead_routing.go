@@ -0,0 +1,345 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Lead routing strategies. Sector and language rules route the leads they match among
+// their agents; round-robin rules take every lead, typically as the last rule.
+const (
+	LeadRoutingRoundRobin = "round_robin"
+	LeadRoutingSector     = "sector"
+	LeadRoutingLanguage   = "language"
+)
+
+// Lead grades, from the lead score
+const (
+	LeadGradeHot  = "hot"
+	LeadGradeWarm = "warm"
+	LeadGradeCold = "cold"
+)
+
+// Lead scoring weights; the score of a lead is out of 100
+const (
+	LeadScoreBudgetWeight        = 50
+	LeadScorePhoneWeight         = 20
+	LeadScoreMessageWeight       = 30
+	LeadHotScore                 = 70
+	LeadWarmScore                = 40
+	leadMessageDetailedLength    = 120
+	leadMessageMinimumLength     = 20
+	MaxLeadRoutingRulesPerAgency = 50
+	MaxLeadRoutingRuleAgents     = 100
+)
+
+// Lead assignment outcomes that did not reach a routing rule
+const (
+	LeadAssignedListingAgent = "listing_agent" // no rule matched; the listing's agent keeps it
+	LeadUnassigned           = "unassigned"    // no rule matched and the listing has no agent
+)
+
+var leadMessageLink = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// IsValidLeadRoutingStrategy checks if a routing strategy is known
+func IsValidLeadRoutingStrategy(strategy string) bool {
+	switch strategy {
+	case LeadRoutingRoundRobin, LeadRoutingSector, LeadRoutingLanguage:
+		return true
+	}
+	return false
+}
+
+// LeadSignals are what a lead is scored on
+type LeadSignals struct {
+	MonthlyIncome float64
+	MonthlyRent   float64
+	PhoneVerified bool
+	Message       string
+}
+
+// LeadScore rates how likely an inquiry is to turn into a deal
+type LeadScore struct {
+	Total          int      `json:"total"`
+	Grade          string   `json:"grade"`
+	BudgetMatch    int      `json:"budget_match"`
+	PhoneVerified  int      `json:"phone_verified"`
+	MessageQuality int      `json:"message_quality"`
+	Reasons        []string `json:"reasons"`
+}
+
+// ScoreLead scores an inquiry on how its budget matches the listing, whether the
+// inquirer verified their phone and the quality of their message
+func ScoreLead(signals LeadSignals) LeadScore {
+	score := LeadScore{Reasons: []string{}}
+
+	if signals.MonthlyRent > 0 && signals.MonthlyIncome > 0 {
+		ratio := signals.MonthlyIncome / signals.MonthlyRent
+		score.BudgetMatch = int(math.Round(math.Min(ratio/MinIncomeToRentRatio, 1) * LeadScoreBudgetWeight))
+		if ratio >= MinIncomeToRentRatio {
+			score.Reasons = append(score.Reasons, "budget matches the listing")
+		} else {
+			score.Reasons = append(score.Reasons, fmt.Sprintf("income is %.1f times the rent", ratio))
+		}
+	}
+
+	if signals.PhoneVerified {
+		score.PhoneVerified = LeadScorePhoneWeight
+		score.Reasons = append(score.Reasons, "phone verified")
+	}
+
+	score.MessageQuality = scoreLeadMessage(signals.Message)
+	switch {
+	case score.MessageQuality >= LeadScoreMessageWeight*2/3:
+		score.Reasons = append(score.Reasons, "detailed message")
+	case score.MessageQuality == 0:
+		score.Reasons = append(score.Reasons, "no useful message")
+	}
+
+	score.Total = score.BudgetMatch + score.PhoneVerified + score.MessageQuality
+	switch {
+	case score.Total >= LeadHotScore:
+		score.Grade = LeadGradeHot
+	case score.Total >= LeadWarmScore:
+		score.Grade = LeadGradeWarm
+	default:
+		score.Grade = LeadGradeCold
+	}
+	return score
+}
+
+// scoreLeadMessage rates a message out of LeadScoreMessageWeight: longer messages score
+// higher, questions add a little, and shouting or links score nothing
+func scoreLeadMessage(message string) int {
+	message = strings.TrimSpace(message)
+	length := len([]rune(message))
+	if length < leadMessageMinimumLength || leadMessageLink.MatchString(message) || isShouting(message) {
+		return 0
+	}
+
+	points := LeadScoreMessageWeight * 2 / 3 * min(length, leadMessageDetailedLength) / leadMessageDetailedLength
+	if strings.Contains(message, "?") {
+		points += LeadScoreMessageWeight / 3
+	}
+	return min(points, LeadScoreMessageWeight)
+}
+
+// isShouting reports whether most letters of a message are upper case
+func isShouting(message string) bool {
+	letters, upper := 0, 0
+	for _, r := range message {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters > 0 && upper*10 > letters*7
+}
+
+// LeadContext is what routing rules match a lead on
+type LeadContext struct {
+	Sector   string // sector of the listing
+	Language string // locale of the inquirer
+	Score    int
+}
+
+// LeadRoutingRule routes the leads of an agency's listings to its agents. Rules are
+// tried by ascending priority and the first matching rule with an active agent takes
+// the lead, handing leads out round-robin among its agents.
+type LeadRoutingRule struct {
+	ID        string    `json:"id"`
+	AgencyID  string    `json:"agency_id"`
+	Name      string    `json:"name"`
+	Strategy  string    `json:"strategy"`
+	Priority  int       `json:"priority"`
+	Sectors   []string  `json:"sectors"`   // sector rules
+	Languages []string  `json:"languages"` // language rules
+	AgentIDs  []string  `json:"agent_ids"` // every agent of the agency when empty, for round-robin rules
+	MinScore  int       `json:"min_score"` // leads scoring less are left to later rules
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LeadRoutingRuleRequest describes a routing rule
+type LeadRoutingRuleRequest struct {
+	Name      string   `json:"name"`
+	Strategy  string   `json:"strategy"`
+	Priority  int      `json:"priority"`
+	Sectors   []string `json:"sectors"`
+	Languages []string `json:"languages"`
+	AgentIDs  []string `json:"agent_ids"`
+	MinScore  int      `json:"min_score"`
+	Active    *bool    `json:"active,omitempty"` // defaults to true
+}
+
+// NewLeadRoutingRule creates a rule of an agency
+func NewLeadRoutingRule(agencyID string, req LeadRoutingRuleRequest, now time.Time) (*LeadRoutingRule, error) {
+	rule := &LeadRoutingRule{
+		ID:        uuid.New().String(),
+		AgencyID:  agencyID,
+		Active:    true,
+		CreatedAt: now,
+	}
+	if err := rule.Apply(req, now); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Apply replaces the settings of a rule after validating them
+func (r *LeadRoutingRule) Apply(req LeadRoutingRuleRequest, now time.Time) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	if !IsValidLeadRoutingStrategy(req.Strategy) {
+		return fmt.Errorf("unknown routing strategy: %s", req.Strategy)
+	}
+	if req.MinScore < 0 || req.MinScore > 100 {
+		return fmt.Errorf("minimum score must be between 0 and 100")
+	}
+
+	agentIDs := uniqueStrings(trimmedStrings(req.AgentIDs))
+	if len(agentIDs) > MaxLeadRoutingRuleAgents {
+		return fmt.Errorf("at most %d agents per rule are allowed", MaxLeadRoutingRuleAgents)
+	}
+	sectors := []string{}
+	languages := []string{}
+	switch req.Strategy {
+	case LeadRoutingSector:
+		for _, sector := range req.Sectors {
+			if slug := SlugifyPlace(sector); slug != "" {
+				sectors = append(sectors, slug)
+			}
+		}
+		if len(sectors) == 0 {
+			return fmt.Errorf("sector rules need at least one sector")
+		}
+	case LeadRoutingLanguage:
+		for _, language := range req.Languages {
+			locale := NormalizeLocale(language)
+			if !IsSupportedLocale(locale) {
+				return fmt.Errorf("unsupported language: %s", language)
+			}
+			languages = append(languages, locale)
+		}
+		if len(languages) == 0 {
+			return fmt.Errorf("language rules need at least one language")
+		}
+	}
+	if req.Strategy != LeadRoutingRoundRobin && len(agentIDs) == 0 {
+		return fmt.Errorf("%s rules need at least one agent", req.Strategy)
+	}
+
+	r.Name = name
+	r.Strategy = req.Strategy
+	r.Priority = req.Priority
+	r.Sectors = uniqueStrings(sectors)
+	r.Languages = uniqueStrings(languages)
+	r.AgentIDs = agentIDs
+	r.MinScore = req.MinScore
+	if req.Active != nil {
+		r.Active = *req.Active
+	}
+	r.UpdatedAt = now
+	return nil
+}
+
+// Matches reports whether the rule takes a lead
+func (r *LeadRoutingRule) Matches(lead LeadContext) bool {
+	if !r.Active || lead.Score < r.MinScore {
+		return false
+	}
+	switch r.Strategy {
+	case LeadRoutingSector:
+		return containsString(r.Sectors, SlugifyPlace(lead.Sector))
+	case LeadRoutingLanguage:
+		return containsString(r.Languages, NormalizeLocale(lead.Language))
+	}
+	return true
+}
+
+// SortLeadRoutingRules orders rules by priority, oldest first among equal priorities
+func SortLeadRoutingRules(rules []LeadRoutingRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+}
+
+// LeadAssignment is the routing outcome of a lead
+type LeadAssignment struct {
+	ID             string    `json:"id"`
+	ApplicationID  string    `json:"application_id"`
+	PropertyID     string    `json:"property_id"`
+	AgencyID       string    `json:"agency_id"`
+	AgentID        *string   `json:"agent_id,omitempty"`
+	RuleID         *string   `json:"rule_id,omitempty"`
+	Outcome        string    `json:"outcome"` // strategy of the rule, listing_agent or unassigned
+	Score          LeadScore `json:"score"`
+	Stage          string    `json:"stage"` // pipeline stage, see LeadPipeline
+	StageEnteredAt time.Time `json:"stage_entered_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewLeadAssignment records the routing of a lead, in the new stage of the default
+// pipeline. A nil rule means no rule matched and the lead went to agentID, the
+// listing's agent, if any.
+func NewLeadAssignment(applicationID, propertyID, agencyID string, rule *LeadRoutingRule, agentID string, score LeadScore, now time.Time) *LeadAssignment {
+	assignment := &LeadAssignment{
+		ID:             uuid.New().String(),
+		ApplicationID:  applicationID,
+		PropertyID:     propertyID,
+		AgencyID:       agencyID,
+		Score:          score,
+		Stage:          LeadStageNew,
+		StageEnteredAt: now,
+		CreatedAt:      now,
+	}
+	if agentID != "" {
+		assignment.AgentID = &agentID
+	}
+	switch {
+	case rule != nil:
+		assignment.RuleID = &rule.ID
+		assignment.Outcome = rule.Strategy
+	case agentID != "":
+		assignment.Outcome = LeadAssignedListingAgent
+	default:
+		assignment.Outcome = LeadUnassigned
+	}
+	return assignment
+}
+
+// LeadAssignmentFilter selects the routing outcomes of an agency
+type LeadAssignmentFilter struct {
+	AgencyID string
+	AgentID  string
+	Outcome  string
+	Stage    string
+	Limit    int
+	Offset   int
+}
+
+// trimmedStrings trims values and drops empty ones
+func trimmedStrings(values []string) []string {
+	trimmed := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreLead(t *testing.T) {
+	hot := ScoreLead(LeadSignals{
+		MonthlyIncome: 3000,
+		MonthlyRent:   900,
+		PhoneVerified: true,
+		Message:       "Hola, me interesa el departamento para mudarme en octubre con mi esposa. ¿Se aceptan mascotas pequeñas y está disponible para visitar el sábado?",
+	})
+	assert.Equal(t, LeadScoreBudgetWeight, hot.BudgetMatch)
+	assert.Equal(t, LeadScorePhoneWeight, hot.PhoneVerified)
+	assert.Equal(t, LeadScoreMessageWeight, hot.MessageQuality)
+	assert.Equal(t, 100, hot.Total)
+	assert.Equal(t, LeadGradeHot, hot.Grade)
+
+	cold := ScoreLead(LeadSignals{MonthlyIncome: 900, MonthlyRent: 900, Message: "LLAMEME YA AL 0991234567 URGENTE"})
+	assert.Equal(t, 17, cold.BudgetMatch)
+	assert.Zero(t, cold.MessageQuality, "shouting scores nothing")
+	assert.Equal(t, LeadGradeCold, cold.Grade)
+	assert.Contains(t, cold.Reasons, "income is 1.0 times the rent")
+
+	assert.Zero(t, ScoreLead(LeadSignals{Message: "Más información en https://example.com por favor"}).MessageQuality)
+}
+
+func TestLeadRoutingRule_ApplyAndMatch(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewLeadRoutingRule("agency-1", LeadRoutingRuleRequest{Name: "Cumbayá", Strategy: LeadRoutingSector, AgentIDs: []string{"agent-1"}}, now)
+	assert.ErrorContains(t, err, "at least one sector")
+	_, err = NewLeadRoutingRule("agency-1", LeadRoutingRuleRequest{Name: "English", Strategy: LeadRoutingLanguage, Languages: []string{"en"}}, now)
+	assert.ErrorContains(t, err, "at least one agent")
+	_, err = NewLeadRoutingRule("agency-1", LeadRoutingRuleRequest{Name: "Klingon", Strategy: LeadRoutingLanguage, Languages: []string{"tlh"}, AgentIDs: []string{"agent-1"}}, now)
+	assert.ErrorContains(t, err, "unsupported language")
+
+	sector, err := NewLeadRoutingRule("agency-1", LeadRoutingRuleRequest{
+		Name:     " Valle ",
+		Strategy: LeadRoutingSector,
+		Sectors:  []string{"Cumbayá", "Tumbaco"},
+		AgentIDs: []string{"agent-1", " agent-1 ", "agent-2"},
+		MinScore: 40,
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Valle", sector.Name)
+	assert.Equal(t, []string{"cumbaya", "tumbaco"}, sector.Sectors)
+	assert.Equal(t, []string{"agent-1", "agent-2"}, sector.AgentIDs)
+	assert.True(t, sector.Active)
+
+	assert.True(t, sector.Matches(LeadContext{Sector: "CUMBAYA", Score: 60}))
+	assert.False(t, sector.Matches(LeadContext{Sector: "Cumbayá", Score: 30}), "leads below the minimum score fall through")
+	assert.False(t, sector.Matches(LeadContext{Sector: "La Carolina", Score: 60}))
+
+	language, err := NewLeadRoutingRule("agency-1", LeadRoutingRuleRequest{Name: "English", Strategy: LeadRoutingLanguage, Languages: []string{"en-US"}, AgentIDs: []string{"agent-3"}}, now)
+	require.NoError(t, err)
+	assert.True(t, language.Matches(LeadContext{Language: "en"}))
+	assert.False(t, language.Matches(LeadContext{Language: "es"}))
+
+	inactive := false
+	roundRobin, err := NewLeadRoutingRule("agency-1", LeadRoutingRuleRequest{Name: "Everyone", Strategy: LeadRoutingRoundRobin, Active: &inactive}, now)
+	require.NoError(t, err)
+	assert.False(t, roundRobin.Matches(LeadContext{}))
+
+	rules := []LeadRoutingRule{*roundRobin, *sector, *language}
+	rules[0].Priority = 10
+	SortLeadRoutingRules(rules)
+	assert.Equal(t, []string{"Valle", "English", "Everyone"}, []string{rules[0].Name, rules[1].Name, rules[2].Name})
+}
+
+func TestNewLeadAssignment(t *testing.T) {
+	now := time.Now()
+	rule := &LeadRoutingRule{ID: "rule-1", Strategy: LeadRoutingLanguage}
+
+	routed := NewLeadAssignment("app-1", "prop-1", "agency-1", rule, "agent-3", LeadScore{}, now)
+	assert.Equal(t, LeadRoutingLanguage, routed.Outcome)
+	assert.Equal(t, "rule-1", *routed.RuleID)
+
+	assert.Equal(t, LeadAssignedListingAgent, NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-1", LeadScore{}, now).Outcome)
+	unassigned := NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "", LeadScore{}, now)
+	assert.Equal(t, LeadUnassigned, unassigned.Outcome)
+	assert.Nil(t, unassigned.AgentID)
+}
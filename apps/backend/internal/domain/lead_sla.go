@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lead response SLA defaults and limits
+const (
+	DefaultLeadResponseTargetMinutes = 60
+	MinLeadResponseMinutes           = 5
+	MaxLeadResponseMinutes           = 7 * 24 * 60
+	// MaxLeadReassignments stops rotating a lead nobody answers
+	MaxLeadReassignments = 3
+
+	DefaultResponseReportDays = 30
+)
+
+// Kinds of first responses to a lead: a masked call, moving it in the pipeline or
+// reviewing the inquiry behind it
+const (
+	LeadResponseCall              = "call"
+	LeadResponseStageChange       = "stage_change"
+	LeadResponseApplicationStatus = "application_status"
+)
+
+// LeadResponsePolicy is how fast an agency expects its agents to answer new leads, and
+// when unanswered leads move to another agent
+type LeadResponsePolicy struct {
+	AgencyID             string     `json:"agency_id"`
+	TargetMinutes        int        `json:"target_minutes"`
+	ReassignAfterMinutes int        `json:"reassign_after_minutes"` // 0 keeps unanswered leads with their agent
+	Custom               bool       `json:"custom"`                 // false while the agency uses the default policy
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultLeadResponsePolicy returns the policy of agencies that did not set theirs
+func DefaultLeadResponsePolicy(agencyID string) *LeadResponsePolicy {
+	return &LeadResponsePolicy{AgencyID: agencyID, TargetMinutes: DefaultLeadResponseTargetMinutes}
+}
+
+// NewLeadResponsePolicy creates the response policy of an agency after validating it.
+// Leads are reassigned no sooner than the target.
+func NewLeadResponsePolicy(agencyID string, targetMinutes, reassignAfterMinutes int, now time.Time) (*LeadResponsePolicy, error) {
+	if targetMinutes < MinLeadResponseMinutes || targetMinutes > MaxLeadResponseMinutes {
+		return nil, fmt.Errorf("the response target must be between %d and %d minutes", MinLeadResponseMinutes, MaxLeadResponseMinutes)
+	}
+	if reassignAfterMinutes != 0 && (reassignAfterMinutes < targetMinutes || reassignAfterMinutes > MaxLeadResponseMinutes) {
+		return nil, fmt.Errorf("leads can be reassigned between the response target and %d minutes, or never (0)", MaxLeadResponseMinutes)
+	}
+	return &LeadResponsePolicy{
+		AgencyID:             agencyID,
+		TargetMinutes:        targetMinutes,
+		ReassignAfterMinutes: reassignAfterMinutes,
+		Custom:               true,
+		UpdatedAt:            &now,
+	}, nil
+}
+
+// Target returns the response target as a duration
+func (p *LeadResponsePolicy) Target() time.Duration {
+	return time.Duration(p.TargetMinutes) * time.Minute
+}
+
+// LeadResponse is the first response to a lead. Its time is counted from when the
+// lead reached the agent who answered: its routing, or its last reassignment.
+type LeadResponse struct {
+	LeadID          string    `json:"lead_id"`
+	AgencyID        string    `json:"agency_id"`
+	AgentID         string    `json:"agent_id,omitempty"` // agent the lead was assigned to
+	Kind            string    `json:"kind"`
+	ResponderID     string    `json:"responder_id,omitempty"`
+	ResponseSeconds int64     `json:"response_seconds"`
+	RespondedAt     time.Time `json:"responded_at"`
+}
+
+// NewLeadResponse records the first response to a lead assigned since assignedAt
+func NewLeadResponse(lead *LeadAssignment, assignedAt time.Time, kind, responderID string, at time.Time) *LeadResponse {
+	response := &LeadResponse{
+		LeadID:          lead.ID,
+		AgencyID:        lead.AgencyID,
+		Kind:            kind,
+		ResponderID:     responderID,
+		ResponseSeconds: int64(math.Max(at.Sub(assignedAt).Seconds(), 0)),
+		RespondedAt:     at,
+	}
+	if lead.AgentID != nil {
+		response.AgentID = *lead.AgentID
+	}
+	return response
+}
+
+// LeadReassignment records an unanswered lead moving to another agent
+type LeadReassignment struct {
+	ID          string    `json:"id"`
+	LeadID      string    `json:"lead_id"`
+	AgencyID    string    `json:"agency_id"`
+	FromAgentID string    `json:"from_agent_id"`
+	ToAgentID   string    `json:"to_agent_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Reassign moves an unanswered lead to another agent and returns the reassignment to record
+func (a *LeadAssignment) Reassign(agentID string, at time.Time) (*LeadReassignment, error) {
+	if a.AgentID == nil {
+		return nil, fmt.Errorf("lead %s has no agent to reassign from", a.ID)
+	}
+	if *a.AgentID == agentID {
+		return nil, fmt.Errorf("lead %s is already assigned to %s", a.ID, agentID)
+	}
+	reassignment := &LeadReassignment{
+		ID:          uuid.New().String(),
+		LeadID:      a.ID,
+		AgencyID:    a.AgencyID,
+		FromAgentID: *a.AgentID,
+		ToAgentID:   agentID,
+		CreatedAt:   at,
+	}
+	a.AgentID = &agentID
+	return reassignment, nil
+}
+
+// NextLeadAgent returns the agent after current among candidates, in ID order, so
+// unanswered leads rotate through an agency's agents. It returns "" when nobody else
+// can take the lead.
+func NextLeadAgent(candidates []string, current string) string {
+	others := []string{}
+	for _, id := range candidates {
+		if id != current {
+			others = append(others, id)
+		}
+	}
+	if len(others) == 0 {
+		return ""
+	}
+	sort.Strings(others)
+	for _, id := range others {
+		if id > current {
+			return id
+		}
+	}
+	return others[0]
+}
+
+// OverdueLead is an unanswered lead past its agency's reassignment window
+type OverdueLead struct {
+	LeadID        string
+	AssignedAt    time.Time // routing or last reassignment
+	Reassignments int
+}
+
+// LeadResponseSample is a lead of a response report with its first response, if any
+type LeadResponseSample struct {
+	LeadID     string
+	AgentID    string // the agent who answered, or who holds the unanswered lead
+	AssignedAt time.Time
+	Response   *LeadResponse
+}
+
+// LeadResponseStats summarizes how fast leads were answered. Compliance is the share
+// of leads answered within the target among those already due: answered, or
+// unanswered past the target.
+type LeadResponseStats struct {
+	AgentID        string  `json:"agent_id,omitempty"`
+	Leads          int     `json:"leads"`
+	Responded      int     `json:"responded"`
+	WithinTarget   int     `json:"within_target"`
+	Overdue        int     `json:"overdue"` // unanswered past the target
+	ComplianceRate float64 `json:"compliance_rate"`
+	AverageMinutes float64 `json:"average_minutes"`
+	MedianMinutes  float64 `json:"median_minutes"`
+}
+
+// LeadResponseReport reports how fast an agency and each of its agents answer leads
+type LeadResponseReport struct {
+	AgencyID string              `json:"agency_id"`
+	Policy   LeadResponsePolicy  `json:"policy"`
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Agency   LeadResponseStats   `json:"agency"`
+	Agents   []LeadResponseStats `json:"agents"`
+}
+
+// BuildLeadResponseReport computes the response stats of the leads routed in a period,
+// for the agency and per agent
+func BuildLeadResponseReport(policy *LeadResponsePolicy, samples []LeadResponseSample, from, to, now time.Time) *LeadResponseReport {
+	report := &LeadResponseReport{AgencyID: policy.AgencyID, Policy: *policy, From: from, To: to, Agents: []LeadResponseStats{}}
+
+	byAgent := map[string][]LeadResponseSample{}
+	for _, sample := range samples {
+		byAgent[sample.AgentID] = append(byAgent[sample.AgentID], sample)
+	}
+	agents := make([]string, 0, len(byAgent))
+	for agentID := range byAgent {
+		agents = append(agents, agentID)
+	}
+	sort.Strings(agents)
+
+	report.Agency = leadResponseStats(policy.Target(), samples, now)
+	for _, agentID := range agents {
+		stats := leadResponseStats(policy.Target(), byAgent[agentID], now)
+		stats.AgentID = agentID
+		report.Agents = append(report.Agents, stats)
+	}
+	return report
+}
+
+func leadResponseStats(target time.Duration, samples []LeadResponseSample, now time.Time) LeadResponseStats {
+	stats := LeadResponseStats{Leads: len(samples)}
+	minutes := []float64{}
+	for _, sample := range samples {
+		if sample.Response == nil {
+			if now.Sub(sample.AssignedAt) > target {
+				stats.Overdue++
+			}
+			continue
+		}
+		stats.Responded++
+		if time.Duration(sample.Response.ResponseSeconds)*time.Second <= target {
+			stats.WithinTarget++
+		}
+		minutes = append(minutes, float64(sample.Response.ResponseSeconds)/60)
+	}
+
+	if due := stats.Responded + stats.Overdue; due > 0 {
+		stats.ComplianceRate = math.Round(float64(stats.WithinTarget)/float64(due)*1000) / 10
+	}
+	if len(minutes) > 0 {
+		sort.Float64s(minutes)
+		total := 0.0
+		for _, m := range minutes {
+			total += m
+		}
+		median := minutes[len(minutes)/2]
+		if len(minutes)%2 == 0 {
+			median = (minutes[len(minutes)/2-1] + minutes[len(minutes)/2]) / 2
+		}
+		stats.AverageMinutes = math.Round(total/float64(len(minutes))*10) / 10
+		stats.MedianMinutes = math.Round(median*10) / 10
+	}
+	return stats
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLeadResponsePolicy(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewLeadResponsePolicy("agency-1", 1, 0, now)
+	assert.ErrorContains(t, err, "between 5 and")
+	_, err = NewLeadResponsePolicy("agency-1", 60, 30, now)
+	assert.ErrorContains(t, err, "reassigned between the response target")
+
+	policy, err := NewLeadResponsePolicy("agency-1", 30, 120, now)
+	require.NoError(t, err)
+	assert.True(t, policy.Custom)
+	assert.Equal(t, 30*time.Minute, policy.Target())
+}
+
+func TestLeadAssignment_Reassign(t *testing.T) {
+	now := time.Date(2025, 9, 25, 9, 0, 0, 0, time.UTC)
+	lead := NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-b", LeadScore{}, now)
+
+	_, err := lead.Reassign("agent-b", now)
+	assert.ErrorContains(t, err, "already assigned")
+
+	reassignment, err := lead.Reassign(NextLeadAgent([]string{"agent-a", "agent-b", "agent-c"}, "agent-b"), now)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-b", reassignment.FromAgentID)
+	assert.Equal(t, "agent-c", *lead.AgentID)
+
+	assert.Equal(t, "agent-a", NextLeadAgent([]string{"agent-c", "agent-a", "agent-b"}, "agent-c"), "rotation wraps around")
+	assert.Equal(t, "", NextLeadAgent([]string{"agent-c"}, "agent-c"))
+}
+
+func TestBuildLeadResponseReport(t *testing.T) {
+	now := time.Date(2025, 9, 25, 12, 0, 0, 0, time.UTC)
+	policy := DefaultLeadResponsePolicy("agency-1")
+	answered := func(minutes int64) *LeadResponse {
+		return &LeadResponse{ResponseSeconds: minutes * 60}
+	}
+	samples := []LeadResponseSample{
+		{LeadID: "lead-1", AgentID: "agent-a", AssignedAt: now.Add(-48 * time.Hour), Response: answered(10)},
+		{LeadID: "lead-2", AgentID: "agent-a", AssignedAt: now.Add(-24 * time.Hour), Response: answered(90)},
+		{LeadID: "lead-3", AgentID: "agent-b", AssignedAt: now.Add(-3 * time.Hour)},
+		{LeadID: "lead-4", AgentID: "agent-b", AssignedAt: now.Add(-10 * time.Minute)},
+	}
+
+	report := BuildLeadResponseReport(policy, samples, now.AddDate(0, 0, -30), now, now)
+	assert.Equal(t, LeadResponseStats{Leads: 4, Responded: 2, WithinTarget: 1, Overdue: 1,
+		ComplianceRate: 33.3, AverageMinutes: 50, MedianMinutes: 50}, report.Agency)
+	require.Len(t, report.Agents, 2)
+	assert.Equal(t, "agent-a", report.Agents[0].AgentID)
+	assert.Equal(t, 50.0, report.Agents[0].ComplianceRate)
+	assert.Equal(t, LeadResponseStats{AgentID: "agent-b", Leads: 2, Overdue: 1}, report.Agents[1],
+		"leads still within the target are not due yet")
+}
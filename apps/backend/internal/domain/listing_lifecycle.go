@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// DefaultListingExpiryDays is how long listings stay published without renewal when
+// their plan does not say otherwise
+const DefaultListingExpiryDays = 90
+
+// ExpiredListing is a listing marked expired for going stale, with the users to notify
+type ExpiredListing struct {
+	PropertyID string  `json:"property_id"`
+	Title      string  `json:"title"`
+	OwnerID    *string `json:"owner_id,omitempty"`
+	AgentID    *string `json:"agent_id,omitempty"`
+	AgencyID   *string `json:"agency_id,omitempty"`
+}
+
+// ListingRenewal reports a republished listing
+type ListingRenewal struct {
+	PropertyID   string    `json:"property_id"`
+	Status       string    `json:"status"`
+	RenewedAt    time.Time `json:"renewed_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RenewalCount int       `json:"renewal_count"`
+}
+
+// ListingExpiryDays returns the days a listing of a plan stays published; listings
+// without an agency plan use defaultDays
+func ListingExpiryDays(plan *SubscriptionPlan, defaultDays int) int {
+	if plan != nil && plan.ListingExpiryDays > 0 {
+		return plan.ListingExpiryDays
+	}
+	if defaultDays > 0 {
+		return defaultDays
+	}
+	return DefaultListingExpiryDays
+}
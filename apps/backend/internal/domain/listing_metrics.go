@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// DefaultStaleListingDays is the age from which the stale listing report includes an
+// available or reserved listing, well before it expires
+const DefaultStaleListingDays = 60
+
+// ListingMetrics reports how long a listing has been on the market. Open listings
+// count the days since publication; sold, rented and expired ones keep the days they
+// were on the market until they are re-listed.
+type ListingMetrics struct {
+	PropertyID   string       `json:"property_id"`
+	Status       string       `json:"status"`
+	PublishedAt  time.Time    `json:"published_at"`
+	ClosedAt     *time.Time   `json:"closed_at,omitempty"`
+	DaysOnMarket int          `json:"days_on_market"`
+	RenewalCount int          `json:"renewal_count"`
+	Stale        bool         `json:"stale"`
+	Shares       *ShareCounts `json:"shares,omitempty"`
+}
+
+// IsOnMarket reports whether the listing is still offered
+func (m *ListingMetrics) IsOnMarket() bool {
+	return m.Status == StatusAvailable || m.Status == StatusReserved
+}
+
+// MarketDaysOnMarket summarizes days on market for a segment of the market. Closed
+// listings are those sold or rented since Since.
+type MarketDaysOnMarket struct {
+	Province           string    `json:"province,omitempty"`
+	PropertyType       string    `json:"property_type,omitempty"`
+	Since              time.Time `json:"since"`
+	ActiveListings     int       `json:"active_listings"`
+	AvgActiveDays      float64   `json:"avg_active_days"`
+	ClosedListings     int       `json:"closed_listings"`
+	AvgDaysOnMarket    float64   `json:"avg_days_on_market"`
+	MedianDaysOnMarket float64   `json:"median_days_on_market"`
+}
+
+// StaleListing is an open listing published longer than the stale threshold
+type StaleListing struct {
+	PropertyID  string    `json:"property_id"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Price       float64   `json:"price"`
+	AgentID     *string   `json:"agent_id,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	DaysListed  int       `json:"days_listed"`
+}
+
+// DaysBetween returns the whole days elapsed from one time to another, never negative
+func DaysBetween(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Supported content locales. Spanish is the language listings are written in and the
+// fallback for every other locale.
+const (
+	LocaleSpanish = "es"
+	LocaleEnglish = "en"
+	DefaultLocale = LocaleSpanish
+)
+
+// Translation limits, matching the base title and description columns
+const (
+	MaxTranslatedTitleLength       = 255
+	MaxTranslatedDescriptionLength = 10000
+)
+
+// textSearchConfigs maps each locale to its PostgreSQL full-text search configuration
+var textSearchConfigs = map[string]string{
+	LocaleSpanish: "spanish",
+	LocaleEnglish: "english",
+}
+
+// PropertyTranslation holds the title and description of a property in one locale.
+// Machine translations record the hash of the Spanish content they were translated
+// from, so they are refreshed when it changes; editing one makes it a human translation.
+type PropertyTranslation struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Machine     bool   `json:"machine,omitempty"`
+	SourceHash  string `json:"source_hash,omitempty"`
+}
+
+// PropertyTranslations holds the translations of a property by locale. Spanish is kept
+// in the property's own title and description, so it never appears here.
+type PropertyTranslations map[string]PropertyTranslation
+
+// IsSupportedLocale verifies if content can be served in a locale
+func IsSupportedLocale(locale string) bool {
+	_, ok := textSearchConfigs[locale]
+	return ok
+}
+
+// TranslationLocales returns the locales properties are translated into, every
+// supported locale but Spanish, sorted
+func TranslationLocales() []string {
+	locales := []string{}
+	for locale := range textSearchConfigs {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// NormalizeLocale reduces a language tag such as "en-US" to a supported locale,
+// returning "" when the language is not supported
+func NormalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if IsSupportedLocale(tag) {
+		return tag
+	}
+	return ""
+}
+
+// TextSearchConfig returns the full-text search configuration of a locale, Spanish
+// for unsupported ones
+func TextSearchConfig(locale string) string {
+	if config, ok := textSearchConfigs[NormalizeLocale(locale)]; ok {
+		return config
+	}
+	return textSearchConfigs[DefaultLocale]
+}
+
+// ParseAcceptLanguage returns the supported locale preferred in an Accept-Language
+// header such as "en-US,en;q=0.9,es;q=0.8", or DefaultLocale when none is supported
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		locale  string
+		quality float64
+	}
+
+	candidates := []candidate{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{locale: locale, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].locale
+}
+
+// NewPropertyTranslation validates a translation of a property into a locale other
+// than Spanish
+func NewPropertyTranslation(locale, title, description string) (PropertyTranslation, error) {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		return PropertyTranslation{}, fmt.Errorf("unsupported locale")
+	}
+	if locale == DefaultLocale {
+		return PropertyTranslation{}, fmt.Errorf("spanish content is the property's own title and description")
+	}
+
+	translation := PropertyTranslation{
+		Title:       strings.TrimSpace(title),
+		Description: strings.TrimSpace(description),
+	}
+	if translation.Title == "" {
+		return PropertyTranslation{}, fmt.Errorf("translated title is required")
+	}
+	if utf8.RuneCountInString(translation.Title) > MaxTranslatedTitleLength {
+		return PropertyTranslation{}, fmt.Errorf("translated title cannot exceed %d characters", MaxTranslatedTitleLength)
+	}
+	if utf8.RuneCountInString(translation.Description) > MaxTranslatedDescriptionLength {
+		return PropertyTranslation{}, fmt.Errorf("translated description cannot exceed %d characters", MaxTranslatedDescriptionLength)
+	}
+	return translation, nil
+}
+
+// NewMachineTranslation validates a translation produced by a translation service from
+// the Spanish content with sourceHash
+func NewMachineTranslation(locale, title, description, sourceHash string) (PropertyTranslation, error) {
+	translation, err := NewPropertyTranslation(locale, title, description)
+	if err != nil {
+		return PropertyTranslation{}, err
+	}
+	translation.Machine = true
+	translation.SourceHash = sourceHash
+	return translation, nil
+}
+
+// TranslationSource returns the Spanish title and description of a property to
+// translate, the description as sanitized HTML, and the hash identifying them
+func (p *Property) TranslationSource() (title, description, hash string) {
+	description = p.DescriptionHTML
+	if description == "" {
+		description = p.Description
+	}
+	sum := sha256.Sum256([]byte(p.Title + "\x00" + description))
+	return p.Title, description, hex.EncodeToString(sum[:16])
+}
+
+// NeedsMachineTranslation verifies if a property's translation into locale should be
+// machine-translated from Spanish content with sourceHash: it is missing, or it is a
+// machine translation of older content. Human translations are never replaced.
+func (t PropertyTranslations) NeedsMachineTranslation(locale, sourceHash string) bool {
+	translation, ok := t[locale]
+	if !ok || translation.Title == "" {
+		return true
+	}
+	return translation.Machine && translation.SourceHash != sourceHash
+}
+
+// Localize replaces the title and description of a property with their translation
+// into locale and returns the locale the content is now in. Without a translation the
+// Spanish content is kept; a translation without description keeps the Spanish one.
+func (p *Property) Localize(translations PropertyTranslations, locale string) string {
+	locale = NormalizeLocale(locale)
+	if locale == "" || locale == DefaultLocale {
+		return DefaultLocale
+	}
+
+	translation, ok := translations[locale]
+	if !ok || translation.Title == "" {
+		return DefaultLocale
+	}
+
+	p.Title = translation.Title
+	p.MachineTranslated = translation.Machine
+	if translation.Description != "" {
+		p.DescriptionHTML = SanitizeRichText(translation.Description)
+		p.Description = RichTextPlainText(p.DescriptionHTML)
+	}
+	return locale
+}
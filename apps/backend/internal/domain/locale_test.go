@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", LocaleSpanish},
+		{"en-US,en;q=0.9,es;q=0.8", LocaleEnglish},
+		{"es-EC,es;q=0.9,en;q=0.8", LocaleSpanish},
+		{"fr-FR,fr;q=0.9,en;q=0.5", LocaleEnglish},
+		{"de-DE", LocaleSpanish},
+		{"en;q=0, es", LocaleSpanish},
+		{"es;q=0.4, en;q=0.6", LocaleEnglish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	assert.Equal(t, LocaleEnglish, NormalizeLocale("en_GB"))
+	assert.Equal(t, LocaleSpanish, NormalizeLocale(" ES "))
+	assert.Equal(t, "", NormalizeLocale("pt-BR"))
+	assert.Equal(t, "english", TextSearchConfig("en-US"))
+	assert.Equal(t, "spanish", TextSearchConfig("pt"))
+}
+
+func TestNewPropertyTranslation(t *testing.T) {
+	translation, err := NewPropertyTranslation("en-US", " Ocean view suite ", "Two bedrooms")
+	require.NoError(t, err)
+	assert.Equal(t, "Ocean view suite", translation.Title)
+
+	_, err = NewPropertyTranslation("es", "Suite", "")
+	assert.Error(t, err, "spanish is the base content")
+
+	_, err = NewPropertyTranslation("pt", "Suite", "")
+	assert.Error(t, err)
+
+	_, err = NewPropertyTranslation("en", " ", "Two bedrooms")
+	assert.Error(t, err)
+
+	_, err = NewPropertyTranslation("en", strings.Repeat("a", MaxTranslatedTitleLength+1), "")
+	assert.Error(t, err)
+}
+
+func TestProperty_Localize(t *testing.T) {
+	translations := PropertyTranslations{
+		LocaleEnglish: {Title: "House in Cumbayá"},
+	}
+
+	property := NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	assert.Equal(t, LocaleEnglish, property.Localize(translations, "en"))
+	assert.Equal(t, "House in Cumbayá", property.Title)
+	assert.Equal(t, "Casa con jardín", property.Description, "missing descriptions fall back to Spanish")
+
+	untranslated := NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	assert.Equal(t, LocaleSpanish, untranslated.Localize(nil, "en"))
+	assert.Equal(t, "Casa en Cumbayá", untranslated.Title)
+}
+
+func TestPropertyTranslations_NeedsMachineTranslation(t *testing.T) {
+	property := NewProperty("Casa en Cumbayá", "<p>Casa con <strong>jardín</strong></p>", "Pichincha", "Quito", "house", 250000, "owner-1")
+	title, description, hash := property.TranslationSource()
+	assert.Equal(t, "Casa en Cumbayá", title)
+	assert.Equal(t, "<p>Casa con <strong>jardín</strong></p>", description, "descriptions are translated with their markup")
+
+	translations := PropertyTranslations{}
+	assert.True(t, translations.NeedsMachineTranslation(LocaleEnglish, hash))
+
+	machine, err := NewMachineTranslation("en", "House in Cumbayá", "<p>House with <b>garden</b></p>", hash)
+	require.NoError(t, err)
+	translations[LocaleEnglish] = machine
+	assert.False(t, translations.NeedsMachineTranslation(LocaleEnglish, hash))
+
+	property.Description = "Casa con jardín y piscina"
+	property.NormalizeDescription()
+	_, _, changed := property.TranslationSource()
+	assert.True(t, translations.NeedsMachineTranslation(LocaleEnglish, changed), "machine translations follow the Spanish content")
+
+	translations[LocaleEnglish] = PropertyTranslation{Title: "House in Cumbayá", SourceHash: hash}
+	assert.False(t, translations.NeedsMachineTranslation(LocaleEnglish, changed), "edited translations are kept")
+
+	translations[LocaleEnglish] = machine
+	assert.Equal(t, LocaleEnglish, property.Localize(translations, "en"))
+	assert.True(t, property.MachineTranslated)
+}
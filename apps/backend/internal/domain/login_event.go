@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GeoLocation is where an IP address is located. Locations of private and unknown
+// addresses are empty.
+type GeoLocation struct {
+	CountryCode string `json:"country_code,omitempty"` // ISO 3166-1 alpha-2, e.g. EC
+	CountryName string `json:"country_name,omitempty"`
+	City        string `json:"city,omitempty"`
+}
+
+// IsKnown reports whether the country of the location was found
+func (l GeoLocation) IsKnown() bool {
+	return l.CountryCode != ""
+}
+
+// String formats the location as "Quito, Ecuador"
+func (l GeoLocation) String() string {
+	country := l.CountryName
+	if country == "" {
+		country = l.CountryCode
+	}
+	if l.City == "" {
+		return country
+	}
+	return l.City + ", " + country
+}
+
+// MaxLoginUserAgentLength is the longest user agent stored with a login
+const MaxLoginUserAgentLength = 512
+
+// LoginEvent records where a user logged in from. Each login starts a session, whose
+// ID is carried by the refresh token.
+type LoginEvent struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	SessionID   string    `json:"session_id"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent,omitempty"`
+	CountryCode string    `json:"country_code,omitempty"`
+	CountryName string    `json:"country_name,omitempty"`
+	City        string    `json:"city,omitempty"`
+	NewLocation bool      `json:"new_location"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewLoginEvent records a login
+func NewLoginEvent(userID, sessionID, ipAddress, userAgent string, location GeoLocation) *LoginEvent {
+	if len(userAgent) > MaxLoginUserAgentLength {
+		userAgent = userAgent[:MaxLoginUserAgentLength]
+	}
+	return &LoginEvent{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		SessionID:   sessionID,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		CountryCode: location.CountryCode,
+		CountryName: location.CountryName,
+		City:        location.City,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Location returns where the login came from
+func (e *LoginEvent) Location() GeoLocation {
+	return GeoLocation{CountryCode: e.CountryCode, CountryName: e.CountryName, City: e.City}
+}
+
+// NormalizeCountryCodes uppercases ISO 3166-1 alpha-2 codes and drops anything else
+func NormalizeCountryCodes(codes []string) []string {
+	normalized := []string{}
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if IsValidCountryCode(code) {
+			normalized = append(normalized, code)
+		}
+	}
+	return normalized
+}
+
+// IsValidCountryCode verifies if a code looks like an uppercase ISO 3166-1 alpha-2 code
+func IsValidCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// DefaultPurgeRetention is how long deactivated users and agencies are kept before
+// they can be purged, leaving time to reactivate accounts deleted by mistake
+const DefaultPurgeRetention = 90 * 24 * time.Hour
+
+// PropertySlug is the title and slug of a property, for slug maintenance
+type PropertySlug struct {
+	ID    string
+	Title string
+	Slug  string
+}
+
+// SlugReport summarizes a slug recalculation run
+type SlugReport struct {
+	DryRun  bool `json:"dry_run"`
+	Scanned int  `json:"scanned"`
+	Updated int  `json:"updated"`
+}
+
+// PropertyDescription is the description of a property, for description maintenance
+type PropertyDescription struct {
+	ID              string
+	Description     string
+	DescriptionHTML string
+	DescriptionRaw  string
+}
+
+// DescriptionReport summarizes a description sanitization run
+type DescriptionReport struct {
+	DryRun  bool `json:"dry_run"`
+	Scanned int  `json:"scanned"`
+	Updated int  `json:"updated"`
+}
+
+// PurgeReport summarizes a purge of deactivated users and agencies
+type PurgeReport struct {
+	DryRun            bool      `json:"dry_run"`
+	DeactivatedBefore time.Time `json:"deactivated_before"`
+	Users             int64     `json:"users"`
+	Agencies          int64     `json:"agencies"`
+}
@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Maintenance request statuses. Tenants open requests, the property's managers work on
+// and resolve them, and tenants confirm the fix by closing them or reopen them.
+const (
+	MaintenanceOpen       = "open"
+	MaintenanceInProgress = "in_progress"
+	MaintenanceResolved   = "resolved"
+	MaintenanceClosed     = "closed"
+	MaintenanceCancelled  = "cancelled"
+)
+
+// Maintenance request categories
+const (
+	MaintenancePlumbing   = "plumbing"
+	MaintenanceElectrical = "electrical"
+	MaintenanceAppliance  = "appliance"
+	MaintenanceStructural = "structural"
+	MaintenancePest       = "pest"
+	MaintenanceOther      = "other"
+)
+
+// Maintenance request priorities
+const (
+	MaintenancePriorityLow    = "low"
+	MaintenancePriorityNormal = "normal"
+	MaintenancePriorityUrgent = "urgent"
+)
+
+// Maintenance request limits
+const (
+	MaxMaintenanceTitleLength       = 150
+	MaxMaintenanceDescriptionLength = 5000
+	MaxMaintenanceCommentLength     = 2000
+	MaxMaintenancePhotos            = 8
+	MaxMaintenancePhotoSize         = 5 << 20
+)
+
+// maintenanceManagerTransitions lists the statuses the property's managers can move a
+// request to from each status
+var maintenanceManagerTransitions = map[string][]string{
+	MaintenanceOpen:       {MaintenanceInProgress, MaintenanceResolved},
+	MaintenanceInProgress: {MaintenanceResolved},
+	MaintenanceResolved:   {MaintenanceClosed},
+}
+
+// maintenanceTenantTransitions lists the statuses the tenant can move a request to from
+// each status
+var maintenanceTenantTransitions = map[string][]string{
+	MaintenanceOpen:     {MaintenanceCancelled},
+	MaintenanceResolved: {MaintenanceOpen, MaintenanceClosed},
+}
+
+// maintenancePhotoTypes maps the accepted photo content types to their file extension
+var maintenancePhotoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// MaintenancePhoto is a photo attached to a maintenance request
+type MaintenancePhoto struct {
+	ID          string    `json:"id"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StoragePath string    `json:"-"`
+	UploadedBy  string    `json:"uploaded_by"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// MaintenanceUpdate is a change in the status of a maintenance request
+type MaintenanceUpdate struct {
+	ID         string    `json:"id"`
+	RequestID  string    `json:"request_id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Comment    string    `json:"comment,omitempty"`
+	ChangedBy  string    `json:"changed_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MaintenanceRequest is a maintenance ticket opened by the tenant of a rented property
+type MaintenanceRequest struct {
+	ID          string              `json:"id"`
+	PropertyID  string              `json:"property_id"`
+	TenantID    string              `json:"tenant_id"`
+	Category    string              `json:"category"`
+	Priority    string              `json:"priority"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Status      string              `json:"status"`
+	Photos      []MaintenancePhoto  `json:"photos"`
+	History     []MaintenanceUpdate `json:"history,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	ResolvedAt  *time.Time          `json:"resolved_at,omitempty"`
+	ClosedAt    *time.Time          `json:"closed_at,omitempty"`
+}
+
+// NewMaintenanceRequest opens a maintenance request, returning it with its first update
+func NewMaintenanceRequest(propertyID, tenantID, category, priority, title, description string, now time.Time) (*MaintenanceRequest, *MaintenanceUpdate, error) {
+	request := &MaintenanceRequest{
+		ID:          uuid.New().String(),
+		PropertyID:  propertyID,
+		TenantID:    tenantID,
+		Category:    strings.ToLower(strings.TrimSpace(category)),
+		Priority:    strings.ToLower(strings.TrimSpace(priority)),
+		Title:       strings.TrimSpace(title),
+		Description: strings.TrimSpace(description),
+		Status:      MaintenanceOpen,
+		Photos:      []MaintenancePhoto{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if request.Priority == "" {
+		request.Priority = MaintenancePriorityNormal
+	}
+
+	switch request.Category {
+	case MaintenancePlumbing, MaintenanceElectrical, MaintenanceAppliance, MaintenanceStructural, MaintenancePest, MaintenanceOther:
+	default:
+		return nil, nil, fmt.Errorf("invalid category: must be plumbing, electrical, appliance, structural, pest or other")
+	}
+	switch request.Priority {
+	case MaintenancePriorityLow, MaintenancePriorityNormal, MaintenancePriorityUrgent:
+	default:
+		return nil, nil, fmt.Errorf("invalid priority: must be low, normal or urgent")
+	}
+	if request.Title == "" || utf8.RuneCountInString(request.Title) > MaxMaintenanceTitleLength {
+		return nil, nil, fmt.Errorf("invalid request: title is required and must be at most %d characters", MaxMaintenanceTitleLength)
+	}
+	if utf8.RuneCountInString(request.Description) > MaxMaintenanceDescriptionLength {
+		return nil, nil, fmt.Errorf("invalid request: description must be at most %d characters", MaxMaintenanceDescriptionLength)
+	}
+
+	update := &MaintenanceUpdate{
+		ID:        uuid.New().String(),
+		RequestID: request.ID,
+		ToStatus:  MaintenanceOpen,
+		ChangedBy: tenantID,
+		CreatedAt: now,
+	}
+	return request, update, nil
+}
+
+// IsActive reports whether the request still awaits work or confirmation
+func (r *MaintenanceRequest) IsActive() bool {
+	return r.Status != MaintenanceClosed && r.Status != MaintenanceCancelled
+}
+
+// ChangeStatus moves the request to another status, returning the update to record.
+// byTenant selects the transitions of the tenant rather than the property's managers.
+func (r *MaintenanceRequest) ChangeStatus(status, comment, changedBy string, byTenant bool, now time.Time) (*MaintenanceUpdate, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > MaxMaintenanceCommentLength {
+		return nil, fmt.Errorf("invalid comment: must be at most %d characters", MaxMaintenanceCommentLength)
+	}
+
+	transitions := maintenanceManagerTransitions
+	if byTenant {
+		transitions = maintenanceTenantTransitions
+	}
+	allowed := false
+	for _, next := range transitions[r.Status] {
+		if next == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("invalid status transition: cannot change from %s to %s", r.Status, status)
+	}
+
+	update := &MaintenanceUpdate{
+		ID:         uuid.New().String(),
+		RequestID:  r.ID,
+		FromStatus: r.Status,
+		ToStatus:   status,
+		Comment:    comment,
+		ChangedBy:  changedBy,
+		CreatedAt:  now,
+	}
+
+	r.Status = status
+	r.UpdatedAt = now
+	switch status {
+	case MaintenanceResolved:
+		r.ResolvedAt = &now
+	case MaintenanceOpen:
+		r.ResolvedAt = nil
+	case MaintenanceClosed, MaintenanceCancelled:
+		r.ClosedAt = &now
+	}
+	return update, nil
+}
+
+// Photo returns the photo of the request with the given ID
+func (r *MaintenanceRequest) Photo(id string) (*MaintenancePhoto, bool) {
+	for i := range r.Photos {
+		if r.Photos[i].ID == id {
+			return &r.Photos[i], true
+		}
+	}
+	return nil, false
+}
+
+// NewMaintenancePhoto checks an uploaded photo by its content and describes where it is stored
+func NewMaintenancePhoto(requestID string, data []byte, uploadedBy string, now time.Time) (*MaintenancePhoto, error) {
+	if len(data) > MaxMaintenancePhotoSize {
+		return nil, fmt.Errorf("file too large: max %d bytes", MaxMaintenancePhotoSize)
+	}
+	contentType := http.DetectContentType(data)
+	extension, ok := maintenancePhotoTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("invalid photo: only JPEG, PNG and WebP images are accepted")
+	}
+
+	id := uuid.New().String()
+	return &MaintenancePhoto{
+		ID:          id,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StoragePath: fmt.Sprintf("maintenance/%s/%s%s", requestID, id, extension),
+		UploadedBy:  uploadedBy,
+		UploadedAt:  now,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMaintenanceRequest(t *testing.T) {
+	now := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+
+	request, update, err := NewMaintenanceRequest("prop-1", "tenant-1", " Plumbing ", "", "Fuga en el lavabo", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, MaintenancePlumbing, request.Category)
+	assert.Equal(t, MaintenancePriorityNormal, request.Priority)
+	assert.Equal(t, MaintenanceOpen, request.Status)
+	assert.Equal(t, request.ID, update.RequestID)
+	assert.Equal(t, MaintenanceOpen, update.ToStatus)
+
+	_, _, err = NewMaintenanceRequest("prop-1", "tenant-1", "garden", "", "Césped", "", now)
+	assert.ErrorContains(t, err, "invalid category")
+	_, _, err = NewMaintenanceRequest("prop-1", "tenant-1", "other", "critical", "Césped", "", now)
+	assert.ErrorContains(t, err, "invalid priority")
+	_, _, err = NewMaintenanceRequest("prop-1", "tenant-1", "other", "", "  ", "", now)
+	assert.ErrorContains(t, err, "title is required")
+}
+
+func TestMaintenanceRequest_ChangeStatus(t *testing.T) {
+	now := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+	request, _, err := NewMaintenanceRequest("prop-1", "tenant-1", "electrical", "urgent", "Sin luz en la cocina", "", now)
+	require.NoError(t, err)
+
+	_, err = request.ChangeStatus(MaintenanceResolved, "", "tenant-1", true, now)
+	assert.ErrorContains(t, err, "invalid status transition", "tenants cannot resolve their own requests")
+
+	update, err := request.ChangeStatus("in_progress", "Electricista el lunes", "owner-1", false, now)
+	require.NoError(t, err)
+	assert.Equal(t, MaintenanceOpen, update.FromStatus)
+	assert.Equal(t, MaintenanceInProgress, request.Status)
+
+	_, err = request.ChangeStatus(MaintenanceCancelled, "", "tenant-1", true, now)
+	assert.ErrorContains(t, err, "invalid status transition", "work in progress cannot be cancelled")
+
+	_, err = request.ChangeStatus(MaintenanceResolved, "", "owner-1", false, now)
+	require.NoError(t, err)
+	require.NotNil(t, request.ResolvedAt)
+
+	_, err = request.ChangeStatus(MaintenanceOpen, "Sigue fallando", "tenant-1", true, now)
+	require.NoError(t, err)
+	assert.Nil(t, request.ResolvedAt)
+
+	_, err = request.ChangeStatus(MaintenanceResolved, "", "owner-1", false, now)
+	require.NoError(t, err)
+	_, err = request.ChangeStatus(MaintenanceClosed, "", "tenant-1", true, now)
+	require.NoError(t, err)
+	assert.False(t, request.IsActive())
+	require.NotNil(t, request.ClosedAt)
+}
+
+func TestNewMaintenancePhoto(t *testing.T) {
+	now := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	photo, err := NewMaintenancePhoto("req-1", png, "tenant-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", photo.ContentType)
+	assert.Equal(t, "maintenance/req-1/"+photo.ID+".png", photo.StoragePath)
+
+	_, err = NewMaintenancePhoto("req-1", []byte("%PDF-1.4"), "tenant-1", now)
+	assert.ErrorContains(t, err, "invalid photo")
+	_, err = NewMaintenancePhoto("req-1", make([]byte, MaxMaintenancePhotoSize+1), "tenant-1", now)
+	assert.ErrorContains(t, err, "too large")
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox event statuses
+const (
+	OutboxPending   = "pending"
+	OutboxPublished = "published"
+	OutboxFailed    = "failed" // gave up after the last attempt
+)
+
+// Aggregate types of outbox events
+const (
+	AggregateProperty          = "property"
+	AggregateDeal              = "deal"
+	AggregateRentalApplication = "rental_application"
+)
+
+// DefaultOutboxMaxAttempts is how often an event is dispatched before it is marked failed
+const DefaultOutboxMaxAttempts = 10
+
+// OutboxEvent is a domain event stored in the same transaction as the change it
+// describes, so it is published if and only if the change is committed. The
+// dispatcher hands events to handlers at least once; handlers deduplicate by ID.
+type OutboxEvent struct {
+	ID            string          `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	AgencyID      string          `json:"agency_id,omitempty"` // agency of the listing, empty for independent listings
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	AvailableAt   time.Time       `json:"available_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+}
+
+// NewOutboxEvent creates a pending event about an aggregate, available immediately
+func NewOutboxEvent(eventType, aggregateType, aggregateID, agencyID string, data interface{}) (*OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New().String(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		AgencyID:      agencyID,
+		Payload:       payload,
+		Status:        OutboxPending,
+		AvailableAt:   now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkPublished records that every handler processed the event
+func (e *OutboxEvent) MarkPublished(at time.Time) {
+	e.Attempts++
+	e.Status = OutboxPublished
+	e.LastError = ""
+	e.PublishedAt = &at
+}
+
+// RecordFailure records a failed dispatch, retrying with the webhook backoff until
+// maxAttempts is reached
+func (e *OutboxEvent) RecordFailure(dispatchErr error, at time.Time, maxAttempts int) {
+	e.Attempts++
+	e.LastError = dispatchErr.Error()
+	if len(e.LastError) > MaxWebhookErrorLength {
+		e.LastError = e.LastError[:MaxWebhookErrorLength]
+	}
+
+	if e.Attempts >= maxAttempts {
+		e.Status = OutboxFailed
+		return
+	}
+	e.AvailableAt = at.Add(WebhookRetryDelay(e.Attempts))
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxEvent_Lifecycle(t *testing.T) {
+	event, err := NewOutboxEvent(EventPropertyCreated, AggregateProperty, "prop-1", "agency-1", map[string]string{"id": "prop-1"})
+	require.NoError(t, err)
+	assert.Equal(t, OutboxPending, event.Status)
+	assert.JSONEq(t, `{"id":"prop-1"}`, string(event.Payload))
+
+	now := time.Now()
+	event.RecordFailure(errors.New("connection refused"), now, 2)
+	assert.Equal(t, OutboxPending, event.Status)
+	assert.Equal(t, now.Add(30*time.Second), event.AvailableAt)
+	assert.Equal(t, "connection refused", event.LastError)
+
+	event.RecordFailure(errors.New("connection refused"), now, 2)
+	assert.Equal(t, OutboxFailed, event.Status)
+
+	event.MarkPublished(now)
+	assert.Equal(t, OutboxPublished, event.Status)
+	assert.Empty(t, event.LastError)
+	assert.Equal(t, now, *event.PublishedAt)
+}
+
+func TestNewWebhookEventDeliveries_KeepsEventID(t *testing.T) {
+	event := WebhookEvent{ID: "evt-1", Type: EventPropertyUpdated, CreatedAt: time.Now(), Data: map[string]string{"id": "prop-1"}}
+	deliveries, err := NewWebhookEventDeliveries(event, []WebhookSubscription{{ID: "sub-1"}})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "evt-1", deliveries[0].EventID)
+	assert.Equal(t, EventPropertyUpdated, deliveries[0].EventType)
+	assert.Contains(t, string(deliveries[0].Payload), `"id":"evt-1"`)
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Phone verification limits. A code is valid for PhoneVerificationTTL and can be
+// tried MaxPhoneVerificationAttempts times; a new code can be requested every
+// PhoneVerificationResendInterval.
+const (
+	PhoneVerificationCodeLength     = 6
+	PhoneVerificationTTL            = 10 * time.Minute
+	MaxPhoneVerificationAttempts    = 5
+	PhoneVerificationResendInterval = time.Minute
+)
+
+// PhoneVerification is a one-time code sent by SMS to prove a user owns a phone
+// number. Only a hash of the code is stored.
+type PhoneVerification struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Phone      string     `json:"phone"`
+	CodeHash   string     `json:"-"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewPhoneVerification creates a verification for a phone number and returns it with
+// its code
+func NewPhoneVerification(userID, phone string) (*PhoneVerification, string, error) {
+	if userID == "" {
+		return nil, "", fmt.Errorf("user ID cannot be empty")
+	}
+	e164, err := PhoneE164(phone)
+	if err != nil {
+		return nil, "", err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	verification := &PhoneVerification{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Phone:     e164,
+		ExpiresAt: now.Add(PhoneVerificationTTL),
+		CreatedAt: now,
+	}
+	verification.CodeHash = verification.hashCode(code)
+	return verification, code, nil
+}
+
+// IsExpired reports whether the code can no longer be used
+func (v *PhoneVerification) IsExpired(now time.Time) bool {
+	return now.After(v.ExpiresAt)
+}
+
+// CanResend reports whether a new code can be sent after this one
+func (v *PhoneVerification) CanResend(now time.Time) bool {
+	return v.VerifiedAt != nil || !now.Before(v.CreatedAt.Add(PhoneVerificationResendInterval))
+}
+
+// Verify checks a code entered by the user. Every wrong code counts as an attempt.
+func (v *PhoneVerification) Verify(code string, now time.Time) error {
+	if v.VerifiedAt != nil {
+		return fmt.Errorf("phone verification already used")
+	}
+	if v.IsExpired(now) {
+		return fmt.Errorf("verification code expired")
+	}
+	if v.Attempts >= MaxPhoneVerificationAttempts {
+		return fmt.Errorf("too many attempts: request a new code")
+	}
+
+	v.Attempts++
+	if subtle.ConstantTimeCompare([]byte(v.hashCode(strings.TrimSpace(code))), []byte(v.CodeHash)) != 1 {
+		return fmt.Errorf("invalid verification code")
+	}
+
+	v.VerifiedAt = &now
+	return nil
+}
+
+// hashCode hashes a code with the verification ID, so equal codes have different hashes
+func (v *PhoneVerification) hashCode(code string) string {
+	sum := sha256.Sum256([]byte(v.ID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// PhoneE164 converts an Ecuadorian phone number, as accepted on users and agencies, to
+// E.164 format for SMS providers: 0991234567 becomes +593991234567
+func PhoneE164(phone string) (string, error) {
+	phone = normalizePhone(strings.TrimSpace(phone))
+	if err := validatePhone(phone); err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(phone, "0") {
+		return "+593" + phone[1:], nil
+	}
+	return phone, nil
+}
+
+// generateVerificationCode generates a random numeric code
+func generateVerificationCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < PhoneVerificationCodeLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", PhoneVerificationCodeLength, n), nil
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneE164(t *testing.T) {
+	phone, err := PhoneE164("099-123-4567")
+	require.NoError(t, err)
+	assert.Equal(t, "+593991234567", phone)
+
+	phone, err = PhoneE164("+593991234567")
+	require.NoError(t, err)
+	assert.Equal(t, "+593991234567", phone)
+
+	_, err = PhoneE164("12345")
+	assert.Error(t, err)
+}
+
+func TestPhoneVerification_Verify(t *testing.T) {
+	verification, code, err := NewPhoneVerification("user-1", "0991234567")
+	require.NoError(t, err)
+	assert.Len(t, code, PhoneVerificationCodeLength)
+	assert.NotContains(t, verification.CodeHash, code)
+	assert.False(t, verification.CanResend(verification.CreatedAt))
+
+	now := verification.CreatedAt.Add(time.Minute)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	assert.EqualError(t, verification.Verify(wrong, now), "invalid verification code")
+	assert.Equal(t, 1, verification.Attempts)
+
+	require.NoError(t, verification.Verify(code, now))
+	assert.NotNil(t, verification.VerifiedAt)
+	assert.Error(t, verification.Verify(code, now))
+
+	expired, code, err := NewPhoneVerification("user-1", "0991234567")
+	require.NoError(t, err)
+	assert.EqualError(t, expired.Verify(code, expired.ExpiresAt.Add(time.Second)), "verification code expired")
+}
+
+func TestUser_SetPhone(t *testing.T) {
+	phone := "0991234567"
+	verifiedAt := time.Now()
+	user := &User{Role: RoleOwner, Phone: &phone, PhoneVerifiedAt: &verifiedAt}
+	assert.True(t, user.IsPhoneVerified())
+
+	user.SetPhone("099 123 4567")
+	assert.True(t, user.IsPhoneVerified(), "the same number stays verified")
+
+	user.SetPhone("0987654321")
+	assert.False(t, user.IsPhoneVerified())
+	assert.Equal(t, "0987654321", *user.Phone)
+}
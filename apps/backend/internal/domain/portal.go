@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Portal roles. Unlike account roles they are held per property: a user is the tenant
+// of the properties they rent through a rent deal and the landlord of the rented
+// properties they own.
+const (
+	PortalTenant   = "tenant"
+	PortalLandlord = "landlord"
+)
+
+// PortalRental is a lease of a property, as recorded by a rent deal, seen by one of its parties
+type PortalRental struct {
+	DealID         string  `json:"deal_id"`
+	PropertyID     string  `json:"property_id"`
+	Title          string  `json:"title"`
+	Province       string  `json:"province"`
+	City           string  `json:"city"`
+	PropertyStatus string  `json:"property_status"`
+	Role           string  `json:"role"`
+	TenantID       string  `json:"tenant_id,omitempty"`
+	OwnerID        string  `json:"owner_id"`
+	MonthlyRent    float64 `json:"monthly_rent"`
+	// Commission is only shown to the landlord
+	Commission *float64  `json:"commission,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	// EndedAt is when the next rent deal of the property closed
+	EndedAt *time.Time `json:"ended_at,omitempty"`
+	// PreviousDealAt is when the previous rent deal of the property closed
+	PreviousDealAt *time.Time `json:"-"`
+	Current        bool       `json:"current"`
+}
+
+// CoversDocument reports whether a document belongs to the lease: tenants see the
+// public documents of the property and the contracts generated since the previous
+// lease until the next one; landlords see every document.
+func (r PortalRental) CoversDocument(document PropertyDocument) bool {
+	if r.Role == PortalLandlord || document.IsPublic() {
+		return true
+	}
+	if document.Type != DocumentTypeContract {
+		return false
+	}
+	if r.PreviousDealAt != nil && !document.CreatedAt.After(*r.PreviousDealAt) {
+		return false
+	}
+	return r.EndedAt == nil || document.CreatedAt.Before(*r.EndedAt)
+}
+
+// PortalPayment is an amount settled on a lease: a paid rent charge, or the rent deal
+// itself for leases without a rent ledger
+type PortalPayment struct {
+	DealID     string     `json:"deal_id"`
+	ChargeID   string     `json:"charge_id,omitempty"`
+	PropertyID string     `json:"property_id"`
+	Title      string     `json:"title"`
+	Role       string     `json:"role"`
+	Period     *time.Time `json:"period,omitempty"`
+	Amount     float64    `json:"amount"`
+	Commission *float64   `json:"commission,omitempty"`
+	PaidAt     time.Time  `json:"paid_at"`
+}
+
+// PortalDocuments are the documents of a leased property visible to one of its parties
+type PortalDocuments struct {
+	PropertyID string             `json:"property_id"`
+	Title      string             `json:"title"`
+	Role       string             `json:"role"`
+	Documents  []PropertyDocument `json:"documents"`
+}
+
+// PortalDashboard summarizes the leases of a user as tenant and landlord
+type PortalDashboard struct {
+	UserID             string         `json:"user_id"`
+	Roles              []string       `json:"roles"`
+	CurrentRentals     []PortalRental `json:"current_rentals"`
+	PastRentals        int            `json:"past_rentals"`
+	ActiveMaintenance  int            `json:"active_maintenance"`
+	UrgentMaintenance  int            `json:"urgent_maintenance"`
+	Documents          int            `json:"documents"`
+	MonthlyRentPaid    float64        `json:"monthly_rent_paid"`
+	MonthlyRentCharged float64        `json:"monthly_rent_charged"`
+	RentOverdue        float64        `json:"rent_overdue"`
+}
+
+// PortalRoles returns the portal roles a user holds through their leases, sorted
+func PortalRoles(rentals []PortalRental) []string {
+	seen := map[string]bool{}
+	roles := []string{}
+	for _, rental := range rentals {
+		if !seen[rental.Role] {
+			seen[rental.Role] = true
+			roles = append(roles, rental.Role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortalRental_CoversDocument(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	previous, ended := day(5), day(20)
+	lease := PortalRental{Role: PortalTenant, StartedAt: day(10), PreviousDealAt: &previous, EndedAt: &ended}
+
+	contract := func(created time.Time) PropertyDocument {
+		return PropertyDocument{Type: DocumentTypeContract, Visibility: DocumentVisibilityPrivate, CreatedAt: created}
+	}
+
+	assert.True(t, lease.CoversDocument(contract(day(8))), "contracts are usually generated before the deal is recorded")
+	assert.False(t, lease.CoversDocument(contract(day(3))), "contracts of the previous lease")
+	assert.False(t, lease.CoversDocument(contract(day(21))), "contracts of the next lease")
+	assert.False(t, lease.CoversDocument(PropertyDocument{Type: DocumentTypeDeed, Visibility: DocumentVisibilityPrivate, CreatedAt: day(12)}))
+	assert.True(t, lease.CoversDocument(PropertyDocument{Type: DocumentTypeFloorPlan, Visibility: DocumentVisibilityPublic, CreatedAt: day(1)}))
+
+	landlord := PortalRental{Role: PortalLandlord, StartedAt: day(10)}
+	assert.True(t, landlord.CoversDocument(contract(day(3))))
+}
+
+func TestPortalRoles(t *testing.T) {
+	rentals := []PortalRental{{Role: PortalTenant}, {Role: PortalLandlord}, {Role: PortalTenant}}
+	assert.Equal(t, []string{PortalLandlord, PortalTenant}, PortalRoles(rentals))
+	assert.Empty(t, PortalRoles(nil))
+}
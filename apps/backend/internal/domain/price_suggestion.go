@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Price suggestion settings. Comparables are listings of the same type within
+// ComparableAreaTolerance of the area, sold or listed in the last ComparableMaxAge.
+const (
+	MinComparables          = 5
+	MaxComparables          = 20
+	ComparableAreaTolerance = 0.3
+	ComparableMaxAge        = 365 * 24 * time.Hour
+)
+
+// Comparable scopes, from the closest match to the widest
+const (
+	ComparableScopeSector   = "sector"
+	ComparableScopeCity     = "city"
+	ComparableScopeProvince = "province"
+)
+
+// Comparable sources: sold listings use the final price of their sale, listings on the
+// market their asking price
+const (
+	ComparableSourceSold   = "sold"
+	ComparableSourceListed = "listed"
+)
+
+// Price suggestion confidence, from the number and closeness of the comparables
+const (
+	PriceConfidenceLow    = "low"
+	PriceConfidenceMedium = "medium"
+	PriceConfidenceHigh   = "high"
+)
+
+// Price assessments of an asking price against the suggested range
+const (
+	PriceAssessmentBelow  = "below_range"
+	PriceAssessmentWithin = "within_range"
+	PriceAssessmentAbove  = "above_range"
+)
+
+// PriceSuggestionRequest describes a listing being created. Price is the asking price
+// the agent entered, if any, to be assessed against the suggestion.
+type PriceSuggestionRequest struct {
+	Province string   `json:"province"`
+	City     string   `json:"city"`
+	Sector   string   `json:"sector,omitempty"`
+	Type     string   `json:"type"`
+	AreaM2   float64  `json:"area_m2"`
+	Bedrooms int      `json:"bedrooms,omitempty"`
+	Price    *float64 `json:"price,omitempty"`
+}
+
+// Normalize trims the request before validation
+func (r *PriceSuggestionRequest) Normalize() {
+	r.Province = strings.TrimSpace(r.Province)
+	r.City = strings.TrimSpace(r.City)
+	r.Sector = strings.TrimSpace(r.Sector)
+	r.Type = strings.ToLower(strings.TrimSpace(r.Type))
+}
+
+// Validate checks the request
+func (r *PriceSuggestionRequest) Validate() error {
+	if !IsValidProvince(r.Province) {
+		return fmt.Errorf("invalid province: %s", r.Province)
+	}
+	if r.City == "" {
+		return fmt.Errorf("invalid request: city is required")
+	}
+	if !IsValidPropertyType(r.Type) {
+		return fmt.Errorf("invalid property type: %s", r.Type)
+	}
+	if r.AreaM2 <= 0 {
+		return fmt.Errorf("invalid area: must be positive")
+	}
+	if r.Bedrooms < 0 {
+		return fmt.Errorf("invalid bedrooms: must not be negative")
+	}
+	if r.Price != nil && *r.Price <= 0 {
+		return fmt.Errorf("invalid price: must be positive")
+	}
+	return nil
+}
+
+// ComparableCriteria selects the comparables of a request in a scope
+type ComparableCriteria struct {
+	Province string
+	City     string
+	Sector   string
+	Type     string
+	MinArea  float64
+	MaxArea  float64
+	Since    time.Time
+	Limit    int
+}
+
+// CriteriaFor returns the comparable criteria of the request in a scope
+func (r *PriceSuggestionRequest) CriteriaFor(scope string, now time.Time) ComparableCriteria {
+	criteria := ComparableCriteria{
+		Province: r.Province,
+		Type:     r.Type,
+		MinArea:  r.AreaM2 * (1 - ComparableAreaTolerance),
+		MaxArea:  r.AreaM2 * (1 + ComparableAreaTolerance),
+		Since:    now.Add(-ComparableMaxAge),
+		Limit:    MaxComparables,
+	}
+	switch scope {
+	case ComparableScopeSector:
+		criteria.City = r.City
+		criteria.Sector = r.Sector
+	case ComparableScopeCity:
+		criteria.City = r.City
+	}
+	return criteria
+}
+
+// Comparable is a listing similar to the one being priced
+type Comparable struct {
+	PropertyID string    `json:"property_id"`
+	Title      string    `json:"title"`
+	City       string    `json:"city"`
+	Sector     *string   `json:"sector,omitempty"`
+	AreaM2     float64   `json:"area_m2"`
+	Bedrooms   int       `json:"bedrooms"`
+	Price      float64   `json:"price"`
+	PricePerM2 float64   `json:"price_per_m2"`
+	Source     string    `json:"source"`
+	Date       time.Time `json:"date"`
+}
+
+// PriceSuggestion is the suggested price range of a listing, from the interquartile
+// price per m² of its comparables
+type PriceSuggestion struct {
+	SuggestedPrice   float64      `json:"suggested_price"`
+	MinPrice         float64      `json:"min_price"`
+	MaxPrice         float64      `json:"max_price"`
+	PricePerM2       float64      `json:"price_per_m2"`
+	Scope            string       `json:"scope"`
+	Confidence       string       `json:"confidence"`
+	Assessment       string       `json:"assessment,omitempty"`
+	DeviationPercent *float64     `json:"deviation_percent,omitempty"`
+	Comparables      []Comparable `json:"comparables"`
+}
+
+// NewPriceSuggestion suggests a price for a request from comparables found in a scope.
+// It fails when there are fewer than MinComparables.
+func NewPriceSuggestion(request *PriceSuggestionRequest, scope string, comparables []Comparable) (*PriceSuggestion, error) {
+	if len(comparables) < MinComparables {
+		return nil, fmt.Errorf("not enough comparables: found %d, need %d", len(comparables), MinComparables)
+	}
+
+	perM2 := make([]float64, len(comparables))
+	for i, comparable := range comparables {
+		perM2[i] = comparable.PricePerM2
+	}
+	sort.Float64s(perM2)
+
+	median := percentile(perM2, 0.5)
+	suggestion := &PriceSuggestion{
+		SuggestedPrice: roundPrice(median * request.AreaM2),
+		MinPrice:       roundPrice(percentile(perM2, 0.25) * request.AreaM2),
+		MaxPrice:       roundPrice(percentile(perM2, 0.75) * request.AreaM2),
+		PricePerM2:     math.Round(median*100) / 100,
+		Scope:          scope,
+		Confidence:     priceConfidence(scope, len(comparables)),
+		Comparables:    comparables,
+	}
+
+	if request.Price != nil {
+		price := *request.Price
+		deviation := math.Round((price-suggestion.SuggestedPrice)/suggestion.SuggestedPrice*1000) / 10
+		suggestion.DeviationPercent = &deviation
+		switch {
+		case price < suggestion.MinPrice:
+			suggestion.Assessment = PriceAssessmentBelow
+		case price > suggestion.MaxPrice:
+			suggestion.Assessment = PriceAssessmentAbove
+		default:
+			suggestion.Assessment = PriceAssessmentWithin
+		}
+	}
+	return suggestion, nil
+}
+
+// priceConfidence is high with many comparables in the same sector or city, and low
+// when the search had to widen to the province
+func priceConfidence(scope string, comparables int) string {
+	switch {
+	case scope == ComparableScopeProvince:
+		return PriceConfidenceLow
+	case comparables >= 2*MinComparables:
+		return PriceConfidenceHigh
+	default:
+		return PriceConfidenceMedium
+	}
+}
+
+// percentile interpolates the p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// roundPrice rounds a suggested price to hundreds of dollars
+func roundPrice(price float64) float64 {
+	return math.Round(price/100) * 100
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPriceSuggestion(t *testing.T) {
+	request := &PriceSuggestionRequest{Province: "Pichincha", City: "Quito", Type: TypeApartment, AreaM2: 100}
+	require.NoError(t, request.Validate())
+
+	comparables := []Comparable{}
+	for _, perM2 := range []float64{1000, 1100, 1200, 1300, 1400} {
+		comparables = append(comparables, Comparable{PricePerM2: perM2})
+	}
+
+	_, err := NewPriceSuggestion(request, ComparableScopeCity, comparables[:4])
+	assert.ErrorContains(t, err, "not enough comparables")
+
+	price := 180000.0
+	request.Price = &price
+	suggestion, err := NewPriceSuggestion(request, ComparableScopeCity, comparables)
+	require.NoError(t, err)
+	assert.Equal(t, 120000.0, suggestion.SuggestedPrice)
+	assert.Equal(t, 110000.0, suggestion.MinPrice)
+	assert.Equal(t, 130000.0, suggestion.MaxPrice)
+	assert.Equal(t, PriceConfidenceMedium, suggestion.Confidence)
+	assert.Equal(t, PriceAssessmentAbove, suggestion.Assessment)
+	assert.Equal(t, 50.0, *suggestion.DeviationPercent)
+
+	suggestion, err = NewPriceSuggestion(request, ComparableScopeProvince, comparables)
+	require.NoError(t, err)
+	assert.Equal(t, PriceConfidenceLow, suggestion.Confidence)
+
+	assert.ErrorContains(t, (&PriceSuggestionRequest{Province: "Pichincha", City: "Quito", Type: TypeHouse}).Validate(), "invalid area")
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Profile image kinds
+const (
+	ProfileImageAvatar = "avatar"
+	ProfileImageLogo   = "logo"
+)
+
+// Profile image limits. Uploads are cropped to a square and stored in every size of
+// ProfileImageSizes; the largest is the URL stored on the user or agency.
+const (
+	MaxProfileImageUploadSize = int64(5 * 1024 * 1024) // 5MB
+	MinProfileImageDimension  = 128
+)
+
+// ProfileImageSizes are the square variants generated for avatars and logos, smallest first
+var ProfileImageSizes = []int{64, 128, 256, 512}
+
+// ProfileImage is the avatar of a user or the logo of an agency. Each upload gets a new
+// version in its file names, so replacing an image changes its URLs and browsers and
+// CDNs never serve the previous one.
+type ProfileImage struct {
+	Kind      string            `json:"kind"`
+	OwnerID   string            `json:"owner_id"`
+	Version   string            `json:"version"`
+	URL       string            `json:"url"`
+	Variants  map[string]string `json:"variants"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// NewProfileImageVersion creates the version of a new upload
+func NewProfileImageVersion() string {
+	return strconv.FormatInt(time.Now().Unix(), 36) + uuid.New().String()[:4]
+}
+
+// ProfileImageFileName is the storage file name of a variant, e.g.
+// avatars/<user id>/<version>_256.jpg
+func ProfileImageFileName(kind, ownerID, version string, size int) string {
+	ext := "jpg"
+	if kind == ProfileImageLogo {
+		ext = "png" // logos keep their transparency
+	}
+	return fmt.Sprintf("%ss/%s/%s_%d.%s", kind, ownerID, version, size, ext)
+}
+
+// ProfileImageVariants derives the URLs of every variant from the URL of the largest
+// one. URLs not named by ProfileImageFileName have no variants.
+func ProfileImageVariants(url *string) map[string]string {
+	if url == nil {
+		return nil
+	}
+
+	largest := ProfileImageSizes[len(ProfileImageSizes)-1]
+	suffix := fmt.Sprintf("_%d.", largest)
+	index := strings.LastIndex(*url, suffix)
+	if index < 0 {
+		return nil
+	}
+
+	variants := make(map[string]string, len(ProfileImageSizes))
+	for _, size := range ProfileImageSizes {
+		variants[strconv.Itoa(size)] = fmt.Sprintf("%s_%d.%s", (*url)[:index], size, (*url)[index+len(suffix):])
+	}
+	return variants
+}
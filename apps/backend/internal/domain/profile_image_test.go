@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileImageVariants(t *testing.T) {
+	assert.Equal(t, "avatars/user-1/v1_256.jpg", ProfileImageFileName(ProfileImageAvatar, "user-1", "v1", 256))
+	assert.Equal(t, "logos/agency-1/v1_512.png", ProfileImageFileName(ProfileImageLogo, "agency-1", "v1", 512))
+
+	url := "/uploads/images/originals/avatars/user-1/v1_512.jpg"
+	variants := ProfileImageVariants(&url)
+	assert.Len(t, variants, len(ProfileImageSizes))
+	assert.Equal(t, "/uploads/images/originals/avatars/user-1/v1_64.jpg", variants["64"])
+	assert.Equal(t, url, variants["512"])
+
+	external := "https://example.com/logo.png"
+	assert.Nil(t, ProfileImageVariants(&external))
+	assert.Nil(t, ProfileImageVariants(nil))
+}
@@ -14,7 +14,12 @@ type Property struct {
 	ID                    string    `json:"id" db:"id"`
 	Slug                  string    `json:"slug" db:"slug"`
 	Title                 string    `json:"title" db:"title"`
+	// Description is the plain text of DescriptionHTML, safe to show anywhere
 	Description           string    `json:"description" db:"description"`
+	// DescriptionHTML is the description agents wrote, sanitized by SanitizeRichText
+	DescriptionHTML       string    `json:"description_html" db:"description_html"`
+	// DescriptionRaw is the description as agents wrote it, never rendered
+	DescriptionRaw        string    `json:"-" db:"description_raw"`
 	Price                 float64   `json:"price" db:"price"`
 	Province              string    `json:"province" db:"province"`
 	City                  string    `json:"city" db:"city"`
@@ -58,8 +63,20 @@ type Property struct {
 	AgencyID              *string   `json:"agency_id" db:"agency_id"`
 	CreatedBy             *string   `json:"created_by" db:"created_by"`
 	UpdatedBy             *string   `json:"updated_by" db:"updated_by"`
+	// TenantID is the network the listing belongs to in multi-tenant installs
+	TenantID              string    `json:"-" db:"tenant_id"`
 	CreatedAt             time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+	// Locale is the language of Title and Description in localized responses
+	Locale                string    `json:"locale,omitempty" db:"-"`
+	// MachineTranslated marks localized content translated automatically
+	MachineTranslated     bool      `json:"machine_translated,omitempty" db:"-"`
+	// DisplayPrice is Price converted to the currency a response was requested in
+	DisplayPrice          *DisplayPrice `json:"display_price,omitempty" db:"-"`
+	// SectorGuide links to the published guide of the property's sector
+	SectorGuide           *SectorGuideLink `json:"sector_guide,omitempty" db:"-"`
+	// RiskFlags are the hazard zones the property lies in, set on property detail
+	RiskFlags             []PropertyRiskFlag `json:"risk_flags,omitempty" db:"-"`
 }
 
 // NewProperty creates a new property with automatically generated SEO slug
@@ -67,7 +84,7 @@ func NewProperty(title, description, province, city, propertyType string, price
 	id := uuid.New().String()
 	slug := GenerateSlug(title, id)
 
-	return &Property{
+	property := &Property{
 		ID:                id,
 		Slug:              slug,
 		Title:             title,
@@ -106,6 +123,8 @@ func NewProperty(title, description, province, city, propertyType string, price
 		CreatedAt:         time.Now(),
 		UpdatedAt:         time.Now(),
 	}
+	property.NormalizeDescription()
+	return property
 }
 
 // IsValid validates the required fields of the property
@@ -147,6 +166,19 @@ func (p *Property) UpdateSlug() {
 	p.UpdateTimestamp()
 }
 
+// NormalizeDescription sanitizes the description. A Description that no longer is the
+// plain text of DescriptionHTML was set by the caller and becomes the raw description,
+// which may hold HTML; otherwise the raw description is kept.
+func (p *Property) NormalizeDescription() {
+	if p.Description != RichTextPlainText(p.DescriptionHTML) {
+		p.DescriptionRaw = p.Description
+	} else if p.DescriptionRaw == "" {
+		p.DescriptionRaw = p.DescriptionHTML
+	}
+	p.DescriptionHTML = SanitizeRichText(p.DescriptionRaw)
+	p.Description = RichTextPlainText(p.DescriptionHTML)
+}
+
 // IncrementViews increments the view counter
 func (p *Property) IncrementViews() {
 	p.ViewCount++
@@ -197,10 +229,12 @@ const (
 
 // Constants for property status
 const (
-	StatusAvailable = "available"
-	StatusSold      = "sold"
-	StatusRented    = "rented"
-	StatusReserved  = "reserved"
+	StatusAvailable   = "available"
+	StatusSold        = "sold"
+	StatusRented      = "rented"
+	StatusReserved    = "reserved"
+	StatusExpired     = "expired"     // stale listing hidden from public search until renewed
+	StatusQuarantined = "quarantined" // held by spam screening, hidden from public search until approved
 )
 
 // Constants for location precision
@@ -398,6 +432,11 @@ func (p *Property) IsAssignedToAgent(agentID string) bool {
 	return p.AgentID != nil && *p.AgentID == agentID
 }
 
+// IsForRent checks if the property is listed with a monthly rent
+func (p *Property) IsForRent() bool {
+	return p.RentPrice != nil && *p.RentPrice > 0
+}
+
 // GetOwnerID returns the owner ID if available
 func (p *Property) GetOwnerID() *string {
 	return p.OwnerID
@@ -457,6 +496,9 @@ type PaginationParams struct {
 	PageSize int    `json:"page_size"`
 	SortBy   string `json:"sort_by"`
 	SortDesc bool   `json:"sort_desc"`
+	// TotalEstimated is set by repositories whose count strategy returned an estimated
+	// or cached total instead of counting the matches
+	TotalEstimated bool `json:"-"`
 }
 
 // NewPaginationParams creates default pagination parameters
@@ -541,9 +583,11 @@ type Pagination struct {
 	TotalRecords int  `json:"total_records"`
 	HasNext      bool `json:"has_next"`
 	HasPrev      bool `json:"has_prev"`
+	// TotalExact is false when TotalRecords, and so TotalPages and HasNext, are estimates
+	TotalExact   bool `json:"total_exact"`
 }
 
-// NewPagination creates pagination metadata
+// NewPagination creates pagination metadata with an exact total
 func NewPagination(currentPage, pageSize, totalRecords int) *Pagination {
 	totalPages := (totalRecords + pageSize - 1) / pageSize
 	if totalPages == 0 {
@@ -557,9 +601,18 @@ func NewPagination(currentPage, pageSize, totalRecords int) *Pagination {
 		TotalRecords: totalRecords,
 		HasNext:      currentPage < totalPages,
 		HasPrev:      currentPage > 1,
+		TotalExact:   true,
 	}
 }
 
+// Paginate creates the pagination metadata of a page of results, flagging totals the
+// repository estimated
+func (p *PaginationParams) Paginate(totalRecords int) *Pagination {
+	pagination := NewPagination(p.Page, p.PageSize, totalRecords)
+	pagination.TotalExact = !p.TotalEstimated
+	return pagination
+}
+
 // Role-based property management methods
 
 // SetOwner sets the property owner
@@ -661,6 +714,11 @@ type PropertySearchFilters struct {
 	AgentID   *string `json:"agent_id"`
 	AgencyID  *string `json:"agency_id"`
 	CreatedBy *string `json:"created_by"`
+	// IncludeExpired keeps expired listings in results without role-based filters,
+	// for administrators reviewing every listing
+	IncludeExpired bool `json:"-"`
+	// TenantID restricts results to one tenant; set from the resolved request tenant
+	TenantID string `json:"-"`
 	
 	// Additional filters
 	HasPool           *bool    `json:"has_pool"`
@@ -674,6 +732,10 @@ type PropertySearchFilters struct {
 	MinParkingSpaces  *int     `json:"min_parking_spaces"`
 	Furnished         *bool    `json:"furnished"`
 	Tags              []string `json:"tags"`
+	// Amenities are catalog codes, e.g. generator or pet_friendly; listings must have all of them
+	Amenities         []string `json:"amenities"`
+	// ExcludeRisks are hazards, e.g. flood; listings in a zone of any of them are left out
+	ExcludeRisks      []string `json:"exclude_risks"`
 	
 	// Pagination
 	Pagination *PaginationParams `json:"pagination"`
@@ -684,4 +746,115 @@ func NewPropertySearchFilters() *PropertySearchFilters {
 	return &PropertySearchFilters{
 		Pagination: NewPaginationParams(),
 	}
-}
\ No newline at end of file
+}
+// IsValidListingStatus verifies if the listing status (available, sold, ...) is valid
+func IsValidListingStatus(status string) bool {
+	validStatuses := []string{StatusAvailable, StatusSold, StatusRented, StatusReserved}
+	for _, s := range validStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCriteria reports whether at least one filter has been set
+func (f *PropertySearchFilters) HasCriteria() bool {
+	return f.Query != "" ||
+		f.MinPrice != nil || f.MaxPrice != nil ||
+		len(f.PropertyTypes) > 0 || len(f.Provinces) > 0 || len(f.Cities) > 0 || len(f.Sectors) > 0 ||
+		f.MinBedrooms != nil || f.MaxBedrooms != nil ||
+		f.MinBathrooms != nil || f.MaxBathrooms != nil ||
+		f.MinArea != nil || f.MaxArea != nil ||
+		len(f.Status) > 0 || f.Featured != nil ||
+		f.OwnerID != nil || f.AgentID != nil || f.AgencyID != nil || f.CreatedBy != nil ||
+		f.HasPool != nil || f.HasGarden != nil || f.HasTerrace != nil || f.HasBalcony != nil ||
+		f.HasSecurity != nil || f.HasElevator != nil || f.HasAirCondition != nil || f.HasParking != nil ||
+		f.MinParkingSpaces != nil || f.Furnished != nil || len(f.Tags) > 0 || len(f.Amenities) > 0 ||
+		len(f.ExcludeRisks) > 0
+}
+
+// ScopeToActor restricts the filters to the listings an actor manages: admins see
+// every listing, agency accounts their agency's, agents those assigned to them and
+// other users the ones they own. Role-based
+// filters from the request are kept for admins and replaced for everyone else.
+func (f *PropertySearchFilters) ScopeToActor(actor Actor) {
+	if actor.Role == RoleAdmin {
+		f.IncludeExpired = true
+		return
+	}
+
+	f.OwnerID, f.AgentID, f.AgencyID, f.CreatedBy = nil, nil, nil, nil
+	userID := actor.UserID
+	switch actor.Role {
+	case RoleAgency:
+		agencyID := actor.AgencyID
+		if agencyID == "" {
+			agencyID = userID
+		}
+		f.AgencyID = &agencyID
+	case RoleAgent:
+		f.AgentID = &userID
+	default:
+		f.OwnerID = &userID
+	}
+}
+
+// Validate validates the filter values and ranges
+func (f *PropertySearchFilters) Validate() error {
+	if f.MinPrice != nil && *f.MinPrice < 0 || f.MaxPrice != nil && *f.MaxPrice < 0 {
+		return fmt.Errorf("prices must be positive")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return fmt.Errorf("minimum price cannot be greater than maximum price")
+	}
+	if f.MinBedrooms != nil && *f.MinBedrooms < 0 || f.MaxBedrooms != nil && *f.MaxBedrooms < 0 {
+		return fmt.Errorf("bedrooms must be positive")
+	}
+	if f.MinBedrooms != nil && f.MaxBedrooms != nil && *f.MinBedrooms > *f.MaxBedrooms {
+		return fmt.Errorf("minimum bedrooms cannot be greater than maximum bedrooms")
+	}
+	if f.MinBathrooms != nil && *f.MinBathrooms < 0 || f.MaxBathrooms != nil && *f.MaxBathrooms < 0 {
+		return fmt.Errorf("bathrooms must be positive")
+	}
+	if f.MinBathrooms != nil && f.MaxBathrooms != nil && *f.MinBathrooms > *f.MaxBathrooms {
+		return fmt.Errorf("minimum bathrooms cannot be greater than maximum bathrooms")
+	}
+	if f.MinArea != nil && *f.MinArea < 0 || f.MaxArea != nil && *f.MaxArea < 0 {
+		return fmt.Errorf("area must be positive")
+	}
+	if f.MinArea != nil && f.MaxArea != nil && *f.MinArea > *f.MaxArea {
+		return fmt.Errorf("minimum area cannot be greater than maximum area")
+	}
+	if f.MinParkingSpaces != nil && *f.MinParkingSpaces < 0 {
+		return fmt.Errorf("parking spaces must be positive")
+	}
+	for _, province := range f.Provinces {
+		if !IsValidProvince(province) {
+			return fmt.Errorf("invalid province: %s", province)
+		}
+	}
+	for _, propertyType := range f.PropertyTypes {
+		if !IsValidPropertyType(propertyType) {
+			return fmt.Errorf("invalid property type: %s", propertyType)
+		}
+	}
+	for _, status := range f.Status {
+		if !IsValidListingStatus(status) {
+			return fmt.Errorf("invalid status: %s", status)
+		}
+	}
+	if len(f.Amenities) > MaxAmenitySearchCriteria {
+		return fmt.Errorf("invalid amenities: filter by at most %d", MaxAmenitySearchCriteria)
+	}
+	for i, code := range f.Amenities {
+		f.Amenities[i] = NormalizeAmenityCode(code)
+	}
+	for i, hazard := range f.ExcludeRisks {
+		f.ExcludeRisks[i] = strings.ToLower(strings.TrimSpace(hazard))
+		if !IsValidHazard(f.ExcludeRisks[i]) {
+			return fmt.Errorf("invalid hazard: %s", hazard)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Property document types
+const (
+	DocumentTypeDeed      = "deed"
+	DocumentTypeFloorPlan = "floor_plan"
+	DocumentTypeContract  = "contract"
+	DocumentTypeOther     = "other"
+)
+
+// Document visibility levels
+const (
+	// DocumentVisibilityPublic documents are listed with the property, e.g. floor plans
+	DocumentVisibilityPublic = "public"
+	// DocumentVisibilityPrivate documents are only visible to whoever manages the listing,
+	// e.g. deeds and contracts
+	DocumentVisibilityPrivate = "private"
+)
+
+// Document limits
+const (
+	MaxDocumentUploadSize   = 20 * 1024 * 1024 // 20MB
+	MaxDocumentsPerProperty = 20
+)
+
+// SupportedDocumentMimeTypes maps accepted upload content types to file extensions
+var SupportedDocumentMimeTypes = map[string][]string{
+	"application/pdf": {".pdf"},
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/webp":      {".webp"},
+}
+
+// documentDefaultVisibility is the visibility of each document type when none is requested
+var documentDefaultVisibility = map[string]string{
+	DocumentTypeDeed:      DocumentVisibilityPrivate,
+	DocumentTypeFloorPlan: DocumentVisibilityPublic,
+	DocumentTypeContract:  DocumentVisibilityPrivate,
+	DocumentTypeOther:     DocumentVisibilityPrivate,
+}
+
+// PropertyDocument is a file attached to a property, such as a deed or a floor plan
+type PropertyDocument struct {
+	ID          string    `json:"id"`
+	PropertyID  string    `json:"property_id"`
+	Type        string    `json:"type"`
+	Visibility  string    `json:"visibility"`
+	Title       string    `json:"title"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	URL         string    `json:"url"`
+	StoragePath string    `json:"-"`
+	UploadedBy  string    `json:"uploaded_by"`
+	TemplateID  *string   `json:"template_id,omitempty"` // contract template it was generated from
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewPropertyDocument creates a document of the given type. An empty visibility uses the
+// default of the type: floor plans are public, every other document private.
+func NewPropertyDocument(propertyID, docType, visibility, title, fileName, contentType string, size int64) (*PropertyDocument, error) {
+	docType = strings.ToLower(strings.TrimSpace(docType))
+	defaultVisibility, ok := documentDefaultVisibility[docType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported document type: %s", docType)
+	}
+
+	visibility = strings.ToLower(strings.TrimSpace(visibility))
+	if visibility == "" {
+		visibility = defaultVisibility
+	}
+	if visibility != DocumentVisibilityPublic && visibility != DocumentVisibilityPrivate {
+		return nil, fmt.Errorf("unsupported document visibility: %s", visibility)
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	}
+	if len(title) > 255 {
+		return nil, fmt.Errorf("document title must be at most 255 characters")
+	}
+
+	id := uuid.New().String()
+	return &PropertyDocument{
+		ID:          id,
+		PropertyID:  propertyID,
+		Type:        docType,
+		Visibility:  visibility,
+		Title:       title,
+		FileName:    fileName,
+		ContentType: normalizeContentType(contentType),
+		Size:        size,
+		StoragePath: fmt.Sprintf("documents/%s/%s%s", propertyID, id, strings.ToLower(filepath.Ext(fileName))),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// IsPublic reports whether the document is listed to every visitor of the property
+func (d *PropertyDocument) IsPublic() bool {
+	return d.Visibility == DocumentVisibilityPublic
+}
+
+// ValidateDocumentUpload validates the name, content type and size of a document upload
+func ValidateDocumentUpload(fileName, contentType string, size, maxSize int64) error {
+	if size <= 0 {
+		return fmt.Errorf("file is empty")
+	}
+	if size > maxSize {
+		return fmt.Errorf("file too large: %d bytes, max: %d bytes", size, maxSize)
+	}
+
+	extensions, ok := SupportedDocumentMimeTypes[normalizeContentType(contentType)]
+	if !ok {
+		return fmt.Errorf("unsupported document type: %s", contentType)
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, supported := range extensions {
+		if ext == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported file extension: %s", ext)
+}
+
+// normalizeContentType strips parameters and case from a content type
+func normalizeContentType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropertyDocument(t *testing.T) {
+	plan, err := NewPropertyDocument("prop-1", "floor_plan", "", "", "Planta Baja.PDF", "application/pdf", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, DocumentVisibilityPublic, plan.Visibility, "floor plans are public by default")
+	assert.Equal(t, "Planta Baja", plan.Title)
+	assert.Equal(t, "documents/prop-1/"+plan.ID+".pdf", plan.StoragePath)
+	assert.True(t, plan.IsPublic())
+
+	deed, err := NewPropertyDocument("prop-1", " Deed ", "", "Escritura", "escritura.pdf", "application/pdf; charset=binary", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, DocumentTypeDeed, deed.Type)
+	assert.Equal(t, DocumentVisibilityPrivate, deed.Visibility, "deeds are private by default")
+	assert.Equal(t, "application/pdf", deed.ContentType)
+
+	public, err := NewPropertyDocument("prop-1", "contract", "public", "Modelo", "modelo.pdf", "application/pdf", 1024)
+	require.NoError(t, err)
+	assert.True(t, public.IsPublic())
+
+	_, err = NewPropertyDocument("prop-1", "invoice", "", "", "a.pdf", "application/pdf", 1024)
+	assert.ErrorContains(t, err, "unsupported document type")
+
+	_, err = NewPropertyDocument("prop-1", "deed", "shared", "", "a.pdf", "application/pdf", 1024)
+	assert.ErrorContains(t, err, "unsupported document visibility")
+
+	_, err = NewPropertyDocument("prop-1", "deed", "", strings.Repeat("a", 256), "a.pdf", "application/pdf", 1024)
+	assert.Error(t, err)
+}
+
+func TestValidateDocumentUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		contentType string
+		size        int64
+		wantErr     string
+	}{
+		{"pdf", "deed.pdf", "application/pdf", 100, ""},
+		{"jpeg floor plan", "plan.jpeg", "image/jpeg", 100, ""},
+		{"empty", "deed.pdf", "application/pdf", 0, "file is empty"},
+		{"too large", "deed.pdf", "application/pdf", 2000, "file too large"},
+		{"unsupported type", "deed.docx", "application/msword", 100, "unsupported document type"},
+		{"mismatched extension", "deed.exe", "application/pdf", 100, "unsupported file extension"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDocumentUpload(tt.fileName, tt.contentType, tt.size, 1000)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Property draft limits
+const (
+	MaxPropertyDraftBytes    = 256 << 10 // payload size, images are uploaded separately
+	MaxPropertyDraftsPerUser = 20
+	MaxPropertyDraftTitle    = 255
+	// PropertyDraftRetention is how long drafts are kept after their last autosave
+	PropertyDraftRetention = 30 * 24 * time.Hour
+)
+
+// PropertyDraft is the autosaved state of the listing creation wizard. The payload has
+// the fields of a property creation request but is not validated until the draft is
+// published, so partially filled forms can be saved at every step.
+type PropertyDraft struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	Title     string          `json:"title"` // copied from the payload to list drafts
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// NewPropertyDraft creates a draft with a client-generated UUID, so the wizard can
+// autosave before the first response arrives
+func NewPropertyDraft(id, userID string, payload json.RawMessage) (*PropertyDraft, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid draft ID: must be a UUID")
+	}
+
+	now := time.Now()
+	draft := &PropertyDraft{ID: strings.ToLower(id), UserID: userID, CreatedAt: now, UpdatedAt: now}
+	if err := draft.SetPayload(payload); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// SetPayload replaces the payload of the draft. It must be a JSON object; its fields
+// are only checked when the draft is published.
+func (d *PropertyDraft) SetPayload(payload json.RawMessage) error {
+	payload = bytes.TrimSpace(payload)
+	if len(payload) > MaxPropertyDraftBytes {
+		return fmt.Errorf("invalid draft: payload exceeds %d bytes", MaxPropertyDraftBytes)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		return fmt.Errorf("invalid draft: payload must be a JSON object")
+	}
+
+	var title string
+	if raw, ok := fields["title"]; ok {
+		// Ignore titles of the wrong type, the draft is not validated yet
+		json.Unmarshal(raw, &title)
+	}
+	title = strings.TrimSpace(title)
+	if runes := []rune(title); len(runes) > MaxPropertyDraftTitle {
+		title = string(runes[:MaxPropertyDraftTitle])
+	}
+
+	d.Payload = payload
+	d.Title = title
+	d.UpdatedAt = time.Now()
+	return nil
+}
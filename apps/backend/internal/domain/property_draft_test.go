@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropertyDraft(t *testing.T) {
+	draft, err := NewPropertyDraft("6F1C1B9E-3D4B-4C55-9A57-1C2B3D4E5F60", "user-1", json.RawMessage(` {"title":" Casa en Cumbayá ","price":"not validated yet"} `))
+	require.NoError(t, err)
+	assert.Equal(t, "6f1c1b9e-3d4b-4c55-9a57-1c2b3d4e5f60", draft.ID)
+	assert.Equal(t, "Casa en Cumbayá", draft.Title)
+	assert.True(t, strings.HasPrefix(string(draft.Payload), "{"))
+
+	_, err = NewPropertyDraft("draft-1", "user-1", json.RawMessage(`{}`))
+	assert.ErrorContains(t, err, "invalid draft ID")
+
+	for _, payload := range []string{`[]`, `null`, `"casa"`, `{"title":`} {
+		_, err = NewPropertyDraft("6f1c1b9e-3d4b-4c55-9a57-1c2b3d4e5f60", "user-1", json.RawMessage(payload))
+		assert.ErrorContains(t, err, "must be a JSON object", payload)
+	}
+
+	// Titles of the wrong type do not block autosaves
+	require.NoError(t, draft.SetPayload(json.RawMessage(`{"title":42}`)))
+	assert.Empty(t, draft.Title)
+}
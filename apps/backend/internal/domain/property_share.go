@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channels a property is shared through from its page
+const (
+	ShareChannelWhatsApp = "whatsapp"
+	ShareChannelFacebook = "facebook"
+	ShareChannelLink     = "link" // copied link
+)
+
+// ShareDedupWindow is how long repeated shares of a property through the same channel
+// by the same user or session count once
+const ShareDedupWindow = time.Hour
+
+// DefaultFunnelDays is the period of the conversion funnel when none is given
+const DefaultFunnelDays = 30
+
+// shareSessionRegex matches the session IDs browsers attribute anonymous shares to
+var shareSessionRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// ShareChannels lists the valid share channels
+func ShareChannels() []string {
+	return []string{ShareChannelWhatsApp, ShareChannelFacebook, ShareChannelLink}
+}
+
+// IsValidShareChannel reports whether channel is a known share channel
+func IsValidShareChannel(channel string) bool {
+	for _, valid := range ShareChannels() {
+		if channel == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertyShare records a property being shared, attributed to the signed-in user or
+// to the browser session of an anonymous visitor
+type PropertyShare struct {
+	ID         string    `json:"id"`
+	PropertyID string    `json:"property_id"`
+	Channel    string    `json:"channel"`
+	UserID     string    `json:"user_id,omitempty"`
+	SessionID  string    `json:"session_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewPropertyShare creates a share event after validating it. Anonymous shares need a
+// session ID to be told apart.
+func NewPropertyShare(propertyID, channel, userID, sessionID string, now time.Time) (*PropertyShare, error) {
+	if !IsValidShareChannel(channel) {
+		return nil, fmt.Errorf("channel must be one of %v", ShareChannels())
+	}
+	if sessionID != "" && !shareSessionRegex.MatchString(sessionID) {
+		return nil, fmt.Errorf("session_id must be 8 to 64 letters, digits, dashes or underscores")
+	}
+	if userID == "" && sessionID == "" {
+		return nil, fmt.Errorf("anonymous shares need a session_id")
+	}
+	return &PropertyShare{
+		ID:         uuid.New().String(),
+		PropertyID: propertyID,
+		Channel:    channel,
+		UserID:     userID,
+		SessionID:  sessionID,
+		CreatedAt:  now,
+	}, nil
+}
+
+// ShareCounts summarizes the shares of a property or of the whole site
+type ShareCounts struct {
+	Total     int            `json:"total"`
+	ByChannel map[string]int `json:"by_channel"`
+}
+
+// NewShareCounts returns share counts with every channel at zero
+func NewShareCounts() *ShareCounts {
+	counts := &ShareCounts{ByChannel: map[string]int{}}
+	for _, channel := range ShareChannels() {
+		counts.ByChannel[channel] = 0
+	}
+	return counts
+}
+
+// Add counts shares of a channel
+func (c *ShareCounts) Add(channel string, count int) {
+	c.ByChannel[channel] += count
+	c.Total += count
+}
+
+// Steps of the conversion funnel, in order
+const (
+	FunnelStepShares      = "shares"
+	FunnelStepShareVisits = "share_visits" // visits through shared short links
+	FunnelStepInquiries   = "inquiries"
+	FunnelStepVisits      = "visits" // property visits scheduled by agents
+)
+
+// ConversionFunnelCounts are the events of each funnel step in a period
+type ConversionFunnelCounts struct {
+	Shares      ShareCounts
+	ShareVisits int
+	Inquiries   int
+	Visits      int
+}
+
+// FunnelStep is a step of the conversion funnel. Its conversion rate is its count as a
+// percentage of the previous step's.
+type FunnelStep struct {
+	Key            string  `json:"key"`
+	Count          int     `json:"count"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// ConversionFunnel reports how sharing listings turns into visits, inquiries and
+// scheduled property visits across the site
+type ConversionFunnel struct {
+	From   time.Time    `json:"from"`
+	To     time.Time    `json:"to"`
+	Steps  []FunnelStep `json:"steps"`
+	Shares ShareCounts  `json:"shares"`
+}
+
+// BuildConversionFunnel computes the steps of the conversion funnel from their counts
+func BuildConversionFunnel(counts *ConversionFunnelCounts, from, to time.Time) *ConversionFunnel {
+	funnel := &ConversionFunnel{From: from, To: to, Shares: counts.Shares}
+	values := []struct {
+		key   string
+		count int
+	}{
+		{FunnelStepShares, counts.Shares.Total},
+		{FunnelStepShareVisits, counts.ShareVisits},
+		{FunnelStepInquiries, counts.Inquiries},
+		{FunnelStepVisits, counts.Visits},
+	}
+	for i, value := range values {
+		step := FunnelStep{Key: value.key, Count: value.count}
+		if i > 0 && values[i-1].count > 0 {
+			step.ConversionRate = math.Round(float64(value.count)/float64(values[i-1].count)*1000) / 10
+		}
+		funnel.Steps = append(funnel.Steps, step)
+	}
+	return funnel
+}
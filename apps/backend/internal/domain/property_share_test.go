@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropertyShare(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewPropertyShare("prop-1", "telegram", "user-1", "", now)
+	assert.ErrorContains(t, err, "channel must be one of")
+	_, err = NewPropertyShare("prop-1", ShareChannelWhatsApp, "", "", now)
+	assert.ErrorContains(t, err, "anonymous shares need a session_id")
+	_, err = NewPropertyShare("prop-1", ShareChannelWhatsApp, "", "abc", now)
+	assert.ErrorContains(t, err, "session_id must be")
+
+	share, err := NewPropertyShare("prop-1", ShareChannelFacebook, "", "a1b2c3d4-session", now)
+	require.NoError(t, err)
+	assert.Equal(t, "a1b2c3d4-session", share.SessionID)
+	assert.Empty(t, share.UserID)
+}
+
+func TestBuildConversionFunnel(t *testing.T) {
+	shares := NewShareCounts()
+	shares.Add(ShareChannelWhatsApp, 150)
+	shares.Add(ShareChannelLink, 50)
+
+	funnel := BuildConversionFunnel(&ConversionFunnelCounts{Shares: *shares, ShareVisits: 80, Inquiries: 12}, time.Time{}, time.Time{})
+	assert.Equal(t, []FunnelStep{
+		{Key: FunnelStepShares, Count: 200},
+		{Key: FunnelStepShareVisits, Count: 80, ConversionRate: 40},
+		{Key: FunnelStepInquiries, Count: 12, ConversionRate: 15},
+		{Key: FunnelStepVisits, Count: 0},
+	}, funnel.Steps)
+	assert.Equal(t, 0, funnel.Shares.ByChannel[ShareChannelFacebook], "channels without shares are reported")
+}
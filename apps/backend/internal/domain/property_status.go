@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxStatusChangeReasonLength limits the reason of a status change
+const MaxStatusChangeReasonLength = 500
+
+// listingStatusTransitions are the statuses a listing can move to from each status.
+// Rented listings are re-listed when the lease ends; sold listings only when the sale
+// fell through, which admins confirm. Expired listings are renewed and quarantined
+// ones approved through their own flows.
+var listingStatusTransitions = map[string][]string{
+	StatusAvailable: {StatusReserved, StatusSold, StatusRented},
+	StatusReserved:  {StatusAvailable, StatusSold, StatusRented},
+	StatusRented:    {StatusAvailable, StatusSold},
+	StatusSold:      {StatusAvailable},
+}
+
+// CanTransitionStatus reports whether a listing can move from one status to another
+func CanTransitionStatus(from, to string) bool {
+	for _, allowed := range listingStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NextListingStatuses returns the statuses a listing can move to from a status
+func NextListingStatuses(from string) []string {
+	return append([]string{}, listingStatusTransitions[from]...)
+}
+
+// PropertyStatusChange records a transition of a listing's status
+type PropertyStatusChange struct {
+	ID         string    `json:"id"`
+	PropertyID string    `json:"property_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	FinalPrice *float64  `json:"final_price,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedBy  string    `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// NewPropertyStatusChange validates a transition and the data it requires: sold and
+// rented need the final price, and re-listing a sold property needs a reason.
+func NewPropertyStatusChange(propertyID, from, to string, finalPrice *float64, reason, changedBy string) (*PropertyStatusChange, error) {
+	to = strings.ToLower(strings.TrimSpace(to))
+	reason = strings.TrimSpace(reason)
+
+	if !IsValidListingStatus(to) {
+		return nil, fmt.Errorf("invalid status: %s", to)
+	}
+	if !CanTransitionStatus(from, to) {
+		return nil, fmt.Errorf("invalid status transition: cannot change from %s to %s", from, to)
+	}
+	if (to == StatusSold || to == StatusRented) && (finalPrice == nil || *finalPrice <= 0) {
+		return nil, fmt.Errorf("final price is required to mark a property %s", to)
+	}
+	if to != StatusSold && to != StatusRented {
+		finalPrice = nil
+	}
+	if from == StatusSold && reason == "" {
+		return nil, fmt.Errorf("reason is required to re-list a sold property")
+	}
+	if len(reason) > MaxStatusChangeReasonLength {
+		return nil, fmt.Errorf("reason cannot exceed %d characters", MaxStatusChangeReasonLength)
+	}
+
+	return &PropertyStatusChange{
+		ID:         uuid.New().String(),
+		PropertyID: propertyID,
+		FromStatus: from,
+		ToStatus:   to,
+		FinalPrice: finalPrice,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+		ChangedAt:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropertyStatusChange(t *testing.T) {
+	price := 240000.0
+
+	tests := []struct {
+		name       string
+		from, to   string
+		finalPrice *float64
+		reason     string
+		wantErr    string
+	}{
+		{"reserve available", StatusAvailable, StatusReserved, nil, "", ""},
+		{"sell reserved", StatusReserved, StatusSold, &price, "", ""},
+		{"re-list rented", StatusRented, StatusAvailable, nil, "", ""},
+		{"re-list sold with reason", StatusSold, StatusAvailable, nil, "Financing fell through", ""},
+		{"sold requires final price", StatusAvailable, StatusSold, nil, "", "final price is required"},
+		{"reserve sold", StatusSold, StatusReserved, nil, "", "invalid status transition"},
+		{"renew expired", StatusExpired, StatusAvailable, nil, "", "invalid status transition"},
+		{"re-list sold requires reason", StatusSold, StatusAvailable, nil, "", "reason is required"},
+		{"unknown status", StatusAvailable, "archived", nil, "", "invalid status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := NewPropertyStatusChange("prop-1", tt.from, tt.to, tt.finalPrice, tt.reason, "user-1")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, change.FromStatus)
+			assert.Equal(t, tt.to, change.ToStatus)
+		})
+	}
+}
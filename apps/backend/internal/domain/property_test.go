@@ -777,4 +777,126 @@ func TestPaginatedResponse(t *testing.T) {
 	assert.NotNil(t, response.Pagination)
 	assert.Equal(t, properties, response.Data)
 	assert.Equal(t, pagination, response.Pagination)
-}
\ No newline at end of file
+}
+func TestPropertySearchFilters_HasCriteria(t *testing.T) {
+	filters := NewPropertySearchFilters()
+	assert.False(t, filters.HasCriteria())
+
+	pool := true
+	filters.HasPool = &pool
+	assert.True(t, filters.HasCriteria())
+
+	filters = NewPropertySearchFilters()
+	filters.Cities = []string{"Cuenca"}
+	assert.True(t, filters.HasCriteria())
+}
+
+func TestPropertySearchFilters_ScopeToActor(t *testing.T) {
+	requested := "agency-2"
+
+	tests := []struct {
+		name       string
+		actor      Actor
+		wantOwner  string
+		wantAgent  string
+		wantAgency string
+	}{
+		{name: "agency account", actor: NewActor("user-1", "agency", "agency-1"), wantAgency: "agency-1"},
+		{name: "agency account identified by user ID", actor: NewActor("agency-1", "agency", ""), wantAgency: "agency-1"},
+		{name: "agent", actor: NewActor("agent-1", "agent", "agency-1"), wantAgent: "agent-1"},
+		{name: "owner", actor: NewActor("owner-1", "owner", ""), wantOwner: "owner-1"},
+		{name: "buyer", actor: NewActor("buyer-1", "buyer", ""), wantOwner: "buyer-1"},
+	}
+
+	value := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return *p
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := NewPropertySearchFilters()
+			filters.AgencyID = &requested
+			filters.ScopeToActor(tt.actor)
+
+			assert.Equal(t, tt.wantOwner, value(filters.OwnerID))
+			assert.Equal(t, tt.wantAgent, value(filters.AgentID))
+			assert.Equal(t, tt.wantAgency, value(filters.AgencyID), "requested scopes are replaced")
+			assert.False(t, filters.IncludeExpired)
+		})
+	}
+
+	filters := NewPropertySearchFilters()
+	filters.AgencyID = &requested
+	filters.ScopeToActor(NewActor("admin-1", "admin", ""))
+	assert.Equal(t, "agency-2", *filters.AgencyID, "admins keep the requested scope")
+	assert.True(t, filters.IncludeExpired)
+}
+
+func TestPropertySearchFilters_Validate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		setup    func(f *PropertySearchFilters)
+		errorMsg string
+	}{
+		{"empty filters", func(f *PropertySearchFilters) {}, ""},
+		{"valid combination", func(f *PropertySearchFilters) {
+			f.Provinces = []string{"Azuay"}
+			f.PropertyTypes = []string{TypeApartment}
+			f.Status = []string{StatusAvailable}
+			f.MinBedrooms = intPtr(1)
+			f.MaxBedrooms = intPtr(3)
+		}, ""},
+		{"negative price", func(f *PropertySearchFilters) { f.MinPrice = floatPtr(-1) }, "prices must be positive"},
+		{"inverted bedrooms", func(f *PropertySearchFilters) {
+			f.MinBedrooms = intPtr(4)
+			f.MaxBedrooms = intPtr(2)
+		}, "minimum bedrooms cannot be greater than maximum bedrooms"},
+		{"inverted area", func(f *PropertySearchFilters) {
+			f.MinArea = floatPtr(200)
+			f.MaxArea = floatPtr(100)
+		}, "minimum area cannot be greater than maximum area"},
+		{"invalid province", func(f *PropertySearchFilters) { f.Provinces = []string{"Atlantis"} }, "invalid province"},
+		{"invalid status", func(f *PropertySearchFilters) { f.Status = []string{"archived"} }, "invalid status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := NewPropertySearchFilters()
+			tt.setup(filters)
+
+			err := filters.Validate()
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestProperty_NormalizeDescription(t *testing.T) {
+	raw := `<h1>Casa</h1><p onclick="alert(1)">Con <b>piscina</b></p><script>alert(1)</script>`
+	property := NewProperty("Casa", raw, "Guayas", "Samborondón", "house", 285000, "owner-1")
+
+	assert.Equal(t, raw, property.DescriptionRaw)
+	assert.Equal(t, "<h2>Casa</h2><p>Con <strong>piscina</strong></p>", property.DescriptionHTML)
+	assert.Equal(t, "Casa\n\nCon piscina", property.Description)
+
+	// Saving the property again keeps the raw description
+	property.NormalizeDescription()
+	assert.Equal(t, raw, property.DescriptionRaw)
+
+	// A new description replaces it
+	property.Description = "Casa con piscina\ny jardín"
+	property.NormalizeDescription()
+	assert.Equal(t, "Casa con piscina\ny jardín", property.DescriptionRaw)
+	assert.Equal(t, "<p>Casa con piscina<br>y jardín</p>", property.DescriptionHTML)
+	assert.Equal(t, "Casa con piscina\ny jardín", property.Description)
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Property version change types
+const (
+	PropertyChangeCreate = "create"
+	PropertyChangeEdit   = "edit"
+	PropertyChangeRevert = "revert"
+)
+
+// DefaultPropertyHistoryLimit is how many versions a history request returns by default
+const DefaultPropertyHistoryLimit = 50
+
+// propertyUnversionedFields are left out of snapshots: identifiers, counters and
+// timestamps change without edits, the slug derives from the title, and featured
+// status and assignments go through their own limit checks and audit log. Reverting
+// a version never touches them.
+var propertyUnversionedFields = map[string]bool{
+	"id":                     true,
+	"slug":                   true,
+	"view_count":             true,
+	"featured":               true,
+	"real_estate_company_id": true,
+	"owner_id":               true,
+	"agent_id":               true,
+	"agency_id":              true,
+	"created_by":             true,
+	"updated_by":             true,
+	"created_at":             true,
+	"updated_at":             true,
+	"locale":                 true,
+	"display_price":          true,
+}
+
+// PropertyFieldChange is one field changed by a property version
+type PropertyFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// PropertyVersion is a snapshot of a property after an edit, numbered from 1 per
+// property, with the fields the edit changed
+type PropertyVersion struct {
+	ID           string                `json:"id"`
+	PropertyID   string                `json:"property_id"`
+	Version      int                   `json:"version"`
+	ChangeType   string                `json:"change_type"`
+	Snapshot     json.RawMessage       `json:"snapshot"`
+	Changes      []PropertyFieldChange `json:"changes"`
+	ChangedBy    string                `json:"changed_by,omitempty"`
+	RevertedFrom *int                  `json:"reverted_from,omitempty"` // version restored by a revert
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+// NewPropertyVersion snapshots after and diffs it against before; a nil before
+// records the creation of the property. The version number is assigned when stored.
+func NewPropertyVersion(before, after *Property, changeType, changedBy string) (*PropertyVersion, error) {
+	snapshot, err := PropertySnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+	previous := map[string]interface{}{}
+	if before != nil {
+		if previous, err = PropertySnapshot(before); err != nil {
+			return nil, err
+		}
+	}
+
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode property snapshot: %w", err)
+	}
+
+	return &PropertyVersion{
+		ID:         uuid.New().String(),
+		PropertyID: after.ID,
+		ChangeType: changeType,
+		Snapshot:   encoded,
+		Changes:    DiffPropertySnapshots(previous, snapshot),
+		ChangedBy:  changedBy,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// PropertySnapshot returns the versioned fields of a property as JSON values
+func PropertySnapshot(property *Property) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(property)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode property snapshot: %w", err)
+	}
+
+	snapshot := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode property snapshot: %w", err)
+	}
+	for field := range propertyUnversionedFields {
+		delete(snapshot, field)
+	}
+	return snapshot, nil
+}
+
+// DiffPropertySnapshots lists the fields that differ between two snapshots, sorted by name
+func DiffPropertySnapshots(before, after map[string]interface{}) []PropertyFieldChange {
+	fields := make(map[string]bool, len(after))
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	changes := []PropertyFieldChange{}
+	for field := range fields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, PropertyFieldChange{Field: field, From: before[field], To: after[field]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// Restore returns a copy of current with the versioned fields of the snapshot
+func (v *PropertyVersion) Restore(current *Property) (*Property, error) {
+	restored := *current
+	// Decode into fresh slices so current's images and tags are not overwritten
+	restored.Images, restored.Tags = nil, nil
+	if err := json.Unmarshal(v.Snapshot, &restored); err != nil {
+		return nil, fmt.Errorf("failed to decode property snapshot: %w", err)
+	}
+	if restored.Images == nil {
+		restored.Images = []string{}
+	}
+	if restored.Tags == nil {
+		restored.Tags = []string{}
+	}
+
+	restored.UpdateSlug()
+	return &restored, nil
+}
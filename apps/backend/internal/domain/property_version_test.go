@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropertyVersion_DiffsVersionedFields(t *testing.T) {
+	before := NewProperty("Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	after := *before
+	after.Price = 235000
+	after.Tags = []string{"piscina"}
+	after.ViewCount = 40
+	after.Featured = true
+
+	version, err := NewPropertyVersion(before, &after, PropertyChangeEdit, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, before.ID, version.PropertyID)
+	assert.Equal(t, "admin-1", version.ChangedBy)
+	require.Len(t, version.Changes, 2)
+	assert.Equal(t, PropertyFieldChange{Field: "price", From: float64(250000), To: float64(235000)}, version.Changes[0])
+	assert.Equal(t, "tags", version.Changes[1].Field)
+	assert.NotContains(t, string(version.Snapshot), "view_count")
+
+	created, err := NewPropertyVersion(nil, before, PropertyChangeCreate, "owner-1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Changes)
+}
+
+func TestPropertyVersion_Restore(t *testing.T) {
+	original := NewProperty("Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	original.Tags = []string{"piscina"}
+	version, err := NewPropertyVersion(nil, original, PropertyChangeCreate, "owner-1")
+	require.NoError(t, err)
+
+	current := *original
+	current.Title = "Casa remodelada"
+	current.Price = 1
+	current.Tags = []string{"remodelada", "jardín"}
+	current.ViewCount = 90
+	current.UpdateSlug()
+
+	restored, err := version.Restore(&current)
+	require.NoError(t, err)
+	assert.Equal(t, "Casa en Samborondón", restored.Title)
+	assert.Equal(t, float64(250000), restored.Price)
+	assert.Equal(t, []string{"piscina"}, restored.Tags)
+	assert.Equal(t, 90, restored.ViewCount, "counters are not versioned")
+	assert.Equal(t, GenerateSlug(restored.Title, restored.ID), restored.Slug)
+	assert.Equal(t, []string{"remodelada", "jardín"}, current.Tags, "current property is not modified")
+}
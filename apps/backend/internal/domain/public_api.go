@@ -0,0 +1,136 @@
+package domain
+
+import "time"
+
+// PublicProperty is a listing as served by the public API. It carries the listing and
+// its agency, but none of the users behind it: owner, agent and editors are left out,
+// so partners cannot harvest contacts. Leads go through the listing on the site.
+type PublicProperty struct {
+	ID                string    `json:"id"`
+	Slug              string    `json:"slug"`
+	Title             string    `json:"title"`
+	Description       string    `json:"description"`
+	Price             float64   `json:"price"`
+	RentPrice         *float64  `json:"rent_price,omitempty"`
+	CommonExpenses    *float64  `json:"common_expenses,omitempty"`
+	PricePerM2        *float64  `json:"price_per_m2,omitempty"`
+	Province          string    `json:"province"`
+	City              string    `json:"city"`
+	Sector            *string   `json:"sector,omitempty"`
+	Address           *string   `json:"address,omitempty"`
+	Latitude          *float64  `json:"latitude,omitempty"`
+	Longitude         *float64  `json:"longitude,omitempty"`
+	LocationPrecision string    `json:"location_precision"`
+	Type              string    `json:"type"`
+	Status            string    `json:"status"`
+	Bedrooms          int       `json:"bedrooms"`
+	Bathrooms         float32   `json:"bathrooms"`
+	AreaM2            float64   `json:"area_m2"`
+	ParkingSpaces     int       `json:"parking_spaces"`
+	YearBuilt         *int      `json:"year_built,omitempty"`
+	Floors            *int      `json:"floors,omitempty"`
+	Furnished         bool      `json:"furnished"`
+	Garage            bool      `json:"garage"`
+	Pool              bool      `json:"pool"`
+	Garden            bool      `json:"garden"`
+	Terrace           bool      `json:"terrace"`
+	Balcony           bool      `json:"balcony"`
+	Security          bool      `json:"security"`
+	Elevator          bool      `json:"elevator"`
+	AirConditioning   bool      `json:"air_conditioning"`
+	Tags              []string  `json:"tags"`
+	Featured          bool      `json:"featured"`
+	MainImage         *string   `json:"main_image,omitempty"`
+	Images            []string  `json:"images"`
+	VideoTour         *string   `json:"video_tour,omitempty"`
+	Tour360           *string   `json:"tour_360,omitempty"`
+	AgencyID          *string   `json:"agency_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// NewPublicProperty returns the public view of a property. The street address is left
+// out unless the owner published the exact location.
+func NewPublicProperty(p *Property) PublicProperty {
+	public := PublicProperty{
+		ID:                p.ID,
+		Slug:              p.Slug,
+		Title:             p.Title,
+		Description:       p.Description,
+		Price:             p.Price,
+		RentPrice:         p.RentPrice,
+		CommonExpenses:    p.CommonExpenses,
+		PricePerM2:        p.PricePerM2,
+		Province:          p.Province,
+		City:              p.City,
+		Sector:            p.Sector,
+		Latitude:          p.Latitude,
+		Longitude:         p.Longitude,
+		LocationPrecision: p.LocationPrecision,
+		Type:              p.Type,
+		Status:            p.Status,
+		Bedrooms:          p.Bedrooms,
+		Bathrooms:         p.Bathrooms,
+		AreaM2:            p.AreaM2,
+		ParkingSpaces:     p.ParkingSpaces,
+		YearBuilt:         p.YearBuilt,
+		Floors:            p.Floors,
+		Furnished:         p.Furnished,
+		Garage:            p.Garage,
+		Pool:              p.Pool,
+		Garden:            p.Garden,
+		Terrace:           p.Terrace,
+		Balcony:           p.Balcony,
+		Security:          p.Security,
+		Elevator:          p.Elevator,
+		AirConditioning:   p.AirConditioning,
+		Tags:              p.Tags,
+		Featured:          p.Featured,
+		MainImage:         p.MainImage,
+		Images:            p.Images,
+		VideoTour:         p.VideoTour,
+		Tour360:           p.Tour360,
+		AgencyID:          p.AgencyID,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+	if p.LocationPrecision == PrecisionExact {
+		public.Address = p.Address
+	}
+	return public
+}
+
+// PublicAgency is an agency as served by the public API, without its owner, tax
+// registration, commission or direct contacts
+type PublicAgency struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	City          string            `json:"city"`
+	Province      string            `json:"province"`
+	Website       *string           `json:"website,omitempty"`
+	Description   *string           `json:"description,omitempty"`
+	LogoURL       *string           `json:"logo_url,omitempty"`
+	LogoVariants  map[string]string `json:"logo_variants,omitempty"`
+	BusinessHours map[string]string `json:"business_hours,omitempty"`
+	SocialMedia   map[string]string `json:"social_media,omitempty"`
+	Specialties   []string          `json:"specialties,omitempty"`
+	ServiceAreas  []string          `json:"service_areas,omitempty"`
+}
+
+// NewPublicAgency returns the public view of an agency
+func NewPublicAgency(a *Agency) PublicAgency {
+	return PublicAgency{
+		ID:            a.ID,
+		Name:          a.Name,
+		City:          a.City,
+		Province:      a.Province,
+		Website:       a.Website,
+		Description:   a.Description,
+		LogoURL:       a.LogoURL,
+		LogoVariants:  a.LogoVariants,
+		BusinessHours: a.BusinessHours,
+		SocialMedia:   a.SocialMedia,
+		Specialties:   a.Specialties,
+		ServiceAreas:  a.ServiceAreas,
+	}
+}
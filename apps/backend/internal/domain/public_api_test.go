@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublicProperty_LeavesOutPeople(t *testing.T) {
+	owner, agent, agency := "owner-1", "agent-1", "agency-1"
+	address := "Av. Samborondón km 2.5"
+	property := &Property{
+		ID: "prop-1", Title: "Casa en Samborondón", Price: 285000, Status: StatusAvailable,
+		Address: &address, LocationPrecision: PrecisionApproximate,
+		OwnerID: &owner, AgentID: &agent, AgencyID: &agency, CreatedBy: &owner, UpdatedBy: &agent,
+	}
+
+	public := NewPublicProperty(property)
+	assert.Equal(t, "prop-1", public.ID)
+	assert.Equal(t, &agency, public.AgencyID)
+	assert.Nil(t, public.Address)
+
+	encoded, err := json.Marshal(public)
+	require.NoError(t, err)
+	for _, field := range []string{"owner_id", "agent_id", "created_by", "updated_by", "address", owner, agent} {
+		assert.NotContains(t, string(encoded), field)
+	}
+
+	property.LocationPrecision = PrecisionExact
+	assert.Equal(t, &address, NewPublicProperty(property).Address)
+}
+
+func TestNewPublicAgency_LeavesOutContacts(t *testing.T) {
+	agency := &Agency{
+		ID: "agency-1", Name: "Inmobiliaria Costa", Email: "ventas@costa.ec", Phone: "0991234567",
+		RUC: "0991234567001", OwnerID: "owner-1", Commission: 3, Province: "Guayas",
+	}
+
+	encoded, err := json.Marshal(NewPublicAgency(agency))
+	require.NoError(t, err)
+	for _, value := range []string{"ventas@costa.ec", "0991234567", "owner-1", "commission"} {
+		assert.NotContains(t, string(encoded), value)
+	}
+	assert.Contains(t, string(encoded), "Inmobiliaria Costa")
+}
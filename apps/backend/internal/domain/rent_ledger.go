@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rent charge statuses. Pending charges become overdue the day after their due date.
+const (
+	RentChargePending = "pending"
+	RentChargeOverdue = "overdue"
+	RentChargePaid    = "paid"
+)
+
+// Rent payment methods: recorded by the landlord or confirmed by a payment gateway
+const (
+	RentPaymentManual  = "manual"
+	RentPaymentGateway = "gateway"
+)
+
+// Rent ledger limits
+const (
+	// MaxRentScheduleMonths bounds a schedule to a three-year lease
+	MaxRentScheduleMonths      = 36
+	MaxRentPaymentReferenceLen = 100
+)
+
+// RentCharge is the rent of a lease due for a month
+type RentCharge struct {
+	ID         string  `json:"id"`
+	DealID     string  `json:"deal_id"`
+	PropertyID string  `json:"property_id"`
+	TenantID   string  `json:"tenant_id,omitempty"`
+	Amount     float64 `json:"amount"`
+	// Period is the first day of the month the charge covers
+	Period            time.Time  `json:"period"`
+	DueDate           time.Time  `json:"due_date"`
+	Status            string     `json:"status"`
+	PaidAmount        *float64   `json:"paid_amount,omitempty"`
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
+	PaymentMethod     string     `json:"payment_method,omitempty"`
+	PaymentReference  string     `json:"payment_reference,omitempty"`
+	RecordedBy        string     `json:"recorded_by,omitempty"`
+	OverdueNotifiedAt *time.Time `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ScheduleRentCharges creates the monthly charges of a rent deal, the first one due on
+// firstDue and the next ones on the same day of the following months, or on the last
+// day of shorter months
+func ScheduleRentCharges(deal *Deal, firstDue time.Time, months int, amount float64, now time.Time) ([]RentCharge, error) {
+	if deal.Type != DealTypeRent {
+		return nil, fmt.Errorf("invalid lease: only rent deals have a rent ledger")
+	}
+	if months < 1 || months > MaxRentScheduleMonths {
+		return nil, fmt.Errorf("invalid schedule: months must be between 1 and %d", MaxRentScheduleMonths)
+	}
+	if amount == 0 {
+		amount = deal.FinalPrice
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid schedule: amount must be positive")
+	}
+	amount = math.Round(amount*100) / 100
+
+	tenantID := ""
+	if deal.ClientID != nil {
+		tenantID = *deal.ClientID
+	}
+
+	firstDue = CalendarDate(firstDue)
+	charges := make([]RentCharge, 0, months)
+	for i := 0; i < months; i++ {
+		due := addMonthsClamped(firstDue, i)
+		charges = append(charges, RentCharge{
+			ID:         uuid.New().String(),
+			DealID:     deal.ID,
+			PropertyID: deal.PropertyID,
+			TenantID:   tenantID,
+			Amount:     amount,
+			Period:     time.Date(due.Year(), due.Month(), 1, 0, 0, 0, 0, time.UTC),
+			DueDate:    due,
+			Status:     RentChargePending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+	return charges, nil
+}
+
+// addMonthsClamped adds months to a date, keeping its day or the last day of shorter months
+func addMonthsClamped(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
+// IsSettled reports whether the charge was paid
+func (c *RentCharge) IsSettled() bool {
+	return c.Status == RentChargePaid
+}
+
+// IsOverdue reports whether an unpaid charge is past its due date
+func (c *RentCharge) IsOverdue(now time.Time) bool {
+	return !c.IsSettled() && CalendarDate(now).After(c.DueDate)
+}
+
+// RecordPayment settles the charge. The amount must cover the charge in full.
+func (c *RentCharge) RecordPayment(amount float64, method, reference, recordedBy string, paidAt, now time.Time) error {
+	if c.IsSettled() {
+		return fmt.Errorf("rent charge already paid: %s", c.ID)
+	}
+	if method != RentPaymentManual && method != RentPaymentGateway {
+		return fmt.Errorf("invalid payment method: %s", method)
+	}
+	if math.Round(amount*100) < math.Round(c.Amount*100) {
+		return fmt.Errorf("invalid amount: the charge is %s", FormatMoney(c.Amount))
+	}
+	reference = strings.TrimSpace(reference)
+	if len(reference) > MaxRentPaymentReferenceLen {
+		return fmt.Errorf("invalid reference: must be at most %d characters", MaxRentPaymentReferenceLen)
+	}
+	if paidAt.IsZero() {
+		paidAt = now
+	}
+	if paidAt.After(now.Add(24 * time.Hour)) {
+		return fmt.Errorf("invalid payment date: cannot be in the future")
+	}
+
+	c.Status = RentChargePaid
+	c.PaidAmount = &amount
+	c.PaidAt = &paidAt
+	c.PaymentMethod = method
+	c.PaymentReference = reference
+	c.RecordedBy = recordedBy
+	c.UpdatedAt = now
+	return nil
+}
+
+// ReceiptNumber identifies the receipt of a paid charge, e.g. REC-202509-1a2b3c4d
+func (c *RentCharge) ReceiptNumber() string {
+	id := strings.ReplaceAll(c.ID, "-", "")
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("REC-%s-%s", c.Period.Format("200601"), id)
+}
+
+// RentReceiptText is the text of the receipt of a paid charge, laid out as a PDF by a
+// processors.PDFRenderer
+func RentReceiptText(charge *RentCharge, property *Property, issuedAt time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Recibo N.º %s\n", charge.ReceiptNumber())
+	fmt.Fprintf(&b, "Fecha de emisión: %s\n\n", issuedAt.Format(CalendarDateLayout))
+	fmt.Fprintf(&b, "Inmueble: %s\n", property.Title)
+	fmt.Fprintf(&b, "Ubicación: %s, %s\n\n", property.City, property.Province)
+	fmt.Fprintf(&b, "Periodo: %s\n", charge.Period.Format("01/2006"))
+	fmt.Fprintf(&b, "Vencimiento: %s\n", charge.DueDate.Format(CalendarDateLayout))
+	fmt.Fprintf(&b, "Valor del arriendo: %s\n", FormatMoney(charge.Amount))
+	if charge.PaidAmount != nil {
+		fmt.Fprintf(&b, "Valor pagado: %s\n", FormatMoney(*charge.PaidAmount))
+	}
+	if charge.PaidAt != nil {
+		fmt.Fprintf(&b, "Fecha de pago: %s\n", charge.PaidAt.Format(CalendarDateLayout))
+	}
+	method := "registrado por el arrendador"
+	if charge.PaymentMethod == RentPaymentGateway {
+		method = "confirmado por la pasarela de pagos"
+	}
+	fmt.Fprintf(&b, "Forma de pago: %s\n", method)
+	if charge.PaymentReference != "" {
+		fmt.Fprintf(&b, "Referencia: %s\n", charge.PaymentReference)
+	}
+	b.WriteString("\nEste recibo certifica el pago del arriendo del periodo indicado.")
+	return b.String()
+}
+
+// RentLedger is the rent ledger of a lease
+type RentLedger struct {
+	DealID      string       `json:"deal_id"`
+	PropertyID  string       `json:"property_id"`
+	Charges     []RentCharge `json:"charges"`
+	TotalDue    float64      `json:"total_due"`
+	TotalPaid   float64      `json:"total_paid"`
+	Outstanding float64      `json:"outstanding"`
+	Overdue     float64      `json:"overdue"`
+}
+
+// NewRentLedger totals the charges of a lease. Outstanding counts the charges due by
+// now; charges of future months are not owed yet.
+func NewRentLedger(dealID, propertyID string, charges []RentCharge, now time.Time) *RentLedger {
+	ledger := &RentLedger{DealID: dealID, PropertyID: propertyID, Charges: charges}
+	today := CalendarDate(now)
+	for _, charge := range charges {
+		ledger.TotalDue += charge.Amount
+		switch {
+		case charge.IsSettled():
+			if charge.PaidAmount != nil {
+				ledger.TotalPaid += *charge.PaidAmount
+			}
+		case !charge.DueDate.After(today):
+			ledger.Outstanding += charge.Amount
+			if charge.IsOverdue(now) {
+				ledger.Overdue += charge.Amount
+			}
+		}
+	}
+	return ledger
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRentCharges(t *testing.T) {
+	now := time.Date(2025, 9, 7, 10, 0, 0, 0, time.UTC)
+	tenantID := "tenant-1"
+	deal := &Deal{ID: "deal-1", PropertyID: "prop-1", Type: DealTypeRent, FinalPrice: 800, ClientID: &tenantID}
+
+	charges, err := ScheduleRentCharges(deal, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), 3, 0, now)
+	require.NoError(t, err)
+	require.Len(t, charges, 3)
+	assert.Equal(t, 800.0, charges[0].Amount, "the amount defaults to the rent of the deal")
+	assert.Equal(t, "tenant-1", charges[0].TenantID)
+	assert.Equal(t, "2026-02-28", charges[1].DueDate.Format(CalendarDateLayout), "short months are clamped")
+	assert.Equal(t, "2026-03-31", charges[2].DueDate.Format(CalendarDateLayout))
+	assert.Equal(t, "2026-02-01", charges[1].Period.Format(CalendarDateLayout))
+
+	_, err = ScheduleRentCharges(deal, now, MaxRentScheduleMonths+1, 0, now)
+	assert.ErrorContains(t, err, "invalid schedule")
+	_, err = ScheduleRentCharges(&Deal{ID: "deal-2", Type: DealTypeSale, FinalPrice: 90000}, now, 1, 0, now)
+	assert.ErrorContains(t, err, "only rent deals")
+}
+
+func TestRentCharge_RecordPayment(t *testing.T) {
+	now := time.Date(2025, 10, 8, 10, 0, 0, 0, time.UTC)
+	charge := RentCharge{ID: "1a2b3c4d-0000", Amount: 800, Period: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		DueDate: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC), Status: RentChargePending}
+
+	assert.True(t, charge.IsOverdue(now))
+	assert.False(t, charge.IsOverdue(time.Date(2025, 10, 5, 23, 0, 0, 0, time.UTC)), "due dates are overdue the next day")
+
+	assert.ErrorContains(t, charge.RecordPayment(799.99, RentPaymentManual, "", "owner-1", time.Time{}, now), "invalid amount")
+	require.NoError(t, charge.RecordPayment(800, RentPaymentManual, "Transferencia 123", "owner-1", time.Time{}, now))
+	assert.Equal(t, RentChargePaid, charge.Status)
+	assert.Equal(t, now, *charge.PaidAt)
+	assert.False(t, charge.IsOverdue(now))
+	assert.ErrorContains(t, charge.RecordPayment(800, RentPaymentManual, "", "owner-1", time.Time{}, now), "already paid")
+
+	assert.Equal(t, "REC-202510-1a2b3c4d", charge.ReceiptNumber())
+	property := &Property{Title: "Departamento", City: "Quito", Province: "Pichincha"}
+	text := RentReceiptText(&charge, property, now)
+	assert.Contains(t, text, "Valor pagado: $800.00")
+	assert.Contains(t, text, "Referencia: Transferencia 123")
+}
+
+func TestNewRentLedger(t *testing.T) {
+	now := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	paid := 800.0
+	charges := []RentCharge{
+		{Amount: 800, DueDate: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC), Status: RentChargePaid, PaidAmount: &paid},
+		{Amount: 800, DueDate: time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC), Status: RentChargeOverdue},
+		{Amount: 800, DueDate: time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC), Status: RentChargePending},
+	}
+
+	ledger := NewRentLedger("deal-1", "prop-1", charges, now)
+	assert.Equal(t, 2400.0, ledger.TotalDue)
+	assert.Equal(t, 800.0, ledger.TotalPaid)
+	assert.Equal(t, 800.0, ledger.Outstanding, "future months are not owed yet")
+	assert.Equal(t, 800.0, ledger.Overdue)
+}
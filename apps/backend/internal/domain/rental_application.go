@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rental application statuses
+const (
+	ApplicationStatusSubmitted   = "submitted"
+	ApplicationStatusUnderReview = "under_review"
+	ApplicationStatusAccepted    = "accepted"
+	ApplicationStatusRejected    = "rejected"
+	ApplicationStatusWithdrawn   = "withdrawn"
+)
+
+// Rental application limits
+const (
+	// MinIncomeToRentRatio is the monthly income, in months of rent, expected of tenants
+	MinIncomeToRentRatio    = 3.0
+	MaxTenantReferences     = 5
+	MaxApplicationDocuments = 10
+)
+
+// applicationTransitions lists the statuses each status can move to
+var applicationTransitions = map[string][]string{
+	ApplicationStatusSubmitted:   {ApplicationStatusUnderReview, ApplicationStatusAccepted, ApplicationStatusRejected, ApplicationStatusWithdrawn},
+	ApplicationStatusUnderReview: {ApplicationStatusAccepted, ApplicationStatusRejected, ApplicationStatusWithdrawn},
+}
+
+// IsValidApplicationStatus checks if a rental application status is known
+func IsValidApplicationStatus(status string) bool {
+	switch status {
+	case ApplicationStatusSubmitted, ApplicationStatusUnderReview, ApplicationStatusAccepted,
+		ApplicationStatusRejected, ApplicationStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// TenantReference is a previous landlord, employer or personal reference of an applicant
+type TenantReference struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	Phone        string `json:"phone,omitempty"`
+	Email        string `json:"email,omitempty"`
+}
+
+// ApplicationDocument is a file supporting an application, such as pay slips or an ID
+type ApplicationDocument struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StoragePath string    `json:"storage_path"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// TenantScreening summarizes an application for the landlord reviewing it
+type TenantScreening struct {
+	MonthlyRent            float64 `json:"monthly_rent"`
+	IncomeToRentRatio      float64 `json:"income_to_rent_ratio"`
+	MeetsIncomeRequirement bool    `json:"meets_income_requirement"`
+	ReferenceCount         int     `json:"reference_count"`
+	DocumentCount          int     `json:"document_count"`
+}
+
+// RentalApplication is a prospective tenant's application to rent a property
+type RentalApplication struct {
+	ID             string                `json:"id"`
+	PropertyID     string                `json:"property_id"`
+	ApplicantID    string                `json:"applicant_id"`
+	Status         string                `json:"status"`
+	MonthlyIncome  float64               `json:"monthly_income"`
+	Employer       string                `json:"employer,omitempty"`
+	Occupation     string                `json:"occupation,omitempty"`
+	Occupants      int                   `json:"occupants"`
+	HasPets        bool                  `json:"has_pets"`
+	MoveInDate     *time.Time            `json:"move_in_date,omitempty"`
+	Message        string                `json:"message,omitempty"`
+	References     []TenantReference     `json:"references"`
+	Documents      []ApplicationDocument `json:"documents"`
+	DecisionReason string                `json:"decision_reason,omitempty"`
+	ReviewedBy     *string               `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time            `json:"reviewed_at,omitempty"`
+	Screening      *TenantScreening      `json:"screening,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// NewRentalApplication creates a submitted application after validating the applicant's details
+func NewRentalApplication(propertyID, applicantID string, monthlyIncome float64, occupants int, references []TenantReference) (*RentalApplication, error) {
+	if propertyID == "" || applicantID == "" {
+		return nil, fmt.Errorf("property and applicant are required")
+	}
+	if monthlyIncome <= 0 {
+		return nil, fmt.Errorf("monthly income must be positive")
+	}
+	if occupants <= 0 {
+		occupants = 1
+	}
+	if len(references) > MaxTenantReferences {
+		return nil, fmt.Errorf("at most %d references are allowed", MaxTenantReferences)
+	}
+	for i, reference := range references {
+		if strings.TrimSpace(reference.Name) == "" {
+			return nil, fmt.Errorf("reference %d must have a name", i+1)
+		}
+		if reference.Phone == "" && reference.Email == "" {
+			return nil, fmt.Errorf("reference %d must have a phone or email", i+1)
+		}
+		if reference.Email != "" {
+			if _, err := mail.ParseAddress(reference.Email); err != nil {
+				return nil, fmt.Errorf("reference %d has an invalid email", i+1)
+			}
+		}
+	}
+	if references == nil {
+		references = []TenantReference{}
+	}
+
+	now := time.Now()
+	return &RentalApplication{
+		ID:            uuid.New().String(),
+		PropertyID:    propertyID,
+		ApplicantID:   applicantID,
+		Status:        ApplicationStatusSubmitted,
+		MonthlyIncome: monthlyIncome,
+		Occupants:     occupants,
+		References:    references,
+		Documents:     []ApplicationDocument{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// IsOpen reports whether the application still awaits a decision
+func (a *RentalApplication) IsOpen() bool {
+	return a.Status == ApplicationStatusSubmitted || a.Status == ApplicationStatusUnderReview
+}
+
+// InquiryEventData returns the inquiry.created event payload of a submitted
+// application; references stay private to the listing's managers
+func (a *RentalApplication) InquiryEventData() map[string]interface{} {
+	return map[string]interface{}{
+		"application_id": a.ID,
+		"property_id":    a.PropertyID,
+		"applicant_id":   a.ApplicantID,
+		"monthly_income": a.MonthlyIncome,
+		"occupants":      a.Occupants,
+		"move_in_date":   a.MoveInDate,
+		"message":        a.Message,
+		"created_at":     a.CreatedAt,
+	}
+}
+
+// CanTransitionTo reports whether the application can move to status
+func (a *RentalApplication) CanTransitionTo(status string) bool {
+	for _, next := range applicationTransitions[a.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the application to status, recording who made the decision
+func (a *RentalApplication) Transition(status, reason, reviewerID string, at time.Time) error {
+	if !a.CanTransitionTo(status) {
+		return fmt.Errorf("cannot change application from %s to %s", a.Status, status)
+	}
+	if status == ApplicationStatusRejected && strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("a reason is required to reject an application")
+	}
+
+	a.Status = status
+	a.DecisionReason = strings.TrimSpace(reason)
+	if status != ApplicationStatusWithdrawn {
+		a.ReviewedBy = &reviewerID
+		a.ReviewedAt = &at
+	}
+	a.UpdatedAt = at
+	return nil
+}
+
+// NewApplicationDocument creates a document of an application stored under its own prefix
+func NewApplicationDocument(applicationID, name, fileName, contentType string, size int64) ApplicationDocument {
+	id := uuid.New().String()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	}
+	return ApplicationDocument{
+		ID:          id,
+		Name:        name,
+		FileName:    fileName,
+		ContentType: normalizeContentType(contentType),
+		Size:        size,
+		StoragePath: fmt.Sprintf("applications/%s/%s%s", applicationID, id, strings.ToLower(filepath.Ext(fileName))),
+		UploadedAt:  time.Now(),
+	}
+}
+
+// Document returns the document of the application with the given ID
+func (a *RentalApplication) Document(id string) (*ApplicationDocument, bool) {
+	for i := range a.Documents {
+		if a.Documents[i].ID == id {
+			return &a.Documents[i], true
+		}
+	}
+	return nil, false
+}
+
+// ScreenApplication compares the applicant's income to the monthly rent of the property
+func ScreenApplication(application *RentalApplication, monthlyRent float64) *TenantScreening {
+	screening := &TenantScreening{
+		MonthlyRent:    monthlyRent,
+		ReferenceCount: len(application.References),
+		DocumentCount:  len(application.Documents),
+	}
+	if monthlyRent > 0 {
+		screening.IncomeToRentRatio = math.Round(application.MonthlyIncome/monthlyRent*100) / 100
+		screening.MeetsIncomeRequirement = screening.IncomeToRentRatio >= MinIncomeToRentRatio
+	}
+	return screening
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRentalApplication(t *testing.T) {
+	references := []TenantReference{{Name: "Ana Torres", Relationship: "landlord", Phone: "0991234567"}}
+
+	application, err := NewRentalApplication("prop-1", "user-1", 2400, 0, references)
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationStatusSubmitted, application.Status)
+	assert.Equal(t, 1, application.Occupants)
+	assert.True(t, application.IsOpen())
+	assert.NotNil(t, application.Documents)
+
+	_, err = NewRentalApplication("prop-1", "user-1", 0, 1, nil)
+	assert.ErrorContains(t, err, "monthly income")
+
+	_, err = NewRentalApplication("prop-1", "user-1", 2400, 1, []TenantReference{{Name: "Ana"}})
+	assert.ErrorContains(t, err, "phone or email")
+
+	_, err = NewRentalApplication("prop-1", "user-1", 2400, 1, []TenantReference{{Name: "Ana", Email: "not-an-email"}})
+	assert.ErrorContains(t, err, "invalid email")
+
+	_, err = NewRentalApplication("prop-1", "user-1", 2400, 1, make([]TenantReference, MaxTenantReferences+1))
+	assert.ErrorContains(t, err, "at most")
+}
+
+func TestRentalApplication_Transition(t *testing.T) {
+	now := time.Now()
+	application, err := NewRentalApplication("prop-1", "user-1", 2400, 2, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, application.Transition(ApplicationStatusUnderReview, "", "owner-1", now))
+	assert.Equal(t, "owner-1", *application.ReviewedBy)
+
+	assert.ErrorContains(t, application.Transition(ApplicationStatusRejected, " ", "owner-1", now), "reason is required")
+	require.NoError(t, application.Transition(ApplicationStatusRejected, "Ingresos insuficientes", "owner-1", now))
+	assert.False(t, application.IsOpen())
+
+	assert.ErrorContains(t, application.Transition(ApplicationStatusAccepted, "", "owner-1", now), "cannot change application from rejected")
+	assert.False(t, application.CanTransitionTo(ApplicationStatusSubmitted))
+}
+
+func TestScreenApplication(t *testing.T) {
+	application, err := NewRentalApplication("prop-1", "user-1", 2400, 1, nil)
+	require.NoError(t, err)
+
+	screening := ScreenApplication(application, 700)
+	assert.Equal(t, 3.43, screening.IncomeToRentRatio)
+	assert.True(t, screening.MeetsIncomeRequirement)
+
+	screening = ScreenApplication(application, 1000)
+	assert.False(t, screening.MeetsIncomeRequirement)
+
+	screening = ScreenApplication(application, 0)
+	assert.Zero(t, screening.IncomeToRentRatio)
+}
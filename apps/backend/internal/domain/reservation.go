@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation statuses
+const (
+	ReservationActive    = "active"
+	ReservationConverted = "converted" // the lead bought or rented the property
+	ReservationReleased  = "released"  // cancelled before it expired
+	ReservationExpired   = "expired"   // not converted in time, the listing is available again
+)
+
+// Reservation limits
+const (
+	DefaultReservationDays    = 7
+	MaxReservationDays        = 30
+	ReservationReminderBefore = 24 * time.Hour // agents are reminded this long before expiry
+)
+
+// ReservationLead is the prospective buyer or tenant a property is reserved for.
+// A name and an email or phone are required; UserID links a registered user.
+type ReservationLead struct {
+	Name   string  `json:"name"`
+	Email  string  `json:"email,omitempty"`
+	Phone  string  `json:"phone,omitempty"`
+	UserID *string `json:"user_id,omitempty"`
+}
+
+// PropertyReservation holds a listing as reserved for a lead. Unless it is converted
+// into a sale or rental or released, it expires at ExpiresAt and the listing becomes
+// available again.
+type PropertyReservation struct {
+	ID             string          `json:"id"`
+	PropertyID     string          `json:"property_id"`
+	AgencyID       *string         `json:"agency_id,omitempty"`
+	AgentID        *string         `json:"agent_id,omitempty"`
+	Lead           ReservationLead `json:"lead"`
+	Notes          string          `json:"notes,omitempty"`
+	Status         string          `json:"status"`
+	ExpiresAt      time.Time       `json:"expires_at"`
+	ReminderSentAt *time.Time      `json:"reminder_sent_at,omitempty"`
+	CreatedBy      string          `json:"created_by"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ResolvedAt     *time.Time      `json:"resolved_at,omitempty"`
+}
+
+// NewPropertyReservation creates an active reservation of days days from now; zero days
+// uses DefaultReservationDays
+func NewPropertyReservation(propertyID string, lead ReservationLead, days int, now time.Time) (*PropertyReservation, error) {
+	lead.Name = strings.TrimSpace(lead.Name)
+	lead.Email = strings.TrimSpace(lead.Email)
+	lead.Phone = strings.TrimSpace(lead.Phone)
+
+	if lead.Name == "" {
+		return nil, fmt.Errorf("lead name is required")
+	}
+	if lead.Email == "" && lead.Phone == "" {
+		return nil, fmt.Errorf("lead email or phone is required")
+	}
+	if lead.Email != "" {
+		if _, err := mail.ParseAddress(lead.Email); err != nil {
+			return nil, fmt.Errorf("invalid lead email: %s", lead.Email)
+		}
+	}
+	if days == 0 {
+		days = DefaultReservationDays
+	}
+	if days < 1 || days > MaxReservationDays {
+		return nil, fmt.Errorf("reservation days must be between 1 and %d", MaxReservationDays)
+	}
+
+	return &PropertyReservation{
+		ID:         uuid.New().String(),
+		PropertyID: propertyID,
+		Lead:       lead,
+		Status:     ReservationActive,
+		ExpiresAt:  now.AddDate(0, 0, days),
+		CreatedAt:  now,
+	}, nil
+}
+
+// IsActive reports whether the reservation still holds the listing
+func (r *PropertyReservation) IsActive() bool {
+	return r.Status == ReservationActive
+}
+
+// NeedsReminder reports whether the agent should be reminded that the reservation expires soon
+func (r *PropertyReservation) NeedsReminder(now time.Time) bool {
+	return r.IsActive() && r.ReminderSentAt == nil && !now.Before(r.ExpiresAt.Add(-ReservationReminderBefore))
+}
+
+// IsExpired reports whether an active reservation ran out
+func (r *PropertyReservation) IsExpired(now time.Time) bool {
+	return r.IsActive() && !now.Before(r.ExpiresAt)
+}
+
+// Resolve ends an active reservation as converted, released or expired
+func (r *PropertyReservation) Resolve(status string, at time.Time) error {
+	if !r.IsActive() {
+		return fmt.Errorf("reservation is already %s", r.Status)
+	}
+	switch status {
+	case ReservationConverted, ReservationReleased, ReservationExpired:
+	default:
+		return fmt.Errorf("invalid reservation status: %s", status)
+	}
+	r.Status = status
+	r.ResolvedAt = &at
+	return nil
+}
+
+// ReservationStatusFor returns how a reservation ends when its listing moves to a status
+func ReservationStatusFor(listingStatus string) string {
+	if listingStatus == StatusSold || listingStatus == StatusRented {
+		return ReservationConverted
+	}
+	return ReservationReleased
+}
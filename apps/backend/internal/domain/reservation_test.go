@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPropertyReservation(t *testing.T) {
+	now := time.Date(2025, 8, 30, 10, 0, 0, 0, time.UTC)
+
+	reservation, err := NewPropertyReservation("prop-1", ReservationLead{Name: " María Torres ", Phone: "0991234567"}, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, "María Torres", reservation.Lead.Name)
+	assert.Equal(t, now.AddDate(0, 0, DefaultReservationDays), reservation.ExpiresAt)
+	assert.True(t, reservation.IsActive())
+
+	_, err = NewPropertyReservation("prop-1", ReservationLead{Name: "María"}, 7, now)
+	assert.ErrorContains(t, err, "email or phone")
+	_, err = NewPropertyReservation("prop-1", ReservationLead{Name: "María", Email: "maria"}, 7, now)
+	assert.ErrorContains(t, err, "invalid lead email")
+	_, err = NewPropertyReservation("prop-1", ReservationLead{Name: "María", Phone: "0991234567"}, MaxReservationDays+1, now)
+	assert.ErrorContains(t, err, "between 1 and")
+}
+
+func TestPropertyReservation_Expiry(t *testing.T) {
+	now := time.Date(2025, 8, 30, 10, 0, 0, 0, time.UTC)
+	reservation, err := NewPropertyReservation("prop-1", ReservationLead{Name: "María", Phone: "0991234567"}, 3, now)
+	require.NoError(t, err)
+
+	assert.False(t, reservation.NeedsReminder(now))
+	assert.True(t, reservation.NeedsReminder(reservation.ExpiresAt.Add(-time.Hour)))
+	assert.False(t, reservation.IsExpired(reservation.ExpiresAt.Add(-time.Second)))
+	assert.True(t, reservation.IsExpired(reservation.ExpiresAt))
+
+	require.NoError(t, reservation.Resolve(ReservationStatusFor(StatusSold), now))
+	assert.Equal(t, ReservationConverted, reservation.Status)
+	assert.False(t, reservation.NeedsReminder(reservation.ExpiresAt))
+	assert.ErrorContains(t, reservation.Resolve(ReservationExpired, now), "already converted")
+}
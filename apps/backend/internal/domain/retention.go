@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retention targets: the kinds of records pruned once older than their retention
+const (
+	RetentionAuditLog          = "audit_log"
+	RetentionLoginEvents       = "login_events"
+	RetentionJobRuns           = "job_runs"
+	RetentionWebhookDeliveries = "webhook_deliveries"
+	RetentionHoneytokenHits    = "honeytoken_hits"
+	RetentionCSPViolations     = "csp_violations"
+	RetentionShortLinkClicks   = "short_link_clicks"
+	RetentionSearchQueries     = "search_queries"
+	RetentionPropertyDrafts    = "property_drafts"
+)
+
+// Retention categories group the targets in reports
+const (
+	RetentionCategoryAudit    = "audit"
+	RetentionCategoryLogs     = "logs"
+	RetentionCategoryViews    = "views"
+	RetentionCategorySearches = "searches"
+	RetentionCategoryDrafts   = "drafts"
+)
+
+// DefaultRetentionBatchSize is how many rows the pruner deletes per statement, so a
+// large backlog is pruned without long locks
+const DefaultRetentionBatchSize = 5000
+
+// retentionDay is the unit retention periods are configured and reported in
+const retentionDay = 24 * time.Hour
+
+// RetentionPolicy is how long the records of a target are kept; a zero Retention keeps
+// them forever
+type RetentionPolicy struct {
+	Target    string        `json:"target"`
+	Category  string        `json:"category"`
+	Retention time.Duration `json:"-"`
+}
+
+// RetentionDays returns the retention in whole days
+func (p RetentionPolicy) RetentionDays() int {
+	return int(p.Retention / retentionDay)
+}
+
+// Enabled reports whether the records of the target are pruned at all
+func (p RetentionPolicy) Enabled() bool {
+	return p.Retention > 0
+}
+
+// Cutoff returns the time records older than are pruned at now
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.Add(-p.Retention)
+}
+
+// DefaultRetentionPolicies returns the retention of every target, in pruning order.
+// Drafts keep PropertyDraftRetention, as before the retention job pruned them.
+func DefaultRetentionPolicies() []RetentionPolicy {
+	return []RetentionPolicy{
+		{Target: RetentionAuditLog, Category: RetentionCategoryAudit, Retention: 365 * retentionDay},
+		{Target: RetentionLoginEvents, Category: RetentionCategoryLogs, Retention: 180 * retentionDay},
+		{Target: RetentionJobRuns, Category: RetentionCategoryLogs, Retention: 90 * retentionDay},
+		{Target: RetentionWebhookDeliveries, Category: RetentionCategoryLogs, Retention: 30 * retentionDay},
+		{Target: RetentionHoneytokenHits, Category: RetentionCategoryLogs, Retention: 365 * retentionDay},
+		{Target: RetentionCSPViolations, Category: RetentionCategoryLogs, Retention: 90 * retentionDay},
+		{Target: RetentionShortLinkClicks, Category: RetentionCategoryViews, Retention: 180 * retentionDay},
+		{Target: RetentionSearchQueries, Category: RetentionCategorySearches, Retention: 90 * retentionDay},
+		{Target: RetentionPropertyDrafts, Category: RetentionCategoryDrafts, Retention: PropertyDraftRetention},
+	}
+}
+
+// RetentionPolicies returns the default policies with the given retention periods, in
+// days, by target; a period of 0 keeps a target forever
+func RetentionPolicies(days map[string]int) ([]RetentionPolicy, error) {
+	policies := DefaultRetentionPolicies()
+	known := make(map[string]bool, len(policies))
+	for i := range policies {
+		known[policies[i].Target] = true
+		if value, ok := days[policies[i].Target]; ok {
+			policies[i].Retention = time.Duration(value) * retentionDay
+		}
+	}
+	for target, value := range days {
+		if !known[target] {
+			return nil, fmt.Errorf("invalid retention target: %s", target)
+		}
+		if value < 0 {
+			return nil, fmt.Errorf("invalid retention for %s: must be 0 or more days", target)
+		}
+	}
+	return policies, nil
+}
+
+// RetentionTargetReport is what a pruning run found and removed for one target
+type RetentionTargetReport struct {
+	Target        string     `json:"target"`
+	Category      string     `json:"category"`
+	RetentionDays int        `json:"retention_days"`
+	Cutoff        *time.Time `json:"cutoff,omitempty"`
+	Expired       int64      `json:"expired"`
+	Deleted       int64      `json:"deleted"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// RetentionReport summarizes a pruning run. A dry run counts the expired records of
+// every target without deleting them.
+type RetentionReport struct {
+	StartedAt  time.Time               `json:"started_at"`
+	FinishedAt time.Time               `json:"finished_at"`
+	DryRun     bool                    `json:"dry_run"`
+	Expired    int64                   `json:"expired"`
+	Deleted    int64                   `json:"deleted"`
+	Targets    []RetentionTargetReport `json:"targets"`
+}
+
+// Add appends the report of a target to the run's totals
+func (r *RetentionReport) Add(target RetentionTargetReport) {
+	r.Expired += target.Expired
+	r.Deleted += target.Deleted
+	r.Targets = append(r.Targets, target)
+}
+
+// Failed returns the targets that could not be pruned
+func (r *RetentionReport) Failed() []string {
+	var failed []string
+	for _, target := range r.Targets {
+		if target.Error != "" {
+			failed = append(failed, target.Target)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicies(t *testing.T) {
+	policies, err := RetentionPolicies(map[string]int{RetentionAuditLog: 730, RetentionShortLinkClicks: 0})
+	require.NoError(t, err)
+	require.Len(t, policies, len(DefaultRetentionPolicies()))
+
+	byTarget := map[string]RetentionPolicy{}
+	for _, policy := range policies {
+		byTarget[policy.Target] = policy
+	}
+	assert.Equal(t, 730, byTarget[RetentionAuditLog].RetentionDays())
+	assert.False(t, byTarget[RetentionShortLinkClicks].Enabled(), "0 days keeps a target forever")
+	assert.Equal(t, 30, byTarget[RetentionPropertyDrafts].RetentionDays())
+
+	now := time.Date(2025, 9, 15, 3, 50, 0, 0, time.UTC)
+	assert.Equal(t, now.AddDate(0, 0, -30), byTarget[RetentionPropertyDrafts].Cutoff(now))
+
+	_, err = RetentionPolicies(map[string]int{"sessions": 10})
+	assert.Error(t, err)
+	_, err = RetentionPolicies(map[string]int{RetentionJobRuns: -1})
+	assert.Error(t, err)
+}
+
+func TestRetentionReport(t *testing.T) {
+	report := &RetentionReport{}
+	report.Add(RetentionTargetReport{Target: RetentionAuditLog, Expired: 10, Deleted: 10})
+	report.Add(RetentionTargetReport{Target: RetentionJobRuns, Expired: 5, Error: "timeout"})
+
+	assert.Equal(t, int64(15), report.Expired)
+	assert.Equal(t, int64(10), report.Deleted)
+	assert.Equal(t, []string{RetentionJobRuns}, report.Failed())
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review subject types
+const (
+	ReviewSubjectAgent  = "agent"
+	ReviewSubjectAgency = "agency"
+)
+
+// Review statuses. Reviews are published once a moderator approves them.
+const (
+	ReviewPending   = "pending"
+	ReviewPublished = "published"
+	ReviewRejected  = "rejected"
+)
+
+// Review limits
+const (
+	MinReviewRating        = 1
+	MaxReviewRating        = 5
+	MaxReviewCommentLength = 2000
+	MaxModerationNote      = 500
+)
+
+// Review is a rating with an optional comment left by a verified client
+type Review struct {
+	ID          string `json:"id"`
+	SubjectType string `json:"subject_type"`
+	SubjectID   string `json:"subject_id"`
+	ReviewerID  string `json:"reviewer_id"`
+	// ReviewerName is the public name of the reviewer, first name and last initial
+	ReviewerName   string     `json:"reviewer_name"`
+	DealID         *string    `json:"deal_id,omitempty"`        // deal that verified the reviewer
+	ApplicationID  *string    `json:"application_id,omitempty"` // rental application that verified the reviewer
+	Rating         int        `json:"rating"`
+	Comment        string     `json:"comment"`
+	Status         string     `json:"status"`
+	ModerationNote string     `json:"moderation_note,omitempty"`
+	ModeratedBy    *string    `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ReviewSummary aggregates the published reviews of a subject
+type ReviewSummary struct {
+	Count         int         `json:"count"`
+	AverageRating float64     `json:"average_rating"`
+	Distribution  map[int]int `json:"distribution"` // reviews per rating, 1 to 5
+}
+
+// ReviewFilter selects reviews
+type ReviewFilter struct {
+	SubjectType string
+	SubjectID   string
+	Status      string
+	Limit       int
+	Offset      int
+}
+
+// NewReview creates a review awaiting moderation
+func NewReview(subjectType, subjectID, reviewerID string, rating int, comment string) (*Review, error) {
+	now := time.Now()
+	review := &Review{
+		ID:          uuid.New().String(),
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		ReviewerID:  reviewerID,
+		Status:      ReviewPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := review.Edit(rating, comment); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Edit changes the rating and comment, sending the review back to moderation
+func (r *Review) Edit(rating int, comment string) error {
+	if rating < MinReviewRating || rating > MaxReviewRating {
+		return fmt.Errorf("invalid rating: must be between %d and %d", MinReviewRating, MaxReviewRating)
+	}
+	comment = strings.TrimSpace(comment)
+	if len([]rune(comment)) > MaxReviewCommentLength {
+		return fmt.Errorf("invalid comment: at most %d characters", MaxReviewCommentLength)
+	}
+
+	r.Rating = rating
+	r.Comment = comment
+	r.Status = ReviewPending
+	r.ModerationNote = ""
+	r.ModeratedBy = nil
+	r.ModeratedAt = nil
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Moderate publishes or rejects the review
+func (r *Review) Moderate(approve bool, note, moderatorID string, at time.Time) error {
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > MaxModerationNote {
+		return fmt.Errorf("invalid moderation note: at most %d characters", MaxModerationNote)
+	}
+
+	r.Status = ReviewRejected
+	if approve {
+		r.Status = ReviewPublished
+	}
+	r.ModerationNote = note
+	r.ModeratedBy = &moderatorID
+	r.ModeratedAt = &at
+	r.UpdatedAt = at
+	return nil
+}
+
+// IsValidReviewStatus verifies if a status is a review status
+func IsValidReviewStatus(status string) bool {
+	return status == ReviewPending || status == ReviewPublished || status == ReviewRejected
+}
+
+// PublicReviewerName returns a first name and last initial, e.g. "María P."
+func PublicReviewerName(firstName, lastName string) string {
+	name := strings.TrimSpace(firstName)
+	if last := []rune(strings.TrimSpace(lastName)); len(last) > 0 {
+		name += " " + string(last[0]) + "."
+	}
+	return strings.TrimSpace(name)
+}
+
+// AgentProfile is the public page of an agent
+type AgentProfile struct {
+	AgentID        string              `json:"agent_id"`
+	FirstName      string              `json:"first_name"`
+	LastName       string              `json:"last_name"`
+	Bio            *string             `json:"bio,omitempty"`
+	AvatarURL      *string             `json:"avatar_url,omitempty"`
+	Agency         *AgentProfileAgency `json:"agency,omitempty"`
+	ActiveListings int                 `json:"active_listings"`
+	Listings       []Property          `json:"listings"`
+	Stats          AgentProfileStats   `json:"stats"`
+	Reviews        ReviewSummary       `json:"reviews"`
+	RecentReviews  []Review            `json:"recent_reviews"`
+}
+
+// AgentProfileAgency is the agency shown on an agent profile
+type AgentProfileAgency struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	LogoURL *string `json:"logo_url,omitempty"`
+}
+
+// AgentProfileStats summarizes the track record of an agent
+type AgentProfileStats struct {
+	DealsClosed int       `json:"deals_closed"`
+	Sales       int       `json:"sales"`
+	Rentals     int       `json:"rentals"`
+	MemberSince time.Time `json:"member_since"`
+}
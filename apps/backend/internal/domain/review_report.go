@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review report reasons
+const (
+	ReviewReportSpam      = "spam"
+	ReviewReportOffensive = "offensive"
+	ReviewReportFake      = "fake"
+	ReviewReportPersonal  = "personal_information"
+	ReviewReportOther     = "other"
+)
+
+// Review report statuses. Upheld reports reject the review; dismissed reports leave it published.
+const (
+	ReviewReportOpen      = "open"
+	ReviewReportUpheld    = "upheld"
+	ReviewReportDismissed = "dismissed"
+)
+
+// Review report limits
+const (
+	MaxReviewReportDetails = 1000
+	// ReviewReportThreshold is the number of open reports that sends a published review
+	// back to moderation until an administrator resolves them
+	ReviewReportThreshold = 3
+)
+
+// Reputation score parameters. The score is a Bayesian average of the published
+// ratings: every subject starts with ReputationPriorWeight virtual reviews of
+// ReputationPriorRating, so a few reviews cannot produce an extreme score.
+const (
+	ReputationPriorRating = 3.0
+	ReputationPriorWeight = 5
+)
+
+// ReviewReport is an abuse report on a published review
+type ReviewReport struct {
+	ID         string     `json:"id"`
+	ReviewID   string     `json:"review_id"`
+	ReporterID string     `json:"reporter_id"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details,omitempty"`
+	Status     string     `json:"status"`
+	ResolvedBy *string    `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewReviewReport creates an open report. The other reason requires details.
+func NewReviewReport(reviewID, reporterID, reason, details string) (*ReviewReport, error) {
+	if !IsValidReviewReportReason(reason) {
+		return nil, fmt.Errorf("invalid report reason: %s", reason)
+	}
+	details = strings.TrimSpace(details)
+	if len([]rune(details)) > MaxReviewReportDetails {
+		return nil, fmt.Errorf("invalid report details: at most %d characters", MaxReviewReportDetails)
+	}
+	if reason == ReviewReportOther && details == "" {
+		return nil, fmt.Errorf("invalid report details: required when the reason is %s", ReviewReportOther)
+	}
+
+	return &ReviewReport{
+		ID:         uuid.New().String(),
+		ReviewID:   reviewID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Details:    details,
+		Status:     ReviewReportOpen,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// IsValidReviewReportReason verifies if a reason is a review report reason
+func IsValidReviewReportReason(reason string) bool {
+	switch reason {
+	case ReviewReportSpam, ReviewReportOffensive, ReviewReportFake, ReviewReportPersonal, ReviewReportOther:
+		return true
+	}
+	return false
+}
+
+// IsValidReviewReportStatus verifies if a status is a review report status
+func IsValidReviewReportStatus(status string) bool {
+	return status == ReviewReportOpen || status == ReviewReportUpheld || status == ReviewReportDismissed
+}
+
+// Reputation is the aggregate score of the published reviews of a subject
+type Reputation struct {
+	SubjectType   string    `json:"subject_type"`
+	SubjectID     string    `json:"subject_id"`
+	ReviewCount   int       `json:"review_count"`
+	AverageRating float64   `json:"average_rating"`
+	Score         float64   `json:"score"` // 0 to 100
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewReputation computes the reputation of a subject from its review summary
+func NewReputation(subjectType, subjectID string, summary ReviewSummary, at time.Time) *Reputation {
+	return &Reputation{
+		SubjectType:   subjectType,
+		SubjectID:     subjectID,
+		ReviewCount:   summary.Count,
+		AverageRating: math.Round(summary.AverageRating*100) / 100,
+		Score:         ReputationScore(summary.Count, summary.AverageRating),
+		UpdatedAt:     at,
+	}
+}
+
+// ReputationScore maps the Bayesian average of count ratings averaging average to
+// 0-100, rounded to one decimal. Subjects without reviews score the prior.
+func ReputationScore(count int, average float64) float64 {
+	weighted := (ReputationPriorRating*ReputationPriorWeight + average*float64(count)) / float64(ReputationPriorWeight+count)
+	score := (weighted - MinReviewRating) / (MaxReviewRating - MinReviewRating) * 100
+	return math.Round(score*10) / 10
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReviewReport(t *testing.T) {
+	report, err := NewReviewReport("rev-1", "user-1", ReviewReportSpam, "  Publicidad  ")
+	require.NoError(t, err)
+	assert.Equal(t, ReviewReportOpen, report.Status)
+	assert.Equal(t, "Publicidad", report.Details)
+
+	_, err = NewReviewReport("rev-1", "user-1", "boring", "")
+	assert.ErrorContains(t, err, "invalid report reason")
+	_, err = NewReviewReport("rev-1", "user-1", ReviewReportOther, " ")
+	assert.ErrorContains(t, err, "invalid report details", "the other reason needs details")
+}
+
+func TestReputationScore(t *testing.T) {
+	assert.Equal(t, 50.0, ReputationScore(0, 0), "subjects without reviews score the prior")
+	assert.Equal(t, 58.3, ReputationScore(1, 5), "a single review moves the score a little")
+	assert.Equal(t, 83.3, ReputationScore(10, 5))
+	assert.Equal(t, 16.7, ReputationScore(10, 1))
+
+	reputation := NewReputation(ReviewSubjectAgency, "agency-1", ReviewSummary{Count: 3, AverageRating: 13.0 / 3}, time.Now())
+	assert.Equal(t, 4.33, reputation.AverageRating)
+	assert.Equal(t, 3, reputation.ReviewCount)
+	assert.Equal(t, 62.5, reputation.Score)
+}
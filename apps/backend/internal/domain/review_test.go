@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReview_Lifecycle(t *testing.T) {
+	review, err := NewReview(ReviewSubjectAgent, "agent-1", "client-1", 5, "  Muy profesional  ")
+	require.NoError(t, err)
+	assert.Equal(t, ReviewPending, review.Status)
+	assert.Equal(t, "Muy profesional", review.Comment)
+
+	_, err = NewReview(ReviewSubjectAgent, "agent-1", "client-1", 6, "")
+	assert.ErrorContains(t, err, "invalid rating")
+	_, err = NewReview(ReviewSubjectAgent, "agent-1", "client-1", 4, strings.Repeat("a", MaxReviewCommentLength+1))
+	assert.ErrorContains(t, err, "invalid comment")
+
+	now := time.Now()
+	require.NoError(t, review.Moderate(true, "", "admin-1", now))
+	assert.Equal(t, ReviewPublished, review.Status)
+	assert.Equal(t, "admin-1", *review.ModeratedBy)
+
+	require.NoError(t, review.Edit(2, "Cambió de opinión"))
+	assert.Equal(t, ReviewPending, review.Status, "edited reviews are moderated again")
+	assert.Nil(t, review.ModeratedBy)
+}
+
+func TestPublicReviewerName(t *testing.T) {
+	assert.Equal(t, "María P.", PublicReviewerName("María", "Pérez"))
+	assert.Equal(t, "Álvaro Ñ.", PublicReviewerName(" Álvaro ", "Ñusta"))
+	assert.Equal(t, "Juan", PublicReviewerName("Juan", ""))
+}
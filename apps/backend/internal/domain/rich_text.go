@@ -0,0 +1,454 @@
+package domain
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxRichTextDepth bounds how deeply allowed tags nest; deeper tags are dropped and
+// their text kept
+const maxRichTextDepth = 16
+
+// richTextTags maps the tags kept by SanitizeRichText to the tag they are written as.
+// Headings start at h2, the title of the page being its h1, and div, which editors paste
+// for every line, becomes a paragraph.
+var richTextTags = map[string]string{
+	"p": "p", "div": "p", "br": "br", "hr": "hr",
+	"strong": "strong", "b": "strong", "em": "em", "i": "em", "u": "u",
+	"ul": "ul", "ol": "ol", "li": "li", "blockquote": "blockquote",
+	"h1": "h2", "h2": "h2", "h3": "h3", "h4": "h4", "h5": "h4", "h6": "h4",
+	"a": "a",
+}
+
+// richTextVoidTags have no content nor end tag
+var richTextVoidTags = map[string]bool{"br": true, "hr": true}
+
+// richTextBlockTags end an open paragraph, as browsers do, and are separated by blank
+// lines in plain text
+var richTextBlockTags = map[string]bool{
+	"p": true, "ul": true, "ol": true, "blockquote": true, "hr": true, "h2": true, "h3": true, "h4": true,
+}
+
+// richTextDroppedTags are dropped with everything in them
+var richTextDroppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "textarea": true, "select": true, "svg": true, "math": true, "head": true,
+	"title": true, "xmp": true, "noembed": true, "noframes": true, "frameset": true,
+}
+
+// richTextRawTags hold raw text up to their end tag rather than markup
+var richTextRawTags = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true, "xmp": true, "iframe": true,
+	"noembed": true, "noframes": true, "noscript": true,
+}
+
+// richTextLinkSchemes are the schemes links may use
+var richTextLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+
+// richTextMarkup matches input that holds tags or comments rather than plain text
+var richTextMarkup = regexp.MustCompile(`<(?:/?[a-zA-Z][a-zA-Z0-9]*[\s/>]|!--)`)
+
+// SanitizeRichText turns a description agents wrote or pasted into HTML safe to render:
+// only the tags of richTextTags are kept, without attributes but the href of links to
+// web, mailto and tel addresses; scripts, styles and embedded content are dropped with
+// their content, and every tag is closed. Input without markup is plain text, whose
+// blank lines separate paragraphs.
+func SanitizeRichText(input string) string {
+	input = strings.ReplaceAll(strings.ReplaceAll(input, "\r\n", "\n"), "\r", "\n")
+	if strings.TrimSpace(input) == "" {
+		return ""
+	}
+	if !richTextMarkup.MatchString(input) {
+		return plainTextToRichText(input)
+	}
+
+	var out strings.Builder
+	var open []string
+	closeTo := func(i int) {
+		for len(open) > i {
+			out.WriteString("</" + open[len(open)-1] + ">")
+			open = open[:len(open)-1]
+		}
+	}
+	indexOf := func(tag string) int {
+		for i := len(open) - 1; i >= 0; i-- {
+			if open[i] == tag {
+				return i
+			}
+		}
+		return -1
+	}
+
+	tokens := tokenizeHTML(input)
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch token.kind {
+		case htmlText:
+			out.WriteString(html.EscapeString(token.data))
+
+		case htmlStartTag:
+			if richTextDroppedTags[token.data] {
+				i = skipElement(tokens, i)
+				continue
+			}
+			tag, ok := richTextTags[token.data]
+			if !ok {
+				continue
+			}
+			if richTextBlockTags[tag] {
+				if p := indexOf("p"); p >= 0 {
+					closeTo(p)
+				}
+			}
+			if tag == "li" {
+				if li := indexOf("li"); li >= 0 && indexOf("ul") < li && indexOf("ol") < li {
+					closeTo(li)
+				}
+			}
+			if richTextVoidTags[tag] {
+				out.WriteString("<" + tag + ">")
+				continue
+			}
+			if len(open) >= maxRichTextDepth {
+				continue
+			}
+			if tag == "a" {
+				href, ok := richTextLink(token.href)
+				if !ok || indexOf("a") >= 0 {
+					continue
+				}
+				out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">`)
+			} else {
+				out.WriteString("<" + tag + ">")
+			}
+			open = append(open, tag)
+
+		case htmlEndTag:
+			tag, ok := richTextTags[token.data]
+			if !ok {
+				continue
+			}
+			if i := indexOf(tag); i >= 0 {
+				closeTo(i)
+			}
+		}
+	}
+	closeTo(0)
+	return strings.TrimSpace(out.String())
+}
+
+// RichTextPlainText returns the text of HTML sanitized by SanitizeRichText, with blank
+// lines between blocks, a line per list item and line breaks kept
+func RichTextPlainText(sanitized string) string {
+	var out []byte
+	breaks, bullet := 0, false // line breaks and list bullet owed before the next text
+	for _, token := range tokenizeHTML(sanitized) {
+		switch token.kind {
+		case htmlText:
+			text := strings.Join(strings.FieldsFunc(token.data, isHTMLSpaceRune), " ")
+			if startsWithHTMLSpace(token.data) {
+				text = " " + text
+			}
+			if endsWithHTMLSpace(token.data) && strings.TrimSpace(token.data) != "" {
+				text += " "
+			}
+			if len(out) == 0 || breaks > 0 || bullet || out[len(out)-1] == '\n' || out[len(out)-1] == ' ' {
+				text = strings.TrimLeft(text, " ")
+			}
+			if text == "" {
+				continue
+			}
+			if breaks > 0 {
+				out = append(bytesTrimRightSpace(out), strings.Repeat("\n", breaks)...)
+				breaks = 0
+			}
+			if bullet {
+				out = append(out, "- "...)
+				bullet = false
+			}
+			out = append(out, text...)
+
+		case htmlStartTag, htmlEndTag:
+			switch {
+			case token.data == "br":
+				if len(out) > 0 && breaks < 2 {
+					breaks++
+				}
+			case token.data == "li":
+				if token.kind == htmlStartTag {
+					if len(out) > 0 && breaks < 1 {
+						breaks = 1
+					}
+					bullet = true
+				}
+			case richTextBlockTags[token.data]:
+				if len(out) > 0 {
+					breaks = 2
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// plainTextToRichText writes plain text as paragraphs, separated by blank lines, with
+// line breaks
+func plainTextToRichText(text string) string {
+	var out strings.Builder
+	for _, paragraph := range regexp.MustCompile(`\n[ \t]*\n\s*`).Split(strings.TrimSpace(text), -1) {
+		lines := strings.Split(paragraph, "\n")
+		for i := range lines {
+			lines[i] = html.EscapeString(strings.TrimSpace(lines[i]))
+		}
+		out.WriteString("<p>" + strings.Join(lines, "<br>") + "</p>")
+	}
+	return out.String()
+}
+
+// richTextLink returns the address of a link when it uses one of richTextLinkSchemes
+func richTextLink(href string) (string, bool) {
+	// Browsers ignore control characters and whitespace within schemes
+	href = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, href)
+	parsed, err := url.Parse(href)
+	if err != nil || !richTextLinkSchemes[strings.ToLower(parsed.Scheme)] {
+		return "", false
+	}
+	if (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host == "" {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+func startsWithHTMLSpace(s string) bool {
+	return s != "" && isHTMLSpace(s[0])
+}
+
+func endsWithHTMLSpace(s string) bool {
+	return s != "" && isHTMLSpace(s[len(s)-1])
+}
+
+func bytesTrimRightSpace(b []byte) []byte {
+	for len(b) > 0 && b[len(b)-1] == ' ' {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+// HTML token kinds
+const (
+	htmlText = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+// htmlToken is a piece of HTML: decoded text, or a tag with its lowercase name and, for
+// links, its decoded href
+type htmlToken struct {
+	kind int
+	data string
+	href string
+}
+
+// tokenizeHTML splits HTML into text and tags. Comments, doctypes and processing
+// instructions are dropped; a < that does not start a complete tag is text. The
+// content of raw text elements such as script is not parsed, only skipped.
+func tokenizeHTML(input string) []htmlToken {
+	var tokens []htmlToken
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, htmlToken{kind: htmlText, data: html.UnescapeString(text.String())})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(input); {
+		if input[i] != '<' || i+1 >= len(input) {
+			text.WriteByte(input[i])
+			i++
+			continue
+		}
+
+		next := input[i+1]
+		switch {
+		case strings.HasPrefix(input[i:], "<!--"):
+			flush()
+			end := strings.Index(input[i+4:], "-->")
+			if end < 0 {
+				return tokens
+			}
+			i += 4 + end + 3
+		case next == '!' || next == '?':
+			flush()
+			end := strings.IndexByte(input[i:], '>')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case next == '/' || isASCIILetter(next):
+			token, length, ok := parseHTMLTag(input[i:])
+			if !ok {
+				text.WriteByte('<')
+				i++
+				continue
+			}
+			flush()
+			i += length
+			if token.data == "" {
+				continue // </> and other bogus tags
+			}
+			tokens = append(tokens, token)
+			if token.kind == htmlStartTag && richTextRawTags[token.data] {
+				// Raw text runs to the end tag, which is left for the sanitizer
+				end := indexFold(input[i:], "</"+token.data)
+				if end < 0 {
+					return tokens
+				}
+				i += end
+			}
+		default:
+			text.WriteByte('<')
+			i++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// parseHTMLTag parses the tag at the start of s, returning its length. A token without
+// name is a tag to ignore; ok is false when s does not hold a complete tag.
+func parseHTMLTag(s string) (htmlToken, int, bool) {
+	i := 1
+	kind := htmlStartTag
+	if s[i] == '/' {
+		kind = htmlEndTag
+		i++
+	}
+	start := i
+	for i < len(s) && (isASCIILetter(s[i]) || (i > start && (s[i] >= '0' && s[i] <= '9' || s[i] == '-' || s[i] == ':'))) {
+		i++
+	}
+	token := htmlToken{kind: kind, data: strings.ToLower(s[start:i])}
+	if token.data == "" {
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return htmlToken{}, 0, false
+		}
+		return htmlToken{}, end + 1, true
+	}
+
+	// Attributes
+	for i < len(s) {
+		for i < len(s) && (isHTMLSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '>' {
+			return token, i + 1, true
+		}
+		nameStart := i
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) || s[i] != '=' {
+			continue
+		}
+		i++
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		var value string
+		if quote := s[i]; quote == '"' || quote == '\'' {
+			end := strings.IndexByte(s[i+1:], quote)
+			if end < 0 {
+				return htmlToken{}, 0, false
+			}
+			value = s[i+1 : i+1+end]
+			i += end + 2
+		} else {
+			valueStart := i
+			for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+				i++
+			}
+			value = s[valueStart:i]
+		}
+		if name == "href" && token.href == "" {
+			token.href = html.UnescapeString(value)
+		}
+	}
+	return htmlToken{}, 0, false
+}
+
+// skipElement returns the index of the end tag of the element started at tokens[start],
+// or the last token when it is not closed
+func skipElement(tokens []htmlToken, start int) int {
+	name := tokens[start].data
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].data != name {
+			continue
+		}
+		switch tokens[i].kind {
+		case htmlStartTag:
+			depth++
+		case htmlEndTag:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// indexFold is strings.Index ignoring ASCII case
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		match := true
+		for j := 0; j < len(substr); j++ {
+			if lowerASCII(s[i+j]) != lowerASCII(substr[j]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isHTMLSpaceRune(r rune) bool {
+	return r < 0x80 && isHTMLSpace(byte(r))
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeRichText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "  \n ", ""},
+		{"plain text paragraphs", "Casa amplia\ncon jardín\n\n\nCerca del parque & a < 5 min",
+			"<p>Casa amplia<br>con jardín</p><p>Cerca del parque &amp; a &lt; 5 min</p>"},
+		{"allowed tags", "<p>Casa <b>amplia</b> y <i>luminosa</i></p><ul><li>3 dormitorios<li>2 baños</ul>",
+			"<p>Casa <strong>amplia</strong> y <em>luminosa</em></p><ul><li>3 dormitorios</li><li>2 baños</li></ul>"},
+		{"attributes dropped", `<p class="x" style="color:red" onclick="alert(1)">Hola</p>`, "<p>Hola</p>"},
+		{"headings", "<h1>Título</h1><h6>Nota</h6>", "<h2>Título</h2><h4>Nota</h4>"},
+		{"editor divs", "<div>Línea 1</div><div>Línea 2<br></div>", "<p>Línea 1</p><p>Línea 2<br></p>"},
+		{"script dropped with content", "<p>Hola<script>alert('<p>x</p>')</script></p>", "<p>Hola</p>"},
+		{"uppercase script", "<SCRIPT>alert(1)</ScRiPt>ok", "ok"},
+		{"style dropped", "<style>p{color:red}</style><p>ok</p>", "<p>ok</p>"},
+		{"svg dropped", `<svg><g><script>alert(1)</script></g></svg><p>ok</p>`, "<p>ok</p>"},
+		{"unknown tags unwrapped", `<span><font color="red">rojo</font></span>`, "rojo"},
+		{"image dropped", `<img src=x onerror=alert(1)>foto`, "foto"},
+		{"comments dropped", "<p>a<!-- <script>alert(1)</script> -->b</p>", "<p>ab</p>"},
+		{"unclosed tags closed", "<p><strong>hola", "<p><strong>hola</strong></p>"},
+		{"stray end tags", "</p></strong>hola</em>", "hola"},
+		{"block ends paragraph", "<p>a<ul><li>b</li></ul>", "<p>a</p><ul><li>b</li></ul>"},
+		{"incomplete tag is text", `<p>a <b onclick="x</p>`, `<p>a &lt;b onclick=&#34;x</p>`},
+		{"entities kept escaped", "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp;</p>",
+			"<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp;</p>"},
+		{"safe link", `<a href="https://realty.ec/casa?a=1&amp;b=2" target="_blank">ver</a>`,
+			`<a href="https://realty.ec/casa?a=1&amp;b=2" rel="nofollow noopener noreferrer">ver</a>`},
+		{"mailto link", `<a href="mailto:ventas@realty.ec">correo</a>`,
+			`<a href="mailto:ventas@realty.ec" rel="nofollow noopener noreferrer">correo</a>`},
+		{"javascript link", `<a href="javascript:alert(1)">x</a>`, "x"},
+		{"obfuscated javascript link", `<a href="java&#x09;script:alert(1)">x</a>`, "x"},
+		{"entity javascript link", `<a href="&#106;avascript:alert(1)">x</a>`, "x"},
+		{"data link", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, "x"},
+		{"relative link", `<a href="/propiedades">x</a>`, "x"},
+		{"nested links", `<a href="https://a.ec">a<a href="https://b.ec">b</a></a>`,
+			`<a href="https://a.ec" rel="nofollow noopener noreferrer">ab</a>`},
+		{"quote in href", `<a href='https://a.ec/"onmouseover="x'>a</a>`,
+			`<a href="https://a.ec/%22onmouseover=%22x" rel="nofollow noopener noreferrer">a</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeRichText(tt.input))
+		})
+	}
+}
+
+func TestSanitizeRichText_Idempotent(t *testing.T) {
+	inputs := []string{
+		"Casa amplia\n\ncon jardín & piscina",
+		`<h1>Casa</h1><div>Con <b>piscina</b></div><ul><li>Uno<li>Dos</ul><a href="https://realty.ec">ver</a>`,
+		"<p>a <b onclick=\"x</p>",
+	}
+	for _, input := range inputs {
+		sanitized := SanitizeRichText(input)
+		assert.Equal(t, sanitized, SanitizeRichText(sanitized), input)
+	}
+}
+
+func TestSanitizeRichText_DepthLimited(t *testing.T) {
+	input := strings.Repeat("<blockquote>", 100) + "hondo" + strings.Repeat("</blockquote>", 100)
+
+	sanitized := SanitizeRichText(input)
+	assert.Equal(t, maxRichTextDepth, strings.Count(sanitized, "<blockquote>"))
+	assert.Equal(t, maxRichTextDepth, strings.Count(sanitized, "</blockquote>"))
+	assert.Contains(t, sanitized, "hondo")
+}
+
+func TestRichTextPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"paragraphs", "<p>Casa amplia<br>con jardín</p><p>Cerca &amp; bien</p>", "Casa amplia\ncon jardín\n\nCerca & bien"},
+		{"inline tags", "<p>Casa <strong>amplia</strong> y <em>luminosa</em></p>", "Casa amplia y luminosa"},
+		{"lists", "<p>Incluye:</p><ul><li>3 dormitorios</li><li>2 baños</li></ul><p>Fin</p>",
+			"Incluye:\n\n- 3 dormitorios\n- 2 baños\n\nFin"},
+		{"whitespace collapsed", "<p>  a \n  b  </p>\n<p> c</p>", "a b\n\nc"},
+		{"links", `<p>Ver <a href="https://realty.ec" rel="nofollow noopener noreferrer">aquí</a>.</p>`, "Ver aquí."},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RichTextPlainText(tt.input))
+		})
+	}
+}
+
+func TestRichTextPlainText_RoundTrip(t *testing.T) {
+	// Plain text descriptions keep their text through sanitization
+	plain := "Casa amplia\ncon jardín\n\nCerca del parque & a < 5 min"
+	assert.Equal(t, plain, RichTextPlainText(SanitizeRichText(plain)))
+}
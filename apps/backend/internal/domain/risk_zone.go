@@ -0,0 +1,369 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Natural hazards of risk zones
+const (
+	HazardFlood     = "flood"
+	HazardLandslide = "landslide"
+	HazardTsunami   = "tsunami"
+	HazardVolcanic  = "volcanic"
+)
+
+// Risk levels, from least to most exposed
+const (
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+// Risk zone import limits
+const (
+	MaxRiskLayerSize     = 20 << 20
+	MaxRiskZonesPerLayer = 5000
+)
+
+// hazards lists the hazards in the order risk flags are shown
+var hazards = []string{HazardFlood, HazardLandslide, HazardTsunami, HazardVolcanic}
+
+// riskLevelRanks orders the risk levels
+var riskLevelRanks = map[string]int{RiskLevelLow: 1, RiskLevelMedium: 2, RiskLevelHigh: 3}
+
+// riskLevelAliases maps the level values found in hazard layers, including the Spanish
+// ones of SNGRE and municipal maps, to risk levels
+var riskLevelAliases = map[string]string{
+	"low": RiskLevelLow, "bajo": RiskLevelLow, "baja": RiskLevelLow,
+	"medium": RiskLevelMedium, "moderate": RiskLevelMedium, "medio": RiskLevelMedium, "media": RiskLevelMedium, "moderado": RiskLevelMedium,
+	"high": RiskLevelHigh, "very high": RiskLevelHigh, "alto": RiskLevelHigh, "alta": RiskLevelHigh, "muy alto": RiskLevelHigh, "muy alta": RiskLevelHigh,
+}
+
+// IsValidHazard reports whether a hazard is known
+func IsValidHazard(hazard string) bool {
+	for _, h := range hazards {
+		if h == hazard {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeRiskLevel maps a level value of a hazard layer to a risk level, empty when
+// it is not recognized
+func NormalizeRiskLevel(level string) string {
+	return riskLevelAliases[strings.ToLower(strings.TrimSpace(level))]
+}
+
+// GeoBounds is the bounding box of a geometry
+type GeoBounds struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// Contains reports whether a point is inside the box
+func (b GeoBounds) Contains(point GeoPoint) bool {
+	return point.Lat >= b.MinLat && point.Lat <= b.MaxLat && point.Lng >= b.MinLng && point.Lng <= b.MaxLng
+}
+
+// Union returns the box covering both boxes
+func (b GeoBounds) Union(other GeoBounds) GeoBounds {
+	return GeoBounds{
+		MinLat: min(b.MinLat, other.MinLat),
+		MinLng: min(b.MinLng, other.MinLng),
+		MaxLat: max(b.MaxLat, other.MaxLat),
+		MaxLng: max(b.MaxLng, other.MaxLng),
+	}
+}
+
+// RiskPolygon is a polygon of a risk zone: its outer ring followed by its holes
+type RiskPolygon [][]GeoPoint
+
+// Contains reports whether a point is inside the outer ring and outside every hole
+func (p RiskPolygon) Contains(point GeoPoint) bool {
+	if len(p) == 0 || !ringContains(p[0], point) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, point) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains reports whether a point is inside a closed ring by ray casting
+func ringContains(ring []GeoPoint, point GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) &&
+			point.Lng < (b.Lng-a.Lng)*(point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// RiskZone is an area exposed to a natural hazard, imported from a hazard layer
+type RiskZone struct {
+	ID         string        `json:"id"`
+	Layer      string        `json:"layer"`
+	Hazard     string        `json:"hazard"`
+	Level      string        `json:"level"`
+	Name       string        `json:"name"`
+	Source     string        `json:"source"`
+	Polygons   []RiskPolygon `json:"polygons"`
+	Bounds     GeoBounds     `json:"bounds"`
+	ImportedBy string        `json:"imported_by,omitempty"`
+	ImportedAt time.Time     `json:"imported_at"`
+}
+
+// Contains reports whether a point is inside the zone
+func (z *RiskZone) Contains(point GeoPoint) bool {
+	if !z.Bounds.Contains(point) {
+		return false
+	}
+	for _, polygon := range z.Polygons {
+		if polygon.Contains(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// RiskLayer describes a hazard layer to import. Zones take their level from the
+// "level" or "nivel" property of each feature, falling back to DefaultLevel, and their
+// name from "name" or "nombre".
+type RiskLayer struct {
+	Layer        string `json:"layer"`
+	Hazard       string `json:"hazard"`
+	DefaultLevel string `json:"default_level"`
+	Source       string `json:"source"`
+}
+
+// Normalize trims the layer description
+func (l *RiskLayer) Normalize() {
+	l.Layer = strings.ToLower(strings.TrimSpace(l.Layer))
+	l.Hazard = strings.ToLower(strings.TrimSpace(l.Hazard))
+	l.DefaultLevel = NormalizeRiskLevel(l.DefaultLevel)
+	l.Source = strings.TrimSpace(l.Source)
+}
+
+// Validate checks the layer description
+func (l *RiskLayer) Validate() error {
+	if !IsValidSlug(l.Layer) || len(l.Layer) > 100 {
+		return fmt.Errorf("invalid layer: use lowercase letters, numbers and hyphens, e.g. sngre-inundaciones-guayas")
+	}
+	if !IsValidHazard(l.Hazard) {
+		return fmt.Errorf("invalid hazard: must be flood, landslide, tsunami or volcanic")
+	}
+	if len(l.Source) > 200 {
+		return fmt.Errorf("invalid source: must be at most 200 characters")
+	}
+	return nil
+}
+
+// RiskLayerSummary describes an imported hazard layer
+type RiskLayerSummary struct {
+	Layer      string    `json:"layer"`
+	Hazard     string    `json:"hazard"`
+	Source     string    `json:"source"`
+	Zones      int       `json:"zones"`
+	Bounds     GeoBounds `json:"bounds"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// geoJSONFeatureCollection is the subset of GeoJSON read from hazard layers
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Geometry   *geoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ParseRiskLayer reads the Polygon and MultiPolygon features of a GeoJSON
+// FeatureCollection as zones of a layer. Other geometries are skipped.
+func ParseRiskLayer(data []byte, layer RiskLayer, importedBy string, now time.Time) ([]RiskZone, error) {
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("invalid GeoJSON: expected a FeatureCollection")
+	}
+
+	zones := []RiskZone{}
+	for i, feature := range collection.Features {
+		if feature.Geometry == nil {
+			continue
+		}
+		polygons, err := parseGeoJSONPolygons(feature.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GeoJSON: feature %d: %v", i, err)
+		}
+		if len(polygons) == 0 {
+			continue
+		}
+
+		level := NormalizeRiskLevel(featureString(feature.Properties, "level", "nivel", "riesgo"))
+		if level == "" {
+			level = layer.DefaultLevel
+		}
+		if level == "" {
+			return nil, fmt.Errorf("invalid GeoJSON: feature %d has no risk level and the layer has no default level", i)
+		}
+
+		zones = append(zones, RiskZone{
+			ID:         uuid.New().String(),
+			Layer:      layer.Layer,
+			Hazard:     layer.Hazard,
+			Level:      level,
+			Name:       featureString(feature.Properties, "name", "nombre"),
+			Source:     layer.Source,
+			Polygons:   polygons,
+			Bounds:     polygonBounds(polygons),
+			ImportedBy: importedBy,
+			ImportedAt: now,
+		})
+		if len(zones) > MaxRiskZonesPerLayer {
+			return nil, fmt.Errorf("invalid GeoJSON: a layer can have at most %d zones", MaxRiskZonesPerLayer)
+		}
+	}
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("invalid GeoJSON: no polygon features")
+	}
+	return zones, nil
+}
+
+// parseGeoJSONPolygons reads the polygons of a Polygon or MultiPolygon geometry
+func parseGeoJSONPolygons(geometry *geoJSONGeometry) ([]RiskPolygon, error) {
+	var coordinates [][][][]float64
+	switch geometry.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("malformed polygon coordinates")
+		}
+		coordinates = [][][][]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("malformed multipolygon coordinates")
+		}
+	default:
+		return nil, nil
+	}
+
+	polygons := make([]RiskPolygon, 0, len(coordinates))
+	for _, rings := range coordinates {
+		polygon := make(RiskPolygon, 0, len(rings))
+		for _, positions := range rings {
+			if len(positions) < 4 {
+				return nil, fmt.Errorf("rings need at least 4 positions")
+			}
+			ring := make([]GeoPoint, len(positions))
+			for k, position := range positions {
+				if len(position) < 2 {
+					return nil, fmt.Errorf("positions need a longitude and latitude")
+				}
+				// GeoJSON positions are [longitude, latitude]
+				ring[k] = GeoPoint{Lat: position[1], Lng: position[0]}
+				if err := ring[k].Validate(); err != nil {
+					return nil, err
+				}
+			}
+			polygon = append(polygon, ring)
+		}
+		if len(polygon) > 0 {
+			polygons = append(polygons, polygon)
+		}
+	}
+	return polygons, nil
+}
+
+// polygonBounds returns the bounding box of the outer rings of polygons
+func polygonBounds(polygons []RiskPolygon) GeoBounds {
+	first := polygons[0][0][0]
+	bounds := GeoBounds{MinLat: first.Lat, MinLng: first.Lng, MaxLat: first.Lat, MaxLng: first.Lng}
+	for _, polygon := range polygons {
+		for _, point := range polygon[0] {
+			bounds = bounds.Union(GeoBounds{MinLat: point.Lat, MinLng: point.Lng, MaxLat: point.Lat, MaxLng: point.Lng})
+		}
+	}
+	return bounds
+}
+
+// featureString returns the first of keys set as a string in feature properties
+func featureString(properties map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := properties[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// PropertyRiskFlag records that a property lies in a zone exposed to a hazard
+type PropertyRiskFlag struct {
+	Hazard     string    `json:"hazard"`
+	Level      string    `json:"level"`
+	ZoneID     string    `json:"zone_id"`
+	ZoneName   string    `json:"zone_name,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	AssessedAt time.Time `json:"assessed_at"`
+}
+
+// AssessRisk returns the risk flags of a point: for each hazard, the highest level of
+// the zones containing it
+func AssessRisk(point GeoPoint, zones []RiskZone, now time.Time) []PropertyRiskFlag {
+	byHazard := map[string]PropertyRiskFlag{}
+	for i := range zones {
+		zone := &zones[i]
+		if !zone.Contains(point) {
+			continue
+		}
+		if current, ok := byHazard[zone.Hazard]; ok && riskLevelRanks[current.Level] >= riskLevelRanks[zone.Level] {
+			continue
+		}
+		byHazard[zone.Hazard] = PropertyRiskFlag{
+			Hazard:     zone.Hazard,
+			Level:      zone.Level,
+			ZoneID:     zone.ID,
+			ZoneName:   zone.Name,
+			Source:     zone.Source,
+			AssessedAt: now,
+		}
+	}
+
+	flags := make([]PropertyRiskFlag, 0, len(byHazard))
+	for _, flag := range byHazard {
+		flags = append(flags, flag)
+	}
+	SortRiskFlags(flags)
+	return flags
+}
+
+// SortRiskFlags orders risk flags by hazard
+func SortRiskFlags(flags []PropertyRiskFlag) {
+	order := map[string]int{}
+	for i, hazard := range hazards {
+		order[hazard] = i
+	}
+	sort.Slice(flags, func(i, j int) bool { return order[flags[i].Hazard] < order[flags[j].Hazard] })
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guayaquilFloodLayer has a high flood zone with a hole over a raised area and a
+// medium zone covering both
+const guayaquilFloodLayer = `{
+	"type": "FeatureCollection",
+	"features": [
+		{
+			"type": "Feature",
+			"properties": {"nombre": "Estero Salado", "nivel": "Alto"},
+			"geometry": {"type": "Polygon", "coordinates": [
+				[[-79.95, -2.25], [-79.85, -2.25], [-79.85, -2.15], [-79.95, -2.15], [-79.95, -2.25]],
+				[[-79.91, -2.21], [-79.89, -2.21], [-79.89, -2.19], [-79.91, -2.19], [-79.91, -2.21]]
+			]}
+		},
+		{
+			"type": "Feature",
+			"properties": {"name": "Guayaquil norte"},
+			"geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[-80.0, -2.3], [-79.8, -2.3], [-79.8, -2.1], [-80.0, -2.1], [-80.0, -2.3]]]
+			]}
+		},
+		{
+			"type": "Feature",
+			"properties": {"name": "Station"},
+			"geometry": {"type": "Point", "coordinates": [-79.9, -2.2]}
+		}
+	]
+}`
+
+func TestParseRiskLayer(t *testing.T) {
+	layer := RiskLayer{Layer: " SNGRE-Inundaciones ", Hazard: "Flood", DefaultLevel: "medio", Source: "SNGRE"}
+	layer.Normalize()
+	require.NoError(t, layer.Validate())
+	assert.Equal(t, RiskLevelMedium, layer.DefaultLevel)
+
+	now := time.Date(2025, 9, 4, 10, 0, 0, 0, time.UTC)
+	zones, err := ParseRiskLayer([]byte(guayaquilFloodLayer), layer, "admin-1", now)
+	require.NoError(t, err)
+	require.Len(t, zones, 2, "point features are skipped")
+
+	assert.Equal(t, "Estero Salado", zones[0].Name)
+	assert.Equal(t, RiskLevelHigh, zones[0].Level)
+	assert.Len(t, zones[0].Polygons[0], 2)
+	assert.Equal(t, GeoBounds{MinLat: -2.25, MinLng: -79.95, MaxLat: -2.15, MaxLng: -79.85}, zones[0].Bounds)
+	assert.Equal(t, RiskLevelMedium, zones[1].Level, "features without a level take the default")
+
+	layer.DefaultLevel = ""
+	_, err = ParseRiskLayer([]byte(guayaquilFloodLayer), layer, "admin-1", now)
+	assert.ErrorContains(t, err, "no risk level")
+
+	_, err = ParseRiskLayer([]byte(`{"type": "Feature"}`), layer, "admin-1", now)
+	assert.ErrorContains(t, err, "expected a FeatureCollection")
+
+	_, err = ParseRiskLayer([]byte(`{"type": "FeatureCollection", "features": [{"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [0, 0]]]}}]}`), layer, "admin-1", now)
+	assert.ErrorContains(t, err, "at least 4 positions")
+}
+
+func TestAssessRisk(t *testing.T) {
+	layer := RiskLayer{Layer: "sngre-inundaciones", Hazard: HazardFlood, DefaultLevel: RiskLevelMedium}
+	now := time.Date(2025, 9, 4, 10, 0, 0, 0, time.UTC)
+	zones, err := ParseRiskLayer([]byte(guayaquilFloodLayer), layer, "", now)
+	require.NoError(t, err)
+
+	// Inside both zones: the highest level wins
+	flags := AssessRisk(GeoPoint{Lat: -2.24, Lng: -79.94}, zones, now)
+	require.Len(t, flags, 1)
+	assert.Equal(t, RiskLevelHigh, flags[0].Level)
+	assert.Equal(t, "Estero Salado", flags[0].ZoneName)
+
+	// In the hole of the high zone, only the medium zone applies
+	flags = AssessRisk(GeoPoint{Lat: -2.2, Lng: -79.9}, zones, now)
+	require.Len(t, flags, 1)
+	assert.Equal(t, RiskLevelMedium, flags[0].Level)
+
+	assert.Empty(t, AssessRisk(GeoPoint{Lat: -0.18, Lng: -78.47}, zones, now))
+}
+
+func TestPropertySearchFilters_ValidateExcludeRisks(t *testing.T) {
+	filters := NewPropertySearchFilters()
+	filters.ExcludeRisks = []string{" Flood ", "landslide"}
+	require.NoError(t, filters.Validate())
+	assert.Equal(t, []string{"flood", "landslide"}, filters.ExcludeRisks)
+	assert.True(t, filters.HasCriteria())
+
+	filters.ExcludeRisks = []string{"earthquake"}
+	assert.ErrorContains(t, filters.Validate(), "invalid hazard")
+}
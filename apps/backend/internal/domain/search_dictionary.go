@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchSynonym maps a search term to the canonical term used in listings,
+// e.g. "depa" -> "departamento"
+type SearchSynonym struct {
+	ID        string    `json:"id"`
+	Term      string    `json:"term"`
+	Canonical string    `json:"canonical"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SearchStopWord is a word removed from search queries before matching
+type SearchStopWord struct {
+	ID        string    `json:"id"`
+	Word      string    `json:"word"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MaxSearchTermLength is the maximum length of a dictionary entry
+const MaxSearchTermLength = 100
+
+// NewSearchSynonym creates a new synonym entry with normalized terms
+func NewSearchSynonym(term, canonical string) (*SearchSynonym, error) {
+	term = NormalizeSearchTerm(term)
+	canonical = NormalizeSearchTerm(canonical)
+
+	if err := validateSearchTerm(term); err != nil {
+		return nil, fmt.Errorf("invalid term: %w", err)
+	}
+	if err := validateSearchTerm(canonical); err != nil {
+		return nil, fmt.Errorf("invalid canonical term: %w", err)
+	}
+	if term == canonical {
+		return nil, fmt.Errorf("term and canonical term must be different")
+	}
+
+	now := time.Now()
+	return &SearchSynonym{
+		ID:        uuid.New().String(),
+		Term:      term,
+		Canonical: canonical,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// NewSearchStopWord creates a new stop-word entry
+func NewSearchStopWord(word string) (*SearchStopWord, error) {
+	word = NormalizeSearchTerm(word)
+	if err := validateSearchTerm(word); err != nil {
+		return nil, fmt.Errorf("invalid stop word: %w", err)
+	}
+	if strings.Contains(word, " ") {
+		return nil, fmt.Errorf("invalid stop word: must be a single word")
+	}
+
+	return &SearchStopWord{
+		ID:        uuid.New().String(),
+		Word:      word,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NormalizeSearchTerm lowercases a term and collapses inner whitespace
+func NormalizeSearchTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
+
+// validateSearchTerm validates a normalized dictionary term
+func validateSearchTerm(term string) error {
+	if term == "" {
+		return fmt.Errorf("term cannot be empty")
+	}
+	if len(term) > MaxSearchTermLength {
+		return fmt.Errorf("term cannot exceed %d characters", MaxSearchTermLength)
+	}
+	return nil
+}
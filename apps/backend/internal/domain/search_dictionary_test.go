@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSearchSynonym(t *testing.T) {
+	tests := []struct {
+		name              string
+		term              string
+		canonical         string
+		expectError       bool
+		expectedTerm      string
+		expectedCanonical string
+	}{
+		{
+			name:              "valid synonym",
+			term:              "depa",
+			canonical:         "departamento",
+			expectedTerm:      "depa",
+			expectedCanonical: "departamento",
+		},
+		{
+			name:              "normalizes case and whitespace",
+			term:              "  Casa   Rentera ",
+			canonical:         "Casa Comercial",
+			expectedTerm:      "casa rentera",
+			expectedCanonical: "casa comercial",
+		},
+		{
+			name:              "keeps accents",
+			term:              "Urbanización",
+			canonical:         "urbanizacion",
+			expectedTerm:      "urbanización",
+			expectedCanonical: "urbanizacion",
+		},
+		{
+			name:        "empty term",
+			term:        "  ",
+			canonical:   "suite",
+			expectError: true,
+		},
+		{
+			name:        "empty canonical",
+			term:        "suit",
+			canonical:   "",
+			expectError: true,
+		},
+		{
+			name:        "same term and canonical",
+			term:        "Suite",
+			canonical:   "suite",
+			expectError: true,
+		},
+		{
+			name:        "term too long",
+			term:        strings.Repeat("a", MaxSearchTermLength+1),
+			canonical:   "casa",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synonym, err := NewSearchSynonym(tt.term, tt.canonical)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, synonym)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, synonym.ID)
+			assert.Equal(t, tt.expectedTerm, synonym.Term)
+			assert.Equal(t, tt.expectedCanonical, synonym.Canonical)
+			assert.False(t, synonym.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestNewSearchStopWord(t *testing.T) {
+	stopWord, err := NewSearchStopWord(" DE ")
+	require.NoError(t, err)
+	assert.Equal(t, "de", stopWord.Word)
+	assert.NotEmpty(t, stopWord.ID)
+
+	_, err = NewSearchStopWord("")
+	assert.Error(t, err)
+
+	_, err = NewSearchStopWord("de la")
+	assert.Error(t, err)
+}
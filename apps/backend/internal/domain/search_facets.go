@@ -0,0 +1,104 @@
+package domain
+
+import "sort"
+
+// Facet names returned with faceted search results
+const (
+	FacetProvince = "province"
+	FacetCity     = "city"
+	FacetType     = "type"
+	FacetBedrooms = "bedrooms"
+	FacetPrice    = "price"
+	FacetAmenity  = "amenity"
+)
+
+// BedroomFacetBuckets lists bedroom buckets in display order
+var BedroomFacetBuckets = []string{"0", "1", "2", "3", "4", "5+"}
+
+// PriceFacetBuckets lists price buckets (USD) in display order
+var PriceFacetBuckets = []string{"0-50000", "50000-100000", "100000-200000", "200000-500000", "500000+"}
+
+// FacetCount is the number of matching properties for one facet value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets holds aggregation counts for the properties matching a search
+type SearchFacets struct {
+	Provinces   []FacetCount `json:"provinces"`
+	Cities      []FacetCount `json:"cities"`
+	Types       []FacetCount `json:"types"`
+	Bedrooms    []FacetCount `json:"bedrooms"`
+	PriceRanges []FacetCount `json:"price_ranges"`
+	Amenities   []FacetCount `json:"amenities"`
+}
+
+// FacetedSearchResponse is a paginated search response with facet counts
+type FacetedSearchResponse struct {
+	Data       interface{}   `json:"data"`
+	Pagination *Pagination   `json:"pagination"`
+	Facets     *SearchFacets `json:"facets"`
+}
+
+// NewSearchFacets creates empty facets so every group serializes as an array
+func NewSearchFacets() *SearchFacets {
+	return &SearchFacets{
+		Provinces:   []FacetCount{},
+		Cities:      []FacetCount{},
+		Types:       []FacetCount{},
+		Bedrooms:    []FacetCount{},
+		PriceRanges: []FacetCount{},
+		Amenities:   []FacetCount{},
+	}
+}
+
+// Add records the count for a facet value. Unknown facet names are ignored.
+func (f *SearchFacets) Add(facet, value string, count int) {
+	entry := FacetCount{Value: value, Count: count}
+	switch facet {
+	case FacetProvince:
+		f.Provinces = append(f.Provinces, entry)
+	case FacetCity:
+		f.Cities = append(f.Cities, entry)
+	case FacetType:
+		f.Types = append(f.Types, entry)
+	case FacetBedrooms:
+		f.Bedrooms = append(f.Bedrooms, entry)
+	case FacetPrice:
+		f.PriceRanges = append(f.PriceRanges, entry)
+	case FacetAmenity:
+		f.Amenities = append(f.Amenities, entry)
+	}
+}
+
+// Sort orders value facets by count (highest first) and bucket facets by bucket order
+func (f *SearchFacets) Sort() {
+	sortByCount(f.Provinces)
+	sortByCount(f.Cities)
+	sortByCount(f.Types)
+	sortByCount(f.Amenities)
+	sortByBuckets(f.Bedrooms, BedroomFacetBuckets)
+	sortByBuckets(f.PriceRanges, PriceFacetBuckets)
+}
+
+// sortByCount orders facet values by descending count, then alphabetically
+func sortByCount(counts []FacetCount) {
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+}
+
+// sortByBuckets orders facet values following the given bucket order
+func sortByBuckets(counts []FacetCount, buckets []string) {
+	position := make(map[string]int, len(buckets))
+	for i, bucket := range buckets {
+		position[bucket] = i
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		return position[counts[i].Value] < position[counts[j].Value]
+	})
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Sector guide statuses. Only published guides are public and linked from properties.
+const (
+	SectorGuideDraft     = "draft"
+	SectorGuidePublished = "published"
+)
+
+// Sector guide limits
+const (
+	MaxSectorGuideTitleLength   = 200
+	MaxSectorGuideSummaryLength = 500
+	MaxSectorGuideTextLength    = 20000
+	MaxSectorGuidePhotos        = 20
+)
+
+// accentFolder transliterates the accented letters of Spanish place names for slugs
+var accentFolder = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+// slugSeparators matches the runs of characters slugs replace with a hyphen
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// GuidePhoto is a photo of a sector guide
+type GuidePhoto struct {
+	URL     string `json:"url"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// SectorPriceStats are the average prices of the available and reserved listings of a
+// sector, refreshed from the properties table
+type SectorPriceStats struct {
+	ActiveListings int        `json:"active_listings"`
+	AvgSalePrice   float64    `json:"avg_sale_price"`
+	AvgPricePerM2  float64    `json:"avg_price_per_m2"`
+	AvgRentPrice   float64    `json:"avg_rent_price"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// SectorGuide is the editorial guide of a sector of a city, e.g. Cumbayá in Quito
+type SectorGuide struct {
+	ID          string           `json:"id"`
+	Slug        string           `json:"slug"`
+	Province    string           `json:"province"`
+	City        string           `json:"city"`
+	Sector      string           `json:"sector"`
+	Title       string           `json:"title"`
+	Summary     string           `json:"summary"`
+	Description string           `json:"description"`
+	Photos      []GuidePhoto     `json:"photos"`
+	SafetyNotes string           `json:"safety_notes"`
+	Status      string           `json:"status"`
+	Stats       SectorPriceStats `json:"stats"`
+	CreatedBy   string           `json:"created_by,omitempty"`
+	UpdatedBy   string           `json:"updated_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	PublishedAt *time.Time       `json:"published_at,omitempty"`
+}
+
+// SectorGuideContent is the editable content of a guide
+type SectorGuideContent struct {
+	Province    string       `json:"province"`
+	City        string       `json:"city"`
+	Sector      string       `json:"sector"`
+	Slug        string       `json:"slug,omitempty"`
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Photos      []GuidePhoto `json:"photos"`
+	SafetyNotes string       `json:"safety_notes"`
+	Status      string       `json:"status"`
+}
+
+// Normalize trims the content and fills its slug and status defaults
+func (c *SectorGuideContent) Normalize() {
+	c.Province = strings.TrimSpace(c.Province)
+	c.City = strings.TrimSpace(c.City)
+	c.Sector = strings.TrimSpace(c.Sector)
+	c.Title = strings.TrimSpace(c.Title)
+	c.Summary = strings.TrimSpace(c.Summary)
+	c.Description = strings.TrimSpace(c.Description)
+	c.SafetyNotes = strings.TrimSpace(c.SafetyNotes)
+	c.Status = strings.ToLower(strings.TrimSpace(c.Status))
+	if c.Status == "" {
+		c.Status = SectorGuideDraft
+	}
+
+	c.Slug = strings.TrimSpace(c.Slug)
+	if c.Slug == "" {
+		c.Slug = SectorGuideSlug(c.Sector, c.City)
+	}
+	if c.Photos == nil {
+		c.Photos = []GuidePhoto{}
+	}
+	for i := range c.Photos {
+		c.Photos[i].URL = strings.TrimSpace(c.Photos[i].URL)
+		c.Photos[i].Caption = strings.TrimSpace(c.Photos[i].Caption)
+	}
+}
+
+// Validate checks the content
+func (c *SectorGuideContent) Validate() error {
+	if !IsValidProvince(c.Province) {
+		return fmt.Errorf("invalid province: %s", c.Province)
+	}
+	if c.City == "" || c.Sector == "" {
+		return fmt.Errorf("invalid guide: city and sector are required")
+	}
+	if c.Title == "" || utf8.RuneCountInString(c.Title) > MaxSectorGuideTitleLength {
+		return fmt.Errorf("invalid guide: title is required and must be at most %d characters", MaxSectorGuideTitleLength)
+	}
+	if utf8.RuneCountInString(c.Summary) > MaxSectorGuideSummaryLength {
+		return fmt.Errorf("invalid guide: summary must be at most %d characters", MaxSectorGuideSummaryLength)
+	}
+	if utf8.RuneCountInString(c.Description) > MaxSectorGuideTextLength ||
+		utf8.RuneCountInString(c.SafetyNotes) > MaxSectorGuideTextLength {
+		return fmt.Errorf("invalid guide: description and safety notes must be at most %d characters", MaxSectorGuideTextLength)
+	}
+	if !IsValidSlug(c.Slug) || len(c.Slug) > 120 {
+		return fmt.Errorf("invalid slug: %s", c.Slug)
+	}
+	if c.Status != SectorGuideDraft && c.Status != SectorGuidePublished {
+		return fmt.Errorf("invalid status: must be draft or published")
+	}
+	if len(c.Photos) > MaxSectorGuidePhotos {
+		return fmt.Errorf("invalid guide: at most %d photos", MaxSectorGuidePhotos)
+	}
+	for _, photo := range c.Photos {
+		if !strings.HasPrefix(photo.URL, "https://") && !strings.HasPrefix(photo.URL, "/") {
+			return fmt.Errorf("invalid photo URL: %s", photo.URL)
+		}
+	}
+	return nil
+}
+
+// NewSectorGuide creates a guide from validated content
+func NewSectorGuide(content *SectorGuideContent, editorID string, now time.Time) *SectorGuide {
+	guide := &SectorGuide{
+		ID:        uuid.New().String(),
+		CreatedBy: editorID,
+		CreatedAt: now,
+	}
+	guide.Apply(content, editorID, now)
+	return guide
+}
+
+// Apply replaces the content of a guide, stamping its first publication
+func (g *SectorGuide) Apply(content *SectorGuideContent, editorID string, now time.Time) {
+	g.Province = content.Province
+	g.City = content.City
+	g.Sector = content.Sector
+	g.Slug = content.Slug
+	g.Title = content.Title
+	g.Summary = content.Summary
+	g.Description = content.Description
+	g.Photos = content.Photos
+	g.SafetyNotes = content.SafetyNotes
+	g.Status = content.Status
+	g.UpdatedBy = editorID
+	g.UpdatedAt = now
+	if g.Status == SectorGuidePublished && g.PublishedAt == nil {
+		g.PublishedAt = &now
+	}
+}
+
+// IsPublished reports whether the guide is public
+func (g *SectorGuide) IsPublished() bool {
+	return g.Status == SectorGuidePublished
+}
+
+// Link returns the reference to the guide added to property responses
+func (g *SectorGuide) Link() *SectorGuideLink {
+	return &SectorGuideLink{Slug: g.Slug, Title: g.Title}
+}
+
+// SectorGuideLink references the guide of a property's sector
+type SectorGuideLink struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+// SectorGuideSlug builds the slug of a guide from its sector and city, e.g.
+// "cumbaya-quito" for Cumbayá in Quito
+func SectorGuideSlug(sector, city string) string {
+	return SlugifyPlace(sector + " " + city)
+}
+
+// SlugifyPlace lowercases a place name, folds Spanish accents and joins its words with hyphens
+func SlugifyPlace(name string) string {
+	slug := accentFolder.Replace(strings.ToLower(strings.TrimSpace(name)))
+	return strings.Trim(slugSeparators.ReplaceAllString(slug, "-"), "-")
+}
+
+// SectorKey identifies a sector of a city regardless of case and accents
+func SectorKey(province, city, sector string) string {
+	return SlugifyPlace(province) + "|" + SlugifyPlace(city) + "|" + SlugifyPlace(sector)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugifyPlace(t *testing.T) {
+	assert.Equal(t, "cumbaya-quito", SectorGuideSlug("Cumbayá", "Quito"))
+	assert.Equal(t, "la-floresta", SlugifyPlace("  La Floresta "))
+	assert.Equal(t, "samborondon-km-2-5", SlugifyPlace("Samborondón (km 2.5)"))
+	assert.Equal(t, SectorKey("Pichincha", "Quito", "Cumbayá"), SectorKey("pichincha", "QUITO", "cumbaya"))
+}
+
+func TestSectorGuideContent_Validate(t *testing.T) {
+	valid := func() *SectorGuideContent {
+		return &SectorGuideContent{
+			Province: "Pichincha",
+			City:     "Quito",
+			Sector:   "Cumbayá",
+			Title:    "Vivir en Cumbayá",
+			Photos:   []GuidePhoto{{URL: "https://cdn.example.com/cumbaya.jpg"}},
+		}
+	}
+
+	content := valid()
+	content.Normalize()
+	require.NoError(t, content.Validate())
+	assert.Equal(t, "cumbaya-quito", content.Slug)
+	assert.Equal(t, SectorGuideDraft, content.Status)
+
+	tests := []struct {
+		name   string
+		modify func(c *SectorGuideContent)
+		err    string
+	}{
+		{"unknown province", func(c *SectorGuideContent) { c.Province = "Lima" }, "invalid province"},
+		{"missing sector", func(c *SectorGuideContent) { c.Sector = "" }, "city and sector are required"},
+		{"missing title", func(c *SectorGuideContent) { c.Title = "" }, "title is required"},
+		{"bad slug", func(c *SectorGuideContent) { c.Slug = "Cumbayá Quito" }, "invalid slug"},
+		{"unknown status", func(c *SectorGuideContent) { c.Status = "archived" }, "invalid status"},
+		{"insecure photo", func(c *SectorGuideContent) { c.Photos[0].URL = "http://example.com/a.jpg" }, "invalid photo URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := valid()
+			tt.modify(content)
+			content.Normalize()
+			assert.ErrorContains(t, content.Validate(), tt.err)
+		})
+	}
+}
+
+func TestSectorGuide_Apply(t *testing.T) {
+	created := time.Date(2025, 9, 3, 10, 0, 0, 0, time.UTC)
+	content := &SectorGuideContent{Province: "Pichincha", City: "Quito", Sector: "Cumbayá", Title: "Cumbayá"}
+	content.Normalize()
+
+	guide := NewSectorGuide(content, "editor-1", created)
+	assert.False(t, guide.IsPublished())
+	assert.Nil(t, guide.PublishedAt)
+
+	published := created.Add(time.Hour)
+	content.Status = SectorGuidePublished
+	guide.Apply(content, "editor-2", published)
+	require.NotNil(t, guide.PublishedAt)
+	assert.Equal(t, published, *guide.PublishedAt)
+	assert.Equal(t, "editor-1", guide.CreatedBy)
+	assert.Equal(t, "editor-2", guide.UpdatedBy)
+
+	// Later edits keep the first publication date
+	guide.Apply(content, "editor-1", published.Add(time.Hour))
+	assert.Equal(t, published, *guide.PublishedAt)
+}
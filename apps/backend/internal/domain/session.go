@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// SessionRevokeOthers is the session ID that revokes every session of a user but the
+// current one
+const SessionRevokeOthers = "others"
+
+// Session is a login of a user on a device. It lasts while its refresh token is valid
+// and is kept across refreshes; only the hash of the latest refresh token is stored,
+// so a rotated token cannot be used again.
+type Session struct {
+	ID               string     `json:"id"`
+	UserID           string     `json:"user_id"`
+	RefreshTokenHash string     `json:"-"`
+	Device           string     `json:"device"`
+	UserAgent        string     `json:"user_agent,omitempty"`
+	IPAddress        string     `json:"ip_address"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastSeenAt       time.Time  `json:"last_seen_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	Current          bool       `json:"current"`
+}
+
+// NewSession starts a session for a refresh token
+func NewSession(id, userID, refreshToken, ipAddress, userAgent string, expiresAt time.Time) *Session {
+	if len(userAgent) > MaxLoginUserAgentLength {
+		userAgent = userAgent[:MaxLoginUserAgentLength]
+	}
+	now := time.Now()
+	return &Session{
+		ID:               id,
+		UserID:           userID,
+		RefreshTokenHash: HashRefreshToken(refreshToken),
+		Device:           DeviceName(userAgent),
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		CreatedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        expiresAt,
+	}
+}
+
+// IsActive reports whether the session is neither revoked nor expired
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// HashRefreshToken returns the SHA-256 hash a refresh token is stored as
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// userAgentBrowsers and userAgentSystems are matched in order; Edge and Opera
+// also name Chrome, and Chrome names Safari
+var (
+	userAgentBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"}, {"CriOS/", "Chrome"}, {"Chrome/", "Chrome"}, {"Safari/", "Safari"},
+		{"okhttp/", "Android app"}, {"CFNetwork/", "iOS app"},
+	}
+	userAgentSystems = []struct{ token, name string }{
+		{"Android", "Android"}, {"iPhone", "iPhone"}, {"iPad", "iPad"}, {"Windows", "Windows"},
+		{"Mac OS X", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	}
+)
+
+// DeviceName describes the device of a user agent, e.g. "Chrome on Windows"
+func DeviceName(userAgent string) string {
+	browser, system := "", ""
+	for _, candidate := range userAgentBrowsers {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+	for _, candidate := range userAgentSystems {
+		if strings.Contains(userAgent, candidate.token) {
+			system = candidate.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
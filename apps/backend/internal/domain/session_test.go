@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceName(t *testing.T) {
+	tests := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36":                         "Chrome on Windows",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36 Edg/139.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 18_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.5 Mobile/15E148 Safari/604.1": "Safari on iPhone",
+		"Mozilla/5.0 (Linux; Android 14; SM-A546E) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Mobile Safari/537.36":                  "Chrome on Android",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.6; rv:142.0) Gecko/20100101 Firefox/142.0":                                                     "Firefox on macOS",
+		"okhttp/4.12.0": "Android app",
+		"curl/8.5.0":    "Unknown device",
+		"":              "Unknown device",
+	}
+	for userAgent, expected := range tests {
+		assert.Equal(t, expected, DeviceName(userAgent), userAgent)
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	session := NewSession("session-1", "user-1", "refresh-token", "190.152.10.4", strings.Repeat("a", 600), expiresAt)
+
+	assert.Len(t, session.UserAgent, MaxLoginUserAgentLength)
+	assert.Equal(t, HashRefreshToken("refresh-token"), session.RefreshTokenHash)
+	assert.NotContains(t, session.RefreshTokenHash, "refresh-token")
+	assert.True(t, session.IsActive(time.Now()))
+	assert.False(t, session.IsActive(expiresAt))
+
+	revokedAt := time.Now()
+	session.RevokedAt = &revokedAt
+	assert.False(t, session.IsActive(time.Now()))
+}
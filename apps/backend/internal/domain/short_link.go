@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Short link limits
+const (
+	ShortLinkCodeLength  = 7
+	MaxShortLinkCampaign = 50
+	MaxClickSourceLength = 50
+)
+
+// ClickSourceDirect is the source of clicks without a referrer or source tag
+const ClickSourceDirect = "direct"
+
+const shortLinkAlphabet = "23456789abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+var (
+	shortLinkCodeRegex = regexp.MustCompile(`^[A-Za-z0-9]{4,16}$`)
+	campaignRegex      = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
+// knownReferrers maps referrer hosts to the source they are reported as
+var knownReferrers = map[string]string{
+	"facebook.com":     "facebook",
+	"fb.com":           "facebook",
+	"instagram.com":    "instagram",
+	"whatsapp.com":     "whatsapp",
+	"wa.me":            "whatsapp",
+	"t.co":             "twitter",
+	"twitter.com":      "twitter",
+	"x.com":            "twitter",
+	"linkedin.com":     "linkedin",
+	"lnkd.in":          "linkedin",
+	"tiktok.com":       "tiktok",
+	"google.com":       "google",
+	"mail.google.com":  "email",
+	"outlook.live.com": "email",
+}
+
+// ShortLink is a short /l/{code} URL that redirects to a property, optionally tagged
+// with the campaign it was shared in
+type ShortLink struct {
+	ID         string    `json:"id"`
+	Code       string    `json:"code"`
+	PropertyID string    `json:"property_id"`
+	Campaign   string    `json:"campaign,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	ClickCount int       `json:"click_count"`
+	URL        string    `json:"url,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ShortLinkClick is a single visit through a short link
+type ShortLinkClick struct {
+	ID        string    `json:"id"`
+	LinkID    string    `json:"link_id"`
+	Source    string    `json:"source"`
+	ClickedAt time.Time `json:"clicked_at"`
+}
+
+// ShortLinkStats summarizes the short link clicks of a property
+type ShortLinkStats struct {
+	Links      int            `json:"links"`
+	Clicks     int            `json:"clicks"`
+	BySource   map[string]int `json:"by_source"`
+	ByCampaign map[string]int `json:"by_campaign"`
+}
+
+// PropertyViewStats combines the detail page views of a property with the visits
+// arriving through its short links
+type PropertyViewStats struct {
+	PropertyID string         `json:"property_id"`
+	Views      int            `json:"views"`
+	ShortLinks ShortLinkStats `json:"short_links"`
+}
+
+// NewShortLink creates a short link to a property with a random code
+func NewShortLink(propertyID, campaign, createdBy string) (*ShortLink, error) {
+	if propertyID == "" {
+		return nil, fmt.Errorf("property ID cannot be empty")
+	}
+
+	campaign = strings.ToLower(strings.TrimSpace(campaign))
+	if campaign != "" && (len(campaign) > MaxShortLinkCampaign || !campaignRegex.MatchString(campaign)) {
+		return nil, fmt.Errorf("campaign must be up to %d lowercase letters, digits, '-' or '_'", MaxShortLinkCampaign)
+	}
+
+	code, err := GenerateShortLinkCode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShortLink{
+		ID:         uuid.New().String(),
+		Code:       code,
+		PropertyID: propertyID,
+		Campaign:   campaign,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// GenerateShortLinkCode returns a random code without look-alike characters
+func GenerateShortLinkCode() (string, error) {
+	max := big.NewInt(int64(len(shortLinkAlphabet)))
+	code := make([]byte, ShortLinkCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate short link code: %w", err)
+		}
+		code[i] = shortLinkAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// IsValidShortLinkCode verifies the format of a short link code
+func IsValidShortLinkCode(code string) bool {
+	return shortLinkCodeRegex.MatchString(code)
+}
+
+// ClickSource returns where a click came from: an explicit source tag (?src= or
+// ?utm_source=) wins, then well-known referrers, then the referrer host itself.
+// Clicks without either are direct.
+func ClickSource(tag, referer string) string {
+	if source := normalizeClickSource(tag); source != "" {
+		return source
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(referer))
+	if err != nil || ref.Hostname() == "" {
+		return ClickSourceDirect
+	}
+	host := strings.TrimPrefix(strings.ToLower(ref.Hostname()), "www.")
+	// l.facebook.com, web.whatsapp.com... match their parent domain
+	for parent := host; parent != ""; {
+		if source, ok := knownReferrers[parent]; ok {
+			return source
+		}
+		dot := strings.Index(parent, ".")
+		if dot < 0 {
+			break
+		}
+		parent = parent[dot+1:]
+	}
+	if len(host) > MaxClickSourceLength {
+		host = host[:MaxClickSourceLength]
+	}
+	return host
+}
+
+// normalizeClickSource lowercases a source tag and drops anything but letters,
+// digits, '.', '-' and '_'
+func normalizeClickSource(tag string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(tag)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+			b.WriteRune(r)
+		}
+		if b.Len() == MaxClickSourceLength {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShortLink(t *testing.T) {
+	link, err := NewShortLink("prop-1", " Verano-2025 ", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "verano-2025", link.Campaign)
+	assert.Len(t, link.Code, ShortLinkCodeLength)
+	assert.True(t, IsValidShortLinkCode(link.Code))
+
+	_, err = NewShortLink("prop-1", "campaña de verano", "user-1")
+	assert.Error(t, err)
+
+	_, err = NewShortLink("", "", "user-1")
+	assert.Error(t, err)
+}
+
+func TestIsValidShortLinkCode(t *testing.T) {
+	assert.True(t, IsValidShortLinkCode("aB3dE5f"))
+	assert.False(t, IsValidShortLinkCode("abc"))
+	assert.False(t, IsValidShortLinkCode("abc/../x"))
+}
+
+func TestClickSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		referer string
+		want    string
+	}{
+		{"tag wins over referrer", "WhatsApp", "https://www.facebook.com/", "whatsapp"},
+		{"tag is sanitized", " news<letter> ", "", "newsletter"},
+		{"known referrer", "", "https://l.facebook.com/l.php?u=x", "facebook"},
+		{"known referrer subdomain", "", "https://web.whatsapp.com/", "whatsapp"},
+		{"email client", "", "https://mail.google.com/mail/u/0/", "email"},
+		{"unknown referrer host", "", "https://www.plusvalia.com/casa", "plusvalia.com"},
+		{"no referrer", "", "", ClickSourceDirect},
+		{"invalid referrer", "", "::not a url", ClickSourceDirect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClickSource(tt.tag, tt.referer))
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Spam screened entity types
+const (
+	SpamEntityInquiry = "inquiry" // rental application
+	SpamEntityListing = "listing"
+)
+
+// Spam case statuses. Quarantined entities stay hidden until an administrator
+// approves or blocks them.
+const (
+	SpamCaseQuarantined = "quarantined"
+	SpamCaseApproved    = "approved"
+	SpamCaseBlocked     = "blocked"
+)
+
+// Spam signals
+const (
+	SpamSignalHoneypot        = "honeypot"
+	SpamSignalRate            = "rate"
+	SpamSignalDisposableEmail = "disposable_email"
+	SpamSignalPhoneFormat     = "phone_format"
+	SpamSignalBannedKeyword   = "banned_keyword"
+)
+
+// Spam scoring. Scores add up to at most MaxSpamScore; submissions scoring
+// SpamQuarantineScore or more are quarantined.
+const (
+	MaxSpamScore        = 100
+	SpamQuarantineScore = 60
+
+	spamHoneypotScore        = 100
+	spamRateScore            = 40
+	spamDisposableEmailScore = 40
+	spamPhoneFormatScore     = 20
+	spamKeywordScore         = 30
+	spamMaxKeywordScore      = 60
+)
+
+// Spam rate limits: submissions per user within SpamRateWindow before the rate
+// signal fires
+const (
+	SpamRateWindow        = time.Hour
+	SpamInquiryRateLimit  = 5
+	SpamListingRateLimit  = 10
+	MaxSpamKeywordMatches = 5
+)
+
+// DefaultDisposableEmailDomains lists throwaway email providers
+var DefaultDisposableEmailDomains = []string{
+	"10minutemail.com", "dispostable.com", "guerrillamail.com", "mailinator.com", "maildrop.cc",
+	"sharklasers.com", "temp-mail.org", "tempmail.com", "throwawaymail.com", "trashmail.com",
+	"yopmail.com",
+}
+
+// DefaultSpamKeywords lists phrases common in rental and sale scams
+var DefaultSpamKeywords = []string{
+	"western union", "moneygram", "gift card", "tarjeta de regalo", "bitcoin", "criptomoneda",
+	"depósito por adelantado", "deposito por adelantado", "pago por adelantado",
+	"sin ver la propiedad", "estoy en el extranjero", "ganancias garantizadas",
+}
+
+// SpamRules configures spam scoring
+type SpamRules struct {
+	DisposableEmailDomains []string
+	Keywords               []string
+}
+
+// DefaultSpamRules returns the built-in disposable domains and keywords
+func DefaultSpamRules() SpamRules {
+	return SpamRules{
+		DisposableEmailDomains: append([]string{}, DefaultDisposableEmailDomains...),
+		Keywords:               append([]string{}, DefaultSpamKeywords...),
+	}
+}
+
+// SpamCheck holds what a submission is scored on
+type SpamCheck struct {
+	// Honeypot is a form field hidden from people; bots fill it in
+	Honeypot string
+	Email    string
+	Phone    string
+	Text     string
+	// RecentSubmissions counts the submitter's submissions within SpamRateWindow,
+	// including this one
+	RecentSubmissions int
+	RateLimit         int
+}
+
+// SpamSignal is one reason a submission looks like spam
+type SpamSignal struct {
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// SpamAssessment is the score of a submission with the signals that produced it
+type SpamAssessment struct {
+	Score   int          `json:"score"`
+	Signals []SpamSignal `json:"signals"`
+}
+
+// Quarantine reports whether the submission must be held for review
+func (a SpamAssessment) Quarantine() bool {
+	return a.Score >= SpamQuarantineScore
+}
+
+// Assess scores a submission
+func (r SpamRules) Assess(check SpamCheck) SpamAssessment {
+	assessment := SpamAssessment{Signals: []SpamSignal{}}
+	add := func(name string, score int, detail string) {
+		assessment.Signals = append(assessment.Signals, SpamSignal{Name: name, Score: score, Detail: detail})
+		assessment.Score += score
+	}
+
+	if strings.TrimSpace(check.Honeypot) != "" {
+		add(SpamSignalHoneypot, spamHoneypotScore, "")
+	}
+	if check.RateLimit > 0 && check.RecentSubmissions > check.RateLimit {
+		add(SpamSignalRate, spamRateScore,
+			fmt.Sprintf("%d submissions in %s", check.RecentSubmissions, SpamRateWindow))
+	}
+	if domain := emailDomain(check.Email); domain != "" && r.isDisposable(domain) {
+		add(SpamSignalDisposableEmail, spamDisposableEmailScore, domain)
+	}
+	if phone := strings.TrimSpace(check.Phone); phone != "" && validatePhone(normalizePhone(phone)) != nil {
+		add(SpamSignalPhoneFormat, spamPhoneFormatScore, phone)
+	}
+	if matches := r.keywordMatches(check.Text); len(matches) > 0 {
+		score := spamKeywordScore * len(matches)
+		if score > spamMaxKeywordScore {
+			score = spamMaxKeywordScore
+		}
+		add(SpamSignalBannedKeyword, score, strings.Join(matches, ", "))
+	}
+
+	if assessment.Score > MaxSpamScore {
+		assessment.Score = MaxSpamScore
+	}
+	return assessment
+}
+
+// isDisposable matches a domain and its subdomains against the disposable list
+func (r SpamRules) isDisposable(domain string) bool {
+	for _, disposable := range r.DisposableEmailDomains {
+		disposable = strings.ToLower(strings.TrimSpace(disposable))
+		if disposable != "" && (domain == disposable || strings.HasSuffix(domain, "."+disposable)) {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordMatches returns the keywords found in a text, sorted, at most MaxSpamKeywordMatches
+func (r SpamRules) keywordMatches(text string) []string {
+	text = strings.ToLower(text)
+	matches := []string{}
+	for _, keyword := range r.Keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && strings.Contains(text, keyword) {
+			matches = append(matches, keyword)
+		}
+	}
+	sort.Strings(matches)
+	if len(matches) > MaxSpamKeywordMatches {
+		matches = matches[:MaxSpamKeywordMatches]
+	}
+	return matches
+}
+
+// emailDomain returns the lowercased domain of an email address
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// normalizePhone drops the spaces, dashes and parentheses people type in phone numbers
+func normalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(phone)
+}
+
+// SpamCase is a quarantined submission awaiting an administrator's decision
+type SpamCase struct {
+	ID          string       `json:"id"`
+	EntityType  string       `json:"entity_type"`
+	EntityID    string       `json:"entity_id"`
+	SubmitterID string       `json:"submitter_id,omitempty"`
+	Score       int          `json:"score"`
+	Signals     []SpamSignal `json:"signals"`
+	Status      string       `json:"status"`
+	ReviewedBy  *string      `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time   `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// SpamCaseFilter selects spam cases
+type SpamCaseFilter struct {
+	EntityType string
+	Status     string
+	Limit      int
+	Offset     int
+}
+
+// NewSpamCase quarantines a submission
+func NewSpamCase(entityType, entityID, submitterID string, assessment SpamAssessment) *SpamCase {
+	return &SpamCase{
+		ID:          uuid.New().String(),
+		EntityType:  entityType,
+		EntityID:    entityID,
+		SubmitterID: submitterID,
+		Score:       assessment.Score,
+		Signals:     assessment.Signals,
+		Status:      SpamCaseQuarantined,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Decide approves or blocks a quarantined submission
+func (c *SpamCase) Decide(approve bool, reviewerID string, at time.Time) error {
+	if c.Status != SpamCaseQuarantined {
+		return fmt.Errorf("invalid spam case: already %s", c.Status)
+	}
+
+	c.Status = SpamCaseBlocked
+	if approve {
+		c.Status = SpamCaseApproved
+	}
+	c.ReviewedBy = &reviewerID
+	c.ReviewedAt = &at
+	return nil
+}
+
+// IsValidSpamEntityType verifies if an entity type is screened for spam
+func IsValidSpamEntityType(entityType string) bool {
+	return entityType == SpamEntityInquiry || entityType == SpamEntityListing
+}
+
+// IsValidSpamCaseStatus verifies if a status is a spam case status
+func IsValidSpamCaseStatus(status string) bool {
+	return status == SpamCaseQuarantined || status == SpamCaseApproved || status == SpamCaseBlocked
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpamRules_Assess(t *testing.T) {
+	rules := DefaultSpamRules()
+
+	clean := rules.Assess(SpamCheck{Email: "maria@gmail.com", Phone: "099 123 4567", Text: "¿Aceptan mascotas?", RecentSubmissions: 1, RateLimit: 5})
+	assert.Equal(t, 0, clean.Score)
+	assert.Empty(t, clean.Signals)
+	assert.False(t, clean.Quarantine())
+
+	honeypot := rules.Assess(SpamCheck{Honeypot: "http://spam.example"})
+	assert.Equal(t, MaxSpamScore, honeypot.Score)
+	assert.True(t, honeypot.Quarantine())
+
+	scam := rules.Assess(SpamCheck{
+		Email:             "x@mail.YOPMAIL.com",
+		Phone:             "12345",
+		Text:              "Estoy en el extranjero, envíe el depósito por adelantado por Western Union",
+		RecentSubmissions: 6,
+		RateLimit:         5,
+	})
+	assert.Equal(t, MaxSpamScore, scam.Score, "scores are capped")
+	names := []string{}
+	for _, signal := range scam.Signals {
+		names = append(names, signal.Name)
+	}
+	assert.Equal(t, []string{SpamSignalRate, SpamSignalDisposableEmail, SpamSignalPhoneFormat, SpamSignalBannedKeyword}, names)
+	assert.Equal(t, spamMaxKeywordScore, scam.Signals[3].Score, "keyword scores are capped")
+
+	oneKeyword := rules.Assess(SpamCheck{Text: "Acepto bitcoin"})
+	assert.Equal(t, spamKeywordScore, oneKeyword.Score)
+	assert.False(t, oneKeyword.Quarantine(), "a single keyword is not enough to quarantine")
+}
+
+func TestSpamCase_Decide(t *testing.T) {
+	spamCase := NewSpamCase(SpamEntityListing, "prop-1", "user-1", SpamAssessment{Score: 80})
+	assert.Equal(t, SpamCaseQuarantined, spamCase.Status)
+
+	require.NoError(t, spamCase.Decide(false, "admin-1", time.Now()))
+	assert.Equal(t, SpamCaseBlocked, spamCase.Status)
+	assert.Equal(t, "admin-1", *spamCase.ReviewedBy)
+	assert.ErrorContains(t, spamCase.Decide(true, "admin-1", time.Now()), "already blocked")
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// Startup phases of an instance, gating its readiness
+const (
+	// StartupPhaseStarting means the startup checks have not all passed yet
+	StartupPhaseStarting = "starting"
+	// StartupPhaseReady means every startup check passed and the instance takes traffic
+	StartupPhaseReady = "ready"
+	// StartupPhaseFailed means the checks still failed when the grace period ran out
+	StartupPhaseFailed = "failed"
+)
+
+// StartupCheckResult is the latest result of a startup check
+type StartupCheckResult struct {
+	Name      string    `json:"name"`
+	Passed    bool      `json:"passed"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// StartupReport is the progress of the startup phase of an instance
+type StartupReport struct {
+	Phase       string               `json:"phase"`
+	StartedAt   time.Time            `json:"started_at"`
+	ReadyAt     *time.Time           `json:"ready_at,omitempty"`
+	GracePeriod string               `json:"grace_period"`
+	Checks      []StartupCheckResult `json:"checks"`
+}
+
+// Ready reports whether the instance may take traffic
+func (r *StartupReport) Ready() bool {
+	return r.Phase == StartupPhaseReady
+}
+
+// FailedChecks returns the names of the checks that have not passed
+func (r *StartupReport) FailedChecks() []string {
+	var failed []string
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
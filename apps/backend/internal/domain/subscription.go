@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Subscription plans
+const (
+	PlanFree    = "free"
+	PlanPro     = "pro"
+	PlanPremium = "premium"
+)
+
+// DefaultSubscriptionPlan is the plan of agencies that never subscribed
+const DefaultSubscriptionPlan = PlanFree
+
+// Unlimited marks a plan limit without a maximum
+const Unlimited = -1
+
+// SubscriptionPlan defines the limits and features of an agency plan
+type SubscriptionPlan struct {
+	Name              string  `json:"name"`
+	Rank              int     `json:"rank"`
+	MonthlyPrice      float64 `json:"monthly_price"`
+	MaxActiveListings int     `json:"max_active_listings"`
+	MaxFeatured       int     `json:"max_featured"`
+	ListingExpiryDays int     `json:"listing_expiry_days"`
+	// ImageQuotaPlan is the image quota plan that limits the agency's image storage
+	ImageQuotaPlan string `json:"image_quota_plan"`
+}
+
+// DefaultSubscriptionPlans are the plans offered to agencies
+var DefaultSubscriptionPlans = map[string]SubscriptionPlan{
+	PlanFree:    {Name: PlanFree, Rank: 0, MonthlyPrice: 0, MaxActiveListings: 10, MaxFeatured: 0, ListingExpiryDays: 60, ImageQuotaPlan: "basic"},
+	PlanPro:     {Name: PlanPro, Rank: 1, MonthlyPrice: 49, MaxActiveListings: 100, MaxFeatured: 5, ListingExpiryDays: 90, ImageQuotaPlan: "professional"},
+	PlanPremium: {Name: PlanPremium, Rank: 2, MonthlyPrice: 149, MaxActiveListings: Unlimited, MaxFeatured: 25, ListingExpiryDays: 180, ImageQuotaPlan: "enterprise"},
+}
+
+// AllowsListings reports whether count active listings fit in the plan
+func (p SubscriptionPlan) AllowsListings(count int) bool {
+	return p.MaxActiveListings == Unlimited || count <= p.MaxActiveListings
+}
+
+// AllowsFeatured reports whether count featured listings fit in the plan
+func (p SubscriptionPlan) AllowsFeatured(count int) bool {
+	return p.MaxFeatured == Unlimited || count <= p.MaxFeatured
+}
+
+// IsUpgradeFrom reports whether the plan ranks above another
+func (p SubscriptionPlan) IsUpgradeFrom(other SubscriptionPlan) bool {
+	return p.Rank > other.Rank
+}
+
+// PlanUsage is what an agency currently uses of its plan
+type PlanUsage struct {
+	ActiveListings   int   `json:"active_listings"`
+	FeaturedListings int   `json:"featured_listings"`
+	StorageBytes     int64 `json:"storage_bytes"`
+}
+
+// CheckDowngrade verifies the current usage fits in a smaller plan
+func (u PlanUsage) CheckDowngrade(plan SubscriptionPlan, maxStorageBytes int64) error {
+	if !plan.AllowsListings(u.ActiveListings) {
+		return fmt.Errorf("cannot downgrade: %d active listings exceed the %s plan limit of %d", u.ActiveListings, plan.Name, plan.MaxActiveListings)
+	}
+	if !plan.AllowsFeatured(u.FeaturedListings) {
+		return fmt.Errorf("cannot downgrade: %d featured listings exceed the %s plan limit of %d", u.FeaturedListings, plan.Name, plan.MaxFeatured)
+	}
+	if maxStorageBytes > 0 && u.StorageBytes > maxStorageBytes {
+		return fmt.Errorf("cannot downgrade: %d bytes of images exceed the %s plan storage of %d bytes", u.StorageBytes, plan.Name, maxStorageBytes)
+	}
+	return nil
+}
+
+// AgencySubscription is an agency's plan, its limits and current usage
+type AgencySubscription struct {
+	AgencyID        string           `json:"agency_id"`
+	Plan            SubscriptionPlan `json:"plan"`
+	MaxStorageBytes int64            `json:"max_storage_bytes"`
+	Usage           PlanUsage        `json:"usage"`
+	ChangedAt       *time.Time       `json:"changed_at,omitempty"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionPlan_Limits(t *testing.T) {
+	free := DefaultSubscriptionPlans[PlanFree]
+	pro := DefaultSubscriptionPlans[PlanPro]
+	premium := DefaultSubscriptionPlans[PlanPremium]
+
+	assert.True(t, free.AllowsListings(10))
+	assert.False(t, free.AllowsListings(11))
+	assert.False(t, free.AllowsFeatured(1))
+	assert.True(t, premium.AllowsListings(100000))
+	assert.True(t, pro.IsUpgradeFrom(free))
+	assert.False(t, free.IsUpgradeFrom(premium))
+
+	for name, plan := range DefaultSubscriptionPlans {
+		_, ok := DefaultImageQuotaPlans[plan.ImageQuotaPlan]
+		assert.True(t, ok, "plan %s must reference an image quota plan", name)
+	}
+}
+
+func TestPlanUsage_CheckDowngrade(t *testing.T) {
+	free := DefaultSubscriptionPlans[PlanFree]
+
+	assert.NoError(t, PlanUsage{ActiveListings: 8, StorageBytes: 100}.CheckDowngrade(free, 1<<30))
+	assert.ErrorContains(t, PlanUsage{ActiveListings: 11}.CheckDowngrade(free, 1<<30), "active listings")
+	assert.ErrorContains(t, PlanUsage{FeaturedListings: 1}.CheckDowngrade(free, 1<<30), "featured listings")
+	assert.ErrorContains(t, PlanUsage{StorageBytes: 2 << 30}.CheckDowngrade(free, 1<<30), "bytes of images")
+}
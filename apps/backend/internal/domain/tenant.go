@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTenantID owns every record of single-tenant installs and rows created before
+// multi-tenancy was enabled
+const DefaultTenantID = "default"
+
+// Tenant limits
+const (
+	MaxTenantNameLength = 100
+	MaxTenantDomains    = 10
+)
+
+var (
+	tenantIDRegex     = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+	tenantDomainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+	hexColorRegex     = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// Tenant is a real estate network the platform is white-labeled for. Its listings,
+// users and agencies are isolated from those of other tenants.
+type Tenant struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Domains   []string          `json:"domains"` // hosts the tenant is resolved from
+	Branding  TenantBranding    `json:"branding"`
+	Settings  map[string]string `json:"settings"` // per-tenant overrides read by the frontend
+	Active    bool              `json:"active"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// TenantBranding is the public look of a tenant's site
+type TenantBranding struct {
+	DisplayName    string `json:"display_name"`
+	LogoURL        string `json:"logo_url,omitempty"`
+	FaviconURL     string `json:"favicon_url,omitempty"`
+	PrimaryColor   string `json:"primary_color,omitempty"`
+	SecondaryColor string `json:"secondary_color,omitempty"`
+	SupportEmail   string `json:"support_email,omitempty"`
+	SupportPhone   string `json:"support_phone,omitempty"`
+}
+
+// NewTenant creates an active tenant
+func NewTenant(id, name string, domains []string) (*Tenant, error) {
+	now := time.Now()
+	tenant := &Tenant{
+		ID:        strings.ToLower(strings.TrimSpace(id)),
+		Name:      strings.TrimSpace(name),
+		Domains:   NormalizeTenantDomains(domains),
+		Branding:  TenantBranding{DisplayName: strings.TrimSpace(name)},
+		Settings:  map[string]string{},
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// Validate verifies the tenant identifier, domains and branding
+func (t *Tenant) Validate() error {
+	if !tenantIDRegex.MatchString(t.ID) {
+		return fmt.Errorf("invalid tenant ID: use 2-63 lowercase letters, digits or '-'")
+	}
+	if t.Name == "" || len(t.Name) > MaxTenantNameLength {
+		return fmt.Errorf("invalid tenant name: must be 1-%d characters", MaxTenantNameLength)
+	}
+	if len(t.Domains) > MaxTenantDomains {
+		return fmt.Errorf("invalid tenant domains: at most %d allowed", MaxTenantDomains)
+	}
+	for _, domain := range t.Domains {
+		if !tenantDomainRegex.MatchString(domain) {
+			return fmt.Errorf("invalid tenant domain: %s", domain)
+		}
+	}
+	for _, color := range []string{t.Branding.PrimaryColor, t.Branding.SecondaryColor} {
+		if color != "" && !hexColorRegex.MatchString(color) {
+			return fmt.Errorf("invalid branding color: %s, use #rrggbb", color)
+		}
+	}
+	if t.Branding.SupportEmail != "" {
+		if err := validateEmail(t.Branding.SupportEmail); err != nil {
+			return fmt.Errorf("invalid branding support email: %w", err)
+		}
+	}
+	return nil
+}
+
+// NormalizeTenantDomains lowercases the domains, drops ports and duplicates
+func NormalizeTenantDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	seen := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		host := NormalizeHost(domain)
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		normalized = append(normalized, host)
+	}
+	return normalized
+}
+
+// NormalizeHost lowercases a request host and strips its port and trailing dot
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// IsValidTenantID verifies the format of a tenant identifier
+func IsValidTenantID(id string) bool {
+	return tenantIDRegex.MatchString(id)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTenant(t *testing.T) {
+	tenant, err := NewTenant(" Red-Norte ", "Red Norte", []string{"RedNorte.ec:443", "rednorte.ec", "www.rednorte.ec."})
+	require.NoError(t, err)
+	assert.Equal(t, "red-norte", tenant.ID)
+	assert.Equal(t, []string{"rednorte.ec", "www.rednorte.ec"}, tenant.Domains)
+	assert.Equal(t, "Red Norte", tenant.Branding.DisplayName)
+	assert.True(t, tenant.Active)
+
+	_, err = NewTenant("-bad", "Red Norte", nil)
+	assert.ErrorContains(t, err, "invalid tenant ID")
+
+	_, err = NewTenant("red-norte", "", nil)
+	assert.ErrorContains(t, err, "invalid tenant name")
+
+	_, err = NewTenant("red-norte", "Red Norte", []string{"localhost"})
+	assert.ErrorContains(t, err, "invalid tenant domain")
+}
+
+func TestTenant_ValidateBranding(t *testing.T) {
+	tenant, err := NewTenant("red-norte", "Red Norte", nil)
+	require.NoError(t, err)
+
+	tenant.Branding.PrimaryColor = "#1a73e8"
+	tenant.Branding.SupportEmail = "ayuda@rednorte.ec"
+	assert.NoError(t, tenant.Validate())
+
+	tenant.Branding.SecondaryColor = "blue"
+	assert.ErrorContains(t, tenant.Validate(), "invalid branding color")
+
+	tenant.Branding.SecondaryColor = ""
+	tenant.Branding.SupportEmail = "ayuda"
+	assert.ErrorContains(t, tenant.Validate(), "invalid branding support email")
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload session statuses for direct-to-storage uploads
+const (
+	UploadStatusPending    = "pending"
+	UploadStatusProcessing = "processing"
+	UploadStatusCompleted  = "completed"
+	UploadStatusFailed     = "failed"
+	UploadStatusExpired    = "expired"
+)
+
+// DefaultUploadURLTTL is how long a presigned upload URL stays valid
+const DefaultUploadURLTTL = 15 * time.Minute
+
+// UploadSession tracks an image uploaded by the client straight to object storage.
+// The session is created with a presigned URL and confirmed once the upload finished.
+type UploadSession struct {
+	ID          string    `json:"id"`
+	PropertyID  string    `json:"property_id"`
+	ObjectKey   string    `json:"object_key"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	AltText     string    `json:"alt_text"`
+	Status      string    `json:"status"`
+	ImageID     string    `json:"image_id,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	UploadURL   string    `json:"upload_url,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewUploadSession creates a pending upload session with a unique object key
+func NewUploadSession(propertyID, fileName, contentType, altText string, size int64, ttl time.Duration) *UploadSession {
+	id := uuid.New().String()
+	now := time.Now()
+	return &UploadSession{
+		ID:          id,
+		PropertyID:  propertyID,
+		ObjectKey:   fmt.Sprintf("uploads/%s/%s%s", propertyID, id, filepath.Ext(fileName)),
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		AltText:     altText,
+		Status:      UploadStatusPending,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsExpired checks if the upload window has closed
+func (s *UploadSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// MarkProcessing marks the uploaded object as accepted and queued for processing
+func (s *UploadSession) MarkProcessing(size int64) {
+	s.Size = size
+	s.Status = UploadStatusProcessing
+	s.UpdatedAt = time.Now()
+}
+
+// MarkCompleted records the image created from the upload
+func (s *UploadSession) MarkCompleted(imageID string) {
+	s.ImageID = imageID
+	s.Status = UploadStatusCompleted
+	s.Error = ""
+	s.UpdatedAt = time.Now()
+}
+
+// MarkFailed records why the upload could not be processed
+func (s *UploadSession) MarkFailed(reason string) {
+	s.Status = UploadStatusFailed
+	s.Error = reason
+	s.UpdatedAt = time.Now()
+}
@@ -51,6 +51,7 @@ type User struct {
 	ReceiveNotifications    bool       `json:"receive_notifications" db:"receive_notifications"`
 	ReceiveNewsletter       bool       `json:"receive_newsletter" db:"receive_newsletter"`
 	AgencyID                *string    `json:"agency_id" db:"agency_id"`
+	PhoneVerifiedAt         *time.Time `json:"phone_verified_at,omitempty" db:"phone_verified_at"`
 	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt               time.Time  `json:"updated_at" db:"updated_at"`
 	
@@ -64,6 +65,9 @@ type User struct {
 	LastLoginAt             *time.Time `json:"last_login_at"`
 	DeletedAt               *time.Time `json:"deleted_at"`
 	Status                  UserStatus `json:"status"`
+
+	// Square sizes of the avatar, derived from AvatarURL by ProfileImageVariants
+	AvatarVariants map[string]string `json:"avatar_variants,omitempty"`
 }
 
 // NewUser creates a new user with validation
@@ -155,6 +159,40 @@ func (u *User) SetAgency(agencyID string) error {
 	return nil
 }
 
+// JoinAgency makes the user an agent of an agency, e.g. when accepting an invitation.
+// Agencies and admins cannot join, and agents must leave their current agency first.
+func (u *User) JoinAgency(agencyID string) error {
+	if agencyID == "" {
+		return fmt.Errorf("agency ID cannot be empty")
+	}
+
+	switch u.Role {
+	case RoleAdmin, RoleAgency:
+		return fmt.Errorf("%s users cannot join an agency", u.Role)
+	case RoleAgent:
+		if u.AgencyID != nil && *u.AgencyID != agencyID {
+			return fmt.Errorf("user already belongs to an agency")
+		}
+	}
+
+	u.Role = RoleAgent
+	u.AgencyID = &agencyID
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// LeaveAgency removes an agent from its agency; the user keeps a buyer account
+func (u *User) LeaveAgency() error {
+	if u.Role != RoleAgent || u.AgencyID == nil {
+		return fmt.Errorf("user is not an agency member")
+	}
+
+	u.Role = RoleBuyer
+	u.AgencyID = nil
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
 // Activate sets the user status to active
 func (u *User) Activate() error {
 	if u.Status == StatusSuspended {
@@ -187,6 +225,44 @@ func (u *User) UpdateLastLogin() {
 	u.UpdatedAt = now
 }
 
+// SetAvatar updates the avatar of the user; an empty URL removes it
+func (u *User) SetAvatar(avatarURL string) {
+	if avatarURL == "" {
+		u.AvatarURL = nil
+	} else {
+		u.AvatarURL = &avatarURL
+	}
+	u.AvatarVariants = ProfileImageVariants(u.AvatarURL)
+	u.UpdatedAt = time.Now()
+}
+
+// SetPhone changes the phone number of the user. A different number has to be
+// verified again.
+func (u *User) SetPhone(phone string) {
+	phone = strings.TrimSpace(phone)
+	if u.Phone != nil && normalizePhone(*u.Phone) == normalizePhone(phone) {
+		return
+	}
+
+	if phone == "" {
+		u.Phone = nil
+	} else {
+		u.Phone = &phone
+	}
+	u.PhoneVerifiedAt = nil
+}
+
+// IsPhoneVerified checks if the user verified their phone number
+func (u *User) IsPhoneVerified() bool {
+	return u.Phone != nil && u.PhoneVerifiedAt != nil
+}
+
+// RequiresPhoneVerification checks if the user must verify their phone number to
+// publish listings
+func (u *User) RequiresPhoneVerification() bool {
+	return u.Role == RoleAgent || u.Role == RoleOwner || u.Role == RoleAgency
+}
+
 // CanManageProperty checks if user can manage a specific property
 func (u *User) CanManageProperty(propertyOwnerID string, propertyAgencyID *string) bool {
 	switch u.Role {
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Notification channels
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+// Email frequencies. Instant emails are sent as events happen; daily and weekly ones
+// are grouped into digests, and never turns off non-essential email. Security and
+// account emails are always sent.
+const (
+	EmailFrequencyInstant = "instant"
+	EmailFrequencyDaily   = "daily"
+	EmailFrequencyWeekly  = "weekly"
+	EmailFrequencyNever   = "never"
+)
+
+// NotificationChannels holds the channels a user accepts notifications on
+type NotificationChannels struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+	Push  bool `json:"push"`
+}
+
+// SearchDefaults are the filters applied to a user's searches that do not set them
+type SearchDefaults struct {
+	Provinces     []string `json:"provinces"`
+	PropertyTypes []string `json:"property_types"`
+	MinPrice      *float64 `json:"min_price,omitempty"`
+	MaxPrice      *float64 `json:"max_price,omitempty"`
+}
+
+// UserPreferences holds how a user wants to be notified and to search
+type UserPreferences struct {
+	UserID         string               `json:"user_id"`
+	Channels       NotificationChannels `json:"channels"`
+	EmailFrequency string               `json:"email_frequency"`
+	Locale         string               `json:"locale"`
+	SearchDefaults SearchDefaults       `json:"search_defaults"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// DefaultUserPreferences returns the preferences of a user who never saved any, taken
+// from the notification and search fields of their profile
+func DefaultUserPreferences(user *User) *UserPreferences {
+	prefs := &UserPreferences{
+		UserID:         user.ID,
+		Channels:       NotificationChannels{Email: user.ReceiveNotifications},
+		EmailFrequency: EmailFrequencyInstant,
+		Locale:         DefaultLocale,
+		SearchDefaults: SearchDefaults{
+			Provinces:     append([]string{}, user.PreferredProvinces...),
+			PropertyTypes: append([]string{}, user.PreferredPropertyTypes...),
+			MinPrice:      user.MinBudget,
+			MaxPrice:      user.MaxBudget,
+		},
+		UpdatedAt: user.UpdatedAt,
+	}
+	if !user.ReceiveNotifications {
+		prefs.EmailFrequency = EmailFrequencyNever
+	}
+	return prefs
+}
+
+// Normalize trims and canonicalizes the preferences before validation
+func (p *UserPreferences) Normalize() {
+	p.EmailFrequency = strings.ToLower(strings.TrimSpace(p.EmailFrequency))
+	if p.EmailFrequency == "" {
+		p.EmailFrequency = EmailFrequencyInstant
+	}
+	if locale := NormalizeLocale(p.Locale); locale != "" {
+		p.Locale = locale
+	} else if strings.TrimSpace(p.Locale) == "" {
+		p.Locale = DefaultLocale
+	}
+
+	for i, province := range p.SearchDefaults.Provinces {
+		p.SearchDefaults.Provinces[i] = strings.TrimSpace(province)
+	}
+	for i, propertyType := range p.SearchDefaults.PropertyTypes {
+		p.SearchDefaults.PropertyTypes[i] = strings.ToLower(strings.TrimSpace(propertyType))
+	}
+	if p.SearchDefaults.Provinces == nil {
+		p.SearchDefaults.Provinces = []string{}
+	}
+	if p.SearchDefaults.PropertyTypes == nil {
+		p.SearchDefaults.PropertyTypes = []string{}
+	}
+}
+
+// Validate checks the preferences
+func (p *UserPreferences) Validate() error {
+	switch p.EmailFrequency {
+	case EmailFrequencyInstant, EmailFrequencyDaily, EmailFrequencyWeekly, EmailFrequencyNever:
+	default:
+		return fmt.Errorf("email frequency must be instant, daily, weekly or never")
+	}
+	if !IsSupportedLocale(p.Locale) {
+		return fmt.Errorf("unsupported locale: %s", p.Locale)
+	}
+
+	for _, province := range p.SearchDefaults.Provinces {
+		if !IsValidProvince(province) {
+			return fmt.Errorf("invalid province: %s", province)
+		}
+	}
+	for _, propertyType := range p.SearchDefaults.PropertyTypes {
+		if !IsValidPropertyType(propertyType) {
+			return fmt.Errorf("invalid property type: %s", propertyType)
+		}
+	}
+
+	minPrice, maxPrice := p.SearchDefaults.MinPrice, p.SearchDefaults.MaxPrice
+	if (minPrice != nil && *minPrice < 0) || (maxPrice != nil && *maxPrice < 0) {
+		return fmt.Errorf("price band must not be negative")
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		return fmt.Errorf("minimum price cannot exceed maximum price")
+	}
+	return nil
+}
+
+// AllowsChannel reports whether the user accepts notifications on a channel
+func (p *UserPreferences) AllowsChannel(channel string) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.Channels.Email && p.EmailFrequency != EmailFrequencyNever
+	case ChannelSMS:
+		return p.Channels.SMS
+	case ChannelPush:
+		return p.Channels.Push
+	default:
+		return false
+	}
+}
+
+// ApplySearchDefaults fills the filters a search left unset with the user's defaults.
+// A search that sets provinces, types or a price keeps its own values for them.
+func (p *UserPreferences) ApplySearchDefaults(filters *PropertySearchFilters) {
+	defaults := p.SearchDefaults
+	if len(filters.Provinces) == 0 && len(defaults.Provinces) > 0 {
+		filters.Provinces = append([]string{}, defaults.Provinces...)
+	}
+	if len(filters.PropertyTypes) == 0 && len(defaults.PropertyTypes) > 0 {
+		filters.PropertyTypes = append([]string{}, defaults.PropertyTypes...)
+	}
+	if filters.MinPrice == nil && filters.MaxPrice == nil {
+		filters.MinPrice = defaults.MinPrice
+		filters.MaxPrice = defaults.MaxPrice
+	}
+}
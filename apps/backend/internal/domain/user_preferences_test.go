@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserPreferences_Validate(t *testing.T) {
+	minPrice, maxPrice := 200000.0, 100000.0
+
+	tests := []struct {
+		name    string
+		prefs   UserPreferences
+		wantErr string
+	}{
+		{"defaults", UserPreferences{}, ""},
+		{"unknown frequency", UserPreferences{EmailFrequency: "hourly"}, "email frequency"},
+		{"unsupported locale", UserPreferences{Locale: "fr"}, "unsupported locale"},
+		{"invalid province", UserPreferences{SearchDefaults: SearchDefaults{Provinces: []string{"Lima"}}}, "invalid province"},
+		{"invalid type", UserPreferences{SearchDefaults: SearchDefaults{PropertyTypes: []string{"castle"}}}, "invalid property type"},
+		{"inverted price band", UserPreferences{SearchDefaults: SearchDefaults{MinPrice: &minPrice, MaxPrice: &maxPrice}}, "minimum price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prefs.Normalize()
+			err := tt.prefs.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUserPreferences_AllowsChannel(t *testing.T) {
+	prefs := &UserPreferences{Channels: NotificationChannels{Email: true, Push: true}, EmailFrequency: EmailFrequencyDaily}
+	assert.True(t, prefs.AllowsChannel(ChannelEmail))
+	assert.True(t, prefs.AllowsChannel(ChannelPush))
+	assert.False(t, prefs.AllowsChannel(ChannelSMS))
+
+	prefs.EmailFrequency = EmailFrequencyNever
+	assert.False(t, prefs.AllowsChannel(ChannelEmail))
+}
+
+func TestDefaultUserPreferences(t *testing.T) {
+	prefs := DefaultUserPreferences(&User{ID: "user-1", PreferredPropertyTypes: []string{"house"}})
+	assert.False(t, prefs.Channels.Email)
+	assert.Equal(t, EmailFrequencyNever, prefs.EmailFrequency)
+	assert.Equal(t, DefaultLocale, prefs.Locale)
+	assert.Equal(t, []string{"house"}, prefs.SearchDefaults.PropertyTypes)
+}
@@ -279,6 +279,62 @@ func TestUserSetAgency(t *testing.T) {
 	}
 }
 
+func TestUserJoinAndLeaveAgency(t *testing.T) {
+	agencyID := uuid.New().String()
+	otherAgencyID := uuid.New().String()
+
+	tests := []struct {
+		name        string
+		user        *User
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:        "buyer becomes agent",
+			user:        &User{ID: uuid.New().String(), Role: RoleBuyer},
+			expectError: false,
+		},
+		{
+			name:        "agent without agency joins",
+			user:        &User{ID: uuid.New().String(), Role: RoleAgent},
+			expectError: false,
+		},
+		{
+			name:        "agent of another agency",
+			user:        &User{ID: uuid.New().String(), Role: RoleAgent, AgencyID: &otherAgencyID},
+			expectError: true,
+			errorMsg:    "already belongs to an agency",
+		},
+		{
+			name:        "agency users cannot join",
+			user:        &User{ID: uuid.New().String(), Role: RoleAgency},
+			expectError: true,
+			errorMsg:    "cannot join an agency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.user.JoinAgency(agencyID)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, RoleAgent, tt.user.Role)
+			assert.Equal(t, agencyID, *tt.user.AgencyID)
+
+			assert.NoError(t, tt.user.LeaveAgency())
+			assert.Equal(t, RoleBuyer, tt.user.Role)
+			assert.Nil(t, tt.user.AgencyID)
+			assert.Error(t, tt.user.LeaveAgency())
+		})
+	}
+}
+
 func TestUserStatusManagement(t *testing.T) {
 	user := &User{
 		ID:     uuid.New().String(),
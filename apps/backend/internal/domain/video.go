@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Video processing statuses
+const (
+	VideoStatusProcessing = "processing"
+	VideoStatusReady      = "ready"
+	VideoStatusFailed     = "failed"
+)
+
+// Video limits
+const (
+	MaxVideoUploadSize   = 500 * 1024 * 1024 // 500MB
+	MaxVideosPerProperty = 5
+	MaxVideoDuration     = 10 * time.Minute
+)
+
+// SupportedVideoMimeTypes maps accepted upload content types to file extensions
+var SupportedVideoMimeTypes = map[string]string{
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+	"video/x-msvideo": ".avi",
+}
+
+// VideoRenditionPreset describes an output rendition produced by transcoding
+type VideoRenditionPreset struct {
+	Name         string `json:"name"`
+	Height       int    `json:"height"`
+	VideoBitrate int    `json:"video_bitrate_kbps"`
+	AudioBitrate int    `json:"audio_bitrate_kbps"`
+}
+
+// DefaultVideoRenditions are the HLS ladder used for property tours
+var DefaultVideoRenditions = []VideoRenditionPreset{
+	{Name: "1080p", Height: 1080, VideoBitrate: 5000, AudioBitrate: 128},
+	{Name: "720p", Height: 720, VideoBitrate: 2800, AudioBitrate: 128},
+	{Name: "480p", Height: 480, VideoBitrate: 1400, AudioBitrate: 96},
+}
+
+// VideoRendition is a transcoded output of a video
+type VideoRendition struct {
+	Name      string `json:"name"`
+	Format    string `json:"format"` // hls, mp4
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Bandwidth int    `json:"bandwidth"` // bits per second
+	URL       string `json:"url"`
+	Path      string `json:"-"`
+}
+
+// PropertyVideo is a video tour uploaded for a property
+type PropertyVideo struct {
+	ID              string           `json:"id"`
+	PropertyID      string           `json:"property_id"`
+	FileName        string           `json:"file_name"`
+	ContentType     string           `json:"content_type"`
+	Size            int64            `json:"size"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Status          string           `json:"status"`
+	Renditions      []VideoRendition `json:"renditions"`
+	ThumbnailURL    string           `json:"thumbnail_url,omitempty"`
+	StoragePrefix   string           `json:"-"`
+	StoredPaths     []string         `json:"-"`
+	Error           string           `json:"error,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// NewPropertyVideo creates a video in processing state
+func NewPropertyVideo(propertyID, fileName, contentType string, size int64) *PropertyVideo {
+	id := uuid.New().String()
+	now := time.Now()
+	return &PropertyVideo{
+		ID:            id,
+		PropertyID:    propertyID,
+		FileName:      fileName,
+		ContentType:   contentType,
+		Size:          size,
+		Status:        VideoStatusProcessing,
+		Renditions:    []VideoRendition{},
+		StoragePrefix: fmt.Sprintf("videos/%s/%s", propertyID, id),
+		StoredPaths:   []string{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// MarkReady records the transcoding outputs
+func (v *PropertyVideo) MarkReady(duration float64, renditions []VideoRendition, thumbnailURL string) {
+	v.DurationSeconds = duration
+	v.Renditions = renditions
+	v.ThumbnailURL = thumbnailURL
+	v.Status = VideoStatusReady
+	v.Error = ""
+	v.UpdatedAt = time.Now()
+}
+
+// MarkFailed records why transcoding failed
+func (v *PropertyVideo) MarkFailed(reason string) {
+	v.Status = VideoStatusFailed
+	v.Error = reason
+	v.UpdatedAt = time.Now()
+}
+
+// HLSRenditions returns the HLS renditions ordered as stored (highest quality first)
+func (v *PropertyVideo) HLSRenditions() []VideoRendition {
+	renditions := []VideoRendition{}
+	for _, rendition := range v.Renditions {
+		if rendition.Format == "hls" {
+			renditions = append(renditions, rendition)
+		}
+	}
+	return renditions
+}
+
+// ValidateVideoUpload validates the name, content type and size of a video upload
+func ValidateVideoUpload(fileName, contentType string, size, maxSize int64) error {
+	if size <= 0 {
+		return fmt.Errorf("file is empty")
+	}
+	if size > maxSize {
+		return fmt.Errorf("file too large: %d bytes, max: %d bytes", size, maxSize)
+	}
+
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if _, ok := SupportedVideoMimeTypes[contentType]; !ok {
+		return fmt.Errorf("unsupported video type: %s", contentType)
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, supported := range SupportedVideoMimeTypes {
+		if ext == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported file extension: %s", ext)
+}
@@ -0,0 +1,358 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	EventPropertyCreated = "property.created"
+	EventPropertyUpdated = "property.updated"
+	EventPropertySold    = "property.sold"
+	EventPropertyRented  = "property.rented"
+	// EventPropertyStatusChanged is sent for every listing status transition
+	EventPropertyStatusChanged = "property.status_changed"
+	// EventInquiryCreated is sent when a prospective tenant applies to rent a listing
+	EventInquiryCreated = "inquiry.created"
+)
+
+// WebhookEventTypes lists the events subscriptions can receive
+var WebhookEventTypes = []string{
+	EventPropertyCreated,
+	EventPropertyUpdated,
+	EventPropertySold,
+	EventPropertyRented,
+	EventPropertyStatusChanged,
+	EventInquiryCreated,
+}
+
+// EventImageProcessed is published on the event bus when an uploaded image has been
+// optimized and stored; webhooks do not carry it
+const EventImageProcessed = "image.processed"
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // gave up after the last attempt
+)
+
+// Webhook limits
+const (
+	DefaultWebhookMaxAttempts = 8
+	MaxWebhookURLLength       = 500
+	MaxWebhookErrorLength     = 500
+	webhookBaseRetryDelay     = 30 * time.Second
+	webhookMaxRetryDelay      = 6 * time.Hour
+)
+
+// WebhookSignatureHeader carries the HMAC signature of a delivery as t=<unix>,v1=<hex>
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// WebhookSubscription pushes events to a third-party URL, typically an agency's CRM.
+// Agency subscriptions receive the events of the agency's listings; subscriptions
+// without an agency, created by admins, receive every event.
+type WebhookSubscription struct {
+	ID          string    `json:"id"`
+	AgencyID    *string   `json:"agency_id,omitempty"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"-"` // signs deliveries; only returned when created
+	EventTypes  []string  `json:"event_types"`
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedBy   string    `json:"created_by,omitempty"` // empty once the creator was purged
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookEvent is the JSON body of a delivery
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookDelivery is one event queued for one subscription, retried with exponential
+// backoff until it is delivered or runs out of attempts
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// NewWebhookSubscription creates an active subscription with a random signing secret
+func NewWebhookSubscription(rawURL string, eventTypes []string, agencyID *string, createdBy string) (*WebhookSubscription, error) {
+	secret, err := GenerateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription := &WebhookSubscription{
+		ID:         uuid.New().String(),
+		AgencyID:   agencyID,
+		URL:        strings.TrimSpace(rawURL),
+		Secret:     secret,
+		EventTypes: NormalizeWebhookEventTypes(eventTypes),
+		Active:     true,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// Validate verifies the subscription URL and event types
+func (s *WebhookSubscription) Validate() error {
+	if err := ValidateWebhookURL(s.URL); err != nil {
+		return err
+	}
+	if len(s.EventTypes) == 0 {
+		return fmt.Errorf("invalid event types: subscribe to at least one of %s", strings.Join(WebhookEventTypes, ", "))
+	}
+	for _, eventType := range s.EventTypes {
+		if !IsValidWebhookEventType(eventType) {
+			return fmt.Errorf("invalid event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// Receives verifies if the subscription gets events of the given type
+func (s *WebhookSubscription) Receives(eventType string) bool {
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhookURL verifies a webhook URL is an absolute HTTPS URL that does not
+// point into the network the API runs in. Host names are checked again on delivery,
+// after they are resolved.
+func ValidateWebhookURL(rawURL string) error {
+	if rawURL == "" || len(rawURL) > MaxWebhookURLLength {
+		return fmt.Errorf("invalid webhook URL: must be 1-%d characters", MaxWebhookURLLength)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return fmt.Errorf("invalid webhook URL: must be an absolute https:// URL")
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("invalid webhook URL: credentials are not allowed, verify the signature instead")
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("invalid webhook URL: internal addresses are not allowed")
+	}
+	if ip := net.ParseIP(host); ip != nil && IsInternalIP(ip) {
+		return fmt.Errorf("invalid webhook URL: internal addresses are not allowed")
+	}
+	return nil
+}
+
+// internalNetworks are the ranges, beyond the private ones, that webhooks may not reach:
+// "this network", carrier-grade NAT (where some clouds serve instance metadata),
+// IETF protocol assignments and benchmarking
+var internalNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{"0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// IsInternalIP reports whether an address belongs to the network the API runs in:
+// loopback, private, link-local (including the 169.254.169.254 metadata endpoint),
+// unspecified, multicast and other non-public ranges
+func IsInternalIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidWebhookEventType verifies if subscriptions can receive an event type
+func IsValidWebhookEventType(eventType string) bool {
+	for _, valid := range WebhookEventTypes {
+		if eventType == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeWebhookEventTypes lowercases event types and drops duplicates
+func NormalizeWebhookEventTypes(eventTypes []string) []string {
+	normalized := make([]string, 0, len(eventTypes))
+	seen := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		normalized = append(normalized, eventType)
+	}
+	return normalized
+}
+
+// GenerateWebhookSecret returns a random signing secret
+func GenerateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// SignWebhookPayload returns the signature header value of a delivery body. Receivers
+// recompute HMAC-SHA256 of "<t>.<body>" with their secret and compare it with v1,
+// rejecting old timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature header made with SignWebhookPayload by a
+// sender sharing the secret, rejecting timestamps further than tolerance from now
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return fmt.Errorf("invalid signature: expected t=<unix>,v1=<hex>")
+	}
+	signedAt := time.Unix(unix, 0)
+	if signedAt.Before(now.Add(-tolerance)) || signedAt.After(now.Add(tolerance)) {
+		return fmt.Errorf("invalid signature: timestamp outside the %s tolerance", tolerance)
+	}
+
+	expected := SignWebhookPayload(secret, signedAt, body)
+	if !hmac.Equal([]byte(expected), []byte("t="+timestamp+",v1="+signature)) {
+		return fmt.Errorf("invalid signature: does not match the payload")
+	}
+	return nil
+}
+
+// NewWebhookDeliveries queues an event for each subscription, due immediately
+func NewWebhookDeliveries(eventType string, data interface{}, subscriptions []WebhookSubscription) ([]WebhookDelivery, error) {
+	event := WebhookEvent{ID: uuid.New().String(), Type: eventType, CreatedAt: time.Now(), Data: data}
+	return NewWebhookEventDeliveries(event, subscriptions)
+}
+
+// NewWebhookEventDeliveries queues an event with a known ID, such as an outbox event,
+// for each subscription. Deliveries of the same event to a subscription are stored once.
+func NewWebhookEventDeliveries(event WebhookEvent, subscriptions []WebhookSubscription) ([]WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	now := time.Now()
+	deliveries := make([]WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, WebhookDelivery{
+			ID:             uuid.New().String(),
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+		})
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt updates the delivery after an attempt. Failed attempts are retried
+// after 30s, 1m, 2m... up to 6h between attempts, until maxAttempts is reached.
+func (d *WebhookDelivery) RecordAttempt(statusCode int, attemptErr error, at time.Time, maxAttempts int) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+
+	if attemptErr == nil && statusCode >= 200 && statusCode < 300 {
+		d.Status = WebhookDeliveryDelivered
+		d.LastError = ""
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &at
+		return
+	}
+
+	if attemptErr != nil {
+		d.LastError = attemptErr.Error()
+	} else {
+		d.LastError = fmt.Sprintf("unexpected status code %d", statusCode)
+	}
+	if len(d.LastError) > MaxWebhookErrorLength {
+		d.LastError = d.LastError[:MaxWebhookErrorLength]
+	}
+
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryFailed
+		d.NextAttemptAt = nil
+		return
+	}
+	next := at.Add(WebhookRetryDelay(d.Attempts))
+	d.NextAttemptAt = &next
+}
+
+// WebhookRetryDelay returns the wait before the attempt following the given number of
+// failed attempts
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookSubscription(t *testing.T) {
+	subscription, err := NewWebhookSubscription("https://crm.example.com/hooks", []string{"Property.Created", "property.created", "inquiry.created"}, nil, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{EventPropertyCreated, EventInquiryCreated}, subscription.EventTypes)
+	assert.Contains(t, subscription.Secret, "whsec_")
+	assert.True(t, subscription.Receives(EventInquiryCreated))
+	assert.False(t, subscription.Receives(EventPropertySold))
+
+	_, err = NewWebhookSubscription("http://crm.example.com/hooks", []string{EventPropertyCreated}, nil, "admin-1")
+	assert.ErrorContains(t, err, "https://")
+
+	_, err = NewWebhookSubscription("https://user:pw@crm.example.com", []string{EventPropertyCreated}, nil, "admin-1")
+	assert.ErrorContains(t, err, "credentials")
+
+	for _, internal := range []string{"https://localhost/hooks", "https://127.0.0.1:8080", "https://10.0.0.5",
+		"https://169.254.169.254/latest/meta-data", "https://[::1]/hooks", "https://[fd00:ec2::254]", "https://100.100.100.200"} {
+		_, err = NewWebhookSubscription(internal, []string{EventPropertyCreated}, nil, "admin-1")
+		assert.ErrorContains(t, err, "internal addresses", internal)
+	}
+
+	_, err = NewWebhookSubscription("https://crm.example.com/hooks", []string{"property.deleted"}, nil, "admin-1")
+	assert.ErrorContains(t, err, "invalid event type")
+
+	_, err = NewWebhookSubscription("https://crm.example.com/hooks", nil, nil, "admin-1")
+	assert.ErrorContains(t, err, "invalid event types")
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	timestamp := time.Unix(1755388800, 0)
+	signature := SignWebhookPayload("whsec_test", timestamp, []byte(`{"id":"1"}`))
+	assert.Equal(t, "t=1755388800,v1=", signature[:16])
+	assert.Len(t, signature, 16+64)
+	assert.Equal(t, signature, SignWebhookPayload("whsec_test", timestamp, []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_other", timestamp, []byte(`{"id":"1"}`)))
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	now := time.Unix(1755388800, 0)
+	body := []byte(`{"id":"1"}`)
+	header := SignWebhookPayload("whsec_test", now, body)
+
+	assert.NoError(t, VerifyWebhookSignature("whsec_test", header, body, now.Add(time.Minute), 5*time.Minute))
+	assert.ErrorContains(t, VerifyWebhookSignature("whsec_other", header, body, now, 5*time.Minute), "does not match")
+	assert.ErrorContains(t, VerifyWebhookSignature("whsec_test", header, []byte(`{"id":"2"}`), now, 5*time.Minute), "does not match")
+	assert.ErrorContains(t, VerifyWebhookSignature("whsec_test", header, body, now.Add(time.Hour), 5*time.Minute), "tolerance")
+	assert.ErrorContains(t, VerifyWebhookSignature("whsec_test", "v1=abc", body, now, 5*time.Minute), "invalid signature")
+}
+
+func TestWebhookDelivery_RecordAttempt(t *testing.T) {
+	subscriptions := []WebhookSubscription{{ID: "sub-1"}, {ID: "sub-2"}}
+	deliveries, err := NewWebhookDeliveries(EventPropertySold, map[string]string{"property_id": "p-1"}, subscriptions)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, deliveries[0].EventID, deliveries[1].EventID, "one event fans out to every subscription")
+	assert.Contains(t, string(deliveries[0].Payload), `"type":"property.sold"`)
+
+	delivery := deliveries[0]
+	now := time.Now()
+	delivery.RecordAttempt(500, nil, now, 3)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, now.Add(30*time.Second), *delivery.NextAttemptAt)
+	assert.Equal(t, "unexpected status code 500", delivery.LastError)
+
+	delivery.RecordAttempt(0, errors.New("connection refused"), now, 3)
+	assert.Equal(t, now.Add(time.Minute), *delivery.NextAttemptAt)
+
+	delivery.RecordAttempt(0, errors.New("connection refused"), now, 3)
+	assert.Equal(t, WebhookDeliveryFailed, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	delivery = deliveries[1]
+	delivery.RecordAttempt(204, nil, now, 3)
+	assert.Equal(t, WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, now, *delivery.DeliveredAt)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, WebhookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, WebhookRetryDelay(3))
+	assert.Equal(t, 6*time.Hour, WebhookRetryDelay(20))
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Widget limits
+const (
+	DefaultWidgetListings = 12
+	MaxWidgetListings     = 50
+	MaxWidgetOrigins      = 10
+)
+
+// WidgetTokenClaims are what an embed token grants: the listings of one agency, from the
+// sites of the given origins, until it expires. Tokens are public, as they are in the
+// embed code of the agency's site; they keep the widget endpoint from serving the
+// listings of other agencies.
+type WidgetTokenClaims struct {
+	AgencyID  string   `json:"agency_id"`
+	Origins   []string `json:"origins,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// AllowsOrigin reports whether a browser request from origin may use the token. Tokens
+// without origins allow every site; requests without an Origin header are not made by
+// the scripts of other sites and are allowed.
+func (c *WidgetTokenClaims) AllowsOrigin(origin string) bool {
+	if len(c.Origins) == 0 || origin == "" {
+		return true
+	}
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	for _, allowed := range c.Origins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeWidgetOrigins validates the origins of the sites a widget is embedded in,
+// e.g. https://www.inmobiliaria.ec, and returns them lowercased without duplicates
+func NormalizeWidgetOrigins(origins []string) ([]string, error) {
+	if len(origins) > MaxWidgetOrigins {
+		return nil, fmt.Errorf("invalid origins: at most %d", MaxWidgetOrigins)
+	}
+
+	normalized := make([]string, 0, len(origins))
+	seen := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+			return nil, fmt.Errorf("invalid origin %q: expected scheme://host[:port]", origin)
+		}
+		if !seen[origin] {
+			seen[origin] = true
+			normalized = append(normalized, origin)
+		}
+	}
+	return normalized, nil
+}
+
+// SignWidgetToken encodes claims into an embed token, signed with HMAC-SHA256 as
+// base64url(claims).base64url(signature)
+func SignWidgetToken(secret string, claims WidgetTokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode widget token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(widgetTokenSignature(secret, encoded)), nil
+}
+
+// ParseWidgetToken verifies an embed token made with SignWidgetToken and returns its
+// claims, rejecting tokens that expired by now
+func ParseWidgetToken(secret, token string, now time.Time) (*WidgetTokenClaims, error) {
+	encoded, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid widget token: malformed")
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, widgetTokenSignature(secret, encoded)) {
+		return nil, fmt.Errorf("invalid widget token: bad signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid widget token: malformed")
+	}
+	var claims WidgetTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.AgencyID == "" {
+		return nil, fmt.Errorf("invalid widget token: malformed")
+	}
+	if claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("invalid widget token: expired")
+	}
+	return &claims, nil
+}
+
+func widgetTokenSignature(secret, encodedClaims string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("widget."))
+	mac.Write([]byte(encodedClaims))
+	return mac.Sum(nil)
+}
+
+// WidgetAgency is the agency heading an embedded widget
+type WidgetAgency struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	LogoURL *string `json:"logo_url,omitempty"`
+}
+
+// WidgetListing is the compact view of a listing shown in an embedded widget: enough
+// for a card linking to the listing
+type WidgetListing struct {
+	ID        string   `json:"id"`
+	Slug      string   `json:"slug"`
+	Title     string   `json:"title"`
+	Price     float64  `json:"price"`
+	RentPrice *float64 `json:"rent_price,omitempty"`
+	Type      string   `json:"type"`
+	Status    string   `json:"status"`
+	City      string   `json:"city"`
+	Province  string   `json:"province"`
+	Sector    *string  `json:"sector,omitempty"`
+	Bedrooms  int      `json:"bedrooms"`
+	Bathrooms float32  `json:"bathrooms"`
+	AreaM2    float64  `json:"area_m2"`
+	Featured  bool     `json:"featured"`
+	Image     *string  `json:"image,omitempty"`
+}
+
+// NewWidgetListing returns the widget view of a property
+func NewWidgetListing(p *Property) WidgetListing {
+	return WidgetListing{
+		ID:        p.ID,
+		Slug:      p.Slug,
+		Title:     p.Title,
+		Price:     p.Price,
+		RentPrice: p.RentPrice,
+		Type:      p.Type,
+		Status:    p.Status,
+		City:      p.City,
+		Province:  p.Province,
+		Sector:    p.Sector,
+		Bedrooms:  p.Bedrooms,
+		Bathrooms: p.Bathrooms,
+		AreaM2:    p.AreaM2,
+		Featured:  p.Featured,
+		Image:     p.MainImage,
+	}
+}
+
+// WidgetPayload is the response of the widget endpoint
+type WidgetPayload struct {
+	Agency   WidgetAgency    `json:"agency"`
+	Listings []WidgetListing `json:"listings"`
+	Total    int             `json:"total"`
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWidgetToken_SignAndParse(t *testing.T) {
+	now := time.Date(2025, 9, 12, 10, 0, 0, 0, time.UTC)
+	claims := WidgetTokenClaims{
+		AgencyID:  "agency-1",
+		Origins:   []string{"https://www.inmobiliaria.ec"},
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+	token, err := SignWidgetToken("secret", claims)
+	require.NoError(t, err)
+
+	parsed, err := ParseWidgetToken("secret", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *parsed)
+
+	_, err = ParseWidgetToken("other-secret", token, now)
+	assert.ErrorContains(t, err, "bad signature")
+	_, err = ParseWidgetToken("secret", token, now.Add(time.Hour))
+	assert.ErrorContains(t, err, "expired")
+	_, err = ParseWidgetToken("secret", "not-a-token", now)
+	assert.ErrorContains(t, err, "malformed")
+
+	// Claims cannot be swapped for those of another agency
+	forged, err := SignWidgetToken("attacker", WidgetTokenClaims{AgencyID: "agency-2"})
+	require.NoError(t, err)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	_, err = ParseWidgetToken("secret", payload+"."+signature, now)
+	assert.ErrorContains(t, err, "bad signature")
+}
+
+func TestWidgetTokenClaims_AllowsOrigin(t *testing.T) {
+	claims := WidgetTokenClaims{AgencyID: "agency-1", Origins: []string{"https://www.inmobiliaria.ec"}}
+	assert.True(t, claims.AllowsOrigin("https://www.inmobiliaria.ec"))
+	assert.True(t, claims.AllowsOrigin("HTTPS://WWW.INMOBILIARIA.EC/"))
+	assert.True(t, claims.AllowsOrigin(""), "requests without Origin are not made from other sites")
+	assert.False(t, claims.AllowsOrigin("https://scraper.example"))
+
+	assert.True(t, (&WidgetTokenClaims{AgencyID: "agency-1"}).AllowsOrigin("https://scraper.example"))
+}
+
+func TestNormalizeWidgetOrigins(t *testing.T) {
+	origins, err := NormalizeWidgetOrigins([]string{" https://WWW.Inmobiliaria.ec/ ", "https://www.inmobiliaria.ec", "http://localhost:3000"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://www.inmobiliaria.ec", "http://localhost:3000"}, origins)
+
+	for _, origin := range []string{"www.inmobiliaria.ec", "ftp://inmobiliaria.ec", "https://inmobiliaria.ec/casas", "https://user@inmobiliaria.ec"} {
+		_, err := NormalizeWidgetOrigins([]string{origin})
+		assert.ErrorContains(t, err, "invalid origin", origin)
+	}
+}
@@ -0,0 +1,214 @@
+package einvoice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultHTTPTimeout = 15 * time.Second
+
+// Supported providers
+const (
+	ProviderNone = "none"
+	ProviderLog  = "log"
+	ProviderHTTP = "http"
+)
+
+// Authorization statuses reported by the SRI for a submitted document
+const (
+	StatusAuthorized    = "AUTORIZADO"
+	StatusNotAuthorized = "NO AUTORIZADO"
+	StatusProcessing    = "EN PROCESO"
+)
+
+// Reception is the answer of the SRI reception service to a submitted document
+type Reception struct {
+	// Received is false when the document was returned (DEVUELTA) and must be fixed
+	Received bool
+	Messages []string
+}
+
+// Authorization is the answer of the SRI authorization service for an access key
+type Authorization struct {
+	Status       string
+	Number       string
+	AuthorizedAt time.Time
+	// Document is the authorized XML when the provider returns it
+	Document []byte
+	Messages []string
+}
+
+// Provider submits electronic documents to the SRI and queries their authorization.
+// Providers sign the documents with the issuer's certificate before submitting them.
+type Provider interface {
+	// Name identifies the provider
+	Name() string
+
+	// Submit sends an unsigned document identified by its 49-digit access key
+	Submit(accessKey string, document []byte) (*Reception, error)
+
+	// Authorize queries the authorization of a submitted document
+	Authorize(accessKey string) (*Authorization, error)
+}
+
+// IsValidProvider verifies if a provider is supported
+func IsValidProvider(provider string) bool {
+	switch provider {
+	case ProviderNone, ProviderLog, ProviderHTTP:
+		return true
+	}
+	return false
+}
+
+// LogProvider writes documents to the log and authorizes them at once, with the
+// access key as authorization number as in the SRI offline scheme. It is used in
+// local development and tests.
+type LogProvider struct {
+	logger *log.Logger
+	now    func() time.Time
+}
+
+// NewLogProvider creates a provider that logs documents
+func NewLogProvider(logger *log.Logger) *LogProvider {
+	return &LogProvider{logger: logger, now: time.Now}
+}
+
+// Name identifies the provider
+func (p *LogProvider) Name() string {
+	return ProviderLog
+}
+
+// Submit logs the document
+func (p *LogProvider) Submit(accessKey string, document []byte) (*Reception, error) {
+	p.logger.Printf("Electronic document %s submitted (%d bytes)", accessKey, len(document))
+	return &Reception{Received: true}, nil
+}
+
+// Authorize authorizes the document
+func (p *LogProvider) Authorize(accessKey string) (*Authorization, error) {
+	return &Authorization{Status: StatusAuthorized, Number: accessKey, AuthorizedAt: p.now()}, nil
+}
+
+// HTTPProvider relays documents to an electronic invoicing service that signs them and
+// talks to the SRI web services. Documents are POSTed to {endpoint}/documents as
+// {"access_key": "...", "xml": "<base64>"} and their authorization is read from
+// GET {endpoint}/documents/{access_key}/authorization, authenticated with a bearer key.
+type HTTPProvider struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewHTTPProvider creates a provider for an invoicing service
+func NewHTTPProvider(endpoint, apiKey string, timeout time.Duration) (*HTTPProvider, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("electronic invoicing endpoint is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &HTTPProvider{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (p *HTTPProvider) Name() string {
+	return ProviderHTTP
+}
+
+// Submit sends the document to the invoicing service
+func (p *HTTPProvider) Submit(accessKey string, document []byte) (*Reception, error) {
+	body, err := json.Marshal(map[string]interface{}{"access_key": accessKey, "xml": document})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding document: %w", err)
+	}
+
+	var response struct {
+		Status   string   `json:"status"` // RECIBIDA or DEVUELTA
+		Messages []string `json:"messages"`
+	}
+	if err := p.do(http.MethodPost, p.endpoint+"/documents", body, &response); err != nil {
+		return nil, err
+	}
+	return &Reception{Received: response.Status == "RECIBIDA", Messages: response.Messages}, nil
+}
+
+// Authorize queries the authorization of the document from the invoicing service
+func (p *HTTPProvider) Authorize(accessKey string) (*Authorization, error) {
+	var response struct {
+		Status              string    `json:"status"`
+		AuthorizationNumber string    `json:"authorization_number"`
+		AuthorizedAt        time.Time `json:"authorized_at"`
+		XML                 []byte    `json:"xml"`
+		Messages            []string  `json:"messages"`
+	}
+	endpoint := fmt.Sprintf("%s/documents/%s/authorization", p.endpoint, url.PathEscape(accessKey))
+	if err := p.do(http.MethodGet, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &Authorization{
+		Status:       response.Status,
+		Number:       response.AuthorizationNumber,
+		AuthorizedAt: response.AuthorizedAt,
+		Document:     response.XML,
+		Messages:     response.Messages,
+	}, nil
+}
+
+// do sends a request to the invoicing service and decodes its JSON response
+func (p *HTTPProvider) do(method, endpoint string, body []byte, response interface{}) error {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating invoicing request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting invoicing service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("error reading invoicing response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("invoicing service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if err := json.Unmarshal(raw, response); err != nil {
+		return fmt.Errorf("error decoding invoicing response: %w", err)
+	}
+	return nil
+}
+
+// NewProvider creates the provider of an electronic invoicing configuration. The none
+// provider has no provider.
+func NewProvider(provider, endpoint, apiKey string, timeout time.Duration, logger *log.Logger) (Provider, error) {
+	switch provider {
+	case ProviderNone, "":
+		return nil, nil
+	case ProviderLog:
+		return NewLogProvider(logger), nil
+	case ProviderHTTP:
+		return NewHTTPProvider(endpoint, apiKey, timeout)
+	}
+	return nil, fmt.Errorf("unsupported electronic invoicing provider: %s", provider)
+}
@@ -0,0 +1,68 @@
+package einvoice
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	const accessKey = "0809202501179214673900110010010000000011234567811"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/documents":
+			var body struct {
+				AccessKey string `json:"access_key"`
+				XML       []byte `json:"xml"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, accessKey, body.AccessKey)
+			assert.Equal(t, "<factura/>", string(body.XML))
+			fmt.Fprint(w, `{"status":"RECIBIDA"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/documents/"+accessKey+"/authorization":
+			fmt.Fprintf(w, `{"status":"AUTORIZADO","authorization_number":"%s","authorized_at":"2025-09-08T10:00:00-05:00","xml":"PGF1dG9yaXphY2lvbi8+"}`, accessKey)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "unknown document")
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider(server.URL+"/", "secret", time.Second)
+	require.NoError(t, err)
+
+	reception, err := provider.Submit(accessKey, []byte("<factura/>"))
+	require.NoError(t, err)
+	assert.True(t, reception.Received)
+
+	authorization, err := provider.Authorize(accessKey)
+	require.NoError(t, err)
+	assert.Equal(t, StatusAuthorized, authorization.Status)
+	assert.Equal(t, accessKey, authorization.Number)
+	assert.Equal(t, "<autorizacion/>", string(authorization.Document))
+
+	_, err = provider.Authorize("missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(ProviderNone, "", "", 0, nil)
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	_, err = NewProvider(ProviderHTTP, "", "", 0, nil)
+	assert.Error(t, err)
+
+	_, err = NewProvider("fax", "", "", 0, nil)
+	assert.Error(t, err)
+}
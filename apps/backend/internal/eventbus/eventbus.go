@@ -0,0 +1,150 @@
+// Package eventbus publishes domain events to a message broker so downstream
+// consumers such as analytics, the search indexer or notifiers are decoupled from the
+// HTTP service. Events are JSON envelopes published to "<prefix>.<event type>"
+// subjects, e.g. realty.property.created.
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"realty-core/internal/domain"
+)
+
+// Backend names
+const (
+	BackendNone = "none"
+	BackendLog  = "log"
+	BackendNATS = "nats"
+)
+
+// DefaultSubjectPrefix prefixes the subject of every event
+const DefaultSubjectPrefix = "realty"
+
+// Event is the envelope published for each domain event
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	AgencyID   string          `json:"agency_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Bus publishes domain events. It implements service.EventPublisher for events
+// published after a write and service.OutboxHandler for events of the outbox.
+type Bus interface {
+	// Publish sends an event with a new ID
+	Publish(eventType, agencyID string, data interface{}) error
+
+	// HandleOutboxEvent sends an outbox event, keeping its ID so consumers can
+	// discard redeliveries
+	HandleOutboxEvent(event *domain.OutboxEvent) error
+
+	// Close releases the broker connection
+	Close() error
+}
+
+// Config holds the settings of the event bus
+type Config struct {
+	Backend       string // none, log, nats
+	SubjectPrefix string
+	Timeout       time.Duration // per publish, including the broker acknowledgement
+
+	NATSURL   string // nats://host:4222 or tls://host:4222, optionally with user:password
+	NATSToken string
+	NATSName  string // client name shown in the NATS monitoring endpoints
+}
+
+// New creates the bus configured by config.Backend
+func New(config Config, logger *log.Logger) (Bus, error) {
+	if config.SubjectPrefix == "" {
+		config.SubjectPrefix = DefaultSubjectPrefix
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+
+	switch strings.ToLower(config.Backend) {
+	case "", BackendNone:
+		return NoopBus{}, nil
+	case BackendLog:
+		return &LogBus{prefix: config.SubjectPrefix, logger: logger}, nil
+	case BackendNATS:
+		return NewNATSBus(config)
+	default:
+		return nil, fmt.Errorf("unsupported event bus backend: %s", config.Backend)
+	}
+}
+
+// NewEvent wraps event data in an envelope with a new ID
+func NewEvent(eventType, agencyID string, data interface{}) (Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		AgencyID:   agencyID,
+		OccurredAt: time.Now(),
+		Data:       payload,
+	}, nil
+}
+
+// FromOutboxEvent wraps an outbox event, keeping its ID
+func FromOutboxEvent(event *domain.OutboxEvent) Event {
+	return Event{
+		ID:         event.ID,
+		Type:       event.EventType,
+		AgencyID:   event.AgencyID,
+		OccurredAt: event.CreatedAt,
+		Data:       event.Payload,
+	}
+}
+
+// Subject returns the subject events of a type are published to
+func Subject(prefix, eventType string) string {
+	return prefix + "." + eventType
+}
+
+// NoopBus discards events, for deployments without a broker
+type NoopBus struct{}
+
+// Publish discards the event
+func (NoopBus) Publish(eventType, agencyID string, data interface{}) error { return nil }
+
+// HandleOutboxEvent discards the event
+func (NoopBus) HandleOutboxEvent(event *domain.OutboxEvent) error { return nil }
+
+// Close does nothing
+func (NoopBus) Close() error { return nil }
+
+// LogBus logs events instead of publishing them, for development
+type LogBus struct {
+	prefix string
+	logger *log.Logger
+}
+
+// Publish logs the event
+func (b *LogBus) Publish(eventType, agencyID string, data interface{}) error {
+	event, err := NewEvent(eventType, agencyID, data)
+	if err != nil {
+		return err
+	}
+	b.logger.Printf("Event %s published to %s: %s", event.ID, Subject(b.prefix, eventType), event.Data)
+	return nil
+}
+
+// HandleOutboxEvent logs the event
+func (b *LogBus) HandleOutboxEvent(event *domain.OutboxEvent) error {
+	b.logger.Printf("Event %s published to %s: %s", event.ID, Subject(b.prefix, event.EventType), event.Payload)
+	return nil
+}
+
+// Close does nothing
+func (b *LogBus) Close() error { return nil }
@@ -0,0 +1,256 @@
+package eventbus
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const natsDefaultPort = "4222"
+
+// natsInfo is the part of the server INFO message the client uses
+type natsInfo struct {
+	Headers    bool  `json:"headers"`
+	MaxPayload int64 `json:"max_payload"`
+}
+
+// natsConnect is the CONNECT message sent after INFO
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name,omitempty"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	Protocol  int    `json:"protocol"`
+	Headers   bool   `json:"headers"`
+	AuthToken string `json:"auth_token,omitempty"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+}
+
+// NATSBus publishes events to NATS over its text protocol. Each publish is followed by
+// a PING and waits for the PONG, so an error is returned when the server did not
+// receive the event. When the server supports headers, the event ID is sent as
+// Nats-Msg-Id so JetStream streams discard duplicates. The connection is opened on
+// the first publish and reopened after failures.
+type NATSBus struct {
+	address string
+	useTLS  bool
+	host    string
+	connect natsConnect
+	prefix  string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	info   natsInfo
+}
+
+// NewNATSBus validates the NATS settings; the connection is opened on first use
+func NewNATSBus(config Config) (*NATSBus, error) {
+	parsed, err := url.Parse(config.NATSURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL: %s", config.NATSURL)
+	}
+	if parsed.Scheme != "nats" && parsed.Scheme != "tls" {
+		return nil, fmt.Errorf("invalid NATS URL: scheme must be nats:// or tls://")
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		address = net.JoinHostPort(parsed.Hostname(), natsDefaultPort)
+	}
+	prefix := config.SubjectPrefix
+	if prefix == "" {
+		prefix = DefaultSubjectPrefix
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	connect := natsConnect{
+		Name:      config.NATSName,
+		Lang:      "go",
+		Version:   "1.0",
+		Protocol:  1,
+		Headers:   true,
+		AuthToken: config.NATSToken,
+	}
+	if parsed.User != nil {
+		connect.User = parsed.User.Username()
+		connect.Pass, _ = parsed.User.Password()
+	}
+
+	return &NATSBus{
+		address: address,
+		useTLS:  parsed.Scheme == "tls",
+		host:    parsed.Hostname(),
+		connect: connect,
+		prefix:  prefix,
+		timeout: timeout,
+	}, nil
+}
+
+// Publish sends an event with a new ID
+func (b *NATSBus) Publish(eventType, agencyID string, data interface{}) error {
+	event, err := NewEvent(eventType, agencyID, data)
+	if err != nil {
+		return err
+	}
+	return b.PublishEvent(event)
+}
+
+// HandleOutboxEvent sends an outbox event, keeping its ID
+func (b *NATSBus) HandleOutboxEvent(event *domain.OutboxEvent) error {
+	return b.PublishEvent(FromOutboxEvent(event))
+}
+
+// PublishEvent sends an event to the subject of its type, reconnecting once if the
+// connection was lost
+func (b *NATSBus) PublishEvent(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	subject := Subject(b.prefix, event.Type)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if b.conn == nil {
+			if err := b.dial(); err != nil {
+				return fmt.Errorf("failed to connect to NATS: %w", err)
+			}
+		}
+		if lastErr = b.publish(subject, event.ID, payload); lastErr == nil {
+			return nil
+		}
+		b.closeConn()
+	}
+	return fmt.Errorf("failed to publish %s to NATS: %w", subject, lastErr)
+}
+
+// Close closes the connection
+func (b *NATSBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeConn()
+	return nil
+}
+
+// dial opens the connection and completes the INFO/CONNECT handshake
+func (b *NATSBus) dial() error {
+	dialer := &net.Dialer{Timeout: b.timeout}
+	var conn net.Conn
+	var err error
+	if b.useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", b.address, &tls.Config{ServerName: b.host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", b.address)
+	}
+	if err != nil {
+		return err
+	}
+	b.conn, b.reader = conn, bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(b.timeout))
+
+	line, err := b.readLine()
+	if err != nil {
+		b.closeConn()
+		return fmt.Errorf("failed to read server info: %w", err)
+	}
+	infoJSON, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		b.closeConn()
+		return fmt.Errorf("unexpected server greeting: %s", line)
+	}
+	b.info = natsInfo{}
+	if err := json.Unmarshal([]byte(infoJSON), &b.info); err != nil {
+		b.closeConn()
+		return fmt.Errorf("invalid server info: %w", err)
+	}
+
+	connect, err := json.Marshal(b.connect)
+	if err != nil {
+		b.closeConn()
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		b.closeConn()
+		return err
+	}
+	if err := b.awaitPong(); err != nil {
+		b.closeConn()
+		return err
+	}
+	return nil
+}
+
+// publish sends one message and waits for the server to acknowledge it
+func (b *NATSBus) publish(subject, id string, payload []byte) error {
+	if b.info.MaxPayload > 0 && int64(len(payload)) > b.info.MaxPayload {
+		return fmt.Errorf("event of %d bytes exceeds the server limit of %d bytes", len(payload), b.info.MaxPayload)
+	}
+	b.conn.SetDeadline(time.Now().Add(b.timeout))
+
+	var message string
+	if b.info.Headers {
+		headers := "NATS/1.0\r\nNats-Msg-Id: " + id + "\r\n\r\n"
+		message = fmt.Sprintf("HPUB %s %d %d\r\n%s%s\r\nPING\r\n", subject, len(headers), len(headers)+len(payload), headers, payload)
+	} else {
+		message = fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload)
+	}
+	if _, err := b.conn.Write([]byte(message)); err != nil {
+		return err
+	}
+	return b.awaitPong()
+}
+
+// awaitPong reads server messages until the PONG answering our PING
+func (b *NATSBus) awaitPong() error {
+	for {
+		line, err := b.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := b.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.Trim(strings.TrimPrefix(line, "-ERR"), " '"))
+		}
+	}
+}
+
+// readLine reads one protocol line without the trailing CRLF
+func (b *NATSBus) readLine() (string, error) {
+	line, err := b.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// closeConn drops the connection so the next publish reconnects
+func (b *NATSBus) closeConn() {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn, b.reader = nil, nil
+	}
+}
@@ -0,0 +1,159 @@
+package eventbus
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// natsMessage is a message received by fakeNATSServer
+type natsMessage struct {
+	subject string
+	headers string
+	payload []byte
+}
+
+// fakeNATSServer speaks enough of the NATS protocol to receive publishes
+type fakeNATSServer struct {
+	listener net.Listener
+	info     string
+	token    string
+
+	mu       sync.Mutex
+	connects []string
+	messages []natsMessage
+}
+
+func newFakeNATSServer(t *testing.T, info, token string) *fakeNATSServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeNATSServer{listener: listener, info: info, token: token}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeNATSServer) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	fmt.Fprintf(conn, "INFO %s\r\n", s.info)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "CONNECT":
+			s.mu.Lock()
+			s.connects = append(s.connects, fields[1])
+			s.mu.Unlock()
+			if s.token != "" && !strings.Contains(fields[1], `"auth_token":"`+s.token+`"`) {
+				fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case fields[0] == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case fields[0] == "PUB" || fields[0] == "HPUB":
+			total, _ := strconv.Atoi(fields[len(fields)-1])
+			body := make([]byte, total+2)
+			if _, err := io.ReadFull(reader, body); err != nil {
+				return
+			}
+			message := natsMessage{subject: fields[1], payload: body[:total]}
+			if fields[0] == "HPUB" {
+				headerLen, _ := strconv.Atoi(fields[2])
+				message.headers, message.payload = string(body[:headerLen]), body[headerLen:total]
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, message)
+			s.mu.Unlock()
+		}
+	}
+}
+
+func TestNATSBus_Publish(t *testing.T) {
+	server := newFakeNATSServer(t, `{"headers":true,"max_payload":1048576}`, "s3cret")
+	bus, err := New(Config{Backend: BackendNATS, NATSURL: server.url(), NATSToken: "s3cret", Timeout: time.Second}, nil)
+	require.NoError(t, err)
+	defer bus.Close()
+
+	require.NoError(t, bus.Publish(domain.EventPropertyCreated, "agency-1", map[string]string{"id": "prop-1"}))
+
+	outboxEvent, err := domain.NewOutboxEvent(domain.EventImageProcessed, "image", "img-1", "", map[string]string{"id": "img-1"})
+	require.NoError(t, err)
+	require.NoError(t, bus.HandleOutboxEvent(outboxEvent))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Len(t, server.connects, 1, "the connection is reused")
+	require.Len(t, server.messages, 2)
+
+	assert.Equal(t, "realty.property.created", server.messages[0].subject)
+	var event Event
+	require.NoError(t, json.Unmarshal(server.messages[0].payload, &event))
+	assert.Equal(t, "agency-1", event.AgencyID)
+	assert.JSONEq(t, `{"id":"prop-1"}`, string(event.Data))
+	assert.Contains(t, server.messages[0].headers, "Nats-Msg-Id: "+event.ID)
+
+	assert.Equal(t, "realty.image.processed", server.messages[1].subject)
+	assert.Contains(t, server.messages[1].headers, "Nats-Msg-Id: "+outboxEvent.ID, "outbox events keep their ID")
+}
+
+func TestNATSBus_WithoutHeaders(t *testing.T) {
+	server := newFakeNATSServer(t, `{"headers":false}`, "")
+	bus, err := NewNATSBus(Config{NATSURL: server.url(), SubjectPrefix: "staging", Timeout: time.Second})
+	require.NoError(t, err)
+	defer bus.Close()
+
+	require.NoError(t, bus.Publish(domain.EventInquiryCreated, "", map[string]string{"id": "app-1"}))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Len(t, server.messages, 1)
+	assert.Equal(t, "staging.inquiry.created", server.messages[0].subject)
+	assert.Empty(t, server.messages[0].headers)
+}
+
+func TestNATSBus_Errors(t *testing.T) {
+	server := newFakeNATSServer(t, `{"headers":true}`, "s3cret")
+	bus, err := NewNATSBus(Config{NATSURL: server.url(), NATSToken: "wrong", Timeout: time.Second})
+	require.NoError(t, err)
+	defer bus.Close()
+
+	err = bus.Publish(domain.EventPropertyCreated, "", nil)
+	assert.ErrorContains(t, err, "Authorization Violation")
+
+	_, err = NewNATSBus(Config{NATSURL: "http://localhost:4222"})
+	assert.ErrorContains(t, err, "invalid NATS URL")
+
+	_, err = New(Config{Backend: "kafka"}, nil)
+	assert.ErrorContains(t, err, "unsupported event bus backend")
+}
@@ -0,0 +1,366 @@
+// Package gcal talks to Google Calendar: the OAuth consent agents connect their
+// calendar with, pushing appointments as events and importing the agent's events
+// incrementally with sync tokens.
+package gcal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const defaultHTTPTimeout = 15 * time.Second
+
+// Google endpoints
+const (
+	DefaultAuthURL  = "https://accounts.google.com/o/oauth2/v2/auth"
+	DefaultTokenURL = "https://oauth2.googleapis.com/token"
+	DefaultAPIURL   = "https://www.googleapis.com/calendar/v3"
+)
+
+// Scope lets the platform read and write the events of the agent's calendars
+const Scope = "https://www.googleapis.com/auth/calendar.events"
+
+// appointmentProperty is the private extended property pushed events carry the
+// appointment ID in
+const appointmentProperty = "realty_appointment_id"
+
+// maxEventPages bounds an import; a calendar with more changes is imported again from
+// the same point on the next sync
+const maxEventPages = 20
+
+var (
+	// ErrSyncTokenExpired means the sync token is no longer valid and the calendar has
+	// to be imported from scratch
+	ErrSyncTokenExpired = errors.New("calendar sync token expired")
+	// ErrAuthorizationRevoked means the agent revoked access or the refresh token expired
+	ErrAuthorizationRevoked = errors.New("calendar authorization revoked")
+)
+
+// Config holds the OAuth client of the platform
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // where Google sends the agent back after consent
+	AuthURL      string // Google defaults when empty
+	TokenURL     string
+	APIURL       string
+	Timeout      time.Duration
+}
+
+// Client calls the Google OAuth and Calendar APIs
+type Client struct {
+	config Config
+	client *http.Client
+}
+
+// NewClient creates a Google Calendar client for an OAuth client
+func NewClient(config Config) (*Client, error) {
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return nil, fmt.Errorf("Google OAuth client ID and secret are required")
+	}
+	if _, err := url.ParseRequestURI(config.RedirectURL); err != nil {
+		return nil, fmt.Errorf("invalid Google OAuth redirect URL: %w", err)
+	}
+	if config.AuthURL == "" {
+		config.AuthURL = DefaultAuthURL
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = DefaultTokenURL
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	config.APIURL = strings.TrimRight(config.APIURL, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHTTPTimeout
+	}
+
+	return &Client{config: config, client: &http.Client{Timeout: config.Timeout}}, nil
+}
+
+// AuthorizationURL returns the consent page the agent is sent to. Offline access with
+// a forced consent prompt makes Google return a refresh token every time.
+func (c *Client) AuthorizationURL(state string) string {
+	query := url.Values{
+		"client_id":              {c.config.ClientID},
+		"redirect_uri":           {c.config.RedirectURL},
+		"response_type":          {"code"},
+		"scope":                  {Scope},
+		"access_type":            {"offline"},
+		"prompt":                 {"consent"},
+		"include_granted_scopes": {"true"},
+		"state":                  {state},
+	}
+	return c.config.AuthURL + "?" + query.Encode()
+}
+
+// Exchange exchanges the authorization code Google redirected back with for a token
+func (c *Client) Exchange(code string) (*domain.CalendarToken, error) {
+	return c.token(url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.config.RedirectURL},
+	})
+}
+
+// Refresh obtains a new access token with a refresh token
+func (c *Client) Refresh(refreshToken string) (*domain.CalendarToken, error) {
+	return c.token(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// token requests a token from the OAuth token endpoint
+func (c *Client) token(form url.Values) (*domain.CalendarToken, error) {
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+
+	resp, err := c.client.PostForm(c.config.TokenURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting Google OAuth: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Error        string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error decoding Google OAuth response (status %d): %w", resp.StatusCode, err)
+	}
+	if body.Error == "invalid_grant" {
+		return nil, ErrAuthorizationRevoked
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("Google OAuth returned status %d: %s", resp.StatusCode, body.Error)
+	}
+
+	return &domain.CalendarToken{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// eventTime is the start or end of an event: a time, or a date for all-day events
+type eventTime struct {
+	Date     string `json:"date,omitempty"`
+	DateTime string `json:"dateTime,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// event is an event of the Calendar API
+type event struct {
+	ID           string    `json:"id,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Summary      string    `json:"summary,omitempty"`
+	Location     string    `json:"location,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Transparency string    `json:"transparency,omitempty"`
+	Start        eventTime `json:"start"`
+	End          eventTime `json:"end"`
+	Attendees    []struct {
+		Self           bool   `json:"self"`
+		ResponseStatus string `json:"responseStatus"`
+	} `json:"attendees,omitempty"`
+	ExtendedProperties *extendedProperties `json:"extendedProperties,omitempty"`
+}
+
+// extendedProperties are the custom properties of an event; private ones are only
+// visible to the platform
+type extendedProperties struct {
+	Private map[string]string `json:"private,omitempty"`
+}
+
+// ListEvents imports the events of a calendar changed since a sync token, or every
+// event from a time on when the token is empty. Recurring events are expanded into
+// their occurrences.
+func (c *Client) ListEvents(accessToken, calendarID, syncToken string, from time.Time) (*domain.CalendarEventPage, error) {
+	page := &domain.CalendarEventPage{Events: []domain.CalendarEvent{}}
+	pageToken := ""
+	for i := 0; i < maxEventPages; i++ {
+		query := url.Values{"singleEvents": {"true"}, "maxResults": {"250"}}
+		if syncToken != "" {
+			query.Set("syncToken", syncToken)
+		} else {
+			query.Set("timeMin", from.UTC().Format(time.RFC3339))
+			query.Set("showDeleted", "false")
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		var body struct {
+			Summary       string  `json:"summary"`
+			Items         []event `json:"items"`
+			NextPageToken string  `json:"nextPageToken"`
+			NextSyncToken string  `json:"nextSyncToken"`
+		}
+		status, err := c.call(http.MethodGet, c.eventsURL(calendarID, "")+"?"+query.Encode(), accessToken, nil, &body)
+		if status == http.StatusGone {
+			return nil, ErrSyncTokenExpired
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		page.CalendarName = body.Summary
+		for _, item := range body.Items {
+			page.Events = append(page.Events, item.toDomain())
+		}
+		if body.NextPageToken == "" {
+			page.NextSyncToken = body.NextSyncToken
+			return page, nil
+		}
+		pageToken = body.NextPageToken
+	}
+	// Keep the previous token so the changes are imported again, with the rest, next time
+	page.NextSyncToken = syncToken
+	return page, nil
+}
+
+// PutEvent pushes an appointment as the event with the given ID, creating it or
+// replacing it, and returns the event's new version. Events deleted in the calendar
+// are restored.
+func (c *Client) PutEvent(accessToken, calendarID, eventID string, appointment *domain.Appointment) (string, error) {
+	body := appointmentEvent(eventID, appointment)
+	var saved event
+
+	status, err := c.call(http.MethodPut, c.eventsURL(calendarID, eventID), accessToken, body, &saved)
+	if status == http.StatusNotFound {
+		_, err = c.call(http.MethodPost, c.eventsURL(calendarID, ""), accessToken, body, &saved)
+	}
+	if err != nil {
+		return "", err
+	}
+	return saved.ETag, nil
+}
+
+// DeleteEvent deletes an event; events already deleted are ignored
+func (c *Client) DeleteEvent(accessToken, calendarID, eventID string) error {
+	status, err := c.call(http.MethodDelete, c.eventsURL(calendarID, eventID), accessToken, nil, nil)
+	if status == http.StatusNotFound || status == http.StatusGone {
+		return nil
+	}
+	return err
+}
+
+// eventsURL returns the URL of the events of a calendar, or of one event
+func (c *Client) eventsURL(calendarID, eventID string) string {
+	u := c.config.APIURL + "/calendars/" + url.PathEscape(calendarID) + "/events"
+	if eventID != "" {
+		u += "/" + url.PathEscape(eventID)
+	}
+	return u
+}
+
+// call sends a Calendar API request and decodes its response, returning its status
+func (c *Client) call(method, u, accessToken string, payload, result interface{}) (int, error) {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, fmt.Errorf("error encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error contacting Google Calendar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp.StatusCode, fmt.Errorf("Google Calendar rejected the access token")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("Google Calendar returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return resp.StatusCode, fmt.Errorf("error decoding Google Calendar response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// appointmentEvent returns the event an appointment is pushed as
+func appointmentEvent(eventID string, appointment *domain.Appointment) *event {
+	location := domain.AppointmentLocation(appointment.TimeZone)
+	description := appointment.Notes
+	if appointment.ClientName != "" {
+		description = strings.TrimSpace("Cliente: " + appointment.ClientName + "\n" + description)
+	}
+
+	return &event{
+		ID:          eventID,
+		Status:      "confirmed",
+		Summary:     appointment.Title,
+		Location:    appointment.Location,
+		Description: description,
+		Start:       eventTime{DateTime: appointment.StartsAt.In(location).Format(time.RFC3339), TimeZone: appointment.TimeZone},
+		End:         eventTime{DateTime: appointment.EndsAt.In(location).Format(time.RFC3339), TimeZone: appointment.TimeZone},
+		ExtendedProperties: &extendedProperties{
+			Private: map[string]string{appointmentProperty: appointment.ID},
+		},
+	}
+}
+
+// toDomain converts an imported event. All-day events block whole days in mainland
+// Ecuador time.
+func (e event) toDomain() domain.CalendarEvent {
+	imported := domain.CalendarEvent{
+		ExternalID:  e.ID,
+		ETag:        e.ETag,
+		Cancelled:   e.Status == "cancelled",
+		Transparent: e.Transparency == "transparent",
+		StartsAt:    parseEventTime(e.Start),
+		EndsAt:      parseEventTime(e.End),
+	}
+	for _, attendee := range e.Attendees {
+		if attendee.Self && attendee.ResponseStatus == "declined" {
+			imported.Cancelled = true
+		}
+	}
+	if e.ExtendedProperties != nil {
+		imported.AppointmentID = e.ExtendedProperties.Private[appointmentProperty]
+	}
+	return imported
+}
+
+// parseEventTime parses the start or end of an event; the zero time when malformed
+func parseEventTime(t eventTime) time.Time {
+	if t.DateTime != "" {
+		parsed, _ := time.Parse(time.RFC3339, t.DateTime)
+		return parsed
+	}
+	if t.Date != "" {
+		parsed, _ := time.ParseInLocation(domain.CalendarDateLayout, t.Date, domain.AppointmentLocation(domain.TimeZoneEcuador))
+		return parsed
+	}
+	return time.Time{}
+}
@@ -0,0 +1,128 @@
+package gcal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	client, err := NewClient(Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://api.example.com/api/calendar/google/callback",
+		TokenURL:     server.URL + "/token",
+		APIURL:       server.URL,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestClient_Token(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token": "ya29.token", "refresh_token": "1//refresh", "expires_in": 3599}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	consent, err := url.Parse(client.AuthorizationURL("cst_state"))
+	require.NoError(t, err)
+	assert.Equal(t, "offline", consent.Query().Get("access_type"))
+	assert.Equal(t, "cst_state", consent.Query().Get("state"))
+
+	token, err := client.Exchange("code")
+	require.NoError(t, err)
+	assert.Equal(t, "1//refresh", token.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+
+	_, err = client.Refresh("revoked")
+	assert.ErrorIs(t, err, ErrAuthorizationRevoked)
+}
+
+func TestClient_ListEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		query := r.URL.Query()
+		switch {
+		case query.Get("syncToken") == "expired":
+			w.WriteHeader(http.StatusGone)
+		case query.Get("pageToken") == "":
+			assert.NotEmpty(t, query.Get("timeMin"), "full imports start at a time")
+			w.Write([]byte(`{"summary": "maria@example.com", "nextPageToken": "p2", "items": [
+				{"id": "e1", "status": "confirmed", "start": {"dateTime": "2025-09-20T10:00:00-05:00"}, "end": {"dateTime": "2025-09-20T11:00:00-05:00"}},
+				{"id": "e2", "status": "confirmed", "transparency": "transparent", "start": {"date": "2025-09-21"}, "end": {"date": "2025-09-22"}}]}`))
+		default:
+			w.Write([]byte(`{"summary": "maria@example.com", "nextSyncToken": "sync-1", "items": [
+				{"id": "rcabc", "status": "confirmed", "start": {"dateTime": "2025-09-22T10:00:00-05:00"}, "end": {"dateTime": "2025-09-22T11:00:00-05:00"},
+				 "extendedProperties": {"private": {"realty_appointment_id": "abc"}}},
+				{"id": "e3", "status": "confirmed", "start": {"dateTime": "2025-09-23T10:00:00-05:00"}, "end": {"dateTime": "2025-09-23T11:00:00-05:00"},
+				 "attendees": [{"self": true, "responseStatus": "declined"}]}]}`))
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	page, err := client.ListEvents("ya29.token", "primary", "", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "sync-1", page.NextSyncToken)
+	assert.Equal(t, "maria@example.com", page.CalendarName)
+	require.Len(t, page.Events, 4)
+	assert.True(t, page.Events[0].IsBusy())
+	assert.False(t, page.Events[1].IsBusy(), "events marked as free do not block")
+	assert.Equal(t, 24*time.Hour, page.Events[1].EndsAt.Sub(page.Events[1].StartsAt))
+	assert.Equal(t, "abc", page.Events[2].AppointmentID)
+	assert.False(t, page.Events[2].IsBusy(), "pushed appointments are not busy times")
+	assert.False(t, page.Events[3].IsBusy(), "declined invitations do not block")
+
+	_, err = client.ListEvents("ya29.token", "primary", "expired", time.Now())
+	assert.ErrorIs(t, err, ErrSyncTokenExpired)
+}
+
+func TestClient_PutEvent(t *testing.T) {
+	inserted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			http.Error(w, "not found", http.StatusNotFound)
+		case http.MethodPost:
+			var body event
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "rc123", body.ID)
+			assert.Equal(t, "appointment-1", body.ExtendedProperties.Private[appointmentProperty])
+			assert.Equal(t, "2025-09-20T10:00:00-06:00", body.Start.DateTime)
+			inserted = true
+			w.Write([]byte(`{"id": "rc123", "etag": "\"v1\""}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	location := domain.AppointmentLocation(domain.TimeZoneGalapagos)
+	appointment := &domain.Appointment{ID: "appointment-1", Title: "Visita: Casa", TimeZone: domain.TimeZoneGalapagos,
+		StartsAt: time.Date(2025, 9, 20, 10, 0, 0, 0, location), EndsAt: time.Date(2025, 9, 20, 11, 0, 0, 0, location)}
+
+	etag, err := client.PutEvent("ya29.token", "primary", "rc123", appointment)
+	require.NoError(t, err)
+	assert.True(t, inserted, "missing events are inserted with their ID")
+	assert.Equal(t, `"v1"`, etag)
+
+	assert.NoError(t, client.DeleteEvent("ya29.token", "primary", "rc123"), "events already deleted are ignored")
+}
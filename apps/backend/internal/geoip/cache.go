@@ -0,0 +1,90 @@
+package geoip
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// Cache sizing. Addresses rarely move between countries, so lookups are kept for a
+// day; failed lookups are not cached.
+const (
+	DefaultCacheTTL        = 24 * time.Hour
+	DefaultCacheMaxEntries = 10000
+)
+
+// cachedLocation is a location with when it was looked up
+type cachedLocation struct {
+	location domain.GeoLocation
+	at       time.Time
+}
+
+// CachedLocator keeps the locations of recently seen addresses in memory, so the
+// web service is contacted once per address and TTL instead of once per request
+type CachedLocator struct {
+	locator    Locator
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedLocation
+}
+
+// NewCachedLocator creates a cache in front of a locator
+func NewCachedLocator(locator Locator, ttl time.Duration, maxEntries int) (*CachedLocator, error) {
+	if locator == nil {
+		return nil, fmt.Errorf("geoip locator is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &CachedLocator{
+		locator:    locator,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    map[string]cachedLocation{},
+	}, nil
+}
+
+// Locate returns the cached location of an address, looking it up when missing or expired
+func (c *CachedLocator) Locate(ip string) (domain.GeoLocation, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[ip]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.at) < c.ttl {
+		return entry.location, nil
+	}
+
+	location, err := c.locator.Locate(ip)
+	if err != nil {
+		return domain.GeoLocation{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[ip] = cachedLocation{location: location, at: now}
+	return location, nil
+}
+
+// evict drops expired entries, or every entry when none expired
+func (c *CachedLocator) evict(now time.Time) {
+	for ip, entry := range c.entries {
+		if now.Sub(entry.at) >= c.ttl {
+			delete(c.entries, ip)
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = map[string]cachedLocation{}
+	}
+}
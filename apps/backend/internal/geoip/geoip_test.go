@@ -0,0 +1,119 @@
+package geoip
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// stubLocator counts lookups and fails on demand
+type stubLocator struct {
+	lookups int
+	err     error
+}
+
+func (l *stubLocator) Locate(ip string) (domain.GeoLocation, error) {
+	l.lookups++
+	if l.err != nil {
+		return domain.GeoLocation{}, l.err
+	}
+	return domain.GeoLocation{CountryCode: "EC", City: fmt.Sprintf("City %d", l.lookups)}, nil
+}
+
+func TestMaxMindLocator_Locate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "1234", user)
+		assert.Equal(t, "secret", pass)
+
+		if r.URL.Path == "/geoip/v2.1/city/203.0.113.9" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":"IP_ADDRESS_NOT_FOUND"}`)
+			return
+		}
+		assert.Equal(t, "/geoip/v2.1/city/190.152.10.4", r.URL.Path)
+		fmt.Fprint(w, `{"country":{"iso_code":"ec","names":{"en":"Ecuador","es":"Ecuador"}},"city":{"names":{"en":"Quito"}}}`)
+	}))
+	defer server.Close()
+
+	locator, err := NewMaxMindLocator(server.URL+"/geoip/v2.1/city/", "1234", "secret", time.Second)
+	require.NoError(t, err)
+
+	location, err := locator.Locate("190.152.10.4")
+	require.NoError(t, err)
+	assert.Equal(t, domain.GeoLocation{CountryCode: "EC", CountryName: "Ecuador", City: "Quito"}, location)
+	assert.Equal(t, "Quito, Ecuador", location.String())
+
+	location, err = locator.Locate("203.0.113.9")
+	require.NoError(t, err)
+	assert.False(t, location.IsKnown())
+
+	// Private addresses are never sent to the service
+	location, err = locator.Locate("192.168.1.20")
+	require.NoError(t, err)
+	assert.False(t, location.IsKnown())
+
+	_, err = locator.Locate("not-an-ip")
+	assert.Error(t, err)
+
+	_, err = NewMaxMindLocator("", "", "", 0)
+	assert.Error(t, err)
+}
+
+func TestMaxMindLocator_ServiceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":"AUTHORIZATION_INVALID"}`)
+	}))
+	defer server.Close()
+
+	locator, err := NewMaxMindLocator(server.URL, "1234", "wrong", time.Second)
+	require.NoError(t, err)
+
+	_, err = locator.Locate("190.152.10.4")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+}
+
+func TestCachedLocator(t *testing.T) {
+	stub := &stubLocator{}
+	cache, err := NewCachedLocator(stub, time.Hour, 2)
+	require.NoError(t, err)
+	now := time.Date(2025, 8, 25, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.Locate("190.152.10.4")
+	require.NoError(t, err)
+	again, err := cache.Locate("190.152.10.4")
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Equal(t, 1, stub.lookups)
+
+	// Expired entries are looked up again
+	now = now.Add(2 * time.Hour)
+	_, err = cache.Locate("190.152.10.4")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stub.lookups)
+
+	// The cache never grows past its limit
+	_, _ = cache.Locate("190.152.10.5")
+	_, _ = cache.Locate("190.152.10.6")
+	assert.LessOrEqual(t, len(cache.entries), 2)
+
+	// Failures are not cached
+	stub.err = fmt.Errorf("service unavailable")
+	_, err = cache.Locate("190.152.10.7")
+	assert.Error(t, err)
+	assert.NotContains(t, cache.entries, "190.152.10.7")
+
+	_, err = NewCachedLocator(nil, 0, 0)
+	assert.Error(t, err)
+}
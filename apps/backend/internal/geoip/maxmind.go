@@ -0,0 +1,132 @@
+package geoip
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const defaultHTTPTimeout = 5 * time.Second
+
+// DefaultMaxMindEndpoint is the GeoIP2 City web service; GeoLite2 accounts use
+// https://geolite.info/geoip/v2.1/city instead
+const DefaultMaxMindEndpoint = "https://geoip.maxmind.com/geoip/v2.1/city"
+
+// Locator finds where an IP address is located
+type Locator interface {
+	// Locate returns the location of an IP address; private and unknown addresses
+	// have an empty location
+	Locate(ip string) (domain.GeoLocation, error)
+}
+
+// MaxMindLocator looks addresses up with the MaxMind GeoIP2 web services. The
+// address is appended to the endpoint, the account ID and license key are sent as
+// basic auth and the service answers with:
+//
+//	{"country": {"iso_code": "EC", "names": {"en": "Ecuador", "es": "Ecuador"}},
+//	 "city": {"names": {"en": "Quito", "es": "Quito"}}}
+type MaxMindLocator struct {
+	endpoint   string
+	accountID  string
+	licenseKey string
+	language   string
+	client     *http.Client
+}
+
+// NewMaxMindLocator creates a locator for a MaxMind account
+func NewMaxMindLocator(endpoint, accountID, licenseKey string, timeout time.Duration) (*MaxMindLocator, error) {
+	if endpoint == "" {
+		endpoint = DefaultMaxMindEndpoint
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid MaxMind endpoint: %w", err)
+	}
+	if accountID == "" || licenseKey == "" {
+		return nil, fmt.Errorf("MaxMind account ID and license key are required")
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &MaxMindLocator{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		accountID:  accountID,
+		licenseKey: licenseKey,
+		language:   "es",
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Locate requests the country and city of an IP address
+func (l *MaxMindLocator) Locate(ip string) (domain.GeoLocation, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return domain.GeoLocation{}, fmt.Errorf("invalid IP address: %s", ip)
+	}
+	if !isPublicIP(parsed) {
+		return domain.GeoLocation{}, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, l.endpoint+"/"+url.PathEscape(parsed.String()), nil)
+	if err != nil {
+		return domain.GeoLocation{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.SetBasicAuth(l.accountID, l.licenseKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return domain.GeoLocation{}, fmt.Errorf("error contacting MaxMind: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Reserved and unlisted addresses are answered with 404
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.GeoLocation{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return domain.GeoLocation{}, fmt.Errorf("MaxMind returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var body struct {
+		Country struct {
+			ISOCode string            `json:"iso_code"`
+			Names   map[string]string `json:"names"`
+		} `json:"country"`
+		City struct {
+			Names map[string]string `json:"names"`
+		} `json:"city"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return domain.GeoLocation{}, fmt.Errorf("error decoding MaxMind response: %w", err)
+	}
+
+	return domain.GeoLocation{
+		CountryCode: strings.ToUpper(body.Country.ISOCode),
+		CountryName: l.localized(body.Country.Names),
+		City:        l.localized(body.City.Names),
+	}, nil
+}
+
+// localized picks the Spanish name, falling back to English
+func (l *MaxMindLocator) localized(names map[string]string) string {
+	if name := names[l.language]; name != "" {
+		return name
+	}
+	return names["en"]
+}
+
+// isPublicIP reports whether an address can be located; loopback, private and
+// link-local addresses cannot
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast())
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// AccountingExportHandler exports the transactions of agencies for their accountants
+type AccountingExportHandler struct {
+	exportService *service.AccountingExportService
+	logger        *log.Logger
+}
+
+// NewAccountingExportHandler creates a new accounting export handler
+func NewAccountingExportHandler(exportService *service.AccountingExportService, logger *log.Logger) *AccountingExportHandler {
+	return &AccountingExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// ExportTransactions handles GET /api/agencies/{id}/transactions/export?format=csv&from=2025-01-01&to=2026-01-01
+// format is csv (default) or contifico, journal entries for Contifico's import; the
+// period defaults to the current year and to is exclusive.
+func (h *AccountingExportHandler) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseDateParam(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(query.Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	data, fileName, err := h.exportService.ExportTransactions(agencyID, from, to, strings.ToLower(query.Get("format")), actor(r))
+	if err != nil {
+		h.sendExportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Helper functions
+
+func (h *AccountingExportHandler) sendExportError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Accounting export error: %v", err)
+		http.Error(w, "Failed to export transactions", http.StatusInternalServerError)
+	}
+}
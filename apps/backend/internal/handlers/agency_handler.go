@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
 	"realty-core/internal/service"
 )
 
@@ -31,6 +32,12 @@ func NewAgencyHandlerSimple(
 	}
 }
 
+// tenantService returns the agency service scoped to the tenant of the request, so
+// agencies of other tenants are not found
+func (h *AgencyHandlerSimple) tenantService(r *http.Request) *service.AgencyService {
+	return h.agencyService.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
 // CreateAgencyRequest represents the request to create an agency
 type CreateAgencyRequest struct {
 	Name          string  `json:"name"`
@@ -63,7 +70,7 @@ func (h *AgencyHandlerSimple) CreateAgency(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	agency, err := h.agencyService.CreateAgency(req.Name, req.RUC, req.Address, req.Phone, req.Email, req.LicenseNumber)
+	agency, err := h.tenantService(r).CreateAgency(req.Name, req.RUC, req.Address, req.Phone, req.Email, req.LicenseNumber)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -91,7 +98,7 @@ func (h *AgencyHandlerSimple) GetAgency(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	agency, err := h.agencyService.GetAgency(id)
+	agency, err := h.tenantService(r).GetAgency(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -114,7 +121,7 @@ func (h *AgencyHandlerSimple) UpdateAgency(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	agency, err := h.agencyService.GetAgency(id)
+	agency, err := h.tenantService(r).GetAgency(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -143,7 +150,7 @@ func (h *AgencyHandlerSimple) UpdateAgency(w http.ResponseWriter, r *http.Reques
 		agency.Commission = req.Commission
 	}
 
-	if err := h.agencyService.UpdateAgency(agency); err != nil {
+	if err := h.tenantService(r).UpdateAgency(agency); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -159,8 +166,12 @@ func (h *AgencyHandlerSimple) DeleteAgency(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if err := h.agencyService.DeleteAgency(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.tenantService(r).DeleteAgency(id); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -209,7 +220,7 @@ func (h *AgencyHandlerSimple) SearchAgencies(w http.ResponseWriter, r *http.Requ
 		},
 	}
 
-	agencies, pagination, err := h.agencyService.SearchAgencies(params)
+	agencies, pagination, err := h.tenantService(r).SearchAgencies(params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -232,7 +243,7 @@ func (h *AgencyHandlerSimple) SearchAgencies(w http.ResponseWriter, r *http.Requ
 
 // GetActiveAgencies handles getting active agencies
 func (h *AgencyHandlerSimple) GetActiveAgencies(w http.ResponseWriter, r *http.Request) {
-	agencies, err := h.agencyService.GetActiveAgencies()
+	agencies, err := h.tenantService(r).GetActiveAgencies()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -252,7 +263,7 @@ func (h *AgencyHandlerSimple) GetAgenciesByServiceArea(w http.ResponseWriter, r
 		return
 	}
 
-	agencies, err := h.agencyService.GetAgenciesByServiceArea(area)
+	agencies, err := h.tenantService(r).GetAgenciesByServiceArea(area)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -273,7 +284,7 @@ func (h *AgencyHandlerSimple) GetAgenciesBySpecialty(w http.ResponseWriter, r *h
 		return
 	}
 
-	agencies, err := h.agencyService.GetAgenciesBySpecialty(specialty)
+	agencies, err := h.tenantService(r).GetAgenciesBySpecialty(specialty)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -294,7 +305,7 @@ func (h *AgencyHandlerSimple) GetAgencyAgents(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	agents, err := h.agencyService.GetAgencyAgents(id)
+	agents, err := h.tenantService(r).GetAgencyAgents(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -327,7 +338,7 @@ func (h *AgencyHandlerSimple) SetAgencyLicense(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := h.agencyService.SetLicenseNumber(id, licenseNumber); err != nil {
+	if err := h.tenantService(r).SetLicenseNumber(id, licenseNumber); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -337,7 +348,7 @@ func (h *AgencyHandlerSimple) SetAgencyLicense(w http.ResponseWriter, r *http.Re
 
 // GetAgencyStatistics handles getting agency statistics
 func (h *AgencyHandlerSimple) GetAgencyStatistics(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.agencyService.GetAgencyStatistics()
+	stats, err := h.tenantService(r).GetAgencyStatistics()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -354,7 +365,7 @@ func (h *AgencyHandlerSimple) GetAgencyPerformance(w http.ResponseWriter, r *htt
 		return
 	}
 
-	performance, err := h.agencyService.GetAgencyPerformance(id)
+	performance, err := h.tenantService(r).GetAgencyPerformance(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -363,6 +374,40 @@ func (h *AgencyHandlerSimple) GetAgencyPerformance(w http.ResponseWriter, r *htt
 	h.sendJSONResponse(w, performance, http.StatusOK)
 }
 
+// RemoveAgencyMember handles DELETE /api/agencies/{id}/members/{userId}?reassign_to={agentId}.
+// The member's properties move to reassign_to, or stay with the agency unassigned.
+func (h *AgencyHandlerSimple) RemoveAgencyMember(w http.ResponseWriter, r *http.Request) {
+	id := h.extractIDFromPath(r.URL.Path)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	memberID := ""
+	if len(parts) >= 5 && parts[3] == "members" {
+		memberID = parts[4]
+	}
+	if id == "" || memberID == "" {
+		http.Error(w, "Agency ID and member ID required", http.StatusBadRequest)
+		return
+	}
+
+	reassigned, err := h.tenantService(r).RemoveAgencyMember(id, memberID, r.URL.Query().Get("reassign_to"))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "reassign"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"agency_id":             id,
+		"removed":               memberID,
+		"properties_reassigned": reassigned,
+	}, http.StatusOK)
+}
+
 // Helper functions
 
 func (h *AgencyHandlerSimple) extractIDFromPath(path string) string {
@@ -1,12 +1,21 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/repository"
+	"realty-core/internal/service"
 )
 
 // Tests básicos para AgencyHandler enfocados en validación de entrada
@@ -88,6 +97,17 @@ func TestAgencyHandler_InputValidation(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "Agency ID required",
 		},
+		{
+			name:   "RemoveAgencyMember without member ID",
+			method: http.MethodDelete,
+			path:   "/api/agencies/agency-1/members/",
+			body:   "",
+			handlerFunc: func(h *AgencyHandlerSimple, w http.ResponseWriter, r *http.Request) {
+				h.RemoveAgencyMember(w, r)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Agency ID and member ID required",
+		},
 	}
 
 	for _, tt := range tests {
@@ -245,4 +265,31 @@ func TestAgencyHandler_QueryParameterValidation(t *testing.T) {
 }
 
 // Tests básicos completados para AgencyHandler
-// Los tests que requieren servicios reales se omiten por limitaciones de setup
\ No newline at end of file
+// Los tests que requieren servicios reales se omiten por limitaciones de setup
+
+func TestAgencyHandler_HidesAgenciesOfOtherTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	agencies := service.NewAgencyService(repository.NewAgencyRepository(db), repository.NewUserRepository(db), log.Default())
+	handler := NewAgencyHandlerSimple(agencies, nil, log.Default())
+	request := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/api/agencies/acme-agency", nil)
+		return req.WithContext(context.WithValue(req.Context(), middleware.TenantIDKey, "beta"))
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		mock.ExpectQuery(`FROM agencies\s+WHERE id = \$1 AND tenant_id = \$2`).
+			WithArgs("acme-agency", "beta").
+			WillReturnError(sql.ErrNoRows)
+		rr := httptest.NewRecorder()
+		if method == http.MethodGet {
+			handler.GetAgency(rr, request(method))
+		} else {
+			handler.DeleteAgency(rr, request(method))
+		}
+		assert.Equal(t, http.StatusNotFound, rr.Code, method)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
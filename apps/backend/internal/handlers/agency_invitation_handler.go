@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// AgencyInvitationHandler handles agent invitations to agencies
+type AgencyInvitationHandler struct {
+	invitationService *service.AgencyInvitationService
+	logger            *log.Logger
+}
+
+// NewAgencyInvitationHandler creates a new agency invitation handler
+func NewAgencyInvitationHandler(invitationService *service.AgencyInvitationService, logger *log.Logger) *AgencyInvitationHandler {
+	return &AgencyInvitationHandler{
+		invitationService: invitationService,
+		logger:            logger,
+	}
+}
+
+// CreateInvitation handles POST /api/agencies/{id}/invitations ({"email": "..."})
+func (h *AgencyInvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Email == "" {
+		http.Error(w, "Email required", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.invitationService.InviteAgent(agencyID, req.Email, middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.sendInvitationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, invitation, http.StatusCreated)
+}
+
+// ListInvitations handles GET /api/agencies/{id}/invitations?status=pending
+func (h *AgencyInvitationHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	invitations, err := h.invitationService.ListInvitations(agencyID, r.URL.Query().Get("status"))
+	if err != nil {
+		h.sendInvitationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"agency_id":   agencyID,
+		"invitations": invitations,
+		"count":       len(invitations),
+	}, http.StatusOK)
+}
+
+// RevokeInvitation handles DELETE /api/agencies/{id}/invitations/{invitationId}
+func (h *AgencyInvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	invitationID := pathSegment(r.URL.Path, 4)
+	if agencyID == "" || invitationID == "" {
+		http.Error(w, "Agency ID and invitation ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.invitationService.RevokeInvitation(agencyID, invitationID); err != nil {
+		h.sendInvitationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInvitation handles POST /api/invitations/accept ({"token": "..."}).
+// The authenticated user joins the inviting agency as an agent.
+func (h *AgencyInvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Token == "" {
+		http.Error(w, "Token required", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.invitationService.AcceptInvitation(req.Token, userID)
+	if err != nil {
+		h.sendInvitationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, invitation, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *AgencyInvitationHandler) sendInvitationError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "expired"):
+		http.Error(w, err.Error(), http.StatusGone)
+	case strings.Contains(err.Error(), "different email"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already"), strings.Contains(err.Error(), "invitation is"),
+		strings.Contains(err.Error(), "cannot"), strings.Contains(err.Error(), "not active"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Agency invitation error: %v", err)
+		http.Error(w, "Failed to process invitation", http.StatusInternalServerError)
+	}
+}
+
+func (h *AgencyInvitationHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
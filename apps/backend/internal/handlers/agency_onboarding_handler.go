@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// AgencyOnboardingHandler serves the setup checklist of agencies
+type AgencyOnboardingHandler struct {
+	onboardingService *service.AgencyOnboardingService
+	logger            *log.Logger
+}
+
+// NewAgencyOnboardingHandler creates a new agency onboarding handler
+func NewAgencyOnboardingHandler(onboardingService *service.AgencyOnboardingService, logger *log.Logger) *AgencyOnboardingHandler {
+	return &AgencyOnboardingHandler{
+		onboardingService: onboardingService,
+		logger:            logger,
+	}
+}
+
+// GetOnboarding handles GET /api/agencies/{id}/onboarding
+// Each step reports whether it is completed: license set, logo uploaded, first agent
+// invited, first listing published and payment method added.
+func (h *AgencyOnboardingHandler) GetOnboarding(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[2] == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	onboarding, err := h.onboardingService.GetOnboarding(parts[2], actor)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "permission denied"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			h.logger.Printf("Agency onboarding error: %v", err)
+			http.Error(w, "Failed to get onboarding", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(onboarding)
+}
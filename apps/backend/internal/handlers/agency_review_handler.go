@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// AgencyReviewHandler serves agency reviews and reputation scores
+type AgencyReviewHandler struct {
+	agencyReviewService *service.AgencyReviewService
+	logger              *log.Logger
+}
+
+// NewAgencyReviewHandler creates a new agency review handler
+func NewAgencyReviewHandler(agencyReviewService *service.AgencyReviewService, logger *log.Logger) *AgencyReviewHandler {
+	return &AgencyReviewHandler{
+		agencyReviewService: agencyReviewService,
+		logger:              logger,
+	}
+}
+
+// GetReputation handles GET /api/agencies/{id}/reputation
+func (h *AgencyReviewHandler) GetReputation(w http.ResponseWriter, r *http.Request) {
+	reputation, err := h.agencyReviewService.GetReputation(pathSegment(r.URL.Path, 2))
+	if err != nil {
+		h.sendAgencyReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, reputation, http.StatusOK)
+}
+
+// ListReviews handles GET /api/agencies/{id}/reviews?limit=20&offset=0
+// The page of published reviews comes with the agency's reputation.
+func (h *AgencyReviewHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	reviews, total, err := h.agencyReviewService.ListReviews(agencyID, limit, offset)
+	if err != nil {
+		h.sendAgencyReviewError(w, err)
+		return
+	}
+	reputation, err := h.agencyReviewService.GetReputation(agencyID)
+	if err != nil {
+		h.sendAgencyReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"reviews":    reviews,
+		"total":      total,
+		"reputation": reputation,
+	}, http.StatusOK)
+}
+
+// SubmitReview handles POST /api/agencies/{id}/reviews
+// Submitting again replaces the caller's review, which goes back to moderation.
+func (h *AgencyReviewHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req service.SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.agencyReviewService.SubmitReview(pathSegment(r.URL.Path, 2), req, actor(r))
+	if err != nil {
+		h.sendAgencyReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, review, http.StatusCreated)
+}
+
+// Helper functions
+
+func (h *AgencyReviewHandler) sendAgencyReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Agency review error: %v", err)
+		http.Error(w, "Failed to process agency review", http.StatusInternalServerError)
+	}
+}
+
+func (h *AgencyReviewHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
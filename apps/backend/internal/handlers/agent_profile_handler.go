@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// AgentProfileHandler serves public agent profiles and their reviews. Review
+// moderation is served by ReviewHandler.
+type AgentProfileHandler struct {
+	profileService *service.AgentProfileService
+	logger         *log.Logger
+}
+
+// NewAgentProfileHandler creates a new agent profile handler
+func NewAgentProfileHandler(profileService *service.AgentProfileService, logger *log.Logger) *AgentProfileHandler {
+	return &AgentProfileHandler{
+		profileService: profileService,
+		logger:         logger,
+	}
+}
+
+// GetProfile handles GET /api/agents/{id}/profile
+func (h *AgentProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.profileService.GetProfile(pathSegment(r.URL.Path, 2))
+	if err != nil {
+		h.sendProfileError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, profile, http.StatusOK)
+}
+
+// ListReviews handles GET /api/agents/{id}/reviews?limit=20&offset=0
+func (h *AgentProfileHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	reviews, total, err := h.profileService.ListReviews(pathSegment(r.URL.Path, 2), limit, offset)
+	if err != nil {
+		h.sendProfileError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"reviews": reviews,
+		"total":   total,
+	}, http.StatusOK)
+}
+
+// SubmitReview handles POST /api/agents/{id}/reviews
+// Submitting again replaces the caller's review, which goes back to moderation.
+func (h *AgentProfileHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req service.SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.profileService.SubmitReview(pathSegment(r.URL.Path, 2), req, actor(r))
+	if err != nil {
+		h.sendProfileError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, review, http.StatusCreated)
+}
+
+// Helper functions
+
+func (h *AgentProfileHandler) sendProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Agent profile error: %v", err)
+		http.Error(w, "Failed to process agent profile", http.StatusInternalServerError)
+	}
+}
+
+func (h *AgentProfileHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
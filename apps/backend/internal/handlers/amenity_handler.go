@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// AmenityHandler handles the amenity catalog and the amenities of properties
+type AmenityHandler struct {
+	amenityService *service.AmenityService
+	logger         *log.Logger
+}
+
+// NewAmenityHandler creates a new amenity handler
+func NewAmenityHandler(amenityService *service.AmenityService, logger *log.Logger) *AmenityHandler {
+	return &AmenityHandler{
+		amenityService: amenityService,
+		logger:         logger,
+	}
+}
+
+// ListCatalog handles GET /api/amenities?include_inactive=true
+// Deactivated entries are only listed for admins.
+func (h *AmenityHandler) ListCatalog(w http.ResponseWriter, r *http.Request) {
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+
+	amenities, err := h.amenityService.ListCatalog(includeInactive, actor(r))
+	if err != nil {
+		h.sendAmenityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"amenities": amenities,
+		"count":     len(amenities),
+	}, http.StatusOK)
+}
+
+// CreateAmenity handles POST /api/amenities (admin only)
+// ({"code": "generator", "name_es": "Generador eléctrico", "name_en": "Power generator", "category": "utilities"})
+func (h *AmenityHandler) CreateAmenity(w http.ResponseWriter, r *http.Request) {
+	var amenity domain.Amenity
+	if err := json.NewDecoder(r.Body).Decode(&amenity); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.amenityService.CreateAmenity(&amenity, actor(r))
+	if err != nil {
+		h.sendAmenityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, created, http.StatusCreated)
+}
+
+// UpdateAmenity handles PUT /api/amenities/{code} (admin only), replacing the entry
+// Setting "active": false hides the amenity from listing forms.
+func (h *AmenityHandler) UpdateAmenity(w http.ResponseWriter, r *http.Request) {
+	code := pathSegment(r.URL.Path, 2)
+	if code == "" {
+		http.Error(w, "Amenity code required", http.StatusBadRequest)
+		return
+	}
+
+	var amenity domain.Amenity
+	if err := json.NewDecoder(r.Body).Decode(&amenity); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.amenityService.UpdateAmenity(code, &amenity, actor(r))
+	if err != nil {
+		h.sendAmenityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, updated, http.StatusOK)
+}
+
+// DeleteAmenity handles DELETE /api/amenities/{code} (admin only)
+func (h *AmenityHandler) DeleteAmenity(w http.ResponseWriter, r *http.Request) {
+	code := pathSegment(r.URL.Path, 2)
+	if code == "" {
+		http.Error(w, "Amenity code required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.amenityService.DeleteAmenity(code, actor(r)); err != nil {
+		h.sendAmenityError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPropertyAmenities handles GET /api/properties/{id}/amenities
+func (h *AmenityHandler) GetPropertyAmenities(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	amenities, err := h.amenityService.GetPropertyAmenities(propertyID)
+	if err != nil {
+		h.sendAmenityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"amenities":   amenities,
+	}, http.StatusOK)
+}
+
+// SetPropertyAmenities handles PUT /api/properties/{id}/amenities
+// ({"amenities": [{"code": "cistern", "details": {"capacity_liters": 2000}}, {"code": "pet_friendly"}]})
+func (h *AmenityHandler) SetPropertyAmenities(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Amenities []domain.PropertyAmenity `json:"amenities"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	amenities, err := h.amenityService.SetPropertyAmenities(propertyID, req.Amenities, actor(r))
+	if err != nil {
+		h.sendAmenityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"amenities":   amenities,
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *AmenityHandler) sendAmenityError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "already exists"), strings.Contains(err.Error(), "in use"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Amenity error: %v", err)
+		http.Error(w, "Failed to process amenities", http.StatusInternalServerError)
+	}
+}
+
+func (h *AmenityHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// APIKeyHandler lets administrators issue, revoke and monitor the keys of the public API,
+// and agencies see the usage of their own keys
+type APIKeyHandler struct {
+	keyService *service.APIKeyService
+	logger     *log.Logger
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(keyService *service.APIKeyService, logger *log.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		keyService: keyService,
+		logger:     logger,
+	}
+}
+
+// CreateAPIKeyRequest is the body of POST /api/admin/api-keys
+type CreateAPIKeyRequest struct {
+	Name         string `json:"name"`
+	ContactEmail string `json:"contact_email"`
+	Tier         string `json:"tier"`
+	AgencyID     string `json:"agency_id,omitempty"`
+}
+
+// ListKeys handles GET /api/admin/api-keys
+func (h *APIKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := h.keyService.ListKeys(actor(r))
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{
+		"keys":  keys,
+		"tiers": h.keyService.Tiers(),
+	}, http.StatusOK)
+}
+
+// CreateKey handles POST /api/admin/api-keys
+// The key is only returned in this response; store it before closing it.
+func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	key, plaintext, err := h.keyService.CreateKey(actor(r), req.Name, req.ContactEmail, req.Tier, req.AgencyID)
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{
+		"key":     plaintext,
+		"api_key": key,
+	}, http.StatusCreated)
+}
+
+// RevokeKey handles DELETE /api/admin/api-keys/{id}
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), "/api/admin/api-keys/")
+	if id == "" || strings.Contains(id, "/") {
+		http.Error(w, "API key ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.keyService.RevokeKey(actor(r), id); err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetUsage handles GET /api/admin/api-keys/{id}/usage?from=2025-09-01&to=2025-09-30
+// Days are UTC; the range defaults to the last 30 days.
+func (h *APIKeyHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/api-keys/"), "/usage")
+	if id == "" || strings.Contains(id, "/") {
+		http.Error(w, "API key ID required", http.StatusBadRequest)
+		return
+	}
+
+	from, to, ok := h.usageRange(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.keyService.GetUsage(actor(r), id, from, to)
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	var total domain.APIUsageCounts
+	for _, day := range usage {
+		total.Add(day.APIUsageCounts)
+	}
+	h.sendJSONResponse(w, map[string]interface{}{
+		"api_key_id": id,
+		"from":       from.Format("2006-01-02"),
+		"to":         to.Format("2006-01-02"),
+		"total":      total.Requests,
+		"totals":     total,
+		"days":       usage,
+	}, http.StatusOK)
+}
+
+// GetUsageReport handles GET /api/admin/usage?from=2025-09-01&to=2025-09-30&agency_id=...
+// It meters every consumer of the public API, or those of an agency, with requests
+// served and rejected and bytes sent per consumer and per day. Days are UTC; the range
+// defaults to the last 30 days.
+func (h *APIKeyHandler) GetUsageReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, ok := h.usageRange(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.keyService.UsageReport(actor(r), from, to, r.URL.Query().Get("agency_id"))
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+// GetAgencyUsage handles GET /api/agencies/{id}/usage?from=2025-09-01&to=2025-09-30
+// It reports the public API usage of the agency's keys to the agency and to admins.
+func (h *APIKeyHandler) GetAgencyUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	from, to, ok := h.usageRange(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.keyService.AgencyUsageReport(actor(r), agencyID, from, to)
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+// Helper functions
+
+// usageRange parses the ?from= and ?to= days of usage requests, the last 30 days by
+// default. It answers 400 and returns false when a day is not in the form YYYY-MM-DD.
+func (h *APIKeyHandler) usageRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -29)
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return from, to, false
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return from, to, false
+		}
+	}
+	return from, to, true
+}
+
+func (h *APIKeyHandler) sendKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("API key error: %v", err)
+		http.Error(w, "Failed to process API keys", http.StatusInternalServerError)
+	}
+}
+
+func (h *APIKeyHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// calendarCacheMaxAge is how long calendar apps may reuse an agent's calendar feed;
+// Google Calendar refreshes subscriptions every few hours regardless
+const calendarCacheMaxAge = 5 * time.Minute
+
+// defaultAppointmentRange is the period listed when no range is given
+const defaultAppointmentRange = 30 * 24 * time.Hour
+
+// AppointmentHandler serves the agenda of visits and open houses of agents and its
+// calendar feed
+type AppointmentHandler struct {
+	appointmentService *service.AppointmentService
+	logger             *log.Logger
+}
+
+// NewAppointmentHandler creates a new appointment handler
+func NewAppointmentHandler(appointmentService *service.AppointmentService, logger *log.Logger) *AppointmentHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+		logger:             logger,
+	}
+}
+
+// AgentAppointments handles GET and POST /api/agents/{id}/appointments
+// GET lists the appointments starting between ?from= and ?to= (dates or RFC 3339 times,
+// the next 30 days by default). POST schedules one:
+// {"property_id": "...", "kind": "visit", "starts_at": "2025-09-20T10:00", "duration_minutes": 45}
+// Times without an offset are local times of the property, in Galápagos an hour behind
+// the mainland.
+func (h *AppointmentHandler) AgentAppointments(w http.ResponseWriter, r *http.Request) {
+	agentID := pathSegment(r.URL.Path, 2)
+	if agentID == "" {
+		http.Error(w, "Agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		from, err := parseDateParam(r.URL.Query().Get("from"))
+		if err != nil {
+			http.Error(w, "Invalid from date", http.StatusBadRequest)
+			return
+		}
+		to, err := parseDateParam(r.URL.Query().Get("to"))
+		if err != nil {
+			http.Error(w, "Invalid to date", http.StatusBadRequest)
+			return
+		}
+		if from == nil {
+			today := domain.CalendarDate(time.Now())
+			from = &today
+		}
+		if to == nil {
+			end := from.Add(defaultAppointmentRange)
+			to = &end
+		}
+
+		appointments, err := h.appointmentService.List(agentID, *from, *to, actor(r))
+		if err != nil {
+			h.sendAppointmentError(w, err)
+			return
+		}
+		h.sendJSONResponse(w, map[string]interface{}{
+			"agent_id":     agentID,
+			"from":         from,
+			"to":           to,
+			"appointments": appointments,
+			"count":        len(appointments),
+		}, http.StatusOK)
+
+	case http.MethodPost:
+		var req service.ScheduleAppointmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		appointment, err := h.appointmentService.Schedule(agentID, req, actor(r))
+		if err != nil {
+			h.sendAppointmentError(w, err)
+			return
+		}
+		h.sendJSONResponse(w, appointment, http.StatusCreated)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// CancelAppointment handles DELETE /api/appointments/{id}
+// The appointment stays in the calendar feed as cancelled so subscribed calendars drop it.
+func (h *AppointmentHandler) CancelAppointment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	appointmentID := pathSegment(r.URL.Path, 2)
+	if appointmentID == "" {
+		http.Error(w, "Appointment ID required", http.StatusBadRequest)
+		return
+	}
+
+	appointment, err := h.appointmentService.Cancel(appointmentID, actor(r))
+	if err != nil {
+		h.sendAppointmentError(w, err)
+		return
+	}
+	h.sendJSONResponse(w, appointment, http.StatusOK)
+}
+
+// RotateCalendarToken handles POST /api/agents/{id}/calendar-token
+// It returns the URL to subscribe to from Google Calendar ("From URL") or any other
+// calendar app. The token is shown once; rotating it again revokes the previous URL.
+func (h *AppointmentHandler) RotateCalendarToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := pathSegment(r.URL.Path, 2)
+	if agentID == "" {
+		http.Error(w, "Agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.appointmentService.RotateCalendarToken(agentID, actor(r))
+	if err != nil {
+		h.sendAppointmentError(w, err)
+		return
+	}
+
+	feed, err := url.Parse(requestURL(r))
+	if err != nil {
+		h.sendAppointmentError(w, err)
+		return
+	}
+	feed.Path = "/api/agents/" + url.PathEscape(agentID) + "/calendar.ics"
+	feed.RawQuery = url.Values{"token": {token}}.Encode()
+	webcal := *feed
+	webcal.Scheme = "webcal"
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{
+		"token":      token,
+		"url":        feed.String(),
+		"webcal_url": webcal.String(),
+	}, http.StatusCreated)
+}
+
+// Calendar handles GET /api/agents/{id}/calendar.ics?token=...
+// It serves the agent's visits and open houses of the last 30 days and the next year as
+// an iCalendar feed, authenticated by the calendar token since calendar apps cannot sign in.
+func (h *AppointmentHandler) Calendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agentID := pathSegment(r.URL.Path, 2)
+	if agentID == "" || pathSegment(r.URL.Path, 3) != "calendar.ics" {
+		http.Error(w, "Agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := h.appointmentService.Calendar(agentID, r.URL.Query().Get("token"))
+	if err != nil {
+		h.sendAppointmentError(w, err)
+		return
+	}
+	body := domain.RenderICalendar(calendar, time.Now())
+
+	header := w.Header()
+	header.Set("Content-Type", "text/calendar; charset=utf-8")
+	header.Set("Content-Disposition", `inline; filename="agenda.ics"`)
+	header.Set("Cache-Control", "private, max-age="+strconv.Itoa(int(calendarCacheMaxAge.Seconds())))
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// Helper functions
+
+func (h *AppointmentHandler) sendAppointmentError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid calendar token"):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case strings.Contains(err.Error(), "conflicts with"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Appointment error: %v", err)
+		http.Error(w, "Failed to process appointment request", http.StatusInternalServerError)
+	}
+}
+
+func (h *AppointmentHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
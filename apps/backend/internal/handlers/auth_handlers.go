@@ -15,9 +15,11 @@ import (
 
 // AuthHandlers handles authentication endpoints
 type AuthHandlers struct {
-	userService *service.UserServiceSimple
-	jwtManager  *auth.JWTManager
-	logger      *logging.Logger
+	userService     *service.UserServiceSimple
+	jwtManager      *auth.JWTManager
+	loginLocations  *service.LoginLocationService
+	sessions        *service.SessionService
+	logger          *logging.Logger
 }
 
 // NewAuthHandlers creates a new auth handlers instance
@@ -29,6 +31,24 @@ func NewAuthHandlers(userService *service.UserServiceSimple, jwtManager *auth.JW
 	}
 }
 
+// tenantService returns the user service scoped to the tenant of the request, so users
+// sign in to the tenant they belong to
+func (ah *AuthHandlers) tenantService(r *http.Request) *service.UserServiceSimple {
+	return ah.userService.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
+// SetLoginLocations records the location of every login and alerts users of logins
+// from new locations
+func (ah *AuthHandlers) SetLoginLocations(loginLocations *service.LoginLocationService) {
+	ah.loginLocations = loginLocations
+}
+
+// SetSessions persists the refresh token of every login as a session users can list
+// and revoke
+func (ah *AuthHandlers) SetSessions(sessions *service.SessionService) {
+	ah.sessions = sessions
+}
+
 // LoginRequest represents login request payload
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -71,7 +91,7 @@ func (ah *AuthHandlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
 
 	// Authenticate user
-	user, err := ah.userService.AuthenticateUser(req.Email, req.Password)
+	user, err := ah.tenantService(r).AuthenticateUser(req.Email, req.Password)
 	if err != nil {
 		// Log security event
 		if ah.logger != nil {
@@ -100,12 +120,22 @@ func (ah *AuthHandlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		user.Email,
 		string(user.Role),
 		agencyID,
+		middleware.GetTenantID(r.Context()),
 	)
 	if err != nil {
 		ah.handleError(w, "Failed to generate authentication tokens", http.StatusInternalServerError, err)
 		return
 	}
 
+	if ah.sessions != nil {
+		_, err := ah.sessions.Start(user.ID, tokenPair.SessionID, tokenPair.RefreshToken, getClientIP(r), r.UserAgent(),
+			time.Now().Add(ah.jwtManager.RefreshTokenTTL()))
+		if err != nil {
+			ah.handleError(w, "Failed to start session", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	// Calculate expiration time
 	expiresAt := time.Now().Add(15 * time.Minute).Format(time.RFC3339)
 
@@ -119,6 +149,17 @@ func (ah *AuthHandlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	// Record where the session was started from; the location was resolved by the
+	// GeoIP middleware
+	if ah.loginLocations != nil {
+		location := middleware.GetGeoLocation(r.Context())
+		if _, err := ah.loginLocations.RecordLogin(user, tokenPair.SessionID, getClientIP(r), r.UserAgent(), location); err != nil && ah.logger != nil {
+			ah.logger.Error("Failed to record login location", err, map[string]interface{}{
+				"user_id": user.ID,
+			})
+		}
+	}
+
 	// Response
 	response := LoginResponse{
 		User:      user,
@@ -153,8 +194,14 @@ func (ah *AuthHandlers) RefreshTokenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Refresh tokens only renew sessions of the tenant they were issued in
+	if refreshClaims.TenantID != middleware.GetTenantID(r.Context()) {
+		ah.handleError(w, "Invalid or expired refresh token", http.StatusUnauthorized, nil)
+		return
+	}
+
 	// Get user info
-	user, err := ah.userService.GetUser(refreshClaims.UserID)
+	user, err := ah.tenantService(r).GetUser(refreshClaims.UserID)
 	if err != nil {
 		ah.handleError(w, "User not found", http.StatusNotFound, err)
 		return
@@ -183,6 +230,20 @@ func (ah *AuthHandlers) RefreshTokenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Rotate the stored refresh token; revoked sessions and replayed tokens are refused
+	if ah.sessions != nil {
+		err := ah.sessions.Rotate(user.ID, tokenPair.SessionID, req.RefreshToken, tokenPair.RefreshToken,
+			getClientIP(r), r.UserAgent(), time.Now().Add(ah.jwtManager.RefreshTokenTTL()))
+		if err != nil {
+			if strings.Contains(err.Error(), "session revoked") {
+				ah.handleError(w, "Session has been revoked", http.StatusUnauthorized, err)
+				return
+			}
+			ah.handleError(w, "Failed to refresh tokens", http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	// Blacklist old refresh token
 	ah.jwtManager.BlacklistRefreshToken(req.RefreshToken)
 
@@ -235,6 +296,16 @@ func (ah *AuthHandlers) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 		ah.jwtManager.BlacklistRefreshToken(req.RefreshToken)
 	}
 
+	// End the session, which stops its refresh token on every instance
+	if sessionID := middleware.GetSessionID(r.Context()); ah.sessions != nil && sessionID != "" {
+		if _, err := ah.sessions.Revoke(userID, sessionID, sessionID); err != nil && ah.logger != nil {
+			ah.logger.Error("Failed to revoke session on logout", err, map[string]interface{}{
+				"user_id":    userID,
+				"session_id": sessionID,
+			})
+		}
+	}
+
 	// Log successful logout
 	if ah.logger != nil {
 		ah.logger.Info("User logged out", map[string]interface{}{
@@ -255,6 +326,89 @@ func (ah *AuthHandlers) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// ListSessionsHandler handles GET /api/auth/sessions
+// It lists the active sessions of the user by device, IP and last activity; the
+// session of the request is flagged as current.
+func (ah *AuthHandlers) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		ah.handleError(w, "Authentication required", http.StatusUnauthorized, nil)
+		return
+	}
+	if ah.sessions == nil {
+		ah.handleError(w, "Sessions are not available", http.StatusNotFound, nil)
+		return
+	}
+
+	sessions, err := ah.sessions.List(userID, middleware.GetSessionID(r.Context()))
+	if err != nil {
+		ah.handleError(w, "Failed to list sessions", http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": sessions,
+		"total":    len(sessions),
+	})
+}
+
+// RevokeSessionHandler handles DELETE /api/auth/sessions/{id}
+// DELETE /api/auth/sessions/others revokes every session except the current one.
+func (ah *AuthHandlers) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		ah.handleError(w, "Authentication required", http.StatusUnauthorized, nil)
+		return
+	}
+	if ah.sessions == nil {
+		ah.handleError(w, "Sessions are not available", http.StatusNotFound, nil)
+		return
+	}
+
+	sessionID := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), "/api/auth/sessions/")
+	if sessionID == "" || strings.Contains(sessionID, "/") {
+		ah.handleError(w, "Session ID is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	revoked, err := ah.sessions.Revoke(userID, sessionID, middleware.GetSessionID(r.Context()))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			ah.handleError(w, "Session not found", http.StatusNotFound, nil)
+		case strings.Contains(err.Error(), "invalid"):
+			ah.handleError(w, err.Error(), http.StatusBadRequest, nil)
+		default:
+			ah.handleError(w, "Failed to revoke session", http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if ah.logger != nil {
+		ah.logger.SecurityEvent(
+			"Session Revoked",
+			middleware.GetUserEmail(r.Context()),
+			"User revoked sessions",
+			map[string]interface{}{
+				"user_id":    userID,
+				"session_id": sessionID,
+				"revoked":    revoked,
+				"ip":         getClientIP(r),
+			},
+		)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Sessions revoked",
+		"revoked":   revoked,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
 // ValidateTokenHandler validates current token (health check for auth)
 func (ah *AuthHandlers) ValidateTokenHandler(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
@@ -263,7 +417,7 @@ func (ah *AuthHandlers) ValidateTokenHandler(w http.ResponseWriter, r *http.Requ
 	agencyID := middleware.GetAgencyID(r.Context())
 
 	// Get user info
-	user, err := ah.userService.GetUser(userID)
+	user, err := ah.tenantService(r).GetUser(userID)
 	if err != nil {
 		ah.handleError(w, "User not found", http.StatusNotFound, err)
 		return
@@ -318,7 +472,7 @@ func (ah *AuthHandlers) ChangePasswordHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	// Change password
-	err := ah.userService.ChangePassword(userID, req.CurrentPassword, req.NewPassword)
+	err := ah.tenantService(r).ChangePassword(userID, req.CurrentPassword, req.NewPassword)
 	if err != nil {
 		if strings.Contains(err.Error(), "current password") {
 			ah.handleError(w, "Current password is incorrect", http.StatusBadRequest, err)
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// defaultCalendarDays is the range of a calendar query without ?to=
+const defaultCalendarDays = 90
+
+// AvailabilityHandler serves the availability calendars of rental properties
+type AvailabilityHandler struct {
+	availabilityService *service.AvailabilityService
+	logger              *log.Logger
+}
+
+// NewAvailabilityHandler creates a new availability handler
+func NewAvailabilityHandler(availabilityService *service.AvailabilityService, logger *log.Logger) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+		logger:              logger,
+	}
+}
+
+// GetCalendar handles GET /api/properties/{id}/availability?from=2025-09-01&to=2025-12-01
+// from defaults to today and to to 90 days later; ranges span at most a year.
+func (h *AvailabilityHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from := domain.CalendarDate(time.Now())
+	if value := query.Get("from"); value != "" {
+		date, err := domain.ParseCalendarDate(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		from = date
+	}
+	to := from.AddDate(0, 0, defaultCalendarDays)
+	if value := query.Get("to"); value != "" {
+		date, err := domain.ParseCalendarDate(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to = date
+	}
+
+	calendar, err := h.availabilityService.GetCalendar(propertyID, from, to, actor(r))
+	if err != nil {
+		h.sendAvailabilityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, calendar, http.StatusOK)
+}
+
+// CheckStay handles GET /api/properties/{id}/availability/check?start=2025-10-01&end=2025-10-05
+// The response tells whether the stay can be booked and, if not, why.
+func (h *AvailabilityHandler) CheckStay(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	period, ok := h.parsePeriod(w, r.URL.Query().Get("start"), r.URL.Query().Get("end"))
+	if !ok {
+		return
+	}
+
+	response := map[string]interface{}{
+		"property_id": propertyID,
+		"start_date":  period.Start,
+		"end_date":    period.End,
+		"nights":      period.Nights(),
+		"available":   true,
+	}
+	if err := h.availabilityService.CheckStay(propertyID, period); err != nil {
+		if !strings.Contains(err.Error(), "not available") {
+			h.sendAvailabilityError(w, err)
+			return
+		}
+		response["available"] = false
+		response["reason"] = err.Error()
+	}
+
+	h.sendJSONResponse(w, response, http.StatusOK)
+}
+
+// UpdateSettings handles PUT /api/properties/{id}/availability/settings ({"min_stay_nights": 3})
+func (h *AvailabilityHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		MinStayNights int `json:"min_stay_nights"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.availabilityService.UpdateSettings(propertyID, req.MinStayNights, actor(r))
+	if err != nil {
+		h.sendAvailabilityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, settings, http.StatusOK)
+}
+
+// BlockDates handles POST /api/properties/{id}/availability/blocks
+// ({"start_date": "2025-12-20", "end_date": "2026-01-03", "note": "Owner holidays"})
+// The end date is exclusive: it is free for the next stay.
+func (h *AvailabilityHandler) BlockDates(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		Note      string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	period, ok := h.parsePeriod(w, req.StartDate, req.EndDate)
+	if !ok {
+		return
+	}
+
+	block, err := h.availabilityService.BlockDates(propertyID, period, req.Note, actor(r))
+	if err != nil {
+		h.sendAvailabilityError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, block, http.StatusCreated)
+}
+
+// UnblockDates handles DELETE /api/properties/{id}/availability/blocks/{blockId}
+func (h *AvailabilityHandler) UnblockDates(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	blockID := pathSegment(r.URL.Path, 5)
+	if propertyID == "" || blockID == "" {
+		http.Error(w, "Property ID and block ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.availabilityService.UnblockDates(propertyID, blockID, actor(r)); err != nil {
+		h.sendAvailabilityError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Helper functions
+
+// parsePeriod parses a start and exclusive end date, answering 400 when they are invalid
+func (h *AvailabilityHandler) parsePeriod(w http.ResponseWriter, startValue, endValue string) (domain.StayPeriod, bool) {
+	start, err := domain.ParseCalendarDate(startValue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.StayPeriod{}, false
+	}
+	end, err := domain.ParseCalendarDate(endValue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.StayPeriod{}, false
+	}
+	period, err := domain.NewStayPeriod(start, end)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return domain.StayPeriod{}, false
+	}
+	return period, true
+}
+
+func (h *AvailabilityHandler) sendAvailabilityError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "not available"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Availability error: %v", err)
+		http.Error(w, "Failed to process availability", http.StatusInternalServerError)
+	}
+}
+
+func (h *AvailabilityHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
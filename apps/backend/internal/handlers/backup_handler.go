@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// BackupHandler reports the verification of the database backups to monitoring
+type BackupHandler struct {
+	backups *service.BackupVerificationService
+	logger  *log.Logger
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(backups *service.BackupVerificationService, logger *log.Logger) *BackupHandler {
+	return &BackupHandler{
+		backups: backups,
+		logger:  logger,
+	}
+}
+
+// Backups handles GET /api/monitoring/backups?limit=
+// It sums up the latest verifications of the dumps, answering 503 when the latest one
+// failed or none passed recently, like the health checks, so uptime probes alert on it.
+func (h *BackupHandler) Backups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	status, err := h.backups.Status(limit)
+	if err != nil {
+		h.logger.Printf("Error getting backup status: %v", err)
+		http.Error(w, "Failed to get backup status", http.StatusInternalServerError)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if status.Status == domain.BackupStatusFailing || status.Status == domain.BackupStatusStale {
+		statusCode = http.StatusServiceUnavailable
+	}
+	h.sendJSONResponse(w, status, statusCode)
+}
+
+func (h *BackupHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// CalendarSyncHandler handles connecting agents' Google Calendars, syncing them and
+// the conflicts between appointments and imported busy times
+type CalendarSyncHandler struct {
+	syncService *service.CalendarSyncService
+	logger      *log.Logger
+}
+
+// NewCalendarSyncHandler creates a new calendar sync handler
+func NewCalendarSyncHandler(syncService *service.CalendarSyncService, logger *log.Logger) *CalendarSyncHandler {
+	return &CalendarSyncHandler{
+		syncService: syncService,
+		logger:      logger,
+	}
+}
+
+// CalendarSync handles GET and DELETE /api/agents/{id}/calendar-sync
+// GET returns the agent's connection and when it last synced; DELETE disconnects the
+// calendar, keeping the events already pushed to it.
+func (h *CalendarSyncHandler) CalendarSync(w http.ResponseWriter, r *http.Request) {
+	agentID := pathSegment(r.URL.Path, 2)
+	if agentID == "" {
+		http.Error(w, "Agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		connection, err := h.syncService.GetConnection(agentID, actor(r))
+		if err != nil {
+			h.sendCalendarSyncError(w, err)
+			return
+		}
+		h.sendJSONResponse(w, connection, http.StatusOK)
+
+	case http.MethodDelete:
+		if err := h.syncService.Disconnect(agentID, actor(r)); err != nil {
+			h.sendCalendarSyncError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Connect handles POST /api/agents/{id}/calendar-sync/connect
+// It returns the Google consent page to send the agent to; Google redirects back to
+// the OAuth callback.
+func (h *CalendarSyncHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authorizationURL, err := h.syncService.Connect(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendCalendarSyncError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{"authorization_url": authorizationURL}, http.StatusOK)
+}
+
+// GoogleCallback handles GET /api/calendar/google/callback?state=...&code=...
+// Google redirects the agent here after consent; the state identifies the agent, so
+// it needs no session.
+func (h *CalendarSyncHandler) GoogleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if reason := query.Get("error"); reason != "" {
+		http.Error(w, "Calendar access was not granted: "+reason, http.StatusBadRequest)
+		return
+	}
+
+	connection, err := h.syncService.CompleteAuthorization(query.Get("state"), query.Get("code"))
+	if err != nil {
+		h.sendCalendarSyncError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, connection, http.StatusOK)
+}
+
+// Sync handles POST /api/agents/{id}/calendar-sync/sync
+// The calendar is otherwise synced every few minutes.
+func (h *CalendarSyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.syncService.Sync(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendCalendarSyncError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+// Conflicts handles GET /api/agents/{id}/calendar-sync/conflicts
+// It lists upcoming appointments overlapping busy times imported after they were
+// scheduled.
+func (h *CalendarSyncHandler) Conflicts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	conflicts, err := h.syncService.Conflicts(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendCalendarSyncError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{"conflicts": conflicts}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *CalendarSyncHandler) sendCalendarSyncError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "unavailable"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case strings.Contains(err.Error(), "access revoked"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Calendar sync error: %v", err)
+		http.Error(w, "Failed to process calendar sync request", http.StatusInternalServerError)
+	}
+}
+
+func (h *CalendarSyncHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
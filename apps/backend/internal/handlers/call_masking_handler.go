@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"realty-core/internal/service"
+	"realty-core/internal/voice"
+)
+
+// CallMaskingHandler handles the proxy numbers buyers and agents call each other
+// through, and the Twilio webhooks forwarding those calls
+type CallMaskingHandler struct {
+	callService *service.CallMaskingService
+	logger      *log.Logger
+}
+
+// NewCallMaskingHandler creates a new call masking handler
+func NewCallMaskingHandler(callService *service.CallMaskingService, logger *log.Logger) *CallMaskingHandler {
+	return &CallMaskingHandler{
+		callService: callService,
+		logger:      logger,
+	}
+}
+
+// GetCallSession handles POST /api/leads/{id}/call
+// It returns the proxy number the lead's buyer and agent call each other through,
+// lending one to the lead on its first call.
+func (h *CallMaskingHandler) GetCallSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session, err := h.callService.GetCallSession(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendCallError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, session, http.StatusOK)
+}
+
+// Voice handles POST /api/calls/voice, Twilio's request for instructions on a call to
+// a proxy number. It is authenticated by the X-Twilio-Signature header.
+func (h *CallMaskingHandler) Voice(w http.ResponseWriter, r *http.Request) {
+	h.webhook(w, r, h.callService.AnswerCall)
+}
+
+// Status handles POST /api/calls/status, Twilio reporting how a forwarded call ended
+func (h *CallMaskingHandler) Status(w http.ResponseWriter, r *http.Request) {
+	h.webhook(w, r, h.callService.RecordCallStatus)
+}
+
+// Helper functions
+
+// webhook verifies and answers a Twilio form post with TwiML
+func (h *CallMaskingHandler) webhook(w http.ResponseWriter, r *http.Request, answer func(params url.Values, signature string) ([]byte, error)) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	twiml, err := answer(r.PostForm, r.Header.Get(voice.SignatureHeader))
+	if err != nil {
+		h.sendCallError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(twiml)
+}
+
+func (h *CallMaskingHandler) sendCallError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "unavailable"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Call masking error: %v", err)
+		http.Error(w, "Failed to process call", http.StatusInternalServerError)
+	}
+}
+
+func (h *CallMaskingHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
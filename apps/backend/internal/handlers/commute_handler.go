@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// CommuteHandler serves commute estimates from properties
+type CommuteHandler struct {
+	commuteService *service.CommuteService
+	logger         *log.Logger
+}
+
+// NewCommuteHandler creates a new commute handler
+func NewCommuteHandler(commuteService *service.CommuteService, logger *log.Logger) *CommuteHandler {
+	return &CommuteHandler{
+		commuteService: commuteService,
+		logger:         logger,
+	}
+}
+
+// GetCommute handles GET /api/properties/{id}/commute?to=lat,lng&mode=driving|transit
+// Without mode the response has both driving and public transport estimates, each
+// omitted when the routing provider cannot route it.
+func (h *CommuteHandler) GetCommute(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[2] == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+	propertyID := parts[2]
+
+	query := r.URL.Query()
+	if query.Get("to") == "" {
+		http.Error(w, "Destination required: to=lat,lng", http.StatusBadRequest)
+		return
+	}
+	to, err := domain.ParseGeoPoint(query.Get("to"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	commute, err := h.commuteService.GetCommute(propertyID, to, query.Get("mode"))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "invalid"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case strings.Contains(err.Error(), "no coordinates"):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case strings.Contains(err.Error(), "no routing provider"):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			h.logger.Printf("Error estimating commute from property %s: %v", propertyID, err)
+			http.Error(w, "Failed to estimate commute", http.StatusBadGateway)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	json.NewEncoder(w).Encode(commute)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"realty-core/internal/config"
+)
+
+// ConfigHandler exposes the effective configuration and its reload to administrators
+type ConfigHandler struct {
+	watcher *config.Watcher
+	logger  *log.Logger
+}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler(watcher *config.Watcher, logger *log.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		watcher: watcher,
+		logger:  logger,
+	}
+}
+
+// GetConfig handles GET /api/admin/config
+// Credentials are masked; reloadable lists the settings a reload applies without a restart.
+func (h *ConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{
+		"config":      h.watcher.Current().Redacted(),
+		"reloadable":  config.ReloadableSettings(),
+		"last_reload": h.watcher.LastReload(),
+	}, http.StatusOK)
+}
+
+// Reload handles POST /api/admin/config/reload, the API equivalent of sending SIGHUP
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := h.watcher.Reload("api")
+	if err != nil {
+		if _, ok := err.(*config.ConfigError); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Printf("Error reloading configuration: %v", err)
+		http.Error(w, "Failed to reload configuration", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSONResponse(w, result, http.StatusOK)
+}
+
+func (h *ConfigHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
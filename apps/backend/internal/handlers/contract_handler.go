@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ContractHandler handles contract templates and contract generation
+type ContractHandler struct {
+	contractService *service.ContractService
+	logger          *log.Logger
+}
+
+// NewContractHandler creates a new contract handler
+func NewContractHandler(contractService *service.ContractService, logger *log.Logger) *ContractHandler {
+	return &ContractHandler{
+		contractService: contractService,
+		logger:          logger,
+	}
+}
+
+// ListTemplates handles GET /api/contract-templates?type=rental and lists active templates
+func (h *ContractHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.contractService.ListTemplates(r.URL.Query().Get("type"))
+	if err != nil {
+		h.sendContractError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"templates": templates,
+		"count":     len(templates),
+	}, http.StatusOK)
+}
+
+// ListTemplateVersions handles GET /api/admin/contract-templates/{name}/versions
+func (h *ContractHandler) ListTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	name := pathSegment(r.URL.Path, 3)
+	if name == "" {
+		http.Error(w, "Template name required", http.StatusBadRequest)
+		return
+	}
+
+	versions, err := h.contractService.ListTemplateVersions(name, actor(r))
+	if err != nil {
+		h.sendContractError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"name":     name,
+		"versions": versions,
+		"count":    len(versions),
+	}, http.StatusOK)
+}
+
+// SaveTemplate handles POST /api/admin/contract-templates and saves a new version of a
+// template. The first version of a template, or any with "activate": true, becomes active.
+func (h *ContractHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	var req service.SaveContractTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tmpl, err := h.contractService.SaveTemplate(req, actor(r))
+	if err != nil {
+		h.sendContractError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, tmpl, http.StatusCreated)
+}
+
+// ActivateTemplate handles POST /api/admin/contract-templates/{id}/activate
+func (h *ContractHandler) ActivateTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID := pathSegment(r.URL.Path, 3)
+	if templateID == "" {
+		http.Error(w, "Template ID required", http.StatusBadRequest)
+		return
+	}
+
+	tmpl, err := h.contractService.ActivateTemplate(templateID, actor(r))
+	if err != nil {
+		h.sendContractError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, tmpl, http.StatusOK)
+}
+
+// GenerateContract handles POST /api/properties/{id}/contracts. The PDF is stored as a
+// private document of the property, downloadable from GET /api/documents/{id}/download.
+func (h *ContractHandler) GenerateContract(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req service.GenerateContractRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	document, err := h.contractService.GenerateContract(propertyID, req, actor(r))
+	if err != nil {
+		h.sendContractError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, document, http.StatusCreated)
+}
+
+// Helper functions
+
+func (h *ContractHandler) sendContractError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "exceeded"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Contract error: %v", err)
+		http.Error(w, "Failed to process contract", http.StatusInternalServerError)
+	}
+}
+
+func (h *ContractHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
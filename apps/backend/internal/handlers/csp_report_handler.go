@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// CSPReportHandler collects the Content-Security-Policy violations browsers report and
+// lists them to administrators
+type CSPReportHandler struct {
+	reports *service.CSPReportService
+	logger  *log.Logger
+}
+
+// NewCSPReportHandler creates a new CSP report handler
+func NewCSPReportHandler(reports *service.CSPReportService, logger *log.Logger) *CSPReportHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &CSPReportHandler{
+		reports: reports,
+		logger:  logger,
+	}
+}
+
+// Report handles POST /api/security/csp-report
+// It takes the report-uri reports of application/csp-report and the Reporting API
+// batches of application/reports+json. Browsers ignore the response, so it is empty.
+func (h *CSPReportHandler) Report(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/csp-report", "application/reports+json", "application/json":
+	default:
+		http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, domain.MaxCSPReportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Report too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reports, err := domain.ParseCSPReports(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.reports.Record(reports, r.UserAgent())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Violations handles GET /api/admin/security/csp-reports?period=24h&limit=100
+// It lists the violations reported within the period, 7 days by default, most reported
+// first.
+func (h *CSPReportHandler) Violations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var period time.Duration
+	if value := r.URL.Query().Get("period"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid period, expected a duration such as 24h", http.StatusBadRequest)
+			return
+		}
+		period = parsed
+	}
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	violations, err := h.reports.Violations(period, limit, actor(r))
+	if err != nil {
+		h.sendCSPReportError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{"violations": violations}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *CSPReportHandler) sendCSPReportError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("CSP report error: %v", err)
+		http.Error(w, "Failed to list CSP reports", http.StatusInternalServerError)
+	}
+}
+
+func (h *CSPReportHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"realty-core/internal/service"
+)
+
+// CurrencyHandler handles the exchange rates used for display prices
+type CurrencyHandler struct {
+	currencyService *service.CurrencyService
+	logger          *log.Logger
+}
+
+// NewCurrencyHandler creates a new currency handler
+func NewCurrencyHandler(currencyService *service.CurrencyService, logger *log.Logger) *CurrencyHandler {
+	return &CurrencyHandler{
+		currencyService: currencyService,
+		logger:          logger,
+	}
+}
+
+// GetRates handles GET /api/exchange-rates
+func (h *CurrencyHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rates, err := h.currencyService.GetRates()
+	if err != nil {
+		h.logger.Printf("Exchange rates error: %v", err)
+		http.Error(w, "Exchange rates are unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	h.sendJSONResponse(w, rates, http.StatusOK)
+}
+
+func (h *CurrencyHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
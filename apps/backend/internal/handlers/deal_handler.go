@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// DealHandler handles closed deals and commission reports
+type DealHandler struct {
+	dealService *service.DealService
+	logger      *log.Logger
+}
+
+// NewDealHandler creates a new deal handler
+func NewDealHandler(dealService *service.DealService, logger *log.Logger) *DealHandler {
+	return &DealHandler{
+		dealService: dealService,
+		logger:      logger,
+	}
+}
+
+// RecordDeal handles POST /api/deals
+// ({"property_id": "...", "type": "sale", "final_price": 120000, "commission_percent": 3})
+// Rentals may book dates on the availability calendar with "rental_start" and "rental_end".
+func (h *DealHandler) RecordDeal(w http.ResponseWriter, r *http.Request) {
+	var req service.RecordDealRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.PropertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	deal, err := h.dealService.RecordDeal(req, actor(r))
+	if err != nil {
+		h.sendDealError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, deal, http.StatusCreated)
+}
+
+// GetDeal handles GET /api/deals/{id}
+func (h *DealHandler) GetDeal(w http.ResponseWriter, r *http.Request) {
+	dealID := pathSegment(r.URL.Path, 2)
+	if dealID == "" {
+		http.Error(w, "Deal ID required", http.StatusBadRequest)
+		return
+	}
+
+	deal, err := h.dealService.GetDeal(dealID, actor(r))
+	if err != nil {
+		h.sendDealError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, deal, http.StatusOK)
+}
+
+// ListDeals handles GET /api/deals?agency_id=&agent_id=&property_id=&type=&from=2025-01-01&to=2025-02-01&limit=50&offset=0
+func (h *DealHandler) ListDeals(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, to, ok := h.parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	filter := domain.DealFilter{
+		AgencyID:   query.Get("agency_id"),
+		AgentID:    query.Get("agent_id"),
+		PropertyID: query.Get("property_id"),
+		Type:       query.Get("type"),
+		From:       from,
+		To:         to,
+	}
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+	filter.Offset, _ = strconv.Atoi(query.Get("offset"))
+
+	deals, total, err := h.dealService.ListDeals(filter, actor(r))
+	if err != nil {
+		h.sendDealError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"deals": deals,
+		"total": total,
+	}, http.StatusOK)
+}
+
+// GetAgencyDealSummary handles GET /api/agencies/{id}/deals/summary?from=2025-01-01&to=2025-02-01
+func (h *DealHandler) GetAgencyDealSummary(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	from, to, ok := h.parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.dealService.GetAgencyDealSummary(agencyID, from, to, actor(r))
+	if err != nil {
+		h.sendDealError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, summary, http.StatusOK)
+}
+
+// Helper functions
+
+// parsePeriod reads the optional from/to query parameters as dates or RFC 3339 timestamps
+func (h *DealHandler) parsePeriod(w http.ResponseWriter, r *http.Request) (*time.Time, *time.Time, bool) {
+	from, err := parseDateParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// parseDateParam parses a YYYY-MM-DD date or RFC 3339 timestamp; empty values are nil
+func parseDateParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func (h *DealHandler) sendDealError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already sold"), strings.Contains(err.Error(), "not available"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Deal error: %v", err)
+		http.Error(w, "Failed to process deal", http.StatusInternalServerError)
+	}
+}
+
+func (h *DealHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
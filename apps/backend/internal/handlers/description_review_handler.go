@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// DescriptionReviewHandler serves the admin queue of listing descriptions flagged or
+// masked by the description policy
+type DescriptionReviewHandler struct {
+	descriptionService *service.DescriptionPolicyService
+	logger             *log.Logger
+}
+
+// NewDescriptionReviewHandler creates a new description review handler
+func NewDescriptionReviewHandler(descriptionService *service.DescriptionPolicyService, logger *log.Logger) *DescriptionReviewHandler {
+	return &DescriptionReviewHandler{
+		descriptionService: descriptionService,
+		logger:             logger,
+	}
+}
+
+// decideDescriptionRequest upholds or overrides the policy on a description
+type decideDescriptionRequest struct {
+	Action string `json:"action"` // uphold or override
+}
+
+// ListQueue handles GET /api/admin/description-reviews?status=pending&limit=20&offset=0
+// Each review carries the description as written and what the policy found in it.
+func (h *DescriptionReviewHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	reviews, total, err := h.descriptionService.ListQueue(domain.DescriptionReviewFilter{
+		Status: query.Get("status"),
+		Limit:  limit,
+		Offset: offset,
+	}, actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"reviews": reviews,
+		"total":   total,
+	}, http.StatusOK)
+}
+
+// Decide handles POST /api/admin/description-reviews/{propertyID}/decide
+// Upholding masks everything the policy found; overriding restores the description as
+// written and exempts the listing from the policy.
+func (h *DescriptionReviewHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	var req decideDescriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Action != "uphold" && req.Action != "override" {
+		http.Error(w, "invalid action: must be uphold or override", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.descriptionService.Decide(pathSegment(r.URL.Path, 3), req.Action == "override", actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, review, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *DescriptionReviewHandler) sendReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Description review error: %v", err)
+		http.Error(w, "Failed to process description review", http.StatusInternalServerError)
+	}
+}
+
+func (h *DescriptionReviewHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ElectronicInvoiceHandler serves the SRI electronic invoices issued to agencies
+type ElectronicInvoiceHandler struct {
+	invoiceService *service.ElectronicInvoiceService
+	logger         *log.Logger
+}
+
+// NewElectronicInvoiceHandler creates a new electronic invoice handler
+func NewElectronicInvoiceHandler(invoiceService *service.ElectronicInvoiceService, logger *log.Logger) *ElectronicInvoiceHandler {
+	return &ElectronicInvoiceHandler{
+		invoiceService: invoiceService,
+		logger:         logger,
+	}
+}
+
+// ListAgencyInvoices handles GET /api/agencies/{id}/invoices
+func (h *ElectronicInvoiceHandler) ListAgencyInvoices(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	invoices, err := h.invoiceService.ListAgencyInvoices(agencyID, actor(r))
+	if err != nil {
+		h.sendInvoiceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"invoices": invoices,
+		"count":    len(invoices),
+	}, http.StatusOK)
+}
+
+// GetInvoice handles GET /api/invoices/{id}
+func (h *ElectronicInvoiceHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	invoiceID := pathSegment(r.URL.Path, 2)
+	if invoiceID == "" {
+		http.Error(w, "Invoice ID required", http.StatusBadRequest)
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(invoiceID, actor(r))
+	if err != nil {
+		h.sendInvoiceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, invoice, http.StatusOK)
+}
+
+// DownloadXML handles GET /api/invoices/{id}/xml, the authorized XML once the SRI
+// authorizes the invoice
+func (h *ElectronicInvoiceHandler) DownloadXML(w http.ResponseWriter, r *http.Request) {
+	invoiceID := pathSegment(r.URL.Path, 2)
+	if invoiceID == "" {
+		http.Error(w, "Invoice ID required", http.StatusBadRequest)
+		return
+	}
+
+	invoice, document, err := h.invoiceService.InvoiceXML(invoiceID, actor(r))
+	if err != nil {
+		h.sendInvoiceError(w, err)
+		return
+	}
+
+	h.sendDownload(w, "application/xml", invoice.AccessKey+".xml", document)
+}
+
+// DownloadRIDE handles GET /api/invoices/{id}/pdf, the printed representation (RIDE)
+func (h *ElectronicInvoiceHandler) DownloadRIDE(w http.ResponseWriter, r *http.Request) {
+	invoiceID := pathSegment(r.URL.Path, 2)
+	if invoiceID == "" {
+		http.Error(w, "Invoice ID required", http.StatusBadRequest)
+		return
+	}
+
+	invoice, pdf, err := h.invoiceService.InvoiceRIDE(invoiceID, actor(r))
+	if err != nil {
+		h.sendInvoiceError(w, err)
+		return
+	}
+
+	h.sendDownload(w, "application/pdf", "factura-"+invoice.Number()+".pdf", pdf)
+}
+
+// Helper functions
+
+func (h *ElectronicInvoiceHandler) sendDownload(w http.ResponseWriter, contentType, fileName string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *ElectronicInvoiceHandler) sendInvoiceError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Electronic invoice error: %v", err)
+		http.Error(w, "Failed to process invoice", http.StatusInternalServerError)
+	}
+}
+
+func (h *ElectronicInvoiceHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
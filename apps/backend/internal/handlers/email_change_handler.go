@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// EmailChangeHandler serves the two-step account email change
+type EmailChangeHandler struct {
+	emailChangeService *service.EmailChangeService
+	logger             *log.Logger
+}
+
+// NewEmailChangeHandler creates a new email change handler
+func NewEmailChangeHandler(emailChangeService *service.EmailChangeService, logger *log.Logger) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		emailChangeService: emailChangeService,
+		logger:             logger,
+	}
+}
+
+// requestEmailChangeRequest starts an email change
+type requestEmailChangeRequest struct {
+	Password string `json:"password"`
+	NewEmail string `json:"new_email"`
+}
+
+// emailChangeTokenRequest carries the token of a confirm or cancel link
+type emailChangeTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// RequestChange handles POST /api/auth/email-change
+// The password is confirmed; the new address receives a confirmation link and the
+// current address a link to cancel. The email does not change until confirmed.
+func (h *EmailChangeHandler) RequestChange(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req requestEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Password == "" || req.NewEmail == "" {
+		http.Error(w, "password and new_email are required", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.emailChangeService.RequestChange(userID, req.Password, req.NewEmail)
+	if err != nil {
+		h.sendEmailChangeError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, change, http.StatusAccepted)
+}
+
+// Confirm handles POST /api/auth/email-change/confirm
+// Public: the token of the link sent to the new address authorizes the change.
+func (h *EmailChangeHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req emailChangeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.emailChangeService.Confirm(req.Token)
+	if err != nil {
+		h.sendEmailChangeError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"message": "Email changed",
+		"email":   user.Email,
+	}, http.StatusOK)
+}
+
+// Cancel handles POST /api/auth/email-change/cancel
+// Public: the token of the link sent to the current address authorizes cancelling.
+func (h *EmailChangeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	var req emailChangeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.emailChangeService.Cancel(req.Token)
+	if err != nil {
+		h.sendEmailChangeError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, change, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *EmailChangeHandler) sendEmailChangeError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "password is incorrect"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already in use"), strings.Contains(err.Error(), "email change is"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Email change error: %v", err)
+		http.Error(w, "Failed to process email change", http.StatusInternalServerError)
+	}
+}
+
+func (h *EmailChangeHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
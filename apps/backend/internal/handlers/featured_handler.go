@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"realty-core/internal/service"
+)
+
+// FeaturedHandler handles timed featured promotions of listings
+type FeaturedHandler struct {
+	featuredService *service.FeaturedService
+	logger          *log.Logger
+}
+
+// NewFeaturedHandler creates a new featured promotion handler
+func NewFeaturedHandler(featuredService *service.FeaturedService, logger *log.Logger) *FeaturedHandler {
+	return &FeaturedHandler{
+		featuredService: featuredService,
+		logger:          logger,
+	}
+}
+
+// GetFeaturedStatus handles GET /api/properties/{id}/featured
+func (h *FeaturedHandler) GetFeaturedStatus(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.featuredService.GetStatus(propertyID)
+	if err != nil {
+		h.sendFeaturedError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, status, http.StatusOK)
+}
+
+// ExtendFeatured handles POST /api/properties/{id}/featured/extend ({"days": 30}).
+// A running promotion is extended; an expired one is renewed from now.
+func (h *FeaturedHandler) ExtendFeatured(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Days int `json:"days"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Days < 0 {
+		http.Error(w, "Days must be positive", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	status, err := h.featuredService.ExtendFeatured(propertyID, time.Duration(req.Days)*24*time.Hour, actor)
+	if err != nil {
+		h.sendFeaturedError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, status, http.StatusOK)
+}
+
+// ExpireFeatured handles POST /api/admin/maintenance/featured/expire and ends every
+// promotion past its end without waiting for the scheduler
+func (h *FeaturedHandler) ExpireFeatured(w http.ResponseWriter, r *http.Request) {
+	ids, err := h.featuredService.ExpireFeatured()
+	if err != nil {
+		h.sendFeaturedError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_ids": ids,
+		"count":        len(ids),
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *FeaturedHandler) sendFeaturedError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "plan limit"):
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Featured promotion error: %v", err)
+		http.Error(w, "Failed to update featured promotion", http.StatusInternalServerError)
+	}
+}
+
+func (h *FeaturedHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// feedCacheMaxAge is how long readers and proxies reuse a feed; aggregators poll feeds
+// far more often than listings are published
+const feedCacheMaxAge = 15 * time.Minute
+
+// FeedHandler serves the RSS and Atom feeds of new listings
+type FeedHandler struct {
+	feedService *service.FeedService
+	logger      *log.Logger
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(feedService *service.FeedService, logger *log.Logger) *FeedHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &FeedHandler{
+		feedService: feedService,
+		logger:      logger,
+	}
+}
+
+// PropertiesFeed handles GET /api/feeds/properties.rss and GET /api/feeds/properties.atom
+// Filters are province, city and type, repeated or comma-separated, and min_price and
+// max_price; limit caps the items, 20 by default and at most 50.
+func (h *FeedHandler) PropertiesFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var format, contentType string
+	switch {
+	case strings.HasSuffix(r.URL.Path, ".rss"):
+		format, contentType = domain.FeedFormatRSS, "application/rss+xml; charset=utf-8"
+	case strings.HasSuffix(r.URL.Path, ".atom"):
+		format, contentType = domain.FeedFormatAtom, "application/atom+xml; charset=utf-8"
+	default:
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := domain.NewPropertySearchFilters()
+	filters.Provinces = parseListParam(query, "province")
+	filters.Cities = parseListParam(query, "city")
+	filters.PropertyTypes = parseListParam(query, "type")
+	filters.TenantID = middleware.GetTenantID(r.Context())
+
+	var err error
+	if filters.MinPrice, err = parseFloatParam(query, "min_price", "Invalid minimum price"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filters.MaxPrice, err = parseFloatParam(query, "max_price", "Invalid maximum price"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	feed, err := h.feedService.PropertyFeed(filters, limit, requestURL(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Printf("Error building property feed: %v", err)
+		http.Error(w, "Failed to build feed", http.StatusInternalServerError)
+		return
+	}
+
+	body, err := domain.RenderPropertyFeed(feed, format)
+	if err != nil {
+		h.logger.Printf("Error rendering property feed: %v", err)
+		http.Error(w, "Failed to build feed", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := feed.Updated.UTC().Truncate(time.Second)
+
+	header := w.Header()
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(feedCacheMaxAge.Seconds())))
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	if feedNotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// feedNotModified reports whether the reader already has the feed, by its ETag or, when
+// the request has no If-None-Match, by its last modification
+func feedNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			if candidate = strings.TrimSpace(candidate); candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		return !lastModified.After(since)
+	}
+	return false
+}
+
+// requestURL returns the absolute URL a request was made to
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+func TestFeedHandler_PropertiesFeed(t *testing.T) {
+	createdAt := time.Date(2025, 9, 12, 15, 30, 0, 0, time.UTC)
+	mockService := new(MockPropertyService)
+	mockService.On("FilterPropertiesPaginated", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+		return len(f.Status) == 1 && f.Status[0] == domain.StatusAvailable && len(f.Cities) == 2 && *f.MaxPrice == 200000
+	}), mock.MatchedBy(func(p *domain.PaginationParams) bool {
+		return p.PageSize == domain.MaxFeedItems && p.SortBy == "created_at" && p.SortDesc
+	})).Return(&domain.PaginatedResponse{
+		Data:       []domain.Property{{ID: "prop-1", Slug: "casa-prop-1", Title: "Casa", Type: domain.TypeHouse, CreatedAt: createdAt, UpdatedAt: createdAt}},
+		Pagination: domain.NewPagination(1, domain.MaxFeedItems, 1),
+	}, nil)
+	handler := NewFeedHandler(service.NewFeedService(mockService, "https://inmo.ec/propiedades", nil), nil)
+
+	rec := httptest.NewRecorder()
+	handler.PropertiesFeed(rec, httptest.NewRequest(http.MethodGet, "/api/feeds/properties.rss?city=Quito,Cumbayá&max_price=200000&limit=500", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=900", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "Fri, 12 Sep 2025 15:30:00 GMT", rec.Header().Get("Last-Modified"))
+	assert.Contains(t, rec.Body.String(), "<link>https://inmo.ec/propiedades/casa-prop-1</link>")
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/feeds/properties.rss?city=Quito,Cumbayá&max_price=200000&limit=500", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.PropertiesFeed(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.PropertiesFeed(rec, httptest.NewRequest(http.MethodGet, "/api/feeds/properties.atom?province=Atlantis", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.PropertiesFeed(rec, httptest.NewRequest(http.MethodGet, "/api/feeds/properties.json", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -74,6 +74,16 @@ func TestPropertyHandler_SearchRanked(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			wantError:      false,
 		},
+		{
+			name: "english search uses the english search configuration",
+			url:  "/api/properties/search/ranked?q=garden+house&limit=10&locale=en",
+			mockSetup: func(mockService *MockPropertyService) {
+				params := repository.AdvancedSearchParams{Query: "garden house", Limit: 10, Locale: "en"}
+				mockService.On("AdvancedSearch", params).Return([]repository.PropertySearchResult{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			wantError:      false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -291,6 +301,41 @@ func TestPropertyHandler_AdvancedSearch(t *testing.T) {
 	}
 }
 
+func TestPropertyHandler_AdvancedSearchFaceted(t *testing.T) {
+	mockService := &MockPropertyService{}
+	facets := domain.NewSearchFacets()
+	facets.Add(domain.FacetType, "house", 4)
+	response := &domain.FacetedSearchResponse{
+		Data:       []repository.PropertySearchResult{},
+		Pagination: domain.NewPagination(1, 10, 4),
+		Facets:     facets,
+	}
+	mockService.On("AdvancedSearchFaceted", mock.MatchedBy(func(params repository.AdvancedSearchParams) bool {
+		return params.Query == "casa" && params.Province == "Guayas"
+	}), mock.AnythingOfType("*domain.PaginationParams")).Return(response, nil)
+
+	handler := NewPropertyHandler(mockService)
+	body := bytes.NewBufferString(`{"query":"casa","province":"Guayas"}`)
+	req := httptest.NewRequest("POST", "/api/properties/search/advanced/faceted", body)
+	rr := httptest.NewRecorder()
+
+	handler.AdvancedSearchFaceted(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"facets"`)
+	assert.Contains(t, rr.Body.String(), `"value":"house"`)
+	mockService.AssertExpectations(t)
+
+	// Invalid JSON and wrong method never reach the service
+	rr = httptest.NewRecorder()
+	handler.AdvancedSearchFaceted(rr, httptest.NewRequest("POST", "/api/properties/search/advanced/faceted", bytes.NewBufferString("{")))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.AdvancedSearchFaceted(rr, httptest.NewRequest("GET", "/api/properties/search/advanced/faceted", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
 func TestPropertyHandler_SearchRanked_MethodNotAllowed(t *testing.T) {
 	mockService := &MockPropertyService{}
 	handler := NewPropertyHandler(mockService)
@@ -340,7 +385,9 @@ func TestPropertyHandler_FilterProperties_EnhancedSearch(t *testing.T) {
 			url:  "/api/properties/filter?q=casa+moderna+terraza",
 			mockSetup: func(mockService *MockPropertyService) {
 				properties := []domain.Property{*createTestProperty()}
-				mockService.On("SearchProperties", "casa moderna terraza").Return(properties, nil)
+				mockService.On("FilterProperties", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+					return f.Query == "casa moderna terraza"
+				})).Return(properties, nil)
 			},
 			expectedStatus: http.StatusOK,
 			wantError:      false,
@@ -349,7 +396,7 @@ func TestPropertyHandler_FilterProperties_EnhancedSearch(t *testing.T) {
 			name: "search query too short",
 			url:  "/api/properties/filter?q=a",
 			mockSetup: func(mockService *MockPropertyService) {
-				mockService.On("SearchProperties", "a").Return([]domain.Property{}, assert.AnError)
+				mockService.On("FilterProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).Return([]domain.Property{}, assert.AnError)
 			},
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
@@ -373,7 +420,7 @@ func TestPropertyHandler_FilterProperties_EnhancedSearch(t *testing.T) {
 				var response SuccessResponse
 				err := json.Unmarshal(rr.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, "Properties filtered by search query", response.Message)
+				assert.Equal(t, "Properties filtered", response.Message)
 			}
 
 			mockService.AssertExpectations(t)
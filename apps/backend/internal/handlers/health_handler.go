@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 
 	"realty-core/internal/cache"
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
 	"realty-core/internal/repository"
 	"realty-core/internal/service"
 )
@@ -22,6 +25,8 @@ type HealthHandler struct {
 	agencyRepo   *repository.AgencyRepository
 	imageCache   cache.ImageCacheInterface
 	propertyService *service.PropertyService
+	poolMonitor     *monitoring.DBPoolMonitor
+	startup         *service.StartupService
 }
 
 // NewHealthHandler creates a new health handler
@@ -34,6 +39,10 @@ func NewHealthHandler(
 	imageCache cache.ImageCacheInterface,
 	propertyService *service.PropertyService,
 ) *HealthHandler {
+	var poolMonitor *monitoring.DBPoolMonitor
+	if db != nil {
+		poolMonitor = monitoring.NewDBPoolMonitor(db)
+	}
 	return &HealthHandler{
 		db:              db,
 		propertyRepo:    propertyRepo,
@@ -42,9 +51,22 @@ func NewHealthHandler(
 		agencyRepo:      agencyRepo,
 		imageCache:      imageCache,
 		propertyService: propertyService,
+		poolMonitor:     poolMonitor,
 	}
 }
 
+// SetPoolMonitor shares the monitor sampling the database pool in the background, so
+// the pool statistics of detailed health checks cover the interval since its last sample
+func (h *HealthHandler) SetPoolMonitor(monitor *monitoring.DBPoolMonitor) {
+	h.poolMonitor = monitor
+}
+
+// SetStartup gates readiness on the startup checks: the instance reports ready only
+// once they all passed, and not alive when they still failed after the grace period
+func (h *HealthHandler) SetStartup(startup *service.StartupService) {
+	h.startup = startup
+}
+
 // HealthStatus represents the overall health status
 type HealthStatus struct {
 	Status      string                 `json:"status"`
@@ -53,6 +75,8 @@ type HealthStatus struct {
 	Uptime      time.Duration          `json:"uptime"`
 	Services    map[string]ServiceHealth `json:"services"`
 	System      SystemHealth           `json:"system"`
+	// DatabasePool is the connection pool and its tuning warnings
+	DatabasePool *monitoring.DBPoolReport `json:"database_pool,omitempty"`
 }
 
 // ServiceHealth represents the health of individual services
@@ -131,6 +155,15 @@ func (h *HealthHandler) DetailedHealthCheck(w http.ResponseWriter, r *http.Reque
 	
 	// Database health
 	dbHealth := h.checkDatabaseHealth()
+	var poolReport *monitoring.DBPoolReport
+	if h.poolMonitor != nil {
+		report := h.poolMonitor.Sample()
+		poolReport = &report
+		// An exhausted pool still serves queries, late; it is reported, not unhealthy
+		if len(report.Warnings) > 0 && dbHealth.Message == "" {
+			dbHealth.Message = "Connection pool: " + strings.Join(report.Warnings, "; ")
+		}
+	}
 	services["database"] = dbHealth
 	
 	// Repository health (sample some operations)
@@ -161,6 +194,7 @@ func (h *HealthHandler) DetailedHealthCheck(w http.ResponseWriter, r *http.Reque
 		Uptime:    time.Since(startTime),
 		Services:  services,
 		System:    systemHealth,
+		DatabasePool: poolReport,
 	}
 	
 	// Set appropriate HTTP status
@@ -188,6 +222,17 @@ func (h *HealthHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
 	ready := true
 	checks := make(map[string]bool)
 	
+	// Startup checks: schema version, storage, cache and search index
+	var startup *domain.StartupReport
+	if h.startup != nil {
+		report := h.startup.Report()
+		startup = &report
+		checks["startup"] = report.Ready()
+		if !report.Ready() {
+			ready = false
+		}
+	}
+	
 	// Database connectivity
 	if err := h.db.Ping(); err != nil {
 		ready = false
@@ -206,6 +251,9 @@ func (h *HealthHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
 		"timestamp": time.Now(),
 		"checks":    checks,
 	}
+	if startup != nil {
+		response["startup"] = startup
+	}
 	
 	statusCode := http.StatusOK
 	if !ready {
@@ -220,14 +268,20 @@ func (h *HealthHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
 func (h *HealthHandler) LivenessCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	
-	// Simple liveness check - if we can respond, we're alive
+	// Simple liveness check - if we can respond, we're alive, unless startup failed and
+	// the instance should be replaced
+	alive := h.startup == nil || h.startup.Report().Phase != domain.StartupPhaseFailed
 	response := map[string]interface{}{
-		"alive":     true,
+		"alive":     alive,
 		"timestamp": time.Now(),
 		"uptime":    time.Since(startTime).String(),
 	}
 	
-	w.WriteHeader(http.StatusOK)
+	statusCode := http.StatusOK
+	if !alive {
+		statusCode = http.StatusServiceUnavailable
+	}
+	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -274,6 +328,38 @@ func (h *HealthHandler) CacheHealth(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(cacheHealth)
 }
 
+// FlushCache handles POST /api/admin/maintenance/cache/flush?cache=properties|images
+// Without a cache parameter every cache is flushed.
+func (h *HealthHandler) FlushCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	target := r.URL.Query().Get("cache")
+	if target != "" && target != "properties" && target != "images" {
+		http.Error(w, "Cache must be properties or images", http.StatusBadRequest)
+		return
+	}
+
+	flushed := []string{}
+	if (target == "" || target == "properties") && h.propertyService != nil {
+		h.propertyService.ClearCache()
+		flushed = append(flushed, "properties")
+	}
+	if (target == "" || target == "images") && h.imageCache != nil {
+		h.imageCache.Clear()
+		flushed = append(flushed, "images")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flushed":    flushed,
+		"flushed_at": time.Now(),
+	})
+}
+
 // MetricsEndpoint provides Prometheus-style metrics
 func (h *HealthHandler) MetricsEndpoint(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
@@ -326,6 +412,30 @@ func (h *HealthHandler) MetricsEndpoint(w http.ResponseWriter, r *http.Request)
 		)
 	}
 	
+	// Cumulative pool statistics; sampling the monitor would reset its interval
+	if h.db != nil {
+		pool := h.db.Stats()
+		metrics = append(metrics,
+			"# HELP realty_core_db_pool_connections Database connections by state",
+			"# TYPE realty_core_db_pool_connections gauge",
+			fmt.Sprintf("realty_core_db_pool_connections{state=\"in_use\"} %d", pool.InUse),
+			fmt.Sprintf("realty_core_db_pool_connections{state=\"idle\"} %d", pool.Idle),
+			"",
+			"# HELP realty_core_db_pool_max_open Maximum open database connections, 0 for unlimited",
+			"# TYPE realty_core_db_pool_max_open gauge",
+			fmt.Sprintf("realty_core_db_pool_max_open %d", pool.MaxOpenConnections),
+			"",
+			"# HELP realty_core_db_pool_wait_total Total waits for a database connection",
+			"# TYPE realty_core_db_pool_wait_total counter",
+			fmt.Sprintf("realty_core_db_pool_wait_total %d", pool.WaitCount),
+			"",
+			"# HELP realty_core_db_pool_wait_seconds_total Total time waited for database connections",
+			"# TYPE realty_core_db_pool_wait_seconds_total counter",
+			fmt.Sprintf("realty_core_db_pool_wait_seconds_total %.3f", pool.WaitDuration.Seconds()),
+			"",
+		)
+	}
+	
 	for _, metric := range metrics {
 		w.Write([]byte(metric + "\n"))
 	}
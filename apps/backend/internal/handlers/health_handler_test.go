@@ -1,13 +1,15 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -182,6 +184,15 @@ func (m *MockImageCache) SetVariant(imageID string, width, height, quality int,
 	m.Called(imageID, width, height, quality, format, data, contentType)
 }
 
+func (m *MockImageCache) GetPreset(imageID, presetKey string) ([]byte, string, bool) {
+	args := m.Called(imageID, presetKey)
+	return args.Get(0).([]byte), args.String(1), args.Bool(2)
+}
+
+func (m *MockImageCache) SetPreset(imageID, presetKey string, data []byte, contentType string) {
+	m.Called(imageID, presetKey, data, contentType)
+}
+
 func (m *MockImageCache) InvalidateImage(imageID string) int {
 	args := m.Called(imageID)
 	return args.Int(0)
@@ -235,7 +246,9 @@ func TestHealthHandler_BasicHealthCheck(t *testing.T) {
 
 func TestHealthHandler_ReadinessCheck_Healthy(t *testing.T) {
 	// Setup
-	mockDB := &sql.DB{} // This is a simplified mock
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
 	handler := &HealthHandler{db: mockDB}
 	
 	req := httptest.NewRequest("GET", "/api/health/ready", nil)
@@ -248,7 +261,7 @@ func TestHealthHandler_ReadinessCheck_Healthy(t *testing.T) {
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 	
 	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
+	err = json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	
 	assert.NotNil(t, response["ready"])
@@ -256,6 +269,51 @@ func TestHealthHandler_ReadinessCheck_Healthy(t *testing.T) {
 	assert.NotNil(t, response["timestamp"])
 }
 
+func TestHealthHandler_ReadinessCheck_GatedOnStartup(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	cacheUp := false
+	startup := service.NewStartupService(service.StartupConfig{GracePeriod: time.Millisecond, Interval: time.Millisecond}, nil,
+		service.StartupCheck{Name: "cache", Run: func(ctx context.Context) error {
+			if !cacheUp {
+				return fmt.Errorf("failed to reach cache: connection refused")
+			}
+			return nil
+		}})
+	handler := &HealthHandler{db: mockDB}
+	handler.SetStartup(startup)
+
+	w := httptest.NewRecorder()
+	handler.ReadinessCheck(w, httptest.NewRequest("GET", "/api/health/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "not ready before the startup checks pass")
+
+	cacheUp = true
+	assert.NoError(t, startup.Run(context.Background()))
+	w = httptest.NewRecorder()
+	handler.ReadinessCheck(w, httptest.NewRequest("GET", "/api/health/ready", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ready", response["startup"].(map[string]interface{})["phase"])
+}
+
+func TestHealthHandler_LivenessCheck_StartupFailed(t *testing.T) {
+	startup := service.NewStartupService(service.StartupConfig{GracePeriod: time.Millisecond, Interval: time.Millisecond}, nil,
+		service.StartupCheck{Name: "storage_write", Run: func(ctx context.Context) error {
+			return fmt.Errorf("failed to write to storage: permission denied")
+		}})
+	assert.Error(t, startup.Run(context.Background()))
+	handler := &HealthHandler{}
+	handler.SetStartup(startup)
+
+	w := httptest.NewRecorder()
+	handler.LivenessCheck(w, httptest.NewRequest("GET", "/api/health/live", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "a failed startup is replaced")
+}
+
 func TestHealthHandler_LivenessCheck(t *testing.T) {
 	// Setup
 	handler := &HealthHandler{}
@@ -377,8 +435,12 @@ func TestHealthHandler_DetailedHealthCheck_Healthy(t *testing.T) {
 		},
 	})
 	
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
 	handler := &HealthHandler{
-		db:           &sql.DB{}, // Simplified
+		db:           mockDB,
 		propertyRepo: nil,       // Simplified for basic test
 		imageCache:   mockImageCache,
 	}
@@ -393,7 +455,7 @@ func TestHealthHandler_DetailedHealthCheck_Healthy(t *testing.T) {
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 	
 	var response HealthStatus
-	err := json.Unmarshal(w.Body.Bytes(), &response)
+	err = json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	
 	assert.NotEmpty(t, response.Status)
@@ -409,6 +471,31 @@ func TestHealthHandler_DetailedHealthCheck_Healthy(t *testing.T) {
 	mockImageCache.AssertExpectations(t)
 }
 
+func TestHealthHandler_ReportsDatabasePool(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+	mock.ExpectPing()
+
+	handler := NewHealthHandler(db, nil, nil, nil, nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.DetailedHealthCheck(w, httptest.NewRequest("GET", "/api/health/detailed", nil))
+
+	var response HealthStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.DatabasePool) {
+		assert.Equal(t, 7, response.DatabasePool.Stats.MaxOpen)
+		assert.False(t, response.DatabasePool.Exhausted)
+	}
+
+	w = httptest.NewRecorder()
+	handler.MetricsEndpoint(w, httptest.NewRequest("GET", "/api/metrics", nil))
+	assert.Contains(t, w.Body.String(), "realty_core_db_pool_max_open 7")
+	assert.Contains(t, w.Body.String(), `realty_core_db_pool_connections{state="in_use"}`)
+}
+
 func TestHealthHandler_GetSystemHealth(t *testing.T) {
 	// Setup
 	handler := &HealthHandler{}
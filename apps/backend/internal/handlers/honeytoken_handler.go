@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"realty-core/internal/service"
+)
+
+// HoneytokenHandler lets administrators plant canary listings and see who requested them
+type HoneytokenHandler struct {
+	honeytokens *service.HoneytokenService
+	logger      *log.Logger
+}
+
+// NewHoneytokenHandler creates a new honeytoken handler
+func NewHoneytokenHandler(honeytokens *service.HoneytokenService, logger *log.Logger) *HoneytokenHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &HoneytokenHandler{
+		honeytokens: honeytokens,
+		logger:      logger,
+	}
+}
+
+// Honeytokens handles GET and POST /api/admin/honeytokens
+// GET lists every canary; POST creates one from {source, label, api_key_id, listing}.
+func (h *HoneytokenHandler) Honeytokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := h.honeytokens.List(actor(r))
+		if err != nil {
+			h.sendHoneytokenError(w, err)
+			return
+		}
+		h.sendJSONResponse(w, map[string]interface{}{"honeytokens": tokens}, http.StatusOK)
+	case http.MethodPost:
+		var req service.CreateHoneytokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		token, err := h.honeytokens.Create(req, actor(r))
+		if err != nil {
+			h.sendHoneytokenError(w, err)
+			return
+		}
+		h.sendJSONResponse(w, token, http.StatusCreated)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// RetireHoneytoken handles DELETE /api/admin/honeytokens/{id}
+// The canary stops being planted and alerted on; its hits stay in the reports.
+func (h *HoneytokenHandler) RetireHoneytoken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := pathSegment(r.URL.Path, 3)
+	if id == "" {
+		http.Error(w, "Honeytoken ID required", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.honeytokens.Retire(id, actor(r))
+	if err != nil {
+		h.sendHoneytokenError(w, err)
+		return
+	}
+	h.sendJSONResponse(w, token, http.StatusOK)
+}
+
+// Report handles GET /api/admin/honeytokens/report?from=2025-09-01&to=2025-09-30
+// It sums up the hits on canaries per source and per suspect: API key, user or client
+// fingerprint. Days are UTC and inclusive; the range defaults to the last 30 days.
+func (h *HoneytokenHandler) Report(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var from, to time.Time
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	report, err := h.honeytokens.Report(from, to, actor(r))
+	if err != nil {
+		h.sendHoneytokenError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *HoneytokenHandler) sendHoneytokenError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Honeytoken error: %v", err)
+		http.Error(w, "Failed to process honeytokens", http.StatusInternalServerError)
+	}
+}
+
+func (h *HoneytokenHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
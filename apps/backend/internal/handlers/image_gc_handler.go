@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ImageGCHandler exposes the orphaned image collector to administrators
+type ImageGCHandler struct {
+	gcService *service.ImageGCService
+	logger    *log.Logger
+}
+
+// NewImageGCHandler creates a new image GC handler
+func NewImageGCHandler(gcService *service.ImageGCService, logger *log.Logger) *ImageGCHandler {
+	return &ImageGCHandler{
+		gcService: gcService,
+		logger:    logger,
+	}
+}
+
+// RunGC handles POST /api/admin/maintenance/images/gc?dry_run=true
+func (h *ImageGCHandler) RunGC(w http.ResponseWriter, r *http.Request) {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	report, err := h.gcService.Run(dryRun)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already running"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case strings.Contains(err.Error(), "does not support"):
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+		default:
+			h.logger.Printf("Error running image GC: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+// GetLastReport handles GET /api/admin/maintenance/images/gc
+func (h *ImageGCHandler) GetLastReport(w http.ResponseWriter, r *http.Request) {
+	report := h.gcService.LastReport()
+	if report == nil {
+		http.Error(w, "Image GC has not run yet", http.StatusNotFound)
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+func (h *ImageGCHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/processors"
 	"realty-core/internal/service"
 )
 
@@ -59,6 +64,14 @@ func (h *ImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 	// Upload and process image
 	imageInfo, err := h.imageService.Upload(propertyID, file, handler, altText)
 	if err != nil {
+		if status, ok := imageLimitStatus(w, err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+			return
+		}
+		if status, ok := quotaErrorStatus(err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+			return
+		}
 		h.sendErrorResponse(w, fmt.Sprintf("Failed to upload image: %v", err), http.StatusBadRequest)
 		return
 	}
@@ -66,6 +79,186 @@ func (h *ImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 	h.sendSuccessResponse(w, "Image uploaded successfully", imageInfo)
 }
 
+// UploadImages handles POST /api/images/batch
+// Accepts several files in the "images" field and/or a zip file in the "archive" field,
+// all associated to the property given in "property_id". Each file is validated and
+// processed independently and reported in the per-file results.
+func (h *ImageHandler) UploadImages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxBatchUploadSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		h.sendErrorResponse(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	propertyID := r.FormValue("property_id")
+	if propertyID == "" {
+		h.sendErrorResponse(w, "Property ID is required", http.StatusBadRequest)
+		return
+	}
+	altText := r.FormValue("alt_text")
+
+	files := r.MultipartForm.File["images"]
+	archives := r.MultipartForm.File["archive"]
+	if len(files) == 0 && len(archives) == 0 {
+		h.sendErrorResponse(w, "At least one image or archive is required", http.StatusBadRequest)
+		return
+	}
+	if len(archives) > 1 {
+		h.sendErrorResponse(w, "Only one archive can be uploaded per request", http.StatusBadRequest)
+		return
+	}
+
+	uploads := make([]domain.ImageUpload, 0, len(files))
+	for _, fileHeader := range files {
+		data, err := h.readFormFile(fileHeader, domain.MaxUploadSize)
+		if err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to read %s: %v", fileHeader.Filename, err), http.StatusBadRequest)
+			return
+		}
+		uploads = append(uploads, domain.ImageUpload{
+			FileName:    fileHeader.Filename,
+			ContentType: fileHeader.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+
+	if len(archives) == 1 {
+		data, err := h.readFormFile(archives[0], domain.MaxBatchUploadSize)
+		if err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to read archive: %v", err), http.StatusBadRequest)
+			return
+		}
+		extracted, err := h.imageService.ExtractArchive(data)
+		if err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to extract archive: %v", err), http.StatusBadRequest)
+			return
+		}
+		uploads = append(uploads, extracted...)
+	}
+
+	result, err := h.imageService.UploadBatch(propertyID, uploads, altText)
+	if err != nil {
+		h.sendErrorResponse(w, fmt.Sprintf("Failed to upload images: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	h.sendSuccessResponse(w, fmt.Sprintf("Batch upload completed: %d succeeded, %d failed", result.Succeeded, result.Failed), result)
+}
+
+// CreateUploadURL handles POST /api/images/upload-url.
+// It returns a presigned URL the client uploads the file to with PUT and the same Content-Type.
+func (h *ImageHandler) CreateUploadURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		PropertyID  string `json:"property_id"`
+		FileName    string `json:"file_name"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+		AltText     string `json:"alt_text"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.PropertyID == "" || req.FileName == "" {
+		h.sendErrorResponse(w, "Property ID and file name are required", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.imageService.CreateUploadURL(req.PropertyID, req.FileName, req.ContentType, req.AltText, req.Size)
+	if err != nil {
+		if status, ok := quotaErrorStatus(err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+			return
+		}
+		switch {
+		case strings.Contains(err.Error(), "not enabled"), strings.Contains(err.Error(), "does not support"):
+			h.sendErrorResponse(w, err.Error(), http.StatusNotImplemented)
+		case strings.Contains(err.Error(), "property not found"):
+			h.sendErrorResponse(w, "Property not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "validation failed"), strings.Contains(err.Error(), "exceeded"):
+			h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to create upload URL: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, "Upload URL created successfully", session)
+}
+
+// ConfirmUpload handles POST /api/images/uploads/{id}/confirm.
+// The upload is validated and processed in the background; poll GetUploadSession for the result.
+func (h *ImageHandler) ConfirmUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := h.extractIDFromPath(r.URL.Path, "/api/images/uploads/")
+	if sessionID == "" {
+		h.sendErrorResponse(w, "Upload ID is required", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.imageService.ConfirmUpload(sessionID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not enabled"), strings.Contains(err.Error(), "does not support"):
+			h.sendErrorResponse(w, err.Error(), http.StatusNotImplemented)
+		case strings.Contains(err.Error(), "upload session not found"):
+			h.sendErrorResponse(w, "Upload not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "expired"), strings.Contains(err.Error(), "upload session is"):
+			h.sendErrorResponse(w, err.Error(), http.StatusConflict)
+		case strings.Contains(err.Error(), "validation failed"), strings.Contains(err.Error(), "file not found"):
+			h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to confirm upload: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, "Upload accepted for processing", session)
+}
+
+// GetUploadSession handles GET /api/images/uploads/{id}
+func (h *ImageHandler) GetUploadSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := h.extractIDFromPath(r.URL.Path, "/api/images/uploads/")
+	if sessionID == "" {
+		h.sendErrorResponse(w, "Upload ID is required", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.imageService.GetUploadSession(sessionID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.sendErrorResponse(w, "Upload not found", http.StatusNotFound)
+		} else {
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to get upload: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, "Upload retrieved successfully", session)
+}
+
 // GetImage handles requests to get image metadata
 func (h *ImageHandler) GetImage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -185,6 +378,61 @@ func (h *ImageHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
 	h.sendSuccessResponse(w, "Image deleted successfully", nil)
 }
 
+// ReplaceImage handles PUT /api/images/{id}/file (multipart: image)
+// The image keeps its ID, alt text and sort order; the previous file is purged from the CDN.
+func (h *ImageHandler) ReplaceImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	imageID := h.extractIDFromPath(r.URL.Path, "/api/images/")
+	if imageID == "" {
+		h.sendErrorResponse(w, "Image ID is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		h.sendErrorResponse(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	_, fileHeader, err := r.FormFile("image")
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get uploaded file", http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.readFormFile(fileHeader, domain.MaxUploadSize)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	imageInfo, err := h.imageService.ReplaceImage(imageID, fileHeader.Filename, data)
+	if err != nil {
+		if status, ok := imageLimitStatus(w, err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+			return
+		}
+		if status, ok := quotaErrorStatus(err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+			return
+		}
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			h.sendErrorResponse(w, "Image not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "validation failed"):
+			h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to replace image: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, "Image replaced successfully", imageInfo)
+}
+
 // ReorderImages handles requests to reorder images for a property
 func (h *ImageHandler) ReorderImages(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -289,7 +537,9 @@ func (h *ImageHandler) GetMainImage(w http.ResponseWriter, r *http.Request) {
 	h.sendSuccessResponse(w, "Main image retrieved successfully", image)
 }
 
-// GetImageVariant handles requests to get image variants (thumbnails, resized images)
+// GetImageVariant handles GET /api/images/{id}/variant?preset={name}
+// Only the configured presets can be requested: arbitrary sizes would let clients fill
+// the cache and the storage with renditions no page uses.
 func (h *ImageHandler) GetImageVariant(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -303,28 +553,19 @@ func (h *ImageHandler) GetImageVariant(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse query parameters
-	query := r.URL.Query()
-	width := h.parseIntParam(query.Get("w"), 0)
-	height := h.parseIntParam(query.Get("h"), 0)
-	quality := h.parseIntParam(query.Get("q"), domain.DefaultQuality)
-	format := query.Get("f")
-
-	// Default format
-	if format == "" {
-		format = "jpg"
-	}
-
-	// Validate format
-	if !domain.IsValidImageFormat(format) {
-		h.sendErrorResponse(w, fmt.Sprintf("Invalid format: %s", format), http.StatusBadRequest)
+	preset := r.URL.Query().Get("preset")
+	if preset == "" {
+		h.sendErrorResponse(w, fmt.Sprintf("An image preset is required, one of: %s", h.presetNames()), http.StatusBadRequest)
 		return
 	}
 
-	// Get image variant
-	imageData, err := h.imageService.GetImageVariant(imageID, width, height, format, quality)
+	imageData, contentType, err := h.imageService.GetImagePreset(imageID, preset)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if status, ok := imageLimitStatus(w, err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+		} else if strings.Contains(err.Error(), "unknown image preset") {
+			h.sendErrorResponse(w, fmt.Sprintf("Unknown image preset %q, one of: %s", preset, h.presetNames()), http.StatusBadRequest)
+		} else if strings.Contains(err.Error(), "not found") {
 			h.sendErrorResponse(w, "Image not found", http.StatusNotFound)
 		} else {
 			h.sendErrorResponse(w, fmt.Sprintf("Failed to get image variant: %v", err), http.StatusInternalServerError)
@@ -332,18 +573,31 @@ func (h *ImageHandler) GetImageVariant(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set appropriate headers
-	contentType := fmt.Sprintf("image/%s", format)
-	if format == "jpg" {
-		contentType = "image/jpeg"
-	}
-
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Cache-Control", "public, max-age=3600") // Cache for 1 hour
-	w.Header().Set("Content-Length", strconv.Itoa(len(imageData)))
 
-	// Write image data
-	w.Write(imageData)
+	// Write image data, honoring Range requests
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(imageData))
+}
+
+// GetImagePresets handles GET /api/images/presets
+func (h *ImageHandler) GetImagePresets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.sendSuccessResponse(w, "Image presets retrieved successfully", h.imageService.ImagePresets())
+}
+
+// presetNames lists the names of the image presets for error messages
+func (h *ImageHandler) presetNames() string {
+	presets := h.imageService.ImagePresets()
+	names := make([]string, 0, len(presets))
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+	return strings.Join(names, ", ")
 }
 
 // GetThumbnail handles requests to get image thumbnails
@@ -372,7 +626,9 @@ func (h *ImageHandler) GetThumbnail(w http.ResponseWriter, r *http.Request) {
 	// Get thumbnail
 	thumbnailData, err := h.imageService.GenerateThumbnail(imageID, size)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if status, ok := imageLimitStatus(w, err); ok {
+			h.sendErrorResponse(w, err.Error(), status)
+		} else if strings.Contains(err.Error(), "not found") {
 			h.sendErrorResponse(w, "Image not found", http.StatusNotFound)
 		} else {
 			h.sendErrorResponse(w, fmt.Sprintf("Failed to generate thumbnail: %v", err), http.StatusInternalServerError)
@@ -383,10 +639,9 @@ func (h *ImageHandler) GetThumbnail(w http.ResponseWriter, r *http.Request) {
 	// Set appropriate headers
 	w.Header().Set("Content-Type", "image/jpeg")
 	w.Header().Set("Cache-Control", "public, max-age=3600") // Cache for 1 hour
-	w.Header().Set("Content-Length", strconv.Itoa(len(thumbnailData)))
 
-	// Write thumbnail data
-	w.Write(thumbnailData)
+	// Write thumbnail data, honoring Range requests
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(thumbnailData))
 }
 
 // GetImageStats handles requests to get image statistics
@@ -447,8 +702,75 @@ func (h *ImageHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
 	h.sendSuccessResponse(w, "Cache statistics retrieved successfully", stats)
 }
 
+// GetAgencyQuota handles GET /api/agencies/{id}/quota
+// Returns the agency image plan with its limits and current storage usage to admins and
+// members of the agency.
+func (h *ImageHandler) GetAgencyQuota(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agencyID := h.extractIDFromPath(r.URL.Path, "/api/agencies/")
+	if agencyID == "" {
+		h.sendErrorResponse(w, "Agency ID is required", http.StatusBadRequest)
+		return
+	}
+	actor := actor(r)
+	if !actor.BelongsToAgency(agencyID) {
+		h.sendErrorResponse(w, "Only members of the agency can view its quota", http.StatusForbidden)
+		return
+	}
+
+	quota, err := h.imageService.GetAgencyQuota(agencyID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not enabled"):
+			h.sendErrorResponse(w, err.Error(), http.StatusNotImplemented)
+		case strings.Contains(err.Error(), "not found"):
+			h.sendErrorResponse(w, "Agency not found", http.StatusNotFound)
+		default:
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to get agency quota: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, "Agency quota retrieved successfully", quota)
+}
+
 // Helper methods
 
+// quotaErrorStatus maps upload limit errors to their status: an exhausted agency plan
+// requires an upgrade (402) while a full property rejects the upload itself (413)
+func quotaErrorStatus(err error) (int, bool) {
+	switch {
+	case strings.Contains(err.Error(), "storage quota exceeded"):
+		return http.StatusPaymentRequired, true
+	case strings.Contains(err.Error(), "maximum images per property exceeded"):
+		return http.StatusRequestEntityTooLarge, true
+	default:
+		return 0, false
+	}
+}
+
+// imageLimitRetryAfter is the Retry-After of images refused while decoding is at capacity
+const imageLimitRetryAfter = "5"
+
+// imageLimitStatus maps decode limit errors to their status: an image too large to ever
+// be decoded is rejected (413), one refused while other images use the decoding memory
+// can be retried (429), which sets Retry-After
+func imageLimitStatus(w http.ResponseWriter, err error) (int, bool) {
+	switch {
+	case errors.Is(err, processors.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge, true
+	case errors.Is(err, processors.ErrImageProcessingBusy):
+		w.Header().Set("Retry-After", imageLimitRetryAfter)
+		return http.StatusTooManyRequests, true
+	default:
+		return 0, false
+	}
+}
+
 // extractIDFromPath extracts ID from URL path
 func (h *ImageHandler) extractIDFromPath(path, prefix string) string {
 	if !strings.HasPrefix(path, prefix) {
@@ -464,17 +786,16 @@ func (h *ImageHandler) extractIDFromPath(path, prefix string) string {
 	return ""
 }
 
-// parseIntParam parses integer parameter with default value
-func (h *ImageHandler) parseIntParam(param string, defaultValue int) int {
-	if param == "" {
-		return defaultValue
-	}
-
-	if value, err := strconv.Atoi(param); err == nil {
-		return value
+// readFormFile reads an uploaded multipart file, reading at most limit+1 bytes
+// so oversized files are detected by validation without loading them entirely
+func (h *ImageHandler) readFormFile(fileHeader *multipart.FileHeader, limit int64) ([]byte, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
 	}
+	defer file.Close()
 
-	return defaultValue
+	return io.ReadAll(io.LimitReader(file, limit+1))
 }
 
 // sendSuccessResponse sends a success response
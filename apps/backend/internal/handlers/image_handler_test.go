@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -15,6 +16,8 @@ import (
 
 	"realty-core/internal/cache"
 	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+	"realty-core/internal/processors"
 )
 
 // MockImageService es un mock del servicio de imágenes
@@ -30,6 +33,46 @@ func (m *MockImageService) Upload(propertyID string, file multipart.File, header
 	return args.Get(0).(*domain.ImageInfo), args.Error(1)
 }
 
+func (m *MockImageService) UploadBatch(propertyID string, uploads []domain.ImageUpload, altText string) (*domain.BatchUploadResult, error) {
+	args := m.Called(propertyID, uploads, altText)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BatchUploadResult), args.Error(1)
+}
+
+func (m *MockImageService) ExtractArchive(archive []byte) ([]domain.ImageUpload, error) {
+	args := m.Called(archive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ImageUpload), args.Error(1)
+}
+
+func (m *MockImageService) CreateUploadURL(propertyID, fileName, contentType, altText string, size int64) (*domain.UploadSession, error) {
+	args := m.Called(propertyID, fileName, contentType, altText, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UploadSession), args.Error(1)
+}
+
+func (m *MockImageService) ConfirmUpload(sessionID string) (*domain.UploadSession, error) {
+	args := m.Called(sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UploadSession), args.Error(1)
+}
+
+func (m *MockImageService) GetUploadSession(id string) (*domain.UploadSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UploadSession), args.Error(1)
+}
+
 func (m *MockImageService) GetImage(id string) (*domain.ImageInfo, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -56,6 +99,14 @@ func (m *MockImageService) DeleteImage(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockImageService) ReplaceImage(id, originalName string, data []byte) (*domain.ImageInfo, error) {
+	args := m.Called(id, originalName, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImageInfo), args.Error(1)
+}
+
 func (m *MockImageService) ReorderImages(propertyID string, imageIDs []string) error {
 	args := m.Called(propertyID, imageIDs)
 	return args.Error(0)
@@ -82,6 +133,19 @@ func (m *MockImageService) GetImageVariant(imageID string, width, height int, fo
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (m *MockImageService) GetImagePreset(imageID, presetName string) ([]byte, string, error) {
+	args := m.Called(imageID, presetName)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]byte), args.String(1), args.Error(2)
+}
+
+func (m *MockImageService) ImagePresets() []domain.ImagePreset {
+	presets, _ := domain.ParseImagePresets(domain.DefaultImagePresetsSpec)
+	return domain.SortedImagePresets(presets)
+}
+
 func (m *MockImageService) GenerateThumbnail(imageID string, size int) ([]byte, error) {
 	args := m.Called(imageID, size)
 	if args.Get(0) == nil {
@@ -113,6 +177,14 @@ func (m *MockImageService) GetCacheStats() cache.ImageCacheStats {
 	return args.Get(0).(cache.ImageCacheStats)
 }
 
+func (m *MockImageService) GetAgencyQuota(agencyID string) (*domain.ImageQuota, error) {
+	args := m.Called(agencyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImageQuota), args.Error(1)
+}
+
 func TestNewImageHandler(t *testing.T) {
 	mockService := &MockImageService{}
 	handler := NewImageHandler(mockService)
@@ -209,6 +281,50 @@ func TestImageHandler_UploadImage(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "Failed to upload image",
 		},
+		{
+			name:   "storage quota exceeded",
+			method: http.MethodPost,
+			setupRequest: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				writer.WriteField("property_id", "test-property-id")
+				
+				fileWriter, _ := writer.CreateFormFile("image", "test.jpg")
+				fileWriter.Write([]byte("fake-image-data"))
+				writer.Close()
+				
+				req := httptest.NewRequest(http.MethodPost, "/api/images", body)
+				req.Header.Set("Content-Type", writer.FormDataContentType())
+				return req
+			},
+			mockSetup: func(m *MockImageService) {
+				m.On("Upload", "test-property-id", mock.Anything, mock.Anything, "").Return(nil, fmt.Errorf("storage quota exceeded: basic plan allows 1000 bytes, 900 used"))
+			},
+			expectedStatus: http.StatusPaymentRequired,
+			expectedBody:   "storage quota exceeded",
+		},
+		{
+			name:   "maximum images exceeded",
+			method: http.MethodPost,
+			setupRequest: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				writer.WriteField("property_id", "test-property-id")
+				
+				fileWriter, _ := writer.CreateFormFile("image", "test.jpg")
+				fileWriter.Write([]byte("fake-image-data"))
+				writer.Close()
+				
+				req := httptest.NewRequest(http.MethodPost, "/api/images", body)
+				req.Header.Set("Content-Type", writer.FormDataContentType())
+				return req
+			},
+			mockSetup: func(m *MockImageService) {
+				m.On("Upload", "test-property-id", mock.Anything, mock.Anything, "").Return(nil, fmt.Errorf("maximum images per property exceeded: 20"))
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "maximum images per property exceeded",
+		},
 	}
 
 	for _, tt := range tests {
@@ -231,6 +347,180 @@ func TestImageHandler_UploadImage(t *testing.T) {
 	}
 }
 
+func TestImageHandler_UploadImages(t *testing.T) {
+	newBatchRequest := func(propertyID string, files map[string]string, archive []byte) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		if propertyID != "" {
+			writer.WriteField("property_id", propertyID)
+		}
+		for name, content := range files {
+			fileWriter, _ := writer.CreateFormFile("images", name)
+			fileWriter.Write([]byte(content))
+		}
+		if archive != nil {
+			fileWriter, _ := writer.CreateFormFile("archive", "photos.zip")
+			fileWriter.Write(archive)
+		}
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/images/batch", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("files and archive are uploaded together", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		extracted := []domain.ImageUpload{{FileName: "sala.jpg", ContentType: "image/jpeg", Data: []byte("zip-image")}}
+		mockService.On("ExtractArchive", []byte("zip-data")).Return(extracted, nil)
+		mockService.On("UploadBatch", "property-1", mock.MatchedBy(func(uploads []domain.ImageUpload) bool {
+			return len(uploads) == 2 && uploads[0].FileName == "cocina.jpg" && uploads[1].FileName == "sala.jpg"
+		}), "").Return(&domain.BatchUploadResult{
+			PropertyID: "property-1",
+			Total:      2,
+			Succeeded:  1,
+			Failed:     1,
+			Results: []domain.ImageUploadResult{
+				{Index: 0, FileName: "cocina.jpg", Success: true},
+				{Index: 1, FileName: "sala.jpg", Error: "image validation failed"},
+			},
+		}, nil)
+
+		rr := httptest.NewRecorder()
+		handler.UploadImages(rr, newBatchRequest("property-1", map[string]string{"cocina.jpg": "image"}, []byte("zip-data")))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "1 succeeded, 1 failed")
+		assert.Contains(t, rr.Body.String(), `"error":"image validation failed"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing property ID", func(t *testing.T) {
+		handler := NewImageHandler(&MockImageService{})
+		rr := httptest.NewRecorder()
+		handler.UploadImages(rr, newBatchRequest("", map[string]string{"a.jpg": "image"}, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Property ID is required")
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		handler := NewImageHandler(&MockImageService{})
+		rr := httptest.NewRecorder()
+		handler.UploadImages(rr, newBatchRequest("property-1", nil, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "At least one image or archive is required")
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+		mockService.On("ExtractArchive", []byte("not-a-zip")).Return(nil, fmt.Errorf("invalid zip archive"))
+
+		rr := httptest.NewRecorder()
+		handler.UploadImages(rr, newBatchRequest("property-1", nil, []byte("not-a-zip")))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Failed to extract archive")
+		mockService.AssertNotCalled(t, "UploadBatch", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		handler := NewImageHandler(&MockImageService{})
+		rr := httptest.NewRecorder()
+		handler.UploadImages(rr, httptest.NewRequest(http.MethodGet, "/api/images/batch", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestImageHandler_DirectUploads(t *testing.T) {
+	t.Run("create upload URL", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		session := &domain.UploadSession{ID: "upload-1", Status: domain.UploadStatusPending, UploadURL: "https://bucket.s3/uploads/x.jpg?X-Amz-Signature=abc"}
+		mockService.On("CreateUploadURL", "property-1", "sala.jpg", "image/jpeg", "Sala", int64(2048)).Return(session, nil)
+
+		body := `{"property_id":"property-1","file_name":"sala.jpg","content_type":"image/jpeg","size":2048,"alt_text":"Sala"}`
+		rr := httptest.NewRecorder()
+		handler.CreateUploadURL(rr, httptest.NewRequest(http.MethodPost, "/api/images/upload-url", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "X-Amz-Signature")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("create upload URL without presigning storage", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		mockService.On("CreateUploadURL", "property-1", "sala.jpg", "image/jpeg", "", int64(10)).
+			Return(nil, fmt.Errorf("storage backend local does not support direct uploads"))
+
+		body := `{"property_id":"property-1","file_name":"sala.jpg","content_type":"image/jpeg","size":10}`
+		rr := httptest.NewRecorder()
+		handler.CreateUploadURL(rr, httptest.NewRequest(http.MethodPost, "/api/images/upload-url", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusNotImplemented, rr.Code)
+	})
+
+	t.Run("create upload URL rejects invalid file", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		mockService.On("CreateUploadURL", "property-1", "doc.pdf", "application/pdf", "", int64(10)).
+			Return(nil, fmt.Errorf("upload validation failed: unsupported content type: application/pdf"))
+
+		body := `{"property_id":"property-1","file_name":"doc.pdf","content_type":"application/pdf","size":10}`
+		rr := httptest.NewRecorder()
+		handler.CreateUploadURL(rr, httptest.NewRequest(http.MethodPost, "/api/images/upload-url", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("confirm upload", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		mockService.On("ConfirmUpload", "upload-1").Return(&domain.UploadSession{ID: "upload-1", Status: domain.UploadStatusProcessing}, nil)
+
+		rr := httptest.NewRecorder()
+		handler.ConfirmUpload(rr, httptest.NewRequest(http.MethodPost, "/api/images/uploads/upload-1/confirm", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"status":"processing"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("confirm expired upload", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		mockService.On("ConfirmUpload", "upload-1").Return(nil, fmt.Errorf("upload session expired"))
+
+		rr := httptest.NewRecorder()
+		handler.ConfirmUpload(rr, httptest.NewRequest(http.MethodPost, "/api/images/uploads/upload-1/confirm", nil))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("get unknown upload", func(t *testing.T) {
+		mockService := &MockImageService{}
+		handler := NewImageHandler(mockService)
+
+		mockService.On("GetUploadSession", "missing").Return(nil, fmt.Errorf("upload session not found: missing"))
+
+		rr := httptest.NewRecorder()
+		handler.GetUploadSession(rr, httptest.NewRequest(http.MethodGet, "/api/images/uploads/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
 func TestImageHandler_GetImage(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -295,6 +585,89 @@ func TestImageHandler_GetImage(t *testing.T) {
 	}
 }
 
+func TestImageHandler_GetAgencyQuota(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		role           string
+		agencyID       string
+		mockSetup      func(*MockImageService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "agency gets its quota",
+			path:     "/api/agencies/agency-1/quota",
+			role:     "agency",
+			agencyID: "agency-1",
+			mockSetup: func(m *MockImageService) {
+				quota := domain.NewImageQuota("agency-1", domain.DefaultImageQuotaPlans["basic"], domain.ImageStorageUsage{ImageCount: 3, UsedBytes: 2048})
+				m.On("GetAgencyQuota", "agency-1").Return(quota, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "\"used_bytes\":2048",
+		},
+		{
+			name: "agency not found",
+			path: "/api/agencies/missing/quota",
+			role: "admin",
+			mockSetup: func(m *MockImageService) {
+				m.On("GetAgencyQuota", "missing").Return(nil, fmt.Errorf("agency not found: missing"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Agency not found",
+		},
+		{
+			name:     "quotas not enabled",
+			path:     "/api/agencies/agency-1/quota",
+			role:     "agent",
+			agencyID: "agency-1",
+			mockSetup: func(m *MockImageService) {
+				m.On("GetAgencyQuota", "agency-1").Return(nil, fmt.Errorf("image quotas are not enabled"))
+			},
+			expectedStatus: http.StatusNotImplemented,
+			expectedBody:   "not enabled",
+		},
+		{
+			name:           "another agency is forbidden",
+			path:           "/api/agencies/agency-1/quota",
+			role:           "agent",
+			agencyID:       "agency-2",
+			mockSetup:      func(m *MockImageService) {},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Only members of the agency",
+		},
+		{
+			name:           "anonymous is forbidden",
+			path:           "/api/agencies/agency-1/quota",
+			mockSetup:      func(m *MockImageService) {},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Only members of the agency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockImageService{}
+			handler := NewImageHandler(mockService)
+			
+			tt.mockSetup(mockService)
+			
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			ctx := context.WithValue(req.Context(), middleware.RoleKey, tt.role)
+			req = req.WithContext(context.WithValue(ctx, middleware.AgencyIDKey, tt.agencyID))
+			rr := httptest.NewRecorder()
+			
+			handler.GetAgencyQuota(rr, req)
+			
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestImageHandler_GetImagesByProperty(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -717,24 +1090,40 @@ func TestImageHandler_GetImageVariant(t *testing.T) {
 		expectedBody   string
 	}{
 		{
-			name: "successful get variant",
-			path: "/api/images/test-id/variant?w=200&h=200&f=jpg&q=80",
+			name: "successful get preset",
+			path: "/api/images/test-id/variant?preset=card",
 			mockSetup: func(m *MockImageService) {
 				expectedData := []byte("fake-variant-data")
-				m.On("GetImageVariant", "test-id", 200, 200, "jpg", 80).Return(expectedData, nil)
+				m.On("GetImagePreset", "test-id", "card").Return(expectedData, "image/jpeg", nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   "fake-variant-data",
 		},
 		{
 			name: "variant not found",
-			path: "/api/images/nonexistent-id/variant?w=200&h=200",
+			path: "/api/images/nonexistent-id/variant?preset=card",
 			mockSetup: func(m *MockImageService) {
-				m.On("GetImageVariant", "nonexistent-id", 200, 200, "jpg", 85).Return(nil, fmt.Errorf("variant not found"))
+				m.On("GetImagePreset", "nonexistent-id", "card").Return(nil, "", fmt.Errorf("failed to get image: image not found"))
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "Image not found",
 		},
+		{
+			name:           "ad-hoc sizes are rejected",
+			path:           "/api/images/test-id/variant?w=200&h=200&f=jpg&q=80",
+			mockSetup:      func(m *MockImageService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "card, gallery, hero, og-image, thumb",
+		},
+		{
+			name: "unknown preset",
+			path: "/api/images/test-id/variant?preset=huge",
+			mockSetup: func(m *MockImageService) {
+				m.On("GetImagePreset", "test-id", "huge").Return(nil, "", fmt.Errorf("unknown image preset: huge"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Unknown image preset",
+		},
 	}
 
 	for _, tt := range tests {
@@ -784,6 +1173,26 @@ func TestImageHandler_GetThumbnail(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "Image not found",
 		},
+		{
+			name: "image too large to decode",
+			path: "/api/images/huge-id/thumbnail",
+			mockSetup: func(m *MockImageService) {
+				m.On("GenerateThumbnail", "huge-id", 150).Return(nil,
+					fmt.Errorf("failed to generate thumbnail: %w: 20000x20000 is 400.0 megapixels, the limit is 100.0", processors.ErrImageTooLarge))
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "400.0 megapixels",
+		},
+		{
+			name: "decoding at capacity",
+			path: "/api/images/busy-id/thumbnail",
+			mockSetup: func(m *MockImageService) {
+				m.On("GenerateThumbnail", "busy-id", 150).Return(nil,
+					fmt.Errorf("failed to generate thumbnail: %w: 4 images are being decoded, retry shortly", processors.ErrImageProcessingBusy))
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   "retry shortly",
+		},
 	}
 
 	for _, tt := range tests {
@@ -797,6 +1206,9 @@ func TestImageHandler_GetThumbnail(t *testing.T) {
 			rr := httptest.NewRecorder()
 			
 			handler.GetThumbnail(rr, req)
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				assert.Equal(t, imageLimitRetryAfter, rr.Header().Get("Retry-After"))
+			}
 			
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			assert.Contains(t, rr.Body.String(), tt.expectedBody)
@@ -851,3 +1263,19 @@ func TestImageHandler_CleanupTempFiles(t *testing.T) {
 	}
 }
 
+
+func TestImageHandler_QuarantinedImageNotFound(t *testing.T) {
+	mockService := &MockImageService{}
+	mockService.On("GetImage", "img-q").Return(nil, fmt.Errorf("image not found: img-q"))
+	mockService.On("GetImagePreset", "img-q", "card").Return(nil, "", fmt.Errorf("failed to get image: image not found: img-q"))
+	handler := NewImageHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	handler.GetImage(rr, httptest.NewRequest(http.MethodGet, "/api/images/img-q", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.GetImageVariant(rr, httptest.NewRequest(http.MethodGet, "/api/images/img-q/variant?preset=card", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
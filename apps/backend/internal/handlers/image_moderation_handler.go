@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// ImageModerationHandler handles the admin review queue for moderated images
+type ImageModerationHandler struct {
+	moderationService *service.ImageModerationService
+	logger            *log.Logger
+}
+
+// NewImageModerationHandler creates a new image moderation handler
+func NewImageModerationHandler(moderationService *service.ImageModerationService, logger *log.Logger) *ImageModerationHandler {
+	return &ImageModerationHandler{
+		moderationService: moderationService,
+		logger:            logger,
+	}
+}
+
+// ReviewRequest represents an admin moderation decision
+type ReviewRequest struct {
+	Note string `json:"note"`
+}
+
+// ListQueue handles GET /api/admin/moderation/images?status=&page=&page_size=
+func (h *ImageModerationHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	params := domain.NewPaginationParams()
+	if page, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && page > 0 {
+		params.Page = page
+	}
+	if pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size")); err == nil && pageSize > 0 {
+		params.PageSize = pageSize
+	}
+
+	queue, err := h.moderationService.ListQueue(r.URL.Query().Get("status"), params)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, queue, http.StatusOK)
+}
+
+// ApproveImage handles POST /api/admin/moderation/images/{id}/approve
+func (h *ImageModerationHandler) ApproveImage(w http.ResponseWriter, r *http.Request) {
+	imageID := h.extractImageID(r.URL.Path)
+	if imageID == "" {
+		http.Error(w, "Image ID required", http.StatusBadRequest)
+		return
+	}
+
+	req, ok := h.decodeReview(w, r)
+	if !ok {
+		return
+	}
+
+	record, err := h.moderationService.Approve(imageID, middleware.GetUserID(r.Context()), req.Note)
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, record, http.StatusOK)
+}
+
+// RejectImage handles POST /api/admin/moderation/images/{id}/reject.
+// Rejected images are deleted together with their files.
+func (h *ImageModerationHandler) RejectImage(w http.ResponseWriter, r *http.Request) {
+	imageID := h.extractImageID(r.URL.Path)
+	if imageID == "" {
+		http.Error(w, "Image ID required", http.StatusBadRequest)
+		return
+	}
+
+	req, ok := h.decodeReview(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.Reject(imageID, middleware.GetUserID(r.Context()), req.Note); err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]string{"rejected": imageID}, http.StatusOK)
+}
+
+// Helper functions
+
+// extractImageID returns the image ID from /api/admin/moderation/images/{id}/...
+func (h *ImageModerationHandler) extractImageID(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		if part == "images" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// decodeReview reads the optional review note
+func (h *ImageModerationHandler) decodeReview(w http.ResponseWriter, r *http.Request) (ReviewRequest, bool) {
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+func (h *ImageModerationHandler) sendReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "not awaiting review"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *ImageModerationHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ImageSuggestionHandler handles the attributes and main image suggested by listing photos
+type ImageSuggestionHandler struct {
+	attributeService *service.ImageAttributeService
+	logger           *log.Logger
+}
+
+// NewImageSuggestionHandler creates a new image suggestion handler
+func NewImageSuggestionHandler(attributeService *service.ImageAttributeService, logger *log.Logger) *ImageSuggestionHandler {
+	return &ImageSuggestionHandler{
+		attributeService: attributeService,
+		logger:           logger,
+	}
+}
+
+// GetSuggestions handles GET /api/properties/{id}/image-suggestions. Suggestions are
+// for the agent to confirm and are never applied to the listing.
+func (h *ImageSuggestionHandler) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	suggestions, err := h.attributeService.Suggest(propertyID, actor(r))
+	if err != nil {
+		h.sendSuggestionError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, suggestions, http.StatusOK)
+}
+
+// Analyze handles POST /api/properties/{id}/image-suggestions/analyze, queueing every
+// photo of the listing for analysis by the image-analysis job
+func (h *ImageSuggestionHandler) Analyze(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	queued, err := h.attributeService.QueueProperty(propertyID, actor(r))
+	if err != nil {
+		h.sendSuggestionError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]int{"queued": queued}, http.StatusAccepted)
+}
+
+// Helper functions
+
+func (h *ImageSuggestionHandler) sendSuggestionError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "unavailable"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Image suggestion error: %v", err)
+		http.Error(w, "Failed to process image suggestions", http.StatusInternalServerError)
+	}
+}
+
+func (h *ImageSuggestionHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// InventoryReportHandler serves agency inventory reports
+type InventoryReportHandler struct {
+	reportService *service.InventoryReportService
+	logger        *log.Logger
+}
+
+// NewInventoryReportHandler creates a new inventory report handler
+func NewInventoryReportHandler(reportService *service.InventoryReportService, logger *log.Logger) *InventoryReportHandler {
+	return &InventoryReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+// GetInventoryReport handles GET /api/agencies/{id}/reports/inventory?period=weekly|monthly&limit=12
+// Each snapshot has the active, new, sold and rented listings of the period with the
+// average days on market and discount from list price.
+func (h *InventoryReportHandler) GetInventoryReport(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[2] == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+	agencyID := parts[2]
+
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	actor := actor(r)
+
+	period := query.Get("period")
+	snapshots, err := h.reportService.GetInventoryReport(agencyID, period, limit, actor)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "permission denied"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case strings.Contains(err.Error(), "invalid"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Printf("Error getting inventory report of agency %s: %v", agencyID, err)
+			http.Error(w, "Failed to get inventory report", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if period == "" {
+		period = domain.InventoryPeriodMonthly
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"agency_id": agencyID,
+		"period":    period,
+		"snapshots": snapshots,
+		"count":     len(snapshots),
+	})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/scheduler"
+)
+
+// JobHandler lets administrators inspect and trigger scheduled jobs
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+	logger    *log.Logger
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(scheduler *scheduler.Scheduler, logger *log.Logger) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// ListJobs handles GET /api/admin/jobs
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := h.scheduler.Jobs()
+	h.sendJSONResponse(w, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	}, http.StatusOK)
+}
+
+// ListRuns handles GET /api/admin/jobs/{name}/runs?limit=20 and, without a name,
+// GET /api/admin/jobs/runs for the runs of every job
+func (h *JobHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	name := pathSegment(r.URL.Path, 3)
+	if name == "runs" {
+		name = ""
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	runs, err := h.scheduler.History(name, limit)
+	if err != nil {
+		h.sendJobError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"runs":  runs,
+		"count": len(runs),
+	}, http.StatusOK)
+}
+
+// TriggerJob handles POST /api/admin/jobs/{name}/run. The job runs in the background;
+// the response is the started run.
+func (h *JobHandler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	run, err := h.scheduler.Trigger(pathSegment(r.URL.Path, 3), middleware.GetUserID(r.Context()))
+	if err != nil {
+		h.sendJobError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, run, http.StatusAccepted)
+}
+
+// Helper functions
+
+func (h *JobHandler) sendJobError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "already running"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.logger.Printf("Job error: %v", err)
+		http.Error(w, "Failed to process job", http.StatusInternalServerError)
+	}
+}
+
+func (h *JobHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// JWTKeyHandler publishes the public JWT signing keys and lets administrators rotate them
+type JWTKeyHandler struct {
+	keyService *service.JWTSigningKeyService
+	logger     *log.Logger
+}
+
+// NewJWTKeyHandler creates a new JWT key handler
+func NewJWTKeyHandler(keyService *service.JWTSigningKeyService, logger *log.Logger) *JWTKeyHandler {
+	return &JWTKeyHandler{
+		keyService: keyService,
+		logger:     logger,
+	}
+}
+
+// GetJWKS handles GET /.well-known/jwks.json
+// Verifiers should refetch the set when a token names an unknown kid.
+func (h *JWTKeyHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.keyService.JWKS())
+}
+
+// ListKeys handles GET /api/admin/auth/keys
+func (h *JWTKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys, err := h.keyService.ListKeys(actor(r))
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.sendJSONResponse(w, map[string]interface{}{
+		"enabled": h.keyService.Enabled(),
+		"keys":    keys,
+	}, http.StatusOK)
+}
+
+// RotateKey handles POST /api/admin/auth/keys/rotate
+func (h *JWTKeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, err := h.keyService.Rotate(actor(r))
+	if err != nil {
+		h.sendKeyError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, key, http.StatusCreated)
+}
+
+// Helper functions
+
+func (h *JWTKeyHandler) sendKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("JWT signing key error: %v", err)
+		http.Error(w, "Failed to process signing keys", http.StatusInternalServerError)
+	}
+}
+
+func (h *JWTKeyHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// LeadPipelineHandler handles the CRM pipeline of agencies: its stages, moving leads
+// between stages, the kanban board and stage-duration analytics
+type LeadPipelineHandler struct {
+	pipelineService *service.LeadPipelineService
+	logger          *log.Logger
+}
+
+// NewLeadPipelineHandler creates a new lead pipeline handler
+func NewLeadPipelineHandler(pipelineService *service.LeadPipelineService, logger *log.Logger) *LeadPipelineHandler {
+	return &LeadPipelineHandler{
+		pipelineService: pipelineService,
+		logger:          logger,
+	}
+}
+
+type updatePipelineRequest struct {
+	Stages []domain.PipelineStage `json:"stages"`
+}
+
+type moveLeadRequest struct {
+	Stage string `json:"stage"`
+}
+
+// GetPipeline handles GET /api/agencies/{id}/lead-pipeline
+func (h *LeadPipelineHandler) GetPipeline(w http.ResponseWriter, r *http.Request) {
+	pipeline, err := h.pipelineService.GetPipeline(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, pipeline, http.StatusOK)
+}
+
+// UpdatePipeline handles PUT /api/agencies/{id}/lead-pipeline
+// ({"stages": [{"key": "new", "name": "Nuevo", "kind": "open"}, ...]}). The first stage
+// is where new leads enter; stages that still have leads cannot be removed.
+func (h *LeadPipelineHandler) UpdatePipeline(w http.ResponseWriter, r *http.Request) {
+	var req updatePipelineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	pipeline, err := h.pipelineService.UpdatePipeline(pathSegment(r.URL.Path, 2), req.Stages, actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, pipeline, http.StatusOK)
+}
+
+// GetBoard handles GET /api/agencies/{id}/lead-pipeline/board?agent_id=
+// Leads are grouped by stage for a kanban board; agents only get their own leads.
+func (h *LeadPipelineHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	board, err := h.pipelineService.GetBoard(pathSegment(r.URL.Path, 2), r.URL.Query().Get("agent_id"), actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, board, http.StatusOK)
+}
+
+// GetAnalytics handles GET /api/agencies/{id}/lead-pipeline/analytics?agent_id=&from=2025-06-01&to=2025-09-01
+func (h *LeadPipelineHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	from, err := parseDateParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	analytics, err := h.pipelineService.GetStageAnalytics(pathSegment(r.URL.Path, 2), r.URL.Query().Get("agent_id"), from, to, actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, analytics, http.StatusOK)
+}
+
+// MoveLead handles POST /api/leads/{id}/stage ({"stage": "contacted"})
+func (h *LeadPipelineHandler) MoveLead(w http.ResponseWriter, r *http.Request) {
+	var req moveLeadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	lead, transition, err := h.pipelineService.MoveLead(pathSegment(r.URL.Path, 2), req.Stage, actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"lead":       lead,
+		"transition": transition,
+	}, http.StatusOK)
+}
+
+// GetLeadHistory handles GET /api/leads/{id}/stages
+func (h *LeadPipelineHandler) GetLeadHistory(w http.ResponseWriter, r *http.Request) {
+	transitions, err := h.pipelineService.GetLeadHistory(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{"transitions": transitions}, http.StatusOK)
+}
+
+// GetLeadTimeline handles GET /api/leads/{id}/timeline
+// It merges the lead's routing, stage changes and masked calls, oldest first.
+func (h *LeadPipelineHandler) GetLeadTimeline(w http.ResponseWriter, r *http.Request) {
+	timeline, err := h.pipelineService.GetLeadTimeline(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendPipelineError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{"timeline": timeline}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *LeadPipelineHandler) sendPipelineError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "moved meanwhile"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Lead pipeline error: %v", err)
+		http.Error(w, "Failed to process lead pipeline", http.StatusInternalServerError)
+	}
+}
+
+func (h *LeadPipelineHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
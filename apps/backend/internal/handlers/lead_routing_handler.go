@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// LeadRoutingHandler handles the lead routing rules of agencies, the routing outcome of
+// their leads and the leads routed to agents
+type LeadRoutingHandler struct {
+	routingService *service.LeadRoutingService
+	logger         *log.Logger
+}
+
+// NewLeadRoutingHandler creates a new lead routing handler
+func NewLeadRoutingHandler(routingService *service.LeadRoutingService, logger *log.Logger) *LeadRoutingHandler {
+	return &LeadRoutingHandler{
+		routingService: routingService,
+		logger:         logger,
+	}
+}
+
+// ListRules handles GET /api/agencies/{id}/lead-routing/rules
+func (h *LeadRoutingHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.routingService.ListRules(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendRoutingError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{"rules": rules}, http.StatusOK)
+}
+
+// CreateRule handles POST /api/agencies/{id}/lead-routing/rules
+// Rules are tried by ascending priority; the first matching rule with an active agent
+// takes the lead.
+func (h *LeadRoutingHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req domain.LeadRoutingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.routingService.CreateRule(pathSegment(r.URL.Path, 2), req, actor(r))
+	if err != nil {
+		h.sendRoutingError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, rule, http.StatusCreated)
+}
+
+// UpdateRule handles PUT /api/agencies/{id}/lead-routing/rules/{ruleID}
+func (h *LeadRoutingHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	var req domain.LeadRoutingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.routingService.UpdateRule(pathSegment(r.URL.Path, 2), pathSegment(r.URL.Path, 5), req, actor(r))
+	if err != nil {
+		h.sendRoutingError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, rule, http.StatusOK)
+}
+
+// DeleteRule handles DELETE /api/agencies/{id}/lead-routing/rules/{ruleID}
+func (h *LeadRoutingHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := h.routingService.DeleteRule(pathSegment(r.URL.Path, 2), pathSegment(r.URL.Path, 5), actor(r)); err != nil {
+		h.sendRoutingError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAssignments handles GET /api/agencies/{id}/lead-routing/assignments?agent_id=&outcome=sector&limit=20&offset=0
+// Each outcome carries the lead's score and the rule that routed it.
+func (h *LeadRoutingHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	assignments, total, err := h.routingService.ListAssignments(domain.LeadAssignmentFilter{
+		AgencyID: pathSegment(r.URL.Path, 2),
+		AgentID:  query.Get("agent_id"),
+		Outcome:  query.Get("outcome"),
+		Limit:    limit,
+		Offset:   offset,
+	}, actor(r))
+	if err != nil {
+		h.sendRoutingError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"assignments": assignments,
+		"total":       total,
+	}, http.StatusOK)
+}
+
+// ListMyLeads handles GET /api/leads/mine?limit=20&offset=0 for agents
+func (h *LeadRoutingHandler) ListMyLeads(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	leads, total, err := h.routingService.ListMyLeads(limit, offset, actor(r))
+	if err != nil {
+		h.sendRoutingError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"leads": leads,
+		"total": total,
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *LeadRoutingHandler) sendRoutingError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Lead routing error: %v", err)
+		http.Error(w, "Failed to process lead routing", http.StatusInternalServerError)
+	}
+}
+
+func (h *LeadRoutingHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
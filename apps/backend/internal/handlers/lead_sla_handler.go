@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// LeadSLAHandler handles the lead response policy of agencies and the report of how
+// fast their agents answer leads
+type LeadSLAHandler struct {
+	slaService *service.LeadSLAService
+	logger     *log.Logger
+}
+
+// NewLeadSLAHandler creates a new lead SLA handler
+func NewLeadSLAHandler(slaService *service.LeadSLAService, logger *log.Logger) *LeadSLAHandler {
+	return &LeadSLAHandler{
+		slaService: slaService,
+		logger:     logger,
+	}
+}
+
+type updateLeadSLARequest struct {
+	TargetMinutes        int `json:"target_minutes"`
+	ReassignAfterMinutes int `json:"reassign_after_minutes"`
+}
+
+// GetPolicy handles GET /api/agencies/{id}/lead-sla
+func (h *LeadSLAHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.slaService.GetPolicy(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendSLAError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, policy, http.StatusOK)
+}
+
+// UpdatePolicy handles PUT /api/agencies/{id}/lead-sla
+// ({"target_minutes": 30, "reassign_after_minutes": 120}). A reassign_after_minutes of 0
+// keeps unanswered leads with their agent.
+func (h *LeadSLAHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req updateLeadSLARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.slaService.UpdatePolicy(pathSegment(r.URL.Path, 2), req.TargetMinutes, req.ReassignAfterMinutes, actor(r))
+	if err != nil {
+		h.sendSLAError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, policy, http.StatusOK)
+}
+
+// GetReport handles GET /api/agencies/{id}/lead-sla/report?agent_id=&from=2025-09-01&to=2025-10-01
+// Agents only get their own response times.
+func (h *LeadSLAHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	from, err := parseDateParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.slaService.GetReport(pathSegment(r.URL.Path, 2), r.URL.Query().Get("agent_id"), from, to, actor(r))
+	if err != nil {
+		h.sendSLAError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *LeadSLAHandler) sendSLAError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Lead SLA error: %v", err)
+		http.Error(w, "Failed to process lead response times", http.StatusInternalServerError)
+	}
+}
+
+func (h *LeadSLAHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ListingLifecycleHandler handles listing expiration and renewal
+type ListingLifecycleHandler struct {
+	lifecycleService *service.ListingLifecycleService
+	logger           *log.Logger
+}
+
+// NewListingLifecycleHandler creates a new listing lifecycle handler
+func NewListingLifecycleHandler(lifecycleService *service.ListingLifecycleService, logger *log.Logger) *ListingLifecycleHandler {
+	return &ListingLifecycleHandler{
+		lifecycleService: lifecycleService,
+		logger:           logger,
+	}
+}
+
+// RenewListing handles POST /api/properties/{id}/renew and republishes an expired or
+// available listing
+func (h *ListingLifecycleHandler) RenewListing(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	renewal, err := h.lifecycleService.RenewListing(propertyID, actor)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "permission denied"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case strings.Contains(err.Error(), "plan limit"):
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+		case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "not renewable"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			h.logger.Printf("Error renewing listing %s: %v", propertyID, err)
+			http.Error(w, "Failed to renew listing", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, renewal, http.StatusOK)
+}
+
+// ExpireStale handles POST /api/admin/maintenance/listings/expire and expires stale
+// listings without waiting for the scheduler
+func (h *ListingLifecycleHandler) ExpireStale(w http.ResponseWriter, r *http.Request) {
+	expired, err := h.lifecycleService.ExpireStale()
+	if err != nil {
+		h.logger.Printf("Error expiring stale listings: %v", err)
+		http.Error(w, "Failed to expire listings", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"expired": expired,
+		"count":   len(expired),
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *ListingLifecycleHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
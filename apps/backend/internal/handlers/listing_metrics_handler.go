@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ListingMetricsHandler serves days on market analytics and stale listing reports
+type ListingMetricsHandler struct {
+	metricsService *service.ListingMetricsService
+	logger         *log.Logger
+}
+
+// NewListingMetricsHandler creates a new listing metrics handler
+func NewListingMetricsHandler(metricsService *service.ListingMetricsService, logger *log.Logger) *ListingMetricsHandler {
+	return &ListingMetricsHandler{
+		metricsService: metricsService,
+		logger:         logger,
+	}
+}
+
+// GetPropertyAnalytics handles GET /api/properties/{id}/analytics
+// The property's agency, agent or owner see its days on market and whether it is stale.
+func (h *ListingMetricsHandler) GetPropertyAnalytics(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	metrics, err := h.metricsService.GetPropertyMetrics(propertyID, actor(r))
+	if err != nil {
+		h.sendMetricsError(w, err, "Failed to get property analytics")
+		return
+	}
+
+	h.sendJSONResponse(w, metrics, http.StatusOK)
+}
+
+// GetMarketDaysOnMarket handles GET /api/analytics/market/days-on-market?province=Pichincha&type=house&days=365
+// Listings sold or rented in the last days, 365 by default, give the average and median
+// days on market; listings still on the market their average age.
+func (h *ListingMetricsHandler) GetMarketDaysOnMarket(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	days, ok := h.parsePositive(w, query.Get("days"), "days")
+	if !ok {
+		return
+	}
+
+	market, err := h.metricsService.GetMarketDaysOnMarket(query.Get("province"), query.Get("type"), days)
+	if err != nil {
+		h.sendMetricsError(w, err, "Failed to get market days on market")
+		return
+	}
+
+	h.sendJSONResponse(w, market, http.StatusOK)
+}
+
+// GetStaleListings handles GET /api/agencies/{id}/reports/stale-listings?days=60&limit=200
+// Lists the agency's available and reserved listings published at least days ago,
+// LISTING_STALE_DAYS by default, oldest first.
+func (h *ListingMetricsHandler) GetStaleListings(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	days, ok := h.parsePositive(w, query.Get("days"), "days")
+	if !ok {
+		return
+	}
+	limit, ok := h.parsePositive(w, query.Get("limit"), "limit")
+	if !ok {
+		return
+	}
+
+	listings, err := h.metricsService.GetStaleListings(agencyID, days, limit, actor(r))
+	if err != nil {
+		h.sendMetricsError(w, err, "Failed to get stale listings")
+		return
+	}
+
+	if days == 0 {
+		days = h.metricsService.StaleDays()
+	}
+	h.sendJSONResponse(w, map[string]interface{}{
+		"agency_id":  agencyID,
+		"stale_days": days,
+		"listings":   listings,
+		"count":      len(listings),
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+// parsePositive parses an optional positive query parameter, zero when absent
+func (h *ListingMetricsHandler) parsePositive(w http.ResponseWriter, value, name string) (int, bool) {
+	if value == "" {
+		return 0, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		http.Error(w, "Invalid "+name+" parameter", http.StatusBadRequest)
+		return 0, false
+	}
+	return parsed, true
+}
+
+func (h *ListingMetricsHandler) sendMetricsError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Listing metrics error: %v", err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *ListingMetricsHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// MaintenanceRequestHandler serves the maintenance requests of rented properties
+type MaintenanceRequestHandler struct {
+	maintenanceService *service.MaintenanceRequestService
+	logger             *log.Logger
+}
+
+// NewMaintenanceRequestHandler creates a new maintenance request handler
+func NewMaintenanceRequestHandler(maintenanceService *service.MaintenanceRequestService, logger *log.Logger) *MaintenanceRequestHandler {
+	return &MaintenanceRequestHandler{
+		maintenanceService: maintenanceService,
+		logger:             logger,
+	}
+}
+
+// OpenRequest handles POST /api/properties/{id}/maintenance
+// ({"category": "plumbing", "priority": "urgent", "title": "Leaking sink", "description": "..."})
+// Only the current tenant of the rented property can open requests.
+func (h *MaintenanceRequestHandler) OpenRequest(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req service.OpenMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.PropertyID = propertyID
+
+	request, err := h.maintenanceService.Open(req, actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, request, http.StatusCreated)
+}
+
+// ListPropertyHistory handles GET /api/properties/{id}/maintenance?status=open
+// Requests come newest first with their status history.
+func (h *MaintenanceRequestHandler) ListPropertyHistory(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	requests, err := h.maintenanceService.ListPropertyHistory(propertyID, r.URL.Query().Get("status"), actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"requests":    requests,
+		"count":       len(requests),
+	}, http.StatusOK)
+}
+
+// ListMyRequests handles GET /api/maintenance/mine
+func (h *MaintenanceRequestHandler) ListMyRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.maintenanceService.ListMyRequests(actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"requests": requests,
+		"count":    len(requests),
+	}, http.StatusOK)
+}
+
+// GetRequest handles GET /api/maintenance/{id}
+func (h *MaintenanceRequestHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := pathSegment(r.URL.Path, 2)
+	if requestID == "" {
+		http.Error(w, "Request ID required", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.maintenanceService.GetRequest(requestID, actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, request, http.StatusOK)
+}
+
+// ChangeStatus handles PATCH /api/maintenance/{id}/status ({"status": "in_progress", "comment": "Plumber visits Monday"})
+func (h *MaintenanceRequestHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	requestID := pathSegment(r.URL.Path, 2)
+	if requestID == "" {
+		http.Error(w, "Request ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status  string `json:"status"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.maintenanceService.ChangeStatus(requestID, req.Status, req.Comment, actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, request, http.StatusOK)
+}
+
+// AddPhoto handles POST /api/maintenance/{id}/photos (multipart form with a "photo" file)
+// Photos are JPEG, PNG or WebP images of at most 5MB.
+func (h *MaintenanceRequestHandler) AddPhoto(w http.ResponseWriter, r *http.Request) {
+	requestID := pathSegment(r.URL.Path, 2)
+	if requestID == "" {
+		http.Error(w, "Request ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseMultipartForm(domain.MaxMaintenancePhotoSize); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		http.Error(w, "photo file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	photo, err := h.maintenanceService.AddPhoto(requestID, header.Filename, file, actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, photo, http.StatusCreated)
+}
+
+// GetPhoto handles GET /api/maintenance/{id}/photos/{photoId}
+func (h *MaintenanceRequestHandler) GetPhoto(w http.ResponseWriter, r *http.Request) {
+	requestID := pathSegment(r.URL.Path, 2)
+	photoID := pathSegment(r.URL.Path, 4)
+	if requestID == "" || photoID == "" {
+		http.Error(w, "Request and photo ID required", http.StatusBadRequest)
+		return
+	}
+
+	photo, data, err := h.maintenanceService.GetPhoto(requestID, photoID, actor(r))
+	if err != nil {
+		h.sendMaintenanceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", photo.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Helper functions
+
+func (h *MaintenanceRequestHandler) sendMaintenanceError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "no longer"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "too large"):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case strings.Contains(err.Error(), "infected"):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "exceeded"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Maintenance request error: %v", err)
+		http.Error(w, "Failed to process maintenance request", http.StatusInternalServerError)
+	}
+}
+
+func (h *MaintenanceRequestHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"realty-core/internal/logging"
@@ -140,9 +141,57 @@ func (mh *MonitoringHandler) generatePrometheusOutput(snapshot monitoring.Metric
 		_ = sanitized // Use variable to avoid unused warning
 	}
 	
+	output += mh.generateRequestSeriesOutput()
+	
 	return output
 }
 
+// prometheusLabelEscaper escapes label values in the Prometheus text format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// generateRequestSeriesOutput writes the request metrics labelled by route template,
+// status class and, when enabled, agency and tenant, answering which endpoint or agency
+// generates the load, e.g. topk(5, sum by (agency) (rate(realty_core_http_route_requests_total[5m])))
+func (mh *MonitoringHandler) generateRequestSeriesOutput() string {
+	requests := mh.metricsCollector.Requests()
+	series := requests.Series()
+	if len(series) == 0 {
+		return ""
+	}
+	agencyLabels := requests.AgencyLabels()
+
+	var requestsOut, sumOut, countOut strings.Builder
+	for _, s := range series {
+		labels := requestSeriesLabels(s.Labels, agencyLabels)
+		requestsOut.WriteString("realty_core_http_route_requests_total" + labels + " " + strconv.FormatInt(s.Requests, 10) + "\n")
+		sumOut.WriteString("realty_core_http_route_duration_ms_sum" + labels + " " + strconv.FormatFloat(s.DurationSumMs, 'f', 3, 64) + "\n")
+		countOut.WriteString("realty_core_http_route_duration_ms_count" + labels + " " + strconv.FormatInt(s.Requests, 10) + "\n")
+	}
+
+	return "# HELP realty_core_http_route_requests_total HTTP requests by route template and status class\n" +
+		"# TYPE realty_core_http_route_requests_total counter\n" +
+		requestsOut.String() + "\n" +
+		"# HELP realty_core_http_route_duration_ms HTTP request duration by route template and status class\n" +
+		"# TYPE realty_core_http_route_duration_ms summary\n" +
+		sumOut.String() + countOut.String() + "\n"
+}
+
+// requestSeriesLabels formats the labels of a request series; agency and tenant are
+// only present when agency labels are enabled, so every series has the same labels
+func requestSeriesLabels(labels monitoring.RequestLabels, agencyLabels bool) string {
+	pairs := []string{
+		`method="` + prometheusLabelEscaper.Replace(labels.Method) + `"`,
+		`route="` + prometheusLabelEscaper.Replace(labels.Route) + `"`,
+		`status_class="` + prometheusLabelEscaper.Replace(labels.StatusClass) + `"`,
+	}
+	if agencyLabels {
+		pairs = append(pairs,
+			`agency="`+prometheusLabelEscaper.Replace(labels.Agency)+`"`,
+			`tenant="`+prometheusLabelEscaper.Replace(labels.Tenant)+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
 // GetAlerts returns current active alerts
 func (mh *MonitoringHandler) GetAlerts(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -231,8 +280,9 @@ func (mh *MonitoringHandler) GetAlertRules(w http.ResponseWriter, r *http.Reques
 	}
 	
 	response := AlertRulesResponse{
-		Count: len(rulesResponse),
-		Rules: rulesResponse,
+		Count:     len(rulesResponse),
+		Rules:     rulesResponse,
+		Anomalies: mh.alertManager.GetAnomalyRules(),
 	}
 	
 	w.WriteHeader(http.StatusOK)
@@ -259,6 +309,8 @@ type AlertRuleResponse struct {
 type AlertRulesResponse struct {
 	Count int                            `json:"count"`
 	Rules map[string]AlertRuleResponse  `json:"rules"`
+	// Anomalies holds the windows and thresholds of the business metric rules
+	Anomalies []monitoring.AnomalyRule `json:"anomalies"`
 }
 
 // UpdateAlertRule updates an alert rule configuration
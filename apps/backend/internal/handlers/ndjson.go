@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ndjsonContentType is the media type of newline-delimited JSON: one document per line
+const ndjsonContentType = "application/x-ndjson"
+
+// ndjsonFlushEvery is how many rows are written between flushes
+const ndjsonFlushEvery = 100
+
+// wantsNDJSON reports whether a request asks for newline-delimited JSON in Accept
+func wantsNDJSON(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == ndjsonContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// ndjsonStream writes rows as newline-delimited JSON as they are produced, so large
+// result sets are never buffered. The status is sent with the first row: an error
+// before it is answered as usual, an error after it ends the stream with a last line
+// {"error": "..."} that clients must check for.
+type ndjsonStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	encoder    *json.Encoder
+	rows       int
+	failed     bool
+}
+
+func newNDJSONStream(w http.ResponseWriter) *ndjsonStream {
+	return &ndjsonStream{w: w, controller: http.NewResponseController(w), encoder: json.NewEncoder(w)}
+}
+
+// Write writes a row, flushing every ndjsonFlushEvery rows; its error, such as a
+// disconnected client, should stop the query producing the rows
+func (s *ndjsonStream) Write(row interface{}) error {
+	if s.rows == 0 {
+		s.start()
+	}
+	if err := s.encoder.Encode(row); err != nil {
+		s.failed = true
+		return err
+	}
+	s.rows++
+	if s.rows%ndjsonFlushEvery == 0 {
+		s.controller.Flush()
+	}
+	return nil
+}
+
+// Started reports whether the status was sent
+func (s *ndjsonStream) Started() bool {
+	return s.rows > 0
+}
+
+// Finish ends the stream; an error of the producer ends it with an error line. An empty
+// result set is an empty 200 response.
+func (s *ndjsonStream) Finish(err error) {
+	if s.rows == 0 && err == nil {
+		s.start()
+		return
+	}
+	if err != nil && !s.failed {
+		log.Printf("NDJSON stream interrupted after %d rows: %v", s.rows, err)
+		s.encoder.Encode(map[string]string{"error": "stream interrupted"})
+	}
+	s.controller.Flush()
+}
+
+func (s *ndjsonStream) start() {
+	s.w.Header().Set("Content-Type", ndjsonContentType)
+	s.w.Header().Set("X-Content-Type-Options", "nosniff")
+	s.w.WriteHeader(http.StatusOK)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
 	"realty-core/internal/service"
 )
 
@@ -37,19 +38,34 @@ func NewPaginationHandlerSimple(
 	}
 }
 
+// tenantProperties, tenantUsers and tenantAgencies return the services scoped to the
+// tenant of the request
+func (h *PaginationHandlerSimple) tenantProperties(r *http.Request) *service.PropertyService {
+	// ForTenant of a PropertyService returns a PropertyService
+	return h.propertyService.ForTenant(middleware.GetTenantID(r.Context())).(*service.PropertyService)
+}
+
+func (h *PaginationHandlerSimple) tenantUsers(r *http.Request) *service.UserServiceSimple {
+	return h.userService.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
+func (h *PaginationHandlerSimple) tenantAgencies(r *http.Request) *service.AgencyService {
+	return h.agencyService.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
 // GetPaginatedProperties handles paginated property retrieval
 func (h *PaginationHandlerSimple) GetPaginatedProperties(w http.ResponseWriter, r *http.Request) {
 	params := h.extractPaginationParams(r)
 	
 	// Use existing property service with pagination
-	properties, err := h.propertyService.GetPaginatedProperties(params)
+	properties, err := h.tenantProperties(r).GetPaginatedProperties(params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Count total properties for pagination metadata
-	totalCount, err := h.propertyService.CountProperties()
+	totalCount, err := h.tenantProperties(r).CountProperties()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -96,7 +112,7 @@ func (h *PaginationHandlerSimple) GetPaginatedUsers(w http.ResponseWriter, r *ht
 	params := h.extractPaginationParams(r)
 	
 	// Use the correct method signature
-	users, totalCount, err := h.userService.SearchUsers("", "", domain.UserRole(""), nil, params.PageSize, params.GetOffset())
+	users, totalCount, err := h.tenantUsers(r).SearchUsers("", "", domain.UserRole(""), nil, params.PageSize, params.GetOffset())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -120,7 +136,7 @@ func (h *PaginationHandlerSimple) GetPaginatedAgencies(w http.ResponseWriter, r
 		Pagination: params,
 	}
 
-	agencies, pagination, err := h.agencyService.SearchAgencies(searchParams)
+	agencies, pagination, err := h.tenantAgencies(r).SearchAgencies(searchParams)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -148,12 +164,12 @@ func (h *PaginationHandlerSimple) GetPaginatedSearch(w http.ResponseWriter, r *h
 	searchResults := make(map[string]interface{})
 	
 	// Search properties
-	if properties, err := h.propertyService.SearchPropertiesSimple(query, params); err == nil {
+	if properties, err := h.tenantProperties(r).SearchPropertiesSimple(query, params); err == nil {
 		searchResults["properties"] = properties
 	}
 
 	// Search users
-	if users, _, err := h.userService.SearchUsers("", query, domain.UserRole(""), nil, params.PageSize, params.GetOffset()); err == nil {
+	if users, _, err := h.tenantUsers(r).SearchUsers("", query, domain.UserRole(""), nil, params.PageSize, params.GetOffset()); err == nil {
 		searchResults["users"] = users
 	}
 
@@ -162,7 +178,7 @@ func (h *PaginationHandlerSimple) GetPaginatedSearch(w http.ResponseWriter, r *h
 		Query:      query,
 		Pagination: params,
 	}
-	if agencies, _, err := h.agencyService.SearchAgencies(agencySearchParams); err == nil {
+	if agencies, _, err := h.tenantAgencies(r).SearchAgencies(agencySearchParams); err == nil {
 		searchResults["agencies"] = agencies
 	}
 
@@ -181,7 +197,7 @@ func (h *PaginationHandlerSimple) GetPaginationStats(w http.ResponseWriter, r *h
 	stats := make(map[string]interface{})
 
 	// Get counts for different entities
-	if propertyCount, err := h.propertyService.CountProperties(); err == nil {
+	if propertyCount, err := h.tenantProperties(r).CountProperties(); err == nil {
 		stats["total_properties"] = propertyCount
 	}
 
@@ -243,14 +259,14 @@ func (h *PaginationHandlerSimple) HandleAdvancedPagination(w http.ResponseWriter
 
 	switch req.Entity {
 	case "properties":
-		result, err = h.propertyService.GetPaginatedProperties(params)
+		result, err = h.tenantProperties(r).GetPaginatedProperties(params)
 		if err == nil {
-			if count, countErr := h.propertyService.CountProperties(); countErr == nil {
+			if count, countErr := h.tenantProperties(r).CountProperties(); countErr == nil {
 				pagination = domain.NewPagination(params.Page, params.PageSize, count)
 			}
 		}
 	case "users":
-		users, totalCount, searchErr := h.userService.SearchUsers("", req.SearchTerm, domain.UserRole(""), nil, params.PageSize, params.GetOffset())
+		users, totalCount, searchErr := h.tenantUsers(r).SearchUsers("", req.SearchTerm, domain.UserRole(""), nil, params.PageSize, params.GetOffset())
 		if searchErr == nil {
 			result = users
 			pagination = domain.NewPagination(params.Page, params.PageSize, totalCount)
@@ -261,7 +277,7 @@ func (h *PaginationHandlerSimple) HandleAdvancedPagination(w http.ResponseWriter
 			Query:      req.SearchTerm,
 			Pagination: params,
 		}
-		result, pagination, err = h.agencyService.SearchAgencies(searchParams)
+		result, pagination, err = h.tenantAgencies(r).SearchAgencies(searchParams)
 	case "images":
 		result, err = h.imageService.GetPaginatedImages(params)
 		if err == nil {
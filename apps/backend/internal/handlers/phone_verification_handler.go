@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// PhoneVerificationHandler serves SMS verification of the phone of agents and owners
+type PhoneVerificationHandler struct {
+	phoneVerificationService *service.PhoneVerificationService
+	logger                   *log.Logger
+}
+
+// NewPhoneVerificationHandler creates a new phone verification handler
+func NewPhoneVerificationHandler(phoneVerificationService *service.PhoneVerificationService, logger *log.Logger) *PhoneVerificationHandler {
+	return &PhoneVerificationHandler{
+		phoneVerificationService: phoneVerificationService,
+		logger:                   logger,
+	}
+}
+
+// verifyPhoneRequest carries the code received by SMS
+type verifyPhoneRequest struct {
+	Code string `json:"code"`
+}
+
+// RequestCode handles POST /api/auth/phone/request-code
+// A code is sent by SMS to the phone number of the profile; a new code can be
+// requested once a minute.
+func (h *PhoneVerificationHandler) RequestCode(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	verification, err := h.phoneVerificationService.RequestCode(userID)
+	if err != nil {
+		h.sendPhoneVerificationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"message":    "Verification code sent",
+		"phone":      verification.Phone,
+		"expires_at": verification.ExpiresAt,
+	}, http.StatusAccepted)
+}
+
+// VerifyCode handles POST /api/auth/phone/verify
+// A valid code marks the phone as verified, which is required to publish listings.
+func (h *PhoneVerificationHandler) VerifyCode(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req verifyPhoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.phoneVerificationService.VerifyCode(userID, req.Code)
+	if err != nil {
+		h.sendPhoneVerificationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"message":           "Phone verified",
+		"phone_verified_at": user.PhoneVerifiedAt,
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *PhoneVerificationHandler) sendPhoneVerificationError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "too many"):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case strings.Contains(err.Error(), "only available to"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already verified"), strings.Contains(err.Error(), "already used"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "expired"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not configured"), strings.Contains(err.Error(), "failed to send"):
+		h.logger.Printf("Phone verification error: %v", err)
+		http.Error(w, "SMS delivery is unavailable", http.StatusServiceUnavailable)
+	default:
+		h.logger.Printf("Phone verification error: %v", err)
+		http.Error(w, "Failed to process phone verification", http.StatusInternalServerError)
+	}
+}
+
+func (h *PhoneVerificationHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
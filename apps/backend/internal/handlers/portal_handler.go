@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// PortalHandler serves the tenant and landlord dashboards of the signed-in user
+type PortalHandler struct {
+	portalService *service.PortalService
+	logger        *log.Logger
+}
+
+// NewPortalHandler creates a new portal handler
+func NewPortalHandler(portalService *service.PortalService, logger *log.Logger) *PortalHandler {
+	return &PortalHandler{
+		portalService: portalService,
+		logger:        logger,
+	}
+}
+
+// GetDashboard handles GET /api/portal
+// The dashboard lists the user's portal roles, current leases and pending maintenance.
+func (h *PortalHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard, err := h.portalService.Dashboard(actor(r))
+	if err != nil {
+		h.sendPortalError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, dashboard, http.StatusOK)
+}
+
+// ListRentals handles GET /api/portal/rentals?role=tenant
+func (h *PortalHandler) ListRentals(w http.ResponseWriter, r *http.Request) {
+	rentals, err := h.portalService.ListRentals(r.URL.Query().Get("role"), actor(r))
+	if err != nil {
+		h.sendPortalError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"rentals": rentals,
+		"count":   len(rentals),
+	}, http.StatusOK)
+}
+
+// ListPayments handles GET /api/portal/payments
+func (h *PortalHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
+	payments, err := h.portalService.ListPayments(actor(r))
+	if err != nil {
+		h.sendPortalError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"payments": payments,
+		"count":    len(payments),
+	}, http.StatusOK)
+}
+
+// ListMaintenance handles GET /api/portal/maintenance?status=open
+func (h *PortalHandler) ListMaintenance(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.portalService.ListMaintenance(r.URL.Query().Get("status"), actor(r))
+	if err != nil {
+		h.sendPortalError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"requests": requests,
+		"count":    len(requests),
+	}, http.StatusOK)
+}
+
+// ListDocuments handles GET /api/portal/documents
+// Tenants see the public documents and the contracts of their lease; landlords every document.
+func (h *PortalHandler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	documents, err := h.portalService.ListDocuments(actor(r))
+	if err != nil {
+		h.sendPortalError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"properties": documents,
+		"count":      len(documents),
+	}, http.StatusOK)
+}
+
+// DownloadDocument handles GET /api/portal/documents/{id}
+func (h *PortalHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	documentID := pathSegment(r.URL.Path, 3)
+	if documentID == "" {
+		http.Error(w, "Document ID required", http.StatusBadRequest)
+		return
+	}
+
+	document, data, err := h.portalService.GetDocument(documentID, actor(r))
+	if err != nil {
+		h.sendPortalError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Helper functions
+
+func (h *PortalHandler) sendPortalError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Portal error: %v", err)
+		http.Error(w, "Failed to load portal", http.StatusInternalServerError)
+	}
+}
+
+func (h *PortalHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// ProfileImageHandler serves the upload of user avatars and agency logos
+type ProfileImageHandler struct {
+	profileImageService *service.ProfileImageService
+	logger              *log.Logger
+}
+
+// NewProfileImageHandler creates a new profile image handler
+func NewProfileImageHandler(profileImageService *service.ProfileImageService, logger *log.Logger) *ProfileImageHandler {
+	return &ProfileImageHandler{
+		profileImageService: profileImageService,
+		logger:              logger,
+	}
+}
+
+// UploadAvatar handles PUT /api/users/{id}/avatar (multipart form, file field "image")
+// The image is cropped to square sizes; the response lists the URL of each size.
+func (h *ProfileImageHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID := pathSegment(r.URL.Path, 2)
+	if userID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	data, ok := h.readImage(w, r)
+	if !ok {
+		return
+	}
+
+	image, err := h.profileImageService.UploadAvatar(userID, data, actor(r))
+	if err != nil {
+		h.sendProfileImageError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, image, http.StatusOK)
+}
+
+// DeleteAvatar handles DELETE /api/users/{id}/avatar
+func (h *ProfileImageHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID := pathSegment(r.URL.Path, 2)
+	if userID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.profileImageService.DeleteAvatar(userID, actor(r)); err != nil {
+		h.sendProfileImageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadAgencyLogo handles PUT /api/agencies/{id}/logo (multipart form, file field "image")
+func (h *ProfileImageHandler) UploadAgencyLogo(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	data, ok := h.readImage(w, r)
+	if !ok {
+		return
+	}
+
+	image, err := h.profileImageService.UploadAgencyLogo(agencyID, data, actor(r))
+	if err != nil {
+		h.sendProfileImageError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, image, http.StatusOK)
+}
+
+// DeleteAgencyLogo handles DELETE /api/agencies/{id}/logo
+func (h *ProfileImageHandler) DeleteAgencyLogo(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.profileImageService.DeleteAgencyLogo(agencyID, actor(r)); err != nil {
+		h.sendProfileImageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Helper functions
+
+// readImage reads the uploaded image, rejecting files over the profile image limit
+func (h *ProfileImageHandler) readImage(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxProfileImageUploadSize+1<<20)
+	if err := r.ParseMultipartForm(domain.MaxProfileImageUploadSize); err != nil {
+		http.Error(w, "Failed to parse form: image must be at most 5MB", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "Failed to get uploaded file", http.StatusBadRequest)
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, domain.MaxProfileImageUploadSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read uploaded file", http.StatusBadRequest)
+		return nil, false
+	}
+	return data, true
+}
+
+func (h *ProfileImageHandler) sendProfileImageError(w http.ResponseWriter, err error) {
+	if status, ok := imageLimitStatus(w, err); ok {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "exceeds"):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Profile image error: %v", err)
+		http.Error(w, "Failed to process image", http.StatusInternalServerError)
+	}
+}
+
+func (h *ProfileImageHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -5,17 +5,24 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
 	"realty-core/internal/repository"
 	"realty-core/internal/service"
 )
 
 // PropertyHandler handles HTTP requests for properties
 type PropertyHandler struct {
-	service service.PropertyServiceInterface
+	service      service.PropertyServiceInterface
+	translations *service.PropertyTranslationService
+	currencies   *service.CurrencyService
+	preferences  *service.UserPreferencesService
+	guides       *service.SectorGuideService
+	risks        *service.RiskZoneService
 }
 
 // NewPropertyHandler creates a new instance of the handler
@@ -23,6 +30,56 @@ func NewPropertyHandler(service service.PropertyServiceInterface) *PropertyHandl
 	return &PropertyHandler{service: service}
 }
 
+// tenantService returns the property service scoped to the tenant of the request, so
+// properties of other tenants are not found
+func (h *PropertyHandler) tenantService(r *http.Request) service.PropertyServiceInterface {
+	return h.service.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
+// SetTranslationService serves property titles and descriptions in the locale of each
+// request (?locale= or Accept-Language), falling back to Spanish
+func (h *PropertyHandler) SetTranslationService(translations *service.PropertyTranslationService) {
+	h.translations = translations
+}
+
+// SetCurrencyService adds prices converted to the currency of ?currency= (e.g. COP)
+// to property responses. Stored prices stay in USD.
+func (h *PropertyHandler) SetCurrencyService(currencies *service.CurrencyService) {
+	h.currencies = currencies
+}
+
+// SetPreferencesService serves signed-in users in their preferred locale and fills the
+// filters their searches leave unset with their search defaults (disabled by ?defaults=false)
+func (h *PropertyHandler) SetPreferencesService(preferences *service.UserPreferencesService) {
+	h.preferences = preferences
+}
+
+// SetSectorGuideService links properties to the published guide of their sector
+func (h *PropertyHandler) SetSectorGuideService(guides *service.SectorGuideService) {
+	h.guides = guides
+}
+
+// SetRiskZoneService adds the hazard zones a property lies in to property detail responses
+func (h *PropertyHandler) SetRiskZoneService(risks *service.RiskZoneService) {
+	h.risks = risks
+}
+
+// withRiskFlags returns a copy of a property with its risk flags; when they cannot be
+// loaded the property is served without them
+func (h *PropertyHandler) withRiskFlags(property *domain.Property) *domain.Property {
+	if h.risks == nil || property == nil {
+		return property
+	}
+	flags, err := h.risks.GetPropertyRisk(property.ID)
+	if err != nil {
+		log.Printf("Error loading risk flags of property %s: %v", property.ID, err)
+		return property
+	}
+	withFlags := *property
+	withFlags.RiskFlags = flags
+	return &withFlags
+}
+
 // CreatePropertyRequest represents the request structure for creating a property
 // Updated to match complete domain Property struct - ALL 50+ fields supported (2025)
 type CreatePropertyRequest struct {
@@ -87,6 +144,9 @@ type CreatePropertyRequest struct {
 	ContactPhone  string `json:"contact_phone"`
 	ContactEmail  string `json:"contact_email"`
 	Notes         string `json:"notes,omitempty"`
+
+	// Website is the spam honeypot field, hidden from people by the form
+	Website string `json:"website,omitempty"`
 }
 
 
@@ -167,11 +227,22 @@ func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request)
 		ContactPhone:  req.ContactPhone,
 		ContactEmail:  req.ContactEmail,
 		Notes:         req.Notes,
+		Website:       req.Website,
+
+		TenantID: middleware.GetTenantID(r.Context()),
 	}
 
-	property, err := h.service.CreatePropertyComplete(serviceReq)
+	property, err := h.tenantService(r).CreatePropertyComplete(serviceReq)
 
 	if err != nil {
+		if strings.Contains(err.Error(), "plan limit") {
+			h.respondError(w, http.StatusPaymentRequired, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "phone verification required") {
+			h.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -192,7 +263,7 @@ func (h *PropertyHandler) GetProperty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	property, err := h.service.GetProperty(id)
+	property, err := h.tenantService(r).GetProperty(id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.respondError(w, http.StatusNotFound, err.Error())
@@ -202,7 +273,7 @@ func (h *PropertyHandler) GetProperty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, property, "Property retrieved successfully")
+	h.respondLocalized(w, r, http.StatusOK, h.withRiskFlags(property), "Property retrieved successfully")
 }
 
 // GetPropertyBySlug handles GET /api/properties/slug/{slug}
@@ -218,7 +289,7 @@ func (h *PropertyHandler) GetPropertyBySlug(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	property, err := h.service.GetPropertyBySlug(slug)
+	property, err := h.tenantService(r).GetPropertyBySlug(slug)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.respondError(w, http.StatusNotFound, err.Error())
@@ -228,17 +299,38 @@ func (h *PropertyHandler) GetPropertyBySlug(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, property, "Property retrieved by slug successfully")
+	h.respondLocalized(w, r, http.StatusOK, h.withRiskFlags(property), "Property retrieved by slug successfully")
 }
 
 // ListProperties handles GET /api/properties
+// With ?page= or ?page_size= a single page is returned, as by /api/properties/paginated;
+// with Accept: application/x-ndjson the catalog is streamed one property per line.
 func (h *PropertyHandler) ListProperties(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
+	if hasPaginationParams(r) && !wantsNDJSON(r) {
+		h.ListPropertiesPaginated(w, r)
+		return
+	}
 
-	properties, err := h.service.ListProperties()
+	if wantsNDJSON(r) {
+		filters := domain.NewPropertySearchFilters()
+		filters.TenantID = middleware.GetTenantID(r.Context())
+		h.streamProperties(w, r, filters, http.StatusInternalServerError)
+		return
+	}
+
+	var properties []domain.Property
+	var err error
+	if tenantID := middleware.GetTenantID(r.Context()); tenantID != "" {
+		filters := domain.NewPropertySearchFilters()
+		filters.TenantID = tenantID
+		properties, err = h.tenantService(r).FilterProperties(filters)
+	} else {
+		properties, err = h.tenantService(r).ListProperties()
+	}
 	if err != nil {
 		h.respondError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -266,7 +358,8 @@ func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	property, err := h.service.UpdateProperty(
+	property, err := h.tenantService(r).UpdatePropertyBy(
+		middleware.GetUserID(r.Context()),
 		id,
 		req.Title,
 		req.Description,
@@ -301,7 +394,7 @@ func (h *PropertyHandler) DeleteProperty(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.service.DeleteProperty(id)
+	err := h.tenantService(r).DeleteProperty(id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.respondError(w, http.StatusNotFound, err.Error())
@@ -314,72 +407,65 @@ func (h *PropertyHandler) DeleteProperty(w http.ResponseWriter, r *http.Request)
 	h.respondSuccess(w, http.StatusOK, nil, "Property deleted successfully")
 }
 
-// FilterProperties handles GET /api/properties/filter (basic filtering)
+// FilterProperties handles GET /api/properties/filter
+// All supported filters can be combined in a single request, e.g.
+// ?province=Pichincha&city=Quito&type=apartment&min_bedrooms=2&has_pool=true
+// With ?page= or ?page_size= a single page is returned, as by /api/properties/filter/paginated;
+// with Accept: application/x-ndjson the matches are streamed one property per line.
 func (h *PropertyHandler) FilterProperties(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
-
-	query := r.URL.Query()
-	province := query.Get("province")
-	minPriceStr := query.Get("min_price")
-	maxPriceStr := query.Get("max_price")
-	searchQuery := query.Get("q")
-
-	// Search by query if provided
-	if searchQuery != "" {
-		properties, err := h.service.SearchProperties(searchQuery)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		h.respondSuccess(w, http.StatusOK, properties, "Properties filtered by search query")
+	if hasPaginationParams(r) && !wantsNDJSON(r) {
+		h.FilterPropertiesPaginated(w, r)
 		return
 	}
 
-	// Filter by province if provided
-	if province != "" {
-		properties, err := h.service.FilterByProvince(province)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		h.respondSuccess(w, http.StatusOK, properties, "Properties filtered by province")
+	filters, err := h.parseFilterParams(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	h.applySearchDefaults(r, filters)
 
-	// Filter by price range if provided
-	if minPriceStr != "" && maxPriceStr != "" {
-		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, "Invalid minimum price")
-			return
-		}
-
-		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, "Invalid maximum price")
-			return
-		}
+	if wantsNDJSON(r) {
+		h.streamProperties(w, r, filters, http.StatusBadRequest)
+		return
+	}
 
-		properties, err := h.service.FilterByPriceRange(minPrice, maxPrice)
+	// If no filters, return all properties
+	if !filters.HasCriteria() && filters.TenantID == "" {
+		properties, err := h.tenantService(r).ListProperties()
 		if err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
+			h.respondError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		h.respondSuccess(w, http.StatusOK, properties, "Properties filtered by price range")
+		h.respondSuccess(w, http.StatusOK, properties, "All properties")
 		return
 	}
 
-	// If no filters, return all properties
-	properties, err := h.service.ListProperties()
+	properties, err := h.tenantService(r).FilterProperties(filters)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, properties, "All properties")
+	h.respondSuccess(w, http.StatusOK, properties, "Properties filtered")
+}
+
+// streamProperties streams the properties matching filters as NDJSON; errors before
+// the first property are answered with errorStatus
+func (h *PropertyHandler) streamProperties(w http.ResponseWriter, r *http.Request, filters *domain.PropertySearchFilters, errorStatus int) {
+	stream := newNDJSONStream(w)
+	err := h.tenantService(r).StreamProperties(filters, func(property *domain.Property) error {
+		return stream.Write(property)
+	})
+	if err != nil && !stream.Started() {
+		h.respondError(w, errorStatus, err.Error())
+		return
+	}
+	stream.Finish(err)
 }
 
 // SearchRanked handles GET /api/properties/search/ranked
@@ -408,13 +494,20 @@ func (h *PropertyHandler) SearchRanked(w http.ResponseWriter, r *http.Request) {
 		limit = parsedLimit
 	}
 
-	results, err := h.service.SearchPropertiesRanked(searchQuery, limit)
+	var results []repository.PropertySearchResult
+	var err error
+	if locale := h.requestLocale(r); locale != domain.DefaultLocale {
+		// Other locales are searched with their own text search configuration
+		results, err = h.tenantService(r).AdvancedSearch(repository.AdvancedSearchParams{Query: searchQuery, Limit: limit, Locale: locale})
+	} else {
+		results, err = h.tenantService(r).SearchPropertiesRanked(searchQuery, limit)
+	}
 	if err != nil {
 		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, results, "Ranked search results retrieved successfully")
+	h.respondLocalized(w, r, http.StatusOK, results, "Ranked search results retrieved successfully")
 }
 
 // SearchSuggestions handles GET /api/properties/search/suggestions
@@ -438,7 +531,7 @@ func (h *PropertyHandler) SearchSuggestions(w http.ResponseWriter, r *http.Reque
 		limit = parsedLimit
 	}
 
-	suggestions, err := h.service.GetSearchSuggestions(searchQuery, limit)
+	suggestions, err := h.tenantService(r).GetSearchSuggestions(searchQuery, limit)
 	if err != nil {
 		h.respondError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -469,6 +562,7 @@ func (h *PropertyHandler) AdvancedSearch(w http.ResponseWriter, r *http.Request)
 		MaxArea      float64 `json:"max_area"`
 		FeaturedOnly bool    `json:"featured_only"`
 		Limit        int     `json:"limit"`
+		Locale       string  `json:"locale"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -492,15 +586,16 @@ func (h *PropertyHandler) AdvancedSearch(w http.ResponseWriter, r *http.Request)
 		MaxArea:      req.MaxArea,
 		FeaturedOnly: req.FeaturedOnly,
 		Limit:        req.Limit,
+		Locale:       h.searchLocale(r, req.Locale),
 	}
 
-	results, err := h.service.AdvancedSearch(params)
+	results, err := h.tenantService(r).AdvancedSearch(params)
 	if err != nil {
 		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, results, "Advanced search results retrieved successfully")
+	h.respondLocalizedTo(w, r, params.Locale, http.StatusOK, results, "Advanced search results retrieved successfully")
 }
 
 // GetStatistics handles GET /api/properties/statistics
@@ -510,7 +605,7 @@ func (h *PropertyHandler) GetStatistics(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	stats, err := h.service.GetStatistics()
+	stats, err := h.tenantService(r).GetStatistics()
 	if err != nil {
 		h.respondError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -543,7 +638,7 @@ func (h *PropertyHandler) SetPropertyLocation(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	err := h.service.SetPropertyLocation(id, req.Latitude, req.Longitude, req.Precision)
+	err := h.tenantService(r).SetPropertyLocation(id, req.Latitude, req.Longitude, req.Precision)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.respondError(w, http.StatusNotFound, err.Error())
@@ -578,10 +673,12 @@ func (h *PropertyHandler) SetPropertyFeatured(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	err := h.service.SetPropertyFeatured(id, req.Featured)
+	err := h.tenantService(r).SetPropertyFeatured(id, req.Featured)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.respondError(w, http.StatusNotFound, err.Error())
+		} else if strings.Contains(err.Error(), "plan limit") {
+			h.respondError(w, http.StatusPaymentRequired, err.Error())
 		} else {
 			h.respondError(w, http.StatusInternalServerError, err.Error())
 		}
@@ -613,7 +710,7 @@ func (h *PropertyHandler) SetPropertyParkingSpaces(w http.ResponseWriter, r *htt
 		return
 	}
 
-	err := h.service.SetPropertyParkingSpaces(id, req.ParkingSpaces)
+	err := h.tenantService(r).SetPropertyParkingSpaces(id, req.ParkingSpaces)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			h.respondError(w, http.StatusNotFound, err.Error())
@@ -729,6 +826,69 @@ func (h *PropertyHandler) respondSuccess(w http.ResponseWriter, status int, data
 	}
 }
 
+// requestLocale returns the supported locale of a request: the locale query parameter,
+// else the preferred locale of the signed-in user, else the Accept-Language header,
+// else Spanish
+func (h *PropertyHandler) requestLocale(r *http.Request) string {
+	if locale := domain.NormalizeLocale(r.URL.Query().Get("locale")); locale != "" {
+		return locale
+	}
+	if userID := middleware.GetUserID(r.Context()); h.preferences != nil && userID != "" {
+		if locale := h.preferences.PreferredLocale(userID); locale != "" {
+			return locale
+		}
+	}
+	return domain.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// applySearchDefaults fills the filters a signed-in user's search left unset with their
+// search defaults, unless the request asks for ?defaults=false
+func (h *PropertyHandler) applySearchDefaults(r *http.Request, filters *domain.PropertySearchFilters) {
+	userID := middleware.GetUserID(r.Context())
+	if h.preferences == nil || userID == "" || r.URL.Query().Get("defaults") == "false" {
+		return
+	}
+	h.preferences.ApplySearchDefaults(userID, filters)
+}
+
+// searchLocale returns the locale of a JSON search request, falling back to the request's
+func (h *PropertyHandler) searchLocale(r *http.Request, requested string) string {
+	if locale := domain.NormalizeLocale(requested); locale != "" {
+		return locale
+	}
+	return h.requestLocale(r)
+}
+
+// respondLocalized sends a successful response localized to the request's locale
+func (h *PropertyHandler) respondLocalized(w http.ResponseWriter, r *http.Request, status int, data interface{}, message string) {
+	h.respondLocalizedTo(w, r, h.requestLocale(r), status, data, message)
+}
+
+// respondLocalizedTo sends a successful response with property titles and descriptions in
+// locale where translated, announcing the language in Content-Language, and with display
+// prices in the requested currency and links to sector guides
+func (h *PropertyHandler) respondLocalizedTo(w http.ResponseWriter, r *http.Request, locale string, status int, data interface{}, message string) {
+	if currency := r.URL.Query().Get("currency"); currency != "" && h.currencies != nil {
+		annotated, err := h.currencies.Annotate(data, currency)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		data = annotated
+	}
+	if h.guides != nil {
+		data = h.guides.Link(data)
+	}
+
+	served := domain.DefaultLocale
+	if h.translations != nil {
+		data, served = h.translations.Localize(data, locale)
+	}
+	w.Header().Set("Content-Language", served)
+	w.Header().Add("Vary", "Accept-Language")
+	h.respondSuccess(w, status, data, message)
+}
+
 // Pagination handlers
 
 // ListPropertiesPaginated handles GET /api/properties/paginated
@@ -744,13 +904,20 @@ func (h *PropertyHandler) ListPropertiesPaginated(w http.ResponseWriter, r *http
 		return
 	}
 
-	result, err := h.service.ListPropertiesPaginated(pagination)
+	var result *domain.PaginatedResponse
+	if tenantID := middleware.GetTenantID(r.Context()); tenantID != "" {
+		filters := domain.NewPropertySearchFilters()
+		filters.TenantID = tenantID
+		result, err = h.tenantService(r).FilterPropertiesPaginated(filters, pagination)
+	} else {
+		result, err = h.tenantService(r).ListPropertiesPaginated(pagination)
+	}
 	if err != nil {
 		h.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, result, "Paginated properties retrieved successfully")
+	h.respondLocalized(w, r, http.StatusOK, result, "Paginated properties retrieved successfully")
 }
 
 // FilterPropertiesPaginated handles GET /api/properties/filter/paginated
@@ -760,11 +927,12 @@ func (h *PropertyHandler) FilterPropertiesPaginated(w http.ResponseWriter, r *ht
 		return
 	}
 
-	query := r.URL.Query()
-	province := query.Get("province")
-	minPriceStr := query.Get("min_price")
-	maxPriceStr := query.Get("max_price")
-	searchQuery := query.Get("q")
+	filters, err := h.parseFilterParams(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.applySearchDefaults(r, filters)
 
 	pagination, err := h.parsePaginationParams(r)
 	if err != nil {
@@ -772,61 +940,69 @@ func (h *PropertyHandler) FilterPropertiesPaginated(w http.ResponseWriter, r *ht
 		return
 	}
 
-	var result *domain.PaginatedResponse
-
-	// Search by query if provided
-	if searchQuery != "" {
-		result, err = h.service.SearchPropertiesPaginated(searchQuery, pagination)
+	// If no filters, return all properties paginated
+	if !filters.HasCriteria() && filters.TenantID == "" {
+		result, err := h.tenantService(r).ListPropertiesPaginated(pagination)
 		if err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
+			h.respondError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-		h.respondSuccess(w, http.StatusOK, result, "Paginated properties filtered by search query")
+		h.respondLocalized(w, r, http.StatusOK, result, "All paginated properties")
 		return
 	}
 
-	// Filter by province if provided
-	if province != "" {
-		result, err = h.service.FilterByProvincePaginated(province, pagination)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		h.respondSuccess(w, http.StatusOK, result, "Paginated properties filtered by province")
+	result, err := h.tenantService(r).FilterPropertiesPaginated(filters, pagination)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Filter by price range if provided
-	if minPriceStr != "" && maxPriceStr != "" {
-		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, "Invalid minimum price")
-			return
-		}
+	h.respondLocalized(w, r, http.StatusOK, result, "Paginated properties filtered")
+}
 
-		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, "Invalid maximum price")
-			return
-		}
+// ListManagedProperties handles GET /api/properties/managed
+// Lists the listings the caller manages with the filters of /api/properties/filter;
+// admins can narrow them with ?agency_id=, ?agent_id= or ?owner_id=.
+func (h *PropertyHandler) ListManagedProperties(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
 
-		result, err = h.service.FilterByPriceRangePaginated(minPrice, maxPrice, pagination)
-		if err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
-			return
+	filters, err := h.parseFilterParams(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	for name, target := range map[string]**string{
+		"agency_id": &filters.AgencyID,
+		"agent_id":  &filters.AgentID,
+		"owner_id":  &filters.OwnerID,
+	} {
+		if value := strings.TrimSpace(query.Get(name)); value != "" {
+			*target = &value
 		}
-		h.respondSuccess(w, http.StatusOK, result, "Paginated properties filtered by price range")
+	}
+
+	pagination, err := h.parsePaginationParams(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// If no filters, return all properties paginated
-	result, err = h.service.ListPropertiesPaginated(pagination)
+	actor := actor(r)
+	result, err := h.tenantService(r).ListManagedProperties(filters, pagination, actor)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		if strings.Contains(err.Error(), "permission denied") {
+			h.respondError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, result, "All paginated properties")
+	h.respondLocalized(w, r, http.StatusOK, result, "Managed properties retrieved successfully")
 }
 
 // SearchRankedPaginated handles GET /api/properties/search/ranked/paginated
@@ -850,46 +1026,42 @@ func (h *PropertyHandler) SearchRankedPaginated(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	result, err := h.service.SearchPropertiesRankedPaginated(searchQuery, pagination)
+	var result *domain.PaginatedResponse
+	if locale := h.requestLocale(r); locale != domain.DefaultLocale {
+		result, err = h.tenantService(r).AdvancedSearchPaginated(repository.AdvancedSearchParams{Query: searchQuery, Locale: locale}, pagination)
+	} else {
+		result, err = h.tenantService(r).SearchPropertiesRankedPaginated(searchQuery, pagination)
+	}
 	if err != nil {
 		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, result, "Paginated ranked search results retrieved successfully")
+	h.respondLocalized(w, r, http.StatusOK, result, "Paginated ranked search results retrieved successfully")
 }
 
-// AdvancedSearchPaginated handles POST /api/properties/search/advanced/paginated
-func (h *PropertyHandler) AdvancedSearchPaginated(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
-	}
-
-	var req struct {
-		Query        string                   `json:"query"`
-		Province     string                   `json:"province"`
-		City         string                   `json:"city"`
-		Type         string                   `json:"type"`
-		MinPrice     float64                  `json:"min_price"`
-		MaxPrice     float64                  `json:"max_price"`
-		MinBedrooms  int                      `json:"min_bedrooms"`
-		MaxBedrooms  int                      `json:"max_bedrooms"`
-		MinBathrooms float64                  `json:"min_bathrooms"`
-		MaxBathrooms float64                  `json:"max_bathrooms"`
-		MinArea      float64                  `json:"min_area"`
-		MaxArea      float64                  `json:"max_area"`
-		FeaturedOnly bool                     `json:"featured_only"`
-		Pagination   *domain.PaginationParams `json:"pagination"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
-		return
-	}
+// advancedSearchRequest is the JSON body of the paginated and faceted advanced search endpoints
+type advancedSearchRequest struct {
+	Query        string                   `json:"query"`
+	Province     string                   `json:"province"`
+	City         string                   `json:"city"`
+	Type         string                   `json:"type"`
+	MinPrice     float64                  `json:"min_price"`
+	MaxPrice     float64                  `json:"max_price"`
+	MinBedrooms  int                      `json:"min_bedrooms"`
+	MaxBedrooms  int                      `json:"max_bedrooms"`
+	MinBathrooms float64                  `json:"min_bathrooms"`
+	MaxBathrooms float64                  `json:"max_bathrooms"`
+	MinArea      float64                  `json:"min_area"`
+	MaxArea      float64                  `json:"max_area"`
+	FeaturedOnly bool                     `json:"featured_only"`
+	Locale       string                   `json:"locale"`
+	Pagination   *domain.PaginationParams `json:"pagination"`
+}
 
-	// Build search parameters
-	params := repository.AdvancedSearchParams{
+// params builds the repository search parameters from the request
+func (req *advancedSearchRequest) params() repository.AdvancedSearchParams {
+	return repository.AdvancedSearchParams{
 		Query:        req.Query,
 		Province:     req.Province,
 		City:         req.City,
@@ -903,20 +1075,69 @@ func (h *PropertyHandler) AdvancedSearchPaginated(w http.ResponseWriter, r *http
 		MinArea:      req.MinArea,
 		MaxArea:      req.MaxArea,
 		FeaturedOnly: req.FeaturedOnly,
+		Locale:       req.Locale,
+	}
+}
+
+// pagination returns the requested pagination or the defaults
+func (req *advancedSearchRequest) pagination() *domain.PaginationParams {
+	if req.Pagination == nil {
+		return domain.NewPaginationParams()
+	}
+	return req.Pagination
+}
+
+// AdvancedSearchPaginated handles POST /api/properties/search/advanced/paginated
+func (h *PropertyHandler) AdvancedSearchPaginated(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req advancedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	req.Locale = h.searchLocale(r, req.Locale)
+	result, err := h.tenantService(r).AdvancedSearchPaginated(req.params(), req.pagination())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	pagination := req.Pagination
-	if pagination == nil {
-		pagination = domain.NewPaginationParams()
+	h.respondLocalizedTo(w, r, req.Locale, http.StatusOK, result, "Paginated advanced search results retrieved successfully")
+}
+
+// AdvancedSearchFaceted handles POST /api/properties/search/advanced/faceted
+// Returns one page of results together with facet counts for all matching properties
+func (h *PropertyHandler) AdvancedSearchFaceted(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
 	}
 
-	result, err := h.service.AdvancedSearchPaginated(params, pagination)
+	var req advancedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	req.Locale = h.searchLocale(r, req.Locale)
+	result, err := h.tenantService(r).AdvancedSearchFaceted(req.params(), req.pagination())
 	if err != nil {
 		h.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	h.respondSuccess(w, http.StatusOK, result, "Paginated advanced search results retrieved successfully")
+	h.respondLocalizedTo(w, r, req.Locale, http.StatusOK, result, "Faceted search results retrieved successfully")
+}
+
+// hasPaginationParams reports whether a list request asks for a single page
+func hasPaginationParams(r *http.Request) bool {
+	query := r.URL.Query()
+	return query.Get("page") != "" || query.Get("page_size") != ""
 }
 
 // parsePaginationParams parses pagination parameters from URL query string
@@ -958,4 +1179,135 @@ func (h *PropertyHandler) parsePaginationParams(r *http.Request) (*domain.Pagina
 	}
 	
 	return pagination, nil
-}
\ No newline at end of file
+}
+// parseFilterParams parses the combinable property filters from the URL query string.
+// List filters (province, city, sector, type, status, tags, amenities, exclude_risks) accept repeated or comma-separated values.
+func (h *PropertyHandler) parseFilterParams(r *http.Request) (*domain.PropertySearchFilters, error) {
+	query := r.URL.Query()
+	filters := domain.NewPropertySearchFilters()
+
+	filters.Query = strings.TrimSpace(query.Get("q"))
+	filters.Provinces = parseListParam(query, "province")
+	filters.Cities = parseListParam(query, "city")
+	filters.Sectors = parseListParam(query, "sector")
+	filters.PropertyTypes = parseListParam(query, "type")
+	filters.Status = parseListParam(query, "status")
+	filters.Tags = parseListParam(query, "tags")
+	filters.Amenities = parseListParam(query, "amenities")
+	filters.ExcludeRisks = parseListParam(query, "exclude_risks")
+	filters.TenantID = middleware.GetTenantID(r.Context())
+
+	var err error
+	if filters.MinPrice, err = parseFloatParam(query, "min_price", "Invalid minimum price"); err != nil {
+		return nil, err
+	}
+	if filters.MaxPrice, err = parseFloatParam(query, "max_price", "Invalid maximum price"); err != nil {
+		return nil, err
+	}
+	if filters.MinArea, err = parseFloatParam(query, "min_area", "Invalid minimum area"); err != nil {
+		return nil, err
+	}
+	if filters.MaxArea, err = parseFloatParam(query, "max_area", "Invalid maximum area"); err != nil {
+		return nil, err
+	}
+	if filters.MinBedrooms, err = parseIntParam(query, "min_bedrooms", "Invalid minimum bedrooms"); err != nil {
+		return nil, err
+	}
+	if filters.MaxBedrooms, err = parseIntParam(query, "max_bedrooms", "Invalid maximum bedrooms"); err != nil {
+		return nil, err
+	}
+	if filters.MinParkingSpaces, err = parseIntParam(query, "min_parking_spaces", "Invalid minimum parking spaces"); err != nil {
+		return nil, err
+	}
+
+	minBathrooms, err := parseFloatParam(query, "min_bathrooms", "Invalid minimum bathrooms")
+	if err != nil {
+		return nil, err
+	}
+	if minBathrooms != nil {
+		value := float32(*minBathrooms)
+		filters.MinBathrooms = &value
+	}
+	maxBathrooms, err := parseFloatParam(query, "max_bathrooms", "Invalid maximum bathrooms")
+	if err != nil {
+		return nil, err
+	}
+	if maxBathrooms != nil {
+		value := float32(*maxBathrooms)
+		filters.MaxBathrooms = &value
+	}
+
+	boolFilters := map[string]**bool{
+		"featured":          &filters.Featured,
+		"furnished":         &filters.Furnished,
+		"has_pool":          &filters.HasPool,
+		"has_garden":        &filters.HasGarden,
+		"has_terrace":       &filters.HasTerrace,
+		"has_balcony":       &filters.HasBalcony,
+		"has_security":      &filters.HasSecurity,
+		"has_elevator":      &filters.HasElevator,
+		"has_air_condition": &filters.HasAirCondition,
+		"has_parking":       &filters.HasParking,
+	}
+	for name, target := range boolFilters {
+		value, err := parseBoolParam(query, name)
+		if err != nil {
+			return nil, err
+		}
+		*target = value
+	}
+
+	return filters, nil
+}
+
+// parseListParam collects repeated and comma-separated values for a query parameter
+func parseListParam(query url.Values, name string) []string {
+	var values []string
+	for _, raw := range query[name] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseFloatParam parses an optional float query parameter
+func parseFloatParam(query url.Values, name, errorMessage string) (*float64, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s", errorMessage)
+	}
+	return &value, nil
+}
+
+// parseIntParam parses an optional integer query parameter
+func parseIntParam(query url.Values, name, errorMessage string) (*int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s", errorMessage)
+	}
+	return &value, nil
+}
+
+// parseBoolParam parses an optional boolean query parameter
+func parseBoolParam(query url.Values, name string) (*bool, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameter: %s", name, raw)
+	}
+	return &value, nil
+}
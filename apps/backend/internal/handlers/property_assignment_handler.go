@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// PropertyAssignmentHandler handles agent assignments and listing transfers
+type PropertyAssignmentHandler struct {
+	assignmentService *service.PropertyAssignmentService
+	logger            *log.Logger
+}
+
+// NewPropertyAssignmentHandler creates a new property assignment handler
+func NewPropertyAssignmentHandler(assignmentService *service.PropertyAssignmentService, logger *log.Logger) *PropertyAssignmentHandler {
+	return &PropertyAssignmentHandler{
+		assignmentService: assignmentService,
+		logger:            logger,
+	}
+}
+
+// AssignAgent handles PUT /api/properties/{id}/agent ({"agent_id": "..."})
+func (h *PropertyAssignmentHandler) AssignAgent(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		AgentID string `json:"agent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.AgentID == "" {
+		http.Error(w, "Agent ID required", http.StatusBadRequest)
+		return
+	}
+
+	property, err := h.assignmentService.AssignAgent(propertyID, req.AgentID, actor(r))
+	if err != nil {
+		h.sendAssignmentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, property, http.StatusOK)
+}
+
+// UnassignAgent handles DELETE /api/properties/{id}/agent
+func (h *PropertyAssignmentHandler) UnassignAgent(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	property, err := h.assignmentService.UnassignAgent(propertyID, actor(r))
+	if err != nil {
+		h.sendAssignmentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, property, http.StatusOK)
+}
+
+// TransferListings handles POST /api/agencies/{id}/transfers
+// ({"from_agent_id": "...", "to_agent_id": "..."}) and moves all listings between agents
+func (h *PropertyAssignmentHandler) TransferListings(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		FromAgentID string `json:"from_agent_id"`
+		ToAgentID   string `json:"to_agent_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	propertyIDs, err := h.assignmentService.TransferListings(agencyID, req.FromAgentID, req.ToAgentID, actor(r))
+	if err != nil {
+		h.sendAssignmentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"agency_id":     agencyID,
+		"from_agent_id": req.FromAgentID,
+		"to_agent_id":   req.ToAgentID,
+		"property_ids":  propertyIDs,
+		"count":         len(propertyIDs),
+	}, http.StatusOK)
+}
+
+// GetAuditLog handles GET /api/agencies/{id}/audit?limit=50&offset=0
+func (h *PropertyAssignmentHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	entries, total, err := h.assignmentService.GetAgencyAuditLog(agencyID, limit, offset, actor(r))
+	if err != nil {
+		h.sendAssignmentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"agency_id": agencyID,
+		"entries":   entries,
+		"total":     total,
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *PropertyAssignmentHandler) sendAssignmentError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Property assignment error: %v", err)
+		http.Error(w, "Failed to update assignment", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyAssignmentHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// PropertyDocumentHandler handles deeds, floor plans and contracts attached to properties
+type PropertyDocumentHandler struct {
+	documentService *service.PropertyDocumentService
+	logger          *log.Logger
+}
+
+// NewPropertyDocumentHandler creates a new property document handler
+func NewPropertyDocumentHandler(documentService *service.PropertyDocumentService, logger *log.Logger) *PropertyDocumentHandler {
+	return &PropertyDocumentHandler{
+		documentService: documentService,
+		logger:          logger,
+	}
+}
+
+// UploadDocument handles POST /api/properties/{id}/documents
+// (multipart: document, type, visibility, title)
+func (h *PropertyDocumentHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		http.Error(w, "document file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	actor := actor(r)
+
+	document, err := h.documentService.Upload(service.UploadDocumentRequest{
+		PropertyID:  propertyID,
+		Type:        r.FormValue("type"),
+		Visibility:  r.FormValue("visibility"),
+		Title:       r.FormValue("title"),
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	}, file, actor)
+	if err != nil {
+		h.sendDocumentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, document, http.StatusCreated)
+}
+
+// GetDocumentsByProperty handles GET /api/properties/{id}/documents. Private documents
+// are only listed to whoever manages the listing.
+func (h *PropertyDocumentHandler) GetDocumentsByProperty(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	documents, err := h.documentService.ListDocuments(propertyID, actor)
+	if err != nil {
+		h.sendDocumentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"documents":   documents,
+		"count":       len(documents),
+	}, http.StatusOK)
+}
+
+// DownloadDocument handles GET /api/documents/{id}/download and serves the file,
+// including private documents to whoever manages the listing
+func (h *PropertyDocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	documentID := pathSegment(r.URL.Path, 2)
+	if documentID == "" {
+		http.Error(w, "Document ID required", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	document, data, err := h.documentService.GetDocument(documentID, actor)
+	if err != nil {
+		h.sendDocumentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if !document.IsPublic() {
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// DeleteDocument handles DELETE /api/documents/{id}
+func (h *PropertyDocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	documentID := pathSegment(r.URL.Path, 2)
+	if documentID == "" {
+		http.Error(w, "Document ID required", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	if err := h.documentService.DeleteDocument(documentID, actor); err != nil {
+		h.sendDocumentError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]string{"message": "Document deleted successfully"}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *PropertyDocumentHandler) sendDocumentError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "too large"):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case strings.Contains(err.Error(), "infected"):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case strings.Contains(err.Error(), "validation failed"), strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "exceeded"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Property document error: %v", err)
+		http.Error(w, "Failed to process document", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyDocumentHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
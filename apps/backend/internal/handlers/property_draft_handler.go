@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// PropertyDraftHandler autosaves and publishes drafts of the listing creation wizard
+type PropertyDraftHandler struct {
+	draftService *service.PropertyDraftService
+	logger       *log.Logger
+}
+
+// NewPropertyDraftHandler creates a new property draft handler
+func NewPropertyDraftHandler(draftService *service.PropertyDraftService, logger *log.Logger) *PropertyDraftHandler {
+	return &PropertyDraftHandler{
+		draftService: draftService,
+		logger:       logger,
+	}
+}
+
+// SaveDraft handles PUT /api/properties/drafts/{draftId}
+// The body is the partial creation request of the wizard; the client generates the
+// draft UUID so every step can autosave to the same draft.
+func (h *PropertyDraftHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, domain.MaxPropertyDraftBytes))
+	if err != nil {
+		http.Error(w, "invalid draft: payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	draft, err := h.draftService.SaveDraft(pathSegment(r.URL.Path, 3), payload, actor(r))
+	if err != nil {
+		h.sendDraftError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, draft, http.StatusOK)
+}
+
+// ListDrafts handles GET /api/properties/drafts
+func (h *PropertyDraftHandler) ListDrafts(w http.ResponseWriter, r *http.Request) {
+	drafts, err := h.draftService.ListDrafts(actor(r))
+	if err != nil {
+		h.sendDraftError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"drafts": drafts,
+		"count":  len(drafts),
+	}, http.StatusOK)
+}
+
+// GetDraft handles GET /api/properties/drafts/{draftId}
+func (h *PropertyDraftHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	draft, err := h.draftService.GetDraft(pathSegment(r.URL.Path, 3), actor(r))
+	if err != nil {
+		h.sendDraftError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, draft, http.StatusOK)
+}
+
+// DeleteDraft handles DELETE /api/properties/drafts/{draftId}
+func (h *PropertyDraftHandler) DeleteDraft(w http.ResponseWriter, r *http.Request) {
+	if err := h.draftService.DeleteDraft(pathSegment(r.URL.Path, 3), actor(r)); err != nil {
+		h.sendDraftError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PublishDraft handles POST /api/properties/drafts/{draftId}/publish
+// The draft goes through the same validations as POST /api/properties and is deleted
+// once the property is created.
+func (h *PropertyDraftHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	property, err := h.draftService.PublishDraft(pathSegment(r.URL.Path, 3), middleware.GetTenantID(r.Context()), actor(r))
+	if err != nil {
+		h.sendDraftError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, property, http.StatusCreated)
+}
+
+// Helper functions
+
+func (h *PropertyDraftHandler) sendDraftError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "plan limit"):
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Property draft error: %v", err)
+		http.Error(w, "Failed to process property draft", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyDraftHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
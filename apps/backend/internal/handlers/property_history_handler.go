@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// PropertyHistoryHandler serves the version history of properties and reverts them
+type PropertyHistoryHandler struct {
+	historyService *service.PropertyHistoryService
+	logger         *log.Logger
+}
+
+// NewPropertyHistoryHandler creates a new property history handler
+func NewPropertyHistoryHandler(historyService *service.PropertyHistoryService, logger *log.Logger) *PropertyHistoryHandler {
+	return &PropertyHistoryHandler{
+		historyService: historyService,
+		logger:         logger,
+	}
+}
+
+// GetHistory handles GET /api/properties/{id}/history?limit=50
+// Versions are newest first, each with the full snapshot and the fields it changed.
+func (h *PropertyHistoryHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	propertyID := pathSegment(r.URL.Path, 2)
+
+	versions, err := h.historyService.ListHistory(propertyID, limit, actor(r))
+	if err != nil {
+		h.sendHistoryError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"versions":    versions,
+		"count":       len(versions),
+	}, http.StatusOK)
+}
+
+// Revert handles POST /api/properties/{id}/revert/{version}
+func (h *PropertyHistoryHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(pathSegment(r.URL.Path, 4))
+	if err != nil {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return
+	}
+
+	property, err := h.historyService.Revert(pathSegment(r.URL.Path, 2), version, actor(r))
+	if err != nil {
+		h.sendHistoryError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, property, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *PropertyHistoryHandler) sendHistoryError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Property history error: %v", err)
+		http.Error(w, "Failed to process property history", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyHistoryHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// PropertyShareHandler records properties being shared and serves the admin conversion
+// funnel
+type PropertyShareHandler struct {
+	shareService *service.PropertyShareService
+	logger       *log.Logger
+}
+
+// NewPropertyShareHandler creates a new property share handler
+func NewPropertyShareHandler(shareService *service.PropertyShareService, logger *log.Logger) *PropertyShareHandler {
+	return &PropertyShareHandler{
+		shareService: shareService,
+		logger:       logger,
+	}
+}
+
+type recordShareRequest struct {
+	Channel   string `json:"channel"`
+	SessionID string `json:"session_id"`
+}
+
+// RecordShare handles POST /api/properties/{id}/share ({"channel": "whatsapp", "session_id": "..."})
+// Anyone can share; anonymous visitors send the ID of their browser session, signed-in
+// users are attributed by their token. Repeated shares within an hour count once and
+// answer 200 instead of 201.
+func (h *PropertyShareHandler) RecordShare(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req recordShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.SessionID == "" {
+		req.SessionID = middleware.GetSessionID(r.Context())
+	}
+
+	share, recorded, err := h.shareService.RecordShare(propertyID, req.Channel, req.SessionID, actor(r))
+	if err != nil {
+		h.sendShareError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if recorded {
+		status = http.StatusCreated
+	}
+	h.sendJSONResponse(w, map[string]interface{}{
+		"share":    share,
+		"recorded": recorded,
+	}, status)
+}
+
+// GetConversionFunnel handles GET /api/admin/analytics/funnel?from=2025-09-01&to=2025-10-01
+// Shares by channel, visits through short links, inquiries and scheduled visits in the
+// period, the last 30 days by default.
+func (h *PropertyShareHandler) GetConversionFunnel(w http.ResponseWriter, r *http.Request) {
+	from, err := parseDateParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	funnel, err := h.shareService.GetConversionFunnel(from, to, actor(r))
+	if err != nil {
+		h.sendShareError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, funnel, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *PropertyShareHandler) sendShareError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Property share error: %v", err)
+		http.Error(w, "Failed to process property shares", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyShareHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// PropertyStatusHandler handles listing status transitions
+type PropertyStatusHandler struct {
+	statusService *service.PropertyStatusService
+	logger        *log.Logger
+}
+
+// NewPropertyStatusHandler creates a new property status handler
+func NewPropertyStatusHandler(statusService *service.PropertyStatusService, logger *log.Logger) *PropertyStatusHandler {
+	return &PropertyStatusHandler{
+		statusService: statusService,
+		logger:        logger,
+	}
+}
+
+// ChangeStatus handles POST /api/properties/{id}/status, e.g.
+// {"status":"sold","final_price":235000,"closing_date":"2025-08-29T00:00:00Z"}
+// Invalid transitions, such as reserving a sold property, return 409.
+func (h *PropertyStatusHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req service.ChangeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.statusService.ChangeStatus(propertyID, req, actor(r))
+	if err != nil {
+		h.sendStatusError(w, propertyID, err)
+		return
+	}
+
+	h.sendJSONResponse(w, change, http.StatusOK)
+}
+
+// GetStatusHistory handles GET /api/properties/{id}/status/history
+func (h *PropertyStatusHandler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.statusService.GetStatusHistory(propertyID, actor(r))
+	if err != nil {
+		h.sendStatusError(w, propertyID, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"changes": changes,
+		"count":   len(changes),
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *PropertyStatusHandler) sendStatusError(w http.ResponseWriter, propertyID string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "status transition"), strings.Contains(err.Error(), "already sold"),
+		strings.Contains(err.Error(), "not available"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "required"),
+		strings.Contains(err.Error(), "cannot exceed"), strings.Contains(err.Error(), "must be"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Error changing status of property %s: %v", propertyID, err)
+		http.Error(w, "Failed to change property status", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyStatusHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -2,22 +2,27 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
 	"realty-core/internal/repository"
+	"realty-core/internal/service"
 )
 
 // MockPropertyService is a mock implementation of PropertyServiceInterface
 type MockPropertyService struct {
 	mock.Mock
+	tenants map[string]*MockPropertyService // services scoped to tenants, itself by default
 }
 
 func (m *MockPropertyService) CreateProperty(title, description, province, city, propertyType string, price float64, parkingSpaces int) (*domain.Property, error) {
@@ -25,6 +30,11 @@ func (m *MockPropertyService) CreateProperty(title, description, province, city,
 	return args.Get(0).(*domain.Property), args.Error(1)
 }
 
+func (m *MockPropertyService) CreatePropertyComplete(req service.CreatePropertyFullRequest) (*domain.Property, error) {
+	args := m.Called(req)
+	return args.Get(0).(*domain.Property), args.Error(1)
+}
+
 func (m *MockPropertyService) GetProperty(id string) (*domain.Property, error) {
 	args := m.Called(id)
 	return args.Get(0).(*domain.Property), args.Error(1)
@@ -40,6 +50,11 @@ func (m *MockPropertyService) ListProperties() ([]domain.Property, error) {
 	return args.Get(0).([]domain.Property), args.Error(1)
 }
 
+func (m *MockPropertyService) UpdatePropertyBy(changedBy, id, title, description, province, city, propertyType string, price float64) (*domain.Property, error) {
+	args := m.Called(changedBy, id, title, description, province, city, propertyType, price)
+	return args.Get(0).(*domain.Property), args.Error(1)
+}
+
 func (m *MockPropertyService) UpdateProperty(id, title, description, province, city, propertyType string, price float64) (*domain.Property, error) {
 	args := m.Called(id, title, description, province, city, propertyType, price)
 	return args.Get(0).(*domain.Property), args.Error(1)
@@ -60,6 +75,22 @@ func (m *MockPropertyService) FilterByPriceRange(minPrice, maxPrice float64) ([]
 	return args.Get(0).([]domain.Property), args.Error(1)
 }
 
+func (m *MockPropertyService) FilterProperties(filters *domain.PropertySearchFilters) ([]domain.Property, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]domain.Property), args.Error(1)
+}
+
+func (m *MockPropertyService) StreamProperties(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error {
+	args := m.Called(filters)
+	properties := args.Get(0).([]domain.Property)
+	for i := range properties {
+		if err := fn(&properties[i]); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockPropertyService) GetStatistics() (map[string]interface{}, error) {
 	args := m.Called()
 	return args.Get(0).(map[string]interface{}), args.Error(1)
@@ -122,6 +153,19 @@ func (m *MockPropertyService) FilterByPriceRangePaginated(minPrice, maxPrice flo
 	return args.Get(0).(*domain.PaginatedResponse), args.Error(1)
 }
 
+func (m *MockPropertyService) FilterPropertiesPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).(*domain.PaginatedResponse), args.Error(1)
+}
+
+func (m *MockPropertyService) ListManagedProperties(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams, actor domain.Actor) (*domain.PaginatedResponse, error) {
+	args := m.Called(filters, pagination, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PaginatedResponse), args.Error(1)
+}
+
 func (m *MockPropertyService) SearchPropertiesPaginated(query string, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error) {
 	args := m.Called(query, pagination)
 	return args.Get(0).(*domain.PaginatedResponse), args.Error(1)
@@ -137,6 +181,21 @@ func (m *MockPropertyService) AdvancedSearchPaginated(params repository.Advanced
 	return args.Get(0).(*domain.PaginatedResponse), args.Error(1)
 }
 
+func (m *MockPropertyService) AdvancedSearchFaceted(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) (*domain.FacetedSearchResponse, error) {
+	args := m.Called(params, pagination)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FacetedSearchResponse), args.Error(1)
+}
+
+func (m *MockPropertyService) ForTenant(tenantID string) service.PropertyServiceInterface {
+	if scoped, ok := m.tenants[tenantID]; ok {
+		return scoped
+	}
+	return m
+}
+
 // Helper function to create a test property
 func createTestProperty() *domain.Property {
 	return domain.NewProperty(
@@ -181,8 +240,9 @@ func TestPropertyHandler_CreateProperty(t *testing.T) {
 			},
 			mockSetup: func(m *MockPropertyService) {
 				property := createTestProperty()
-				m.On("CreateProperty", "Beautiful house in Samborondón", "Modern house with pool", "Guayas", "Samborondón", "house", 285000.0, 0).
-					Return(property, nil)
+				m.On("CreatePropertyComplete", mock.MatchedBy(func(req service.CreatePropertyFullRequest) bool {
+					return req.Title == "Beautiful house in Samborondón" && req.Province == "Guayas" && req.Price == 285000
+				})).Return(property, nil)
 			},
 			expectedStatus: http.StatusCreated,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -226,8 +286,9 @@ func TestPropertyHandler_CreateProperty(t *testing.T) {
 				Price:       100000,
 			},
 			mockSetup: func(m *MockPropertyService) {
-				m.On("CreateProperty", "Invalid Property", "Description", "InvalidProvince", "City", "house", 100000.0, 0).
-					Return((*domain.Property)(nil), errors.New("invalid province: InvalidProvince"))
+				m.On("CreatePropertyComplete", mock.MatchedBy(func(req service.CreatePropertyFullRequest) bool {
+					return req.Province == "InvalidProvince"
+				})).Return((*domain.Property)(nil), errors.New("invalid province: InvalidProvince"))
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "invalid province: InvalidProvince",
@@ -600,7 +661,7 @@ func TestPropertyHandler_UpdateProperty(t *testing.T) {
 				property := createTestProperty()
 				property.Title = "Updated Beautiful house"
 				property.Price = 300000
-				m.On("UpdateProperty", "test-id", "Updated Beautiful house", "Updated description", "Guayas", "Samborondón", "house", 300000.0).
+				m.On("UpdatePropertyBy", "", "test-id", "Updated Beautiful house", "Updated description", "Guayas", "Samborondón", "house", 300000.0).
 					Return(property, nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -630,7 +691,7 @@ func TestPropertyHandler_UpdateProperty(t *testing.T) {
 			url:         "/api/properties/",
 			requestBody: CreatePropertyRequest{},
 			mockSetup: func(m *MockPropertyService) {
-				m.On("UpdateProperty", "properties", "", "", "", "", "", 0.0).
+				m.On("UpdatePropertyBy", "", "properties", "", "", "", "", "", 0.0).
 					Return((*domain.Property)(nil), errors.New("property not found"))
 			},
 			expectedStatus: http.StatusNotFound,
@@ -658,7 +719,7 @@ func TestPropertyHandler_UpdateProperty(t *testing.T) {
 				Price:       300000,
 			},
 			mockSetup: func(m *MockPropertyService) {
-				m.On("UpdateProperty", "nonexistent-id", "Updated title", "Updated description", "Guayas", "Samborondón", "house", 300000.0).
+				m.On("UpdatePropertyBy", "", "nonexistent-id", "Updated title", "Updated description", "Guayas", "Samborondón", "house", 300000.0).
 					Return((*domain.Property)(nil), errors.New("property not found"))
 			},
 			expectedStatus: http.StatusNotFound,
@@ -677,7 +738,7 @@ func TestPropertyHandler_UpdateProperty(t *testing.T) {
 				Price:       300000,
 			},
 			mockSetup: func(m *MockPropertyService) {
-				m.On("UpdateProperty", "test-id", "", "Updated description", "Guayas", "Samborondón", "house", 300000.0).
+				m.On("UpdatePropertyBy", "", "test-id", "", "Updated description", "Guayas", "Samborondón", "house", 300000.0).
 					Return((*domain.Property)(nil), errors.New("title is required"))
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -840,14 +901,16 @@ func TestPropertyHandler_FilterProperties(t *testing.T) {
 			url:    "/api/properties/filter?q=beautiful",
 			mockSetup: func(m *MockPropertyService) {
 				properties := []domain.Property{*createTestProperty()}
-				m.On("SearchProperties", "beautiful").Return(properties, nil)
+				m.On("FilterProperties", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+					return f.Query == "beautiful"
+				})).Return(properties, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				var response SuccessResponse
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, "Properties filtered by search query", response.Message)
+				assert.Equal(t, "Properties filtered", response.Message)
 
 				properties, ok := response.Data.([]interface{})
 				assert.True(t, ok)
@@ -860,14 +923,16 @@ func TestPropertyHandler_FilterProperties(t *testing.T) {
 			url:    "/api/properties/filter?province=Guayas",
 			mockSetup: func(m *MockPropertyService) {
 				properties := []domain.Property{*createTestProperty()}
-				m.On("FilterByProvince", "Guayas").Return(properties, nil)
+				m.On("FilterProperties", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+					return len(f.Provinces) == 1 && f.Provinces[0] == "Guayas"
+				})).Return(properties, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				var response SuccessResponse
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, "Properties filtered by province", response.Message)
+				assert.Equal(t, "Properties filtered", response.Message)
 			},
 		},
 		{
@@ -876,14 +941,40 @@ func TestPropertyHandler_FilterProperties(t *testing.T) {
 			url:    "/api/properties/filter?min_price=100000&max_price=500000",
 			mockSetup: func(m *MockPropertyService) {
 				properties := []domain.Property{*createTestProperty()}
-				m.On("FilterByPriceRange", 100000.0, 500000.0).Return(properties, nil)
+				m.On("FilterProperties", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+					return *f.MinPrice == 100000.0 && *f.MaxPrice == 500000.0
+				})).Return(properties, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response SuccessResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Properties filtered", response.Message)
+			},
+		},
+		{
+			name:   "combined filters",
+			method: http.MethodGet,
+			url:    "/api/properties/filter?province=Pichincha&city=Quito,Cumbaya&type=apartment&min_bedrooms=2&max_bathrooms=3.5&min_area=80&has_pool=true&has_elevator=false&min_parking_spaces=1&status=available&featured=true",
+			mockSetup: func(m *MockPropertyService) {
+				properties := []domain.Property{*createTestProperty()}
+				m.On("FilterProperties", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+					return assert.ObjectsAreEqual([]string{"Pichincha"}, f.Provinces) &&
+						assert.ObjectsAreEqual([]string{"Quito", "Cumbaya"}, f.Cities) &&
+						assert.ObjectsAreEqual([]string{"apartment"}, f.PropertyTypes) &&
+						assert.ObjectsAreEqual([]string{"available"}, f.Status) &&
+						*f.MinBedrooms == 2 && *f.MaxBathrooms == 3.5 && *f.MinArea == 80 &&
+						*f.HasPool && !*f.HasElevator && f.HasGarden == nil &&
+						*f.MinParkingSpaces == 1 && *f.Featured
+				})).Return(properties, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				var response SuccessResponse
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, "Properties filtered by price range", response.Message)
+				assert.Equal(t, "Properties filtered", response.Message)
 			},
 		},
 		{
@@ -935,7 +1026,7 @@ func TestPropertyHandler_FilterProperties(t *testing.T) {
 			method: http.MethodGet,
 			url:    "/api/properties/filter?q=test",
 			mockSetup: func(m *MockPropertyService) {
-				m.On("SearchProperties", "test").Return([]domain.Property{}, errors.New("database error"))
+				m.On("FilterProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).Return([]domain.Property{}, errors.New("database error"))
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "database error",
@@ -945,7 +1036,7 @@ func TestPropertyHandler_FilterProperties(t *testing.T) {
 			method: http.MethodGet,
 			url:    "/api/properties/filter?province=InvalidProvince",
 			mockSetup: func(m *MockPropertyService) {
-				m.On("FilterByProvince", "InvalidProvince").Return([]domain.Property{}, errors.New("invalid province"))
+				m.On("FilterProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).Return([]domain.Property{}, errors.New("invalid province"))
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "invalid province",
@@ -955,11 +1046,27 @@ func TestPropertyHandler_FilterProperties(t *testing.T) {
 			method: http.MethodGet,
 			url:    "/api/properties/filter?min_price=500000&max_price=100000",
 			mockSetup: func(m *MockPropertyService) {
-				m.On("FilterByPriceRange", 500000.0, 100000.0).Return([]domain.Property{}, errors.New("minimum price cannot be greater"))
+				m.On("FilterProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).Return([]domain.Property{}, errors.New("minimum price cannot be greater"))
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "minimum price cannot be greater",
 		},
+		{
+			name:           "invalid bedrooms",
+			method:         http.MethodGet,
+			url:            "/api/properties/filter?min_bedrooms=two",
+			mockSetup:      func(m *MockPropertyService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid minimum bedrooms",
+		},
+		{
+			name:           "invalid amenity flag",
+			method:         http.MethodGet,
+			url:            "/api/properties/filter?has_pool=maybe",
+			mockSetup:      func(m *MockPropertyService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid has_pool parameter",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1541,4 +1648,106 @@ func TestPropertyHandler_SuccessResponse(t *testing.T) {
 	assert.Equal(t, "Properties retrieved successfully", successResp.Message)
 
 	mockService.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestPropertyHandler_ListsPageWhenAsked(t *testing.T) {
+	mockService := new(MockPropertyService)
+	page := &domain.PaginatedResponse{Data: []domain.Property{*createTestProperty()}, Pagination: &domain.Pagination{CurrentPage: 2, PageSize: 10}}
+	mockService.On("ListPropertiesPaginated", mock.MatchedBy(func(p *domain.PaginationParams) bool {
+		return p.Page == 2 && p.PageSize == 10
+	})).Return(page, nil)
+	mockService.On("FilterPropertiesPaginated", mock.AnythingOfType("*domain.PropertySearchFilters"), mock.MatchedBy(func(p *domain.PaginationParams) bool {
+		return p.Page == 3
+	})).Return(page, nil)
+
+	rec := httptest.NewRecorder()
+	NewPropertyHandler(mockService).ListProperties(rec, httptest.NewRequest(http.MethodGet, "/api/properties?page=2&page_size=10", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"current_page":2`)
+
+	rec = httptest.NewRecorder()
+	NewPropertyHandler(mockService).FilterProperties(rec, httptest.NewRequest(http.MethodGet, "/api/properties/filter?city=Quito&page=3", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "ListProperties")
+	mockService.AssertNotCalled(t, "FilterProperties", mock.Anything)
+}
+
+func TestPropertyHandler_StreamsNDJSON(t *testing.T) {
+	t.Run("streams one property per line", func(t *testing.T) {
+		mockService := new(MockPropertyService)
+		first, second := createTestProperty(), createTestProperty()
+		second.ID = "second"
+		mockService.On("StreamProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).
+			Return([]domain.Property{*first, *second}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/properties", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		NewPropertyHandler(mockService).ListProperties(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		var property domain.Property
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &property))
+		assert.Equal(t, "second", property.ID)
+		mockService.AssertNotCalled(t, "ListProperties")
+	})
+
+	t.Run("filter errors before the first row keep their status", func(t *testing.T) {
+		mockService := new(MockPropertyService)
+		mockService.On("StreamProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).
+			Return([]domain.Property{}, errors.New("search query must be at least 2 characters"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/properties/filter?q=a", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		NewPropertyHandler(mockService).FilterProperties(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("errors mid-stream end it with an error line", func(t *testing.T) {
+		mockService := new(MockPropertyService)
+		mockService.On("StreamProperties", mock.AnythingOfType("*domain.PropertySearchFilters")).
+			Return([]domain.Property{*createTestProperty()}, errors.New("connection reset"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/properties", nil)
+		req.Header.Set("Accept", "application/x-ndjson; charset=utf-8")
+		rec := httptest.NewRecorder()
+		NewPropertyHandler(mockService).ListProperties(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.JSONEq(t, `{"error":"stream interrupted"}`, lines[1])
+	})
+}
+
+func TestPropertyHandler_ScopesRequestsToTheirTenant(t *testing.T) {
+	acme := &MockPropertyService{}
+	acme.On("GetProperty", "other-tenant-id").Return((*domain.Property)(nil), errors.New("property not found: other-tenant-id"))
+	acme.On("DeleteProperty", "other-tenant-id").Return(errors.New("property not found: other-tenant-id"))
+	mockService := &MockPropertyService{tenants: map[string]*MockPropertyService{"acme": acme}}
+	handler := NewPropertyHandler(mockService)
+
+	request := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/api/properties/other-tenant-id", nil)
+		return req.WithContext(context.WithValue(req.Context(), middleware.TenantIDKey, "acme"))
+	}
+
+	rec := httptest.NewRecorder()
+	handler.GetProperty(rec, request(http.MethodGet))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.DeleteProperty(rec, request(http.MethodDelete))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	acme.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetProperty", mock.Anything)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// PropertyTranslationHandler handles the translated titles and descriptions of properties
+type PropertyTranslationHandler struct {
+	translationService *service.PropertyTranslationService
+	logger             *log.Logger
+}
+
+// NewPropertyTranslationHandler creates a new property translation handler
+func NewPropertyTranslationHandler(translationService *service.PropertyTranslationService, logger *log.Logger) *PropertyTranslationHandler {
+	return &PropertyTranslationHandler{
+		translationService: translationService,
+		logger:             logger,
+	}
+}
+
+// GetTranslations handles GET /api/properties/{id}/translations
+func (h *PropertyTranslationHandler) GetTranslations(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	translations, err := h.translationService.GetTranslations(propertyID)
+	if err != nil {
+		h.sendTranslationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id":    propertyID,
+		"default_locale": domain.DefaultLocale,
+		"translations":   translations,
+	}, http.StatusOK)
+}
+
+// SetTranslation handles PUT /api/properties/{id}/translations/{locale}
+// ({"title": "...", "description": "..."})
+func (h *PropertyTranslationHandler) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	locale := pathSegment(r.URL.Path, 4)
+	if propertyID == "" || locale == "" {
+		http.Error(w, "Property ID and locale required", http.StatusBadRequest)
+		return
+	}
+
+	var req domain.PropertyTranslation
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	translation, err := h.translationService.SetTranslation(propertyID, locale, req.Title, req.Description, actor(r))
+	if err != nil {
+		h.sendTranslationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, translation, http.StatusOK)
+}
+
+// autoTranslateRequest optionally replaces a translation edited by hand
+type autoTranslateRequest struct {
+	Overwrite bool `json:"overwrite"`
+}
+
+// AutoTranslate handles POST /api/properties/{id}/translations/{locale}/auto
+// ({"overwrite": false}, optional). The translation is marked as machine-translated
+// until edited with SetTranslation.
+func (h *PropertyTranslationHandler) AutoTranslate(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	locale := pathSegment(r.URL.Path, 4)
+	if propertyID == "" || locale == "" {
+		http.Error(w, "Property ID and locale required", http.StatusBadRequest)
+		return
+	}
+
+	var req autoTranslateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	translation, err := h.translationService.AutoTranslate(propertyID, locale, req.Overwrite, actor(r))
+	if err != nil {
+		h.sendTranslationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, translation, http.StatusOK)
+}
+
+// DeleteTranslation handles DELETE /api/properties/{id}/translations/{locale}
+func (h *PropertyTranslationHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	locale := pathSegment(r.URL.Path, 4)
+	if propertyID == "" || locale == "" {
+		http.Error(w, "Property ID and locale required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.translationService.DeleteTranslation(propertyID, locale, actor(r)); err != nil {
+		h.sendTranslationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Helper functions
+
+func (h *PropertyTranslationHandler) sendTranslationError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "unavailable"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case strings.Contains(err.Error(), "already edited"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Property translation error: %v", err)
+		http.Error(w, "Failed to process translation", http.StatusInternalServerError)
+	}
+}
+
+func (h *PropertyTranslationHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
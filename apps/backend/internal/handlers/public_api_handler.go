@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// PublicAPIHandler serves the public read-only API under /api/public/v1: property
+// search, property detail and agency listing for partners and researchers. Requests
+// are authenticated by APIKeyMiddleware; responses carry the public views of listings
+// and agencies, without the contacts of the people behind them.
+type PublicAPIHandler struct {
+	properties  service.PropertyServiceInterface
+	agencies    *service.AgencyService
+	honeytokens *service.HoneytokenService
+	search      *PropertyHandler
+	logger      *log.Logger
+}
+
+// NewPublicAPIHandler creates a new public API handler
+func NewPublicAPIHandler(properties service.PropertyServiceInterface, agencies *service.AgencyService, logger *log.Logger) *PublicAPIHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &PublicAPIHandler{
+		properties: properties,
+		agencies:   agencies,
+		search:     NewPropertyHandler(properties),
+		logger:     logger,
+	}
+}
+
+// tenantProperties and tenantAgencies return the services scoped to the tenant of the
+// request
+func (h *PublicAPIHandler) tenantProperties(r *http.Request) service.PropertyServiceInterface {
+	return h.properties.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
+func (h *PublicAPIHandler) tenantAgencies(r *http.Request) *service.AgencyService {
+	return h.agencies.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
+// SetHoneytokens plants the public API canaries in the first page of the searches
+// their filters fit
+func (h *PublicAPIHandler) SetHoneytokens(honeytokens *service.HoneytokenService) {
+	h.honeytokens = honeytokens
+}
+
+// SearchProperties handles GET /api/public/v1/properties
+// It takes the filters of /api/properties/filter plus ?agency_id=, and page, page_size
+// (up to 100), sort_by and sort_desc. Without ?status= every listed status is returned.
+// With Accept: application/x-ndjson every match is exported instead, one per line.
+func (h *PublicAPIHandler) SearchProperties(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		return
+	}
+
+	filters, err := h.search.parseFilterParams(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_FILTER", err.Error())
+		return
+	}
+
+	// Expired and quarantined listings are hidden by the status filter, which is always
+	// set because filtering by agency would otherwise show them
+	for _, status := range filters.Status {
+		if !domain.IsValidListingStatus(status) {
+			h.sendError(w, http.StatusBadRequest, "INVALID_FILTER", fmt.Sprintf("invalid status: %s", status))
+			return
+		}
+	}
+	if len(filters.Status) == 0 {
+		filters.Status = []string{domain.StatusAvailable, domain.StatusSold, domain.StatusRented, domain.StatusReserved}
+	}
+	if agencyID := strings.TrimSpace(r.URL.Query().Get("agency_id")); agencyID != "" {
+		filters.AgencyID = &agencyID
+	}
+
+	if wantsNDJSON(r) {
+		h.exportProperties(w, r, filters)
+		return
+	}
+
+	pagination, err := h.search.parsePaginationParams(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_PAGINATION", err.Error())
+		return
+	}
+
+	result, err := h.tenantProperties(r).FilterPropertiesPaginated(filters, pagination)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			h.sendError(w, http.StatusBadRequest, "INVALID_FILTER", err.Error())
+			return
+		}
+		h.logger.Printf("Public API property search failed: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to search properties")
+		return
+	}
+
+	properties, _ := result.Data.([]domain.Property)
+	if h.honeytokens != nil && pagination.Page <= 1 {
+		properties = h.honeytokens.Plant(domain.HoneytokenSourcePublicAPI, middleware.GetAPIKeyID(r.Context()),
+			filters, properties, pagination.PageSize)
+	}
+	public := make([]domain.PublicProperty, 0, len(properties))
+	for i := range properties {
+		public = append(public, domain.NewPublicProperty(&properties[i]))
+	}
+
+	h.sendJSON(w, http.StatusOK, domain.PaginatedResponse{Data: public, Pagination: result.Pagination})
+}
+
+// exportProperties streams every listing matching filters as NDJSON. The canaries of
+// the key are planted among the first rows, as in the first page of a search.
+func (h *PublicAPIHandler) exportProperties(w http.ResponseWriter, r *http.Request, filters *domain.PropertySearchFilters) {
+	w.Header().Set("X-API-Version", "v1")
+	stream := newNDJSONStream(w)
+	write := func(properties []domain.Property) error {
+		for i := range properties {
+			if err := stream.Write(domain.NewPublicProperty(&properties[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var head []domain.Property // held back until the canaries are planted among them
+	planted := h.honeytokens == nil
+	err := h.tenantProperties(r).StreamProperties(filters, func(property *domain.Property) error {
+		if planted {
+			return stream.Write(domain.NewPublicProperty(property))
+		}
+		head = append(head, *property)
+		if len(head) < domain.HoneytokenPlantPosition {
+			return nil
+		}
+		planted = true
+		return write(h.honeytokens.Plant(domain.HoneytokenSourcePublicAPI, middleware.GetAPIKeyID(r.Context()),
+			filters, head, 0))
+	})
+	if err == nil && !planted {
+		err = write(head)
+	}
+	if err != nil && !stream.Started() {
+		if strings.Contains(err.Error(), "invalid") {
+			h.sendError(w, http.StatusBadRequest, "INVALID_FILTER", err.Error())
+			return
+		}
+		h.logger.Printf("Public API property export failed: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to export properties")
+		return
+	}
+	stream.Finish(err)
+}
+
+// GetProperty handles GET /api/public/v1/properties/{id}
+func (h *PublicAPIHandler) GetProperty(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), "/api/public/v1/properties/")
+	if id == "" || strings.Contains(id, "/") {
+		h.sendError(w, http.StatusBadRequest, "INVALID_ID", "Property ID required")
+		return
+	}
+
+	property, err := h.tenantProperties(r).GetProperty(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Property not found")
+			return
+		}
+		h.logger.Printf("Public API property %s failed: %v", id, err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get property")
+		return
+	}
+	if !domain.IsValidListingStatus(property.Status) {
+		h.sendError(w, http.StatusNotFound, "NOT_FOUND", "Property not found")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{"data": domain.NewPublicProperty(property)})
+}
+
+// ListAgencies handles GET /api/public/v1/agencies
+// Lists active agencies, searched by ?q= and filtered by the ?province= they serve, with
+// page and page_size.
+func (h *PublicAPIHandler) ListAgencies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		return
+	}
+
+	pagination, err := h.search.parsePaginationParams(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_PAGINATION", err.Error())
+		return
+	}
+	if err := pagination.Validate(); err != nil {
+		h.sendError(w, http.StatusBadRequest, "INVALID_PAGINATION", err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	active := true
+	params := &domain.AgencySearchParams{
+		Query:      strings.TrimSpace(query.Get("q")),
+		Active:     &active,
+		Pagination: pagination,
+	}
+	if province := strings.TrimSpace(query.Get("province")); province != "" {
+		params.ServiceAreas = []string{province}
+	}
+
+	agencies, meta, err := h.tenantAgencies(r).SearchAgencies(params)
+	if err != nil {
+		h.logger.Printf("Public API agency listing failed: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list agencies")
+		return
+	}
+
+	public := make([]domain.PublicAgency, 0, len(agencies))
+	for _, agency := range agencies {
+		public = append(public, domain.NewPublicAgency(agency))
+	}
+
+	h.sendJSON(w, http.StatusOK, domain.PaginatedResponse{Data: public, Pagination: meta})
+}
+
+// Helper functions
+
+func (h *PublicAPIHandler) sendError(w http.ResponseWriter, statusCode int, code, message string) {
+	h.sendJSON(w, statusCode, map[string]interface{}{
+		"error":   http.StatusText(statusCode),
+		"message": message,
+		"code":    code,
+	})
+}
+
+func (h *PublicAPIHandler) sendJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-API-Version", "v1")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Printf("Error encoding public API response: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestPublicAPIHandler_SearchProperties(t *testing.T) {
+	owner, agency := "owner-1", "agency-1"
+	mockService := new(MockPropertyService)
+	mockService.On("FilterPropertiesPaginated", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+		return len(f.Status) == 4 && f.AgencyID != nil && *f.AgencyID == agency && len(f.Provinces) == 1
+	}), mock.Anything).Return(&domain.PaginatedResponse{
+		Data:       []domain.Property{{ID: "prop-1", Title: "Casa", Status: domain.StatusAvailable, OwnerID: &owner, AgencyID: &agency}},
+		Pagination: domain.NewPagination(1, 20, 1),
+	}, nil)
+	handler := NewPublicAPIHandler(mockService, nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.SearchProperties(rec, httptest.NewRequest(http.MethodGet, "/api/public/v1/properties?province=Guayas&agency_id=agency-1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "v1", rec.Header().Get("X-API-Version"))
+	assert.NotContains(t, rec.Body.String(), "owner")
+
+	var response struct {
+		Data       []domain.PublicProperty `json:"data"`
+		Pagination domain.Pagination       `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "prop-1", response.Data[0].ID)
+	assert.Equal(t, 1, response.Pagination.TotalRecords)
+	mockService.AssertExpectations(t)
+
+	// Hidden statuses cannot be requested
+	rec = httptest.NewRecorder()
+	handler.SearchProperties(rec, httptest.NewRequest(http.MethodGet, "/api/public/v1/properties?status=quarantined", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPublicAPIHandler_ExportProperties(t *testing.T) {
+	owner := "owner-1"
+	mockService := new(MockPropertyService)
+	mockService.On("StreamProperties", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+		return len(f.Status) == 4
+	})).Return([]domain.Property{
+		{ID: "prop-1", Status: domain.StatusAvailable, OwnerID: &owner},
+		{ID: "prop-2", Status: domain.StatusSold},
+	}, nil)
+	handler := NewPublicAPIHandler(mockService, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/public/v1/properties?page=3", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	handler.SearchProperties(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "v1", rec.Header().Get("X-API-Version"))
+	assert.NotContains(t, rec.Body.String(), owner)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2, "exports ignore pagination")
+	var property domain.PublicProperty
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &property))
+	assert.Equal(t, "prop-2", property.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestPublicAPIHandler_GetProperty(t *testing.T) {
+	owner := "owner-1"
+	mockService := new(MockPropertyService)
+	mockService.On("GetProperty", "prop-1").Return(&domain.Property{ID: "prop-1", Status: domain.StatusAvailable, OwnerID: &owner}, nil)
+	mockService.On("GetProperty", "prop-2").Return(&domain.Property{ID: "prop-2", Status: domain.StatusQuarantined}, nil)
+	mockService.On("GetProperty", "prop-3").Return((*domain.Property)(nil), errors.New("property not found"))
+	handler := NewPublicAPIHandler(mockService, nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.GetProperty(rec, httptest.NewRequest(http.MethodGet, "/api/public/v1/properties/prop-1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"prop-1"`)
+	assert.NotContains(t, rec.Body.String(), owner)
+
+	for _, id := range []string{"prop-2", "prop-3"} {
+		rec = httptest.NewRecorder()
+		handler.GetProperty(rec, httptest.NewRequest(http.MethodGet, "/api/public/v1/properties/"+id, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, id)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.GetProperty(rec, httptest.NewRequest(http.MethodPost, "/api/public/v1/properties/prop-1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
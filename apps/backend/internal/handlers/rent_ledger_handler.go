@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// RentGatewaySignatureHeader carries the signature of a gateway confirmation as t=<unix>,v1=<hex>
+const RentGatewaySignatureHeader = "X-Gateway-Signature"
+
+// maxGatewayConfirmationSize bounds the body of a gateway confirmation
+const maxGatewayConfirmationSize = 64 << 10
+
+// RentLedgerHandler serves the rent ledgers of leases
+type RentLedgerHandler struct {
+	ledgerService *service.RentLedgerService
+	logger        *log.Logger
+}
+
+// NewRentLedgerHandler creates a new rent ledger handler
+func NewRentLedgerHandler(ledgerService *service.RentLedgerService, logger *log.Logger) *RentLedgerHandler {
+	return &RentLedgerHandler{
+		ledgerService: ledgerService,
+		logger:        logger,
+	}
+}
+
+// ScheduleCharges handles POST /api/leases/{dealId}/charges
+// ({"first_due_date": "2025-10-05", "months": 12, "amount": 800}); the amount defaults
+// to the rent of the deal
+func (h *RentLedgerHandler) ScheduleCharges(w http.ResponseWriter, r *http.Request) {
+	dealID := pathSegment(r.URL.Path, 2)
+	if dealID == "" {
+		http.Error(w, "Lease ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		FirstDueDate string  `json:"first_due_date"`
+		Months       int     `json:"months"`
+		Amount       float64 `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	firstDue, err := domain.ParseCalendarDate(req.FirstDueDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ledger, err := h.ledgerService.ScheduleCharges(dealID, service.ScheduleRentRequest{
+		FirstDueDate: firstDue,
+		Months:       req.Months,
+		Amount:       req.Amount,
+	}, actor(r))
+	if err != nil {
+		h.sendLedgerError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, ledger, http.StatusCreated)
+}
+
+// GetLedger handles GET /api/leases/{dealId}/ledger
+func (h *RentLedgerHandler) GetLedger(w http.ResponseWriter, r *http.Request) {
+	dealID := pathSegment(r.URL.Path, 2)
+	if dealID == "" {
+		http.Error(w, "Lease ID required", http.StatusBadRequest)
+		return
+	}
+
+	ledger, err := h.ledgerService.GetLedger(dealID, actor(r))
+	if err != nil {
+		h.sendLedgerError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, ledger, http.StatusOK)
+}
+
+// RecordPayment handles POST /api/rent-charges/{id}/payments
+// ({"amount": 800, "reference": "Transferencia 123", "paid_at": "2025-10-03"}); paid_at defaults to now
+func (h *RentLedgerHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	chargeID := pathSegment(r.URL.Path, 2)
+	if chargeID == "" {
+		http.Error(w, "Charge ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Amount    float64 `json:"amount"`
+		Reference string  `json:"reference"`
+		PaidAt    string  `json:"paid_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	var paidAt time.Time
+	if req.PaidAt != "" {
+		date, err := domain.ParseCalendarDate(req.PaidAt)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		paidAt = date
+	}
+
+	charge, err := h.ledgerService.RecordPayment(chargeID, service.RecordRentPaymentRequest{
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		PaidAt:    paidAt,
+	}, actor(r))
+	if err != nil {
+		h.sendLedgerError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, charge, http.StatusOK)
+}
+
+// DownloadReceipt handles GET /api/rent-charges/{id}/receipt
+func (h *RentLedgerHandler) DownloadReceipt(w http.ResponseWriter, r *http.Request) {
+	chargeID := pathSegment(r.URL.Path, 2)
+	if chargeID == "" {
+		http.Error(w, "Charge ID required", http.StatusBadRequest)
+		return
+	}
+
+	charge, pdf, err := h.ledgerService.Receipt(chargeID, actor(r))
+	if err != nil {
+		h.sendLedgerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", charge.ReceiptNumber()+".pdf"))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// ConfirmGatewayPayment handles POST /api/rent-payments/gateway, called by the payment
+// gateway when a rent payment clears. The body ({"charge_id": "...", "amount": 800,
+// "reference": "...", "paid_at": "2025-10-03T14:00:00Z"}) is signed in the
+// X-Gateway-Signature header like webhook deliveries.
+func (h *RentLedgerHandler) ConfirmGatewayPayment(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxGatewayConfirmationSize))
+	if err != nil {
+		http.Error(w, "Failed to read confirmation", http.StatusBadRequest)
+		return
+	}
+
+	charge, err := h.ledgerService.ConfirmGatewayPayment(body, r.Header.Get(RentGatewaySignatureHeader))
+	if err != nil {
+		h.sendLedgerError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, charge, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *RentLedgerHandler) sendLedgerError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not configured"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case strings.Contains(err.Error(), "already"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Rent ledger error: %v", err)
+		http.Error(w, "Failed to process rent ledger", http.StatusInternalServerError)
+	}
+}
+
+func (h *RentLedgerHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
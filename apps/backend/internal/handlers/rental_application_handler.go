@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// RentalApplicationHandler handles rental applications and their review by landlords
+type RentalApplicationHandler struct {
+	applicationService *service.RentalApplicationService
+	logger             *log.Logger
+}
+
+// NewRentalApplicationHandler creates a new rental application handler
+func NewRentalApplicationHandler(applicationService *service.RentalApplicationService, logger *log.Logger) *RentalApplicationHandler {
+	return &RentalApplicationHandler{
+		applicationService: applicationService,
+		logger:             logger,
+	}
+}
+
+// SubmitApplication handles POST /api/rental-applications
+// Served behind the CAPTCHA middleware's inquiry endpoint.
+func (h *RentalApplicationHandler) SubmitApplication(w http.ResponseWriter, r *http.Request) {
+	var req service.SubmitApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	application, err := h.applicationService.Submit(req, actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, application, http.StatusCreated)
+}
+
+// GetApplication handles GET /api/rental-applications/{id}
+func (h *RentalApplicationHandler) GetApplication(w http.ResponseWriter, r *http.Request) {
+	applicationID := pathSegment(r.URL.Path, 2)
+	if applicationID == "" {
+		http.Error(w, "Application ID required", http.StatusBadRequest)
+		return
+	}
+
+	application, err := h.applicationService.GetApplication(applicationID, actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, application, http.StatusOK)
+}
+
+// ListMyApplications handles GET /api/rental-applications and lists the applications
+// submitted by the current user
+func (h *RentalApplicationHandler) ListMyApplications(w http.ResponseWriter, r *http.Request) {
+	applications, err := h.applicationService.ListMyApplications(actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"applications": applications,
+		"count":        len(applications),
+	}, http.StatusOK)
+}
+
+// ListPropertyApplications handles GET /api/properties/{id}/applications?status=submitted
+func (h *RentalApplicationHandler) ListPropertyApplications(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	applications, err := h.applicationService.ListPropertyApplications(propertyID, r.URL.Query().Get("status"), actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id":  propertyID,
+		"applications": applications,
+		"count":        len(applications),
+	}, http.StatusOK)
+}
+
+// UpdateApplicationStatus handles PUT /api/rental-applications/{id}/status
+// ({"status": "rejected", "reason": "..."})
+func (h *RentalApplicationHandler) UpdateApplicationStatus(w http.ResponseWriter, r *http.Request) {
+	applicationID := pathSegment(r.URL.Path, 2)
+	if applicationID == "" {
+		http.Error(w, "Application ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	application, err := h.applicationService.UpdateStatus(applicationID, req.Status, req.Reason, actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, application, http.StatusOK)
+}
+
+// UploadDocument handles POST /api/rental-applications/{id}/documents (multipart: document, name)
+func (h *RentalApplicationHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	applicationID := pathSegment(r.URL.Path, 2)
+	if applicationID == "" {
+		http.Error(w, "Application ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		http.Error(w, "document file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	document, err := h.applicationService.AddDocument(applicationID, r.FormValue("name"), header.Filename,
+		header.Header.Get("Content-Type"), header.Size, file, actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, document, http.StatusCreated)
+}
+
+// DownloadDocument handles GET /api/rental-applications/{id}/documents/{documentId}
+func (h *RentalApplicationHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	applicationID := pathSegment(r.URL.Path, 2)
+	documentID := pathSegment(r.URL.Path, 4)
+	if applicationID == "" || documentID == "" {
+		http.Error(w, "Application and document ID required", http.StatusBadRequest)
+		return
+	}
+
+	document, data, err := h.applicationService.GetDocument(applicationID, documentID, actor(r))
+	if err != nil {
+		h.sendApplicationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Helper functions
+
+func (h *RentalApplicationHandler) sendApplicationError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already submitted"), strings.Contains(err.Error(), "no longer"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "too large"):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case strings.Contains(err.Error(), "infected"):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case strings.Contains(err.Error(), "validation failed"), strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "exceeded"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Rental application error: %v", err)
+		http.Error(w, "Failed to process rental application", http.StatusInternalServerError)
+	}
+}
+
+func (h *RentalApplicationHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+)
+
+// actor builds the acting user from the authenticated request
+func actor(r *http.Request) domain.Actor {
+	ctx := r.Context()
+	return domain.NewActor(middleware.GetUserID(ctx), middleware.GetUserRole(ctx), middleware.GetAgencyID(ctx))
+}
+
+// pathSegment returns the index-th segment after /api/, e.g. 2 is {id} in /api/agencies/{id}
+// and 3 is {id} in /api/admin/tenants/{id}
+func pathSegment(path string, index int) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if index < len(parts) {
+		return parts[index]
+	}
+	return ""
+}
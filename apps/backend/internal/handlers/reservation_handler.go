@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ReservationHandler handles the reservation of listings for leads
+type ReservationHandler struct {
+	reservationService *service.ReservationService
+	logger             *log.Logger
+}
+
+// NewReservationHandler creates a new reservation handler
+func NewReservationHandler(reservationService *service.ReservationService, logger *log.Logger) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+		logger:             logger,
+	}
+}
+
+// Reserve handles POST /api/properties/{id}/reservations, e.g.
+// {"lead":{"name":"María Torres","phone":"0991234567"},"days":7}
+// The listing stays reserved until it is sold or rented through POST
+// /api/properties/{id}/status, made available there, or the reservation expires.
+func (h *ReservationHandler) Reserve(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req service.ReserveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := h.reservationService.Reserve(propertyID, req, actor(r))
+	if err != nil {
+		h.sendReservationError(w, propertyID, err)
+		return
+	}
+
+	h.sendJSONResponse(w, reservation, http.StatusCreated)
+}
+
+// ListReservations handles GET /api/properties/{id}/reservations
+func (h *ReservationHandler) ListReservations(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	reservations, err := h.reservationService.ListReservations(propertyID, actor(r))
+	if err != nil {
+		h.sendReservationError(w, propertyID, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"reservations": reservations,
+		"count":        len(reservations),
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *ReservationHandler) sendReservationError(w http.ResponseWriter, propertyID string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "status transition"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Error with reservations of property %s: %v", propertyID, err)
+		http.Error(w, "Failed to process reservation", http.StatusInternalServerError)
+	}
+}
+
+func (h *ReservationHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// RetentionHandler exposes the pruning of old records to administrators
+type RetentionHandler struct {
+	retention *service.RetentionService
+	logger    *log.Logger
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retention *service.RetentionService, logger *log.Logger) *RetentionHandler {
+	return &RetentionHandler{
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Retention handles GET and POST /api/admin/maintenance/retention
+// GET is a dry run: it reports the retention of every target and how many records
+// pruning would delete now, with the last real run. POST prunes now (?dry_run=true
+// only reports).
+func (h *RetentionHandler) Retention(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	switch r.Method {
+	case http.MethodGet:
+		dryRun = true
+	case http.MethodPost:
+		dryRun, _ = strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.retention.Run(dryRun)
+	if report == nil {
+		if strings.Contains(err.Error(), "already running") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.Printf("Error running retention pruning: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		h.logger.Printf("Retention pruning incomplete: %v", err)
+	}
+
+	if r.Method == http.MethodGet {
+		h.sendJSONResponse(w, struct {
+			*domain.RetentionReport
+			LastRun *domain.RetentionReport `json:"last_run,omitempty"`
+		}{report, h.retention.LastReport()}, http.StatusOK)
+		return
+	}
+	h.sendJSONResponse(w, report, http.StatusOK)
+}
+
+func (h *RetentionHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
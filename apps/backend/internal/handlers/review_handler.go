@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ReviewHandler serves review deletion, moderation and abuse reports for every
+// review subject
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+	logger        *log.Logger
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviewService *service.ReviewService, logger *log.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+		logger:        logger,
+	}
+}
+
+// moderateReviewRequest approves or rejects a review
+type moderateReviewRequest struct {
+	Action string `json:"action"` // approve or reject
+	Note   string `json:"note,omitempty"`
+}
+
+// reportReviewRequest reports an abusive review
+type reportReviewRequest struct {
+	Reason  string `json:"reason"` // spam, offensive, fake, personal_information or other
+	Details string `json:"details,omitempty"`
+}
+
+// resolveReportRequest upholds or dismisses the reports of a review
+type resolveReportRequest struct {
+	Action string `json:"action"` // uphold or dismiss
+	Note   string `json:"note,omitempty"`
+}
+
+// DeleteReview handles DELETE /api/reviews/{id}
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	if err := h.reviewService.DeleteReview(pathSegment(r.URL.Path, 2), actor(r)); err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReportReview handles POST /api/reviews/{id}/report
+func (h *ReviewHandler) ReportReview(w http.ResponseWriter, r *http.Request) {
+	var req reportReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reviewService.ReportReview(pathSegment(r.URL.Path, 2), req.Reason, req.Details, actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, report, http.StatusCreated)
+}
+
+// ListModerationQueue handles GET /api/admin/reviews?status=pending&limit=20&offset=0
+func (h *ReviewHandler) ListModerationQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	reviews, total, err := h.reviewService.ListModerationQueue(query.Get("status"), limit, offset, actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"reviews": reviews,
+		"total":   total,
+	}, http.StatusOK)
+}
+
+// ModerateReview handles POST /api/admin/reviews/{id}/moderate
+func (h *ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	var req moderateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Action != "approve" && req.Action != "reject" {
+		http.Error(w, "invalid action: must be approve or reject", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(pathSegment(r.URL.Path, 3), req.Action == "approve", req.Note, actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, review, http.StatusOK)
+}
+
+// ListReports handles GET /api/admin/review-reports?status=open&limit=20&offset=0
+func (h *ReviewHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	reports, total, err := h.reviewService.ListReports(query.Get("status"), limit, offset, actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"reports": reports,
+		"total":   total,
+	}, http.StatusOK)
+}
+
+// ResolveReport handles POST /api/admin/review-reports/{id}/resolve
+// Upholding rejects the review; either action closes every open report of the review.
+func (h *ReviewHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	var req resolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Action != "uphold" && req.Action != "dismiss" {
+		http.Error(w, "invalid action: must be uphold or dismiss", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewService.ResolveReport(pathSegment(r.URL.Path, 3), req.Action == "uphold", req.Note, actor(r))
+	if err != nil {
+		h.sendReviewError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, review, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *ReviewHandler) sendReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already reported"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Review error: %v", err)
+		http.Error(w, "Failed to process review", http.StatusInternalServerError)
+	}
+}
+
+func (h *ReviewHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// RiskZoneHandler serves hazard layers and the risk flags of properties
+type RiskZoneHandler struct {
+	riskZoneService *service.RiskZoneService
+	logger          *log.Logger
+}
+
+// NewRiskZoneHandler creates a new risk zone handler
+func NewRiskZoneHandler(riskZoneService *service.RiskZoneService, logger *log.Logger) *RiskZoneHandler {
+	return &RiskZoneHandler{
+		riskZoneService: riskZoneService,
+		logger:          logger,
+	}
+}
+
+// ListLayers handles GET /api/risk-layers
+func (h *RiskZoneHandler) ListLayers(w http.ResponseWriter, r *http.Request) {
+	layers, err := h.riskZoneService.ListLayers()
+	if err != nil {
+		h.sendRiskZoneError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"layers": layers,
+		"count":  len(layers),
+	}, http.StatusOK)
+}
+
+// ImportLayer handles PUT /api/risk-layers/{layer}?hazard=flood&default_level=high&source=SNGRE
+// (admin only). The body is a GeoJSON FeatureCollection of Polygon or MultiPolygon
+// features of at most 20MB; it replaces the layer and reassesses the properties around it.
+func (h *RiskZoneHandler) ImportLayer(w http.ResponseWriter, r *http.Request) {
+	name := pathSegment(r.URL.Path, 2)
+	if name == "" {
+		http.Error(w, "Layer name required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, domain.MaxRiskLayerSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read GeoJSON", http.StatusBadRequest)
+		return
+	}
+	if len(data) > domain.MaxRiskLayerSize {
+		http.Error(w, "GeoJSON exceeds 20MB", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	query := r.URL.Query()
+	layer := domain.RiskLayer{
+		Layer:        name,
+		Hazard:       query.Get("hazard"),
+		DefaultLevel: query.Get("default_level"),
+		Source:       query.Get("source"),
+	}
+
+	result, err := h.riskZoneService.ImportLayer(layer, data, actor(r))
+	if err != nil {
+		h.sendRiskZoneError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, result, http.StatusOK)
+}
+
+// DeleteLayer handles DELETE /api/risk-layers/{layer} (admin only)
+func (h *RiskZoneHandler) DeleteLayer(w http.ResponseWriter, r *http.Request) {
+	name := pathSegment(r.URL.Path, 2)
+	if name == "" {
+		http.Error(w, "Layer name required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.riskZoneService.DeleteLayer(name, actor(r)); err != nil {
+		h.sendRiskZoneError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPropertyRisk handles GET /api/properties/{id}/risk
+func (h *RiskZoneHandler) GetPropertyRisk(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	flags, err := h.riskZoneService.GetPropertyRisk(propertyID)
+	if err != nil {
+		h.sendRiskZoneError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"risk_flags":  flags,
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *RiskZoneHandler) sendRiskZoneError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Risk zone error: %v", err)
+		http.Error(w, "Failed to process risk zones", http.StatusInternalServerError)
+	}
+}
+
+func (h *RiskZoneHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"realty-core/internal/security"
+)
+
+// defaultScraperReportClients bounds the clients listed in the scraper activity report
+const defaultScraperReportClients = 50
+
+// ScraperHandler serves the activity of the scraper guard to admins
+type ScraperHandler struct {
+	guard *security.ScraperGuard
+}
+
+// NewScraperHandler creates a new scraper handler
+func NewScraperHandler(guard *security.ScraperGuard) *ScraperHandler {
+	return &ScraperHandler{guard: guard}
+}
+
+// Activity handles GET /api/admin/scraper-activity?limit=50&all=false (admin)
+// It lists the clients the guard delayed, challenged or blocked, or that look automated,
+// most blocked first; all=true lists every tracked client.
+func (h *ScraperHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultScraperReportClients
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.guard.Activity(limit, all, time.Now()))
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// SearchDictionaryHandler handles admin HTTP requests for search synonyms and stop words
+type SearchDictionaryHandler struct {
+	dictionaryService *service.SearchDictionaryService
+	logger            *log.Logger
+}
+
+// NewSearchDictionaryHandler creates a new search dictionary handler
+func NewSearchDictionaryHandler(dictionaryService *service.SearchDictionaryService, logger *log.Logger) *SearchDictionaryHandler {
+	return &SearchDictionaryHandler{
+		dictionaryService: dictionaryService,
+		logger:            logger,
+	}
+}
+
+// CreateSynonymRequest represents the request to add a search synonym
+type CreateSynonymRequest struct {
+	Term      string `json:"term"`
+	Canonical string `json:"canonical"`
+}
+
+// CreateStopWordRequest represents the request to add a search stop word
+type CreateStopWordRequest struct {
+	Word string `json:"word"`
+}
+
+// ListSynonyms handles GET /api/admin/search/synonyms
+func (h *SearchDictionaryHandler) ListSynonyms(w http.ResponseWriter, r *http.Request) {
+	synonyms, err := h.dictionaryService.ListSynonyms()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSONResponse(w, synonyms, http.StatusOK)
+}
+
+// CreateSynonym handles POST /api/admin/search/synonyms
+func (h *SearchDictionaryHandler) CreateSynonym(w http.ResponseWriter, r *http.Request) {
+	var req CreateSynonymRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Term) == "" || strings.TrimSpace(req.Canonical) == "" {
+		http.Error(w, "Term and canonical are required", http.StatusBadRequest)
+		return
+	}
+
+	synonym, reindexed, err := h.dictionaryService.AddSynonym(req.Term, req.Canonical)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"synonym":   synonym,
+		"reindexed": reindexed,
+	}, http.StatusCreated)
+}
+
+// DeleteSynonym handles DELETE /api/admin/search/synonyms/{id}
+func (h *SearchDictionaryHandler) DeleteSynonym(w http.ResponseWriter, r *http.Request) {
+	id := h.extractEntryFromPath(r.URL.Path, "synonyms")
+	if id == "" {
+		http.Error(w, "Synonym ID required", http.StatusBadRequest)
+		return
+	}
+
+	reindexed, err := h.dictionaryService.DeleteSynonym(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"deleted":   id,
+		"reindexed": reindexed,
+	}, http.StatusOK)
+}
+
+// ListStopWords handles GET /api/admin/search/stopwords
+func (h *SearchDictionaryHandler) ListStopWords(w http.ResponseWriter, r *http.Request) {
+	stopWords, err := h.dictionaryService.ListStopWords()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSONResponse(w, stopWords, http.StatusOK)
+}
+
+// CreateStopWord handles POST /api/admin/search/stopwords
+func (h *SearchDictionaryHandler) CreateStopWord(w http.ResponseWriter, r *http.Request) {
+	var req CreateStopWordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.Word) == "" {
+		http.Error(w, "Word is required", http.StatusBadRequest)
+		return
+	}
+
+	stopWord, err := h.dictionaryService.AddStopWord(req.Word)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.sendJSONResponse(w, stopWord, http.StatusCreated)
+}
+
+// DeleteStopWord handles DELETE /api/admin/search/stopwords/{word}
+func (h *SearchDictionaryHandler) DeleteStopWord(w http.ResponseWriter, r *http.Request) {
+	word := h.extractEntryFromPath(r.URL.Path, "stopwords")
+	if word == "" {
+		http.Error(w, "Stop word required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dictionaryService.DeleteStopWord(word); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReloadDictionary handles POST /api/admin/search/dictionary/reload
+func (h *SearchDictionaryHandler) ReloadDictionary(w http.ResponseWriter, r *http.Request) {
+	if err := h.dictionaryService.Reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]string{"status": "reloaded"}, http.StatusOK)
+}
+
+// Helper functions
+
+// extractEntryFromPath returns the unescaped path segment after the given collection name
+func (h *SearchDictionaryHandler) extractEntryFromPath(path, collection string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		if part == collection && i+1 < len(parts) {
+			entry, err := url.PathUnescape(parts[i+1])
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSpace(entry)
+		}
+	}
+	return ""
+}
+
+func (h *SearchDictionaryHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// SectorGuideHandler serves the editorial guides of city sectors
+type SectorGuideHandler struct {
+	sectorGuideService *service.SectorGuideService
+	logger             *log.Logger
+}
+
+// NewSectorGuideHandler creates a new sector guide handler
+func NewSectorGuideHandler(sectorGuideService *service.SectorGuideService, logger *log.Logger) *SectorGuideHandler {
+	return &SectorGuideHandler{
+		sectorGuideService: sectorGuideService,
+		logger:             logger,
+	}
+}
+
+// ListGuides handles GET /api/guides?province=Pichincha&city=Quito&include_drafts=true
+// Drafts are only listed for content editors.
+func (h *SectorGuideHandler) ListGuides(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	includeDrafts := query.Get("include_drafts") == "true"
+
+	guides, err := h.sectorGuideService.ListGuides(query.Get("province"), query.Get("city"), includeDrafts, actor(r))
+	if err != nil {
+		h.sendSectorGuideError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"guides": guides,
+		"count":  len(guides),
+	}, http.StatusOK)
+}
+
+// GetGuide handles GET /api/guides/{slug}, e.g. /api/guides/cumbaya-quito
+func (h *SectorGuideHandler) GetGuide(w http.ResponseWriter, r *http.Request) {
+	slug := pathSegment(r.URL.Path, 2)
+	if slug == "" {
+		http.Error(w, "Guide slug required", http.StatusBadRequest)
+		return
+	}
+
+	guide, err := h.sectorGuideService.GetGuide(slug, actor(r))
+	if err != nil {
+		h.sendSectorGuideError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, guide, http.StatusOK)
+}
+
+// CreateGuide handles POST /api/guides (content editors)
+// ({"province": "Pichincha", "city": "Quito", "sector": "Cumbayá", "title": "Vivir en Cumbayá",
+// "summary": "...", "description": "...", "photos": [{"url": "https://...", "caption": "Parque central"}],
+// "safety_notes": "...", "status": "draft"})
+// The slug defaults to the sector and city; average prices are filled from current listings.
+func (h *SectorGuideHandler) CreateGuide(w http.ResponseWriter, r *http.Request) {
+	var content domain.SectorGuideContent
+	if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	guide, err := h.sectorGuideService.CreateGuide(&content, actor(r))
+	if err != nil {
+		h.sendSectorGuideError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, guide, http.StatusCreated)
+}
+
+// UpdateGuide handles PUT /api/guides/{id} (content editors), replacing the content
+func (h *SectorGuideHandler) UpdateGuide(w http.ResponseWriter, r *http.Request) {
+	id := pathSegment(r.URL.Path, 2)
+	if id == "" {
+		http.Error(w, "Guide ID required", http.StatusBadRequest)
+		return
+	}
+
+	var content domain.SectorGuideContent
+	if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	guide, err := h.sectorGuideService.UpdateGuide(id, &content, actor(r))
+	if err != nil {
+		h.sendSectorGuideError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, guide, http.StatusOK)
+}
+
+// DeleteGuide handles DELETE /api/guides/{id} (content editors)
+func (h *SectorGuideHandler) DeleteGuide(w http.ResponseWriter, r *http.Request) {
+	id := pathSegment(r.URL.Path, 2)
+	if id == "" {
+		http.Error(w, "Guide ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sectorGuideService.DeleteGuide(id, actor(r)); err != nil {
+		h.sendSectorGuideError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Helper functions
+
+func (h *SectorGuideHandler) sendSectorGuideError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "already exists"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Sector guide error: %v", err)
+		http.Error(w, "Failed to process sector guide", http.StatusInternalServerError)
+	}
+}
+
+func (h *SectorGuideHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
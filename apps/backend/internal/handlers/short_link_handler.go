@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// ShortLinkHandler handles property short links and their analytics
+type ShortLinkHandler struct {
+	shortLinkService *service.ShortLinkService
+	logger           *log.Logger
+}
+
+// NewShortLinkHandler creates a new short link handler
+func NewShortLinkHandler(shortLinkService *service.ShortLinkService, logger *log.Logger) *ShortLinkHandler {
+	return &ShortLinkHandler{
+		shortLinkService: shortLinkService,
+		logger:           logger,
+	}
+}
+
+// Redirect handles GET /l/{code}, recording the click before redirecting to the
+// property page. The click source is taken from ?src= or ?utm_source=, else the referrer.
+func (h *ShortLinkHandler) Redirect(w http.ResponseWriter, r *http.Request) {
+	code := pathSegment(r.URL.Path, 1)
+
+	source := r.URL.Query().Get("src")
+	if source == "" {
+		source = r.URL.Query().Get("utm_source")
+	}
+
+	target, err := h.shortLinkService.Resolve(code, source, r.Referer())
+	if err != nil {
+		h.sendShortLinkError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// CreateLink handles POST /api/properties/{id}/short-links ({"campaign": "verano-2025"})
+func (h *ShortLinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Campaign string `json:"campaign"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	link, err := h.shortLinkService.CreateLink(propertyID, req.Campaign, actor(r))
+	if err != nil {
+		h.sendShortLinkError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, link, http.StatusCreated)
+}
+
+// ListLinks handles GET /api/properties/{id}/short-links
+func (h *ShortLinkHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	links, err := h.shortLinkService.ListLinks(propertyID, actor(r))
+	if err != nil {
+		h.sendShortLinkError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"links": links,
+		"count": len(links),
+	}, http.StatusOK)
+}
+
+// GetViewStats handles GET /api/properties/{id}/view-stats with the property's views and
+// the clicks through its short links by source and campaign
+func (h *ShortLinkHandler) GetViewStats(w http.ResponseWriter, r *http.Request) {
+	propertyID := pathSegment(r.URL.Path, 2)
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.shortLinkService.GetViewStats(propertyID, actor(r))
+	if err != nil {
+		h.sendShortLinkError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, stats, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *ShortLinkHandler) sendShortLinkError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Short link error: %v", err)
+		http.Error(w, "Failed to process short link", http.StatusInternalServerError)
+	}
+}
+
+func (h *ShortLinkHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
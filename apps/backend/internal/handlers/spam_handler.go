@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// SpamHandler serves the admin queue of inquiries and listings quarantined as spam
+type SpamHandler struct {
+	spamService *service.SpamService
+	logger      *log.Logger
+}
+
+// NewSpamHandler creates a new spam handler
+func NewSpamHandler(spamService *service.SpamService, logger *log.Logger) *SpamHandler {
+	return &SpamHandler{
+		spamService: spamService,
+		logger:      logger,
+	}
+}
+
+// decideSpamRequest approves or blocks a quarantined submission
+type decideSpamRequest struct {
+	Action string `json:"action"` // approve or block
+}
+
+// ListQueue handles GET /api/admin/spam-cases?status=quarantined&type=inquiry&limit=20&offset=0
+// Each case carries its score and the signals that produced it.
+func (h *SpamHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	cases, total, err := h.spamService.ListQueue(domain.SpamCaseFilter{
+		EntityType: query.Get("type"),
+		Status:     query.Get("status"),
+		Limit:      limit,
+		Offset:     offset,
+	}, actor(r))
+	if err != nil {
+		h.sendSpamError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"cases": cases,
+		"total": total,
+	}, http.StatusOK)
+}
+
+// Decide handles POST /api/admin/spam-cases/{id}/decide
+// Approving publishes the listing or notifies the landlord of the inquiry; blocking
+// keeps it hidden.
+func (h *SpamHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	var req decideSpamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Action != "approve" && req.Action != "block" {
+		http.Error(w, "invalid action: must be approve or block", http.StatusBadRequest)
+		return
+	}
+
+	spamCase, err := h.spamService.Decide(pathSegment(r.URL.Path, 3), req.Action == "approve", actor(r))
+	if err != nil {
+		h.sendSpamError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, spamCase, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *SpamHandler) sendSpamError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "already decided"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Spam review error: %v", err)
+		http.Error(w, "Failed to process spam review", http.StatusInternalServerError)
+	}
+}
+
+func (h *SpamHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/storage"
+)
+
+// servedImageDirs are the storage directories published by the file server; temp
+// uploads and anything else under the storage path stay private
+var servedImageDirs = map[string]bool{"originals": true, "thumbnails": true, "variants": true}
+
+// servedImageTypes are the content types served inline. Anything else, SVG included,
+// since it can carry scripts, is served as a download.
+var servedImageTypes = map[string]bool{
+	"image/jpeg": true, "image/png": true, "image/gif": true, "image/webp": true, "image/avif": true, "image/bmp": true,
+}
+
+// HiddenImageFiles tells the stored files of images moderation hid, e.g.
+// repository.ImageModerationRepository
+type HiddenImageFiles interface {
+	IsFileHidden(fileName string) (bool, error)
+}
+
+// StaticImageHandler serves the stored image files with Range, If-Range and
+// conditional request support, so large originals can be resumed and partially loaded
+type StaticImageHandler struct {
+	storage storage.ImageStorage
+	hidden  HiddenImageFiles
+	prefix  string
+	maxAge  time.Duration
+	logger  *log.Logger
+}
+
+// NewStaticImageHandler creates a new handler serving the files of imageStorage under
+// the URL prefix, e.g. /uploads/images
+func NewStaticImageHandler(imageStorage storage.ImageStorage, prefix string, maxAge time.Duration, logger *log.Logger) *StaticImageHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &StaticImageHandler{
+		storage: imageStorage,
+		prefix:  "/" + strings.Trim(prefix, "/"),
+		maxAge:  maxAge,
+		logger:  logger,
+	}
+}
+
+// SetModeration refuses the files of images quarantined by moderation, originals and
+// renditions alike
+func (h *StaticImageHandler) SetModeration(hidden HiddenImageFiles) {
+	h.hidden = hidden
+}
+
+// ServeImage handles GET and HEAD {prefix}/{originals|thumbnails|variants}/{file}
+// Files of local storage are copied straight from disk, which the server does with
+// sendfile; other backends are read into memory first.
+func (h *StaticImageHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filePath, ok := h.storagePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if h.hidden != nil {
+		hidden, err := h.hidden.IsFileHidden(path.Base(filePath))
+		if err != nil {
+			h.logger.Printf("Error checking moderation of image %s: %v", filePath, err)
+			http.Error(w, "Failed to read image", http.StatusInternalServerError)
+			return
+		}
+		if hidden {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	if opener, ok := h.storage.(storage.FileOpener); ok {
+		file, err := opener.Open(filePath)
+		if err != nil {
+			h.sendStorageError(w, r, filePath, err)
+			return
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			h.sendStorageError(w, r, filePath, err)
+			return
+		}
+		etag := `"` + strconv.FormatInt(info.Size(), 36) + "-" + strconv.FormatInt(info.ModTime().UnixNano(), 36) + `"`
+		h.serve(w, r, file, path.Base(filePath), info.ModTime(), etag)
+		return
+	}
+
+	data, err := h.storage.Retrieve(filePath)
+	if err != nil {
+		h.sendStorageError(w, r, filePath, err)
+		return
+	}
+	sum := sha256.Sum256(data)
+	h.serve(w, r, bytes.NewReader(data), path.Base(filePath), time.Time{}, `"`+hex.EncodeToString(sum[:16])+`"`)
+}
+
+// storagePath returns the storage path of a request path, if it is a served file
+func (h *StaticImageHandler) storagePath(urlPath string) (string, bool) {
+	if !strings.HasPrefix(urlPath, h.prefix+"/") {
+		return "", false
+	}
+	filePath := strings.TrimPrefix(path.Clean(strings.TrimPrefix(urlPath, h.prefix)), "/")
+	dir, name, found := strings.Cut(filePath, "/")
+	if !found || name == "" || !servedImageDirs[dir] {
+		return "", false
+	}
+	return filePath, true
+}
+
+// serve writes content with its sniffed type, validators and cache headers; Range,
+// If-Range and the conditional headers are handled by http.ServeContent
+func (h *StaticImageHandler) serve(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, name string, modTime time.Time, etag string) {
+	contentType, err := sniffImageType(content, name)
+	if err != nil {
+		h.logger.Printf("Error reading image %s: %v", name, err)
+		http.Error(w, "Failed to read image", http.StatusInternalServerError)
+		return
+	}
+
+	header := w.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	if servedImageTypes[contentType] {
+		header.Set("Content-Type", contentType)
+	} else {
+		header.Set("Content-Type", "application/octet-stream")
+		header.Set("Content-Disposition", "attachment")
+	}
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	http.ServeContent(w, r, name, modTime, content)
+}
+
+// sendStorageError answers a failed read of the storage
+func (h *StaticImageHandler) sendStorageError(w http.ResponseWriter, r *http.Request, filePath string, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		http.NotFound(w, r)
+		return
+	}
+	h.logger.Printf("Error serving image %s: %v", filePath, err)
+	http.Error(w, "Failed to read image", http.StatusInternalServerError)
+}
+
+// sniffImageType detects the type of an image from its first bytes, so a file with a
+// wrong extension is served as what it is. The extension is only trusted for what the
+// sniffer does not tell apart.
+func sniffImageType(content io.ReadSeeker, name string) (string, error) {
+	var head [512]byte
+	n, err := io.ReadFull(content, head[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	if isAVIF(head[:n]) {
+		return "image/avif", nil
+	}
+	detected, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	if detected != "application/octet-stream" {
+		return detected, nil
+	}
+	if byExtension, _, _ := strings.Cut(mime.TypeByExtension(strings.ToLower(path.Ext(name))), ";"); byExtension != "" {
+		return byExtension, nil
+	}
+	return detected, nil
+}
+
+// isAVIF reports whether data starts an AVIF file, an ISO media file of brand avif or
+// avis, which http.DetectContentType does not know
+func isAVIF(data []byte) bool {
+	if len(data) < 12 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+	brand := string(data[8:12])
+	return brand == "avif" || brand == "avis"
+}
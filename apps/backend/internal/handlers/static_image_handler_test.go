@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/storage"
+)
+
+// memoryImageStorage is a storage backend without FileOpener, like S3
+type memoryImageStorage struct {
+	storage.ImageStorage
+	files map[string][]byte
+}
+
+func (m *memoryImageStorage) Retrieve(filePath string) ([]byte, error) {
+	data, ok := m.files[filePath]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", filePath)
+	}
+	return data, nil
+}
+
+func pngBytes(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	img.Set(1, 1, color.RGBA{R: 200, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestStaticImageHandler_Range(t *testing.T) {
+	local, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/images", 1024*1024)
+	require.NoError(t, err)
+	data := pngBytes(t)
+	// Stored with the wrong extension, the type is sniffed from the content
+	filePath, err := local.Store(data, "casa.jpg")
+	require.NoError(t, err)
+	handler := NewStaticImageHandler(local, "/uploads/images", time.Hour, nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeImage(w, httptest.NewRequest(http.MethodGet, "/uploads/images/"+filePath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+	assert.Equal(t, data, w.Body.Bytes())
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/uploads/images/"+filePath, nil)
+	req.Header.Set("Range", "bytes=8-15")
+	w = httptest.NewRecorder()
+	handler.ServeImage(w, req)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, data[8:16], w.Body.Bytes())
+	assert.Contains(t, w.Header().Get("Content-Range"), "bytes 8-15/")
+
+	// A stale If-Range gets the whole file
+	req.Header.Set("If-Range", `"stale"`)
+	w = httptest.NewRecorder()
+	handler.ServeImage(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Body.Bytes(), len(data))
+
+	req.Header.Set("If-Range", etag)
+	w = httptest.NewRecorder()
+	handler.ServeImage(w, req)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/uploads/images/"+filePath, nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeImage(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestStaticImageHandler_NotServed(t *testing.T) {
+	local, err := storage.NewLocalImageStorage(t.TempDir(), "", 1024*1024)
+	require.NoError(t, err)
+	_, err = local.StoreVariant([]byte("draft"), "upload.jpg", "temp")
+	require.NoError(t, err)
+	handler := NewStaticImageHandler(local, "/uploads/images", time.Hour, nil)
+
+	for _, path := range []string{
+		"/uploads/images/temp/upload.jpg",
+		"/uploads/images/originals/missing.jpg",
+		"/uploads/images/originals/../temp/upload.jpg",
+		"/uploads/images/originals",
+		"/uploads/imagesx/originals/a.jpg",
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeImage(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeImage(w, httptest.NewRequest(http.MethodDelete, "/uploads/images/originals/a.jpg", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestStaticImageHandler_RemoteStorage(t *testing.T) {
+	remote := &memoryImageStorage{files: map[string][]byte{
+		"originals/casa.png": pngBytes(t),
+		"originals/logo.svg": []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`),
+	}}
+	handler := NewStaticImageHandler(remote, "/uploads/images/", time.Hour, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/uploads/images/originals/casa.png", nil)
+	req.Header.Set("Range", "bytes=-4")
+	w := httptest.NewRecorder()
+	handler.ServeImage(w, req)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Len(t, w.Body.Bytes(), 4)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	handler.ServeImage(w, httptest.NewRequest(http.MethodGet, "/uploads/images/originals/logo.svg", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"), "SVG is not served inline")
+	assert.Equal(t, "attachment", w.Header().Get("Content-Disposition"))
+}
+
+func TestSniffImageType_AVIF(t *testing.T) {
+	avif := append([]byte{0, 0, 0, 0x1c}, []byte("ftypavif\x00\x00\x00\x00mif1miaf")...)
+	contentType, err := sniffImageType(bytes.NewReader(avif), "casa.bin")
+	require.NoError(t, err)
+	assert.Equal(t, "image/avif", contentType)
+}
+
+// quarantinedFiles hides the files of one quarantined image
+type quarantinedFiles struct {
+	stem string
+}
+
+func (q quarantinedFiles) IsFileHidden(fileName string) (bool, error) {
+	return strings.HasPrefix(fileName, q.stem+".") || strings.HasPrefix(fileName, q.stem+"_"), nil
+}
+
+func TestStaticImageHandler_QuarantinedImages(t *testing.T) {
+	data := pngBytes(t)
+	backend := &memoryImageStorage{files: map[string][]byte{
+		"originals/prop-1_aaaa1111.png":             data,
+		"thumbnails/prop-1_aaaa1111_thumb_150.jpg":  data,
+		"variants/prop-1_aaaa1111_card_380x200.jpg": data,
+		"originals/prop-1_bbbb2222.png":             data,
+		"thumbnails/prop-1_bbbb2222_thumb_150.jpg":  data,
+	}}
+	handler := NewStaticImageHandler(backend, "/uploads/images", time.Hour, nil)
+	handler.SetModeration(quarantinedFiles{stem: "prop-1_aaaa1111"})
+
+	for _, filePath := range []string{"originals/prop-1_aaaa1111.png", "thumbnails/prop-1_aaaa1111_thumb_150.jpg", "variants/prop-1_aaaa1111_card_380x200.jpg"} {
+		w := httptest.NewRecorder()
+		handler.ServeImage(w, httptest.NewRequest(http.MethodGet, "/uploads/images/"+filePath, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, filePath)
+	}
+	for _, filePath := range []string{"originals/prop-1_bbbb2222.png", "thumbnails/prop-1_bbbb2222_thumb_150.jpg"} {
+		w := httptest.NewRecorder()
+		handler.ServeImage(w, httptest.NewRequest(http.MethodGet, "/uploads/images/"+filePath, nil))
+		assert.Equal(t, http.StatusOK, w.Code, filePath)
+	}
+}
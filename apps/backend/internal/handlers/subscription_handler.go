@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// SubscriptionHandler handles agency subscription plans
+type SubscriptionHandler struct {
+	subscriptionService *service.SubscriptionService
+	logger              *log.Logger
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(subscriptionService *service.SubscriptionService, logger *log.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// ListPlans handles GET /api/plans
+func (h *SubscriptionHandler) ListPlans(w http.ResponseWriter, r *http.Request) {
+	plans := h.subscriptionService.ListPlans()
+	h.sendJSONResponse(w, map[string]interface{}{
+		"plans": plans,
+		"count": len(plans),
+	}, http.StatusOK)
+}
+
+// GetAgencyPlan handles GET /api/agencies/{id}/plan
+func (h *SubscriptionHandler) GetAgencyPlan(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+	if !actor(r).CanAdministerAgency(agencyID) {
+		http.Error(w, "Only agency administrators can view the plan", http.StatusForbidden)
+		return
+	}
+
+	subscription, err := h.subscriptionService.GetAgencySubscription(agencyID)
+	if err != nil {
+		h.sendSubscriptionError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, subscription, http.StatusOK)
+}
+
+// ChangeAgencyPlan handles PUT /api/agencies/{id}/plan ({"plan": "pro"}) for upgrades
+// and downgrades
+func (h *SubscriptionHandler) ChangeAgencyPlan(w http.ResponseWriter, r *http.Request) {
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Plan string `json:"plan"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Plan == "" {
+		http.Error(w, "Plan required", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.subscriptionService.ChangePlan(agencyID, req.Plan, actor(r))
+	if err != nil {
+		h.sendSubscriptionError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, subscription, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *SubscriptionHandler) sendSubscriptionError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "cannot downgrade"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.logger.Printf("Subscription error: %v", err)
+		http.Error(w, "Failed to process subscription", http.StatusInternalServerError)
+	}
+}
+
+func (h *SubscriptionHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+// TenantHandler serves the branding of the current tenant and lets administrators
+// manage tenants
+type TenantHandler struct {
+	tenantService *service.TenantService
+	logger        *log.Logger
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(tenantService *service.TenantService, logger *log.Logger) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+		logger:        logger,
+	}
+}
+
+// GetCurrentTenant handles GET /api/tenant
+// Returns the branding and settings of the tenant the request resolved to, so the
+// frontend can theme itself; single-tenant installs get multi_tenancy false.
+func (h *TenantHandler) GetCurrentTenant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tenantID := middleware.GetTenantID(r.Context())
+	if !h.tenantService.Enabled() || tenantID == "" {
+		h.sendJSONResponse(w, map[string]interface{}{"multi_tenancy": false}, http.StatusOK)
+		return
+	}
+
+	tenant, err := h.tenantService.GetTenant(tenantID)
+	if err != nil {
+		h.sendTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	h.sendJSONResponse(w, map[string]interface{}{
+		"multi_tenancy": true,
+		"id":            tenant.ID,
+		"name":          tenant.Name,
+		"branding":      tenant.Branding,
+		"settings":      tenant.Settings,
+	}, http.StatusOK)
+}
+
+// ListTenants handles GET /api/admin/tenants
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tenants, err := h.tenantService.ListTenants(actor(r))
+	if err != nil {
+		h.sendTenantError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"tenants": tenants,
+		"count":   len(tenants),
+	}, http.StatusOK)
+}
+
+// CreateTenant handles POST /api/admin/tenants
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req service.CreateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tenant, err := h.tenantService.CreateTenant(actor(r), req)
+	if err != nil {
+		h.sendTenantError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, tenant, http.StatusCreated)
+}
+
+// UpdateTenant handles PUT /api/admin/tenants/{id}
+func (h *TenantHandler) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tenantID := pathSegment(r.URL.Path, 3)
+	if tenantID == "" {
+		http.Error(w, "Tenant ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req service.UpdateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	tenant, err := h.tenantService.UpdateTenant(actor(r), tenantID, req)
+	if err != nil {
+		h.sendTenantError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, tenant, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *TenantHandler) sendTenantError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "already exists"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Tenant error: %v", err)
+		http.Error(w, "Failed to process tenant", http.StatusInternalServerError)
+	}
+}
+
+func (h *TenantHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
 	"realty-core/internal/service"
 )
 
@@ -31,6 +32,12 @@ func NewUserHandlerSimple(
 	}
 }
 
+// tenantService returns the user service scoped to the tenant of the request, so users
+// of other tenants are not found
+func (h *UserHandlerSimple) tenantService(r *http.Request) *service.UserServiceSimple {
+	return h.userService.ForTenant(middleware.GetTenantID(r.Context()))
+}
+
 // CreateUserRequest represents the request to create a user
 type CreateUserRequest struct {
 	FirstName string `json:"first_name"`
@@ -63,6 +70,7 @@ type ChangePasswordRequest struct {
 }
 
 // CreateUser handles user creation
+// Served behind the CAPTCHA middleware's registration endpoint.
 func (h *UserHandlerSimple) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -85,7 +93,7 @@ func (h *UserHandlerSimple) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.CreateUser(req.FirstName, req.LastName, req.Email, req.Phone, req.Cedula, req.Password, role)
+	user, err := h.tenantService(r).CreateUser(req.FirstName, req.LastName, req.Email, req.Phone, req.Cedula, req.Password, role)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -102,7 +110,7 @@ func (h *UserHandlerSimple) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.GetUser(id)
+	user, err := h.tenantService(r).GetUser(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -125,7 +133,7 @@ func (h *UserHandlerSimple) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.GetUser(id)
+	user, err := h.tenantService(r).GetUser(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -134,10 +142,10 @@ func (h *UserHandlerSimple) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Update fields
 	user.FirstName = req.FirstName
 	user.LastName = req.LastName
-	user.Phone = &req.Phone
+	user.SetPhone(req.Phone)
 	user.Bio = &req.Bio
 
-	if err := h.userService.UpdateUser(user); err != nil {
+	if err := h.tenantService(r).UpdateUser(user); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -153,8 +161,12 @@ func (h *UserHandlerSimple) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.userService.DeleteUser(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.tenantService(r).DeleteUser(id); err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -169,7 +181,7 @@ func (h *UserHandlerSimple) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.AuthenticateUser(req.Email, req.Password)
+	user, err := h.tenantService(r).AuthenticateUser(req.Email, req.Password)
 	if err != nil {
 		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
@@ -200,7 +212,7 @@ func (h *UserHandlerSimple) ChangePassword(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if err := h.userService.ChangePassword(id, req.OldPassword, req.NewPassword); err != nil {
+	if err := h.tenantService(r).ChangePassword(id, req.OldPassword, req.NewPassword); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -245,7 +257,7 @@ func (h *UserHandlerSimple) SearchUsers(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	users, total, err := h.userService.SearchUsers("", name, role, active, limit, offset)
+	users, total, err := h.tenantService(r).SearchUsers("", name, role, active, limit, offset)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -270,7 +282,7 @@ func (h *UserHandlerSimple) GetUsersByRole(w http.ResponseWriter, r *http.Reques
 	}
 
 	role := domain.UserRole(roleStr)
-	users, err := h.userService.GetUsersByRole(role)
+	users, err := h.tenantService(r).GetUsersByRole(role)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -285,7 +297,7 @@ func (h *UserHandlerSimple) GetUsersByRole(w http.ResponseWriter, r *http.Reques
 
 // GetUserStatistics handles getting user statistics
 func (h *UserHandlerSimple) GetUserStatistics(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.userService.GetUserStatistics()
+	stats, err := h.tenantService(r).GetUserStatistics()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -297,7 +309,7 @@ func (h *UserHandlerSimple) GetUserStatistics(w http.ResponseWriter, r *http.Req
 // GetUserDashboard handles getting user dashboard data
 func (h *UserHandlerSimple) GetUserDashboard(w http.ResponseWriter, r *http.Request) {
 	// Simple dashboard with basic stats
-	stats, err := h.userService.GetUserStatistics()
+	stats, err := h.tenantService(r).GetUserStatistics()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
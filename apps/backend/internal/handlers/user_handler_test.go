@@ -1,12 +1,21 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/middleware"
+	"realty-core/internal/repository"
+	"realty-core/internal/service"
 )
 
 // Tests básicos para UserHandler enfocados en validación de entrada
@@ -268,4 +277,31 @@ func TestUserHandler_MissingFields(t *testing.T) {
 }
 
 // Tests básicos completados para UserHandler
-// Los tests que requieren servicios reales se omiten por limitaciones de setup
\ No newline at end of file
+// Los tests que requieren servicios reales se omiten por limitaciones de setup
+
+func TestUserHandler_HidesUsersOfOtherTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	users := service.NewUserService(repository.NewUserRepository(db), repository.NewAgencyRepository(db), log.Default())
+	handler := NewUserHandlerSimple(users, nil, log.Default())
+	request := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/api/users/acme-user", nil)
+		return req.WithContext(context.WithValue(req.Context(), middleware.TenantIDKey, "beta"))
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		mock.ExpectQuery(`FROM users\s+WHERE id = \$1 AND tenant_id = \$2`).
+			WithArgs("acme-user", "beta").
+			WillReturnError(sql.ErrNoRows)
+		rr := httptest.NewRecorder()
+		if method == http.MethodGet {
+			handler.GetUser(rr, request(method))
+		} else {
+			handler.DeleteUser(rr, request(method))
+		}
+		assert.Equal(t, http.StatusNotFound, rr.Code, method)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// UserPreferencesHandler serves the preference center of users
+type UserPreferencesHandler struct {
+	preferencesService *service.UserPreferencesService
+	logger             *log.Logger
+}
+
+// NewUserPreferencesHandler creates a new user preferences handler
+func NewUserPreferencesHandler(preferencesService *service.UserPreferencesService, logger *log.Logger) *UserPreferencesHandler {
+	return &UserPreferencesHandler{
+		preferencesService: preferencesService,
+		logger:             logger,
+	}
+}
+
+// GetPreferences handles GET /api/users/{id}/preferences
+func (h *UserPreferencesHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := pathSegment(r.URL.Path, 2)
+	if userID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.preferencesService.GetPreferences(userID, actor(r))
+	if err != nil {
+		h.sendPreferencesError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, prefs, http.StatusOK)
+}
+
+// UpdatePreferences handles PUT /api/users/{id}/preferences
+// The body replaces every preference, e.g.
+// {"channels":{"email":true,"sms":false,"push":true},"email_frequency":"daily","locale":"en",
+// "search_defaults":{"provinces":["Pichincha"],"property_types":["apartment"],"max_price":180000}}
+func (h *UserPreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID := pathSegment(r.URL.Path, 2)
+	if userID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	var prefs domain.UserPreferences
+	if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.preferencesService.UpdatePreferences(userID, &prefs, actor(r))
+	if err != nil {
+		h.sendPreferencesError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, updated, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *UserPreferencesHandler) sendPreferencesError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("User preferences error: %v", err)
+		http.Error(w, "Failed to process preferences", http.StatusInternalServerError)
+	}
+}
+
+func (h *UserPreferencesHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
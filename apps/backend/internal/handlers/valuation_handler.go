@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// ValuationHandler serves listing price suggestions
+type ValuationHandler struct {
+	valuationService *service.ValuationService
+	logger           *log.Logger
+}
+
+// NewValuationHandler creates a new valuation handler
+func NewValuationHandler(valuationService *service.ValuationService, logger *log.Logger) *ValuationHandler {
+	return &ValuationHandler{
+		valuationService: valuationService,
+		logger:           logger,
+	}
+}
+
+// SuggestPrice handles POST /api/tools/price-suggestion
+// Body: {"province": "Pichincha", "city": "Quito", "sector": "Cumbayá", "type": "house", "area_m2": 180, "price": 250000}
+// The response has the suggested price with its range, the comparables it was computed
+// from and, when a price is sent, whether it is below, within or above the range.
+func (h *ValuationHandler) SuggestPrice(w http.ResponseWriter, r *http.Request) {
+	var request domain.PriceSuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+
+	suggestion, err := h.valuationService.SuggestPrice(&request, actor)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "permission denied"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case strings.Contains(err.Error(), "not enough comparables"):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case strings.Contains(err.Error(), "invalid"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.logger.Printf("Error suggesting price: %v", err)
+			http.Error(w, "Failed to suggest price", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestion)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/service"
+)
+
+// VideoHandler handles property video uploads and playback
+type VideoHandler struct {
+	videoService *service.VideoService
+	logger       *log.Logger
+}
+
+// NewVideoHandler creates a new video handler
+func NewVideoHandler(videoService *service.VideoService, logger *log.Logger) *VideoHandler {
+	return &VideoHandler{
+		videoService: videoService,
+		logger:       logger,
+	}
+}
+
+// UploadVideo handles POST /api/videos (multipart: property_id, video).
+// The video is transcoded in the background; poll GET /api/videos/{id} for its status.
+func (h *VideoHandler) UploadVideo(w http.ResponseWriter, r *http.Request) {
+	// Parts larger than 32MB are spooled to disk by the multipart reader
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	propertyID := r.FormValue("property_id")
+	if propertyID == "" {
+		http.Error(w, "property_id is required", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("video")
+	if err != nil {
+		http.Error(w, "video file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	video, err := h.videoService.Upload(propertyID, header.Filename, header.Header.Get("Content-Type"), header.Size, file)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "property not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "too large"):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case strings.Contains(err.Error(), "validation failed"), strings.Contains(err.Error(), "exceeded"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case strings.Contains(err.Error(), "queue is full"):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			h.logger.Printf("Error uploading video: %v", err)
+			http.Error(w, "Failed to upload video", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendJSONResponse(w, video, http.StatusAccepted)
+}
+
+// GetVideo handles GET /api/videos/{id}
+func (h *VideoHandler) GetVideo(w http.ResponseWriter, r *http.Request) {
+	videoID := h.extractVideoID(r.URL.Path)
+	if videoID == "" {
+		http.Error(w, "Video ID required", http.StatusBadRequest)
+		return
+	}
+
+	video, err := h.videoService.GetVideo(videoID)
+	if err != nil {
+		h.sendVideoError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, video, http.StatusOK)
+}
+
+// GetVideosByProperty handles GET /api/properties/{id}/videos
+func (h *VideoHandler) GetVideosByProperty(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	propertyID := ""
+	for i, part := range parts {
+		if part == "properties" && i+1 < len(parts) {
+			propertyID = parts[i+1]
+		}
+	}
+	if propertyID == "" {
+		http.Error(w, "Property ID required", http.StatusBadRequest)
+		return
+	}
+
+	videos, err := h.videoService.GetVideosByProperty(propertyID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"property_id": propertyID,
+		"videos":      videos,
+		"count":       len(videos),
+	}, http.StatusOK)
+}
+
+// GetManifest handles GET /api/videos/{id}/manifest.m3u8 and serves the HLS master playlist
+func (h *VideoHandler) GetManifest(w http.ResponseWriter, r *http.Request) {
+	videoID := h.extractVideoID(r.URL.Path)
+	if videoID == "" {
+		http.Error(w, "Video ID required", http.StatusBadRequest)
+		return
+	}
+
+	manifest, err := h.videoService.GetManifest(videoID)
+	if err != nil {
+		h.sendVideoError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(manifest))
+}
+
+// DeleteVideo handles DELETE /api/videos/{id}
+func (h *VideoHandler) DeleteVideo(w http.ResponseWriter, r *http.Request) {
+	videoID := h.extractVideoID(r.URL.Path)
+	if videoID == "" {
+		http.Error(w, "Video ID required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.videoService.DeleteVideo(videoID); err != nil {
+		h.sendVideoError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]string{"deleted": videoID}, http.StatusOK)
+}
+
+// Helper functions
+
+// extractVideoID returns the video ID from /api/videos/{id}/...
+func (h *VideoHandler) extractVideoID(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		if part == "videos" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+func (h *VideoHandler) sendVideoError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "not ready"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *VideoHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
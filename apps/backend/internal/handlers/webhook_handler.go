@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/service"
+)
+
+// WebhookHandler lets agencies manage webhook subscriptions and inspect deliveries
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+	logger         *log.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService, logger *log.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// ListSubscriptions handles GET /api/webhooks?agency_id=
+// Also returns the event types subscriptions can receive.
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookService.ListSubscriptions(r.URL.Query().Get("agency_id"), actor(r))
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"subscriptions": subscriptions,
+		"count":         len(subscriptions),
+		"event_types":   domain.WebhookEventTypes,
+	}, http.StatusOK)
+}
+
+// CreateSubscription handles POST /api/webhooks
+// The response carries the signing secret, which is not shown again. Deliveries are
+// signed in the X-Webhook-Signature header as t=<unix>,v1=<hex HMAC-SHA256 of "t.body">.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req service.SaveWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(req, actor(r))
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"subscription": subscription,
+		"secret":       subscription.Secret,
+	}, http.StatusCreated)
+}
+
+// GetSubscription handles GET /api/webhooks/{id}
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, err := h.webhookService.GetSubscription(pathSegment(r.URL.Path, 2), actor(r))
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, subscription, http.StatusOK)
+}
+
+// UpdateSubscription handles PUT /api/webhooks/{id}
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req service.SaveWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(pathSegment(r.URL.Path, 2), req, actor(r))
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, subscription, http.StatusOK)
+}
+
+// DeleteSubscription handles DELETE /api/webhooks/{id}
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhookService.DeleteSubscription(pathSegment(r.URL.Path, 2), actor(r)); err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/webhooks/{id}/deliveries?limit=50
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	deliveries, err := h.webhookService.ListDeliveries(pathSegment(r.URL.Path, 2), limit, actor(r))
+	if err != nil {
+		h.sendWebhookError(w, err)
+		return
+	}
+
+	h.sendJSONResponse(w, map[string]interface{}{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	}, http.StatusOK)
+}
+
+// Helper functions
+
+func (h *WebhookHandler) sendWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.logger.Printf("Webhook error: %v", err)
+		http.Error(w, "Failed to process webhook", http.StatusInternalServerError)
+	}
+}
+
+func (h *WebhookHandler) sendJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/service"
+)
+
+// WidgetTokenHeader carries the embed token of widget requests that do not send it as
+// ?token=
+const WidgetTokenHeader = "X-Widget-Token"
+
+// WidgetHandler serves the listing widgets agencies embed in their own sites, and issues
+// the embed tokens that open them
+type WidgetHandler struct {
+	widgetService *service.WidgetService
+	cacheMaxAge   time.Duration
+	logger        *log.Logger
+}
+
+// NewWidgetHandler creates a new widget handler. Widget responses may be reused by
+// browsers and CDNs for cacheMaxAge.
+func NewWidgetHandler(widgetService *service.WidgetService, cacheMaxAge time.Duration, logger *log.Logger) *WidgetHandler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &WidgetHandler{
+		widgetService: widgetService,
+		cacheMaxAge:   cacheMaxAge,
+		logger:        logger,
+	}
+}
+
+// IssueWidgetTokenRequest is the body of POST /api/agencies/{id}/widget-tokens
+type IssueWidgetTokenRequest struct {
+	Origins []string `json:"origins"`
+}
+
+// IssueToken handles POST /api/agencies/{id}/widget-tokens ({"origins": ["https://www.inmobiliaria.ec"]})
+// The token goes in the embed code of the agency's site; without origins it works from
+// any site.
+func (h *WidgetHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agencyID := pathSegment(r.URL.Path, 2)
+	if agencyID == "" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req IssueWidgetTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	actor := actor(r)
+	token, claims, err := h.widgetService.IssueToken(actor, agencyID, req.Origins)
+	if err != nil {
+		h.sendWidgetError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":      token,
+		"agency_id":  claims.AgencyID,
+		"origins":    claims.Origins,
+		"expires_at": time.Unix(claims.ExpiresAt, 0).UTC(),
+	})
+}
+
+// AgencyListings handles GET /api/widgets/agency/{id}/listings?token=...&limit=12
+// It answers any origin the token allows, with a compact payload browsers and CDNs may
+// cache. Its CORS headers and preflights come from DefaultCORSRoutes.
+func (h *WidgetHandler) AgencyListings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	header := w.Header()
+
+	agencyID := pathSegment(r.URL.Path, 3)
+	if agencyID == "" || pathSegment(r.URL.Path, 4) != "listings" {
+		http.Error(w, "Agency ID required", http.StatusBadRequest)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = r.Header.Get(WidgetTokenHeader)
+		header.Add("Vary", WidgetTokenHeader)
+	}
+	origin := r.Header.Get("Origin")
+	_, err := h.widgetService.VerifyToken(token, agencyID, origin)
+	if err != nil {
+		h.sendWidgetError(w, err)
+		return
+	}
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	payload, err := h.widgetService.AgencyListings(agencyID, limit)
+	if err != nil {
+		h.sendWidgetError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		h.logger.Printf("Error encoding widget response: %v", err)
+		http.Error(w, "Failed to get widget listings", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.cacheMaxAge.Seconds())))
+	header.Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// Helper functions
+
+func (h *WidgetHandler) sendWidgetError(w http.ResponseWriter, err error) {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case strings.Contains(err.Error(), "invalid widget token"):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case strings.Contains(err.Error(), "not found"):
+		http.Error(w, "Agency not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not configured"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		h.logger.Printf("Widget error: %v", err)
+		http.Error(w, "Failed to process widget request", http.StatusInternalServerError)
+	}
+}
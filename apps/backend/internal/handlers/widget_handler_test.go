@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/middleware"
+	"realty-core/internal/service"
+)
+
+func TestWidgetHandler_AgencyListingsRequiresToken(t *testing.T) {
+	widgets := service.NewWidgetService(nil, nil, service.WidgetConfig{SigningSecret: "secret"}, log.New(io.Discard, "", 0))
+	handler := NewWidgetHandler(widgets, 5*time.Minute, log.New(io.Discard, "", 0))
+	token, err := domain.SignWidgetToken("secret", domain.WidgetTokenClaims{
+		AgencyID:  "agency-1",
+		Origins:   []string{"https://www.inmobiliaria.ec"},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	// CORS comes from the widget route of the default CORS routes
+	routes, err := middleware.ParseCORSRoutes(middleware.DefaultCORSRoutes, middleware.CORSPolicy{})
+	require.NoError(t, err)
+	cors, err := middleware.NewCORSMiddleware(middleware.CORSConfig{Routes: routes})
+	require.NoError(t, err)
+	server := cors.Handler(http.HandlerFunc(handler.AgencyListings))
+
+	serve := func(method, path, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			req.Header.Set("Access-Control-Request-Headers", WidgetTokenHeader)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodOptions, "/api/widgets/agency/agency-1/listings", "https://www.inmobiliaria.ec")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), WidgetTokenHeader)
+
+	rec = serve(http.MethodGet, "/api/widgets/agency/agency-1/listings", "https://www.inmobiliaria.ec")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.NotContains(t, rec.Header().Values("Vary"), "Origin", "every origin gets the same cacheable answer")
+
+	rec = serve(http.MethodGet, "/api/widgets/agency/agency-2/listings?token="+token, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code, "tokens only open the listings of their agency")
+
+	rec = serve(http.MethodGet, "/api/widgets/agency/agency-1/listings?token="+token, "https://scraper.example")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(http.MethodGet, "/api/widgets/agency/agency-1/listings?token="+token+"x", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
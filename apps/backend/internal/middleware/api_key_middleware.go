@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/logging"
+)
+
+// APIKeyHeader carries the key of public API requests. Keys are not accepted in the
+// query string, where they would end up in access logs and browser histories.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyIDKey holds the API key a public API request was authenticated with
+const APIKeyIDKey contextKey = "api_key_id"
+
+// APIKeyVerifier authenticates API keys, counts requests against their quotas and
+// meters the bytes sent to each key
+type APIKeyVerifier interface {
+	UseAPIKey(key string) (*domain.APIKey, domain.APIKeyQuota, error)
+	RecordBytesSent(keyID string, bytes int64)
+}
+
+// APIKeyMiddleware authenticates the requests of the public API with their API key and
+// enforces the quotas of the key's tier. Install it before the security middleware:
+// requests it authenticated skip the per-IP rate limit, since their key has a tier of
+// its own, e.g.
+//
+//	mux.Handle("/api/public/v1/", apiKeyMiddleware.RequireAPIKey(securityMiddleware.RateLimitMiddleware(publicAPI)))
+type APIKeyMiddleware struct {
+	verifier APIKeyVerifier
+	logger   *logging.Logger
+}
+
+// NewAPIKeyMiddleware creates a new API key middleware
+func NewAPIKeyMiddleware(verifier APIKeyVerifier) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		verifier: verifier,
+		logger:   logging.GetGlobalLogger(),
+	}
+}
+
+// RequireAPIKey rejects requests without a valid key with 401 and requests over the
+// quotas of their key with 429. Every response carries the remaining quotas; the bytes
+// of the responses served are metered for the key.
+func (am *APIKeyMiddleware) RequireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plaintext := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+		if plaintext == "" {
+			am.handleAPIKeyError(w, http.StatusUnauthorized, "API_KEY_REQUIRED", "An API key is required in the "+APIKeyHeader+" header")
+			return
+		}
+
+		key, quota, err := am.verifier.UseAPIKey(plaintext)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidAPIKey) {
+				am.handleAPIKeyError(w, http.StatusUnauthorized, "INVALID_API_KEY", err.Error())
+				return
+			}
+			if am.logger != nil {
+				am.logger.Error("API key check failed", err, map[string]interface{}{"url": r.URL.Path})
+			}
+			w.Header().Set("Retry-After", "5")
+			am.handleAPIKeyError(w, http.StatusServiceUnavailable, "API_KEY_CHECK_FAILED", "API keys cannot be checked right now, retry shortly")
+			return
+		}
+
+		header := w.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(quota.MinuteLimit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(quota.MinuteRemaining))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(quota.MinuteResetAt.Unix(), 10))
+		header.Set("X-Quota-Limit", strconv.FormatInt(quota.DailyLimit, 10))
+		header.Set("X-Quota-Remaining", strconv.FormatInt(quota.DailyRemaining, 10))
+		header.Set("X-Quota-Reset", strconv.FormatInt(quota.DailyResetAt.Unix(), 10))
+
+		if !quota.Allowed() {
+			retryAfter := int(time.Until(quota.RetryAt()).Seconds()) + 1
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			header.Set("Retry-After", strconv.Itoa(retryAfter))
+			if quota.Exceeded == domain.APIKeyWindowDay {
+				am.handleAPIKeyError(w, http.StatusTooManyRequests, "API_QUOTA_EXCEEDED",
+					"The daily quota of the "+quota.Tier+" tier is exhausted; it resets at 00:00 UTC")
+			} else {
+				am.handleAPIKeyError(w, http.StatusTooManyRequests, "API_RATE_LIMIT_EXCEEDED",
+					"Too many requests per minute for the "+quota.Tier+" tier")
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), APIKeyIDKey, key.ID)
+		recorder := &apiKeyResponseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		am.verifier.RecordBytesSent(key.ID, recorder.bytesWritten)
+	})
+}
+
+// apiKeyResponseRecorder counts the bytes of the responses to public API requests
+type apiKeyResponseRecorder struct {
+	http.ResponseWriter
+	bytesWritten int64
+}
+
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (rr *apiKeyResponseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+// Write counts the bytes written
+func (rr *apiKeyResponseRecorder) Write(data []byte) (int, error) {
+	n, err := rr.ResponseWriter.Write(data)
+	rr.bytesWritten += int64(n)
+	return n, err
+}
+
+// handleAPIKeyError sends an API key error
+func (am *APIKeyMiddleware) handleAPIKeyError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   http.StatusText(statusCode),
+		"message": message,
+		"code":    code,
+	})
+}
+
+// GetAPIKeyID extracts the API key of a public API request from its context, empty for
+// other requests
+func GetAPIKeyID(ctx context.Context) string {
+	if keyID, ok := ctx.Value(APIKeyIDKey).(string); ok {
+		return keyID
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+// fakeAPIKeyVerifier answers every key with the same result
+type fakeAPIKeyVerifier struct {
+	quota     domain.APIKeyQuota
+	err       error
+	bytesSent map[string]int64
+}
+
+func (f *fakeAPIKeyVerifier) UseAPIKey(key string) (*domain.APIKey, domain.APIKeyQuota, error) {
+	if f.err != nil {
+		return nil, domain.APIKeyQuota{}, f.err
+	}
+	return &domain.APIKey{ID: "key-1", Tier: f.quota.Tier}, f.quota, nil
+}
+
+func (f *fakeAPIKeyVerifier) RecordBytesSent(keyID string, bytes int64) {
+	if f.bytesSent == nil {
+		f.bytesSent = map[string]int64{}
+	}
+	f.bytesSent[keyID] += bytes
+}
+
+func serveWithAPIKey(verifier APIKeyVerifier, key string) (*httptest.ResponseRecorder, string) {
+	var keyID string
+	handler := NewAPIKeyMiddleware(verifier).RequireAPIKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyID = GetAPIKeyID(r.Context())
+		w.Write([]byte(`{"data":[]}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/public/v1/properties", nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, keyID
+}
+
+func TestAPIKeyMiddleware_AuthenticatesRequests(t *testing.T) {
+	now := time.Now()
+	verifier := &fakeAPIKeyVerifier{quota: domain.APIKeyQuota{
+		Tier: "free", MinuteLimit: 60, MinuteRemaining: 59, MinuteResetAt: now.Add(time.Minute),
+		DailyLimit: 5000, DailyRemaining: 4999, DailyResetAt: now.Add(time.Hour),
+	}}
+
+	rec, keyID := serveWithAPIKey(verifier, "rk_valid")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "key-1", keyID)
+	assert.Equal(t, "59", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "4999", rec.Header().Get("X-Quota-Remaining"))
+	assert.Equal(t, int64(len(`{"data":[]}`)), verifier.bytesSent["key-1"])
+
+	rec, keyID = serveWithAPIKey(verifier, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "API_KEY_REQUIRED")
+	assert.Empty(t, keyID)
+
+	rec, _ = serveWithAPIKey(&fakeAPIKeyVerifier{err: domain.ErrInvalidAPIKey}, "rk_revoked")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_API_KEY")
+
+	rec, _ = serveWithAPIKey(&fakeAPIKeyVerifier{err: errors.New("connection refused")}, "rk_valid")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "connection refused")
+}
+
+func TestAPIKeyMiddleware_RejectsRequestsOverQuota(t *testing.T) {
+	now := time.Now()
+	quota := domain.APIKeyQuota{
+		Tier: "free", MinuteLimit: 60, MinuteResetAt: now.Add(20 * time.Second),
+		DailyLimit: 5000, DailyResetAt: now.Add(2 * time.Hour), Exceeded: domain.APIKeyWindowMinute,
+	}
+
+	rec, keyID := serveWithAPIKey(&fakeAPIKeyVerifier{quota: quota}, "rk_valid")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "API_RATE_LIMIT_EXCEEDED")
+	assert.Contains(t, []string{"20", "21"}, rec.Header().Get("Retry-After"))
+	assert.Empty(t, keyID)
+
+	quota.Exceeded = domain.APIKeyWindowDay
+	rec, _ = serveWithAPIKey(&fakeAPIKeyVerifier{quota: quota}, "rk_valid")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "API_QUOTA_EXCEEDED")
+	assert.Contains(t, []string{"7200", "7201"}, rec.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware_SkipsAPIKeyRequests(t *testing.T) {
+	sm := NewSecurityMiddleware()
+	defer sm.Stop()
+	sm.SetRateLimit(1)
+
+	served := 0
+	handler := NewAPIKeyMiddleware(&fakeAPIKeyVerifier{quota: domain.APIKeyQuota{Tier: "partner"}}).
+		RequireAPIKey(sm.RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served++
+		})))
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/public/v1/properties", nil)
+		req.Header.Set(APIKeyHeader, "rk_valid")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, 5, served)
+
+	// Without a key the same client is limited by IP
+	limited := false
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		sm.RateLimitMiddleware(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+		limited = limited || rec.Code == http.StatusTooManyRequests
+	}
+	assert.True(t, limited)
+}
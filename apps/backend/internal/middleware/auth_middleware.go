@@ -16,6 +16,13 @@ type AuthMiddleware struct {
 	authManager  *auth.AuthorizationManager
 	logger       *logging.Logger
 	skipPaths    map[string]bool
+	sessions     SessionChecker
+}
+
+// SessionChecker verifies that the session an access token was issued for has not
+// been revoked
+type SessionChecker interface {
+	IsActive(sessionID, ipAddress string) (bool, error)
 }
 
 // NewAuthMiddleware creates a new authentication middleware
@@ -31,6 +38,9 @@ func NewAuthMiddleware(jwtManager *auth.JWTManager, authManager *auth.Authorizat
 		"/api/monitoring/prometheus":     true,
 		"/api/auth/login":                true,
 		"/api/auth/refresh":              true,
+		"/api/auth/email-change/confirm": true, // Authorized by the emailed token
+		"/api/auth/email-change/cancel":  true,
+		"/api/security/csp-report":       true, // Sent by browsers, without credentials
 		"/api/properties":                true, // Public property listing
 		"/api/properties/filter":         true, // Public property search
 		"/api/properties/search/ranked":  true, // Public search
@@ -46,6 +56,12 @@ func NewAuthMiddleware(jwtManager *auth.JWTManager, authManager *auth.Authorizat
 	}
 }
 
+// SetSessionChecker rejects access tokens of revoked sessions. Tokens without a
+// session are accepted until they expire.
+func (am *AuthMiddleware) SetSessionChecker(sessions SessionChecker) {
+	am.sessions = sessions
+}
+
 // contextKey is used for context values
 type contextKey string
 
@@ -54,6 +70,9 @@ const (
 	EmailKey    contextKey = "email"
 	RoleKey     contextKey = "role"
 	AgencyIDKey contextKey = "agency_id"
+	SessionIDKey contextKey = "session_id"
+	// TokenTenantIDKey holds the tenant the access token was issued in
+	TokenTenantIDKey contextKey = "token_tenant_id"
 )
 
 // Authenticate provides basic JWT authentication
@@ -65,8 +84,9 @@ func (am *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		// Skip authentication for GET requests to public endpoints
-		if r.Method == http.MethodGet && am.isPublicReadEndpoint(r.URL.Path) {
+		// Skip authentication for GET and HEAD requests to public endpoints, and their
+		// CORS preflights, which never carry credentials
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions) && am.isPublicReadEndpoint(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -87,12 +107,38 @@ func (am *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		// Reject tokens of revoked sessions; the check fails open so an unavailable
+		// database does not log everyone out
+		if am.sessions != nil && tokenInfo.SessionID != "" {
+			active, err := am.sessions.IsActive(tokenInfo.SessionID, getClientIP(r))
+			if err != nil && am.logger != nil {
+				am.logger.Warn("Session check failed", map[string]interface{}{
+					"session_id": tokenInfo.SessionID,
+					"error":      err.Error(),
+				})
+			}
+			if err == nil && !active {
+				am.handleAuthError(w, "session has been revoked", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		// Tokens are only valid in the tenant they were issued in; when the tenant is
+		// resolved after authentication TenantMiddleware runs the same check
+		if tenantID := GetTenantID(r.Context()); tenantID != "" && tokenInfo.TenantID != tenantID {
+			am.handleAuthError(w, "token was issued for another tenant", http.StatusForbidden)
+			return
+		}
+
 		// Add user info to context
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, UserIDKey, tokenInfo.UserID)
 		ctx = context.WithValue(ctx, EmailKey, tokenInfo.Email)
 		ctx = context.WithValue(ctx, RoleKey, tokenInfo.Role)
 		ctx = context.WithValue(ctx, AgencyIDKey, tokenInfo.AgencyID)
+		ctx = context.WithValue(ctx, SessionIDKey, tokenInfo.SessionID)
+		ctx = context.WithValue(ctx, TokenTenantIDKey, tokenInfo.TenantID)
+		setMetricsAgency(ctx, tokenInfo.AgencyID)
 
 		// Log authentication
 		if am.logger != nil {
@@ -218,6 +264,10 @@ func (am *AuthMiddleware) isPublicReadEndpoint(path string) bool {
 		"/api/images/",
 		"/api/agencies",
 		"/api/agencies/",
+		"/api/public/", // Authenticated by API key
+		"/api/widgets/", // Authenticated by embed token
+		"/api/feeds/",
+		"/uploads/images/", // Stored image files, served under IMAGE_SERVE_PREFIX
 	}
 
 	for _, publicPath := range publicPaths {
@@ -226,6 +276,11 @@ func (am *AuthMiddleware) isPublicReadEndpoint(path string) bool {
 		}
 	}
 
+	// Agent calendar feeds are authenticated by their calendar token
+	if strings.HasPrefix(path, "/api/agents/") && strings.HasSuffix(path, "/calendar.ics") {
+		return true
+	}
+
 	return false
 }
 
@@ -300,6 +355,14 @@ func GetAgencyID(ctx context.Context) string {
 	return ""
 }
 
+// GetSessionID extracts the session of the access token from request context
+func GetSessionID(ctx context.Context) string {
+	if sessionID, ok := ctx.Value(SessionIDKey).(string); ok {
+		return sessionID
+	}
+	return ""
+}
+
 // ExtractResourceID helper functions for common patterns
 
 // ExtractPropertyID extracts property ID from URL path
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/auth"
+	"realty-core/internal/captcha"
+	"realty-core/internal/logging"
+)
+
+// CaptchaTokenHeader carries the token of the CAPTCHA the client solved
+const CaptchaTokenHeader = "X-Captcha-Token"
+
+// CaptchaMiddleware requires a solved CAPTCHA on public write endpoints. Each
+// endpoint is enforced only when enabled, and users authenticated with a trusted
+// role skip the challenge.
+type CaptchaMiddleware struct {
+	verifier    captcha.Verifier
+	endpoints   map[string]bool
+	bypassRoles map[string]bool
+	jwtManager  *auth.JWTManager
+	logger      *logging.Logger
+}
+
+// NewCaptchaMiddleware creates a CAPTCHA middleware. A nil verifier disables every
+// endpoint.
+func NewCaptchaMiddleware(verifier captcha.Verifier, endpoints, bypassRoles []string) *CaptchaMiddleware {
+	cm := &CaptchaMiddleware{
+		verifier:    verifier,
+		endpoints:   map[string]bool{},
+		bypassRoles: map[string]bool{},
+		logger:      logging.GetGlobalLogger(),
+	}
+	for _, endpoint := range endpoints {
+		cm.endpoints[strings.TrimSpace(endpoint)] = true
+	}
+	for _, role := range bypassRoles {
+		cm.bypassRoles[strings.TrimSpace(role)] = true
+	}
+	return cm
+}
+
+// SetTokenValidator lets the middleware recognize trusted users on public endpoints,
+// where the authentication middleware does not read the bearer token
+func (cm *CaptchaMiddleware) SetTokenValidator(jwtManager *auth.JWTManager) {
+	cm.jwtManager = jwtManager
+}
+
+// Enforced reports whether an endpoint requires a CAPTCHA
+func (cm *CaptchaMiddleware) Enforced(endpoint string) bool {
+	return cm.verifier != nil && cm.endpoints[endpoint]
+}
+
+// Protect requires a CAPTCHA on an endpoint, e.g.
+//
+//	mux.Handle("/api/users", captchaMiddleware.Protect(captcha.EndpointRegistration)(createUser))
+func (cm *CaptchaMiddleware) Protect(endpoint string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cm.Enforced(endpoint) || cm.bypassRoles[cm.role(r)] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := strings.TrimSpace(r.Header.Get(CaptchaTokenHeader))
+			if token == "" {
+				cm.handleCaptchaError(w, http.StatusBadRequest, "CAPTCHA_REQUIRED", "A CAPTCHA token is required in the "+CaptchaTokenHeader+" header")
+				return
+			}
+
+			clientIP := getClientIP(r)
+			result, err := cm.verifier.Verify(token, clientIP)
+			if err != nil {
+				if cm.logger != nil {
+					cm.logger.Error("CAPTCHA verification failed", err, map[string]interface{}{
+						"provider": cm.verifier.Name(),
+						"endpoint": endpoint,
+					})
+				}
+				cm.handleCaptchaError(w, http.StatusServiceUnavailable, "CAPTCHA_UNAVAILABLE", "CAPTCHA could not be verified, please try again")
+				return
+			}
+			if !result.Success {
+				if cm.logger != nil {
+					cm.logger.SecurityEvent(
+						"CAPTCHA Failed",
+						"",
+						"Request with an invalid CAPTCHA",
+						map[string]interface{}{
+							"client_ip":   clientIP,
+							"endpoint":    endpoint,
+							"error_codes": result.ErrorCodes,
+						},
+					)
+				}
+				cm.handleCaptchaError(w, http.StatusForbidden, "CAPTCHA_FAILED", "CAPTCHA verification failed")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// role returns the role of the authenticated user, reading the bearer token when the
+// authentication middleware skipped the request
+func (cm *CaptchaMiddleware) role(r *http.Request) string {
+	if role := GetUserRole(r.Context()); role != "" {
+		return role
+	}
+	if cm.jwtManager == nil {
+		return ""
+	}
+	token := auth.ExtractTokenFromHeader(r.Header.Get("Authorization"))
+	if token == "" {
+		return ""
+	}
+	if info := cm.jwtManager.ParseTokenInfo(token); info.IsValid {
+		return info.Role
+	}
+	return ""
+}
+
+// handleCaptchaError writes a CAPTCHA error response
+func (cm *CaptchaMiddleware) handleCaptchaError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   "CAPTCHA verification required",
+		"message": message,
+		"code":    code,
+	})
+}
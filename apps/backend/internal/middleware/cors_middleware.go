@@ -0,0 +1,384 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCORSMaxAge is how long browsers cache preflight responses; Chromium caps it
+// at two hours
+const DefaultCORSMaxAge = 2 * time.Hour
+
+// Default CORS method and header sets
+var (
+	DefaultCORSMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+	DefaultCORSHeaders = []string{
+		"Accept", "Accept-Language", "Authorization", "Content-Type", "If-None-Match", "X-Tenant-ID",
+		APIKeyHeader, CaptchaTokenHeader, PageTokenHeader,
+	}
+	DefaultCORSExposedHeaders = []string{
+		"Content-Disposition", "ETag", "Retry-After", NextPageTokenHeader,
+	}
+)
+
+// DefaultCORSRoutes opens the public read-only routes, authenticated by API key or embed
+// token rather than credentials, to any site. See ParseCORSRoutes.
+const DefaultCORSRoutes = "/api/public/=origins:*|methods:GET,HEAD,OPTIONS|credentials:;" +
+	"/api/widgets/=origins:*|methods:GET,OPTIONS|headers:X-Widget-Token|credentials:;" +
+	"/api/feeds/=origins:*|methods:GET,HEAD,OPTIONS|credentials:"
+
+// CORSPolicy is what cross-origin requests a route accepts
+type CORSPolicy struct {
+	// Origins allowed, as scheme://host[:port], https://*.example.com for any subdomain,
+	// or * for any origin
+	Origins []string
+	// CredentialOrigins are the allowed origins that may send cookies and HTTP auth;
+	// wildcard subdomains are accepted but not *
+	CredentialOrigins []string
+	Methods           []string
+	Headers           []string // request headers allowed besides the CORS-safelisted ones
+	ExposedHeaders    []string // response headers scripts may read
+	MaxAge            time.Duration
+}
+
+// CORSRoute overrides the policy of the routes under a path prefix
+type CORSRoute struct {
+	Prefix string
+	Policy CORSPolicy
+}
+
+// CORSConfig holds the default policy and its per-route overrides
+type CORSConfig struct {
+	Default CORSPolicy
+	Routes  []CORSRoute
+}
+
+// corsPolicy is a policy ready to be matched against requests
+type corsPolicy struct {
+	anyOrigin         bool
+	origins           []originPattern
+	credentialOrigins []originPattern
+	methods           map[string]bool
+	headers           map[string]bool
+	allowMethods      string
+	allowHeaders      string
+	exposeHeaders     string
+	maxAge            string
+}
+
+// originPattern is an allowed origin; host starting with "*." matches any subdomain
+type originPattern struct {
+	scheme string
+	host   string
+	port   string
+}
+
+// CORSMiddleware answers preflight requests and adds the CORS headers of the policy of
+// each route, so browsers let allowed origins read the responses
+type CORSMiddleware struct {
+	policy *corsPolicy
+	routes []corsRoute // longest prefix first
+}
+
+type corsRoute struct {
+	prefix string
+	policy *corsPolicy
+}
+
+// NewCORSMiddleware creates a CORS middleware; it fails on origins that are not valid
+// or credentials allowed for any origin
+func NewCORSMiddleware(config CORSConfig) (*CORSMiddleware, error) {
+	policy, err := compileCORSPolicy(config.Default)
+	if err != nil {
+		return nil, err
+	}
+	m := &CORSMiddleware{policy: policy}
+	for _, route := range config.Routes {
+		compiled, err := compileCORSPolicy(route.Policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS route %s: %w", route.Prefix, err)
+		}
+		m.routes = append(m.routes, corsRoute{prefix: route.Prefix, policy: compiled})
+	}
+	sort.SliceStable(m.routes, func(i, j int) bool {
+		return len(m.routes[i].prefix) > len(m.routes[j].prefix)
+	})
+	return m, nil
+}
+
+// Handler applies the CORS policy of the route of each request. Preflight requests are
+// answered here, with 403 when the origin, method or headers are not allowed; other
+// requests from origins that are not allowed are served without CORS headers, so the
+// browser keeps the response from the page.
+func (m *CORSMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy := m.policyFor(r.URL.Path)
+		header := w.Header()
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && origin != "" && r.Header.Get("Access-Control-Request-Method") != ""
+
+		// Responses depend on the origin unless every origin gets the same answer
+		if !policy.anyOrigin || len(policy.credentialOrigins) > 0 {
+			addVary(header, "Origin")
+		}
+		if preflight {
+			addVary(header, "Access-Control-Request-Method", "Access-Control-Request-Headers")
+			m.preflight(w, r, policy, origin)
+			return
+		}
+
+		if origin != "" && policy.allowsOrigin(origin) {
+			policy.setOrigin(header, origin)
+			if policy.exposeHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers a preflight request
+func (m *CORSMiddleware) preflight(w http.ResponseWriter, r *http.Request, policy *corsPolicy, origin string) {
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	if !policy.allowsOrigin(origin) || !policy.methods[method] {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	for _, requested := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		requested = strings.ToLower(strings.TrimSpace(requested))
+		if requested != "" && !policy.headers[requested] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	header := w.Header()
+	policy.setOrigin(header, origin)
+	header.Set("Access-Control-Allow-Methods", policy.allowMethods)
+	if policy.allowHeaders != "" {
+		header.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+	}
+	header.Set("Access-Control-Max-Age", policy.maxAge)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// policyFor returns the policy of the longest route prefix matching path
+func (m *CORSMiddleware) policyFor(path string) *corsPolicy {
+	for _, route := range m.routes {
+		if strings.HasPrefix(path, route.prefix) {
+			return route.policy
+		}
+	}
+	return m.policy
+}
+
+// setOrigin allows an origin, with credentials when the policy lets it send them
+func (p *corsPolicy) setOrigin(header http.Header, origin string) {
+	if matchOrigin(p.credentialOrigins, origin) {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Allow-Credentials", "true")
+		return
+	}
+	if p.anyOrigin {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", origin)
+}
+
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	return p.anyOrigin || matchOrigin(p.origins, origin) || matchOrigin(p.credentialOrigins, origin)
+}
+
+// compileCORSPolicy validates a policy and fills in the defaults of its empty sets
+func compileCORSPolicy(policy CORSPolicy) (*corsPolicy, error) {
+	compiled := &corsPolicy{
+		methods: map[string]bool{},
+		headers: map[string]bool{},
+	}
+	for _, origin := range policy.Origins {
+		if origin = strings.TrimSpace(origin); origin == "*" {
+			compiled.anyOrigin = true
+			continue
+		}
+		pattern, err := parseOriginPattern(origin)
+		if err != nil {
+			return nil, err
+		}
+		compiled.origins = append(compiled.origins, pattern)
+	}
+	for _, origin := range policy.CredentialOrigins {
+		if origin = strings.TrimSpace(origin); origin == "*" {
+			return nil, fmt.Errorf("invalid credential origin: credentials cannot be allowed for any origin")
+		}
+		pattern, err := parseOriginPattern(origin)
+		if err != nil {
+			return nil, err
+		}
+		compiled.credentialOrigins = append(compiled.credentialOrigins, pattern)
+	}
+
+	methods := policy.Methods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	var allowMethods []string
+	for _, method := range methods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method != "" && !compiled.methods[method] {
+			compiled.methods[method] = true
+			allowMethods = append(allowMethods, method)
+		}
+	}
+	compiled.allowMethods = strings.Join(allowMethods, ", ")
+
+	headers := policy.Headers
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
+	}
+	var allowHeaders []string
+	for _, name := range headers {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if name != "" && !compiled.headers[strings.ToLower(name)] {
+			compiled.headers[strings.ToLower(name)] = true
+			allowHeaders = append(allowHeaders, name)
+		}
+	}
+	compiled.allowHeaders = strings.Join(allowHeaders, ", ")
+
+	exposed := policy.ExposedHeaders
+	if exposed == nil {
+		exposed = DefaultCORSExposedHeaders
+	}
+	compiled.exposeHeaders = strings.Join(exposed, ", ")
+
+	maxAge := policy.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultCORSMaxAge
+	}
+	compiled.maxAge = strconv.Itoa(int(maxAge.Seconds()))
+	return compiled, nil
+}
+
+// parseOriginPattern parses scheme://host[:port], where host may start with "*." for
+// any subdomain
+func parseOriginPattern(origin string) (originPattern, error) {
+	scheme, rest, found := strings.Cut(strings.ToLower(origin), "://")
+	if !found || (scheme != "http" && scheme != "https") || rest == "" || strings.ContainsAny(rest, "/?#@") {
+		return originPattern{}, fmt.Errorf("invalid origin %q: expected scheme://host[:port]", origin)
+	}
+	host, port := rest, ""
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.HasSuffix(rest, "]") {
+		host, port = rest[:i], rest[i+1:]
+		if _, err := strconv.Atoi(port); err != nil {
+			return originPattern{}, fmt.Errorf("invalid origin %q: bad port", origin)
+		}
+	}
+	if strings.Contains(strings.TrimPrefix(host, "*."), "*") || host == "*." {
+		return originPattern{}, fmt.Errorf("invalid origin %q: only a leading *. wildcard is supported", origin)
+	}
+	return originPattern{scheme: scheme, host: host, port: port}, nil
+}
+
+// matchOrigin reports whether an Origin header matches one of the patterns
+func matchOrigin(patterns []originPattern, origin string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	parsed, err := url.Parse(strings.ToLower(origin))
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	host, port := parsed.Hostname(), parsed.Port()
+	for _, pattern := range patterns {
+		if pattern.scheme != parsed.Scheme || pattern.port != port {
+			continue
+		}
+		if suffix, wildcard := strings.CutPrefix(pattern.host, "*"); wildcard {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if pattern.host == host {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary adds values to the Vary header unless already listed
+func addVary(header http.Header, values ...string) {
+	listed := map[string]bool{}
+	for _, line := range header.Values("Vary") {
+		for _, value := range strings.Split(line, ",") {
+			listed[strings.ToLower(strings.TrimSpace(value))] = true
+		}
+	}
+	for _, value := range values {
+		if !listed[strings.ToLower(value)] {
+			header.Add("Vary", value)
+			listed[strings.ToLower(value)] = true
+		}
+	}
+}
+
+// ParseCORSRoutes parses per-route policies separated by semicolons as
+// "prefix=key:value|key:value", where keys are origins, credentials, methods, headers,
+// expose (comma separated lists) and max_age (a duration). Unset keys inherit from the
+// default policy; an empty value clears a list, e.g. "credentials:" allows none.
+// Example: "/api/admin/=origins:https://admin.realty.ec|credentials:https://admin.realty.ec|methods:GET,POST"
+func ParseCORSRoutes(spec string, defaults CORSPolicy) ([]CORSRoute, error) {
+	routes := []CORSRoute{}
+	for _, entry := range strings.Split(spec, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		prefix, definition, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid CORS route %q: expected /prefix=key:value|...", entry)
+		}
+
+		policy := defaults
+		for _, setting := range strings.Split(definition, "|") {
+			key, value, ok := strings.Cut(strings.TrimSpace(setting), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid CORS route %s: expected key:value, got %q", prefix, setting)
+			}
+			var list []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			switch strings.TrimSpace(key) {
+			case "origins":
+				policy.Origins = list
+			case "credentials":
+				policy.CredentialOrigins = list
+			case "methods":
+				policy.Methods = list
+			case "headers":
+				policy.Headers = list
+			case "expose":
+				policy.ExposedHeaders = append([]string{}, list...)
+			case "max_age":
+				maxAge, err := time.ParseDuration(strings.TrimSpace(value))
+				if err != nil || maxAge <= 0 {
+					return nil, fmt.Errorf("invalid CORS route %s: max_age must be a positive duration", prefix)
+				}
+				policy.MaxAge = maxAge
+			default:
+				return nil, fmt.Errorf("invalid CORS route %s: unknown key %q", prefix, key)
+			}
+		}
+		routes = append(routes, CORSRoute{Prefix: prefix, Policy: policy})
+	}
+	return routes, nil
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCORS(t *testing.T, routes string) http.Handler {
+	t.Helper()
+	policy := CORSPolicy{
+		Origins:           []string{"https://realty.ec", "https://*.partners.ec"},
+		CredentialOrigins: []string{"https://admin.realty.ec"},
+		MaxAge:            10 * time.Minute,
+	}
+	parsed, err := ParseCORSRoutes(routes, policy)
+	require.NoError(t, err)
+	cors, err := NewCORSMiddleware(CORSConfig{Default: policy, Routes: parsed})
+	require.NoError(t, err)
+	return cors.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func corsRequest(method, path, origin string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	return req
+}
+
+func TestCORSMiddleware_Origins(t *testing.T) {
+	handler := newTestCORS(t, "")
+
+	tests := []struct {
+		origin      string
+		allowed     bool
+		credentials bool
+	}{
+		{"https://realty.ec", true, false},
+		{"https://admin.realty.ec", true, true},
+		{"https://quito.partners.ec", true, false},
+		{"https://a.b.partners.ec", true, false},
+		{"https://partners.ec", false, false},
+		{"http://quito.partners.ec", false, false},
+		{"https://quito.partners.ec:8443", false, false},
+		{"https://evilpartners.ec", false, false},
+		{"https://realty.ec.evil.com", false, false},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, corsRequest(http.MethodGet, "/api/properties", tt.origin))
+		assert.Equal(t, http.StatusOK, recorder.Code, tt.origin)
+		if tt.allowed {
+			assert.Equal(t, tt.origin, recorder.Header().Get("Access-Control-Allow-Origin"), tt.origin)
+			assert.Contains(t, recorder.Header().Get("Access-Control-Expose-Headers"), "ETag")
+		} else {
+			assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"), tt.origin)
+		}
+		assert.Equal(t, tt.credentials, recorder.Header().Get("Access-Control-Allow-Credentials") == "true", tt.origin)
+		assert.Equal(t, []string{"Origin", "Accept-Language"}, recorder.Header().Values("Vary"), tt.origin)
+	}
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	handler := newTestCORS(t, "")
+
+	req := corsRequest(http.MethodOptions, "/api/properties/123", "https://admin.realty.ec")
+	req.Header.Set("Access-Control-Request-Method", "PATCH")
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://admin.realty.ec", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, recorder.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+	assert.Contains(t, recorder.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	assert.Equal(t, "600", recorder.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, recorder.Header().Values("Vary"))
+
+	// Headers that are not allowed and unknown origins are refused
+	req.Header.Set("Access-Control-Request-Headers", "X-Debug")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+	req = corsRequest(http.MethodOptions, "/api/properties/123", "https://evil.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	// OPTIONS requests that are not preflights reach the handler
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, corsRequest(http.MethodOptions, "/api/properties", ""))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCORSMiddleware_RouteOverrides(t *testing.T) {
+	handler := newTestCORS(t, DefaultCORSRoutes+";/api/admin/=credentials:https://admin.realty.ec|origins:|methods:GET,POST|max_age:1m")
+
+	// Public routes answer any origin without credentials and only read methods
+	req := corsRequest(http.MethodOptions, "/api/public/properties", "https://anywhere.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "X-API-Key")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, HEAD, OPTIONS", recorder.Header().Get("Access-Control-Allow-Methods"))
+
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+	// Same answer for every origin, so responses do not vary by it
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, corsRequest(http.MethodGet, "/api/public/properties", "https://admin.realty.ec"))
+	assert.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, []string{"Accept-Language"}, recorder.Header().Values("Vary"))
+
+	// Admin routes only answer the admin origin
+	req = corsRequest(http.MethodOptions, "/api/admin/users", "https://admin.realty.ec")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "GET, POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "60", recorder.Header().Get("Access-Control-Max-Age"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, corsRequest(http.MethodGet, "/api/admin/users", "https://realty.ec"))
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestNewCORSMiddleware_InvalidPolicies(t *testing.T) {
+	_, err := NewCORSMiddleware(CORSConfig{Default: CORSPolicy{CredentialOrigins: []string{"*"}}})
+	assert.ErrorContains(t, err, "credentials cannot be allowed for any origin")
+
+	for _, origin := range []string{"realty.ec", "ftp://realty.ec", "https://realty.ec/path", "https://*", "https://api.*.realty.ec"} {
+		_, err := NewCORSMiddleware(CORSConfig{Default: CORSPolicy{Origins: []string{origin}}})
+		assert.Error(t, err, origin)
+	}
+}
+
+func TestParseCORSRoutes(t *testing.T) {
+	defaults := CORSPolicy{Origins: []string{"https://realty.ec"}, Methods: []string{"GET"}}
+	routes, err := ParseCORSRoutes(" /api/feeds/=origins:*|expose: ; ", defaults)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.Equal(t, "/api/feeds/", routes[0].Prefix)
+	assert.Equal(t, []string{"*"}, routes[0].Policy.Origins)
+	assert.Equal(t, []string{"GET"}, routes[0].Policy.Methods)
+	assert.NotNil(t, routes[0].Policy.ExposedHeaders)
+	assert.Empty(t, routes[0].Policy.ExposedHeaders)
+
+	for _, spec := range []string{"api/=origins:*", "/api/=origins", "/api/=colors:red", "/api/=max_age:soon"} {
+		_, err := ParseCORSRoutes(spec, defaults)
+		assert.Error(t, err, spec)
+	}
+}
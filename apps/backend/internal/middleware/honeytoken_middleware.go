@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/security"
+)
+
+// honeytokenDetailPrefixes are the property detail endpoints, followed by the id or slug
+var honeytokenDetailPrefixes = []string{"/api/properties/slug/", "/api/public/v1/properties/", "/api/properties/"}
+
+// HoneytokenRecorder finds canary listings and records the requests for them
+type HoneytokenRecorder interface {
+	Lookup(idOrSlug string) (*domain.Honeytoken, bool)
+	RecordHit(token *domain.Honeytoken, hit domain.HoneytokenHit)
+}
+
+// HoneytokenMiddleware records the requests for the detail of canary listings, with
+// what identifies their client
+type HoneytokenMiddleware struct {
+	recorder HoneytokenRecorder
+}
+
+// NewHoneytokenMiddleware creates a new honeytoken middleware
+func NewHoneytokenMiddleware(recorder HoneytokenRecorder) *HoneytokenMiddleware {
+	return &HoneytokenMiddleware{recorder: recorder}
+}
+
+// Middleware records hits on canaries and lets every request through, so the canary is
+// served like any listing and the client is not tipped off
+func (hm *HoneytokenMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			if key := honeytokenDetailKey(r.URL.Path); key != "" {
+				if token, ok := hm.recorder.Lookup(key); ok {
+					ctx := r.Context()
+					clientIP := getClientIP(r)
+					hm.recorder.RecordHit(token, domain.HoneytokenHit{
+						Path:        r.URL.Path,
+						ClientIP:    clientIP,
+						UserAgent:   r.UserAgent(),
+						Referer:     r.Referer(),
+						Fingerprint: security.RequestFingerprint(clientIP),
+						APIKeyID:    GetAPIKeyID(ctx),
+						UserID:      GetUserID(ctx),
+					})
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// honeytokenDetailKey returns the listing id or slug of a property detail path, or ""
+// for other paths
+func honeytokenDetailKey(path string) string {
+	path = strings.TrimSuffix(path, "/")
+	for _, prefix := range honeytokenDetailPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			key := strings.TrimPrefix(path, prefix)
+			if key == "" || strings.Contains(key, "/") {
+				return ""
+			}
+			return key
+		}
+	}
+	return ""
+}
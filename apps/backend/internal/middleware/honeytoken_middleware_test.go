@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// fakeHoneytokens watches a single canary listing
+type fakeHoneytokens struct {
+	token *domain.Honeytoken
+	hits  []domain.HoneytokenHit
+}
+
+func (f *fakeHoneytokens) Lookup(idOrSlug string) (*domain.Honeytoken, bool) {
+	return f.token, idOrSlug == f.token.Listing.ID || idOrSlug == f.token.Listing.Slug
+}
+
+func (f *fakeHoneytokens) RecordHit(token *domain.Honeytoken, hit domain.HoneytokenHit) {
+	f.hits = append(f.hits, hit)
+}
+
+func TestHoneytokenMiddleware_RecordsDetailRequests(t *testing.T) {
+	recorder := &fakeHoneytokens{token: &domain.Honeytoken{ID: "t-1", Listing: domain.Property{ID: "canary-id", Slug: "casa-canary"}}}
+	served := 0
+	server := NewHoneytokenMiddleware(recorder).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	}))
+	get := func(method, path string) int {
+		r := httptest.NewRequest(method, path, nil)
+		r.RemoteAddr = "203.0.113.7:4000"
+		r.Header.Set("User-Agent", "python-requests/2.31")
+		r.Header.Set("Referer", "https://copy.example/casa")
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get(http.MethodGet, "/api/properties/canary-id"), "canaries are served like any listing")
+	get(http.MethodGet, "/api/properties/slug/casa-canary")
+	get(http.MethodGet, "/api/public/v1/properties/canary-id/")
+	get(http.MethodGet, "/api/properties/real-id")
+	get(http.MethodGet, "/api/properties/canary-id/images")
+	get(http.MethodDelete, "/api/properties/canary-id")
+
+	assert.Equal(t, 6, served)
+	require.Len(t, recorder.hits, 3)
+	assert.Equal(t, "/api/properties/slug/casa-canary", recorder.hits[1].Path)
+	assert.Equal(t, "203.0.113.7", recorder.hits[0].ClientIP)
+	assert.Equal(t, "python-requests/2.31", recorder.hits[0].UserAgent)
+	assert.Equal(t, "https://copy.example/casa", recorder.hits[0].Referer)
+	assert.NotEmpty(t, recorder.hits[0].Fingerprint)
+}
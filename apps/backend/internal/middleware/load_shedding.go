@@ -0,0 +1,259 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/logging"
+	"realty-core/internal/monitoring"
+)
+
+// Default load shedding limits
+const (
+	DefaultMaxConcurrentRequests = 256
+	DefaultQueueTimeout          = 2 * time.Second
+)
+
+// DefaultPriorityRoutes are never shed: health checks keep the instance in the load
+// balancer and auth routes keep users able to log in during a spike
+var DefaultPriorityRoutes = []string{"/api/health", "/api/auth/"}
+
+// RouteLimit bounds the requests served at once on the routes under a path prefix.
+// Requests past the limit wait up to QueueTimeout for a slot, the load shedder's queue
+// timeout when unset; MaxQueue bounds how many wait, so a spike is answered at once
+// rather than after the timeout.
+type RouteLimit struct {
+	Prefix        string
+	MaxConcurrent int
+	MaxQueue      int
+	QueueTimeout  time.Duration
+}
+
+// routeLimiter holds the slots of a route limit
+type routeLimiter struct {
+	limit   RouteLimit
+	slots   chan struct{}
+	mutex   sync.Mutex
+	waiting int
+}
+
+// newRouteLimiter creates the slots of a limit, waiting queueTimeout when the limit has
+// no timeout of its own
+func newRouteLimiter(limit RouteLimit, queueTimeout time.Duration) *routeLimiter {
+	if limit.QueueTimeout <= 0 {
+		limit.QueueTimeout = queueTimeout
+	}
+	if limit.QueueTimeout <= 0 {
+		limit.QueueTimeout = DefaultQueueTimeout
+	}
+	if limit.MaxQueue <= 0 {
+		limit.MaxQueue = limit.MaxConcurrent
+	}
+	return &routeLimiter{limit: limit, slots: make(chan struct{}, limit.MaxConcurrent)}
+}
+
+// acquire takes a slot, waiting in the queue up to the queue timeout, and reports
+// whether it got one
+func (rl *routeLimiter) acquire(r *http.Request) bool {
+	select {
+	case rl.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	rl.mutex.Lock()
+	if rl.waiting >= rl.limit.MaxQueue {
+		rl.mutex.Unlock()
+		return false
+	}
+	rl.waiting++
+	rl.mutex.Unlock()
+	defer func() {
+		rl.mutex.Lock()
+		rl.waiting--
+		rl.mutex.Unlock()
+	}()
+
+	timer := time.NewTimer(rl.limit.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case rl.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (rl *routeLimiter) release() {
+	<-rl.slots
+}
+
+// LoadShedder limits the requests served at once per route so a spike on expensive
+// endpoints, such as image uploads exhausting database connections, is answered early
+// with 503 and Retry-After instead of timing everything out. Routes without a limit of
+// their own share the default limit; priority routes are never shed.
+type LoadShedder struct {
+	mutex        sync.RWMutex
+	routes       []*routeLimiter // longest prefix first
+	fallback     *routeLimiter
+	queueTimeout time.Duration
+	priority     []string
+	retryAfter   time.Duration
+	logger       *logging.Logger
+}
+
+// NewLoadShedder creates a load shedder sharing maxConcurrent slots among the routes
+// without a limit of their own
+func NewLoadShedder(maxConcurrent int, queueTimeout time.Duration) *LoadShedder {
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrentRequests
+	}
+	return &LoadShedder{
+		fallback:     newRouteLimiter(RouteLimit{Prefix: "/", MaxConcurrent: maxConcurrent}, queueTimeout),
+		queueTimeout: queueTimeout,
+		priority:     DefaultPriorityRoutes,
+		retryAfter:   5 * time.Second,
+		logger:       logging.GetGlobalLogger(),
+	}
+}
+
+// SetRouteLimits replaces the limits of specific routes. The longest matching prefix
+// applies; limits without a queue timeout use the load shedder's.
+func (ls *LoadShedder) SetRouteLimits(limits []RouteLimit) {
+	routes := make([]*routeLimiter, 0, len(limits))
+	for _, limit := range limits {
+		if limit.MaxConcurrent > 0 && limit.Prefix != "" {
+			routes = append(routes, newRouteLimiter(limit, ls.queueTimeout))
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].limit.Prefix) > len(routes[j].limit.Prefix)
+	})
+
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+	ls.routes = routes
+}
+
+// SetPriorityRoutes replaces the routes that are never shed, matched like route limits
+func (ls *LoadShedder) SetPriorityRoutes(prefixes []string) {
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+	ls.priority = prefixes
+}
+
+// SetRetryAfter changes how long shed clients are told to wait
+func (ls *LoadShedder) SetRetryAfter(retryAfter time.Duration) {
+	if retryAfter > 0 {
+		ls.retryAfter = retryAfter
+	}
+}
+
+// Middleware sheds requests past the concurrency limit of their route
+func (ls *LoadShedder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := ls.limiterFor(r.URL.Path)
+		if limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !limiter.acquire(r) {
+			ls.handleShed(w, r, limiter.limit.Prefix)
+			return
+		}
+		defer limiter.release()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limiterFor returns the limiter of a path, nil for priority routes
+func (ls *LoadShedder) limiterFor(path string) *routeLimiter {
+	ls.mutex.RLock()
+	defer ls.mutex.RUnlock()
+
+	for _, prefix := range ls.priority {
+		if matchesRoute(path, prefix) {
+			return nil
+		}
+	}
+	for _, route := range ls.routes {
+		if matchesRoute(path, route.limit.Prefix) {
+			return route
+		}
+	}
+	return ls.fallback
+}
+
+// matchesRoute reports whether path is the route prefix or below it: "/api/health"
+// matches itself and "/api/health/ready" but not "/api/healthcheck", and a prefix
+// ending in "/" matches everything below it
+func matchesRoute(path, prefix string) bool {
+	if strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// handleShed answers a shed request with 503 and Retry-After
+func (ls *LoadShedder) handleShed(w http.ResponseWriter, r *http.Request, prefix string) {
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter("http_requests_shed_total", "Requests rejected by load shedding").Inc()
+	}
+	if ls.logger != nil {
+		ls.logger.Warn("Request shed", map[string]interface{}{
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"limit":  prefix,
+		})
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(ls.retryAfter.Seconds()))))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   "Service Unavailable",
+		"message": "The server is busy. Please try again later.",
+		"code":    "OVERLOADED",
+	})
+}
+
+// ParseRouteLimits parses route limits separated by semicolons as
+// "prefix=max_concurrent[:queue_timeout]", e.g. "/api/images=32:2s;/api/properties=64"
+func ParseRouteLimits(spec string) ([]RouteLimit, error) {
+	limits := []RouteLimit{}
+	for _, entry := range strings.Split(spec, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		prefix, definition, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid route limit %q: expected /prefix=max_concurrent[:queue_timeout]", entry)
+		}
+
+		maxValue, timeoutValue, hasTimeout := strings.Cut(strings.TrimSpace(definition), ":")
+		maxConcurrent, err := strconv.Atoi(maxValue)
+		if err != nil || maxConcurrent <= 0 {
+			return nil, fmt.Errorf("invalid route limit %s: max concurrent must be a positive number", prefix)
+		}
+		limit := RouteLimit{Prefix: prefix, MaxConcurrent: maxConcurrent}
+		if hasTimeout {
+			if limit.QueueTimeout, err = time.ParseDuration(timeoutValue); err != nil || limit.QueueTimeout <= 0 {
+				return nil, fmt.Errorf("invalid route limit %s: queue timeout must be a positive duration", prefix)
+			}
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
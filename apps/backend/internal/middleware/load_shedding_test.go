@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShedder_ShedsPastRouteLimit(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	shedder := NewLoadShedder(10, time.Second)
+	shedder.SetRouteLimits([]RouteLimit{{Prefix: "/api/images", MaxConcurrent: 1, MaxQueue: 1, QueueTimeout: 50 * time.Millisecond}})
+	shedder.SetRetryAfter(1500 * time.Millisecond)
+	server := shedder.Middleware(handler)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/images/batch", nil))
+	}()
+	<-started
+
+	// The queued request times out
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/images/batch", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+	assert.Contains(t, recorder.Body.String(), "OVERLOADED")
+
+	// Other routes and priority routes are not affected
+	for _, path := range []string{"/api/properties", "/api/health", "/api/auth/login"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusOK, recorder.Code, path)
+		}(path)
+		<-started
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestLoadShedder_QueueFull(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	shedder := NewLoadShedder(1, time.Minute)
+	server := shedder.Middleware(handler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+		}()
+	}
+	<-started
+	require.Eventually(t, func() bool {
+		shedder.fallback.mutex.Lock()
+		defer shedder.fallback.mutex.Unlock()
+		return shedder.fallback.waiting == 1
+	}, time.Second, time.Millisecond)
+
+	// With the queue full the request is shed at once rather than after the timeout
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Less(t, time.Since(start), time.Second)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	limits, err := ParseRouteLimits("/api/images=32:2s; /api/properties=64")
+	require.NoError(t, err)
+	assert.Equal(t, []RouteLimit{
+		{Prefix: "/api/images", MaxConcurrent: 32, QueueTimeout: 2 * time.Second},
+		{Prefix: "/api/properties", MaxConcurrent: 64},
+	}, limits)
+
+	_, err = ParseRouteLimits("/api/images=0")
+	assert.Error(t, err)
+	_, err = ParseRouteLimits("api/images=10")
+	assert.Error(t, err)
+	_, err = ParseRouteLimits("/api/images=10:soon")
+	assert.Error(t, err)
+}
+
+func TestLoadShedder_RouteMatching(t *testing.T) {
+	shedder := NewLoadShedder(10, 3*time.Second)
+	shedder.SetRouteLimits([]RouteLimit{
+		{Prefix: "/api/images", MaxConcurrent: 1},
+		{Prefix: "/api/properties", MaxConcurrent: 1, QueueTimeout: time.Second},
+	})
+
+	// Priority routes match whole path segments
+	assert.Nil(t, shedder.limiterFor("/api/health"))
+	assert.Nil(t, shedder.limiterFor("/api/health/ready"))
+	assert.Nil(t, shedder.limiterFor("/api/auth/login"))
+	assert.Equal(t, shedder.fallback, shedder.limiterFor("/api/healthcheck"))
+	assert.Equal(t, shedder.fallback, shedder.limiterFor("/api/health-report"))
+
+	images := shedder.limiterFor("/api/images/batch")
+	require.NotNil(t, images)
+	assert.Equal(t, "/api/images", images.limit.Prefix)
+	assert.Equal(t, images, shedder.limiterFor("/api/images"))
+	assert.Equal(t, shedder.fallback, shedder.limiterFor("/api/imagesets"))
+
+	// Limits without a queue timeout use the configured one, not the default
+	assert.Equal(t, 3*time.Second, images.limit.QueueTimeout)
+	assert.Equal(t, time.Second, shedder.limiterFor("/api/properties").limit.QueueTimeout)
+	assert.Equal(t, 3*time.Second, shedder.fallback.limit.QueueTimeout)
+	assert.Equal(t, DefaultQueueTimeout, NewLoadShedder(10, 0).fallback.limit.QueueTimeout)
+}
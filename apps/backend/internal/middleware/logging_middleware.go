@@ -120,6 +120,11 @@ func (rr *responseRecorder) WriteHeader(statusCode int) {
 	rr.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
 // Write captures the number of bytes written
 func (rr *responseRecorder) Write(data []byte) (int, error) {
 	n, err := rr.ResponseWriter.Write(data)
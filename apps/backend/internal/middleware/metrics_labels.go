@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"realty-core/internal/monitoring"
+)
+
+// metricsLabelsKey holds the labels of a request the monitoring middleware records
+const metricsLabelsKey contextKey = "metrics_labels"
+
+// metricsLabels collects the agency and tenant of a request for its metrics. The
+// monitoring middleware runs outside the auth and tenant middlewares, so they fill in
+// the labels it records once the request is served.
+type metricsLabels struct {
+	agency string
+	tenant string
+}
+
+// withMetricsLabels adds an empty label holder to the request context
+func withMetricsLabels(r *http.Request) (*http.Request, *metricsLabels) {
+	labels := &metricsLabels{}
+	return r.WithContext(context.WithValue(r.Context(), metricsLabelsKey, labels)), labels
+}
+
+// setMetricsAgency labels the metrics of a request with the agency it was made for
+func setMetricsAgency(ctx context.Context, agencyID string) {
+	if labels, ok := ctx.Value(metricsLabelsKey).(*metricsLabels); ok {
+		labels.agency = agencyID
+	}
+}
+
+// setMetricsTenant labels the metrics of a request with its tenant
+func setMetricsTenant(ctx context.Context, tenantID string) {
+	if labels, ok := ctx.Value(metricsLabelsKey).(*metricsLabels); ok {
+		labels.tenant = tenantID
+	}
+}
+
+// recordRequestMetrics counts a served request by route template, status class and,
+// when enabled, agency and tenant
+func recordRequestMetrics(metrics *monitoring.MetricsCollector, r *http.Request, labels *metricsLabels, statusCode int, duration time.Duration) {
+	metrics.Requests().Record(r.Method, routeTemplate(r.URL.Path), statusCode, labels.agency, labels.tenant, duration)
+}
+
+// routeTemplate turns a request path into its route template for metric labels:
+// besides the IDs sanitizePath replaces, segments with digits such as slugs, short
+// codes and dates become {id}, and file names {file}
+func routeTemplate(path string) string {
+	segments := strings.Split(sanitizePath(path), "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "" || segment == "{id}":
+		case strings.Contains(segment, "."):
+			segments[i] = "{file}"
+		case len(segment) > 3 && strings.IndexFunc(segment, unicode.IsDigit) >= 0:
+			segments[i] = "{id}"
+		case strings.ContainsAny(segment, "@%:"):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
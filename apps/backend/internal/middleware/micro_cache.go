@@ -0,0 +1,349 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/cache"
+	"realty-core/internal/domain"
+	"realty-core/internal/logging"
+	"realty-core/internal/monitoring"
+)
+
+// Micro-cache groups: the entries a write invalidates together
+const (
+	MicroCacheGroupProperties = "properties"
+	MicroCacheGroupAgencies   = "agencies"
+)
+
+// Micro-cache limits
+const (
+	MinMicroCacheTTL            = 5 * time.Second
+	MaxMicroCacheTTL            = 30 * time.Second
+	DefaultMicroCacheTTL        = 10 * time.Second
+	defaultMicroCacheMaxBody    = 1 << 20
+	microCacheHeader            = "X-Cache"
+	microCacheFlightMaxWaitTime = 10 * time.Second
+)
+
+// DefaultMicroCacheRoutes are the public list endpoints homepage traffic hits, by
+// exact path, with the group of each
+var DefaultMicroCacheRoutes = map[string]string{
+	"/api/properties":        MicroCacheGroupProperties,
+	"/api/properties/filter": MicroCacheGroupProperties,
+	"/api/agencies/active":   MicroCacheGroupAgencies,
+}
+
+// DefaultMicroCacheInvalidations are the groups a successful write under a path prefix
+// invalidates: listings change on property and image writes, and admins moderate both
+var DefaultMicroCacheInvalidations = map[string][]string{
+	"/api/properties": {MicroCacheGroupProperties},
+	"/api/images":     {MicroCacheGroupProperties},
+	"/api/agencies":   {MicroCacheGroupAgencies},
+	"/api/admin":      {MicroCacheGroupProperties, MicroCacheGroupAgencies},
+}
+
+// microCacheUnstoredHeaders are the response headers that describe one response rather
+// than the cached content
+var microCacheUnstoredHeaders = []string{"Age", "Content-Length", "Date", "Set-Cookie", microCacheHeader}
+
+// microCacheEntry is a stored response
+type microCacheEntry struct {
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body"`
+	StoredAt  time.Time   `json:"stored_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// microCacheFlight is a miss being served; concurrent misses of the same key wait for
+// it instead of all reaching the database
+type microCacheFlight struct {
+	done  chan struct{}
+	entry *microCacheEntry
+}
+
+// MicroCache caches the responses of anonymous GETs on public list endpoints for a few
+// seconds. Successful writes invalidate the groups they affect by starting a new
+// generation of them, and background jobs changing listings are bounded by the TTL.
+// Store failures fail open: the request is served uncached.
+type MicroCache struct {
+	store         cache.ResponseStore
+	ttl           time.Duration
+	routes        map[string]string
+	invalidations map[string][]string
+	maxBodyBytes  int
+	logger        *logging.Logger
+
+	flightMutex sync.Mutex
+	flights     map[string]*microCacheFlight
+}
+
+// NewMicroCache creates a micro-cache of the default routes; the TTL is clamped to
+// 5-30 seconds
+func NewMicroCache(store cache.ResponseStore, ttl time.Duration) *MicroCache {
+	if ttl < MinMicroCacheTTL {
+		ttl = MinMicroCacheTTL
+	}
+	if ttl > MaxMicroCacheTTL {
+		ttl = MaxMicroCacheTTL
+	}
+	return &MicroCache{
+		store:         store,
+		ttl:           ttl,
+		routes:        DefaultMicroCacheRoutes,
+		invalidations: DefaultMicroCacheInvalidations,
+		maxBodyBytes:  defaultMicroCacheMaxBody,
+		logger:        logging.GetGlobalLogger(),
+		flights:       make(map[string]*microCacheFlight),
+	}
+}
+
+// Invalidate starts a new generation of groups, e.g. after a write outside HTTP
+func (mc *MicroCache) Invalidate(groups ...string) {
+	for _, group := range groups {
+		if err := mc.store.Bump(group); err != nil {
+			mc.logError("Micro-cache invalidation failed", err, map[string]interface{}{"group": group})
+		}
+	}
+}
+
+// Middleware serves cached responses of anonymous GETs and invalidates them on writes
+func (mc *MicroCache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			recorder := &securityResponseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+			if recorder.statusCode < 400 {
+				mc.invalidateFor(r.URL.Path)
+			}
+			return
+		}
+
+		// Streamed NDJSON exports are for integrations, not homepage traffic
+		group, cacheable := mc.routes[r.URL.Path]
+		if !cacheable || r.Header.Get("Authorization") != "" || strings.Contains(r.Header.Get("Accept"), "ndjson") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := mc.key(group, r)
+		if err != nil {
+			mc.logError("Micro-cache lookup failed", err, map[string]interface{}{"path": r.URL.Path})
+			next.ServeHTTP(w, r)
+			return
+		}
+		if entry := mc.lookup(key); entry != nil {
+			mc.serve(w, r, entry)
+			return
+		}
+		if r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		flight, leader := mc.join(key)
+		if !leader {
+			timer := time.NewTimer(microCacheFlightMaxWaitTime)
+			defer timer.Stop()
+			select {
+			case <-flight.done:
+				if flight.entry != nil {
+					mc.serve(w, r, flight.entry)
+					return
+				}
+			case <-timer.C:
+			case <-r.Context().Done():
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer mc.land(key, flight)
+
+		recorder := &microCacheRecorder{ResponseWriter: w, statusCode: http.StatusOK, maxBody: mc.maxBodyBytes}
+		next.ServeHTTP(recorder, r)
+		countMicroCache("microcache_misses_total", "Micro-cache misses")
+
+		if !recorder.storable() {
+			return
+		}
+		now := time.Now()
+		header := recorder.Header().Clone()
+		for _, name := range microCacheUnstoredHeaders {
+			header.Del(name)
+		}
+		entry := &microCacheEntry{
+			Status:    recorder.statusCode,
+			Header:    header,
+			Body:      recorder.body.Bytes(),
+			StoredAt:  now,
+			ExpiresAt: now.Add(mc.ttl),
+		}
+		flight.entry = entry
+		data, err := json.Marshal(entry)
+		if err == nil {
+			err = mc.store.Set(key, data, mc.ttl)
+		}
+		if err != nil {
+			mc.logError("Micro-cache store failed", err, map[string]interface{}{"path": r.URL.Path})
+		}
+	})
+}
+
+// key identifies a response by group generation, host, tenant, path, query and the
+// locale listings are localized to, so visitors are not served another language
+func (mc *MicroCache) key(group string, r *http.Request) (string, error) {
+	generation, err := mc.store.Generation(group)
+	if err != nil {
+		return "", err
+	}
+	identity := strings.Join([]string{r.Host, GetTenantID(r.Context()), r.URL.Path, r.URL.Query().Encode(), microCacheLocale(r)}, "\n")
+	sum := sha256.Sum256([]byte(identity))
+	return fmt.Sprintf("%s:%d:%s", group, generation, hex.EncodeToString(sum[:16])), nil
+}
+
+// microCacheLocale negotiates the locale of an anonymous request the way localized
+// handlers do: the locale query parameter, else the Accept-Language header
+func microCacheLocale(r *http.Request) string {
+	if locale := domain.NormalizeLocale(r.URL.Query().Get("locale")); locale != "" {
+		return locale
+	}
+	return domain.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// lookup returns an unexpired stored response
+func (mc *MicroCache) lookup(key string) *microCacheEntry {
+	data, found, err := mc.store.Get(key)
+	if err != nil {
+		mc.logError("Micro-cache lookup failed", err, map[string]interface{}{"key": key})
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	var entry microCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Now().After(entry.ExpiresAt) {
+		return nil
+	}
+	return &entry
+}
+
+// serve writes a cached response with the headers it was stored with, such as
+// Content-Language and Vary
+func (mc *MicroCache) serve(w http.ResponseWriter, r *http.Request, entry *microCacheEntry) {
+	countMicroCache("microcache_hits_total", "Micro-cache hits")
+
+	for name, values := range entry.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(entry.StoredAt).Seconds())))
+	w.Header().Set(microCacheHeader, "HIT")
+	w.WriteHeader(entry.Status)
+	if r.Method != http.MethodHead {
+		w.Write(entry.Body)
+	}
+}
+
+// join returns the flight serving a key, and whether the caller leads it
+func (mc *MicroCache) join(key string) (*microCacheFlight, bool) {
+	mc.flightMutex.Lock()
+	defer mc.flightMutex.Unlock()
+
+	if flight, exists := mc.flights[key]; exists {
+		return flight, false
+	}
+	flight := &microCacheFlight{done: make(chan struct{})}
+	mc.flights[key] = flight
+	return flight, true
+}
+
+// land ends a flight, releasing the requests waiting for it
+func (mc *MicroCache) land(key string, flight *microCacheFlight) {
+	mc.flightMutex.Lock()
+	delete(mc.flights, key)
+	mc.flightMutex.Unlock()
+	close(flight.done)
+}
+
+// invalidateFor invalidates the groups a write on path affects
+func (mc *MicroCache) invalidateFor(path string) {
+	for prefix, groups := range mc.invalidations {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			mc.Invalidate(groups...)
+		}
+	}
+}
+
+func (mc *MicroCache) logError(message string, err error, fields map[string]interface{}) {
+	if mc.logger == nil {
+		return
+	}
+	fields["error"] = err.Error()
+	mc.logger.Warn(message, fields)
+}
+
+func countMicroCache(name, help string) {
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter(name, help).Inc()
+	}
+}
+
+// microCacheRecorder passes a response through while keeping a copy of its body
+type microCacheRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+	maxBody     int
+	overflow    bool
+}
+
+func (rec *microCacheRecorder) WriteHeader(statusCode int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.statusCode = statusCode
+		rec.Header().Set(microCacheHeader, "MISS")
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (rec *microCacheRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *microCacheRecorder) Write(data []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if !rec.overflow {
+		if rec.body.Len()+len(data) > rec.maxBody {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(data)
+		}
+	}
+	return rec.ResponseWriter.Write(data)
+}
+
+// storable reports whether the response may be shared with other anonymous clients
+func (rec *microCacheRecorder) storable() bool {
+	if rec.statusCode != http.StatusOK || rec.overflow {
+		return false
+	}
+	if rec.Header().Get("Set-Cookie") != "" {
+		return false
+	}
+	cacheControl := strings.ToLower(rec.Header().Get("Cache-Control"))
+	return !strings.Contains(cacheControl, "private") && !strings.Contains(cacheControl, "no-store")
+}
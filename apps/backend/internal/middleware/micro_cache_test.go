@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/cache"
+)
+
+func newTestMicroCache() *MicroCache {
+	return NewMicroCache(cache.NewMemoryResponseStore(100, 1<<20, time.Minute), 10*time.Second)
+}
+
+// countingHandler answers list requests with the number of times it was called
+func countingHandler(calls *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		call := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"call":%d}`, call)
+	})
+}
+
+func TestMicroCache_ServesAnonymousGetsFromCache(t *testing.T) {
+	var calls int32
+	server := newTestMicroCache().Middleware(countingHandler(&calls))
+
+	first := httptest.NewRecorder()
+	server.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/api/properties?page=1&province=Pichincha", nil))
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+
+	// Query parameter order does not matter
+	second := httptest.NewRecorder()
+	server.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/api/properties?province=Pichincha&page=1", nil))
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.NotEmpty(t, second.Header().Get("Age"))
+	assert.Equal(t, `{"call":1}`, second.Body.String())
+
+	// Other queries, authenticated requests and other routes are not served from cache
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/properties?page=2", nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/api/properties?page=1&province=Pichincha", nil)
+			r.Header.Set("Authorization", "Bearer token")
+			return r
+		}(),
+		httptest.NewRequest(http.MethodGet, "/api/properties/123", nil),
+	} {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		assert.NotEqual(t, "HIT", recorder.Header().Get("X-Cache"), request.URL.String())
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestMicroCache_KeepsLocalizedResponsesApart(t *testing.T) {
+	var calls int32
+	server := newTestMicroCache().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+		locale := "es"
+		if r.Header.Get("Accept-Language") == "en-US" {
+			locale = "en"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", locale)
+		w.Header().Add("Vary", "Accept-Language")
+		fmt.Fprintf(w, `{"call":%d,"locale":%q}`, call, locale)
+	}))
+	request := func(language string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/properties?page=1", nil)
+		r.Header.Set("Accept-Language", language)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	assert.Equal(t, "MISS", request("es").Header().Get("X-Cache"))
+	english := request("en-US")
+	assert.Equal(t, "MISS", english.Header().Get("X-Cache"))
+	assert.Equal(t, `{"call":2,"locale":"en"}`, english.Body.String())
+
+	// Each language is served its own response, with the headers it was stored with
+	cached := request("en-US")
+	assert.Equal(t, "HIT", cached.Header().Get("X-Cache"))
+	assert.Equal(t, `{"call":2,"locale":"en"}`, cached.Body.String())
+	assert.Equal(t, "en", cached.Header().Get("Content-Language"))
+	assert.Equal(t, []string{"Accept-Language"}, cached.Header().Values("Vary"))
+	assert.Equal(t, "application/json", cached.Header().Get("Content-Type"))
+
+	spanish := request("es-EC,es;q=0.9")
+	assert.Equal(t, "HIT", spanish.Header().Get("X-Cache"))
+	assert.Equal(t, "es", spanish.Header().Get("Content-Language"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestMicroCache_WritesInvalidateTheirGroup(t *testing.T) {
+	var calls int32
+	server := newTestMicroCache().Middleware(countingHandler(&calls))
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	get("/api/properties")
+	get("/api/agencies/active")
+	require.Equal(t, "HIT", get("/api/properties").Header().Get("X-Cache"))
+
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/images/upload", nil))
+	assert.Equal(t, "MISS", get("/api/properties").Header().Get("X-Cache"))
+	assert.Equal(t, "HIT", get("/api/agencies/active").Header().Get("X-Cache"))
+
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/api/agencies/agency-1", nil))
+	assert.Equal(t, "MISS", get("/api/agencies/active").Header().Get("X-Cache"))
+	assert.Equal(t, "HIT", get("/api/properties").Header().Get("X-Cache"))
+}
+
+func TestMicroCache_FailedWritesDoNotInvalidate(t *testing.T) {
+	var calls int32
+	mc := newTestMicroCache()
+	server := mc.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		countingHandler(&calls).ServeHTTP(w, r)
+	}))
+
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/properties/123", nil))
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+	assert.Equal(t, "HIT", recorder.Header().Get("X-Cache"))
+}
+
+func TestMicroCache_SkipsUnshareableResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"error status", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) }},
+		{"cookie", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Set-Cookie", "session=abc")
+			w.Write([]byte("{}"))
+		}},
+		{"private", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "private, max-age=60")
+			w.Write([]byte("{}"))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestMicroCache().Middleware(tt.handler)
+			server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+			assert.NotEqual(t, "HIT", recorder.Header().Get("X-Cache"))
+		})
+	}
+}
+
+func TestMicroCache_CoalescesConcurrentMisses(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := newTestMicroCache().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Write([]byte(`{"data":[]}`))
+	}))
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 5)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/properties/filter?type=casa", nil))
+			bodies[i] = recorder.Body.String()
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, body := range bodies {
+		assert.Equal(t, `{"data":[]}`, body)
+	}
+}
+
+// failingResponseStore fails every operation, like an unreachable Redis
+type failingResponseStore struct{}
+
+func (failingResponseStore) Get(string) ([]byte, bool, error) {
+	return nil, false, fmt.Errorf("connection refused")
+}
+func (failingResponseStore) Set(string, []byte, time.Duration) error {
+	return fmt.Errorf("connection refused")
+}
+func (failingResponseStore) Generation(string) (int64, error) {
+	return 0, fmt.Errorf("connection refused")
+}
+func (failingResponseStore) Bump(string) error { return fmt.Errorf("connection refused") }
+
+func TestMicroCache_FailsOpen(t *testing.T) {
+	var calls int32
+	server := NewMicroCache(failingResponseStore{}, time.Second).Middleware(countingHandler(&calls))
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/properties", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/properties", nil))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
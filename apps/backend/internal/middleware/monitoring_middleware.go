@@ -18,6 +18,7 @@ func MonitoringMiddleware(next http.Handler) http.Handler {
 			ResponseWriter: w,
 			statusCode:     http.StatusOK,
 		}
+		r, labels := withMetricsLabels(r)
 		
 		// Execute next handler
 		next.ServeHTTP(recorder, r)
@@ -30,6 +31,7 @@ func MonitoringMiddleware(next http.Handler) http.Handler {
 		metrics := monitoring.GetGlobalMetrics()
 		if metrics != nil {
 			metrics.RecordHTTPRequest(r.Method, path, recorder.statusCode, duration)
+			recordRequestMetrics(metrics, r, labels, recorder.statusCode, duration)
 		}
 	})
 }
@@ -45,6 +47,7 @@ func PerformanceMonitoringMiddleware(next http.Handler) http.Handler {
 			statusCode:     http.StatusOK,
 			bytesWritten:   0,
 		}
+		r, labels := withMetricsLabels(r)
 		
 		// Execute next handler
 		next.ServeHTTP(recorder, r)
@@ -57,6 +60,7 @@ func PerformanceMonitoringMiddleware(next http.Handler) http.Handler {
 		if metrics != nil {
 			// Record HTTP request
 			metrics.RecordHTTPRequest(r.Method, path, recorder.statusCode, duration)
+			recordRequestMetrics(metrics, r, labels, recorder.statusCode, duration)
 			
 			// Record custom performance metrics
 			performanceCounter := metrics.GetOrCreateCounter(
@@ -112,6 +116,11 @@ func (mrr *monitoringResponseRecorder) WriteHeader(statusCode int) {
 	mrr.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (mrr *monitoringResponseRecorder) Unwrap() http.ResponseWriter {
+	return mrr.ResponseWriter
+}
+
 func (mrr *monitoringResponseRecorder) Write(data []byte) (int, error) {
 	n, err := mrr.ResponseWriter.Write(data)
 	mrr.bytesWritten += n
@@ -306,6 +315,11 @@ func (cmr *cacheMonitoringRecorder) WriteHeader(statusCode int) {
 	cmr.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (cmr *cacheMonitoringRecorder) Unwrap() http.ResponseWriter {
+	return cmr.ResponseWriter
+}
+
 // isCacheableRequest determines if a request is cacheable
 func isCacheableRequest(r *http.Request) bool {
 	// Only GET requests are typically cacheable
@@ -17,6 +17,11 @@ func (rec *ResponseRecorder) WriteHeader(code int) {
 	rec.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (rec *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
 // PerformanceLogger logs request performance metrics
 func PerformanceLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -51,7 +56,7 @@ func CompressionMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Simple compression by setting appropriate headers
 		w.Header().Set("Content-Encoding", "gzip")
-		w.Header().Set("Vary", "Accept-Encoding")
+		addVary(w.Header(), "Accept-Encoding")
 		
 		next.ServeHTTP(w, r)
 	})
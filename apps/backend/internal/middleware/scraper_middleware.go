@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/logging"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/security"
+)
+
+// Page tokens: every catalog response carries the token of its next page, which deep
+// pages must be requested with, in the header or as ?page_token=
+const (
+	PageTokenHeader     = "X-Page-Token"
+	NextPageTokenHeader = "X-Next-Page-Token"
+	PageTokenParam      = "page_token"
+)
+
+// PublicCatalogExportPath streams the catalog as NDJSON to API key holders, metered by
+// their key instead of the scraper guard
+const PublicCatalogExportPath = "/api/public/v1/properties"
+
+// DefaultScraperGuardPaths are the public catalog endpoints competitors crawl
+var DefaultScraperGuardPaths = []string{"/api/properties"}
+
+// scraperUnboundedPaths are the catalog endpoints that return every match unless asked
+// for a page, by exact path
+var scraperUnboundedPaths = map[string]bool{"/api/properties": true, "/api/properties/filter": true}
+
+// scraperExemptRoles are the authenticated roles that manage listings and are never
+// slowed down
+var scraperExemptRoles = map[string]bool{"admin": true, "agency": true, "agent": true}
+
+// ScraperMiddleware applies the friction of a scraper guard to anonymous and buyer GETs
+// of the public catalog. They must page through it, sending the X-Page-Token of the
+// previous page with deep pages: unpaginated lists and NDJSON exports, which return the
+// whole catalog in one request, are refused after counting against the guard. Exports
+// are served to API keys by PublicCatalogExportPath instead.
+type ScraperMiddleware struct {
+	guard   *security.ScraperGuard
+	paths   []string
+	proxies TrustedProxies
+	logger  *logging.Logger
+	now     func() time.Time
+}
+
+// NewScraperMiddleware creates a scraper middleware guarding the catalog paths, by
+// prefix; no paths guards DefaultScraperGuardPaths
+func NewScraperMiddleware(guard *security.ScraperGuard, paths []string) *ScraperMiddleware {
+	if len(paths) == 0 {
+		paths = DefaultScraperGuardPaths
+	}
+	return &ScraperMiddleware{
+		guard:  guard,
+		paths:  paths,
+		logger: logging.GetGlobalLogger(),
+		now:    time.Now,
+	}
+}
+
+// SetTrustedProxies tells clients apart by the X-Forwarded-For entries the proxies in
+// front of the API appended. Without them clients are told apart by the remote address.
+func (sm *ScraperMiddleware) SetTrustedProxies(proxies TrustedProxies) {
+	sm.proxies = proxies
+}
+
+// Middleware delays, challenges or refuses catalog requests as the guard decides, and
+// hands out the token of the next page with each response
+func (sm *ScraperMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !sm.guarded(r.URL.Path) || GetAPIKeyID(r.Context()) != "" ||
+			scraperExemptRoles[GetUserRole(r.Context())] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		clientIP := sm.proxies.ClientIP(r)
+		page := security.ParsePage(r.URL.Query())
+		token := r.Header.Get(PageTokenHeader)
+		if token == "" {
+			token = r.URL.Query().Get(PageTokenParam)
+		}
+		req := security.ScraperRequest{
+			Fingerprint: security.RequestFingerprint(clientIP),
+			ClientIP:    clientIP,
+			UserAgent:   r.UserAgent(),
+			UserID:      GetUserID(ctx),
+			Page:        page,
+			PageToken:   token,
+			Signals:     security.ScraperSignals(r),
+		}
+
+		now := sm.now()
+		decision := sm.guard.Check(req, now)
+		switch decision.Action {
+		case security.ScraperBlock:
+			sm.handleBlocked(w, r, req, decision)
+			return
+		case security.ScraperChallenge:
+			sm.count("scraper_page_tokens_rejected_total", "Deep catalog pages requested without a valid page token")
+			sm.writeError(w, http.StatusPreconditionRequired, "PAGE_TOKEN_REQUIRED",
+				"This page must be requested with the "+NextPageTokenHeader+" of the previous page")
+			return
+		}
+
+		switch {
+		case strings.Contains(r.Header.Get("Accept"), "ndjson"):
+			sm.count("scraper_exports_rejected_total", "Catalog exports refused to anonymous and buyer clients")
+			sm.writeError(w, http.StatusForbidden, "EXPORT_NOT_ALLOWED",
+				"Streamed catalog exports are only available from "+PublicCatalogExportPath+" with an API key")
+			return
+		case scraperUnboundedPaths[r.URL.Path] && !paginated(r):
+			sm.count("scraper_unpaginated_rejected_total", "Unpaginated catalog requests refused by the scraper guard")
+			sm.writeError(w, http.StatusBadRequest, "PAGINATION_REQUIRED",
+				"The catalog must be requested a page at a time with ?page= and ?page_size=")
+			return
+		}
+
+		if decision.Action == security.ScraperDelay {
+			sm.count("scraper_requests_delayed_total", "Catalog requests delayed by the scraper guard")
+			timer := time.NewTimer(decision.Delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		w.Header().Set(NextPageTokenHeader, sm.guard.IssuePageToken(req.Fingerprint, page+1, now))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// paginated reports whether a catalog request asks for a single page
+func paginated(r *http.Request) bool {
+	query := r.URL.Query()
+	return query.Get("page") != "" || query.Get("page_size") != ""
+}
+
+// guarded reports whether a path is a guarded catalog endpoint
+func (sm *ScraperMiddleware) guarded(path string) bool {
+	for _, prefix := range sm.paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleBlocked answers a refused request with 429 and Retry-After
+func (sm *ScraperMiddleware) handleBlocked(w http.ResponseWriter, r *http.Request, req security.ScraperRequest, decision security.ScraperDecision) {
+	sm.count("scraper_requests_blocked_total", "Catalog requests refused by the scraper guard")
+	if sm.logger != nil {
+		sm.logger.SecurityEvent(
+			"Scraping Suspected",
+			req.UserID,
+			"Catalog requests past the hard limit",
+			map[string]interface{}{
+				"client_ip":   req.ClientIP,
+				"fingerprint": req.Fingerprint,
+				"user_agent":  req.UserAgent,
+				"path":        r.URL.Path,
+				"decision":    decision.String(),
+			},
+		)
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+	sm.writeError(w, http.StatusTooManyRequests, "SCRAPING_SUSPECTED", "Too many requests. Please slow down and try again later.")
+}
+
+func (sm *ScraperMiddleware) writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":   http.StatusText(statusCode),
+		"message": message,
+		"code":    code,
+	})
+}
+
+func (sm *ScraperMiddleware) count(name, help string) {
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter(name, help).Inc()
+	}
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/security"
+)
+
+func TestScraperMiddleware_PageTokensAndBlocking(t *testing.T) {
+	guard := security.NewScraperGuard(security.ScraperGuardConfig{SoftLimit: 5, HardLimit: 5, DeepPage: 1, PageTokenSecret: "secret"})
+	served := 0
+	server := NewScraperMiddleware(guard, nil).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	}))
+	get := func(path, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "203.0.113.7:4000"
+		r.Header.Set("User-Agent", "Mozilla/5.0")
+		r.Header.Set("Accept-Language", "es-EC")
+		if token != "" {
+			r.Header.Set(PageTokenHeader, token)
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	first := get("/api/properties/filter?page=1", "")
+	require.Equal(t, http.StatusOK, first.Code, "the first page needs no token")
+	next := first.Header().Get(NextPageTokenHeader)
+	require.NotEmpty(t, next)
+
+	deep := get("/api/properties/filter?page=5", "")
+	assert.Equal(t, http.StatusPreconditionRequired, deep.Code)
+	assert.Contains(t, deep.Body.String(), "PAGE_TOKEN_REQUIRED")
+
+	second := get("/api/properties/filter?page=2", next)
+	assert.Equal(t, http.StatusOK, second.Code, "the token of the previous page opens the next one")
+
+	get("/api/properties/filter?page=1", "")
+	get("/api/properties/filter?page=1", "")
+	blocked := get("/api/properties/filter?page=1", "")
+	assert.Equal(t, http.StatusTooManyRequests, blocked.Code)
+	assert.NotEmpty(t, blocked.Header().Get("Retry-After"))
+	assert.Equal(t, 4, served)
+
+	other := httptest.NewRecorder()
+	server.ServeHTTP(other, httptest.NewRequest(http.MethodGet, "/api/agencies", nil))
+	assert.Equal(t, http.StatusOK, other.Code, "other endpoints are not guarded")
+	assert.Empty(t, other.Header().Get(NextPageTokenHeader))
+}
+
+func TestScraperMiddleware_RefusesWholeCatalogRequests(t *testing.T) {
+	guard := security.NewScraperGuard(security.ScraperGuardConfig{SoftLimit: 3, HardLimit: 3, DeepPage: 5, PageTokenSecret: "secret"})
+	served := 0
+	server := NewScraperMiddleware(guard, nil).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	}))
+	get := func(path, accept, role string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "203.0.113.8:4000"
+		r.Header.Set("User-Agent", "Mozilla/5.0")
+		r.Header.Set("Accept-Language", "es-EC")
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		if role != "" {
+			r = r.WithContext(context.WithValue(r.Context(), RoleKey, role))
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	unpaginated := get("/api/properties", "", "")
+	assert.Equal(t, http.StatusBadRequest, unpaginated.Code)
+	assert.Contains(t, unpaginated.Body.String(), "PAGINATION_REQUIRED")
+
+	export := get("/api/properties?page=1", "application/x-ndjson", "buyer")
+	assert.Equal(t, http.StatusForbidden, export.Code)
+	assert.Contains(t, export.Body.String(), "EXPORT_NOT_ALLOWED")
+
+	// Staff keep the whole catalog and its export
+	assert.Equal(t, http.StatusOK, get("/api/properties", "application/x-ndjson", "agency").Code)
+	assert.Equal(t, http.StatusOK, get("/api/properties/filter", "", "admin").Code)
+	assert.Equal(t, 2, served)
+
+	// Refused requests count against the guard, whatever they accept
+	assert.Equal(t, http.StatusBadRequest, get("/api/properties/filter", "", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/properties?page=1", "", "").Code)
+	assert.Equal(t, 2, served)
+}
+
+func TestScraperMiddleware_FingerprintsTheTrustedClientAddress(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10"})
+	require.NoError(t, err)
+	guard := security.NewScraperGuard(security.ScraperGuardConfig{SoftLimit: 2, HardLimit: 2, DeepPage: 5, PageTokenSecret: "secret"})
+	scraper := NewScraperMiddleware(guard, nil)
+	scraper.SetTrustedProxies(proxies)
+	server := scraper.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+	get := func(remote, forwardedFor, userAgent string) int {
+		r := httptest.NewRequest(http.MethodGet, "/api/properties/filter?page=1", nil)
+		r.RemoteAddr = remote
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+		r.Header.Set("User-Agent", userAgent)
+		r.Header.Set("Accept-Language", "es-EC")
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, r)
+		return recorder.Code
+	}
+
+	// A client reaching the API directly cannot spoof X-Forwarded-For or rotate its User-Agent to start over
+	assert.Equal(t, http.StatusOK, get("203.0.113.7:4000", "198.51.100.1", "Mozilla/5.0"))
+	assert.Equal(t, http.StatusOK, get("203.0.113.7:4000", "198.51.100.2", "Mozilla/5.0 (X11; Linux x86_64)"))
+	assert.Equal(t, http.StatusTooManyRequests, get("203.0.113.7:4000", "198.51.100.3", "Mozilla/5.0 (Macintosh)"))
+
+	// Behind the proxies the client is the nearest untrusted hop, not the first one it claims
+	assert.Equal(t, http.StatusOK, get("10.0.0.2:4000", "198.51.100.9, 198.51.100.20, 192.0.2.10", "Mozilla/5.0"))
+	assert.Equal(t, http.StatusOK, get("10.0.0.3:4000", "198.51.100.10, 198.51.100.20", "Mozilla/5.0"))
+	assert.Equal(t, http.StatusTooManyRequests, get("10.0.0.2:4000", "198.51.100.11, 198.51.100.20", "Mozilla/5.0"))
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{" 10.0.0.0/8 ", "", "2001:db8::1"})
+	require.NoError(t, err)
+	assert.Len(t, proxies, 2)
+
+	_, err = ParseTrustedProxies([]string{"proxy.internal"})
+	assert.EqualError(t, err, "invalid trusted proxy: proxy.internal")
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSPReportPath is where browsers send the violations of the Content-Security-Policy
+const CSPReportPath = "/api/security/csp-report"
+
+// cspReportGroup names the Reporting API endpoint of CSP reports in report-to
+const cspReportGroup = "csp-endpoint"
+
+// Default security header policies
+const (
+	DefaultCSP = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; font-src 'self'"
+	// DefaultDocsCSP lets the embedded API docs UI load its bundle from jsDelivr and run
+	// its inline bootstrap script
+	DefaultDocsCSP = "default-src 'self'; script-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; " +
+		"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; img-src 'self' data: https:; " +
+		"font-src 'self' data: https://cdn.jsdelivr.net; connect-src 'self'; frame-ancestors 'none'"
+	// DefaultCSPRoutes relaxes the policy of the docs UI, see ParseCSPRoutes
+	DefaultCSPRoutes  = "/api/docs=" + DefaultDocsCSP
+	DefaultHSTSMaxAge = 365 * 24 * time.Hour
+	// hstsPreloadMinMaxAge is the shortest max-age the HSTS preload list accepts
+	hstsPreloadMinMaxAge = 365 * 24 * time.Hour
+)
+
+// CSPRoute replaces the Content-Security-Policy of the routes under a path prefix;
+// routes relaxed this way get no report-only policy
+type CSPRoute struct {
+	Prefix string
+	CSP    string
+}
+
+// SecurityHeadersConfig configures the security headers of every response
+type SecurityHeadersConfig struct {
+	CSP string // enforced policy; empty sends none
+	// CSPReportOnly is a policy browsers only report violations of, so a stricter policy
+	// can be tried before it is enforced
+	CSPReportOnly string
+	// ReportURI receives the violations of both policies, as report-uri and report-to;
+	// empty disables reporting
+	ReportURI             string
+	HSTSMaxAge            time.Duration // 0 sends no Strict-Transport-Security
+	HSTSIncludeSubdomains bool
+	// HSTSPreload asks browsers to ship the domain in their preload lists, which requires
+	// a max-age of a year and subdomains included, and is hard to undo
+	HSTSPreload bool
+	Routes      []CSPRoute
+}
+
+// DefaultSecurityHeadersConfig returns the security headers sent unless configured
+func DefaultSecurityHeadersConfig() SecurityHeadersConfig {
+	routes, _ := ParseCSPRoutes(DefaultCSPRoutes)
+	return SecurityHeadersConfig{
+		CSP:                   DefaultCSP,
+		ReportURI:             CSPReportPath,
+		HSTSMaxAge:            DefaultHSTSMaxAge,
+		HSTSIncludeSubdomains: true,
+		Routes:                routes,
+	}
+}
+
+// ValidateSecurityHeaders reports whether a configuration can be set with
+// SetSecurityHeaders
+func ValidateSecurityHeaders(config SecurityHeadersConfig) error {
+	_, err := compileSecurityHeaders(config)
+	return err
+}
+
+// securityHeaders are the header values of a SecurityHeadersConfig
+type securityHeaders struct {
+	csp                string
+	cspReportOnly      string
+	hsts               string
+	reportingEndpoints string
+	routes             []CSPRoute // with reporting, longest prefix first
+}
+
+// compileSecurityHeaders validates a configuration and builds its header values
+func compileSecurityHeaders(config SecurityHeadersConfig) (*securityHeaders, error) {
+	if config.HSTSMaxAge < 0 {
+		return nil, fmt.Errorf("invalid HSTS max age: must not be negative")
+	}
+	if config.HSTSPreload && (config.HSTSMaxAge < hstsPreloadMinMaxAge || !config.HSTSIncludeSubdomains) {
+		return nil, fmt.Errorf("invalid HSTS preload: requires a max age of at least a year and subdomains included")
+	}
+	if strings.ContainsAny(config.ReportURI, " ;,\"\r\n") {
+		return nil, fmt.Errorf("invalid CSP report URI: %q", config.ReportURI)
+	}
+	for _, policy := range []string{config.CSP, config.CSPReportOnly} {
+		if err := validateCSP(policy); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := &securityHeaders{
+		csp:           withCSPReporting(config.CSP, config.ReportURI),
+		cspReportOnly: withCSPReporting(config.CSPReportOnly, config.ReportURI),
+	}
+	if config.ReportURI != "" && (config.CSP != "" || config.CSPReportOnly != "" || len(config.Routes) > 0) {
+		headers.reportingEndpoints = cspReportGroup + `="` + config.ReportURI + `"`
+	}
+	if config.HSTSMaxAge > 0 {
+		headers.hsts = "max-age=" + strconv.FormatInt(int64(config.HSTSMaxAge/time.Second), 10)
+		if config.HSTSIncludeSubdomains {
+			headers.hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			headers.hsts += "; preload"
+		}
+	}
+	for _, route := range config.Routes {
+		if !strings.HasPrefix(route.Prefix, "/") {
+			return nil, fmt.Errorf("invalid CSP route %q: prefix must start with /", route.Prefix)
+		}
+		if err := validateCSP(route.CSP); err != nil {
+			return nil, fmt.Errorf("invalid CSP route %s: %w", route.Prefix, err)
+		}
+		headers.routes = append(headers.routes, CSPRoute{Prefix: route.Prefix, CSP: withCSPReporting(route.CSP, config.ReportURI)})
+	}
+	sort.SliceStable(headers.routes, func(i, j int) bool {
+		return len(headers.routes[i].Prefix) > len(headers.routes[j].Prefix)
+	})
+	return headers, nil
+}
+
+// policiesFor returns the enforced and report-only policies of a path
+func (h *securityHeaders) policiesFor(path string) (string, string) {
+	for _, route := range h.routes {
+		if strings.HasPrefix(path, route.Prefix) {
+			return route.CSP, ""
+		}
+	}
+	return h.csp, h.cspReportOnly
+}
+
+// validateCSP rejects policies that would break the header they are sent in
+func validateCSP(policy string) error {
+	if strings.ContainsAny(policy, "\r\n,") {
+		return fmt.Errorf("invalid CSP: policies cannot hold line breaks or commas")
+	}
+	return nil
+}
+
+// withCSPReporting adds the report-uri and report-to directives to a policy that has
+// none
+func withCSPReporting(policy, reportURI string) string {
+	policy = strings.TrimRight(strings.TrimSpace(policy), ";")
+	if policy == "" || reportURI == "" || strings.Contains(policy, "report-uri") || strings.Contains(policy, "report-to") {
+		return policy
+	}
+	return policy + "; report-uri " + reportURI + "; report-to " + cspReportGroup
+}
+
+// ParseCSPRoutes parses per-route policies separated by "|" as "prefix=policy", e.g.
+// "/api/docs=default-src 'self'; script-src 'self' 'unsafe-inline'"
+func ParseCSPRoutes(spec string) ([]CSPRoute, error) {
+	routes := []CSPRoute{}
+	for _, entry := range strings.Split(spec, "|") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		prefix, policy, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || !strings.HasPrefix(prefix, "/") || strings.TrimSpace(policy) == "" {
+			return nil, fmt.Errorf("invalid CSP route %q: expected /prefix=policy", entry)
+		}
+		routes = append(routes, CSPRoute{Prefix: prefix, CSP: strings.TrimSpace(policy)})
+	}
+	return routes, nil
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveSecurityHeaders(sm *SecurityMiddleware, path string) http.Header {
+	recorder := httptest.NewRecorder()
+	handler := sm.SecurityHeadersMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder.Header()
+}
+
+func TestSecurityHeadersMiddleware_Defaults(t *testing.T) {
+	sm := NewSecurityMiddleware()
+	defer sm.Stop()
+
+	header := serveSecurityHeaders(sm, "/api/properties")
+	assert.Equal(t, DefaultCSP+"; report-uri "+CSPReportPath+"; report-to csp-endpoint", header.Get("Content-Security-Policy"))
+	assert.Empty(t, header.Get("Content-Security-Policy-Report-Only"))
+	assert.Equal(t, `csp-endpoint="`+CSPReportPath+`"`, header.Get("Reporting-Endpoints"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+	assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+
+	// The docs UI gets its relaxed policy
+	header = serveSecurityHeaders(sm, "/api/docs/index.html")
+	assert.Contains(t, header.Get("Content-Security-Policy"), "script-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net")
+}
+
+func TestSecurityHeadersMiddleware_ReportOnlyAndPreload(t *testing.T) {
+	sm := NewSecurityMiddleware()
+	defer sm.Stop()
+
+	require.NoError(t, sm.SetSecurityHeaders(SecurityHeadersConfig{
+		CSPReportOnly:         "default-src 'none'; frame-ancestors 'none';",
+		ReportURI:             "https://reports.realty.ec/csp",
+		HSTSMaxAge:            2 * 365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           true,
+		Routes:                []CSPRoute{{Prefix: "/api/docs", CSP: "default-src 'self'; report-uri /elsewhere"}},
+	}))
+
+	header := serveSecurityHeaders(sm, "/api/properties")
+	assert.Empty(t, header.Get("Content-Security-Policy"))
+	assert.Equal(t, "default-src 'none'; frame-ancestors 'none'; report-uri https://reports.realty.ec/csp; report-to csp-endpoint",
+		header.Get("Content-Security-Policy-Report-Only"))
+	assert.Equal(t, "max-age=63072000; includeSubDomains; preload", header.Get("Strict-Transport-Security"))
+
+	// Relaxed routes keep their own reporting and skip the report-only trial
+	header = serveSecurityHeaders(sm, "/api/docs")
+	assert.Equal(t, "default-src 'self'; report-uri /elsewhere", header.Get("Content-Security-Policy"))
+	assert.Empty(t, header.Get("Content-Security-Policy-Report-Only"))
+}
+
+func TestSetSecurityHeaders_Invalid(t *testing.T) {
+	sm := NewSecurityMiddleware()
+	defer sm.Stop()
+
+	tests := []struct {
+		name   string
+		config SecurityHeadersConfig
+		err    string
+	}{
+		{"preload without subdomains", SecurityHeadersConfig{HSTSMaxAge: DefaultHSTSMaxAge, HSTSPreload: true}, "invalid HSTS preload"},
+		{"preload with short max age", SecurityHeadersConfig{HSTSMaxAge: time.Hour, HSTSIncludeSubdomains: true, HSTSPreload: true}, "invalid HSTS preload"},
+		{"two policies", SecurityHeadersConfig{CSP: "default-src 'self', script-src 'none'"}, "invalid CSP"},
+		{"report URI", SecurityHeadersConfig{CSP: DefaultCSP, ReportURI: "/report; script-src *"}, "invalid CSP report URI"},
+		{"route prefix", SecurityHeadersConfig{Routes: []CSPRoute{{Prefix: "docs", CSP: DefaultCSP}}}, "invalid CSP route"},
+	}
+	for _, tt := range tests {
+		assert.ErrorContains(t, sm.SetSecurityHeaders(tt.config), tt.err, tt.name)
+	}
+
+	// The previous headers are kept
+	assert.Contains(t, serveSecurityHeaders(sm, "/").Get("Content-Security-Policy"), DefaultCSP)
+}
+
+func TestParseCSPRoutes(t *testing.T) {
+	routes, err := ParseCSPRoutes("/api/docs=default-src 'self'; script-src 'unsafe-inline' | /status=default-src 'none'")
+	require.NoError(t, err)
+	assert.Equal(t, []CSPRoute{
+		{Prefix: "/api/docs", CSP: "default-src 'self'; script-src 'unsafe-inline'"},
+		{Prefix: "/status", CSP: "default-src 'none'"},
+	}, routes)
+
+	for _, spec := range []string{"api/docs=default-src 'self'", "/api/docs", "/api/docs= "} {
+		_, err := ParseCSPRoutes(spec)
+		assert.Error(t, err, spec)
+	}
+}
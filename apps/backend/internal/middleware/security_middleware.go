@@ -1,15 +1,22 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"realty-core/internal/domain"
+	"realty-core/internal/geoip"
 	"realty-core/internal/logging"
 	"realty-core/internal/security"
 )
 
+// GeoLocationKey holds where the client of a request is located
+const GeoLocationKey contextKey = "geo_location"
+
 // SecurityMiddleware provides comprehensive security protection
 type SecurityMiddleware struct {
 	rateLimiter     *security.AdaptiveRateLimiter
@@ -17,31 +24,96 @@ type SecurityMiddleware struct {
 	ipValidator     *security.IPValidator
 	securityMetrics *security.SecurityMetrics
 	logger          *logging.Logger
+
+	geoMutex         sync.RWMutex
+	geoLocator       geoip.Locator
+	blockedCountries map[string]bool
+
+	headersMutex sync.RWMutex
+	headers      *securityHeaders
 }
 
 // NewSecurityMiddleware creates a new security middleware
 func NewSecurityMiddleware() *SecurityMiddleware {
-	adaptiveConfig := security.AdaptiveConfig{
-		BaseMaxRequests:    100,
-		MaxMaxRequests:     200,
-		MinMaxRequests:     20,
-		AdaptationInterval: 30 * time.Second,
-	}
-
+	headers, _ := compileSecurityHeaders(DefaultSecurityHeadersConfig())
 	return &SecurityMiddleware{
-		rateLimiter:     security.NewAdaptiveRateLimiter(adaptiveConfig, time.Minute),
+		rateLimiter:     security.NewAdaptiveRateLimiter(adaptiveLimits(100), time.Minute),
 		inputValidator:  security.NewInputValidator(),
 		ipValidator:     security.NewIPValidator(),
 		securityMetrics: security.NewSecurityMetrics(time.Hour),
 		logger:          logging.GetGlobalLogger(),
+		headers:         headers,
+	}
+}
+
+// SetSecurityHeaders replaces the Content-Security-Policy, reporting and HSTS settings
+// of SecurityHeadersMiddleware
+func (sm *SecurityMiddleware) SetSecurityHeaders(config SecurityHeadersConfig) error {
+	headers, err := compileSecurityHeaders(config)
+	if err != nil {
+		return err
+	}
+
+	sm.headersMutex.Lock()
+	defer sm.headersMutex.Unlock()
+	sm.headers = headers
+	return nil
+}
+
+// SetRateLimit changes the requests allowed per client and minute at normal load. Under
+// low load clients get twice as many and under high load a fifth.
+func (sm *SecurityMiddleware) SetRateLimit(perMinute int) {
+	if perMinute <= 0 {
+		return
+	}
+	sm.rateLimiter.SetLimits(adaptiveLimits(perMinute))
+}
+
+// SetGeoIP enables locating clients by IP address. Without a locator GeoIPMiddleware
+// lets every request through unlocated.
+func (sm *SecurityMiddleware) SetGeoIP(locator geoip.Locator) {
+	sm.geoMutex.Lock()
+	defer sm.geoMutex.Unlock()
+	sm.geoLocator = locator
+}
+
+// SetBlockedCountries replaces the ISO 3166-1 alpha-2 codes of the countries denied access
+func (sm *SecurityMiddleware) SetBlockedCountries(codes []string) {
+	blocked := map[string]bool{}
+	for _, code := range domain.NormalizeCountryCodes(codes) {
+		blocked[code] = true
 	}
+
+	sm.geoMutex.Lock()
+	defer sm.geoMutex.Unlock()
+	sm.blockedCountries = blocked
 }
 
-// RateLimitMiddleware applies rate limiting with adaptive behavior
+// adaptiveLimits returns the adaptive rate limits for a base requests per minute
+func adaptiveLimits(perMinute int) security.AdaptiveConfig {
+	minRequests := perMinute / 5
+	if minRequests < 1 {
+		minRequests = 1
+	}
+	return security.AdaptiveConfig{
+		BaseMaxRequests:    perMinute,
+		MaxMaxRequests:     perMinute * 2,
+		MinMaxRequests:     minRequests,
+		AdaptationInterval: 30 * time.Second,
+	}
+}
+
+// RateLimitMiddleware applies rate limiting with adaptive behavior. Requests
+// authenticated with a public API key are limited by the tier of their key instead.
 func (sm *SecurityMiddleware) RateLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		
+		if GetAPIKeyID(r.Context()) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		
 		// Get client identifier (IP address)
 		clientIP := getClientIP(r)
 		
@@ -106,16 +178,68 @@ func (sm *SecurityMiddleware) IPValidationMiddleware(next http.Handler) http.Han
 	})
 }
 
-// SecurityHeadersMiddleware adds security headers to responses
+// GeoIPMiddleware locates the client, stores the location in the request context and
+// rejects clients from blocked countries. Lookup failures let the request through
+// unlocated rather than locking everyone out while the GeoIP service is down.
+func (sm *SecurityMiddleware) GeoIPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.geoMutex.RLock()
+		locator, blocked := sm.geoLocator, sm.blockedCountries
+		sm.geoMutex.RUnlock()
+		if locator == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		clientIP := getClientIP(r)
+		location, err := locator.Locate(clientIP)
+		if err != nil {
+			if sm.logger != nil {
+				sm.logger.Warn("GeoIP lookup failed", map[string]interface{}{
+					"client_ip": clientIP,
+					"error":     err.Error(),
+				})
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if blocked[location.CountryCode] {
+			sm.handleBlockedCountry(w, r, clientIP, location)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), GeoLocationKey, location)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// SecurityHeadersMiddleware adds security headers to responses, with the
+// Content-Security-Policy of the route and its report-only trial
 func (sm *SecurityMiddleware) SecurityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sm.headersMutex.RLock()
+		headers := sm.headers
+		sm.headersMutex.RUnlock()
+		csp, cspReportOnly := headers.policiesFor(r.URL.Path)
+		
 		// Add security headers
 		w.Header().Set("X-Content-Type-Options", "nosniff")
 		w.Header().Set("X-Frame-Options", "DENY")
 		w.Header().Set("X-XSS-Protection", "1; mode=block")
 		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
-		w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; font-src 'self'")
-		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		if csp != "" {
+			w.Header().Set("Content-Security-Policy", csp)
+		}
+		if cspReportOnly != "" {
+			w.Header().Set("Content-Security-Policy-Report-Only", cspReportOnly)
+		}
+		if headers.reportingEndpoints != "" {
+			w.Header().Set("Reporting-Endpoints", headers.reportingEndpoints)
+		}
+		if headers.hsts != "" {
+			w.Header().Set("Strict-Transport-Security", headers.hsts)
+		}
 		w.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
 		
 		// Remove server information
@@ -275,6 +399,36 @@ func (sm *SecurityMiddleware) handleBlockedIP(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleBlockedCountry handles requests from blocked countries
+func (sm *SecurityMiddleware) handleBlockedCountry(w http.ResponseWriter, r *http.Request, clientIP string, location domain.GeoLocation) {
+	sm.securityMetrics.RecordBlockedRequest(clientIP, "blocked_country_"+location.CountryCode)
+	
+	if sm.logger != nil {
+		sm.logger.SecurityEvent(
+			"Blocked Country",
+			"",
+			"Request from blocked country",
+			map[string]interface{}{
+				"client_ip": clientIP,
+				"country":   location.CountryCode,
+				"method":    r.Method,
+				"url":       r.URL.Path,
+			},
+		)
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	
+	response := map[string]interface{}{
+		"error":   "Access denied",
+		"message": "Access from your country is not allowed",
+		"code":    "COUNTRY_BLOCKED",
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
 // handleSecurityThreat handles detected security threats
 func (sm *SecurityMiddleware) handleSecurityThreat(w http.ResponseWriter, r *http.Request, threatType string, threats []security.ThreatInfo) {
 	clientIP := getClientIP(r)
@@ -323,6 +477,15 @@ func (sm *SecurityMiddleware) Stop() {
 	sm.rateLimiter.Stop()
 }
 
+// GetGeoLocation extracts the client location from request context, empty when GeoIP
+// is disabled or the lookup failed
+func GetGeoLocation(ctx context.Context) domain.GeoLocation {
+	if location, ok := ctx.Value(GeoLocationKey).(domain.GeoLocation); ok {
+		return location
+	}
+	return domain.GeoLocation{}
+}
+
 // getClientIP extracts the client IP address from the request
 func getClientIP(r *http.Request) string {
 	// Check for common proxy headers
@@ -357,4 +520,9 @@ type securityResponseRecorder struct {
 func (rr *securityResponseRecorder) WriteHeader(statusCode int) {
 	rr.statusCode = statusCode
 	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the wrapped writer so handlers can flush streamed responses
+func (rr *securityResponseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
 }
\ No newline at end of file
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"realty-core/internal/domain"
+)
+
+// TenantIDKey holds the tenant a request was resolved to
+const TenantIDKey contextKey = "tenant_id"
+
+// TenantResolver finds the tenant serving a request
+type TenantResolver interface {
+	// ResolveTenant returns the active tenant with the given ID or, when id is empty,
+	// the one serving host
+	ResolveTenant(host, id string) (*domain.Tenant, error)
+}
+
+// TenantMiddleware resolves the tenant of each request from the tenant header or the
+// request host. It is only installed when multi-tenancy is enabled, so single-tenant
+// installs never carry a tenant and their queries are not scoped.
+type TenantMiddleware struct {
+	resolver  TenantResolver
+	header    string
+	skipPaths map[string]bool
+}
+
+// NewTenantMiddleware creates a new tenant middleware reading the tenant ID from header
+func NewTenantMiddleware(resolver TenantResolver, header string) *TenantMiddleware {
+	if header == "" {
+		header = "X-Tenant-ID"
+	}
+	return &TenantMiddleware{
+		resolver: resolver,
+		header:   header,
+		skipPaths: map[string]bool{
+			"/api/health":       true,
+			"/api/health/ready": true,
+			"/api/health/live":  true,
+			"/api/metrics":      true,
+		},
+	}
+}
+
+// ResolveTenant adds the tenant ID to the request context; unknown or inactive tenants
+// get 404. Requests already authenticated with a token of another tenant get 403.
+func (tm *TenantMiddleware) ResolveTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tm.skipPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tenant, err := tm.resolver.ResolveTenant(r.Host, strings.TrimSpace(r.Header.Get(tm.header)))
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "Tenant Not Found",
+				"message": err.Error(),
+				"code":    "TENANT_NOT_FOUND",
+			})
+			return
+		}
+
+		if tokenTenantID, authenticated := r.Context().Value(TokenTenantIDKey).(string); authenticated && tokenTenantID != tenant.ID {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "Tenant Mismatch",
+				"message": "token was issued for another tenant",
+				"code":    "TENANT_MISMATCH",
+			})
+			return
+		}
+
+		w.Header().Add("Vary", tm.header)
+		ctx := context.WithValue(r.Context(), TenantIDKey, tenant.ID)
+		setMetricsTenant(ctx, tenant.ID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetTenantID extracts the tenant ID from request context, empty when multi-tenancy
+// is disabled
+func GetTenantID(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(TenantIDKey).(string); ok {
+		return tenantID
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/auth"
+	"realty-core/internal/domain"
+)
+
+type fakeTenantResolver struct{}
+
+func (fakeTenantResolver) ResolveTenant(host, id string) (*domain.Tenant, error) {
+	if id == "tenant-a" || id == "tenant-b" {
+		return &domain.Tenant{ID: id}, nil
+	}
+	return nil, errors.New("tenant not found")
+}
+
+func TestTenantMiddleware_RejectsTokensOfOtherTenants(t *testing.T) {
+	manager := auth.NewJWTManager("secret", time.Minute, time.Hour, "test")
+	pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "tenant-a")
+	require.NoError(t, err)
+
+	tenants := NewTenantMiddleware(fakeTenantResolver{}, "X-Tenant")
+	authentication := NewAuthMiddleware(manager, nil)
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	chains := map[string]http.Handler{
+		"tenant first": tenants.ResolveTenant(authentication.Authenticate(ok)),
+		"auth first":   authentication.Authenticate(tenants.ResolveTenant(ok)),
+	}
+
+	for name, chain := range chains {
+		t.Run(name, func(t *testing.T) {
+			get := func(tenant string) int {
+				r := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+				r.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+				r.Header.Set("X-Tenant", tenant)
+				recorder := httptest.NewRecorder()
+				chain.ServeHTTP(recorder, r)
+				return recorder.Code
+			}
+
+			assert.Equal(t, http.StatusOK, get("tenant-a"))
+			assert.Equal(t, http.StatusForbidden, get("tenant-b"), "a token of tenant A cannot act in tenant B")
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedProxies are the reverse proxies and load balancers in front of the API. Only
+// the X-Forwarded-For entries they appended are believed; anything before them was
+// sent by the client and can be anything.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses proxy addresses and CIDR ranges, e.g. 10.0.0.0/8
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	proxies := TrustedProxies{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		cidr := value
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", value)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// ClientIP returns the address of the client of a request: the remote address, unless
+// it is a trusted proxy, in which case X-Forwarded-For is walked back from the nearest
+// hop to the first address that is not a trusted proxy
+func (t TrustedProxies) ClientIP(r *http.Request) string {
+	client := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(client); err == nil {
+		client = host
+	}
+	if !t.trusted(client) {
+		return client
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		client = hop
+		if !t.trusted(hop) {
+			break
+		}
+	}
+	return client
+}
+
+// trusted reports whether an address is a trusted proxy
+func (t TrustedProxies) trusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range t {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// DefaultSafeClasses are classifier classes that never count as offending
+var DefaultSafeClasses = []string{"neutral", "drawings", "safe"}
+
+// HTTPModelProvider scores images with a self-hosted NSFW classifier (e.g. an
+// nsfwjs or open_nsfw server). The image is POSTed as the request body and the
+// server answers with a JSON object of class probabilities:
+//
+//	{"porn": 0.91, "sexy": 0.05, "neutral": 0.04}
+type HTTPModelProvider struct {
+	endpoint    string
+	safeClasses map[string]bool
+	client      *http.Client
+}
+
+// NewHTTPModelProvider creates a provider for a classifier endpoint
+func NewHTTPModelProvider(endpoint string, timeout time.Duration) (*HTTPModelProvider, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("moderation endpoint is required")
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid moderation endpoint: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	safe := make(map[string]bool, len(DefaultSafeClasses))
+	for _, class := range DefaultSafeClasses {
+		safe[class] = true
+	}
+
+	return &HTTPModelProvider{
+		endpoint:    endpoint,
+		safeClasses: safe,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (p *HTTPModelProvider) Name() string {
+	return "http-model"
+}
+
+// Detect sends the image to the classifier and returns the offending classes
+func (p *HTTPModelProvider) Detect(data []byte, contentType string) ([]domain.ModerationLabel, error) {
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting moderation model: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("moderation model returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var scores map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&scores); err != nil {
+		return nil, fmt.Errorf("error decoding moderation response: %w", err)
+	}
+
+	labels := []domain.ModerationLabel{}
+	for class, confidence := range scores {
+		class = strings.ToLower(class)
+		if p.safeClasses[class] || confidence <= 0 {
+			continue
+		}
+		labels = append(labels, domain.ModerationLabel{Name: class, Confidence: confidence})
+	}
+
+	return labels, nil
+}
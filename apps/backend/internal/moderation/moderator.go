@@ -0,0 +1,95 @@
+package moderation
+
+import (
+	"fmt"
+	"sort"
+
+	"realty-core/internal/domain"
+)
+
+// Provider scores image content. Implementations may call a cloud service
+// (e.g. AWS Rekognition) or a locally hosted NSFW classification model.
+type Provider interface {
+	// Name identifies the provider in moderation records
+	Name() string
+
+	// Detect returns the offending categories found in the image with their confidence
+	Detect(data []byte, contentType string) ([]domain.ModerationLabel, error)
+}
+
+// Policy maps moderation scores to statuses
+type Policy struct {
+	// FlagThreshold queues the image for review while keeping it visible
+	FlagThreshold float64
+	// QuarantineThreshold hides the image until an admin reviews it
+	QuarantineThreshold float64
+}
+
+// DefaultPolicy returns the default moderation thresholds
+func DefaultPolicy() Policy {
+	return Policy{
+		FlagThreshold:       0.5,
+		QuarantineThreshold: 0.85,
+	}
+}
+
+// Validate checks that thresholds are within 0-1 and ordered
+func (p Policy) Validate() error {
+	if p.FlagThreshold <= 0 || p.FlagThreshold > 1 || p.QuarantineThreshold <= 0 || p.QuarantineThreshold > 1 {
+		return fmt.Errorf("moderation thresholds must be between 0 and 1")
+	}
+	if p.FlagThreshold > p.QuarantineThreshold {
+		return fmt.Errorf("flag threshold cannot exceed quarantine threshold")
+	}
+	return nil
+}
+
+// Status returns the moderation status for a score
+func (p Policy) Status(score float64) string {
+	switch {
+	case score >= p.QuarantineThreshold:
+		return domain.ModerationStatusQuarantined
+	case score >= p.FlagThreshold:
+		return domain.ModerationStatusFlagged
+	default:
+		return domain.ModerationStatusApproved
+	}
+}
+
+// Moderator scores uploads with a provider and applies the policy
+type Moderator struct {
+	provider Provider
+	policy   Policy
+}
+
+// NewModerator creates a new moderator
+func NewModerator(provider Provider, policy Policy) (*Moderator, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("moderation provider is required")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &Moderator{provider: provider, policy: policy}, nil
+}
+
+// Moderate scores an image. When the provider fails the image is left pending
+// for manual review instead of blocking the upload.
+func (m *Moderator) Moderate(imageID, propertyID string, data []byte, contentType string) *domain.ImageModeration {
+	record := domain.NewImageModeration(imageID, propertyID, m.provider.Name())
+
+	labels, err := m.provider.Detect(data, contentType)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Confidence > labels[j].Confidence })
+	record.Labels = labels
+	if len(labels) > 0 {
+		record.Score = labels[0].Confidence
+	}
+	record.Status = m.policy.Status(record.Score)
+
+	return record
+}
@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+type stubProvider struct {
+	labels []domain.ModerationLabel
+	err    error
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Detect(data []byte, contentType string) ([]domain.ModerationLabel, error) {
+	return p.labels, p.err
+}
+
+func TestPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+	require.NoError(t, policy.Validate())
+
+	assert.Equal(t, domain.ModerationStatusApproved, policy.Status(0.1))
+	assert.Equal(t, domain.ModerationStatusFlagged, policy.Status(0.5))
+	assert.Equal(t, domain.ModerationStatusQuarantined, policy.Status(0.9))
+
+	assert.Error(t, Policy{FlagThreshold: 0.9, QuarantineThreshold: 0.5}.Validate())
+	assert.Error(t, Policy{FlagThreshold: 0, QuarantineThreshold: 0.5}.Validate())
+	assert.Error(t, Policy{FlagThreshold: 0.5, QuarantineThreshold: 1.5}.Validate())
+}
+
+func TestModerator_Moderate(t *testing.T) {
+	_, err := NewModerator(nil, DefaultPolicy())
+	assert.Error(t, err)
+
+	t.Run("highest label decides the status", func(t *testing.T) {
+		moderator, err := NewModerator(&stubProvider{labels: []domain.ModerationLabel{
+			{Name: "sexy", Confidence: 0.3},
+			{Name: "porn", Confidence: 0.92},
+		}}, DefaultPolicy())
+		require.NoError(t, err)
+
+		record := moderator.Moderate("img-1", "prop-1", []byte("data"), "image/jpeg")
+		assert.Equal(t, domain.ModerationStatusQuarantined, record.Status)
+		assert.Equal(t, 0.92, record.Score)
+		assert.Equal(t, "porn", record.Labels[0].Name)
+		assert.Equal(t, "stub", record.Provider)
+		assert.True(t, record.IsHidden())
+	})
+
+	t.Run("clean image is approved", func(t *testing.T) {
+		moderator, _ := NewModerator(&stubProvider{labels: []domain.ModerationLabel{}}, DefaultPolicy())
+
+		record := moderator.Moderate("img-1", "prop-1", []byte("data"), "image/jpeg")
+		assert.Equal(t, domain.ModerationStatusApproved, record.Status)
+		assert.False(t, record.InQueue())
+	})
+
+	t.Run("provider failure leaves image pending", func(t *testing.T) {
+		moderator, _ := NewModerator(&stubProvider{err: errors.New("timeout")}, DefaultPolicy())
+
+		record := moderator.Moderate("img-1", "prop-1", []byte("data"), "image/jpeg")
+		assert.Equal(t, domain.ModerationStatusPending, record.Status)
+		assert.Equal(t, "timeout", record.Error)
+		assert.True(t, record.InQueue())
+	})
+}
+
+func TestHTTPModelProvider_Detect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "image-bytes" || r.Header.Get("Content-Type") != "image/jpeg" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"Porn": 0.7, "sexy": 0.2, "neutral": 0.1, "drawings": 0.0, "hentai": 0}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPModelProvider(server.URL, 0)
+	require.NoError(t, err)
+
+	labels, err := provider.Detect([]byte("image-bytes"), "image/jpeg")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.ModerationLabel{
+		{Name: "porn", Confidence: 0.7},
+		{Name: "sexy", Confidence: 0.2},
+	}, labels)
+
+	_, err = provider.Detect([]byte("other"), "image/jpeg")
+	assert.Error(t, err)
+
+	_, err = NewHTTPModelProvider("", 0)
+	assert.Error(t, err)
+}
@@ -49,6 +49,9 @@ type AlertManager struct {
 	alertHistory []*Alert
 	maxHistory  int
 	
+	// Anomaly rules on business metrics
+	anomalies *AnomalyDetector
+	
 	// Notification channels
 	notifyChannels []AlertNotifier
 }
@@ -66,11 +69,15 @@ func NewAlertManager() *AlertManager {
 		activeAlerts: make(map[string]*Alert),
 		alertHistory: make([]*Alert, 0),
 		maxHistory:   1000,
+		anomalies:    NewAnomalyDetector(),
 		notifyChannels: make([]AlertNotifier, 0),
 	}
 	
 	// Register default alert rules
 	am.registerDefaultRules()
+	for _, rule := range DefaultAnomalyRules() {
+		am.AddAnomalyRule(rule)
+	}
 	
 	return am
 }
@@ -185,6 +192,19 @@ func (am *AlertManager) registerDefaultRules() {
 			return metrics.Database.QueryDuration > 100
 		},
 	})
+	
+	// Database pool exhaustion alert, sampled by DBPoolMonitor
+	am.AddRule(&AlertRule{
+		Name:        "db_pool_exhausted",
+		Description: "Requests are waiting too long for a database connection",
+		Level:       AlertLevelWarning,
+		Cooldown:    5 * time.Minute,
+		Enabled:     true,
+		Tags:        map[string]string{"category": "database"},
+		Condition: func(metrics *MetricsSnapshot) bool {
+			return metrics.Custom[MetricDBPoolExhausted] > 0
+		},
+	})
 }
 
 // AddRule adds a new alert rule
@@ -238,6 +258,9 @@ func (am *AlertManager) EvaluateRules(metrics *MetricsSnapshot) {
 	
 	now := time.Now()
 	
+	// Sample business counters before anomaly rules compare them
+	am.anomalies.Observe(metrics)
+	
 	for _, rule := range am.rules {
 		if !rule.Enabled {
 			continue
@@ -313,6 +336,9 @@ func (am *AlertManager) generateAlertDescription(rule *AlertRule, metrics *Metri
 		return fmt.Sprintf("Average query duration: %.2fms", metrics.Database.QueryDuration)
 		
 	default:
+		if description, ok := am.anomalies.Describe(rule.Name); ok {
+			return description
+		}
 		return rule.Description
 	}
 }
@@ -334,6 +360,10 @@ func (am *AlertManager) generateAlertMetadata(rule *AlertRule, metrics *MetricsS
 		metadata["cache_metrics"] = metrics.Cache
 	case "high_db_connections", "slow_db_queries":
 		metadata["database_metrics"] = metrics.Database
+	default:
+		if reading, ok := am.anomalies.Reading(rule.Name); ok {
+			metadata["anomaly"] = reading
+		}
 	}
 	
 	return metadata
@@ -0,0 +1,417 @@
+package monitoring
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AnomalyKind is how an anomaly rule compares the recent activity of a business counter
+// with its baseline
+type AnomalyKind string
+
+const (
+	// AnomalyDrop fires when the counter grows Threshold (a fraction) less than in the
+	// previous window, e.g. 0.5 for a 50% drop in new listings
+	AnomalyDrop AnomalyKind = "drop"
+	// AnomalySurge fires when the counter grows Threshold times more than in the previous
+	// window, e.g. 5 for a surge in failed logins
+	AnomalySurge AnomalyKind = "surge"
+	// AnomalyAbsent fires when the counter does not grow for a whole window, e.g. no image
+	// uploads for an hour
+	AnomalyAbsent AnomalyKind = "absent"
+)
+
+// Business counters watched by the default anomaly rules
+const (
+	MetricPropertiesCreated = "properties_created_total"
+	MetricLoginFailures     = "login_failures_total"
+	MetricImagesUploaded    = "images_uploaded_total"
+)
+
+// anomalySampleInterval is the minimum time between two samples of a counter
+const anomalySampleInterval = time.Minute
+
+// AnomalyRule detects unusual activity of a business counter, comparing how much it grew
+// in the last Window with how much it grew in the window before
+type AnomalyRule struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Metric      string        `json:"metric"` // counter name, e.g. properties_created_total
+	Kind        AnomalyKind   `json:"kind"`
+	Window      time.Duration `json:"window"`
+	Threshold   float64       `json:"threshold"`  // drop fraction or surge factor; unused when absent
+	MinEvents   float64       `json:"min_events"` // events of the busier window below which nothing is anomalous
+	Level       AlertLevel    `json:"level"`
+	Cooldown    time.Duration `json:"cooldown"`
+}
+
+// Validate verifies an anomaly rule can be evaluated
+func (r AnomalyRule) Validate() error {
+	if r.Name == "" || r.Metric == "" {
+		return fmt.Errorf("anomaly rule name and metric are required")
+	}
+	if r.Window <= 0 {
+		return fmt.Errorf("anomaly rule %s: window must be positive", r.Name)
+	}
+	switch r.Kind {
+	case AnomalyDrop:
+		if r.Threshold <= 0 || r.Threshold > 1 {
+			return fmt.Errorf("anomaly rule %s: drop threshold must be a fraction between 0 and 1", r.Name)
+		}
+	case AnomalySurge:
+		if r.Threshold <= 1 {
+			return fmt.Errorf("anomaly rule %s: surge threshold must be a factor above 1", r.Name)
+		}
+	case AnomalyAbsent:
+	default:
+		return fmt.Errorf("anomaly rule %s: kind must be drop, surge or absent", r.Name)
+	}
+	switch r.Level {
+	case AlertLevelInfo, AlertLevelWarning, AlertLevelCritical:
+	default:
+		return fmt.Errorf("anomaly rule %s: level must be info, warning or critical", r.Name)
+	}
+	return nil
+}
+
+// DefaultAnomalyRules are registered with every alert manager: a 50% drop in new
+// listings day over day, a surge in failed logins and an hour without image uploads
+func DefaultAnomalyRules() []AnomalyRule {
+	return []AnomalyRule{
+		{
+			Name:        "new_listings_drop",
+			Description: "New listings dropped by half compared with the previous day",
+			Metric:      MetricPropertiesCreated,
+			Kind:        AnomalyDrop,
+			Window:      24 * time.Hour,
+			Threshold:   0.5,
+			MinEvents:   10,
+			Level:       AlertLevelWarning,
+			Cooldown:    6 * time.Hour,
+		},
+		{
+			Name:        "failed_logins_surge",
+			Description: "Failed logins surged compared with the previous 15 minutes",
+			Metric:      MetricLoginFailures,
+			Kind:        AnomalySurge,
+			Window:      15 * time.Minute,
+			Threshold:   5,
+			MinEvents:   50,
+			Level:       AlertLevelCritical,
+			Cooldown:    30 * time.Minute,
+		},
+		{
+			Name:        "image_uploads_absent",
+			Description: "No images were uploaded in the last hour",
+			Metric:      MetricImagesUploaded,
+			Kind:        AnomalyAbsent,
+			Window:      time.Hour,
+			Level:       AlertLevelWarning,
+			Cooldown:    time.Hour,
+		},
+	}
+}
+
+// ParseAnomalyRules parses rules separated by semicolons as
+// "name=metric kind window threshold min_events level [cooldown]", e.g.
+// "new_listings_drop=properties_created_total drop 24h 0.5 10 warning 6h". "name=off"
+// disables a rule, and is returned in disabled.
+func ParseAnomalyRules(spec string) (rules []AnomalyRule, disabled []string, err error) {
+	for _, entry := range strings.Split(spec, ";") {
+		name, definition, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			if strings.TrimSpace(entry) != "" {
+				return nil, nil, fmt.Errorf("invalid anomaly rule %q: expected name=definition", entry)
+			}
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(definition), "off") {
+			disabled = append(disabled, name)
+			continue
+		}
+
+		rule, err := parseAnomalyRule(name, strings.Fields(definition))
+		if err != nil {
+			return nil, nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, disabled, nil
+}
+
+func parseAnomalyRule(name string, fields []string) (AnomalyRule, error) {
+	if len(fields) != 6 && len(fields) != 7 {
+		return AnomalyRule{}, fmt.Errorf("invalid anomaly rule %s: expected metric kind window threshold min_events level [cooldown]", name)
+	}
+	rule := AnomalyRule{
+		Name:   name,
+		Metric: fields[0],
+		Kind:   AnomalyKind(strings.ToLower(fields[1])),
+		Level:  AlertLevel(strings.ToLower(fields[5])),
+	}
+
+	var err error
+	if rule.Window, err = time.ParseDuration(fields[2]); err != nil {
+		return AnomalyRule{}, fmt.Errorf("invalid anomaly rule %s: window: %w", name, err)
+	}
+	if rule.Threshold, err = strconv.ParseFloat(fields[3], 64); err != nil {
+		return AnomalyRule{}, fmt.Errorf("invalid anomaly rule %s: threshold: %w", name, err)
+	}
+	if rule.MinEvents, err = strconv.ParseFloat(fields[4], 64); err != nil {
+		return AnomalyRule{}, fmt.Errorf("invalid anomaly rule %s: min_events: %w", name, err)
+	}
+	rule.Cooldown = rule.Window
+	if len(fields) == 7 {
+		if rule.Cooldown, err = time.ParseDuration(fields[6]); err != nil {
+			return AnomalyRule{}, fmt.Errorf("invalid anomaly rule %s: cooldown: %w", name, err)
+		}
+	}
+	rule.Description = fmt.Sprintf("Anomaly in %s: %s over %s", rule.Metric, rule.Kind, rule.Window)
+
+	if err := rule.Validate(); err != nil {
+		return AnomalyRule{}, fmt.Errorf("invalid anomaly rule %s: %w", name, err)
+	}
+	return rule, nil
+}
+
+// AnomalyReading is the activity of a counter an anomaly rule last compared
+type AnomalyReading struct {
+	Current  float64 `json:"current"`  // growth in the last window
+	Baseline float64 `json:"baseline"` // growth in the window before
+}
+
+// counterSample is the total of a counter at a point in time
+type counterSample struct {
+	at    time.Time
+	value float64
+}
+
+// AnomalyDetector keeps the recent history of the business counters anomaly rules
+// watch. History is kept in memory, so rules only fire once the process has been up
+// for the windows they compare.
+type AnomalyDetector struct {
+	mutex    sync.Mutex
+	rules    map[string]AnomalyRule
+	samples  map[string][]counterSample
+	readings map[string]AnomalyReading
+}
+
+// NewAnomalyDetector creates an anomaly detector without rules
+func NewAnomalyDetector() *AnomalyDetector {
+	return &AnomalyDetector{
+		rules:    make(map[string]AnomalyRule),
+		samples:  make(map[string][]counterSample),
+		readings: make(map[string]AnomalyReading),
+	}
+}
+
+// SetRule adds or replaces an anomaly rule
+func (d *AnomalyDetector) SetRule(rule AnomalyRule) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.rules[rule.Name] = rule
+}
+
+// RemoveRule removes an anomaly rule
+func (d *AnomalyDetector) RemoveRule(name string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	delete(d.rules, name)
+	delete(d.readings, name)
+}
+
+// Observe samples the counters watched by the rules from a metrics snapshot. Counters
+// not created yet count as zero.
+func (d *AnomalyDetector) Observe(metrics *MetricsSnapshot) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	retention := map[string]time.Duration{}
+	for _, rule := range d.rules {
+		if 2*rule.Window > retention[rule.Metric] {
+			retention[rule.Metric] = 2 * rule.Window
+		}
+	}
+
+	for metric, keep := range retention {
+		samples := d.samples[metric]
+		if n := len(samples); n > 0 && metrics.Timestamp.Sub(samples[n-1].at) < anomalySampleInterval {
+			continue
+		}
+		samples = append(samples, counterSample{at: metrics.Timestamp, value: metrics.Custom[metric]})
+
+		// Keep one sample older than the longest window so it stays covered
+		cutoff := metrics.Timestamp.Add(-keep)
+		drop := 0
+		for drop+1 < len(samples) && !samples[drop+1].at.After(cutoff) {
+			drop++
+		}
+		d.samples[metric] = samples[drop:]
+	}
+	for metric := range d.samples {
+		if _, watched := retention[metric]; !watched {
+			delete(d.samples, metric)
+		}
+	}
+}
+
+// Check reports whether an anomaly rule fires at now, recording what it compared
+func (d *AnomalyDetector) Check(name string, now time.Time) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	rule, exists := d.rules[name]
+	if !exists {
+		return false
+	}
+	samples := d.samples[rule.Metric]
+
+	current, covered := growth(samples, now.Add(-rule.Window), now)
+	if !covered {
+		return false
+	}
+	if rule.Kind == AnomalyAbsent {
+		d.readings[name] = AnomalyReading{Current: current}
+		return current == 0
+	}
+
+	baseline, covered := growth(samples, now.Add(-2*rule.Window), now.Add(-rule.Window))
+	if !covered {
+		return false
+	}
+	d.readings[name] = AnomalyReading{Current: current, Baseline: baseline}
+
+	if rule.Kind == AnomalyDrop {
+		return baseline >= rule.MinEvents && current <= baseline*(1-rule.Threshold)
+	}
+	return current >= rule.MinEvents && current >= baseline*rule.Threshold
+}
+
+// Reading returns what an anomaly rule last compared
+func (d *AnomalyDetector) Reading(name string) (AnomalyReading, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	reading, exists := d.readings[name]
+	return reading, exists
+}
+
+// Describe explains a fired anomaly rule with the activity it compared
+func (d *AnomalyDetector) Describe(name string) (string, bool) {
+	d.mutex.Lock()
+	rule, exists := d.rules[name]
+	reading := d.readings[name]
+	d.mutex.Unlock()
+	if !exists {
+		return "", false
+	}
+
+	if rule.Kind == AnomalyAbsent {
+		return fmt.Sprintf("%s did not increase in the last %s", rule.Metric, rule.Window), true
+	}
+	return fmt.Sprintf("%s increased by %.0f in the last %s against %.0f in the %s before",
+		rule.Metric, reading.Current, rule.Window, reading.Baseline, rule.Window), true
+}
+
+// Rules returns the anomaly rules sorted by name
+func (d *AnomalyDetector) Rules() []AnomalyRule {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	rules := make([]AnomalyRule, 0, len(d.rules))
+	for _, rule := range d.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules
+}
+
+// growth returns how much a counter grew between from and to, from the last sample at
+// or before from, and whether the samples reach back that far. Counter resets count
+// the value after the reset as growth.
+func growth(samples []counterSample, from, to time.Time) (float64, bool) {
+	start := -1
+	for i, sample := range samples {
+		if sample.at.After(from) {
+			break
+		}
+		start = i
+	}
+	if start < 0 {
+		return 0, false
+	}
+
+	total := 0.0
+	previous := samples[start].value
+	for _, sample := range samples[start+1:] {
+		if sample.at.After(to) {
+			break
+		}
+		if sample.value >= previous {
+			total += sample.value - previous
+		} else {
+			total += sample.value
+		}
+		previous = sample.value
+	}
+	return total, true
+}
+
+// AddAnomalyRule registers an anomaly rule as an alert rule, replacing one of the same
+// name
+func (am *AlertManager) AddAnomalyRule(rule AnomalyRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	am.anomalies.SetRule(rule)
+
+	name := rule.Name
+	am.AddRule(&AlertRule{
+		Name:        rule.Name,
+		Description: rule.Description,
+		Level:       rule.Level,
+		Cooldown:    rule.Cooldown,
+		Enabled:     true,
+		Tags:        map[string]string{"category": "business", "metric": rule.Metric, "anomaly": string(rule.Kind)},
+		Condition: func(metrics *MetricsSnapshot) bool {
+			return am.anomalies.Check(name, metrics.Timestamp)
+		},
+	})
+	return nil
+}
+
+// RemoveAnomalyRule removes an anomaly rule
+func (am *AlertManager) RemoveAnomalyRule(name string) {
+	am.RemoveRule(name)
+	am.anomalies.RemoveRule(name)
+}
+
+// ApplyAnomalyRules adds or replaces the anomaly rules of a ParseAnomalyRules spec and
+// removes the ones it turns off
+func (am *AlertManager) ApplyAnomalyRules(spec string) error {
+	rules, disabled, err := ParseAnomalyRules(spec)
+	if err != nil {
+		return err
+	}
+	for _, name := range disabled {
+		am.RemoveAnomalyRule(name)
+	}
+	for _, rule := range rules {
+		if err := am.AddAnomalyRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAnomalyRules returns the anomaly rules sorted by name
+func (am *AlertManager) GetAnomalyRules() []AnomalyRule {
+	return am.anomalies.Rules()
+}
@@ -0,0 +1,138 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// observeEvery feeds the detector one snapshot per step, the counter growing by the
+// increments in order
+func observeEvery(d *AnomalyDetector, metric string, start time.Time, step time.Duration, total float64, increments []float64) (time.Time, float64) {
+	at := start
+	for _, increment := range increments {
+		total += increment
+		d.Observe(&MetricsSnapshot{Timestamp: at, Custom: map[string]float64{metric: total}})
+		at = at.Add(step)
+	}
+	return at.Add(-step), total
+}
+
+func TestAnomalyDetector_Drop(t *testing.T) {
+	d := NewAnomalyDetector()
+	d.SetRule(AnomalyRule{Name: "drop", Metric: "listings", Kind: AnomalyDrop, Window: time.Hour, Threshold: 0.5, MinEvents: 10, Level: AlertLevelWarning})
+	start := time.Date(2025, 9, 9, 8, 0, 0, 0, time.UTC)
+
+	// Not enough history yet
+	now, total := observeEvery(d, "listings", start, 10*time.Minute, 0, []float64{0, 4, 4, 4, 4, 4, 4})
+	assert.False(t, d.Check("drop", now))
+
+	// 24 listings in the first hour, 6 in the second
+	now, _ = observeEvery(d, "listings", now.Add(10*time.Minute), 10*time.Minute, total, []float64{1, 1, 1, 1, 1, 1})
+	assert.True(t, d.Check("drop", now))
+	reading, ok := d.Reading("drop")
+	require.True(t, ok)
+	assert.Equal(t, AnomalyReading{Current: 6, Baseline: 24}, reading)
+
+	description, ok := d.Describe("drop")
+	require.True(t, ok)
+	assert.Equal(t, "listings increased by 6 in the last 1h0m0s against 24 in the 1h0m0s before", description)
+}
+
+func TestAnomalyDetector_SurgeAndAbsent(t *testing.T) {
+	d := NewAnomalyDetector()
+	d.SetRule(AnomalyRule{Name: "surge", Metric: "logins", Kind: AnomalySurge, Window: 10 * time.Minute, Threshold: 5, MinEvents: 50, Level: AlertLevelCritical})
+	d.SetRule(AnomalyRule{Name: "absent", Metric: "uploads", Kind: AnomalyAbsent, Window: 30 * time.Minute, Level: AlertLevelWarning})
+	start := time.Date(2025, 9, 9, 8, 0, 0, 0, time.UTC)
+
+	// A quiet baseline, then 60 failures in ten minutes
+	now, _ := observeEvery(d, "logins", start, 5*time.Minute, 0, []float64{0, 1, 1, 1, 2, 30, 30})
+	assert.True(t, d.Check("surge", now))
+
+	// Below MinEvents even a large factor is not a surge
+	d.SetRule(AnomalyRule{Name: "surge", Metric: "logins", Kind: AnomalySurge, Window: 10 * time.Minute, Threshold: 5, MinEvents: 100, Level: AlertLevelCritical})
+	assert.False(t, d.Check("surge", now))
+
+	// The uploads counter was never created: zero for the whole window
+	assert.True(t, d.Check("absent", now))
+	d.Observe(&MetricsSnapshot{Timestamp: now.Add(5 * time.Minute), Custom: map[string]float64{"uploads": 1}})
+	assert.False(t, d.Check("absent", now.Add(5*time.Minute)))
+}
+
+func TestGrowth_CounterReset(t *testing.T) {
+	start := time.Date(2025, 9, 9, 8, 0, 0, 0, time.UTC)
+	samples := []counterSample{
+		{at: start, value: 10},
+		{at: start.Add(time.Minute), value: 15},
+		{at: start.Add(2 * time.Minute), value: 3},
+		{at: start.Add(3 * time.Minute), value: 4},
+	}
+	total, covered := growth(samples, start, start.Add(3*time.Minute))
+	assert.True(t, covered)
+	assert.Equal(t, 9.0, total)
+
+	_, covered = growth(samples, start.Add(-time.Minute), start.Add(3*time.Minute))
+	assert.False(t, covered)
+}
+
+func TestParseAnomalyRules(t *testing.T) {
+	rules, disabled, err := ParseAnomalyRules("image_uploads_absent=off; quotes_drop=quotes_total drop 24h 0.4 20 warning 2h")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image_uploads_absent"}, disabled)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "quotes_total", rules[0].Metric)
+	assert.Equal(t, AnomalyDrop, rules[0].Kind)
+	assert.Equal(t, 24*time.Hour, rules[0].Window)
+	assert.Equal(t, 0.4, rules[0].Threshold)
+	assert.Equal(t, 2*time.Hour, rules[0].Cooldown)
+
+	_, _, err = ParseAnomalyRules("logins=login_failures_total surge 15m 0.5 10 critical")
+	assert.ErrorContains(t, err, "surge threshold")
+	_, _, err = ParseAnomalyRules("logins=login_failures_total surge 15m")
+	assert.ErrorContains(t, err, "expected metric kind")
+	_, _, err = ParseAnomalyRules("garbage")
+	assert.ErrorContains(t, err, "expected name=definition")
+}
+
+type recordingNotifier struct {
+	alerts chan *Alert
+}
+
+func (n *recordingNotifier) Notify(alert *Alert) error {
+	n.alerts <- alert
+	return nil
+}
+
+func (n *recordingNotifier) GetType() string { return "recording" }
+
+func TestAlertManager_AnomalyRules(t *testing.T) {
+	am := NewAlertManager()
+	assert.Len(t, am.GetAnomalyRules(), len(DefaultAnomalyRules()))
+
+	require.NoError(t, am.ApplyAnomalyRules("new_listings_drop=off;failed_logins_surge=off;image_uploads_absent=uploads absent 30m 0 0 warning"))
+	rules := am.GetAnomalyRules()
+	require.Len(t, rules, 1)
+	assert.Equal(t, 30*time.Minute, rules[0].Window)
+	_, exists := am.GetRules()["new_listings_drop"]
+	assert.False(t, exists)
+
+	notifier := &recordingNotifier{alerts: make(chan *Alert, 1)}
+	am.AddNotifier(notifier)
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i <= 6; i++ {
+		am.EvaluateRules(&MetricsSnapshot{Timestamp: start.Add(time.Duration(i) * 10 * time.Minute), Custom: map[string]float64{"uploads": 5}})
+	}
+
+	select {
+	case alert := <-notifier.alerts:
+		assert.Equal(t, "image_uploads_absent", alert.Name)
+		assert.Equal(t, "business", alert.Tags["category"])
+		assert.Equal(t, "uploads did not increase in the last 30m0s", alert.Description)
+		assert.Equal(t, AnomalyReading{}, alert.Metadata["anomaly"])
+	case <-time.After(time.Second):
+		t.Fatal("anomaly alert was not notified")
+	}
+}
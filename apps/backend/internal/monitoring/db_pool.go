@@ -0,0 +1,213 @@
+package monitoring
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Gauges of the database connection pool
+const (
+	MetricDBPoolMaxOpen        = "db_pool_max_open"
+	MetricDBPoolOpen           = "db_pool_open"
+	MetricDBPoolInUse          = "db_pool_in_use"
+	MetricDBPoolIdle           = "db_pool_idle"
+	MetricDBPoolWaitCount      = "db_pool_wait_count"
+	MetricDBPoolWaitDurationMs = "db_pool_wait_duration_ms"
+	MetricDBPoolMaxIdleClosed  = "db_pool_max_idle_closed"
+	MetricDBPoolLifetimeClosed = "db_pool_max_lifetime_closed"
+	MetricDBPoolExhausted      = "db_pool_exhausted"
+)
+
+const (
+	defaultDBPoolSampleInterval = 30 * time.Second
+	// defaultDBPoolWaitWarning is the average wait for a connection above which the
+	// pool is considered exhausted
+	defaultDBPoolWaitWarning = 50 * time.Millisecond
+)
+
+// DBPoolStats is a sample of the database connection pool
+type DBPoolStats struct {
+	MaxOpen           int     `json:"max_open"`
+	Open              int     `json:"open"`
+	InUse             int     `json:"in_use"`
+	Idle              int     `json:"idle"`
+	WaitCount         int64   `json:"wait_count"`
+	WaitDurationMs    float64 `json:"wait_duration_ms"`
+	MaxIdleClosed     int64   `json:"max_idle_closed"`
+	MaxIdleTimeClosed int64   `json:"max_idle_time_closed"`
+	MaxLifetimeClosed int64   `json:"max_lifetime_closed"`
+}
+
+// DBPoolReport is a sample of the pool with what changed since the previous one and
+// the tuning it suggests
+type DBPoolReport struct {
+	Stats       DBPoolStats `json:"stats"`
+	SampledAt   time.Time   `json:"sampled_at"`
+	Interval    string      `json:"interval,omitempty"`
+	Waits       int64       `json:"waits"`           // waits for a connection in the interval
+	AvgWaitMs   float64     `json:"avg_wait_ms"`     // average wait in the interval
+	Utilization float64     `json:"utilization_pct"` // connections in use of the maximum
+	Exhausted   bool        `json:"exhausted"`
+	Warnings    []string    `json:"warnings,omitempty"`
+}
+
+// DBPoolMonitor samples sql.DBStats into gauges and warns when the pool is too small or
+// too large for its load. Counters of sql.DBStats are cumulative, so warnings look at
+// what changed since the previous sample.
+type DBPoolMonitor struct {
+	db          *sql.DB
+	waitWarning time.Duration
+	logger      *log.Logger
+
+	mutex     sync.Mutex
+	previous  sql.DBStats
+	sampledAt time.Time
+	done      chan struct{}
+}
+
+// NewDBPoolMonitor creates a monitor of the pool of a database
+func NewDBPoolMonitor(db *sql.DB) *DBPoolMonitor {
+	return &DBPoolMonitor{db: db, waitWarning: defaultDBPoolWaitWarning, logger: log.Default()}
+}
+
+// SetWaitWarning sets the average wait for a connection above which the pool is
+// considered exhausted
+func (m *DBPoolMonitor) SetWaitWarning(wait time.Duration) {
+	if wait > 0 {
+		m.waitWarning = wait
+	}
+}
+
+// Sample reads the pool statistics, records them as gauges of the global metrics and
+// reports them with the warnings of the interval since the previous sample
+func (m *DBPoolMonitor) Sample() DBPoolReport {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	current := m.db.Stats()
+	report := analyzeDBPool(m.previous, current, m.waitWarning)
+	report.SampledAt = now
+	if !m.sampledAt.IsZero() {
+		report.Interval = now.Sub(m.sampledAt).Round(time.Second).String()
+	}
+	m.previous = current
+	m.sampledAt = now
+
+	if metrics := GetGlobalMetrics(); metrics != nil {
+		metrics.RecordDBPool(report)
+	}
+	return report
+}
+
+// Start samples the pool every interval, logging its warnings, until Stop is called
+func (m *DBPoolMonitor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultDBPoolSampleInterval
+	}
+	m.done = make(chan struct{})
+	done := m.done
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, warning := range m.Sample().Warnings {
+					m.logger.Printf("Database pool: %s", warning)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops sampling the pool
+func (m *DBPoolMonitor) Stop() {
+	if m.done == nil {
+		return
+	}
+	close(m.done)
+	m.done = nil
+}
+
+// analyzeDBPool reports the current statistics of a pool and the tuning suggested by
+// their change since the previous ones
+func analyzeDBPool(previous, current sql.DBStats, waitWarning time.Duration) DBPoolReport {
+	report := DBPoolReport{
+		Stats: DBPoolStats{
+			MaxOpen:           current.MaxOpenConnections,
+			Open:              current.OpenConnections,
+			InUse:             current.InUse,
+			Idle:              current.Idle,
+			WaitCount:         current.WaitCount,
+			WaitDurationMs:    float64(current.WaitDuration.Microseconds()) / 1000,
+			MaxIdleClosed:     current.MaxIdleClosed,
+			MaxIdleTimeClosed: current.MaxIdleTimeClosed,
+			MaxLifetimeClosed: current.MaxLifetimeClosed,
+		},
+		Waits: current.WaitCount - previous.WaitCount,
+	}
+	if report.Waits > 0 {
+		wait := (current.WaitDuration - previous.WaitDuration) / time.Duration(report.Waits)
+		report.AvgWaitMs = float64(wait.Microseconds()) / 1000
+		report.Exhausted = wait >= waitWarning
+	}
+	if current.MaxOpenConnections > 0 {
+		report.Utilization = float64(current.InUse) / float64(current.MaxOpenConnections) * 100
+	}
+
+	if report.Exhausted {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"%d requests waited %.1fms on average for one of %d connections; the pool is exhausted, raise DB_MAX_OPEN_CONNS within the server's max_connections or shorten slow queries",
+			report.Waits, report.AvgWaitMs, current.MaxOpenConnections))
+	} else if current.MaxOpenConnections > 0 && current.InUse >= current.MaxOpenConnections {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"all %d connections are in use; further queries wait for one", current.MaxOpenConnections))
+	}
+	if current.MaxOpenConnections == 0 {
+		report.Warnings = append(report.Warnings,
+			"the pool is unlimited; set DB_MAX_OPEN_CONNS below the server's max_connections")
+	}
+	// Connections closed for exceeding the idle limit are reopened for the next burst
+	if closed := current.MaxIdleClosed - previous.MaxIdleClosed; closed > int64(current.MaxOpenConnections) && current.MaxOpenConnections > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"%d connections were closed for exceeding the idle limit and reopened; raise DB_MAX_IDLE_CONNS", closed))
+	}
+	return report
+}
+
+// RecordDBPool records a sample of the database connection pool
+func (m *MetricsCollector) RecordDBPool(report DBPoolReport) {
+	m.RecordDBConnection(report.Stats.InUse)
+
+	gauges := []struct {
+		name, description string
+		value             float64
+	}{
+		{MetricDBPoolMaxOpen, "Maximum open database connections, 0 for unlimited", float64(report.Stats.MaxOpen)},
+		{MetricDBPoolOpen, "Open database connections", float64(report.Stats.Open)},
+		{MetricDBPoolInUse, "Database connections in use", float64(report.Stats.InUse)},
+		{MetricDBPoolIdle, "Idle database connections", float64(report.Stats.Idle)},
+		{MetricDBPoolWaitCount, "Total waits for a database connection", float64(report.Stats.WaitCount)},
+		{MetricDBPoolWaitDurationMs, "Total time waited for database connections in milliseconds", report.Stats.WaitDurationMs},
+		{MetricDBPoolMaxIdleClosed, "Total connections closed for exceeding the idle limit", float64(report.Stats.MaxIdleClosed)},
+		{MetricDBPoolLifetimeClosed, "Total connections closed for exceeding their lifetime", float64(report.Stats.MaxLifetimeClosed)},
+		{MetricDBPoolExhausted, "Whether requests waited too long for a database connection in the last sample", boolGauge(report.Exhausted)},
+	}
+	for _, gauge := range gauges {
+		m.GetOrCreateGauge(gauge.name, gauge.description).Set(gauge.value)
+	}
+}
+
+func boolGauge(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
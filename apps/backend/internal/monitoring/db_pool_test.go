@@ -0,0 +1,66 @@
+package monitoring
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeDBPool_WarnsOfExhaustion(t *testing.T) {
+	previous := sql.DBStats{MaxOpenConnections: 25, WaitCount: 10, WaitDuration: time.Second}
+	current := sql.DBStats{
+		MaxOpenConnections: 25, OpenConnections: 25, InUse: 25,
+		WaitCount: 30, WaitDuration: 5 * time.Second,
+	}
+
+	report := analyzeDBPool(previous, current, 50*time.Millisecond)
+	assert.Equal(t, int64(20), report.Waits)
+	assert.Equal(t, 200.0, report.AvgWaitMs)
+	assert.Equal(t, 100.0, report.Utilization)
+	assert.True(t, report.Exhausted)
+	assert.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "raise DB_MAX_OPEN_CONNS")
+}
+
+func TestAnalyzeDBPool_HealthyAndMisconfiguredPools(t *testing.T) {
+	// Short waits are not exhaustion
+	report := analyzeDBPool(sql.DBStats{MaxOpenConnections: 25},
+		sql.DBStats{MaxOpenConnections: 25, InUse: 5, Idle: 5, WaitCount: 4, WaitDuration: 20 * time.Millisecond},
+		50*time.Millisecond)
+	assert.False(t, report.Exhausted)
+	assert.Empty(t, report.Warnings)
+	assert.Equal(t, 20.0, report.Utilization)
+
+	report = analyzeDBPool(sql.DBStats{}, sql.DBStats{InUse: 3}, 50*time.Millisecond)
+	assert.Contains(t, report.Warnings, "the pool is unlimited; set DB_MAX_OPEN_CONNS below the server's max_connections")
+
+	report = analyzeDBPool(sql.DBStats{MaxOpenConnections: 10, MaxIdleClosed: 5},
+		sql.DBStats{MaxOpenConnections: 10, MaxIdleClosed: 60}, 50*time.Millisecond)
+	assert.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "raise DB_MAX_IDLE_CONNS")
+}
+
+func TestMetricsCollector_RecordDBPool(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.RecordDBPool(DBPoolReport{
+		Stats:     DBPoolStats{MaxOpen: 25, Open: 12, InUse: 8, Idle: 4, WaitCount: 3, WaitDurationMs: 120},
+		Exhausted: true,
+	})
+
+	snapshot := collector.GetMetricsSnapshot()
+	assert.Equal(t, 8.0, snapshot.Database.Connections)
+	assert.Equal(t, 25.0, snapshot.Custom[MetricDBPoolMaxOpen])
+	assert.Equal(t, 4.0, snapshot.Custom[MetricDBPoolIdle])
+	assert.Equal(t, 120.0, snapshot.Custom[MetricDBPoolWaitDurationMs])
+	assert.Equal(t, 1.0, snapshot.Custom[MetricDBPoolExhausted])
+
+	alerts := NewAlertManager()
+	alerts.EvaluateRules(&snapshot)
+	active := false
+	for _, alert := range alerts.GetActiveAlerts() {
+		active = active || alert.Name == "db_pool_exhausted"
+	}
+	assert.True(t, active)
+}
@@ -16,6 +16,9 @@ type MetricsCollector struct {
 	httpDurations   map[string]*Histogram
 	httpErrors      map[string]*Counter
 	
+	// Request metrics labelled by route template, status class and agency
+	requests        *RequestMetrics
+	
 	// Database metrics
 	dbConnections   *Gauge
 	dbQueries       *Counter
@@ -53,6 +56,7 @@ func NewMetricsCollector() *MetricsCollector {
 		httpRequests:     make(map[string]*Counter),
 		httpDurations:    make(map[string]*Histogram),
 		httpErrors:       make(map[string]*Counter),
+		requests:         NewRequestMetrics(),
 		customCounters:   make(map[string]*Counter),
 		customGauges:     make(map[string]*Gauge),
 		customHistograms: make(map[string]*Histogram),
@@ -452,6 +456,20 @@ func (m *MetricsCollector) GetMetricsSnapshot() MetricsSnapshot {
 		},
 	}
 	
+	// Collect custom metrics (histograms report their mean)
+	if len(m.customCounters)+len(m.customGauges)+len(m.customHistograms) > 0 {
+		snapshot.Custom = make(map[string]float64)
+		for name, counter := range m.customCounters {
+			snapshot.Custom[name] = float64(counter.Get())
+		}
+		for name, gauge := range m.customGauges {
+			snapshot.Custom[name] = gauge.Get()
+		}
+		for name, histogram := range m.customHistograms {
+			snapshot.Custom[name] = histogram.GetMean()
+		}
+	}
+	
 	// Collect HTTP metrics
 	for key, counter := range m.httpRequests {
 		if duration, exists := m.httpDurations[key]; exists {
@@ -476,6 +494,7 @@ type MetricsSnapshot struct {
 	Cache     CacheMetrics            `json:"cache"`
 	Business  BusinessMetrics         `json:"business"`
 	System    SystemMetrics           `json:"system"`
+	Custom    map[string]float64      `json:"custom,omitempty"`
 }
 
 // HTTPMetric contains HTTP-related metrics
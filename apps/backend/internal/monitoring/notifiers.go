@@ -0,0 +1,126 @@
+package monitoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultNotifierTimeout bounds a notification when no timeout is configured
+const defaultNotifierTimeout = 10 * time.Second
+
+// alertText formats an alert as the plain text notifications carry
+func alertText(alert *Alert) string {
+	text := fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(alert.Level)), alert.Name, alert.Message)
+	if alert.Description != "" && alert.Description != alert.Message {
+		text += "\n" + alert.Description
+	}
+	return text + "\n" + alert.Timestamp.UTC().Format(time.RFC3339)
+}
+
+// SlackNotifier posts alerts to a Slack channel through an incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a notifier posting to a Slack incoming webhook URL
+func NewSlackNotifier(webhookURL string, timeout time.Duration) (*SlackNotifier, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("Slack webhook URL is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultNotifierTimeout
+	}
+	return &SlackNotifier{webhookURL: webhookURL, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Notify posts the alert as a Slack message
+func (sn *SlackNotifier) Notify(alert *Alert) error {
+	body, err := json.Marshal(map[string]string{"text": alertText(alert)})
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack message: %w", err)
+	}
+
+	resp, err := sn.client.Post(sn.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post Slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetType returns the notifier type
+func (sn *SlackNotifier) GetType() string {
+	return "slack"
+}
+
+// EmailSettings configures the SMTP server alert emails are sent through
+type EmailSettings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// EmailNotifier emails alerts to a list of recipients over SMTP
+type EmailNotifier struct {
+	settings   EmailSettings
+	recipients []string
+	send       func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates a notifier emailing alerts to recipients
+func NewEmailNotifier(settings EmailSettings, recipients []string) (*EmailNotifier, error) {
+	if settings.Host == "" || settings.From == "" {
+		return nil, fmt.Errorf("SMTP host and sender are required")
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("at least one alert email recipient is required")
+	}
+	return &EmailNotifier{settings: settings, recipients: recipients, send: smtp.SendMail}, nil
+}
+
+// Notify emails the alert
+func (en *EmailNotifier) Notify(alert *Alert) error {
+	var auth smtp.Auth
+	if en.settings.Username != "" {
+		auth = smtp.PlainAuth("", en.settings.Username, en.settings.Password, en.settings.Host)
+	}
+
+	addr := net.JoinHostPort(en.settings.Host, strconv.Itoa(en.settings.Port))
+	if err := en.send(addr, auth, en.settings.From, en.recipients, en.message(alert)); err != nil {
+		return fmt.Errorf("failed to send alert email: %w", err)
+	}
+	return nil
+}
+
+// message builds the alert email with its headers
+func (en *EmailNotifier) message(alert *Alert) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", en.settings.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(en.recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] %s\r\n", strings.ToUpper(string(alert.Level)), alert.Name)
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.Timestamp.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(alertText(alert), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
+
+// GetType returns the notifier type
+func (en *EmailNotifier) GetType() string {
+	return "email"
+}
@@ -0,0 +1,76 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAlert() *Alert {
+	return &Alert{
+		Name:        "failed_logins_surge",
+		Level:       AlertLevelCritical,
+		Message:     "Failed logins surged compared with the previous 15 minutes",
+		Description: "login_failures_total increased by 300 in the last 15m0s against 12 in the 15m0s before",
+		Timestamp:   time.Date(2025, 9, 9, 13, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var message map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := NewSlackNotifier(server.URL, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, notifier.Notify(testAlert()))
+	assert.Contains(t, message["text"], "[CRITICAL] failed_logins_surge")
+	assert.Contains(t, message["text"], "increased by 300")
+
+	_, err = NewSlackNotifier("", time.Second)
+	assert.Error(t, err)
+}
+
+func TestSlackNotifier_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	notifier, err := NewSlackNotifier(server.URL, time.Second)
+	require.NoError(t, err)
+	assert.ErrorContains(t, notifier.Notify(testAlert()), "status 403")
+}
+
+func TestEmailNotifier(t *testing.T) {
+	notifier, err := NewEmailNotifier(EmailSettings{Host: "smtp.example.com", Port: 587, Username: "alerts", Password: "secret", From: "alerts@example.com"},
+		[]string{"ops@example.com", "cto@example.com"})
+	require.NoError(t, err)
+
+	var sentTo []string
+	var sentAddr, sentMsg string
+	notifier.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		assert.NotNil(t, auth)
+		sentAddr, sentTo, sentMsg = addr, to, string(msg)
+		return nil
+	}
+
+	require.NoError(t, notifier.Notify(testAlert()))
+	assert.Equal(t, "smtp.example.com:587", sentAddr)
+	assert.Equal(t, []string{"ops@example.com", "cto@example.com"}, sentTo)
+	assert.Contains(t, sentMsg, "Subject: [CRITICAL] failed_logins_surge\r\n")
+	assert.Contains(t, sentMsg, "To: ops@example.com, cto@example.com\r\n")
+
+	_, err = NewEmailNotifier(EmailSettings{Host: "smtp.example.com", From: "alerts@example.com"}, nil)
+	assert.Error(t, err)
+}
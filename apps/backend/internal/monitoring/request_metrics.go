@@ -0,0 +1,192 @@
+package monitoring
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Label values standing in for the values cardinality safeguards drop
+const (
+	// OverflowLabel replaces routes and agencies beyond the configured limits
+	OverflowLabel = "other"
+	// UnmatchedRoute labels 404 and 405 responses on routes not seen before, so scanners
+	// probing random paths do not add series
+	UnmatchedRoute = "unmatched"
+)
+
+// Default cardinality limits of request metrics
+const (
+	DefaultMaxRouteLabels  = 300
+	DefaultMaxAgencyLabels = 50
+)
+
+// knownMethods are the HTTP methods kept as labels; others are labelled OTHER
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// RequestLabels identify a series of request metrics. Agency and Tenant are empty
+// unless agency labels are enabled, and for anonymous requests.
+type RequestLabels struct {
+	Method      string `json:"method"`
+	Route       string `json:"route"`
+	StatusClass string `json:"status_class"`
+	Agency      string `json:"agency,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
+}
+
+// RequestSeries holds the requests of a label set and their total duration
+type RequestSeries struct {
+	Labels        RequestLabels `json:"labels"`
+	Requests      int64         `json:"requests"`
+	DurationSumMs float64       `json:"duration_sum_ms"`
+}
+
+// RequestMetrics counts requests by route template, status class and, optionally,
+// agency and tenant. New routes, agencies and tenants past the limits are folded into
+// OverflowLabel so a crawler or a long tail of agencies cannot grow the series without
+// bound.
+type RequestMetrics struct {
+	mutex        sync.Mutex
+	maxRoutes    int
+	maxAgencies  int
+	agencyLabels bool
+	routes       map[string]bool
+	agencies     map[string]bool
+	tenants      map[string]bool
+	series       map[RequestLabels]*RequestSeries
+}
+
+// NewRequestMetrics creates request metrics with the default limits and without agency
+// labels
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{
+		maxRoutes:   DefaultMaxRouteLabels,
+		maxAgencies: DefaultMaxAgencyLabels,
+		routes:      make(map[string]bool),
+		agencies:    make(map[string]bool),
+		tenants:     make(map[string]bool),
+		series:      make(map[RequestLabels]*RequestSeries),
+	}
+}
+
+// Configure sets the label limits and whether requests are labelled with their agency
+// and tenant. Values already admitted keep their labels.
+func (rm *RequestMetrics) Configure(maxRoutes, maxAgencies int, agencyLabels bool) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	if maxRoutes > 0 {
+		rm.maxRoutes = maxRoutes
+	}
+	if maxAgencies > 0 {
+		rm.maxAgencies = maxAgencies
+	}
+	rm.agencyLabels = agencyLabels
+}
+
+// AgencyLabels reports whether requests are labelled with their agency and tenant
+func (rm *RequestMetrics) AgencyLabels() bool {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	return rm.agencyLabels
+}
+
+// Record counts a request on a route template, e.g. /api/properties/{id}
+func (rm *RequestMetrics) Record(method, route string, statusCode int, agency, tenant string, duration time.Duration) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	labels := RequestLabels{
+		Method:      method,
+		Route:       rm.routeLabel(route, statusCode),
+		StatusClass: StatusClass(statusCode),
+	}
+	if !knownMethods[method] {
+		labels.Method = "OTHER"
+	}
+	if rm.agencyLabels {
+		labels.Agency = admitLabel(rm.agencies, agency, rm.maxAgencies)
+		labels.Tenant = admitLabel(rm.tenants, tenant, rm.maxAgencies)
+	}
+
+	series, exists := rm.series[labels]
+	if !exists {
+		series = &RequestSeries{Labels: labels}
+		rm.series[labels] = series
+	}
+	series.Requests++
+	series.DurationSumMs += float64(duration.Microseconds()) / 1000
+}
+
+// Series returns the recorded series sorted by route, method, status class, agency and
+// tenant
+func (rm *RequestMetrics) Series() []RequestSeries {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	series := make([]RequestSeries, 0, len(rm.series))
+	for _, s := range rm.series {
+		series = append(series, *s)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i].Labels, series[j].Labels
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.StatusClass != b.StatusClass {
+			return a.StatusClass < b.StatusClass
+		}
+		if a.Agency != b.Agency {
+			return a.Agency < b.Agency
+		}
+		return a.Tenant < b.Tenant
+	})
+	return series
+}
+
+// routeLabel admits a route template. Unknown routes answered 404 or 405 are
+// unmatched rather than admitted.
+func (rm *RequestMetrics) routeLabel(route string, statusCode int) string {
+	if rm.routes[route] {
+		return route
+	}
+	if statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed {
+		return UnmatchedRoute
+	}
+	return admitLabel(rm.routes, route, rm.maxRoutes)
+}
+
+// admitLabel returns value when it is already known or there is room for it, and
+// OverflowLabel otherwise. Empty values stay empty.
+func admitLabel(known map[string]bool, value string, max int) string {
+	if value == "" || known[value] {
+		return value
+	}
+	if len(known) >= max {
+		return OverflowLabel
+	}
+	known[value] = true
+	return value
+}
+
+// StatusClass returns the class of an HTTP status code, e.g. 2xx
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
+
+// Requests returns the request metrics labelled by route, status class and agency
+func (m *MetricsCollector) Requests() *RequestMetrics {
+	return m.requests
+}
@@ -0,0 +1,59 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMetrics_Record(t *testing.T) {
+	rm := NewRequestMetrics()
+	rm.Record("GET", "/api/properties/{id}", 200, "agency-1", "", 20*time.Millisecond)
+	rm.Record("GET", "/api/properties/{id}", 204, "agency-2", "", 10*time.Millisecond)
+	rm.Record("GET", "/api/properties/{id}", 404, "", "", 5*time.Millisecond)
+	rm.Record("BREW", "/api/properties/{id}", 500, "", "", time.Millisecond)
+
+	series := rm.Series()
+	require.Len(t, series, 3)
+	assert.Equal(t, RequestSeries{
+		Labels:        RequestLabels{Method: "GET", Route: "/api/properties/{id}", StatusClass: "2xx"},
+		Requests:      2,
+		DurationSumMs: 30,
+	}, series[0])
+	assert.Equal(t, "4xx", series[1].Labels.StatusClass)
+	assert.Equal(t, "OTHER", series[2].Labels.Method)
+}
+
+func TestRequestMetrics_CardinalitySafeguards(t *testing.T) {
+	rm := NewRequestMetrics()
+	rm.Configure(2, 1, true)
+
+	rm.Record("GET", "/api/properties", 200, "agency-1", "tenant-1", time.Millisecond)
+	rm.Record("GET", "/api/agencies", 200, "agency-2", "tenant-1", time.Millisecond)
+	// Routes past the limit and agencies past theirs are folded
+	rm.Record("GET", "/api/users", 200, "agency-1", "", time.Millisecond)
+	// Unknown routes answered 404 do not take a slot
+	rm.Record("GET", "/wp-admin", 404, "", "", time.Millisecond)
+	// Known routes keep their label on 404
+	rm.Record("GET", "/api/properties", 404, "", "", time.Millisecond)
+
+	labels := []RequestLabels{}
+	for _, s := range rm.Series() {
+		labels = append(labels, s.Labels)
+	}
+	assert.ElementsMatch(t, []RequestLabels{
+		{Method: "GET", Route: "/api/properties", StatusClass: "2xx", Agency: "agency-1", Tenant: "tenant-1"},
+		{Method: "GET", Route: "/api/agencies", StatusClass: "2xx", Agency: OverflowLabel, Tenant: "tenant-1"},
+		{Method: "GET", Route: OverflowLabel, StatusClass: "2xx", Agency: "agency-1"},
+		{Method: "GET", Route: UnmatchedRoute, StatusClass: "4xx"},
+		{Method: "GET", Route: "/api/properties", StatusClass: "4xx"},
+	}, labels)
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "2xx", StatusClass(201))
+	assert.Equal(t, "5xx", StatusClass(503))
+	assert.Equal(t, "unknown", StatusClass(0))
+}
@@ -0,0 +1,246 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultKeyID identifies the key configured as a single secret
+const DefaultKeyID = "default"
+
+// MinKeyLength is the shortest accepted key secret
+const MinKeyLength = 32
+
+// encryptedPrefix marks encrypted values: "enc:v2:<key id>:<base64 nonce and ciphertext>".
+// The key ID, column and row ID are authenticated with the value, so it cannot be copied
+// to another row or column. Values without a prefix are plaintext written before
+// encryption was enabled.
+const encryptedPrefix = "enc:v2:"
+
+// legacyPrefix marks values encrypted before the column and row were authenticated; only
+// the key ID was. They are still read, and the pii-reencryption job rewrites them.
+const legacyPrefix = "enc:v1:"
+
+// KeySet holds the PII encryption keys by key ID. Values are encrypted with the
+// ActiveKeyID key and decrypted with whichever key they name, so a rotated key keeps
+// decrypting until the re-encryption job has rewritten its values. IndexKey keys the
+// blind indexes used to look encrypted values up and must never change.
+type KeySet struct {
+	ActiveKeyID string            `json:"active_key_id"`
+	Keys        map[string]string `json:"keys"`
+	IndexKey    string            `json:"index_key"`
+}
+
+// ParseKeySet parses the PII encryption secret. A JSON object such as
+//
+//	{"active_key_id": "2025-08", "keys": {"2025-08": "...", "2025-05": "..."}, "index_key": "..."}
+//
+// is a key set; any other value is a single key with DefaultKeyID that also keys the
+// blind indexes. When moving from a single key to a set, its secret becomes the
+// index_key.
+func ParseKeySet(secret string) (KeySet, error) {
+	trimmed := strings.TrimSpace(secret)
+	if !strings.HasPrefix(trimmed, "{") {
+		keys := KeySet{ActiveKeyID: DefaultKeyID, Keys: map[string]string{DefaultKeyID: secret}, IndexKey: secret}
+		return keys, keys.Validate()
+	}
+
+	var keys KeySet
+	if err := json.Unmarshal([]byte(trimmed), &keys); err != nil {
+		return KeySet{}, fmt.Errorf("invalid PII key set: %w", err)
+	}
+	if err := keys.Validate(); err != nil {
+		return KeySet{}, err
+	}
+	return keys, nil
+}
+
+// Validate verifies the active key is part of the set and every key is long enough
+func (k KeySet) Validate() error {
+	if k.ActiveKeyID == "" {
+		return errors.New("invalid PII key set: active key ID is required")
+	}
+	if _, ok := k.Keys[k.ActiveKeyID]; !ok {
+		return fmt.Errorf("invalid PII key set: active key %s not found", k.ActiveKeyID)
+	}
+	for id, key := range k.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return fmt.Errorf("invalid PII key set: key ID %q cannot be empty or contain ':'", id)
+		}
+		if len(key) < MinKeyLength {
+			return fmt.Errorf("invalid PII key set: key %s must be at least %d characters", id, MinKeyLength)
+		}
+	}
+	if len(k.IndexKey) < MinKeyLength {
+		return fmt.Errorf("invalid PII key set: index key must be at least %d characters", MinKeyLength)
+	}
+	return nil
+}
+
+// Cipher encrypts PII columns with AES-256-GCM. A nil Cipher leaves values in
+// plaintext, so repositories work unchanged while encryption is disabled.
+type Cipher struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+	indexKey    []byte
+}
+
+// NewCipher creates a cipher for a key set
+func NewCipher(keys KeySet) (*Cipher, error) {
+	if err := keys.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{activeKeyID: keys.ActiveKeyID, aeads: map[string]cipher.AEAD{}}
+	for id, secret := range keys.Keys {
+		sum := sha256.Sum256([]byte(secret))
+		block, err := aes.NewCipher(sum[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		c.aeads[id] = aead
+	}
+	index := sha256.Sum256([]byte("pii-blind-index:" + keys.IndexKey))
+	c.indexKey = index[:]
+	return c, nil
+}
+
+// ActiveKeyID returns the ID of the key new values are encrypted with
+func (c *Cipher) ActiveKeyID() string {
+	if c == nil {
+		return ""
+	}
+	return c.activeKeyID
+}
+
+// Encrypt encrypts a value of a column of a row with the active key. Empty values stay
+// empty.
+func (c *Cipher) Encrypt(column Column, rowID, plaintext string) (string, error) {
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := c.aeads[c.activeKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), additionalData(c.activeKeyID, column, rowID))
+	return encryptedPrefix + c.activeKeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value of a column of a row encrypted with any key of the set.
+// Plaintext values are returned as they are.
+func (c *Cipher) Decrypt(column Column, rowID, value string) (string, error) {
+	keyID, data, legacy, ok := parseEncrypted(value)
+	if !ok {
+		return value, nil
+	}
+	if c == nil {
+		return "", errors.New("encrypted value found but PII encryption is not configured")
+	}
+
+	aead, known := c.aeads[keyID]
+	if !known {
+		return "", fmt.Errorf("encrypted value uses unknown PII key %s", keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	aad := additionalData(keyID, column, rowID)
+	if legacy {
+		aad = []byte(keyID)
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value with PII key %s", keyID)
+	}
+	return string(plaintext), nil
+}
+
+// EncryptOptional encrypts an optional value of a column of a row
+func (c *Cipher) EncryptOptional(column Column, rowID string, plaintext *string) (*string, error) {
+	if plaintext == nil {
+		return nil, nil
+	}
+	encrypted, err := c.Encrypt(column, rowID, *plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+// DecryptOptional decrypts an optional value of a column of a row in place
+func (c *Cipher) DecryptOptional(column Column, rowID string, value *string) error {
+	if value == nil {
+		return nil
+	}
+	plaintext, err := c.Decrypt(column, rowID, *value)
+	if err != nil {
+		return err
+	}
+	*value = plaintext
+	return nil
+}
+
+// IsCurrent reports whether a value needs no re-encryption: it is empty or encrypted
+// with the active key
+func (c *Cipher) IsCurrent(value string) bool {
+	if value == "" {
+		return true
+	}
+	keyID, _, legacy, ok := parseEncrypted(value)
+	return ok && !legacy && keyID == c.ActiveKeyID()
+}
+
+// BlindIndex returns a keyed hash of a value for equality lookups on an encrypted
+// column. Without a cipher there is no index.
+func (c *Cipher) BlindIndex(value string) string {
+	value = strings.TrimSpace(value)
+	if c == nil || value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ActivePrefix returns the prefix of the values encrypted with the active key
+func (c *Cipher) ActivePrefix() string {
+	return encryptedPrefix + c.ActiveKeyID() + ":"
+}
+
+// additionalData returns the data authenticated with a value: its key ID, column and row
+func additionalData(keyID string, column Column, rowID string) []byte {
+	return []byte(keyID + ":" + column.Name() + ":" + rowID)
+}
+
+// parseEncrypted splits an encrypted value into its key ID and payload, and reports
+// whether it was written with the legacy format
+func parseEncrypted(value string) (string, string, bool, bool) {
+	prefix, legacy := encryptedPrefix, false
+	if strings.HasPrefix(value, legacyPrefix) {
+		prefix, legacy = legacyPrefix, true
+	} else if !strings.HasPrefix(value, encryptedPrefix) {
+		return "", "", false, false
+	}
+	keyID, data, ok := strings.Cut(value[len(prefix):], ":")
+	if !ok || keyID == "" {
+		return "", "", false, false
+	}
+	return keyID, data, legacy, true
+}
@@ -0,0 +1,142 @@
+package pii
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldKey   = "old-key-0123456789abcdef0123456789"
+	newKey   = "new-key-0123456789abcdef0123456789"
+	indexKey = "index-key-0123456789abcdef01234567"
+)
+
+func TestParseKeySet(t *testing.T) {
+	keys, err := ParseKeySet(oldKey)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultKeyID, keys.ActiveKeyID)
+	assert.Equal(t, oldKey, keys.IndexKey)
+
+	keys, err = ParseKeySet(`{"active_key_id": "2025-08", "keys": {"2025-08": "` + newKey + `", "2025-05": "` + oldKey + `"}, "index_key": "` + indexKey + `"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08", keys.ActiveKeyID)
+	assert.Len(t, keys.Keys, 2)
+
+	_, err = ParseKeySet("short")
+	assert.Error(t, err)
+	_, err = ParseKeySet(`{"active_key_id": "a:b", "keys": {"a:b": "` + newKey + `"}, "index_key": "` + indexKey + `"}`)
+	assert.Error(t, err)
+	_, err = ParseKeySet(`{"active_key_id": "2025-08", "keys": {"2025-08": "` + newKey + `"}}`)
+	assert.Error(t, err, "the index key is required")
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	keys, err := ParseKeySet(oldKey)
+	require.NoError(t, err)
+	c, err := NewCipher(keys)
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt(UserPhone, "user-1", "+593987654321")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v2:default:"))
+	assert.NotContains(t, encrypted, "987654321")
+
+	again, err := c.Encrypt(UserPhone, "user-1", "+593987654321")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "every encryption uses a new nonce")
+
+	plaintext, err := c.Decrypt(UserPhone, "user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "+593987654321", plaintext)
+
+	// Plaintext written before encryption was enabled is read as it is
+	plaintext, err = c.Decrypt(UserPhone, "user-1", "0987654321")
+	require.NoError(t, err)
+	assert.Equal(t, "0987654321", plaintext)
+
+	empty, err := c.Encrypt(UserPhone, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "", empty)
+
+	// Tampered values fail authentication
+	_, err = c.Decrypt(UserPhone, "user-1", encrypted[:len(encrypted)-4]+"AAAA")
+	assert.Error(t, err)
+
+	// Values copied to another row or column fail authentication
+	_, err = c.Decrypt(UserPhone, "user-2", encrypted)
+	assert.Error(t, err)
+	_, err = c.Decrypt(ReservationLeadPhone, "user-1", encrypted)
+	assert.Error(t, err)
+}
+
+func TestCipher_LegacyValues(t *testing.T) {
+	keys, err := ParseKeySet(oldKey)
+	require.NoError(t, err)
+	c, err := NewCipher(keys)
+	require.NoError(t, err)
+
+	// Written before the column and row were authenticated
+	aead := c.aeads[DefaultKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	legacy := "enc:v1:default:" + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte("0987654321"), []byte(DefaultKeyID)))
+
+	plaintext, err := c.Decrypt(UserPhone, "user-1", legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "0987654321", plaintext)
+	assert.False(t, c.IsCurrent(legacy), "legacy values are rewritten by the re-encryption job")
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	oldCipher, err := NewCipher(KeySet{ActiveKeyID: "2025-05", Keys: map[string]string{"2025-05": oldKey}, IndexKey: indexKey})
+	require.NoError(t, err)
+	encrypted, err := oldCipher.Encrypt(DealNotes, "deal-1", "Llamar al 0987654321")
+	require.NoError(t, err)
+
+	rotated, err := NewCipher(KeySet{
+		ActiveKeyID: "2025-08",
+		Keys:        map[string]string{"2025-08": newKey, "2025-05": oldKey},
+		IndexKey:    indexKey,
+	})
+	require.NoError(t, err)
+
+	assert.False(t, rotated.IsCurrent(encrypted))
+	assert.False(t, rotated.IsCurrent("plaintext"))
+	assert.True(t, rotated.IsCurrent(""))
+
+	plaintext, err := rotated.Decrypt(DealNotes, "deal-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "Llamar al 0987654321", plaintext)
+
+	reEncrypted, err := rotated.Encrypt(DealNotes, "deal-1", plaintext)
+	require.NoError(t, err)
+	assert.True(t, rotated.IsCurrent(reEncrypted))
+	assert.True(t, strings.HasPrefix(reEncrypted, rotated.ActivePrefix()))
+
+	// The blind index does not change with the active key
+	assert.Equal(t, oldCipher.BlindIndex("1712345678"), rotated.BlindIndex(" 1712345678 "))
+
+	// Values encrypted with a removed key cannot be read
+	_, err = oldCipher.Decrypt(DealNotes, "deal-1", reEncrypted)
+	assert.ErrorContains(t, err, "unknown PII key 2025-08")
+}
+
+func TestCipher_Nil(t *testing.T) {
+	var c *Cipher
+
+	value, err := c.Encrypt(UserPhone, "user-1", "0987654321")
+	require.NoError(t, err)
+	assert.Equal(t, "0987654321", value)
+	assert.Equal(t, "", c.BlindIndex("1712345678"))
+
+	phone := "0987654321"
+	require.NoError(t, c.DecryptOptional(UserPhone, "user-1", &phone))
+	assert.Equal(t, "0987654321", phone)
+	require.NoError(t, c.DecryptOptional(UserPhone, "user-1", nil))
+
+	_, err = c.Decrypt(UserPhone, "user-1", "enc:v2:default:AAAA")
+	assert.ErrorContains(t, err, "not configured")
+}
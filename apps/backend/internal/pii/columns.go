@@ -0,0 +1,52 @@
+package pii
+
+// Column is a database column holding encrypted PII
+type Column struct {
+	Table  string
+	Column string
+	// IndexColumn holds the blind index of the column, when it is looked up by value
+	IndexColumn string
+}
+
+// Name returns the column as table.column
+func (c Column) Name() string {
+	return c.Table + "." + c.Column
+}
+
+// The encrypted PII columns, and other secrets kept with the same keys such as the
+// OAuth tokens of connected calendars. User emails stay in plaintext: they are the login
+// identifier and are unique per user.
+var (
+	UserPhone                = Column{Table: "users", Column: "phone"}
+	UserNationalID           = Column{Table: "users", Column: "national_id", IndexColumn: "national_id_index"}
+	PhoneVerificationPhone   = Column{Table: "phone_verifications", Column: "phone", IndexColumn: "phone_index"}
+	DealNotes                = Column{Table: "deals", Column: "notes"}
+	RentalApplicationMessage = Column{Table: "rental_applications", Column: "message"}
+	ReservationLeadEmail     = Column{Table: "property_reservations", Column: "lead_email"}
+	ReservationLeadPhone     = Column{Table: "property_reservations", Column: "lead_phone"}
+	CalendarAccessToken      = Column{Table: "calendar_connections", Column: "access_token"}
+	CalendarRefreshToken     = Column{Table: "calendar_connections", Column: "refresh_token"}
+	CallBuyerPhone           = Column{Table: "call_sessions", Column: "buyer_phone", IndexColumn: "buyer_phone_index"}
+	CallAgentPhone           = Column{Table: "call_sessions", Column: "agent_phone", IndexColumn: "agent_phone_index"}
+)
+
+// Columns lists the encrypted columns for the pii-reencryption job
+var Columns = []Column{
+	UserPhone,
+	UserNationalID,
+	PhoneVerificationPhone,
+	DealNotes,
+	RentalApplicationMessage,
+	ReservationLeadEmail,
+	ReservationLeadPhone,
+	CalendarAccessToken,
+	CalendarRefreshToken,
+	CallBuyerPhone,
+	CallAgentPhone,
+}
+
+// Value is the value of an encrypted column in one row
+type Value struct {
+	ID    string
+	Value string
+}
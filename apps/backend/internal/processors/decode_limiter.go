@@ -0,0 +1,163 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrImageTooLarge is returned for images whose decoded pixels exceed the limits of
+	// a single image, whatever the load
+	ErrImageTooLarge = errors.New("image too large to process")
+
+	// ErrImageProcessingBusy is returned when an image could not be decoded within the
+	// wait of the limiter because other decodes hold its memory; retrying later succeeds
+	ErrImageProcessingBusy = errors.New("image processing is busy")
+)
+
+// DecodeLimiterConfig configures a DecodeLimiter
+type DecodeLimiterConfig struct {
+	MaxConcurrent int           // decodes running at once
+	MemoryBytes   int64         // estimated decoded pixels held by all decodes at once
+	MaxPixels     int64         // pixels of a single image
+	Wait          time.Duration // time a decode waits for capacity before failing as busy
+}
+
+// DefaultDecodeLimiterConfig decodes up to four 40MP photos at once in 512MB
+func DefaultDecodeLimiterConfig() DecodeLimiterConfig {
+	return DecodeLimiterConfig{
+		MaxConcurrent: 4,
+		MemoryBytes:   512 * 1024 * 1024,
+		MaxPixels:     100_000_000,
+		Wait:          10 * time.Second,
+	}
+}
+
+// DecodeLimiter bounds the memory of decoded images. Decoding holds every pixel of an
+// image in memory, up to 120MB for a 40MP JPEG, so several large uploads processed at
+// once would exhaust the memory of an instance. Each decode reserves its estimated size
+// before it starts, waiting while the budget or the concurrent decodes are exhausted.
+type DecodeLimiter struct {
+	config DecodeLimiterConfig
+	slots  chan struct{}
+
+	mu       sync.Mutex
+	inUse    int64
+	released chan struct{} // closed and replaced whenever memory is released
+}
+
+// NewDecodeLimiter creates a decode limiter; zero fields take their default
+func NewDecodeLimiter(config DecodeLimiterConfig) *DecodeLimiter {
+	defaults := DefaultDecodeLimiterConfig()
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = defaults.MaxConcurrent
+	}
+	if config.MemoryBytes <= 0 {
+		config.MemoryBytes = defaults.MemoryBytes
+	}
+	if config.MaxPixels <= 0 {
+		config.MaxPixels = defaults.MaxPixels
+	}
+	if config.Wait <= 0 {
+		config.Wait = defaults.Wait
+	}
+
+	return &DecodeLimiter{
+		config:   config,
+		slots:    make(chan struct{}, config.MaxConcurrent),
+		released: make(chan struct{}),
+	}
+}
+
+// Acquire reserves the memory of decoding an image of the given header. The returned
+// function releases it and must be called once the decoded image is no longer needed.
+func (l *DecodeLimiter) Acquire(header image.Config) (func(), error) {
+	pixels := int64(header.Width) * int64(header.Height)
+	bytes := EstimateDecodedBytes(header)
+	if pixels > l.config.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d is %.1f megapixels, the limit is %.1f",
+			ErrImageTooLarge, header.Width, header.Height, float64(pixels)/1e6, float64(l.config.MaxPixels)/1e6)
+	}
+	if bytes > l.config.MemoryBytes {
+		return nil, fmt.Errorf("%w: %dx%d needs about %s to decode, the limit is %s",
+			ErrImageTooLarge, header.Width, header.Height, formatBytes(bytes), formatBytes(l.config.MemoryBytes))
+	}
+
+	deadline := time.NewTimer(l.config.Wait)
+	defer deadline.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-deadline.C:
+		return nil, fmt.Errorf("%w: %d images are being decoded, retry shortly", ErrImageProcessingBusy, l.config.MaxConcurrent)
+	}
+
+	for {
+		l.mu.Lock()
+		if l.inUse+bytes <= l.config.MemoryBytes {
+			l.inUse += bytes
+			l.mu.Unlock()
+			return l.releaseFunc(bytes), nil
+		}
+		released := l.released
+		inUse := l.inUse
+		l.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-deadline.C:
+			<-l.slots
+			return nil, fmt.Errorf("%w: decoding needs %s while %s of %s are in use, retry shortly",
+				ErrImageProcessingBusy, formatBytes(bytes), formatBytes(inUse), formatBytes(l.config.MemoryBytes))
+		}
+	}
+}
+
+// releaseFunc returns the function releasing a reservation, which does nothing after
+// its first call
+func (l *DecodeLimiter) releaseFunc(bytes int64) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			l.inUse -= bytes
+			close(l.released)
+			l.released = make(chan struct{})
+			l.mu.Unlock()
+			<-l.slots
+		})
+	}
+}
+
+// InUse returns the memory reserved by running decodes
+func (l *DecodeLimiter) InUse() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inUse
+}
+
+// EstimateDecodedBytes estimates the memory of the decoded pixels of an image from its
+// header. It is an upper bound: JPEG photos decode to YCbCr, counted as 3 bytes per
+// pixel although subsampled chroma usually takes less, most other images to 4 or 8.
+func EstimateDecodedBytes(header image.Config) int64 {
+	pixels := int64(header.Width) * int64(header.Height)
+	if _, ok := header.ColorModel.(color.Palette); ok {
+		return pixels
+	}
+
+	switch header.ColorModel {
+	case color.YCbCrModel:
+		return pixels * 3
+	case color.GrayModel:
+		return pixels
+	case color.Gray16Model:
+		return pixels * 2
+	case color.RGBA64Model, color.NRGBA64Model:
+		return pixels * 8
+	}
+	return pixels * 4
+}
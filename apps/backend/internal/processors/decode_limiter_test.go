@@ -0,0 +1,94 @@
+package processors
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestEstimateDecodedBytes(t *testing.T) {
+	assert.Equal(t, int64(120_000_000), EstimateDecodedBytes(image.Config{ColorModel: color.YCbCrModel, Width: 8000, Height: 5000}))
+	assert.Equal(t, int64(400), EstimateDecodedBytes(image.Config{ColorModel: color.NRGBAModel, Width: 10, Height: 10}))
+	assert.Equal(t, int64(100), EstimateDecodedBytes(image.Config{ColorModel: color.Palette{color.Black}, Width: 10, Height: 10}))
+	assert.Equal(t, int64(800), EstimateDecodedBytes(image.Config{ColorModel: color.RGBA64Model, Width: 10, Height: 10}))
+}
+
+func TestDecodeLimiter_RejectsImagesOverTheLimits(t *testing.T) {
+	limiter := NewDecodeLimiter(DecodeLimiterConfig{MemoryBytes: 1000, MaxPixels: 500})
+
+	_, err := limiter.Acquire(image.Config{ColorModel: color.GrayModel, Width: 30, Height: 20})
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+	assert.Contains(t, err.Error(), "megapixels")
+
+	_, err = limiter.Acquire(image.Config{ColorModel: color.RGBAModel, Width: 20, Height: 20})
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+	assert.Contains(t, err.Error(), "to decode")
+}
+
+func TestDecodeLimiter_WaitsForMemory(t *testing.T) {
+	limiter := NewDecodeLimiter(DecodeLimiterConfig{MemoryBytes: 1000, Wait: 50 * time.Millisecond})
+	header := image.Config{ColorModel: color.RGBAModel, Width: 10, Height: 20} // 800 bytes
+
+	release, err := limiter.Acquire(header)
+	require.NoError(t, err)
+	assert.Equal(t, int64(800), limiter.InUse())
+
+	// The budget is taken until the first decode is released
+	_, err = limiter.Acquire(header)
+	assert.ErrorIs(t, err, ErrImageProcessingBusy)
+
+	acquired := make(chan error, 1)
+	go func() {
+		second, err := limiter.Acquire(header)
+		if err == nil {
+			second()
+		}
+		acquired <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	release()
+	release() // released once only
+	assert.NoError(t, <-acquired)
+	assert.Equal(t, int64(0), limiter.InUse())
+}
+
+func TestDecodeLimiter_LimitsConcurrentDecodes(t *testing.T) {
+	limiter := NewDecodeLimiter(DecodeLimiterConfig{MaxConcurrent: 1, Wait: 20 * time.Millisecond})
+	header := image.Config{ColorModel: color.GrayModel, Width: 1, Height: 1}
+
+	release, err := limiter.Acquire(header)
+	require.NoError(t, err)
+	_, err = limiter.Acquire(header)
+	assert.ErrorIs(t, err, ErrImageProcessingBusy)
+
+	release()
+	release, err = limiter.Acquire(header)
+	require.NoError(t, err)
+	release()
+}
+
+func TestImageProcessor_DecodeLimits(t *testing.T) {
+	var photo bytes.Buffer
+	require.NoError(t, jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 400, 300)), nil))
+
+	processor := NewImageProcessor(1920, 1080)
+	processor.SetDecodeLimiter(NewDecodeLimiter(DecodeLimiterConfig{MaxPixels: 100_000}))
+
+	_, err := processor.GenerateThumbnail(photo.Bytes(), domain.ThumbnailSize)
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+	assert.ErrorIs(t, processor.ValidateImageData(photo.Bytes(), 0), ErrImageTooLarge)
+
+	// Dimensions are read from the header, without decoding
+	width, height, _, err := processor.GetImageDimensions(photo.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 400, width)
+	assert.Equal(t, 300, height)
+}
@@ -0,0 +1,357 @@
+package processors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// EXIF/TIFF tags read from uploaded photos
+const (
+	tagMake             = 0x010F
+	tagModel            = 0x0110
+	tagOrientation      = 0x0112
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagDateTimeOriginal = 0x9003
+)
+
+// JPEG markers
+const (
+	markerSOI   = 0xD8
+	markerEOI   = 0xD9
+	markerSOS   = 0xDA
+	markerAPP0  = 0xE0 // JFIF
+	markerAPP1  = 0xE1 // EXIF and XMP
+	markerAPP13 = 0xED // Photoshop IPTC
+	markerCOM   = 0xFE
+)
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// exifDateLayout is the EXIF date format ("2024:03:15 10:30:00")
+const exifDateLayout = "2006:01:02 15:04:05"
+
+// ScrubMetadata removes EXIF, XMP, IPTC and comment metadata from JPEG and PNG data
+// without re-encoding pixels, and returns the capture date and camera data found.
+// GPS coordinates are never returned; HadLocation only reports that they were removed.
+// When orientation is preserved, a minimal EXIF block holding only the orientation
+// tag is written back so the photo keeps displaying upright.
+func (ip *ImageProcessor) ScrubMetadata(data []byte) ([]byte, *domain.ImageEXIF, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, markerSOI}):
+		return ip.scrubJPEG(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return scrubPNG(data)
+	default:
+		// Other formats are re-encoded by the optimizer, which drops their metadata
+		return data, &domain.ImageEXIF{Orientation: 1}, nil
+	}
+}
+
+// scrubJPEG drops APP1, APP13 and COM segments from a JPEG
+func (ip *ImageProcessor) scrubJPEG(data []byte) ([]byte, *domain.ImageEXIF, error) {
+	exif := &domain.ImageEXIF{Orientation: 1}
+	output := bytes.NewBuffer(make([]byte, 0, len(data)))
+	output.Write(data[:2])
+
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xFF || pos+1 >= len(data) {
+			return nil, nil, fmt.Errorf("invalid JPEG segment at offset %d", pos)
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			pos++ // fill byte
+			continue
+		}
+		if marker == markerEOI || (marker >= 0xD0 && marker <= 0xD7) {
+			output.Write(data[pos : pos+2])
+			pos += 2
+			continue
+		}
+		if pos+4 > len(data) {
+			return nil, nil, fmt.Errorf("truncated JPEG segment at offset %d", pos)
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, nil, fmt.Errorf("invalid JPEG segment length at offset %d", pos)
+		}
+		payload := data[pos+4 : end]
+
+		switch marker {
+		case markerAPP1:
+			if bytes.HasPrefix(payload, exifHeader) {
+				parseTIFF(payload[len(exifHeader):], exif)
+			}
+		case markerAPP13, markerCOM:
+		case markerSOS:
+			// Entropy-coded data follows; copy the rest of the file unchanged
+			if ip.preserveOrientation && exif.Orientation > 1 {
+				return insertAfterAPP0(output.Bytes(), data[pos:], exif.Orientation), exif, nil
+			}
+			output.Write(data[pos:])
+			return output.Bytes(), exif, nil
+		default:
+			output.Write(data[pos:end])
+		}
+		pos = end
+	}
+
+	if ip.preserveOrientation && exif.Orientation > 1 {
+		return insertAfterAPP0(output.Bytes(), nil, exif.Orientation), exif, nil
+	}
+	return output.Bytes(), exif, nil
+}
+
+// insertAfterAPP0 writes an orientation-only EXIF segment after SOI and any APP0 (JFIF) segment
+func insertAfterAPP0(head, tail []byte, orientation int) []byte {
+	pos := 2
+	for pos+4 <= len(head) && head[pos] == 0xFF && head[pos+1] == markerAPP0 {
+		pos += 2 + int(binary.BigEndian.Uint16(head[pos+2:pos+4]))
+	}
+
+	result := make([]byte, 0, len(head)+len(tail)+40)
+	result = append(result, head[:pos]...)
+	result = append(result, orientationSegment(orientation)...)
+	result = append(result, head[pos:]...)
+	return append(result, tail...)
+}
+
+// orientationSegment builds an APP1 EXIF segment containing only the orientation tag
+func orientationSegment(orientation int) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // big-endian header, IFD0 at offset 8
+		0x00, 0x01, // one entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, // orientation, SHORT, count 1
+		0x00, byte(orientation), 0x00, 0x00, // value
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	payload := append(append([]byte{}, exifHeader...), tiff...)
+
+	segment := []byte{0xFF, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// scrubPNG drops eXIf and text chunks from a PNG
+func scrubPNG(data []byte) ([]byte, *domain.ImageEXIF, error) {
+	exif := &domain.ImageEXIF{Orientation: 1}
+	output := bytes.NewBuffer(make([]byte, 0, len(data)))
+	output.Write(pngSignature)
+
+	pos := len(pngSignature)
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, nil, fmt.Errorf("invalid PNG chunk at offset %d", pos)
+		}
+
+		switch chunkType {
+		case "eXIf":
+			parseTIFF(data[pos+8:pos+8+length], exif)
+		case "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			output.Write(data[pos:end])
+		}
+		pos = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return output.Bytes(), exif, nil
+}
+
+// ReadOrientation returns the EXIF orientation (1-8) of JPEG or PNG data, or 1 when absent
+func ReadOrientation(data []byte) int {
+	exif := &domain.ImageEXIF{Orientation: 1}
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, markerSOI}):
+		pos := 2
+		for pos+4 <= len(data) && data[pos] == 0xFF {
+			marker := data[pos+1]
+			if marker == markerSOS || marker == markerEOI {
+				break
+			}
+			end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:pos+4]))
+			if end > len(data) {
+				break
+			}
+			if marker == markerAPP1 && bytes.HasPrefix(data[pos+4:end], exifHeader) {
+				parseTIFF(data[pos+4+len(exifHeader):end], exif)
+				break
+			}
+			pos = end
+		}
+	case bytes.HasPrefix(data, pngSignature):
+		pos := len(pngSignature)
+		for pos+8 <= len(data) {
+			length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+			end := pos + 12 + length
+			if end > len(data) {
+				break
+			}
+			if string(data[pos+4:pos+8]) == "eXIf" {
+				parseTIFF(data[pos+8:pos+8+length], exif)
+				break
+			}
+			pos = end
+		}
+	}
+	return exif.Orientation
+}
+
+// parseTIFF reads the tags of interest from a TIFF-structured EXIF block.
+// Malformed data is ignored; whatever was read before the error is kept.
+func parseTIFF(tiff []byte, exif *domain.ImageEXIF) {
+	if len(tiff) < 8 {
+		return
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return
+	}
+
+	var dateTime, dateTimeOriginal string
+	readIFD(tiff, order, order.Uint32(tiff[4:8]), func(tag, valueType uint16, value []byte) {
+		switch tag {
+		case tagMake:
+			exif.CameraMake = asciiValue(value)
+		case tagModel:
+			exif.CameraModel = asciiValue(value)
+		case tagOrientation:
+			if valueType == 3 && len(value) >= 2 {
+				if orientation := int(order.Uint16(value)); orientation >= 1 && orientation <= 8 {
+					exif.Orientation = orientation
+				}
+			}
+		case tagDateTime:
+			dateTime = asciiValue(value)
+		case tagGPSIFD:
+			exif.HadLocation = true
+		case tagExifIFD:
+			if len(value) >= 4 {
+				readIFD(tiff, order, order.Uint32(value), func(tag, _ uint16, value []byte) {
+					if tag == tagDateTimeOriginal {
+						dateTimeOriginal = asciiValue(value)
+					}
+				})
+			}
+		}
+	})
+
+	for _, candidate := range []string{dateTimeOriginal, dateTime} {
+		if capturedAt, err := time.Parse(exifDateLayout, candidate); err == nil {
+			exif.CapturedAt = &capturedAt
+			break
+		}
+	}
+}
+
+// tiffTypeSizes maps TIFF field types to their size in bytes
+var tiffTypeSizes = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+// readIFD calls visit with the raw value of every entry in the IFD at offset
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32, visit func(tag, valueType uint16, value []byte)) {
+	if int(offset)+2 > len(tiff) {
+		return
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+
+	for i := 0; i < count; i++ {
+		entry := int(offset) + 2 + i*12
+		if entry+12 > len(tiff) {
+			return
+		}
+		tag := order.Uint16(tiff[entry : entry+2])
+		valueType := order.Uint16(tiff[entry+2 : entry+4])
+		typeSize, ok := tiffTypeSizes[valueType]
+		if !ok {
+			continue
+		}
+
+		size := typeSize * int(order.Uint32(tiff[entry+4:entry+8]))
+		if size < 0 || size > len(tiff) {
+			continue
+		}
+		value := tiff[entry+8 : entry+12]
+		if size > 4 {
+			valueOffset := int(order.Uint32(tiff[entry+8 : entry+12]))
+			if valueOffset+size > len(tiff) {
+				continue
+			}
+			value = tiff[valueOffset : valueOffset+size]
+		} else {
+			value = value[:size]
+		}
+
+		visit(tag, valueType, value)
+	}
+}
+
+// asciiValue trims the NUL terminator and padding of an ASCII tag value
+func asciiValue(value []byte) string {
+	return strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+}
+
+// applyOrientation transforms pixels so an image with the given EXIF orientation displays upright
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = w-1-x, y
+			case 3: // rotated 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90 clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90 counter-clockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
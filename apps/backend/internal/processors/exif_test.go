@@ -0,0 +1,237 @@
+package processors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"realty-core/internal/domain"
+)
+
+// tiffEntry is an IFD entry used to build test EXIF blocks
+type tiffEntry struct {
+	tag       uint16
+	valueType uint16
+	count     uint32
+	data      []byte
+}
+
+// buildIFD appends an IFD with its out-of-line values to tiff and returns its offset
+func buildIFD(tiff []byte, entries []tiffEntry) ([]byte, uint32) {
+	order := binary.LittleEndian
+	offset := uint32(len(tiff))
+	dataOffset := offset + 2 + uint32(len(entries))*12 + 4
+
+	ifd := order.AppendUint16(nil, uint16(len(entries)))
+	var data []byte
+	for _, entry := range entries {
+		ifd = order.AppendUint16(ifd, entry.tag)
+		ifd = order.AppendUint16(ifd, entry.valueType)
+		ifd = order.AppendUint32(ifd, entry.count)
+		if len(entry.data) <= 4 {
+			ifd = append(ifd, append(entry.data, make([]byte, 4-len(entry.data))...)...)
+		} else {
+			ifd = order.AppendUint32(ifd, dataOffset+uint32(len(data)))
+			data = append(data, entry.data...)
+		}
+	}
+	ifd = order.AppendUint32(ifd, 0)
+
+	return append(append(tiff, ifd...), data...), offset
+}
+
+func asciiEntry(tag uint16, value string) tiffEntry {
+	return tiffEntry{tag: tag, valueType: 2, count: uint32(len(value) + 1), data: append([]byte(value), 0)}
+}
+
+func longEntry(tag uint16, value uint32) tiffEntry {
+	return tiffEntry{tag: tag, valueType: 4, count: 1, data: binary.LittleEndian.AppendUint32(nil, value)}
+}
+
+// buildTestEXIF builds a little-endian TIFF block with camera, date, orientation and GPS tags
+func buildTestEXIF(orientation uint16) []byte {
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+
+	// Sub-IFDs are written after IFD0, so lay out IFD0 first with placeholder pointers
+	ifd0 := []tiffEntry{
+		asciiEntry(tagMake, "Canon"),
+		asciiEntry(tagModel, "EOS R6"),
+		{tag: tagOrientation, valueType: 3, count: 1, data: binary.LittleEndian.AppendUint16(nil, orientation)},
+		asciiEntry(tagDateTime, "2024:01:01 00:00:00"),
+		longEntry(tagExifIFD, 0),
+		longEntry(tagGPSIFD, 0),
+	}
+	tiff, _ = buildIFD(tiff, ifd0)
+
+	tiff, exifOffset := buildIFD(tiff, []tiffEntry{asciiEntry(tagDateTimeOriginal, "2024:03:15 10:30:00")})
+	tiff, gpsOffset := buildIFD(tiff, []tiffEntry{asciiEntry(0x0001, "S"), {tag: 0x0002, valueType: 5, count: 3, data: make([]byte, 24)}})
+
+	// Patch the sub-IFD pointers (entries 4 and 5 of IFD0)
+	binary.LittleEndian.PutUint32(tiff[8+2+4*12+8:], exifOffset)
+	binary.LittleEndian.PutUint32(tiff[8+2+5*12+8:], gpsOffset)
+	return tiff
+}
+
+// createJPEGWithEXIF creates a JPEG of the given size carrying an EXIF and a comment segment
+func createJPEGWithEXIF(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{255, 0, 0, 255}) // red top row
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+	encoded := buf.Bytes()
+
+	payload := append(append([]byte{}, exifHeader...), buildTestEXIF(orientation)...)
+	app1 := []byte{0xFF, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	app1 = append(app1, payload...)
+
+	comment := []byte("shot at home")
+	com := []byte{0xFF, markerCOM, 0, byte(len(comment) + 2)}
+	com = append(com, comment...)
+
+	result := append([]byte{}, encoded[:2]...)
+	result = append(result, app1...)
+	result = append(result, com...)
+	return append(result, encoded[2:]...)
+}
+
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestImageProcessor_ScrubMetadata_JPEG(t *testing.T) {
+	data := createJPEGWithEXIF(t, 40, 20, 6)
+
+	t.Run("strips metadata and keeps capture data", func(t *testing.T) {
+		processor := NewImageProcessor(0, 0)
+		processor.SetPreserveOrientation(false)
+
+		scrubbed, exif, err := processor.ScrubMetadata(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Canon", exif.CameraMake)
+		assert.Equal(t, "EOS R6", exif.CameraModel)
+		assert.Equal(t, 6, exif.Orientation)
+		assert.True(t, exif.HadLocation)
+		require.NotNil(t, exif.CapturedAt)
+		assert.Equal(t, time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC), *exif.CapturedAt)
+
+		assert.False(t, bytes.Contains(scrubbed, exifHeader))
+		assert.False(t, bytes.Contains(scrubbed, []byte("Canon")))
+		assert.False(t, bytes.Contains(scrubbed, []byte("shot at home")))
+		assert.Equal(t, 1, ReadOrientation(scrubbed))
+
+		_, _, err = image.Decode(bytes.NewReader(scrubbed))
+		assert.NoError(t, err)
+	})
+
+	t.Run("preserves only the orientation tag", func(t *testing.T) {
+		processor := NewImageProcessor(0, 0)
+
+		scrubbed, _, err := processor.ScrubMetadata(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, 6, ReadOrientation(scrubbed))
+		assert.False(t, bytes.Contains(scrubbed, []byte("Canon")))
+
+		_, exif, err := processor.ScrubMetadata(scrubbed)
+		require.NoError(t, err)
+		assert.False(t, exif.HadLocation)
+		assert.Nil(t, exif.CapturedAt)
+	})
+
+	t.Run("processing applies orientation", func(t *testing.T) {
+		processor := NewImageProcessor(0, 0)
+
+		width, height, _, err := processor.GetImageDimensions(data)
+		require.NoError(t, err)
+		assert.Equal(t, 20, width)
+		assert.Equal(t, 40, height)
+
+		output, _, err := processor.ProcessImage(data, domain.ProcessingOptions{Quality: 95, Format: "png", PreserveAspect: true})
+		require.NoError(t, err)
+
+		img, _, err := image.Decode(bytes.NewReader(output))
+		require.NoError(t, err)
+		assert.Equal(t, 20, img.Bounds().Dx())
+		assert.Equal(t, 40, img.Bounds().Dy())
+
+		// The red top row ends up as the right column after a 90 degree clockwise rotation
+		r, g, _, _ := img.At(19, 20).RGBA()
+		assert.Greater(t, r, g)
+
+		// The box applies to the displayed image although it is scaled before rotating
+		output, _, err = processor.ProcessImage(data, domain.ProcessingOptions{MaxWidth: 10, MaxHeight: 40, Quality: 95, Format: "png", PreserveAspect: true})
+		require.NoError(t, err)
+		img, _, err = image.Decode(bytes.NewReader(output))
+		require.NoError(t, err)
+		assert.Equal(t, 10, img.Bounds().Dx())
+		assert.Equal(t, 20, img.Bounds().Dy())
+	})
+}
+
+func TestImageProcessor_ScrubMetadata_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	encoded := buf.Bytes()
+
+	// Insert eXIf and tEXt chunks after IHDR (8-byte signature + 25-byte IHDR chunk)
+	data := append([]byte{}, encoded[:33]...)
+	data = append(data, pngChunk("eXIf", buildTestEXIF(1))...)
+	data = append(data, pngChunk("tEXt", []byte("Comment\x00shot at home"))...)
+	data = append(data, encoded[33:]...)
+
+	scrubbed, exif, err := NewImageProcessor(0, 0).ScrubMetadata(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Canon", exif.CameraMake)
+	assert.True(t, exif.HadLocation)
+	assert.False(t, bytes.Contains(scrubbed, []byte("eXIf")))
+	assert.False(t, bytes.Contains(scrubbed, []byte("shot at home")))
+
+	_, err = png.Decode(bytes.NewReader(scrubbed))
+	assert.NoError(t, err)
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 2x1 image: red pixel on the left, blue on the right
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{0, 0, 255, 255})
+
+	red := color.RGBA{255, 0, 0, 255}
+	tests := []struct {
+		orientation int
+		width       int
+		redX, redY  int
+	}{
+		{1, 2, 0, 0},
+		{2, 2, 1, 0},
+		{3, 2, 1, 0},
+		{4, 2, 0, 0},
+		{5, 1, 0, 0},
+		{6, 1, 0, 0},
+		{7, 1, 0, 1},
+		{8, 1, 0, 1},
+	}
+
+	for _, tt := range tests {
+		result := applyOrientation(img, tt.orientation)
+		assert.Equal(t, tt.width, result.Bounds().Dx(), "orientation %d", tt.orientation)
+		assert.Equal(t, red, color.RGBAModel.Convert(result.At(tt.redX, tt.redY)), "orientation %d", tt.orientation)
+	}
+}
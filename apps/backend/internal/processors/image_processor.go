@@ -2,6 +2,7 @@ package processors
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
@@ -18,6 +19,12 @@ import (
 type ImageProcessor struct {
 	maxWidth  int
 	maxHeight int
+	// preserveOrientation applies the EXIF orientation to the pixels so photos
+	// stay upright once their metadata is stripped
+	preserveOrientation bool
+	// limiter bounds the memory of images decoded at once, possibly shared by several
+	// processors
+	limiter *DecodeLimiter
 }
 
 // NewImageProcessor creates a new image processor
@@ -32,9 +39,45 @@ func NewImageProcessor(maxWidth, maxHeight int) *ImageProcessor {
 	return &ImageProcessor{
 		maxWidth:  maxWidth,
 		maxHeight: maxHeight,
+		preserveOrientation: true,
+		limiter:             NewDecodeLimiter(DefaultDecodeLimiterConfig()),
 	}
 }
 
+// SetPreserveOrientation controls whether the EXIF orientation is kept when metadata is stripped
+func (ip *ImageProcessor) SetPreserveOrientation(preserve bool) {
+	ip.preserveOrientation = preserve
+}
+
+// SetDecodeLimiter sets the limiter bounding the memory of decoded images, to share one
+// between processors
+func (ip *ImageProcessor) SetDecodeLimiter(limiter *DecodeLimiter) {
+	if limiter != nil {
+		ip.limiter = limiter
+	}
+}
+
+// decode decodes an image once the decode limiter has room for its pixels, which are
+// estimated from its header. release must be called when the decoded image is no
+// longer used. The standard decoders cannot decode at a reduced scale, so callers
+// downscale the decoded image before anything else allocates at full size.
+func (ip *ImageProcessor) decode(data []byte) (img image.Image, format string, release func(), err error) {
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if release, err = ip.limiter.Acquire(header); err != nil {
+		return nil, "", nil, err
+	}
+
+	img, format, err = image.Decode(bytes.NewReader(data))
+	if err != nil {
+		release()
+		return nil, "", nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, format, release, nil
+}
+
 // ProcessImage processes an image with the given options
 func (ip *ImageProcessor) ProcessImage(inputData []byte, options domain.ProcessingOptions) ([]byte, *domain.ImageStats, error) {
 	start := time.Now()
@@ -46,16 +89,29 @@ func (ip *ImageProcessor) ProcessImage(inputData []byte, options domain.Processi
 	}
 	
 	// Decode input image
-	inputImage, inputFormat, err := image.Decode(bytes.NewReader(inputData))
+	inputImage, inputFormat, release, err := ip.decode(inputData)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, nil, err
+	}
+	defer release()
+	
+	// Re-encoding drops all metadata, so bake the orientation into the pixels. The image
+	// is scaled first, so only the scaled copy is rotated.
+	orientation := 1
+	if ip.preserveOrientation {
+		orientation = ReadOrientation(inputData)
+	}
+	if !options.PreserveAspect {
+		inputImage = applyOrientation(inputImage, orientation)
+		orientation = 1
 	}
 	
 	// Process the image
-	processedImage, err := ip.processImageWithOptions(inputImage, options)
+	processedImage, err := ip.processImageWithOptions(inputImage, ip.unorientedOptions(options, orientation))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to process image: %w", err)
 	}
+	processedImage = applyOrientation(processedImage, orientation)
 	
 	// Encode output image
 	outputData, err := ip.encodeImage(processedImage, options.Format, options.Quality)
@@ -104,6 +160,22 @@ func (ip *ImageProcessor) processImageWithOptions(inputImage image.Image, option
 	return newImage, nil
 }
 
+// unorientedOptions returns the options of scaling an image before its orientation is
+// applied: images displayed rotated by 90 degrees fit the box with its sides swapped
+func (ip *ImageProcessor) unorientedOptions(options domain.ProcessingOptions, orientation int) domain.ProcessingOptions {
+	if orientation < 5 || orientation > 8 {
+		return options
+	}
+	if options.MaxWidth <= 0 {
+		options.MaxWidth = ip.maxWidth
+	}
+	if options.MaxHeight <= 0 {
+		options.MaxHeight = ip.maxHeight
+	}
+	options.MaxWidth, options.MaxHeight = options.MaxHeight, options.MaxWidth
+	return options
+}
+
 // calculateDimensions calculates new image dimensions based on constraints
 func (ip *ImageProcessor) calculateDimensions(originalWidth, originalHeight int, options domain.ProcessingOptions) (int, int) {
 	maxWidth := options.MaxWidth
@@ -235,15 +307,19 @@ func (ip *ImageProcessor) OptimizeForSize(inputData []byte, targetSizeKB int) ([
 	return ip.ProcessImage(inputData, options)
 }
 
-// GetImageDimensions returns image dimensions without full processing
+// GetImageDimensions returns image dimensions from the image header, without decoding
+// its pixels
 func (ip *ImageProcessor) GetImageDimensions(inputData []byte) (int, int, string, error) {
-	img, format, err := image.Decode(bytes.NewReader(inputData))
+	header, format, err := image.DecodeConfig(bytes.NewReader(inputData))
 	if err != nil {
 		return 0, 0, "", fmt.Errorf("failed to decode image: %w", err)
 	}
 	
-	bounds := img.Bounds()
-	return bounds.Dx(), bounds.Dy(), format, nil
+	// Report dimensions as displayed once the orientation is applied
+	if ip.preserveOrientation && ReadOrientation(inputData) >= 5 {
+		return header.Height, header.Width, format, nil
+	}
+	return header.Width, header.Height, format, nil
 }
 
 // ValidateImageData validates image data and returns basic info
@@ -257,10 +333,14 @@ func (ip *ImageProcessor) ValidateImageData(data []byte, maxSize int64) error {
 	}
 	
 	// Try to decode to validate format
-	_, _, err := image.Decode(bytes.NewReader(data))
+	_, _, release, err := ip.decode(data)
 	if err != nil {
+		if errors.Is(err, ErrImageTooLarge) || errors.Is(err, ErrImageProcessingBusy) {
+			return err
+		}
 		return fmt.Errorf("invalid image format: %w", err)
 	}
+	release()
 	
 	return nil
 }
@@ -311,6 +391,75 @@ func (ip *ImageProcessor) GenerateImageVariant(inputData []byte, width, height i
 	return result, err
 }
 
+// GenerateSquareVariant crops the center square of an image and scales it to size,
+// as used for avatars and logos. Images smaller than size are not upscaled.
+func (ip *ImageProcessor) GenerateSquareVariant(inputData []byte, size int, quality int, format string) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid size: %d", size)
+	}
+	return ip.GenerateFilledVariant(inputData, size, size, quality, format)
+}
+
+// GenerateFilledVariant crops the center of an image to the aspect ratio of width x height
+// and scales it to fill them, as used for cards and social previews. Images smaller than
+// the box are not upscaled: the crop keeps its aspect ratio at its own size.
+func (ip *ImageProcessor) GenerateFilledVariant(inputData []byte, width, height int, quality int, format string) ([]byte, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid dimensions: %dx%d", width, height)
+	}
+	if quality <= 0 || quality > 100 {
+		quality = domain.DefaultQuality
+	}
+	if format == "" {
+		format = "jpg"
+	}
+	
+	inputImage, _, release, err := ip.decode(inputData)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	// The crop is taken before the orientation is applied, so only the scaled image is
+	// rotated; images displayed rotated by 90 degrees are cropped with the box swapped
+	orientation := 1
+	if ip.preserveOrientation {
+		orientation = ReadOrientation(inputData)
+	}
+	if orientation >= 5 && orientation <= 8 {
+		width, height = height, width
+	}
+	
+	// Center of the image with the aspect ratio of the box
+	bounds := inputImage.Bounds()
+	cropWidth, cropHeight := bounds.Dx(), bounds.Dx()*height/width
+	if cropHeight > bounds.Dy() {
+		cropWidth, cropHeight = bounds.Dy()*width/height, bounds.Dy()
+	}
+	x0 := bounds.Min.X + (bounds.Dx()-cropWidth)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-cropHeight)/2
+	crop := image.Rect(x0, y0, x0+cropWidth, y0+cropHeight)
+	
+	if width > cropWidth {
+		width, height = cropWidth, cropHeight
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	filled := image.NewRGBA(image.Rect(0, 0, width, height))
+	
+	// JPEG has no transparency: paint transparent logos on white instead of black
+	if format != "png" {
+		draw.Draw(filled, filled.Bounds(), image.White, image.Point{}, draw.Src)
+	}
+	draw.CatmullRom.Scale(filled, filled.Bounds(), inputImage, crop, draw.Over, nil)
+	
+	return ip.encodeImage(applyOrientation(filled, orientation), format, quality)
+}
+
 // formatBytes formats bytes to human readable string
 func formatBytes(bytes int64) string {
 	if bytes < 1024 {
@@ -432,6 +432,53 @@ func TestImageProcessor_GenerateImageVariant(t *testing.T) {
 	}
 }
 
+func TestImageProcessor_GenerateSquareVariant(t *testing.T) {
+	processor := NewImageProcessor(1920, 1080)
+
+	variant, err := processor.GenerateSquareVariant(createTestImage(400, 300, "jpeg"), 128, 85, "jpg")
+	require.NoError(t, err)
+	width, height, format, err := processor.GetImageDimensions(variant)
+	require.NoError(t, err)
+	assert.Equal(t, 128, width)
+	assert.Equal(t, 128, height)
+	assert.Equal(t, "jpeg", format)
+
+	// Small images are cropped but not upscaled
+	variant, err = processor.GenerateSquareVariant(createTestImage(100, 80, "png"), 256, 85, "png")
+	require.NoError(t, err)
+	width, height, format, err = processor.GetImageDimensions(variant)
+	require.NoError(t, err)
+	assert.Equal(t, 80, width)
+	assert.Equal(t, 80, height)
+	assert.Equal(t, "png", format)
+
+	_, err = processor.GenerateSquareVariant([]byte("not an image"), 128, 85, "jpg")
+	assert.Error(t, err)
+}
+
+func TestImageProcessor_GenerateFilledVariant(t *testing.T) {
+	processor := NewImageProcessor(1920, 1080)
+
+	// A 4:3 photo is cropped to the 1.9:1 of social previews
+	variant, err := processor.GenerateFilledVariant(createTestImage(800, 600, "jpeg"), 380, 200, 85, "jpg")
+	require.NoError(t, err)
+	width, height, _, err := processor.GetImageDimensions(variant)
+	require.NoError(t, err)
+	assert.Equal(t, 380, width)
+	assert.Equal(t, 200, height)
+
+	// Small images keep the aspect ratio of the box at their own size
+	variant, err = processor.GenerateFilledVariant(createTestImage(300, 300, "png"), 600, 200, 85, "png")
+	require.NoError(t, err)
+	width, height, _, err = processor.GetImageDimensions(variant)
+	require.NoError(t, err)
+	assert.Equal(t, 300, width)
+	assert.Equal(t, 100, height)
+
+	_, err = processor.GenerateFilledVariant(createTestImage(300, 300, "png"), 0, 200, 85, "png")
+	assert.Error(t, err)
+}
+
 func TestImageProcessor_calculateDimensions(t *testing.T) {
 	processor := NewImageProcessor(1920, 1080)
 	
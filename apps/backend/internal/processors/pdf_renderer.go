@@ -0,0 +1,206 @@
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// PDFRenderer renders plain text documents, such as generated contracts, as PDF
+type PDFRenderer interface {
+	// Render lays out a titled text document. Blank lines separate paragraphs.
+	Render(title, text string) ([]byte, error)
+}
+
+// A4 page layout in points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+)
+
+// TextPDFRenderer renders text on A4 pages with the standard Helvetica fonts, so no font
+// files are embedded. Text is encoded as WinAnsi, which covers Spanish; other characters
+// are replaced with '?'.
+type TextPDFRenderer struct {
+	FontSize  float64
+	TitleSize float64
+}
+
+// NewTextPDFRenderer creates a PDF renderer with the default font sizes
+func NewTextPDFRenderer() *TextPDFRenderer {
+	return &TextPDFRenderer{FontSize: 11, TitleSize: 15}
+}
+
+// pdfLine is a laid out line of text
+type pdfLine struct {
+	text string
+	bold bool
+	size float64
+}
+
+// Render lays out a titled text document as a PDF
+func (r *TextPDFRenderer) Render(title, text string) ([]byte, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("document text cannot be empty")
+	}
+
+	pages := r.paginate(r.layout(title, text))
+
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-5 are fixed; each page then takes a page and a content object
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (realty-core) >>", pdfString(title)))
+
+	for i, page := range pages {
+		content := r.pageContent(page)
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+// layout wraps the title and paragraphs to the page width
+func (r *TextPDFRenderer) layout(title, text string) []pdfLine {
+	lines := []pdfLine{}
+	if title = strings.TrimSpace(title); title != "" {
+		for _, line := range wrapText(title, r.lineWidth(r.TitleSize)) {
+			lines = append(lines, pdfLine{text: line, bold: true, size: r.TitleSize})
+		}
+		lines = append(lines, pdfLine{size: r.FontSize})
+	}
+
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, pdfLine{size: r.FontSize})
+			continue
+		}
+		for _, line := range wrapText(raw, r.lineWidth(r.FontSize)) {
+			lines = append(lines, pdfLine{text: line, size: r.FontSize})
+		}
+	}
+	return lines
+}
+
+// paginate splits lines into pages
+func (r *TextPDFRenderer) paginate(lines []pdfLine) [][]pdfLine {
+	pages := [][]pdfLine{}
+	page := []pdfLine{}
+	used := 0.0
+	for _, line := range lines {
+		leading := line.size * 1.4
+		if used+leading > pdfPageHeight-2*pdfMargin && len(page) > 0 {
+			pages = append(pages, page)
+			page, used = []pdfLine{}, 0
+		}
+		page = append(page, line)
+		used += leading
+	}
+	return append(pages, page)
+}
+
+// pageContent returns the content stream drawing the lines of a page
+func (r *TextPDFRenderer) pageContent(lines []pdfLine) string {
+	var b strings.Builder
+	y := pdfPageHeight - pdfMargin
+	for _, line := range lines {
+		y -= line.size * 1.4
+		if line.text == "" {
+			continue
+		}
+		font := "F1"
+		if line.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&b, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, line.size, pdfMargin, y, pdfString(line.text))
+	}
+	return b.String()
+}
+
+// lineWidth approximates the characters per line from Helvetica's average glyph width
+func (r *TextPDFRenderer) lineWidth(size float64) int {
+	return int((pdfPageWidth - 2*pdfMargin) / (size * 0.5))
+}
+
+// wrapText breaks text into lines of at most width characters at word boundaries
+func wrapText(text string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// winAnsiPunctuation maps typographic characters outside Latin-1 to WinAnsi
+var winAnsiPunctuation = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfString encodes text as a WinAnsi PDF string literal body
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := winAnsiPunctuation[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package processors
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextPDFRenderer_Render(t *testing.T) {
+	renderer := NewTextPDFRenderer()
+
+	data, err := renderer.Render("Contrato de arrendamiento", "Arrendador: José Muñoz (propietario)\n\nCanon mensual: $800 — pagadero por adelantado")
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	assert.Contains(t, string(data), "/Count 1")
+	assert.Contains(t, string(data), "Jos\xe9 Mu\xf1oz \\(propietario\\)", "text is WinAnsi encoded and escaped")
+	assert.Contains(t, string(data), "$800 \x97 pagadero")
+
+	// startxref points at the cross-reference table
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	require.NotNil(t, match)
+	offset, err := strconv.Atoi(string(match[1]))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data[offset:], []byte("xref\n")))
+
+	_, err = renderer.Render("Vacío", "  ")
+	assert.Error(t, err)
+}
+
+func TestTextPDFRenderer_Paginates(t *testing.T) {
+	paragraph := strings.Repeat("Cláusula con texto extenso para llenar varias líneas del documento. ", 20)
+	text := strings.Repeat(paragraph+"\n\n", 12)
+
+	data, err := NewTextPDFRenderer().Render("Contrato", text)
+	require.NoError(t, err)
+
+	count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(data)
+	require.NotNil(t, count)
+	pages, _ := strconv.Atoi(string(count[1]))
+	assert.Greater(t, pages, 1)
+}
+
+func TestWrapText(t *testing.T) {
+	assert.Equal(t, []string{"uno dos", "tres"}, wrapText("uno dos tres", 8))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, wrapText("abcdefghij", 4))
+	assert.Empty(t, wrapText("   ", 10))
+}
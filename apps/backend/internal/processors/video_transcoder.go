@@ -0,0 +1,277 @@
+package processors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// hlsSegmentSeconds is the target duration of HLS segments
+const hlsSegmentSeconds = 6
+
+// VideoProbe holds the properties of a source video
+type VideoProbe struct {
+	Duration float64
+	Width    int
+	Height   int
+}
+
+// TranscodeOutput lists the renditions and files written to the output directory.
+// Rendition paths and files are relative to that directory.
+type TranscodeOutput struct {
+	Renditions []domain.VideoRendition
+	Files      []string
+}
+
+// VideoTranscoder converts uploaded videos into streaming renditions
+type VideoTranscoder interface {
+	// Probe reads the duration and dimensions of a video
+	Probe(inputPath string) (*VideoProbe, error)
+
+	// Transcode writes HLS renditions and an MP4 fallback into outputDir
+	Transcode(inputPath, outputDir string, presets []domain.VideoRenditionPreset, source *VideoProbe) (*TranscodeOutput, error)
+
+	// ExtractThumbnail writes a JPEG frame taken at the given second
+	ExtractThumbnail(inputPath, outputPath string, at float64) error
+}
+
+// FFmpegTranscoder implements VideoTranscoder with the ffmpeg and ffprobe binaries
+type FFmpegTranscoder struct {
+	ffmpegPath  string
+	ffprobePath string
+	timeout     time.Duration
+}
+
+// NewFFmpegTranscoder creates a new ffmpeg-based transcoder
+func NewFFmpegTranscoder(ffmpegPath, ffprobePath string, timeout time.Duration) *FFmpegTranscoder {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Minute
+	}
+
+	return &FFmpegTranscoder{
+		ffmpegPath:  ffmpegPath,
+		ffprobePath: ffprobePath,
+		timeout:     timeout,
+	}
+}
+
+// Probe reads the duration and dimensions of a video
+func (t *FFmpegTranscoder) Probe(inputPath string) (*VideoProbe, error) {
+	output, err := t.run(t.ffprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe video: %w", err)
+	}
+
+	return parseProbeOutput(output)
+}
+
+// Transcode writes one HLS rendition per applicable preset plus an MP4 fallback of the best one
+func (t *FFmpegTranscoder) Transcode(inputPath, outputDir string, presets []domain.VideoRenditionPreset, source *VideoProbe) (*TranscodeOutput, error) {
+	selected := SelectRenditionPresets(presets, source.Height)
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no rendition presets configured")
+	}
+
+	output := &TranscodeOutput{}
+	for _, preset := range selected {
+		if _, err := t.run(t.ffmpegPath, hlsArgs(inputPath, outputDir, preset)...); err != nil {
+			return nil, fmt.Errorf("failed to transcode %s rendition: %w", preset.Name, err)
+		}
+		output.Renditions = append(output.Renditions, renditionFor(preset, source, "hls", preset.Name+".m3u8"))
+	}
+
+	best := selected[0]
+	if _, err := t.run(t.ffmpegPath, mp4Args(inputPath, outputDir, best)...); err != nil {
+		return nil, fmt.Errorf("failed to transcode MP4 fallback: %w", err)
+	}
+	output.Renditions = append(output.Renditions, renditionFor(best, source, "mp4", best.Name+".mp4"))
+
+	files, err := listFiles(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	output.Files = files
+
+	return output, nil
+}
+
+// ExtractThumbnail writes a JPEG frame taken at the given second
+func (t *FFmpegTranscoder) ExtractThumbnail(inputPath, outputPath string, at float64) error {
+	_, err := t.run(t.ffmpegPath,
+		"-y", "-ss", strconv.FormatFloat(at, 'f', 2, 64),
+		"-i", inputPath,
+		"-frames:v", "1",
+		"-vf", "scale=-2:720",
+		"-q:v", "3",
+		outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to extract thumbnail: %w", err)
+	}
+	return nil
+}
+
+// run executes a binary with the transcoder timeout and returns its standard output
+func (t *FFmpegTranscoder) run(binary string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if len(message) > 500 {
+			message = message[len(message)-500:]
+		}
+		return nil, fmt.Errorf("%s: %w: %s", filepath.Base(binary), err, message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// SelectRenditionPresets drops presets taller than the source so videos are never upscaled.
+// The smallest preset is kept for sources below every preset.
+func SelectRenditionPresets(presets []domain.VideoRenditionPreset, sourceHeight int) []domain.VideoRenditionPreset {
+	selected := []domain.VideoRenditionPreset{}
+	for _, preset := range presets {
+		if sourceHeight <= 0 || preset.Height <= sourceHeight {
+			selected = append(selected, preset)
+		}
+	}
+	if len(selected) == 0 && len(presets) > 0 {
+		selected = append(selected, presets[len(presets)-1])
+	}
+	return selected
+}
+
+// BuildMasterPlaylist builds an HLS master playlist pointing at the URL of each HLS rendition
+func BuildMasterPlaylist(renditions []domain.VideoRendition) string {
+	var builder strings.Builder
+	builder.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rendition := range renditions {
+		if rendition.Format != "hls" {
+			continue
+		}
+		fmt.Fprintf(&builder, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n%s\n",
+			rendition.Bandwidth, rendition.Width, rendition.Height, rendition.Name, rendition.URL)
+	}
+	return builder.String()
+}
+
+// hlsArgs builds the ffmpeg arguments for one HLS rendition
+func hlsArgs(inputPath, outputDir string, preset domain.VideoRenditionPreset) []string {
+	args := append([]string{"-y", "-i", inputPath}, encodingArgs(preset)...)
+	return append(args,
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(hlsSegmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outputDir, preset.Name+"_%03d.ts"),
+		filepath.Join(outputDir, preset.Name+".m3u8"))
+}
+
+// mp4Args builds the ffmpeg arguments for the progressive MP4 fallback
+func mp4Args(inputPath, outputDir string, preset domain.VideoRenditionPreset) []string {
+	args := append([]string{"-y", "-i", inputPath}, encodingArgs(preset)...)
+	return append(args, "-movflags", "+faststart", filepath.Join(outputDir, preset.Name+".mp4"))
+}
+
+// encodingArgs returns H.264/AAC encoding settings for a preset
+func encodingArgs(preset domain.VideoRenditionPreset) []string {
+	return []string{
+		"-vf", fmt.Sprintf("scale=-2:%d", preset.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-b:v", fmt.Sprintf("%dk", preset.VideoBitrate),
+		"-maxrate", fmt.Sprintf("%dk", preset.VideoBitrate*107/100),
+		"-bufsize", fmt.Sprintf("%dk", preset.VideoBitrate*3/2),
+		"-g", strconv.Itoa(hlsSegmentSeconds * 30), "-sc_threshold", "0",
+		"-c:a", "aac", "-b:a", fmt.Sprintf("%dk", preset.AudioBitrate), "-ac", "2",
+		"-map_metadata", "-1",
+	}
+}
+
+// renditionFor describes the output of a preset, keeping the source aspect ratio
+func renditionFor(preset domain.VideoRenditionPreset, source *VideoProbe, format, path string) domain.VideoRendition {
+	width := 0
+	if source.Height > 0 {
+		width = source.Width * preset.Height / source.Height
+		width -= width % 2
+	}
+	return domain.VideoRendition{
+		Name:      preset.Name,
+		Format:    format,
+		Width:     width,
+		Height:    preset.Height,
+		Bandwidth: (preset.VideoBitrate + preset.AudioBitrate) * 1000,
+		Path:      path,
+	}
+}
+
+// parseProbeOutput parses ffprobe JSON output
+func parseProbeOutput(output []byte) (*VideoProbe, error) {
+	var result struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse probe output: %w", err)
+	}
+	if len(result.Streams) == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+
+	duration, _ := strconv.ParseFloat(result.Format.Duration, 64)
+	return &VideoProbe{
+		Duration: duration,
+		Width:    result.Streams[0].Width,
+		Height:   result.Streams[0].Height,
+	}, nil
+}
+
+// listFiles returns the files of a directory tree relative to its root
+func listFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transcoded files: %w", err)
+	}
+	return files, nil
+}
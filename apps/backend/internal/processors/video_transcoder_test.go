@@ -0,0 +1,72 @@
+package processors
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestSelectRenditionPresets(t *testing.T) {
+	presets := domain.DefaultVideoRenditions
+
+	selected := SelectRenditionPresets(presets, 720)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "720p", selected[0].Name)
+	assert.Equal(t, "480p", selected[1].Name)
+
+	assert.Len(t, SelectRenditionPresets(presets, 2160), 3)
+	assert.Len(t, SelectRenditionPresets(presets, 0), 3)
+
+	// Sources smaller than every preset still get the lowest rendition
+	small := SelectRenditionPresets(presets, 360)
+	require.Len(t, small, 1)
+	assert.Equal(t, "480p", small[0].Name)
+}
+
+func TestBuildMasterPlaylist(t *testing.T) {
+	playlist := BuildMasterPlaylist([]domain.VideoRendition{
+		{Name: "720p", Format: "hls", Width: 1280, Height: 720, Bandwidth: 2928000, URL: "/uploads/videos/720p.m3u8"},
+		{Name: "720p", Format: "mp4", Width: 1280, Height: 720, Bandwidth: 2928000, URL: "/uploads/videos/720p.mp4"},
+	})
+
+	assert.True(t, strings.HasPrefix(playlist, "#EXTM3U\n"))
+	assert.Contains(t, playlist, "#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=1280x720,NAME=\"720p\"\n/uploads/videos/720p.m3u8\n")
+	assert.NotContains(t, playlist, ".mp4")
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	probe, err := parseProbeOutput([]byte(`{"streams":[{"width":1920,"height":1080}],"format":{"duration":"42.500000"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1920, probe.Width)
+	assert.Equal(t, 1080, probe.Height)
+	assert.Equal(t, 42.5, probe.Duration)
+
+	_, err = parseProbeOutput([]byte(`{"streams":[],"format":{}}`))
+	assert.Error(t, err)
+
+	_, err = parseProbeOutput([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestTranscoderArgs(t *testing.T) {
+	preset := domain.VideoRenditionPreset{Name: "720p", Height: 720, VideoBitrate: 2800, AudioBitrate: 128}
+
+	hls := strings.Join(hlsArgs("in.mov", "out", preset), " ")
+	assert.Contains(t, hls, "-f hls")
+	assert.Contains(t, hls, "scale=-2:720")
+	assert.Contains(t, hls, "-b:v 2800k")
+	assert.True(t, strings.HasSuffix(hls, filepath.Join("out", "720p.m3u8")))
+
+	mp4 := strings.Join(mp4Args("in.mov", "out", preset), " ")
+	assert.Contains(t, mp4, "+faststart")
+	assert.True(t, strings.HasSuffix(mp4, filepath.Join("out", "720p.mp4")))
+
+	rendition := renditionFor(preset, &VideoProbe{Width: 1920, Height: 1080}, "hls", "720p.m3u8")
+	assert.Equal(t, 1280, rendition.Width)
+	assert.Equal(t, 2928000, rendition.Bandwidth)
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// AgencyInvitationRepository defines the interface for agency invitation data operations
+type AgencyInvitationRepository interface {
+	// Create stores a new invitation
+	Create(invitation *domain.AgencyInvitation) error
+
+	// GetByID retrieves an invitation by ID
+	GetByID(id string) (*domain.AgencyInvitation, error)
+
+	// GetByTokenHash retrieves an invitation by the hash of its token
+	GetByTokenHash(tokenHash string) (*domain.AgencyInvitation, error)
+
+	// GetPendingByEmail retrieves the open invitation of an email to an agency
+	GetPendingByEmail(agencyID, email string) (*domain.AgencyInvitation, error)
+
+	// ListByAgency retrieves the invitations of an agency, newest first; an empty status lists all
+	ListByAgency(agencyID, status string) ([]domain.AgencyInvitation, error)
+
+	// Update persists the status of an invitation
+	Update(invitation *domain.AgencyInvitation) error
+
+	// ExpirePending marks pending invitations past their expiry as expired
+	ExpirePending(now time.Time) (int64, error)
+}
+
+// PostgreSQLAgencyInvitationRepository implements AgencyInvitationRepository using PostgreSQL
+type PostgreSQLAgencyInvitationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAgencyInvitationRepository creates a new PostgreSQL agency invitation repository
+func NewPostgreSQLAgencyInvitationRepository(db *sql.DB) *PostgreSQLAgencyInvitationRepository {
+	return &PostgreSQLAgencyInvitationRepository{db: db}
+}
+
+const agencyInvitationColumns = `id, agency_id, email, role, token_hash, status, invited_by,
+		accepted_by, accepted_at, expires_at, created_at, updated_at`
+
+// Create stores a new invitation
+func (r *PostgreSQLAgencyInvitationRepository) Create(invitation *domain.AgencyInvitation) error {
+	if invitation == nil {
+		return fmt.Errorf("invitation cannot be nil")
+	}
+
+	query := `
+		INSERT INTO agency_invitations (` + agencyInvitationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.Exec(query,
+		invitation.ID, invitation.AgencyID, invitation.Email, invitation.Role, invitation.TokenHash,
+		invitation.Status, invitation.InvitedBy, invitation.AcceptedBy, invitation.AcceptedAt,
+		invitation.ExpiresAt, invitation.CreatedAt, invitation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an invitation by ID
+func (r *PostgreSQLAgencyInvitationRepository) GetByID(id string) (*domain.AgencyInvitation, error) {
+	query := `SELECT ` + agencyInvitationColumns + ` FROM agency_invitations WHERE id = $1`
+	return r.getOne(query, id)
+}
+
+// GetByTokenHash retrieves an invitation by the hash of its token
+func (r *PostgreSQLAgencyInvitationRepository) GetByTokenHash(tokenHash string) (*domain.AgencyInvitation, error) {
+	query := `SELECT ` + agencyInvitationColumns + ` FROM agency_invitations WHERE token_hash = $1`
+	return r.getOne(query, tokenHash)
+}
+
+// GetPendingByEmail retrieves the open invitation of an email to an agency
+func (r *PostgreSQLAgencyInvitationRepository) GetPendingByEmail(agencyID, email string) (*domain.AgencyInvitation, error) {
+	query := `
+		SELECT ` + agencyInvitationColumns + `
+		FROM agency_invitations
+		WHERE agency_id = $1 AND LOWER(email) = $2 AND status = 'pending'`
+	return r.getOne(query, agencyID, strings.ToLower(email))
+}
+
+// ListByAgency retrieves the invitations of an agency, newest first; an empty status lists all
+func (r *PostgreSQLAgencyInvitationRepository) ListByAgency(agencyID, status string) ([]domain.AgencyInvitation, error) {
+	query := `
+		SELECT ` + agencyInvitationColumns + `
+		FROM agency_invitations
+		WHERE agency_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query, agencyID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []domain.AgencyInvitation{}
+	for rows.Next() {
+		invitation, err := scanAgencyInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, *invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// Update persists the status of an invitation
+func (r *PostgreSQLAgencyInvitationRepository) Update(invitation *domain.AgencyInvitation) error {
+	if invitation == nil {
+		return fmt.Errorf("invitation cannot be nil")
+	}
+
+	query := `
+		UPDATE agency_invitations
+		SET status = $2, accepted_by = $3, accepted_at = $4, updated_at = $5
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, invitation.ID, invitation.Status, invitation.AcceptedBy, invitation.AcceptedAt, invitation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("invitation not found: %s", invitation.ID)
+	}
+
+	return nil
+}
+
+// ExpirePending marks pending invitations past their expiry as expired
+func (r *PostgreSQLAgencyInvitationRepository) ExpirePending(now time.Time) (int64, error) {
+	query := `
+		UPDATE agency_invitations
+		SET status = 'expired', updated_at = $1
+		WHERE status = 'pending' AND expires_at < $1`
+
+	result, err := r.db.Exec(query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire invitations: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// getOne runs a query selecting a single invitation
+func (r *PostgreSQLAgencyInvitationRepository) getOne(query string, args ...interface{}) (*domain.AgencyInvitation, error) {
+	invitation, err := scanAgencyInvitation(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invitation not found")
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// scanAgencyInvitation scans a row selected with agencyInvitationColumns
+func scanAgencyInvitation(row interface{ Scan(...interface{}) error }) (*domain.AgencyInvitation, error) {
+	invitation := &domain.AgencyInvitation{}
+	var acceptedBy sql.NullString
+	var acceptedAt sql.NullTime
+	err := row.Scan(
+		&invitation.ID, &invitation.AgencyID, &invitation.Email, &invitation.Role, &invitation.TokenHash,
+		&invitation.Status, &invitation.InvitedBy, &acceptedBy, &acceptedAt,
+		&invitation.ExpiresAt, &invitation.CreatedAt, &invitation.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if acceptedBy.Valid {
+		invitation.AcceptedBy = &acceptedBy.String
+	}
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+
+	return invitation, nil
+}
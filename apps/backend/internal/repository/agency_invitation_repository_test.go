@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+var agencyInvitationRowColumns = []string{"id", "agency_id", "email", "role", "token_hash", "status", "invited_by",
+	"accepted_by", "accepted_at", "expires_at", "created_at", "updated_at"}
+
+func TestAgencyInvitationRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAgencyInvitationRepository(db)
+	invitation, _, err := domain.NewAgencyInvitation("agency-1", "agente@example.com", "owner-1", time.Hour)
+	require.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO agency_invitations").
+		WithArgs(invitation.ID, "agency-1", "agente@example.com", domain.RoleAgent, invitation.TokenHash,
+			domain.InvitationStatusPending, "owner-1", nil, nil, invitation.ExpiresAt, invitation.CreatedAt, invitation.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(invitation))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAgencyInvitationRepository_GetByTokenHash(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAgencyInvitationRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM agency_invitations WHERE token_hash = \\$1").
+		WithArgs("hash-1").
+		WillReturnRows(sqlmock.NewRows(agencyInvitationRowColumns).
+			AddRow("inv-1", "agency-1", "agente@example.com", "agent", "hash-1", "accepted", "owner-1",
+				"user-1", now, now.Add(time.Hour), now, now))
+
+	invitation, err := repo.GetByTokenHash("hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "inv-1", invitation.ID)
+	assert.Equal(t, domain.RoleAgent, invitation.Role)
+	require.NotNil(t, invitation.AcceptedBy)
+	assert.Equal(t, "user-1", *invitation.AcceptedBy)
+
+	mock.ExpectQuery("SELECT (.+) FROM agency_invitations WHERE token_hash = \\$1").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(agencyInvitationRowColumns))
+
+	_, err = repo.GetByTokenHash("missing")
+	assert.EqualError(t, err, "invitation not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAgencyInvitationRepository_ExpirePending(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAgencyInvitationRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE agency_invitations SET status = 'expired'").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	expired, err := repo.ExpirePending(now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAgencyRepository_RemoveAgent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewAgencyRepository(db)
+	reassignTo := "agent-2"
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE properties SET agent_id = \\$3").
+		WithArgs("agency-1", "agent-1", &reassignTo, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("UPDATE users SET agency_id = NULL").
+		WithArgs("agency-1", "agent-1", domain.RoleBuyer, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	reassigned, err := repo.RemoveAgent("agency-1", "agent-1", &reassignTo)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), reassigned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
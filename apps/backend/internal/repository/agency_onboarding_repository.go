@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// AgencyOnboardingRepository defines the interface for the data of the agency setup checklist
+type AgencyOnboardingRepository interface {
+	// GetAgencyActivity counts the invitations, agents and published listings of an agency
+	// along with its subscription plan
+	GetAgencyActivity(agencyID string) (*domain.AgencyActivity, error)
+}
+
+// PostgreSQLAgencyOnboardingRepository implements AgencyOnboardingRepository using PostgreSQL
+type PostgreSQLAgencyOnboardingRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAgencyOnboardingRepository creates a new PostgreSQL agency onboarding repository
+func NewPostgreSQLAgencyOnboardingRepository(db *sql.DB) *PostgreSQLAgencyOnboardingRepository {
+	return &PostgreSQLAgencyOnboardingRepository{db: db}
+}
+
+// GetAgencyActivity counts the invitations, agents and published listings of an agency
+// along with its subscription plan. Listings held by spam screening are not published.
+func (r *PostgreSQLAgencyOnboardingRepository) GetAgencyActivity(agencyID string) (*domain.AgencyActivity, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM agency_invitations WHERE agency_id = $1),
+			(SELECT COUNT(*) FROM users WHERE agency_id = $1 AND role = 'agent'),
+			(SELECT COUNT(*) FROM properties WHERE agency_id = $1 AND status != 'quarantined'),
+			subscription_plan
+		FROM agencies WHERE id = $1`
+
+	activity := &domain.AgencyActivity{}
+	err := r.db.QueryRow(query, agencyID).Scan(
+		&activity.InvitationsSent, &activity.Agents, &activity.PublishedListings, &activity.Plan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("agency not found: %s", agencyID)
+		}
+		return nil, fmt.Errorf("failed to get agency activity: %w", err)
+	}
+
+	return activity, nil
+}
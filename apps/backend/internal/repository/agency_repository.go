@@ -8,11 +8,14 @@ import (
 	"time"
 
 	"realty-core/internal/domain"
+	"realty-core/internal/pii"
 )
 
 // AgencyRepository handles database operations for agencies
 type AgencyRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher *pii.Cipher
+	tenant tenantScope // unscoped unless returned by ForTenant
 }
 
 // NewAgencyRepository creates a new agency repository
@@ -20,6 +23,34 @@ func NewAgencyRepository(db *sql.DB) *AgencyRepository {
 	return &AgencyRepository{db: db}
 }
 
+// SetCipher decrypts the PII of the agents returned with an agency
+func (r *AgencyRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+// ForTenant returns the repository scoped to a tenant: it only finds and changes the
+// agencies of the tenant, and their agents and properties, and creates new agencies in
+// it. An empty tenant returns the repository itself.
+func (r *AgencyRepository) ForTenant(tenantID string) *AgencyRepository {
+	if tenantID == "" {
+		return r
+	}
+	scoped := *r
+	scoped.tenant = tenantScope(tenantID)
+	return &scoped
+}
+
+// requireRow reports agencies of other tenants as not found to scoped repositories
+func (r *AgencyRepository) requireRow(result sql.Result, id string) error {
+	if r.tenant == "" {
+		return nil
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("agency not found with id: %s", id)
+	}
+	return nil
+}
+
 // Create creates a new agency in the database
 func (r *AgencyRepository) Create(agency *domain.Agency) error {
 	query := `
@@ -27,9 +58,9 @@ func (r *AgencyRepository) Create(agency *domain.Agency) error {
 			id, name, ruc, address, phone, email, website, description, 
 			logo_url, active, license_number, license_expiry, commission, 
 			business_hours, social_media, specialties, service_areas, 
-			created_at, updated_at
+			created_at, updated_at, tenant_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
 		)`
 
 	// Convert maps and slices to JSON
@@ -53,7 +84,7 @@ func (r *AgencyRepository) Create(agency *domain.Agency) error {
 		agency.Email, agency.Website, agency.Description, agency.LogoURL,
 		agency.Active, agency.LicenseNumber, agency.LicenseExpiry,
 		agency.Commission, agency.BusinessHours, socialMediaJSON,
-		specialtiesJSON, serviceAreasJSON, agency.CreatedAt, agency.UpdatedAt,
+		specialtiesJSON, serviceAreasJSON, agency.CreatedAt, agency.UpdatedAt, r.tenant.id(""),
 	)
 
 	if err != nil {
@@ -71,12 +102,12 @@ func (r *AgencyRepository) GetByID(id string) (*domain.Agency, error) {
 			   business_hours, social_media, specialties, service_areas, 
 			   created_at, updated_at
 		FROM agencies 
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(2)
 
 	agency := &domain.Agency{}
 	var socialMediaJSON, specialtiesJSON, serviceAreasJSON []byte
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, r.tenant.args(id)...).Scan(
 		&agency.ID, &agency.Name, &agency.RUC, &agency.Address, &agency.Phone,
 		&agency.Email, &agency.Website, &agency.Description, &agency.LogoURL,
 		&agency.Active, &agency.LicenseNumber, &agency.LicenseExpiry,
@@ -104,6 +135,7 @@ func (r *AgencyRepository) GetByID(id string) (*domain.Agency, error) {
 		return nil, fmt.Errorf("failed to unmarshal service areas: %w", err)
 	}
 
+	agency.LogoVariants = domain.ProfileImageVariants(agency.LogoURL)
 	return agency, nil
 }
 
@@ -115,12 +147,12 @@ func (r *AgencyRepository) GetByRUC(ruc string) (*domain.Agency, error) {
 			   business_hours, social_media, specialties, service_areas, 
 			   created_at, updated_at
 		FROM agencies 
-		WHERE ruc = $1`
+		WHERE ruc = $1` + r.tenant.condition(2)
 
 	agency := &domain.Agency{}
 	var socialMediaJSON, specialtiesJSON, serviceAreasJSON []byte
 
-	err := r.db.QueryRow(query, ruc).Scan(
+	err := r.db.QueryRow(query, r.tenant.args(ruc)...).Scan(
 		&agency.ID, &agency.Name, &agency.RUC, &agency.Address, &agency.Phone,
 		&agency.Email, &agency.Website, &agency.Description, &agency.LogoURL,
 		&agency.Active, &agency.LicenseNumber, &agency.LicenseExpiry,
@@ -148,6 +180,7 @@ func (r *AgencyRepository) GetByRUC(ruc string) (*domain.Agency, error) {
 		return nil, fmt.Errorf("failed to unmarshal service areas: %w", err)
 	}
 
+	agency.LogoVariants = domain.ProfileImageVariants(agency.LogoURL)
 	return agency, nil
 }
 
@@ -160,7 +193,7 @@ func (r *AgencyRepository) Update(agency *domain.Agency) error {
 			license_number = $11, license_expiry = $12, commission = $13, 
 			business_hours = $14, social_media = $15, specialties = $16, 
 			service_areas = $17, updated_at = $18
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(19)
 
 	// Convert maps and slices to JSON
 	socialMediaJSON, err := json.Marshal(agency.SocialMedia)
@@ -178,29 +211,29 @@ func (r *AgencyRepository) Update(agency *domain.Agency) error {
 		return fmt.Errorf("failed to marshal service areas: %w", err)
 	}
 
-	_, err = r.db.Exec(query,
+	result, err := r.db.Exec(query, r.tenant.args(
 		agency.ID, agency.Name, agency.RUC, agency.Address, agency.Phone,
 		agency.Email, agency.Website, agency.Description, agency.LogoURL,
 		agency.Active, agency.LicenseNumber, agency.LicenseExpiry,
 		agency.Commission, agency.BusinessHours, socialMediaJSON,
 		specialtiesJSON, serviceAreasJSON, agency.UpdatedAt,
-	)
+	)...)
 
 	if err != nil {
 		return fmt.Errorf("failed to update agency: %w", err)
 	}
 
-	return nil
+	return r.requireRow(result, agency.ID)
 }
 
 // Delete deletes an agency from the database
 func (r *AgencyRepository) Delete(id string) error {
-	query := `DELETE FROM agencies WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+	query := `DELETE FROM agencies WHERE id = $1` + r.tenant.condition(2)
+	result, err := r.db.Exec(query, r.tenant.args(id)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete agency: %w", err)
 	}
-	return nil
+	return r.requireRow(result, id)
 }
 
 // GetActive retrieves all active agencies
@@ -212,10 +245,10 @@ func (r *AgencyRepository) GetActive() ([]*domain.Agency, error) {
 			   created_at, updated_at
 		FROM agencies 
 		WHERE active = TRUE 
-		  AND (license_expiry IS NULL OR license_expiry > CURRENT_TIMESTAMP)
+		  AND (license_expiry IS NULL OR license_expiry > CURRENT_TIMESTAMP)` + r.tenant.condition(1) + `
 		ORDER BY name`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, r.tenant.args()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active agencies: %w", err)
 	}
@@ -250,6 +283,7 @@ func (r *AgencyRepository) GetActive() ([]*domain.Agency, error) {
 			return nil, fmt.Errorf("failed to unmarshal service areas: %w", err)
 		}
 
+		agency.LogoVariants = domain.ProfileImageVariants(agency.LogoURL)
 		agencies = append(agencies, agency)
 	}
 
@@ -315,6 +349,12 @@ func (r *AgencyRepository) Search(params *domain.AgencySearchParams) ([]*domain.
 		conditions = append(conditions, `(license_expiry IS NULL OR license_expiry > CURRENT_TIMESTAMP)`)
 	}
 
+	if r.tenant != "" {
+		conditions = append(conditions, fmt.Sprintf(`tenant_id = $%d`, argIndex))
+		args = append(args, string(r.tenant))
+		argIndex++
+	}
+
 	// Add conditions to queries
 	if len(conditions) > 0 {
 		conditionStr := " AND " + strings.Join(conditions, " AND ")
@@ -372,6 +412,7 @@ func (r *AgencyRepository) Search(params *domain.AgencySearchParams) ([]*domain.
 			return nil, 0, fmt.Errorf("failed to unmarshal service areas: %w", err)
 		}
 
+		agency.LogoVariants = domain.ProfileImageVariants(agency.LogoURL)
 		agencies = append(agencies, agency)
 	}
 
@@ -388,11 +429,11 @@ func (r *AgencyRepository) GetByServiceArea(province string) ([]*domain.Agency,
 		FROM agencies 
 		WHERE active = TRUE 
 		  AND (license_expiry IS NULL OR license_expiry > CURRENT_TIMESTAMP)
-		  AND service_areas @> $1
+		  AND service_areas @> $1` + r.tenant.condition(2) + `
 		ORDER BY name`
 
 	provinceJSON, _ := json.Marshal([]string{province})
-	rows, err := r.db.Query(query, string(provinceJSON))
+	rows, err := r.db.Query(query, r.tenant.args(string(provinceJSON))...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agencies by service area: %w", err)
 	}
@@ -427,6 +468,7 @@ func (r *AgencyRepository) GetByServiceArea(province string) ([]*domain.Agency,
 			return nil, fmt.Errorf("failed to unmarshal service areas: %w", err)
 		}
 
+		agency.LogoVariants = domain.ProfileImageVariants(agency.LogoURL)
 		agencies = append(agencies, agency)
 	}
 
@@ -443,11 +485,11 @@ func (r *AgencyRepository) GetBySpecialty(specialty string) ([]*domain.Agency, e
 		FROM agencies 
 		WHERE active = TRUE 
 		  AND (license_expiry IS NULL OR license_expiry > CURRENT_TIMESTAMP)
-		  AND specialties @> $1
+		  AND specialties @> $1` + r.tenant.condition(2) + `
 		ORDER BY name`
 
 	specialtyJSON, _ := json.Marshal([]string{specialty})
-	rows, err := r.db.Query(query, string(specialtyJSON))
+	rows, err := r.db.Query(query, r.tenant.args(string(specialtyJSON))...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agencies by specialty: %w", err)
 	}
@@ -482,6 +524,7 @@ func (r *AgencyRepository) GetBySpecialty(specialty string) ([]*domain.Agency, e
 			return nil, fmt.Errorf("failed to unmarshal service areas: %w", err)
 		}
 
+		agency.LogoVariants = domain.ProfileImageVariants(agency.LogoURL)
 		agencies = append(agencies, agency)
 	}
 
@@ -497,12 +540,12 @@ func (r *AgencyRepository) GetStatistics() (*domain.AgencyStats, error) {
 			COUNT(*) FILTER (WHERE license_number IS NOT NULL AND license_number != '') as licensed_agencies,
 			COUNT(*) FILTER (WHERE license_expiry IS NOT NULL AND license_expiry <= CURRENT_TIMESTAMP) as expired_licenses,
 			COALESCE(AVG(commission), 0) as average_commission,
-			(SELECT COUNT(*) FROM users WHERE user_type = 'agent' AND agency_id IS NOT NULL) as total_agents,
-			(SELECT COUNT(*) FROM properties WHERE agency_id IS NOT NULL) as total_properties
-		FROM agencies`
+			(SELECT COUNT(*) FROM users WHERE user_type = 'agent' AND agency_id IS NOT NULL` + r.tenant.condition(1) + `) as total_agents,
+			(SELECT COUNT(*) FROM properties WHERE agency_id IS NOT NULL` + r.tenant.condition(1) + `) as total_properties
+		FROM agencies` + r.tenant.where(1)
 
 	stats := &domain.AgencyStats{}
-	err := r.db.QueryRow(query).Scan(
+	err := r.db.QueryRow(query, r.tenant.args()...).Scan(
 		&stats.TotalAgencies, &stats.ActiveAgencies, &stats.LicensedAgencies,
 		&stats.ExpiredLicenses, &stats.AverageCommission, &stats.TotalAgents,
 		&stats.TotalProperties,
@@ -529,6 +572,8 @@ func (r *AgencyRepository) GetPerformance(agencyID string) (*domain.AgencyPerfor
 			COALESCE(AVG(p.price), 0) as average_property_value,
 			(SELECT COUNT(*) FROM users WHERE agency_id = a.id AND user_type = 'agent') as total_agents,
 			(SELECT COUNT(*) FROM users WHERE agency_id = a.id AND user_type = 'agent' AND active = TRUE) as active_agents,
+			(SELECT COALESCE(SUM(commission_amount), 0) FROM deals WHERE agency_id = a.id) as commission_earnings,
+			(SELECT COALESCE(SUM(commission_amount), 0) FROM deals WHERE agency_id = a.id AND closing_date >= NOW() - INTERVAL '30 days') as monthly_revenue,
 			CASE 
 				WHEN COUNT(p.id) > 0 THEN 
 					ROUND((COUNT(p.id) FILTER (WHERE p.status IN ('sold', 'rented')) * 100.0 / COUNT(p.id)), 2)
@@ -536,16 +581,17 @@ func (r *AgencyRepository) GetPerformance(agencyID string) (*domain.AgencyPerfor
 			END as conversion_rate
 		FROM agencies a
 		LEFT JOIN properties p ON a.id = p.agency_id
-		WHERE a.id = $1
+		WHERE a.id = $1` + r.tenant.condition(2, "a.tenant_id") + `
 		GROUP BY a.id, a.name`
 
 	performance := &domain.AgencyPerformance{}
-	err := r.db.QueryRow(query, agencyID).Scan(
+	err := r.db.QueryRow(query, r.tenant.args(agencyID)...).Scan(
 		&performance.AgencyID, &performance.AgencyName,
 		&performance.TotalProperties, &performance.SoldProperties,
 		&performance.RentedProperties, &performance.TotalSalesValue,
 		&performance.TotalRentValue, &performance.AveragePropertyValue,
 		&performance.TotalAgents, &performance.ActiveAgents,
+		&performance.CommissionEarnings, &performance.MonthlyRevenue,
 		&performance.ConversionRate,
 	)
 
@@ -568,7 +614,8 @@ func (r *AgencyRepository) GetWithAgents(agencyID string) (*domain.AgencyWithAge
 	}
 
 	// Get associated agents
-	userRepo := NewUserRepository(r.db)
+	userRepo := NewUserRepository(r.db).ForTenant(string(r.tenant))
+	userRepo.SetCipher(r.cipher)
 	agents, err := userRepo.GetByAgency(agencyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get agents for agency: %w", err)
@@ -585,14 +632,14 @@ func (r *AgencyRepository) Activate(id string) error {
 	query := `
 		UPDATE agencies 
 		SET active = TRUE, updated_at = $2
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(3)
 
-	_, err := r.db.Exec(query, id, time.Now())
+	result, err := r.db.Exec(query, r.tenant.args(id, time.Now())...)
 	if err != nil {
 		return fmt.Errorf("failed to activate agency: %w", err)
 	}
 
-	return nil
+	return r.requireRow(result, id)
 }
 
 // Deactivate deactivates an agency
@@ -600,12 +647,86 @@ func (r *AgencyRepository) Deactivate(id string) error {
 	query := `
 		UPDATE agencies 
 		SET active = FALSE, updated_at = $2
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(3)
 
-	_, err := r.db.Exec(query, id, time.Now())
+	result, err := r.db.Exec(query, r.tenant.args(id, time.Now())...)
 	if err != nil {
 		return fmt.Errorf("failed to deactivate agency: %w", err)
 	}
 
-	return nil
-}
\ No newline at end of file
+	return r.requireRow(result, id)
+}
+
+// RemoveAgent removes an agent from an agency in a single transaction. The agent's
+// properties are reassigned to reassignTo, or left without agent when it is nil,
+// and the user keeps a buyer account. Returns the number of reassigned properties.
+func (r *AgencyRepository) RemoveAgent(agencyID, agentID string, reassignTo *string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE properties
+		SET agent_id = $3, updated_at = $4
+		WHERE agency_id = $1 AND agent_id = $2`+r.tenant.condition(5),
+		r.tenant.args(agencyID, agentID, reassignTo, now)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign agent properties: %w", err)
+	}
+	reassigned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	result, err = tx.Exec(`
+		UPDATE users
+		SET agency_id = NULL, user_type = $3, updated_at = $4
+		WHERE id = $2 AND agency_id = $1`+r.tenant.condition(5),
+		r.tenant.args(agencyID, agentID, domain.RoleBuyer, now)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove agent from agency: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return 0, fmt.Errorf("agent not found in agency: %s", agentID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return reassigned, nil
+}
+
+// TransferAgentProperties moves every property of an agency assigned to one agent to
+// another agent and returns the IDs of the moved properties
+func (r *AgencyRepository) TransferAgentProperties(agencyID, fromAgentID, toAgentID, updatedBy string) ([]string, error) {
+	query := `
+		UPDATE properties
+		SET agent_id = $3, updated_by = $4, updated_at = $5
+		WHERE agency_id = $1 AND agent_id = $2` + r.tenant.condition(6) + `
+		RETURNING id`
+
+	rows, err := r.db.Query(query, r.tenant.args(agencyID, fromAgentID, toAgentID, updatedBy, time.Now())...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer agent properties: %w", err)
+	}
+	defer rows.Close()
+
+	propertyIDs := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan property ID: %w", err)
+		}
+		propertyIDs = append(propertyIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return propertyIDs, nil
+}
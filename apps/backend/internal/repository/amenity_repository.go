@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// AmenityRepository defines the interface for the amenity catalog and property amenities
+type AmenityRepository interface {
+	// ListCatalog lists the catalog by category and sort order, optionally with inactive entries
+	ListCatalog(includeInactive bool) ([]domain.Amenity, error)
+
+	// GetByCode retrieves a catalog entry
+	GetByCode(code string) (*domain.Amenity, error)
+
+	// Create adds a catalog entry
+	Create(amenity *domain.Amenity) error
+
+	// Update saves the names, category, icon, state and order of a catalog entry
+	Update(amenity *domain.Amenity) error
+
+	// Delete removes a catalog entry no property uses
+	Delete(code string) error
+
+	// ListByProperty lists the amenities of a property in catalog order
+	ListByProperty(propertyID string) ([]domain.PropertyAmenity, error)
+
+	// ReplaceForProperty replaces the amenities of a property
+	ReplaceForProperty(propertyID string, amenities []domain.PropertyAmenity) error
+}
+
+// PostgreSQLAmenityRepository implements AmenityRepository using PostgreSQL
+type PostgreSQLAmenityRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAmenityRepository creates a new PostgreSQL amenity repository
+func NewPostgreSQLAmenityRepository(db *sql.DB) *PostgreSQLAmenityRepository {
+	return &PostgreSQLAmenityRepository{db: db}
+}
+
+const amenityColumns = `code, name_es, name_en, category, icon, active, sort_order, created_at, updated_at`
+
+// scanAmenity scans a catalog entry selected with amenityColumns
+func scanAmenity(row interface{ Scan(...interface{}) error }) (*domain.Amenity, error) {
+	amenity := &domain.Amenity{}
+	err := row.Scan(&amenity.Code, &amenity.NameES, &amenity.NameEN, &amenity.Category, &amenity.Icon,
+		&amenity.Active, &amenity.SortOrder, &amenity.CreatedAt, &amenity.UpdatedAt)
+	return amenity, err
+}
+
+// ListCatalog lists the catalog by category and sort order
+func (r *PostgreSQLAmenityRepository) ListCatalog(includeInactive bool) ([]domain.Amenity, error) {
+	query := `SELECT ` + amenityColumns + ` FROM amenities WHERE $1 OR active ORDER BY category, sort_order, code`
+
+	rows, err := r.db.Query(query, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list amenities: %w", err)
+	}
+	defer rows.Close()
+
+	amenities := []domain.Amenity{}
+	for rows.Next() {
+		amenity, err := scanAmenity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan amenity: %w", err)
+		}
+		amenities = append(amenities, *amenity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate amenities: %w", err)
+	}
+	return amenities, nil
+}
+
+// GetByCode retrieves a catalog entry
+func (r *PostgreSQLAmenityRepository) GetByCode(code string) (*domain.Amenity, error) {
+	query := `SELECT ` + amenityColumns + ` FROM amenities WHERE code = $1`
+
+	amenity, err := scanAmenity(r.db.QueryRow(query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("amenity not found: %s", code)
+		}
+		return nil, fmt.Errorf("failed to get amenity: %w", err)
+	}
+	return amenity, nil
+}
+
+// Create adds a catalog entry
+func (r *PostgreSQLAmenityRepository) Create(amenity *domain.Amenity) error {
+	query := `
+		INSERT INTO amenities (` + amenityColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (code) DO NOTHING`
+
+	result, err := r.db.Exec(query, amenity.Code, amenity.NameES, amenity.NameEN, amenity.Category, amenity.Icon,
+		amenity.Active, amenity.SortOrder, amenity.CreatedAt, amenity.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create amenity: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check created amenity: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("amenity already exists: %s", amenity.Code)
+	}
+	return nil
+}
+
+// Update saves the names, category, icon, state and order of a catalog entry
+func (r *PostgreSQLAmenityRepository) Update(amenity *domain.Amenity) error {
+	query := `
+		UPDATE amenities SET name_es = $2, name_en = $3, category = $4, icon = $5,
+			active = $6, sort_order = $7, updated_at = $8
+		WHERE code = $1`
+
+	result, err := r.db.Exec(query, amenity.Code, amenity.NameES, amenity.NameEN, amenity.Category, amenity.Icon,
+		amenity.Active, amenity.SortOrder, amenity.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update amenity: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated amenity: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("amenity not found: %s", amenity.Code)
+	}
+	return nil
+}
+
+// Delete removes a catalog entry no property uses
+func (r *PostgreSQLAmenityRepository) Delete(code string) error {
+	query := `
+		DELETE FROM amenities
+		WHERE code = $1 AND NOT EXISTS (SELECT 1 FROM property_amenities WHERE amenity_code = $1)`
+
+	result, err := r.db.Exec(query, code)
+	if err != nil {
+		return fmt.Errorf("failed to delete amenity: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted amenity: %w", err)
+	}
+	if rowsAffected == 0 {
+		if _, err := r.GetByCode(code); err != nil {
+			return err
+		}
+		return fmt.Errorf("amenity in use: %s is set on properties, deactivate it instead", code)
+	}
+	return nil
+}
+
+// ListByProperty lists the amenities of a property in catalog order
+func (r *PostgreSQLAmenityRepository) ListByProperty(propertyID string) ([]domain.PropertyAmenity, error) {
+	query := `
+		SELECT pa.amenity_code, a.name_es, pa.details
+		FROM property_amenities pa
+		JOIN amenities a ON a.code = pa.amenity_code
+		WHERE pa.property_id = $1
+		ORDER BY a.category, a.sort_order, a.code`
+
+	rows, err := r.db.Query(query, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property amenities: %w", err)
+	}
+	defer rows.Close()
+
+	amenities := []domain.PropertyAmenity{}
+	for rows.Next() {
+		var amenity domain.PropertyAmenity
+		var details []byte
+		if err := rows.Scan(&amenity.Code, &amenity.Name, &details); err != nil {
+			return nil, fmt.Errorf("failed to scan property amenity: %w", err)
+		}
+		amenity.Details = json.RawMessage(details)
+		amenities = append(amenities, amenity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate property amenities: %w", err)
+	}
+	return amenities, nil
+}
+
+// ReplaceForProperty replaces the amenities of a property in one transaction
+func (r *PostgreSQLAmenityRepository) ReplaceForProperty(propertyID string, amenities []domain.PropertyAmenity) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM property_amenities WHERE property_id = $1`, propertyID); err != nil {
+		return fmt.Errorf("failed to clear property amenities: %w", err)
+	}
+
+	query := `INSERT INTO property_amenities (property_id, amenity_code, details) VALUES ($1, $2, $3)`
+	for _, amenity := range amenities {
+		if _, err := tx.Exec(query, propertyID, amenity.Code, []byte(amenity.Details)); err != nil {
+			return fmt.Errorf("failed to add property amenity %s: %w", amenity.Code, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit property amenities: %w", err)
+	}
+	return nil
+}
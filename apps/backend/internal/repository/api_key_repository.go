@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// APIKeyRepository defines the interface for public API key operations
+type APIKeyRepository interface {
+	// Create stores a new API key
+	Create(key *domain.APIKey) error
+
+	// GetByID retrieves a key by ID
+	GetByID(id string) (*domain.APIKey, error)
+
+	// GetByHash retrieves a key by the hash of the key
+	GetByHash(keyHash string) (*domain.APIKey, error)
+
+	// List returns every key, newest first
+	List() ([]domain.APIKey, error)
+
+	// Revoke marks a key as revoked at the given time
+	Revoke(id string, revokedAt time.Time) error
+
+	// AddUsage adds what keys used on a day to their usage and sets their last use
+	AddUsage(day time.Time, usage map[string]domain.APIUsageCounts, usedAt time.Time) error
+
+	// DailyRequests returns the requests a key made on a day
+	DailyRequests(keyID string, day time.Time) (int64, error)
+
+	// Usage returns the daily usage of a key between two days, inclusive
+	Usage(keyID string, from, to time.Time) ([]domain.APIKeyUsage, error)
+
+	// UsageBetween returns the daily usage of every key between two days, inclusive,
+	// only of the keys of an agency when agencyID is not empty
+	UsageBetween(from, to time.Time, agencyID string) ([]domain.APIKeyUsage, error)
+}
+
+// PostgreSQLAPIKeyRepository implements APIKeyRepository using PostgreSQL
+type PostgreSQLAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAPIKeyRepository creates a new PostgreSQL API key repository
+func NewPostgreSQLAPIKeyRepository(db *sql.DB) *PostgreSQLAPIKeyRepository {
+	return &PostgreSQLAPIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, name, contact_email, agency_id, key_prefix, key_hash, tier, created_by,
+		created_at, last_used_at, revoked_at`
+
+// Create stores a new API key
+func (r *PostgreSQLAPIKeyRepository) Create(key *domain.APIKey) error {
+	if key == nil {
+		return fmt.Errorf("API key cannot be nil")
+	}
+
+	var createdBy sql.NullString
+	if key.CreatedBy != "" {
+		createdBy = sql.NullString{String: key.CreatedBy, Valid: true}
+	}
+
+	query := `
+		INSERT INTO api_keys (` + apiKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.Exec(query,
+		key.ID, key.Name, key.ContactEmail, key.AgencyID, key.Prefix, key.KeyHash, key.Tier,
+		createdBy, key.CreatedAt, key.LastUsedAt, key.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a key by ID
+func (r *PostgreSQLAPIKeyRepository) GetByID(id string) (*domain.APIKey, error) {
+	return r.getOne(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id)
+}
+
+// GetByHash retrieves a key by the hash of the key
+func (r *PostgreSQLAPIKeyRepository) GetByHash(keyHash string) (*domain.APIKey, error) {
+	return r.getOne(`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash)
+}
+
+// List returns every key, newest first
+func (r *PostgreSQLAPIKeyRepository) List() ([]domain.APIKey, error) {
+	rows, err := r.db.Query(`SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks a key as revoked at the given time
+func (r *PostgreSQLAPIKeyRepository) Revoke(id string, revokedAt time.Time) error {
+	result, err := r.db.Exec(`UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`, id, revokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("API key not found or already revoked: %s", id)
+	}
+
+	return nil
+}
+
+// AddUsage adds what keys used on a day to their usage and sets their last use
+func (r *PostgreSQLAPIKeyRepository) AddUsage(day time.Time, usage map[string]domain.APIUsageCounts, usedAt time.Time) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	// Keys are written in a stable order so concurrent flushes do not deadlock
+	keyIDs := make([]string, 0, len(usage))
+	for keyID := range usage {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, keyID := range keyIDs {
+		counts := usage[keyID]
+		_, err := tx.Exec(`
+			INSERT INTO api_key_usage (api_key_id, day, requests, rejected_requests, bytes_sent)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (api_key_id, day) DO UPDATE SET
+				requests = api_key_usage.requests + EXCLUDED.requests,
+				rejected_requests = api_key_usage.rejected_requests + EXCLUDED.rejected_requests,
+				bytes_sent = api_key_usage.bytes_sent + EXCLUDED.bytes_sent`,
+			keyID, day.Format("2006-01-02"), counts.Requests, counts.RejectedRequests, counts.BytesSent)
+		if err != nil {
+			return fmt.Errorf("failed to add usage of API key %s: %w", keyID, err)
+		}
+		if _, err := tx.Exec(`UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, keyID, usedAt); err != nil {
+			return fmt.Errorf("failed to update last use of API key %s: %w", keyID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit API key usage: %w", err)
+	}
+	return nil
+}
+
+// DailyRequests returns the requests a key made on a day
+func (r *PostgreSQLAPIKeyRepository) DailyRequests(keyID string, day time.Time) (int64, error) {
+	var requests int64
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(requests), 0) FROM api_key_usage WHERE api_key_id = $1 AND day = $2`,
+		keyID, day.Format("2006-01-02")).Scan(&requests)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get daily requests of API key: %w", err)
+	}
+	return requests, nil
+}
+
+// Usage returns the daily usage of a key between two days, inclusive
+func (r *PostgreSQLAPIKeyRepository) Usage(keyID string, from, to time.Time) ([]domain.APIKeyUsage, error) {
+	return r.queryUsage(`
+		SELECT api_key_id, day, requests, rejected_requests, bytes_sent FROM api_key_usage
+		WHERE api_key_id = $1 AND day BETWEEN $2 AND $3
+		ORDER BY day`,
+		keyID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+// UsageBetween returns the daily usage of every key between two days, inclusive, only
+// of the keys of an agency when agencyID is not empty
+func (r *PostgreSQLAPIKeyRepository) UsageBetween(from, to time.Time, agencyID string) ([]domain.APIKeyUsage, error) {
+	if agencyID == "" {
+		return r.queryUsage(`
+			SELECT api_key_id, day, requests, rejected_requests, bytes_sent FROM api_key_usage
+			WHERE day BETWEEN $1 AND $2
+			ORDER BY day, api_key_id`,
+			from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	return r.queryUsage(`
+		SELECT u.api_key_id, u.day, u.requests, u.rejected_requests, u.bytes_sent
+		FROM api_key_usage u
+		JOIN api_keys k ON k.id = u.api_key_id
+		WHERE k.agency_id = $3 AND u.day BETWEEN $1 AND $2
+		ORDER BY u.day, u.api_key_id`,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), agencyID)
+}
+
+// queryUsage runs a query selecting daily usage
+func (r *PostgreSQLAPIKeyRepository) queryUsage(query string, args ...interface{}) ([]domain.APIKeyUsage, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []domain.APIKeyUsage{}
+	for rows.Next() {
+		var day domain.APIKeyUsage
+		if err := rows.Scan(&day.APIKeyID, &day.Day, &day.Requests, &day.RejectedRequests, &day.BytesSent); err != nil {
+			return nil, fmt.Errorf("failed to scan API key usage: %w", err)
+		}
+		usage = append(usage, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get API key usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// getOne runs a query selecting a single API key
+func (r *PostgreSQLAPIKeyRepository) getOne(query string, args ...interface{}) (*domain.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return key, nil
+}
+
+// scanAPIKey scans a row selecting apiKeyColumns
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	var agencyID, createdBy sql.NullString
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.Name, &key.ContactEmail, &agencyID, &key.Prefix, &key.KeyHash, &key.Tier,
+		&createdBy, &key.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if agencyID.Valid {
+		key.AgencyID = &agencyID.String
+	}
+	key.CreatedBy = createdBy.String
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestAPIKeyRepository_AddUsage(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAPIKeyRepository(db)
+	day := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	usedAt := day.Add(13 * time.Hour)
+
+	mock.ExpectBegin()
+	for _, keyID := range []string{"key-a", "key-b"} {
+		mock.ExpectExec("INSERT INTO api_key_usage .* ON CONFLICT \\(api_key_id, day\\) DO UPDATE").
+			WithArgs(keyID, "2025-09-10", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE api_keys SET last_used_at = \\$2 WHERE id = \\$1").
+			WithArgs(keyID, usedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.AddUsage(day, map[string]domain.APIUsageCounts{
+		"key-b": {Requests: 3, BytesSent: 1200},
+		"key-a": {Requests: 7, RejectedRequests: 1},
+	}, usedAt))
+	require.NoError(t, repo.AddUsage(day, nil, usedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_GetByHash(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAPIKeyRepository(db)
+	createdAt := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "name", "contact_email", "agency_id", "key_prefix", "key_hash", "tier", "created_by",
+		"created_at", "last_used_at", "revoked_at"}
+
+	mock.ExpectQuery("SELECT .* FROM api_keys WHERE key_hash = \\$1").
+		WithArgs("hash-1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("key-1", "Portal", "api@portal.ec", nil, "rk_0123abcd", "hash-1", "partner", "admin-1", createdAt, nil, createdAt))
+	mock.ExpectQuery("SELECT .* FROM api_keys WHERE key_hash = \\$1").
+		WithArgs("hash-2").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	key, err := repo.GetByHash("hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "partner", key.Tier)
+	assert.Nil(t, key.AgencyID)
+	assert.Nil(t, key.LastUsedAt)
+	assert.False(t, key.IsActive())
+
+	_, err = repo.GetByHash("hash-2")
+	assert.ErrorContains(t, err, "API key not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_UsageBetween(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAPIKeyRepository(db)
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	columns := []string{"api_key_id", "day", "requests", "rejected_requests", "bytes_sent"}
+
+	mock.ExpectQuery("SELECT .* FROM api_key_usage\\s+WHERE day BETWEEN \\$1 AND \\$2").
+		WithArgs("2025-09-01", "2025-09-30").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("key-a", from, int64(12), int64(1), int64(4096)).
+			AddRow("key-b", from, int64(3), int64(0), int64(512)))
+	mock.ExpectQuery("JOIN api_keys k ON k.id = u.api_key_id\\s+WHERE k.agency_id = \\$3").
+		WithArgs("2025-09-01", "2025-09-30", "agency-1").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	usage, err := repo.UsageBetween(from, to, "")
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	assert.Equal(t, domain.APIUsageCounts{Requests: 12, RejectedRequests: 1, BytesSent: 4096}, usage[0].APIUsageCounts)
+
+	usage, err = repo.UsageBetween(from, to, "agency-1")
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
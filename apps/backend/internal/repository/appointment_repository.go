@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// AppointmentRepository defines data access for agent appointments and calendar feeds
+type AppointmentRepository interface {
+	// Create stores a new appointment
+	Create(appointment *domain.Appointment) error
+
+	// GetByID retrieves an appointment by ID
+	GetByID(id string) (*domain.Appointment, error)
+
+	// ListByAgent returns the appointments of an agent starting between from and to,
+	// earliest first
+	ListByAgent(agentID string, from, to time.Time) ([]domain.Appointment, error)
+
+	// UpdateStatus saves the status and sequence of an appointment
+	UpdateStatus(appointment *domain.Appointment) error
+
+	// SetCalendarToken replaces the calendar feed token of an agent
+	SetCalendarToken(agentID, tokenHash string, createdAt time.Time) error
+
+	// GetCalendarTokenHash returns the hash of the calendar feed token of an agent
+	GetCalendarTokenHash(agentID string) (string, error)
+}
+
+// PostgreSQLAppointmentRepository implements AppointmentRepository using PostgreSQL
+type PostgreSQLAppointmentRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAppointmentRepository creates a new PostgreSQL appointment repository
+func NewPostgreSQLAppointmentRepository(db *sql.DB) *PostgreSQLAppointmentRepository {
+	return &PostgreSQLAppointmentRepository{db: db}
+}
+
+const appointmentColumns = `id, agent_id, property_id, kind, title, location, notes, client_name,
+	starts_at, ends_at, time_zone, status, sequence, created_by, created_at, updated_at`
+
+// scanAppointment scans an appointment selected with appointmentColumns, with its times
+// in its time zone
+func scanAppointment(row interface{ Scan(...interface{}) error }) (*domain.Appointment, error) {
+	appointment := &domain.Appointment{}
+	err := row.Scan(&appointment.ID, &appointment.AgentID, &appointment.PropertyID, &appointment.Kind,
+		&appointment.Title, &appointment.Location, &appointment.Notes, &appointment.ClientName,
+		&appointment.StartsAt, &appointment.EndsAt, &appointment.TimeZone, &appointment.Status,
+		&appointment.Sequence, &appointment.CreatedBy, &appointment.CreatedAt, &appointment.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	location := domain.AppointmentLocation(appointment.TimeZone)
+	appointment.StartsAt = appointment.StartsAt.In(location)
+	appointment.EndsAt = appointment.EndsAt.In(location)
+	return appointment, nil
+}
+
+// Create stores a new appointment
+func (r *PostgreSQLAppointmentRepository) Create(appointment *domain.Appointment) error {
+	query := `
+		INSERT INTO appointments (id, agent_id, property_id, kind, title, location, notes, client_name,
+			starts_at, ends_at, time_zone, status, sequence, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err := r.db.Exec(query, appointment.ID, appointment.AgentID, appointment.PropertyID, appointment.Kind,
+		appointment.Title, appointment.Location, appointment.Notes, appointment.ClientName,
+		appointment.StartsAt, appointment.EndsAt, appointment.TimeZone, appointment.Status,
+		appointment.Sequence, appointment.CreatedBy, appointment.CreatedAt, appointment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create appointment: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an appointment by ID
+func (r *PostgreSQLAppointmentRepository) GetByID(id string) (*domain.Appointment, error) {
+	query := `SELECT ` + appointmentColumns + ` FROM appointments WHERE id = $1`
+
+	appointment, err := scanAppointment(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("appointment not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment: %w", err)
+	}
+	return appointment, nil
+}
+
+// ListByAgent returns the appointments of an agent starting between from and to,
+// earliest first
+func (r *PostgreSQLAppointmentRepository) ListByAgent(agentID string, from, to time.Time) ([]domain.Appointment, error) {
+	query := `SELECT ` + appointmentColumns + ` FROM appointments
+		WHERE agent_id = $1 AND starts_at >= $2 AND starts_at < $3
+		ORDER BY starts_at, id`
+
+	rows, err := r.db.Query(query, agentID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list appointments: %w", err)
+	}
+	defer rows.Close()
+
+	appointments := []domain.Appointment{}
+	for rows.Next() {
+		appointment, err := scanAppointment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan appointment: %w", err)
+		}
+		appointments = append(appointments, *appointment)
+	}
+	return appointments, rows.Err()
+}
+
+// UpdateStatus saves the status and sequence of an appointment
+func (r *PostgreSQLAppointmentRepository) UpdateStatus(appointment *domain.Appointment) error {
+	query := `UPDATE appointments SET status = $2, sequence = $3, updated_at = $4 WHERE id = $1`
+
+	result, err := r.db.Exec(query, appointment.ID, appointment.Status, appointment.Sequence, appointment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update appointment: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("appointment not found: %s", appointment.ID)
+	}
+	return nil
+}
+
+// SetCalendarToken replaces the calendar feed token of an agent
+func (r *PostgreSQLAppointmentRepository) SetCalendarToken(agentID, tokenHash string, createdAt time.Time) error {
+	query := `
+		INSERT INTO agent_calendar_feeds (agent_id, token_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (agent_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at`
+
+	if _, err := r.db.Exec(query, agentID, tokenHash, createdAt); err != nil {
+		return fmt.Errorf("failed to set calendar token: %w", err)
+	}
+	return nil
+}
+
+// GetCalendarTokenHash returns the hash of the calendar feed token of an agent
+func (r *PostgreSQLAppointmentRepository) GetCalendarTokenHash(agentID string) (string, error) {
+	var tokenHash string
+	err := r.db.QueryRow(`SELECT token_hash FROM agent_calendar_feeds WHERE agent_id = $1`, agentID).Scan(&tokenHash)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("calendar feed not found: %s", agentID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get calendar token: %w", err)
+	}
+	return tokenHash, nil
+}
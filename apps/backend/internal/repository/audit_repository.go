@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// AuditRepository defines the interface for audit log operations
+type AuditRepository interface {
+	// Create stores an audit entry
+	Create(entry *domain.AuditEntry) error
+
+	// ListByEntity retrieves the entries of an entity, newest first
+	ListByEntity(entityType, entityID string, limit int) ([]domain.AuditEntry, error)
+
+	// ListByAgency retrieves the entries of an agency, newest first, with the total count
+	ListByAgency(agencyID string, limit, offset int) ([]domain.AuditEntry, int, error)
+}
+
+// PostgreSQLAuditRepository implements AuditRepository using PostgreSQL
+type PostgreSQLAuditRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAuditRepository creates a new PostgreSQL audit repository
+func NewPostgreSQLAuditRepository(db *sql.DB) *PostgreSQLAuditRepository {
+	return &PostgreSQLAuditRepository{db: db}
+}
+
+const auditColumns = `id, actor_id, actor_role, action, entity_type, entity_id, agency_id, details, created_at`
+
+// Create stores an audit entry
+func (r *PostgreSQLAuditRepository) Create(entry *domain.AuditEntry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry cannot be nil")
+	}
+
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit details: %w", err)
+	}
+
+	query := `INSERT INTO audit_log (` + auditColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err = r.db.Exec(query,
+		entry.ID, entry.ActorID, entry.ActorRole, entry.Action, entry.EntityType, entry.EntityID,
+		entry.AgencyID, details, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListByEntity retrieves the entries of an entity, newest first
+func (r *PostgreSQLAuditRepository) ListByEntity(entityType, entityID string, limit int) ([]domain.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, entityType, entityID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditEntries(rows)
+}
+
+// ListByAgency retrieves the entries of an agency, newest first, with the total count
+func (r *PostgreSQLAuditRepository) ListByAgency(agencyID string, limit, offset int) ([]domain.AuditEntry, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE agency_id = $1`, agencyID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log: %w", err)
+	}
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE agency_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, agencyID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditEntries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// scanAuditEntries scans rows selected with auditColumns
+func scanAuditEntries(rows *sql.Rows) ([]domain.AuditEntry, error) {
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var agencyID sql.NullString
+		var details []byte
+		err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorRole, &entry.Action, &entry.EntityType,
+			&entry.EntityID, &agencyID, &details, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		if agencyID.Valid {
+			entry.AgencyID = &agencyID.String
+		}
+		entry.Details = map[string]interface{}{}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &entry.Details); err != nil {
+				return nil, fmt.Errorf("failed to decode audit details: %w", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return entries, nil
+}
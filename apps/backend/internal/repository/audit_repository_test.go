@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestAuditRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAuditRepository(db)
+	agencyID := "agency-1"
+	actor := domain.NewActor("user-1", "agency", agencyID)
+	entry := domain.NewAuditEntry(actor, domain.AuditActionAgentAssigned, domain.AuditEntityProperty, "prop-1", &agencyID,
+		map[string]interface{}{"agent_id": "agent-1"})
+
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(entry.ID, "user-1", domain.RoleAgency, domain.AuditActionAgentAssigned, "property", "prop-1",
+			&agencyID, []byte(`{"agent_id":"agent-1"}`), entry.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.Create(entry))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_ListByAgency(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLAuditRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT COUNT").WithArgs("agency-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT (.+) FROM audit_log WHERE agency_id = \\$1").
+		WithArgs("agency-1", 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor_id", "actor_role", "action", "entity_type", "entity_id", "agency_id", "details", "created_at"}).
+			AddRow("audit-1", "user-1", "agency", domain.AuditActionListingsTransferred, "agency", "agency-1", "agency-1",
+				[]byte(`{"from_agent_id":"agent-1","property_ids":["prop-1","prop-2"]}`), now))
+
+	entries, total, err := repo.ListByAgency("agency-1", 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "agent-1", entries[0].Details["from_agent_id"])
+	assert.Len(t, entries[0].Details["property_ids"], 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAgencyRepository_TransferAgentProperties(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewAgencyRepository(db)
+
+	mock.ExpectQuery("UPDATE properties SET agent_id = \\$3").
+		WithArgs("agency-1", "agent-1", "agent-2", "user-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("prop-1").AddRow("prop-2"))
+
+	propertyIDs, err := repo.TransferAgentProperties("agency-1", "agent-1", "agent-2", "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"prop-1", "prop-2"}, propertyIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// AvailabilityRepository defines data access for the availability calendars of properties
+type AvailabilityRepository interface {
+	// GetSettings returns the booking rules of a property, the defaults if it never set any
+	GetSettings(propertyID string) (*domain.AvailabilitySettings, error)
+
+	// SaveSettings saves the booking rules of a property
+	SaveSettings(settings *domain.AvailabilitySettings) error
+
+	// ListBlocks returns the unavailable ranges of a property overlapping a period, by start date
+	ListBlocks(propertyID string, period domain.StayPeriod) ([]domain.AvailabilityBlock, error)
+
+	// GetBlock retrieves an unavailable range by ID
+	GetBlock(id string) (*domain.AvailabilityBlock, error)
+
+	// AddBlock saves an unavailable range, failing with "dates not available" when it
+	// overlaps another range of the property
+	AddBlock(block *domain.AvailabilityBlock) error
+
+	// DeleteBlock removes an unavailable range
+	DeleteBlock(id string) error
+}
+
+// PostgreSQLAvailabilityRepository implements AvailabilityRepository using PostgreSQL
+type PostgreSQLAvailabilityRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLAvailabilityRepository creates a new PostgreSQL availability repository
+func NewPostgreSQLAvailabilityRepository(db *sql.DB) *PostgreSQLAvailabilityRepository {
+	return &PostgreSQLAvailabilityRepository{db: db}
+}
+
+const availabilityBlockColumns = `id, property_id, start_date, end_date, kind, deal_id, note,
+	COALESCE(created_by::text, ''), created_at`
+
+// scanAvailabilityBlock scans a block selected with availabilityBlockColumns
+func scanAvailabilityBlock(row interface{ Scan(...interface{}) error }) (*domain.AvailabilityBlock, error) {
+	block := &domain.AvailabilityBlock{}
+	var dealID sql.NullString
+	err := row.Scan(&block.ID, &block.PropertyID, &block.Start, &block.End, &block.Kind, &dealID,
+		&block.Note, &block.CreatedBy, &block.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	block.Start, block.End = domain.CalendarDate(block.Start), domain.CalendarDate(block.End)
+	if dealID.Valid {
+		block.DealID = &dealID.String
+	}
+	return block, nil
+}
+
+// GetSettings returns the booking rules of a property, the defaults if it never set any
+func (r *PostgreSQLAvailabilityRepository) GetSettings(propertyID string) (*domain.AvailabilitySettings, error) {
+	query := `
+		SELECT min_stay_nights, COALESCE(updated_by::text, ''), updated_at
+		FROM property_availability_settings WHERE property_id = $1`
+
+	settings := domain.DefaultAvailabilitySettings(propertyID)
+	var updatedAt time.Time
+	err := r.db.QueryRow(query, propertyID).Scan(&settings.MinStayNights, &settings.UpdatedBy, &updatedAt)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability settings: %w", err)
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+// SaveSettings saves the booking rules of a property
+func (r *PostgreSQLAvailabilityRepository) SaveSettings(settings *domain.AvailabilitySettings) error {
+	query := `
+		INSERT INTO property_availability_settings (property_id, min_stay_nights, updated_by, updated_at)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4)
+		ON CONFLICT (property_id) DO UPDATE SET
+			min_stay_nights = EXCLUDED.min_stay_nights,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(query, settings.PropertyID, settings.MinStayNights, settings.UpdatedBy, settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save availability settings: %w", err)
+	}
+	return nil
+}
+
+// ListBlocks returns the unavailable ranges of a property overlapping a period, by start date
+func (r *PostgreSQLAvailabilityRepository) ListBlocks(propertyID string, period domain.StayPeriod) ([]domain.AvailabilityBlock, error) {
+	query := `SELECT ` + availabilityBlockColumns + ` FROM property_availability_blocks
+		WHERE property_id = $1 AND start_date < $3 AND end_date > $2
+		ORDER BY start_date`
+
+	rows, err := r.db.Query(query, propertyID, period.Start, period.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := []domain.AvailabilityBlock{}
+	for rows.Next() {
+		block, err := scanAvailabilityBlock(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan availability block: %w", err)
+		}
+		blocks = append(blocks, *block)
+	}
+	return blocks, rows.Err()
+}
+
+// GetBlock retrieves an unavailable range by ID
+func (r *PostgreSQLAvailabilityRepository) GetBlock(id string) (*domain.AvailabilityBlock, error) {
+	query := `SELECT ` + availabilityBlockColumns + ` FROM property_availability_blocks WHERE id = $1`
+
+	block, err := scanAvailabilityBlock(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("availability block not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get availability block: %w", err)
+	}
+	return block, nil
+}
+
+// AddBlock saves an unavailable range, failing with "dates not available" when it
+// overlaps another range of the property. The property row is locked so concurrent
+// bookings of the same dates cannot both succeed.
+func (r *PostgreSQLAvailabilityRepository) AddBlock(block *domain.AvailabilityBlock) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT 1 FROM properties WHERE id = $1 FOR UPDATE`, block.PropertyID); err != nil {
+		return fmt.Errorf("failed to lock property: %w", err)
+	}
+
+	overlapping, err := scanAvailabilityBlock(tx.QueryRow(`SELECT `+availabilityBlockColumns+` FROM property_availability_blocks
+		WHERE property_id = $1 AND start_date < $3 AND end_date > $2 LIMIT 1`,
+		block.PropertyID, block.Start, block.End))
+	if err == nil {
+		return domain.CheckConflicts(block.StayPeriod, []domain.AvailabilityBlock{*overlapping})
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check availability: %w", err)
+	}
+
+	query := `
+		INSERT INTO property_availability_blocks (id, property_id, start_date, end_date, kind, deal_id, note, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::uuid, $9)`
+	_, err = tx.Exec(query, block.ID, block.PropertyID, block.Start, block.End, block.Kind, block.DealID,
+		block.Note, block.CreatedBy, block.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add availability block: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit availability block: %w", err)
+	}
+	return nil
+}
+
+// DeleteBlock removes an unavailable range
+func (r *PostgreSQLAvailabilityRepository) DeleteBlock(id string) error {
+	result, err := r.db.Exec(`DELETE FROM property_availability_blocks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete availability block: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("availability block not found: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// BackupSampleQuery is a query run against a restored dump, which must return at least
+// MinRows rows
+type BackupSampleQuery struct {
+	Name    string
+	Query   string
+	MinRows int
+}
+
+// DefaultBackupSampleQueries are the queries the API relies on most, run against every
+// restored dump with its schema first in the search path
+var DefaultBackupSampleQueries = []BackupSampleQuery{
+	{Name: "recent_properties", Query: `SELECT id, slug, title, price FROM properties ORDER BY created_at DESC LIMIT 10`, MinRows: 1},
+	{Name: "admin_users", Query: `SELECT id FROM users WHERE role = 'admin' AND active LIMIT 1`, MinRows: 1},
+	{Name: "property_search", Query: `SELECT id FROM properties WHERE search_vector IS NOT NULL LIMIT 10`, MinRows: 1},
+	{Name: "property_images", Query: `SELECT i.id FROM images i JOIN properties p ON p.id = i.property_id LIMIT 10`, MinRows: 0},
+	{Name: "agency_listings", Query: `SELECT a.id, COUNT(p.id) FROM agencies a LEFT JOIN properties p ON p.agency_id = a.id GROUP BY a.id LIMIT 10`, MinRows: 0},
+}
+
+// BackupRepository inspects dumps restored into temporary schemas and keeps the results
+// of their verification
+type BackupRepository interface {
+	// TableRowCounts counts the rows of every table of a schema, by table
+	TableRowCounts(schema string) (map[string]int64, error)
+
+	// ForeignKeys lists the foreign keys of the tables of a schema
+	ForeignKeys(schema string) ([]domain.BackupForeignKey, error)
+
+	// CountOrphans counts the rows of a schema violating a foreign key
+	CountOrphans(schema string, fk domain.BackupForeignKey) (int64, error)
+
+	// RunSampleQuery runs a read-only query with schema first in the search path and
+	// returns how many rows it returned
+	RunSampleQuery(schema string, query BackupSampleQuery) (int, error)
+
+	// DropSchema drops a restored schema and everything in it
+	DropSchema(schema string) error
+
+	// SaveVerification stores the result of a verification
+	SaveVerification(verification *domain.BackupVerification) error
+
+	// ListVerifications returns the latest verifications, newest first
+	ListVerifications(limit int) ([]domain.BackupVerification, error)
+}
+
+// PostgreSQLBackupRepository implements BackupRepository using PostgreSQL
+type PostgreSQLBackupRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLBackupRepository creates a new PostgreSQL backup repository
+func NewPostgreSQLBackupRepository(db *sql.DB) *PostgreSQLBackupRepository {
+	return &PostgreSQLBackupRepository{db: db}
+}
+
+// TableRowCounts counts the rows of every table of a schema, by table
+func (r *PostgreSQLBackupRepository) TableRowCounts(schema string) (map[string]int64, error) {
+	rows, err := r.db.Query(`SELECT tablename FROM pg_tables WHERE schemaname = $1 ORDER BY tablename`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var count int64
+		query := `SELECT COUNT(*) FROM ` + pq.QuoteIdentifier(schema) + `.` + pq.QuoteIdentifier(table)
+		if err := r.db.QueryRow(query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count rows of %s: %w", table, err)
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// ForeignKeys lists the foreign keys of the tables of a schema, with their columns in
+// key order
+func (r *PostgreSQLBackupRepository) ForeignKeys(schema string) ([]domain.BackupForeignKey, error) {
+	query := `
+		SELECT c.conname, child.relname, parent.relname,
+			ARRAY(SELECT a.attname FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum ORDER BY k.ord),
+			ARRAY(SELECT a.attname FROM unnest(c.confkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum ORDER BY k.ord)
+		FROM pg_constraint c
+		JOIN pg_class child ON child.oid = c.conrelid
+		JOIN pg_class parent ON parent.oid = c.confrelid
+		JOIN pg_namespace n ON n.oid = c.connamespace
+		WHERE c.contype = 'f' AND n.nspname = $1
+		ORDER BY child.relname, c.conname`
+
+	rows, err := r.db.Query(query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.BackupForeignKey{}
+	for rows.Next() {
+		var fk domain.BackupForeignKey
+		if err := rows.Scan(&fk.Name, &fk.Table, &fk.ParentTable, pq.Array(&fk.Columns), pq.Array(&fk.ParentColumns)); err != nil {
+			return nil, fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		keys = append(keys, fk)
+	}
+	return keys, rows.Err()
+}
+
+// CountOrphans counts the rows of a schema whose non-null key has no parent row
+func (r *PostgreSQLBackupRepository) CountOrphans(schema string, fk domain.BackupForeignKey) (int64, error) {
+	if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.ParentColumns) {
+		return 0, fmt.Errorf("invalid foreign key: %s", fk.Name)
+	}
+
+	notNull := make([]string, len(fk.Columns))
+	matches := make([]string, len(fk.Columns))
+	for i := range fk.Columns {
+		child, parent := pq.QuoteIdentifier(fk.Columns[i]), pq.QuoteIdentifier(fk.ParentColumns[i])
+		notNull[i] = "c." + child + " IS NOT NULL"
+		matches[i] = "p." + parent + " = c." + child
+	}
+	query := `SELECT COUNT(*) FROM ` + pq.QuoteIdentifier(schema) + `.` + pq.QuoteIdentifier(fk.Table) + ` c
+		WHERE ` + strings.Join(notNull, " AND ") + ` AND NOT EXISTS (
+			SELECT 1 FROM ` + pq.QuoteIdentifier(schema) + `.` + pq.QuoteIdentifier(fk.ParentTable) + ` p
+			WHERE ` + strings.Join(matches, " AND ") + `)`
+
+	var orphans int64
+	if err := r.db.QueryRow(query).Scan(&orphans); err != nil {
+		return 0, fmt.Errorf("failed to check foreign key %s: %w", fk.Name, err)
+	}
+	return orphans, nil
+}
+
+// RunSampleQuery runs a query in a read-only transaction with schema first in the
+// search path, so its unqualified tables are those of the restored dump
+func (r *PostgreSQLBackupRepository) RunSampleQuery(schema string, query BackupSampleQuery) (int, error) {
+	tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SET LOCAL search_path TO ` + pq.QuoteIdentifier(schema) + `, public`); err != nil {
+		return 0, fmt.Errorf("failed to set search path: %w", err)
+	}
+	rows, err := tx.Query(query.Query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to run %s: %w", query.Name, err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to run %s: %w", query.Name, err)
+	}
+	return count, nil
+}
+
+// DropSchema drops a restored schema and everything in it. Only the temporary schemas
+// of backup verification may be dropped.
+func (r *PostgreSQLBackupRepository) DropSchema(schema string) error {
+	if !strings.HasPrefix(schema, domain.BackupVerifySchemaPrefix) {
+		return fmt.Errorf("invalid schema: %s is not a backup verification schema", schema)
+	}
+	if _, err := r.db.Exec(`DROP SCHEMA IF EXISTS ` + pq.QuoteIdentifier(schema) + ` CASCADE`); err != nil {
+		return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+	}
+	return nil
+}
+
+// SaveVerification stores the result of a verification
+func (r *PostgreSQLBackupRepository) SaveVerification(verification *domain.BackupVerification) error {
+	checks, err := json.Marshal(verification.Checks)
+	if err != nil {
+		return fmt.Errorf("failed to encode backup checks: %w", err)
+	}
+
+	query := `
+		INSERT INTO backup_verifications (id, dump_name, dump_format, dump_size_bytes, dump_modified_at,
+			schema_name, status, started_at, finished_at, restore_seconds, tables, rows_restored, checks, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err = r.db.Exec(query, verification.ID, verification.Dump.Name, verification.Dump.Format,
+		verification.Dump.SizeBytes, verification.Dump.ModifiedAt, verification.Schema, verification.Status,
+		verification.StartedAt, verification.FinishedAt, verification.RestoreSeconds, verification.Tables,
+		verification.Rows, checks, verification.Error)
+	if err != nil {
+		return fmt.Errorf("failed to save backup verification: %w", err)
+	}
+	return nil
+}
+
+// ListVerifications returns the latest verifications, newest first
+func (r *PostgreSQLBackupRepository) ListVerifications(limit int) ([]domain.BackupVerification, error) {
+	query := `
+		SELECT id, dump_name, dump_format, dump_size_bytes, dump_modified_at, schema_name, status,
+			started_at, finished_at, restore_seconds, tables, rows_restored, checks, error
+		FROM backup_verifications
+		ORDER BY started_at DESC, id
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup verifications: %w", err)
+	}
+	defer rows.Close()
+
+	verifications := []domain.BackupVerification{}
+	for rows.Next() {
+		var v domain.BackupVerification
+		var checks []byte
+		if err := rows.Scan(&v.ID, &v.Dump.Name, &v.Dump.Format, &v.Dump.SizeBytes, &v.Dump.ModifiedAt,
+			&v.Schema, &v.Status, &v.StartedAt, &v.FinishedAt, &v.RestoreSeconds, &v.Tables, &v.Rows,
+			&checks, &v.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan backup verification: %w", err)
+		}
+		if err := json.Unmarshal(checks, &v.Checks); err != nil {
+			return nil, fmt.Errorf("failed to decode backup checks: %w", err)
+		}
+		verifications = append(verifications, v)
+	}
+	return verifications, rows.Err()
+}
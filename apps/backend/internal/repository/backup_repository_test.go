@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestBackupRepository_CountOrphans(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLBackupRepository(db)
+	fk := domain.BackupForeignKey{Name: "images_property_id_fkey", Table: "images", Columns: []string{"property_id"},
+		ParentTable: "properties", ParentColumns: []string{"id"}}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "backup_verify_20250916030000"."images" c`) +
+		`\s+WHERE c\."property_id" IS NOT NULL AND NOT EXISTS \(\s+SELECT 1 FROM "backup_verify_20250916030000"\."properties" p\s+WHERE p\."id" = c\."property_id"\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	orphans, err := repo.CountOrphans("backup_verify_20250916030000", fk)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), orphans)
+
+	fk.ParentColumns = nil
+	_, err = repo.CountOrphans("backup_verify_20250916030000", fk)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBackupRepository_DropSchema(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLBackupRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DROP SCHEMA IF EXISTS "backup_verify_20250916030000" CASCADE`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, repo.DropSchema("backup_verify_20250916030000"))
+
+	assert.Error(t, repo.DropSchema("public"), "only verification schemas are dropped")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+// CalendarSyncRepository defines data access for the external calendars agents sync
+// their agenda with
+type CalendarSyncRepository interface {
+	// SaveConnection creates or replaces the calendar connection of an agent
+	SaveConnection(connection *domain.CalendarConnection) error
+
+	// GetConnection retrieves the calendar connection of an agent
+	GetConnection(agentID string) (*domain.CalendarConnection, error)
+
+	// GetConnectionByState retrieves the connection waiting for an authorization state
+	GetConnectionByState(stateHash string) (*domain.CalendarConnection, error)
+
+	// ListActiveConnections lists the connections to sync, least recently synced first
+	ListActiveConnections(limit int) ([]domain.CalendarConnection, error)
+
+	// DeleteConnection deletes the calendar connection of an agent with its imported
+	// busy times and pushed event links
+	DeleteConnection(agentID string) error
+
+	// ListEventLinks lists the events the appointments of a connection were pushed as
+	ListEventLinks(connectionID string) ([]domain.CalendarEventLink, error)
+
+	// SaveEventLink creates or replaces the event an appointment was pushed as
+	SaveEventLink(link *domain.CalendarEventLink) error
+
+	// DeleteEventLink forgets the event an appointment was pushed as
+	DeleteEventLink(connectionID, appointmentID string) error
+
+	// SaveBusyBlock creates or updates a busy time by its external event
+	SaveBusyBlock(block *domain.CalendarBusyBlock) error
+
+	// DeleteBusyBlock deletes the busy time of an external event, reporting whether it existed
+	DeleteBusyBlock(connectionID, externalID string) (bool, error)
+
+	// DeleteBusyBlocks deletes every busy time imported by a connection
+	DeleteBusyBlocks(connectionID string) error
+
+	// ListBusyBlocks lists the busy times of an agent overlapping from and to
+	ListBusyBlocks(agentID string, from, to time.Time) ([]domain.CalendarBusyBlock, error)
+}
+
+// PostgreSQLCalendarSyncRepository implements CalendarSyncRepository using PostgreSQL
+type PostgreSQLCalendarSyncRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+// NewPostgreSQLCalendarSyncRepository creates a new PostgreSQL calendar sync repository
+func NewPostgreSQLCalendarSyncRepository(db *sql.DB) *PostgreSQLCalendarSyncRepository {
+	return &PostgreSQLCalendarSyncRepository{db: db}
+}
+
+// SetCipher encrypts the OAuth tokens of calendar connections at rest
+func (r *PostgreSQLCalendarSyncRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+const calendarConnectionColumns = `id, agent_id, provider, calendar_id, calendar_name, status, access_token,
+	refresh_token, token_expires_at, sync_token, COALESCE(state_hash, ''), state_expires_at, last_synced_at,
+	last_error, created_at, updated_at`
+
+// scanCalendarConnection scans a connection selected with calendarConnectionColumns
+// and decrypts its tokens
+func (r *PostgreSQLCalendarSyncRepository) scanCalendarConnection(row interface{ Scan(...interface{}) error }) (*domain.CalendarConnection, error) {
+	connection := &domain.CalendarConnection{}
+	err := row.Scan(&connection.ID, &connection.AgentID, &connection.Provider, &connection.CalendarID,
+		&connection.CalendarName, &connection.Status, &connection.AccessToken, &connection.RefreshToken,
+		&connection.TokenExpiresAt, &connection.SyncToken, &connection.StateHash, &connection.StateExpiresAt,
+		&connection.LastSyncedAt, &connection.LastError, &connection.CreatedAt, &connection.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if connection.AccessToken, err = r.cipher.Decrypt(pii.CalendarAccessToken, connection.ID, connection.AccessToken); err != nil {
+		return nil, fmt.Errorf("failed to decrypt calendar access token: %w", err)
+	}
+	if connection.RefreshToken, err = r.cipher.Decrypt(pii.CalendarRefreshToken, connection.ID, connection.RefreshToken); err != nil {
+		return nil, fmt.Errorf("failed to decrypt calendar refresh token: %w", err)
+	}
+	return connection, nil
+}
+
+// SaveConnection creates or replaces the calendar connection of an agent
+func (r *PostgreSQLCalendarSyncRepository) SaveConnection(connection *domain.CalendarConnection) error {
+	accessToken, err := r.cipher.Encrypt(pii.CalendarAccessToken, connection.ID, connection.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt calendar access token: %w", err)
+	}
+	refreshToken, err := r.cipher.Encrypt(pii.CalendarRefreshToken, connection.ID, connection.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt calendar refresh token: %w", err)
+	}
+	var stateHash *string
+	if connection.StateHash != "" {
+		stateHash = &connection.StateHash
+	}
+
+	query := `
+		INSERT INTO calendar_connections (id, agent_id, provider, calendar_id, calendar_name, status, access_token,
+			refresh_token, token_expires_at, sync_token, state_hash, state_expires_at, last_synced_at,
+			last_error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (agent_id) DO UPDATE SET
+			provider = EXCLUDED.provider, calendar_id = EXCLUDED.calendar_id,
+			calendar_name = EXCLUDED.calendar_name, status = EXCLUDED.status,
+			access_token = EXCLUDED.access_token, refresh_token = EXCLUDED.refresh_token,
+			token_expires_at = EXCLUDED.token_expires_at, sync_token = EXCLUDED.sync_token,
+			state_hash = EXCLUDED.state_hash, state_expires_at = EXCLUDED.state_expires_at,
+			last_synced_at = EXCLUDED.last_synced_at, last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.Exec(query, connection.ID, connection.AgentID, connection.Provider, connection.CalendarID,
+		connection.CalendarName, connection.Status, accessToken, refreshToken, connection.TokenExpiresAt,
+		connection.SyncToken, stateHash, connection.StateExpiresAt, connection.LastSyncedAt,
+		connection.LastError, connection.CreatedAt, connection.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar connection: %w", err)
+	}
+	return nil
+}
+
+// GetConnection retrieves the calendar connection of an agent
+func (r *PostgreSQLCalendarSyncRepository) GetConnection(agentID string) (*domain.CalendarConnection, error) {
+	query := `SELECT ` + calendarConnectionColumns + ` FROM calendar_connections WHERE agent_id = $1`
+
+	connection, err := r.scanCalendarConnection(r.db.QueryRow(query, agentID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("calendar connection not found: %s", agentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar connection: %w", err)
+	}
+	return connection, nil
+}
+
+// GetConnectionByState retrieves the connection waiting for an authorization state
+func (r *PostgreSQLCalendarSyncRepository) GetConnectionByState(stateHash string) (*domain.CalendarConnection, error) {
+	query := `SELECT ` + calendarConnectionColumns + ` FROM calendar_connections WHERE state_hash = $1`
+
+	connection, err := r.scanCalendarConnection(r.db.QueryRow(query, stateHash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("calendar authorization not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar connection: %w", err)
+	}
+	return connection, nil
+}
+
+// ListActiveConnections lists the connections to sync, least recently synced first
+func (r *PostgreSQLCalendarSyncRepository) ListActiveConnections(limit int) ([]domain.CalendarConnection, error) {
+	query := `SELECT ` + calendarConnectionColumns + ` FROM calendar_connections
+		WHERE status = $1
+		ORDER BY last_synced_at NULLS FIRST, id
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, domain.CalendarConnectionActive, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar connections: %w", err)
+	}
+	defer rows.Close()
+
+	connections := []domain.CalendarConnection{}
+	for rows.Next() {
+		connection, err := r.scanCalendarConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar connection: %w", err)
+		}
+		connections = append(connections, *connection)
+	}
+	return connections, rows.Err()
+}
+
+// DeleteConnection deletes the calendar connection of an agent; its busy times and
+// event links are deleted with it
+func (r *PostgreSQLCalendarSyncRepository) DeleteConnection(agentID string) error {
+	result, err := r.db.Exec(`DELETE FROM calendar_connections WHERE agent_id = $1`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar connection: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("calendar connection not found: %s", agentID)
+	}
+	return nil
+}
+
+// ListEventLinks lists the events the appointments of a connection were pushed as
+func (r *PostgreSQLCalendarSyncRepository) ListEventLinks(connectionID string) ([]domain.CalendarEventLink, error) {
+	query := `SELECT connection_id, appointment_id, external_id, etag, sequence, pushed_at
+		FROM calendar_event_links WHERE connection_id = $1`
+
+	rows, err := r.db.Query(query, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar event links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []domain.CalendarEventLink{}
+	for rows.Next() {
+		var link domain.CalendarEventLink
+		if err := rows.Scan(&link.ConnectionID, &link.AppointmentID, &link.ExternalID, &link.ETag,
+			&link.Sequence, &link.PushedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar event link: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// SaveEventLink creates or replaces the event an appointment was pushed as
+func (r *PostgreSQLCalendarSyncRepository) SaveEventLink(link *domain.CalendarEventLink) error {
+	query := `
+		INSERT INTO calendar_event_links (connection_id, appointment_id, external_id, etag, sequence, pushed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (connection_id, appointment_id) DO UPDATE SET
+			external_id = EXCLUDED.external_id, etag = EXCLUDED.etag,
+			sequence = EXCLUDED.sequence, pushed_at = EXCLUDED.pushed_at`
+
+	_, err := r.db.Exec(query, link.ConnectionID, link.AppointmentID, link.ExternalID, link.ETag, link.Sequence, link.PushedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar event link: %w", err)
+	}
+	return nil
+}
+
+// DeleteEventLink forgets the event an appointment was pushed as
+func (r *PostgreSQLCalendarSyncRepository) DeleteEventLink(connectionID, appointmentID string) error {
+	_, err := r.db.Exec(`DELETE FROM calendar_event_links WHERE connection_id = $1 AND appointment_id = $2`,
+		connectionID, appointmentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar event link: %w", err)
+	}
+	return nil
+}
+
+// SaveBusyBlock creates or updates a busy time by its external event
+func (r *PostgreSQLCalendarSyncRepository) SaveBusyBlock(block *domain.CalendarBusyBlock) error {
+	if block.ID == "" {
+		block.ID = uuid.New().String()
+	}
+	query := `
+		INSERT INTO calendar_busy_blocks (id, connection_id, agent_id, external_id, starts_at, ends_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (connection_id, external_id) DO UPDATE SET
+			starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(query, block.ID, block.ConnectionID, block.AgentID, block.ExternalID,
+		block.StartsAt, block.EndsAt, block.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save busy time: %w", err)
+	}
+	return nil
+}
+
+// DeleteBusyBlock deletes the busy time of an external event, reporting whether it existed
+func (r *PostgreSQLCalendarSyncRepository) DeleteBusyBlock(connectionID, externalID string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM calendar_busy_blocks WHERE connection_id = $1 AND external_id = $2`,
+		connectionID, externalID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete busy time: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteBusyBlocks deletes every busy time imported by a connection
+func (r *PostgreSQLCalendarSyncRepository) DeleteBusyBlocks(connectionID string) error {
+	if _, err := r.db.Exec(`DELETE FROM calendar_busy_blocks WHERE connection_id = $1`, connectionID); err != nil {
+		return fmt.Errorf("failed to delete busy times: %w", err)
+	}
+	return nil
+}
+
+// ListBusyBlocks lists the busy times of an agent overlapping from and to, earliest first
+func (r *PostgreSQLCalendarSyncRepository) ListBusyBlocks(agentID string, from, to time.Time) ([]domain.CalendarBusyBlock, error) {
+	query := `SELECT id, connection_id, agent_id, external_id, starts_at, ends_at, updated_at
+		FROM calendar_busy_blocks
+		WHERE agent_id = $1 AND starts_at < $3 AND ends_at > $2
+		ORDER BY starts_at, id`
+
+	rows, err := r.db.Query(query, agentID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list busy times: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := []domain.CalendarBusyBlock{}
+	for rows.Next() {
+		var block domain.CalendarBusyBlock
+		if err := rows.Scan(&block.ID, &block.ConnectionID, &block.AgentID, &block.ExternalID,
+			&block.StartsAt, &block.EndsAt, &block.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan busy time: %w", err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, rows.Err()
+}
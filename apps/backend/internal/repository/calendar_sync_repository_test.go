@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+var calendarConnectionRowColumns = []string{"id", "agent_id", "provider", "calendar_id", "calendar_name", "status",
+	"access_token", "refresh_token", "token_expires_at", "sync_token", "state_hash", "state_expires_at",
+	"last_synced_at", "last_error", "created_at", "updated_at"}
+
+func TestCalendarSyncRepository_EncryptsTokens(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	keys, err := pii.ParseKeySet("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	cipher, err := pii.NewCipher(keys)
+	require.NoError(t, err)
+	repo := NewPostgreSQLCalendarSyncRepository(db)
+	repo.SetCipher(cipher)
+
+	now := time.Now()
+	connection := domain.NewCalendarConnection("connection-1", "agent-1", now)
+	_, err = connection.StartAuthorization(now)
+	require.NoError(t, err)
+	require.NoError(t, connection.Authorize(&domain.CalendarToken{AccessToken: "ya29.token", RefreshToken: "1//refresh",
+		ExpiresAt: now.Add(time.Hour)}, now))
+
+	var accessToken, refreshToken string
+	mock.ExpectExec(`INSERT INTO calendar_connections (.+) ON CONFLICT \(agent_id\) DO UPDATE`).
+		WithArgs("connection-1", "agent-1", domain.CalendarProviderGoogle, domain.CalendarPrimary, "",
+			domain.CalendarConnectionActive, encryptedArg{&accessToken}, encryptedArg{&refreshToken}, connection.TokenExpiresAt,
+			"", nil, nil, nil, "", now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SaveConnection(connection))
+
+	mock.ExpectQuery(`SELECT (.+) FROM calendar_connections WHERE agent_id = \$1`).
+		WithArgs("agent-1").
+		WillReturnRows(sqlmock.NewRows(calendarConnectionRowColumns).
+			AddRow("connection-1", "agent-1", "google", "primary", "maria@example.com", "active", accessToken, refreshToken,
+				connection.TokenExpiresAt, "sync-1", "", nil, nil, "", now, now))
+
+	found, err := repo.GetConnection("agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, "ya29.token", found.AccessToken)
+	assert.Equal(t, "1//refresh", found.RefreshToken)
+	assert.Equal(t, "sync-1", found.SyncToken)
+
+	mock.ExpectQuery(`SELECT (.+) FROM calendar_connections WHERE agent_id = \$1`).
+		WithArgs("agent-2").
+		WillReturnRows(sqlmock.NewRows(calendarConnectionRowColumns))
+	_, err = repo.GetConnection("agent-2")
+	assert.ErrorContains(t, err, "calendar connection not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalendarSyncRepository_ListBusyBlocks(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLCalendarSyncRepository(db)
+
+	from := time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	mock.ExpectQuery(`SELECT (.+) FROM calendar_busy_blocks\s+WHERE agent_id = \$1 AND starts_at < \$3 AND ends_at > \$2`).
+		WithArgs("agent-1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "connection_id", "agent_id", "external_id", "starts_at", "ends_at", "updated_at"}).
+			AddRow("block-1", "connection-1", "agent-1", "e1", from.Add(15*time.Hour), from.Add(16*time.Hour), from))
+
+	blocks, err := repo.ListBusyBlocks("agent-1", from, to)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "e1", blocks[0].ExternalID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
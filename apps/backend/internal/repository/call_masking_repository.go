@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+// CallMaskingRepository defines data access for the proxy numbers lent to leads and
+// the calls placed through them
+type CallMaskingRepository interface {
+	// CreateSession saves a new call session, reporting false when the lead already
+	// has an active one
+	CreateSession(session *domain.CallSession) (bool, error)
+
+	// GetActiveSession retrieves the active call session of a lead
+	GetActiveSession(leadID string) (*domain.CallSession, error)
+
+	// FindSession retrieves the unexpired session a caller reaches by calling a proxy number
+	FindSession(proxyNumber, caller string, now time.Time) (*domain.CallSession, error)
+
+	// ListBusyNumbers lists the proxy numbers of unexpired sessions of any of the phones
+	ListBusyNumbers(phones []string, now time.Time) ([]string, error)
+
+	// CloseSession saves a closed session
+	CloseSession(session *domain.CallSession) error
+
+	// CreateCall saves a call, ignoring calls already recorded
+	CreateCall(call *domain.LeadCall) error
+
+	// GetCallBySID retrieves a call by its provider ID
+	GetCallBySID(callSID string) (*domain.LeadCall, error)
+
+	// FinishCall saves how a call ended
+	FinishCall(call *domain.LeadCall) error
+
+	// ListLeadCalls lists the calls of a lead, oldest first
+	ListLeadCalls(leadID string) ([]domain.LeadCall, error)
+}
+
+// PostgreSQLCallMaskingRepository implements CallMaskingRepository using PostgreSQL
+type PostgreSQLCallMaskingRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+// NewPostgreSQLCallMaskingRepository creates a new PostgreSQL call masking repository
+func NewPostgreSQLCallMaskingRepository(db *sql.DB) *PostgreSQLCallMaskingRepository {
+	return &PostgreSQLCallMaskingRepository{db: db}
+}
+
+// SetCipher encrypts the participants' phone numbers at rest
+func (r *PostgreSQLCallMaskingRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+const callSessionColumns = `id, lead_id, agency_id, proxy_number, buyer_id, buyer_phone, agent_id, agent_phone,
+	status, expires_at, created_at, closed_at`
+
+// scanCallSession scans a session selected with callSessionColumns and decrypts the
+// participants' phone numbers
+func (r *PostgreSQLCallMaskingRepository) scanCallSession(row interface{ Scan(...interface{}) error }) (*domain.CallSession, error) {
+	session := &domain.CallSession{}
+	err := row.Scan(&session.ID, &session.LeadID, &session.AgencyID, &session.ProxyNumber, &session.BuyerID,
+		&session.BuyerPhone, &session.AgentID, &session.AgentPhone, &session.Status, &session.ExpiresAt,
+		&session.CreatedAt, &session.ClosedAt)
+	if err != nil {
+		return nil, err
+	}
+	if session.BuyerPhone, err = r.cipher.Decrypt(pii.CallBuyerPhone, session.ID, session.BuyerPhone); err != nil {
+		return nil, fmt.Errorf("failed to decrypt buyer phone of call session %s: %w", session.ID, err)
+	}
+	if session.AgentPhone, err = r.cipher.Decrypt(pii.CallAgentPhone, session.ID, session.AgentPhone); err != nil {
+		return nil, fmt.Errorf("failed to decrypt agent phone of call session %s: %w", session.ID, err)
+	}
+	return session, nil
+}
+
+// phoneIndex returns the blind index of a phone number, NULL without a cipher
+func (r *PostgreSQLCallMaskingRepository) phoneIndex(phone string) sql.NullString {
+	index := r.cipher.BlindIndex(phone)
+	return sql.NullString{String: index, Valid: index != ""}
+}
+
+// CreateSession saves a new call session, reporting false when the lead already has an
+// active one
+func (r *PostgreSQLCallMaskingRepository) CreateSession(session *domain.CallSession) (bool, error) {
+	buyerPhone, err := r.cipher.Encrypt(pii.CallBuyerPhone, session.ID, session.BuyerPhone)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt buyer phone: %w", err)
+	}
+	agentPhone, err := r.cipher.Encrypt(pii.CallAgentPhone, session.ID, session.AgentPhone)
+	if err != nil {
+		return false, fmt.Errorf("failed to encrypt agent phone: %w", err)
+	}
+
+	query := `
+		INSERT INTO call_sessions (id, lead_id, agency_id, proxy_number, buyer_id, buyer_phone, buyer_phone_index,
+			agent_id, agent_phone, agent_phone_index, status, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (lead_id) WHERE status = 'active' DO NOTHING`
+
+	result, err := r.db.Exec(query, session.ID, session.LeadID, session.AgencyID, session.ProxyNumber,
+		session.BuyerID, buyerPhone, r.phoneIndex(session.BuyerPhone), session.AgentID, agentPhone,
+		r.phoneIndex(session.AgentPhone), session.Status, session.ExpiresAt, session.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create call session: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetActiveSession retrieves the active call session of a lead
+func (r *PostgreSQLCallMaskingRepository) GetActiveSession(leadID string) (*domain.CallSession, error) {
+	query := `SELECT ` + callSessionColumns + ` FROM call_sessions WHERE lead_id = $1 AND status = $2`
+
+	session, err := r.scanCallSession(r.db.QueryRow(query, leadID, domain.CallSessionActive))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("call session not found for lead: %s", leadID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call session: %w", err)
+	}
+	return session, nil
+}
+
+// FindSession retrieves the unexpired session a caller reaches by calling a proxy
+// number. Encrypted numbers are found by their blind index; rows written without a
+// cipher hold the plaintext.
+func (r *PostgreSQLCallMaskingRepository) FindSession(proxyNumber, caller string, now time.Time) (*domain.CallSession, error) {
+	query := `SELECT ` + callSessionColumns + ` FROM call_sessions
+		WHERE proxy_number = $1 AND status = $2 AND expires_at > $3
+			AND (buyer_phone_index = $4 OR agent_phone_index = $4 OR buyer_phone = $5 OR agent_phone = $5)
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	session, err := r.scanCallSession(r.db.QueryRow(query, proxyNumber, domain.CallSessionActive, now,
+		r.phoneIndex(caller), caller))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("call session not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find call session: %w", err)
+	}
+	return session, nil
+}
+
+// ListBusyNumbers lists the proxy numbers of unexpired sessions of any of the phones
+func (r *PostgreSQLCallMaskingRepository) ListBusyNumbers(phones []string, now time.Time) ([]string, error) {
+	indexes := make([]string, 0, len(phones))
+	for _, phone := range phones {
+		if index := r.cipher.BlindIndex(phone); index != "" {
+			indexes = append(indexes, index)
+		}
+	}
+
+	query := `SELECT DISTINCT proxy_number FROM call_sessions
+		WHERE status = $1 AND expires_at > $2
+			AND (buyer_phone_index = ANY($3) OR agent_phone_index = ANY($3)
+				OR buyer_phone = ANY($4) OR agent_phone = ANY($4))`
+
+	rows, err := r.db.Query(query, domain.CallSessionActive, now, pq.Array(indexes), pq.Array(phones))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list busy proxy numbers: %w", err)
+	}
+	defer rows.Close()
+
+	numbers := []string{}
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			return nil, fmt.Errorf("failed to scan proxy number: %w", err)
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, rows.Err()
+}
+
+// CloseSession saves a closed session
+func (r *PostgreSQLCallMaskingRepository) CloseSession(session *domain.CallSession) error {
+	_, err := r.db.Exec(`UPDATE call_sessions SET status = $2, closed_at = $3 WHERE id = $1`,
+		session.ID, session.Status, session.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("failed to close call session: %w", err)
+	}
+	return nil
+}
+
+const leadCallColumns = `id, session_id, lead_id, call_sid, caller_role, status, duration_seconds, started_at, ended_at`
+
+func scanLeadCall(row interface{ Scan(...interface{}) error }) (*domain.LeadCall, error) {
+	call := &domain.LeadCall{}
+	err := row.Scan(&call.ID, &call.SessionID, &call.LeadID, &call.CallSID, &call.CallerRole, &call.Status,
+		&call.DurationSeconds, &call.StartedAt, &call.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// CreateCall saves a call; the provider retries webhooks, so calls already recorded
+// are ignored
+func (r *PostgreSQLCallMaskingRepository) CreateCall(call *domain.LeadCall) error {
+	query := `
+		INSERT INTO lead_calls (id, session_id, lead_id, call_sid, caller_role, status, duration_seconds, started_at, ended_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (call_sid) DO NOTHING`
+
+	_, err := r.db.Exec(query, call.ID, call.SessionID, call.LeadID, call.CallSID, call.CallerRole, call.Status,
+		call.DurationSeconds, call.StartedAt, call.EndedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lead call: %w", err)
+	}
+	return nil
+}
+
+// GetCallBySID retrieves a call by its provider ID
+func (r *PostgreSQLCallMaskingRepository) GetCallBySID(callSID string) (*domain.LeadCall, error) {
+	query := `SELECT ` + leadCallColumns + ` FROM lead_calls WHERE call_sid = $1`
+
+	call, err := scanLeadCall(r.db.QueryRow(query, callSID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("lead call not found: %s", callSID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead call: %w", err)
+	}
+	return call, nil
+}
+
+// FinishCall saves how a call ended
+func (r *PostgreSQLCallMaskingRepository) FinishCall(call *domain.LeadCall) error {
+	_, err := r.db.Exec(`UPDATE lead_calls SET status = $2, duration_seconds = $3, ended_at = $4 WHERE id = $1`,
+		call.ID, call.Status, call.DurationSeconds, call.EndedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish lead call: %w", err)
+	}
+	return nil
+}
+
+// ListLeadCalls lists the calls of a lead, oldest first
+func (r *PostgreSQLCallMaskingRepository) ListLeadCalls(leadID string) ([]domain.LeadCall, error) {
+	query := `SELECT ` + leadCallColumns + ` FROM lead_calls WHERE lead_id = $1 ORDER BY started_at`
+
+	rows, err := r.db.Query(query, leadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead calls: %w", err)
+	}
+	defer rows.Close()
+
+	calls := []domain.LeadCall{}
+	for rows.Next() {
+		call, err := scanLeadCall(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lead call: %w", err)
+		}
+		calls = append(calls, *call)
+	}
+	return calls, rows.Err()
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+var callSessionRowColumns = []string{"id", "lead_id", "agency_id", "proxy_number", "buyer_id", "buyer_phone",
+	"agent_id", "agent_phone", "status", "expires_at", "created_at", "closed_at"}
+
+func TestCallMaskingRepository_SessionsByBlindIndex(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	keys, err := pii.ParseKeySet("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	cipher, err := pii.NewCipher(keys)
+	require.NoError(t, err)
+	repo := NewPostgreSQLCallMaskingRepository(db)
+	repo.SetCipher(cipher)
+
+	now := time.Now()
+	session := &domain.CallSession{ID: "session-1", LeadID: "lead-1", AgencyID: "agency-1", ProxyNumber: "+593991110000",
+		BuyerID: "buyer-1", BuyerPhone: "+593991234567", AgentID: "agent-1", AgentPhone: "+593987654321",
+		Status: domain.CallSessionActive, ExpiresAt: now.Add(domain.DefaultCallSessionTTL), CreatedAt: now}
+
+	var buyerPhone, agentPhone string
+	buyerIndex := cipher.BlindIndex("+593991234567")
+	mock.ExpectExec(`INSERT INTO call_sessions (.+) ON CONFLICT \(lead_id\) WHERE status = 'active' DO NOTHING`).
+		WithArgs("session-1", "lead-1", "agency-1", "+593991110000", "buyer-1", encryptedArg{&buyerPhone}, buyerIndex,
+			"agent-1", encryptedArg{&agentPhone}, cipher.BlindIndex("+593987654321"), domain.CallSessionActive,
+			session.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	created, err := repo.CreateSession(session)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	mock.ExpectQuery(`SELECT (.+) FROM call_sessions\s+WHERE proxy_number = \$1 AND status = \$2 AND expires_at > \$3`).
+		WithArgs("+593991110000", domain.CallSessionActive, now, buyerIndex, "+593991234567").
+		WillReturnRows(sqlmock.NewRows(callSessionRowColumns).
+			AddRow("session-1", "lead-1", "agency-1", "+593991110000", "buyer-1", buyerPhone, "agent-1", agentPhone,
+				"active", session.ExpiresAt, now, nil))
+
+	found, err := repo.FindSession("+593991110000", "+593991234567", now)
+	require.NoError(t, err)
+	assert.Equal(t, "+593991234567", found.BuyerPhone)
+	assert.Equal(t, "+593987654321", found.AgentPhone)
+
+	mock.ExpectQuery(`SELECT (.+) FROM call_sessions\s+WHERE proxy_number = \$1`).
+		WithArgs("+593991110000", domain.CallSessionActive, now, cipher.BlindIndex("+593990000000"), "+593990000000").
+		WillReturnRows(sqlmock.NewRows(callSessionRowColumns))
+	_, err = repo.FindSession("+593991110000", "+593990000000", now)
+	assert.ErrorContains(t, err, "call session not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCallMaskingRepository_ListLeadCalls(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLCallMaskingRepository(db)
+
+	started := time.Date(2025, 9, 24, 15, 0, 0, 0, time.UTC)
+	ended := started.Add(2 * time.Minute)
+	mock.ExpectQuery(`SELECT (.+) FROM lead_calls WHERE lead_id = \$1 ORDER BY started_at`).
+		WithArgs("lead-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "lead_id", "call_sid", "caller_role", "status",
+			"duration_seconds", "started_at", "ended_at"}).
+			AddRow("call-1", "session-1", "lead-1", "CA1", "buyer", "completed", 95, started, ended))
+
+	calls, err := repo.ListLeadCalls("lead-1")
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, domain.LeadCallCompleted, calls[0].Status)
+	assert.Equal(t, 95, calls[0].DurationSeconds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
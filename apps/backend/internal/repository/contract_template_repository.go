@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// ContractTemplateRepository defines the interface for versioned contract templates
+type ContractTemplateRepository interface {
+	// CreateVersion saves a template as the next version of its name and sets its version
+	CreateVersion(tmpl *domain.ContractTemplate) error
+
+	// GetByID retrieves a template version by ID
+	GetByID(id string) (*domain.ContractTemplate, error)
+
+	// GetActive retrieves the active version of a template
+	GetActive(name string) (*domain.ContractTemplate, error)
+
+	// ListActive retrieves the active version of every template, optionally of one type
+	ListActive(contractType string) ([]domain.ContractTemplate, error)
+
+	// ListVersions retrieves every version of a template, newest first
+	ListVersions(name string) ([]domain.ContractTemplate, error)
+
+	// Activate makes a version the active one of its template
+	Activate(id string) error
+}
+
+// PostgreSQLContractTemplateRepository implements ContractTemplateRepository using PostgreSQL
+type PostgreSQLContractTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLContractTemplateRepository creates a new PostgreSQL contract template repository
+func NewPostgreSQLContractTemplateRepository(db *sql.DB) *PostgreSQLContractTemplateRepository {
+	return &PostgreSQLContractTemplateRepository{db: db}
+}
+
+const contractTemplateColumns = `id, name, type, version, title, body, active, created_by, created_at`
+
+// CreateVersion saves a template as the next version of its name. The version is
+// assigned in the insert; concurrent saves of the same name fail on the unique version.
+func (r *PostgreSQLContractTemplateRepository) CreateVersion(tmpl *domain.ContractTemplate) error {
+	if tmpl == nil {
+		return fmt.Errorf("template cannot be nil")
+	}
+
+	var createdBy sql.NullString
+	if tmpl.CreatedBy != "" {
+		createdBy = sql.NullString{String: tmpl.CreatedBy, Valid: true}
+	}
+
+	query := `
+		INSERT INTO contract_templates (` + contractTemplateColumns + `)
+		SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, FALSE, $6, $7
+		FROM contract_templates WHERE name = $2
+		RETURNING version`
+
+	err := r.db.QueryRow(query,
+		tmpl.ID, tmpl.Name, tmpl.Type, tmpl.Title, tmpl.Body, createdBy, tmpl.CreatedAt,
+	).Scan(&tmpl.Version)
+	if err != nil {
+		return fmt.Errorf("failed to create template version: %w", err)
+	}
+
+	tmpl.Active = false
+	return nil
+}
+
+// GetByID retrieves a template version by ID
+func (r *PostgreSQLContractTemplateRepository) GetByID(id string) (*domain.ContractTemplate, error) {
+	query := `SELECT ` + contractTemplateColumns + ` FROM contract_templates WHERE id = $1`
+
+	tmpl, err := scanContractTemplate(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("template not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// GetActive retrieves the active version of a template
+func (r *PostgreSQLContractTemplateRepository) GetActive(name string) (*domain.ContractTemplate, error) {
+	query := `SELECT ` + contractTemplateColumns + ` FROM contract_templates WHERE name = $1 AND active`
+
+	tmpl, err := scanContractTemplate(r.db.QueryRow(query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("template not found or not active: %s", name)
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// ListActive retrieves the active version of every template, optionally of one type
+func (r *PostgreSQLContractTemplateRepository) ListActive(contractType string) ([]domain.ContractTemplate, error) {
+	query := `SELECT ` + contractTemplateColumns + ` FROM contract_templates WHERE active`
+	args := []interface{}{}
+	if contractType != "" {
+		query += ` AND type = $1`
+		args = append(args, contractType)
+	}
+	query += ` ORDER BY name ASC`
+
+	return r.list(query, args...)
+}
+
+// ListVersions retrieves every version of a template, newest first
+func (r *PostgreSQLContractTemplateRepository) ListVersions(name string) ([]domain.ContractTemplate, error) {
+	query := `SELECT ` + contractTemplateColumns + ` FROM contract_templates WHERE name = $1 ORDER BY version DESC`
+
+	return r.list(query, name)
+}
+
+// Activate makes a version the active one of its template, deactivating the previous one
+func (r *PostgreSQLContractTemplateRepository) Activate(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name string
+	err = tx.QueryRow(`SELECT name FROM contract_templates WHERE id = $1 FOR UPDATE`, id).Scan(&name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("template not found: %s", id)
+		}
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE contract_templates SET active = FALSE WHERE name = $1 AND active`, name); err != nil {
+		return fmt.Errorf("failed to deactivate template: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE contract_templates SET active = TRUE WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to activate template: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// list runs a query selecting contractTemplateColumns
+func (r *PostgreSQLContractTemplateRepository) list(query string, args ...interface{}) ([]domain.ContractTemplate, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []domain.ContractTemplate{}
+	for rows.Next() {
+		tmpl, err := scanContractTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, *tmpl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return templates, nil
+}
+
+// scanContractTemplate scans a row selected with contractTemplateColumns
+func scanContractTemplate(row interface{ Scan(...interface{}) error }) (*domain.ContractTemplate, error) {
+	tmpl := &domain.ContractTemplate{}
+	var createdBy sql.NullString
+	err := row.Scan(&tmpl.ID, &tmpl.Name, &tmpl.Type, &tmpl.Version, &tmpl.Title, &tmpl.Body,
+		&tmpl.Active, &createdBy, &tmpl.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.CreatedBy = createdBy.String
+	return tmpl, nil
+}
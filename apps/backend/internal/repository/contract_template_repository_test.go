@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestContractTemplateRepository_CreateVersion(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLContractTemplateRepository(db)
+	tmpl := &domain.ContractTemplate{
+		ID: "tmpl-2", Name: "arriendo-estandar", Type: "rental", Title: "Contrato", Body: "{{.Owner.Name}}",
+		CreatedBy: "admin-1", CreatedAt: time.Now(),
+	}
+
+	mock.ExpectQuery("INSERT INTO contract_templates(.+)COALESCE\\(MAX\\(version\\), 0\\) \\+ 1(.+)RETURNING version").
+		WithArgs("tmpl-2", "arriendo-estandar", "rental", "Contrato", "{{.Owner.Name}}", "admin-1", tmpl.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+
+	require.NoError(t, repo.CreateVersion(tmpl))
+	assert.Equal(t, 3, tmpl.Version)
+	assert.False(t, tmpl.Active)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContractTemplateRepository_Activate(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLContractTemplateRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name FROM contract_templates WHERE id = \\$1 FOR UPDATE").
+		WithArgs("tmpl-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("arriendo-estandar"))
+	mock.ExpectExec("UPDATE contract_templates SET active = FALSE WHERE name = \\$1").
+		WithArgs("arriendo-estandar").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE contract_templates SET active = TRUE WHERE id = \\$1").
+		WithArgs("tmpl-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name FROM contract_templates WHERE id = \\$1 FOR UPDATE").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	mock.ExpectRollback()
+
+	require.NoError(t, repo.Activate("tmpl-1"))
+	assert.ErrorContains(t, repo.Activate("missing"), "template not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
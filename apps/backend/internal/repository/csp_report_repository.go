@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// CSPReportRepository defines data access for the Content-Security-Policy violations
+// reported by browsers
+type CSPReportRepository interface {
+	// AddViolations adds the reports counted of each violation, creating the new ones
+	AddViolations(violations []domain.CSPViolation) error
+
+	// ListViolations returns the violations reported since a time, most reported first
+	ListViolations(since time.Time, limit int) ([]domain.CSPViolation, error)
+}
+
+// PostgreSQLCSPReportRepository implements CSPReportRepository using PostgreSQL
+type PostgreSQLCSPReportRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLCSPReportRepository creates a new PostgreSQL CSP report repository
+func NewPostgreSQLCSPReportRepository(db *sql.DB) *PostgreSQLCSPReportRepository {
+	return &PostgreSQLCSPReportRepository{db: db}
+}
+
+// AddViolations adds the reports counted of each violation, creating the new ones
+func (r *PostgreSQLCSPReportRepository) AddViolations(violations []domain.CSPViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	// Violations are written in a stable order so concurrent flushes do not deadlock
+	sorted := append([]domain.CSPViolation(nil), violations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Fingerprint < sorted[j].Fingerprint })
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, v := range sorted {
+		_, err := tx.Exec(`
+			INSERT INTO csp_violations (fingerprint, document_uri, effective_directive, blocked_uri, source_file,
+				line_number, disposition, count, user_agent, first_seen_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (fingerprint) DO UPDATE SET
+				count = csp_violations.count + EXCLUDED.count,
+				user_agent = EXCLUDED.user_agent,
+				first_seen_at = LEAST(csp_violations.first_seen_at, EXCLUDED.first_seen_at),
+				last_seen_at = GREATEST(csp_violations.last_seen_at, EXCLUDED.last_seen_at)`,
+			v.Fingerprint, v.DocumentURI, v.EffectiveDirective, v.BlockedURI, v.SourceFile, v.LineNumber,
+			v.Disposition, v.Count, v.UserAgent, v.FirstSeenAt, v.LastSeenAt)
+		if err != nil {
+			return fmt.Errorf("failed to add CSP violation %s: %w", v.Fingerprint, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit CSP violations: %w", err)
+	}
+	return nil
+}
+
+// ListViolations returns the violations reported since a time, most reported first
+func (r *PostgreSQLCSPReportRepository) ListViolations(since time.Time, limit int) ([]domain.CSPViolation, error) {
+	query := `
+		SELECT fingerprint, document_uri, effective_directive, blocked_uri, source_file, line_number,
+			disposition, count, user_agent, first_seen_at, last_seen_at
+		FROM csp_violations
+		WHERE last_seen_at >= $1
+		ORDER BY count DESC, last_seen_at DESC, fingerprint
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSP violations: %w", err)
+	}
+	defer rows.Close()
+
+	violations := []domain.CSPViolation{}
+	for rows.Next() {
+		var v domain.CSPViolation
+		if err := rows.Scan(&v.Fingerprint, &v.DocumentURI, &v.EffectiveDirective, &v.BlockedURI, &v.SourceFile,
+			&v.LineNumber, &v.Disposition, &v.Count, &v.UserAgent, &v.FirstSeenAt, &v.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan CSP violation: %w", err)
+		}
+		violations = append(violations, v)
+	}
+	return violations, rows.Err()
+}
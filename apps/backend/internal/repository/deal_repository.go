@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+// DealRepository defines the interface for closed deal operations
+type DealRepository interface {
+	// Create stores a deal and marks its property as sold or rented
+	Create(deal *domain.Deal) error
+
+	// GetByID retrieves a deal by ID
+	GetByID(id string) (*domain.Deal, error)
+
+	// List retrieves deals matching a filter, latest closing first, with the total count
+	List(filter domain.DealFilter) ([]domain.Deal, int, error)
+
+	// GetAgencySummary aggregates the deals of an agency closed in a period
+	GetAgencySummary(agencyID string, from, to *time.Time) (*domain.DealSummary, error)
+}
+
+// PostgreSQLDealRepository implements DealRepository using PostgreSQL
+type PostgreSQLDealRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+// NewPostgreSQLDealRepository creates a new PostgreSQL deal repository
+func NewPostgreSQLDealRepository(db *sql.DB) *PostgreSQLDealRepository {
+	return &PostgreSQLDealRepository{db: db}
+}
+
+// SetCipher encrypts deal notes at rest; agents often keep client contact details there
+func (r *PostgreSQLDealRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+const dealColumns = `id, property_id, agency_id, agent_id, type, final_price, closing_date,
+		commission_percent, commission_amount, notes, recorded_by, created_at, client_id`
+
+// Create stores a deal and marks its property as sold or rented in a single transaction
+func (r *PostgreSQLDealRepository) Create(deal *domain.Deal) error {
+	return r.CreateWithEvents(deal)
+}
+
+// CreateWithEvents stores a deal, marks its property as sold or rented and stores the
+// outbox events in a single transaction
+func (r *PostgreSQLDealRepository) CreateWithEvents(deal *domain.Deal, events ...*domain.OutboxEvent) error {
+	if deal == nil {
+		return fmt.Errorf("deal cannot be nil")
+	}
+
+	notes, err := r.cipher.Encrypt(pii.DealNotes, deal.ID, deal.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt deal notes: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO deals (` + dealColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+	_, err = tx.Exec(query,
+		deal.ID, deal.PropertyID, deal.AgencyID, deal.AgentID, deal.Type, deal.FinalPrice, deal.ClosingDate,
+		deal.CommissionPercent, deal.CommissionAmount, notes, deal.RecordedBy, deal.CreatedAt, deal.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to create deal: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE properties SET status = $2, updated_at = $3 WHERE id = $1`,
+		deal.PropertyID, deal.PropertyStatus(), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update property status: %w", err)
+	}
+
+	if err := insertOutboxEvents(tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a deal by ID
+func (r *PostgreSQLDealRepository) GetByID(id string) (*domain.Deal, error) {
+	query := `SELECT ` + dealColumns + ` FROM deals WHERE id = $1`
+
+	deal, err := scanDeal(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("deal not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get deal: %w", err)
+	}
+
+	if err := r.openNotes(deal); err != nil {
+		return nil, err
+	}
+
+	return deal, nil
+}
+
+// List retrieves deals matching a filter, latest closing first, with the total count
+func (r *PostgreSQLDealRepository) List(filter domain.DealFilter) ([]domain.Deal, int, error) {
+	where, args := dealFilterClause(filter)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM deals`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deals: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM deals%s ORDER BY closing_date DESC LIMIT $%d OFFSET $%d`,
+		dealColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query deals: %w", err)
+	}
+	defer rows.Close()
+
+	deals := []domain.Deal{}
+	for rows.Next() {
+		deal, err := scanDeal(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan deal: %w", err)
+		}
+		if err := r.openNotes(deal); err != nil {
+			return nil, 0, err
+		}
+		deals = append(deals, *deal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return deals, total, nil
+}
+
+// GetAgencySummary aggregates the deals of an agency closed in a period, with the
+// commission earned by each agent
+func (r *PostgreSQLDealRepository) GetAgencySummary(agencyID string, from, to *time.Time) (*domain.DealSummary, error) {
+	where, args := dealFilterClause(domain.DealFilter{AgencyID: agencyID, From: from, To: to})
+	summary := &domain.DealSummary{AgencyID: agencyID, From: from, To: to, ByAgent: []domain.AgentCommission{}}
+
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE type = 'sale'),
+			COUNT(*) FILTER (WHERE type = 'rent'),
+			COALESCE(SUM(final_price) FILTER (WHERE type = 'sale'), 0),
+			COALESCE(SUM(final_price) FILTER (WHERE type = 'rent'), 0),
+			COALESCE(SUM(commission_amount), 0)
+		FROM deals` + where
+
+	err := r.db.QueryRow(query, args...).Scan(
+		&summary.TotalDeals, &summary.Sales, &summary.Rentals,
+		&summary.TotalSalesValue, &summary.TotalRentValue, &summary.TotalCommission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize deals: %w", err)
+	}
+
+	agentQuery := `
+		SELECT
+			d.agent_id,
+			COALESCE(MAX(u.first_name || ' ' || u.last_name), ''),
+			COUNT(*),
+			COALESCE(SUM(d.final_price) FILTER (WHERE d.type = 'sale'), 0),
+			COALESCE(SUM(d.final_price) FILTER (WHERE d.type = 'rent'), 0),
+			COALESCE(SUM(d.commission_amount), 0)
+		FROM deals d
+		LEFT JOIN users u ON u.id = d.agent_id` +
+		strings.Replace(where, "WHERE ", "WHERE d.agent_id IS NOT NULL AND ", 1) + `
+		GROUP BY d.agent_id
+		ORDER BY SUM(d.commission_amount) DESC`
+
+	rows, err := r.db.Query(agentQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize agent commissions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var agent domain.AgentCommission
+		if err := rows.Scan(&agent.AgentID, &agent.AgentName, &agent.Deals, &agent.SalesValue, &agent.RentValue, &agent.CommissionAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan agent commission: %w", err)
+		}
+		summary.ByAgent = append(summary.ByAgent, agent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return summary, nil
+}
+
+// dealFilterClause builds the WHERE clause of a deal filter
+func dealFilterClause(filter domain.DealFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.AgencyID != "" {
+		add("agency_id = $%d", filter.AgencyID)
+	}
+	if filter.AgentID != "" {
+		add("agent_id = $%d", filter.AgentID)
+	}
+	if filter.ClientID != "" {
+		add("client_id = $%d", filter.ClientID)
+	}
+	if filter.PropertyID != "" {
+		add("property_id = $%d", filter.PropertyID)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.From != nil {
+		add("closing_date >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("closing_date < $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// openNotes decrypts the notes of a scanned deal
+func (r *PostgreSQLDealRepository) openNotes(deal *domain.Deal) error {
+	notes, err := r.cipher.Decrypt(pii.DealNotes, deal.ID, deal.Notes)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt notes of deal %s: %w", deal.ID, err)
+	}
+	deal.Notes = notes
+	return nil
+}
+
+// scanDeal scans a row selected with dealColumns
+func scanDeal(row interface{ Scan(...interface{}) error }) (*domain.Deal, error) {
+	deal := &domain.Deal{}
+	var agencyID, agentID, clientID sql.NullString
+	err := row.Scan(
+		&deal.ID, &deal.PropertyID, &agencyID, &agentID, &deal.Type, &deal.FinalPrice, &deal.ClosingDate,
+		&deal.CommissionPercent, &deal.CommissionAmount, &deal.Notes, &deal.RecordedBy, &deal.CreatedAt, &clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if agencyID.Valid {
+		deal.AgencyID = &agencyID.String
+	}
+	if agentID.Valid {
+		deal.AgentID = &agentID.String
+	}
+	if clientID.Valid {
+		deal.ClientID = &clientID.String
+	}
+
+	return deal, nil
+}
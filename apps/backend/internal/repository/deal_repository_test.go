@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+func TestDealRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLDealRepository(db)
+	deal, err := domain.NewDeal("prop-1", domain.DealTypeRent, 900, 50, time.Now())
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO deals").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE properties SET status").
+		WithArgs("prop-1", domain.StatusRented, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Create(deal))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDealRepository_GetAgencySummary(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLDealRepository(db)
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM deals WHERE agency_id = \\$1 AND closing_date >= \\$2").
+		WithArgs("agency-1", from).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "sales", "rentals", "sales_value", "rent_value", "commission"}).
+			AddRow(3, 2, 1, 300000.0, 900.0, 9450.0))
+	mock.ExpectQuery("FROM deals d LEFT JOIN users u (.+) WHERE d.agent_id IS NOT NULL AND agency_id = \\$1").
+		WithArgs("agency-1", from).
+		WillReturnRows(sqlmock.NewRows([]string{"agent_id", "name", "deals", "sales_value", "rent_value", "commission"}).
+			AddRow("agent-1", "Ana Pérez", 2, 300000.0, 0.0, 9000.0).
+			AddRow("agent-2", "Luis Mora", 1, 0.0, 900.0, 450.0))
+
+	summary, err := repo.GetAgencySummary("agency-1", &from, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.TotalDeals)
+	assert.Equal(t, 2, summary.Sales)
+	assert.Equal(t, 9450.0, summary.TotalCommission)
+	require.Len(t, summary.ByAgent, 2)
+	assert.Equal(t, "Ana Pérez", summary.ByAgent[0].AgentName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDealRepository_EncryptsNotes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	keys, err := pii.ParseKeySet("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	cipher, err := pii.NewCipher(keys)
+	require.NoError(t, err)
+
+	repo := NewPostgreSQLDealRepository(db)
+	repo.SetCipher(cipher)
+	deal, err := domain.NewDeal("prop-1", domain.DealTypeSale, 120000, 3, time.Now())
+	require.NoError(t, err)
+	deal.Notes = "Comprador: 0987654321"
+
+	var stored string
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO deals").
+		WithArgs(deal.ID, "prop-1", nil, nil, domain.DealTypeSale, 120000.0, sqlmock.AnyArg(), 3.0, sqlmock.AnyArg(),
+			encryptedArg{&stored}, sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE properties SET status").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.Create(deal))
+
+	mock.ExpectQuery("SELECT (.+) FROM deals WHERE id = \\$1").
+		WithArgs(deal.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "property_id", "agency_id", "agent_id", "type", "final_price", "closing_date",
+			"commission_percent", "commission_amount", "notes", "recorded_by", "created_at", "client_id"}).
+			AddRow(deal.ID, "prop-1", nil, nil, domain.DealTypeSale, 120000.0, deal.ClosingDate, 3.0, 3600.0,
+				stored, "", deal.CreatedAt, nil))
+
+	found, err := repo.GetByID(deal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Comprador: 0987654321", found.Notes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// encryptedArg matches an encrypted argument and keeps it
+type encryptedArg struct {
+	value *string
+}
+
+func (a encryptedArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*a.value = s
+	return ok && strings.HasPrefix(s, "enc:v2:")
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// DescriptionReviewRepository defines data access for the listing descriptions queued by
+// the description policy
+type DescriptionReviewRepository interface {
+	// Save queues the review of a listing's latest description, replacing its pending
+	// review
+	Save(review *domain.DescriptionReview) error
+
+	// GetByPropertyID retrieves the review of a listing
+	GetByPropertyID(propertyID string) (*domain.DescriptionReview, error)
+
+	// DeletePending drops the pending review of a listing whose description no longer
+	// breaks the policy
+	DeletePending(propertyID string) error
+
+	// List retrieves reviews matching a filter, oldest first, with the total count
+	List(filter domain.DescriptionReviewFilter) ([]domain.DescriptionReview, int, error)
+
+	// Decide stores the decision on a pending review; a review decided meanwhile fails
+	// with a not found error
+	Decide(review *domain.DescriptionReview) error
+}
+
+// PostgreSQLDescriptionReviewRepository implements DescriptionReviewRepository using
+// PostgreSQL
+type PostgreSQLDescriptionReviewRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLDescriptionReviewRepository creates a new PostgreSQL description review
+// repository
+func NewPostgreSQLDescriptionReviewRepository(db *sql.DB) *PostgreSQLDescriptionReviewRepository {
+	return &PostgreSQLDescriptionReviewRepository{db: db}
+}
+
+const descriptionReviewColumns = `property_id, findings, original, status, reviewed_by, reviewed_at, created_at`
+
+// Save queues the review of a listing's latest description. Decided reviews are
+// replaced too, except overrides, which exempt the listing.
+func (r *PostgreSQLDescriptionReviewRepository) Save(review *domain.DescriptionReview) error {
+	findings, err := json.Marshal(review.Findings)
+	if err != nil {
+		return fmt.Errorf("failed to encode description findings: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO description_reviews (`+descriptionReviewColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (property_id) DO UPDATE SET
+			findings = EXCLUDED.findings, original = EXCLUDED.original, status = EXCLUDED.status,
+			reviewed_by = NULL, reviewed_at = NULL, created_at = EXCLUDED.created_at
+		WHERE description_reviews.status <> $8`,
+		review.PropertyID, findings, review.Original, review.Status, review.ReviewedBy, review.ReviewedAt,
+		review.CreatedAt, domain.DescriptionReviewOverridden)
+	if err != nil {
+		return fmt.Errorf("failed to save description review: %w", err)
+	}
+	return nil
+}
+
+// GetByPropertyID retrieves the review of a listing
+func (r *PostgreSQLDescriptionReviewRepository) GetByPropertyID(propertyID string) (*domain.DescriptionReview, error) {
+	query := `SELECT ` + descriptionReviewColumns + ` FROM description_reviews WHERE property_id = $1`
+
+	review, err := scanDescriptionReview(r.db.QueryRow(query, propertyID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("description review not found: %s", propertyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeletePending drops the pending review of a listing
+func (r *PostgreSQLDescriptionReviewRepository) DeletePending(propertyID string) error {
+	_, err := r.db.Exec(`DELETE FROM description_reviews WHERE property_id = $1 AND status = $2`,
+		propertyID, domain.DescriptionReviewPending)
+	if err != nil {
+		return fmt.Errorf("failed to delete description review: %w", err)
+	}
+	return nil
+}
+
+// List retrieves reviews matching a filter, oldest first, with the total count
+func (r *PostgreSQLDescriptionReviewRepository) List(filter domain.DescriptionReviewFilter) ([]domain.DescriptionReview, int, error) {
+	where := ""
+	args := []interface{}{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = " WHERE status = $1"
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM description_reviews`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count description reviews: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM description_reviews%s ORDER BY created_at LIMIT $%d OFFSET $%d`,
+		descriptionReviewColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list description reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []domain.DescriptionReview{}
+	for rows.Next() {
+		review, err := scanDescriptionReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, *review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return reviews, total, nil
+}
+
+// Decide stores the decision on a pending review
+func (r *PostgreSQLDescriptionReviewRepository) Decide(review *domain.DescriptionReview) error {
+	result, err := r.db.Exec(`
+		UPDATE description_reviews SET status = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE property_id = $1 AND status = $5`,
+		review.PropertyID, review.Status, review.ReviewedBy, review.ReviewedAt, domain.DescriptionReviewPending)
+	if err != nil {
+		return fmt.Errorf("failed to decide description review: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("description review not found or already decided: %s", review.PropertyID)
+	}
+
+	return nil
+}
+
+// scanDescriptionReview scans a row selected with descriptionReviewColumns
+func scanDescriptionReview(row interface{ Scan(...interface{}) error }) (*domain.DescriptionReview, error) {
+	var review domain.DescriptionReview
+	var reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+	var findings []byte
+
+	err := row.Scan(&review.PropertyID, &findings, &review.Original, &review.Status, &reviewedBy, &reviewedAt,
+		&review.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan description review: %w", err)
+	}
+
+	if err := json.Unmarshal(findings, &review.Findings); err != nil {
+		return nil, fmt.Errorf("failed to decode description findings: %w", err)
+	}
+	if reviewedBy.Valid {
+		review.ReviewedBy = &reviewedBy.String
+	}
+	if reviewedAt.Valid {
+		review.ReviewedAt = &reviewedAt.Time
+	}
+
+	return &review, nil
+}
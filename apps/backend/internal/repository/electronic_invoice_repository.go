@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// ElectronicInvoiceRepository defines data access for the SRI invoices issued to agencies
+type ElectronicInvoiceRepository interface {
+	// NextSequential reserves the next sequential number of an emission point
+	NextSequential(establishment, emissionPoint string) (int, error)
+
+	// Create stores a new invoice
+	Create(invoice *domain.ElectronicInvoice) error
+
+	// GetByID retrieves an invoice with its documents
+	GetByID(id string) (*domain.ElectronicInvoice, error)
+
+	// ListByAgency returns the invoices of an agency, newest first
+	ListByAgency(agencyID string) ([]domain.ElectronicInvoice, error)
+
+	// ListUnauthorized returns the pending and received invoices with fewer than
+	// maxAttempts attempts, oldest first
+	ListUnauthorized(maxAttempts, limit int) ([]domain.ElectronicInvoice, error)
+
+	// UpdateStatus saves the status, authorization and attempts of an invoice
+	UpdateStatus(invoice *domain.ElectronicInvoice) error
+}
+
+// PostgreSQLElectronicInvoiceRepository implements ElectronicInvoiceRepository using PostgreSQL
+type PostgreSQLElectronicInvoiceRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLElectronicInvoiceRepository creates a new PostgreSQL electronic invoice repository
+func NewPostgreSQLElectronicInvoiceRepository(db *sql.DB) *PostgreSQLElectronicInvoiceRepository {
+	return &PostgreSQLElectronicInvoiceRepository{db: db}
+}
+
+const electronicInvoiceColumns = `id, agency_id, source, source_ref, establishment, emission_point, sequential,
+	access_key, environment, issue_date, buyer, lines, subtotal, iva_rate, iva, total, status,
+	authorization_number, authorized_at, messages, xml, authorized_xml, attempts, created_at, updated_at`
+
+// scanElectronicInvoice scans an invoice selected with electronicInvoiceColumns
+func scanElectronicInvoice(row interface{ Scan(...interface{}) error }) (*domain.ElectronicInvoice, error) {
+	invoice := &domain.ElectronicInvoice{}
+	var buyer, lines, messages []byte
+	var authorizedAt sql.NullTime
+	err := row.Scan(&invoice.ID, &invoice.AgencyID, &invoice.Source, &invoice.SourceRef, &invoice.Establishment,
+		&invoice.EmissionPoint, &invoice.Sequential, &invoice.AccessKey, &invoice.Environment, &invoice.IssueDate,
+		&buyer, &lines, &invoice.Subtotal, &invoice.IVARate, &invoice.IVA, &invoice.Total, &invoice.Status,
+		&invoice.AuthorizationNumber, &authorizedAt, &messages, &invoice.XML, &invoice.AuthorizedXML,
+		&invoice.Attempts, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if authorizedAt.Valid {
+		invoice.AuthorizedAt = &authorizedAt.Time
+	}
+	if err := json.Unmarshal(buyer, &invoice.Buyer); err != nil {
+		return nil, fmt.Errorf("failed to decode invoice buyer: %w", err)
+	}
+	if err := json.Unmarshal(lines, &invoice.Lines); err != nil {
+		return nil, fmt.Errorf("failed to decode invoice lines: %w", err)
+	}
+	if err := json.Unmarshal(messages, &invoice.Messages); err != nil {
+		return nil, fmt.Errorf("failed to decode invoice messages: %w", err)
+	}
+	return invoice, nil
+}
+
+// NextSequential reserves the next sequential number of an emission point
+func (r *PostgreSQLElectronicInvoiceRepository) NextSequential(establishment, emissionPoint string) (int, error) {
+	query := `
+		INSERT INTO einvoice_sequences (establishment, emission_point, last_sequential)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (establishment, emission_point)
+		DO UPDATE SET last_sequential = einvoice_sequences.last_sequential + 1
+		RETURNING last_sequential`
+
+	var sequential int
+	if err := r.db.QueryRow(query, establishment, emissionPoint).Scan(&sequential); err != nil {
+		return 0, fmt.Errorf("failed to reserve invoice sequential: %w", err)
+	}
+	return sequential, nil
+}
+
+// Create stores a new invoice
+func (r *PostgreSQLElectronicInvoiceRepository) Create(invoice *domain.ElectronicInvoice) error {
+	buyer, err := json.Marshal(invoice.Buyer)
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice buyer: %w", err)
+	}
+	lines, err := json.Marshal(invoice.Lines)
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice lines: %w", err)
+	}
+	messages, err := json.Marshal(invoice.Messages)
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice messages: %w", err)
+	}
+
+	query := `
+		INSERT INTO electronic_invoices (id, agency_id, source, source_ref, establishment, emission_point, sequential,
+			access_key, environment, issue_date, buyer, lines, subtotal, iva_rate, iva, total, status,
+			messages, xml, attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`
+
+	_, err = r.db.Exec(query, invoice.ID, invoice.AgencyID, invoice.Source, invoice.SourceRef, invoice.Establishment,
+		invoice.EmissionPoint, invoice.Sequential, invoice.AccessKey, invoice.Environment, invoice.IssueDate,
+		buyer, lines, invoice.Subtotal, invoice.IVARate, invoice.IVA, invoice.Total, invoice.Status,
+		messages, invoice.XML, invoice.Attempts, invoice.CreatedAt, invoice.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create electronic invoice: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an invoice with its documents
+func (r *PostgreSQLElectronicInvoiceRepository) GetByID(id string) (*domain.ElectronicInvoice, error) {
+	query := `SELECT ` + electronicInvoiceColumns + ` FROM electronic_invoices WHERE id = $1`
+
+	invoice, err := scanElectronicInvoice(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("electronic invoice not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get electronic invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// ListByAgency returns the invoices of an agency, newest first
+func (r *PostgreSQLElectronicInvoiceRepository) ListByAgency(agencyID string) ([]domain.ElectronicInvoice, error) {
+	return r.list(`SELECT `+electronicInvoiceColumns+` FROM electronic_invoices
+		WHERE agency_id = $1 ORDER BY issue_date DESC, sequential DESC`, agencyID)
+}
+
+// ListUnauthorized returns the pending and received invoices with fewer than
+// maxAttempts attempts, oldest first
+func (r *PostgreSQLElectronicInvoiceRepository) ListUnauthorized(maxAttempts, limit int) ([]domain.ElectronicInvoice, error) {
+	return r.list(`SELECT `+electronicInvoiceColumns+` FROM electronic_invoices
+		WHERE status IN ('pending', 'received') AND attempts < $1
+		ORDER BY created_at LIMIT $2`, maxAttempts, limit)
+}
+
+// UpdateStatus saves the status, authorization and attempts of an invoice
+func (r *PostgreSQLElectronicInvoiceRepository) UpdateStatus(invoice *domain.ElectronicInvoice) error {
+	messages, err := json.Marshal(invoice.Messages)
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice messages: %w", err)
+	}
+
+	query := `
+		UPDATE electronic_invoices
+		SET status = $2, authorization_number = $3, authorized_at = $4, messages = $5,
+			authorized_xml = $6, attempts = $7, updated_at = $8
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, invoice.ID, invoice.Status, invoice.AuthorizationNumber, invoice.AuthorizedAt,
+		messages, invoice.AuthorizedXML, invoice.Attempts, invoice.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update electronic invoice: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("electronic invoice not found: %s", invoice.ID)
+	}
+	return nil
+}
+
+// list runs an invoice query
+func (r *PostgreSQLElectronicInvoiceRepository) list(query string, args ...interface{}) ([]domain.ElectronicInvoice, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list electronic invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := []domain.ElectronicInvoice{}
+	for rows.Next() {
+		invoice, err := scanElectronicInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan electronic invoice: %w", err)
+		}
+		invoices = append(invoices, *invoice)
+	}
+	return invoices, rows.Err()
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// EmailChangeRepository defines the interface for account email change operations
+type EmailChangeRepository interface {
+	// Create stores a new email change
+	Create(change *domain.EmailChange) error
+
+	// GetByConfirmTokenHash retrieves a change by the hash of its confirm token
+	GetByConfirmTokenHash(tokenHash string) (*domain.EmailChange, error)
+
+	// GetByCancelTokenHash retrieves a change by the hash of its cancel token
+	GetByCancelTokenHash(tokenHash string) (*domain.EmailChange, error)
+
+	// GetPendingByUser retrieves the open email change of a user
+	GetPendingByUser(userID string) (*domain.EmailChange, error)
+
+	// Update persists the status of a change
+	Update(change *domain.EmailChange) error
+}
+
+// PostgreSQLEmailChangeRepository implements EmailChangeRepository using PostgreSQL
+type PostgreSQLEmailChangeRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLEmailChangeRepository creates a new PostgreSQL email change repository
+func NewPostgreSQLEmailChangeRepository(db *sql.DB) *PostgreSQLEmailChangeRepository {
+	return &PostgreSQLEmailChangeRepository{db: db}
+}
+
+const emailChangeColumns = `id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, status,
+		expires_at, confirmed_at, created_at, updated_at`
+
+// Create stores a new email change
+func (r *PostgreSQLEmailChangeRepository) Create(change *domain.EmailChange) error {
+	if change == nil {
+		return fmt.Errorf("email change cannot be nil")
+	}
+
+	query := `
+		INSERT INTO email_changes (` + emailChangeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.Exec(query,
+		change.ID, change.UserID, change.OldEmail, change.NewEmail, change.ConfirmTokenHash,
+		change.CancelTokenHash, change.Status, change.ExpiresAt, change.ConfirmedAt,
+		change.CreatedAt, change.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create email change: %w", err)
+	}
+
+	return nil
+}
+
+// GetByConfirmTokenHash retrieves a change by the hash of its confirm token
+func (r *PostgreSQLEmailChangeRepository) GetByConfirmTokenHash(tokenHash string) (*domain.EmailChange, error) {
+	query := `SELECT ` + emailChangeColumns + ` FROM email_changes WHERE confirm_token_hash = $1`
+	return r.getOne(query, tokenHash)
+}
+
+// GetByCancelTokenHash retrieves a change by the hash of its cancel token
+func (r *PostgreSQLEmailChangeRepository) GetByCancelTokenHash(tokenHash string) (*domain.EmailChange, error) {
+	query := `SELECT ` + emailChangeColumns + ` FROM email_changes WHERE cancel_token_hash = $1`
+	return r.getOne(query, tokenHash)
+}
+
+// GetPendingByUser retrieves the open email change of a user
+func (r *PostgreSQLEmailChangeRepository) GetPendingByUser(userID string) (*domain.EmailChange, error) {
+	query := `SELECT ` + emailChangeColumns + ` FROM email_changes WHERE user_id = $1 AND status = 'pending'`
+	return r.getOne(query, userID)
+}
+
+// Update persists the status of a change
+func (r *PostgreSQLEmailChangeRepository) Update(change *domain.EmailChange) error {
+	if change == nil {
+		return fmt.Errorf("email change cannot be nil")
+	}
+
+	query := `UPDATE email_changes SET status = $2, confirmed_at = $3, updated_at = $4 WHERE id = $1`
+
+	result, err := r.db.Exec(query, change.ID, change.Status, change.ConfirmedAt, change.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update email change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("email change not found: %s", change.ID)
+	}
+
+	return nil
+}
+
+// getOne runs a query selecting a single email change
+func (r *PostgreSQLEmailChangeRepository) getOne(query string, args ...interface{}) (*domain.EmailChange, error) {
+	change := &domain.EmailChange{}
+	var confirmedAt sql.NullTime
+	err := r.db.QueryRow(query, args...).Scan(
+		&change.ID, &change.UserID, &change.OldEmail, &change.NewEmail, &change.ConfirmTokenHash,
+		&change.CancelTokenHash, &change.Status, &change.ExpiresAt, &confirmedAt,
+		&change.CreatedAt, &change.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("email change not found")
+		}
+		return nil, fmt.Errorf("failed to get email change: %w", err)
+	}
+
+	if confirmedAt.Valid {
+		change.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return change, nil
+}
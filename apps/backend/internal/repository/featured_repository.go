@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// FeaturedRepository defines the interface for timed featured promotions
+type FeaturedRepository interface {
+	// GetStatus returns whether a property is featured and until when
+	GetStatus(propertyID string) (*domain.FeaturedStatus, error)
+
+	// SetFeaturedUntil features a property until a time
+	SetFeaturedUntil(propertyID string, until time.Time) error
+
+	// ExpireFeatured unfeatures properties whose promotion ended and returns their IDs
+	ExpireFeatured(now time.Time) ([]string, error)
+}
+
+// PostgreSQLFeaturedRepository implements FeaturedRepository using PostgreSQL
+type PostgreSQLFeaturedRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLFeaturedRepository creates a new PostgreSQL featured promotion repository
+func NewPostgreSQLFeaturedRepository(db *sql.DB) *PostgreSQLFeaturedRepository {
+	return &PostgreSQLFeaturedRepository{db: db}
+}
+
+// GetStatus returns whether a property is featured and until when
+func (r *PostgreSQLFeaturedRepository) GetStatus(propertyID string) (*domain.FeaturedStatus, error) {
+	status := &domain.FeaturedStatus{PropertyID: propertyID}
+	var until sql.NullTime
+
+	err := r.db.QueryRow(`SELECT featured, featured_until FROM properties WHERE id = $1`, propertyID).Scan(&status.Featured, &until)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("property not found: %s", propertyID)
+		}
+		return nil, fmt.Errorf("failed to get featured status: %w", err)
+	}
+
+	if until.Valid {
+		status.FeaturedUntil = &until.Time
+	}
+	return status, nil
+}
+
+// SetFeaturedUntil features a property until a time
+func (r *PostgreSQLFeaturedRepository) SetFeaturedUntil(propertyID string, until time.Time) error {
+	query := `UPDATE properties SET featured = TRUE, featured_until = $2, updated_at = $3 WHERE id = $1`
+
+	result, err := r.db.Exec(query, propertyID, until, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to feature property: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("property not found: %s", propertyID)
+	}
+
+	return nil
+}
+
+// ExpireFeatured unfeatures properties whose promotion ended and returns their IDs
+func (r *PostgreSQLFeaturedRepository) ExpireFeatured(now time.Time) ([]string, error) {
+	query := `
+		UPDATE properties
+		SET featured = FALSE, featured_until = NULL, updated_at = $1
+		WHERE featured = TRUE AND featured_until IS NOT NULL AND featured_until <= $1
+		RETURNING id`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire featured properties: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan property ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return ids, nil
+}
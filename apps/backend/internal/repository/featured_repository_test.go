@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeaturedRepository_ExpireFeatured(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLFeaturedRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE properties SET featured = FALSE(.+)featured_until <= \\$1 RETURNING id").
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("prop-1").AddRow("prop-2"))
+
+	ids, err := repo.ExpireFeatured(now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prop-1", "prop-2"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeaturedRepository_GetStatus(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLFeaturedRepository(db)
+	until := time.Now().Add(24 * time.Hour)
+
+	mock.ExpectQuery("SELECT featured, featured_until FROM properties").
+		WithArgs("prop-1").
+		WillReturnRows(sqlmock.NewRows([]string{"featured", "featured_until"}).AddRow(true, until))
+	mock.ExpectQuery("SELECT featured, featured_until FROM properties").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"featured", "featured_until"}))
+
+	status, err := repo.GetStatus("prop-1")
+	require.NoError(t, err)
+	assert.True(t, status.Featured)
+	require.NotNil(t, status.FeaturedUntil)
+	assert.True(t, status.FeaturedUntil.Equal(until))
+
+	_, err = repo.GetStatus("missing")
+	assert.ErrorContains(t, err, "property not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -41,6 +41,7 @@ func TestPostgreSQLPropertyRepository_SearchProperties(t *testing.T) {
 					"tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				}).AddRow(
 					"123e4567-e89b-12d3-a456-426614174000", "casa-moderna", "Casa moderna", "Descripción", 285000.0,
 					"Guayas", "Samborondón", "", "", 0.0, 0.0, "", "house", "available", 4, 3.5, 320.0,
@@ -48,6 +49,7 @@ func TestPostgreSQLPropertyRepository_SearchProperties(t *testing.T) {
 					false, false, false, false, false, false, false, false,
 					"[]", false, 0, "", time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				)
 				mock.ExpectQuery(`SELECT .+ FROM properties`).
 					WithArgs("casa moderna", 10).
@@ -262,6 +264,36 @@ func TestPostgreSQLPropertyRepository_AdvancedSearch(t *testing.T) {
 	}
 }
 
+func TestPostgreSQLPropertyRepository_GetSearchFacets(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyRepository(db)
+
+	rows := sqlmock.NewRows([]string{"facet", "value", "count"}).
+		AddRow("province", "Pichincha", 3).
+		AddRow("province", "Guayas", 7).
+		AddRow("type", "house", 10).
+		AddRow("bedrooms", "5+", 1).
+		AddRow("bedrooms", "3", 9).
+		AddRow("price", "500000+", 2).
+		AddRow("price", "100000-200000", 8).
+		AddRow("amenity", "pool", 4)
+	mock.ExpectQuery(`WITH filtered AS MATERIALIZED`).
+		WithArgs("casa", "", "", "", 0.0, 999999999.0, 0, 100, 0.0, 100.0, 0.0, 999999.0, false).
+		WillReturnRows(rows)
+
+	facets, err := repo.GetSearchFacets(AdvancedSearchParams{Query: "casa"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FacetCount{{Value: "Guayas", Count: 7}, {Value: "Pichincha", Count: 3}}, facets.Provinces)
+	assert.Equal(t, []domain.FacetCount{{Value: "3", Count: 9}, {Value: "5+", Count: 1}}, facets.Bedrooms)
+	assert.Equal(t, "100000-200000", facets.PriceRanges[0].Value)
+	assert.Equal(t, []domain.FacetCount{{Value: "pool", Count: 4}}, facets.Amenities)
+	assert.Empty(t, facets.Cities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestAdvancedSearchParams_Validation(t *testing.T) {
 	tests := []struct {
 		name   string
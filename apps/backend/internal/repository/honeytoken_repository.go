@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// HoneytokenRepository defines data access for canary listings and their hits
+type HoneytokenRepository interface {
+	// Create stores a new honeytoken
+	Create(token *domain.Honeytoken) error
+
+	// List returns every honeytoken, retired ones included, newest first
+	List() ([]domain.Honeytoken, error)
+
+	// Retire saves a honeytoken as retired
+	Retire(token *domain.Honeytoken) error
+
+	// RecordHit stores a hit on a honeytoken
+	RecordHit(hit *domain.HoneytokenHit) error
+
+	// HitsBetween returns the hits made between from and to, oldest first
+	HitsBetween(from, to time.Time) ([]domain.HoneytokenHit, error)
+}
+
+// PostgreSQLHoneytokenRepository implements HoneytokenRepository using PostgreSQL
+type PostgreSQLHoneytokenRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLHoneytokenRepository creates a new PostgreSQL honeytoken repository
+func NewPostgreSQLHoneytokenRepository(db *sql.DB) *PostgreSQLHoneytokenRepository {
+	return &PostgreSQLHoneytokenRepository{db: db}
+}
+
+// Create stores a new honeytoken
+func (r *PostgreSQLHoneytokenRepository) Create(token *domain.Honeytoken) error {
+	listing, err := json.Marshal(token.Listing)
+	if err != nil {
+		return fmt.Errorf("failed to encode honeytoken listing: %w", err)
+	}
+
+	query := `
+		INSERT INTO honeytokens (id, source, label, listing, property_id, slug, api_key_id, active,
+			created_by, created_at, retired_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = r.db.Exec(query, token.ID, token.Source, token.Label, listing, token.Listing.ID, token.Listing.Slug,
+		token.APIKeyID, token.Active, token.CreatedBy, token.CreatedAt, token.RetiredAt)
+	if err != nil {
+		return fmt.Errorf("failed to create honeytoken: %w", err)
+	}
+	return nil
+}
+
+// List returns every honeytoken, retired ones included, newest first
+func (r *PostgreSQLHoneytokenRepository) List() ([]domain.Honeytoken, error) {
+	query := `
+		SELECT id, source, label, listing, api_key_id, active, created_by, created_at, retired_at
+		FROM honeytokens
+		ORDER BY created_at DESC, id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list honeytokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []domain.Honeytoken{}
+	for rows.Next() {
+		var token domain.Honeytoken
+		var listing []byte
+		var apiKeyID sql.NullString
+		var retiredAt sql.NullTime
+		if err := rows.Scan(&token.ID, &token.Source, &token.Label, &listing, &apiKeyID, &token.Active,
+			&token.CreatedBy, &token.CreatedAt, &retiredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan honeytoken: %w", err)
+		}
+		if err := json.Unmarshal(listing, &token.Listing); err != nil {
+			return nil, fmt.Errorf("failed to decode honeytoken listing: %w", err)
+		}
+		if apiKeyID.Valid {
+			token.APIKeyID = &apiKeyID.String
+		}
+		if retiredAt.Valid {
+			token.RetiredAt = &retiredAt.Time
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// Retire saves a honeytoken as retired
+func (r *PostgreSQLHoneytokenRepository) Retire(token *domain.Honeytoken) error {
+	result, err := r.db.Exec(`UPDATE honeytokens SET active = $2, retired_at = $3 WHERE id = $1`,
+		token.ID, token.Active, token.RetiredAt)
+	if err != nil {
+		return fmt.Errorf("failed to retire honeytoken: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("honeytoken not found: %s", token.ID)
+	}
+	return nil
+}
+
+// RecordHit stores a hit on a honeytoken
+func (r *PostgreSQLHoneytokenRepository) RecordHit(hit *domain.HoneytokenHit) error {
+	query := `
+		INSERT INTO honeytoken_hits (id, honeytoken_id, path, client_ip, user_agent, referer, fingerprint,
+			api_key_id, user_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.Exec(query, hit.ID, hit.HoneytokenID, hit.Path, hit.ClientIP, hit.UserAgent, hit.Referer,
+		hit.Fingerprint, hit.APIKeyID, hit.UserID, hit.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record honeytoken hit: %w", err)
+	}
+	return nil
+}
+
+// HitsBetween returns the hits made between from and to, oldest first
+func (r *PostgreSQLHoneytokenRepository) HitsBetween(from, to time.Time) ([]domain.HoneytokenHit, error) {
+	query := `
+		SELECT id, honeytoken_id, path, client_ip, user_agent, referer, fingerprint, api_key_id, user_id, created_at
+		FROM honeytoken_hits
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY created_at, id`
+
+	rows, err := r.db.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list honeytoken hits: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []domain.HoneytokenHit{}
+	for rows.Next() {
+		var hit domain.HoneytokenHit
+		if err := rows.Scan(&hit.ID, &hit.HoneytokenID, &hit.Path, &hit.ClientIP, &hit.UserAgent, &hit.Referer,
+			&hit.Fingerprint, &hit.APIKeyID, &hit.UserID, &hit.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan honeytoken hit: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// ImageAnalysisRepository defines data access for the attribute analyses of listing photos
+type ImageAnalysisRepository interface {
+	// Enqueue queues the analysis of a photo, restarting it when the photo was replaced
+	Enqueue(analysis *domain.ImageAnalysis) error
+
+	// Save stores the outcome of an analysis attempt
+	Save(analysis *domain.ImageAnalysis) error
+
+	// ListPending retrieves pending analyses, oldest first
+	ListPending(limit int) ([]domain.ImageAnalysis, error)
+
+	// ListByProperty retrieves the analyses of a listing's photos
+	ListByProperty(propertyID string) ([]domain.ImageAnalysis, error)
+}
+
+// PostgreSQLImageAnalysisRepository implements ImageAnalysisRepository using PostgreSQL
+type PostgreSQLImageAnalysisRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLImageAnalysisRepository creates a new PostgreSQL image analysis repository
+func NewPostgreSQLImageAnalysisRepository(db *sql.DB) *PostgreSQLImageAnalysisRepository {
+	return &PostgreSQLImageAnalysisRepository{db: db}
+}
+
+const imageAnalysisColumns = `image_id, property_id, status, labels, provider, error, attempts, analyzed_at, created_at`
+
+// Enqueue queues the analysis of a photo
+func (r *PostgreSQLImageAnalysisRepository) Enqueue(analysis *domain.ImageAnalysis) error {
+	_, err := r.db.Exec(`
+		INSERT INTO image_analyses (`+imageAnalysisColumns+`)
+		VALUES ($1, $2, $3, '[]', '', '', 0, NULL, $4)
+		ON CONFLICT (image_id) DO UPDATE SET
+			status = EXCLUDED.status, labels = EXCLUDED.labels, provider = EXCLUDED.provider,
+			error = EXCLUDED.error, attempts = 0, analyzed_at = NULL, created_at = EXCLUDED.created_at`,
+		analysis.ImageID, analysis.PropertyID, domain.ImageAnalysisPending, analysis.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to queue image analysis: %w", err)
+	}
+	return nil
+}
+
+// Save stores the outcome of an analysis attempt
+func (r *PostgreSQLImageAnalysisRepository) Save(analysis *domain.ImageAnalysis) error {
+	labels, err := json.Marshal(analysis.Labels)
+	if err != nil {
+		return fmt.Errorf("failed to encode image labels: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE image_analyses
+		SET status = $2, labels = $3, provider = $4, error = $5, attempts = $6, analyzed_at = $7
+		WHERE image_id = $1`,
+		analysis.ImageID, analysis.Status, labels, analysis.Provider, analysis.Error, analysis.Attempts,
+		analysis.AnalyzedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save image analysis: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("image analysis not found: %s", analysis.ImageID)
+	}
+	return nil
+}
+
+// ListPending retrieves pending analyses, oldest first
+func (r *PostgreSQLImageAnalysisRepository) ListPending(limit int) ([]domain.ImageAnalysis, error) {
+	return r.list(`SELECT `+imageAnalysisColumns+` FROM image_analyses
+		WHERE status = $1 ORDER BY created_at LIMIT $2`, domain.ImageAnalysisPending, limit)
+}
+
+// ListByProperty retrieves the analyses of a listing's photos
+func (r *PostgreSQLImageAnalysisRepository) ListByProperty(propertyID string) ([]domain.ImageAnalysis, error) {
+	return r.list(`SELECT `+imageAnalysisColumns+` FROM image_analyses
+		WHERE property_id = $1 ORDER BY created_at`, propertyID)
+}
+
+// list runs a query selecting imageAnalysisColumns
+func (r *PostgreSQLImageAnalysisRepository) list(query string, args ...interface{}) ([]domain.ImageAnalysis, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list image analyses: %w", err)
+	}
+	defer rows.Close()
+
+	analyses := []domain.ImageAnalysis{}
+	for rows.Next() {
+		var analysis domain.ImageAnalysis
+		var labels []byte
+		var analyzedAt sql.NullTime
+		err := rows.Scan(&analysis.ImageID, &analysis.PropertyID, &analysis.Status, &labels, &analysis.Provider,
+			&analysis.Error, &analysis.Attempts, &analyzedAt, &analysis.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image analysis: %w", err)
+		}
+
+		analysis.Labels = []domain.ImageLabel{}
+		if len(labels) > 0 {
+			if err := json.Unmarshal(labels, &analysis.Labels); err != nil {
+				return nil, fmt.Errorf("failed to decode image labels: %w", err)
+			}
+		}
+		if analyzedAt.Valid {
+			analysis.AnalyzedAt = &analyzedAt.Time
+		}
+		analyses = append(analyses, analysis)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return analyses, nil
+}
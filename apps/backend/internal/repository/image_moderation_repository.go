@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// ImageModerationRepository defines the interface for image moderation records
+type ImageModerationRepository interface {
+	// Save creates or replaces the moderation record of an image
+	Save(moderation *domain.ImageModeration) error
+
+	// GetByImageID retrieves the moderation record of an image
+	GetByImageID(imageID string) (*domain.ImageModeration, error)
+
+	// ListByStatus retrieves moderation records with the given statuses, most severe first
+	ListByStatus(statuses []string, limit, offset int) ([]domain.ImageModeration, int, error)
+
+	// IsFileHidden reports whether a stored file, original or rendition, belongs to an
+	// image quarantined by moderation
+	IsFileHidden(fileName string) (bool, error)
+}
+
+// PostgreSQLImageModerationRepository implements ImageModerationRepository using PostgreSQL
+type PostgreSQLImageModerationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLImageModerationRepository creates a new PostgreSQL image moderation repository
+func NewPostgreSQLImageModerationRepository(db *sql.DB) *PostgreSQLImageModerationRepository {
+	return &PostgreSQLImageModerationRepository{db: db}
+}
+
+const imageModerationColumns = `image_id, property_id, status, score, labels, provider, error,
+		reviewed_by, review_note, reviewed_at, created_at, updated_at`
+
+// Save creates or replaces the moderation record of an image
+func (r *PostgreSQLImageModerationRepository) Save(moderation *domain.ImageModeration) error {
+	if moderation == nil {
+		return fmt.Errorf("moderation cannot be nil")
+	}
+	if !domain.IsValidModerationStatus(moderation.Status) {
+		return fmt.Errorf("invalid moderation status: %s", moderation.Status)
+	}
+
+	labels, err := json.Marshal(moderation.Labels)
+	if err != nil {
+		return fmt.Errorf("failed to encode moderation labels: %w", err)
+	}
+
+	query := `
+		INSERT INTO image_moderation (` + imageModerationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (image_id) DO UPDATE SET
+			status = EXCLUDED.status, score = EXCLUDED.score, labels = EXCLUDED.labels,
+			provider = EXCLUDED.provider, error = EXCLUDED.error, reviewed_by = EXCLUDED.reviewed_by,
+			review_note = EXCLUDED.review_note, reviewed_at = EXCLUDED.reviewed_at,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.Exec(query,
+		moderation.ImageID, moderation.PropertyID, moderation.Status, moderation.Score, labels,
+		moderation.Provider, moderation.Error, moderation.ReviewedBy, moderation.ReviewNote,
+		moderation.ReviewedAt, moderation.CreatedAt, moderation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save image moderation: %w", err)
+	}
+
+	return nil
+}
+
+// GetByImageID retrieves the moderation record of an image
+func (r *PostgreSQLImageModerationRepository) GetByImageID(imageID string) (*domain.ImageModeration, error) {
+	query := `SELECT ` + imageModerationColumns + ` FROM image_moderation WHERE image_id = $1`
+
+	moderation, err := scanImageModeration(r.db.QueryRow(query, imageID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("moderation record not found: %s", imageID)
+		}
+		return nil, fmt.Errorf("failed to get image moderation: %w", err)
+	}
+
+	return moderation, nil
+}
+
+// ListByStatus retrieves moderation records with the given statuses, most severe first,
+// along with the total number of matching records
+func (r *PostgreSQLImageModerationRepository) ListByStatus(statuses []string, limit, offset int) ([]domain.ImageModeration, int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM image_moderation WHERE status = ANY($1)`, pq.Array(statuses)).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation queue: %w", err)
+	}
+
+	query := `
+		SELECT ` + imageModerationColumns + `
+		FROM image_moderation
+		WHERE status = ANY($1)
+		ORDER BY score DESC, created_at ASC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, pq.Array(statuses), limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query moderation queue: %w", err)
+	}
+	defer rows.Close()
+
+	records := []domain.ImageModeration{}
+	for rows.Next() {
+		moderation, err := scanImageModeration(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan image moderation: %w", err)
+		}
+		records = append(records, *moderation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return records, total, nil
+}
+
+// scanImageModeration scans a row selected with imageModerationColumns
+func scanImageModeration(row interface{ Scan(...interface{}) error }) (*domain.ImageModeration, error) {
+	moderation := &domain.ImageModeration{}
+	var labels []byte
+	err := row.Scan(
+		&moderation.ImageID, &moderation.PropertyID, &moderation.Status, &moderation.Score,
+		&labels, &moderation.Provider, &moderation.Error, &moderation.ReviewedBy,
+		&moderation.ReviewNote, &moderation.ReviewedAt, &moderation.CreatedAt, &moderation.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	moderation.Labels = []domain.ModerationLabel{}
+	if len(labels) > 0 {
+		if err := json.Unmarshal(labels, &moderation.Labels); err != nil {
+			return nil, fmt.Errorf("failed to decode moderation labels: %w", err)
+		}
+	}
+
+	return moderation, nil
+}
+
+// IsFileHidden reports whether a stored file belongs to a quarantined image. Originals
+// carry the image's file name; thumbnails and variants its stem followed by "_".
+func (r *PostgreSQLImageModerationRepository) IsFileHidden(fileName string) (bool, error) {
+	var hidden bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM image_moderation m JOIN images i ON i.id = m.image_id
+			WHERE m.status = $2 AND (i.file_name = $1
+				OR $1 LIKE regexp_replace(i.file_name, '\.[^.]*$', '') || '\_%')
+		)`, fileName, domain.ModerationStatusQuarantined).Scan(&hidden)
+	if err != nil {
+		return false, fmt.Errorf("failed to check image moderation: %w", err)
+	}
+	return hidden, nil
+}
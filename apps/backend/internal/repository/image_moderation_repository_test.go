@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestImageModerationRepository_IsFileHidden(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLImageModerationRepository(db)
+
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM image_moderation m JOIN images i ON i.id = m.image_id\s+WHERE m.status = \$2 AND \(i.file_name = \$1`).
+		WithArgs("prop-1_aaaa1111_thumb_150.jpg", domain.ModerationStatusQuarantined).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	hidden, err := repo.IsFileHidden("prop-1_aaaa1111_thumb_150.jpg")
+	require.NoError(t, err)
+	assert.True(t, hidden)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
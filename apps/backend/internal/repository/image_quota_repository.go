@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// ImageQuotaRepository defines the interface for agency image quota data
+type ImageQuotaRepository interface {
+	// GetAgencyPlan returns the image quota plan of an agency
+	GetAgencyPlan(agencyID string) (string, error)
+
+	// GetAgencyUsage returns the images stored for the properties of an agency
+	GetAgencyUsage(agencyID string) (*domain.ImageStorageUsage, error)
+}
+
+// PostgreSQLImageQuotaRepository implements ImageQuotaRepository using PostgreSQL
+type PostgreSQLImageQuotaRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLImageQuotaRepository creates a new PostgreSQL image quota repository
+func NewPostgreSQLImageQuotaRepository(db *sql.DB) *PostgreSQLImageQuotaRepository {
+	return &PostgreSQLImageQuotaRepository{db: db}
+}
+
+// GetAgencyPlan returns the image quota plan of an agency
+func (r *PostgreSQLImageQuotaRepository) GetAgencyPlan(agencyID string) (string, error) {
+	var plan string
+	err := r.db.QueryRow(`SELECT image_quota_plan FROM agencies WHERE id = $1`, agencyID).Scan(&plan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("agency not found: %s", agencyID)
+		}
+		return "", fmt.Errorf("failed to get agency quota plan: %w", err)
+	}
+	return plan, nil
+}
+
+// GetAgencyUsage returns the images stored for the properties of an agency
+func (r *PostgreSQLImageQuotaRepository) GetAgencyUsage(agencyID string) (*domain.ImageStorageUsage, error) {
+	query := `
+		SELECT COUNT(i.id), COALESCE(SUM(i.size), 0)
+		FROM images i
+		JOIN properties p ON p.id = i.property_id
+		WHERE p.agency_id = $1`
+
+	usage := &domain.ImageStorageUsage{}
+	if err := r.db.QueryRow(query, agencyID).Scan(&usage.ImageCount, &usage.UsedBytes); err != nil {
+		return nil, fmt.Errorf("failed to get agency image usage: %w", err)
+	}
+	return usage, nil
+}
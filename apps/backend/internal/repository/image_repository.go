@@ -16,6 +16,10 @@ type ImageRepository interface {
 	// GetByID retrieves image by ID
 	GetByID(id string) (*domain.ImageInfo, error)
 	
+	// GetVisibleByID retrieves an image by ID for public reads; quarantined images are
+	// not found
+	GetVisibleByID(id string) (*domain.ImageInfo, error)
+	
 	// GetByPropertyID retrieves all images for a property
 	GetByPropertyID(propertyID string) ([]domain.ImageInfo, error)
 	
@@ -42,8 +46,17 @@ type ImageRepository interface {
 	
 	// GetImageStats returns image statistics
 	GetImageStats() (map[string]interface{}, error)
+	
+	// ListImageFiles returns the ID, property, file name, URL and creation time of every
+	// image, including moderated ones, for storage reconciliation
+	ListImageFiles() ([]domain.ImageInfo, error)
 }
 
+// visibleImageCondition excludes images quarantined by content moderation from listings
+// and every other public read
+const visibleImageCondition = `NOT EXISTS (
+			SELECT 1 FROM image_moderation m WHERE m.image_id = images.id AND m.status = 'quarantined')`
+
 // PostgreSQLImageRepository implements ImageRepository using PostgreSQL
 type PostgreSQLImageRepository struct {
 	db *sql.DB
@@ -67,15 +80,17 @@ func (r *PostgreSQLImageRepository) Create(image *domain.ImageInfo) error {
 	query := `
 		INSERT INTO images (
 			id, property_id, file_name, original_url, alt_text, sort_order,
-			size, width, height, format, quality, is_optimized, created_at, updated_at
+			size, width, height, format, quality, is_optimized,
+			captured_at, camera_make, camera_model, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 		)`
 	
 	_, err := r.db.Exec(query,
 		image.ID, image.PropertyID, image.FileName, image.OriginalURL, image.AltText,
 		image.SortOrder, image.Size, image.Width, image.Height, image.Format,
-		image.Quality, image.IsOptimized, image.CreatedAt, image.UpdatedAt)
+		image.Quality, image.IsOptimized, image.CapturedAt, image.CameraMake,
+		image.CameraModel, image.CreatedAt, image.UpdatedAt)
 	
 	if err != nil {
 		return fmt.Errorf("failed to create image: %w", err)
@@ -86,15 +101,26 @@ func (r *PostgreSQLImageRepository) Create(image *domain.ImageInfo) error {
 
 // GetByID retrieves image by ID
 func (r *PostgreSQLImageRepository) GetByID(id string) (*domain.ImageInfo, error) {
+	return r.getByID(id, "")
+}
+
+// GetVisibleByID retrieves an image by ID unless moderation quarantined it
+func (r *PostgreSQLImageRepository) GetVisibleByID(id string) (*domain.ImageInfo, error) {
+	return r.getByID(id, " AND "+visibleImageCondition)
+}
+
+// getByID retrieves an image by ID matching an extra condition
+func (r *PostgreSQLImageRepository) getByID(id, condition string) (*domain.ImageInfo, error) {
 	if id == "" {
 		return nil, fmt.Errorf("image ID cannot be empty")
 	}
 	
 	query := `
 		SELECT id, property_id, file_name, original_url, alt_text, sort_order,
-			   size, width, height, format, quality, is_optimized, created_at, updated_at
+			   size, width, height, format, quality, is_optimized,
+			   captured_at, camera_make, camera_model, created_at, updated_at
 		FROM images
-		WHERE id = $1`
+		WHERE id = $1` + condition
 	
 	image := &domain.ImageInfo{}
 	
@@ -102,6 +128,7 @@ func (r *PostgreSQLImageRepository) GetByID(id string) (*domain.ImageInfo, error
 		&image.ID, &image.PropertyID, &image.FileName, &image.OriginalURL,
 		&image.AltText, &image.SortOrder, &image.Size, &image.Width,
 		&image.Height, &image.Format, &image.Quality, &image.IsOptimized,
+		&image.CapturedAt, &image.CameraMake, &image.CameraModel,
 		&image.CreatedAt, &image.UpdatedAt)
 	
 	if err != nil {
@@ -122,9 +149,10 @@ func (r *PostgreSQLImageRepository) GetByPropertyID(propertyID string) ([]domain
 	
 	query := `
 		SELECT id, property_id, file_name, original_url, alt_text, sort_order,
-			   size, width, height, format, quality, is_optimized, created_at, updated_at
+			   size, width, height, format, quality, is_optimized,
+			   captured_at, camera_make, camera_model, created_at, updated_at
 		FROM images
-		WHERE property_id = $1
+		WHERE property_id = $1 AND `+visibleImageCondition+`
 		ORDER BY sort_order ASC, created_at ASC`
 	
 	rows, err := r.db.Query(query, propertyID)
@@ -141,6 +169,7 @@ func (r *PostgreSQLImageRepository) GetByPropertyID(propertyID string) ([]domain
 			&image.ID, &image.PropertyID, &image.FileName, &image.OriginalURL,
 			&image.AltText, &image.SortOrder, &image.Size, &image.Width,
 			&image.Height, &image.Format, &image.Quality, &image.IsOptimized,
+			&image.CapturedAt, &image.CameraMake, &image.CameraModel,
 			&image.CreatedAt, &image.UpdatedAt)
 		
 		if err != nil {
@@ -157,6 +186,32 @@ func (r *PostgreSQLImageRepository) GetByPropertyID(propertyID string) ([]domain
 	return images, nil
 }
 
+// ListImageFiles returns the file references of every image
+func (r *PostgreSQLImageRepository) ListImageFiles() ([]domain.ImageInfo, error) {
+	query := `SELECT id, property_id, file_name, original_url, created_at FROM images ORDER BY created_at ASC`
+	
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query image files: %w", err)
+	}
+	defer rows.Close()
+	
+	images := []domain.ImageInfo{}
+	for rows.Next() {
+		var image domain.ImageInfo
+		if err := rows.Scan(&image.ID, &image.PropertyID, &image.FileName, &image.OriginalURL, &image.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan image file: %w", err)
+		}
+		images = append(images, image)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	
+	return images, nil
+}
+
 // Update updates image metadata
 func (r *PostgreSQLImageRepository) Update(image *domain.ImageInfo) error {
 	if image == nil {
@@ -272,9 +327,10 @@ func (r *PostgreSQLImageRepository) GetMainImage(propertyID string) (*domain.Ima
 	
 	query := `
 		SELECT id, property_id, file_name, original_url, alt_text, sort_order,
-			   size, width, height, format, quality, is_optimized, created_at, updated_at
+			   size, width, height, format, quality, is_optimized,
+			   captured_at, camera_make, camera_model, created_at, updated_at
 		FROM images
-		WHERE property_id = $1
+		WHERE property_id = $1 AND `+visibleImageCondition+`
 		ORDER BY sort_order ASC, created_at ASC
 		LIMIT 1`
 	
@@ -284,6 +340,7 @@ func (r *PostgreSQLImageRepository) GetMainImage(propertyID string) (*domain.Ima
 		&image.ID, &image.PropertyID, &image.FileName, &image.OriginalURL,
 		&image.AltText, &image.SortOrder, &image.Size, &image.Width,
 		&image.Height, &image.Format, &image.Quality, &image.IsOptimized,
+		&image.CapturedAt, &image.CameraMake, &image.CameraModel,
 		&image.CreatedAt, &image.UpdatedAt)
 	
 	if err != nil {
@@ -373,7 +430,8 @@ func (r *PostgreSQLImageRepository) GetImagesByFormat(format string) ([]domain.I
 	
 	query := `
 		SELECT id, property_id, file_name, original_url, alt_text, sort_order,
-			   size, width, height, format, quality, is_optimized, created_at, updated_at
+			   size, width, height, format, quality, is_optimized,
+			   captured_at, camera_make, camera_model, created_at, updated_at
 		FROM images
 		WHERE format = $1
 		ORDER BY created_at DESC`
@@ -392,6 +450,7 @@ func (r *PostgreSQLImageRepository) GetImagesByFormat(format string) ([]domain.I
 			&image.ID, &image.PropertyID, &image.FileName, &image.OriginalURL,
 			&image.AltText, &image.SortOrder, &image.Size, &image.Width,
 			&image.Height, &image.Format, &image.Quality, &image.IsOptimized,
+			&image.CapturedAt, &image.CameraMake, &image.CameraModel,
 			&image.CreatedAt, &image.UpdatedAt)
 		
 		if err != nil {
@@ -503,6 +562,9 @@ func (r *PostgreSQLImageRepository) CreateImageTable() error {
 			format VARCHAR(10) DEFAULT '',
 			quality INTEGER DEFAULT 85,
 			is_optimized BOOLEAN DEFAULT false,
+			captured_at TIMESTAMP,
+			camera_make VARCHAR(100) DEFAULT '',
+			camera_model VARCHAR(100) DEFAULT '',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (property_id) REFERENCES properties(id) ON DELETE CASCADE
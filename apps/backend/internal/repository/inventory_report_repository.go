@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// InventoryReportRepository defines the interface for agency inventory snapshots
+type InventoryReportRepository interface {
+	// ComputeSnapshots aggregates the inventory of every agency over a period
+	ComputeSnapshots(period string, start, end, computedAt time.Time) ([]domain.InventorySnapshot, error)
+
+	// UpsertSnapshots stores snapshots, replacing those of the same agency and period
+	UpsertSnapshots(snapshots []domain.InventorySnapshot) error
+
+	// ListSnapshots lists the latest snapshots of an agency for a period, newest first
+	ListSnapshots(agencyID, period string, limit int) ([]domain.InventorySnapshot, error)
+}
+
+// PostgreSQLInventoryReportRepository implements InventoryReportRepository using PostgreSQL
+type PostgreSQLInventoryReportRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLInventoryReportRepository creates a new PostgreSQL inventory report repository
+func NewPostgreSQLInventoryReportRepository(db *sql.DB) *PostgreSQLInventoryReportRepository {
+	return &PostgreSQLInventoryReportRepository{db: db}
+}
+
+const inventorySnapshotColumns = `agency_id, period, period_start, period_end, active_listings, new_listings,
+	sold_listings, rented_listings, avg_days_on_market, avg_discount_percent, computed_at`
+
+// ComputeSnapshots aggregates the inventory of every agency over a period from its
+// properties and the deals closed in the period
+func (r *PostgreSQLInventoryReportRepository) ComputeSnapshots(period string, start, end, computedAt time.Time) ([]domain.InventorySnapshot, error) {
+	query := `
+		SELECT a.id,
+			(SELECT COUNT(*) FROM properties p WHERE p.agency_id = a.id
+				AND p.status IN ('available', 'reserved') AND p.created_at < $2),
+			(SELECT COUNT(*) FROM properties p WHERE p.agency_id = a.id
+				AND p.created_at >= $1 AND p.created_at < $2),
+			COUNT(d.id) FILTER (WHERE d.type = 'sale'),
+			COUNT(d.id) FILTER (WHERE d.type = 'rent'),
+			COALESCE(AVG(COALESCE(p.days_on_market, GREATEST(EXTRACT(EPOCH FROM (d.closing_date - p.created_at)) / 86400, 0))), 0),
+			COALESCE(AVG((p.price - d.final_price) / NULLIF(p.price, 0) * 100) FILTER (WHERE d.type = 'sale'), 0)
+		FROM agencies a
+		LEFT JOIN deals d ON d.agency_id = a.id AND d.closing_date >= $1 AND d.closing_date < $2
+		LEFT JOIN properties p ON p.id = d.property_id
+		GROUP BY a.id`
+
+	rows, err := r.db.Query(query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute inventory snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []domain.InventorySnapshot{}
+	for rows.Next() {
+		snapshot := domain.InventorySnapshot{Period: period, PeriodStart: start, PeriodEnd: end, ComputedAt: computedAt}
+		if err := rows.Scan(&snapshot.AgencyID, &snapshot.ActiveListings, &snapshot.NewListings,
+			&snapshot.SoldListings, &snapshot.RentedListings, &snapshot.AvgDaysOnMarket, &snapshot.AvgDiscountPercent); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate inventory snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// UpsertSnapshots stores snapshots in one transaction
+func (r *PostgreSQLInventoryReportRepository) UpsertSnapshots(snapshots []domain.InventorySnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO inventory_snapshots (` + inventorySnapshotColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (agency_id, period, period_start) DO UPDATE SET
+			period_end = EXCLUDED.period_end, active_listings = EXCLUDED.active_listings,
+			new_listings = EXCLUDED.new_listings, sold_listings = EXCLUDED.sold_listings,
+			rented_listings = EXCLUDED.rented_listings, avg_days_on_market = EXCLUDED.avg_days_on_market,
+			avg_discount_percent = EXCLUDED.avg_discount_percent, computed_at = EXCLUDED.computed_at`
+
+	for _, s := range snapshots {
+		_, err := tx.Exec(query, s.AgencyID, s.Period, s.PeriodStart, s.PeriodEnd, s.ActiveListings, s.NewListings,
+			s.SoldListings, s.RentedListings, s.AvgDaysOnMarket, s.AvgDiscountPercent, s.ComputedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save inventory snapshot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots lists the latest snapshots of an agency for a period, newest first
+func (r *PostgreSQLInventoryReportRepository) ListSnapshots(agencyID, period string, limit int) ([]domain.InventorySnapshot, error) {
+	query := `SELECT ` + inventorySnapshotColumns + ` FROM inventory_snapshots
+		WHERE agency_id = $1 AND period = $2 ORDER BY period_start DESC LIMIT $3`
+
+	rows, err := r.db.Query(query, agencyID, period, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []domain.InventorySnapshot{}
+	for rows.Next() {
+		var s domain.InventorySnapshot
+		if err := rows.Scan(&s.AgencyID, &s.Period, &s.PeriodStart, &s.PeriodEnd, &s.ActiveListings, &s.NewListings,
+			&s.SoldListings, &s.RentedListings, &s.AvgDaysOnMarket, &s.AvgDiscountPercent, &s.ComputedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate inventory snapshots: %w", err)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// jobLockClass namespaces the advisory locks of scheduled jobs from other advisory locks
+const jobLockClass = 7301
+
+// JobRepository defines the interface for scheduled job locks and run history
+type JobRepository interface {
+	// TryLock takes the advisory lock of a job without waiting. ok is false when
+	// another instance holds it; otherwise unlock releases it.
+	TryLock(name string) (unlock func(), ok bool, err error)
+
+	// CreateRun records a started run
+	CreateRun(run *domain.JobRun) error
+
+	// FinishRun records the outcome of a run
+	FinishRun(run *domain.JobRun) error
+
+	// ListRuns retrieves the latest runs of a job, or of every job when name is empty
+	ListRuns(name string, limit int) ([]domain.JobRun, error)
+}
+
+// PostgreSQLJobRepository implements JobRepository using PostgreSQL
+type PostgreSQLJobRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLJobRepository creates a new PostgreSQL job repository
+func NewPostgreSQLJobRepository(db *sql.DB) *PostgreSQLJobRepository {
+	return &PostgreSQLJobRepository{db: db}
+}
+
+const jobRunColumns = `id, job_name, trigger, triggered_by, instance, status, summary, error,
+		started_at, finished_at, duration_ms`
+
+// TryLock takes a session-level advisory lock on a dedicated connection, which is
+// held until unlock or until the connection dies with the instance
+func (r *PostgreSQLJobRepository) TryLock(name string) (func(), bool, error) {
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`, jobLockClass, name).Scan(&locked)
+	if err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to take job lock: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// Closing the connection also releases the lock if the unlock fails
+		conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1, hashtext($2))`, jobLockClass, name)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+// CreateRun records a started run
+func (r *PostgreSQLJobRepository) CreateRun(run *domain.JobRun) error {
+	query := `INSERT INTO job_runs (` + jobRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	triggeredBy := sql.NullString{String: run.TriggeredBy, Valid: run.TriggeredBy != ""}
+	_, err := r.db.Exec(query,
+		run.ID, run.JobName, run.Trigger, triggeredBy, run.Instance, run.Status,
+		run.Summary, run.Error, run.StartedAt, run.FinishedAt, run.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+
+	return nil
+}
+
+// FinishRun records the outcome of a run
+func (r *PostgreSQLJobRepository) FinishRun(run *domain.JobRun) error {
+	query := `
+		UPDATE job_runs SET status = $2, summary = $3, error = $4, finished_at = $5, duration_ms = $6
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, run.ID, run.Status, run.Summary, run.Error, run.FinishedAt, run.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to update job run: %w", err)
+	}
+
+	return nil
+}
+
+// ListRuns retrieves the latest runs of a job, or of every job when name is empty
+func (r *PostgreSQLJobRepository) ListRuns(name string, limit int) ([]domain.JobRun, error) {
+	query := `SELECT ` + jobRunColumns + ` FROM job_runs
+		WHERE ($1 = '' OR job_name = $1)
+		ORDER BY started_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []domain.JobRun{}
+	for rows.Next() {
+		var run domain.JobRun
+		var triggeredBy sql.NullString
+		var finishedAt sql.NullTime
+		err := rows.Scan(&run.ID, &run.JobName, &run.Trigger, &triggeredBy, &run.Instance, &run.Status,
+			&run.Summary, &run.Error, &run.StartedAt, &finishedAt, &run.DurationMs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		run.TriggeredBy = triggeredBy.String
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepository_TryLock(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLJobRepository(db)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1, hashtext\\(\\$2\\)\\)").
+		WithArgs(jobLockClass, "image-gc").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(\\$1, hashtext\\(\\$2\\)\\)").
+		WithArgs(jobLockClass, "image-gc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, ok, err := repo.TryLock("image-gc")
+	require.NoError(t, err)
+	require.True(t, ok)
+	unlock()
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").
+		WithArgs(jobLockClass, "image-gc").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	_, ok, err = repo.TryLock("image-gc")
+	require.NoError(t, err)
+	assert.False(t, ok, "the lock is held by another instance")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepository_ListRuns(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLJobRepository(db)
+	started := time.Date(2025, 8, 19, 3, 0, 0, 0, time.UTC)
+	finished := started.Add(1500 * time.Millisecond)
+
+	rows := sqlmock.NewRows([]string{"id", "job_name", "trigger", "triggered_by", "instance", "status", "summary", "error", "started_at", "finished_at", "duration_ms"}).
+		AddRow("run-2", "image-gc", "manual", "admin-1", "api-1", "running", "", "", started.Add(time.Hour), nil, 0).
+		AddRow("run-1", "image-gc", "schedule", nil, "api-2", "succeeded", "2 orphan files", "", started, finished, 1500)
+	mock.ExpectQuery("SELECT .* FROM job_runs\\s+WHERE \\(\\$1 = '' OR job_name = \\$1\\)").
+		WithArgs("image-gc", 20).
+		WillReturnRows(rows)
+
+	runs, err := repo.ListRuns("image-gc", 20)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "admin-1", runs[0].TriggeredBy)
+	assert.Nil(t, runs[0].FinishedAt)
+	assert.Empty(t, runs[1].TriggeredBy)
+	assert.Equal(t, finished, *runs[1].FinishedAt)
+	assert.Equal(t, int64(1500), runs[1].DurationMs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
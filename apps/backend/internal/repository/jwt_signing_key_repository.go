@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// JWTSigningKeyRepository defines the interface for the stored JWT signing keys
+type JWTSigningKeyRepository interface {
+	// ListValid retrieves the keys that verify tokens at a time, newest first
+	ListValid(now time.Time) ([]domain.JWTSigningKey, error)
+
+	// Rotate retires the current key with a grace period ending at expiresAt and
+	// stores key as the new current key, atomically
+	Rotate(key *domain.JWTSigningKey, retiredAt, expiresAt time.Time) error
+
+	// DeleteExpired permanently deletes keys whose grace period ended before a time
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// PostgreSQLJWTSigningKeyRepository implements JWTSigningKeyRepository for PostgreSQL
+type PostgreSQLJWTSigningKeyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLJWTSigningKeyRepository creates a new PostgreSQL JWT signing key repository
+func NewPostgreSQLJWTSigningKeyRepository(db *sql.DB) *PostgreSQLJWTSigningKeyRepository {
+	return &PostgreSQLJWTSigningKeyRepository{db: db}
+}
+
+// ListValid retrieves the keys that verify tokens at a time, newest first
+func (r *PostgreSQLJWTSigningKeyRepository) ListValid(now time.Time) ([]domain.JWTSigningKey, error) {
+	rows, err := r.db.Query(`
+		SELECT id, algorithm, public_key, sealed_private_key, created_at, retired_at, expires_at
+		FROM jwt_signing_keys
+		WHERE expires_at IS NULL OR expires_at > $1
+		ORDER BY created_at DESC`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.JWTSigningKey{}
+	for rows.Next() {
+		var key domain.JWTSigningKey
+		var retiredAt, expiresAt sql.NullTime
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PublicKey, &key.SealedPrivateKey,
+			&key.CreatedAt, &retiredAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+		if expiresAt.Valid {
+			key.ExpiresAt = &expiresAt.Time
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Rotate retires the current key and stores the new current key in one transaction
+func (r *PostgreSQLJWTSigningKeyRepository) Rotate(key *domain.JWTSigningKey, retiredAt, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE jwt_signing_keys SET retired_at = $1, expires_at = $2
+		WHERE retired_at IS NULL`, retiredAt, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to retire signing key: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO jwt_signing_keys (id, algorithm, public_key, sealed_private_key, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		key.ID, key.Algorithm, key.PublicKey, key.SealedPrivateKey, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit signing key rotation: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired permanently deletes keys whose grace period ended before a time
+func (r *PostgreSQLJWTSigningKeyRepository) DeleteExpired(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM jwt_signing_keys WHERE expires_at <= $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired signing keys: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
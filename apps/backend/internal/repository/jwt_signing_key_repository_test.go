@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestJWTSigningKeyRepository_ListValid(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLJWTSigningKeyRepository(db)
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(24 * time.Hour)
+
+	rows := sqlmock.NewRows([]string{"id", "algorithm", "public_key", "sealed_private_key", "created_at", "retired_at", "expires_at"}).
+		AddRow("20250815-new", "EdDSA", "PEM", "sealed", now, nil, nil).
+		AddRow("20250801-old", "EdDSA", "PEM", "sealed", now.Add(-14*24*time.Hour), now, expiresAt)
+	mock.ExpectQuery("SELECT id, algorithm, public_key, sealed_private_key.*WHERE expires_at IS NULL OR expires_at > \\$1").
+		WithArgs(now).
+		WillReturnRows(rows)
+
+	keys, err := repo.ListValid(now)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.True(t, keys[0].IsCurrent())
+	assert.False(t, keys[1].IsCurrent())
+	assert.Equal(t, expiresAt, *keys[1].ExpiresAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJWTSigningKeyRepository_Rotate(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLJWTSigningKeyRepository(db)
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(7 * 24 * time.Hour)
+	key := &domain.JWTSigningKey{ID: "20250815-new", Algorithm: "EdDSA", PublicKey: "PEM", SealedPrivateKey: "sealed", CreatedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE jwt_signing_keys SET retired_at = \\$1, expires_at = \\$2\\s+WHERE retired_at IS NULL").
+		WithArgs(now, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO jwt_signing_keys").
+		WithArgs(key.ID, key.Algorithm, key.PublicKey, key.SealedPrivateKey, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Rotate(key, now, expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJWTSigningKeyRepository_DeleteExpired(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLJWTSigningKeyRepository(db)
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM jwt_signing_keys WHERE expires_at <= \\$1").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deleted, err := repo.DeleteExpired(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// LeadPipelineRepository defines data access for the CRM pipelines of agencies and
+// the stage transitions of their leads
+type LeadPipelineRepository interface {
+	// GetPipeline retrieves the pipeline of an agency, or its default pipeline
+	GetPipeline(agencyID string) (*domain.LeadPipeline, error)
+
+	// SavePipeline creates or replaces the custom pipeline of an agency
+	SavePipeline(pipeline *domain.LeadPipeline) error
+
+	// MoveStage saves the new stage of a lead that is still in transition.FromStage,
+	// together with the transition
+	MoveStage(lead *domain.LeadAssignment, transition *domain.LeadStageTransition) error
+
+	// ListLeadTransitions retrieves the stage transitions of a lead, oldest first
+	ListLeadTransitions(leadID string) ([]domain.LeadStageTransition, error)
+
+	// ListAgencyTransitions retrieves the stage transitions of an agency's leads in a
+	// period, optionally of one agent's leads
+	ListAgencyTransitions(agencyID, agentID string, from, to time.Time) ([]domain.LeadStageTransition, error)
+}
+
+// PostgreSQLLeadPipelineRepository implements LeadPipelineRepository using PostgreSQL
+type PostgreSQLLeadPipelineRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLLeadPipelineRepository creates a new PostgreSQL lead pipeline repository
+func NewPostgreSQLLeadPipelineRepository(db *sql.DB) *PostgreSQLLeadPipelineRepository {
+	return &PostgreSQLLeadPipelineRepository{db: db}
+}
+
+const leadStageTransitionColumns = `t.id, t.lead_id, t.agency_id, t.from_stage, t.to_stage, t.actor_id,
+		t.duration_seconds, t.created_at`
+
+// GetPipeline retrieves the pipeline of an agency, or its default pipeline
+func (r *PostgreSQLLeadPipelineRepository) GetPipeline(agencyID string) (*domain.LeadPipeline, error) {
+	var stages []byte
+	var updatedAt time.Time
+	err := r.db.QueryRow(`SELECT stages, updated_at FROM lead_pipelines WHERE agency_id = $1`, agencyID).
+		Scan(&stages, &updatedAt)
+	if err == sql.ErrNoRows {
+		return domain.DefaultLeadPipeline(agencyID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead pipeline: %w", err)
+	}
+
+	pipeline := &domain.LeadPipeline{AgencyID: agencyID, Custom: true, UpdatedAt: &updatedAt}
+	if err := json.Unmarshal(stages, &pipeline.Stages); err != nil {
+		return nil, fmt.Errorf("failed to decode pipeline stages: %w", err)
+	}
+	return pipeline, nil
+}
+
+// SavePipeline creates or replaces the custom pipeline of an agency
+func (r *PostgreSQLLeadPipelineRepository) SavePipeline(pipeline *domain.LeadPipeline) error {
+	stages, err := json.Marshal(pipeline.Stages)
+	if err != nil {
+		return fmt.Errorf("failed to encode pipeline stages: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO lead_pipelines (agency_id, stages, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (agency_id) DO UPDATE SET stages = EXCLUDED.stages, updated_at = EXCLUDED.updated_at`,
+		pipeline.AgencyID, stages, pipeline.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save lead pipeline: %w", err)
+	}
+	return nil
+}
+
+// MoveStage saves the new stage of a lead with its transition in one transaction. A
+// lead moved by someone else meanwhile is left alone.
+func (r *PostgreSQLLeadPipelineRepository) MoveStage(lead *domain.LeadAssignment, transition *domain.LeadStageTransition) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE lead_assignments SET stage = $2, stage_entered_at = $3
+		WHERE id = $1 AND stage = $4`,
+		lead.ID, lead.Stage, lead.StageEnteredAt, transition.FromStage)
+	if err != nil {
+		return fmt.Errorf("failed to move lead: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("lead %s was moved meanwhile", lead.ID)
+	}
+
+	var actorID interface{}
+	if transition.ActorID != "" {
+		actorID = transition.ActorID
+	}
+	_, err = tx.Exec(`
+		INSERT INTO lead_stage_transitions (id, lead_id, agency_id, from_stage, to_stage, actor_id,
+			duration_seconds, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		transition.ID, transition.LeadID, transition.AgencyID, transition.FromStage, transition.ToStage,
+		actorID, transition.DurationSeconds, transition.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record stage transition: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stage transition: %w", err)
+	}
+	return nil
+}
+
+// ListLeadTransitions retrieves the stage transitions of a lead, oldest first
+func (r *PostgreSQLLeadPipelineRepository) ListLeadTransitions(leadID string) ([]domain.LeadStageTransition, error) {
+	return r.listTransitions(`SELECT `+leadStageTransitionColumns+` FROM lead_stage_transitions t
+		WHERE t.lead_id = $1 ORDER BY t.created_at`, leadID)
+}
+
+// ListAgencyTransitions retrieves the stage transitions of an agency's leads in a period
+func (r *PostgreSQLLeadPipelineRepository) ListAgencyTransitions(agencyID, agentID string, from, to time.Time) ([]domain.LeadStageTransition, error) {
+	if agentID == "" {
+		return r.listTransitions(`SELECT `+leadStageTransitionColumns+` FROM lead_stage_transitions t
+			WHERE t.agency_id = $1 AND t.created_at >= $2 AND t.created_at < $3 ORDER BY t.created_at`,
+			agencyID, from, to)
+	}
+	return r.listTransitions(`SELECT `+leadStageTransitionColumns+` FROM lead_stage_transitions t
+		JOIN lead_assignments l ON l.id = t.lead_id
+		WHERE t.agency_id = $1 AND t.created_at >= $2 AND t.created_at < $3 AND l.agent_id = $4
+		ORDER BY t.created_at`, agencyID, from, to, agentID)
+}
+
+// listTransitions runs a query selecting leadStageTransitionColumns
+func (r *PostgreSQLLeadPipelineRepository) listTransitions(query string, args ...interface{}) ([]domain.LeadStageTransition, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stage transitions: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := []domain.LeadStageTransition{}
+	for rows.Next() {
+		var transition domain.LeadStageTransition
+		var actorID sql.NullString
+		err := rows.Scan(&transition.ID, &transition.LeadID, &transition.AgencyID, &transition.FromStage,
+			&transition.ToStage, &actorID, &transition.DurationSeconds, &transition.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stage transition: %w", err)
+		}
+		transition.ActorID = actorID.String
+		transitions = append(transitions, transition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestLeadPipelineRepository_GetPipelineDefault(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLeadPipelineRepository(db)
+
+	mock.ExpectQuery(`SELECT stages, updated_at FROM lead_pipelines WHERE agency_id = \$1`).
+		WithArgs("agency-1").
+		WillReturnRows(sqlmock.NewRows([]string{"stages", "updated_at"}))
+
+	pipeline, err := repo.GetPipeline("agency-1")
+	require.NoError(t, err)
+	assert.False(t, pipeline.Custom)
+	assert.Equal(t, domain.LeadStageNew, pipeline.EntryStage())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLeadPipelineRepository_MoveStage(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLeadPipelineRepository(db)
+
+	now := time.Now()
+	lead := domain.NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-1", domain.LeadScore{}, now)
+	transition, err := lead.MoveToStage(domain.DefaultLeadPipeline("agency-1"), domain.LeadStageContacted, "agent-1", now.Add(time.Hour))
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE lead_assignments SET stage = \$2, stage_entered_at = \$3\s+WHERE id = \$1 AND stage = \$4`).
+		WithArgs(lead.ID, domain.LeadStageContacted, lead.StageEnteredAt, domain.LeadStageNew).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorContains(t, repo.MoveStage(lead, transition), "moved meanwhile")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE lead_assignments`).
+		WithArgs(lead.ID, domain.LeadStageContacted, lead.StageEnteredAt, domain.LeadStageNew).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO lead_stage_transitions`).
+		WithArgs(transition.ID, lead.ID, "agency-1", domain.LeadStageNew, domain.LeadStageContacted, "agent-1",
+			int64(3600), transition.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.MoveStage(lead, transition))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
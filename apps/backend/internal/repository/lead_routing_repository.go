@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// LeadRoutingRepository defines data access for the lead routing rules of agencies and
+// the routing outcome of each lead
+type LeadRoutingRepository interface {
+	// CreateRule saves a new routing rule
+	CreateRule(rule *domain.LeadRoutingRule) error
+
+	// UpdateRule saves the settings of a routing rule
+	UpdateRule(rule *domain.LeadRoutingRule) error
+
+	// DeleteRule deletes a routing rule of an agency
+	DeleteRule(agencyID, id string) error
+
+	// GetRule retrieves a routing rule by ID
+	GetRule(id string) (*domain.LeadRoutingRule, error)
+
+	// ListRules retrieves the routing rules of an agency by priority
+	ListRules(agencyID string) ([]domain.LeadRoutingRule, error)
+
+	// NextAgent advances the round-robin cursor of a rule and returns its previous value
+	NextAgent(ruleID string) (int, error)
+
+	// CreateAssignment saves the routing outcome of a lead
+	CreateAssignment(assignment *domain.LeadAssignment) error
+
+	// GetAssignment retrieves a routed lead by ID
+	GetAssignment(id string) (*domain.LeadAssignment, error)
+
+	// ListAssignments retrieves routing outcomes, newest first, with their total
+	ListAssignments(filter domain.LeadAssignmentFilter) ([]domain.LeadAssignment, int, error)
+
+	// CountByStage counts the leads of an agency, optionally of one agent, per pipeline stage
+	CountByStage(agencyID, agentID string) (map[string]int, error)
+}
+
+// PostgreSQLLeadRoutingRepository implements LeadRoutingRepository using PostgreSQL
+type PostgreSQLLeadRoutingRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLLeadRoutingRepository creates a new PostgreSQL lead routing repository
+func NewPostgreSQLLeadRoutingRepository(db *sql.DB) *PostgreSQLLeadRoutingRepository {
+	return &PostgreSQLLeadRoutingRepository{db: db}
+}
+
+const leadRoutingRuleColumns = `id, agency_id, name, strategy, priority, sectors, languages, agent_ids,
+		min_score, active, created_at, updated_at`
+
+const leadAssignmentColumns = `id, application_id, property_id, agency_id, agent_id, rule_id, outcome,
+		score_details, stage, stage_entered_at, created_at`
+
+// CreateRule saves a new routing rule
+func (r *PostgreSQLLeadRoutingRepository) CreateRule(rule *domain.LeadRoutingRule) error {
+	_, err := r.db.Exec(`
+		INSERT INTO lead_routing_rules (`+leadRoutingRuleColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		rule.ID, rule.AgencyID, rule.Name, rule.Strategy, rule.Priority, pq.Array(rule.Sectors),
+		pq.Array(rule.Languages), pq.Array(rule.AgentIDs), rule.MinScore, rule.Active,
+		rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule saves the settings of a routing rule
+func (r *PostgreSQLLeadRoutingRepository) UpdateRule(rule *domain.LeadRoutingRule) error {
+	result, err := r.db.Exec(`
+		UPDATE lead_routing_rules
+		SET name = $3, strategy = $4, priority = $5, sectors = $6, languages = $7, agent_ids = $8,
+			min_score = $9, active = $10, updated_at = $11
+		WHERE id = $1 AND agency_id = $2`,
+		rule.ID, rule.AgencyID, rule.Name, rule.Strategy, rule.Priority, pq.Array(rule.Sectors),
+		pq.Array(rule.Languages), pq.Array(rule.AgentIDs), rule.MinScore, rule.Active, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+	return expectRuleAffected(result, rule.ID)
+}
+
+// DeleteRule deletes a routing rule of an agency
+func (r *PostgreSQLLeadRoutingRepository) DeleteRule(agencyID, id string) error {
+	result, err := r.db.Exec(`DELETE FROM lead_routing_rules WHERE id = $1 AND agency_id = $2`, id, agencyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+	return expectRuleAffected(result, id)
+}
+
+// GetRule retrieves a routing rule by ID
+func (r *PostgreSQLLeadRoutingRepository) GetRule(id string) (*domain.LeadRoutingRule, error) {
+	rule, err := scanLeadRoutingRule(r.db.QueryRow(`SELECT `+leadRoutingRuleColumns+` FROM lead_routing_rules WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("routing rule not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get routing rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules retrieves the routing rules of an agency by priority
+func (r *PostgreSQLLeadRoutingRepository) ListRules(agencyID string) ([]domain.LeadRoutingRule, error) {
+	rows, err := r.db.Query(`SELECT `+leadRoutingRuleColumns+` FROM lead_routing_rules
+		WHERE agency_id = $1 ORDER BY priority, created_at`, agencyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []domain.LeadRoutingRule{}
+	for rows.Next() {
+		rule, err := scanLeadRoutingRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return rules, nil
+}
+
+// NextAgent advances the round-robin cursor of a rule. The update is atomic, so
+// concurrent leads go to different agents.
+func (r *PostgreSQLLeadRoutingRepository) NextAgent(ruleID string) (int, error) {
+	var cursor int
+	err := r.db.QueryRow(`
+		UPDATE lead_routing_rules SET next_agent = next_agent + 1
+		WHERE id = $1
+		RETURNING next_agent - 1`, ruleID).Scan(&cursor)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("routing rule not found: %s", ruleID)
+		}
+		return 0, fmt.Errorf("failed to advance routing rule: %w", err)
+	}
+	return cursor, nil
+}
+
+// CreateAssignment saves the routing outcome of a lead
+func (r *PostgreSQLLeadRoutingRepository) CreateAssignment(assignment *domain.LeadAssignment) error {
+	score, err := json.Marshal(assignment.Score)
+	if err != nil {
+		return fmt.Errorf("failed to encode lead score: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO lead_assignments (id, application_id, property_id, agency_id, agent_id, rule_id, outcome,
+			score, score_details, stage, stage_entered_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		assignment.ID, assignment.ApplicationID, assignment.PropertyID, assignment.AgencyID,
+		assignment.AgentID, assignment.RuleID, assignment.Outcome, assignment.Score.Total, score,
+		assignment.Stage, assignment.StageEnteredAt, assignment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lead assignment: %w", err)
+	}
+	return nil
+}
+
+// GetAssignment retrieves a routed lead by ID
+func (r *PostgreSQLLeadRoutingRepository) GetAssignment(id string) (*domain.LeadAssignment, error) {
+	assignment, err := scanLeadAssignment(r.db.QueryRow(`SELECT `+leadAssignmentColumns+` FROM lead_assignments WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("lead not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get lead: %w", err)
+	}
+	return assignment, nil
+}
+
+// ListAssignments retrieves routing outcomes, newest first, with their total
+func (r *PostgreSQLLeadRoutingRepository) ListAssignments(filter domain.LeadAssignmentFilter) ([]domain.LeadAssignment, int, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.AgencyID != "" {
+		args = append(args, filter.AgencyID)
+		conditions = append(conditions, fmt.Sprintf("agency_id = $%d", len(args)))
+	}
+	if filter.AgentID != "" {
+		args = append(args, filter.AgentID)
+		conditions = append(conditions, fmt.Sprintf("agent_id = $%d", len(args)))
+	}
+	if filter.Outcome != "" {
+		args = append(args, filter.Outcome)
+		conditions = append(conditions, fmt.Sprintf("outcome = $%d", len(args)))
+	}
+	if filter.Stage != "" {
+		args = append(args, filter.Stage)
+		conditions = append(conditions, fmt.Sprintf("stage = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM lead_assignments`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count lead assignments: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM lead_assignments%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		leadAssignmentColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list lead assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []domain.LeadAssignment{}
+	for rows.Next() {
+		assignment, err := scanLeadAssignment(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan lead assignment: %w", err)
+		}
+		assignments = append(assignments, *assignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return assignments, total, nil
+}
+
+// CountByStage counts the leads of an agency, optionally of one agent, per pipeline stage
+func (r *PostgreSQLLeadRoutingRepository) CountByStage(agencyID, agentID string) (map[string]int, error) {
+	query := `SELECT stage, COUNT(*) FROM lead_assignments WHERE agency_id = $1`
+	args := []interface{}{agencyID}
+	if agentID != "" {
+		query += ` AND agent_id = $2`
+		args = append(args, agentID)
+	}
+	rows, err := r.db.Query(query+` GROUP BY stage`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count leads by stage: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var stage string
+		var count int
+		if err := rows.Scan(&stage, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan stage count: %w", err)
+		}
+		counts[stage] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return counts, nil
+}
+
+// scanLeadAssignment scans a row selecting leadAssignmentColumns
+func scanLeadAssignment(row interface{ Scan(...interface{}) error }) (*domain.LeadAssignment, error) {
+	var assignment domain.LeadAssignment
+	var agentID, ruleID sql.NullString
+	var score []byte
+	err := row.Scan(&assignment.ID, &assignment.ApplicationID, &assignment.PropertyID, &assignment.AgencyID,
+		&agentID, &ruleID, &assignment.Outcome, &score, &assignment.Stage, &assignment.StageEnteredAt,
+		&assignment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if agentID.Valid {
+		assignment.AgentID = &agentID.String
+	}
+	if ruleID.Valid {
+		assignment.RuleID = &ruleID.String
+	}
+	if err := json.Unmarshal(score, &assignment.Score); err != nil {
+		return nil, fmt.Errorf("failed to decode lead score: %w", err)
+	}
+	return &assignment, nil
+}
+
+// scanLeadRoutingRule scans a row selecting leadRoutingRuleColumns
+func scanLeadRoutingRule(row interface{ Scan(...interface{}) error }) (*domain.LeadRoutingRule, error) {
+	var rule domain.LeadRoutingRule
+	err := row.Scan(&rule.ID, &rule.AgencyID, &rule.Name, &rule.Strategy, &rule.Priority,
+		pq.Array(&rule.Sectors), pq.Array(&rule.Languages), pq.Array(&rule.AgentIDs), &rule.MinScore,
+		&rule.Active, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// expectRuleAffected reports a missing rule when a statement changed no rows
+func expectRuleAffected(result sql.Result, id string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("routing rule not found: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestLeadRoutingRepository_NextAgent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLeadRoutingRepository(db)
+
+	mock.ExpectQuery(`UPDATE lead_routing_rules SET next_agent = next_agent \+ 1\s+WHERE id = \$1\s+RETURNING next_agent - 1`).
+		WithArgs("rule-1").
+		WillReturnRows(sqlmock.NewRows([]string{"next_agent"}).AddRow(4))
+
+	cursor, err := repo.NextAgent("rule-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, cursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLeadRoutingRepository_ListAssignments(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLeadRoutingRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM lead_assignments WHERE agency_id = \$1 AND agent_id = \$2`).
+		WithArgs("agency-1", "agent-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT (.+) FROM lead_assignments WHERE (.+) ORDER BY created_at DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs("agency-1", "agent-1", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "application_id", "property_id", "agency_id", "agent_id",
+			"rule_id", "outcome", "score_details", "stage", "stage_entered_at", "created_at"}).
+			AddRow("lead-1", "app-1", "prop-1", "agency-1", "agent-1", nil, domain.LeadAssignedListingAgent,
+				[]byte(`{"total":72,"grade":"hot"}`), domain.LeadStageContacted, time.Now(), time.Now()))
+
+	assignments, total, err := repo.ListAssignments(domain.LeadAssignmentFilter{AgencyID: "agency-1", AgentID: "agent-1", Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, assignments, 1)
+	assert.Nil(t, assignments[0].RuleID)
+	assert.Equal(t, "agent-1", *assignments[0].AgentID)
+	assert.Equal(t, domain.LeadGradeHot, assignments[0].Score.Grade)
+	assert.Equal(t, domain.LeadStageContacted, assignments[0].Stage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// LeadSLARepository defines data access for the response policies of agencies, the
+// first responses to their leads and the reassignments of unanswered leads
+type LeadSLARepository interface {
+	// GetPolicy retrieves the response policy of an agency, or its default policy
+	GetPolicy(agencyID string) (*domain.LeadResponsePolicy, error)
+
+	// SavePolicy creates or replaces the response policy of an agency
+	SavePolicy(policy *domain.LeadResponsePolicy) error
+
+	// GetLeadIDByApplication retrieves the ID of the lead an inquiry was routed as
+	GetLeadIDByApplication(applicationID string) (string, error)
+
+	// LastReassignedAt retrieves when a lead was last reassigned, nil if never
+	LastReassignedAt(leadID string) (*time.Time, error)
+
+	// SaveFirstResponse saves the first response to a lead, reporting false when the
+	// lead was already answered
+	SaveFirstResponse(response *domain.LeadResponse) (bool, error)
+
+	// ListOverdueLeads lists unanswered leads past their agency's reassignment window,
+	// oldest first
+	ListOverdueLeads(now time.Time, limit int) ([]domain.OverdueLead, error)
+
+	// Reassign saves the new agent of a lead still unanswered and held by
+	// reassignment.FromAgentID, together with the reassignment
+	Reassign(reassignment *domain.LeadReassignment) error
+
+	// ListResponseSamples lists the leads of an agency routed in a period with their
+	// first responses, optionally of one agent
+	ListResponseSamples(agencyID, agentID string, from, to time.Time) ([]domain.LeadResponseSample, error)
+}
+
+// PostgreSQLLeadSLARepository implements LeadSLARepository using PostgreSQL
+type PostgreSQLLeadSLARepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLLeadSLARepository creates a new PostgreSQL lead SLA repository
+func NewPostgreSQLLeadSLARepository(db *sql.DB) *PostgreSQLLeadSLARepository {
+	return &PostgreSQLLeadSLARepository{db: db}
+}
+
+// GetPolicy retrieves the response policy of an agency, or its default policy
+func (r *PostgreSQLLeadSLARepository) GetPolicy(agencyID string) (*domain.LeadResponsePolicy, error) {
+	policy := &domain.LeadResponsePolicy{AgencyID: agencyID, Custom: true}
+	var updatedAt time.Time
+	err := r.db.QueryRow(`
+		SELECT target_minutes, reassign_after_minutes, updated_at FROM lead_response_policies WHERE agency_id = $1`,
+		agencyID).Scan(&policy.TargetMinutes, &policy.ReassignAfterMinutes, &updatedAt)
+	if err == sql.ErrNoRows {
+		return domain.DefaultLeadResponsePolicy(agencyID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead response policy: %w", err)
+	}
+	policy.UpdatedAt = &updatedAt
+	return policy, nil
+}
+
+// SavePolicy creates or replaces the response policy of an agency
+func (r *PostgreSQLLeadSLARepository) SavePolicy(policy *domain.LeadResponsePolicy) error {
+	_, err := r.db.Exec(`
+		INSERT INTO lead_response_policies (agency_id, target_minutes, reassign_after_minutes, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (agency_id) DO UPDATE SET target_minutes = EXCLUDED.target_minutes,
+			reassign_after_minutes = EXCLUDED.reassign_after_minutes, updated_at = EXCLUDED.updated_at`,
+		policy.AgencyID, policy.TargetMinutes, policy.ReassignAfterMinutes, policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save lead response policy: %w", err)
+	}
+	return nil
+}
+
+// GetLeadIDByApplication retrieves the ID of the lead an inquiry was routed as
+func (r *PostgreSQLLeadSLARepository) GetLeadIDByApplication(applicationID string) (string, error) {
+	var leadID string
+	err := r.db.QueryRow(`SELECT id FROM lead_assignments WHERE application_id = $1`, applicationID).Scan(&leadID)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("lead not found for application: %s", applicationID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get lead of application: %w", err)
+	}
+	return leadID, nil
+}
+
+// LastReassignedAt retrieves when a lead was last reassigned, nil if never
+func (r *PostgreSQLLeadSLARepository) LastReassignedAt(leadID string) (*time.Time, error) {
+	var at sql.NullTime
+	err := r.db.QueryRow(`SELECT MAX(created_at) FROM lead_reassignments WHERE lead_id = $1`, leadID).Scan(&at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead reassignment: %w", err)
+	}
+	if !at.Valid {
+		return nil, nil
+	}
+	return &at.Time, nil
+}
+
+// SaveFirstResponse saves the first response to a lead; later responses are ignored
+func (r *PostgreSQLLeadSLARepository) SaveFirstResponse(response *domain.LeadResponse) (bool, error) {
+	var agentID, responderID interface{}
+	if response.AgentID != "" {
+		agentID = response.AgentID
+	}
+	if response.ResponderID != "" {
+		responderID = response.ResponderID
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO lead_responses (lead_id, agency_id, agent_id, kind, responder_id, response_seconds, responded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (lead_id) DO NOTHING`,
+		response.LeadID, response.AgencyID, agentID, response.Kind, responderID, response.ResponseSeconds, response.RespondedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to save lead response: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ListOverdueLeads lists unanswered leads past their agency's reassignment window,
+// oldest first. Leads routed before the agency set its policy are left alone, as are
+// leads reassigned domain.MaxLeadReassignments times.
+func (r *PostgreSQLLeadSLARepository) ListOverdueLeads(now time.Time, limit int) ([]domain.OverdueLead, error) {
+	query := `
+		SELECT a.id, COALESCE(ra.last_at, a.created_at), COALESCE(ra.count, 0)
+		FROM lead_assignments a
+		JOIN lead_response_policies p ON p.agency_id = a.agency_id AND p.reassign_after_minutes > 0
+		LEFT JOIN (
+			SELECT lead_id, MAX(created_at) AS last_at, COUNT(*) AS count
+			FROM lead_reassignments GROUP BY lead_id
+		) ra ON ra.lead_id = a.id
+		WHERE a.agent_id IS NOT NULL
+			AND a.created_at >= p.updated_at
+			AND NOT EXISTS (SELECT 1 FROM lead_responses lr WHERE lr.lead_id = a.id)
+			AND COALESCE(ra.count, 0) < $2
+			AND COALESCE(ra.last_at, a.created_at) + p.reassign_after_minutes * INTERVAL '1 minute' <= $1
+		ORDER BY a.created_at
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, now, domain.MaxLeadReassignments, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overdue leads: %w", err)
+	}
+	defer rows.Close()
+
+	leads := []domain.OverdueLead{}
+	for rows.Next() {
+		var lead domain.OverdueLead
+		if err := rows.Scan(&lead.LeadID, &lead.AssignedAt, &lead.Reassignments); err != nil {
+			return nil, fmt.Errorf("failed to scan overdue lead: %w", err)
+		}
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+// Reassign saves the new agent of a lead with the reassignment in one transaction. A
+// lead answered or reassigned by someone else meanwhile is left alone.
+func (r *PostgreSQLLeadSLARepository) Reassign(reassignment *domain.LeadReassignment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE lead_assignments SET agent_id = $2
+		WHERE id = $1 AND agent_id = $3
+			AND NOT EXISTS (SELECT 1 FROM lead_responses WHERE lead_id = $1)`,
+		reassignment.LeadID, reassignment.ToAgentID, reassignment.FromAgentID)
+	if err != nil {
+		return fmt.Errorf("failed to reassign lead: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("lead %s was answered or reassigned meanwhile", reassignment.LeadID)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO lead_reassignments (id, lead_id, agency_id, from_agent_id, to_agent_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		reassignment.ID, reassignment.LeadID, reassignment.AgencyID, reassignment.FromAgentID,
+		reassignment.ToAgentID, reassignment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record lead reassignment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListResponseSamples lists the leads of an agency routed in a period with their first
+// responses. Answered leads count for the agent who held them when answered,
+// unanswered leads for their current agent.
+func (r *PostgreSQLLeadSLARepository) ListResponseSamples(agencyID, agentID string, from, to time.Time) ([]domain.LeadResponseSample, error) {
+	query := `
+		SELECT a.id, COALESCE(lr.agent_id::text, a.agent_id::text, ''),
+			COALESCE((SELECT MAX(created_at) FROM lead_reassignments ra WHERE ra.lead_id = a.id), a.created_at),
+			lr.kind, COALESCE(lr.responder_id::text, ''), lr.response_seconds, lr.responded_at
+		FROM lead_assignments a
+		LEFT JOIN lead_responses lr ON lr.lead_id = a.id
+		WHERE a.agency_id = $1 AND a.created_at >= $2 AND a.created_at < $3`
+	args := []interface{}{agencyID, from, to}
+	if agentID != "" {
+		query += ` AND COALESCE(lr.agent_id::text, a.agent_id::text) = $4`
+		args = append(args, agentID)
+	}
+
+	rows, err := r.db.Query(query+` ORDER BY a.created_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead responses: %w", err)
+	}
+	defer rows.Close()
+
+	samples := []domain.LeadResponseSample{}
+	for rows.Next() {
+		var sample domain.LeadResponseSample
+		var kind sql.NullString
+		var responderID string
+		var seconds sql.NullInt64
+		var respondedAt sql.NullTime
+		if err := rows.Scan(&sample.LeadID, &sample.AgentID, &sample.AssignedAt, &kind, &responderID,
+			&seconds, &respondedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan lead response: %w", err)
+		}
+		if kind.Valid {
+			sample.Response = &domain.LeadResponse{
+				LeadID:          sample.LeadID,
+				AgencyID:        agencyID,
+				AgentID:         sample.AgentID,
+				Kind:            kind.String,
+				ResponderID:     responderID,
+				ResponseSeconds: seconds.Int64,
+				RespondedAt:     respondedAt.Time,
+			}
+		}
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
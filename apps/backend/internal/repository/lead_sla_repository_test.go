@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestLeadSLARepository_Reassign(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLeadSLARepository(db)
+
+	now := time.Date(2025, 9, 25, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT a.id, (.+) FROM lead_assignments a\s+JOIN lead_response_policies p`).
+		WithArgs(now, domain.MaxLeadReassignments, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "assigned_at", "count"}).
+			AddRow("lead-1", now.Add(-2*time.Hour), 0))
+	leads, err := repo.ListOverdueLeads(now, 50)
+	require.NoError(t, err)
+	require.Len(t, leads, 1)
+	assert.Equal(t, "lead-1", leads[0].LeadID)
+
+	reassignment := &domain.LeadReassignment{ID: "r1", LeadID: "lead-1", AgencyID: "agency-1",
+		FromAgentID: "agent-a", ToAgentID: "agent-b", CreatedAt: now}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE lead_assignments SET agent_id = \$2\s+WHERE id = \$1 AND agent_id = \$3`).
+		WithArgs("lead-1", "agent-b", "agent-a").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorContains(t, repo.Reassign(reassignment), "answered or reassigned meanwhile")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE lead_assignments SET agent_id = \$2`).
+		WithArgs("lead-1", "agent-b", "agent-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO lead_reassignments`).
+		WithArgs("r1", "lead-1", "agency-1", "agent-a", "agent-b", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.Reassign(reassignment))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLeadSLARepository_ListResponseSamples(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLeadSLARepository(db)
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 30)
+	mock.ExpectQuery(`SELECT (.+) FROM lead_assignments a\s+LEFT JOIN lead_responses lr ON lr.lead_id = a.id\s+WHERE a.agency_id = \$1 AND a.created_at >= \$2 AND a.created_at < \$3 AND (.+) = \$4 ORDER BY a.created_at`).
+		WithArgs("agency-1", from, to, "agent-a").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "agent_id", "assigned_at", "kind", "responder_id", "response_seconds", "responded_at"}).
+			AddRow("lead-1", "agent-a", from, "call", "agent-a", 600, from.Add(10*time.Minute)).
+			AddRow("lead-2", "agent-a", from, nil, "", nil, nil))
+
+	samples, err := repo.ListResponseSamples("agency-1", "agent-a", from, to)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, int64(600), samples[0].Response.ResponseSeconds)
+	assert.Equal(t, domain.LeadResponseCall, samples[0].Response.Kind)
+	assert.Nil(t, samples[1].Response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
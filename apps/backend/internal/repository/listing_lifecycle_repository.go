@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// ListingLifecycleRepository defines the interface for listing expiration and renewal
+type ListingLifecycleRepository interface {
+	// ExpireStale marks available listings published longer than their plan's expiry
+	// window as expired. planDays maps agency plans to days; other listings use defaultDays.
+	ExpireStale(now time.Time, planDays map[string]int, defaultDays int) ([]domain.ExpiredListing, error)
+
+	// Renew republishes an available or expired listing from now and returns its renewal count
+	Renew(propertyID string, now time.Time) (int, error)
+}
+
+// PostgreSQLListingLifecycleRepository implements ListingLifecycleRepository using PostgreSQL
+type PostgreSQLListingLifecycleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLListingLifecycleRepository creates a new PostgreSQL listing lifecycle repository
+func NewPostgreSQLListingLifecycleRepository(db *sql.DB) *PostgreSQLListingLifecycleRepository {
+	return &PostgreSQLListingLifecycleRepository{db: db}
+}
+
+// ExpireStale marks available listings published longer than their plan's expiry window as expired
+func (r *PostgreSQLListingLifecycleRepository) ExpireStale(now time.Time, planDays map[string]int, defaultDays int) ([]domain.ExpiredListing, error) {
+	plans := make([]string, 0, len(planDays))
+	days := make([]int64, 0, len(planDays))
+	for plan, d := range planDays {
+		plans = append(plans, plan)
+		days = append(days, int64(d))
+	}
+
+	query := `
+		UPDATE properties p
+		SET status = 'expired', expired_at = $1, updated_at = $1
+		FROM (
+			SELECT pr.id
+			FROM properties pr
+			LEFT JOIN agencies a ON a.id = pr.agency_id
+			LEFT JOIN unnest($2::text[], $3::int[]) AS plan(name, days) ON plan.name = a.subscription_plan
+			WHERE pr.status = 'available'
+			AND COALESCE(pr.renewed_at, pr.created_at) < $1 - make_interval(days => COALESCE(plan.days, $4))
+		) stale
+		WHERE p.id = stale.id
+		RETURNING p.id, p.title, p.owner_id, p.agent_id, p.agency_id`
+
+	rows, err := r.db.Query(query, now, pq.Array(plans), pq.Array(days), defaultDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire stale listings: %w", err)
+	}
+	defer rows.Close()
+
+	expired := []domain.ExpiredListing{}
+	for rows.Next() {
+		var listing domain.ExpiredListing
+		var ownerID, agentID, agencyID sql.NullString
+		if err := rows.Scan(&listing.PropertyID, &listing.Title, &ownerID, &agentID, &agencyID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired listing: %w", err)
+		}
+
+		if ownerID.Valid {
+			listing.OwnerID = &ownerID.String
+		}
+		if agentID.Valid {
+			listing.AgentID = &agentID.String
+		}
+		if agencyID.Valid {
+			listing.AgencyID = &agencyID.String
+		}
+		expired = append(expired, listing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return expired, nil
+}
+
+// Renew republishes an available or expired listing from now. The publication date
+// moves to now so the listing ranks as new again.
+func (r *PostgreSQLListingLifecycleRepository) Renew(propertyID string, now time.Time) (int, error) {
+	query := `
+		UPDATE properties
+		SET status = 'available', created_at = $2, renewed_at = $2, expired_at = NULL,
+			renewal_count = renewal_count + 1, updated_at = $2
+		WHERE id = $1 AND status IN ('available', 'expired')
+		RETURNING renewal_count`
+
+	var count int
+	if err := r.db.QueryRow(query, propertyID, now).Scan(&count); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("property not found or not renewable: %s", propertyID)
+		}
+		return 0, fmt.Errorf("failed to renew listing: %w", err)
+	}
+
+	return count, nil
+}
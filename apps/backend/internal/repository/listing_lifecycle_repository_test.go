@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingLifecycleRepository_ExpireStale(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLListingLifecycleRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE properties p SET status = 'expired'(.+)unnest\\(\\$2::text\\[\\], \\$3::int\\[\\]\\)(.+)RETURNING").
+		WithArgs(now, sqlmock.AnyArg(), sqlmock.AnyArg(), 90).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "owner_id", "agent_id", "agency_id"}).
+			AddRow("prop-1", "Casa en Cumbayá", "owner-1", nil, nil).
+			AddRow("prop-2", "Suite en Urdesa", nil, "agent-1", "agency-1"))
+
+	expired, err := repo.ExpireStale(now, map[string]int{"free": 60}, 90)
+	require.NoError(t, err)
+	require.Len(t, expired, 2)
+	assert.Equal(t, "owner-1", *expired[0].OwnerID)
+	assert.Nil(t, expired[0].AgentID)
+	assert.Equal(t, "agency-1", *expired[1].AgencyID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListingLifecycleRepository_Renew(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLListingLifecycleRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE properties SET status = 'available', created_at = \\$2").
+		WithArgs("prop-1", now).
+		WillReturnRows(sqlmock.NewRows([]string{"renewal_count"}).AddRow(2))
+	mock.ExpectQuery("UPDATE properties SET status = 'available', created_at = \\$2").
+		WithArgs("prop-sold", now).
+		WillReturnRows(sqlmock.NewRows([]string{"renewal_count"}))
+
+	count, err := repo.Renew("prop-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = repo.Renew("prop-sold", now)
+	assert.ErrorContains(t, err, "not renewable")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// ListingMetricsRepository defines the interface for days on market and listing freshness
+type ListingMetricsRepository interface {
+	// GetPropertyMetrics retrieves the publication, closing and stored days on market of a property
+	GetPropertyMetrics(propertyID string) (*domain.ListingMetrics, error)
+
+	// GetMarketDaysOnMarket summarizes days on market of the listings of a province and
+	// type, empty for all, counting closings since a time
+	GetMarketDaysOnMarket(province, propertyType string, since, now time.Time) (*domain.MarketDaysOnMarket, error)
+
+	// ListStaleListings lists the open listings of an agency published before a time, oldest first
+	ListStaleListings(agencyID string, publishedBefore time.Time, limit int) ([]domain.StaleListing, error)
+}
+
+// PostgreSQLListingMetricsRepository implements ListingMetricsRepository using PostgreSQL
+type PostgreSQLListingMetricsRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLListingMetricsRepository creates a new PostgreSQL listing metrics repository
+func NewPostgreSQLListingMetricsRepository(db *sql.DB) *PostgreSQLListingMetricsRepository {
+	return &PostgreSQLListingMetricsRepository{db: db}
+}
+
+// GetPropertyMetrics retrieves the publication, closing and stored days on market of a
+// property. Open listings have no stored days on market.
+func (r *PostgreSQLListingMetricsRepository) GetPropertyMetrics(propertyID string) (*domain.ListingMetrics, error) {
+	query := `
+		SELECT id, status, COALESCE(renewed_at, created_at), closed_at, COALESCE(days_on_market, 0), renewal_count
+		FROM properties WHERE id = $1`
+
+	metrics := &domain.ListingMetrics{}
+	var closedAt sql.NullTime
+	err := r.db.QueryRow(query, propertyID).Scan(
+		&metrics.PropertyID, &metrics.Status, &metrics.PublishedAt, &closedAt, &metrics.DaysOnMarket, &metrics.RenewalCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("property not found: %s", propertyID)
+		}
+		return nil, fmt.Errorf("failed to get listing metrics: %w", err)
+	}
+
+	if closedAt.Valid {
+		metrics.ClosedAt = &closedAt.Time
+	}
+	return metrics, nil
+}
+
+// GetMarketDaysOnMarket summarizes days on market of a market segment
+func (r *PostgreSQLListingMetricsRepository) GetMarketDaysOnMarket(province, propertyType string, since, now time.Time) (*domain.MarketDaysOnMarket, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status IN ('available', 'reserved')),
+			COALESCE(AVG(EXTRACT(EPOCH FROM ($3 - COALESCE(renewed_at, created_at))) / 86400)
+				FILTER (WHERE status IN ('available', 'reserved')), 0),
+			COUNT(*) FILTER (WHERE status IN ('sold', 'rented') AND closed_at >= $4),
+			COALESCE(AVG(days_on_market) FILTER (WHERE status IN ('sold', 'rented') AND closed_at >= $4), 0),
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY days_on_market)
+				FILTER (WHERE status IN ('sold', 'rented') AND closed_at >= $4), 0)
+		FROM properties
+		WHERE ($1 = '' OR province = $1) AND ($2 = '' OR type = $2)`
+
+	market := &domain.MarketDaysOnMarket{Province: province, PropertyType: propertyType, Since: since}
+	err := r.db.QueryRow(query, province, propertyType, now, since).Scan(
+		&market.ActiveListings, &market.AvgActiveDays, &market.ClosedListings,
+		&market.AvgDaysOnMarket, &market.MedianDaysOnMarket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market days on market: %w", err)
+	}
+
+	return market, nil
+}
+
+// ListStaleListings lists the open listings of an agency published before a time, oldest first
+func (r *PostgreSQLListingMetricsRepository) ListStaleListings(agencyID string, publishedBefore time.Time, limit int) ([]domain.StaleListing, error) {
+	query := `
+		SELECT id, title, status, price, agent_id, COALESCE(renewed_at, created_at) AS published_at
+		FROM properties
+		WHERE agency_id = $1 AND status IN ('available', 'reserved')
+		AND COALESCE(renewed_at, created_at) < $2
+		ORDER BY published_at
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, agencyID, publishedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale listings: %w", err)
+	}
+	defer rows.Close()
+
+	listings := []domain.StaleListing{}
+	for rows.Next() {
+		var listing domain.StaleListing
+		var agentID sql.NullString
+		if err := rows.Scan(&listing.PropertyID, &listing.Title, &listing.Status, &listing.Price, &agentID, &listing.PublishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stale listing: %w", err)
+		}
+		if agentID.Valid {
+			listing.AgentID = &agentID.String
+		}
+		listings = append(listings, listing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate stale listings: %w", err)
+	}
+	return listings, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// LoginEventRepository defines the interface for the login history of users
+type LoginEventRepository interface {
+	// Create stores a login
+	Create(event *domain.LoginEvent) error
+
+	// CountLogins counts the located logins of a user and how many of them came from
+	// a location
+	CountLogins(userID string, location domain.GeoLocation) (total int, fromLocation int, err error)
+}
+
+// PostgreSQLLoginEventRepository implements LoginEventRepository using PostgreSQL
+type PostgreSQLLoginEventRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLLoginEventRepository creates a new PostgreSQL login event repository
+func NewPostgreSQLLoginEventRepository(db *sql.DB) *PostgreSQLLoginEventRepository {
+	return &PostgreSQLLoginEventRepository{db: db}
+}
+
+const loginEventColumns = `id, user_id, session_id, ip_address, user_agent, country_code, country_name, city, new_location, created_at`
+
+// Create stores a login
+func (r *PostgreSQLLoginEventRepository) Create(event *domain.LoginEvent) error {
+	query := `
+		INSERT INTO login_events (` + loginEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.Exec(query,
+		event.ID, event.UserID, event.SessionID, event.IPAddress, event.UserAgent,
+		event.CountryCode, event.CountryName, event.City, event.NewLocation, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create login event: %w", err)
+	}
+
+	return nil
+}
+
+// CountLogins counts the located logins of a user and how many of them came from
+// the country and city of a location
+func (r *PostgreSQLLoginEventRepository) CountLogins(userID string, location domain.GeoLocation) (int, int, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE country_code <> ''),
+		       COUNT(*) FILTER (WHERE country_code = $2 AND city = $3)
+		FROM login_events WHERE user_id = $1`
+
+	var total, fromLocation int
+	if err := r.db.QueryRow(query, userID, location.CountryCode, location.City).Scan(&total, &fromLocation); err != nil {
+		return 0, 0, fmt.Errorf("failed to count logins: %w", err)
+	}
+
+	return total, fromLocation, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestLoginEventRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLoginEventRepository(db)
+
+	event := domain.NewLoginEvent("user-1", "session-1", "190.152.10.4", "Mozilla/5.0",
+		domain.GeoLocation{CountryCode: "EC", CountryName: "Ecuador", City: "Quito"})
+	event.NewLocation = true
+
+	mock.ExpectExec(`INSERT INTO login_events`).
+		WithArgs(event.ID, "user-1", "session-1", "190.152.10.4", "Mozilla/5.0", "EC", "Ecuador", "Quito", true, event.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(event))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginEventRepository_CountLogins(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLLoginEventRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER \(WHERE country_code <> ''\),\s+COUNT\(\*\) FILTER \(WHERE country_code = \$2 AND city = \$3\)\s+FROM login_events WHERE user_id = \$1`).
+		WithArgs("user-1", "EC", "Cuenca").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "from_location"}).AddRow(4, 0))
+
+	total, fromLocation, err := repo.CountLogins("user-1", domain.GeoLocation{CountryCode: "EC", City: "Cuenca"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, 0, fromLocation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// MaintenanceRepository defines the interface for bulk data maintenance run by operators
+type MaintenanceRepository interface {
+	// ListPropertySlugs retrieves the title and slug of every property
+	ListPropertySlugs() ([]domain.PropertySlug, error)
+
+	// UpdatePropertySlug replaces the slug of a property
+	UpdatePropertySlug(propertyID, slug string) error
+
+	// ListPropertyDescriptions retrieves the description of every property
+	ListPropertyDescriptions() ([]domain.PropertyDescription, error)
+
+	// UpdatePropertyDescription replaces the description of a property
+	UpdatePropertyDescription(description domain.PropertyDescription) error
+
+	// BackfillPricePerM2 computes the missing price per m² of properties with an area
+	// and returns how many were filled
+	BackfillPricePerM2() (int64, error)
+
+	// RefreshSearchVectors rewrites every property so the search vector triggers rebuild
+	// the PostgreSQL full-text index, e.g. after a text search configuration change
+	RefreshSearchVectors() (int64, error)
+
+	// PurgeDeactivated deletes users and agencies deactivated before the given time.
+	// Agencies are only deleted once none of their users remain. A dry run reports the
+	// counts without deleting.
+	PurgeDeactivated(before time.Time, dryRun bool) (users, agencies int64, err error)
+}
+
+// PostgreSQLMaintenanceRepository implements MaintenanceRepository using PostgreSQL
+type PostgreSQLMaintenanceRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLMaintenanceRepository creates a new PostgreSQL maintenance repository
+func NewPostgreSQLMaintenanceRepository(db *sql.DB) *PostgreSQLMaintenanceRepository {
+	return &PostgreSQLMaintenanceRepository{db: db}
+}
+
+// ListPropertySlugs retrieves the title and slug of every property
+func (r *PostgreSQLMaintenanceRepository) ListPropertySlugs() ([]domain.PropertySlug, error) {
+	rows, err := r.db.Query(`SELECT id, title, COALESCE(slug, '') FROM properties ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query property slugs: %w", err)
+	}
+	defer rows.Close()
+
+	slugs := []domain.PropertySlug{}
+	for rows.Next() {
+		var slug domain.PropertySlug
+		if err := rows.Scan(&slug.ID, &slug.Title, &slug.Slug); err != nil {
+			return nil, fmt.Errorf("failed to scan property slug: %w", err)
+		}
+		slugs = append(slugs, slug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return slugs, nil
+}
+
+// UpdatePropertySlug replaces the slug of a property
+func (r *PostgreSQLMaintenanceRepository) UpdatePropertySlug(propertyID, slug string) error {
+	result, err := r.db.Exec(`UPDATE properties SET slug = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, propertyID, slug)
+	if err != nil {
+		return fmt.Errorf("failed to update property slug: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("property not found: %s", propertyID)
+	}
+	return nil
+}
+
+// ListPropertyDescriptions retrieves the description of every property
+func (r *PostgreSQLMaintenanceRepository) ListPropertyDescriptions() ([]domain.PropertyDescription, error) {
+	rows, err := r.db.Query(`
+		SELECT id, COALESCE(description, ''), description_html, description_raw
+		FROM properties ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query property descriptions: %w", err)
+	}
+	defer rows.Close()
+
+	descriptions := []domain.PropertyDescription{}
+	for rows.Next() {
+		var d domain.PropertyDescription
+		if err := rows.Scan(&d.ID, &d.Description, &d.DescriptionHTML, &d.DescriptionRaw); err != nil {
+			return nil, fmt.Errorf("failed to scan property description: %w", err)
+		}
+		descriptions = append(descriptions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return descriptions, nil
+}
+
+// UpdatePropertyDescription replaces the description of a property
+func (r *PostgreSQLMaintenanceRepository) UpdatePropertyDescription(description domain.PropertyDescription) error {
+	result, err := r.db.Exec(`
+		UPDATE properties SET description = $2, description_html = $3, description_raw = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		description.ID, description.Description, description.DescriptionHTML, description.DescriptionRaw)
+	if err != nil {
+		return fmt.Errorf("failed to update property description: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("property not found: %s", description.ID)
+	}
+	return nil
+}
+
+// BackfillPricePerM2 computes the missing price per m² of properties with an area
+func (r *PostgreSQLMaintenanceRepository) BackfillPricePerM2() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE properties
+		SET price_per_m2 = ROUND((price / area_m2)::numeric, 2), updated_at = CURRENT_TIMESTAMP
+		WHERE price_per_m2 IS NULL AND area_m2 > 0 AND price > 0`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill price per m2: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// RefreshSearchVectors rewrites every property so the search vector triggers run again
+func (r *PostgreSQLMaintenanceRepository) RefreshSearchVectors() (int64, error) {
+	result, err := r.db.Exec(`UPDATE properties SET title = title`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh search vectors: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// PurgeDeactivated deletes users and agencies deactivated before the given time. Both
+// deletes run in one transaction, rolled back on a dry run.
+func (r *PostgreSQLMaintenanceRepository) PurgeDeactivated(before time.Time, dryRun bool) (int64, int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM users WHERE active = FALSE AND updated_at < $1`, before)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge users: %w", err)
+	}
+	users, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	result, err = tx.Exec(`
+		DELETE FROM agencies a
+		WHERE a.active = FALSE AND a.updated_at < $1
+		AND NOT EXISTS (SELECT 1 FROM users u WHERE u.agency_id = a.id)`, before)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge agencies: %w", err)
+	}
+	agencies, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if dryRun {
+		return users, agencies, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return users, agencies, nil
+}
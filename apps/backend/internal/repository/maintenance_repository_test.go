@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceRepository_PurgeDeactivated(t *testing.T) {
+	before := time.Date(2025, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "purge commits", dryRun: false},
+		{name: "dry run rolls back", dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewPostgreSQLMaintenanceRepository(db)
+
+			mock.ExpectBegin()
+			mock.ExpectExec("DELETE FROM users WHERE active = FALSE AND updated_at < \\$1").
+				WithArgs(before).
+				WillReturnResult(sqlmock.NewResult(0, 3))
+			mock.ExpectExec("DELETE FROM agencies a.*NOT EXISTS \\(SELECT 1 FROM users u WHERE u.agency_id = a.id\\)").
+				WithArgs(before).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			if tt.dryRun {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			users, agencies, err := repo.PurgeDeactivated(before, tt.dryRun)
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), users)
+			assert.Equal(t, int64(1), agencies)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMaintenanceRepository_Slugs(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLMaintenanceRepository(db)
+
+	mock.ExpectQuery("SELECT id, title, COALESCE\\(slug, ''\\) FROM properties").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug"}).
+			AddRow("prop-1", "Casa en Cumbayá", "casa-en-cumbay-prop-1"))
+	mock.ExpectExec("UPDATE properties SET slug = \\$2").
+		WithArgs("prop-2", "casa-prop-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE properties\\s+SET price_per_m2 = ROUND\\(\\(price / area_m2\\)::numeric, 2\\)").
+		WillReturnResult(sqlmock.NewResult(0, 12))
+
+	slugs, err := repo.ListPropertySlugs()
+	require.NoError(t, err)
+	require.Len(t, slugs, 1)
+	assert.Equal(t, "Casa en Cumbayá", slugs[0].Title)
+
+	assert.ErrorContains(t, repo.UpdatePropertySlug("prop-2", "casa-prop-2"), "property not found")
+
+	filled, err := repo.BackfillPricePerM2()
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), filled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// MaintenanceRequestRepository defines data access for maintenance requests and their history
+type MaintenanceRequestRepository interface {
+	// Create stores a new request with its first update
+	Create(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate) error
+
+	// GetByID retrieves a request by ID, without its history
+	GetByID(id string) (*domain.MaintenanceRequest, error)
+
+	// ListByProperty returns the requests of a property, newest first, optionally by status
+	ListByProperty(propertyID, status string) ([]domain.MaintenanceRequest, error)
+
+	// ListByTenant returns the requests opened by a tenant, newest first
+	ListByTenant(tenantID string) ([]domain.MaintenanceRequest, error)
+
+	// UpdateStatus saves the status of a request together with the update recording it
+	UpdateStatus(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate) error
+
+	// AddPhoto appends a photo to a request
+	AddPhoto(requestID string, photo domain.MaintenancePhoto) error
+
+	// ListUpdates returns the history of the given requests, oldest first
+	ListUpdates(requestIDs ...string) ([]domain.MaintenanceUpdate, error)
+}
+
+// PostgreSQLMaintenanceRequestRepository implements MaintenanceRequestRepository using PostgreSQL
+type PostgreSQLMaintenanceRequestRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLMaintenanceRequestRepository creates a new PostgreSQL maintenance request repository
+func NewPostgreSQLMaintenanceRequestRepository(db *sql.DB) *PostgreSQLMaintenanceRequestRepository {
+	return &PostgreSQLMaintenanceRequestRepository{db: db}
+}
+
+const maintenanceRequestColumns = `id, property_id, tenant_id, category, priority, title, description, status,
+	photos, created_at, updated_at, resolved_at, closed_at`
+
+// scanMaintenanceRequest scans a request selected with maintenanceRequestColumns
+func scanMaintenanceRequest(row interface{ Scan(...interface{}) error }) (*domain.MaintenanceRequest, error) {
+	request := &domain.MaintenanceRequest{}
+	var photos []byte
+	var resolvedAt, closedAt sql.NullTime
+	err := row.Scan(&request.ID, &request.PropertyID, &request.TenantID, &request.Category, &request.Priority,
+		&request.Title, &request.Description, &request.Status, &photos,
+		&request.CreatedAt, &request.UpdatedAt, &resolvedAt, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(photos, &request.Photos); err != nil {
+		return nil, fmt.Errorf("failed to decode maintenance photos: %w", err)
+	}
+	if resolvedAt.Valid {
+		request.ResolvedAt = &resolvedAt.Time
+	}
+	if closedAt.Valid {
+		request.ClosedAt = &closedAt.Time
+	}
+	return request, nil
+}
+
+// insertMaintenanceUpdate records a status change inside a transaction
+func insertMaintenanceUpdate(tx *sql.Tx, update *domain.MaintenanceUpdate) error {
+	query := `
+		INSERT INTO maintenance_request_updates (id, request_id, from_status, to_status, comment, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := tx.Exec(query, update.ID, update.RequestID, update.FromStatus, update.ToStatus,
+		update.Comment, update.ChangedBy, update.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record maintenance update: %w", err)
+	}
+	return nil
+}
+
+// Create stores a new request with its first update
+func (r *PostgreSQLMaintenanceRequestRepository) Create(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate) error {
+	photos, err := json.Marshal(request.Photos)
+	if err != nil {
+		return fmt.Errorf("failed to encode maintenance photos: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO maintenance_requests (id, property_id, tenant_id, category, priority, title, description,
+			status, photos, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = tx.Exec(query, request.ID, request.PropertyID, request.TenantID, request.Category, request.Priority,
+		request.Title, request.Description, request.Status, photos, request.CreatedAt, request.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance request: %w", err)
+	}
+	if err := insertMaintenanceUpdate(tx, update); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit maintenance request: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a request by ID, without its history
+func (r *PostgreSQLMaintenanceRequestRepository) GetByID(id string) (*domain.MaintenanceRequest, error) {
+	query := `SELECT ` + maintenanceRequestColumns + ` FROM maintenance_requests WHERE id = $1`
+
+	request, err := scanMaintenanceRequest(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("maintenance request not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance request: %w", err)
+	}
+	return request, nil
+}
+
+// ListByProperty returns the requests of a property, newest first, optionally by status
+func (r *PostgreSQLMaintenanceRequestRepository) ListByProperty(propertyID, status string) ([]domain.MaintenanceRequest, error) {
+	query := `SELECT ` + maintenanceRequestColumns + ` FROM maintenance_requests
+		WHERE property_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC`
+	return r.list(query, propertyID, status)
+}
+
+// ListByTenant returns the requests opened by a tenant, newest first
+func (r *PostgreSQLMaintenanceRequestRepository) ListByTenant(tenantID string) ([]domain.MaintenanceRequest, error) {
+	query := `SELECT ` + maintenanceRequestColumns + ` FROM maintenance_requests
+		WHERE tenant_id = $1 ORDER BY created_at DESC`
+	return r.list(query, tenantID)
+}
+
+// UpdateStatus saves the status of a request together with the update recording it
+func (r *PostgreSQLMaintenanceRequestRepository) UpdateStatus(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The previous status guards against two concurrent changes of the same request
+	query := `
+		UPDATE maintenance_requests
+		SET status = $2, updated_at = $3, resolved_at = $4, closed_at = $5
+		WHERE id = $1 AND status = $6`
+
+	result, err := tx.Exec(query, request.ID, request.Status, request.UpdatedAt, request.ResolvedAt, request.ClosedAt, update.FromStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update maintenance request: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("maintenance request no longer %s: %s", update.FromStatus, request.ID)
+	}
+
+	if err := insertMaintenanceUpdate(tx, update); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit maintenance update: %w", err)
+	}
+	return nil
+}
+
+// AddPhoto appends a photo to a request
+func (r *PostgreSQLMaintenanceRequestRepository) AddPhoto(requestID string, photo domain.MaintenancePhoto) error {
+	encoded, err := json.Marshal([]domain.MaintenancePhoto{photo})
+	if err != nil {
+		return fmt.Errorf("failed to encode photo: %w", err)
+	}
+
+	query := `
+		UPDATE maintenance_requests
+		SET photos = photos || $2::jsonb, updated_at = $3
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, requestID, encoded, photo.UploadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add maintenance photo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("maintenance request not found: %s", requestID)
+	}
+	return nil
+}
+
+// ListUpdates returns the history of the given requests, oldest first
+func (r *PostgreSQLMaintenanceRequestRepository) ListUpdates(requestIDs ...string) ([]domain.MaintenanceUpdate, error) {
+	updates := []domain.MaintenanceUpdate{}
+	if len(requestIDs) == 0 {
+		return updates, nil
+	}
+
+	query := `
+		SELECT id, request_id, from_status, to_status, comment, changed_by, created_at
+		FROM maintenance_request_updates
+		WHERE request_id = ANY($1)
+		ORDER BY created_at, id`
+
+	rows, err := r.db.Query(query, pq.Array(requestIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var update domain.MaintenanceUpdate
+		err := rows.Scan(&update.ID, &update.RequestID, &update.FromStatus, &update.ToStatus,
+			&update.Comment, &update.ChangedBy, &update.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance update: %w", err)
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
+// list runs a query selecting maintenanceRequestColumns
+func (r *PostgreSQLMaintenanceRequestRepository) list(query string, args ...interface{}) ([]domain.MaintenanceRequest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []domain.MaintenanceRequest{}
+	for rows.Next() {
+		request, err := scanMaintenanceRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance request: %w", err)
+		}
+		requests = append(requests, *request)
+	}
+	return requests, rows.Err()
+}
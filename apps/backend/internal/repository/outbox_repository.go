@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// OutboxRepository defines the interface for dispatching outbox events. Events are
+// written by the repositories of the entities they describe, in the same transaction.
+type OutboxRepository interface {
+	// ClaimPending returns up to limit pending events available at now, oldest first,
+	// and postpones them by lease so other instances skip them while they are dispatched
+	ClaimPending(now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error)
+
+	// Update saves the outcome of dispatching an event
+	Update(event *domain.OutboxEvent) error
+
+	// DeletePublishedBefore deletes events published before a time, returning how many
+	DeletePublishedBefore(before time.Time) (int64, error)
+}
+
+// PostgreSQLOutboxRepository implements OutboxRepository using PostgreSQL
+type PostgreSQLOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLOutboxRepository creates a new PostgreSQL outbox repository
+func NewPostgreSQLOutboxRepository(db *sql.DB) *PostgreSQLOutboxRepository {
+	return &PostgreSQLOutboxRepository{db: db}
+}
+
+const outboxColumns = `id, event_type, aggregate_type, aggregate_id, agency_id, payload, status,
+		attempts, available_at, last_error, created_at, published_at`
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// ClaimPending leases the pending events available at now
+func (r *PostgreSQLOutboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events SET available_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status = 'pending' AND available_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	rows, err := r.db.Query(query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.OutboxEvent{}
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, *event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	// RETURNING does not keep the subquery order
+	sort.Slice(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return events, nil
+}
+
+// Update saves the outcome of dispatching an event
+func (r *PostgreSQLOutboxRepository) Update(event *domain.OutboxEvent) error {
+	query := `
+		UPDATE outbox_events
+		SET status = $2, attempts = $3, available_at = $4, last_error = $5, published_at = $6
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, event.ID, event.Status, event.Attempts, event.AvailableAt,
+		event.LastError, event.PublishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePublishedBefore deletes events published before a time
+func (r *PostgreSQLOutboxRepository) DeletePublishedBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM outbox_events WHERE status = 'published' AND published_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check deleted outbox events: %w", err)
+	}
+	return deleted, nil
+}
+
+// insertOutboxEvents stores events with exec, normally the transaction of the change
+// they describe
+func insertOutboxEvents(exec execer, events []*domain.OutboxEvent) error {
+	query := `INSERT INTO outbox_events (` + outboxColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	for _, event := range events {
+		agencyID := sql.NullString{String: event.AgencyID, Valid: event.AgencyID != ""}
+		_, err := exec.Exec(query,
+			event.ID, event.EventType, event.AggregateType, event.AggregateID, agencyID, string(event.Payload),
+			event.Status, event.Attempts, event.AvailableAt, event.LastError, event.CreatedAt, event.PublishedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create outbox event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// execWithOutbox runs write directly when there are no events, otherwise in a
+// transaction that also stores the events
+func execWithOutbox(db *sql.DB, events []*domain.OutboxEvent, write func(exec execer) error) error {
+	if len(events) == 0 {
+		return write(db)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := insertOutboxEvents(tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// scanOutboxEvent scans a row selected with outboxColumns
+func scanOutboxEvent(row interface{ Scan(...interface{}) error }) (*domain.OutboxEvent, error) {
+	event := &domain.OutboxEvent{}
+	var agencyID sql.NullString
+	var payload []byte
+	var publishedAt sql.NullTime
+	err := row.Scan(
+		&event.ID, &event.EventType, &event.AggregateType, &event.AggregateID, &agencyID, &payload,
+		&event.Status, &event.Attempts, &event.AvailableAt, &event.LastError, &event.CreatedAt, &publishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	event.AgencyID = agencyID.String
+	event.Payload = payload
+	if publishedAt.Valid {
+		event.PublishedAt = &publishedAt.Time
+	}
+
+	return event, nil
+}
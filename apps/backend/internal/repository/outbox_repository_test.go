@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestOutboxRepository_ClaimPending(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLOutboxRepository(db)
+	now := time.Date(2025, 8, 18, 12, 0, 0, 0, time.UTC)
+	lease := 5 * time.Minute
+
+	rows := sqlmock.NewRows([]string{"id", "event_type", "aggregate_type", "aggregate_id", "agency_id", "payload", "status", "attempts", "available_at", "last_error", "created_at", "published_at"}).
+		AddRow("evt-2", "property.updated", "property", "prop-1", nil, []byte(`{}`), "pending", 0, now.Add(lease), "", now.Add(-time.Second), nil).
+		AddRow("evt-1", "property.created", "property", "prop-1", "agency-1", []byte(`{"id":"prop-1"}`), "pending", 0, now.Add(lease), "", now.Add(-time.Minute), nil)
+	mock.ExpectQuery("UPDATE outbox_events SET available_at = \\$2.*FOR UPDATE SKIP LOCKED").
+		WithArgs(now, now.Add(lease), 10).
+		WillReturnRows(rows)
+
+	events, err := repo.ClaimPending(now, lease, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "evt-1", events[0].ID, "events are returned oldest first")
+	assert.Equal(t, "agency-1", events[0].AgencyID)
+	assert.Empty(t, events[1].AgencyID)
+	assert.JSONEq(t, `{"id":"prop-1"}`, string(events[0].Payload))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPropertyRepository_UpdateWithEvents(t *testing.T) {
+	property := domain.NewProperty("Casa en Samborondón", "Casa familiar con jardín", "Guayas", "Samborondón", "house", 285000, "owner-1")
+	event, err := domain.NewOutboxEvent(domain.EventPropertyUpdated, domain.AggregateProperty, property.ID, "", property)
+	require.NoError(t, err)
+
+	t.Run("stores the event in the update transaction", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE properties SET").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO outbox_events").
+			WithArgs(event.ID, domain.EventPropertyUpdated, domain.AggregateProperty, property.ID, sqlmock.AnyArg(),
+				sqlmock.AnyArg(), domain.OutboxPending, 0, sqlmock.AnyArg(), "", sqlmock.AnyArg(), nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, NewPostgreSQLPropertyRepository(db).UpdateWithEvents(property, event))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back the update when the event cannot be stored", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE properties SET").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO outbox_events").WillReturnError(fmt.Errorf("disk full"))
+		mock.ExpectRollback()
+
+		err := NewPostgreSQLPropertyRepository(db).UpdateWithEvents(property, event)
+		assert.ErrorContains(t, err, "failed to create outbox event")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
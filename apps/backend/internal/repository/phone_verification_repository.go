@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+// PhoneVerificationRepository defines the interface for SMS phone verification codes
+type PhoneVerificationRepository interface {
+	// Create stores a new verification code
+	Create(verification *domain.PhoneVerification) error
+
+	// GetLatestByUser retrieves the most recent verification code sent to a user
+	GetLatestByUser(userID string) (*domain.PhoneVerification, error)
+
+	// Update persists the attempts and verification time of a code
+	Update(verification *domain.PhoneVerification) error
+}
+
+// PostgreSQLPhoneVerificationRepository implements PhoneVerificationRepository using PostgreSQL
+type PostgreSQLPhoneVerificationRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+// NewPostgreSQLPhoneVerificationRepository creates a new PostgreSQL phone verification repository
+func NewPostgreSQLPhoneVerificationRepository(db *sql.DB) *PostgreSQLPhoneVerificationRepository {
+	return &PostgreSQLPhoneVerificationRepository{db: db}
+}
+
+// SetCipher encrypts the phone numbers codes are sent to at rest, like the phone of
+// their user
+func (r *PostgreSQLPhoneVerificationRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+const phoneVerificationColumns = `id, user_id, phone, code_hash, attempts, expires_at, verified_at, created_at`
+
+// Create stores a new verification code
+func (r *PostgreSQLPhoneVerificationRepository) Create(verification *domain.PhoneVerification) error {
+	if verification == nil {
+		return fmt.Errorf("phone verification cannot be nil")
+	}
+
+	phone, err := r.cipher.Encrypt(pii.PhoneVerificationPhone, verification.ID, verification.Phone)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt phone: %w", err)
+	}
+	index := r.cipher.BlindIndex(verification.Phone)
+
+	query := `
+		INSERT INTO phone_verifications (` + phoneVerificationColumns + `, phone_index)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err = r.db.Exec(query,
+		verification.ID, verification.UserID, phone, verification.CodeHash,
+		verification.Attempts, verification.ExpiresAt, verification.VerifiedAt, verification.CreatedAt,
+		sql.NullString{String: index, Valid: index != ""})
+	if err != nil {
+		return fmt.Errorf("failed to create phone verification: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestByUser retrieves the most recent verification code sent to a user
+func (r *PostgreSQLPhoneVerificationRepository) GetLatestByUser(userID string) (*domain.PhoneVerification, error) {
+	query := `SELECT ` + phoneVerificationColumns + ` FROM phone_verifications
+		WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`
+
+	verification := &domain.PhoneVerification{}
+	var verifiedAt sql.NullTime
+	err := r.db.QueryRow(query, userID).Scan(
+		&verification.ID, &verification.UserID, &verification.Phone, &verification.CodeHash,
+		&verification.Attempts, &verification.ExpiresAt, &verifiedAt, &verification.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("phone verification not found: %s", userID)
+		}
+		return nil, fmt.Errorf("failed to get phone verification: %w", err)
+	}
+
+	if verification.Phone, err = r.cipher.Decrypt(pii.PhoneVerificationPhone, verification.ID, verification.Phone); err != nil {
+		return nil, fmt.Errorf("failed to decrypt phone of phone verification %s: %w", verification.ID, err)
+	}
+	if verifiedAt.Valid {
+		verification.VerifiedAt = &verifiedAt.Time
+	}
+
+	return verification, nil
+}
+
+// Update persists the attempts and verification time of a code
+func (r *PostgreSQLPhoneVerificationRepository) Update(verification *domain.PhoneVerification) error {
+	if verification == nil {
+		return fmt.Errorf("phone verification cannot be nil")
+	}
+
+	query := `UPDATE phone_verifications SET attempts = $2, verified_at = $3 WHERE id = $1`
+
+	result, err := r.db.Exec(query, verification.ID, verification.Attempts, verification.VerifiedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update phone verification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("phone verification not found: %s", verification.ID)
+	}
+
+	return nil
+}
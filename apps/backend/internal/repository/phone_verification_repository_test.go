@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+func TestPhoneVerificationRepository_EncryptsPhone(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	keys, err := pii.ParseKeySet("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	cipher, err := pii.NewCipher(keys)
+	require.NoError(t, err)
+	repo := NewPostgreSQLPhoneVerificationRepository(db)
+	repo.SetCipher(cipher)
+
+	now := time.Now()
+	verification := &domain.PhoneVerification{ID: "verification-1", UserID: "user-1", Phone: "+593991234567",
+		CodeHash: "hash", ExpiresAt: now.Add(domain.PhoneVerificationTTL), CreatedAt: now}
+
+	var phone string
+	mock.ExpectExec(`INSERT INTO phone_verifications \((.+), phone_index\)`).
+		WithArgs("verification-1", "user-1", encryptedArg{&phone}, "hash", 0, verification.ExpiresAt, nil, now,
+			cipher.BlindIndex("+593991234567")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Create(verification))
+
+	mock.ExpectQuery(`SELECT (.+) FROM phone_verifications`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "phone", "code_hash", "attempts", "expires_at",
+			"verified_at", "created_at"}).
+			AddRow("verification-1", "user-1", phone, "hash", 0, verification.ExpiresAt, nil, now))
+
+	latest, err := repo.GetLatestByUser("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "+593991234567", latest.Phone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
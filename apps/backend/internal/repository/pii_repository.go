@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/pii"
+)
+
+// PIIRepository defines the interface for re-encrypting PII columns
+type PIIRepository interface {
+	// ListStale retrieves, ordered by ID, values of a column not encrypted with the
+	// active key: plaintext or encrypted with a rotated key. Pages start after afterID.
+	ListStale(column pii.Column, activePrefix, afterID string, limit int) ([]pii.Value, error)
+
+	// Rewrite replaces a value and its blind index, unless the row changed since it was
+	// read. It reports whether the row was rewritten.
+	Rewrite(column pii.Column, id, oldValue, newValue, index string) (bool, error)
+}
+
+// PostgreSQLPIIRepository implements PIIRepository using PostgreSQL. Table and column
+// names come from pii.Columns, never from input.
+type PostgreSQLPIIRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPIIRepository creates a new PostgreSQL PII repository
+func NewPostgreSQLPIIRepository(db *sql.DB) *PostgreSQLPIIRepository {
+	return &PostgreSQLPIIRepository{db: db}
+}
+
+// ListStale retrieves values of a column not encrypted with the active key
+func (r *PostgreSQLPIIRepository) ListStale(column pii.Column, activePrefix, afterID string, limit int) ([]pii.Value, error) {
+	query := fmt.Sprintf(`
+		SELECT id::text, %[2]s FROM %[1]s
+		WHERE %[2]s IS NOT NULL AND %[2]s <> '' AND NOT starts_with(%[2]s, $1) AND id::text > $2
+		ORDER BY id::text LIMIT $3`, column.Table, column.Column)
+
+	rows, err := r.db.Query(query, activePrefix, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", column.Name(), err)
+	}
+	defer rows.Close()
+
+	values := []pii.Value{}
+	for rows.Next() {
+		var value pii.Value
+		if err := rows.Scan(&value.ID, &value.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", column.Name(), err)
+		}
+		values = append(values, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return values, nil
+}
+
+// Rewrite replaces a value and its blind index when the row still holds oldValue
+func (r *PostgreSQLPIIRepository) Rewrite(column pii.Column, id, oldValue, newValue, index string) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET %s = $3 WHERE id::text = $1 AND %s = $2`,
+		column.Table, column.Column, column.Column)
+	args := []interface{}{id, oldValue, newValue}
+	if column.IndexColumn != "" {
+		query = fmt.Sprintf(`UPDATE %s SET %s = $3, %s = $4 WHERE id::text = $1 AND %s = $2`,
+			column.Table, column.Column, column.IndexColumn, column.Column)
+		args = append(args, sql.NullString{String: index, Valid: index != ""})
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to rewrite %s: %w", column.Name(), err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
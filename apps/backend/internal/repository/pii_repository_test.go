@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/pii"
+)
+
+func TestPIIRepository_ListStale(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPIIRepository(db)
+
+	mock.ExpectQuery(`SELECT id::text, phone FROM users\s+WHERE phone IS NOT NULL AND phone <> '' AND NOT starts_with\(phone, \$1\) AND id::text > \$2\s+ORDER BY id::text LIMIT \$3`).
+		WithArgs("enc:v2:2025-08:", "user-1", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "phone"}).
+			AddRow("user-2", "0987654321").
+			AddRow("user-3", "enc:v1:2025-05:AAAA"))
+
+	values, err := repo.ListStale(pii.Column{Table: "users", Column: "phone"}, "enc:v2:2025-08:", "user-1", 100)
+	require.NoError(t, err)
+	assert.Equal(t, []pii.Value{{ID: "user-2", Value: "0987654321"}, {ID: "user-3", Value: "enc:v1:2025-05:AAAA"}}, values)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPIIRepository_Rewrite(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPIIRepository(db)
+
+	mock.ExpectExec(`UPDATE deals SET notes = \$3 WHERE id::text = \$1 AND notes = \$2`).
+		WithArgs("deal-1", "old", "new").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	ok, err := repo.Rewrite(pii.Column{Table: "deals", Column: "notes"}, "deal-1", "old", "new", "")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Indexed columns rewrite their blind index too; a changed row is left alone
+	mock.ExpectExec(`UPDATE users SET national_id = \$3, national_id_index = \$4 WHERE id::text = \$1 AND national_id = \$2`).
+		WithArgs("user-1", "1712345678", "enc", sql.NullString{String: "abc", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	ok, err = repo.Rewrite(pii.Column{Table: "users", Column: "national_id", IndexColumn: "national_id_index"}, "user-1", "1712345678", "enc", "abc")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
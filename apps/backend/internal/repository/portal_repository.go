@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// PortalRepository defines data access for the leases shown in the tenant and landlord portal
+type PortalRepository interface {
+	// ListRentals returns the rent deals a user is the client of, or whose property
+	// they own, newest first
+	ListRentals(userID string) ([]domain.PortalRental, error)
+}
+
+// PostgreSQLPortalRepository implements PortalRepository using PostgreSQL
+type PostgreSQLPortalRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPortalRepository creates a new PostgreSQL portal repository
+func NewPostgreSQLPortalRepository(db *sql.DB) *PostgreSQLPortalRepository {
+	return &PostgreSQLPortalRepository{db: db}
+}
+
+// ListRentals returns the rent deals a user is the client of, or whose property they
+// own, newest first. The window over every rent deal of the property dates the leases
+// before and after each one.
+func (r *PostgreSQLPortalRepository) ListRentals(userID string) ([]domain.PortalRental, error) {
+	query := `
+		SELECT d.id, d.property_id, p.title, p.province, p.city, p.status,
+			COALESCE(d.client_id::text, ''), COALESCE(p.owner_id::text, ''),
+			d.final_price, d.commission_amount, d.closing_date, d.previous_closing, d.next_closing
+		FROM (
+			SELECT id, property_id, client_id, final_price, commission_amount, closing_date,
+				LAG(closing_date) OVER leases AS previous_closing,
+				LEAD(closing_date) OVER leases AS next_closing
+			FROM deals
+			WHERE type = $2 AND property_id IN (
+				SELECT property_id FROM deals WHERE client_id = $1
+				UNION
+				SELECT id FROM properties WHERE owner_id = $1)
+			WINDOW leases AS (PARTITION BY property_id ORDER BY closing_date)
+		) d
+		JOIN properties p ON p.id = d.property_id
+		WHERE d.client_id = $1 OR p.owner_id = $1
+		ORDER BY d.closing_date DESC`
+
+	rows, err := r.db.Query(query, userID, domain.DealTypeRent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rentals: %w", err)
+	}
+	defer rows.Close()
+
+	rentals := []domain.PortalRental{}
+	for rows.Next() {
+		var rental domain.PortalRental
+		var commission float64
+		var previous, next sql.NullTime
+		err := rows.Scan(&rental.DealID, &rental.PropertyID, &rental.Title, &rental.Province, &rental.City,
+			&rental.PropertyStatus, &rental.TenantID, &rental.OwnerID, &rental.MonthlyRent, &commission,
+			&rental.StartedAt, &previous, &next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rental: %w", err)
+		}
+
+		rental.Role = domain.PortalTenant
+		if rental.OwnerID == userID {
+			rental.Role = domain.PortalLandlord
+			rental.Commission = &commission
+		}
+		if previous.Valid {
+			rental.PreviousDealAt = &previous.Time
+		}
+		if next.Valid {
+			rental.EndedAt = &next.Time
+		}
+		rental.Current = rental.EndedAt == nil && rental.PropertyStatus == domain.StatusRented
+		rentals = append(rentals, rental)
+	}
+	return rentals, rows.Err()
+}
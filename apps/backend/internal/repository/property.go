@@ -5,11 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"realty-core/internal/domain"
 
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
 // PropertyRepository defines the data access operations for properties
@@ -20,6 +21,7 @@ type PropertyRepository interface {
 	GetAll() ([]domain.Property, error)
 	Update(property *domain.Property) error
 	Delete(id string) error
+	IncrementViewCount(id string) error
 	GetByProvince(province string) ([]domain.Property, error)
 	GetByPriceRange(minPrice, maxPrice float64) ([]domain.Property, error)
 	// Full-text search methods
@@ -27,6 +29,10 @@ type PropertyRepository interface {
 	SearchPropertiesRanked(query string, limit int) ([]PropertySearchResult, error)
 	GetSearchSuggestions(query string, limit int) ([]SearchSuggestion, error)
 	AdvancedSearch(params AdvancedSearchParams) ([]PropertySearchResult, error)
+	// Combined filter methods
+	GetByFilters(filters *domain.PropertySearchFilters) ([]domain.Property, error)
+	GetByFiltersPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) ([]domain.Property, int, error)
+	StreamByFilters(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error
 	// Pagination methods
 	GetAllPaginated(pagination *domain.PaginationParams) ([]domain.Property, int, error)
 	GetByProvincePaginated(province string, pagination *domain.PaginationParams) ([]domain.Property, int, error)
@@ -34,6 +40,10 @@ type PropertyRepository interface {
 	SearchPropertiesPaginated(query string, pagination *domain.PaginationParams) ([]domain.Property, int, error)
 	SearchPropertiesRankedPaginated(query string, pagination *domain.PaginationParams) ([]PropertySearchResult, int, error)
 	AdvancedSearchPaginated(params AdvancedSearchParams, pagination *domain.PaginationParams) ([]PropertySearchResult, int, error)
+	// Faceted search methods
+	GetSearchFacets(params AdvancedSearchParams) (*domain.SearchFacets, error)
+	// ForTenant returns the repository scoped to a tenant, unscoped when it is empty
+	ForTenant(tenantID string) PropertyRepository
 }
 
 // PropertySearchResult represents a search result with ranking
@@ -65,20 +75,99 @@ type AdvancedSearchParams struct {
 	MaxArea      float64
 	FeaturedOnly bool
 	Limit        int
+	// Locale selects the full-text search configuration, Spanish when empty
+	Locale       string
 }
 
 // PostgreSQLPropertyRepository implements PropertyRepository using PostgreSQL
 type PostgreSQLPropertyRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	counter    *RowCounter
+	statements *statementCache // nil unless prepared statements are enabled
+	tenant     tenantScope     // unscoped unless returned by ForTenant
 }
 
 // NewPostgreSQLPropertyRepository creates a new instance of the repository
 func NewPostgreSQLPropertyRepository(db *sql.DB) *PostgreSQLPropertyRepository {
-	return &PostgreSQLPropertyRepository{db: db}
+	return &PostgreSQLPropertyRepository{db: db, counter: NewRowCounter(db)}
+}
+
+// ForTenant returns the repository scoped to a tenant: reads and writes only reach the
+// properties of the tenant and new properties are created in it. An empty tenant, as in
+// single-tenant installs, returns the repository unscoped.
+func (r *PostgreSQLPropertyRepository) ForTenant(tenantID string) PropertyRepository {
+	if tenantID == "" {
+		return r
+	}
+	scoped := *r
+	scoped.tenant = tenantScope(tenantID)
+	return &scoped
+}
+
+// SetCountPolicies sets how paginated methods count their totals, exactly by default
+func (r *PostgreSQLPropertyRepository) SetCountPolicies(policies map[string]CountPolicy) {
+	r.counter.SetPolicies(policies)
+}
+
+// count counts the total of a page, flagging estimated totals in its pagination
+func (r *PostgreSQLPropertyRepository) count(endpoint string, pagination *domain.PaginationParams, countQuery string, args ...interface{}) (int, error) {
+	total, exact, err := r.counter.Count(endpoint, countQuery, args...)
+	if err != nil {
+		return 0, err
+	}
+	pagination.TotalEstimated = !exact
+	return total, nil
+}
+
+// EnablePreparedStatements runs the hot paths, property lookups and paginated
+// listings, as prepared statements. The lookups are prepared now so a database
+// rejecting them fails at startup. Statements are per connection, so poolers in
+// transaction mode such as PgBouncer need them disabled.
+func (r *PostgreSQLPropertyRepository) EnablePreparedStatements() error {
+	statements := newStatementCache(r.db)
+	for _, query := range []string{propertyByIDQuery, propertyBySlugQuery} {
+		if err := statements.Prepare(query); err != nil {
+			statements.Close()
+			return err
+		}
+	}
+	r.statements = statements
+	return nil
+}
+
+// Close closes the prepared statements of the repository
+func (r *PostgreSQLPropertyRepository) Close() error {
+	if r.statements == nil {
+		return nil
+	}
+	return r.statements.Close()
+}
+
+// query runs a hot query, prepared when prepared statements are enabled
+func (r *PostgreSQLPropertyRepository) query(query string, args ...interface{}) (*sql.Rows, error) {
+	if r.statements != nil {
+		return r.statements.Query(query, args...)
+	}
+	return r.db.Query(query, args...)
+}
+
+// queryRow runs a hot single-row query, prepared when prepared statements are enabled
+func (r *PostgreSQLPropertyRepository) queryRow(query string, args ...interface{}) rowScanner {
+	if r.statements != nil {
+		return r.statements.QueryRow(query, args...)
+	}
+	return r.db.QueryRow(query, args...)
 }
 
 // Create inserts a new property into the database
 func (r *PostgreSQLPropertyRepository) Create(property *domain.Property) error {
+	return r.CreateWithEvents(property)
+}
+
+// CreateWithEvents inserts a new property and its outbox events in one transaction
+func (r *PostgreSQLPropertyRepository) CreateWithEvents(property *domain.Property, events ...*domain.OutboxEvent) error {
+	property.NormalizeDescription()
+
 	// Convert slices to JSON for storage in JSONB
 	imagesJSON, err := json.Marshal(property.Images)
 	if err != nil {
@@ -100,72 +189,58 @@ func (r *PostgreSQLPropertyRepository) Create(property *domain.Property) error {
 			garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
 			tags, featured, view_count, real_estate_company_id,
 			created_at, updated_at, parking_spaces,
-			owner_id, agent_id, agency_id, created_by, updated_by
+			owner_id, agent_id, agency_id, created_by, updated_by, tenant_id,
+			description_html, description_raw
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
 			$18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32,
-			$33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48
+			$33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48, $49,
+			$50, $51
 		)
 	`
 
-	_, err = r.db.Exec(
-		query,
-		property.ID, property.Slug, property.Title, property.Description, property.Price,
-		property.Province, property.City, property.Sector, property.Address,
-		property.Latitude, property.Longitude, property.LocationPrecision,
-		property.Type, property.Status, property.Bedrooms, property.Bathrooms, property.AreaM2,
-		property.MainImage, string(imagesJSON), property.VideoTour, property.Tour360,
-		property.RentPrice, property.CommonExpenses, property.PricePerM2,
-		property.YearBuilt, property.Floors, property.PropertyStatus, property.Furnished,
-		property.Garage, property.Pool, property.Garden, property.Terrace, property.Balcony,
-		property.Security, property.Elevator, property.AirConditioning,
-		string(tagsJSON), property.Featured, property.ViewCount, property.RealEstateCompanyID,
-		property.CreatedAt, property.UpdatedAt, property.ParkingSpaces,
-		property.OwnerID, property.AgentID, property.AgencyID, property.CreatedBy, property.UpdatedBy,
-	)
-
+	tenantID := r.tenant.id(property.TenantID)
+
+	err = execWithOutbox(r.db, events, func(exec execer) error {
+		_, err := exec.Exec(
+			query,
+			property.ID, property.Slug, property.Title, property.Description, property.Price,
+			property.Province, property.City, property.Sector, property.Address,
+			property.Latitude, property.Longitude, property.LocationPrecision,
+			property.Type, property.Status, property.Bedrooms, property.Bathrooms, property.AreaM2,
+			property.MainImage, string(imagesJSON), property.VideoTour, property.Tour360,
+			property.RentPrice, property.CommonExpenses, property.PricePerM2,
+			property.YearBuilt, property.Floors, property.PropertyStatus, property.Furnished,
+			property.Garage, property.Pool, property.Garden, property.Terrace, property.Balcony,
+			property.Security, property.Elevator, property.AirConditioning,
+			string(tagsJSON), property.Featured, property.ViewCount, property.RealEstateCompanyID,
+			property.CreatedAt, property.UpdatedAt, property.ParkingSpaces,
+			property.OwnerID, property.AgentID, property.AgencyID, property.CreatedBy, property.UpdatedBy,
+			tenantID, property.DescriptionHTML, property.DescriptionRaw,
+		)
+		if err != nil {
+			return fmt.Errorf("error creating property: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("error creating property: %w", err)
+		return err
 	}
 
 	log.Printf("Property created successfully: %s", property.ID)
 	return nil
 }
 
+// propertyByIDQuery and propertyBySlugQuery are the lookups of property pages, the
+// hottest queries of the API
+const (
+	propertyByIDQuery   = "SELECT " + propertyColumns + "\n\tFROM properties WHERE id = $1"
+	propertyBySlugQuery = "SELECT " + propertyColumns + "\n\tFROM properties WHERE slug = $1"
+)
+
 // GetByID retrieves a property by its ID
 func (r *PostgreSQLPropertyRepository) GetByID(id string) (*domain.Property, error) {
-	query := `
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE id = $1
-	`
-
-	var property domain.Property
-	var imagesJSON, tagsJSON string
-
-	err := r.db.QueryRow(query, id).Scan(
-		&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-		&property.Province, &property.City, &property.Sector, &property.Address,
-		&property.Latitude, &property.Longitude, &property.LocationPrecision,
-		&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-		&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-		&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-		&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-		&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-		&property.Security, &property.Elevator, &property.AirConditioning,
-		&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-		&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-		&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-	)
-
+	property, err := scanProperty(r.queryRow(propertyByIDQuery+r.tenant.condition(2), r.tenant.args(id)...), true)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("property not found: %s", id)
@@ -173,58 +248,12 @@ func (r *PostgreSQLPropertyRepository) GetByID(id string) (*domain.Property, err
 		return nil, fmt.Errorf("error retrieving property: %w", err)
 	}
 
-	// Convert JSON back to slices
-	if imagesJSON != "" {
-		err = json.Unmarshal([]byte(imagesJSON), &property.Images)
-		if err != nil {
-			return nil, fmt.Errorf("error converting images from JSON: %w", err)
-		}
-	}
-
-	if tagsJSON != "" {
-		err = json.Unmarshal([]byte(tagsJSON), &property.Tags)
-		if err != nil {
-			return nil, fmt.Errorf("error converting tags from JSON: %w", err)
-		}
-	}
-
-	return &property, nil
+	return property, nil
 }
 
 // GetBySlug retrieves a property by its SEO slug
 func (r *PostgreSQLPropertyRepository) GetBySlug(slug string) (*domain.Property, error) {
-	query := `
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE slug = $1
-	`
-
-	var property domain.Property
-	var imagesJSON, tagsJSON string
-
-	err := r.db.QueryRow(query, slug).Scan(
-		&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-		&property.Province, &property.City, &property.Sector, &property.Address,
-		&property.Latitude, &property.Longitude, &property.LocationPrecision,
-		&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-		&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-		&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-		&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-		&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-		&property.Security, &property.Elevator, &property.AirConditioning,
-		&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-		&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-		&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-	)
-
+	property, err := scanProperty(r.queryRow(propertyBySlugQuery+r.tenant.condition(2), r.tenant.args(slug)...), true)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("property not found with slug: %s", slug)
@@ -232,99 +261,35 @@ func (r *PostgreSQLPropertyRepository) GetBySlug(slug string) (*domain.Property,
 		return nil, fmt.Errorf("error retrieving property by slug: %w", err)
 	}
 
-	// Convert JSON back to slices
-	if imagesJSON != "" {
-		err = json.Unmarshal([]byte(imagesJSON), &property.Images)
-		if err != nil {
-			return nil, fmt.Errorf("error converting images from JSON: %w", err)
-		}
-	}
-
-	if tagsJSON != "" {
-		err = json.Unmarshal([]byte(tagsJSON), &property.Tags)
-		if err != nil {
-			return nil, fmt.Errorf("error converting tags from JSON: %w", err)
-		}
-	}
-
-	return &property, nil
+	return property, nil
 }
 
 // GetAll returns all properties (with pagination in a real implementation)
 func (r *PostgreSQLPropertyRepository) GetAll() ([]domain.Property, error) {
-	query := `
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		ORDER BY featured DESC, created_at DESC
-	`
+	query, args := r.tenant.scope(selectFrom(propertyColumns, "properties")).
+		OrderBy("featured DESC").OrderBy("created_at DESC").
+		SQL()
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying properties: %w", err)
 	}
 	defer rows.Close()
 
-	var properties []domain.Property
-
-	for rows.Next() {
-		var property domain.Property
-		var imagesJSON, tagsJSON string
-
-		err := rows.Scan(
-			&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-			&property.Province, &property.City, &property.Sector, &property.Address,
-			&property.Latitude, &property.Longitude, &property.LocationPrecision,
-			&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-			&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-			&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-			&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-			&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-			&property.Security, &property.Elevator, &property.AirConditioning,
-			&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-			&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-			&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning property: %w", err)
-		}
-
-		// Convert JSON back to slices
-		if imagesJSON != "" {
-			err = json.Unmarshal([]byte(imagesJSON), &property.Images)
-			if err != nil {
-				property.Images = []string{} // Continue with empty slice if JSON is invalid
-			}
-		}
-
-		if tagsJSON != "" {
-			err = json.Unmarshal([]byte(tagsJSON), &property.Tags)
-			if err != nil {
-				property.Tags = []string{} // Continue with empty slice if JSON is invalid
-			}
-		}
-
-		properties = append(properties, property)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating properties: %w", err)
-	}
-
-	return properties, nil
+	return r.scanProperties(rows)
 }
 
 // Update modifies an existing property
 func (r *PostgreSQLPropertyRepository) Update(property *domain.Property) error {
+	return r.UpdateWithEvents(property)
+}
+
+// UpdateWithEvents modifies an existing property and stores its outbox events in one
+// transaction
+func (r *PostgreSQLPropertyRepository) UpdateWithEvents(property *domain.Property, events ...*domain.OutboxEvent) error {
 	property.UpdateTimestamp()
 	property.UpdateSlug()
+	property.NormalizeDescription()
 
 	// Convert slices to JSON
 	imagesJSON, err := json.Marshal(property.Images)
@@ -347,39 +312,46 @@ func (r *PostgreSQLPropertyRepository) Update(property *domain.Property) error {
 			year_built = $25, floors = $26, property_status = $27, furnished = $28,
 			garage = $29, pool = $30, garden = $31, terrace = $32, balcony = $33,
 			security = $34, elevator = $35, air_conditioning = $36,
-			tags = $37, featured = $38, view_count = $39, real_estate_company_id = $40,
-			updated_at = $41, parking_spaces = $42,
-			owner_id = $43, agent_id = $44, agency_id = $45, created_by = $46, updated_by = $47
-		WHERE id = $1
+			tags = $37, featured = $38, real_estate_company_id = $39,
+			updated_at = $40, parking_spaces = $41,
+			owner_id = $42, agent_id = $43, agency_id = $44, created_by = $45, updated_by = $46,
+			description_html = $47, description_raw = $48
+		WHERE id = $1` + r.tenant.condition(49) + `
 	`
 
-	result, err := r.db.Exec(
-		query,
-		property.ID, property.Slug, property.Title, property.Description, property.Price,
-		property.Province, property.City, property.Sector, property.Address,
-		property.Latitude, property.Longitude, property.LocationPrecision,
-		property.Type, property.Status, property.Bedrooms, property.Bathrooms, property.AreaM2,
-		property.MainImage, string(imagesJSON), property.VideoTour, property.Tour360,
-		property.RentPrice, property.CommonExpenses, property.PricePerM2,
-		property.YearBuilt, property.Floors, property.PropertyStatus, property.Furnished,
-		property.Garage, property.Pool, property.Garden, property.Terrace, property.Balcony,
-		property.Security, property.Elevator, property.AirConditioning,
-		string(tagsJSON), property.Featured, property.ViewCount, property.RealEstateCompanyID,
-		property.UpdatedAt, property.ParkingSpaces,
-		property.OwnerID, property.AgentID, property.AgencyID, property.CreatedBy, property.UpdatedBy,
-	)
+	err = execWithOutbox(r.db, events, func(exec execer) error {
+		result, err := exec.Exec(
+			query,
+			r.tenant.args(property.ID, property.Slug, property.Title, property.Description, property.Price,
+			property.Province, property.City, property.Sector, property.Address,
+			property.Latitude, property.Longitude, property.LocationPrecision,
+			property.Type, property.Status, property.Bedrooms, property.Bathrooms, property.AreaM2,
+			property.MainImage, string(imagesJSON), property.VideoTour, property.Tour360,
+			property.RentPrice, property.CommonExpenses, property.PricePerM2,
+			property.YearBuilt, property.Floors, property.PropertyStatus, property.Furnished,
+			property.Garage, property.Pool, property.Garden, property.Terrace, property.Balcony,
+			property.Security, property.Elevator, property.AirConditioning,
+			string(tagsJSON), property.Featured, property.RealEstateCompanyID,
+			property.UpdatedAt, property.ParkingSpaces,
+			property.OwnerID, property.AgentID, property.AgencyID, property.CreatedBy, property.UpdatedBy,
+			property.DescriptionHTML, property.DescriptionRaw)...,
+		)
+		if err != nil {
+			return fmt.Errorf("error updating property: %w", err)
+		}
 
-	if err != nil {
-		return fmt.Errorf("error updating property: %w", err)
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking update result: %w", err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
+		if rowsAffected == 0 {
+			return fmt.Errorf("property not found: %s", property.ID)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("error checking update result: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("property not found: %s", property.ID)
+		return err
 	}
 
 	log.Printf("Property updated successfully: %s", property.ID)
@@ -388,9 +360,9 @@ func (r *PostgreSQLPropertyRepository) Update(property *domain.Property) error {
 
 // Delete removes a property from the database
 func (r *PostgreSQLPropertyRepository) Delete(id string) error {
-	query := `DELETE FROM properties WHERE id = $1`
+	query := `DELETE FROM properties WHERE id = $1` + r.tenant.condition(2)
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.Exec(query, r.tenant.args(id)...)
 	if err != nil {
 		return fmt.Errorf("error deleting property: %w", err)
 	}
@@ -408,126 +380,61 @@ func (r *PostgreSQLPropertyRepository) Delete(id string) error {
 	return nil
 }
 
-// GetByProvince filters properties by province
-func (r *PostgreSQLPropertyRepository) GetByProvince(province string) ([]domain.Property, error) {
-	query := `
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE province = $1
-		ORDER BY featured DESC, created_at DESC
-	`
+// IncrementViewCount counts a view of a property in place, so reads neither rewrite the
+// row nor race with concurrent edits, and leave updated_at alone
+func (r *PostgreSQLPropertyRepository) IncrementViewCount(id string) error {
+	query := `UPDATE properties SET view_count = view_count + 1 WHERE id = $1` + r.tenant.condition(2)
 
-	rows, err := r.db.Query(query, province)
+	result, err := r.db.Exec(query, r.tenant.args(id)...)
 	if err != nil {
-		return nil, fmt.Errorf("error querying properties by province: %w", err)
+		return fmt.Errorf("error incrementing view count: %w", err)
 	}
-	defer rows.Close()
 
-	var properties []domain.Property
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking view count result: %w", err)
+	}
 
-	for rows.Next() {
-		var property domain.Property
-		var imagesJSON, tagsJSON string
+	if rowsAffected == 0 {
+		return fmt.Errorf("property not found: %s", id)
+	}
 
-		err := rows.Scan(
-			&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-			&property.Province, &property.City, &property.Sector, &property.Address,
-			&property.Latitude, &property.Longitude, &property.LocationPrecision,
-			&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-			&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-			&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-			&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-			&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-			&property.Security, &property.Elevator, &property.AirConditioning,
-			&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-			&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-			&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning property: %w", err)
-		}
+	return nil
+}
 
-		// Convert JSON back to slices
-		if imagesJSON != "" {
-			json.Unmarshal([]byte(imagesJSON), &property.Images)
-		}
-		if tagsJSON != "" {
-			json.Unmarshal([]byte(tagsJSON), &property.Tags)
-		}
+// GetByProvince filters properties by province
+func (r *PostgreSQLPropertyRepository) GetByProvince(province string) ([]domain.Property, error) {
+	query, args := r.tenant.scope(selectFrom(propertyColumns, "properties")).
+		Where("province = ?", province).
+		Where(publicStatusCondition).
+		OrderBy("featured DESC").OrderBy("created_at DESC").
+		SQL()
 
-		properties = append(properties, property)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying properties by province: %w", err)
 	}
+	defer rows.Close()
 
-	return properties, nil
+	return r.scanProperties(rows)
 }
 
 // GetByPriceRange filters properties by price range
 func (r *PostgreSQLPropertyRepository) GetByPriceRange(minPrice, maxPrice float64) ([]domain.Property, error) {
-	query := `
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE price >= $1 AND price <= $2
-		ORDER BY featured DESC, created_at DESC
-	`
-
-	rows, err := r.db.Query(query, minPrice, maxPrice)
+	query, args := r.tenant.scope(selectFrom(propertyColumns, "properties")).
+		Where("price >= ?", minPrice).
+		Where("price <= ?", maxPrice).
+		Where(publicStatusCondition).
+		OrderBy("featured DESC").OrderBy("created_at DESC").
+		SQL()
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying properties by price range: %w", err)
 	}
 	defer rows.Close()
 
-	var properties []domain.Property
-
-	for rows.Next() {
-		var property domain.Property
-		var imagesJSON, tagsJSON string
-
-		err := rows.Scan(
-			&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-			&property.Province, &property.City, &property.Sector, &property.Address,
-			&property.Latitude, &property.Longitude, &property.LocationPrecision,
-			&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-			&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-			&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-			&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-			&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-			&property.Security, &property.Elevator, &property.AirConditioning,
-			&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-			&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-			&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning property: %w", err)
-		}
-
-		// Convert JSON back to slices
-		if imagesJSON != "" {
-			json.Unmarshal([]byte(imagesJSON), &property.Images)
-		}
-		if tagsJSON != "" {
-			json.Unmarshal([]byte(tagsJSON), &property.Tags)
-		}
-
-		properties = append(properties, property)
-	}
-
-	return properties, nil
+	return r.scanProperties(rows)
 }
 
 // SearchProperties performs basic full-text search
@@ -537,17 +444,9 @@ func (r *PostgreSQLPropertyRepository) SearchProperties(query string, limit int)
 	}
 	
 	sqlQuery := `
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
+		SELECT ` + propertyColumns + `
 		FROM properties 
-		WHERE search_vector @@ plainto_tsquery('spanish', $1)
+		WHERE search_vector @@ plainto_tsquery('spanish', $1) AND status NOT IN ('expired', 'quarantined')` + r.tenant.condition(3) + `
 		ORDER BY 
 			ts_rank_cd(search_vector, plainto_tsquery('spanish', $1)) DESC,
 			featured DESC,
@@ -555,47 +454,13 @@ func (r *PostgreSQLPropertyRepository) SearchProperties(query string, limit int)
 		LIMIT $2
 	`
 
-	rows, err := r.db.Query(sqlQuery, query, limit)
+	rows, err := r.db.Query(sqlQuery, r.tenant.args(query, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("error performing full-text search: %w", err)
 	}
 	defer rows.Close()
 
-	var properties []domain.Property
-	for rows.Next() {
-		var property domain.Property
-		var imagesJSON, tagsJSON string
-
-		err := rows.Scan(
-			&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-			&property.Province, &property.City, &property.Sector, &property.Address,
-			&property.Latitude, &property.Longitude, &property.LocationPrecision,
-			&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-			&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-			&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-			&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-			&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-			&property.Security, &property.Elevator, &property.AirConditioning,
-			&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-			&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-			&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning search result: %w", err)
-		}
-
-		// Convert JSON back to slices
-		if imagesJSON != "" {
-			json.Unmarshal([]byte(imagesJSON), &property.Images)
-		}
-		if tagsJSON != "" {
-			json.Unmarshal([]byte(tagsJSON), &property.Tags)
-		}
-
-		properties = append(properties, property)
-	}
-
-	return properties, nil
+	return r.scanProperties(rows)
 }
 
 // SearchPropertiesRanked performs full-text search with ranking scores
@@ -608,7 +473,7 @@ func (r *PostgreSQLPropertyRepository) SearchPropertiesRanked(query string, limi
 		SELECT id, slug, title, description, price, province, city, type,
 			   ts_rank_cd(search_vector, plainto_tsquery('spanish', $1)) as rank
 		FROM properties 
-		WHERE search_vector @@ plainto_tsquery('spanish', $1)
+		WHERE search_vector @@ plainto_tsquery('spanish', $1) AND status NOT IN ('expired', 'quarantined')` + r.tenant.condition(3) + `
 		ORDER BY 
 			ts_rank_cd(search_vector, plainto_tsquery('spanish', $1)) DESC,
 			featured DESC,
@@ -616,7 +481,7 @@ func (r *PostgreSQLPropertyRepository) SearchPropertiesRanked(query string, limi
 		LIMIT $2
 	`
 
-	rows, err := r.db.Query(sqlQuery, query, limit)
+	rows, err := r.db.Query(sqlQuery, r.tenant.args(query, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("error performing ranked search: %w", err)
 	}
@@ -656,8 +521,27 @@ func (r *PostgreSQLPropertyRepository) GetSearchSuggestions(query string, limit
 	sqlQuery := `
 		SELECT * FROM get_search_suggestions($1, $2)
 	`
+	// get_search_suggestions reads every tenant; scoped repositories suggest the cities
+	// and provinces of their tenant inline
+	if r.tenant != "" {
+		sqlQuery = `
+		SELECT text, category, frequency FROM (
+			SELECT city AS text, '` + SuggestionCategoryCity + `' AS category, COUNT(*) AS frequency
+			FROM properties
+			WHERE city ILIKE $1 || '%' AND status NOT IN ('expired', 'quarantined')` + r.tenant.condition(3) + `
+			GROUP BY city
+			UNION ALL
+			SELECT province, '` + SuggestionCategoryProvince + `', COUNT(*)
+			FROM properties
+			WHERE province ILIKE $1 || '%' AND status NOT IN ('expired', 'quarantined')` + r.tenant.condition(3) + `
+			GROUP BY province
+		) suggestions
+		ORDER BY frequency DESC, text
+		LIMIT $2
+	`
+	}
 
-	rows, err := r.db.Query(sqlQuery, query, limit)
+	rows, err := r.db.Query(sqlQuery, r.tenant.args(query, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("error getting search suggestions: %w", err)
 	}
@@ -696,16 +580,29 @@ func (r *PostgreSQLPropertyRepository) AdvancedSearch(params AdvancedSearchParam
 
 	sqlQuery := `
 		SELECT * FROM advanced_search_properties($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		WHERE id NOT IN (SELECT id FROM properties WHERE status IN ('expired', 'quarantined'))
+	`
+	// advanced_search_properties only knows Spanish and reads every tenant; other locales
+	// and scoped repositories filter inline
+	if vector, config := searchVectorColumns(params.Locale); vector != "search_vector" || r.tenant != "" {
+		sqlQuery = `
+		SELECT id, slug, title, description, price, province, city, type,
+			   bedrooms, bathrooms, area_m2, featured,
+			   CASE WHEN $1 = '' THEN 0 ELSE ts_rank_cd(` + vector + `, plainto_tsquery('` + config + `', $1)) END as rank
+		FROM properties ` + localizedSearchWhere(params.Locale) + r.tenant.condition(15) + `
+		ORDER BY rank DESC, featured DESC, created_at DESC
+		LIMIT $14
 	`
+	}
 
 	rows, err := r.db.Query(
 		sqlQuery,
-		params.Query, params.Province, params.City, params.Type,
-		params.MinPrice, params.MaxPrice,
-		params.MinBedrooms, params.MaxBedrooms,
-		params.MinBathrooms, params.MaxBathrooms,
-		params.MinArea, params.MaxArea,
-		params.FeaturedOnly, params.Limit,
+		r.tenant.args(params.Query, params.Province, params.City, params.Type,
+			params.MinPrice, params.MaxPrice,
+			params.MinBedrooms, params.MaxBedrooms,
+			params.MinBathrooms, params.MaxBathrooms,
+			params.MinArea, params.MaxArea,
+			params.FeaturedOnly, params.Limit)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error performing advanced search: %w", err)
@@ -742,31 +639,18 @@ func (r *PostgreSQLPropertyRepository) AdvancedSearch(params AdvancedSearchParam
 
 // GetAllPaginated returns paginated properties with total count
 func (r *PostgreSQLPropertyRepository) GetAllPaginated(pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	q := r.tenant.scope(selectFrom(propertyColumns, "properties"))
+
 	// Get total count
-	countQuery := "SELECT COUNT(*) FROM properties"
-	var totalCount int
-	err := r.db.QueryRow(countQuery).Scan(&totalCount)
+	countQuery, countArgs := q.CountSQL()
+	totalCount, err := r.count(CountEndpointList, pagination, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting properties: %w", err)
 	}
 
 	// Get paginated data
-	query := fmt.Sprintf(`
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		ORDER BY %s
-		LIMIT $1 OFFSET $2
-	`, pagination.GetOrderBy())
-
-	rows, err := r.db.Query(query, pagination.GetLimit(), pagination.GetOffset())
+	query, args := q.OrderBySort(pagination).Paginate(pagination).SQL()
+	rows, err := r.query(query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying paginated properties: %w", err)
 	}
@@ -782,32 +666,20 @@ func (r *PostgreSQLPropertyRepository) GetAllPaginated(pagination *domain.Pagina
 
 // GetByProvincePaginated returns paginated properties filtered by province
 func (r *PostgreSQLPropertyRepository) GetByProvincePaginated(province string, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	q := r.tenant.scope(selectFrom(propertyColumns, "properties")).
+		Where("province = ?", province).
+		Where(publicStatusCondition)
+
 	// Get total count
-	countQuery := "SELECT COUNT(*) FROM properties WHERE province = $1"
-	var totalCount int
-	err := r.db.QueryRow(countQuery, province).Scan(&totalCount)
+	countQuery, countArgs := q.CountSQL()
+	totalCount, err := r.count(CountEndpointProvince, pagination, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting properties by province: %w", err)
 	}
 
 	// Get paginated data
-	query := fmt.Sprintf(`
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE province = $1
-		ORDER BY %s
-		LIMIT $2 OFFSET $3
-	`, pagination.GetOrderBy())
-
-	rows, err := r.db.Query(query, province, pagination.GetLimit(), pagination.GetOffset())
+	query, args := q.OrderBySort(pagination).Paginate(pagination).SQL()
+	rows, err := r.query(query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying paginated properties by province: %w", err)
 	}
@@ -823,32 +695,21 @@ func (r *PostgreSQLPropertyRepository) GetByProvincePaginated(province string, p
 
 // GetByPriceRangePaginated returns paginated properties filtered by price range
 func (r *PostgreSQLPropertyRepository) GetByPriceRangePaginated(minPrice, maxPrice float64, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	q := r.tenant.scope(selectFrom(propertyColumns, "properties")).
+		Where("price >= ?", minPrice).
+		Where("price <= ?", maxPrice).
+		Where(publicStatusCondition)
+
 	// Get total count
-	countQuery := "SELECT COUNT(*) FROM properties WHERE price >= $1 AND price <= $2"
-	var totalCount int
-	err := r.db.QueryRow(countQuery, minPrice, maxPrice).Scan(&totalCount)
+	countQuery, countArgs := q.CountSQL()
+	totalCount, err := r.count(CountEndpointPriceRange, pagination, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting properties by price range: %w", err)
 	}
 
 	// Get paginated data
-	query := fmt.Sprintf(`
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE price >= $1 AND price <= $2
-		ORDER BY %s
-		LIMIT $3 OFFSET $4
-	`, pagination.GetOrderBy())
-
-	rows, err := r.db.Query(query, minPrice, maxPrice, pagination.GetLimit(), pagination.GetOffset())
+	query, args := q.OrderBySort(pagination).Paginate(pagination).SQL()
+	rows, err := r.query(query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error querying paginated properties by price range: %w", err)
 	}
@@ -864,34 +725,23 @@ func (r *PostgreSQLPropertyRepository) GetByPriceRangePaginated(minPrice, maxPri
 
 // SearchPropertiesPaginated performs paginated full-text search
 func (r *PostgreSQLPropertyRepository) SearchPropertiesPaginated(query string, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	q := r.tenant.scope(selectFrom(propertyColumns, "properties")).
+		Where("search_vector @@ plainto_tsquery('spanish', ?)", query).
+		Where(publicStatusCondition)
+
 	// Get total count
-	countQuery := "SELECT COUNT(*) FROM properties WHERE search_vector @@ plainto_tsquery('spanish', $1)"
-	var totalCount int
-	err := r.db.QueryRow(countQuery, query).Scan(&totalCount)
+	countQuery, countArgs := q.CountSQL()
+	totalCount, err := r.count(CountEndpointSearch, pagination, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting search results: %w", err)
 	}
 
 	// Get paginated data with FTS ranking
-	sqlQuery := fmt.Sprintf(`
-		SELECT id, slug, title, description, price, province, city, sector, address,
-			   latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
-			   main_image, images, video_tour, tour_360,
-			   rent_price, common_expenses, price_per_m2,
-			   year_built, floors, property_status, furnished,
-			   garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
-			   tags, featured, view_count, real_estate_company_id,
-			   created_at, updated_at, parking_spaces,
-			   owner_id, agent_id, agency_id, created_by, updated_by
-		FROM properties 
-		WHERE search_vector @@ plainto_tsquery('spanish', $1)
-		ORDER BY 
-			ts_rank_cd(search_vector, plainto_tsquery('spanish', $1)) DESC,
-			%s
-		LIMIT $2 OFFSET $3
-	`, pagination.GetOrderBy())
-
-	rows, err := r.db.Query(sqlQuery, query, pagination.GetLimit(), pagination.GetOffset())
+	sqlQuery, args := q.OrderBy("ts_rank_cd(search_vector, plainto_tsquery('spanish', ?)) DESC", query).
+		OrderBySort(pagination).
+		Paginate(pagination).
+		SQL()
+	rows, err := r.query(sqlQuery, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error performing paginated search: %w", err)
 	}
@@ -908,9 +758,9 @@ func (r *PostgreSQLPropertyRepository) SearchPropertiesPaginated(query string, p
 // SearchPropertiesRankedPaginated performs paginated full-text search with ranking
 func (r *PostgreSQLPropertyRepository) SearchPropertiesRankedPaginated(query string, pagination *domain.PaginationParams) ([]PropertySearchResult, int, error) {
 	// Get total count
-	countQuery := "SELECT COUNT(*) FROM properties WHERE search_vector @@ plainto_tsquery('spanish', $1)"
-	var totalCount int
-	err := r.db.QueryRow(countQuery, query).Scan(&totalCount)
+	countQuery := "SELECT COUNT(*) FROM properties WHERE search_vector @@ plainto_tsquery('spanish', $1) AND status NOT IN ('expired', 'quarantined')" +
+		r.tenant.condition(2)
+	totalCount, err := r.count(CountEndpointSearchRanked, pagination, countQuery, r.tenant.args(query)...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting ranked search results: %w", err)
 	}
@@ -920,7 +770,7 @@ func (r *PostgreSQLPropertyRepository) SearchPropertiesRankedPaginated(query str
 		SELECT id, slug, title, description, price, province, city, type,
 			   ts_rank_cd(search_vector, plainto_tsquery('spanish', $1)) as rank
 		FROM properties 
-		WHERE search_vector @@ plainto_tsquery('spanish', $1)
+		WHERE search_vector @@ plainto_tsquery('spanish', $1) AND status NOT IN ('expired', 'quarantined')` + r.tenant.condition(4) + `
 		ORDER BY 
 			ts_rank_cd(search_vector, plainto_tsquery('spanish', $1)) DESC,
 			featured DESC,
@@ -928,7 +778,7 @@ func (r *PostgreSQLPropertyRepository) SearchPropertiesRankedPaginated(query str
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(sqlQuery, query, pagination.GetLimit(), pagination.GetOffset())
+	rows, err := r.db.Query(sqlQuery, r.tenant.args(query, pagination.GetLimit(), pagination.GetOffset())...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error performing paginated ranked search: %w", err)
 	}
@@ -968,8 +818,40 @@ func (r *PostgreSQLPropertyRepository) AdvancedSearchPaginated(params AdvancedSe
 	}
 	
 	// Get total count using a simpler query
-	countQuery := `
-		SELECT COUNT(*) FROM properties 
+	countQuery := `SELECT COUNT(*) FROM properties ` + localizedSearchWhere(params.Locale) + r.tenant.condition(14)
+
+	totalCount, err := r.count(CountEndpointAdvanced, pagination, countQuery, r.tenant.args(advancedSearchArgs(params)...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting advanced search results: %w", err)
+	}
+
+	// Use existing advanced search with offset simulation
+	offset := pagination.GetOffset()
+	adjustedParams := params
+	adjustedParams.Limit = pagination.GetLimit() + offset
+	
+	allResults, err := r.AdvancedSearch(adjustedParams)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	// Apply offset manually
+	if offset >= len(allResults) {
+		return []PropertySearchResult{}, totalCount, nil
+	}
+	
+	end := offset + pagination.GetLimit()
+	if end > len(allResults) {
+		end = len(allResults)
+	}
+	
+	results := allResults[offset:end]
+	return results, totalCount, nil
+}
+
+// advancedSearchWhere filters properties with the same criteria as advanced_search_properties.
+// Arguments are built by advancedSearchArgs.
+const advancedSearchWhere = `
 		WHERE ($1 = '' OR search_vector @@ plainto_tsquery('spanish', $1))
 		AND ($2 = '' OR province = $2)
 		AND ($3 = '' OR city = $3)
@@ -979,8 +861,31 @@ func (r *PostgreSQLPropertyRepository) AdvancedSearchPaginated(params AdvancedSe
 		AND bathrooms >= $9 AND bathrooms <= $10
 		AND area_m2 >= $11 AND area_m2 <= $12
 		AND ($13 = false OR featured = true)
+		AND status NOT IN ('expired', 'quarantined')
 	`
-	
+
+// searchVectorColumns returns the search vector column and text search configuration of a locale
+func searchVectorColumns(locale string) (vector, config string) {
+	if domain.NormalizeLocale(locale) == domain.LocaleEnglish {
+		return "search_vector_en", domain.TextSearchConfig(locale)
+	}
+	return "search_vector", domain.TextSearchConfig(domain.DefaultLocale)
+}
+
+// localizedSearchWhere returns advancedSearchWhere matching the query against the
+// search vector of a locale
+func localizedSearchWhere(locale string) string {
+	vector, config := searchVectorColumns(locale)
+	if vector == "search_vector" {
+		return advancedSearchWhere
+	}
+	return strings.Replace(advancedSearchWhere,
+		"search_vector @@ plainto_tsquery('spanish', $1)",
+		vector+" @@ plainto_tsquery('"+config+"', $1)", 1)
+}
+
+// advancedSearchArgs returns the arguments for advancedSearchWhere, replacing unset maximums
+func advancedSearchArgs(params AdvancedSearchParams) []interface{} {
 	maxPrice := params.MaxPrice
 	if maxPrice == 0 {
 		maxPrice = 999999999
@@ -997,41 +902,282 @@ func (r *PostgreSQLPropertyRepository) AdvancedSearchPaginated(params AdvancedSe
 	if maxArea == 0 {
 		maxArea = 999999
 	}
-	
-	var totalCount int
-	err := r.db.QueryRow(countQuery, 
+
+	return []interface{}{
 		params.Query, params.Province, params.City, params.Type,
 		params.MinPrice, maxPrice,
 		params.MinBedrooms, maxBedrooms,
 		params.MinBathrooms, maxBathrooms,
 		params.MinArea, maxArea,
-		params.FeaturedOnly).Scan(&totalCount)
+		params.FeaturedOnly,
+	}
+}
+
+// GetSearchFacets returns facet counts for the properties matching the advanced search
+// parameters. The matching rows are read once and every facet is aggregated from them.
+func (r *PostgreSQLPropertyRepository) GetSearchFacets(params AdvancedSearchParams) (*domain.SearchFacets, error) {
+	query := `
+		WITH filtered AS MATERIALIZED (
+			SELECT province, city, type, bedrooms, price,
+				   pool, garden, terrace, balcony, security, elevator, air_conditioning, garage, furnished
+			FROM properties
+			` + localizedSearchWhere(params.Locale) + r.tenant.condition(14) + `
+		)
+		SELECT 'province', province, COUNT(*) FROM filtered GROUP BY province
+		UNION ALL
+		SELECT 'city', city, COUNT(*) FROM filtered GROUP BY city
+		UNION ALL
+		SELECT 'type', type, COUNT(*) FROM filtered GROUP BY type
+		UNION ALL
+		SELECT 'bedrooms', CASE WHEN bedrooms >= 5 THEN '5+' ELSE bedrooms::text END, COUNT(*)
+		FROM filtered GROUP BY 2
+		UNION ALL
+		SELECT 'price',
+			CASE
+				WHEN price < 50000 THEN '0-50000'
+				WHEN price < 100000 THEN '50000-100000'
+				WHEN price < 200000 THEN '100000-200000'
+				WHEN price < 500000 THEN '200000-500000'
+				ELSE '500000+'
+			END, COUNT(*)
+		FROM filtered GROUP BY 2
+		UNION ALL
+		SELECT 'amenity', amenity.name, COUNT(*)
+		FROM filtered,
+			LATERAL (VALUES
+				('pool', pool), ('garden', garden), ('terrace', terrace), ('balcony', balcony),
+				('security', security), ('elevator', elevator), ('air_conditioning', air_conditioning),
+				('garage', garage), ('furnished', furnished)
+			) AS amenity(name, present)
+		WHERE amenity.present
+		GROUP BY amenity.name
+	`
+
+	rows, err := r.db.Query(query, r.tenant.args(advancedSearchArgs(params)...)...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error counting advanced search results: %w", err)
+		return nil, fmt.Errorf("error computing search facets: %w", err)
 	}
+	defer rows.Close()
 
-	// Use existing advanced search with offset simulation
-	offset := pagination.GetOffset()
-	adjustedParams := params
-	adjustedParams.Limit = pagination.GetLimit() + offset
-	
-	allResults, err := r.AdvancedSearch(adjustedParams)
+	facets := domain.NewSearchFacets()
+	for rows.Next() {
+		var facet, value string
+		var count int
+		if err := rows.Scan(&facet, &value, &count); err != nil {
+			return nil, fmt.Errorf("error scanning search facet: %w", err)
+		}
+		facets.Add(facet, value, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading search facets: %w", err)
+	}
+
+	facets.Sort()
+	return facets, nil
+}
+
+// GetByFilters returns properties matching every provided filter
+func (r *PostgreSQLPropertyRepository) GetByFilters(filters *domain.PropertySearchFilters) ([]domain.Property, error) {
+	query, args := whereFilters(r.tenant.scope(selectFrom(propertyColumns, "properties")), filters).
+		OrderBy("featured DESC").OrderBy("created_at DESC").
+		SQL()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying properties by filters: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanProperties(rows)
+}
+
+// StreamByFilters calls fn with each property matching the filters, in the order of
+// GetByFilters, as rows are scanned rather than after loading them all. Streaming stops
+// at the first error fn returns.
+func (r *PostgreSQLPropertyRepository) StreamByFilters(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error {
+	query, args := whereFilters(r.tenant.scope(selectFrom(propertyColumns, "properties")), filters).
+		OrderBy("featured DESC").OrderBy("created_at DESC").
+		SQL()
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("error querying properties by filters: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		property, err := scanProperty(rows, false)
+		if err != nil {
+			return fmt.Errorf("error scanning property: %w", err)
+		}
+		if err := fn(property); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating properties: %w", err)
+	}
+	return nil
+}
+
+// GetByFiltersPaginated returns a page of properties matching every provided filter
+func (r *PostgreSQLPropertyRepository) GetByFiltersPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	q := whereFilters(r.tenant.scope(selectFrom(propertyColumns, "properties")), filters)
+
+	// Get total count
+	countQuery, countArgs := q.CountSQL()
+	totalCount, err := r.count(CountEndpointFilter, pagination, countQuery, countArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting properties by filters: %w", err)
+	}
+
+	// Get paginated data
+	query, args := q.OrderBySort(pagination).Paginate(pagination).SQL()
+	rows, err := r.query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error querying paginated properties by filters: %w", err)
+	}
+	defer rows.Close()
+
+	properties, err := r.scanProperties(rows)
 	if err != nil {
 		return nil, 0, err
 	}
-	
-	// Apply offset manually
-	if offset >= len(allResults) {
-		return []PropertySearchResult{}, totalCount, nil
+
+	return properties, totalCount, nil
+}
+
+// publicStatusCondition hides listings that are no longer public
+const publicStatusCondition = "status NOT IN ('expired', 'quarantined')"
+
+// buildFilterConditions builds a parameterized WHERE clause from the filters
+func buildFilterConditions(filters *domain.PropertySearchFilters) (string, []interface{}) {
+	return whereFilters(selectFrom(propertyColumns, "properties"), filters).WhereSQL()
+}
+
+// whereFilters adds a condition for every provided filter.
+// Each condition targets an indexed column so the planner can combine them.
+func whereFilters(q *selectQuery, filters *domain.PropertySearchFilters) *selectQuery {
+	if filters == nil {
+		return q
 	}
-	
-	end := offset + pagination.GetLimit()
-	if end > len(allResults) {
-		end = len(allResults)
+
+	if filters.Query != "" {
+		q.Where("search_vector @@ plainto_tsquery('spanish', ?)", filters.Query)
 	}
-	
-	results := allResults[offset:end]
-	return results, totalCount, nil
+	if len(filters.Provinces) > 0 {
+		q.Where("province = ANY(?)", pq.Array(filters.Provinces))
+	}
+	if len(filters.Cities) > 0 {
+		q.Where("city = ANY(?)", pq.Array(filters.Cities))
+	}
+	if len(filters.Sectors) > 0 {
+		q.Where("sector = ANY(?)", pq.Array(filters.Sectors))
+	}
+	if len(filters.PropertyTypes) > 0 {
+		q.Where("type = ANY(?)", pq.Array(filters.PropertyTypes))
+	}
+	if len(filters.Status) > 0 {
+		q.Where("status = ANY(?)", pq.Array(filters.Status))
+	}
+	if filters.MinPrice != nil {
+		q.Where("price >= ?", *filters.MinPrice)
+	}
+	if filters.MaxPrice != nil {
+		q.Where("price <= ?", *filters.MaxPrice)
+	}
+	if filters.MinBedrooms != nil {
+		q.Where("bedrooms >= ?", *filters.MinBedrooms)
+	}
+	if filters.MaxBedrooms != nil {
+		q.Where("bedrooms <= ?", *filters.MaxBedrooms)
+	}
+	if filters.MinBathrooms != nil {
+		q.Where("bathrooms >= ?", *filters.MinBathrooms)
+	}
+	if filters.MaxBathrooms != nil {
+		q.Where("bathrooms <= ?", *filters.MaxBathrooms)
+	}
+	if filters.MinArea != nil {
+		q.Where("area_m2 >= ?", *filters.MinArea)
+	}
+	if filters.MaxArea != nil {
+		q.Where("area_m2 <= ?", *filters.MaxArea)
+	}
+	if filters.MinParkingSpaces != nil {
+		q.Where("parking_spaces >= ?", *filters.MinParkingSpaces)
+	}
+	if filters.Featured != nil {
+		q.Where("featured = ?", *filters.Featured)
+	}
+
+	// Amenity flags
+	if filters.HasPool != nil {
+		q.Where("pool = ?", *filters.HasPool)
+	}
+	if filters.HasGarden != nil {
+		q.Where("garden = ?", *filters.HasGarden)
+	}
+	if filters.HasTerrace != nil {
+		q.Where("terrace = ?", *filters.HasTerrace)
+	}
+	if filters.HasBalcony != nil {
+		q.Where("balcony = ?", *filters.HasBalcony)
+	}
+	if filters.HasSecurity != nil {
+		q.Where("security = ?", *filters.HasSecurity)
+	}
+	if filters.HasElevator != nil {
+		q.Where("elevator = ?", *filters.HasElevator)
+	}
+	if filters.HasAirCondition != nil {
+		q.Where("air_conditioning = ?", *filters.HasAirCondition)
+	}
+	if filters.HasParking != nil {
+		q.Where("garage = ?", *filters.HasParking)
+	}
+	if filters.Furnished != nil {
+		q.Where("furnished = ?", *filters.Furnished)
+	}
+	if len(filters.Tags) > 0 {
+		q.Where("tags ??& ?", pq.Array(filters.Tags))
+	}
+	if len(filters.Amenities) > 0 {
+		q.Where("NOT EXISTS (SELECT 1 FROM unnest(?::text[]) AS wanted(code) WHERE NOT EXISTS "+
+			"(SELECT 1 FROM property_amenities pa WHERE pa.property_id = properties.id AND pa.amenity_code = wanted.code))",
+			pq.Array(filters.Amenities))
+	}
+	if len(filters.ExcludeRisks) > 0 {
+		q.Where("NOT EXISTS (SELECT 1 FROM property_risk_flags rf WHERE rf.property_id = properties.id AND rf.hazard = ANY(?))",
+			pq.Array(filters.ExcludeRisks))
+	}
+
+	// Role-based filters
+	if filters.OwnerID != nil {
+		q.Where("owner_id = ?", *filters.OwnerID)
+	}
+	if filters.AgentID != nil {
+		q.Where("agent_id = ?", *filters.AgentID)
+	}
+	if filters.AgencyID != nil {
+		q.Where("agency_id = ?", *filters.AgencyID)
+	}
+	if filters.CreatedBy != nil {
+		q.Where("created_by = ?", *filters.CreatedBy)
+	}
+	if filters.TenantID != "" {
+		q.Where("tenant_id = ?", filters.TenantID)
+	}
+
+	// Public searches hide expired and quarantined listings; owners, agencies and explicit status
+	// filters still see them
+	if len(filters.Status) == 0 && !filters.IncludeExpired && filters.OwnerID == nil && filters.AgentID == nil &&
+		filters.AgencyID == nil && filters.CreatedBy == nil {
+		q.Where(publicStatusCondition)
+	}
+
+	return q
 }
 
 // scanProperties is a helper function to scan properties from rows
@@ -1039,43 +1185,11 @@ func (r *PostgreSQLPropertyRepository) scanProperties(rows *sql.Rows) ([]domain.
 	var properties []domain.Property
 
 	for rows.Next() {
-		var property domain.Property
-		var imagesJSON, tagsJSON string
-
-		err := rows.Scan(
-			&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
-			&property.Province, &property.City, &property.Sector, &property.Address,
-			&property.Latitude, &property.Longitude, &property.LocationPrecision,
-			&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
-			&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
-			&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
-			&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
-			&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
-			&property.Security, &property.Elevator, &property.AirConditioning,
-			&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
-			&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
-			&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
-		)
+		property, err := scanProperty(rows, false)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning property: %w", err)
 		}
-
-		// Convert JSON back to slices
-		if imagesJSON != "" {
-			err = json.Unmarshal([]byte(imagesJSON), &property.Images)
-			if err != nil {
-				property.Images = []string{} // Continue with empty slice if JSON is invalid
-			}
-		}
-
-		if tagsJSON != "" {
-			err = json.Unmarshal([]byte(tagsJSON), &property.Tags)
-			if err != nil {
-				property.Tags = []string{} // Continue with empty slice if JSON is invalid
-			}
-		}
-
-		properties = append(properties, property)
+		properties = append(properties, *property)
 	}
 
 	if err := rows.Err(); err != nil {
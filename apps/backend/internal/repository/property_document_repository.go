@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// PropertyDocumentRepository defines the interface for property document operations
+type PropertyDocumentRepository interface {
+	// Create saves a new document record
+	Create(document *domain.PropertyDocument) error
+
+	// GetByID retrieves a document by ID
+	GetByID(id string) (*domain.PropertyDocument, error)
+
+	// GetByPropertyID retrieves the documents of a property, oldest first. When
+	// publicOnly is set private documents are left out.
+	GetByPropertyID(propertyID string, publicOnly bool) ([]domain.PropertyDocument, error)
+
+	// Delete removes a document record
+	Delete(id string) error
+
+	// CountByProperty returns the number of documents of a property
+	CountByProperty(propertyID string) (int, error)
+}
+
+// PostgreSQLPropertyDocumentRepository implements PropertyDocumentRepository using PostgreSQL
+type PostgreSQLPropertyDocumentRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPropertyDocumentRepository creates a new PostgreSQL property document repository
+func NewPostgreSQLPropertyDocumentRepository(db *sql.DB) *PostgreSQLPropertyDocumentRepository {
+	return &PostgreSQLPropertyDocumentRepository{db: db}
+}
+
+const propertyDocumentColumns = `id, property_id, type, visibility, title, file_name, content_type,
+		size, url, storage_path, uploaded_by, template_id, created_at`
+
+// Create saves a new document record
+func (r *PostgreSQLPropertyDocumentRepository) Create(document *domain.PropertyDocument) error {
+	if document == nil {
+		return fmt.Errorf("document cannot be nil")
+	}
+
+	query := `
+		INSERT INTO property_documents (` + propertyDocumentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	var uploadedBy sql.NullString
+	if document.UploadedBy != "" {
+		uploadedBy = sql.NullString{String: document.UploadedBy, Valid: true}
+	}
+
+	_, err := r.db.Exec(query,
+		document.ID, document.PropertyID, document.Type, document.Visibility, document.Title,
+		document.FileName, document.ContentType, document.Size, document.URL,
+		document.StoragePath, uploadedBy, document.TemplateID, document.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a document by ID
+func (r *PostgreSQLPropertyDocumentRepository) GetByID(id string) (*domain.PropertyDocument, error) {
+	if id == "" {
+		return nil, fmt.Errorf("document ID cannot be empty")
+	}
+
+	query := `SELECT ` + propertyDocumentColumns + ` FROM property_documents WHERE id = $1`
+
+	document, err := scanPropertyDocument(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("document not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	return document, nil
+}
+
+// GetByPropertyID retrieves the documents of a property, oldest first
+func (r *PostgreSQLPropertyDocumentRepository) GetByPropertyID(propertyID string, publicOnly bool) ([]domain.PropertyDocument, error) {
+	query := `SELECT ` + propertyDocumentColumns + ` FROM property_documents WHERE property_id = $1`
+	if publicOnly {
+		query += ` AND visibility = 'public'`
+	}
+	query += ` ORDER BY created_at ASC`
+
+	rows, err := r.db.Query(query, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := []domain.PropertyDocument{}
+	for rows.Next() {
+		document, err := scanPropertyDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		documents = append(documents, *document)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return documents, nil
+}
+
+// Delete removes a document record
+func (r *PostgreSQLPropertyDocumentRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM property_documents WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("document not found: %s", id)
+	}
+
+	return nil
+}
+
+// CountByProperty returns the number of documents of a property
+func (r *PostgreSQLPropertyDocumentRepository) CountByProperty(propertyID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM property_documents WHERE property_id = $1`, propertyID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+	return count, nil
+}
+
+// scanPropertyDocument scans a row selected with propertyDocumentColumns
+func scanPropertyDocument(row interface{ Scan(...interface{}) error }) (*domain.PropertyDocument, error) {
+	document := &domain.PropertyDocument{}
+	var uploadedBy, templateID sql.NullString
+	err := row.Scan(
+		&document.ID, &document.PropertyID, &document.Type, &document.Visibility, &document.Title,
+		&document.FileName, &document.ContentType, &document.Size, &document.URL,
+		&document.StoragePath, &uploadedBy, &templateID, &document.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	document.UploadedBy = uploadedBy.String
+	if templateID.Valid {
+		document.TemplateID = &templateID.String
+	}
+	return document, nil
+}
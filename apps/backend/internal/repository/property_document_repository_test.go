@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var propertyDocumentRowColumns = []string{
+	"id", "property_id", "type", "visibility", "title", "file_name", "content_type",
+	"size", "url", "storage_path", "uploaded_by", "template_id", "created_at",
+}
+
+func TestPropertyDocumentRepository_GetByPropertyID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyDocumentRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM property_documents WHERE property_id = \\$1 AND visibility = 'public' ORDER BY created_at").
+		WithArgs("prop-1").
+		WillReturnRows(sqlmock.NewRows(propertyDocumentRowColumns).
+			AddRow("doc-1", "prop-1", "floor_plan", "public", "Planta", "planta.pdf", "application/pdf",
+				1024, "/uploads/documents/prop-1/doc-1.pdf", "documents/prop-1/doc-1.pdf", nil, nil, now))
+	mock.ExpectQuery("FROM property_documents WHERE property_id = \\$1 ORDER BY created_at").
+		WithArgs("prop-1").
+		WillReturnRows(sqlmock.NewRows(propertyDocumentRowColumns).
+			AddRow("doc-1", "prop-1", "floor_plan", "public", "Planta", "planta.pdf", "application/pdf",
+				1024, "/uploads/documents/prop-1/doc-1.pdf", "documents/prop-1/doc-1.pdf", nil, nil, now).
+			AddRow("doc-2", "prop-1", "deed", "private", "Escritura", "escritura.pdf", "application/pdf",
+				2048, "/uploads/documents/prop-1/doc-2.pdf", "documents/prop-1/doc-2.pdf", "user-1", "tmpl-1", now))
+
+	public, err := repo.GetByPropertyID("prop-1", true)
+	require.NoError(t, err)
+	require.Len(t, public, 1)
+	assert.Equal(t, "floor_plan", public[0].Type)
+	assert.Empty(t, public[0].UploadedBy)
+
+	all, err := repo.GetByPropertyID("prop-1", false)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "user-1", all[1].UploadedBy)
+	assert.Equal(t, "documents/prop-1/doc-2.pdf", all[1].StoragePath)
+	require.NotNil(t, all[1].TemplateID)
+	assert.Equal(t, "tmpl-1", *all[1].TemplateID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPropertyDocumentRepository_Delete(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyDocumentRepository(db)
+
+	mock.ExpectExec("DELETE FROM property_documents WHERE id = \\$1").
+		WithArgs("doc-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM property_documents WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, repo.Delete("doc-1"))
+	assert.ErrorContains(t, repo.Delete("missing"), "document not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
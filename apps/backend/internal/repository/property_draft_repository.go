@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// PropertyDraftRepository defines the interface for listing wizard drafts
+type PropertyDraftRepository interface {
+	// Save creates or replaces a draft. Drafts of other users are never overwritten:
+	// saving one returns a not found error.
+	Save(draft *domain.PropertyDraft) error
+
+	// GetByID retrieves a draft
+	GetByID(id string) (*domain.PropertyDraft, error)
+
+	// ListByUser retrieves the drafts of a user, most recently saved first
+	ListByUser(userID string) ([]domain.PropertyDraft, error)
+
+	// CountByUser counts the drafts of a user
+	CountByUser(userID string) (int, error)
+
+	// Delete removes a draft
+	Delete(id string) error
+
+	// DeleteUpdatedBefore removes drafts last saved before the given time and returns
+	// how many were removed
+	DeleteUpdatedBefore(before time.Time) (int64, error)
+}
+
+// PostgreSQLPropertyDraftRepository implements PropertyDraftRepository using PostgreSQL
+type PostgreSQLPropertyDraftRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPropertyDraftRepository creates a new PostgreSQL property draft repository
+func NewPostgreSQLPropertyDraftRepository(db *sql.DB) *PostgreSQLPropertyDraftRepository {
+	return &PostgreSQLPropertyDraftRepository{db: db}
+}
+
+const propertyDraftColumns = `id, user_id, title, payload, created_at, updated_at`
+
+// Save creates or replaces a draft of its user
+func (r *PostgreSQLPropertyDraftRepository) Save(draft *domain.PropertyDraft) error {
+	query := `
+		INSERT INTO property_drafts (` + propertyDraftColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			title = EXCLUDED.title, payload = EXCLUDED.payload, updated_at = EXCLUDED.updated_at
+		WHERE property_drafts.user_id = EXCLUDED.user_id
+		RETURNING created_at`
+
+	err := r.db.QueryRow(query,
+		draft.ID, draft.UserID, draft.Title, []byte(draft.Payload), draft.CreatedAt, draft.UpdatedAt,
+	).Scan(&draft.CreatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("property draft not found: %s", draft.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save property draft: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a draft
+func (r *PostgreSQLPropertyDraftRepository) GetByID(id string) (*domain.PropertyDraft, error) {
+	query := `SELECT ` + propertyDraftColumns + ` FROM property_drafts WHERE id = $1`
+
+	draft, err := scanPropertyDraft(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("property draft not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// ListByUser retrieves the drafts of a user, most recently saved first
+func (r *PostgreSQLPropertyDraftRepository) ListByUser(userID string) ([]domain.PropertyDraft, error) {
+	query := `SELECT ` + propertyDraftColumns + ` FROM property_drafts
+		WHERE user_id = $1
+		ORDER BY updated_at DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts := []domain.PropertyDraft{}
+	for rows.Next() {
+		draft, err := scanPropertyDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, *draft)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return drafts, nil
+}
+
+// CountByUser counts the drafts of a user
+func (r *PostgreSQLPropertyDraftRepository) CountByUser(userID string) (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM property_drafts WHERE user_id = $1`, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count property drafts: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes a draft
+func (r *PostgreSQLPropertyDraftRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM property_drafts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete property draft: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("property draft not found: %s", id)
+	}
+
+	return nil
+}
+
+// DeleteUpdatedBefore removes drafts last saved before the given time
+func (r *PostgreSQLPropertyDraftRepository) DeleteUpdatedBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM property_drafts WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale property drafts: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// scanPropertyDraft scans a row selected with propertyDraftColumns
+func scanPropertyDraft(row interface{ Scan(...interface{}) error }) (*domain.PropertyDraft, error) {
+	var draft domain.PropertyDraft
+	var payload []byte
+
+	err := row.Scan(&draft.ID, &draft.UserID, &draft.Title, &payload, &draft.CreatedAt, &draft.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan property draft: %w", err)
+	}
+
+	draft.Payload = payload
+	return &draft, nil
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestPropertyDraftRepository_Save(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPropertyDraftRepository(db)
+
+	createdAt := time.Now().Add(-time.Hour)
+	draft := &domain.PropertyDraft{ID: "draft-1", UserID: "user-1", Title: "Casa", Payload: []byte(`{"title":"Casa"}`),
+		CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+	mock.ExpectQuery(`INSERT INTO property_drafts (.+) ON CONFLICT \(id\) DO UPDATE`).
+		WithArgs("draft-1", "user-1", "Casa", []byte(`{"title":"Casa"}`), draft.CreatedAt, draft.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+	require.NoError(t, repo.Save(draft))
+	assert.Equal(t, createdAt, draft.CreatedAt, "autosaves keep the creation time")
+
+	// The conflict update is skipped for drafts of other users
+	mock.ExpectQuery(`INSERT INTO property_drafts`).WillReturnError(sql.ErrNoRows)
+	assert.ErrorContains(t, repo.Save(draft), "property draft not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// PropertyShareRepository defines data access for property share events and the
+// conversion funnel they open
+type PropertyShareRepository interface {
+	// Create saves a share unless the same user or session shared the property through
+	// the same channel within domain.ShareDedupWindow, reporting whether it was saved
+	Create(share *domain.PropertyShare) (bool, error)
+
+	// CountByProperty counts the shares of a property by channel
+	CountByProperty(propertyID string) (*domain.ShareCounts, error)
+
+	// GetFunnelCounts counts the events of each conversion funnel step between from and to
+	GetFunnelCounts(from, to time.Time) (*domain.ConversionFunnelCounts, error)
+}
+
+// PostgreSQLPropertyShareRepository implements PropertyShareRepository using PostgreSQL
+type PostgreSQLPropertyShareRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPropertyShareRepository creates a new PostgreSQL property share repository
+func NewPostgreSQLPropertyShareRepository(db *sql.DB) *PostgreSQLPropertyShareRepository {
+	return &PostgreSQLPropertyShareRepository{db: db}
+}
+
+// Create saves a share unless it repeats a recent one of the same user or session
+func (r *PostgreSQLPropertyShareRepository) Create(share *domain.PropertyShare) (bool, error) {
+	var userID, sessionID interface{}
+	if share.UserID != "" {
+		userID = share.UserID
+	}
+	if share.SessionID != "" {
+		sessionID = share.SessionID
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO property_shares (id, property_id, channel, user_id, session_id, created_at)
+		SELECT $1, $2, $3, $4::uuid, $5::varchar, $6
+		WHERE NOT EXISTS (
+			SELECT 1 FROM property_shares
+			WHERE property_id = $2 AND channel = $3 AND created_at > $7
+				AND (user_id = $4::uuid OR session_id = $5::varchar)
+		)`,
+		share.ID, share.PropertyID, share.Channel, userID, sessionID, share.CreatedAt,
+		share.CreatedAt.Add(-domain.ShareDedupWindow))
+	if err != nil {
+		return false, fmt.Errorf("failed to record property share: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// CountByProperty counts the shares of a property by channel
+func (r *PostgreSQLPropertyShareRepository) CountByProperty(propertyID string) (*domain.ShareCounts, error) {
+	rows, err := r.db.Query(`
+		SELECT channel, COUNT(*) FROM property_shares WHERE property_id = $1 GROUP BY channel`, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count property shares: %w", err)
+	}
+	defer rows.Close()
+	return scanShareCounts(rows)
+}
+
+// GetFunnelCounts counts the shares by channel, visits through short links, inquiries
+// and scheduled property visits between from and to
+func (r *PostgreSQLPropertyShareRepository) GetFunnelCounts(from, to time.Time) (*domain.ConversionFunnelCounts, error) {
+	rows, err := r.db.Query(`
+		SELECT channel, COUNT(*) FROM property_shares
+		WHERE created_at >= $1 AND created_at < $2 GROUP BY channel`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count property shares: %w", err)
+	}
+	defer rows.Close()
+	shares, err := scanShareCounts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := &domain.ConversionFunnelCounts{Shares: *shares}
+	err = r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM short_link_clicks WHERE clicked_at >= $1 AND clicked_at < $2),
+			(SELECT COUNT(*) FROM rental_applications WHERE created_at >= $1 AND created_at < $2),
+			(SELECT COUNT(*) FROM appointments WHERE kind = 'visit' AND created_at >= $1 AND created_at < $2)`,
+		from, to).Scan(&counts.ShareVisits, &counts.Inquiries, &counts.Visits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count funnel steps: %w", err)
+	}
+	return counts, nil
+}
+
+func scanShareCounts(rows *sql.Rows) (*domain.ShareCounts, error) {
+	counts := domain.NewShareCounts()
+	for rows.Next() {
+		var channel string
+		var count int
+		if err := rows.Scan(&channel, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan share count: %w", err)
+		}
+		counts.Add(channel, count)
+	}
+	return counts, rows.Err()
+}
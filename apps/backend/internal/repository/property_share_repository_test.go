@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestPropertyShareRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPropertyShareRepository(db)
+
+	now := time.Date(2025, 9, 26, 10, 0, 0, 0, time.UTC)
+	share := &domain.PropertyShare{ID: "share-1", PropertyID: "prop-1", Channel: domain.ShareChannelWhatsApp,
+		SessionID: "session-123", CreatedAt: now}
+	mock.ExpectExec(`INSERT INTO property_shares (.+) WHERE NOT EXISTS`).
+		WithArgs("share-1", "prop-1", "whatsapp", nil, "session-123", now, now.Add(-domain.ShareDedupWindow)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO property_shares`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	recorded, err := repo.Create(share)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	recorded, err = repo.Create(share)
+	require.NoError(t, err)
+	assert.False(t, recorded, "repeated shares count once")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPropertyShareRepository_GetFunnelCounts(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPropertyShareRepository(db)
+
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 30)
+	mock.ExpectQuery(`SELECT channel, COUNT\(\*\) FROM property_shares\s+WHERE created_at >= \$1 AND created_at < \$2 GROUP BY channel`).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"channel", "count"}).AddRow("whatsapp", 7).AddRow("link", 3))
+	mock.ExpectQuery(`FROM short_link_clicks (.+) FROM rental_applications (.+) FROM appointments WHERE kind = 'visit'`).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"clicks", "inquiries", "visits"}).AddRow(5, 2, 1))
+
+	counts, err := repo.GetFunnelCounts(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 10, counts.Shares.Total)
+	assert.Equal(t, 7, counts.Shares.ByChannel[domain.ShareChannelWhatsApp])
+	assert.Equal(t, 0, counts.Shares.ByChannel[domain.ShareChannelFacebook])
+	assert.Equal(t, 5, counts.ShareVisits)
+	assert.Equal(t, 2, counts.Inquiries)
+	assert.Equal(t, 1, counts.Visits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
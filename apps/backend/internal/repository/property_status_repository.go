@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// PropertyStatusRepository defines the interface for listing status transitions
+type PropertyStatusRepository interface {
+	// ChangeStatus moves a property from the change's status to its new one and records
+	// the change, along with outbox events, in one transaction. It fails when the
+	// property's status changed meanwhile.
+	ChangeStatus(change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error
+
+	// Record records a change whose status was already applied, e.g. by a deal
+	Record(change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error
+
+	// ListByProperty lists the status changes of a property, latest first
+	ListByProperty(propertyID string) ([]domain.PropertyStatusChange, error)
+}
+
+// PostgreSQLPropertyStatusRepository implements PropertyStatusRepository using PostgreSQL
+type PostgreSQLPropertyStatusRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPropertyStatusRepository creates a new PostgreSQL property status repository
+func NewPostgreSQLPropertyStatusRepository(db *sql.DB) *PostgreSQLPropertyStatusRepository {
+	return &PostgreSQLPropertyStatusRepository{db: db}
+}
+
+const propertyStatusChangeColumns = `id, property_id, from_status, to_status, final_price, reason, changed_by, changed_at`
+
+// ChangeStatus moves a property to a new status and records the change in one transaction
+func (r *PostgreSQLPropertyStatusRepository) ChangeStatus(change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	if change == nil {
+		return fmt.Errorf("status change cannot be nil")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := applyStatusChange(tx, change); err != nil {
+		return err
+	}
+	if err := insertOutboxEvents(tx, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Record records a change whose status was already applied
+func (r *PostgreSQLPropertyStatusRepository) Record(change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	if change == nil {
+		return fmt.Errorf("status change cannot be nil")
+	}
+
+	return execWithOutbox(r.db, events, func(exec execer) error {
+		return insertStatusChange(exec, change)
+	})
+}
+
+// ListByProperty lists the status changes of a property, latest first
+func (r *PostgreSQLPropertyStatusRepository) ListByProperty(propertyID string) ([]domain.PropertyStatusChange, error) {
+	query := `SELECT ` + propertyStatusChangeColumns + ` FROM property_status_changes
+		WHERE property_id = $1 ORDER BY changed_at DESC`
+
+	rows, err := r.db.Query(query, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []domain.PropertyStatusChange{}
+	for rows.Next() {
+		var change domain.PropertyStatusChange
+		var finalPrice sql.NullFloat64
+		if err := rows.Scan(&change.ID, &change.PropertyID, &change.FromStatus, &change.ToStatus,
+			&finalPrice, &change.Reason, &change.ChangedBy, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		if finalPrice.Valid {
+			change.FinalPrice = &finalPrice.Float64
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// applyStatusChange moves a property from the change's status to its new one and
+// records the change. It fails when the property's status changed meanwhile.
+func applyStatusChange(exec execer, change *domain.PropertyStatusChange) error {
+	result, err := exec.Exec(`UPDATE properties SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2`,
+		change.PropertyID, change.FromStatus, change.ToStatus, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update property status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("invalid status transition: property %s is no longer %s", change.PropertyID, change.FromStatus)
+	}
+
+	return insertStatusChange(exec, change)
+}
+
+// insertStatusChange stores a status change with exec
+func insertStatusChange(exec execer, change *domain.PropertyStatusChange) error {
+	query := `INSERT INTO property_status_changes (` + propertyStatusChangeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := exec.Exec(query,
+		change.ID, change.PropertyID, change.FromStatus, change.ToStatus,
+		change.FinalPrice, change.Reason, change.ChangedBy, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
@@ -104,6 +104,9 @@ func TestPostgreSQLPropertyRepository_Create(t *testing.T) {
 						sqlmock.AnyArg(), // agency_id
 						sqlmock.AnyArg(), // created_by
 						sqlmock.AnyArg(), // updated_by
+						domain.DefaultTenantID,
+						"<p>Modern house with pool</p>", // description_html
+						"Modern house with pool",        // description_raw
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -166,12 +169,14 @@ func TestPostgreSQLPropertyRepository_GetByID(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				}).AddRow(
 					"test-id", "test-slug", "Test Title", "Test Description", 100000.0, "Guayas", "Samborondón",
 					nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
 					`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 					false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				)
 				mock.ExpectQuery(`SELECT .+ FROM properties WHERE id = \$1`).
 					WithArgs("test-id").
@@ -255,15 +260,15 @@ func TestPostgreSQLPropertyRepository_GetBySlug(t *testing.T) {
 					"garage", "pool", "garden", "terrace", "balcony", "security", "elevator",
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
-					"owner_id", "agent_id", "agency_id", "created_by", "updated_by", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				}).AddRow(
 					"test-id", "test-slug-12345678", "Test Title", "Test Description", 100000.0, "Guayas", "Samborondón",
 					nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
 					`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 					false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
-					nil, nil, nil, nil, nil, 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				)
 				mock.ExpectQuery(`SELECT .+ FROM properties WHERE slug = \$1`).
 					WithArgs("test-slug-12345678").
@@ -335,18 +340,21 @@ func TestPostgreSQLPropertyRepository_GetAll(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				}).AddRow(
 					"id1", "slug1", "Title 1", "Description 1", 100000.0, "Guayas", "Samborondón",
 					nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
 					`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 					false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				).AddRow(
 					"id2", "slug2", "Title 2", "Description 2", 200000.0, "Pichincha", "Quito",
 					nil, nil, nil, nil, "approximate", "apartment", "available", 2, 2.0, 80.0, nil,
 					`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 					false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				)
 				mock.ExpectQuery(`SELECT .+ FROM properties ORDER BY featured DESC, created_at DESC`).
 					WillReturnRows(rows)
@@ -367,6 +375,7 @@ func TestPostgreSQLPropertyRepository_GetAll(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				})
 				mock.ExpectQuery(`SELECT .+ FROM properties ORDER BY featured DESC, created_at DESC`).
 					WillReturnRows(rows)
@@ -421,7 +430,8 @@ func TestPostgreSQLPropertyRepository_Update(t *testing.T) {
 			name:     "successful update",
 			property: createTestProperty(),
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(`UPDATE properties SET`).
+				// view_count is only changed in place by IncrementViewCount
+				mock.ExpectExec(`(?s)UPDATE properties SET (.+)featured = \$38, real_estate_company_id = \$39`).
 					WithArgs(
 						sqlmock.AnyArg(), // slug
 						sqlmock.AnyArg(), // title
@@ -460,9 +470,16 @@ func TestPostgreSQLPropertyRepository_Update(t *testing.T) {
 						sqlmock.AnyArg(), // air_conditioning
 						sqlmock.AnyArg(), // tags
 						sqlmock.AnyArg(), // featured
-						sqlmock.AnyArg(), // view_count
 						sqlmock.AnyArg(), // real_estate_company_id
 						sqlmock.AnyArg(), // updated_at
+						sqlmock.AnyArg(), // parking_spaces
+						sqlmock.AnyArg(), // owner_id
+						sqlmock.AnyArg(), // agent_id
+						sqlmock.AnyArg(), // agency_id
+						sqlmock.AnyArg(), // created_by
+						sqlmock.AnyArg(), // updated_by
+						sqlmock.AnyArg(), // description_html
+						sqlmock.AnyArg(), // description_raw
 						sqlmock.AnyArg(), // id (WHERE clause)
 					).
 					WillReturnResult(sqlmock.NewResult(0, 1))
@@ -577,6 +594,23 @@ func TestPostgreSQLPropertyRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestPostgreSQLPropertyRepository_IncrementViewCount(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(`^UPDATE properties SET view_count = view_count \+ 1 WHERE id = \$1$`).
+		WithArgs("test-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE properties SET view_count`).
+		WithArgs("nonexistent-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewPostgreSQLPropertyRepository(db)
+	assert.NoError(t, repo.IncrementViewCount("test-id"))
+	assert.ErrorContains(t, repo.IncrementViewCount("nonexistent-id"), "property not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestPostgreSQLPropertyRepository_GetByProvince(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -600,14 +634,16 @@ func TestPostgreSQLPropertyRepository_GetByProvince(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				}).AddRow(
 					"id1", "slug1", "Title 1", "Description 1", 100000.0, "Guayas", "Samborondón",
 					nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
 					`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 					false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				)
-				mock.ExpectQuery(`SELECT .+ FROM properties WHERE province = \$1 ORDER BY featured DESC, created_at DESC`).
+				mock.ExpectQuery(`SELECT .+ FROM properties WHERE province = \$1 AND status NOT IN \('expired', 'quarantined'\) ORDER BY featured DESC, created_at DESC`).
 					WithArgs("Guayas").
 					WillReturnRows(rows)
 			},
@@ -628,8 +664,9 @@ func TestPostgreSQLPropertyRepository_GetByProvince(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				})
-				mock.ExpectQuery(`SELECT .+ FROM properties WHERE province = \$1 ORDER BY featured DESC, created_at DESC`).
+				mock.ExpectQuery(`SELECT .+ FROM properties WHERE province = \$1 AND status NOT IN \('expired', 'quarantined'\) ORDER BY featured DESC, created_at DESC`).
 					WithArgs("Loja").
 					WillReturnRows(rows)
 			},
@@ -640,7 +677,7 @@ func TestPostgreSQLPropertyRepository_GetByProvince(t *testing.T) {
 			name:     "database error",
 			province: "Guayas",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT .+ FROM properties WHERE province = \$1 ORDER BY featured DESC, created_at DESC`).
+				mock.ExpectQuery(`SELECT .+ FROM properties WHERE province = \$1 AND status NOT IN \('expired', 'quarantined'\) ORDER BY featured DESC, created_at DESC`).
 					WithArgs("Guayas").
 					WillReturnError(errors.New("database connection failed"))
 			},
@@ -698,14 +735,16 @@ func TestPostgreSQLPropertyRepository_GetByPriceRange(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				}).AddRow(
 					"id1", "slug1", "Title 1", "Description 1", 200000.0, "Guayas", "Samborondón",
 					nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
 					`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 					false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 				)
-				mock.ExpectQuery(`SELECT .+ FROM properties WHERE price >= \$1 AND price <= \$2 ORDER BY featured DESC, created_at DESC`).
+				mock.ExpectQuery(`SELECT .+ FROM properties WHERE price >= \$1 AND price <= \$2 AND status NOT IN \('expired', 'quarantined'\) ORDER BY featured DESC, created_at DESC`).
 					WithArgs(100000.0, 300000.0).
 					WillReturnRows(rows)
 			},
@@ -727,8 +766,9 @@ func TestPostgreSQLPropertyRepository_GetByPriceRange(t *testing.T) {
 					"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 					"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 				})
-				mock.ExpectQuery(`SELECT .+ FROM properties WHERE price >= \$1 AND price <= \$2 ORDER BY featured DESC, created_at DESC`).
+				mock.ExpectQuery(`SELECT .+ FROM properties WHERE price >= \$1 AND price <= \$2 AND status NOT IN \('expired', 'quarantined'\) ORDER BY featured DESC, created_at DESC`).
 					WithArgs(500000.0, 1000000.0).
 					WillReturnRows(rows)
 			},
@@ -740,7 +780,7 @@ func TestPostgreSQLPropertyRepository_GetByPriceRange(t *testing.T) {
 			minPrice: 100000,
 			maxPrice: 300000,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT .+ FROM properties WHERE price >= \$1 AND price <= \$2 ORDER BY featured DESC, created_at DESC`).
+				mock.ExpectQuery(`SELECT .+ FROM properties WHERE price >= \$1 AND price <= \$2 AND status NOT IN \('expired', 'quarantined'\) ORDER BY featured DESC, created_at DESC`).
 					WithArgs(100000.0, 300000.0).
 					WillReturnError(errors.New("database connection failed"))
 			},
@@ -790,12 +830,14 @@ func TestScanProperty(t *testing.T) {
 		"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 		"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 	}).AddRow(
 		"test-id", "test-slug", "Test Title", "Test Description", 100000.0, "Guayas", "Samborondón",
 		"Test Sector", "Test Address", -2.1667, -79.9, "exact", "house", "available", 3, 2.5, 150.0, "main.jpg",
 		`["image1.jpg","image2.jpg"]`, "video.mp4", "tour360.html", 1200.0, 150.0, 666.67, 2020, 2, "new", true,
 		true, true, true, true, true, true, true, true, `["luxury","pool","garden"]`, true, 25, "company-id", time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM properties WHERE id = \$1`).
@@ -838,12 +880,14 @@ func TestJSONHandling(t *testing.T) {
 		"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
 		"created_at", "updated_at", "parking_spaces",
 					"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+					"description_html", "description_raw",
 	}).AddRow(
 		"test-id", "test-slug", "Test Title", "Test Description", 100000.0, "Guayas", "Samborondón",
 		nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
 		`invalid json`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
 		false, false, false, false, false, `[]`, false, 0, nil, time.Now(), time.Now(), 0,
 					nil, nil, nil, nil, nil,
+					"", "",
 	)
 
 	mock.ExpectQuery(`SELECT .+ FROM properties WHERE id = \$1`).
@@ -886,7 +930,9 @@ func TestCreatePropertyWithComplexData(t *testing.T) {
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg()).
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	repo := NewPostgreSQLPropertyRepository(db)
@@ -894,4 +940,247 @@ func TestCreatePropertyWithComplexData(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+func TestBuildFilterConditions(t *testing.T) {
+	minBedrooms := 2
+	maxPrice := 250000.0
+	hasPool := true
+
+	t.Run("no filters", func(t *testing.T) {
+		where, args := buildFilterConditions(domain.NewPropertySearchFilters())
+		assert.Equal(t, "WHERE status NOT IN ('expired', 'quarantined')", where)
+		assert.Empty(t, args)
+	})
+
+	t.Run("combined filters", func(t *testing.T) {
+		filters := domain.NewPropertySearchFilters()
+		filters.Provinces = []string{"Pichincha"}
+		filters.Cities = []string{"Quito", "Cumbayá"}
+		filters.MaxPrice = &maxPrice
+		filters.MinBedrooms = &minBedrooms
+		filters.HasPool = &hasPool
+
+		where, args := buildFilterConditions(filters)
+		assert.Equal(t, "WHERE province = ANY($1) AND city = ANY($2) AND price <= $3 AND bedrooms >= $4 AND pool = $5 AND status NOT IN ('expired', 'quarantined')", where)
+		assert.Len(t, args, 5)
+		assert.Equal(t, 250000.0, args[2])
+		assert.Equal(t, 2, args[3])
+		assert.Equal(t, true, args[4])
+	})
+
+	t.Run("owner listings include expired", func(t *testing.T) {
+		ownerID := "owner-1"
+		filters := domain.NewPropertySearchFilters()
+		filters.OwnerID = &ownerID
+
+		where, args := buildFilterConditions(filters)
+		assert.Equal(t, "WHERE owner_id = $1", where)
+		assert.Equal(t, []interface{}{"owner-1"}, args)
+	})
+
+	t.Run("agent scope", func(t *testing.T) {
+		filters := domain.NewPropertySearchFilters()
+		filters.Cities = []string{"Quito"}
+		filters.ScopeToActor(domain.NewActor("agent-1", "agent", "agency-1"))
+
+		where, args := buildFilterConditions(filters)
+		assert.Equal(t, "WHERE city = ANY($1) AND agent_id = $2", where)
+		assert.Equal(t, "agent-1", args[1])
+	})
+
+	t.Run("admin scope includes expired", func(t *testing.T) {
+		filters := domain.NewPropertySearchFilters()
+		filters.ScopeToActor(domain.NewActor("admin-1", "admin", ""))
+
+		where, args := buildFilterConditions(filters)
+		assert.Equal(t, "", where)
+		assert.Empty(t, args)
+	})
+
+	t.Run("tenant scope", func(t *testing.T) {
+		filters := domain.NewPropertySearchFilters()
+		filters.TenantID = "red-norte"
+
+		where, args := buildFilterConditions(filters)
+		assert.Equal(t, "WHERE tenant_id = $1 AND status NOT IN ('expired', 'quarantined')", where)
+		assert.Equal(t, []interface{}{"red-norte"}, args)
+	})
+
+	t.Run("catalog amenities", func(t *testing.T) {
+		filters := domain.NewPropertySearchFilters()
+		filters.Amenities = []string{"generator", "pet_friendly"}
+
+		where, args := buildFilterConditions(filters)
+		assert.Contains(t, where, "unnest($1::text[]) AS wanted(code)")
+		assert.Contains(t, where, "pa.property_id = properties.id")
+		assert.Len(t, args, 1)
+	})
+
+	t.Run("excluded risks", func(t *testing.T) {
+		filters := domain.NewPropertySearchFilters()
+		filters.ExcludeRisks = []string{"flood"}
+
+		where, args := buildFilterConditions(filters)
+		assert.Contains(t, where, "NOT EXISTS (SELECT 1 FROM property_risk_flags rf WHERE rf.property_id = properties.id AND rf.hazard = ANY($1))")
+		assert.Len(t, args, 1)
+	})
+}
+
+func TestPostgreSQLPropertyRepository_GetByFiltersPaginated(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	minBedrooms := 3
+	featured := true
+	filters := domain.NewPropertySearchFilters()
+	filters.PropertyTypes = []string{"house"}
+	filters.MinBedrooms = &minBedrooms
+	filters.Featured = &featured
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE type = ANY\(\$1\) AND bedrooms >= \$2 AND featured = \$3`).
+		WithArgs(sqlmock.AnyArg(), 3, true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{
+		"id", "slug", "title", "description", "price", "province", "city",
+		"sector", "address", "latitude", "longitude", "location_precision",
+		"type", "status", "bedrooms", "bathrooms", "area_m2", "main_image",
+		"images", "video_tour", "tour_360", "rent_price", "common_expenses",
+		"price_per_m2", "year_built", "floors", "property_status", "furnished",
+		"garage", "pool", "garden", "terrace", "balcony", "security", "elevator",
+		"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
+		"created_at", "updated_at", "parking_spaces",
+		"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+		"description_html", "description_raw",
+	}).AddRow(
+		"id1", "slug1", "Title 1", "Description 1", 200000.0, "Guayas", "Samborondón",
+		nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
+		`[]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
+		false, false, false, false, false, `[]`, true, 0, nil, time.Now(), time.Now(), 0,
+		nil, nil, nil, nil, nil,
+		"", "",
+	)
+	mock.ExpectQuery(`SELECT .+ FROM properties\s+WHERE type = ANY\(\$1\) AND bedrooms >= \$2 AND featured = \$3 AND status NOT IN \('expired', 'quarantined'\)\s+ORDER BY created_at DESC\s+LIMIT \$4 OFFSET \$5`).
+		WithArgs(sqlmock.AnyArg(), 3, true, 20, 0).
+		WillReturnRows(rows)
+
+	repo := NewPostgreSQLPropertyRepository(db)
+	properties, total, err := repo.GetByFiltersPaginated(filters, domain.NewPaginationParams())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, properties, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgreSQLPropertyRepository_StreamByFilters(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	columns := []string{
+		"id", "slug", "title", "description", "price", "province", "city",
+		"sector", "address", "latitude", "longitude", "location_precision",
+		"type", "status", "bedrooms", "bathrooms", "area_m2", "main_image",
+		"images", "video_tour", "tour_360", "rent_price", "common_expenses",
+		"price_per_m2", "year_built", "floors", "property_status", "furnished",
+		"garage", "pool", "garden", "terrace", "balcony", "security", "elevator",
+		"air_conditioning", "tags", "featured", "view_count", "real_estate_company_id",
+		"created_at", "updated_at", "parking_spaces",
+		"owner_id", "agent_id", "agency_id", "created_by", "updated_by",
+		"description_html", "description_raw",
+	}
+	rows := sqlmock.NewRows(columns)
+	for _, id := range []string{"id1", "id2", "id3"} {
+		rows.AddRow(
+			id, "slug-"+id, "Title", "Description", 100000.0, "Pichincha", "Quito",
+			nil, nil, nil, nil, "approximate", "house", "available", 3, 2.5, 150.0, nil,
+			`["a.jpg"]`, nil, nil, nil, nil, nil, nil, nil, "used", false, false, false, false,
+			false, false, false, false, false, `["pool"]`, false, 0, nil, time.Now(), time.Now(), 0,
+			nil, nil, nil, nil, nil,
+			"", "",
+		)
+	}
+	mock.ExpectQuery(`SELECT .+ FROM properties\s+WHERE province = ANY\(\$1\) .+ORDER BY featured DESC, created_at DESC`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	filters := domain.NewPropertySearchFilters()
+	filters.Provinces = []string{"Pichincha"}
+
+	// The callback's error stops the stream after the second row
+	var streamed []string
+	stop := errors.New("client disconnected")
+	repo := NewPostgreSQLPropertyRepository(db)
+	err := repo.StreamByFilters(filters, func(property *domain.Property) error {
+		streamed = append(streamed, property.ID)
+		assert.Equal(t, []string{"a.jpg"}, property.Images)
+		assert.Equal(t, []string{"pool"}, property.Tags)
+		if len(streamed) == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"id1", "id2"}, streamed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgreSQLPropertyRepository_ForTenant(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyRepository(db)
+	assert.Same(t, repo, repo.ForTenant(""), "single-tenant installs stay unscoped")
+	scoped := repo.ForTenant("beta")
+
+	// Properties of other tenants are not found, read or written
+	mock.ExpectQuery(`SELECT .+ FROM properties WHERE id = \$1 AND tenant_id = \$2`).
+		WithArgs("acme-property", "beta").
+		WillReturnError(sql.ErrNoRows)
+	_, err := scoped.GetByID("acme-property")
+	assert.ErrorContains(t, err, "property not found")
+
+	mock.ExpectQuery(`SELECT .+ FROM properties WHERE slug = \$1 AND tenant_id = \$2`).
+		WithArgs("casa-acme", "beta").
+		WillReturnError(sql.ErrNoRows)
+	_, err = scoped.GetBySlug("casa-acme")
+	assert.ErrorContains(t, err, "property not found")
+
+	mock.ExpectExec(`(?s)UPDATE properties SET .+WHERE id = \$1 AND tenant_id = \$49`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	property := createTestProperty()
+	property.ID = "acme-property"
+	assert.ErrorContains(t, scoped.Update(property), "property not found")
+
+	mock.ExpectExec(`DELETE FROM properties WHERE id = \$1 AND tenant_id = \$2`).
+		WithArgs("acme-property", "beta").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, scoped.Delete("acme-property"), "property not found")
+
+	// Listings and searches only see the tenant
+	mock.ExpectQuery(`SELECT .+ FROM properties\s+WHERE tenant_id = \$1 ORDER BY featured DESC`).
+		WithArgs("beta").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = scoped.GetAll()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`(?s)SELECT id, slug.+FROM properties.+AND tenant_id = \$15.+LIMIT \$14`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = scoped.AdvancedSearch(AdvancedSearchParams{Query: "casa"})
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`(?s)SELECT text, category, frequency FROM .+AND tenant_id = \$3`).
+		WithArgs("Qui", 10, "beta").
+		WillReturnRows(sqlmock.NewRows([]string{"text", "category", "frequency"}))
+	_, err = scoped.GetSearchSuggestions("Qui", 10)
+	assert.NoError(t, err)
+
+	// New properties are created in the tenant, whatever tenant they carry
+	property.TenantID = "acme"
+	mock.ExpectExec("INSERT INTO properties").
+		WithArgs(anyArgsExcept(51, 48, "beta")...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, scoped.Create(property))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// PropertyTranslationRepository defines the interface for the per-locale titles and
+// descriptions of properties
+type PropertyTranslationRepository interface {
+	// GetTranslations retrieves the translations of a property
+	GetTranslations(propertyID string) (domain.PropertyTranslations, error)
+
+	// GetTranslationsByIDs retrieves the translations of several properties by property ID.
+	// Properties without translations are left out.
+	GetTranslationsByIDs(propertyIDs []string) (map[string]domain.PropertyTranslations, error)
+
+	// SetTranslation adds or replaces the translation of a property into a locale
+	SetTranslation(propertyID, locale string, translation domain.PropertyTranslation) error
+
+	// DeleteTranslation removes the translation of a property into a locale
+	DeleteTranslation(propertyID, locale string) error
+}
+
+// PostgreSQLPropertyTranslationRepository implements PropertyTranslationRepository on the
+// translations JSONB column of properties
+type PostgreSQLPropertyTranslationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPropertyTranslationRepository creates a new PostgreSQL property translation repository
+func NewPostgreSQLPropertyTranslationRepository(db *sql.DB) *PostgreSQLPropertyTranslationRepository {
+	return &PostgreSQLPropertyTranslationRepository{db: db}
+}
+
+// GetTranslations retrieves the translations of a property
+func (r *PostgreSQLPropertyTranslationRepository) GetTranslations(propertyID string) (domain.PropertyTranslations, error) {
+	var raw []byte
+	err := r.db.QueryRow(`SELECT translations FROM properties WHERE id = $1`, propertyID).Scan(&raw)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("property not found: %s", propertyID)
+		}
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+
+	return decodeTranslations(raw)
+}
+
+// GetTranslationsByIDs retrieves the translations of several properties by property ID
+func (r *PostgreSQLPropertyTranslationRepository) GetTranslationsByIDs(propertyIDs []string) (map[string]domain.PropertyTranslations, error) {
+	result := map[string]domain.PropertyTranslations{}
+	if len(propertyIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, translations FROM properties
+		WHERE id = ANY($1) AND translations <> '{}'::jsonb`, pq.Array(propertyIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query translations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var raw []byte
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, fmt.Errorf("failed to scan translations: %w", err)
+		}
+		translations, err := decodeTranslations(raw)
+		if err != nil {
+			return nil, err
+		}
+		result[id] = translations
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetTranslation adds or replaces the translation of a property into a locale. The
+// English search vector is refreshed by the update trigger.
+func (r *PostgreSQLPropertyTranslationRepository) SetTranslation(propertyID, locale string, translation domain.PropertyTranslation) error {
+	value, err := json.Marshal(translation)
+	if err != nil {
+		return fmt.Errorf("failed to encode translation: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE properties
+		SET translations = jsonb_set(translations, ARRAY[$2::text], $3::jsonb), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, propertyID, locale, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to set translation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("property not found: %s", propertyID)
+	}
+	return nil
+}
+
+// DeleteTranslation removes the translation of a property into a locale
+func (r *PostgreSQLPropertyTranslationRepository) DeleteTranslation(propertyID, locale string) error {
+	result, err := r.db.Exec(`
+		UPDATE properties
+		SET translations = translations - $2::text, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND translations ? $2::text`, propertyID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete translation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("translation not found: %s/%s", propertyID, locale)
+	}
+	return nil
+}
+
+// decodeTranslations parses the translations column
+func decodeTranslations(raw []byte) (domain.PropertyTranslations, error) {
+	translations := domain.PropertyTranslations{}
+	if len(raw) == 0 {
+		return translations, nil
+	}
+	if err := json.Unmarshal(raw, &translations); err != nil {
+		return nil, fmt.Errorf("failed to decode translations: %w", err)
+	}
+	return translations, nil
+}
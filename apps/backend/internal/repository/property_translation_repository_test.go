@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestPropertyTranslationRepository_SetAndGet(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyTranslationRepository(db)
+
+	mock.ExpectExec("UPDATE properties SET translations = jsonb_set\\(translations, ARRAY\\[\\$2::text\\], \\$3::jsonb\\)").
+		WithArgs("prop-1", "en", `{"title":"Ocean view suite","description":"Two bedrooms"}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, translations FROM properties WHERE id = ANY\\(\\$1\\)").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "translations"}).
+			AddRow("prop-1", []byte(`{"en":{"title":"Ocean view suite","description":"Two bedrooms"}}`)))
+
+	err := repo.SetTranslation("prop-1", "en", domain.PropertyTranslation{Title: "Ocean view suite", Description: "Two bedrooms"})
+	require.NoError(t, err)
+
+	translations, err := repo.GetTranslationsByIDs([]string{"prop-1", "prop-2"})
+	require.NoError(t, err)
+	require.Contains(t, translations, "prop-1")
+	assert.NotContains(t, translations, "prop-2")
+	assert.Equal(t, "Ocean view suite", translations["prop-1"]["en"].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPropertyTranslationRepository_DeleteTranslation(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyTranslationRepository(db)
+
+	mock.ExpectExec("SET translations = translations - \\$2::text").
+		WithArgs("prop-1", "en").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorContains(t, repo.DeleteTranslation("prop-1", "en"), "translation not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPropertyRepository_AdvancedSearchPaginated_English(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyRepository(db)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM properties .*search_vector_en @@ plainto_tsquery\\('english', \\$1\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("ts_rank_cd\\(search_vector_en, plainto_tsquery\\('english', \\$1\\)\\).*FROM properties").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "slug", "title", "description", "price", "province", "city", "type",
+			"bedrooms", "bathrooms", "area_m2", "featured", "rank",
+		}).AddRow("prop-1", "casa-cumbaya", "Casa en Cumbayá", "Casa con jardín", 250000.0,
+			"Pichincha", "Quito", "house", 3, 2.5, 180.0, false, 0.4))
+
+	results, total, err := repo.AdvancedSearchPaginated(
+		AdvancedSearchParams{Query: "garden house", Locale: "en"},
+		domain.NewPaginationParams(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, results, 1)
+	assert.Equal(t, "prop-1", results[0].Property.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// propertyVersionLockClass namespaces the advisory locks that number property versions
+const propertyVersionLockClass = 7302
+
+// PropertyVersionRepository defines the interface for property version history
+type PropertyVersionRepository interface {
+	// Create stores a version, numbering it after the latest version of the property
+	Create(version *domain.PropertyVersion) error
+
+	// ListByProperty retrieves the latest versions of a property, newest first
+	ListByProperty(propertyID string, limit int) ([]domain.PropertyVersion, error)
+
+	// GetByVersion retrieves one version of a property
+	GetByVersion(propertyID string, version int) (*domain.PropertyVersion, error)
+}
+
+// PostgreSQLPropertyVersionRepository implements PropertyVersionRepository using PostgreSQL
+type PostgreSQLPropertyVersionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPropertyVersionRepository creates a new PostgreSQL property version repository
+func NewPostgreSQLPropertyVersionRepository(db *sql.DB) *PostgreSQLPropertyVersionRepository {
+	return &PostgreSQLPropertyVersionRepository{db: db}
+}
+
+const propertyVersionColumns = `id, property_id, version, change_type, snapshot, changes, changed_by,
+		reverted_from, created_at`
+
+// Create numbers and stores a version. Concurrent edits of a property wait on a
+// transaction-level advisory lock, so version numbers have no gaps or duplicates.
+func (r *PostgreSQLPropertyVersionRepository) Create(version *domain.PropertyVersion) error {
+	changes, err := json.Marshal(version.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode property changes: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, hashtext($2))`, propertyVersionLockClass, version.PropertyID); err != nil {
+		return fmt.Errorf("failed to lock property versions: %w", err)
+	}
+
+	query := `
+		INSERT INTO property_versions (` + propertyVersionColumns + `)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(version), 0) + 1 FROM property_versions WHERE property_id = $2),
+			$3, $4, $5, $6, $7, $8)
+		RETURNING version`
+
+	changedBy := sql.NullString{String: version.ChangedBy, Valid: version.ChangedBy != ""}
+	err = tx.QueryRow(query,
+		version.ID, version.PropertyID, version.ChangeType, []byte(version.Snapshot), changes,
+		changedBy, version.RevertedFrom, version.CreatedAt,
+	).Scan(&version.Version)
+	if err != nil {
+		return fmt.Errorf("failed to create property version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit property version: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProperty retrieves the latest versions of a property, newest first
+func (r *PostgreSQLPropertyVersionRepository) ListByProperty(propertyID string, limit int) ([]domain.PropertyVersion, error) {
+	query := `SELECT ` + propertyVersionColumns + ` FROM property_versions
+		WHERE property_id = $1
+		ORDER BY version DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, propertyID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []domain.PropertyVersion{}
+	for rows.Next() {
+		version, err := scanPropertyVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetByVersion retrieves one version of a property
+func (r *PostgreSQLPropertyVersionRepository) GetByVersion(propertyID string, number int) (*domain.PropertyVersion, error) {
+	query := `SELECT ` + propertyVersionColumns + ` FROM property_versions
+		WHERE property_id = $1 AND version = $2`
+
+	version, err := scanPropertyVersion(r.db.QueryRow(query, propertyID, number))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("property version not found: %s v%d", propertyID, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// scanPropertyVersion scans a row selected with propertyVersionColumns
+func scanPropertyVersion(row interface{ Scan(...interface{}) error }) (*domain.PropertyVersion, error) {
+	var version domain.PropertyVersion
+	var snapshot, changes []byte
+	var changedBy sql.NullString
+	var revertedFrom sql.NullInt64
+
+	err := row.Scan(&version.ID, &version.PropertyID, &version.Version, &version.ChangeType, &snapshot,
+		&changes, &changedBy, &revertedFrom, &version.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan property version: %w", err)
+	}
+
+	version.Snapshot = json.RawMessage(snapshot)
+	version.Changes = []domain.PropertyFieldChange{}
+	if len(changes) > 0 {
+		if err := json.Unmarshal(changes, &version.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode property changes: %w", err)
+		}
+	}
+	version.ChangedBy = changedBy.String
+	if revertedFrom.Valid {
+		from := int(revertedFrom.Int64)
+		version.RevertedFrom = &from
+	}
+
+	return &version, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestPropertyVersionRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPropertyVersionRepository(db)
+
+	version := &domain.PropertyVersion{
+		ID:         "ver-1",
+		PropertyID: "prop-1",
+		ChangeType: domain.PropertyChangeEdit,
+		Snapshot:   []byte(`{"title":"Casa"}`),
+		Changes:    []domain.PropertyFieldChange{{Field: "title", From: "Depto", To: "Casa"}},
+		CreatedAt:  time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(propertyVersionLockClass, "prop-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO property_versions`).
+		WithArgs("ver-1", "prop-1", domain.PropertyChangeEdit, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sql.NullString{}, nil, version.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Create(version))
+	assert.Equal(t, 3, version.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPropertyVersionRepository_GetByVersion(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLPropertyVersionRepository(db)
+
+	columns := []string{"id", "property_id", "version", "change_type", "snapshot", "changes", "changed_by", "reverted_from", "created_at"}
+	mock.ExpectQuery(`SELECT (.+) FROM property_versions`).WithArgs("prop-1", 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("ver-2", "prop-1", 2, domain.PropertyChangeRevert,
+			[]byte(`{"title":"Casa"}`), []byte(`[{"field":"title","from":"Depto","to":"Casa"}]`), "admin-1", 1, time.Now()))
+
+	version, err := repo.GetByVersion("prop-1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "admin-1", version.ChangedBy)
+	assert.Equal(t, 1, *version.RevertedFrom)
+	require.Len(t, version.Changes, 1)
+	assert.Equal(t, "title", version.Changes[0].Field)
+
+	mock.ExpectQuery(`SELECT (.+) FROM property_versions`).WithArgs("prop-1", 9).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetByVersion("prop-1", 9)
+	assert.ErrorContains(t, err, "property version not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"realty-core/internal/domain"
+)
+
+// propertyColumns are the columns of a property in the order scanProperty scans them
+const propertyColumns = `id, slug, title, description, price, province, city, sector, address,
+	latitude, longitude, location_precision, type, status, bedrooms, bathrooms, area_m2,
+	main_image, images, video_tour, tour_360,
+	rent_price, common_expenses, price_per_m2,
+	year_built, floors, property_status, furnished,
+	garage, pool, garden, terrace, balcony, security, elevator, air_conditioning,
+	tags, featured, view_count, real_estate_company_id,
+	created_at, updated_at, parking_spaces,
+	owner_id, agent_id, agency_id, created_by, updated_by,
+	description_html, description_raw`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProperty scans a row selected with propertyColumns. Errors of Scan are returned
+// unwrapped so callers can tell sql.ErrNoRows apart. Images and tags stored as invalid
+// JSON are an error when strict, and empty otherwise.
+func scanProperty(row rowScanner, strict bool) (*domain.Property, error) {
+	var property domain.Property
+	var imagesJSON, tagsJSON string
+
+	err := row.Scan(
+		&property.ID, &property.Slug, &property.Title, &property.Description, &property.Price,
+		&property.Province, &property.City, &property.Sector, &property.Address,
+		&property.Latitude, &property.Longitude, &property.LocationPrecision,
+		&property.Type, &property.Status, &property.Bedrooms, &property.Bathrooms, &property.AreaM2,
+		&property.MainImage, &imagesJSON, &property.VideoTour, &property.Tour360,
+		&property.RentPrice, &property.CommonExpenses, &property.PricePerM2,
+		&property.YearBuilt, &property.Floors, &property.PropertyStatus, &property.Furnished,
+		&property.Garage, &property.Pool, &property.Garden, &property.Terrace, &property.Balcony,
+		&property.Security, &property.Elevator, &property.AirConditioning,
+		&tagsJSON, &property.Featured, &property.ViewCount, &property.RealEstateCompanyID,
+		&property.CreatedAt, &property.UpdatedAt, &property.ParkingSpaces,
+		&property.OwnerID, &property.AgentID, &property.AgencyID, &property.CreatedBy, &property.UpdatedBy,
+		&property.DescriptionHTML, &property.DescriptionRaw,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows written before descriptions were sanitized are sanitized when read, until the
+	// sanitize-descriptions admin command rewrites them
+	if property.DescriptionHTML == "" && property.Description != "" {
+		property.NormalizeDescription()
+	}
+
+	if imagesJSON != "" {
+		if err := json.Unmarshal([]byte(imagesJSON), &property.Images); err != nil {
+			if strict {
+				return nil, fmt.Errorf("error converting images from JSON: %w", err)
+			}
+			property.Images = []string{}
+		}
+	}
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &property.Tags); err != nil {
+			if strict {
+				return nil, fmt.Errorf("error converting tags from JSON: %w", err)
+			}
+			property.Tags = []string{}
+		}
+	}
+
+	return &property, nil
+}
+
+// propertySortColumns are the columns properties may be sorted by; anything else sorts
+// by defaultSortColumn. Sort fields come from query strings, so they are never
+// interpolated into SQL without this lookup.
+var propertySortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+	"price":      "price",
+	"area_m2":    "area_m2",
+	"bedrooms":   "bedrooms",
+	"bathrooms":  "bathrooms",
+	"view_count": "view_count",
+}
+
+const defaultSortColumn = "created_at"
+
+// selectQuery builds a parameterized SELECT. Conditions and order terms are written with
+// ? placeholders, numbered $1, $2... in the order they appear in the query when it is
+// built, so clauses can be added in any order without tracking argument indexes. ?? is a
+// literal question mark, as in the jsonb operator ?&.
+type selectQuery struct {
+	columns   string
+	from      string
+	where     []clause
+	orderBy   []clause
+	paginated bool
+	limit     int
+	offset    int
+}
+
+// clause is SQL with ? placeholders and their arguments
+type clause struct {
+	sql  string
+	args []interface{}
+}
+
+func newClause(sql string, args []interface{}) clause {
+	if placeholders := countPlaceholders(sql); placeholders != len(args) {
+		panic(fmt.Sprintf("query builder: %q has %d placeholders and %d arguments", sql, placeholders, len(args)))
+	}
+	return clause{sql: sql, args: args}
+}
+
+// selectFrom starts a query selecting columns from a table
+func selectFrom(columns, from string) *selectQuery {
+	return &selectQuery{columns: columns, from: from}
+}
+
+// Where adds a condition, joined to the others with AND
+func (q *selectQuery) Where(condition string, args ...interface{}) *selectQuery {
+	q.where = append(q.where, newClause(condition, args))
+	return q
+}
+
+// OrderBy adds an order term
+func (q *selectQuery) OrderBy(term string, args ...interface{}) *selectQuery {
+	q.orderBy = append(q.orderBy, newClause(term, args))
+	return q
+}
+
+// OrderBySort adds the order term of the requested sort, restricted to propertySortColumns
+func (q *selectQuery) OrderBySort(pagination *domain.PaginationParams) *selectQuery {
+	column, ok := propertySortColumns[pagination.SortBy]
+	if !ok {
+		column = defaultSortColumn
+	}
+	direction := "ASC"
+	if pagination.SortDesc {
+		direction = "DESC"
+	}
+	return q.OrderBy(column + " " + direction)
+}
+
+// Paginate limits the query to a page
+func (q *selectQuery) Paginate(pagination *domain.PaginationParams) *selectQuery {
+	q.paginated = true
+	q.limit = pagination.GetLimit()
+	q.offset = pagination.GetOffset()
+	return q
+}
+
+// SQL returns the query and its arguments
+func (q *selectQuery) SQL() (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("SELECT ")
+	sb.WriteString(q.columns)
+	sb.WriteString("\n\tFROM ")
+	sb.WriteString(q.from)
+	q.writeWhere(&sb, &args)
+	if len(q.orderBy) > 0 {
+		sb.WriteString("\n\tORDER BY ")
+		for i, term := range q.orderBy {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeClause(&sb, &args, term)
+		}
+	}
+	if q.paginated {
+		sb.WriteString("\n\tLIMIT ")
+		writeClause(&sb, &args, clause{sql: "? OFFSET ?", args: []interface{}{q.limit, q.offset}})
+	}
+	return sb.String(), args
+}
+
+// CountSQL returns a COUNT(*) of the rows the query matches, ignoring order and page
+func (q *selectQuery) CountSQL() (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("SELECT COUNT(*) FROM ")
+	sb.WriteString(q.from)
+	q.writeWhere(&sb, &args)
+	return sb.String(), args
+}
+
+// WhereSQL returns the WHERE clause of the query and its arguments, empty without conditions
+func (q *selectQuery) WhereSQL() (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+
+	q.writeWhere(&sb, &args)
+	return strings.TrimPrefix(sb.String(), " "), args
+}
+
+func (q *selectQuery) writeWhere(sb *strings.Builder, args *[]interface{}) {
+	for i, condition := range q.where {
+		if i == 0 {
+			sb.WriteString(" WHERE ")
+		} else {
+			sb.WriteString(" AND ")
+		}
+		writeClause(sb, args, condition)
+	}
+}
+
+// writeClause writes a clause, numbering its placeholders after the arguments so far
+func writeClause(sb *strings.Builder, args *[]interface{}, c clause) {
+	for i := 0; i < len(c.sql); i++ {
+		if c.sql[i] != '?' {
+			sb.WriteByte(c.sql[i])
+			continue
+		}
+		if i+1 < len(c.sql) && c.sql[i+1] == '?' {
+			sb.WriteByte('?')
+			i++
+			continue
+		}
+		*args = append(*args, c.args[0])
+		c.args = c.args[1:]
+		fmt.Fprintf(sb, "$%d", len(*args))
+	}
+}
+
+// countPlaceholders counts the ? placeholders of SQL, skipping ?? escapes
+func countPlaceholders(sql string) int {
+	return strings.Count(strings.ReplaceAll(sql, "??", ""), "?")
+}
+
+// maxPreparedStatements bounds the statements a repository keeps prepared; filter
+// combinations are many, and queries beyond the bound run unprepared
+const maxPreparedStatements = 128
+
+// statementCache prepares queries on first use and reuses their statements, saving the
+// parse and plan of hot queries on every request. database/sql re-prepares statements
+// on each pooled connection as needed.
+type statementCache struct {
+	db         *sql.DB
+	mutex      sync.RWMutex
+	statements map[string]*sql.Stmt
+}
+
+func newStatementCache(db *sql.DB) *statementCache {
+	return &statementCache{db: db, statements: make(map[string]*sql.Stmt)}
+}
+
+// Prepare prepares a query ahead of its first use
+func (c *statementCache) Prepare(query string) error {
+	_, err := c.statement(query)
+	return err
+}
+
+// statement returns the prepared statement of a query, nil once the cache is full
+func (c *statementCache) statement(query string) (*sql.Stmt, error) {
+	c.mutex.RLock()
+	stmt, ok := c.statements[query]
+	c.mutex.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if stmt, ok := c.statements[query]; ok {
+		return stmt, nil
+	}
+	if len(c.statements) >= maxPreparedStatements {
+		return nil, nil
+	}
+	stmt, err := c.db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	c.statements[query] = stmt
+	return stmt, nil
+}
+
+// Query runs a query with its prepared statement
+func (c *statementCache) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := c.statement(query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.db.Query(query, args...)
+	}
+	return stmt.Query(args...)
+}
+
+// QueryRow runs a single-row query with its prepared statement
+func (c *statementCache) QueryRow(query string, args ...interface{}) rowScanner {
+	stmt, err := c.statement(query)
+	if err != nil {
+		return errorRow{err}
+	}
+	if stmt == nil {
+		return c.db.QueryRow(query, args...)
+	}
+	return stmt.QueryRow(args...)
+}
+
+// Close closes the prepared statements
+func (c *statementCache) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var firstErr error
+	for query, stmt := range c.statements {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.statements, query)
+	}
+	return firstErr
+}
+
+// errorRow is a row whose query failed before running
+type errorRow struct {
+	err error
+}
+
+func (r errorRow) Scan(dest ...interface{}) error {
+	return r.err
+}
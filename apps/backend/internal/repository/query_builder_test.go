@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestSelectQuery_NumbersPlaceholders(t *testing.T) {
+	pagination := domain.NewPaginationParams()
+	pagination.Page = 3
+	pagination.PageSize = 10
+	pagination.SortBy = "price"
+	pagination.SortDesc = false
+
+	q := selectFrom("id", "properties").
+		Where("province = ?", "Azuay").
+		Where("tags ??& ?", pq.Array([]string{"vista"})).
+		Where(publicStatusCondition).
+		OrderBy("ts_rank_cd(search_vector, plainto_tsquery('spanish', ?)) DESC", "casa").
+		OrderBySort(pagination).
+		Paginate(pagination)
+
+	query, args := q.SQL()
+	assert.Equal(t, "SELECT id\n\tFROM properties WHERE province = $1 AND tags ?& $2 AND status NOT IN ('expired', 'quarantined')"+
+		"\n\tORDER BY ts_rank_cd(search_vector, plainto_tsquery('spanish', $3)) DESC, price ASC\n\tLIMIT $4 OFFSET $5", query)
+	assert.Equal(t, []interface{}{"Azuay", pq.Array([]string{"vista"}), "casa", 10, 20}, args)
+
+	countQuery, countArgs := q.CountSQL()
+	assert.Equal(t, "SELECT COUNT(*) FROM properties WHERE province = $1 AND tags ?& $2 AND status NOT IN ('expired', 'quarantined')", countQuery)
+	assert.Len(t, countArgs, 2)
+
+	countQuery, countArgs = selectFrom("id", "properties").CountSQL()
+	assert.Equal(t, "SELECT COUNT(*) FROM properties", countQuery)
+	assert.Empty(t, countArgs)
+}
+
+func TestSelectQuery_RestrictsSortColumns(t *testing.T) {
+	pagination := domain.NewPaginationParams()
+	pagination.SortBy = "price; DROP TABLE properties"
+
+	query, _ := selectFrom("id", "properties").OrderBySort(pagination).SQL()
+	assert.Contains(t, query, "ORDER BY created_at DESC")
+	assert.NotContains(t, query, "DROP")
+}
+
+func TestSelectQuery_PanicsOnArgumentMismatch(t *testing.T) {
+	assert.Panics(t, func() { selectFrom("id", "properties").Where("price BETWEEN ? AND ?", 1) })
+	assert.NotPanics(t, func() { selectFrom("id", "properties").Where("tags ??| ?", pq.Array([]string{"a"})) })
+}
+
+func TestPostgreSQLPropertyRepository_PreparedStatements(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	byID := mock.ExpectPrepare(`SELECT .+ FROM properties WHERE id = \$1`)
+	mock.ExpectPrepare(`SELECT .+ FROM properties WHERE slug = \$1`)
+
+	repo := NewPostgreSQLPropertyRepository(db)
+	require.NoError(t, repo.EnablePreparedStatements())
+
+	// Lookups reuse the statements prepared at startup
+	byID.ExpectQuery().WithArgs("missing").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err := repo.GetByID("missing")
+	assert.ErrorContains(t, err, "property not found: missing")
+
+	// Listings are prepared on first use
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE province = \$1`).
+		WithArgs("Loja").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectPrepare(`SELECT .+ FROM properties WHERE province = \$1 .+ LIMIT \$2 OFFSET \$3`).
+		ExpectQuery().WithArgs("Loja", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, total, err := repo.GetByProvincePaginated("Loja", domain.NewPaginationParams())
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
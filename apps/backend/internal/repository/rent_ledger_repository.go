@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// RentLedgerRepository defines data access for the rent charges of leases
+type RentLedgerRepository interface {
+	// CreateCharges stores the scheduled charges of a lease
+	CreateCharges(charges []domain.RentCharge) error
+
+	// GetCharge retrieves a charge by ID
+	GetCharge(id string) (*domain.RentCharge, error)
+
+	// ListByDeals returns the charges of the given leases by due date
+	ListByDeals(dealIDs ...string) ([]domain.RentCharge, error)
+
+	// RecordPayment saves the payment of a charge not paid yet
+	RecordPayment(charge *domain.RentCharge) error
+
+	// ListPendingDueBefore returns the pending charges due before a date
+	ListPendingDueBefore(date time.Time) ([]domain.RentCharge, error)
+
+	// MarkOverdue marks a pending charge overdue, recording when its parties were notified
+	MarkOverdue(id string, notifiedAt time.Time) error
+}
+
+// PostgreSQLRentLedgerRepository implements RentLedgerRepository using PostgreSQL
+type PostgreSQLRentLedgerRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLRentLedgerRepository creates a new PostgreSQL rent ledger repository
+func NewPostgreSQLRentLedgerRepository(db *sql.DB) *PostgreSQLRentLedgerRepository {
+	return &PostgreSQLRentLedgerRepository{db: db}
+}
+
+const rentChargeColumns = `id, deal_id, property_id, COALESCE(tenant_id::text, ''), amount, period, due_date, status,
+	paid_amount, paid_at, COALESCE(payment_method, ''), payment_reference, COALESCE(recorded_by::text, ''),
+	overdue_notified_at, created_at, updated_at`
+
+// scanRentCharge scans a charge selected with rentChargeColumns
+func scanRentCharge(row interface{ Scan(...interface{}) error }) (*domain.RentCharge, error) {
+	charge := &domain.RentCharge{}
+	var paidAmount sql.NullFloat64
+	var paidAt, notifiedAt sql.NullTime
+	err := row.Scan(&charge.ID, &charge.DealID, &charge.PropertyID, &charge.TenantID, &charge.Amount,
+		&charge.Period, &charge.DueDate, &charge.Status, &paidAmount, &paidAt, &charge.PaymentMethod,
+		&charge.PaymentReference, &charge.RecordedBy, &notifiedAt, &charge.CreatedAt, &charge.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if paidAmount.Valid {
+		charge.PaidAmount = &paidAmount.Float64
+	}
+	if paidAt.Valid {
+		charge.PaidAt = &paidAt.Time
+	}
+	if notifiedAt.Valid {
+		charge.OverdueNotifiedAt = &notifiedAt.Time
+	}
+	return charge, nil
+}
+
+// CreateCharges stores the scheduled charges of a lease
+func (r *PostgreSQLRentLedgerRepository) CreateCharges(charges []domain.RentCharge) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO rent_charges (id, deal_id, property_id, tenant_id, amount, period, due_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (deal_id, period) DO NOTHING`
+	for _, charge := range charges {
+		result, err := tx.Exec(query, charge.ID, charge.DealID, charge.PropertyID, charge.TenantID, charge.Amount,
+			charge.Period, charge.DueDate, charge.Status, charge.CreatedAt, charge.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create rent charge: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("rent already scheduled for %s", charge.Period.Format("2006-01"))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rent charges: %w", err)
+	}
+	return nil
+}
+
+// GetCharge retrieves a charge by ID
+func (r *PostgreSQLRentLedgerRepository) GetCharge(id string) (*domain.RentCharge, error) {
+	query := `SELECT ` + rentChargeColumns + ` FROM rent_charges WHERE id = $1`
+
+	charge, err := scanRentCharge(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("rent charge not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rent charge: %w", err)
+	}
+	return charge, nil
+}
+
+// ListByDeals returns the charges of the given leases by due date
+func (r *PostgreSQLRentLedgerRepository) ListByDeals(dealIDs ...string) ([]domain.RentCharge, error) {
+	if len(dealIDs) == 0 {
+		return []domain.RentCharge{}, nil
+	}
+	query := `SELECT ` + rentChargeColumns + ` FROM rent_charges WHERE deal_id = ANY($1) ORDER BY due_date, deal_id`
+	return r.list(query, pq.Array(dealIDs))
+}
+
+// RecordPayment saves the payment of a charge not paid yet
+func (r *PostgreSQLRentLedgerRepository) RecordPayment(charge *domain.RentCharge) error {
+	query := `
+		UPDATE rent_charges
+		SET status = $2, paid_amount = $3, paid_at = $4, payment_method = $5, payment_reference = $6,
+			recorded_by = NULLIF($7, '')::uuid, updated_at = $8
+		WHERE id = $1 AND status <> $2`
+
+	result, err := r.db.Exec(query, charge.ID, domain.RentChargePaid, charge.PaidAmount, charge.PaidAt,
+		charge.PaymentMethod, charge.PaymentReference, charge.RecordedBy, charge.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record rent payment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("rent charge already paid: %s", charge.ID)
+	}
+	return nil
+}
+
+// ListPendingDueBefore returns the pending charges due before a date
+func (r *PostgreSQLRentLedgerRepository) ListPendingDueBefore(date time.Time) ([]domain.RentCharge, error) {
+	query := `SELECT ` + rentChargeColumns + ` FROM rent_charges
+		WHERE status = $1 AND due_date < $2 ORDER BY due_date`
+	return r.list(query, domain.RentChargePending, date)
+}
+
+// MarkOverdue marks a pending charge overdue, recording when its parties were notified
+func (r *PostgreSQLRentLedgerRepository) MarkOverdue(id string, notifiedAt time.Time) error {
+	query := `
+		UPDATE rent_charges SET status = $2, overdue_notified_at = $3, updated_at = $3
+		WHERE id = $1 AND status = $4`
+
+	if _, err := r.db.Exec(query, id, domain.RentChargeOverdue, notifiedAt, domain.RentChargePending); err != nil {
+		return fmt.Errorf("failed to mark rent charge overdue: %w", err)
+	}
+	return nil
+}
+
+// list runs a query selecting rentChargeColumns
+func (r *PostgreSQLRentLedgerRepository) list(query string, args ...interface{}) ([]domain.RentCharge, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rent charges: %w", err)
+	}
+	defer rows.Close()
+
+	charges := []domain.RentCharge{}
+	for rows.Next() {
+		charge, err := scanRentCharge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rent charge: %w", err)
+		}
+		charges = append(charges, *charge)
+	}
+	return charges, rows.Err()
+}
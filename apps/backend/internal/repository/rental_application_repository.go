@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+// RentalApplicationRepository defines the interface for rental application operations
+type RentalApplicationRepository interface {
+	// Create saves a new application
+	Create(application *domain.RentalApplication) error
+
+	// GetByID retrieves an application by ID
+	GetByID(id string) (*domain.RentalApplication, error)
+
+	// ListByProperty retrieves the applications of a property, newest first, optionally by
+	// status. Applications quarantined as spam are left out.
+	ListByProperty(propertyID, status string) ([]domain.RentalApplication, error)
+
+	// ListByApplicant retrieves the applications submitted by a user, newest first
+	ListByApplicant(applicantID string) ([]domain.RentalApplication, error)
+
+	// HasOpenApplication reports whether the applicant awaits a decision on the property
+	HasOpenApplication(propertyID, applicantID string) (bool, error)
+
+	// UpdateStatus saves the status and decision of an application that is still in fromStatus
+	UpdateStatus(application *domain.RentalApplication, fromStatus string) error
+
+	// AddDocument appends a supporting document to an application
+	AddDocument(applicationID string, document domain.ApplicationDocument) error
+}
+
+// PostgreSQLRentalApplicationRepository implements RentalApplicationRepository using PostgreSQL
+type PostgreSQLRentalApplicationRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+// NewPostgreSQLRentalApplicationRepository creates a new PostgreSQL rental application repository
+func NewPostgreSQLRentalApplicationRepository(db *sql.DB) *PostgreSQLRentalApplicationRepository {
+	return &PostgreSQLRentalApplicationRepository{db: db}
+}
+
+// SetCipher encrypts application messages at rest; applicants often leave their
+// phone number there
+func (r *PostgreSQLRentalApplicationRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+const rentalApplicationColumns = `id, property_id, applicant_id, status, monthly_income, employer, occupation,
+		occupants, has_pets, move_in_date, message, references_list, documents, decision_reason,
+		reviewed_by, reviewed_at, created_at, updated_at`
+
+// Create saves a new application
+func (r *PostgreSQLRentalApplicationRepository) Create(application *domain.RentalApplication) error {
+	if application == nil {
+		return fmt.Errorf("application cannot be nil")
+	}
+
+	references, err := json.Marshal(application.References)
+	if err != nil {
+		return fmt.Errorf("failed to encode references: %w", err)
+	}
+	documents, err := json.Marshal(application.Documents)
+	if err != nil {
+		return fmt.Errorf("failed to encode documents: %w", err)
+	}
+	message, err := r.cipher.Encrypt(pii.RentalApplicationMessage, application.ID, application.Message)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
+	query := `
+		INSERT INTO rental_applications (` + rentalApplicationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err = r.db.Exec(query,
+		application.ID, application.PropertyID, application.ApplicantID, application.Status,
+		application.MonthlyIncome, application.Employer, application.Occupation, application.Occupants,
+		application.HasPets, application.MoveInDate, message, references, documents,
+		application.DecisionReason, application.ReviewedBy, application.ReviewedAt,
+		application.CreatedAt, application.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create application: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an application by ID
+func (r *PostgreSQLRentalApplicationRepository) GetByID(id string) (*domain.RentalApplication, error) {
+	if id == "" {
+		return nil, fmt.Errorf("application ID cannot be empty")
+	}
+
+	query := `SELECT ` + rentalApplicationColumns + ` FROM rental_applications WHERE id = $1`
+
+	application, err := scanRentalApplication(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("application not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+
+	if err := r.openMessage(application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+// ListByProperty retrieves the applications of a property, newest first. Applications
+// quarantined as spam are left out until an administrator approves them.
+func (r *PostgreSQLRentalApplicationRepository) ListByProperty(propertyID, status string) ([]domain.RentalApplication, error) {
+	query := `SELECT ` + rentalApplicationColumns + ` FROM rental_applications
+		WHERE property_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM spam_cases sc
+			WHERE sc.entity_type = 'inquiry' AND sc.entity_id = rental_applications.id AND sc.status <> 'approved'
+		)`
+	args := []interface{}{propertyID}
+	if status != "" {
+		query += ` AND status = $2`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	return r.list(query, args...)
+}
+
+// ListByApplicant retrieves the applications submitted by a user, newest first
+func (r *PostgreSQLRentalApplicationRepository) ListByApplicant(applicantID string) ([]domain.RentalApplication, error) {
+	query := `SELECT ` + rentalApplicationColumns + ` FROM rental_applications
+		WHERE applicant_id = $1 ORDER BY created_at DESC`
+
+	return r.list(query, applicantID)
+}
+
+// HasOpenApplication reports whether the applicant awaits a decision on the property
+func (r *PostgreSQLRentalApplicationRepository) HasOpenApplication(propertyID, applicantID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM rental_applications
+			WHERE property_id = $1 AND applicant_id = $2 AND status IN ('submitted', 'under_review')
+		)`
+
+	var exists bool
+	if err := r.db.QueryRow(query, propertyID, applicantID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check open applications: %w", err)
+	}
+	return exists, nil
+}
+
+// UpdateStatus saves the status and decision of an application that is still in fromStatus
+func (r *PostgreSQLRentalApplicationRepository) UpdateStatus(application *domain.RentalApplication, fromStatus string) error {
+	query := `
+		UPDATE rental_applications
+		SET status = $3, decision_reason = $4, reviewed_by = $5, reviewed_at = $6, updated_at = $7
+		WHERE id = $1 AND status = $2`
+
+	result, err := r.db.Exec(query,
+		application.ID, fromStatus, application.Status, application.DecisionReason,
+		application.ReviewedBy, application.ReviewedAt, application.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update application status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("application not found or no longer %s: %s", fromStatus, application.ID)
+	}
+
+	return nil
+}
+
+// AddDocument appends a supporting document to an application
+func (r *PostgreSQLRentalApplicationRepository) AddDocument(applicationID string, document domain.ApplicationDocument) error {
+	encoded, err := json.Marshal([]domain.ApplicationDocument{document})
+	if err != nil {
+		return fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	query := `
+		UPDATE rental_applications
+		SET documents = documents || $2::jsonb, updated_at = $3
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, applicationID, encoded, document.UploadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add application document: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("application not found: %s", applicationID)
+	}
+
+	return nil
+}
+
+// list runs a query selecting rentalApplicationColumns
+func (r *PostgreSQLRentalApplicationRepository) list(query string, args ...interface{}) ([]domain.RentalApplication, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []domain.RentalApplication{}
+	for rows.Next() {
+		application, err := scanRentalApplication(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		if err := r.openMessage(application); err != nil {
+			return nil, err
+		}
+		applications = append(applications, *application)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return applications, nil
+}
+
+// openMessage decrypts the message of a scanned application
+func (r *PostgreSQLRentalApplicationRepository) openMessage(application *domain.RentalApplication) error {
+	message, err := r.cipher.Decrypt(pii.RentalApplicationMessage, application.ID, application.Message)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt message of application %s: %w", application.ID, err)
+	}
+	application.Message = message
+	return nil
+}
+
+// scanRentalApplication scans a row selected with rentalApplicationColumns
+func scanRentalApplication(row interface{ Scan(...interface{}) error }) (*domain.RentalApplication, error) {
+	application := &domain.RentalApplication{}
+	var moveInDate, reviewedAt sql.NullTime
+	var reviewedBy sql.NullString
+	var references, documents []byte
+	err := row.Scan(
+		&application.ID, &application.PropertyID, &application.ApplicantID, &application.Status,
+		&application.MonthlyIncome, &application.Employer, &application.Occupation, &application.Occupants,
+		&application.HasPets, &moveInDate, &application.Message, &references, &documents,
+		&application.DecisionReason, &reviewedBy, &reviewedAt, &application.CreatedAt, &application.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if moveInDate.Valid {
+		application.MoveInDate = &moveInDate.Time
+	}
+	if reviewedBy.Valid {
+		application.ReviewedBy = &reviewedBy.String
+	}
+	if reviewedAt.Valid {
+		application.ReviewedAt = &reviewedAt.Time
+	}
+	if err := json.Unmarshal(references, &application.References); err != nil {
+		return nil, fmt.Errorf("failed to decode references: %w", err)
+	}
+	if err := json.Unmarshal(documents, &application.Documents); err != nil {
+		return nil, fmt.Errorf("failed to decode documents: %w", err)
+	}
+
+	return application, nil
+}
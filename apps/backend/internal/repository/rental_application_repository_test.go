@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestRentalApplicationRepository_GetByID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLRentalApplicationRepository(db)
+	now := time.Now()
+	columns := []string{
+		"id", "property_id", "applicant_id", "status", "monthly_income", "employer", "occupation",
+		"occupants", "has_pets", "move_in_date", "message", "references_list", "documents", "decision_reason",
+		"reviewed_by", "reviewed_at", "created_at", "updated_at",
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM rental_applications WHERE id = \\$1").
+		WithArgs("app-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			"app-1", "prop-1", "user-1", "submitted", 2400.0, "Banco Pichincha", "Analista",
+			2, false, nil, "", []byte(`[{"name":"Ana","relationship":"landlord","phone":"0991234567"}]`),
+			[]byte(`[{"id":"doc-1","name":"Rol de pagos","file_name":"rol.pdf","content_type":"application/pdf","size":10,"storage_path":"applications/app-1/doc-1.pdf"}]`),
+			"", nil, nil, now, now))
+	mock.ExpectQuery("SELECT (.+) FROM rental_applications WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	application, err := repo.GetByID("app-1")
+	require.NoError(t, err)
+	require.Len(t, application.References, 1)
+	assert.Equal(t, "Ana", application.References[0].Name)
+	document, ok := application.Document("doc-1")
+	require.True(t, ok)
+	assert.Equal(t, "applications/app-1/doc-1.pdf", document.StoragePath)
+	assert.Nil(t, application.ReviewedBy)
+
+	_, err = repo.GetByID("missing")
+	assert.ErrorContains(t, err, "application not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRentalApplicationRepository_UpdateStatus(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLRentalApplicationRepository(db)
+	now := time.Now()
+	reviewer := "owner-1"
+	application := &domain.RentalApplication{
+		ID: "app-1", Status: domain.ApplicationStatusAccepted, ReviewedBy: &reviewer, ReviewedAt: &now, UpdatedAt: now,
+	}
+
+	mock.ExpectExec("UPDATE rental_applications(.+)WHERE id = \\$1 AND status = \\$2").
+		WithArgs("app-1", "submitted", "accepted", "", &reviewer, &now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE rental_applications(.+)WHERE id = \\$1 AND status = \\$2").
+		WithArgs("app-1", "submitted", "accepted", "", &reviewer, &now, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, repo.UpdateStatus(application, domain.ApplicationStatusSubmitted))
+	assert.ErrorContains(t, repo.UpdateStatus(application, domain.ApplicationStatusSubmitted), "no longer submitted")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+// ReservationRepository defines the interface for property reservations
+type ReservationRepository interface {
+	// Create stores an active reservation and applies the change that reserves its
+	// property in one transaction
+	Create(reservation *domain.PropertyReservation, change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error
+
+	// GetByID retrieves a reservation by ID
+	GetByID(id string) (*domain.PropertyReservation, error)
+
+	// GetActiveByProperty retrieves the active reservation of a property
+	GetActiveByProperty(propertyID string) (*domain.PropertyReservation, error)
+
+	// ListByProperty lists the reservations of a property, latest first
+	ListByProperty(propertyID string) ([]domain.PropertyReservation, error)
+
+	// Resolve saves how a reservation ended. A non-nil change is applied to its property
+	// in the same transaction, e.g. the return to available of an expired reservation.
+	Resolve(reservation *domain.PropertyReservation, change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error
+
+	// ListActiveExpiringBefore lists active reservations that expire before a time
+	ListActiveExpiringBefore(before time.Time) ([]domain.PropertyReservation, error)
+
+	// MarkReminded records that the agent was reminded of a reservation's expiry
+	MarkReminded(id string, at time.Time) error
+}
+
+// PostgreSQLReservationRepository implements ReservationRepository using PostgreSQL
+type PostgreSQLReservationRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+// NewPostgreSQLReservationRepository creates a new PostgreSQL reservation repository
+func NewPostgreSQLReservationRepository(db *sql.DB) *PostgreSQLReservationRepository {
+	return &PostgreSQLReservationRepository{db: db}
+}
+
+// SetCipher encrypts the email and phone of reservation leads at rest
+func (r *PostgreSQLReservationRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+const reservationColumns = `id, property_id, agency_id, agent_id, lead_name, lead_email, lead_phone, lead_user_id,
+	notes, status, expires_at, reminder_sent_at, created_by, created_at, resolved_at`
+
+// Create stores an active reservation and reserves its property
+func (r *PostgreSQLReservationRepository) Create(reservation *domain.PropertyReservation, change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	if reservation == nil || change == nil {
+		return fmt.Errorf("reservation and status change cannot be nil")
+	}
+
+	leadEmail, err := r.cipher.Encrypt(pii.ReservationLeadEmail, reservation.ID, reservation.Lead.Email)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt lead email: %w", err)
+	}
+	leadPhone, err := r.cipher.Encrypt(pii.ReservationLeadPhone, reservation.ID, reservation.Lead.Phone)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt lead phone: %w", err)
+	}
+
+	return r.inTransaction(func(tx *sql.Tx) error {
+		if err := applyStatusChange(tx, change); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO property_reservations (` + reservationColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+		_, err := tx.Exec(query,
+			reservation.ID, reservation.PropertyID, reservation.AgencyID, reservation.AgentID,
+			reservation.Lead.Name, leadEmail, leadPhone, reservation.Lead.UserID,
+			reservation.Notes, reservation.Status, reservation.ExpiresAt, reservation.ReminderSentAt,
+			reservation.CreatedBy, reservation.CreatedAt, reservation.ResolvedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+
+		return insertOutboxEvents(tx, events)
+	})
+}
+
+// GetByID retrieves a reservation by ID
+func (r *PostgreSQLReservationRepository) GetByID(id string) (*domain.PropertyReservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM property_reservations WHERE id = $1`
+
+	reservation, err := r.scanReservation(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reservation not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return reservation, nil
+}
+
+// GetActiveByProperty retrieves the active reservation of a property
+func (r *PostgreSQLReservationRepository) GetActiveByProperty(propertyID string) (*domain.PropertyReservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM property_reservations
+		WHERE property_id = $1 AND status = 'active'`
+
+	reservation, err := r.scanReservation(r.db.QueryRow(query, propertyID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("active reservation not found for property: %s", propertyID)
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return reservation, nil
+}
+
+// ListByProperty lists the reservations of a property, latest first
+func (r *PostgreSQLReservationRepository) ListByProperty(propertyID string) ([]domain.PropertyReservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM property_reservations
+		WHERE property_id = $1 ORDER BY created_at DESC`
+	return r.list(query, propertyID)
+}
+
+// Resolve saves how a reservation ended, applying a status change to its property
+func (r *PostgreSQLReservationRepository) Resolve(reservation *domain.PropertyReservation, change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	if reservation == nil {
+		return fmt.Errorf("reservation cannot be nil")
+	}
+
+	return r.inTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE property_reservations SET status = $2, resolved_at = $3
+			WHERE id = $1 AND status = 'active'`, reservation.ID, reservation.Status, reservation.ResolvedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update reservation: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("active reservation not found: %s", reservation.ID)
+		}
+
+		if change != nil {
+			if err := applyStatusChange(tx, change); err != nil {
+				return err
+			}
+		}
+		return insertOutboxEvents(tx, events)
+	})
+}
+
+// ListActiveExpiringBefore lists active reservations that expire before a time, soonest first
+func (r *PostgreSQLReservationRepository) ListActiveExpiringBefore(before time.Time) ([]domain.PropertyReservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM property_reservations
+		WHERE status = 'active' AND expires_at <= $1 ORDER BY expires_at`
+	return r.list(query, before)
+}
+
+// MarkReminded records that the agent was reminded of a reservation's expiry
+func (r *PostgreSQLReservationRepository) MarkReminded(id string, at time.Time) error {
+	result, err := r.db.Exec(`UPDATE property_reservations SET reminder_sent_at = $2 WHERE id = $1`, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to update reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("reservation not found: %s", id)
+	}
+	return nil
+}
+
+func (r *PostgreSQLReservationRepository) list(query string, args ...interface{}) ([]domain.PropertyReservation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := []domain.PropertyReservation{}
+	for rows.Next() {
+		reservation, err := r.scanReservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, *reservation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+func (r *PostgreSQLReservationRepository) inTransaction(write func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := write(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// scanReservation scans a row selected with reservationColumns and decrypts the lead's
+// contact details
+func (r *PostgreSQLReservationRepository) scanReservation(row interface{ Scan(...interface{}) error }) (*domain.PropertyReservation, error) {
+	reservation := &domain.PropertyReservation{}
+	var agencyID, agentID, leadUserID sql.NullString
+	var reminderSentAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&reservation.ID, &reservation.PropertyID, &agencyID, &agentID,
+		&reservation.Lead.Name, &reservation.Lead.Email, &reservation.Lead.Phone, &leadUserID,
+		&reservation.Notes, &reservation.Status, &reservation.ExpiresAt, &reminderSentAt,
+		&reservation.CreatedBy, &reservation.CreatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Lead.Email, err = r.cipher.Decrypt(pii.ReservationLeadEmail, reservation.ID, reservation.Lead.Email); err != nil {
+		return nil, fmt.Errorf("failed to decrypt lead email of reservation %s: %w", reservation.ID, err)
+	}
+	if reservation.Lead.Phone, err = r.cipher.Decrypt(pii.ReservationLeadPhone, reservation.ID, reservation.Lead.Phone); err != nil {
+		return nil, fmt.Errorf("failed to decrypt lead phone of reservation %s: %w", reservation.ID, err)
+	}
+
+	if agencyID.Valid {
+		reservation.AgencyID = &agencyID.String
+	}
+	if agentID.Valid {
+		reservation.AgentID = &agentID.String
+	}
+	if leadUserID.Valid {
+		reservation.Lead.UserID = &leadUserID.String
+	}
+	if reminderSentAt.Valid {
+		reservation.ReminderSentAt = &reminderSentAt.Time
+	}
+	if resolvedAt.Valid {
+		reservation.ResolvedAt = &resolvedAt.Time
+	}
+	return reservation, nil
+}
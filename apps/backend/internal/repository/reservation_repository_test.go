@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/pii"
+)
+
+var reservationRowColumns = []string{"id", "property_id", "agency_id", "agent_id", "lead_name", "lead_email",
+	"lead_phone", "lead_user_id", "notes", "status", "expires_at", "reminder_sent_at", "created_by", "created_at",
+	"resolved_at"}
+
+func TestReservationRepository_EncryptsLeadContact(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	keys, err := pii.ParseKeySet("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	cipher, err := pii.NewCipher(keys)
+	require.NoError(t, err)
+	repo := NewPostgreSQLReservationRepository(db)
+	repo.SetCipher(cipher)
+
+	now := time.Now()
+	reservation := &domain.PropertyReservation{ID: "reservation-1", PropertyID: "property-1",
+		Lead:   domain.ReservationLead{Name: "Lucía Andrade", Email: "lucia@example.com", Phone: "+593991234567"},
+		Status: domain.ReservationActive, ExpiresAt: now.AddDate(0, 0, 7), CreatedBy: "agent-1", CreatedAt: now}
+	change := &domain.PropertyStatusChange{ID: "change-1", PropertyID: "property-1", FromStatus: "available",
+		ToStatus: "reserved", ChangedBy: "agent-1", ChangedAt: now}
+
+	var email, phone string
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE properties SET status").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO property_status_changes").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO property_reservations").
+		WithArgs("reservation-1", "property-1", nil, nil, "Lucía Andrade", encryptedArg{&email}, encryptedArg{&phone},
+			nil, "", domain.ReservationActive, reservation.ExpiresAt, nil, "agent-1", now, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.Create(reservation, change))
+
+	mock.ExpectQuery(`SELECT (.+) FROM property_reservations WHERE id = \$1`).
+		WithArgs("reservation-1").
+		WillReturnRows(sqlmock.NewRows(reservationRowColumns).
+			AddRow("reservation-1", "property-1", nil, nil, "Lucía Andrade", email, phone, nil, "",
+				"active", reservation.ExpiresAt, nil, "agent-1", now, nil))
+	found, err := repo.GetByID("reservation-1")
+	require.NoError(t, err)
+	assert.Equal(t, "lucia@example.com", found.Lead.Email)
+	assert.Equal(t, "+593991234567", found.Lead.Phone)
+
+	// Rows written before encryption are read as they are until re-encrypted
+	mock.ExpectQuery(`SELECT (.+) FROM property_reservations\s+WHERE property_id = \$1 ORDER BY created_at DESC`).
+		WithArgs("property-1").
+		WillReturnRows(sqlmock.NewRows(reservationRowColumns).
+			AddRow("reservation-0", "property-1", nil, nil, "Marco Vera", "marco@example.com", "", nil, "",
+				"released", now, nil, "agent-1", now, now))
+	listed, err := repo.ListByProperty("property-1")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "marco@example.com", listed[0].Lead.Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// RetentionRepository counts and deletes the records of a retention target older than
+// a cutoff
+type RetentionRepository interface {
+	// CountExpired counts the records of a target older than cutoff
+	CountExpired(target string, cutoff time.Time) (int64, error)
+
+	// DeleteExpired deletes up to limit records of a target older than cutoff and returns
+	// how many were deleted
+	DeleteExpired(target string, cutoff time.Time, limit int) (int64, error)
+}
+
+// retentionTable is where the records of a retention target live: the table, the time
+// their age is measured from and which of them may be pruned at all
+type retentionTable struct {
+	table     string
+	column    string
+	condition string
+}
+
+// retentionTables maps retention targets to their tables. Pending webhook deliveries
+// and running jobs are never pruned, whatever their age.
+var retentionTables = map[string]retentionTable{
+	domain.RetentionAuditLog:          {table: "audit_log", column: "created_at"},
+	domain.RetentionLoginEvents:       {table: "login_events", column: "created_at"},
+	domain.RetentionJobRuns:           {table: "job_runs", column: "started_at", condition: "status <> 'running'"},
+	domain.RetentionWebhookDeliveries: {table: "webhook_deliveries", column: "created_at", condition: "status <> 'pending'"},
+	domain.RetentionHoneytokenHits:    {table: "honeytoken_hits", column: "created_at"},
+	domain.RetentionCSPViolations:     {table: "csp_violations", column: "last_seen_at"},
+	domain.RetentionShortLinkClicks:   {table: "short_link_clicks", column: "clicked_at"},
+	domain.RetentionSearchQueries:     {table: "search_query_stats", column: "last_searched_at"},
+	domain.RetentionPropertyDrafts:    {table: "property_drafts", column: "updated_at"},
+}
+
+// where returns the condition selecting the expired records, with the cutoff as $1
+func (t retentionTable) where() string {
+	where := t.column + " < $1"
+	if t.condition != "" {
+		where += " AND " + t.condition
+	}
+	return where
+}
+
+// PostgreSQLRetentionRepository implements RetentionRepository using PostgreSQL
+type PostgreSQLRetentionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLRetentionRepository creates a new PostgreSQL retention repository
+func NewPostgreSQLRetentionRepository(db *sql.DB) *PostgreSQLRetentionRepository {
+	return &PostgreSQLRetentionRepository{db: db}
+}
+
+// CountExpired counts the records of a target older than cutoff
+func (r *PostgreSQLRetentionRepository) CountExpired(target string, cutoff time.Time) (int64, error) {
+	table, ok := retentionTables[target]
+	if !ok {
+		return 0, fmt.Errorf("invalid retention target: %s", target)
+	}
+
+	var count int64
+	query := `SELECT COUNT(*) FROM ` + table.table + ` WHERE ` + table.where()
+	if err := r.db.QueryRow(query, cutoff).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expired %s: %w", target, err)
+	}
+	return count, nil
+}
+
+// DeleteExpired deletes up to limit records of a target older than cutoff, by physical
+// row so tables without a single-column key are pruned alike
+func (r *PostgreSQLRetentionRepository) DeleteExpired(target string, cutoff time.Time, limit int) (int64, error) {
+	table, ok := retentionTables[target]
+	if !ok {
+		return 0, fmt.Errorf("invalid retention target: %s", target)
+	}
+
+	query := `DELETE FROM ` + table.table + ` WHERE ctid IN (
+		SELECT ctid FROM ` + table.table + ` WHERE ` + table.where() + ` LIMIT $2)`
+	result, err := r.db.Exec(query, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired %s: %w", target, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
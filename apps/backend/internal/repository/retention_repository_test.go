@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestRetentionRepository_CountAndDeleteExpired(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLRetentionRepository(db)
+	cutoff := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM webhook_deliveries WHERE created_at < $1 AND status <> 'pending'`)).
+		WithArgs(cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+	count, err := repo.CountExpired(domain.RetentionWebhookDeliveries, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+
+	mock.ExpectExec(`DELETE FROM job_runs WHERE ctid IN \(\s*SELECT ctid FROM job_runs WHERE started_at < \$1 AND status <> 'running' LIMIT \$2\)`).
+		WithArgs(cutoff, 500).
+		WillReturnResult(sqlmock.NewResult(0, 17))
+	deleted, err := repo.DeleteExpired(domain.RetentionJobRuns, cutoff, 500)
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), deleted)
+
+	_, err = repo.CountExpired("users", cutoff)
+	assert.Error(t, err, "only retention targets are pruned")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
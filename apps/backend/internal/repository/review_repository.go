@@ -0,0 +1,450 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// ReviewRepository defines the interface for review operations
+type ReviewRepository interface {
+	// Create stores a review
+	Create(review *domain.Review) error
+
+	// Update stores the rating, comment and moderation of a review
+	Update(review *domain.Review) error
+
+	// GetByID retrieves a review
+	GetByID(id string) (*domain.Review, error)
+
+	// GetByReviewer retrieves the review of a subject left by a user
+	GetByReviewer(subjectType, subjectID, reviewerID string) (*domain.Review, error)
+
+	// List retrieves reviews matching a filter, newest first, with the total count
+	List(filter domain.ReviewFilter) ([]domain.Review, int, error)
+
+	// Summary aggregates the published reviews of a subject
+	Summary(subjectType, subjectID string) (*domain.ReviewSummary, error)
+
+	// Delete removes a review
+	Delete(id string) error
+
+	// SaveReputation creates or replaces the reputation of a subject
+	SaveReputation(reputation *domain.Reputation) error
+
+	// GetReputation retrieves the reputation of a subject
+	GetReputation(subjectType, subjectID string) (*domain.Reputation, error)
+
+	// CreateReport stores a report; a second report of the same review by the same
+	// user fails with "review already reported"
+	CreateReport(report *domain.ReviewReport) error
+
+	// GetReport retrieves a report
+	GetReport(id string) (*domain.ReviewReport, error)
+
+	// ListReports retrieves reports in a status, oldest first, with the total count
+	ListReports(status string, limit, offset int) ([]domain.ReviewReport, int, error)
+
+	// CountOpenReports counts the open reports of a review
+	CountOpenReports(reviewID string) (int, error)
+
+	// ResolveReports closes the open reports of a review with a status and returns how
+	// many were closed
+	ResolveReports(reviewID, status, resolvedBy string, at time.Time) (int64, error)
+}
+
+// PostgreSQLReviewRepository implements ReviewRepository using PostgreSQL
+type PostgreSQLReviewRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLReviewRepository creates a new PostgreSQL review repository
+func NewPostgreSQLReviewRepository(db *sql.DB) *PostgreSQLReviewRepository {
+	return &PostgreSQLReviewRepository{db: db}
+}
+
+const reviewColumns = `r.id, r.subject_type, r.subject_id, r.reviewer_id,
+		COALESCE(u.first_name, ''), COALESCE(u.last_name, ''), r.deal_id, r.application_id, r.rating, r.comment, r.status,
+		r.moderation_note, r.moderated_by, r.moderated_at, r.created_at, r.updated_at`
+
+const reviewFrom = ` FROM reviews r LEFT JOIN users u ON u.id = r.reviewer_id`
+
+// Create stores a review
+func (r *PostgreSQLReviewRepository) Create(review *domain.Review) error {
+	query := `
+		INSERT INTO reviews (id, subject_type, subject_id, reviewer_id, deal_id, application_id, rating, comment,
+			status, moderation_note, moderated_by, moderated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := r.db.Exec(query,
+		review.ID, review.SubjectType, review.SubjectID, review.ReviewerID, review.DealID, review.ApplicationID,
+		review.Rating, review.Comment, review.Status, review.ModerationNote, review.ModeratedBy, review.ModeratedAt,
+		review.CreatedAt, review.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create review: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores the rating, comment and moderation of a review
+func (r *PostgreSQLReviewRepository) Update(review *domain.Review) error {
+	query := `
+		UPDATE reviews SET deal_id = $2, application_id = $3, rating = $4, comment = $5, status = $6,
+			moderation_note = $7, moderated_by = $8, moderated_at = $9, updated_at = $10
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query,
+		review.ID, review.DealID, review.ApplicationID, review.Rating, review.Comment, review.Status, review.ModerationNote,
+		review.ModeratedBy, review.ModeratedAt, review.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("review not found: %s", review.ID)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a review
+func (r *PostgreSQLReviewRepository) GetByID(id string) (*domain.Review, error) {
+	query := `SELECT ` + reviewColumns + reviewFrom + ` WHERE r.id = $1`
+
+	review, err := scanReview(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("review not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// GetByReviewer retrieves the review of a subject left by a user
+func (r *PostgreSQLReviewRepository) GetByReviewer(subjectType, subjectID, reviewerID string) (*domain.Review, error) {
+	query := `SELECT ` + reviewColumns + reviewFrom + `
+		WHERE r.subject_type = $1 AND r.subject_id = $2 AND r.reviewer_id = $3`
+
+	review, err := scanReview(r.db.QueryRow(query, subjectType, subjectID, reviewerID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("review not found: %s %s by %s", subjectType, subjectID, reviewerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// List retrieves reviews matching a filter, newest first, with the total count
+func (r *PostgreSQLReviewRepository) List(filter domain.ReviewFilter) ([]domain.Review, int, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.SubjectType != "" {
+		add("r.subject_type = $%d", filter.SubjectType)
+	}
+	if filter.SubjectID != "" {
+		add("r.subject_id = $%d", filter.SubjectID)
+	}
+	if filter.Status != "" {
+		add("r.status = $%d", filter.Status)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM reviews r`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s%s%s ORDER BY r.created_at DESC LIMIT $%d OFFSET $%d`,
+		reviewColumns, reviewFrom, where, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []domain.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, *review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return reviews, total, nil
+}
+
+// Summary aggregates the published reviews of a subject
+func (r *PostgreSQLReviewRepository) Summary(subjectType, subjectID string) (*domain.ReviewSummary, error) {
+	query := `
+		SELECT rating, COUNT(*) FROM reviews
+		WHERE subject_type = $1 AND subject_id = $2 AND status = $3
+		GROUP BY rating`
+
+	rows, err := r.db.Query(query, subjectType, subjectID, domain.ReviewPublished)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize reviews: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &domain.ReviewSummary{Distribution: map[int]int{}}
+	total := 0
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan review summary: %w", err)
+		}
+		summary.Distribution[rating] = count
+		summary.Count += count
+		total += rating * count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	if summary.Count > 0 {
+		summary.AverageRating = float64(total) / float64(summary.Count)
+	}
+	return summary, nil
+}
+
+// Delete removes a review
+func (r *PostgreSQLReviewRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM reviews WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("review not found: %s", id)
+	}
+
+	return nil
+}
+
+// SaveReputation creates or replaces the reputation of a subject
+func (r *PostgreSQLReviewRepository) SaveReputation(reputation *domain.Reputation) error {
+	query := `
+		INSERT INTO reputation_scores (subject_type, subject_id, review_count, average_rating, score, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (subject_type, subject_id) DO UPDATE SET
+			review_count = EXCLUDED.review_count, average_rating = EXCLUDED.average_rating,
+			score = EXCLUDED.score, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(query,
+		reputation.SubjectType, reputation.SubjectID, reputation.ReviewCount, reputation.AverageRating,
+		reputation.Score, reputation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save reputation: %w", err)
+	}
+
+	return nil
+}
+
+// GetReputation retrieves the reputation of a subject
+func (r *PostgreSQLReviewRepository) GetReputation(subjectType, subjectID string) (*domain.Reputation, error) {
+	query := `
+		SELECT subject_type, subject_id, review_count, average_rating, score, updated_at
+		FROM reputation_scores WHERE subject_type = $1 AND subject_id = $2`
+
+	var reputation domain.Reputation
+	err := r.db.QueryRow(query, subjectType, subjectID).Scan(&reputation.SubjectType, &reputation.SubjectID,
+		&reputation.ReviewCount, &reputation.AverageRating, &reputation.Score, &reputation.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reputation not found: %s %s", subjectType, subjectID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reputation: %w", err)
+	}
+
+	return &reputation, nil
+}
+
+const reviewReportColumns = `id, review_id, reporter_id, reason, details, status, resolved_by, resolved_at, created_at`
+
+// CreateReport stores a report unless the user already reported the review
+func (r *PostgreSQLReviewRepository) CreateReport(report *domain.ReviewReport) error {
+	query := `
+		INSERT INTO review_reports (` + reviewReportColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (review_id, reporter_id) DO NOTHING`
+
+	result, err := r.db.Exec(query,
+		report.ID, report.ReviewID, report.ReporterID, report.Reason, report.Details, report.Status,
+		report.ResolvedBy, report.ResolvedAt, report.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create review report: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("review already reported: %s", report.ReviewID)
+	}
+
+	return nil
+}
+
+// GetReport retrieves a report
+func (r *PostgreSQLReviewRepository) GetReport(id string) (*domain.ReviewReport, error) {
+	query := `SELECT ` + reviewReportColumns + ` FROM review_reports WHERE id = $1`
+
+	report, err := scanReviewReport(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("review report not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ListReports retrieves reports in a status, oldest first, with the total count
+func (r *PostgreSQLReviewRepository) ListReports(status string, limit, offset int) ([]domain.ReviewReport, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM review_reports WHERE status = $1`, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count review reports: %w", err)
+	}
+
+	query := `SELECT ` + reviewReportColumns + ` FROM review_reports
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list review reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []domain.ReviewReport{}
+	for rows.Next() {
+		report, err := scanReviewReport(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reports = append(reports, *report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// CountOpenReports counts the open reports of a review
+func (r *PostgreSQLReviewRepository) CountOpenReports(reviewID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM review_reports WHERE review_id = $1 AND status = $2`,
+		reviewID, domain.ReviewReportOpen).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count review reports: %w", err)
+	}
+	return count, nil
+}
+
+// ResolveReports closes the open reports of a review with a status
+func (r *PostgreSQLReviewRepository) ResolveReports(reviewID, status, resolvedBy string, at time.Time) (int64, error) {
+	query := `
+		UPDATE review_reports SET status = $3, resolved_by = $4, resolved_at = $5
+		WHERE review_id = $1 AND status = $2`
+
+	result, err := r.db.Exec(query, reviewID, domain.ReviewReportOpen, status, resolvedBy, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve review reports: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// scanReview scans a row selected with reviewColumns
+func scanReview(row interface{ Scan(...interface{}) error }) (*domain.Review, error) {
+	var review domain.Review
+	var firstName, lastName string
+	var dealID, applicationID, moderatedBy sql.NullString
+	var moderatedAt sql.NullTime
+
+	err := row.Scan(&review.ID, &review.SubjectType, &review.SubjectID, &review.ReviewerID, &firstName, &lastName,
+		&dealID, &applicationID, &review.Rating, &review.Comment, &review.Status, &review.ModerationNote, &moderatedBy,
+		&moderatedAt, &review.CreatedAt, &review.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan review: %w", err)
+	}
+
+	review.ReviewerName = domain.PublicReviewerName(firstName, lastName)
+	if dealID.Valid {
+		review.DealID = &dealID.String
+	}
+	if applicationID.Valid {
+		review.ApplicationID = &applicationID.String
+	}
+	if moderatedBy.Valid {
+		review.ModeratedBy = &moderatedBy.String
+	}
+	if moderatedAt.Valid {
+		review.ModeratedAt = &moderatedAt.Time
+	}
+
+	return &review, nil
+}
+
+// scanReviewReport scans a row selected with reviewReportColumns
+func scanReviewReport(row interface{ Scan(...interface{}) error }) (*domain.ReviewReport, error) {
+	var report domain.ReviewReport
+	var resolvedBy sql.NullString
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(&report.ID, &report.ReviewID, &report.ReporterID, &report.Reason, &report.Details,
+		&report.Status, &resolvedBy, &resolvedAt, &report.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan review report: %w", err)
+	}
+
+	if resolvedBy.Valid {
+		report.ResolvedBy = &resolvedBy.String
+	}
+	if resolvedAt.Valid {
+		report.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &report, nil
+}
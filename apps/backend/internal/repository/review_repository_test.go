@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestReviewRepository_Summary(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLReviewRepository(db)
+
+	mock.ExpectQuery(`SELECT rating, COUNT\(\*\) FROM reviews`).
+		WithArgs(domain.ReviewSubjectAgent, "agent-1", domain.ReviewPublished).
+		WillReturnRows(sqlmock.NewRows([]string{"rating", "count"}).AddRow(5, 3).AddRow(2, 1))
+
+	summary, err := repo.Summary(domain.ReviewSubjectAgent, "agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, summary.Count)
+	assert.InDelta(t, 4.25, summary.AverageRating, 0.001)
+	assert.Equal(t, map[int]int{5: 3, 2: 1}, summary.Distribution)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_List(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLReviewRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reviews r WHERE r.status = \$1`).WithArgs(domain.ReviewPending).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT (.+) FROM reviews r LEFT JOIN users u (.+) LIMIT \$2 OFFSET \$3`).
+		WithArgs(domain.ReviewPending, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subject_type", "subject_id", "reviewer_id", "first_name", "last_name",
+			"deal_id", "application_id", "rating", "comment", "status", "moderation_note", "moderated_by", "moderated_at", "created_at", "updated_at"}).
+			AddRow("rev-1", domain.ReviewSubjectAgent, "agent-1", "client-1", "María", "Pérez", "deal-1", nil, 5, "Excelente",
+				domain.ReviewPending, "", nil, nil, now, now))
+
+	reviews, total, err := repo.List(domain.ReviewFilter{Status: domain.ReviewPending, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "María P.", reviews[0].ReviewerName)
+	assert.Equal(t, "deal-1", *reviews[0].DealID)
+	assert.Nil(t, reviews[0].ModeratedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_CreateReport(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLReviewRepository(db)
+
+	report, err := domain.NewReviewReport("rev-1", "user-1", domain.ReviewReportSpam, "")
+	require.NoError(t, err)
+
+	mock.ExpectExec(`INSERT INTO review_reports (.+) ON CONFLICT \(review_id, reporter_id\) DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.CreateReport(report))
+
+	mock.ExpectExec(`INSERT INTO review_reports`).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, repo.CreateReport(report), "review already reported")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_ResolveReports(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLReviewRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec(`UPDATE review_reports SET status = \$3, resolved_by = \$4, resolved_at = \$5`).
+		WithArgs("rev-1", domain.ReviewReportOpen, domain.ReviewReportUpheld, "admin-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	resolved, err := repo.ResolveReports("rev-1", domain.ReviewReportUpheld, "admin-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resolved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetReputation(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLReviewRepository(db)
+
+	mock.ExpectQuery(`FROM reputation_scores WHERE subject_type = \$1 AND subject_id = \$2`).
+		WithArgs(domain.ReviewSubjectAgency, "agency-1").
+		WillReturnRows(sqlmock.NewRows([]string{"subject_type", "subject_id", "review_count", "average_rating", "score", "updated_at"}).
+			AddRow(domain.ReviewSubjectAgency, "agency-1", 4, 4.5, 65.6, time.Now()))
+
+	reputation, err := repo.GetReputation(domain.ReviewSubjectAgency, "agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, reputation.ReviewCount)
+	assert.Equal(t, 65.6, reputation.Score)
+
+	mock.ExpectQuery(`FROM reputation_scores`).WillReturnRows(sqlmock.NewRows([]string{"subject_type"}))
+	_, err = repo.GetReputation(domain.ReviewSubjectAgency, "agency-2")
+	assert.ErrorContains(t, err, "reputation not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
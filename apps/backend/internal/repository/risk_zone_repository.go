@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// LocatedProperty is the ID and coordinates of a property
+type LocatedProperty struct {
+	ID       string
+	Location domain.GeoPoint
+}
+
+// RiskZoneRepository defines data access for hazard zones and property risk flags
+type RiskZoneRepository interface {
+	// ReplaceLayer replaces the zones of a layer with the imported ones
+	ReplaceLayer(layer string, zones []domain.RiskZone) error
+
+	// DeleteLayer removes the zones of a layer, and with them their risk flags
+	DeleteLayer(layer string) error
+
+	// GetLayer returns the summary of a layer
+	GetLayer(layer string) (*domain.RiskLayerSummary, error)
+
+	// ListLayers summarizes the imported layers by name
+	ListLayers() ([]domain.RiskLayerSummary, error)
+
+	// FindCandidates returns the zones whose bounding box contains a point
+	FindCandidates(point domain.GeoPoint) ([]domain.RiskZone, error)
+
+	// ListLocatedProperties returns the properties with coordinates inside a box
+	ListLocatedProperties(bounds domain.GeoBounds) ([]LocatedProperty, error)
+
+	// ReplaceFlags replaces the risk flags of a property
+	ReplaceFlags(propertyID string, flags []domain.PropertyRiskFlag) error
+
+	// ListFlags returns the risk flags of a property
+	ListFlags(propertyID string) ([]domain.PropertyRiskFlag, error)
+}
+
+// PostgreSQLRiskZoneRepository implements RiskZoneRepository using PostgreSQL
+type PostgreSQLRiskZoneRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLRiskZoneRepository creates a new PostgreSQL risk zone repository
+func NewPostgreSQLRiskZoneRepository(db *sql.DB) *PostgreSQLRiskZoneRepository {
+	return &PostgreSQLRiskZoneRepository{db: db}
+}
+
+const riskZoneColumns = `id, layer, hazard, level, name, source, polygons, min_lat, min_lng, max_lat, max_lng,
+	COALESCE(imported_by::text, ''), imported_at`
+
+// scanRiskZone scans a zone selected with riskZoneColumns
+func scanRiskZone(row interface{ Scan(...interface{}) error }) (*domain.RiskZone, error) {
+	zone := &domain.RiskZone{}
+	var polygons []byte
+	err := row.Scan(&zone.ID, &zone.Layer, &zone.Hazard, &zone.Level, &zone.Name, &zone.Source, &polygons,
+		&zone.Bounds.MinLat, &zone.Bounds.MinLng, &zone.Bounds.MaxLat, &zone.Bounds.MaxLng,
+		&zone.ImportedBy, &zone.ImportedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(polygons, &zone.Polygons); err != nil {
+		return nil, fmt.Errorf("failed to decode risk zone polygons: %w", err)
+	}
+	return zone, nil
+}
+
+// ReplaceLayer replaces the zones of a layer with the imported ones
+func (r *PostgreSQLRiskZoneRepository) ReplaceLayer(layer string, zones []domain.RiskZone) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM risk_zones WHERE layer = $1`, layer); err != nil {
+		return fmt.Errorf("failed to clear risk layer: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_zones (id, layer, hazard, level, name, source, polygons,
+			min_lat, min_lng, max_lat, max_lng, imported_by, imported_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, '')::uuid, $13)`
+	for _, zone := range zones {
+		polygons, err := json.Marshal(zone.Polygons)
+		if err != nil {
+			return fmt.Errorf("failed to encode risk zone polygons: %w", err)
+		}
+		_, err = tx.Exec(query, zone.ID, zone.Layer, zone.Hazard, zone.Level, zone.Name, zone.Source, polygons,
+			zone.Bounds.MinLat, zone.Bounds.MinLng, zone.Bounds.MaxLat, zone.Bounds.MaxLng, zone.ImportedBy, zone.ImportedAt)
+		if err != nil {
+			return fmt.Errorf("failed to import risk zone: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit risk layer: %w", err)
+	}
+	return nil
+}
+
+// DeleteLayer removes the zones of a layer, and with them their risk flags
+func (r *PostgreSQLRiskZoneRepository) DeleteLayer(layer string) error {
+	result, err := r.db.Exec(`DELETE FROM risk_zones WHERE layer = $1`, layer)
+	if err != nil {
+		return fmt.Errorf("failed to delete risk layer: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("risk layer not found: %s", layer)
+	}
+	return nil
+}
+
+const riskLayerSummaryQuery = `
+	SELECT layer, MIN(hazard), MIN(source), COUNT(*), MIN(min_lat), MIN(min_lng), MAX(max_lat), MAX(max_lng), MAX(imported_at)
+	FROM risk_zones`
+
+// scanRiskLayerSummary scans a row of riskLayerSummaryQuery
+func scanRiskLayerSummary(row interface{ Scan(...interface{}) error }) (*domain.RiskLayerSummary, error) {
+	summary := &domain.RiskLayerSummary{}
+	err := row.Scan(&summary.Layer, &summary.Hazard, &summary.Source, &summary.Zones,
+		&summary.Bounds.MinLat, &summary.Bounds.MinLng, &summary.Bounds.MaxLat, &summary.Bounds.MaxLng, &summary.ImportedAt)
+	return summary, err
+}
+
+// GetLayer returns the summary of a layer
+func (r *PostgreSQLRiskZoneRepository) GetLayer(layer string) (*domain.RiskLayerSummary, error) {
+	summary, err := scanRiskLayerSummary(r.db.QueryRow(riskLayerSummaryQuery+` WHERE layer = $1 GROUP BY layer`, layer))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("risk layer not found: %s", layer)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk layer: %w", err)
+	}
+	return summary, nil
+}
+
+// ListLayers summarizes the imported layers by name
+func (r *PostgreSQLRiskZoneRepository) ListLayers() ([]domain.RiskLayerSummary, error) {
+	rows, err := r.db.Query(riskLayerSummaryQuery + ` GROUP BY layer ORDER BY layer`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk layers: %w", err)
+	}
+	defer rows.Close()
+
+	layers := []domain.RiskLayerSummary{}
+	for rows.Next() {
+		summary, err := scanRiskLayerSummary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk layer: %w", err)
+		}
+		layers = append(layers, *summary)
+	}
+	return layers, rows.Err()
+}
+
+// FindCandidates returns the zones whose bounding box contains a point
+func (r *PostgreSQLRiskZoneRepository) FindCandidates(point domain.GeoPoint) ([]domain.RiskZone, error) {
+	query := `SELECT ` + riskZoneColumns + ` FROM risk_zones
+		WHERE $1 BETWEEN min_lat AND max_lat AND $2 BETWEEN min_lng AND max_lng`
+
+	rows, err := r.db.Query(query, point.Lat, point.Lng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find risk zones: %w", err)
+	}
+	defer rows.Close()
+
+	zones := []domain.RiskZone{}
+	for rows.Next() {
+		zone, err := scanRiskZone(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk zone: %w", err)
+		}
+		zones = append(zones, *zone)
+	}
+	return zones, rows.Err()
+}
+
+// ListLocatedProperties returns the properties with coordinates inside a box
+func (r *PostgreSQLRiskZoneRepository) ListLocatedProperties(bounds domain.GeoBounds) ([]LocatedProperty, error) {
+	query := `
+		SELECT id, latitude, longitude FROM properties
+		WHERE latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4`
+
+	rows, err := r.db.Query(query, bounds.MinLat, bounds.MaxLat, bounds.MinLng, bounds.MaxLng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list located properties: %w", err)
+	}
+	defer rows.Close()
+
+	properties := []LocatedProperty{}
+	for rows.Next() {
+		var property LocatedProperty
+		if err := rows.Scan(&property.ID, &property.Location.Lat, &property.Location.Lng); err != nil {
+			return nil, fmt.Errorf("failed to scan located property: %w", err)
+		}
+		properties = append(properties, property)
+	}
+	return properties, rows.Err()
+}
+
+// ReplaceFlags replaces the risk flags of a property
+func (r *PostgreSQLRiskZoneRepository) ReplaceFlags(propertyID string, flags []domain.PropertyRiskFlag) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM property_risk_flags WHERE property_id = $1`, propertyID); err != nil {
+		return fmt.Errorf("failed to clear risk flags: %w", err)
+	}
+
+	query := `
+		INSERT INTO property_risk_flags (property_id, hazard, level, zone_id, zone_name, source, assessed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, flag := range flags {
+		if _, err := tx.Exec(query, propertyID, flag.Hazard, flag.Level, flag.ZoneID, flag.ZoneName, flag.Source, flag.AssessedAt); err != nil {
+			return fmt.Errorf("failed to add %s risk flag: %w", flag.Hazard, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit risk flags: %w", err)
+	}
+	return nil
+}
+
+// ListFlags returns the risk flags of a property
+func (r *PostgreSQLRiskZoneRepository) ListFlags(propertyID string) ([]domain.PropertyRiskFlag, error) {
+	query := `
+		SELECT hazard, level, zone_id, zone_name, source, assessed_at
+		FROM property_risk_flags WHERE property_id = $1`
+
+	rows, err := r.db.Query(query, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := []domain.PropertyRiskFlag{}
+	for rows.Next() {
+		var flag domain.PropertyRiskFlag
+		if err := rows.Scan(&flag.Hazard, &flag.Level, &flag.ZoneID, &flag.ZoneName, &flag.Source, &flag.AssessedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan risk flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	domain.SortRiskFlags(flags)
+	return flags, nil
+}
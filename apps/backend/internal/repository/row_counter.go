@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Count strategies of paginated endpoints
+const (
+	// CountExact runs COUNT(*) on every request
+	CountExact = "exact"
+	// CountEstimate uses the planner's estimate: pg_class.reltuples for whole tables and
+	// the row estimate of EXPLAIN for filtered counts. Estimates below the threshold of
+	// the policy are counted exactly.
+	CountEstimate = "estimate"
+	// CountCached runs COUNT(*) and reuses its result for the TTL of the policy
+	CountCached = "cached"
+)
+
+// Paginated endpoints with a count policy of their own
+const (
+	CountEndpointDefault      = "default"
+	CountEndpointList         = "properties.list"
+	CountEndpointFilter       = "properties.filter"
+	CountEndpointProvince     = "properties.province"
+	CountEndpointPriceRange   = "properties.price_range"
+	CountEndpointSearch       = "properties.search"
+	CountEndpointSearchRanked = "properties.search_ranked"
+	CountEndpointAdvanced     = "properties.advanced_search"
+)
+
+const (
+	defaultCountThreshold = 1000
+	defaultCountCacheTTL  = 30 * time.Second
+	maxCachedCounts       = 10000
+)
+
+// CountPolicy is how an endpoint counts the total of its pagination metadata
+type CountPolicy struct {
+	Strategy  string
+	Threshold int           // estimates below it are counted exactly
+	TTL       time.Duration // how long cached counts are reused
+}
+
+// cachedCount is a count reused until it expires
+type cachedCount struct {
+	total     int
+	expiresAt time.Time
+}
+
+// wholeTableCount matches counts without conditions, estimated from pg_class
+var wholeTableCount = regexp.MustCompile(`(?i)^\s*SELECT COUNT\(\*\) FROM ([a-z_]+)\s*$`)
+
+// RowCounter counts the totals of paginated queries with the policy of each endpoint.
+// Exact COUNT(*) over large tables and full-text matches dominates the latency of
+// paginated endpoints; estimated and cached totals trade exactness for it, and report
+// it so the pagination metadata can flag the total.
+type RowCounter struct {
+	db       *sql.DB
+	mutex    sync.RWMutex
+	policies map[string]CountPolicy
+	fallback CountPolicy
+	cache    map[string]cachedCount
+}
+
+// NewRowCounter creates a counter counting every endpoint exactly
+func NewRowCounter(db *sql.DB) *RowCounter {
+	return &RowCounter{
+		db:       db,
+		policies: make(map[string]CountPolicy),
+		fallback: CountPolicy{Strategy: CountExact},
+		cache:    make(map[string]cachedCount),
+	}
+}
+
+// SetPolicies replaces the policies of endpoints; the policy of CountEndpointDefault
+// applies to the others
+func (c *RowCounter) SetPolicies(policies map[string]CountPolicy) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.policies = make(map[string]CountPolicy, len(policies))
+	c.fallback = CountPolicy{Strategy: CountExact}
+	for endpoint, policy := range policies {
+		if endpoint == CountEndpointDefault {
+			c.fallback = policy
+			continue
+		}
+		c.policies[endpoint] = policy
+	}
+	c.cache = make(map[string]cachedCount)
+}
+
+// Count returns the total of a COUNT(*) query of an endpoint and whether it is exact
+func (c *RowCounter) Count(endpoint, countQuery string, args ...interface{}) (int, bool, error) {
+	policy := c.policy(endpoint)
+
+	switch policy.Strategy {
+	case CountEstimate:
+		estimate, err := c.estimate(countQuery, args...)
+		if err == nil && estimate >= policy.Threshold {
+			return estimate, false, nil
+		}
+	case CountCached:
+		key := endpoint + "\x00" + countQuery + "\x00" + fmt.Sprint(args...)
+		if total, ok := c.cached(key); ok {
+			return total, false, nil
+		}
+		total, err := c.exact(countQuery, args...)
+		if err != nil {
+			return 0, false, err
+		}
+		c.store(key, total, policy.TTL)
+		return total, true, nil
+	}
+
+	total, err := c.exact(countQuery, args...)
+	if err != nil {
+		return 0, false, err
+	}
+	return total, true, nil
+}
+
+func (c *RowCounter) policy(endpoint string) CountPolicy {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if policy, ok := c.policies[endpoint]; ok {
+		return policy
+	}
+	return c.fallback
+}
+
+func (c *RowCounter) exact(countQuery string, args ...interface{}) (int, error) {
+	var total int
+	if err := c.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// estimate returns the planner's estimate of a count. Tables never analyzed have no
+// estimate, which returns an error so the count is exact.
+func (c *RowCounter) estimate(countQuery string, args ...interface{}) (int, error) {
+	if match := wholeTableCount.FindStringSubmatch(countQuery); match != nil {
+		var reltuples float64
+		err := c.db.QueryRow("SELECT reltuples FROM pg_class WHERE oid = to_regclass($1)", match[1]).Scan(&reltuples)
+		if err != nil {
+			return 0, err
+		}
+		if reltuples < 0 {
+			return 0, fmt.Errorf("table %s has not been analyzed", match[1])
+		}
+		return int(reltuples), nil
+	}
+
+	rowsQuery, found := strings.CutPrefix(strings.TrimSpace(countQuery), "SELECT COUNT(*)")
+	if !found {
+		return 0, fmt.Errorf("unsupported count query: %s", countQuery)
+	}
+	var planJSON string
+	if err := c.db.QueryRow("EXPLAIN (FORMAT JSON) SELECT 1"+rowsQuery, args...).Scan(&planJSON); err != nil {
+		return 0, err
+	}
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(planJSON), &plans); err != nil || len(plans) == 0 {
+		return 0, fmt.Errorf("invalid query plan: %s", planJSON)
+	}
+	return int(plans[0].Plan.Rows), nil
+}
+
+func (c *RowCounter) cached(key string) (int, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	count, ok := c.cache[key]
+	if !ok || time.Now().After(count.expiresAt) {
+		return 0, false
+	}
+	return count.total, true
+}
+
+func (c *RowCounter) store(key string, total int, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultCountCacheTTL
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Searches are unbounded; start over rather than tracking recency
+	if len(c.cache) >= maxCachedCounts {
+		c.cache = make(map[string]cachedCount)
+	}
+	c.cache[key] = cachedCount{total: total, expiresAt: time.Now().Add(ttl)}
+}
+
+// ParseCountPolicies parses count policies separated by semicolons as
+// "endpoint=strategy[:option]", where the option is the exact-count threshold of
+// estimate and the TTL of cached, e.g.
+// "properties.list=estimate:10000;properties.search=cached:30s;default=exact"
+func ParseCountPolicies(spec string) (map[string]CountPolicy, error) {
+	policies := make(map[string]CountPolicy)
+	for _, entry := range strings.Split(spec, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		endpoint, definition, ok := strings.Cut(entry, "=")
+		endpoint = strings.TrimSpace(endpoint)
+		if !ok || endpoint == "" {
+			return nil, fmt.Errorf("invalid count policy %q: expected endpoint=strategy[:option]", entry)
+		}
+
+		strategy, option, hasOption := strings.Cut(strings.TrimSpace(definition), ":")
+		policy := CountPolicy{Strategy: strings.ToLower(strategy)}
+		switch policy.Strategy {
+		case CountExact:
+			if hasOption {
+				return nil, fmt.Errorf("invalid count policy %s: exact takes no option", endpoint)
+			}
+		case CountEstimate:
+			policy.Threshold = defaultCountThreshold
+			if hasOption {
+				threshold, err := strconv.Atoi(option)
+				if err != nil || threshold < 0 {
+					return nil, fmt.Errorf("invalid count policy %s: threshold must be a non-negative number", endpoint)
+				}
+				policy.Threshold = threshold
+			}
+		case CountCached:
+			policy.TTL = defaultCountCacheTTL
+			if hasOption {
+				ttl, err := time.ParseDuration(option)
+				if err != nil || ttl <= 0 {
+					return nil, fmt.Errorf("invalid count policy %s: TTL must be a positive duration", endpoint)
+				}
+				policy.TTL = ttl
+			}
+		default:
+			return nil, fmt.Errorf("invalid count policy %s: strategy must be exact, estimate or cached", endpoint)
+		}
+		policies[endpoint] = policy
+	}
+	return policies, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestRowCounter_ExactByDefault(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE province = \$1`).
+		WithArgs("Pichincha").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	total, exact, err := NewRowCounter(db).Count(CountEndpointProvince, "SELECT COUNT(*) FROM properties WHERE province = $1", "Pichincha")
+	require.NoError(t, err)
+	assert.Equal(t, 42, total)
+	assert.True(t, exact)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRowCounter_Estimate(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	counter := NewRowCounter(db)
+	counter.SetPolicies(map[string]CountPolicy{
+		CountEndpointDefault: {Strategy: CountEstimate, Threshold: 1000},
+	})
+
+	// Whole tables are estimated from pg_class
+	mock.ExpectQuery(`SELECT reltuples FROM pg_class WHERE oid = to_regclass\(\$1\)`).
+		WithArgs("properties").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(250000.0))
+	total, exact, err := counter.Count(CountEndpointList, "SELECT COUNT(*) FROM properties")
+	require.NoError(t, err)
+	assert.Equal(t, 250000, total)
+	assert.False(t, exact)
+
+	// Filtered counts from the plan
+	mock.ExpectQuery(`EXPLAIN \(FORMAT JSON\) SELECT 1 FROM properties WHERE search_vector @@ plainto_tsquery\('spanish', \$1\)`).
+		WithArgs("casa").
+		WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(`[{"Plan": {"Node Type": "Bitmap Heap Scan", "Plan Rows": 5300}}]`))
+	total, exact, err = counter.Count(CountEndpointSearch, "SELECT COUNT(*) FROM properties WHERE search_vector @@ plainto_tsquery('spanish', $1)", "casa")
+	require.NoError(t, err)
+	assert.Equal(t, 5300, total)
+	assert.False(t, exact)
+
+	// Estimates below the threshold are counted exactly
+	mock.ExpectQuery(`EXPLAIN \(FORMAT JSON\) SELECT 1 FROM properties WHERE city = \$1`).
+		WithArgs("Cuenca").
+		WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(`[{"Plan": {"Plan Rows": 80}}]`))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE city = \$1`).
+		WithArgs("Cuenca").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(77))
+	total, exact, err = counter.Count(CountEndpointFilter, "SELECT COUNT(*) FROM properties WHERE city = $1", "Cuenca")
+	require.NoError(t, err)
+	assert.Equal(t, 77, total)
+	assert.True(t, exact)
+
+	// Tables never analyzed are counted exactly
+	mock.ExpectQuery(`SELECT reltuples FROM pg_class`).
+		WithArgs("properties").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(-1.0))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	total, exact, err = counter.Count(CountEndpointList, "SELECT COUNT(*) FROM properties")
+	require.NoError(t, err)
+	assert.Equal(t, 12, total)
+	assert.True(t, exact)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRowCounter_Cached(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	counter := NewRowCounter(db)
+	counter.SetPolicies(map[string]CountPolicy{CountEndpointSearch: {Strategy: CountCached, TTL: time.Minute}})
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE title = \$1`).
+		WithArgs("casa").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(9))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM properties WHERE title = \$1`).
+		WithArgs("depa").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	countQuery := "SELECT COUNT(*) FROM properties WHERE title = $1"
+	total, exact, err := counter.Count(CountEndpointSearch, countQuery, "casa")
+	require.NoError(t, err)
+	assert.Equal(t, 9, total)
+	assert.True(t, exact)
+
+	// Reused, so possibly stale
+	total, exact, err = counter.Count(CountEndpointSearch, countQuery, "casa")
+	require.NoError(t, err)
+	assert.Equal(t, 9, total)
+	assert.False(t, exact)
+
+	// Other arguments are counted
+	total, _, err = counter.Count(CountEndpointSearch, countQuery, "depa")
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgreSQLPropertyRepository_FlagsEstimatedTotals(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLPropertyRepository(db)
+	repo.SetCountPolicies(map[string]CountPolicy{CountEndpointList: {Strategy: CountEstimate, Threshold: 1000}})
+
+	mock.ExpectQuery(`SELECT reltuples FROM pg_class`).
+		WithArgs("properties").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(50000.0))
+	mock.ExpectQuery(`SELECT .+ FROM properties\s+ORDER BY created_at DESC\s+LIMIT \$1 OFFSET \$2`).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	pagination := domain.NewPaginationParams()
+	_, total, err := repo.GetAllPaginated(pagination)
+	require.NoError(t, err)
+	assert.Equal(t, 50000, total)
+	assert.True(t, pagination.TotalEstimated)
+	assert.False(t, pagination.Paginate(total).TotalExact)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParseCountPolicies(t *testing.T) {
+	policies, err := ParseCountPolicies("properties.list=estimate:10000; properties.search=cached ;default=exact;properties.filter=estimate")
+	require.NoError(t, err)
+	assert.Equal(t, CountPolicy{Strategy: CountEstimate, Threshold: 10000}, policies[CountEndpointList])
+	assert.Equal(t, CountPolicy{Strategy: CountCached, TTL: 30 * time.Second}, policies[CountEndpointSearch])
+	assert.Equal(t, CountPolicy{Strategy: CountExact}, policies[CountEndpointDefault])
+	assert.Equal(t, 1000, policies[CountEndpointFilter].Threshold)
+
+	for _, invalid := range []string{"properties.list", "properties.list=guess", "properties.list=estimate:many", "properties.search=cached:0s", "default=exact:5"} {
+		_, err := ParseCountPolicies(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the latest migration this build relies on. Bump it with every new
+// migration; instances refuse to become ready on a database behind it.
+const SchemaVersion = 93
+
+// SchemaRepository reads the version of the database schema
+type SchemaRepository interface {
+	// CurrentVersion returns the latest migration applied to the database
+	CurrentVersion() (int, error)
+}
+
+// PostgreSQLSchemaRepository implements SchemaRepository using the schema_migrations
+// table the migrations are recorded in
+type PostgreSQLSchemaRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLSchemaRepository creates a new PostgreSQL schema repository
+func NewPostgreSQLSchemaRepository(db *sql.DB) *PostgreSQLSchemaRepository {
+	return &PostgreSQLSchemaRepository{db: db}
+}
+
+// CurrentVersion returns the highest migration recorded in schema_migrations. Versions
+// are read as text, since they are recorded both as numbers and as zero-padded
+// strings such as '021'.
+func (r *PostgreSQLSchemaRepository) CurrentVersion() (int, error) {
+	rows, err := r.db.Query(`SELECT version::text FROM schema_migrations`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	defer rows.Close()
+
+	current := 0
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return 0, fmt.Errorf("failed to scan schema version: %w", err)
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(version)); err == nil && n > current {
+			current = n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return current, nil
+}
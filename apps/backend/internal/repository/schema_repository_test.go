@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRepository_CurrentVersion(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLSchemaRepository(db)
+
+	mock.ExpectQuery(`SELECT version::text FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("021").AddRow("78").AddRow("9").AddRow("manual"))
+	version, err := repo.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 78, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSchemaVersion_MatchesMigrations(t *testing.T) {
+	entries, err := os.ReadDir("../../migrations")
+	require.NoError(t, err)
+
+	latest := 0
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if n, err := strconv.Atoi(prefix); found && err == nil && n > latest {
+			latest = n
+		}
+	}
+	assert.Equal(t, latest, SchemaVersion, "bump SchemaVersion with every migration")
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// SearchDictionaryRepository handles database operations for search synonyms and stop words
+type SearchDictionaryRepository struct {
+	db *sql.DB
+}
+
+// NewSearchDictionaryRepository creates a new search dictionary repository
+func NewSearchDictionaryRepository(db *sql.DB) *SearchDictionaryRepository {
+	return &SearchDictionaryRepository{db: db}
+}
+
+// ListSynonyms returns all synonym entries ordered by term
+func (r *SearchDictionaryRepository) ListSynonyms() ([]domain.SearchSynonym, error) {
+	query := `
+		SELECT id, term, canonical, created_at, updated_at
+		FROM search_synonyms
+		ORDER BY term`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list synonyms: %w", err)
+	}
+	defer rows.Close()
+
+	var synonyms []domain.SearchSynonym
+	for rows.Next() {
+		var synonym domain.SearchSynonym
+		if err := rows.Scan(&synonym.ID, &synonym.Term, &synonym.Canonical, &synonym.CreatedAt, &synonym.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan synonym: %w", err)
+		}
+		synonyms = append(synonyms, synonym)
+	}
+
+	return synonyms, rows.Err()
+}
+
+// GetSynonymByID retrieves a synonym entry by ID
+func (r *SearchDictionaryRepository) GetSynonymByID(id string) (*domain.SearchSynonym, error) {
+	query := `
+		SELECT id, term, canonical, created_at, updated_at
+		FROM search_synonyms
+		WHERE id = $1`
+
+	synonym := &domain.SearchSynonym{}
+	err := r.db.QueryRow(query, id).Scan(&synonym.ID, &synonym.Term, &synonym.Canonical, &synonym.CreatedAt, &synonym.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("synonym not found with id: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get synonym: %w", err)
+	}
+
+	return synonym, nil
+}
+
+// SaveSynonym inserts a synonym or updates the canonical term of an existing one
+func (r *SearchDictionaryRepository) SaveSynonym(synonym *domain.SearchSynonym) error {
+	query := `
+		INSERT INTO search_synonyms (id, term, canonical, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (term) DO UPDATE SET canonical = EXCLUDED.canonical, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, synonym.ID, synonym.Term, synonym.Canonical, synonym.CreatedAt, synonym.UpdatedAt).
+		Scan(&synonym.ID, &synonym.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save synonym: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSynonym removes a synonym entry
+func (r *SearchDictionaryRepository) DeleteSynonym(id string) error {
+	result, err := r.db.Exec(`DELETE FROM search_synonyms WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete synonym: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("synonym not found with id: %s", id)
+	}
+
+	return nil
+}
+
+// ListStopWords returns all stop words ordered alphabetically
+func (r *SearchDictionaryRepository) ListStopWords() ([]domain.SearchStopWord, error) {
+	rows, err := r.db.Query(`SELECT id, word, created_at FROM search_stop_words ORDER BY word`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stop words: %w", err)
+	}
+	defer rows.Close()
+
+	var stopWords []domain.SearchStopWord
+	for rows.Next() {
+		var stopWord domain.SearchStopWord
+		if err := rows.Scan(&stopWord.ID, &stopWord.Word, &stopWord.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stop word: %w", err)
+		}
+		stopWords = append(stopWords, stopWord)
+	}
+
+	return stopWords, rows.Err()
+}
+
+// AddStopWord inserts a stop word, ignoring duplicates
+func (r *SearchDictionaryRepository) AddStopWord(stopWord *domain.SearchStopWord) error {
+	query := `
+		INSERT INTO search_stop_words (id, word, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (word) DO NOTHING`
+
+	if _, err := r.db.Exec(query, stopWord.ID, stopWord.Word, stopWord.CreatedAt); err != nil {
+		return fmt.Errorf("failed to add stop word: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStopWord removes a stop word
+func (r *SearchDictionaryRepository) DeleteStopWord(word string) error {
+	result, err := r.db.Exec(`DELETE FROM search_stop_words WHERE word = $1`, word)
+	if err != nil {
+		return fmt.Errorf("failed to delete stop word: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stop word not found: %s", word)
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// SectorGuideRepository defines the interface for sector guide persistence
+type SectorGuideRepository interface {
+	// Create saves a new guide
+	Create(guide *domain.SectorGuide) error
+
+	// GetByID retrieves a guide by ID
+	GetByID(id string) (*domain.SectorGuide, error)
+
+	// GetBySlug retrieves a guide by slug
+	GetBySlug(slug string) (*domain.SectorGuide, error)
+
+	// Update saves the content of a guide
+	Update(guide *domain.SectorGuide) error
+
+	// Delete removes a guide
+	Delete(id string) error
+
+	// List lists the guides of a province and city, either empty for all, by title;
+	// drafts are included on request
+	List(province, city string, includeDrafts bool) ([]domain.SectorGuide, error)
+
+	// RefreshStats recomputes the average prices of every guide from the available and
+	// reserved listings of its sector, returning the number of guides updated
+	RefreshStats(now time.Time) (int, error)
+}
+
+// PostgreSQLSectorGuideRepository implements SectorGuideRepository using PostgreSQL
+type PostgreSQLSectorGuideRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLSectorGuideRepository creates a new PostgreSQL sector guide repository
+func NewPostgreSQLSectorGuideRepository(db *sql.DB) *PostgreSQLSectorGuideRepository {
+	return &PostgreSQLSectorGuideRepository{db: db}
+}
+
+const sectorGuideColumns = `id, slug, province, city, sector, title, summary, description, photos, safety_notes,
+	status, active_listings, avg_sale_price, avg_price_per_m2, avg_rent_price, stats_updated_at,
+	created_by, updated_by, created_at, updated_at, published_at`
+
+// scanSectorGuide scans a guide selected with sectorGuideColumns
+func scanSectorGuide(row interface{ Scan(...interface{}) error }) (*domain.SectorGuide, error) {
+	guide := &domain.SectorGuide{}
+	var photos []byte
+	var statsUpdatedAt, publishedAt sql.NullTime
+	err := row.Scan(&guide.ID, &guide.Slug, &guide.Province, &guide.City, &guide.Sector, &guide.Title,
+		&guide.Summary, &guide.Description, &photos, &guide.SafetyNotes, &guide.Status,
+		&guide.Stats.ActiveListings, &guide.Stats.AvgSalePrice, &guide.Stats.AvgPricePerM2, &guide.Stats.AvgRentPrice,
+		&statsUpdatedAt, &guide.CreatedBy, &guide.UpdatedBy, &guide.CreatedAt, &guide.UpdatedAt, &publishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	guide.Photos = []domain.GuidePhoto{}
+	if len(photos) > 0 {
+		if err := json.Unmarshal(photos, &guide.Photos); err != nil {
+			return nil, fmt.Errorf("failed to decode guide photos: %w", err)
+		}
+	}
+	if statsUpdatedAt.Valid {
+		guide.Stats.UpdatedAt = &statsUpdatedAt.Time
+	}
+	if publishedAt.Valid {
+		guide.PublishedAt = &publishedAt.Time
+	}
+	return guide, nil
+}
+
+// Create saves a new guide
+func (r *PostgreSQLSectorGuideRepository) Create(guide *domain.SectorGuide) error {
+	photos, err := json.Marshal(guide.Photos)
+	if err != nil {
+		return fmt.Errorf("failed to encode guide photos: %w", err)
+	}
+
+	query := `
+		INSERT INTO sector_guides (id, slug, province, city, sector, title, summary, description, photos,
+			safety_notes, status, created_by, updated_by, created_at, updated_at, published_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err = r.db.Exec(query, guide.ID, guide.Slug, guide.Province, guide.City, guide.Sector, guide.Title,
+		guide.Summary, guide.Description, photos, guide.SafetyNotes, guide.Status, guide.CreatedBy,
+		guide.UpdatedBy, guide.CreatedAt, guide.UpdatedAt, guide.PublishedAt)
+	if err != nil {
+		return sectorGuideWriteError("create", guide, err)
+	}
+	return nil
+}
+
+// GetByID retrieves a guide by ID
+func (r *PostgreSQLSectorGuideRepository) GetByID(id string) (*domain.SectorGuide, error) {
+	return r.getOne(`SELECT `+sectorGuideColumns+` FROM sector_guides WHERE id = $1`, id)
+}
+
+// GetBySlug retrieves a guide by slug
+func (r *PostgreSQLSectorGuideRepository) GetBySlug(slug string) (*domain.SectorGuide, error) {
+	return r.getOne(`SELECT `+sectorGuideColumns+` FROM sector_guides WHERE slug = $1`, slug)
+}
+
+func (r *PostgreSQLSectorGuideRepository) getOne(query, key string) (*domain.SectorGuide, error) {
+	guide, err := scanSectorGuide(r.db.QueryRow(query, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sector guide not found: %s", key)
+		}
+		return nil, fmt.Errorf("failed to get sector guide: %w", err)
+	}
+	return guide, nil
+}
+
+// Update saves the content of a guide
+func (r *PostgreSQLSectorGuideRepository) Update(guide *domain.SectorGuide) error {
+	photos, err := json.Marshal(guide.Photos)
+	if err != nil {
+		return fmt.Errorf("failed to encode guide photos: %w", err)
+	}
+
+	query := `
+		UPDATE sector_guides SET slug = $2, province = $3, city = $4, sector = $5, title = $6, summary = $7,
+			description = $8, photos = $9, safety_notes = $10, status = $11, updated_by = $12,
+			updated_at = $13, published_at = $14
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, guide.ID, guide.Slug, guide.Province, guide.City, guide.Sector, guide.Title,
+		guide.Summary, guide.Description, photos, guide.SafetyNotes, guide.Status, guide.UpdatedBy,
+		guide.UpdatedAt, guide.PublishedAt)
+	if err != nil {
+		return sectorGuideWriteError("update", guide, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated sector guide: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("sector guide not found: %s", guide.ID)
+	}
+	return nil
+}
+
+// Delete removes a guide
+func (r *PostgreSQLSectorGuideRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM sector_guides WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sector guide: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted sector guide: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("sector guide not found: %s", id)
+	}
+	return nil
+}
+
+// List lists guides by title
+func (r *PostgreSQLSectorGuideRepository) List(province, city string, includeDrafts bool) ([]domain.SectorGuide, error) {
+	query := `
+		SELECT ` + sectorGuideColumns + ` FROM sector_guides
+		WHERE ($1 = '' OR province = $1) AND ($2 = '' OR LOWER(city) = LOWER($2))
+		AND ($3 OR status = 'published')
+		ORDER BY province, city, title`
+
+	rows, err := r.db.Query(query, province, city, includeDrafts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sector guides: %w", err)
+	}
+	defer rows.Close()
+
+	guides := []domain.SectorGuide{}
+	for rows.Next() {
+		guide, err := scanSectorGuide(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sector guide: %w", err)
+		}
+		guides = append(guides, *guide)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sector guides: %w", err)
+	}
+	return guides, nil
+}
+
+// RefreshStats recomputes the average prices of every guide
+func (r *PostgreSQLSectorGuideRepository) RefreshStats(now time.Time) (int, error) {
+	query := `
+		UPDATE sector_guides g SET
+			active_listings = s.active_listings, avg_sale_price = s.avg_sale_price,
+			avg_price_per_m2 = s.avg_price_per_m2, avg_rent_price = s.avg_rent_price, stats_updated_at = $1
+		FROM (
+			SELECT sg.id,
+				COUNT(p.id) AS active_listings,
+				COALESCE(AVG(p.price) FILTER (WHERE p.price > 0), 0) AS avg_sale_price,
+				COALESCE(AVG(p.price / NULLIF(p.area_m2, 0)) FILTER (WHERE p.price > 0), 0) AS avg_price_per_m2,
+				COALESCE(AVG(p.rent_price) FILTER (WHERE p.rent_price > 0), 0) AS avg_rent_price
+			FROM sector_guides sg
+			LEFT JOIN properties p ON p.province = sg.province AND LOWER(p.city) = LOWER(sg.city)
+				AND LOWER(p.sector) = LOWER(sg.sector) AND p.status IN ('available', 'reserved')
+			GROUP BY sg.id
+		) s
+		WHERE g.id = s.id`
+
+	result, err := r.db.Exec(query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh sector guide stats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check refreshed sector guides: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// sectorGuideWriteError reports unique violations of the slug or sector as conflicts
+func sectorGuideWriteError(action string, guide *domain.SectorGuide, err error) error {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "sector_guides_slug_key"):
+		return fmt.Errorf("sector guide already exists with slug %s", guide.Slug)
+	case strings.Contains(message, "idx_sector_guides_location"):
+		return fmt.Errorf("sector guide already exists for %s, %s", guide.Sector, guide.City)
+	}
+	return fmt.Errorf("failed to %s sector guide: %w", action, err)
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// SessionRepository defines the interface for the persisted refresh-token sessions
+type SessionRepository interface {
+	// Create stores a new session
+	Create(session *domain.Session) error
+
+	// GetByID retrieves a session by ID, active or not
+	GetByID(id string) (*domain.Session, error)
+
+	// ListActive retrieves the sessions of a user that are neither revoked nor
+	// expired, most recently seen first
+	ListActive(userID string, now time.Time) ([]domain.Session, error)
+
+	// Rotate replaces the refresh token of an active session when oldHash is still its
+	// latest token. It reports whether the session was rotated.
+	Rotate(id, oldHash, newHash, ipAddress string, seenAt, expiresAt time.Time) (bool, error)
+
+	// Touch records activity on an active session and reports whether it is active
+	Touch(id, ipAddress string, seenAt time.Time) (bool, error)
+
+	// Revoke revokes an active session of a user and reports whether it was revoked
+	Revoke(userID, id string, at time.Time) (bool, error)
+
+	// RevokeOthers revokes every active session of a user except one and returns how
+	// many were revoked
+	RevokeOthers(userID, keepID string, at time.Time) (int, error)
+
+	// DeleteInactive deletes sessions expired or revoked before a time
+	DeleteInactive(before time.Time) (int, error)
+}
+
+// PostgreSQLSessionRepository implements SessionRepository using PostgreSQL
+type PostgreSQLSessionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLSessionRepository creates a new PostgreSQL session repository
+func NewPostgreSQLSessionRepository(db *sql.DB) *PostgreSQLSessionRepository {
+	return &PostgreSQLSessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, refresh_token_hash, device, user_agent, ip_address, created_at,
+		last_seen_at, expires_at, revoked_at`
+
+// Create stores a new session
+func (r *PostgreSQLSessionRepository) Create(session *domain.Session) error {
+	query := `
+		INSERT INTO sessions (` + sessionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.Exec(query,
+		session.ID, session.UserID, session.RefreshTokenHash, session.Device, session.UserAgent,
+		session.IPAddress, session.CreatedAt, session.LastSeenAt, session.ExpiresAt, session.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a session by ID
+func (r *PostgreSQLSessionRepository) GetByID(id string) (*domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+
+	session, err := scanSession(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListActive retrieves the active sessions of a user, most recently seen first
+func (r *PostgreSQLSessionRepository) ListActive(userID string, now time.Time) ([]domain.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC`
+
+	rows, err := r.db.Query(query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Rotate replaces the refresh token of an active session
+func (r *PostgreSQLSessionRepository) Rotate(id, oldHash, newHash, ipAddress string, seenAt, expiresAt time.Time) (bool, error) {
+	query := `
+		UPDATE sessions SET refresh_token_hash = $3, ip_address = $4, last_seen_at = $5, expires_at = $6
+		WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL AND expires_at > $5`
+
+	return r.execAffected("rotate session", query, id, oldHash, newHash, ipAddress, seenAt, expiresAt)
+}
+
+// Touch records activity on an active session
+func (r *PostgreSQLSessionRepository) Touch(id, ipAddress string, seenAt time.Time) (bool, error) {
+	query := `
+		UPDATE sessions SET ip_address = $2, last_seen_at = $3
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > $3`
+
+	return r.execAffected("touch session", query, id, ipAddress, seenAt)
+}
+
+// Revoke revokes an active session of a user
+func (r *PostgreSQLSessionRepository) Revoke(userID, id string, at time.Time) (bool, error) {
+	query := `UPDATE sessions SET revoked_at = $3 WHERE id = $2 AND user_id = $1 AND revoked_at IS NULL`
+
+	return r.execAffected("revoke session", query, userID, id, at)
+}
+
+// RevokeOthers revokes every active session of a user except one
+func (r *PostgreSQLSessionRepository) RevokeOthers(userID, keepID string, at time.Time) (int, error) {
+	query := `UPDATE sessions SET revoked_at = $3 WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(query, userID, keepID, at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// DeleteInactive deletes sessions expired or revoked before a time
+func (r *PostgreSQLSessionRepository) DeleteInactive(before time.Time) (int, error) {
+	query := `DELETE FROM sessions WHERE expires_at < $1 OR revoked_at < $1`
+
+	result, err := r.db.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete inactive sessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// execAffected runs an update and reports whether it changed a row
+func (r *PostgreSQLSessionRepository) execAffected(action, query string, args ...interface{}) (bool, error) {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// scanSession scans a row selected with sessionColumns
+func scanSession(row interface{ Scan(...interface{}) error }) (*domain.Session, error) {
+	session := &domain.Session{}
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.RefreshTokenHash, &session.Device, &session.UserAgent,
+		&session.IPAddress, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return session, nil
+}
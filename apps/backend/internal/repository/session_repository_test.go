@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestSessionRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLSessionRepository(db)
+
+	session := domain.NewSession("session-1", "user-1", "refresh-token", "190.152.10.4", "okhttp/4.12.0", time.Now().Add(time.Hour))
+
+	mock.ExpectExec(`INSERT INTO sessions`).
+		WithArgs("session-1", "user-1", domain.HashRefreshToken("refresh-token"), "Android app", "okhttp/4.12.0",
+			"190.152.10.4", session.CreatedAt, session.LastSeenAt, session.ExpiresAt, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Create(session))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_ListActive(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLSessionRepository(db)
+	now := time.Date(2025, 8, 26, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT (.+) FROM sessions\s+WHERE user_id = \$1 AND revoked_at IS NULL AND expires_at > \$2\s+ORDER BY last_seen_at DESC`).
+		WithArgs("user-1", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "refresh_token_hash", "device", "user_agent", "ip_address",
+			"created_at", "last_seen_at", "expires_at", "revoked_at"}).
+			AddRow("session-1", "user-1", "hash", "Chrome on Windows", "Mozilla/5.0", "190.152.10.4",
+				now.Add(-time.Hour), now, now.Add(time.Hour), nil))
+
+	sessions, err := repo.ListActive("user-1", now)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "Chrome on Windows", sessions[0].Device)
+	assert.Nil(t, sessions[0].RevokedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_RotateAndRevoke(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLSessionRepository(db)
+	now := time.Date(2025, 8, 26, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE sessions SET refresh_token_hash = \$3, ip_address = \$4, last_seen_at = \$5, expires_at = \$6\s+WHERE id = \$1 AND refresh_token_hash = \$2 AND revoked_at IS NULL AND expires_at > \$5`).
+		WithArgs("session-1", "old", "new", "190.152.10.4", now, now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rotated, err := repo.Rotate("session-1", "old", "new", "190.152.10.4", now, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, rotated)
+
+	mock.ExpectExec(`UPDATE sessions SET revoked_at = \$3 WHERE id = \$2 AND user_id = \$1 AND revoked_at IS NULL`).
+		WithArgs("user-1", "session-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	revoked, err := repo.Revoke("user-1", "session-1", now)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	mock.ExpectExec(`UPDATE sessions SET revoked_at = \$3 WHERE user_id = \$1 AND id <> \$2 AND revoked_at IS NULL`).
+		WithArgs("user-1", "session-2", now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	count, err := repo.RevokeOthers("user-1", "session-2", now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"realty-core/internal/domain"
+)
+
+// ShortLinkRepository defines the interface for property short links and their clicks
+type ShortLinkRepository interface {
+	// Create saves a new short link; a taken code fails with "short link code already exists"
+	Create(link *domain.ShortLink) error
+
+	// GetByCode retrieves a short link by its code
+	GetByCode(code string) (*domain.ShortLink, error)
+
+	// ListByProperty retrieves the short links of a property, newest first
+	ListByProperty(propertyID string) ([]domain.ShortLink, error)
+
+	// RecordClick saves a click through a link and increments its click count
+	RecordClick(click *domain.ShortLinkClick) error
+
+	// StatsByProperty summarizes the clicks through a property's links by source and campaign
+	StatsByProperty(propertyID string) (*domain.ShortLinkStats, error)
+}
+
+// PostgreSQLShortLinkRepository implements ShortLinkRepository using PostgreSQL
+type PostgreSQLShortLinkRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLShortLinkRepository creates a new PostgreSQL short link repository
+func NewPostgreSQLShortLinkRepository(db *sql.DB) *PostgreSQLShortLinkRepository {
+	return &PostgreSQLShortLinkRepository{db: db}
+}
+
+const shortLinkColumns = `id, code, property_id, campaign, created_by, click_count, created_at`
+
+// Create saves a new short link
+func (r *PostgreSQLShortLinkRepository) Create(link *domain.ShortLink) error {
+	if link == nil {
+		return fmt.Errorf("short link cannot be nil")
+	}
+
+	var campaign, createdBy sql.NullString
+	if link.Campaign != "" {
+		campaign = sql.NullString{String: link.Campaign, Valid: true}
+	}
+	if link.CreatedBy != "" {
+		createdBy = sql.NullString{String: link.CreatedBy, Valid: true}
+	}
+
+	query := `
+		INSERT INTO short_links (` + shortLinkColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (code) DO NOTHING`
+
+	result, err := r.db.Exec(query, link.ID, link.Code, link.PropertyID, campaign, createdBy, link.ClickCount, link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create short link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("short link code already exists: %s", link.Code)
+	}
+
+	return nil
+}
+
+// GetByCode retrieves a short link by its code
+func (r *PostgreSQLShortLinkRepository) GetByCode(code string) (*domain.ShortLink, error) {
+	query := `SELECT ` + shortLinkColumns + ` FROM short_links WHERE code = $1`
+
+	link, err := scanShortLink(r.db.QueryRow(query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("short link not found: %s", code)
+		}
+		return nil, fmt.Errorf("failed to get short link: %w", err)
+	}
+
+	return link, nil
+}
+
+// ListByProperty retrieves the short links of a property, newest first
+func (r *PostgreSQLShortLinkRepository) ListByProperty(propertyID string) ([]domain.ShortLink, error) {
+	query := `SELECT ` + shortLinkColumns + ` FROM short_links WHERE property_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query short links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []domain.ShortLink{}
+	for rows.Next() {
+		link, err := scanShortLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan short link: %w", err)
+		}
+		links = append(links, *link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return links, nil
+}
+
+// RecordClick saves a click through a link and increments its click count
+func (r *PostgreSQLShortLinkRepository) RecordClick(click *domain.ShortLinkClick) error {
+	if click == nil {
+		return fmt.Errorf("click cannot be nil")
+	}
+	if click.ID == "" {
+		click.ID = uuid.New().String()
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO short_link_clicks (id, link_id, source, clicked_at) VALUES ($1, $2, $3, $4)`,
+		click.ID, click.LinkID, click.Source, click.ClickedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record click: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE short_links SET click_count = click_count + 1 WHERE id = $1`, click.LinkID); err != nil {
+		return fmt.Errorf("failed to update click count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// StatsByProperty summarizes the clicks through a property's links by source and campaign.
+// Clicks of links without a campaign are counted under "none".
+func (r *PostgreSQLShortLinkRepository) StatsByProperty(propertyID string) (*domain.ShortLinkStats, error) {
+	stats := &domain.ShortLinkStats{BySource: map[string]int{}, ByCampaign: map[string]int{}}
+
+	err := r.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(click_count), 0)
+		FROM short_links WHERE property_id = $1`, propertyID).Scan(&stats.Links, &stats.Clicks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count short links: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT c.source, COALESCE(l.campaign, 'none'), COUNT(*)
+		FROM short_link_clicks c
+		JOIN short_links l ON l.id = c.link_id
+		WHERE l.property_id = $1
+		GROUP BY c.source, l.campaign`, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query click stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source, campaign string
+		var clicks int
+		if err := rows.Scan(&source, &campaign, &clicks); err != nil {
+			return nil, fmt.Errorf("failed to scan click stats: %w", err)
+		}
+		stats.BySource[source] += clicks
+		stats.ByCampaign[campaign] += clicks
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return stats, nil
+}
+
+// scanShortLink scans a row selected with shortLinkColumns
+func scanShortLink(row interface{ Scan(...interface{}) error }) (*domain.ShortLink, error) {
+	link := &domain.ShortLink{}
+	var campaign, createdBy sql.NullString
+	err := row.Scan(&link.ID, &link.Code, &link.PropertyID, &campaign, &createdBy, &link.ClickCount, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	link.Campaign = campaign.String
+	link.CreatedBy = createdBy.String
+	return link, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestShortLinkRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLShortLinkRepository(db)
+	link := &domain.ShortLink{ID: "link-1", Code: "aB3dE5f", PropertyID: "prop-1", CreatedAt: time.Now()}
+
+	mock.ExpectExec("INSERT INTO short_links .* ON CONFLICT \\(code\\) DO NOTHING").
+		WithArgs("link-1", "aB3dE5f", "prop-1", nil, nil, 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO short_links").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, repo.Create(link))
+	assert.ErrorContains(t, repo.Create(link), "already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShortLinkRepository_RecordClick(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLShortLinkRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO short_link_clicks").
+		WithArgs(sqlmock.AnyArg(), "link-1", "whatsapp", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE short_links SET click_count = click_count \\+ 1 WHERE id = \\$1").
+		WithArgs("link-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	click := &domain.ShortLinkClick{LinkID: "link-1", Source: "whatsapp", ClickedAt: time.Now()}
+	require.NoError(t, repo.RecordClick(click))
+	assert.NotEmpty(t, click.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShortLinkRepository_StatsByProperty(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLShortLinkRepository(db)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COALESCE\\(SUM\\(click_count\\), 0\\)").
+		WithArgs("prop-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "sum"}).AddRow(2, 6))
+	mock.ExpectQuery("FROM short_link_clicks c").
+		WithArgs("prop-1").
+		WillReturnRows(sqlmock.NewRows([]string{"source", "campaign", "count"}).
+			AddRow("whatsapp", "verano", 3).
+			AddRow("direct", "verano", 1).
+			AddRow("whatsapp", "none", 2))
+
+	stats, err := repo.StatsByProperty("prop-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Links)
+	assert.Equal(t, 6, stats.Clicks)
+	assert.Equal(t, map[string]int{"whatsapp": 5, "direct": 1}, stats.BySource)
+	assert.Equal(t, map[string]int{"verano": 4, "none": 2}, stats.ByCampaign)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
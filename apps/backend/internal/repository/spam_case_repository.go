@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"realty-core/internal/domain"
+)
+
+// SpamCaseRepository defines the interface for quarantined submissions
+type SpamCaseRepository interface {
+	// Create stores a spam case
+	Create(spamCase *domain.SpamCase) error
+
+	// GetByID retrieves a spam case
+	GetByID(id string) (*domain.SpamCase, error)
+
+	// List retrieves spam cases matching a filter, oldest first, with the total count
+	List(filter domain.SpamCaseFilter) ([]domain.SpamCase, int, error)
+
+	// Decide stores the decision on a quarantined case; a case decided meanwhile
+	// fails with a not found error
+	Decide(spamCase *domain.SpamCase) error
+}
+
+// PostgreSQLSpamCaseRepository implements SpamCaseRepository using PostgreSQL
+type PostgreSQLSpamCaseRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLSpamCaseRepository creates a new PostgreSQL spam case repository
+func NewPostgreSQLSpamCaseRepository(db *sql.DB) *PostgreSQLSpamCaseRepository {
+	return &PostgreSQLSpamCaseRepository{db: db}
+}
+
+const spamCaseColumns = `id, entity_type, entity_id, submitter_id, score, signals, status, reviewed_by, reviewed_at, created_at`
+
+// Create stores a spam case
+func (r *PostgreSQLSpamCaseRepository) Create(spamCase *domain.SpamCase) error {
+	signals, err := json.Marshal(spamCase.Signals)
+	if err != nil {
+		return fmt.Errorf("failed to encode spam signals: %w", err)
+	}
+
+	query := `
+		INSERT INTO spam_cases (` + spamCaseColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = r.db.Exec(query,
+		spamCase.ID, spamCase.EntityType, spamCase.EntityID,
+		sql.NullString{String: spamCase.SubmitterID, Valid: spamCase.SubmitterID != ""},
+		spamCase.Score, signals, spamCase.Status, spamCase.ReviewedBy, spamCase.ReviewedAt, spamCase.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create spam case: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a spam case
+func (r *PostgreSQLSpamCaseRepository) GetByID(id string) (*domain.SpamCase, error) {
+	query := `SELECT ` + spamCaseColumns + ` FROM spam_cases WHERE id = $1`
+
+	spamCase, err := scanSpamCase(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("spam case not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return spamCase, nil
+}
+
+// List retrieves spam cases matching a filter, oldest first, with the total count
+func (r *PostgreSQLSpamCaseRepository) List(filter domain.SpamCaseFilter) ([]domain.SpamCase, int, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM spam_cases`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count spam cases: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM spam_cases%s ORDER BY created_at LIMIT $%d OFFSET $%d`,
+		spamCaseColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list spam cases: %w", err)
+	}
+	defer rows.Close()
+
+	cases := []domain.SpamCase{}
+	for rows.Next() {
+		spamCase, err := scanSpamCase(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		cases = append(cases, *spamCase)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return cases, total, nil
+}
+
+// Decide stores the decision on a quarantined case
+func (r *PostgreSQLSpamCaseRepository) Decide(spamCase *domain.SpamCase) error {
+	query := `
+		UPDATE spam_cases SET status = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE id = $1 AND status = $5`
+
+	result, err := r.db.Exec(query,
+		spamCase.ID, spamCase.Status, spamCase.ReviewedBy, spamCase.ReviewedAt, domain.SpamCaseQuarantined)
+	if err != nil {
+		return fmt.Errorf("failed to decide spam case: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("spam case not found or already decided: %s", spamCase.ID)
+	}
+
+	return nil
+}
+
+// scanSpamCase scans a row selected with spamCaseColumns
+func scanSpamCase(row interface{ Scan(...interface{}) error }) (*domain.SpamCase, error) {
+	var spamCase domain.SpamCase
+	var submitterID, reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+	var signals []byte
+
+	err := row.Scan(&spamCase.ID, &spamCase.EntityType, &spamCase.EntityID, &submitterID, &spamCase.Score,
+		&signals, &spamCase.Status, &reviewedBy, &reviewedAt, &spamCase.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan spam case: %w", err)
+	}
+
+	spamCase.SubmitterID = submitterID.String
+	if err := json.Unmarshal(signals, &spamCase.Signals); err != nil {
+		return nil, fmt.Errorf("failed to decode spam signals: %w", err)
+	}
+	if reviewedBy.Valid {
+		spamCase.ReviewedBy = &reviewedBy.String
+	}
+	if reviewedAt.Valid {
+		spamCase.ReviewedAt = &reviewedAt.Time
+	}
+
+	return &spamCase, nil
+}
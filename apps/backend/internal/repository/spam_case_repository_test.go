@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestSpamCaseRepository_List(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLSpamCaseRepository(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM spam_cases WHERE status = \$1 AND entity_type = \$2`).
+		WithArgs(domain.SpamCaseQuarantined, domain.SpamEntityListing).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT (.+) FROM spam_cases WHERE (.+) ORDER BY created_at LIMIT \$3 OFFSET \$4`).
+		WithArgs(domain.SpamCaseQuarantined, domain.SpamEntityListing, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "submitter_id", "score", "signals",
+			"status", "reviewed_by", "reviewed_at", "created_at"}).
+			AddRow("case-1", domain.SpamEntityListing, "prop-1", nil, 100, []byte(`[{"name":"honeypot","score":100}]`),
+				domain.SpamCaseQuarantined, nil, nil, time.Now()))
+
+	cases, total, err := repo.List(domain.SpamCaseFilter{EntityType: domain.SpamEntityListing, Status: domain.SpamCaseQuarantined, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, cases, 1)
+	assert.Equal(t, "", cases[0].SubmitterID)
+	assert.Equal(t, []domain.SpamSignal{{Name: domain.SpamSignalHoneypot, Score: 100}}, cases[0].Signals)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamCaseRepository_Decide(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+	repo := NewPostgreSQLSpamCaseRepository(db)
+
+	spamCase := domain.NewSpamCase(domain.SpamEntityInquiry, "app-1", "user-1", domain.SpamAssessment{Score: 70})
+	require.NoError(t, spamCase.Decide(true, "admin-1", time.Now()))
+
+	mock.ExpectExec(`UPDATE spam_cases SET status = \$2, reviewed_by = \$3, reviewed_at = \$4\s+WHERE id = \$1 AND status = \$5`).
+		WithArgs(spamCase.ID, domain.SpamCaseApproved, spamCase.ReviewedBy, spamCase.ReviewedAt, domain.SpamCaseQuarantined).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorContains(t, repo.Decide(spamCase), "already decided")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// SubscriptionRepository defines the interface for agency subscription data
+type SubscriptionRepository interface {
+	// GetAgencyPlan returns the subscription plan of an agency and when it last changed
+	GetAgencyPlan(agencyID string) (string, *time.Time, error)
+
+	// SetAgencyPlan changes the subscription plan of an agency along with its image quota plan
+	SetAgencyPlan(agencyID, plan, imageQuotaPlan string, changedAt time.Time) error
+
+	// GetAgencyUsage returns the active listings, featured listings and image storage of an agency
+	GetAgencyUsage(agencyID string) (*domain.PlanUsage, error)
+}
+
+// PostgreSQLSubscriptionRepository implements SubscriptionRepository using PostgreSQL
+type PostgreSQLSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLSubscriptionRepository creates a new PostgreSQL subscription repository
+func NewPostgreSQLSubscriptionRepository(db *sql.DB) *PostgreSQLSubscriptionRepository {
+	return &PostgreSQLSubscriptionRepository{db: db}
+}
+
+// GetAgencyPlan returns the subscription plan of an agency and when it last changed
+func (r *PostgreSQLSubscriptionRepository) GetAgencyPlan(agencyID string) (string, *time.Time, error) {
+	var plan string
+	var changedAt sql.NullTime
+	err := r.db.QueryRow(`SELECT subscription_plan, plan_changed_at FROM agencies WHERE id = $1`, agencyID).Scan(&plan, &changedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil, fmt.Errorf("agency not found: %s", agencyID)
+		}
+		return "", nil, fmt.Errorf("failed to get agency plan: %w", err)
+	}
+
+	if changedAt.Valid {
+		return plan, &changedAt.Time, nil
+	}
+	return plan, nil, nil
+}
+
+// SetAgencyPlan changes the subscription plan of an agency along with its image quota plan
+func (r *PostgreSQLSubscriptionRepository) SetAgencyPlan(agencyID, plan, imageQuotaPlan string, changedAt time.Time) error {
+	query := `
+		UPDATE agencies
+		SET subscription_plan = $2, image_quota_plan = $3, plan_changed_at = $4, updated_at = $4
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, agencyID, plan, imageQuotaPlan, changedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update agency plan: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("agency not found: %s", agencyID)
+	}
+
+	return nil
+}
+
+// GetAgencyUsage returns the active listings, featured listings and image storage of an agency
+func (r *PostgreSQLSubscriptionRepository) GetAgencyUsage(agencyID string) (*domain.PlanUsage, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM properties WHERE agency_id = $1 AND status = 'available'),
+			(SELECT COUNT(*) FROM properties WHERE agency_id = $1 AND featured = TRUE AND status = 'available'),
+			(SELECT COALESCE(SUM(i.size), 0) FROM images i JOIN properties p ON p.id = i.property_id WHERE p.agency_id = $1)`
+
+	usage := &domain.PlanUsage{}
+	if err := r.db.QueryRow(query, agencyID).Scan(&usage.ActiveListings, &usage.FeaturedListings, &usage.StorageBytes); err != nil {
+		return nil, fmt.Errorf("failed to get agency plan usage: %w", err)
+	}
+	return usage, nil
+}
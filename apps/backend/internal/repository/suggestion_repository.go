@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// Suggestion categories provided by SuggestionRepository
+const (
+	SuggestionCategoryPopular  = "popular"
+	SuggestionCategoryProvince = "province"
+	SuggestionCategoryCity     = "city"
+	SuggestionCategoryAgency   = "agency"
+)
+
+// SuggestionRepository provides the data blended into autocomplete suggestions
+type SuggestionRepository struct {
+	db     *sql.DB
+	tenant tenantScope
+}
+
+// NewSuggestionRepository creates a new suggestion repository
+func NewSuggestionRepository(db *sql.DB) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// ForTenant returns the repository scoped to a tenant: queries are counted for the
+// tenant and catalogs list its listings and agencies only. An empty tenant returns the
+// repository itself, which counts queries for the default tenant.
+func (r *SuggestionRepository) ForTenant(tenantID string) *SuggestionRepository {
+	if tenantID == "" {
+		return r
+	}
+	scoped := *r
+	scoped.tenant = tenantScope(tenantID)
+	return &scoped
+}
+
+// RecordQuery increments the usage count of a normalized search query
+func (r *SuggestionRepository) RecordQuery(query string) error {
+	sqlQuery := `
+		INSERT INTO search_query_stats (tenant_id, query, search_count, first_searched_at, last_searched_at)
+		VALUES ($1, $2, 1, $3, $3)
+		ON CONFLICT (tenant_id, query) DO UPDATE
+		SET search_count = search_query_stats.search_count + 1,
+			last_searched_at = EXCLUDED.last_searched_at`
+
+	if _, err := r.db.Exec(sqlQuery, r.tenant.id(""), query, time.Now()); err != nil {
+		return fmt.Errorf("failed to record search query: %w", err)
+	}
+	return nil
+}
+
+// GetPopularQueries returns the most used queries of the tenant searched since the given
+// time at least minCount times. Queries searched only a few times are left out: they are
+// often names, addresses or phone numbers rather than popular searches.
+func (r *SuggestionRepository) GetPopularQueries(since time.Time, minCount, limit int) ([]SearchSuggestion, error) {
+	sqlQuery := `
+		SELECT query, search_count
+		FROM search_query_stats
+		WHERE tenant_id = $1 AND last_searched_at >= $2 AND search_count >= $3
+		ORDER BY search_count DESC, last_searched_at DESC
+		LIMIT $4`
+
+	rows, err := r.db.Query(sqlQuery, r.tenant.id(""), since, minCount, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSuggestions(rows, SuggestionCategoryPopular)
+}
+
+// GetLocationCatalog returns every province of Ecuador and the cities with listings,
+// with the number of properties in each
+func (r *SuggestionRepository) GetLocationCatalog() ([]SearchSuggestion, error) {
+	provinceCounts := make(map[string]int)
+	rows, err := r.db.Query(`SELECT province, COUNT(*) FROM properties`+r.tenant.where(1)+` GROUP BY province`, r.tenant.args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count properties by province: %w", err)
+	}
+	provinces, err := scanSuggestions(rows, SuggestionCategoryProvince)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	for _, province := range provinces {
+		provinceCounts[province.Text] = province.Frequency
+	}
+
+	catalog := make([]SearchSuggestion, 0, len(domain.EcuadorProvinces))
+	for _, province := range domain.EcuadorProvinces {
+		catalog = append(catalog, SearchSuggestion{
+			Text:      province,
+			Category:  SuggestionCategoryProvince,
+			Frequency: provinceCounts[province],
+		})
+	}
+
+	rows, err = r.db.Query(`SELECT city, COUNT(*) FROM properties WHERE city <> ''`+r.tenant.condition(1)+` GROUP BY city`, r.tenant.args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count properties by city: %w", err)
+	}
+	defer rows.Close()
+
+	cities, err := scanSuggestions(rows, SuggestionCategoryCity)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(catalog, cities...), nil
+}
+
+// GetAgencyNames returns active agencies with their number of listings
+func (r *SuggestionRepository) GetAgencyNames() ([]SearchSuggestion, error) {
+	sqlQuery := `
+		SELECT a.name, COUNT(p.id)
+		FROM agencies a
+		LEFT JOIN properties p ON p.agency_id = a.id
+		WHERE a.active = TRUE` + r.tenant.condition(1, "a.tenant_id") + `
+		GROUP BY a.id, a.name`
+
+	rows, err := r.db.Query(sqlQuery, r.tenant.args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agency names: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSuggestions(rows, SuggestionCategoryAgency)
+}
+
+// scanSuggestions scans (text, frequency) rows into suggestions of one category
+func scanSuggestions(rows *sql.Rows, category string) ([]SearchSuggestion, error) {
+	var suggestions []SearchSuggestion
+	for rows.Next() {
+		suggestion := SearchSuggestion{Category: category}
+		if err := rows.Scan(&suggestion.Text, &suggestion.Frequency); err != nil {
+			return nil, fmt.Errorf("failed to scan %s suggestion: %w", category, err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s suggestions: %w", category, err)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+func TestSuggestionRepository_RecordQuery(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewSuggestionRepository(db)
+
+	mock.ExpectExec(`INSERT INTO search_query_stats`).
+		WithArgs(domain.DefaultTenantID, "casa en quito", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO search_query_stats`).
+		WithArgs("tenant-a", "casa en quito", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RecordQuery("casa en quito"))
+	assert.NoError(t, repo.ForTenant("tenant-a").RecordQuery("casa en quito"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuggestionRepository_GetPopularQueries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewSuggestionRepository(db).ForTenant("tenant-a")
+	since := time.Now().Add(-24 * time.Hour)
+
+	mock.ExpectQuery(`SELECT query, search_count\s+FROM search_query_stats\s+WHERE tenant_id = \$1 AND last_searched_at >= \$2 AND search_count >= \$3`).
+		WithArgs("tenant-a", since, 5, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"query", "search_count"}).AddRow("casa en quito", 12))
+
+	popular, err := repo.GetPopularQueries(since, 5, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchSuggestion{{Text: "casa en quito", Category: SuggestionCategoryPopular, Frequency: 12}}, popular)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuggestionRepository_GetLocationCatalog(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewSuggestionRepository(db)
+
+	mock.ExpectQuery(`SELECT province, COUNT\(\*\) FROM properties`).
+		WillReturnRows(sqlmock.NewRows([]string{"province", "count"}).AddRow("Guayas", 150))
+	mock.ExpectQuery(`SELECT city, COUNT\(\*\) FROM properties`).
+		WillReturnRows(sqlmock.NewRows([]string{"city", "count"}).AddRow("Samborondón", 60))
+
+	catalog, err := repo.GetLocationCatalog()
+
+	assert.NoError(t, err)
+	// Every province is listed even without properties, followed by cities with listings
+	assert.Len(t, catalog, len(domain.EcuadorProvinces)+1)
+	for _, entry := range catalog {
+		if entry.Text == "Guayas" {
+			assert.Equal(t, 150, entry.Frequency)
+		}
+	}
+	last := catalog[len(catalog)-1]
+	assert.Equal(t, SearchSuggestion{Text: "Samborondón", Category: SuggestionCategoryCity, Frequency: 60}, last)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// TenantRepository defines the interface for tenant persistence
+type TenantRepository interface {
+	// Create saves a new tenant; a taken ID fails with "tenant already exists"
+	Create(tenant *domain.Tenant) error
+
+	// GetByID retrieves a tenant by its ID
+	GetByID(id string) (*domain.Tenant, error)
+
+	// List retrieves every tenant ordered by ID
+	List() ([]domain.Tenant, error)
+
+	// Update saves the name, domains, branding, settings and status of a tenant
+	Update(tenant *domain.Tenant) error
+}
+
+// PostgreSQLTenantRepository implements TenantRepository using PostgreSQL
+type PostgreSQLTenantRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLTenantRepository creates a new PostgreSQL tenant repository
+func NewPostgreSQLTenantRepository(db *sql.DB) *PostgreSQLTenantRepository {
+	return &PostgreSQLTenantRepository{db: db}
+}
+
+const tenantColumns = `id, name, domains, branding, settings, active, created_at, updated_at`
+
+// Create saves a new tenant
+func (r *PostgreSQLTenantRepository) Create(tenant *domain.Tenant) error {
+	branding, settings, err := encodeTenantJSON(tenant)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tenants (` + tenantColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO NOTHING`
+
+	result, err := r.db.Exec(query, tenant.ID, tenant.Name, pq.Array(tenant.Domains), branding, settings,
+		tenant.Active, tenant.CreatedAt, tenant.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tenant already exists: %s", tenant.ID)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tenant by its ID
+func (r *PostgreSQLTenantRepository) GetByID(id string) (*domain.Tenant, error) {
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE id = $1`
+
+	tenant, err := scanTenant(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tenant not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return tenant, nil
+}
+
+// List retrieves every tenant ordered by ID
+func (r *PostgreSQLTenantRepository) List() ([]domain.Tenant, error) {
+	query := `SELECT ` + tenantColumns + ` FROM tenants ORDER BY id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := []domain.Tenant{}
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, *tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return tenants, nil
+}
+
+// Update saves the name, domains, branding, settings and status of a tenant
+func (r *PostgreSQLTenantRepository) Update(tenant *domain.Tenant) error {
+	branding, settings, err := encodeTenantJSON(tenant)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tenants
+		SET name = $2, domains = $3, branding = $4, settings = $5, active = $6, updated_at = $7
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, tenant.ID, tenant.Name, pq.Array(tenant.Domains), branding, settings,
+		tenant.Active, tenant.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("tenant not found: %s", tenant.ID)
+	}
+
+	return nil
+}
+
+// encodeTenantJSON encodes the JSONB columns of a tenant
+func encodeTenantJSON(tenant *domain.Tenant) (string, string, error) {
+	branding, err := json.Marshal(tenant.Branding)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode tenant branding: %w", err)
+	}
+	settings := tenant.Settings
+	if settings == nil {
+		settings = map[string]string{}
+	}
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode tenant settings: %w", err)
+	}
+	return string(branding), string(settingsJSON), nil
+}
+
+// scanTenant scans a tenant row
+func scanTenant(row interface{ Scan(...interface{}) error }) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	var branding, settings []byte
+
+	err := row.Scan(&tenant.ID, &tenant.Name, pq.Array(&tenant.Domains), &branding, &settings,
+		&tenant.Active, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(branding) > 0 {
+		if err := json.Unmarshal(branding, &tenant.Branding); err != nil {
+			return nil, fmt.Errorf("failed to decode tenant branding: %w", err)
+		}
+	}
+	tenant.Settings = map[string]string{}
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &tenant.Settings); err != nil {
+			return nil, fmt.Errorf("failed to decode tenant settings: %w", err)
+		}
+	}
+	if tenant.Domains == nil {
+		tenant.Domains = []string{}
+	}
+
+	return &tenant, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestTenantRepository_Create(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLTenantRepository(db)
+	tenant, err := domain.NewTenant("red-norte", "Red Norte", []string{"rednorte.ec"})
+	require.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO tenants").
+		WithArgs("red-norte", "Red Norte", sqlmock.AnyArg(), `{"display_name":"Red Norte"}`, `{}`,
+			true, tenant.CreatedAt, tenant.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Create(tenant))
+
+	mock.ExpectExec("INSERT INTO tenants").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, repo.Create(tenant), "tenant already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepository_List(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLTenantRepository(db)
+	now := time.Date(2025, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "domains", "branding", "settings", "active", "created_at", "updated_at"}).
+		AddRow("default", "Realty Core", "{}", `{"display_name":"Realty Core"}`, `{}`, true, now, now).
+		AddRow("red-norte", "Red Norte", "{rednorte.ec,www.rednorte.ec}", `{"display_name":"Red Norte","primary_color":"#1a73e8"}`, `{"currency":"USD"}`, true, now, now)
+	mock.ExpectQuery("SELECT id, name, domains, branding, settings, active.*FROM tenants ORDER BY id").
+		WillReturnRows(rows)
+
+	tenants, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, tenants, 2)
+	assert.Empty(t, tenants[0].Domains)
+	assert.Equal(t, []string{"rednorte.ec", "www.rednorte.ec"}, tenants[1].Domains)
+	assert.Equal(t, "#1a73e8", tenants[1].Branding.PrimaryColor)
+	assert.Equal(t, "USD", tenants[1].Settings["currency"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepository_GetByIDNotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLTenantRepository(db)
+	mock.ExpectQuery("SELECT .* FROM tenants WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByID("missing")
+	assert.ErrorContains(t, err, "tenant not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
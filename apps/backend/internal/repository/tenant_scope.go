@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// tenantScope restricts the queries of a repository to the rows of a tenant. The zero
+// value leaves them unscoped, as in single-tenant installs.
+type tenantScope string
+
+// condition returns the condition restricting a raw query to the tenant, with the tenant
+// as placeholder $n, or nothing when unscoped. column is tenant_id unless given, e.g.
+// a.tenant_id in joins.
+func (t tenantScope) condition(n int, column ...string) string {
+	if t == "" {
+		return ""
+	}
+	name := "tenant_id"
+	if len(column) > 0 {
+		name = column[0]
+	}
+	return fmt.Sprintf(" AND %s = $%d", name, n)
+}
+
+// where is condition for queries without a WHERE clause
+func (t tenantScope) where(n int) string {
+	if t == "" {
+		return ""
+	}
+	return fmt.Sprintf(" WHERE tenant_id = $%d", n)
+}
+
+// args appends the tenant to the arguments of a raw query using condition or where
+func (t tenantScope) args(args ...interface{}) []interface{} {
+	if t == "" {
+		return args
+	}
+	return append(args, string(t))
+}
+
+// scope restricts a query builder to the tenant
+func (t tenantScope) scope(q *selectQuery) *selectQuery {
+	if t != "" {
+		q.Where("tenant_id = ?", string(t))
+	}
+	return q
+}
+
+// id returns the tenant new rows are created in, or fallback when unscoped and fallback
+// is set
+func (t tenantScope) id(fallback string) string {
+	if t != "" {
+		return string(t)
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return domain.DefaultTenantID
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+// anyArgsExcept matches n arguments, requiring value at position index
+func anyArgsExcept(n, index int, value driver.Value) []driver.Value {
+	args := make([]driver.Value, n)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[index] = value
+	return args
+}
+
+func TestUserRepository_ForTenant(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	assert.Same(t, repo, repo.ForTenant(""))
+	scoped := repo.ForTenant("beta")
+
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(anyArgsExcept(25, 24, "beta")...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	user := &domain.User{ID: "beta-user", Email: "ana@example.com", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	assert.NoError(t, scoped.Create(user))
+
+	// Unscoped repositories create users in the default tenant
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(anyArgsExcept(25, 24, domain.DefaultTenantID)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Create(user))
+
+	mock.ExpectExec(`UPDATE users SET .+WHERE id = \$1 AND tenant_id = \$23`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	user.ID = "acme-user"
+	assert.ErrorContains(t, scoped.Update(user), "user not found")
+
+	mock.ExpectExec(`DELETE FROM users WHERE id = \$1 AND tenant_id = \$2`).
+		WithArgs("acme-user", "beta").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, scoped.Delete("acme-user"), "user not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAgencyRepository_ForTenant(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	scoped := NewAgencyRepository(db).ForTenant("beta")
+
+	mock.ExpectQuery(`FROM agencies\s+WHERE id = \$1 AND tenant_id = \$2`).
+		WithArgs("acme-agency", "beta").
+		WillReturnError(sql.ErrNoRows)
+	_, err := scoped.GetByID("acme-agency")
+	assert.ErrorContains(t, err, "agency not found")
+
+	mock.ExpectExec(`UPDATE agencies\s+SET active = FALSE, updated_at = \$2\s+WHERE id = \$1 AND tenant_id = \$3`).
+		WithArgs("acme-agency", sqlmock.AnyArg(), "beta").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, scoped.Deactivate("acme-agency"), "agency not found")
+
+	mock.ExpectExec(`DELETE FROM agencies WHERE id = \$1 AND tenant_id = \$2`).
+		WithArgs("acme-agency", "beta").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorContains(t, scoped.Delete("acme-agency"), "agency not found")
+
+	mock.ExpectQuery(`FROM agencies\s+WHERE active = TRUE .+ AND tenant_id = \$1\s+ORDER BY name`).
+		WithArgs("beta").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = scoped.GetActive()
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// UploadSessionRepository defines the interface for direct upload session operations
+type UploadSessionRepository interface {
+	// Create saves a new upload session
+	Create(session *domain.UploadSession) error
+
+	// GetByID retrieves an upload session by ID
+	GetByID(id string) (*domain.UploadSession, error)
+
+	// Update saves the status, size, image and error of an upload session
+	Update(session *domain.UploadSession) error
+
+	// UpdatePending saves an upload session only while it is still pending, reporting
+	// whether it was, so concurrent confirmations cannot both claim it
+	UpdatePending(session *domain.UploadSession) (bool, error)
+
+	// CountPending returns the number of unexpired pending sessions for a property
+	CountPending(propertyID string) (int, error)
+
+	// ExpirePending marks pending sessions past their expiration as expired
+	ExpirePending(now time.Time) ([]domain.UploadSession, error)
+}
+
+// PostgreSQLUploadSessionRepository implements UploadSessionRepository using PostgreSQL
+type PostgreSQLUploadSessionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLUploadSessionRepository creates a new PostgreSQL upload session repository
+func NewPostgreSQLUploadSessionRepository(db *sql.DB) *PostgreSQLUploadSessionRepository {
+	return &PostgreSQLUploadSessionRepository{db: db}
+}
+
+const uploadSessionColumns = `id, property_id, object_key, file_name, content_type, size, alt_text,
+		status, COALESCE(image_id, ''), error, expires_at, created_at, updated_at`
+
+// Create saves a new upload session
+func (r *PostgreSQLUploadSessionRepository) Create(session *domain.UploadSession) error {
+	if session == nil {
+		return fmt.Errorf("upload session cannot be nil")
+	}
+
+	query := `
+		INSERT INTO image_upload_sessions (
+			id, property_id, object_key, file_name, content_type, size, alt_text,
+			status, error, expires_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.Exec(query,
+		session.ID, session.PropertyID, session.ObjectKey, session.FileName, session.ContentType,
+		session.Size, session.AltText, session.Status, session.Error, session.ExpiresAt,
+		session.CreatedAt, session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create upload session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an upload session by ID
+func (r *PostgreSQLUploadSessionRepository) GetByID(id string) (*domain.UploadSession, error) {
+	if id == "" {
+		return nil, fmt.Errorf("upload session ID cannot be empty")
+	}
+
+	query := `SELECT ` + uploadSessionColumns + ` FROM image_upload_sessions WHERE id = $1`
+
+	session, err := scanUploadSession(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("upload session not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+
+	return session, nil
+}
+
+// Update saves the status, size, image and error of an upload session
+func (r *PostgreSQLUploadSessionRepository) Update(session *domain.UploadSession) error {
+	if session == nil {
+		return fmt.Errorf("upload session cannot be nil")
+	}
+
+	query := `
+		UPDATE image_upload_sessions
+		SET status = $2, size = $3, image_id = NULLIF($4, ''), error = $5, updated_at = $6
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query,
+		session.ID, session.Status, session.Size, session.ImageID, session.Error, session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update upload session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("upload session not found: %s", session.ID)
+	}
+
+	return nil
+}
+
+// UpdatePending saves the status, size and error of an upload session in a single
+// statement conditioned on it still being pending
+func (r *PostgreSQLUploadSessionRepository) UpdatePending(session *domain.UploadSession) (bool, error) {
+	if session == nil {
+		return false, fmt.Errorf("upload session cannot be nil")
+	}
+
+	query := `
+		UPDATE image_upload_sessions
+		SET status = $2, size = $3, error = $4, updated_at = $5
+		WHERE id = $1 AND status = 'pending'`
+
+	result, err := r.db.Exec(query,
+		session.ID, session.Status, session.Size, session.Error, session.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update upload session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CountPending returns the number of unexpired pending sessions for a property
+func (r *PostgreSQLUploadSessionRepository) CountPending(propertyID string) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM image_upload_sessions
+		WHERE property_id = $1 AND status IN ('pending', 'processing') AND expires_at > $2`
+
+	var count int
+	if err := r.db.QueryRow(query, propertyID, time.Now()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pending uploads: %w", err)
+	}
+
+	return count, nil
+}
+
+// ExpirePending marks pending sessions past their expiration as expired and returns them
+// so their uploaded objects (if any) can be removed
+func (r *PostgreSQLUploadSessionRepository) ExpirePending(now time.Time) ([]domain.UploadSession, error) {
+	query := `
+		UPDATE image_upload_sessions
+		SET status = 'expired', updated_at = $1
+		WHERE status = 'pending' AND expires_at <= $1
+		RETURNING ` + uploadSessionColumns
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire upload sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []domain.UploadSession
+	for rows.Next() {
+		session, err := scanUploadSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan upload session: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// scanUploadSession scans a row selected with uploadSessionColumns
+func scanUploadSession(row interface{ Scan(...interface{}) error }) (*domain.UploadSession, error) {
+	session := &domain.UploadSession{}
+	err := row.Scan(
+		&session.ID, &session.PropertyID, &session.ObjectKey, &session.FileName,
+		&session.ContentType, &session.Size, &session.AltText, &session.Status,
+		&session.ImageID, &session.Error, &session.ExpiresAt, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestUploadSessionRepository_UpdatePending(t *testing.T) {
+	session := &domain.UploadSession{ID: "session-1"}
+	session.MarkProcessing(2048)
+
+	t.Run("claims a pending session", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectExec("UPDATE image_upload_sessions(.+)WHERE id = \\$1 AND status = 'pending'").
+			WithArgs("session-1", domain.UploadStatusProcessing, int64(2048), "", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		claimed, err := NewPostgreSQLUploadSessionRepository(db).UpdatePending(session)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reports a session another request already claimed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectExec("UPDATE image_upload_sessions(.+)WHERE id = \\$1 AND status = 'pending'").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		claimed, err := NewPostgreSQLUploadSessionRepository(db).UpdatePending(session)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// UserPreferencesRepository defines the interface for the preference center of users
+type UserPreferencesRepository interface {
+	// GetByUser retrieves the saved preferences of a user
+	GetByUser(userID string) (*domain.UserPreferences, error)
+
+	// Upsert creates or replaces the preferences of a user
+	Upsert(prefs *domain.UserPreferences) error
+}
+
+// PostgreSQLUserPreferencesRepository implements UserPreferencesRepository using PostgreSQL
+type PostgreSQLUserPreferencesRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLUserPreferencesRepository creates a new PostgreSQL user preferences repository
+func NewPostgreSQLUserPreferencesRepository(db *sql.DB) *PostgreSQLUserPreferencesRepository {
+	return &PostgreSQLUserPreferencesRepository{db: db}
+}
+
+const userPreferencesColumns = `user_id, notify_email, notify_sms, notify_push, email_frequency, locale,
+	search_provinces, search_property_types, search_min_price, search_max_price, updated_at`
+
+// GetByUser retrieves the saved preferences of a user
+func (r *PostgreSQLUserPreferencesRepository) GetByUser(userID string) (*domain.UserPreferences, error) {
+	query := `SELECT ` + userPreferencesColumns + ` FROM user_preferences WHERE user_id = $1`
+
+	prefs := &domain.UserPreferences{}
+	var minPrice, maxPrice sql.NullFloat64
+	err := r.db.QueryRow(query, userID).Scan(
+		&prefs.UserID, &prefs.Channels.Email, &prefs.Channels.SMS, &prefs.Channels.Push,
+		&prefs.EmailFrequency, &prefs.Locale,
+		pq.Array(&prefs.SearchDefaults.Provinces), pq.Array(&prefs.SearchDefaults.PropertyTypes),
+		&minPrice, &maxPrice, &prefs.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user preferences not found: %s", userID)
+		}
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+
+	if minPrice.Valid {
+		prefs.SearchDefaults.MinPrice = &minPrice.Float64
+	}
+	if maxPrice.Valid {
+		prefs.SearchDefaults.MaxPrice = &maxPrice.Float64
+	}
+
+	return prefs, nil
+}
+
+// Upsert creates or replaces the preferences of a user
+func (r *PostgreSQLUserPreferencesRepository) Upsert(prefs *domain.UserPreferences) error {
+	if prefs == nil {
+		return fmt.Errorf("user preferences cannot be nil")
+	}
+
+	query := `
+		INSERT INTO user_preferences (` + userPreferencesColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id) DO UPDATE SET
+			notify_email = EXCLUDED.notify_email, notify_sms = EXCLUDED.notify_sms,
+			notify_push = EXCLUDED.notify_push, email_frequency = EXCLUDED.email_frequency,
+			locale = EXCLUDED.locale, search_provinces = EXCLUDED.search_provinces,
+			search_property_types = EXCLUDED.search_property_types,
+			search_min_price = EXCLUDED.search_min_price, search_max_price = EXCLUDED.search_max_price,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(query,
+		prefs.UserID, prefs.Channels.Email, prefs.Channels.SMS, prefs.Channels.Push,
+		prefs.EmailFrequency, prefs.Locale,
+		pq.Array(prefs.SearchDefaults.Provinces), pq.Array(prefs.SearchDefaults.PropertyTypes),
+		prefs.SearchDefaults.MinPrice, prefs.SearchDefaults.MaxPrice, prefs.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+
+	return nil
+}
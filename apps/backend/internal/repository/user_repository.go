@@ -9,11 +9,14 @@ import (
 
 	"github.com/lib/pq"
 	"realty-core/internal/domain"
+	"realty-core/internal/pii"
 )
 
 // UserRepository handles database operations for users
 type UserRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher *pii.Cipher
+	tenant tenantScope // unscoped unless returned by ForTenant
 }
 
 // NewUserRepository creates a new user repository
@@ -21,6 +24,61 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// SetCipher encrypts phone numbers and national IDs at rest. National IDs are looked
+// up through their blind index.
+func (r *UserRepository) SetCipher(cipher *pii.Cipher) {
+	r.cipher = cipher
+}
+
+// ForTenant returns the repository scoped to a tenant: it only finds and changes the
+// users of the tenant and creates new users in it. An empty tenant returns the
+// repository itself.
+func (r *UserRepository) ForTenant(tenantID string) *UserRepository {
+	if tenantID == "" {
+		return r
+	}
+	scoped := *r
+	scoped.tenant = tenantScope(tenantID)
+	return &scoped
+}
+
+// sealedPII holds the encrypted PII columns of a user
+type sealedPII struct {
+	phone           *string
+	nationalID      *string
+	nationalIDIndex sql.NullString
+}
+
+// sealPII encrypts the PII columns of a user for writing
+func (r *UserRepository) sealPII(user *domain.User) (*sealedPII, error) {
+	phone, err := r.cipher.EncryptOptional(pii.UserPhone, user.ID, user.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone: %w", err)
+	}
+	nationalID, err := r.cipher.EncryptOptional(pii.UserNationalID, user.ID, user.Cedula)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt national ID: %w", err)
+	}
+
+	sealed := &sealedPII{phone: phone, nationalID: nationalID}
+	if user.Cedula != nil {
+		index := r.cipher.BlindIndex(*user.Cedula)
+		sealed.nationalIDIndex = sql.NullString{String: index, Valid: index != ""}
+	}
+	return sealed, nil
+}
+
+// openPII decrypts the PII columns of a scanned user
+func (r *UserRepository) openPII(user *domain.User) error {
+	if err := r.cipher.DecryptOptional(pii.UserPhone, user.ID, user.Phone); err != nil {
+		return fmt.Errorf("failed to decrypt phone of user %s: %w", user.ID, err)
+	}
+	if err := r.cipher.DecryptOptional(pii.UserNationalID, user.ID, user.Cedula); err != nil {
+		return fmt.Errorf("failed to decrypt national ID of user %s: %w", user.ID, err)
+	}
+	return nil
+}
+
 // Create creates a new user in the database
 func (r *UserRepository) Create(user *domain.User) error {
 	query := `
@@ -28,18 +86,25 @@ func (r *UserRepository) Create(user *domain.User) error {
 			id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			user_type, active, min_budget, max_budget, preferred_provinces, 
 			preferred_property_types, avatar_url, bio, real_estate_company_id,
-			receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			receive_notifications, receive_newsletter, agency_id, password_hash, created_at, updated_at,
+			national_id_index, phone_verified_at, tenant_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25
 		)`
 
-	_, err := r.db.Exec(query,
-		user.ID, user.FirstName, user.LastName, user.Email, user.Phone, user.Cedula,
+	sealed, err := r.sealPII(user)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(query,
+		user.ID, user.FirstName, user.LastName, user.Email, sealed.phone, sealed.nationalID,
 		user.DateOfBirth, user.Role, user.Active, user.MinBudget, user.MaxBudget,
 		pq.Array(user.PreferredProvinces), pq.Array(user.PreferredPropertyTypes),
 		user.AvatarURL, user.Bio, user.RealEstateCompanyID,
 		user.ReceiveNotifications, user.ReceiveNewsletter, user.AgencyID,
-		user.CreatedAt, user.UpdatedAt,
+		user.PasswordHash, user.CreatedAt, user.UpdatedAt, sealed.nationalIDIndex, user.PhoneVerifiedAt,
+		r.tenant.id(""),
 	)
 
 	if err != nil {
@@ -55,18 +120,18 @@ func (r *UserRepository) GetByID(id string) (*domain.User, error) {
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at, phone_verified_at
 		FROM users 
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(2)
 
 	user := &domain.User{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, r.tenant.args(id)...).Scan(
 		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.Phone,
 		&user.Cedula, &user.DateOfBirth, &user.Role, &user.Active,
 		&user.MinBudget, &user.MaxBudget, pq.Array(&user.PreferredProvinces),
 		pq.Array(&user.PreferredPropertyTypes), &user.AvatarURL, &user.Bio,
 		&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-		&user.AgencyID, &user.CreatedAt, &user.UpdatedAt,
+		&user.AgencyID, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 	)
 
 	if err != nil {
@@ -76,6 +141,11 @@ func (r *UserRepository) GetByID(id string) (*domain.User, error) {
 		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
 
+	user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+	if err := r.openPII(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -85,19 +155,19 @@ func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, password_hash, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, password_hash, created_at, updated_at, phone_verified_at
 		FROM users 
-		WHERE email = $1`
+		WHERE email = $1` + r.tenant.condition(2)
 
 	user := &domain.User{}
 	var provincesJSON, propertyTypesJSON []byte
-	err := r.db.QueryRow(query, email).Scan(
+	err := r.db.QueryRow(query, r.tenant.args(email)...).Scan(
 		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.Phone,
 		&user.Cedula, &user.DateOfBirth, &user.Role, &user.Active,
 		&user.MinBudget, &user.MaxBudget, &provincesJSON,
 		&propertyTypesJSON, &user.AvatarURL, &user.Bio,
 		&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-		&user.AgencyID, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt,
+		&user.AgencyID, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 	)
 	
 	if err == nil {
@@ -117,6 +187,11 @@ func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
+	user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+	if err := r.openPII(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -126,18 +201,20 @@ func (r *UserRepository) GetByNationalID(national_id string) (*domain.User, erro
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at, phone_verified_at
 		FROM users 
-		WHERE national_id = $1`
+		WHERE (national_id_index = $1 OR national_id = $2)` + r.tenant.condition(3)
 
+	// Encrypted national IDs are found by their blind index; rows the re-encryption
+	// job has not reached yet still hold the plaintext
 	user := &domain.User{}
-	err := r.db.QueryRow(query, national_id).Scan(
+	err := r.db.QueryRow(query, r.tenant.args(r.cipher.BlindIndex(national_id), national_id)...).Scan(
 		&user.ID, &user.FirstName, &user.LastName, &user.Email, &user.Phone,
 		&user.Cedula, &user.DateOfBirth, &user.Role, &user.Active,
 		&user.MinBudget, &user.MaxBudget, pq.Array(&user.PreferredProvinces),
 		pq.Array(&user.PreferredPropertyTypes), &user.AvatarURL, &user.Bio,
 		&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-		&user.AgencyID, &user.CreatedAt, &user.UpdatedAt,
+		&user.AgencyID, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 	)
 
 	if err != nil {
@@ -147,6 +224,11 @@ func (r *UserRepository) GetByNationalID(national_id string) (*domain.User, erro
 		return nil, fmt.Errorf("failed to get user by national ID: %w", err)
 	}
 
+	user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+	if err := r.openPII(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -159,32 +241,49 @@ func (r *UserRepository) Update(user *domain.User) error {
 			min_budget = $10, max_budget = $11, preferred_provinces = $12, 
 			preferred_property_types = $13, avatar_url = $14, bio = $15, 
 			real_estate_company_id = $16, receive_notifications = $17, 
-			receive_newsletter = $18, agency_id = $19, updated_at = $20
-		WHERE id = $1`
+			receive_newsletter = $18, agency_id = $19, updated_at = $20, national_id_index = $21,
+			phone_verified_at = $22
+		WHERE id = $1` + r.tenant.condition(23)
 
-	_, err := r.db.Exec(query,
-		user.ID, user.FirstName, user.LastName, user.Email, user.Phone,
-		user.Cedula, user.DateOfBirth, user.Role, user.Active,
+	sealed, err := r.sealPII(user)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(query, r.tenant.args(
+		user.ID, user.FirstName, user.LastName, user.Email, sealed.phone,
+		sealed.nationalID, user.DateOfBirth, user.Role, user.Active,
 		user.MinBudget, user.MaxBudget, pq.Array(user.PreferredProvinces),
 		pq.Array(user.PreferredPropertyTypes), user.AvatarURL, user.Bio,
 		user.RealEstateCompanyID, user.ReceiveNotifications, user.ReceiveNewsletter,
-		user.AgencyID, user.UpdatedAt,
-	)
+		user.AgencyID, user.UpdatedAt, sealed.nationalIDIndex, user.PhoneVerifiedAt,
+	)...)
 
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return nil
+	return r.requireRow(result, user.ID)
 }
 
 // Delete deletes a user from the database
 func (r *UserRepository) Delete(id string) error {
-	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+	query := `DELETE FROM users WHERE id = $1` + r.tenant.condition(2)
+	result, err := r.db.Exec(query, r.tenant.args(id)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+	return r.requireRow(result, id)
+}
+
+// requireRow reports users of other tenants as not found to scoped repositories
+func (r *UserRepository) requireRow(result sql.Result, id string) error {
+	if r.tenant == "" {
+		return nil
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("user not found with id: %s", id)
+	}
 	return nil
 }
 
@@ -194,12 +293,12 @@ func (r *UserRepository) GetByUserType(role domain.UserRole) ([]*domain.User, er
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at, phone_verified_at
 		FROM users 
-		WHERE user_type = $1 AND active = TRUE
+		WHERE user_type = $1 AND active = TRUE` + r.tenant.condition(2) + `
 		ORDER BY created_at DESC`
 
-	rows, err := r.db.Query(query, role)
+	rows, err := r.db.Query(query, r.tenant.args(role)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users by role: %w", err)
 	}
@@ -214,11 +313,15 @@ func (r *UserRepository) GetByUserType(role domain.UserRole) ([]*domain.User, er
 			&user.MinBudget, &user.MaxBudget, pq.Array(&user.PreferredProvinces),
 			pq.Array(&user.PreferredPropertyTypes), &user.AvatarURL, &user.Bio,
 			&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt,
+			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+		if err := r.openPII(user); err != nil {
+			return nil, err
+		}
 		users = append(users, user)
 	}
 
@@ -231,12 +334,12 @@ func (r *UserRepository) GetByAgency(agencyID string) ([]*domain.User, error) {
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at, phone_verified_at
 		FROM users 
-		WHERE agency_id = $1 AND active = TRUE
+		WHERE agency_id = $1 AND active = TRUE` + r.tenant.condition(2) + `
 		ORDER BY first_name, last_name`
 
-	rows, err := r.db.Query(query, agencyID)
+	rows, err := r.db.Query(query, r.tenant.args(agencyID)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users by agency: %w", err)
 	}
@@ -251,11 +354,15 @@ func (r *UserRepository) GetByAgency(agencyID string) ([]*domain.User, error) {
 			&user.MinBudget, &user.MaxBudget, pq.Array(&user.PreferredProvinces),
 			pq.Array(&user.PreferredPropertyTypes), &user.AvatarURL, &user.Bio,
 			&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt,
+			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+		if err := r.openPII(user); err != nil {
+			return nil, err
+		}
 		users = append(users, user)
 	}
 
@@ -269,7 +376,7 @@ func (r *UserRepository) Search(params *domain.UserSearchParams) ([]*domain.User
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at, phone_verified_at
 		FROM users WHERE 1=1`
 
 	countQuery := `SELECT COUNT(*) FROM users WHERE 1=1`
@@ -321,6 +428,12 @@ func (r *UserRepository) Search(params *domain.UserSearchParams) ([]*domain.User
 		argIndex++
 	}
 
+	if r.tenant != "" {
+		conditions = append(conditions, fmt.Sprintf(`tenant_id = $%d`, argIndex))
+		args = append(args, string(r.tenant))
+		argIndex++
+	}
+
 	// Add conditions to queries
 	if len(conditions) > 0 {
 		conditionStr := " AND " + strings.Join(conditions, " AND ")
@@ -358,11 +471,15 @@ func (r *UserRepository) Search(params *domain.UserSearchParams) ([]*domain.User
 			&user.MinBudget, &user.MaxBudget, pq.Array(&user.PreferredProvinces),
 			pq.Array(&user.PreferredPropertyTypes), &user.AvatarURL, &user.Bio,
 			&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt,
+			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
 		}
+		user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+		if err := r.openPII(user); err != nil {
+			return nil, 0, err
+		}
 		users = append(users, user)
 	}
 
@@ -375,14 +492,14 @@ func (r *UserRepository) GetBuyersByBudget(price float64) ([]*domain.User, error
 		SELECT id, first_name, last_name, email, phone, national_id, date_of_birth, 
 			   user_type, active, min_budget, max_budget, preferred_provinces, 
 			   preferred_property_types, avatar_url, bio, real_estate_company_id,
-			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at
+			   receive_notifications, receive_newsletter, agency_id, created_at, updated_at, phone_verified_at
 		FROM users 
 		WHERE user_type = 'buyer' AND active = TRUE 
 		  AND min_budget IS NOT NULL AND max_budget IS NOT NULL
-		  AND $1 >= min_budget AND $1 <= max_budget
+		  AND $1 >= min_budget AND $1 <= max_budget` + r.tenant.condition(2) + `
 		ORDER BY max_budget DESC`
 
-	rows, err := r.db.Query(query, price)
+	rows, err := r.db.Query(query, r.tenant.args(price)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get buyers by budget: %w", err)
 	}
@@ -397,11 +514,15 @@ func (r *UserRepository) GetBuyersByBudget(price float64) ([]*domain.User, error
 			&user.MinBudget, &user.MaxBudget, pq.Array(&user.PreferredProvinces),
 			pq.Array(&user.PreferredPropertyTypes), &user.AvatarURL, &user.Bio,
 			&user.RealEstateCompanyID, &user.ReceiveNotifications, &user.ReceiveNewsletter,
-			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt,
+			&user.AgencyID, &user.CreatedAt, &user.UpdatedAt, &user.PhoneVerifiedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		user.AvatarVariants = domain.ProfileImageVariants(user.AvatarURL)
+		if err := r.openPII(user); err != nil {
+			return nil, err
+		}
 		users = append(users, user)
 	}
 
@@ -421,10 +542,10 @@ func (r *UserRepository) GetStatistics() (*domain.UserStats, error) {
 			COUNT(*) FILTER (WHERE user_type = 'buyer') as buyer_count,
 			COUNT(*) FILTER (WHERE min_budget IS NOT NULL AND max_budget IS NOT NULL) as with_budget,
 			COUNT(*) FILTER (WHERE agency_id IS NOT NULL) as associated_agents
-		FROM users`
+		FROM users` + r.tenant.where(1)
 
 	stats := &domain.UserStats{}
-	err := r.db.QueryRow(query).Scan(
+	err := r.db.QueryRow(query, r.tenant.args()...).Scan(
 		&stats.TotalUsers, &stats.ActiveUsers, &stats.AdminCount,
 		&stats.AgencyCount, &stats.AgentCount, &stats.OwnerCount,
 		&stats.BuyerCount, &stats.WithBudget, &stats.AssociatedAgents,
@@ -444,9 +565,9 @@ func (r *UserRepository) SetEmailVerified(userID string, verified bool) error {
 	query := `
 		UPDATE users 
 		SET updated_at = $2
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(3)
 
-	_, err := r.db.Exec(query, userID, time.Now())
+	_, err := r.db.Exec(query, r.tenant.args(userID, time.Now())...)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -461,9 +582,9 @@ func (r *UserRepository) SetPasswordResetToken(userID, token string, expiry time
 	query := `
 		UPDATE users 
 		SET updated_at = $2
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(3)
 
-	_, err := r.db.Exec(query, userID, time.Now())
+	_, err := r.db.Exec(query, r.tenant.args(userID, time.Now())...)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -478,9 +599,9 @@ func (r *UserRepository) ClearPasswordResetToken(userID string) error {
 	query := `
 		UPDATE users 
 		SET updated_at = $2
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(3)
 
-	_, err := r.db.Exec(query, userID, time.Now())
+	_, err := r.db.Exec(query, r.tenant.args(userID, time.Now())...)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -494,9 +615,9 @@ func (r *UserRepository) UpdateLastLogin(userID string) error {
 	query := `
 		UPDATE users 
 		SET updated_at = $2
-		WHERE id = $1`
+		WHERE id = $1` + r.tenant.condition(3)
 
-	_, err := r.db.Exec(query, userID, now)
+	_, err := r.db.Exec(query, r.tenant.args(userID, now)...)
 	if err != nil {
 		return fmt.Errorf("failed to update last login: %w", err)
 	}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// ValuationRepository defines the interface for the comparables of price suggestions
+type ValuationRepository interface {
+	// FindComparables lists listings matching the criteria: sales closed since the
+	// criteria's date at their final price, and listings on the market at their asking
+	// price, most recent first
+	FindComparables(criteria domain.ComparableCriteria) ([]domain.Comparable, error)
+}
+
+// PostgreSQLValuationRepository implements ValuationRepository using PostgreSQL
+type PostgreSQLValuationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLValuationRepository creates a new PostgreSQL valuation repository
+func NewPostgreSQLValuationRepository(db *sql.DB) *PostgreSQLValuationRepository {
+	return &PostgreSQLValuationRepository{db: db}
+}
+
+// FindComparables lists the comparables matching the criteria
+func (r *PostgreSQLValuationRepository) FindComparables(criteria domain.ComparableCriteria) ([]domain.Comparable, error) {
+	query := `
+		SELECT id, title, city, sector, area_m2, bedrooms, price, source, date FROM (
+			SELECT p.id, p.title, p.city, p.sector, p.area_m2, p.bedrooms, d.final_price AS price,
+				'sold' AS source, d.closing_date AS date
+			FROM deals d
+			JOIN properties p ON p.id = d.property_id
+			WHERE d.type = 'sale' AND d.closing_date >= $7
+			AND p.province = $1 AND ($2 = '' OR p.city = $2) AND ($3 = '' OR p.sector = $3)
+			AND p.type = $4 AND p.area_m2 BETWEEN $5 AND $6
+			UNION ALL
+			SELECT p.id, p.title, p.city, p.sector, p.area_m2, p.bedrooms, p.price,
+				'listed' AS source, COALESCE(p.renewed_at, p.created_at) AS date
+			FROM properties p
+			WHERE p.status IN ('available', 'reserved') AND COALESCE(p.renewed_at, p.created_at) >= $7
+			AND p.province = $1 AND ($2 = '' OR p.city = $2) AND ($3 = '' OR p.sector = $3)
+			AND p.type = $4 AND p.area_m2 BETWEEN $5 AND $6 AND p.price > 0
+		) comparables
+		ORDER BY date DESC
+		LIMIT $8`
+
+	rows, err := r.db.Query(query, criteria.Province, criteria.City, criteria.Sector, criteria.Type,
+		criteria.MinArea, criteria.MaxArea, criteria.Since, criteria.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comparables: %w", err)
+	}
+	defer rows.Close()
+
+	comparables := []domain.Comparable{}
+	for rows.Next() {
+		var comparable domain.Comparable
+		var sector sql.NullString
+		if err := rows.Scan(&comparable.PropertyID, &comparable.Title, &comparable.City, &sector, &comparable.AreaM2,
+			&comparable.Bedrooms, &comparable.Price, &comparable.Source, &comparable.Date); err != nil {
+			return nil, fmt.Errorf("failed to scan comparable: %w", err)
+		}
+		if sector.Valid {
+			comparable.Sector = &sector.String
+		}
+		comparable.PricePerM2 = comparable.Price / comparable.AreaM2
+		comparables = append(comparables, comparable)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comparables: %w", err)
+	}
+	return comparables, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"realty-core/internal/domain"
+)
+
+// VideoRepository defines the interface for property video operations
+type VideoRepository interface {
+	// Create saves a new video record
+	Create(video *domain.PropertyVideo) error
+
+	// GetByID retrieves a video by ID
+	GetByID(id string) (*domain.PropertyVideo, error)
+
+	// GetByPropertyID retrieves all videos of a property
+	GetByPropertyID(propertyID string) ([]domain.PropertyVideo, error)
+
+	// Update saves the processing results of a video
+	Update(video *domain.PropertyVideo) error
+
+	// Delete removes a video record
+	Delete(id string) error
+
+	// CountByProperty returns the number of videos of a property
+	CountByProperty(propertyID string) (int, error)
+}
+
+// PostgreSQLVideoRepository implements VideoRepository using PostgreSQL
+type PostgreSQLVideoRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLVideoRepository creates a new PostgreSQL video repository
+func NewPostgreSQLVideoRepository(db *sql.DB) *PostgreSQLVideoRepository {
+	return &PostgreSQLVideoRepository{db: db}
+}
+
+const videoColumns = `id, property_id, file_name, content_type, size, duration_seconds, status,
+		renditions, thumbnail_url, storage_prefix, stored_paths, error, created_at, updated_at`
+
+// Create saves a new video record
+func (r *PostgreSQLVideoRepository) Create(video *domain.PropertyVideo) error {
+	if video == nil {
+		return fmt.Errorf("video cannot be nil")
+	}
+
+	renditions, storedPaths, err := encodeVideoLists(video)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO property_videos (` + videoColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err = r.db.Exec(query,
+		video.ID, video.PropertyID, video.FileName, video.ContentType, video.Size,
+		video.DurationSeconds, video.Status, renditions, video.ThumbnailURL,
+		video.StoragePrefix, storedPaths, video.Error, video.CreatedAt, video.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create video: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a video by ID
+func (r *PostgreSQLVideoRepository) GetByID(id string) (*domain.PropertyVideo, error) {
+	if id == "" {
+		return nil, fmt.Errorf("video ID cannot be empty")
+	}
+
+	query := `SELECT ` + videoColumns + ` FROM property_videos WHERE id = $1`
+
+	video, err := scanVideo(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("video not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get video: %w", err)
+	}
+
+	return video, nil
+}
+
+// GetByPropertyID retrieves all videos of a property
+func (r *PostgreSQLVideoRepository) GetByPropertyID(propertyID string) ([]domain.PropertyVideo, error) {
+	query := `SELECT ` + videoColumns + ` FROM property_videos WHERE property_id = $1 ORDER BY created_at ASC`
+
+	rows, err := r.db.Query(query, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query videos: %w", err)
+	}
+	defer rows.Close()
+
+	videos := []domain.PropertyVideo{}
+	for rows.Next() {
+		video, err := scanVideo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan video: %w", err)
+		}
+		videos = append(videos, *video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return videos, nil
+}
+
+// Update saves the processing results of a video
+func (r *PostgreSQLVideoRepository) Update(video *domain.PropertyVideo) error {
+	if video == nil {
+		return fmt.Errorf("video cannot be nil")
+	}
+
+	renditions, storedPaths, err := encodeVideoLists(video)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE property_videos SET
+			duration_seconds = $2, status = $3, renditions = $4, thumbnail_url = $5,
+			stored_paths = $6, error = $7, updated_at = $8
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query,
+		video.ID, video.DurationSeconds, video.Status, renditions, video.ThumbnailURL,
+		storedPaths, video.Error, video.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update video: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("video not found: %s", video.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a video record
+func (r *PostgreSQLVideoRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM property_videos WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete video: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("video not found: %s", id)
+	}
+
+	return nil
+}
+
+// CountByProperty returns the number of videos of a property
+func (r *PostgreSQLVideoRepository) CountByProperty(propertyID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM property_videos WHERE property_id = $1`, propertyID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count videos: %w", err)
+	}
+	return count, nil
+}
+
+// encodeVideoLists encodes the JSONB columns of a video
+func encodeVideoLists(video *domain.PropertyVideo) ([]byte, []byte, error) {
+	renditions, err := json.Marshal(video.Renditions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode renditions: %w", err)
+	}
+	storedPaths, err := json.Marshal(video.StoredPaths)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode stored paths: %w", err)
+	}
+	return renditions, storedPaths, nil
+}
+
+// scanVideo scans a row selected with videoColumns
+func scanVideo(row interface{ Scan(...interface{}) error }) (*domain.PropertyVideo, error) {
+	video := &domain.PropertyVideo{}
+	var renditions, storedPaths []byte
+	err := row.Scan(
+		&video.ID, &video.PropertyID, &video.FileName, &video.ContentType, &video.Size,
+		&video.DurationSeconds, &video.Status, &renditions, &video.ThumbnailURL,
+		&video.StoragePrefix, &storedPaths, &video.Error, &video.CreatedAt, &video.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	video.Renditions = []domain.VideoRendition{}
+	if err := json.Unmarshal(renditions, &video.Renditions); err != nil {
+		return nil, fmt.Errorf("failed to decode renditions: %w", err)
+	}
+	video.StoredPaths = []string{}
+	if err := json.Unmarshal(storedPaths, &video.StoredPaths); err != nil {
+		return nil, fmt.Errorf("failed to decode stored paths: %w", err)
+	}
+
+	return video, nil
+}
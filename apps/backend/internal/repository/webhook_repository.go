@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"realty-core/internal/domain"
+)
+
+// WebhookRepository defines the interface for webhook subscriptions and their deliveries
+type WebhookRepository interface {
+	// CreateSubscription saves a new subscription
+	CreateSubscription(subscription *domain.WebhookSubscription) error
+
+	// GetSubscription retrieves a subscription by ID, including its secret
+	GetSubscription(id string) (*domain.WebhookSubscription, error)
+
+	// ListSubscriptions retrieves the subscriptions of an agency, or every subscription
+	// when agencyID is empty, newest first
+	ListSubscriptions(agencyID string) ([]domain.WebhookSubscription, error)
+
+	// ListSubscriptionsForEvent retrieves the active subscriptions receiving an event of
+	// an agency's listing: the agency's own and those without an agency
+	ListSubscriptionsForEvent(eventType, agencyID string) ([]domain.WebhookSubscription, error)
+
+	// UpdateSubscription saves the URL, event types, description and status of a subscription
+	UpdateSubscription(subscription *domain.WebhookSubscription) error
+
+	// DeleteSubscription deletes a subscription and its deliveries
+	DeleteSubscription(id string) error
+
+	// CreateDeliveries queues deliveries in a single transaction, skipping events
+	// already queued for a subscription
+	CreateDeliveries(deliveries []domain.WebhookDelivery) error
+
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now and postpones
+	// them by lease, so other instances skip them while they are attempted
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+
+	// UpdateDelivery saves the outcome of a delivery attempt
+	UpdateDelivery(delivery *domain.WebhookDelivery) error
+
+	// ListDeliveries retrieves the latest deliveries of a subscription, newest first
+	ListDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// PostgreSQLWebhookRepository implements WebhookRepository using PostgreSQL
+type PostgreSQLWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLWebhookRepository creates a new PostgreSQL webhook repository
+func NewPostgreSQLWebhookRepository(db *sql.DB) *PostgreSQLWebhookRepository {
+	return &PostgreSQLWebhookRepository{db: db}
+}
+
+const webhookSubscriptionColumns = `id, agency_id, url, secret, event_types, description, active,
+		created_by, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+		next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+// CreateSubscription saves a new subscription
+func (r *PostgreSQLWebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	if subscription == nil {
+		return fmt.Errorf("webhook subscription cannot be nil")
+	}
+
+	query := `INSERT INTO webhook_subscriptions (` + webhookSubscriptionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.Exec(query,
+		subscription.ID, subscription.AgencyID, subscription.URL, subscription.Secret,
+		pq.Array(subscription.EventTypes), subscription.Description, subscription.Active,
+		subscription.CreatedBy, subscription.CreatedAt, subscription.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID
+func (r *PostgreSQLWebhookRepository) GetSubscription(id string) (*domain.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	subscription, err := scanWebhookSubscription(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook subscription not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// ListSubscriptions retrieves the subscriptions of an agency, or all of them
+func (r *PostgreSQLWebhookRepository) ListSubscriptions(agencyID string) ([]domain.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions`
+	var args []interface{}
+	if agencyID != "" {
+		query += ` WHERE agency_id = $1`
+		args = append(args, agencyID)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	return r.querySubscriptions(query, args...)
+}
+
+// ListSubscriptionsForEvent retrieves the active subscriptions receiving an event
+func (r *PostgreSQLWebhookRepository) ListSubscriptionsForEvent(eventType, agencyID string) ([]domain.WebhookSubscription, error) {
+	var agency sql.NullString
+	if agencyID != "" {
+		agency = sql.NullString{String: agencyID, Valid: true}
+	}
+
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions
+		WHERE active AND $1 = ANY(event_types) AND (agency_id IS NULL OR agency_id = $2)`
+
+	return r.querySubscriptions(query, eventType, agency)
+}
+
+// UpdateSubscription saves the changeable fields of a subscription
+func (r *PostgreSQLWebhookRepository) UpdateSubscription(subscription *domain.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = $2, event_types = $3, description = $4, active = $5, updated_at = $6
+		WHERE id = $1`
+
+	result, err := r.db.Exec(query, subscription.ID, subscription.URL, pq.Array(subscription.EventTypes),
+		subscription.Description, subscription.Active, subscription.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook subscription not found: %s", subscription.ID)
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a subscription; its deliveries cascade
+func (r *PostgreSQLWebhookRepository) DeleteSubscription(id string) error {
+	result, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook subscription not found: %s", id)
+	}
+
+	return nil
+}
+
+// CreateDeliveries queues deliveries in a single transaction. Redelivered outbox
+// events keep their ID, so a delivery already queued for the subscription is skipped.
+func (r *PostgreSQLWebhookRepository) CreateDeliveries(deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	for _, delivery := range deliveries {
+		_, err := tx.Exec(query,
+			delivery.ID, delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload),
+			delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode,
+			delivery.LastError, delivery.CreatedAt, delivery.DeliveredAt)
+		if err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries leases the pending deliveries due at now
+func (r *PostgreSQLWebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	rows, err := r.db.Query(query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (r *PostgreSQLWebhookRepository) UpdateDelivery(delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+			last_error = $6, delivered_at = $7
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves the latest deliveries of a subscription
+func (r *PostgreSQLWebhookRepository) ListDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// querySubscriptions runs a query selecting webhookSubscriptionColumns
+func (r *PostgreSQLWebhookRepository) querySubscriptions(query string, args ...interface{}) ([]domain.WebhookSubscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []domain.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// scanWebhookSubscription scans a row selected with webhookSubscriptionColumns
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }) (*domain.WebhookSubscription, error) {
+	subscription := &domain.WebhookSubscription{}
+	var agencyID, createdBy sql.NullString
+	err := row.Scan(
+		&subscription.ID, &agencyID, &subscription.URL, &subscription.Secret,
+		pq.Array(&subscription.EventTypes), &subscription.Description, &subscription.Active,
+		&createdBy, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if agencyID.Valid {
+		subscription.AgencyID = &agencyID.String
+	}
+	subscription.CreatedBy = createdBy.String
+
+	return subscription, nil
+}
+
+// scanWebhookDeliveries scans rows selected with webhookDeliveryColumns
+func scanWebhookDeliveries(rows *sql.Rows) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		var payload []byte
+		var nextAttemptAt, deliveredAt sql.NullTime
+		err := rows.Scan(
+			&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload,
+			&delivery.Status, &delivery.Attempts, &nextAttemptAt, &delivery.LastStatusCode,
+			&delivery.LastError, &delivery.CreatedAt, &deliveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+
+		delivery.Payload = payload
+		if nextAttemptAt.Valid {
+			delivery.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return deliveries, nil
+}
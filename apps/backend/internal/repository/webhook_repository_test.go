@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository_ListSubscriptionsForEvent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLWebhookRepository(db)
+	now := time.Date(2025, 8, 17, 12, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "agency_id", "url", "secret", "event_types", "description", "active", "created_by", "created_at", "updated_at"}).
+		AddRow("sub-1", "agency-1", "https://crm.example.com", "whsec_1", "{property.created,property.sold}", "", true, "user-1", now, now).
+		AddRow("sub-2", nil, "https://audit.example.com", "whsec_2", "{property.sold}", "audit", true, nil, now, now)
+	mock.ExpectQuery("SELECT .* FROM webhook_subscriptions\\s+WHERE active AND \\$1 = ANY\\(event_types\\) AND \\(agency_id IS NULL OR agency_id = \\$2\\)").
+		WithArgs("property.sold", "agency-1").
+		WillReturnRows(rows)
+
+	subscriptions, err := repo.ListSubscriptionsForEvent("property.sold", "agency-1")
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	assert.Equal(t, "agency-1", *subscriptions[0].AgencyID)
+	assert.Equal(t, []string{"property.created", "property.sold"}, subscriptions[0].EventTypes)
+	assert.Nil(t, subscriptions[1].AgencyID)
+	assert.Empty(t, subscriptions[1].CreatedBy, "the creator of the subscription was purged")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_ClaimDueDeliveries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLWebhookRepository(db)
+	now := time.Date(2025, 8, 17, 12, 0, 0, 0, time.UTC)
+	lease := 5 * time.Minute
+
+	rows := sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at"}).
+		AddRow("del-1", "sub-1", "evt-1", "property.sold", []byte(`{"id":"evt-1"}`), "pending", 1, now.Add(lease), 500, "unexpected status code 500", now, nil)
+	mock.ExpectQuery("UPDATE webhook_deliveries SET next_attempt_at = \\$2.*FOR UPDATE SKIP LOCKED").
+		WithArgs(now, now.Add(lease), 10).
+		WillReturnRows(rows)
+
+	deliveries, err := repo.ClaimDueDeliveries(now, lease, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.JSONEq(t, `{"id":"evt-1"}`, string(deliveries[0].Payload))
+	assert.Equal(t, now.Add(lease), *deliveries[0].NextAttemptAt)
+	assert.Nil(t, deliveries[0].DeliveredAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_DeleteSubscriptionNotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgreSQLWebhookRepository(db)
+	mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorContains(t, repo.DeleteSubscription("missing"), "webhook subscription not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
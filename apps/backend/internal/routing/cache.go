@@ -0,0 +1,102 @@
+package routing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// Cache sizing. Travel times change with traffic and schedules but are good enough
+// for comparing listings for a day. Points are rounded to about 110 meters, so
+// searches from the same office share routes. Routes the provider could not find are
+// cached too; other failures are not.
+const (
+	DefaultCacheTTL        = 24 * time.Hour
+	DefaultCacheMaxEntries = 50000
+	cachePrecision         = 3
+)
+
+// cachedRoute is a route estimate, or the lack of one, with when it was requested
+type cachedRoute struct {
+	estimate *domain.RouteEstimate
+	err      error
+	at       time.Time
+}
+
+// CachedRouter keeps recent routes in memory, so the provider is asked once per
+// origin, destination, mode and TTL
+type CachedRouter struct {
+	router     Router
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedRoute
+}
+
+// NewCachedRouter creates a cache in front of a router
+func NewCachedRouter(router Router, ttl time.Duration, maxEntries int) (*CachedRouter, error) {
+	if router == nil {
+		return nil, fmt.Errorf("router is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &CachedRouter{
+		router:     router,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    map[string]cachedRoute{},
+	}, nil
+}
+
+// Name identifies the cached provider
+func (c *CachedRouter) Name() string {
+	return c.router.Name()
+}
+
+// Route returns the cached route between two points, requesting it when missing or expired
+func (c *CachedRouter) Route(from, to domain.GeoPoint, mode string) (*domain.RouteEstimate, error) {
+	from, to = from.Rounded(cachePrecision), to.Rounded(cachePrecision)
+	key := mode + "|" + from.String() + "|" + to.String()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.at) < c.ttl {
+		return entry.estimate, entry.err
+	}
+
+	estimate, err := c.router.Route(from, to, mode)
+	if err != nil && err != ErrNoRoute && err != ErrModeNotSupported {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cachedRoute{estimate: estimate, err: err, at: now}
+	return estimate, err
+}
+
+// evict drops expired entries, or every entry when none expired
+func (c *CachedRouter) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if now.Sub(entry.at) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = map[string]cachedRoute{}
+	}
+}
@@ -0,0 +1,98 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// OSRMRouter routes with an OSRM server using the car profile. OSRM has no public
+// transport data, so only driving is supported. Coordinates are sent as lng,lat and
+// the server answers with:
+//
+//	{"code": "Ok", "routes": [{"duration": 1260.4, "distance": 14320.8}]}
+type OSRMRouter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewOSRMRouter creates a router for an OSRM server
+func NewOSRMRouter(endpoint string, timeout time.Duration) (*OSRMRouter, error) {
+	if endpoint == "" {
+		endpoint = DefaultOSRMEndpoint
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid OSRM endpoint: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &OSRMRouter{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (r *OSRMRouter) Name() string {
+	return ProviderOSRM
+}
+
+// Route requests the fastest driving route between two points
+func (r *OSRMRouter) Route(from, to domain.GeoPoint, mode string) (*domain.RouteEstimate, error) {
+	if mode != domain.CommuteModeDriving {
+		return nil, ErrModeNotSupported
+	}
+
+	coordinates := osrmCoordinate(from) + ";" + osrmCoordinate(to)
+	req, err := http.NewRequest(http.MethodGet, r.endpoint+"/route/v1/driving/"+coordinates+"?overview=false", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting OSRM: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Routes  []struct {
+			Duration float64 `json:"duration"`
+			Distance float64 `json:"distance"`
+		} `json:"routes"`
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading OSRM response: %w", err)
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("OSRM returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	switch {
+	case body.Code == "NoRoute" || (body.Code == "Ok" && len(body.Routes) == 0):
+		return nil, ErrNoRoute
+	case body.Code != "Ok":
+		return nil, fmt.Errorf("OSRM returned %s: %s", body.Code, body.Message)
+	}
+
+	route := body.Routes[0]
+	return domain.NewRouteEstimate(mode, route.Duration, route.Distance, r.Name()), nil
+}
+
+// osrmCoordinate formats a point the way OSRM expects it, longitude first
+func osrmCoordinate(point domain.GeoPoint) string {
+	return strconv.FormatFloat(point.Lng, 'f', 6, 64) + "," + strconv.FormatFloat(point.Lat, 'f', 6, 64)
+}
@@ -0,0 +1,59 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const defaultHTTPTimeout = 5 * time.Second
+
+// Supported providers
+const (
+	ProviderNone   = "none"
+	ProviderOSRM   = "osrm"
+	ProviderGoogle = "google"
+)
+
+// DefaultOSRMEndpoint is the public OSRM demo server; production deployments should run
+// their own with the Ecuador extract
+const DefaultOSRMEndpoint = "https://router.project-osrm.org"
+
+// ErrModeNotSupported is returned by routers for travel modes they cannot route
+var ErrModeNotSupported = errors.New("travel mode not supported")
+
+// ErrNoRoute is returned when the provider finds no route between two points
+var ErrNoRoute = errors.New("no route found")
+
+// Router estimates travel between two points
+type Router interface {
+	// Name identifies the provider in estimates
+	Name() string
+
+	// Route estimates the travel from one point to another in a commute mode
+	Route(from, to domain.GeoPoint, mode string) (*domain.RouteEstimate, error)
+}
+
+// IsValidProvider verifies if a provider is supported
+func IsValidProvider(provider string) bool {
+	switch provider {
+	case ProviderNone, ProviderOSRM, ProviderGoogle:
+		return true
+	}
+	return false
+}
+
+// NewRouter creates the router of a provider. The none provider has no router.
+func NewRouter(provider, endpoint, apiKey string, timeout time.Duration) (Router, error) {
+	switch provider {
+	case ProviderNone, "":
+		return nil, nil
+	case ProviderOSRM:
+		return NewOSRMRouter(endpoint, timeout)
+	case ProviderGoogle:
+		return NewGoogleRouter(endpoint, apiKey, timeout)
+	}
+	return nil, fmt.Errorf("unsupported routing provider: %s", provider)
+}
@@ -0,0 +1,124 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+var (
+	quitoNorth = domain.GeoPoint{Lat: -0.1807, Lng: -78.4678}
+	cumbaya    = domain.GeoPoint{Lat: -0.2021, Lng: -78.4307}
+)
+
+func TestOSRMRouter_Route(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/route/v1/driving/-78.467800,-0.180700;-78.430700,-0.202100", r.URL.Path)
+		fmt.Fprint(w, `{"code":"Ok","routes":[{"duration":1260.4,"distance":14320.8}]}`)
+	}))
+	defer server.Close()
+
+	router, err := NewOSRMRouter(server.URL, time.Second)
+	require.NoError(t, err)
+
+	estimate, err := router.Route(quitoNorth, cumbaya, domain.CommuteModeDriving)
+	require.NoError(t, err)
+	assert.Equal(t, 21, estimate.DurationMinutes)
+	assert.Equal(t, 14.3, estimate.DistanceKm)
+	assert.Equal(t, ProviderOSRM, estimate.Provider)
+
+	_, err = router.Route(quitoNorth, cumbaya, domain.CommuteModeTransit)
+	assert.Equal(t, ErrModeNotSupported, err)
+}
+
+func TestGoogleRouter_Route(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "key", query.Get("key"))
+		assert.Equal(t, "-0.1807,-78.4678", query.Get("origins"))
+		if query.Get("mode") == domain.CommuteModeTransit {
+			assert.NotEmpty(t, query.Get("departure_time"))
+			fmt.Fprint(w, `{"status":"OK","rows":[{"elements":[{"status":"ZERO_RESULTS"}]}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"OK","rows":[{"elements":[{"status":"OK","duration":{"value":1500},"distance":{"value":15000}}]}]}`)
+	}))
+	defer server.Close()
+
+	_, err := NewGoogleRouter(server.URL, "", time.Second)
+	assert.Error(t, err)
+
+	router, err := NewGoogleRouter(server.URL, "key", time.Second)
+	require.NoError(t, err)
+
+	estimate, err := router.Route(quitoNorth, cumbaya, domain.CommuteModeDriving)
+	require.NoError(t, err)
+	assert.Equal(t, 25, estimate.DurationMinutes)
+
+	_, err = router.Route(quitoNorth, cumbaya, domain.CommuteModeTransit)
+	assert.Equal(t, ErrNoRoute, err)
+}
+
+// countingRouter answers every route with a fixed estimate, counting requests
+type countingRouter struct {
+	requests int
+	err      error
+}
+
+func (r *countingRouter) Name() string { return "counting" }
+
+func (r *countingRouter) Route(from, to domain.GeoPoint, mode string) (*domain.RouteEstimate, error) {
+	r.requests++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return domain.NewRouteEstimate(mode, 600, 5000, r.Name()), nil
+}
+
+func TestCachedRouter(t *testing.T) {
+	router := &countingRouter{}
+	cached, err := NewCachedRouter(router, time.Hour, 10)
+	require.NoError(t, err)
+	now := time.Date(2025, 9, 3, 8, 0, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+
+	_, err = cached.Route(quitoNorth, cumbaya, domain.CommuteModeDriving)
+	require.NoError(t, err)
+	// A destination a few meters away shares the route
+	_, err = cached.Route(quitoNorth, domain.GeoPoint{Lat: -0.20212, Lng: -78.43071}, domain.CommuteModeDriving)
+	require.NoError(t, err)
+	assert.Equal(t, 1, router.requests)
+
+	now = now.Add(2 * time.Hour)
+	_, err = cached.Route(quitoNorth, cumbaya, domain.CommuteModeDriving)
+	require.NoError(t, err)
+	assert.Equal(t, 2, router.requests)
+
+	// Provider failures are not cached
+	router.err = fmt.Errorf("timeout")
+	for i := 0; i < 2; i++ {
+		_, err = cached.Route(cumbaya, quitoNorth, domain.CommuteModeDriving)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 4, router.requests)
+}
+
+func TestNewRouter(t *testing.T) {
+	router, err := NewRouter(ProviderNone, "", "", 0)
+	require.NoError(t, err)
+	assert.Nil(t, router)
+
+	router, err = NewRouter(ProviderOSRM, "", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderOSRM, router.Name())
+
+	_, err = NewRouter("mapquest", "", "", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	guayaquil, err := time.LoadLocation("America/Guayaquil")
+	require.NoError(t, err)
+	// Tuesday
+	from := time.Date(2025, 8, 19, 10, 7, 30, 0, guayaquil)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 8, 19, 10, 15, 0, 0, guayaquil)},
+		{"0 3 * * *", time.Date(2025, 8, 20, 3, 0, 0, 0, guayaquil)},
+		{"@hourly", time.Date(2025, 8, 19, 11, 0, 0, 0, guayaquil)},
+		{"30 8-18/2 * * MON-FRI", time.Date(2025, 8, 19, 10, 30, 0, 0, guayaquil)},
+		{"0 9 * * sat,sun", time.Date(2025, 8, 23, 9, 0, 0, 0, guayaquil)},
+		{"0 0 1 */3 *", time.Date(2025, 10, 1, 0, 0, 0, 0, guayaquil)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, guayaquil)},
+		// Day of month or day of week when both are restricted
+		{"0 12 25 * 5", time.Date(2025, 8, 22, 12, 0, 0, 0, guayaquil)},
+		{"0 0 * * 7", time.Date(2025, 8, 24, 0, 0, 0, 0, guayaquil)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "0 0 * FOO *", "@every 10ms", "@every soon"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,382 @@
+// Package scheduler runs background jobs on cron schedules. Each run takes a lock
+// shared by every instance, so a job runs on one instance at a time, and is recorded
+// in a run history that administrators can inspect.
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	// Embedded zone data, so schedules keep their timezone in minimal containers
+	_ "time/tzdata"
+
+	"realty-core/internal/domain"
+)
+
+// ScheduleOff disables the schedule of a job; it can still be triggered manually
+const ScheduleOff = "off"
+
+// JobFunc runs a job and returns a short summary for the run history
+type JobFunc func() (string, error)
+
+// Locker takes the lock that keeps a job from running on several instances at once
+type Locker interface {
+	// TryLock takes the lock of a job without waiting. ok is false when another
+	// instance holds it; otherwise unlock releases it.
+	TryLock(name string) (unlock func(), ok bool, err error)
+}
+
+// RunStore keeps the run history
+type RunStore interface {
+	// CreateRun records a started run
+	CreateRun(run *domain.JobRun) error
+
+	// FinishRun records the outcome of a run
+	FinishRun(run *domain.JobRun) error
+
+	// ListRuns retrieves the latest runs of a job, or of every job when name is empty
+	ListRuns(name string, limit int) ([]domain.JobRun, error)
+}
+
+// Config configures the scheduler
+type Config struct {
+	Location  *time.Location    // timezone of cron expressions, UTC when nil
+	Schedules map[string]string // overrides the default schedule of jobs by name
+	Instance  string            // identifies this instance in the run history, the hostname by default
+}
+
+// JobStatus describes a registered job
+type JobStatus struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Schedule    string         `json:"schedule"`
+	NextRun     *time.Time     `json:"next_run,omitempty"`
+	Running     bool           `json:"running"`
+	LastRun     *domain.JobRun `json:"last_run,omitempty"` // latest run on this instance
+}
+
+// job is a registered job
+type job struct {
+	name        string
+	description string
+	spec        string
+	schedule    Schedule // nil when the schedule is off
+	run         JobFunc
+	next        time.Time
+	running     bool
+	last        *domain.JobRun
+}
+
+// Scheduler runs registered jobs when their schedule is due
+type Scheduler struct {
+	locker   Locker
+	store    RunStore
+	config   Config
+	logger   *log.Logger
+	now      func() time.Time
+	instance string
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	running sync.WaitGroup
+	wake    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// New creates a scheduler
+func New(locker Locker, store RunStore, config Config, logger *log.Logger) *Scheduler {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	instance := config.Instance
+	if instance == "" {
+		instance, _ = os.Hostname()
+	}
+
+	return &Scheduler{
+		locker:   locker,
+		store:    store,
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+		instance: instance,
+		jobs:     map[string]*job{},
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register adds a job with its default schedule, which Config.Schedules can override.
+// A schedule of "off" only runs the job when triggered.
+func (s *Scheduler) Register(name, spec, description string, run JobFunc) error {
+	if override, ok := s.config.Schedules[name]; ok {
+		spec = override
+	}
+	spec = strings.TrimSpace(spec)
+
+	var schedule Schedule
+	if spec != ScheduleOff {
+		var err error
+		if schedule, err = Parse(spec); err != nil {
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+	j := &job{name: name, description: description, spec: spec, schedule: schedule, run: run}
+	if schedule != nil {
+		j.next = schedule.Next(s.now().In(s.config.Location))
+	}
+	s.jobs[name] = j
+	s.signal()
+	return nil
+}
+
+// Jobs describes the registered jobs, ordered by name
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status := JobStatus{Name: j.name, Description: j.description, Schedule: j.spec, Running: j.running}
+		if !j.next.IsZero() {
+			next := j.next
+			status.NextRun = &next
+		}
+		if j.last != nil {
+			last := *j.last
+			status.LastRun = &last
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
+
+// History returns the latest runs of a job on every instance, or of every job when
+// name is empty
+func (s *Scheduler) History(name string, limit int) ([]domain.JobRun, error) {
+	if name != "" {
+		s.mu.Lock()
+		_, exists := s.jobs[name]
+		s.mu.Unlock()
+		if !exists {
+			return nil, fmt.Errorf("job not found: %s", name)
+		}
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.store.ListRuns(name, limit)
+}
+
+// Trigger starts a job now on behalf of triggeredBy and returns the started run. The
+// job runs in the background; its outcome is recorded in the history.
+func (s *Scheduler) Trigger(name, triggeredBy string) (*domain.JobRun, error) {
+	s.mu.Lock()
+	j, exists := s.jobs[name]
+	s.mu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("job not found: %s", name)
+	}
+
+	run, finish, err := s.begin(j, domain.JobTriggerManual, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+	started := *run
+	go finish()
+	return &started, nil
+}
+
+// RunDue runs the jobs whose schedule is due and waits for them, returning how many
+// were started on this instance
+func (s *Scheduler) RunDue() int {
+	return s.runJobs(s.dueJobs())
+}
+
+// dueJobs returns the jobs whose schedule is due and advances their next run
+func (s *Scheduler) dueJobs() []*job {
+	now := s.now().In(s.config.Location)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := []*job{}
+	for _, j := range s.jobs {
+		if j.schedule != nil && !j.next.After(now) {
+			due = append(due, j)
+			j.next = j.schedule.Next(now)
+		}
+	}
+	return due
+}
+
+// runJobs runs jobs on schedule and waits for them
+func (s *Scheduler) runJobs(jobs []*job) int {
+	var wg sync.WaitGroup
+	started := 0
+	for _, j := range jobs {
+		_, finish, err := s.begin(j, domain.JobTriggerSchedule, "")
+		if err != nil {
+			// Locked by another instance or still running from the previous schedule
+			s.logger.Printf("Job %s skipped: %v", j.name, err)
+			continue
+		}
+		started++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			finish()
+		}()
+	}
+	wg.Wait()
+	return started
+}
+
+// Start runs due jobs until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		for {
+			timer := time.NewTimer(s.untilNext())
+			select {
+			case <-timer.C:
+				go s.runJobs(s.dueJobs())
+			case <-s.wake:
+				timer.Stop()
+			case <-stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	s.logger.Printf("Scheduler started with %d jobs (%s)", len(s.Jobs()), s.config.Location)
+}
+
+// Stop stops scheduling jobs and waits for running ones to finish
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	s.running.Wait()
+}
+
+// begin takes the job's lock and records the start of a run. finish runs the job,
+// records the outcome and releases the lock.
+func (s *Scheduler) begin(j *job, trigger, triggeredBy string) (*domain.JobRun, func(), error) {
+	s.mu.Lock()
+	if j.running {
+		s.mu.Unlock()
+		return nil, nil, fmt.Errorf("job %s is already running", j.name)
+	}
+	j.running = true
+	s.running.Add(1)
+	s.mu.Unlock()
+
+	release := func() {
+		s.mu.Lock()
+		j.running = false
+		s.mu.Unlock()
+		s.running.Done()
+	}
+
+	unlock, ok, err := s.locker.TryLock(j.name)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to lock job %s: %w", j.name, err)
+	}
+	if !ok {
+		release()
+		return nil, nil, fmt.Errorf("job %s is already running on another instance", j.name)
+	}
+
+	run := domain.NewJobRun(j.name, trigger, triggeredBy, s.instance, s.now())
+	if err := s.store.CreateRun(run); err != nil {
+		s.logger.Printf("Error recording run of job %s: %v", j.name, err)
+	}
+
+	finish := func() {
+		defer release()
+		defer unlock()
+
+		summary, err := s.execute(j)
+		run.Finish(summary, err, s.now())
+		if err := s.store.FinishRun(run); err != nil {
+			s.logger.Printf("Error recording run of job %s: %v", j.name, err)
+		}
+		if run.Status == domain.JobRunFailed {
+			s.logger.Printf("Job %s failed after %dms: %s", j.name, run.DurationMs, run.Error)
+		}
+
+		s.mu.Lock()
+		j.last = run
+		s.mu.Unlock()
+	}
+	return run, finish, nil
+}
+
+// execute runs a job, turning a panic into an error
+func (s *Scheduler) execute(j *job) (summary string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return j.run()
+}
+
+// untilNext returns the wait until the next scheduled run, at most a minute so clock
+// changes are picked up
+func (s *Scheduler) untilNext() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := time.Minute
+	now := s.now()
+	for _, j := range s.jobs {
+		if j.schedule == nil {
+			continue
+		}
+		if until := j.next.Sub(now); until < wait {
+			wait = until
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// signal wakes the loop to recompute the next run
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
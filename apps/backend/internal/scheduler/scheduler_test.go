@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryLocker grants each lock to one holder at a time
+type memoryLocker struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	failed bool
+}
+
+func (l *memoryLocker) TryLock(name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failed {
+		return nil, false, errors.New("connection refused")
+	}
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	return func() {
+		l.mu.Lock()
+		delete(l.held, name)
+		l.mu.Unlock()
+	}, true, nil
+}
+
+// memoryRunStore keeps runs in memory
+type memoryRunStore struct {
+	mu   sync.Mutex
+	runs []domain.JobRun
+}
+
+func (s *memoryRunStore) CreateRun(run *domain.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = append(s.runs, *run)
+	return nil
+}
+
+func (s *memoryRunStore) FinishRun(run *domain.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.runs {
+		if s.runs[i].ID == run.ID {
+			s.runs[i] = *run
+		}
+	}
+	return nil
+}
+
+func (s *memoryRunStore) ListRuns(name string, limit int) ([]domain.JobRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := []domain.JobRun{}
+	for i := len(s.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if name == "" || s.runs[i].JobName == name {
+			runs = append(runs, s.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+func newTestScheduler(t *testing.T, config Config) (*Scheduler, *memoryLocker, *memoryRunStore, *time.Time) {
+	locker := &memoryLocker{held: map[string]bool{}}
+	store := &memoryRunStore{}
+	s := New(locker, store, config, log.New(io.Discard, "", 0))
+	now := time.Date(2025, 8, 19, 2, 59, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, locker, store, &now
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	s, locker, store, now := newTestScheduler(t, Config{Schedules: map[string]string{"reports": "off"}})
+
+	runs := 0
+	require.NoError(t, s.Register("cleanup", "0 3 * * *", "Nightly cleanup", func() (string, error) {
+		runs++
+		return "3 rows deleted", nil
+	}))
+	require.NoError(t, s.Register("failing", "0 3 * * *", "Always fails", func() (string, error) {
+		panic("boom")
+	}))
+	require.NoError(t, s.Register("reports", "0 3 * * *", "Disabled by config", func() (string, error) {
+		return "", nil
+	}))
+	assert.ErrorContains(t, s.Register("cleanup", "@daily", "", nil), "already registered")
+	assert.ErrorContains(t, s.Register("broken", "0 25 * * *", "", nil), "hour")
+
+	assert.Equal(t, 0, s.RunDue(), "nothing is due before 03:00")
+
+	*now = now.Add(time.Minute)
+	assert.Equal(t, 2, s.RunDue())
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 0, s.RunDue(), "the next run is tomorrow")
+
+	history, err := s.History("", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	byJob := map[string]domain.JobRun{}
+	for _, run := range history {
+		byJob[run.JobName] = run
+	}
+	assert.Equal(t, domain.JobRunSucceeded, byJob["cleanup"].Status)
+	assert.Equal(t, "3 rows deleted", byJob["cleanup"].Summary)
+	assert.Equal(t, domain.JobRunFailed, byJob["failing"].Status)
+	assert.Contains(t, byJob["failing"].Error, "boom")
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 3)
+	assert.Equal(t, "cleanup", jobs[0].Name)
+	assert.Equal(t, time.Date(2025, 8, 20, 3, 0, 0, 0, time.UTC), *jobs[0].NextRun)
+	assert.Equal(t, domain.JobRunSucceeded, jobs[0].LastRun.Status)
+	assert.Nil(t, jobs[2].NextRun, "jobs with schedule off only run when triggered")
+
+	// Another instance holds the lock
+	locker.held["cleanup"] = true
+	*now = now.Add(24 * time.Hour)
+	assert.Equal(t, 1, s.RunDue())
+	assert.Equal(t, 1, runs)
+	assert.Len(t, store.runs, 3)
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	s, locker, _, _ := newTestScheduler(t, Config{})
+
+	release := make(chan struct{})
+	require.NoError(t, s.Register("sitemap", "off", "Regenerates the sitemap", func() (string, error) {
+		<-release
+		return "sitemap written", nil
+	}))
+
+	run, err := s.Trigger("sitemap", "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobRunRunning, run.Status)
+	assert.Equal(t, domain.JobTriggerManual, run.Trigger)
+	assert.Equal(t, "admin-1", run.TriggeredBy)
+
+	_, err = s.Trigger("sitemap", "admin-1")
+	assert.ErrorContains(t, err, "already running")
+	_, err = s.Trigger("missing", "admin-1")
+	assert.ErrorContains(t, err, "job not found")
+
+	close(release)
+	s.Stop()
+	history, err := s.History("sitemap", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, domain.JobRunSucceeded, history[0].Status)
+
+	locker.failed = true
+	_, err = s.Trigger("sitemap", "admin-1")
+	assert.ErrorContains(t, err, "failed to lock job")
+}
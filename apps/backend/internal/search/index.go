@@ -0,0 +1,134 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// Supported search backends
+const (
+	BackendPostgres    = "postgres"
+	BackendMeilisearch = "meilisearch"
+)
+
+// Index defines the pluggable search backend used by /api/properties/search/*
+type Index interface {
+	// Name returns the backend identifier
+	Name() string
+
+	// IndexProperty adds or replaces a property document in the index
+	IndexProperty(property *domain.Property) error
+
+	// IndexProperties adds or replaces several property documents at once
+	IndexProperties(properties []domain.Property) error
+
+	// DeleteProperty removes a property document from the index
+	DeleteProperty(id string) error
+
+	// Search performs a ranked full-text search
+	Search(query string, limit int) ([]repository.PropertySearchResult, error)
+
+	// SearchPaginated performs a ranked full-text search returning one page and the total count
+	SearchPaginated(query string, pagination *domain.PaginationParams) ([]repository.PropertySearchResult, int, error)
+
+	// AdvancedSearch performs a ranked search combined with structured filters
+	AdvancedSearch(params repository.AdvancedSearchParams) ([]repository.PropertySearchResult, error)
+
+	// AdvancedSearchPaginated performs a filtered ranked search returning one page and the total count
+	AdvancedSearchPaginated(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) ([]repository.PropertySearchResult, int, error)
+}
+
+// DictionaryIndex is implemented by backends that apply synonyms and stop words at index time
+type DictionaryIndex interface {
+	UpdateDictionary(synonyms map[string][]string, stopWords []string) error
+}
+
+// HealthChecker is implemented by backends running outside the database, which can be
+// reachable while their index is missing
+type HealthChecker interface {
+	// Health checks the server is available and the index exists
+	Health() error
+}
+
+// Config holds the settings needed to build a search backend
+type Config struct {
+	Backend           string
+	MeilisearchURL    string
+	MeilisearchAPIKey string
+	IndexName         string
+}
+
+// NewIndex builds the search backend selected in the configuration. A Meilisearch index
+// gets its filterable, sortable and searchable attributes before anything is pushed to
+// it, without which filtered and sorted searches are rejected.
+func NewIndex(cfg Config, repo repository.PropertyRepository) (Index, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", BackendPostgres:
+		return NewPostgresIndex(repo), nil
+	case BackendMeilisearch:
+		index, err := NewMeilisearchIndex(cfg.MeilisearchURL, cfg.MeilisearchAPIKey, cfg.IndexName)
+		if err != nil {
+			return nil, err
+		}
+		if err := index.ConfigureIndex(); err != nil {
+			return nil, fmt.Errorf("error configuring index %s: %w", index.indexName, err)
+		}
+		return index, nil
+	default:
+		return nil, fmt.Errorf("unsupported search backend: %s", cfg.Backend)
+	}
+}
+
+// PostgresIndex implements Index on top of PostgreSQL full-text search.
+// The search_vector column is maintained by a database trigger, so writes are no-ops.
+type PostgresIndex struct {
+	repo repository.PropertyRepository
+}
+
+// NewPostgresIndex creates a new PostgreSQL-backed search index
+func NewPostgresIndex(repo repository.PropertyRepository) *PostgresIndex {
+	return &PostgresIndex{repo: repo}
+}
+
+// Name returns the backend identifier
+func (p *PostgresIndex) Name() string {
+	return BackendPostgres
+}
+
+// IndexProperty is a no-op, the search_vector trigger keeps the index up to date
+func (p *PostgresIndex) IndexProperty(property *domain.Property) error {
+	return nil
+}
+
+// IndexProperties is a no-op, the search_vector trigger keeps the index up to date
+func (p *PostgresIndex) IndexProperties(properties []domain.Property) error {
+	return nil
+}
+
+// DeleteProperty is a no-op, rows are removed with the property
+func (p *PostgresIndex) DeleteProperty(id string) error {
+	return nil
+}
+
+// Search performs a ranked PostgreSQL full-text search
+func (p *PostgresIndex) Search(query string, limit int) ([]repository.PropertySearchResult, error) {
+	return p.repo.SearchPropertiesRanked(query, limit)
+}
+
+// SearchPaginated performs a paginated ranked PostgreSQL full-text search
+func (p *PostgresIndex) SearchPaginated(query string, pagination *domain.PaginationParams) ([]repository.PropertySearchResult, int, error) {
+	return p.repo.SearchPropertiesRankedPaginated(query, pagination)
+}
+
+// AdvancedSearch performs a filtered PostgreSQL full-text search
+func (p *PostgresIndex) AdvancedSearch(params repository.AdvancedSearchParams) ([]repository.PropertySearchResult, error) {
+	return p.repo.AdvancedSearch(params)
+}
+
+// AdvancedSearchPaginated performs a paginated filtered PostgreSQL full-text search
+func (p *PostgresIndex) AdvancedSearchPaginated(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) ([]repository.PropertySearchResult, int, error) {
+	return p.repo.AdvancedSearchPaginated(params, pagination)
+}
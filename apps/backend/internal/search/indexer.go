@@ -0,0 +1,118 @@
+package search
+
+import (
+	"log"
+	"os"
+	"sync"
+
+	"realty-core/internal/domain"
+)
+
+// DefaultQueueSize is the default number of pending index operations
+const DefaultQueueSize = 1000
+
+// indexJob is a pending index operation
+type indexJob struct {
+	property *domain.Property
+	deleteID string
+}
+
+// Indexer keeps the search index in sync with property writes.
+// Operations are queued and applied in the background so writes never wait on the search backend.
+type Indexer struct {
+	index  Index
+	jobs   chan indexJob
+	wg     sync.WaitGroup
+	once   sync.Once
+	logger *log.Logger
+}
+
+// NewIndexer creates a new background indexer for the given index
+func NewIndexer(index Index, queueSize int) *Indexer {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	return &Indexer{
+		index:  index,
+		jobs:   make(chan indexJob, queueSize),
+		logger: log.New(os.Stdout, "[SearchIndexer] ", log.LstdFlags),
+	}
+}
+
+// Index returns the underlying search index
+func (i *Indexer) Index() Index {
+	return i.index
+}
+
+// Start launches the background worker
+func (i *Indexer) Start() {
+	i.wg.Add(1)
+	go i.run()
+}
+
+// Stop drains pending operations and stops the worker
+func (i *Indexer) Stop() {
+	i.once.Do(func() {
+		close(i.jobs)
+	})
+	i.wg.Wait()
+}
+
+// EnqueueIndex schedules a property to be (re)indexed
+func (i *Indexer) EnqueueIndex(property *domain.Property) {
+	if property == nil {
+		return
+	}
+	// Copy so later mutations by the caller don't leak into the queued document
+	snapshot := *property
+	i.enqueue(indexJob{property: &snapshot})
+}
+
+// EnqueueDelete schedules a property to be removed from the index
+func (i *Indexer) EnqueueDelete(id string) {
+	if id == "" {
+		return
+	}
+	i.enqueue(indexJob{deleteID: id})
+}
+
+// Reindex synchronously pushes all given properties to the index
+func (i *Indexer) Reindex(properties []domain.Property) error {
+	return i.index.IndexProperties(properties)
+}
+
+// enqueue adds a job without blocking the caller when the queue is full
+func (i *Indexer) enqueue(job indexJob) {
+	select {
+	case i.jobs <- job:
+	default:
+		i.logger.Printf("Warning: index queue full, dropping operation (property=%s delete=%s)", jobPropertyID(job), job.deleteID)
+	}
+}
+
+// run processes queued jobs until the queue is closed
+func (i *Indexer) run() {
+	defer i.wg.Done()
+
+	for job := range i.jobs {
+		var err error
+		if job.deleteID != "" {
+			err = i.index.DeleteProperty(job.deleteID)
+		} else {
+			err = i.index.IndexProperty(job.property)
+		}
+
+		if err != nil {
+			i.logger.Printf("Error applying index operation on %s backend: %v", i.index.Name(), err)
+		}
+	}
+}
+
+// jobPropertyID returns the property ID of an index job, if any
+func jobPropertyID(job indexJob) string {
+	if job.property == nil {
+		return ""
+	}
+	return job.property.ID
+}
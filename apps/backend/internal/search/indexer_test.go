@@ -0,0 +1,68 @@
+package search
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// recordingIndex records write operations for assertions
+type recordingIndex struct {
+	PostgresIndex
+	mu      sync.Mutex
+	indexed []string
+	deleted []string
+}
+
+func (r *recordingIndex) IndexProperty(property *domain.Property) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indexed = append(r.indexed, property.ID)
+	return nil
+}
+
+func (r *recordingIndex) DeleteProperty(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *recordingIndex) Search(query string, limit int) ([]repository.PropertySearchResult, error) {
+	return nil, nil
+}
+
+func TestIndexer_AppliesQueuedOperations(t *testing.T) {
+	index := &recordingIndex{}
+	indexer := NewIndexer(index, 10)
+	indexer.Start()
+
+	property := domain.NewProperty("Casa", "Casa amplia", "Guayas", "Guayaquil", "house", 200000, "owner-1")
+	indexer.EnqueueIndex(property)
+	indexer.EnqueueDelete("old-id")
+	indexer.EnqueueIndex(nil)
+	indexer.EnqueueDelete("")
+	indexer.Stop()
+
+	assert.Equal(t, []string{property.ID}, index.indexed)
+	assert.Equal(t, []string{"old-id"}, index.deleted)
+	assert.Same(t, Index(index), indexer.Index())
+}
+
+func TestIndexer_DropsWhenQueueFull(t *testing.T) {
+	index := &recordingIndex{}
+	indexer := NewIndexer(index, 1)
+
+	// Worker not started: the second operation must not block
+	indexer.EnqueueDelete("first")
+	indexer.EnqueueDelete("second")
+
+	indexer.Start()
+	indexer.Stop()
+
+	assert.Equal(t, []string{"first"}, index.deleted)
+}
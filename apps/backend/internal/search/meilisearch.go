@@ -0,0 +1,323 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// Default settings for the Meilisearch backend
+const (
+	DefaultIndexName   = "properties"
+	defaultHTTPTimeout = 5 * time.Second
+)
+
+// filterableAttributes are the document fields used by AdvancedSearch filters
+var filterableAttributes = []string{
+	"province", "city", "type", "status", "price", "bedrooms", "bathrooms", "area_m2", "featured",
+}
+
+// sortableAttributes are the document fields accepted by pagination sort_by
+var sortableAttributes = []string{
+	"price", "area_m2", "bedrooms", "bathrooms", "view_count",
+}
+
+// MeilisearchIndex implements Index using a Meilisearch server over its HTTP API
+type MeilisearchIndex struct {
+	baseURL   string
+	apiKey    string
+	indexName string
+	client    *http.Client
+}
+
+// NewMeilisearchIndex creates a new Meilisearch-backed search index
+func NewMeilisearchIndex(baseURL, apiKey, indexName string) (*MeilisearchIndex, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("meilisearch URL is required")
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid meilisearch URL: %w", err)
+	}
+	if indexName == "" {
+		indexName = DefaultIndexName
+	}
+
+	return &MeilisearchIndex{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		apiKey:    apiKey,
+		indexName: indexName,
+		client:    &http.Client{Timeout: defaultHTTPTimeout},
+	}, nil
+}
+
+// Name returns the backend identifier
+func (m *MeilisearchIndex) Name() string {
+	return BackendMeilisearch
+}
+
+// Health checks the server is available and the index exists, so an instance pointed
+// at an empty or unreachable Meilisearch is not taken for healthy
+func (m *MeilisearchIndex) Health() error {
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := m.do(http.MethodGet, "/health", nil, &health); err != nil {
+		return err
+	}
+	if health.Status != "available" {
+		return fmt.Errorf("meilisearch is %s", health.Status)
+	}
+	if err := m.do(http.MethodGet, m.indexPath(""), nil, nil); err != nil {
+		return fmt.Errorf("error reading index %s: %w", m.indexName, err)
+	}
+	return nil
+}
+
+// ConfigureIndex applies the filterable and sortable attribute settings to the index
+func (m *MeilisearchIndex) ConfigureIndex() error {
+	settings := map[string]interface{}{
+		"filterableAttributes": filterableAttributes,
+		"sortableAttributes":   sortableAttributes,
+		"searchableAttributes": []string{"title", "description", "sector", "city", "province", "tags", "type"},
+	}
+	return m.do(http.MethodPatch, m.indexPath("/settings"), settings, nil)
+}
+
+// UpdateDictionary replaces the index synonym and stop-word settings
+func (m *MeilisearchIndex) UpdateDictionary(synonyms map[string][]string, stopWords []string) error {
+	if err := m.do(http.MethodPut, m.indexPath("/settings/synonyms"), synonyms, nil); err != nil {
+		return fmt.Errorf("error updating synonyms: %w", err)
+	}
+	if err := m.do(http.MethodPut, m.indexPath("/settings/stop-words"), stopWords, nil); err != nil {
+		return fmt.Errorf("error updating stop words: %w", err)
+	}
+	return nil
+}
+
+// IndexProperty adds or replaces a property document in the index
+func (m *MeilisearchIndex) IndexProperty(property *domain.Property) error {
+	if property == nil {
+		return fmt.Errorf("property cannot be nil")
+	}
+	return m.IndexProperties([]domain.Property{*property})
+}
+
+// IndexProperties adds or replaces several property documents at once
+func (m *MeilisearchIndex) IndexProperties(properties []domain.Property) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	if err := m.do(http.MethodPost, m.indexPath("/documents?primaryKey=id"), properties, nil); err != nil {
+		return fmt.Errorf("error indexing properties: %w", err)
+	}
+	return nil
+}
+
+// DeleteProperty removes a property document from the index
+func (m *MeilisearchIndex) DeleteProperty(id string) error {
+	if id == "" {
+		return fmt.Errorf("property ID required")
+	}
+	if err := m.do(http.MethodDelete, m.indexPath("/documents/"+url.PathEscape(id)), nil, nil); err != nil {
+		return fmt.Errorf("error deleting property from index: %w", err)
+	}
+	return nil
+}
+
+// Search performs a ranked full-text search with typo tolerance
+func (m *MeilisearchIndex) Search(query string, limit int) ([]repository.PropertySearchResult, error) {
+	results, _, err := m.search(meiliSearchRequest{Query: query, Limit: limit})
+	return results, err
+}
+
+// SearchPaginated performs a ranked full-text search returning one page and the total count
+func (m *MeilisearchIndex) SearchPaginated(query string, pagination *domain.PaginationParams) ([]repository.PropertySearchResult, int, error) {
+	request := meiliSearchRequest{
+		Query:  query,
+		Limit:  pagination.GetLimit(),
+		Offset: pagination.GetOffset(),
+		Sort:   sortFromPagination(pagination),
+	}
+	return m.search(request)
+}
+
+// AdvancedSearch performs a ranked search combined with structured filters
+func (m *MeilisearchIndex) AdvancedSearch(params repository.AdvancedSearchParams) ([]repository.PropertySearchResult, error) {
+	request := meiliSearchRequest{
+		Query:  params.Query,
+		Limit:  params.Limit,
+		Filter: buildMeiliFilter(params),
+	}
+	results, _, err := m.search(request)
+	return results, err
+}
+
+// AdvancedSearchPaginated performs a filtered ranked search returning one page and the total count
+func (m *MeilisearchIndex) AdvancedSearchPaginated(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) ([]repository.PropertySearchResult, int, error) {
+	request := meiliSearchRequest{
+		Query:  params.Query,
+		Limit:  pagination.GetLimit(),
+		Offset: pagination.GetOffset(),
+		Filter: buildMeiliFilter(params),
+		Sort:   sortFromPagination(pagination),
+	}
+	return m.search(request)
+}
+
+// meiliSearchRequest is the body of POST /indexes/{index}/search
+type meiliSearchRequest struct {
+	Query            string   `json:"q"`
+	Limit            int      `json:"limit,omitempty"`
+	Offset           int      `json:"offset,omitempty"`
+	Filter           []string `json:"filter,omitempty"`
+	Sort             []string `json:"sort,omitempty"`
+	ShowRankingScore bool     `json:"showRankingScore"`
+}
+
+// meiliSearchResponse is the subset of the search response used here
+type meiliSearchResponse struct {
+	Hits               []meiliHit `json:"hits"`
+	EstimatedTotalHits int        `json:"estimatedTotalHits"`
+}
+
+// meiliHit is a property document with its ranking score
+type meiliHit struct {
+	domain.Property
+	RankingScore float64 `json:"_rankingScore"`
+}
+
+// search runs a search request and converts hits to search results
+func (m *MeilisearchIndex) search(request meiliSearchRequest) ([]repository.PropertySearchResult, int, error) {
+	request.ShowRankingScore = true
+
+	var response meiliSearchResponse
+	if err := m.do(http.MethodPost, m.indexPath("/search"), request, &response); err != nil {
+		return nil, 0, fmt.Errorf("error performing meilisearch query: %w", err)
+	}
+
+	results := make([]repository.PropertySearchResult, 0, len(response.Hits))
+	for _, hit := range response.Hits {
+		results = append(results, repository.PropertySearchResult{
+			Property: hit.Property,
+			Rank:     hit.RankingScore,
+		})
+	}
+
+	return results, response.EstimatedTotalHits, nil
+}
+
+// buildMeiliFilter converts advanced search parameters into Meilisearch filter expressions
+func buildMeiliFilter(params repository.AdvancedSearchParams) []string {
+	var filters []string
+
+	if params.Province != "" {
+		filters = append(filters, "province = "+strconv.Quote(params.Province))
+	}
+	if params.City != "" {
+		filters = append(filters, "city = "+strconv.Quote(params.City))
+	}
+	if params.Type != "" {
+		filters = append(filters, "type = "+strconv.Quote(params.Type))
+	}
+	if params.MinPrice > 0 {
+		filters = append(filters, fmt.Sprintf("price >= %v", params.MinPrice))
+	}
+	if params.MaxPrice > 0 {
+		filters = append(filters, fmt.Sprintf("price <= %v", params.MaxPrice))
+	}
+	if params.MinBedrooms > 0 {
+		filters = append(filters, fmt.Sprintf("bedrooms >= %d", params.MinBedrooms))
+	}
+	if params.MaxBedrooms > 0 {
+		filters = append(filters, fmt.Sprintf("bedrooms <= %d", params.MaxBedrooms))
+	}
+	if params.MinBathrooms > 0 {
+		filters = append(filters, fmt.Sprintf("bathrooms >= %v", params.MinBathrooms))
+	}
+	if params.MaxBathrooms > 0 {
+		filters = append(filters, fmt.Sprintf("bathrooms <= %v", params.MaxBathrooms))
+	}
+	if params.MinArea > 0 {
+		filters = append(filters, fmt.Sprintf("area_m2 >= %v", params.MinArea))
+	}
+	if params.MaxArea > 0 {
+		filters = append(filters, fmt.Sprintf("area_m2 <= %v", params.MaxArea))
+	}
+	if params.FeaturedOnly {
+		filters = append(filters, "featured = true")
+	}
+
+	return filters
+}
+
+// sortFromPagination maps pagination sorting to a Meilisearch sort rule.
+// Fields that are not sortable in the index fall back to relevance ordering.
+func sortFromPagination(pagination *domain.PaginationParams) []string {
+	if pagination == nil {
+		return nil
+	}
+	for _, attribute := range sortableAttributes {
+		if attribute == pagination.SortBy {
+			order := "asc"
+			if pagination.SortDesc {
+				order = "desc"
+			}
+			return []string{attribute + ":" + order}
+		}
+	}
+	return nil
+}
+
+// indexPath returns the API path for the configured index
+func (m *MeilisearchIndex) indexPath(suffix string) string {
+	return "/indexes/" + url.PathEscape(m.indexName) + suffix
+}
+
+// do sends a JSON request to the Meilisearch API and decodes the response into out
+func (m *MeilisearchIndex) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, m.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting meilisearch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("meilisearch returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("error decoding response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package search
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+func TestNewMeilisearchIndex(t *testing.T) {
+	_, err := NewMeilisearchIndex("", "", "")
+	assert.Error(t, err)
+
+	index, err := NewMeilisearchIndex("http://localhost:7700/", "key", "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:7700", index.baseURL)
+	assert.Equal(t, DefaultIndexName, index.indexName)
+	assert.Equal(t, BackendMeilisearch, index.Name())
+}
+
+func TestNewIndex(t *testing.T) {
+	index, err := NewIndex(Config{Backend: "postgres"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, BackendPostgres, index.Name())
+
+	var settings map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/indexes/properties/settings", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&settings))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	index, err = NewIndex(Config{Backend: "meilisearch", MeilisearchURL: server.URL}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, BackendMeilisearch, index.Name())
+	assert.Equal(t, filterableAttributes, settings["filterableAttributes"], "the index is configured when it is set up")
+	assert.Equal(t, sortableAttributes, settings["sortableAttributes"])
+
+	_, err = NewIndex(Config{Backend: "meilisearch", MeilisearchURL: "http://127.0.0.1:1"}, nil)
+	assert.ErrorContains(t, err, "error configuring index properties")
+
+	_, err = NewIndex(Config{Backend: "solr"}, nil)
+	assert.Error(t, err)
+}
+
+func TestMeilisearchIndex_AdvancedSearch(t *testing.T) {
+	var received meiliSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/indexes/properties/search", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hits":[{"id":"p1","title":"Departamento en Cumbayá","province":"Pichincha","_rankingScore":0.92}],"estimatedTotalHits":1}`))
+	}))
+	defer server.Close()
+
+	index, err := NewMeilisearchIndex(server.URL, "secret", "properties")
+	require.NoError(t, err)
+
+	results, err := index.AdvancedSearch(repository.AdvancedSearchParams{
+		Query:        "departamento",
+		Province:     "Pichincha",
+		MinBedrooms:  2,
+		FeaturedOnly: true,
+		Limit:        10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "p1", results[0].Property.ID)
+	assert.Equal(t, 0.92, results[0].Rank)
+	assert.Equal(t, "departamento", received.Query)
+	assert.True(t, received.ShowRankingScore)
+	assert.Equal(t, []string{`province = "Pichincha"`, "bedrooms >= 2", "featured = true"}, received.Filter)
+}
+
+func TestMeilisearchIndex_SearchPaginated(t *testing.T) {
+	var received meiliSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Write([]byte(`{"hits":[],"estimatedTotalHits":42}`))
+	}))
+	defer server.Close()
+
+	index, err := NewMeilisearchIndex(server.URL, "", "properties")
+	require.NoError(t, err)
+
+	pagination := &domain.PaginationParams{Page: 3, PageSize: 10, SortBy: "price", SortDesc: true}
+	results, total, err := index.SearchPaginated("casa", pagination)
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, 42, total)
+	assert.Equal(t, 10, received.Limit)
+	assert.Equal(t, 20, received.Offset)
+	assert.Equal(t, []string{"price:desc"}, received.Sort)
+}
+
+func TestMeilisearchIndex_WriteOperations(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.RequestURI())
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"taskUid":1}`))
+	}))
+	defer server.Close()
+
+	index, err := NewMeilisearchIndex(server.URL, "", "properties")
+	require.NoError(t, err)
+
+	property := domain.NewProperty("Casa", "Casa con jardín", "Azuay", "Cuenca", "house", 150000, "owner-1")
+	require.NoError(t, index.IndexProperty(property))
+	require.NoError(t, index.DeleteProperty(property.ID))
+
+	assert.Equal(t, []string{
+		"POST /indexes/properties/documents?primaryKey=id",
+		"DELETE /indexes/properties/documents/" + property.ID,
+	}, paths)
+}
+
+func TestMeilisearchIndex_UpdateDictionary(t *testing.T) {
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		bodies[r.URL.Path] = string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	index, err := NewMeilisearchIndex(server.URL, "", "properties")
+	require.NoError(t, err)
+
+	var _ DictionaryIndex = index
+	err = index.UpdateDictionary(map[string][]string{"depa": {"departamento"}}, []string{"de"})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"depa":["departamento"]}`, bodies["/indexes/properties/settings/synonyms"])
+	assert.JSONEq(t, `["de"]`, bodies["/indexes/properties/settings/stop-words"])
+}
+
+func TestMeilisearchIndex_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"Attribute price is not filterable"}`))
+	}))
+	defer server.Close()
+
+	index, err := NewMeilisearchIndex(server.URL, "", "properties")
+	require.NoError(t, err)
+
+	_, err = index.Search("casa", 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400")
+	assert.Contains(t, err.Error(), "not filterable")
+}
+
+func TestMeilisearchIndex_Health(t *testing.T) {
+	indexExists := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/health":
+			w.Write([]byte(`{"status":"available"}`))
+		case r.URL.Path == "/indexes/properties" && indexExists:
+			w.Write([]byte(`{"uid":"properties","primaryKey":"id"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Index properties not found","code":"index_not_found"}`))
+		}
+	}))
+	defer server.Close()
+
+	index, err := NewMeilisearchIndex(server.URL, "", "properties")
+	require.NoError(t, err)
+
+	var _ HealthChecker = index
+	err = index.Health()
+	require.Error(t, err, "a reachable server without the index is not healthy")
+	assert.Contains(t, err.Error(), "index_not_found")
+
+	indexExists = true
+	assert.NoError(t, index.Health())
+}
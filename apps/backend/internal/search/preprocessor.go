@@ -0,0 +1,115 @@
+package search
+
+import (
+	"strings"
+	"sync"
+
+	"realty-core/internal/domain"
+)
+
+// QueryPreprocessor rewrites search queries using the synonym and stop-word dictionary.
+// It is safe for concurrent use and can be reloaded while serving requests.
+type QueryPreprocessor struct {
+	mu        sync.RWMutex
+	synonyms  map[string]string
+	stopWords map[string]bool
+}
+
+// NewQueryPreprocessor creates an empty query preprocessor
+func NewQueryPreprocessor() *QueryPreprocessor {
+	return &QueryPreprocessor{
+		synonyms:  make(map[string]string),
+		stopWords: make(map[string]bool),
+	}
+}
+
+// Load replaces the dictionary used for preprocessing
+func (p *QueryPreprocessor) Load(synonyms []domain.SearchSynonym, stopWords []domain.SearchStopWord) {
+	synonymMap := make(map[string]string, len(synonyms))
+	for _, synonym := range synonyms {
+		synonymMap[synonym.Term] = synonym.Canonical
+	}
+
+	stopWordMap := make(map[string]bool, len(stopWords))
+	for _, stopWord := range stopWords {
+		stopWordMap[stopWord.Word] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.synonyms = synonymMap
+	p.stopWords = stopWordMap
+}
+
+// Process normalizes the query, drops stop words and replaces synonyms with their canonical terms.
+// Multi-word synonyms are matched before single words. If every word is a stop word the
+// normalized query is returned unchanged so searches never become empty.
+func (p *QueryPreprocessor) Process(query string) string {
+	normalized := domain.NormalizeSearchTerm(query)
+	if normalized == "" {
+		return normalized
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	words := strings.Fields(normalized)
+	result := make([]string, 0, len(words))
+
+	for i := 0; i < len(words); {
+		if canonical, length := p.matchPhrase(words[i:]); length > 0 {
+			result = append(result, canonical)
+			i += length
+			continue
+		}
+
+		word := words[i]
+		i++
+		if p.stopWords[word] {
+			continue
+		}
+		result = append(result, word)
+	}
+
+	if len(result) == 0 {
+		return normalized
+	}
+
+	return strings.Join(result, " ")
+}
+
+// Synonyms returns the one-way synonym mapping (term -> canonical terms) used for index-time settings
+func (p *QueryPreprocessor) Synonyms() map[string][]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	synonyms := make(map[string][]string, len(p.synonyms))
+	for term, canonical := range p.synonyms {
+		synonyms[term] = []string{canonical}
+	}
+	return synonyms
+}
+
+// StopWords returns the configured stop words
+func (p *QueryPreprocessor) StopWords() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	words := make([]string, 0, len(p.stopWords))
+	for word := range p.stopWords {
+		words = append(words, word)
+	}
+	return words
+}
+
+// matchPhrase finds the longest synonym term starting at the first word.
+// Callers must hold the read lock.
+func (p *QueryPreprocessor) matchPhrase(words []string) (string, int) {
+	for length := len(words); length > 0; length-- {
+		phrase := strings.Join(words[:length], " ")
+		if canonical, ok := p.synonyms[phrase]; ok {
+			return canonical, length
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+func newTestPreprocessor() *QueryPreprocessor {
+	p := NewQueryPreprocessor()
+	p.Load(
+		[]domain.SearchSynonym{
+			{Term: "depa", Canonical: "departamento"},
+			{Term: "suit", Canonical: "suite"},
+			{Term: "urbanización", Canonical: "urbanizacion"},
+			{Term: "casa rentera", Canonical: "casa comercial"},
+		},
+		[]domain.SearchStopWord{
+			{Word: "de"},
+			{Word: "en"},
+		},
+	)
+	return p
+}
+
+func TestQueryPreprocessor_Process(t *testing.T) {
+	p := newTestPreprocessor()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"replaces synonym", "depa Samborondón", "departamento samborondón"},
+		{"drops stop words", "suit en Cumbayá", "suite cumbayá"},
+		{"accented synonym", "Urbanización privada", "urbanizacion privada"},
+		{"multi-word synonym", "casa rentera de 3 pisos", "casa comercial 3 pisos"},
+		{"collapses whitespace", "  casa   moderna ", "casa moderna"},
+		{"only stop words keeps query", "de en", "de en"},
+		{"empty query", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.Process(tt.query))
+		})
+	}
+}
+
+func TestQueryPreprocessor_EmptyDictionary(t *testing.T) {
+	p := NewQueryPreprocessor()
+	assert.Equal(t, "depa en quito", p.Process("Depa en Quito"))
+}
+
+func TestQueryPreprocessor_Exports(t *testing.T) {
+	p := newTestPreprocessor()
+
+	synonyms := p.Synonyms()
+	assert.Equal(t, []string{"departamento"}, synonyms["depa"])
+	assert.Len(t, synonyms, 4)
+
+	stopWords := p.StopWords()
+	sort.Strings(stopWords)
+	assert.Equal(t, []string{"de", "en"}, stopWords)
+}
@@ -0,0 +1,393 @@
+package search
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"realty-core/internal/cache"
+	"realty-core/internal/repository"
+)
+
+// SuggestionSource provides the catalogs blended into autocomplete suggestions
+type SuggestionSource interface {
+	RecordQuery(query string) error
+	GetPopularQueries(since time.Time, minCount, limit int) ([]repository.SearchSuggestion, error)
+	GetLocationCatalog() ([]repository.SearchSuggestion, error)
+	GetAgencyNames() ([]repository.SearchSuggestion, error)
+}
+
+// SuggestionConfig configures the suggestion engine
+type SuggestionConfig struct {
+	// Weights per suggestion category; categories not listed use DefaultWeight
+	Weights       map[string]float64
+	DefaultWeight float64
+	// PopularWindow is how far back popular queries are considered
+	PopularWindow time.Duration
+	PopularLimit  int
+	// PopularMinCount is how many times a query must have been searched to be suggested
+	PopularMinCount int
+	// CatalogTTL controls how often popular queries, locations and agencies are reloaded
+	CatalogTTL time.Duration
+	// CacheTTL and CacheCapacity configure the per-query suggestion cache
+	CacheTTL      time.Duration
+	CacheCapacity int
+	Logger        *log.Logger
+}
+
+// DefaultSuggestionConfig returns the default weighting and cache settings
+func DefaultSuggestionConfig() SuggestionConfig {
+	return SuggestionConfig{
+		Weights: map[string]float64{
+			repository.SuggestionCategoryPopular:  1.0,
+			repository.SuggestionCategoryCity:     0.9,
+			repository.SuggestionCategoryProvince: 0.85,
+			repository.SuggestionCategoryAgency:   0.7,
+		},
+		DefaultWeight:   0.8,
+		PopularWindow:   30 * 24 * time.Hour,
+		PopularLimit:    500,
+		PopularMinCount: 5,
+		CatalogTTL:      10 * time.Minute,
+		CacheTTL:        2 * time.Minute,
+		CacheCapacity:   1000,
+	}
+}
+
+// minRecordedQueryLength skips recording very short, incomplete queries
+const minRecordedQueryLength = 3
+
+// SuggestionEngine blends database suggestions with popular queries, the location
+// catalog and agency names using prefix and fuzzy matching with per-category weights
+type SuggestionEngine struct {
+	source   SuggestionSource
+	config   SuggestionConfig
+	cache    cache.CacheInterface
+	mu       sync.RWMutex
+	catalog  []repository.SearchSuggestion
+	loadedAt time.Time
+
+	tenantSources func(tenantID string) SuggestionSource
+	tenantsMu     sync.Mutex
+	tenants       map[string]*SuggestionEngine
+}
+
+// NewSuggestionEngine creates a new suggestion engine
+func NewSuggestionEngine(source SuggestionSource, config SuggestionConfig) *SuggestionEngine {
+	defaults := DefaultSuggestionConfig()
+	if config.Weights == nil {
+		config.Weights = defaults.Weights
+	}
+	if config.DefaultWeight <= 0 {
+		config.DefaultWeight = defaults.DefaultWeight
+	}
+	if config.PopularWindow <= 0 {
+		config.PopularWindow = defaults.PopularWindow
+	}
+	if config.PopularLimit <= 0 {
+		config.PopularLimit = defaults.PopularLimit
+	}
+	if config.PopularMinCount <= 0 {
+		config.PopularMinCount = defaults.PopularMinCount
+	}
+	if config.CatalogTTL <= 0 {
+		config.CatalogTTL = defaults.CatalogTTL
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+	if config.CacheCapacity <= 0 {
+		config.CacheCapacity = defaults.CacheCapacity
+	}
+
+	return &SuggestionEngine{
+		source: source,
+		config: config,
+		cache:  cache.NewLRUCache(config.CacheCapacity, 5*1024*1024, config.CacheTTL),
+	}
+}
+
+// SetTenantSources gives each tenant its own popular queries and catalogs, read from the
+// source returned for the tenant. Without it ForTenant returns nil.
+func (e *SuggestionEngine) SetTenantSources(sources func(tenantID string) SuggestionSource) {
+	e.tenantsMu.Lock()
+	defer e.tenantsMu.Unlock()
+	e.tenantSources = sources
+	e.tenants = map[string]*SuggestionEngine{}
+}
+
+// ForTenant returns the engine of a tenant, which records and suggests the queries of
+// the tenant only and caches its catalog apart. An empty tenant returns the engine itself.
+func (e *SuggestionEngine) ForTenant(tenantID string) *SuggestionEngine {
+	if tenantID == "" {
+		return e
+	}
+
+	e.tenantsMu.Lock()
+	defer e.tenantsMu.Unlock()
+	if e.tenantSources == nil {
+		return nil
+	}
+	engine, ok := e.tenants[tenantID]
+	if !ok {
+		engine = NewSuggestionEngine(e.tenantSources(tenantID), e.config)
+		e.tenants[tenantID] = engine
+	}
+	return engine
+}
+
+// Cached returns previously blended suggestions for a query
+func (e *SuggestionEngine) Cached(query string, limit int) ([]repository.SearchSuggestion, bool) {
+	value, found := e.cache.Get(suggestionCacheKey(query, limit))
+	if !found {
+		return nil, false
+	}
+	suggestions, ok := value.([]repository.SearchSuggestion)
+	return suggestions, ok
+}
+
+// Blend merges the base suggestions with matching catalog entries, ranks them and caches the result
+func (e *SuggestionEngine) Blend(query string, base []repository.SearchSuggestion, limit int) []repository.SearchSuggestion {
+	candidates := append([]repository.SearchSuggestion{}, base...)
+	candidates = append(candidates, e.loadCatalog()...)
+
+	suggestions := e.rank(query, candidates, limit)
+
+	size := int64(0)
+	for _, suggestion := range suggestions {
+		size += int64(len(suggestion.Text)+len(suggestion.Category)) + 8
+	}
+	e.cache.Set(suggestionCacheKey(query, limit), suggestions, size)
+
+	return suggestions
+}
+
+// RecordQuery counts a search query towards the popular queries catalog
+func (e *SuggestionEngine) RecordQuery(query string) {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if utf8.RuneCountInString(query) < minRecordedQueryLength {
+		return
+	}
+	if err := e.source.RecordQuery(query); err != nil {
+		e.logf("Error recording search query %q: %v", query, err)
+	}
+}
+
+// Invalidate drops the cached catalogs and suggestions, of every tenant, so they are
+// rebuilt on next use
+func (e *SuggestionEngine) Invalidate() {
+	e.mu.Lock()
+	e.catalog = nil
+	e.loadedAt = time.Time{}
+	e.mu.Unlock()
+	e.cache.Clear()
+
+	e.tenantsMu.Lock()
+	defer e.tenantsMu.Unlock()
+	for _, engine := range e.tenants {
+		engine.Invalidate()
+	}
+}
+
+// loadCatalog returns the blended catalog, reloading it when the TTL has expired.
+// Sources that fail are skipped so suggestions keep working with partial data.
+func (e *SuggestionEngine) loadCatalog() []repository.SearchSuggestion {
+	e.mu.RLock()
+	if e.catalog != nil && time.Since(e.loadedAt) < e.config.CatalogTTL {
+		catalog := e.catalog
+		e.mu.RUnlock()
+		return catalog
+	}
+	e.mu.RUnlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.catalog != nil && time.Since(e.loadedAt) < e.config.CatalogTTL {
+		return e.catalog
+	}
+
+	catalog := []repository.SearchSuggestion{}
+	if popular, err := e.source.GetPopularQueries(time.Now().Add(-e.config.PopularWindow), e.config.PopularMinCount, e.config.PopularLimit); err != nil {
+		e.logf("Error loading popular queries: %v", err)
+	} else {
+		catalog = append(catalog, popular...)
+	}
+	if locations, err := e.source.GetLocationCatalog(); err != nil {
+		e.logf("Error loading location catalog: %v", err)
+	} else {
+		catalog = append(catalog, locations...)
+	}
+	if agencies, err := e.source.GetAgencyNames(); err != nil {
+		e.logf("Error loading agency names: %v", err)
+	} else {
+		catalog = append(catalog, agencies...)
+	}
+
+	e.catalog = catalog
+	e.loadedAt = time.Now()
+	return catalog
+}
+
+// scoredSuggestion is a candidate with its blended score
+type scoredSuggestion struct {
+	suggestion repository.SearchSuggestion
+	score      float64
+}
+
+// rank scores candidates against the query, removes duplicates and returns the best ones
+func (e *SuggestionEngine) rank(query string, candidates []repository.SearchSuggestion, limit int) []repository.SearchSuggestion {
+	normalizedQuery := normalizeForMatch(query)
+	best := make(map[string]scoredSuggestion)
+
+	for _, candidate := range candidates {
+		match := matchScore(normalizedQuery, normalizeForMatch(candidate.Text))
+		if match == 0 {
+			continue
+		}
+
+		score := match * e.weight(candidate.Category) * popularityBoost(candidate.Frequency)
+		key := normalizeForMatch(candidate.Text)
+		if existing, ok := best[key]; !ok || score > existing.score {
+			best[key] = scoredSuggestion{suggestion: candidate, score: score}
+		}
+	}
+
+	scored := make([]scoredSuggestion, 0, len(best))
+	for _, entry := range best {
+		scored = append(scored, entry)
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].suggestion.Text < scored[j].suggestion.Text
+	})
+
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	suggestions := make([]repository.SearchSuggestion, 0, len(scored))
+	for _, entry := range scored {
+		suggestions = append(suggestions, entry.suggestion)
+	}
+	return suggestions
+}
+
+// weight returns the configured weight for a category
+func (e *SuggestionEngine) weight(category string) float64 {
+	if weight, ok := e.config.Weights[category]; ok {
+		return weight
+	}
+	return e.config.DefaultWeight
+}
+
+func (e *SuggestionEngine) logf(format string, args ...interface{}) {
+	if e.config.Logger != nil {
+		e.config.Logger.Printf(format, args...)
+	}
+}
+
+// matchScore rates how well a normalized text matches a normalized query:
+// 1.0 for a prefix of the whole text, 0.8 for a prefix of any word and
+// up to 0.5 for a fuzzy prefix match within the allowed edit distance.
+func matchScore(query, text string) float64 {
+	if query == "" || text == "" {
+		return 0
+	}
+	if strings.HasPrefix(text, query) {
+		return 1.0
+	}
+
+	words := strings.Fields(text)
+	for _, word := range words {
+		if strings.HasPrefix(word, query) {
+			return 0.8
+		}
+	}
+
+	allowed := allowedTypos(query)
+	if allowed == 0 {
+		return 0
+	}
+
+	queryRunes := []rune(query)
+	bestDistance := allowed + 1
+	for _, candidate := range append([]string{text}, words...) {
+		candidateRunes := []rune(candidate)
+		if len(candidateRunes) > len(queryRunes) {
+			candidateRunes = candidateRunes[:len(queryRunes)]
+		}
+		if distance := levenshtein(queryRunes, candidateRunes); distance < bestDistance {
+			bestDistance = distance
+		}
+	}
+	if bestDistance > allowed {
+		return 0
+	}
+	return 0.5 - 0.1*float64(bestDistance-1)
+}
+
+// allowedTypos returns the edit distance tolerated for a query of this length
+func allowedTypos(query string) int {
+	length := utf8.RuneCountInString(query)
+	switch {
+	case length >= 7:
+		return 2
+	case length >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// popularityBoost gives frequently used suggestions a logarithmic bonus
+func popularityBoost(frequency int) float64 {
+	if frequency <= 0 {
+		return 1
+	}
+	return 1 + math.Log10(1+float64(frequency))/4
+}
+
+// accentReplacer folds Spanish accents so "Cumbaya" matches "Cumbayá"
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+)
+
+// normalizeForMatch lowercases, folds accents and collapses whitespace
+func normalizeForMatch(text string) string {
+	return strings.Join(strings.Fields(accentReplacer.Replace(strings.ToLower(text))), " ")
+}
+
+// levenshtein computes the edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// suggestionCacheKey builds the cache key for a normalized query and limit
+func suggestionCacheKey(query string, limit int) string {
+	return fmt.Sprintf("suggest:%s:%d", normalizeForMatch(query), limit)
+}
@@ -0,0 +1,187 @@
+package search
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/repository"
+)
+
+// fakeSuggestionSource serves fixed catalogs and counts loads
+type fakeSuggestionSource struct {
+	popular   []repository.SearchSuggestion
+	locations []repository.SearchSuggestion
+	agencies  []repository.SearchSuggestion
+	agencyErr error
+	recorded  []string
+	loads     int
+	minCount  int
+}
+
+func (f *fakeSuggestionSource) RecordQuery(query string) error {
+	f.recorded = append(f.recorded, query)
+	return nil
+}
+
+func (f *fakeSuggestionSource) GetPopularQueries(since time.Time, minCount, limit int) ([]repository.SearchSuggestion, error) {
+	f.loads++
+	f.minCount = minCount
+	return f.popular, nil
+}
+
+func (f *fakeSuggestionSource) GetLocationCatalog() ([]repository.SearchSuggestion, error) {
+	return f.locations, nil
+}
+
+func (f *fakeSuggestionSource) GetAgencyNames() ([]repository.SearchSuggestion, error) {
+	return f.agencies, f.agencyErr
+}
+
+func newFakeSuggestionSource() *fakeSuggestionSource {
+	return &fakeSuggestionSource{
+		popular: []repository.SearchSuggestion{
+			{Text: "casa en samborondón", Category: repository.SuggestionCategoryPopular, Frequency: 40},
+			{Text: "departamento cumbayá", Category: repository.SuggestionCategoryPopular, Frequency: 25},
+		},
+		locations: []repository.SearchSuggestion{
+			{Text: "Guayas", Category: repository.SuggestionCategoryProvince, Frequency: 150},
+			{Text: "Cumbayá", Category: repository.SuggestionCategoryCity, Frequency: 30},
+			{Text: "Samborondón", Category: repository.SuggestionCategoryCity, Frequency: 60},
+		},
+		agencies: []repository.SearchSuggestion{
+			{Text: "Inmobiliaria Los Andes", Category: repository.SuggestionCategoryAgency, Frequency: 12},
+		},
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		text     string
+		expected float64
+	}{
+		{"prefix of text", "sambo", "samborondon", 1.0},
+		{"prefix of a word", "andes", "inmobiliaria los andes", 0.8},
+		{"one typo", "cumbsy", "cumbaya", 0.5},
+		{"too many typos", "cunbsy", "cumbaya", 0},
+		{"two typos on long query", "samvorondin", "samborondon", 0.4},
+		{"short query is never fuzzy", "gya", "guayas", 0},
+		{"no match", "loja", "guayas", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, matchScore(tt.query, tt.text), 0.0001)
+		})
+	}
+}
+
+func TestSuggestionEngine_Blend(t *testing.T) {
+	source := newFakeSuggestionSource()
+	engine := NewSuggestionEngine(source, SuggestionConfig{})
+
+	base := []repository.SearchSuggestion{
+		{Text: "Samborondón", Category: repository.SuggestionCategoryCity, Frequency: 60},
+	}
+
+	suggestions := engine.Blend("Sambo", base, 5)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "Samborondón", suggestions[0].Text)
+	assert.Equal(t, "casa en samborondón", suggestions[1].Text)
+
+	// Accent-insensitive and fuzzy matches include locations and agencies
+	suggestions = engine.Blend("cumbaya", nil, 5)
+	require.NotEmpty(t, suggestions)
+	assert.Equal(t, "Cumbayá", suggestions[0].Text)
+
+	suggestions = engine.Blend("andes", nil, 5)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, repository.SuggestionCategoryAgency, suggestions[0].Category)
+
+	// Catalog is loaded once within its TTL
+	assert.Equal(t, 1, source.loads)
+	assert.Equal(t, DefaultSuggestionConfig().PopularMinCount, source.minCount, "queries searched once are not suggested")
+}
+
+func TestSuggestionEngine_Weights(t *testing.T) {
+	source := &fakeSuggestionSource{
+		locations: []repository.SearchSuggestion{{Text: "Loja", Category: repository.SuggestionCategoryCity}},
+		agencies:  []repository.SearchSuggestion{{Text: "Loja Propiedades", Category: repository.SuggestionCategoryAgency}},
+	}
+
+	engine := NewSuggestionEngine(source, SuggestionConfig{
+		Weights: map[string]float64{repository.SuggestionCategoryAgency: 2.0},
+	})
+
+	suggestions := engine.Blend("loja", nil, 5)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "Loja Propiedades", suggestions[0].Text)
+}
+
+func TestSuggestionEngine_CacheAndInvalidate(t *testing.T) {
+	source := newFakeSuggestionSource()
+	engine := NewSuggestionEngine(source, SuggestionConfig{})
+
+	_, found := engine.Cached("guay", 5)
+	assert.False(t, found)
+
+	blended := engine.Blend("guay", nil, 5)
+	cached, found := engine.Cached("Guay", 5)
+	assert.True(t, found)
+	assert.Equal(t, blended, cached)
+
+	engine.Invalidate()
+	_, found = engine.Cached("guay", 5)
+	assert.False(t, found)
+
+	engine.Blend("guay", nil, 5)
+	assert.Equal(t, 2, source.loads)
+}
+
+func TestSuggestionEngine_PartialCatalog(t *testing.T) {
+	source := newFakeSuggestionSource()
+	source.agencyErr = errors.New("agencies table missing")
+	engine := NewSuggestionEngine(source, SuggestionConfig{})
+
+	suggestions := engine.Blend("guayas", nil, 5)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "Guayas", suggestions[0].Text)
+}
+
+func TestSuggestionEngine_RecordQuery(t *testing.T) {
+	source := newFakeSuggestionSource()
+	engine := NewSuggestionEngine(source, SuggestionConfig{})
+
+	engine.RecordQuery("  Casa   en Quito ")
+	engine.RecordQuery("ca")
+
+	assert.Equal(t, []string{"casa en quito"}, source.recorded)
+}
+
+func TestSuggestionEngine_ForTenant(t *testing.T) {
+	source := newFakeSuggestionSource()
+	engine := NewSuggestionEngine(source, SuggestionConfig{PopularMinCount: 3})
+	assert.Nil(t, engine.ForTenant("tenant-a"), "without tenant sources tenants get no engine")
+
+	tenantSources := map[string]*fakeSuggestionSource{"tenant-a": {}, "tenant-b": newFakeSuggestionSource()}
+	engine.SetTenantSources(func(tenantID string) SuggestionSource { return tenantSources[tenantID] })
+	assert.Same(t, engine, engine.ForTenant(""))
+
+	tenantA := engine.ForTenant("tenant-a")
+	require.NotNil(t, tenantA)
+	assert.Same(t, tenantA, engine.ForTenant("tenant-a"), "the catalog of a tenant is cached between requests")
+
+	tenantA.RecordQuery("casa en quito")
+	assert.Equal(t, []string{"casa en quito"}, tenantSources["tenant-a"].recorded)
+	assert.Empty(t, source.recorded)
+
+	// The popular queries of other tenants are not suggested
+	assert.Empty(t, tenantA.Blend("casa", nil, 5))
+	assert.NotEmpty(t, engine.ForTenant("tenant-b").Blend("casa", nil, 5))
+	assert.Equal(t, 3, tenantSources["tenant-a"].minCount)
+}
@@ -0,0 +1,172 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsAlgorithm     = "AWS4-HMAC-SHA256"
+	awsService       = "secretsmanager"
+	awsTarget        = "secretsmanager.GetSecretValue"
+	awsContentType   = "application/x-amz-json-1.1"
+	awsNotFoundError = "ResourceNotFoundException"
+)
+
+// AWSProvider reads secrets with the Secrets Manager GetSecretValue API, signing
+// requests with SigV4. Binary secrets are not supported.
+type AWSProvider struct {
+	endpoint        *url.URL
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	client          *http.Client
+	now             func() time.Time
+}
+
+// newAWSProvider validates the AWS settings
+func newAWSProvider(config Config, client *http.Client) (*AWSProvider, error) {
+	if config.AWSRegion == "" {
+		return nil, fmt.Errorf("AWS region is required for the aws secrets backend")
+	}
+	if config.AWSAccessKeyID == "" || config.AWSSecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials are required for the aws secrets backend")
+	}
+
+	endpoint := config.AWSEndpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + config.AWSRegion + ".amazonaws.com"
+	}
+	parsed, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid AWS Secrets Manager endpoint: %s", endpoint)
+	}
+
+	return &AWSProvider{
+		endpoint:        parsed,
+		region:          config.AWSRegion,
+		accessKeyID:     config.AWSAccessKeyID,
+		secretAccessKey: config.AWSSecretAccessKey,
+		sessionToken:    config.AWSSessionToken,
+		client:          client,
+		now:             time.Now,
+	}, nil
+}
+
+// Name identifies the provider
+func (p *AWSProvider) Name() string {
+	return BackendAWS
+}
+
+// GetSecret returns the current version of a secret by name or ARN
+func (p *AWSProvider) GetSecret(name string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": name})
+	if err != nil {
+		return "", fmt.Errorf("error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint.String()+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", awsContentType)
+	req.Header.Set("X-Amz-Target", awsTarget)
+	p.signRequest(req, body)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting AWS Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if strings.Contains(string(message), awsNotFoundError) {
+			return "", fmt.Errorf("secret not found: %s", name)
+		}
+		return "", fmt.Errorf("AWS Secrets Manager returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding AWS Secrets Manager response: %w", err)
+	}
+	if result.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", name)
+	}
+	return *result.SecretString, nil
+}
+
+// signRequest adds SigV4 authorization headers to a request
+func (p *AWSProvider) signRequest(req *http.Request, body []byte) {
+	now := p.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := strings.Join([]string{now.Format("20060102"), p.region, awsService, "aws4_request"}, "/")
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	if p.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.sessionToken)
+		headers["x-amz-security-token"] = p.sessionToken
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{awsAlgorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.secretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsAlgorithm, p.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,162 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcpDefaultEndpoint    = "https://secretmanager.googleapis.com"
+	gcpDefaultMetadataURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	gcpTokenExpiryMargin  = time.Minute
+)
+
+// GCPProvider reads secrets with the Secret Manager accessSecretVersion API. It
+// authenticates with a static access token or, on Google Cloud, with the token of
+// the instance's service account from the metadata server.
+type GCPProvider struct {
+	endpoint    string
+	project     string
+	staticToken string
+	metadataURL string
+	client      *http.Client
+	now         func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// newGCPProvider validates the Google Cloud settings
+func newGCPProvider(config Config, client *http.Client) (*GCPProvider, error) {
+	if config.GCPProject == "" {
+		return nil, fmt.Errorf("GCP project is required for the gcp secrets backend")
+	}
+
+	endpoint := config.GCPEndpoint
+	if endpoint == "" {
+		endpoint = gcpDefaultEndpoint
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid GCP Secret Manager endpoint: %w", err)
+	}
+	metadataURL := config.GCPMetadataURL
+	if metadataURL == "" {
+		metadataURL = gcpDefaultMetadataURL
+	}
+
+	return &GCPProvider{
+		endpoint:    strings.TrimRight(endpoint, "/"),
+		project:     config.GCPProject,
+		staticToken: config.GCPAccessToken,
+		metadataURL: metadataURL,
+		client:      client,
+		now:         time.Now,
+	}, nil
+}
+
+// Name identifies the provider
+func (p *GCPProvider) Name() string {
+	return BackendGCP
+}
+
+// GetSecret returns the latest version of a secret. name is a secret ID of the
+// configured project or a full projects/.../secrets/.../versions/... resource name.
+func (p *GCPProvider) GetSecret(name string) (string, error) {
+	resource := name
+	if !strings.HasPrefix(name, "projects/") {
+		resource = "projects/" + p.project + "/secrets/" + name + "/versions/latest"
+	}
+
+	token, err := p.accessToken()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.endpoint+"/v1/"+resource+":access", nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting GCP Secret Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("secret not found: %s", name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("GCP Secret Manager returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding GCP Secret Manager response: %w", err)
+	}
+	value, err := base64.StdEncoding.DecodeString(result.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding secret %s: %w", name, err)
+	}
+	return string(value), nil
+}
+
+// accessToken returns the static token or a cached metadata server token
+func (p *GCPProvider) accessToken() (string, error) {
+	if p.staticToken != "" {
+		return p.staticToken, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && p.now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.metadataURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting GCP metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("GCP metadata server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding GCP metadata token: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("GCP metadata server returned no access token")
+	}
+
+	p.token = result.AccessToken
+	p.tokenExpiry = p.now().Add(time.Duration(result.ExpiresIn)*time.Second - gcpTokenExpiryMargin)
+	return p.token, nil
+}
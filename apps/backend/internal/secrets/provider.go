@@ -0,0 +1,135 @@
+// Package secrets loads credentials from a secrets manager instead of environment
+// variables. Supported backends are AWS Secrets Manager, HashiCorp Vault (KV v2) and
+// Google Cloud Secret Manager; the env backend reads plain environment variables.
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Backend names
+const (
+	BackendEnv   = "env"
+	BackendAWS   = "aws"
+	BackendVault = "vault"
+	BackendGCP   = "gcp"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// Provider reads secrets from a secrets manager
+type Provider interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// GetSecret returns the current value of a secret
+	GetSecret(name string) (string, error)
+}
+
+// Config holds the settings of the secrets backend
+type Config struct {
+	Backend string // env, aws, vault, gcp
+	Timeout time.Duration
+
+	// AWS Secrets Manager
+	AWSRegion          string
+	AWSEndpoint        string // defaults to https://secretsmanager.{region}.amazonaws.com
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string // temporary credentials of an assumed role
+
+	// HashiCorp Vault
+	VaultAddr      string
+	VaultToken     string
+	VaultMount     string // KV v2 mount, "secret" by default
+	VaultNamespace string // Vault Enterprise namespace
+
+	// Google Cloud Secret Manager
+	GCPProject     string
+	GCPEndpoint    string // defaults to https://secretmanager.googleapis.com
+	GCPAccessToken string // static OAuth token; the metadata server is used when empty
+	GCPMetadataURL string // defaults to the GCE metadata server
+}
+
+// NewProvider creates the provider configured by config.Backend
+func NewProvider(config Config) (Provider, error) {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch strings.ToLower(config.Backend) {
+	case "", BackendEnv:
+		return EnvProvider{}, nil
+	case BackendAWS:
+		return newAWSProvider(config, client)
+	case BackendVault:
+		return newVaultProvider(config, client)
+	case BackendGCP:
+		return newGCPProvider(config, client)
+	default:
+		return nil, fmt.Errorf("unsupported secrets backend: %s", config.Backend)
+	}
+}
+
+// Resolve reads a secret reference. A reference is a secret name, optionally followed
+// by #field to read one field of a JSON secret, e.g. "realty/smtp#password".
+func Resolve(provider Provider, ref string) (string, error) {
+	name, field, hasField := strings.Cut(ref, "#")
+	value, err := provider.GetSecret(name)
+	if err != nil {
+		return "", err
+	}
+	if !hasField {
+		return value, nil
+	}
+	return jsonField(name, value, field)
+}
+
+// jsonField extracts a field of a JSON object secret
+func jsonField(name, value, field string) (string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", name, err)
+	}
+
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("secret field not found: %s#%s", name, field)
+	}
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode secret field %s#%s: %w", name, field, err)
+		}
+		return string(encoded), nil
+	}
+}
+
+// EnvProvider reads secrets from environment variables named after the secret
+type EnvProvider struct{}
+
+// Name identifies the provider
+func (EnvProvider) Name() string {
+	return BackendEnv
+}
+
+// GetSecret returns the value of the environment variable name
+func (EnvProvider) GetSecret(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("secret not found: %s", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,217 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider serves secrets from a map
+type stubProvider map[string]string
+
+func (p stubProvider) Name() string { return "stub" }
+
+func (p stubProvider) GetSecret(name string) (string, error) {
+	if value, ok := p[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret not found: %s", name)
+}
+
+func TestResolve(t *testing.T) {
+	provider := stubProvider{
+		"realty/db":   "postgres://app:pw@db/realty",
+		"realty/smtp": `{"username":"mailer","password":"s3cret","port":587}`,
+	}
+
+	value, err := Resolve(provider, "realty/db")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app:pw@db/realty", value)
+
+	value, err = Resolve(provider, "realty/smtp#password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	value, err = Resolve(provider, "realty/smtp#port")
+	require.NoError(t, err)
+	assert.Equal(t, "587", value)
+
+	_, err = Resolve(provider, "realty/smtp#token")
+	assert.ErrorContains(t, err, "secret field not found")
+
+	_, err = Resolve(provider, "realty/db#password")
+	assert.ErrorContains(t, err, "not a JSON object")
+
+	_, err = Resolve(provider, "realty/missing")
+	assert.ErrorContains(t, err, "secret not found")
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(Config{})
+	require.NoError(t, err)
+	assert.Equal(t, BackendEnv, provider.Name())
+
+	_, err = NewProvider(Config{Backend: "aws"})
+	assert.ErrorContains(t, err, "region is required")
+
+	_, err = NewProvider(Config{Backend: "vault", VaultAddr: "http://vault:8200"})
+	assert.ErrorContains(t, err, "token is required")
+
+	_, err = NewProvider(Config{Backend: "gcp"})
+	assert.ErrorContains(t, err, "project is required")
+
+	_, err = NewProvider(Config{Backend: "keychain"})
+	assert.ErrorContains(t, err, "unsupported secrets backend")
+}
+
+func TestEnvProvider_GetSecret(t *testing.T) {
+	t.Setenv("REALTY_TEST_SECRET", "value")
+
+	value, err := EnvProvider{}.GetSecret("REALTY_TEST_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	_, err = EnvProvider{}.GetSecret("REALTY_TEST_MISSING")
+	assert.ErrorContains(t, err, "secret not found")
+}
+
+func TestAWSProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, awsTarget, r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		authorization := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/20250815/us-east-1/secretsmanager/aws4_request"))
+		assert.Contains(t, authorization, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target")
+
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "realty/missing") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+			return
+		}
+		assert.JSONEq(t, `{"SecretId":"realty/jwt"}`, string(body))
+		fmt.Fprint(w, `{"Name":"realty/jwt","SecretString":"jwt-key"}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{
+		Backend:            BackendAWS,
+		AWSRegion:          "us-east-1",
+		AWSEndpoint:        server.URL,
+		AWSAccessKeyID:     "AKID",
+		AWSSecretAccessKey: "secret",
+		AWSSessionToken:    "session",
+	})
+	require.NoError(t, err)
+	provider.(*AWSProvider).now = func() time.Time { return time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC) }
+
+	value, err := provider.GetSecret("realty/jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-key", value)
+
+	_, err = provider.GetSecret("realty/missing")
+	assert.ErrorContains(t, err, "secret not found: realty/missing")
+}
+
+func TestVaultProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/kv/data/realty/jwt":
+			fmt.Fprint(w, `{"data":{"data":{"value":"jwt-key"},"metadata":{"version":3}}}`)
+		case "/v1/kv/data/realty/smtp":
+			fmt.Fprint(w, `{"data":{"data":{"username":"mailer","password":"s3cret"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{Backend: BackendVault, VaultAddr: server.URL + "/", VaultToken: "vault-token", VaultMount: "kv"})
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret("realty/jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-key", value)
+
+	value, err = Resolve(provider, "realty/smtp#username")
+	require.NoError(t, err)
+	assert.Equal(t, "mailer", value)
+
+	_, err = provider.GetSecret("realty/missing")
+	assert.ErrorContains(t, err, "secret not found")
+}
+
+func TestGCPProvider_GetSecret(t *testing.T) {
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			fmt.Fprint(w, `{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`)
+		case "/v1/projects/realty-prod/secrets/jwt-key/versions/latest:access":
+			assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+			fmt.Fprintf(w, `{"name":"projects/1/secrets/jwt-key/versions/4","payload":{"data":%q}}`, base64.StdEncoding.EncodeToString([]byte("jwt-key")))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(Config{Backend: BackendGCP, GCPProject: "realty-prod", GCPEndpoint: server.URL, GCPMetadataURL: server.URL + "/token"})
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret("jwt-key")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-key", value)
+
+	value, err = provider.GetSecret("projects/realty-prod/secrets/jwt-key/versions/latest")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-key", value)
+	assert.Equal(t, 1, tokenRequests, "the metadata token is cached")
+
+	_, err = provider.GetSecret("db-url")
+	assert.ErrorContains(t, err, "secret not found")
+}
+
+func TestWatcher_Check(t *testing.T) {
+	provider := stubProvider{"realty/jwt": "v1"}
+	watcher := NewWatcher(provider, "realty/jwt", "v1", time.Hour, log.New(io.Discard, "", 0))
+
+	var applied []string
+	reject := false
+	watcher.Start(func(value string) error {
+		if reject {
+			return fmt.Errorf("invalid key set")
+		}
+		applied = append(applied, value)
+		return nil
+	})
+	defer watcher.Stop()
+
+	assert.False(t, watcher.Check(), "unchanged secrets are not reported")
+
+	provider["realty/jwt"] = "v2"
+	reject = true
+	assert.False(t, watcher.Check())
+
+	reject = false
+	assert.True(t, watcher.Check(), "rejected values are retried")
+	assert.False(t, watcher.Check())
+	assert.Equal(t, []string{"v2"}, applied)
+
+	delete(provider, "realty/jwt")
+	assert.False(t, watcher.Check(), "read errors keep the current value")
+}
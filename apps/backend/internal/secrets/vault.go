@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultVaultMount = "secret"
+
+// VaultProvider reads secrets from a HashiCorp Vault KV version 2 engine. A secret
+// with a single "value" key is returned as that value; any other secret is returned
+// as a JSON object, so its keys are read with Resolve's #field syntax.
+type VaultProvider struct {
+	addr      string
+	token     string
+	mount     string
+	namespace string
+	client    *http.Client
+}
+
+// newVaultProvider validates the Vault settings
+func newVaultProvider(config Config, client *http.Client) (*VaultProvider, error) {
+	if config.VaultAddr == "" {
+		return nil, fmt.Errorf("Vault address is required for the vault secrets backend")
+	}
+	if _, err := url.ParseRequestURI(config.VaultAddr); err != nil {
+		return nil, fmt.Errorf("invalid Vault address: %w", err)
+	}
+	if config.VaultToken == "" {
+		return nil, fmt.Errorf("Vault token is required for the vault secrets backend")
+	}
+
+	mount := strings.Trim(config.VaultMount, "/")
+	if mount == "" {
+		mount = defaultVaultMount
+	}
+
+	return &VaultProvider{
+		addr:      strings.TrimRight(config.VaultAddr, "/"),
+		token:     config.VaultToken,
+		mount:     mount,
+		namespace: config.VaultNamespace,
+		client:    client,
+	}, nil
+}
+
+// Name identifies the provider
+func (p *VaultProvider) Name() string {
+	return BackendVault
+}
+
+// GetSecret returns the latest version of the secret at path name
+func (p *VaultProvider) GetSecret(name string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.addr+"/v1/"+p.mount+"/data/"+strings.Trim(name, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("secret not found: %s", name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("Vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding Vault response: %w", err)
+	}
+	if result.Data.Data == nil {
+		return "", fmt.Errorf("secret not found: %s", name)
+	}
+
+	if value, ok := result.Data.Data["value"].(string); ok && len(result.Data.Data) == 1 {
+		return value, nil
+	}
+	encoded, err := json.Marshal(result.Data.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode secret %s: %w", name, err)
+	}
+	return string(encoded), nil
+}
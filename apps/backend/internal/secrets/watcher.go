@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Watcher polls a secret and reports new values, so rotated credentials such as the
+// JWT signing keys are picked up without a restart
+type Watcher struct {
+	provider Provider
+	ref      string
+	interval time.Duration
+	logger   *log.Logger
+
+	mu       sync.Mutex
+	last     string
+	onChange func(value string) error
+	done     chan struct{}
+}
+
+// NewWatcher creates a watcher for a secret reference (see Resolve). initial is the
+// value already in use, so only later changes are reported.
+func NewWatcher(provider Provider, ref, initial string, interval time.Duration, logger *log.Logger) *Watcher {
+	return &Watcher{
+		provider: provider,
+		ref:      ref,
+		interval: interval,
+		logger:   logger,
+		last:     initial,
+	}
+}
+
+// Start polls the secret every interval and calls onChange with each new value. A
+// value onChange rejects is retried on the next poll.
+func (w *Watcher) Start(onChange func(value string) error) {
+	w.onChange = onChange
+	w.done = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.Check()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops polling
+func (w *Watcher) Stop() {
+	if w.done == nil {
+		return
+	}
+	close(w.done)
+	w.done = nil
+}
+
+// Check reads the secret once and reports whether a new value was applied
+func (w *Watcher) Check() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	value, err := Resolve(w.provider, w.ref)
+	if err != nil {
+		w.logger.Printf("Error reading secret %s from %s: %v", w.ref, w.provider.Name(), err)
+		return false
+	}
+	if value == w.last || w.onChange == nil {
+		return false
+	}
+	if err := w.onChange(value); err != nil {
+		w.logger.Printf("Rejected new value of secret %s: %v", w.ref, err)
+		return false
+	}
+
+	w.last = value
+	w.logger.Printf("Secret %s changed, new value applied", w.ref)
+	return true
+}
@@ -105,6 +105,16 @@ func (rl *RateLimiter) cleanup() {
 	}
 }
 
+// SetMaxRequests changes the requests allowed per window. Every client starts a fresh
+// window with the new limit.
+func (rl *RateLimiter) SetMaxRequests(maxRequests int) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	rl.maxRequests = maxRequests
+	rl.buckets = make(map[string]*TokenBucket)
+}
+
 // Stop stops the rate limiter and cleanup routine
 func (rl *RateLimiter) Stop() {
 	if rl.cleanupTicker != nil {
@@ -135,6 +145,7 @@ type RateLimiterStats struct {
 type AdaptiveRateLimiter struct {
 	baseLimiter    *RateLimiter
 	adaptiveConfig AdaptiveConfig
+	configMutex    sync.RWMutex
 	loadMeter      *LoadMeter
 }
 
@@ -185,31 +196,54 @@ func (arl *AdaptiveRateLimiter) updateLimits() {
 	lastUpdate := arl.loadMeter.lastUpdate
 	arl.loadMeter.mutex.RUnlock()
 	
+	config := arl.Limits()
+
 	// Only update if enough time has passed
-	if time.Since(lastUpdate) < arl.adaptiveConfig.AdaptationInterval {
+	if time.Since(lastUpdate) < config.AdaptationInterval {
 		return
 	}
 	
 	// Calculate new max requests based on load
 	var newMaxRequests int
 	if load < 30 { // Low load
-		newMaxRequests = arl.adaptiveConfig.MaxMaxRequests
+		newMaxRequests = config.MaxMaxRequests
 	} else if load > 80 { // High load
-		newMaxRequests = arl.adaptiveConfig.MinMaxRequests
+		newMaxRequests = config.MinMaxRequests
 	} else { // Medium load - interpolate
 		factor := (80 - load) / 50 // 0-1 scale
-		newMaxRequests = arl.adaptiveConfig.MinMaxRequests + 
-			int(factor * float64(arl.adaptiveConfig.MaxMaxRequests - arl.adaptiveConfig.MinMaxRequests))
+		newMaxRequests = config.MinMaxRequests + 
+			int(factor * float64(config.MaxMaxRequests - config.MinMaxRequests))
 	}
 	
 	// Update the base limiter's max requests
+	arl.baseLimiter.mutex.Lock()
 	arl.baseLimiter.maxRequests = newMaxRequests
+	arl.baseLimiter.mutex.Unlock()
 	
 	arl.loadMeter.mutex.Lock()
 	arl.loadMeter.lastUpdate = time.Now()
 	arl.loadMeter.mutex.Unlock()
 }
 
+// SetLimits replaces the adaptive limits at runtime, e.g. on a configuration reload
+func (arl *AdaptiveRateLimiter) SetLimits(config AdaptiveConfig) {
+	arl.configMutex.Lock()
+	if config.AdaptationInterval <= 0 {
+		config.AdaptationInterval = arl.adaptiveConfig.AdaptationInterval
+	}
+	arl.adaptiveConfig = config
+	arl.configMutex.Unlock()
+
+	arl.baseLimiter.SetMaxRequests(config.BaseMaxRequests)
+}
+
+// Limits returns the current adaptive limits
+func (arl *AdaptiveRateLimiter) Limits() AdaptiveConfig {
+	arl.configMutex.RLock()
+	defer arl.configMutex.RUnlock()
+	return arl.adaptiveConfig
+}
+
 // UpdateLoad updates the current server load measurement
 func (arl *AdaptiveRateLimiter) UpdateLoad(load float64) {
 	arl.loadMeter.mutex.Lock()
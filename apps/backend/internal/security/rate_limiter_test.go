@@ -0,0 +1,30 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveRateLimiter_SetLimits(t *testing.T) {
+	limiter := NewAdaptiveRateLimiter(AdaptiveConfig{
+		BaseMaxRequests:    2,
+		MaxMaxRequests:     4,
+		MinMaxRequests:     1,
+		AdaptationInterval: time.Hour,
+	}, time.Minute)
+	defer limiter.baseLimiter.Stop()
+
+	assert.True(t, limiter.Allow("10.0.0.1"))
+	assert.True(t, limiter.Allow("10.0.0.1"))
+	assert.False(t, limiter.Allow("10.0.0.1"))
+
+	limiter.SetLimits(AdaptiveConfig{BaseMaxRequests: 5, MaxMaxRequests: 10, MinMaxRequests: 1})
+
+	assert.Equal(t, time.Hour, limiter.Limits().AdaptationInterval, "a zero interval keeps the current one")
+	for i := 0; i < 5; i++ {
+		assert.True(t, limiter.Allow("10.0.0.1"), "clients start a fresh window with the new limit")
+	}
+	assert.False(t, limiter.Allow("10.0.0.1"))
+}
@@ -0,0 +1,454 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scraper guard actions, from least to most friction
+const (
+	ScraperAllow     = "allow"
+	ScraperDelay     = "delay"
+	ScraperChallenge = "challenge" // a deep page was requested without a valid page token
+	ScraperBlock     = "block"
+)
+
+// maxScraperClients bounds the clients tracked at once; past it the least recently seen
+// are forgotten
+const maxScraperClients = 100000
+
+// automationUserAgents are user agent fragments of HTTP libraries and headless browsers.
+// They are easily spoofed, so they only lower the limits of a client rather than
+// blocking it.
+var automationUserAgents = []string{
+	"curl", "wget", "python-requests", "python-urllib", "aiohttp", "scrapy", "httpclient",
+	"go-http-client", "okhttp", "java/", "node-fetch", "axios", "headlesschrome",
+	"phantomjs", "selenium", "puppeteer", "playwright",
+}
+
+// ScraperGuardConfig configures the friction applied to clients crawling the public
+// catalog
+type ScraperGuardConfig struct {
+	SoftLimit       int           // requests per window served without friction
+	HardLimit       int           // requests per window past which requests are refused
+	Window          time.Duration // window the limits count requests in
+	DelayStep       time.Duration // delay added for each request over the soft limit
+	MaxDelay        time.Duration // longest delay applied to a request
+	DeepPage        int           // pages from a random point past this one need a page token; 0 disables
+	PageTokenSecret string        // signs page tokens; empty uses a random per-instance secret
+	PageTokenTTL    time.Duration // how long a page token can be used
+	ActivityTTL     time.Duration // how long idle clients stay in the activity report
+}
+
+// DefaultScraperGuardConfig returns limits no person browsing listings reaches
+func DefaultScraperGuardConfig() ScraperGuardConfig {
+	return ScraperGuardConfig{
+		SoftLimit:    60,
+		HardLimit:    300,
+		Window:       time.Minute,
+		DelayStep:    100 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		DeepPage:     10,
+		PageTokenTTL: 30 * time.Minute,
+		ActivityTTL:  24 * time.Hour,
+	}
+}
+
+// ScraperRequest describes a catalog request to the guard
+type ScraperRequest struct {
+	Fingerprint string
+	ClientIP    string
+	UserAgent   string
+	UserID      string
+	Page        int
+	PageToken   string
+	Signals     []string // automation signals, see ScraperSignals
+}
+
+// ScraperDecision is the friction to apply to a request
+type ScraperDecision struct {
+	Action     string
+	Delay      time.Duration // before serving a delayed request
+	RetryAfter time.Duration // before a blocked client may retry
+}
+
+// ScraperClientActivity is the activity of a client fingerprint
+type ScraperClientActivity struct {
+	Fingerprint     string    `json:"fingerprint"`
+	ClientIP        string    `json:"client_ip"`
+	UserAgent       string    `json:"user_agent"`
+	UserID          string    `json:"user_id,omitempty"`
+	Status          string    `json:"status"` // the action applied to its latest request
+	Signals         []string  `json:"signals,omitempty"`
+	Requests        int64     `json:"requests"`
+	WindowRequests  int       `json:"window_requests"`
+	DelayedRequests int64     `json:"delayed_requests"`
+	DelaySeconds    float64   `json:"delay_seconds"`
+	TokenRejections int64     `json:"token_rejections"`
+	BlockedRequests int64     `json:"blocked_requests"`
+	DeepestPage     int       `json:"deepest_page"`
+	FirstSeen       time.Time `json:"first_seen"`
+	LastSeen        time.Time `json:"last_seen"`
+}
+
+// ScraperActivityReport summarizes the clients the guard applied friction to
+type ScraperActivityReport struct {
+	GeneratedAt     time.Time               `json:"generated_at"`
+	TrackedClients  int                     `json:"tracked_clients"`
+	DelayedRequests int64                   `json:"delayed_requests"`
+	TokenRejections int64                   `json:"token_rejections"`
+	BlockedRequests int64                   `json:"blocked_requests"`
+	Clients         []ScraperClientActivity `json:"clients"`
+}
+
+// scraperClient is the state kept for a fingerprint
+type scraperClient struct {
+	activity    ScraperClientActivity
+	windowStart time.Time
+	deepPage    int // page past which this client needs page tokens
+}
+
+// ScraperGuard applies escalating friction to clients crawling the public catalog.
+// Clients are told apart by a fingerprint of their network. Past a
+// soft limit each request is delayed a little more, deep pages need a signed token
+// handed out with the previous page, and past a hard limit requests are refused until
+// the window ends. The point from which page tokens are needed varies per client, so
+// crawlers cannot learn where it is.
+type ScraperGuard struct {
+	mutex     sync.Mutex
+	config    ScraperGuardConfig
+	secret    []byte
+	clients   map[string]*scraperClient
+	lastPrune time.Time
+	totals    ScraperActivityReport
+}
+
+// NewScraperGuard creates a scraper guard
+func NewScraperGuard(config ScraperGuardConfig) *ScraperGuard {
+	defaults := DefaultScraperGuardConfig()
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.SoftLimit <= 0 {
+		config.SoftLimit = defaults.SoftLimit
+	}
+	if config.HardLimit < config.SoftLimit {
+		config.HardLimit = config.SoftLimit * 5
+	}
+	if config.PageTokenTTL <= 0 {
+		config.PageTokenTTL = defaults.PageTokenTTL
+	}
+	if config.ActivityTTL <= 0 {
+		config.ActivityTTL = defaults.ActivityTTL
+	}
+
+	secret := []byte(config.PageTokenSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+	return &ScraperGuard{
+		config:  config,
+		secret:  secret,
+		clients: map[string]*scraperClient{},
+	}
+}
+
+// Check records a catalog request and decides the friction to apply to it
+func (g *ScraperGuard) Check(req ScraperRequest, now time.Time) ScraperDecision {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.prune(now)
+	client := g.client(req, now)
+	activity := &client.activity
+
+	if now.Sub(client.windowStart) >= g.config.Window {
+		client.windowStart = now
+		activity.WindowRequests = 0
+	}
+	activity.Requests++
+	activity.WindowRequests++
+	activity.LastSeen = now
+	activity.ClientIP = req.ClientIP
+	activity.UserAgent = req.UserAgent
+	if req.UserID != "" {
+		activity.UserID = req.UserID
+	}
+	activity.Signals = mergeSignals(activity.Signals, req.Signals)
+	if req.Page > activity.DeepestPage {
+		activity.DeepestPage = req.Page
+	}
+
+	// Clients that look automated get half the limits
+	softLimit, hardLimit := g.config.SoftLimit, g.config.HardLimit
+	if len(activity.Signals) > 0 {
+		softLimit, hardLimit = max(softLimit/2, 1), max(hardLimit/2, 1)
+	}
+
+	if activity.WindowRequests > hardLimit {
+		activity.Status = ScraperBlock
+		activity.BlockedRequests++
+		g.totals.BlockedRequests++
+		return ScraperDecision{Action: ScraperBlock, RetryAfter: client.windowStart.Add(g.config.Window).Sub(now)}
+	}
+
+	if client.deepPage > 0 && req.Page > client.deepPage && !g.validPageToken(req.Fingerprint, req.Page, req.PageToken, now) {
+		activity.Status = ScraperChallenge
+		activity.TokenRejections++
+		g.totals.TokenRejections++
+		return ScraperDecision{Action: ScraperChallenge}
+	}
+
+	if over := activity.WindowRequests - softLimit; over > 0 && g.config.DelayStep > 0 {
+		delay := time.Duration(over) * g.config.DelayStep
+		if g.config.MaxDelay > 0 && delay > g.config.MaxDelay {
+			delay = g.config.MaxDelay
+		}
+		activity.Status = ScraperDelay
+		activity.DelayedRequests++
+		activity.DelaySeconds += delay.Seconds()
+		g.totals.DelayedRequests++
+		return ScraperDecision{Action: ScraperDelay, Delay: delay}
+	}
+
+	activity.Status = ScraperAllow
+	return ScraperDecision{Action: ScraperAllow}
+}
+
+// IssuePageToken returns the token a client presents to open a page. It is bound to the
+// client's fingerprint, so tokens cannot be shared across a crawling fleet.
+func (g *ScraperGuard) IssuePageToken(fingerprint string, page int, now time.Time) string {
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+
+	payload := make([]byte, 0, 24)
+	payload = binary.BigEndian.AppendUint32(payload, uint32(page))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(now.Add(g.config.PageTokenTTL).Unix()))
+	payload = append(payload, nonce...)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(g.signPageToken(fingerprint, payload))
+}
+
+// validPageToken reports whether a page token was issued to the fingerprint for the page
+// and has not expired
+func (g *ScraperGuard) validPageToken(fingerprint string, page int, token string, now time.Time) bool {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != 20 {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, g.signPageToken(fingerprint, payload)) {
+		return false
+	}
+	if int(binary.BigEndian.Uint32(payload[:4])) != page {
+		return false
+	}
+	return now.Unix() <= int64(binary.BigEndian.Uint64(payload[4:12]))
+}
+
+func (g *ScraperGuard) signPageToken(fingerprint string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte(fingerprint))
+	mac.Write(payload)
+	return mac.Sum(nil)[:16]
+}
+
+// Activity returns the clients the guard applied friction to or that look automated,
+// most blocked first, at most limit of them; all includes every tracked client
+func (g *ScraperGuard) Activity(limit int, all bool, now time.Time) ScraperActivityReport {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	report := g.totals
+	report.GeneratedAt = now
+	report.TrackedClients = len(g.clients)
+	report.Clients = []ScraperClientActivity{}
+	for _, client := range g.clients {
+		activity := client.activity
+		if !all && activity.DelayedRequests == 0 && activity.TokenRejections == 0 &&
+			activity.BlockedRequests == 0 && len(activity.Signals) == 0 {
+			continue
+		}
+		if now.Sub(client.windowStart) >= g.config.Window {
+			activity.WindowRequests = 0
+		}
+		activity.Signals = append([]string{}, activity.Signals...)
+		report.Clients = append(report.Clients, activity)
+	}
+
+	sort.Slice(report.Clients, func(i, j int) bool {
+		a, b := report.Clients[i], report.Clients[j]
+		if a.BlockedRequests != b.BlockedRequests {
+			return a.BlockedRequests > b.BlockedRequests
+		}
+		if a.TokenRejections != b.TokenRejections {
+			return a.TokenRejections > b.TokenRejections
+		}
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	if limit > 0 && len(report.Clients) > limit {
+		report.Clients = report.Clients[:limit]
+	}
+	return report
+}
+
+// client returns the state of a fingerprint, creating it on its first request
+func (g *ScraperGuard) client(req ScraperRequest, now time.Time) *scraperClient {
+	if client, ok := g.clients[req.Fingerprint]; ok {
+		return client
+	}
+	if len(g.clients) >= maxScraperClients {
+		g.evictOldest()
+	}
+
+	client := &scraperClient{
+		activity:    ScraperClientActivity{Fingerprint: req.Fingerprint, FirstSeen: now},
+		windowStart: now,
+	}
+	if g.config.DeepPage > 0 {
+		// Past a random page between DeepPage and twice it
+		var buf [2]byte
+		rand.Read(buf[:])
+		client.deepPage = g.config.DeepPage + int(binary.BigEndian.Uint16(buf[:]))%(g.config.DeepPage+1)
+	}
+	g.clients[req.Fingerprint] = client
+	return client
+}
+
+// prune forgets clients idle for longer than the activity TTL, once per window
+func (g *ScraperGuard) prune(now time.Time) {
+	if now.Sub(g.lastPrune) < g.config.Window {
+		return
+	}
+	g.lastPrune = now
+	for fingerprint, client := range g.clients {
+		if now.Sub(client.activity.LastSeen) > g.config.ActivityTTL {
+			delete(g.clients, fingerprint)
+		}
+	}
+}
+
+// evictOldest forgets the least recently seen client
+func (g *ScraperGuard) evictOldest() {
+	var oldest string
+	var oldestSeen time.Time
+	for fingerprint, client := range g.clients {
+		if oldest == "" || client.activity.LastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = fingerprint, client.activity.LastSeen
+		}
+	}
+	delete(g.clients, oldest)
+}
+
+// RequestFingerprint identifies the client of a request by its network (/24 for IPv4,
+// /48 for IPv6), so rotating addresses within a network does not reset the limits.
+// clientIP must come from the connection or trusted proxies: headers, sessions and
+// anything else the client sends can be rotated to start afresh.
+func RequestFingerprint(clientIP string) string {
+	network := clientIP
+	if ip := net.ParseIP(clientIP); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			network = ip4.Mask(net.CIDRMask(24, 32)).String()
+		} else {
+			network = ip.Mask(net.CIDRMask(48, 128)).String()
+		}
+	}
+	hash := sha256.Sum256([]byte("network:" + network))
+	return hex.EncodeToString(hash[:12])
+}
+
+// ScraperSignals returns the signs a request comes from a program rather than a browser
+func ScraperSignals(r *http.Request) []string {
+	signals := []string{}
+	userAgent := strings.ToLower(r.UserAgent())
+	if userAgent == "" {
+		signals = append(signals, "missing_user_agent")
+	} else {
+		for _, fragment := range automationUserAgents {
+			if strings.Contains(userAgent, fragment) {
+				signals = append(signals, "automation_user_agent")
+				break
+			}
+		}
+	}
+	if r.Header.Get("Accept-Language") == "" {
+		signals = append(signals, "missing_accept_language")
+	}
+	return signals
+}
+
+// mergeSignals adds the new signals to the known ones
+func mergeSignals(known, signals []string) []string {
+	for _, signal := range signals {
+		found := false
+		for _, existing := range known {
+			if existing == signal {
+				found = true
+				break
+			}
+		}
+		if !found {
+			known = append(known, signal)
+		}
+	}
+	return known
+}
+
+// ParsePage returns the page a catalog request asks for, from ?page= or ?offset= with
+// ?page_size= or ?limit=, 1 when it asks for none
+func ParsePage(query map[string][]string) int {
+	get := func(key string) int {
+		if values := query[key]; len(values) > 0 {
+			if value, err := strconv.Atoi(values[0]); err == nil && value > 0 {
+				return value
+			}
+		}
+		return 0
+	}
+	if page := get("page"); page > 0 {
+		return page
+	}
+	if offset := get("offset"); offset > 0 {
+		size := get("page_size")
+		if size == 0 {
+			size = get("limit")
+		}
+		if size == 0 {
+			size = 20
+		}
+		return offset/size + 1
+	}
+	return 1
+}
+
+// String describes a decision for logs
+func (d ScraperDecision) String() string {
+	switch d.Action {
+	case ScraperDelay:
+		return fmt.Sprintf("%s %s", d.Action, d.Delay)
+	case ScraperBlock:
+		return fmt.Sprintf("%s for %s", d.Action, d.RetryAfter)
+	default:
+		return d.Action
+	}
+}
@@ -0,0 +1,93 @@
+package security
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScraperGuard_EscalatingFriction(t *testing.T) {
+	guard := NewScraperGuard(ScraperGuardConfig{
+		SoftLimit: 4,
+		HardLimit: 8,
+		Window:    time.Minute,
+		DelayStep: 100 * time.Millisecond,
+		MaxDelay:  250 * time.Millisecond,
+	})
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	req := ScraperRequest{Fingerprint: "client-1", ClientIP: "203.0.113.7", UserAgent: "Mozilla/5.0", Page: 1}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, ScraperAllow, guard.Check(req, now).Action)
+	}
+	assert.Equal(t, ScraperDecision{Action: ScraperDelay, Delay: 100 * time.Millisecond}, guard.Check(req, now))
+	assert.Equal(t, 200*time.Millisecond, guard.Check(req, now).Delay, "each request over the soft limit waits longer")
+	assert.Equal(t, 250*time.Millisecond, guard.Check(req, now).Delay, "up to the max delay")
+	guard.Check(req, now)
+
+	blocked := guard.Check(req, now.Add(20*time.Second))
+	assert.Equal(t, ScraperBlock, blocked.Action)
+	assert.Equal(t, 40*time.Second, blocked.RetryAfter)
+
+	assert.Equal(t, ScraperAllow, guard.Check(req, now.Add(time.Minute)).Action, "limits reset with the window")
+
+	automated := ScraperRequest{Fingerprint: "client-2", UserAgent: "python-requests/2.31", Page: 1, Signals: []string{"automation_user_agent"}}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, ScraperAllow, guard.Check(automated, now).Action)
+	}
+	assert.Equal(t, ScraperDelay, guard.Check(automated, now).Action, "automated clients get half the limits")
+
+	report := guard.Activity(10, false, now.Add(time.Minute))
+	require.Len(t, report.Clients, 2)
+	assert.Equal(t, "client-1", report.Clients[0].Fingerprint, "blocked clients come first")
+	assert.Equal(t, int64(1), report.Clients[0].BlockedRequests)
+	assert.Equal(t, int64(4), report.Clients[0].DelayedRequests)
+	assert.Equal(t, []string{"automation_user_agent"}, report.Clients[1].Signals)
+	assert.Equal(t, int64(1), report.BlockedRequests)
+}
+
+func TestScraperGuard_PageTokens(t *testing.T) {
+	guard := NewScraperGuard(ScraperGuardConfig{SoftLimit: 1000, DeepPage: 3, PageTokenSecret: "secret", PageTokenTTL: time.Minute})
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+
+	deep := ScraperRequest{Fingerprint: "client-1", Page: 7}
+	assert.Equal(t, ScraperChallenge, guard.Check(deep, now).Action, "past twice the deep page every client needs a token")
+
+	deep.PageToken = guard.IssuePageToken("client-1", 7, now)
+	assert.Equal(t, ScraperAllow, guard.Check(deep, now).Action)
+
+	assert.Equal(t, ScraperChallenge, guard.Check(ScraperRequest{Fingerprint: "client-1", Page: 8, PageToken: deep.PageToken}, now).Action,
+		"tokens open a single page")
+	assert.Equal(t, ScraperChallenge, guard.Check(ScraperRequest{Fingerprint: "client-2", Page: 7, PageToken: deep.PageToken}, now).Action,
+		"tokens are bound to the client")
+	assert.Equal(t, ScraperChallenge, guard.Check(deep, now.Add(2*time.Minute)).Action, "tokens expire")
+	assert.Equal(t, ScraperAllow, guard.Check(ScraperRequest{Fingerprint: "client-1", Page: 3}, now).Action, "shallow pages need no token")
+
+	assert.NotEqual(t, guard.IssuePageToken("client-1", 7, now), guard.IssuePageToken("client-1", 7, now), "tokens are randomized")
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/properties", nil)
+	r.Header.Set("User-Agent", "Mozilla/5.0")
+	r.Header.Set("Accept-Language", "es-EC")
+
+	assert.Equal(t, RequestFingerprint("203.0.113.7"), RequestFingerprint("203.0.113.99"), "addresses of the same network share a fingerprint")
+	assert.NotEqual(t, RequestFingerprint("203.0.113.7"), RequestFingerprint("198.51.100.7"))
+	assert.Equal(t, RequestFingerprint("2001:db8:1::1"), RequestFingerprint("2001:db8:1:ffff::2"))
+	assert.Empty(t, ScraperSignals(r))
+
+	r.Header.Set("User-Agent", "Scrapy/2.11 (+https://scrapy.org)")
+	r.Header.Del("Accept-Language")
+	assert.Equal(t, []string{"automation_user_agent", "missing_accept_language"}, ScraperSignals(r))
+}
+
+func TestParsePage(t *testing.T) {
+	assert.Equal(t, 1, ParsePage(url.Values{}))
+	assert.Equal(t, 4, ParsePage(url.Values{"page": {"4"}}))
+	assert.Equal(t, 3, ParsePage(url.Values{"offset": {"40"}, "limit": {"20"}}))
+	assert.Equal(t, 1, ParsePage(url.Values{"page": {"-2"}}))
+}
@@ -148,6 +148,8 @@ func TestInputValidator_SanitizeInput(t *testing.T) {
 }
 
 func TestIPValidator_ValidateIP(t *testing.T) {
+	// Private and loopback ranges are only blocked outside development
+	t.Setenv("GO_ENV", "production")
 	validator := NewIPValidator()
 
 	testCases := []struct {
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// exportDealPageSize is how many deals an export reads at a time
+const exportDealPageSize = 500
+
+// AccountingExportService exports the ledger of an agency: the subscription and
+// featured listing fees invoiced by the platform and the commissions of its deals
+type AccountingExportService struct {
+	invoiceRepo repository.ElectronicInvoiceRepository
+	dealRepo    repository.DealRepository
+	accounts    domain.AccountingAccounts
+	now         func() time.Time
+	logger      *log.Logger
+}
+
+// NewAccountingExportService creates an accounting export service
+func NewAccountingExportService(invoiceRepo repository.ElectronicInvoiceRepository, dealRepo repository.DealRepository, logger *log.Logger) *AccountingExportService {
+	return &AccountingExportService{
+		invoiceRepo: invoiceRepo,
+		dealRepo:    dealRepo,
+		accounts:    domain.DefaultAccountingAccounts,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// ListTransactions returns the ledger of an agency from from to the day before to,
+// oldest first. The period defaults to the current year; invoices rejected by the SRI
+// are left out.
+func (s *AccountingExportService) ListTransactions(agencyID string, from, to *time.Time, actor domain.Actor) (*domain.AgencyLedger, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can export its transactions")
+	}
+
+	start, end := s.period(from, to)
+	if !end.After(start) {
+		return nil, fmt.Errorf("invalid period: to must be after from")
+	}
+	if end.Sub(start) > domain.MaxExportDays*24*time.Hour {
+		return nil, fmt.Errorf("invalid period: exports span at most %d days", domain.MaxExportDays)
+	}
+	ledger := &domain.AgencyLedger{AgencyID: agencyID, From: start, To: end, Transactions: []domain.AgencyTransaction{}}
+
+	invoices, err := s.invoiceRepo.ListByAgency(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, invoice := range invoices {
+		if invoice.Status == domain.InvoiceRejected || invoice.IssueDate.Before(start) || !invoice.IssueDate.Before(end) {
+			continue
+		}
+		ledger.Transactions = append(ledger.Transactions, domain.InvoiceTransaction(invoice))
+	}
+
+	filter := domain.DealFilter{AgencyID: agencyID, From: &start, To: &end, Limit: exportDealPageSize}
+	for {
+		deals, total, err := s.dealRepo.List(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, deal := range deals {
+			if deal.CommissionAmount > 0 {
+				ledger.Transactions = append(ledger.Transactions, domain.CommissionTransaction(deal))
+			}
+		}
+		filter.Offset += len(deals)
+		if len(deals) == 0 || filter.Offset >= total {
+			break
+		}
+	}
+
+	domain.SortTransactions(ledger.Transactions)
+	return ledger, nil
+}
+
+// ExportTransactions writes the ledger of an agency in an export format, returning the
+// file with a suggested name
+func (s *AccountingExportService) ExportTransactions(agencyID string, from, to *time.Time, format string, actor domain.Actor) ([]byte, string, error) {
+	if format == "" {
+		format = domain.ExportFormatCSV
+	}
+	if !domain.IsValidExportFormat(format) {
+		return nil, "", fmt.Errorf("invalid format: %s (use csv or contifico)", format)
+	}
+
+	ledger, err := s.ListTransactions(agencyID, from, to, actor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if format == domain.ExportFormatContifico {
+		err = domain.WriteContificoJournal(&buf, ledger.Transactions, s.accounts)
+	} else {
+		err = domain.WriteTransactionsCSV(&buf, ledger.Transactions)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to write export: %w", err)
+	}
+
+	s.logger.Printf("Agency %s exported %d transactions as %s by %s", agencyID, len(ledger.Transactions), format, actor.UserID)
+	return buf.Bytes(), ledger.FileName(format), nil
+}
+
+// period resolves the exported period: the current year by default, up to today
+func (s *AccountingExportService) period(from, to *time.Time) (time.Time, time.Time) {
+	today := domain.CalendarDate(s.now())
+	start := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := today.AddDate(0, 0, 1)
+	if from != nil {
+		start = domain.CalendarDate(*from)
+	}
+	if to != nil {
+		end = domain.CalendarDate(*to)
+	}
+	return start, end
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestAccountingExportService_ExportTransactions(t *testing.T) {
+	agencyID := "agency-1"
+	otherAgency := "agency-2"
+	invoices := &memoryInvoices{invoices: map[string]domain.ElectronicInvoice{
+		"inv-1": {ID: "inv-1", AgencyID: agencyID, Source: domain.InvoiceSourceSubscription, Establishment: "001", EmissionPoint: "001", Sequential: 1,
+			IssueDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Status: domain.InvoiceAuthorized, Subtotal: 49, IVA: 7.35, Total: 56.35,
+			Lines: []domain.InvoiceLine{{Description: "Suscripción mensual plan pro"}}},
+		"inv-2": {ID: "inv-2", AgencyID: agencyID, Source: domain.InvoiceSourceFeatured, Sequential: 2,
+			IssueDate: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Status: domain.InvoiceRejected, Total: 16.1},
+		"inv-3": {ID: "inv-3", AgencyID: agencyID, Source: domain.InvoiceSourceFeatured, Sequential: 3,
+			IssueDate: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), Status: domain.InvoiceAuthorized, Total: 16.1},
+	}}
+	deals := &memoryDealRepository{deals: []domain.Deal{
+		{ID: "deal-1", AgencyID: &agencyID, PropertyID: "prop-1", Type: domain.DealTypeRent, FinalPrice: 800, CommissionPercent: 50, CommissionAmount: 400,
+			ClosingDate: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: "deal-2", AgencyID: &agencyID, PropertyID: "prop-2", Type: domain.DealTypeSale, FinalPrice: 90000,
+			ClosingDate: time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: "deal-3", AgencyID: &otherAgency, PropertyID: "prop-3", Type: domain.DealTypeSale, FinalPrice: 90000, CommissionAmount: 2700,
+			ClosingDate: time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	service := NewAccountingExportService(invoices, deals, log.New(&bytes.Buffer{}, "", 0))
+	service.now = func() time.Time { return time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC) }
+	admin := domain.NewActor("agency-user", "agency", agencyID)
+
+	// The current year by default, without rejected invoices or deals without commission
+	ledger, err := service.ListTransactions(agencyID, nil, nil, admin)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ledger.From)
+	require.Len(t, ledger.Transactions, 2)
+	assert.Equal(t, "deal-1", ledger.Transactions[0].Reference)
+	assert.Equal(t, domain.TransactionIncome, ledger.Transactions[0].Direction)
+	assert.Equal(t, domain.TransactionSubscription, ledger.Transactions[1].Kind)
+
+	data, fileName, err := service.ExportTransactions(agencyID, nil, nil, domain.ExportFormatContifico, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "transacciones-2025-01-01-2025-09-08-contifico.csv", fileName)
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ';'
+	rows, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 6) // header, 2 rows for the commission, 3 for the invoice
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	data, fileName, err = service.ExportTransactions(agencyID, &from, &to, "", admin)
+	require.NoError(t, err)
+	assert.Equal(t, "transacciones-2025-03-01-2025-03-01.csv", fileName)
+	rows, err = csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	_, _, err = service.ExportTransactions(agencyID, nil, nil, "xlsx", admin)
+	assert.Contains(t, err.Error(), "invalid format")
+	_, _, err = service.ExportTransactions(agencyID, &to, &from, "", admin)
+	assert.Contains(t, err.Error(), "invalid period")
+	_, _, err = service.ExportTransactions(agencyID, nil, nil, "", domain.NewActor("other", "agency", otherAgency))
+	assert.Contains(t, err.Error(), "permission denied")
+}
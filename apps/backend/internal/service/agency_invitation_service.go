@@ -0,0 +1,214 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// InvitationNotifier delivers agency invitations to the invited email
+type InvitationNotifier interface {
+	SendAgencyInvitation(invitation *domain.AgencyInvitation, agency *domain.Agency, acceptURL string) error
+}
+
+// LogInvitationNotifier writes invitation links to the log. It is used until an email
+// provider is configured.
+type LogInvitationNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogInvitationNotifier creates a notifier that logs invitation links
+func NewLogInvitationNotifier(logger *log.Logger) *LogInvitationNotifier {
+	return &LogInvitationNotifier{logger: logger}
+}
+
+// SendAgencyInvitation logs the invitation link
+func (n *LogInvitationNotifier) SendAgencyInvitation(invitation *domain.AgencyInvitation, agency *domain.Agency, acceptURL string) error {
+	n.logger.Printf("Invitation to join %s for %s: %s", agency.Name, invitation.Email, acceptURL)
+	return nil
+}
+
+// AgencyInvitationService handles agent invitations to agencies
+type AgencyInvitationService struct {
+	invitationRepo repository.AgencyInvitationRepository
+	agencyRepo     *repository.AgencyRepository
+	userRepo       *repository.UserRepository
+	notifier       InvitationNotifier
+	ttl            time.Duration
+	acceptURL      string
+	now            func() time.Time
+	logger         *log.Logger
+}
+
+// NewAgencyInvitationService creates a new invitation service. acceptURL is the page
+// that receives the invitation token, e.g. https://app.example.com/invitations/accept
+func NewAgencyInvitationService(
+	invitationRepo repository.AgencyInvitationRepository,
+	agencyRepo *repository.AgencyRepository,
+	userRepo *repository.UserRepository,
+	notifier InvitationNotifier,
+	ttl time.Duration,
+	acceptURL string,
+	logger *log.Logger,
+) *AgencyInvitationService {
+	if notifier == nil {
+		notifier = NewLogInvitationNotifier(logger)
+	}
+	if ttl <= 0 {
+		ttl = domain.DefaultInvitationTTL
+	}
+
+	return &AgencyInvitationService{
+		invitationRepo: invitationRepo,
+		agencyRepo:     agencyRepo,
+		userRepo:       userRepo,
+		notifier:       notifier,
+		ttl:            ttl,
+		acceptURL:      acceptURL,
+		now:            time.Now,
+		logger:         logger,
+	}
+}
+
+// InviteAgent creates an invitation for an email to join an agency as an agent and sends it
+func (s *AgencyInvitationService) InviteAgent(agencyID, email, invitedBy string) (*domain.AgencyInvitation, error) {
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return nil, fmt.Errorf("agency not found: %w", err)
+	}
+	if !agency.Active {
+		return nil, fmt.Errorf("agency is not active")
+	}
+
+	invitation, token, err := domain.NewAgencyInvitation(agencyID, email, invitedBy, s.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation: %w", err)
+	}
+
+	if user, _ := s.userRepo.GetByEmail(invitation.Email); user != nil && user.AgencyID != nil && *user.AgencyID == agencyID {
+		return nil, fmt.Errorf("user is already a member of the agency")
+	}
+
+	// A new invitation replaces an open one, so resending issues a fresh token
+	if existing, _ := s.invitationRepo.GetPendingByEmail(agencyID, invitation.Email); existing != nil {
+		if err := existing.Revoke(s.now()); err == nil {
+			if err := s.invitationRepo.Update(existing); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.SendAgencyInvitation(invitation, agency, s.buildAcceptURL(token)); err != nil {
+		s.logger.Printf("Error sending invitation %s: %v", invitation.ID, err)
+	}
+
+	s.logger.Printf("Agent invited to agency %s: %s", agency.Name, invitation.Email)
+	return invitation, nil
+}
+
+// ListInvitations lists the invitations of an agency, optionally filtered by status
+func (s *AgencyInvitationService) ListInvitations(agencyID, status string) ([]domain.AgencyInvitation, error) {
+	if status != "" && !domain.IsValidInvitationStatus(status) {
+		return nil, fmt.Errorf("invalid invitation status: %s", status)
+	}
+
+	if _, err := s.invitationRepo.ExpirePending(s.now()); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.ListByAgency(agencyID, status)
+}
+
+// RevokeInvitation cancels a pending invitation of an agency
+func (s *AgencyInvitationService) RevokeInvitation(agencyID, invitationID string) error {
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.AgencyID != agencyID {
+		return fmt.Errorf("invitation not found")
+	}
+
+	if err := invitation.Revoke(s.now()); err != nil {
+		return fmt.Errorf("cannot revoke: %w", err)
+	}
+
+	return s.invitationRepo.Update(invitation)
+}
+
+// AcceptInvitation links a user to the inviting agency as an agent. The user must be
+// signed in with the invited email.
+func (s *AgencyInvitationService) AcceptInvitation(token, userID string) (*domain.AgencyInvitation, error) {
+	if token == "" {
+		return nil, fmt.Errorf("invitation token is required")
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(domain.HashInvitationToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if invitation.IsExpired(now) {
+		invitation.RefreshStatus(now)
+		if err := s.invitationRepo.Update(invitation); err != nil {
+			s.logger.Printf("Error expiring invitation %s: %v", invitation.ID, err)
+		}
+		return nil, fmt.Errorf("invitation is expired")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, fmt.Errorf("invitation was sent to a different email")
+	}
+
+	agency, err := s.agencyRepo.GetByID(invitation.AgencyID)
+	if err != nil {
+		return nil, fmt.Errorf("agency not found: %w", err)
+	}
+	if !agency.Active {
+		return nil, fmt.Errorf("agency is not active")
+	}
+
+	if err := invitation.Accept(userID, now); err != nil {
+		return nil, err
+	}
+	if err := user.JoinAgency(invitation.AgencyID); err != nil {
+		return nil, fmt.Errorf("cannot join agency: %w", err)
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("User %s joined agency %s as agent", user.Email, agency.Name)
+	return invitation, nil
+}
+
+// buildAcceptURL adds the token to the acceptance page URL
+func (s *AgencyInvitationService) buildAcceptURL(token string) string {
+	if s.acceptURL == "" {
+		return token
+	}
+
+	separator := "?"
+	if strings.Contains(s.acceptURL, "?") {
+		separator = "&"
+	}
+	return s.acceptURL + separator + "token=" + url.QueryEscape(token)
+}
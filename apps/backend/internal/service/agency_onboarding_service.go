@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// OnboardingAgencies is the part of the agency repository onboarding uses
+type OnboardingAgencies interface {
+	GetByID(id string) (*domain.Agency, error)
+}
+
+// AgencyOnboardingService computes the setup checklist of new agencies from their
+// profile and activity, so the frontend can guide them through it
+type AgencyOnboardingService struct {
+	repo     repository.AgencyOnboardingRepository
+	agencies OnboardingAgencies
+}
+
+// NewAgencyOnboardingService creates an agency onboarding service
+func NewAgencyOnboardingService(repo repository.AgencyOnboardingRepository, agencies OnboardingAgencies) *AgencyOnboardingService {
+	return &AgencyOnboardingService{
+		repo:     repo,
+		agencies: agencies,
+	}
+}
+
+// GetOnboarding returns the setup checklist of an agency. The agency account and admins
+// can read it.
+func (s *AgencyOnboardingService) GetOnboarding(agencyID string, actor domain.Actor) (*domain.AgencyOnboarding, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can view its onboarding")
+	}
+
+	agency, err := s.agencies.GetByID(agencyID)
+	if err != nil {
+		return nil, fmt.Errorf("agency not found: %w", err)
+	}
+
+	activity, err := s.repo.GetAgencyActivity(agency.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewAgencyOnboarding(agency, *activity), nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// fakeAgencyOnboardingRepository returns fixed agency activity
+type fakeAgencyOnboardingRepository struct {
+	activity domain.AgencyActivity
+}
+
+func (r *fakeAgencyOnboardingRepository) GetAgencyActivity(agencyID string) (*domain.AgencyActivity, error) {
+	activity := r.activity
+	return &activity, nil
+}
+
+func TestAgencyOnboardingService_GetOnboarding(t *testing.T) {
+	agencies := &memoryProfileImageAgencies{agencies: map[string]*domain.Agency{
+		"agency-1": {ID: "agency-1", LicenseNumber: "1790012345001"},
+	}}
+	repo := &fakeAgencyOnboardingRepository{activity: domain.AgencyActivity{InvitationsSent: 2, Plan: domain.PlanFree}}
+	svc := NewAgencyOnboardingService(repo, agencies)
+
+	onboarding, err := svc.GetOnboarding("agency-1", domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, onboarding.CompletedSteps)
+	assert.Equal(t, domain.OnboardingStepLogo, onboarding.NextStep)
+
+	_, err = svc.GetOnboarding("agency-1", domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = svc.GetOnboarding("missing", domain.NewActor("admin-1", string(domain.RoleAdmin), ""))
+	assert.ErrorContains(t, err, "not found")
+}
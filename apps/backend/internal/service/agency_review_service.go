@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// AgencyReviewService manages the reviews and reputation of agencies. Clients of a
+// deal closed by the agency and applicants whose rental application the agency
+// decided can review it; the reputation is recomputed whenever its reviews change.
+type AgencyReviewService struct {
+	reviews         *ReviewService
+	reviewRepo      repository.ReviewRepository
+	agencies        AgencyLookup
+	dealRepo        repository.DealRepository
+	applicationRepo repository.RentalApplicationRepository
+	propertyRepo    repository.PropertyRepository
+	logger          *log.Logger
+}
+
+// NewAgencyReviewService creates a new agency review service
+func NewAgencyReviewService(
+	reviews *ReviewService,
+	agencies AgencyLookup,
+	dealRepo repository.DealRepository,
+	applicationRepo repository.RentalApplicationRepository,
+	propertyRepo repository.PropertyRepository,
+	logger *log.Logger,
+) *AgencyReviewService {
+	return &AgencyReviewService{
+		reviews:         reviews,
+		reviewRepo:      reviews.reviewRepo,
+		agencies:        agencies,
+		dealRepo:        dealRepo,
+		applicationRepo: applicationRepo,
+		propertyRepo:    propertyRepo,
+		logger:          logger,
+	}
+}
+
+// GetReputation returns the reputation of an active agency. Agencies without
+// published reviews have the prior score.
+func (s *AgencyReviewService) GetReputation(agencyID string) (*domain.Reputation, error) {
+	if _, err := s.activeAgency(agencyID); err != nil {
+		return nil, err
+	}
+
+	reputation, err := s.reviewRepo.GetReputation(domain.ReviewSubjectAgency, agencyID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		reputation = domain.NewReputation(domain.ReviewSubjectAgency, agencyID, domain.ReviewSummary{}, s.reviews.now())
+	}
+	return reputation, nil
+}
+
+// ListReviews returns a page of the published reviews of an agency with the total count
+func (s *AgencyReviewService) ListReviews(agencyID string, limit, offset int) ([]domain.Review, int, error) {
+	if _, err := s.activeAgency(agencyID); err != nil {
+		return nil, 0, err
+	}
+
+	limit, offset = reviewPage(limit, offset)
+	return s.reviewRepo.List(domain.ReviewFilter{
+		SubjectType: domain.ReviewSubjectAgency,
+		SubjectID:   agencyID,
+		Status:      domain.ReviewPublished,
+		Limit:       limit,
+		Offset:      offset,
+	})
+}
+
+// SubmitReview creates or replaces the actor's review of an agency. Edited reviews go
+// back to moderation, so the reputation is refreshed in case a published review
+// was withdrawn from it.
+func (s *AgencyReviewService) SubmitReview(agencyID string, req SubmitReviewRequest, actor domain.Actor) (*domain.Review, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to review agencies")
+	}
+	if actor.AgencyID == agencyID || actor.UserID == agencyID {
+		return nil, fmt.Errorf("permission denied: agencies cannot be reviewed by their own members")
+	}
+	if _, err := s.activeAgency(agencyID); err != nil {
+		return nil, err
+	}
+
+	dealID, applicationID, err := s.verifyReviewer(agencyID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.reviewRepo.GetByReviewer(domain.ReviewSubjectAgency, agencyID, actor.UserID)
+	switch {
+	case err == nil:
+		if err := review.Edit(req.Rating, req.Comment); err != nil {
+			return nil, err
+		}
+		review.DealID, review.ApplicationID = dealID, applicationID
+		if err := s.reviewRepo.Update(review); err != nil {
+			return nil, err
+		}
+		s.reviews.refreshReputation(domain.ReviewSubjectAgency, agencyID)
+		return review, nil
+	case !strings.Contains(err.Error(), "not found"):
+		return nil, err
+	}
+
+	review, err = domain.NewReview(domain.ReviewSubjectAgency, agencyID, actor.UserID, req.Rating, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+	review.DealID, review.ApplicationID = dealID, applicationID
+	if err := s.reviewRepo.Create(review); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Review %s of agency %s submitted by %s", review.ID, agencyID, actor.UserID)
+	return review, nil
+}
+
+// verifyReviewer returns the deal or the decided rental application that entitles a
+// user to review an agency
+func (s *AgencyReviewService) verifyReviewer(agencyID, userID string) (*string, *string, error) {
+	deals, _, err := s.dealRepo.List(domain.DealFilter{AgencyID: agencyID, ClientID: userID, Limit: 1})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(deals) > 0 {
+		return &deals[0].ID, nil, nil
+	}
+
+	applications, err := s.applicationRepo.ListByApplicant(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, application := range applications {
+		if application.Status != domain.ApplicationStatusAccepted && application.Status != domain.ApplicationStatusRejected {
+			continue
+		}
+		property, err := s.propertyRepo.GetByID(application.PropertyID)
+		if err != nil {
+			s.logger.Printf("Error loading property %s of application %s: %v", application.PropertyID, application.ID, err)
+			continue
+		}
+		if property.AgencyID != nil && *property.AgencyID == agencyID {
+			return nil, &application.ID, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("permission denied: only clients of a deal or a rental application handled by this agency can review it")
+}
+
+// activeAgency returns an agency whose reviews are public
+func (s *AgencyReviewService) activeAgency(agencyID string) (*domain.Agency, error) {
+	agency, err := s.agencies.GetByID(agencyID)
+	if err != nil || agency == nil || !agency.IsActive() {
+		return nil, fmt.Errorf("agency not found: %s", agencyID)
+	}
+	return agency, nil
+}
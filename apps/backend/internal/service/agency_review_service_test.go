@@ -0,0 +1,83 @@
+package service
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func newTestAgencyReviewService(t *testing.T) (*AgencyReviewService, *ReviewService) {
+	t.Helper()
+	agencyID := "agency-1"
+	clientID := "client-1"
+	agencies := memoryAgencies{
+		"agency-1": {ID: "agency-1", Name: "Costa Inmobiliaria", Status: domain.AgencyStatusActive},
+		"agency-2": {ID: "agency-2", Name: "Sierra Propiedades", Status: domain.AgencyStatusSuspended},
+	}
+	deals := &memoryDealRepository{deals: []domain.Deal{
+		{ID: "deal-1", AgencyID: &agencyID, ClientID: &clientID, Type: domain.DealTypeSale},
+	}}
+	applications := &memoryApplicationRepository{applications: map[string]domain.RentalApplication{
+		"app-1": {ID: "app-1", PropertyID: "prop-1", ApplicantID: "tenant-1", Status: domain.ApplicationStatusRejected},
+		"app-2": {ID: "app-2", PropertyID: "prop-1", ApplicantID: "tenant-2", Status: domain.ApplicationStatusSubmitted},
+	}}
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(&domain.Property{ID: "prop-1", AgencyID: &agencyID}, nil)
+
+	logger := log.New(os.Stdout, "", 0)
+	reviews := NewReviewService(newMemoryReviewRepository(), logger)
+	return NewAgencyReviewService(reviews, agencies, deals, applications, propertyRepo, logger), reviews
+}
+
+func TestAgencyReviewService_ReviewFlow(t *testing.T) {
+	service, moderation := newTestAgencyReviewService(t)
+	admin := domain.NewActor("admin-1", "admin", "")
+
+	_, err := service.SubmitReview("agency-1", SubmitReviewRequest{Rating: 5}, domain.NewActor("tenant-2", "buyer", ""))
+	assert.ErrorContains(t, err, "permission denied", "applications awaiting a decision do not verify the reviewer")
+	_, err = service.SubmitReview("agency-1", SubmitReviewRequest{Rating: 5}, domain.NewActor("agent-1", "agent", "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = service.SubmitReview("agency-2", SubmitReviewRequest{Rating: 5}, domain.NewActor("client-1", "buyer", ""))
+	assert.ErrorContains(t, err, "agency not found")
+
+	byClient, err := service.SubmitReview("agency-1", SubmitReviewRequest{Rating: 5, Comment: "Excelente"}, domain.NewActor("client-1", "buyer", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "deal-1", *byClient.DealID)
+	byTenant, err := service.SubmitReview("agency-1", SubmitReviewRequest{Rating: 2}, domain.NewActor("tenant-1", "buyer", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "app-1", *byTenant.ApplicationID)
+
+	reputation, err := service.GetReputation("agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, reputation.ReviewCount)
+	assert.Equal(t, 50.0, reputation.Score, "agencies without published reviews score the prior")
+
+	_, err = moderation.ModerateReview(byClient.ID, true, "", admin)
+	require.NoError(t, err)
+	_, err = moderation.ModerateReview(byTenant.ID, true, "", admin)
+	require.NoError(t, err)
+
+	reviews, total, err := service.ListReviews("agency-1", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, reviews, 2)
+
+	reputation, err = service.GetReputation("agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, reputation.ReviewCount)
+	assert.Equal(t, 3.5, reputation.AverageRating)
+	assert.Equal(t, 53.6, reputation.Score)
+
+	// Editing withdraws the review from the reputation until it is moderated again
+	_, err = service.SubmitReview("agency-1", SubmitReviewRequest{Rating: 1}, domain.NewActor("tenant-1", "buyer", ""))
+	require.NoError(t, err)
+	reputation, err = service.GetReputation("agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, reputation.ReviewCount)
+	assert.Equal(t, 5.0, reputation.AverageRating)
+}
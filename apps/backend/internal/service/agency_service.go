@@ -13,6 +13,7 @@ import (
 type AgencyService struct {
 	agencyRepo *repository.AgencyRepository
 	userRepo   *repository.UserRepository
+	plans      *SubscriptionService
 	logger     *log.Logger
 }
 
@@ -25,6 +26,24 @@ func NewAgencyService(agencyRepo *repository.AgencyRepository, userRepo *reposit
 	}
 }
 
+// SetSubscriptionService includes the agency's plan and usage in performance reports
+func (s *AgencyService) SetSubscriptionService(plans *SubscriptionService) {
+	s.plans = plans
+}
+
+// ForTenant returns the service scoped to a tenant: it finds and changes the agencies
+// and agents of the tenant only and creates new agencies in it. An empty tenant returns
+// the service itself.
+func (s *AgencyService) ForTenant(tenantID string) *AgencyService {
+	if tenantID == "" {
+		return s
+	}
+	scoped := *s
+	scoped.agencyRepo = s.agencyRepo.ForTenant(tenantID)
+	scoped.userRepo = s.userRepo.ForTenant(tenantID)
+	return &scoped
+}
+
 // CreateAgency creates a new agency with validation
 func (s *AgencyService) CreateAgency(name, ruc, address, phone, email, licenseNumber string) (*domain.Agency, error) {
 	// Validate basic data
@@ -208,6 +227,15 @@ func (s *AgencyService) GetAgencyPerformance(agencyID string) (*domain.AgencyPer
 		return nil, fmt.Errorf("failed to get agency performance: %w", err)
 	}
 
+	if s.plans != nil {
+		subscription, err := s.plans.GetAgencySubscription(agencyID)
+		if err != nil {
+			s.logger.Printf("Error getting subscription of agency %s: %v", agencyID, err)
+		} else {
+			performance.Subscription = subscription
+		}
+	}
+
 	return performance, nil
 }
 
@@ -517,4 +545,48 @@ func (s *AgencyService) SetLicenseNumber(agencyID, licenseNumber string) error {
 
 	s.logger.Printf("License number updated for agency: %s", agency.Name)
 	return nil
-}
\ No newline at end of file
+}
+// RemoveAgencyMember removes an agent from an agency. The agent's properties are
+// reassigned to another agent of the agency when reassignTo is set, otherwise they stay
+// with the agency without an assigned agent. Returns the number of reassigned properties.
+func (s *AgencyService) RemoveAgencyMember(agencyID, agentID, reassignTo string) (int64, error) {
+	if agencyID == "" || agentID == "" {
+		return 0, fmt.Errorf("agency ID and agent ID are required")
+	}
+
+	agency, err := s.agencyRepo.GetByID(agencyID)
+	if err != nil {
+		return 0, fmt.Errorf("agency not found: %w", err)
+	}
+
+	agent, err := s.userRepo.GetByID(agentID)
+	if err != nil {
+		return 0, fmt.Errorf("agent not found: %w", err)
+	}
+	if agent.AgencyID == nil || *agent.AgencyID != agencyID {
+		return 0, fmt.Errorf("agent not found in agency: %s", agentID)
+	}
+
+	var target *string
+	if reassignTo != "" {
+		if reassignTo == agentID {
+			return 0, fmt.Errorf("cannot reassign properties to the removed agent")
+		}
+		newAgent, err := s.userRepo.GetByID(reassignTo)
+		if err != nil {
+			return 0, fmt.Errorf("reassignment agent not found: %w", err)
+		}
+		if !isActiveAgentOf(newAgent, agencyID) {
+			return 0, fmt.Errorf("reassignment agent must be an active agent of the agency")
+		}
+		target = &reassignTo
+	}
+
+	reassigned, err := s.agencyRepo.RemoveAgent(agencyID, agentID, target)
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Printf("Agent %s removed from agency %s (%d properties reassigned)", agent.Name(), agency.Name, reassigned)
+	return reassigned, nil
+}
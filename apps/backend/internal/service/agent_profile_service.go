@@ -0,0 +1,229 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// Agent profile limits
+const (
+	AgentProfileListings      = 12
+	AgentProfileRecentReviews = 5
+	DefaultReviewPageSize     = 20
+	MaxReviewPageSize         = 100
+)
+
+// UserLookup retrieves users, e.g. repository.UserRepository
+type UserLookup interface {
+	GetByID(id string) (*domain.User, error)
+}
+
+// AgencyLookup retrieves agencies, e.g. repository.AgencyRepository
+type AgencyLookup interface {
+	GetByID(id string) (*domain.Agency, error)
+}
+
+// SubmitReviewRequest holds the rating and comment of a review
+type SubmitReviewRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// AgentProfileService builds the public profile pages of agents and manages their
+// reviews. Only clients with a closed deal with the agent can review them, and
+// reviews are published once an administrator approves them through ReviewService.
+type AgentProfileService struct {
+	reviewRepo   repository.ReviewRepository
+	users        UserLookup
+	agencies     AgencyLookup
+	propertyRepo repository.PropertyRepository
+	dealRepo     repository.DealRepository
+	logger       *log.Logger
+}
+
+// NewAgentProfileService creates a new agent profile service
+func NewAgentProfileService(
+	reviewRepo repository.ReviewRepository,
+	users UserLookup,
+	agencies AgencyLookup,
+	propertyRepo repository.PropertyRepository,
+	dealRepo repository.DealRepository,
+	logger *log.Logger,
+) *AgentProfileService {
+	return &AgentProfileService{
+		reviewRepo:   reviewRepo,
+		users:        users,
+		agencies:     agencies,
+		propertyRepo: propertyRepo,
+		dealRepo:     dealRepo,
+		logger:       logger,
+	}
+}
+
+// GetProfile returns the public profile of an active agent with their agency, active
+// listings, deal record and published reviews
+func (s *AgentProfileService) GetProfile(agentID string) (*domain.AgentProfile, error) {
+	agent, err := s.activeAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &domain.AgentProfile{
+		AgentID:   agent.ID,
+		FirstName: agent.FirstName,
+		LastName:  agent.LastName,
+		Bio:       agent.Bio,
+		AvatarURL: agent.AvatarURL,
+		Stats:     domain.AgentProfileStats{MemberSince: agent.CreatedAt},
+	}
+
+	if agent.AgencyID != nil {
+		if agency, err := s.agencies.GetByID(*agent.AgencyID); err == nil {
+			logo := agency.LogoURL
+			if logo == nil {
+				logo = agency.Logo
+			}
+			profile.Agency = &domain.AgentProfileAgency{ID: agency.ID, Name: agency.Name, LogoURL: logo}
+		} else {
+			s.logger.Printf("Error loading agency %s of agent %s: %v", *agent.AgencyID, agent.ID, err)
+		}
+	}
+
+	filters := &domain.PropertySearchFilters{AgentID: &agent.ID, Status: []string{domain.StatusAvailable}}
+	pagination := domain.NewPaginationParams()
+	pagination.PageSize = AgentProfileListings
+	listings, total, err := s.propertyRepo.GetByFiltersPaginated(filters, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agent listings: %w", err)
+	}
+	profile.Listings = listings
+	profile.ActiveListings = total
+
+	if profile.Stats.Sales, err = s.countDeals(domain.DealFilter{AgentID: agent.ID, Type: domain.DealTypeSale}); err != nil {
+		return nil, err
+	}
+	if profile.Stats.Rentals, err = s.countDeals(domain.DealFilter{AgentID: agent.ID, Type: domain.DealTypeRent}); err != nil {
+		return nil, err
+	}
+	profile.Stats.DealsClosed = profile.Stats.Sales + profile.Stats.Rentals
+
+	summary, err := s.reviewRepo.Summary(domain.ReviewSubjectAgent, agent.ID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Reviews = *summary
+
+	profile.RecentReviews, _, err = s.reviewRepo.List(domain.ReviewFilter{
+		SubjectType: domain.ReviewSubjectAgent,
+		SubjectID:   agent.ID,
+		Status:      domain.ReviewPublished,
+		Limit:       AgentProfileRecentReviews,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// ListReviews returns a page of the published reviews of an agent with the total count
+func (s *AgentProfileService) ListReviews(agentID string, limit, offset int) ([]domain.Review, int, error) {
+	if _, err := s.activeAgent(agentID); err != nil {
+		return nil, 0, err
+	}
+
+	limit, offset = reviewPage(limit, offset)
+	return s.reviewRepo.List(domain.ReviewFilter{
+		SubjectType: domain.ReviewSubjectAgent,
+		SubjectID:   agentID,
+		Status:      domain.ReviewPublished,
+		Limit:       limit,
+		Offset:      offset,
+	})
+}
+
+// SubmitReview creates or replaces the actor's review of an agent. The actor must be
+// the client of a deal the agent closed; edited reviews go back to moderation.
+func (s *AgentProfileService) SubmitReview(agentID string, req SubmitReviewRequest, actor domain.Actor) (*domain.Review, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to review agents")
+	}
+	if actor.UserID == agentID {
+		return nil, fmt.Errorf("permission denied: agents cannot review themselves")
+	}
+	if _, err := s.activeAgent(agentID); err != nil {
+		return nil, err
+	}
+
+	deals, _, err := s.dealRepo.List(domain.DealFilter{AgentID: agentID, ClientID: actor.UserID, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(deals) == 0 {
+		return nil, fmt.Errorf("permission denied: only clients of a deal closed by this agent can review them")
+	}
+
+	review, err := s.reviewRepo.GetByReviewer(domain.ReviewSubjectAgent, agentID, actor.UserID)
+	switch {
+	case err == nil:
+		if err := review.Edit(req.Rating, req.Comment); err != nil {
+			return nil, err
+		}
+		review.DealID = &deals[0].ID
+		if err := s.reviewRepo.Update(review); err != nil {
+			return nil, err
+		}
+		return review, nil
+	case !strings.Contains(err.Error(), "not found"):
+		return nil, err
+	}
+
+	review, err = domain.NewReview(domain.ReviewSubjectAgent, agentID, actor.UserID, req.Rating, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+	review.DealID = &deals[0].ID
+	if err := s.reviewRepo.Create(review); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Review %s of agent %s submitted by %s", review.ID, agentID, actor.UserID)
+	return review, nil
+}
+
+// activeAgent returns an agent whose profile is public
+func (s *AgentProfileService) activeAgent(agentID string) (*domain.User, error) {
+	agent, err := s.users.GetByID(agentID)
+	if err != nil || agent == nil || !agent.IsAgent() || !agent.Active {
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+	return agent, nil
+}
+
+// countDeals returns how many deals match a filter
+func (s *AgentProfileService) countDeals(filter domain.DealFilter) (int, error) {
+	filter.Limit = 1
+	_, total, err := s.dealRepo.List(filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count agent deals: %w", err)
+	}
+	return total, nil
+}
+
+// reviewPage applies the default and maximum review page sizes
+func reviewPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultReviewPageSize
+	}
+	if limit > MaxReviewPageSize {
+		limit = MaxReviewPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+type memoryUsers map[string]*domain.User
+
+func (u memoryUsers) GetByID(id string) (*domain.User, error) {
+	if user, ok := u[id]; ok {
+		return user, nil
+	}
+	return nil, fmt.Errorf("user not found: %s", id)
+}
+
+type memoryAgencies map[string]*domain.Agency
+
+func (a memoryAgencies) GetByID(id string) (*domain.Agency, error) {
+	if agency, ok := a[id]; ok {
+		return agency, nil
+	}
+	return nil, fmt.Errorf("agency not found: %s", id)
+}
+
+type memoryDealRepository struct {
+	deals []domain.Deal
+}
+
+func (r *memoryDealRepository) Create(deal *domain.Deal) error {
+	r.deals = append(r.deals, *deal)
+	return nil
+}
+
+func (r *memoryDealRepository) GetByID(id string) (*domain.Deal, error) {
+	for _, deal := range r.deals {
+		if deal.ID == id {
+			return &deal, nil
+		}
+	}
+	return nil, fmt.Errorf("deal not found: %s", id)
+}
+
+func (r *memoryDealRepository) List(filter domain.DealFilter) ([]domain.Deal, int, error) {
+	matches := []domain.Deal{}
+	for _, deal := range r.deals {
+		if filter.AgencyID != "" && (deal.AgencyID == nil || *deal.AgencyID != filter.AgencyID) {
+			continue
+		}
+		if filter.AgentID != "" && (deal.AgentID == nil || *deal.AgentID != filter.AgentID) {
+			continue
+		}
+		if filter.ClientID != "" && (deal.ClientID == nil || *deal.ClientID != filter.ClientID) {
+			continue
+		}
+		if filter.Type != "" && deal.Type != filter.Type {
+			continue
+		}
+		if (filter.From != nil && deal.ClosingDate.Before(*filter.From)) || (filter.To != nil && !deal.ClosingDate.Before(*filter.To)) {
+			continue
+		}
+		matches = append(matches, deal)
+	}
+	total := len(matches)
+	matches = matches[min(filter.Offset, total):]
+	if filter.Limit > 0 && len(matches) > filter.Limit {
+		matches = matches[:filter.Limit]
+	}
+	return matches, total, nil
+}
+
+func (r *memoryDealRepository) GetAgencySummary(agencyID string, from, to *time.Time) (*domain.DealSummary, error) {
+	return &domain.DealSummary{}, nil
+}
+
+type memoryReviewRepository struct {
+	reviews     map[string]*domain.Review
+	reports     map[string]*domain.ReviewReport
+	reputations map[string]*domain.Reputation
+}
+
+func newMemoryReviewRepository() *memoryReviewRepository {
+	return &memoryReviewRepository{
+		reviews:     map[string]*domain.Review{},
+		reports:     map[string]*domain.ReviewReport{},
+		reputations: map[string]*domain.Reputation{},
+	}
+}
+
+func (r *memoryReviewRepository) Create(review *domain.Review) error {
+	copied := *review
+	r.reviews[review.ID] = &copied
+	return nil
+}
+
+func (r *memoryReviewRepository) Update(review *domain.Review) error {
+	return r.Create(review)
+}
+
+func (r *memoryReviewRepository) GetByID(id string) (*domain.Review, error) {
+	if review, ok := r.reviews[id]; ok {
+		copied := *review
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("review not found: %s", id)
+}
+
+func (r *memoryReviewRepository) GetByReviewer(subjectType, subjectID, reviewerID string) (*domain.Review, error) {
+	for _, review := range r.reviews {
+		if review.SubjectType == subjectType && review.SubjectID == subjectID && review.ReviewerID == reviewerID {
+			copied := *review
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("review not found: %s %s by %s", subjectType, subjectID, reviewerID)
+}
+
+func (r *memoryReviewRepository) List(filter domain.ReviewFilter) ([]domain.Review, int, error) {
+	reviews := []domain.Review{}
+	for _, review := range r.reviews {
+		if (filter.SubjectID == "" || review.SubjectID == filter.SubjectID) && (filter.Status == "" || review.Status == filter.Status) {
+			reviews = append(reviews, *review)
+		}
+	}
+	return reviews, len(reviews), nil
+}
+
+func (r *memoryReviewRepository) Summary(subjectType, subjectID string) (*domain.ReviewSummary, error) {
+	summary := &domain.ReviewSummary{Distribution: map[int]int{}}
+	total := 0
+	for _, review := range r.reviews {
+		if review.SubjectType == subjectType && review.SubjectID == subjectID && review.Status == domain.ReviewPublished {
+			summary.Count++
+			summary.Distribution[review.Rating]++
+			total += review.Rating
+		}
+	}
+	if summary.Count > 0 {
+		summary.AverageRating = float64(total) / float64(summary.Count)
+	}
+	return summary, nil
+}
+
+func (r *memoryReviewRepository) Delete(id string) error {
+	delete(r.reviews, id)
+	return nil
+}
+
+func (r *memoryReviewRepository) SaveReputation(reputation *domain.Reputation) error {
+	copied := *reputation
+	r.reputations[reputation.SubjectType+"/"+reputation.SubjectID] = &copied
+	return nil
+}
+
+func (r *memoryReviewRepository) GetReputation(subjectType, subjectID string) (*domain.Reputation, error) {
+	if reputation, ok := r.reputations[subjectType+"/"+subjectID]; ok {
+		copied := *reputation
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("reputation not found: %s %s", subjectType, subjectID)
+}
+
+func (r *memoryReviewRepository) CreateReport(report *domain.ReviewReport) error {
+	for _, existing := range r.reports {
+		if existing.ReviewID == report.ReviewID && existing.ReporterID == report.ReporterID {
+			return fmt.Errorf("review already reported: %s", report.ReviewID)
+		}
+	}
+	copied := *report
+	r.reports[report.ID] = &copied
+	return nil
+}
+
+func (r *memoryReviewRepository) GetReport(id string) (*domain.ReviewReport, error) {
+	if report, ok := r.reports[id]; ok {
+		copied := *report
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("review report not found: %s", id)
+}
+
+func (r *memoryReviewRepository) ListReports(status string, limit, offset int) ([]domain.ReviewReport, int, error) {
+	reports := []domain.ReviewReport{}
+	for _, report := range r.reports {
+		if report.Status == status {
+			reports = append(reports, *report)
+		}
+	}
+	return reports, len(reports), nil
+}
+
+func (r *memoryReviewRepository) CountOpenReports(reviewID string) (int, error) {
+	count := 0
+	for _, report := range r.reports {
+		if report.ReviewID == reviewID && report.Status == domain.ReviewReportOpen {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryReviewRepository) ResolveReports(reviewID, status, resolvedBy string, at time.Time) (int64, error) {
+	var resolved int64
+	for _, report := range r.reports {
+		if report.ReviewID == reviewID && report.Status == domain.ReviewReportOpen {
+			report.Status = status
+			report.ResolvedBy = &resolvedBy
+			report.ResolvedAt = &at
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+func newTestAgentProfileService(t *testing.T) (*AgentProfileService, *memoryReviewRepository) {
+	t.Helper()
+	agencyID := "agency-1"
+	agentID := "agent-1"
+	clientID := "client-1"
+	users := memoryUsers{
+		"agent-1":  {ID: "agent-1", FirstName: "Ana", LastName: "Torres", Role: domain.RoleAgent, Active: true, AgencyID: &agencyID},
+		"client-1": {ID: "client-1", FirstName: "María", LastName: "Pérez", Role: domain.RoleBuyer, Active: true},
+		"client-2": {ID: "client-2", FirstName: "Luis", LastName: "Mora", Role: domain.RoleBuyer, Active: true},
+	}
+	agencies := memoryAgencies{"agency-1": {ID: "agency-1", Name: "Costa Inmobiliaria"}}
+	deals := &memoryDealRepository{deals: []domain.Deal{
+		{ID: "deal-1", AgentID: &agentID, ClientID: &clientID, Type: domain.DealTypeSale},
+		{ID: "deal-2", AgentID: &agentID, Type: domain.DealTypeRent},
+	}}
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByFiltersPaginated", mock.Anything, mock.Anything).
+		Return([]domain.Property{{ID: "prop-1", Title: "Casa"}}, 7, nil)
+
+	reviews := newMemoryReviewRepository()
+	return NewAgentProfileService(reviews, users, agencies, propertyRepo, deals, log.New(os.Stdout, "", 0)), reviews
+}
+
+func TestAgentProfileService_ReviewFlow(t *testing.T) {
+	service, reviewRepo := newTestAgentProfileService(t)
+	moderation := NewReviewService(reviewRepo, log.New(os.Stdout, "", 0))
+	client := domain.NewActor("client-1", "buyer", "")
+	admin := domain.NewActor("admin-1", "admin", "")
+
+	_, err := service.SubmitReview("agent-1", SubmitReviewRequest{Rating: 5}, domain.NewActor("client-2", "buyer", ""))
+	assert.ErrorContains(t, err, "permission denied", "clients without a deal with the agent cannot review")
+	_, err = service.SubmitReview("agent-1", SubmitReviewRequest{Rating: 5}, domain.NewActor("agent-1", "agent", "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = service.SubmitReview("client-2", SubmitReviewRequest{Rating: 5}, client)
+	assert.ErrorContains(t, err, "agent not found")
+
+	review, err := service.SubmitReview("agent-1", SubmitReviewRequest{Rating: 4, Comment: "Muy atenta"}, client)
+	require.NoError(t, err)
+	assert.Equal(t, "deal-1", *review.DealID)
+
+	reviews, _, err := service.ListReviews("agent-1", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, reviews, "pending reviews are not public")
+
+	_, err = moderation.ModerateReview(review.ID, true, "", client)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = moderation.ModerateReview(review.ID, true, "", admin)
+	require.NoError(t, err)
+
+	profile, err := service.GetProfile("agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Costa Inmobiliaria", profile.Agency.Name)
+	assert.Equal(t, 7, profile.ActiveListings)
+	assert.Equal(t, 2, profile.Stats.DealsClosed)
+	assert.Equal(t, 1, profile.Stats.Sales)
+	assert.Equal(t, 1, profile.Reviews.Count)
+	assert.Equal(t, 4.0, profile.Reviews.AverageRating)
+	require.Len(t, profile.RecentReviews, 1)
+
+	// Resubmitting replaces the review and sends it back to moderation
+	edited, err := service.SubmitReview("agent-1", SubmitReviewRequest{Rating: 2}, client)
+	require.NoError(t, err)
+	assert.Equal(t, review.ID, edited.ID)
+	assert.Equal(t, domain.ReviewPending, edited.Status)
+
+	queue, total, err := moderation.ListModerationQueue("", 0, 0, admin)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, edited.ID, queue[0].ID)
+
+	assert.ErrorContains(t, moderation.DeleteReview(review.ID, domain.NewActor("client-2", "buyer", "")), "permission denied")
+	assert.NoError(t, moderation.DeleteReview(review.ID, client))
+}
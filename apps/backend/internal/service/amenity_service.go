@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// AmenityService manages the amenity catalog, which only admins edit, and the
+// catalog amenities of each property, which its managers set
+type AmenityService struct {
+	repo         repository.AmenityRepository
+	propertyRepo repository.PropertyRepository
+	listener     PropertyChangeListener
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewAmenityService creates an amenity service
+func NewAmenityService(
+	repo repository.AmenityRepository,
+	propertyRepo repository.PropertyRepository,
+	logger *log.Logger,
+) *AmenityService {
+	return &AmenityService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetChangeListener registers a listener for properties whose amenities change,
+// typically the PropertyService so cached searches reflect them
+func (s *AmenityService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// ListCatalog lists the active catalog; admins may include deactivated entries
+func (s *AmenityService) ListCatalog(includeInactive bool, actor domain.Actor) ([]domain.Amenity, error) {
+	return s.repo.ListCatalog(includeInactive && actor.Role == domain.RoleAdmin)
+}
+
+// CreateAmenity adds an active entry to the catalog
+func (s *AmenityService) CreateAmenity(amenity *domain.Amenity, actor domain.Actor) (*domain.Amenity, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can manage the amenity catalog")
+	}
+
+	amenity.Normalize()
+	if err := amenity.Validate(); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	amenity.Active = true
+	amenity.CreatedAt = now
+	amenity.UpdatedAt = now
+
+	if err := s.repo.Create(amenity); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Amenity %s added to the catalog by %s", amenity.Code, actor.UserID)
+	return amenity, nil
+}
+
+// UpdateAmenity saves a catalog entry. Deactivated entries stay on the properties that
+// have them but can no longer be added.
+func (s *AmenityService) UpdateAmenity(code string, update *domain.Amenity, actor domain.Actor) (*domain.Amenity, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can manage the amenity catalog")
+	}
+
+	amenity, err := s.repo.GetByCode(domain.NormalizeAmenityCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	update.Code = amenity.Code
+	update.Normalize()
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	update.CreatedAt = amenity.CreatedAt
+	update.UpdatedAt = s.now()
+
+	if err := s.repo.Update(update); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// DeleteAmenity removes a catalog entry no property has
+func (s *AmenityService) DeleteAmenity(code string, actor domain.Actor) error {
+	if actor.Role != domain.RoleAdmin {
+		return fmt.Errorf("permission denied: only admins can manage the amenity catalog")
+	}
+
+	code = domain.NormalizeAmenityCode(code)
+	if err := s.repo.Delete(code); err != nil {
+		return err
+	}
+
+	s.logger.Printf("Amenity %s removed from the catalog by %s", code, actor.UserID)
+	return nil
+}
+
+// GetPropertyAmenities lists the catalog amenities of a property
+func (s *AmenityService) GetPropertyAmenities(propertyID string) ([]domain.PropertyAmenity, error) {
+	if _, err := s.propertyRepo.GetByID(propertyID); err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	return s.repo.ListByProperty(propertyID)
+}
+
+// SetPropertyAmenities replaces the catalog amenities of a property the actor manages
+func (s *AmenityService) SetPropertyAmenities(propertyID string, amenities []domain.PropertyAmenity, actor domain.Actor) ([]domain.PropertyAmenity, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can change its amenities")
+	}
+
+	entries, err := s.repo.ListCatalog(false)
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]domain.Amenity, len(entries))
+	for _, entry := range entries {
+		catalog[entry.Code] = entry
+	}
+
+	// Deactivated amenities the property already has may be kept
+	current, err := s.repo.ListByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, amenity := range current {
+		if _, ok := catalog[amenity.Code]; !ok {
+			catalog[amenity.Code] = domain.Amenity{Code: amenity.Code, NameES: amenity.Name, Active: true}
+		}
+	}
+
+	if amenities == nil {
+		amenities = []domain.PropertyAmenity{}
+	}
+	if err := domain.ValidatePropertyAmenities(amenities, catalog); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceForProperty(propertyID, amenities); err != nil {
+		return nil, err
+	}
+
+	if s.listener != nil {
+		s.listener.InvalidateProperties(propertyID)
+	}
+	return s.repo.ListByProperty(propertyID)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryAmenities keeps the catalog and property amenities in memory
+type memoryAmenities struct {
+	catalog    map[string]domain.Amenity
+	properties map[string][]domain.PropertyAmenity
+}
+
+func (r *memoryAmenities) ListCatalog(includeInactive bool) ([]domain.Amenity, error) {
+	amenities := []domain.Amenity{}
+	for _, amenity := range r.catalog {
+		if includeInactive || amenity.Active {
+			amenities = append(amenities, amenity)
+		}
+	}
+	sort.Slice(amenities, func(i, j int) bool { return amenities[i].Code < amenities[j].Code })
+	return amenities, nil
+}
+
+func (r *memoryAmenities) GetByCode(code string) (*domain.Amenity, error) {
+	amenity, ok := r.catalog[code]
+	if !ok {
+		return nil, fmt.Errorf("amenity not found: %s", code)
+	}
+	return &amenity, nil
+}
+
+func (r *memoryAmenities) Create(amenity *domain.Amenity) error {
+	if _, ok := r.catalog[amenity.Code]; ok {
+		return fmt.Errorf("amenity already exists: %s", amenity.Code)
+	}
+	r.catalog[amenity.Code] = *amenity
+	return nil
+}
+
+func (r *memoryAmenities) Update(amenity *domain.Amenity) error {
+	r.catalog[amenity.Code] = *amenity
+	return nil
+}
+
+func (r *memoryAmenities) Delete(code string) error {
+	delete(r.catalog, code)
+	return nil
+}
+
+func (r *memoryAmenities) ListByProperty(propertyID string) ([]domain.PropertyAmenity, error) {
+	return append([]domain.PropertyAmenity{}, r.properties[propertyID]...), nil
+}
+
+func (r *memoryAmenities) ReplaceForProperty(propertyID string, amenities []domain.PropertyAmenity) error {
+	r.properties[propertyID] = append([]domain.PropertyAmenity{}, amenities...)
+	return nil
+}
+
+func TestAmenityService_Catalog(t *testing.T) {
+	repo := &memoryAmenities{catalog: map[string]domain.Amenity{}, properties: map[string][]domain.PropertyAmenity{}}
+	var logs bytes.Buffer
+	svc := NewAmenityService(repo, &MockPropertyRepository{}, log.New(&logs, "", 0))
+	svc.now = func() time.Time { return time.Date(2025, 9, 2, 10, 0, 0, 0, time.UTC) }
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+
+	generator := &domain.Amenity{Code: "Generator", NameES: "Generador", NameEN: "Generator", Category: "utilities"}
+	_, err := svc.CreateAmenity(generator, domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	created, err := svc.CreateAmenity(generator, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "generator", created.Code)
+	assert.True(t, created.Active)
+
+	_, err = svc.UpdateAmenity("generator", &domain.Amenity{NameES: "Generador", NameEN: "Generator", Category: "utilities"}, admin)
+	require.NoError(t, err)
+	assert.False(t, repo.catalog["generator"].Active)
+
+	active, err := svc.ListCatalog(true, domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	all, err := svc.ListCatalog(true, admin)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestAmenityService_SetPropertyAmenities(t *testing.T) {
+	repo := &memoryAmenities{
+		catalog: map[string]domain.Amenity{
+			"cistern":      {Code: "cistern", NameES: "Cisterna", Active: true},
+			"pet_friendly": {Code: "pet_friendly", NameES: "Se aceptan mascotas", Active: true},
+			"sauna":        {Code: "sauna", NameES: "Sauna", Active: false},
+		},
+		properties: map[string][]domain.PropertyAmenity{
+			"prop-1": {{Code: "sauna", Name: "Sauna", Details: json.RawMessage(`{}`)}},
+		},
+	}
+	ownerID := "owner-1"
+	properties := &MockPropertyRepository{}
+	properties.On("GetByID", "prop-1").Return(&domain.Property{ID: "prop-1", OwnerID: &ownerID}, nil)
+	listener := &recordingListener{}
+	var logs bytes.Buffer
+	svc := NewAmenityService(repo, properties, log.New(&logs, "", 0))
+	svc.SetChangeListener(listener)
+	owner := domain.NewActor(ownerID, string(domain.RoleOwner), "")
+
+	// A deactivated amenity the property already has can be kept
+	amenities, err := svc.SetPropertyAmenities("prop-1", []domain.PropertyAmenity{
+		{Code: "cistern", Details: json.RawMessage(`{"capacity_liters": 2000}`)},
+		{Code: "sauna"},
+	}, owner)
+	require.NoError(t, err)
+	assert.Len(t, amenities, 2)
+	assert.Equal(t, []string{"prop-1"}, listener.ids)
+
+	// but not added again once removed
+	_, err = svc.SetPropertyAmenities("prop-1", []domain.PropertyAmenity{{Code: "pet_friendly"}}, owner)
+	require.NoError(t, err)
+	_, err = svc.SetPropertyAmenities("prop-1", []domain.PropertyAmenity{{Code: "sauna"}}, owner)
+	assert.ErrorContains(t, err, "not in the catalog")
+
+	_, err = svc.SetPropertyAmenities("prop-1", nil, domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// APIKeyConfig configures the keys of the public API
+type APIKeyConfig struct {
+	Tiers         map[string]domain.APIKeyTier
+	DefaultTier   string
+	CacheTTL      time.Duration // how long a key is used without reading it again, which bounds how long a key revoked on another instance keeps working
+	FlushInterval time.Duration // how often request counts are written to the database
+}
+
+// MaxAPIKeyUsageDays is the longest range of daily usage returned at once
+const MaxAPIKeyUsageDays = 366
+
+// maxInvalidAPIKeys bounds the rejected keys remembered, so clients guessing keys
+// neither reach the database on every request nor grow the memory of the instance
+const maxInvalidAPIKeys = 10000
+
+// APIKeyService manages the keys of the public API, enforces their quotas and meters
+// their use. Keys are cached by each instance and their requests, rejections and bytes
+// sent counted in memory, then written to the database every flush interval, so a
+// request does not cost a write.
+//
+// The per-minute limit is kept by each instance, like the limit per client IP. The
+// daily quota is shared: a key's count is reloaded from the database with the key, so
+// across instances a key can exceed its quota by what the other instances counted
+// within one cache TTL.
+type APIKeyService struct {
+	repo   repository.APIKeyRepository
+	config APIKeyConfig
+	logger *log.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]*apiKeyState                        // by key hash
+	invalid map[string]time.Time                           // hashes of unknown and revoked keys, with when they were rejected
+	pending map[time.Time]map[string]domain.APIUsageCounts // usage not written yet, by UTC day and key ID
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// apiKeyState is a cached key with its request counts
+type apiKeyState struct {
+	key         *domain.APIKey
+	loadedAt    time.Time
+	day         time.Time
+	dayCount    int64
+	minuteStart time.Time
+	minuteCount int
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(repo repository.APIKeyRepository, config APIKeyConfig, logger *log.Logger) *APIKeyService {
+	if len(config.Tiers) == 0 {
+		config.Tiers, _ = domain.ParseAPIKeyTiers(domain.DefaultAPIKeyTiersSpec)
+	}
+	if config.DefaultTier == "" {
+		config.DefaultTier = domain.DefaultAPIKeyTier
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Minute
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 30 * time.Second
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &APIKeyService{
+		repo:    repo,
+		config:  config,
+		logger:  logger,
+		now:     time.Now,
+		keys:    make(map[string]*apiKeyState),
+		invalid: make(map[string]time.Time),
+		pending: make(map[time.Time]map[string]domain.APIUsageCounts),
+	}
+}
+
+// Tiers returns the rate tiers keys can be given
+func (s *APIKeyService) Tiers() []domain.APIKeyTier {
+	return domain.SortedAPIKeyTiers(s.config.Tiers)
+}
+
+// CreateKey creates a key for a consumer of the public API, optionally on behalf of an
+// agency, and returns it with the key itself, which is not stored and cannot be shown
+// again
+func (s *APIKeyService) CreateKey(actor domain.Actor, name, contactEmail, tier, agencyID string) (*domain.APIKey, string, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, "", fmt.Errorf("permission denied: only administrators can create API keys")
+	}
+
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if tier == "" {
+		tier = s.config.DefaultTier
+	}
+	if _, ok := s.config.Tiers[tier]; !ok {
+		return nil, "", fmt.Errorf("invalid API key tier: %s", tier)
+	}
+
+	key, plaintext, err := domain.NewAPIKey(name, contactEmail, tier, actor.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if agencyID = strings.TrimSpace(agencyID); agencyID != "" {
+		key.AgencyID = &agencyID
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Printf("API key %s (%s) created for %s in tier %s", key.Prefix, key.Name, key.ContactEmail, key.Tier)
+	return key, plaintext, nil
+}
+
+// ListKeys returns every key
+func (s *APIKeyService) ListKeys(actor domain.Actor) ([]domain.APIKey, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can list API keys")
+	}
+	return s.repo.List()
+}
+
+// RevokeKey revokes a key. It stops working at once on this instance and within the
+// cache TTL on the others.
+func (s *APIKeyService) RevokeKey(actor domain.Actor, id string) error {
+	if actor.Role != domain.RoleAdmin {
+		return fmt.Errorf("permission denied: only administrators can revoke API keys")
+	}
+
+	key, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Revoke(id, s.now()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.keys, key.KeyHash)
+	s.mu.Unlock()
+
+	s.logger.Printf("API key %s (%s) revoked", key.Prefix, key.Name)
+	return nil
+}
+
+// GetUsage returns the daily usage of a key between two days, inclusive, including the
+// usage this instance has not written yet
+func (s *APIKeyService) GetUsage(actor domain.Actor, id string, from, to time.Time) ([]domain.APIKeyUsage, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can view API key usage")
+	}
+	from, to, err := s.usageRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Usage(id, from, to)
+}
+
+// UsageReport reports the use of the public API by every consumer between two days,
+// inclusive, or by the consumers of an agency when agencyID is not empty
+func (s *APIKeyService) UsageReport(actor domain.Actor, from, to time.Time, agencyID string) (*domain.APIUsageReport, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can view API usage")
+	}
+	return s.usageReport(from, to, strings.TrimSpace(agencyID))
+}
+
+// AgencyUsageReport reports the use of the public API by the keys of an agency between
+// two days, inclusive. Admins and the agency's own account can see it.
+func (s *APIKeyService) AgencyUsageReport(actor domain.Actor, agencyID string, from, to time.Time) (*domain.APIUsageReport, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: cannot view the API usage of this agency")
+	}
+	return s.usageReport(from, to, agencyID)
+}
+
+// usageReport builds a usage report, of the keys of an agency when agencyID is not empty
+func (s *APIKeyService) usageReport(from, to time.Time, agencyID string) (*domain.APIUsageReport, error) {
+	from, to, err := s.usageRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if agencyID != "" {
+		agencyKeys := make([]domain.APIKey, 0, len(keys))
+		for _, key := range keys {
+			if key.AgencyID != nil && *key.AgencyID == agencyID {
+				agencyKeys = append(agencyKeys, key)
+			}
+		}
+		keys = agencyKeys
+	}
+
+	usage, err := s.repo.UsageBetween(from, to, agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := domain.NewAPIUsageReport(from, to, keys, usage, s.config.Tiers)
+	report.AgencyID = agencyID
+	return report, nil
+}
+
+// usageRange validates a range of days to read usage of and writes the usage counted
+// since the last flush, so the range includes it
+func (s *APIKeyService) usageRange(from, to time.Time) (time.Time, time.Time, error) {
+	from, to = utcDay(from), utcDay(to)
+	if to.Before(from) {
+		return from, to, fmt.Errorf("invalid usage range: from is after to")
+	}
+	if to.Sub(from) > MaxAPIKeyUsageDays*24*time.Hour {
+		return from, to, fmt.Errorf("invalid usage range: at most %d days", MaxAPIKeyUsageDays)
+	}
+
+	if err := s.Flush(); err != nil {
+		s.logger.Printf("Failed to write API key usage before reading it: %v", err)
+	}
+	return from, to, nil
+}
+
+// UseAPIKey authenticates a request made with a key and counts it against the key's
+// quotas. A request over a quota is metered as rejected instead; the returned quota
+// tells which window rejected it. Keys that do not exist or were revoked return domain.ErrInvalidAPIKey,
+// and are rejected without reading the database again for a cache TTL.
+func (s *APIKeyService) UseAPIKey(plaintext string) (*domain.APIKey, domain.APIKeyQuota, error) {
+	plaintext = strings.TrimSpace(plaintext)
+	if !strings.HasPrefix(plaintext, domain.APIKeyPrefix) {
+		return nil, domain.APIKeyQuota{}, domain.ErrInvalidAPIKey
+	}
+
+	now := s.now()
+	state, err := s.keyState(domain.HashAPIKey(plaintext), now)
+	if err != nil {
+		return nil, domain.APIKeyQuota{}, err
+	}
+
+	tier, ok := s.config.Tiers[state.key.Tier]
+	if !ok {
+		tier = s.config.Tiers[s.config.DefaultTier]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := utcDay(now)
+	if !state.day.Equal(today) {
+		state.day, state.dayCount = today, 0
+	}
+	if now.Sub(state.minuteStart) >= time.Minute {
+		state.minuteStart, state.minuteCount = now, 0
+	}
+
+	quota := domain.APIKeyQuota{
+		Tier:          tier.Name,
+		MinuteLimit:   tier.RequestsPerMinute,
+		MinuteResetAt: state.minuteStart.Add(time.Minute),
+		DailyLimit:    tier.RequestsPerDay,
+		DailyResetAt:  today.Add(24 * time.Hour),
+	}
+	switch {
+	case state.dayCount >= tier.RequestsPerDay:
+		quota.Exceeded = domain.APIKeyWindowDay
+	case state.minuteCount >= tier.RequestsPerMinute:
+		quota.Exceeded = domain.APIKeyWindowMinute
+	default:
+		state.dayCount++
+		state.minuteCount++
+	}
+	if quota.Allowed() {
+		s.meterLocked(today, state.key.ID, domain.APIUsageCounts{Requests: 1})
+	} else {
+		s.meterLocked(today, state.key.ID, domain.APIUsageCounts{RejectedRequests: 1})
+	}
+	quota.MinuteRemaining = tier.RequestsPerMinute - state.minuteCount
+	if quota.MinuteRemaining < 0 {
+		quota.MinuteRemaining = 0
+	}
+	quota.DailyRemaining = tier.RequestsPerDay - state.dayCount
+	if quota.DailyRemaining < 0 {
+		quota.DailyRemaining = 0
+	}
+
+	return state.key, quota, nil
+}
+
+// keyState returns the cached state of the key with the given hash, loading the key
+// and its requests of the day when it is not cached or its TTL passed
+func (s *APIKeyService) keyState(keyHash string, now time.Time) (*apiKeyState, error) {
+	s.mu.Lock()
+	state, ok := s.keys[keyHash]
+	if ok && now.Sub(state.loadedAt) < s.config.CacheTTL {
+		s.mu.Unlock()
+		return state, nil
+	}
+	if rejectedAt, ok := s.invalid[keyHash]; ok && now.Sub(rejectedAt) < s.config.CacheTTL {
+		s.mu.Unlock()
+		return nil, domain.ErrInvalidAPIKey
+	}
+	s.mu.Unlock()
+
+	key, err := s.repo.GetByHash(keyHash)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+	if err != nil || !key.IsActive() {
+		s.mu.Lock()
+		delete(s.keys, keyHash)
+		if len(s.invalid) >= maxInvalidAPIKeys {
+			s.invalid = make(map[string]time.Time)
+		}
+		s.invalid[keyHash] = now
+		s.mu.Unlock()
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	today := utcDay(now)
+	written, err := s.repo.DailyRequests(key.ID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == nil {
+		state = &apiKeyState{}
+		s.keys[keyHash] = state
+	}
+	state.key = key
+	state.loadedAt = now
+	state.day = today
+	state.dayCount = written + s.pending[today][key.ID].Requests
+	return state, nil
+}
+
+// RecordBytesSent meters the bytes sent in the response to a request made with a key
+func (s *APIKeyService) RecordBytesSent(keyID string, bytes int64) {
+	if keyID == "" || bytes <= 0 {
+		return
+	}
+	s.mu.Lock()
+	s.meterLocked(utcDay(s.now()), keyID, domain.APIUsageCounts{BytesSent: bytes})
+	s.mu.Unlock()
+}
+
+// meterLocked adds usage of a key on a day to the usage not written yet. s.mu must be
+// held.
+func (s *APIKeyService) meterLocked(day time.Time, keyID string, counts domain.APIUsageCounts) {
+	if s.pending[day] == nil {
+		s.pending[day] = make(map[string]domain.APIUsageCounts)
+	}
+	usage := s.pending[day][keyID]
+	usage.Add(counts)
+	s.pending[day][keyID] = usage
+}
+
+// Flush writes the usage counted since the last flush
+func (s *APIKeyService) Flush() error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[time.Time]map[string]domain.APIUsageCounts)
+	s.mu.Unlock()
+
+	var failed error
+	for day, usage := range pending {
+		if err := s.repo.AddUsage(day, usage, s.now()); err != nil {
+			// Keep the counts for the next flush
+			s.mu.Lock()
+			for keyID, counts := range usage {
+				s.meterLocked(day, keyID, counts)
+			}
+			s.mu.Unlock()
+			failed = err
+		}
+	}
+	return failed
+}
+
+// Start writes the usage counted on the flush interval until Stop is called
+func (s *APIKeyService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Flush(); err != nil {
+					s.logger.Printf("Failed to write API key usage: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the flushes and writes the usage counted since the last one
+func (s *APIKeyService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	if err := s.Flush(); err != nil {
+		s.logger.Printf("Failed to write API key usage: %v", err)
+	}
+}
+
+// utcDay returns the start of the UTC day of t
+func utcDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
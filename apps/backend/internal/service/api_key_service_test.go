@@ -0,0 +1,270 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryAPIKeyRepository keeps API keys and their usage in memory
+type memoryAPIKeyRepository struct {
+	keys    map[string]*domain.APIKey
+	usage   map[string]domain.APIUsageCounts // by key ID and day
+	lookups int
+	failAdd bool
+}
+
+func newMemoryAPIKeyRepository() *memoryAPIKeyRepository {
+	return &memoryAPIKeyRepository{keys: map[string]*domain.APIKey{}, usage: map[string]domain.APIUsageCounts{}}
+}
+
+func (r *memoryAPIKeyRepository) Create(key *domain.APIKey) error {
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetByID(id string) (*domain.APIKey, error) {
+	if key, ok := r.keys[id]; ok {
+		copy := *key
+		return &copy, nil
+	}
+	return nil, fmt.Errorf("API key not found")
+}
+
+func (r *memoryAPIKeyRepository) GetByHash(keyHash string) (*domain.APIKey, error) {
+	r.lookups++
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copy := *key
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("API key not found")
+}
+
+func (r *memoryAPIKeyRepository) List() ([]domain.APIKey, error) {
+	keys := []domain.APIKey{}
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepository) Revoke(id string, revokedAt time.Time) error {
+	r.keys[id].RevokedAt = &revokedAt
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) AddUsage(day time.Time, usage map[string]domain.APIUsageCounts, usedAt time.Time) error {
+	if r.failAdd {
+		return errors.New("connection refused")
+	}
+	for keyID, counts := range usage {
+		total := r.usage[keyID+day.Format("2006-01-02")]
+		total.Add(counts)
+		r.usage[keyID+day.Format("2006-01-02")] = total
+	}
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) DailyRequests(keyID string, day time.Time) (int64, error) {
+	return r.usage[keyID+day.Format("2006-01-02")].Requests, nil
+}
+
+func (r *memoryAPIKeyRepository) Usage(keyID string, from, to time.Time) ([]domain.APIKeyUsage, error) {
+	usage := []domain.APIKeyUsage{}
+	for day := from; !day.After(to); day = day.Add(24 * time.Hour) {
+		if counts, ok := r.usage[keyID+day.Format("2006-01-02")]; ok {
+			usage = append(usage, domain.APIKeyUsage{APIKeyID: keyID, Day: day, APIUsageCounts: counts})
+		}
+	}
+	return usage, nil
+}
+
+func (r *memoryAPIKeyRepository) UsageBetween(from, to time.Time, agencyID string) ([]domain.APIKeyUsage, error) {
+	usage := []domain.APIKeyUsage{}
+	for _, key := range r.keys {
+		if agencyID != "" && (key.AgencyID == nil || *key.AgencyID != agencyID) {
+			continue
+		}
+		days, _ := r.Usage(key.ID, from, to)
+		usage = append(usage, days...)
+	}
+	return usage, nil
+}
+
+var testAdmin = domain.Actor{UserID: "admin-1", Role: domain.RoleAdmin}
+
+func newTestAPIKeyService(t *testing.T, repo *memoryAPIKeyRepository, now *time.Time) *APIKeyService {
+	tiers, err := domain.ParseAPIKeyTiers("free:2:3,partner:100:1000")
+	require.NoError(t, err)
+	service := NewAPIKeyService(repo, APIKeyConfig{Tiers: tiers, CacheTTL: time.Minute}, log.New(io.Discard, "", 0))
+	service.now = func() time.Time { return *now }
+	return service
+}
+
+func TestAPIKeyService_CreateKey(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	now := time.Date(2025, 9, 10, 12, 0, 0, 0, time.UTC)
+	service := newTestAPIKeyService(t, repo, &now)
+
+	_, _, err := service.CreateKey(domain.Actor{UserID: "agent-1", Role: domain.RoleAgent}, "Portal", "api@portal.ec", "", "")
+	assert.ErrorContains(t, err, "permission denied")
+	_, _, err = service.CreateKey(testAdmin, "Portal", "api@portal.ec", "gold", "")
+	assert.ErrorContains(t, err, "invalid API key tier")
+
+	key, plaintext, err := service.CreateKey(testAdmin, "Portal", "api@portal.ec", "Partner", "agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, "partner", key.Tier)
+	assert.Equal(t, "agency-1", *key.AgencyID)
+	assert.Equal(t, "admin-1", key.CreatedBy)
+
+	used, _, err := service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, used.ID)
+}
+
+func TestAPIKeyService_EnforcesQuotas(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	now := time.Date(2025, 9, 10, 23, 59, 0, 0, time.UTC)
+	service := newTestAPIKeyService(t, repo, &now)
+	key, plaintext, err := service.CreateKey(testAdmin, "Research", "data@uni.edu.ec", "free", "")
+	require.NoError(t, err)
+
+	_, quota, err := service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	assert.True(t, quota.Allowed())
+	assert.Equal(t, 1, quota.MinuteRemaining)
+	assert.Equal(t, int64(2), quota.DailyRemaining)
+
+	_, _, err = service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	_, quota, err = service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, domain.APIKeyWindowMinute, quota.Exceeded)
+	assert.Equal(t, now.Add(time.Minute), quota.RetryAt())
+
+	now = now.Add(30 * time.Second)
+	_, quota, err = service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, domain.APIKeyWindowMinute, quota.Exceeded, "the minute window is not over")
+
+	now = now.Add(31 * time.Second) // a new minute on the next UTC day
+	for i := 0; i < 2; i++ {
+		_, quota, err = service.UseAPIKey(plaintext)
+		require.NoError(t, err)
+		assert.True(t, quota.Allowed())
+	}
+
+	// The daily quota holds requests counted by other instances
+	require.NoError(t, service.Flush())
+	written := repo.usage[key.ID+"2025-09-11"]
+	written.Requests++
+	repo.usage[key.ID+"2025-09-11"] = written
+	now = now.Add(2 * time.Minute)
+	_, quota, err = service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, domain.APIKeyWindowDay, quota.Exceeded)
+	assert.Equal(t, int64(0), quota.DailyRemaining)
+	assert.Equal(t, time.Date(2025, 9, 12, 0, 0, 0, 0, time.UTC), quota.RetryAt())
+
+	require.NoError(t, service.Flush())
+	assert.Equal(t, domain.APIUsageCounts{Requests: 2, RejectedRequests: 2}, repo.usage[key.ID+"2025-09-10"])
+	assert.Equal(t, domain.APIUsageCounts{Requests: 3, RejectedRequests: 1}, repo.usage[key.ID+"2025-09-11"],
+		"rejected requests are not counted against the quota")
+}
+
+func TestAPIKeyService_RejectsInvalidAndRevokedKeys(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	now := time.Date(2025, 9, 10, 12, 0, 0, 0, time.UTC)
+	service := newTestAPIKeyService(t, repo, &now)
+	key, plaintext, err := service.CreateKey(testAdmin, "Portal", "api@portal.ec", "partner", "")
+	require.NoError(t, err)
+
+	_, _, err = service.UseAPIKey("not-a-key")
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	assert.Equal(t, 0, repo.lookups)
+
+	for i := 0; i < 3; i++ {
+		_, _, err = service.UseAPIKey(domain.APIKeyPrefix + "guessed")
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	}
+	assert.Equal(t, 1, repo.lookups, "unknown keys are remembered")
+
+	_, _, err = service.UseAPIKey(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeKey(testAdmin, key.ID))
+	_, _, err = service.UseAPIKey(plaintext)
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_FlushKeepsCountsOnFailure(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	now := time.Date(2025, 9, 10, 12, 0, 0, 0, time.UTC)
+	service := newTestAPIKeyService(t, repo, &now)
+	key, plaintext, err := service.CreateKey(testAdmin, "Portal", "api@portal.ec", "partner", "")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, _, err = service.UseAPIKey(plaintext)
+		require.NoError(t, err)
+	}
+
+	repo.failAdd = true
+	assert.Error(t, service.Flush())
+	repo.failAdd = false
+
+	usage, err := service.GetUsage(testAdmin, key.ID, now.AddDate(0, 0, -1), now)
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, int64(3), usage[0].Requests)
+
+	_, err = service.GetUsage(testAdmin, key.ID, now, now.AddDate(0, 0, -1))
+	assert.ErrorContains(t, err, "invalid usage range")
+}
+
+func TestAPIKeyService_UsageReports(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	now := time.Date(2025, 9, 10, 12, 0, 0, 0, time.UTC)
+	service := newTestAPIKeyService(t, repo, &now)
+	portal, portalKey, err := service.CreateKey(testAdmin, "Portal", "api@portal.ec", "partner", "agency-1")
+	require.NoError(t, err)
+	_, uniKey, err := service.CreateKey(testAdmin, "Universidad", "data@uni.edu.ec", "free", "")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		key, _, err := service.UseAPIKey(portalKey)
+		require.NoError(t, err)
+		service.RecordBytesSent(key.ID, 2048)
+	}
+	_, _, err = service.UseAPIKey(uniKey)
+	require.NoError(t, err)
+
+	report, err := service.UsageReport(testAdmin, now.AddDate(0, 0, -6), now, "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.APIUsageCounts{Requests: 4, BytesSent: 6144}, report.Totals)
+	require.Len(t, report.Consumers, 2)
+	assert.Equal(t, portal.ID, report.Consumers[0].APIKeyID)
+	assert.Equal(t, int64(1000), report.Consumers[0].DailyLimit)
+	require.Len(t, report.Days, 1)
+
+	agencyActor := domain.Actor{UserID: "owner-1", Role: domain.RoleAgency, AgencyID: "agency-1"}
+	report, err = service.AgencyUsageReport(agencyActor, "agency-1", now, now)
+	require.NoError(t, err)
+	assert.Equal(t, "agency-1", report.AgencyID)
+	require.Len(t, report.Consumers, 1)
+	assert.Equal(t, int64(3), report.Totals.Requests)
+
+	_, err = service.AgencyUsageReport(agencyActor, "agency-2", now, now)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = service.UsageReport(agencyActor, now, now, "")
+	assert.ErrorContains(t, err, "permission denied")
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// Calendar feed window: subscribed calendars keep past events they already have, so the
+// feed only needs recent ones, plus cancellations of them
+const (
+	calendarFeedPast   = 30 * 24 * time.Hour
+	calendarFeedFuture = 365 * 24 * time.Hour
+)
+
+// defaultAppointmentDuration is the length of appointments scheduled without an end
+const defaultAppointmentDuration = time.Hour
+
+// ScheduleAppointmentRequest describes a visit or open house. Times without an offset
+// are local times of the property.
+type ScheduleAppointmentRequest struct {
+	PropertyID      string `json:"property_id"`
+	Kind            string `json:"kind"`
+	Title           string `json:"title,omitempty"`
+	Notes           string `json:"notes,omitempty"`
+	ClientName      string `json:"client_name,omitempty"`
+	StartsAt        string `json:"starts_at"`
+	EndsAt          string `json:"ends_at,omitempty"`
+	DurationMinutes int    `json:"duration_minutes,omitempty"`
+}
+
+// AppointmentCalendar syncs appointments with the external calendars of agents, e.g.
+// CalendarSyncService
+type AppointmentCalendar interface {
+	BusyBlocks(agentID string, from, to time.Time) ([]domain.CalendarBusyBlock, error)
+	PushAppointment(appointment *domain.Appointment)
+}
+
+// AppointmentService keeps the agenda of visits and open houses of each agent and serves
+// it as a calendar feed agents subscribe to from Google Calendar and similar apps.
+// Calendar apps cannot sign in, so the feed is opened by a secret token in its URL.
+type AppointmentService struct {
+	repo         repository.AppointmentRepository
+	propertyRepo repository.PropertyRepository
+	users        UserLookup
+	calendar     AppointmentCalendar
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewAppointmentService creates a new appointment service
+func NewAppointmentService(repo repository.AppointmentRepository, propertyRepo repository.PropertyRepository, users UserLookup, logger *log.Logger) *AppointmentService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &AppointmentService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		users:        users,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetCalendarSync keeps appointments off the busy times of agents' connected calendars
+// and pushes them there as soon as they are scheduled or cancelled
+func (s *AppointmentService) SetCalendarSync(calendar AppointmentCalendar) {
+	s.calendar = calendar
+}
+
+// Schedule schedules a visit or open house of a property the actor manages on the
+// agenda of an agent
+func (s *AppointmentService) Schedule(agentID string, req ScheduleAppointmentRequest, actor domain.Actor) (*domain.Appointment, error) {
+	if err := s.checkAgenda(agentID, actor); err != nil {
+		return nil, err
+	}
+
+	property, err := s.propertyRepo.GetByID(req.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) && !property.IsAssignedToAgent(actor.UserID) {
+		return nil, fmt.Errorf("permission denied: cannot schedule appointments for this property")
+	}
+
+	location := domain.AppointmentLocation(domain.AppointmentTimeZone(property.Province))
+	startsAt, err := domain.ParseAppointmentTime(req.StartsAt, location)
+	if err != nil {
+		return nil, err
+	}
+	endsAt := startsAt.Add(defaultAppointmentDuration)
+	switch {
+	case req.EndsAt != "":
+		if endsAt, err = domain.ParseAppointmentTime(req.EndsAt, location); err != nil {
+			return nil, err
+		}
+	case req.DurationMinutes < 0:
+		return nil, fmt.Errorf("invalid appointment: duration must be positive")
+	case req.DurationMinutes > 0:
+		endsAt = startsAt.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	}
+
+	now := s.now()
+	appointment, err := domain.NewAppointment(agentID, property, req.Kind, req.Title, req.Notes, req.ClientName,
+		startsAt, endsAt, actor.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// An agent cannot be at two properties at once
+	existing, err := s.repo.ListByAgent(agentID, appointment.StartsAt.Add(-domain.MaxAppointmentDuration), appointment.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if other.Status == domain.AppointmentScheduled && other.StartsAt.Before(appointment.EndsAt) && appointment.StartsAt.Before(other.EndsAt) {
+			return nil, fmt.Errorf("appointment conflicts with %q at %s", other.Title, other.StartsAt.Format("2006-01-02 15:04"))
+		}
+	}
+	if s.calendar != nil {
+		busy, err := s.calendar.BusyBlocks(agentID, appointment.StartsAt, appointment.EndsAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := domain.CheckBusyConflicts(appointment.StartsAt, appointment.EndsAt, busy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(appointment); err != nil {
+		return nil, err
+	}
+	if s.calendar != nil {
+		s.calendar.PushAppointment(appointment)
+	}
+	s.logger.Printf("Appointment %s (%s) scheduled for agent %s at %s by %s",
+		appointment.ID, appointment.Kind, agentID, appointment.StartsAt.Format(time.RFC3339), actor.UserID)
+	return appointment, nil
+}
+
+// List returns the appointments of an agent starting between from and to
+func (s *AppointmentService) List(agentID string, from, to time.Time, actor domain.Actor) ([]domain.Appointment, error) {
+	if err := s.checkAgenda(agentID, actor); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid date range: to must be after from")
+	}
+	return s.repo.ListByAgent(agentID, from, to)
+}
+
+// Cancel cancels an appointment; it stays in the calendar feed as cancelled
+func (s *AppointmentService) Cancel(id string, actor domain.Actor) (*domain.Appointment, error) {
+	appointment, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAgenda(appointment.AgentID, actor); err != nil {
+		return nil, err
+	}
+	if err := appointment.Cancel(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateStatus(appointment); err != nil {
+		return nil, err
+	}
+	if s.calendar != nil {
+		s.calendar.PushAppointment(appointment)
+	}
+	return appointment, nil
+}
+
+// RotateCalendarToken issues a new calendar feed token for an agent, replacing the
+// previous one. The token is only returned here; subscriptions with the previous one
+// stop working.
+func (s *AppointmentService) RotateCalendarToken(agentID string, actor domain.Actor) (string, error) {
+	if err := s.checkAgenda(agentID, actor); err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := domain.NewCalendarFeedToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.repo.SetCalendarToken(agentID, tokenHash, s.now()); err != nil {
+		return "", err
+	}
+	s.logger.Printf("Calendar feed token rotated for agent %s by %s", agentID, actor.UserID)
+	return token, nil
+}
+
+// Calendar returns the calendar feed of an agent opened with its token
+func (s *AppointmentService) Calendar(agentID, token string) (*domain.AgentCalendar, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("invalid calendar token: a token is required")
+	}
+	tokenHash, err := s.repo.GetCalendarTokenHash(agentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("invalid calendar token")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(domain.HashAPIKey(token)), []byte(tokenHash)) != 1 {
+		return nil, fmt.Errorf("invalid calendar token")
+	}
+
+	now := s.now()
+	appointments, err := s.repo.ListByAgent(agentID, now.Add(-calendarFeedPast), now.Add(calendarFeedFuture))
+	if err != nil {
+		return nil, err
+	}
+	calendar := &domain.AgentCalendar{AgentID: agentID, Name: "Agenda de visitas", Appointments: appointments}
+	if agent, err := s.users.GetByID(agentID); err == nil {
+		calendar.Name = "Agenda de " + agent.GetFullName()
+	}
+	return calendar, nil
+}
+
+// checkAgenda checks that the actor can manage the agenda of an agent: the agent
+// themselves, an administrator of the agent's agency or an admin
+func (s *AppointmentService) checkAgenda(agentID string, actor domain.Actor) error {
+	if actor.UserID == "" {
+		return fmt.Errorf("permission denied: sign in to manage appointments")
+	}
+	agent, err := s.users.GetByID(agentID)
+	if err != nil {
+		return fmt.Errorf("agent not found: %s", agentID)
+	}
+	if actor.UserID == agentID || actor.Role == domain.RoleAdmin {
+		return nil
+	}
+	if agent.AgencyID != nil && actor.CanAdministerAgency(*agent.AgencyID) {
+		return nil
+	}
+	return fmt.Errorf("permission denied: cannot manage the appointments of this agent")
+}
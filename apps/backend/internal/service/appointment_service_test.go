@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryAppointments keeps appointments and calendar tokens in memory
+type memoryAppointments struct {
+	appointments map[string]domain.Appointment
+	tokens       map[string]string
+}
+
+func (m *memoryAppointments) Create(appointment *domain.Appointment) error {
+	m.appointments[appointment.ID] = *appointment
+	return nil
+}
+
+func (m *memoryAppointments) GetByID(id string) (*domain.Appointment, error) {
+	appointment, ok := m.appointments[id]
+	if !ok {
+		return nil, fmt.Errorf("appointment not found: %s", id)
+	}
+	return &appointment, nil
+}
+
+func (m *memoryAppointments) ListByAgent(agentID string, from, to time.Time) ([]domain.Appointment, error) {
+	appointments := []domain.Appointment{}
+	for _, appointment := range m.appointments {
+		if appointment.AgentID == agentID && !appointment.StartsAt.Before(from) && appointment.StartsAt.Before(to) {
+			appointments = append(appointments, appointment)
+		}
+	}
+	sort.Slice(appointments, func(i, j int) bool { return appointments[i].StartsAt.Before(appointments[j].StartsAt) })
+	return appointments, nil
+}
+
+func (m *memoryAppointments) UpdateStatus(appointment *domain.Appointment) error {
+	m.appointments[appointment.ID] = *appointment
+	return nil
+}
+
+func (m *memoryAppointments) SetCalendarToken(agentID, tokenHash string, createdAt time.Time) error {
+	m.tokens[agentID] = tokenHash
+	return nil
+}
+
+func (m *memoryAppointments) GetCalendarTokenHash(agentID string) (string, error) {
+	tokenHash, ok := m.tokens[agentID]
+	if !ok {
+		return "", fmt.Errorf("calendar feed not found: %s", agentID)
+	}
+	return tokenHash, nil
+}
+
+func newTestAppointmentService(now time.Time) *AppointmentService {
+	agencyID := "agency-1"
+	agentID := "agent-1"
+	property := domain.NewProperty("Casa", "Casa en Puerto Ayora", "Galápagos", "Puerto Ayora", "house", 350000, "owner-1")
+	property.ID = "property-1"
+	property.AgencyID = &agencyID
+	property.AgentID = &agentID
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "property-1").Return(property, nil)
+
+	users := memoryUsers{
+		"agent-1": {ID: "agent-1", FirstName: "María", LastName: "Vera", Role: domain.RoleAgent, AgencyID: &agencyID},
+		"agent-2": {ID: "agent-2", FirstName: "Luis", LastName: "Mora", Role: domain.RoleAgent},
+	}
+	repo := &memoryAppointments{appointments: map[string]domain.Appointment{}, tokens: map[string]string{}}
+
+	svc := NewAppointmentService(repo, propertyRepo, users, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestAppointmentService_Schedule(t *testing.T) {
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	svc := newTestAppointmentService(now)
+	agent := domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1")
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+	other := domain.NewActor("agent-2", string(domain.RoleAgent), "")
+
+	visit, err := svc.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "visit", StartsAt: "2025-09-20T10:00"}, agent)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TimeZoneGalapagos, visit.TimeZone)
+	assert.Equal(t, time.Date(2025, 9, 20, 16, 0, 0, 0, time.UTC), visit.StartsAt.UTC(), "local times are read in the property's time zone")
+	assert.Equal(t, time.Hour, visit.EndsAt.Sub(visit.StartsAt))
+
+	_, err = svc.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "open_house", StartsAt: "2025-09-20T10:30", DurationMinutes: 120}, agency)
+	assert.ErrorContains(t, err, "conflicts with")
+	_, err = svc.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "open_house", StartsAt: "2025-09-20T11:00", DurationMinutes: 120}, agency)
+	require.NoError(t, err)
+
+	_, err = svc.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "visit", StartsAt: "2025-09-21T10:00"}, other)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.Schedule("agent-2", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "visit", StartsAt: "2025-09-21T10:00"}, other)
+	assert.ErrorContains(t, err, "permission denied", "agents only schedule properties they manage")
+
+	appointments, err := svc.List("agent-1", now, now.Add(30*24*time.Hour), agent)
+	require.NoError(t, err)
+	assert.Len(t, appointments, 2)
+
+	cancelled, err := svc.Cancel(visit.ID, agency)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AppointmentCancelled, cancelled.Status)
+	_, err = svc.Cancel(visit.ID, other)
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestAppointmentService_Calendar(t *testing.T) {
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	svc := newTestAppointmentService(now)
+	agent := domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1")
+
+	_, err := svc.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "visit", StartsAt: "2025-09-20T10:00"}, agent)
+	require.NoError(t, err)
+
+	_, err = svc.Calendar("agent-1", "cal_unknown")
+	assert.ErrorContains(t, err, "invalid calendar token", "agents without a token have no feed")
+
+	token, err := svc.RotateCalendarToken("agent-1", agent)
+	require.NoError(t, err)
+	calendar, err := svc.Calendar("agent-1", token)
+	require.NoError(t, err)
+	assert.Equal(t, "Agenda de María Vera", calendar.Name)
+	assert.Len(t, calendar.Appointments, 1)
+
+	_, err = svc.Calendar("agent-2", token)
+	assert.ErrorContains(t, err, "invalid calendar token")
+
+	rotated, err := svc.RotateCalendarToken("agent-1", agent)
+	require.NoError(t, err)
+	_, err = svc.Calendar("agent-1", token)
+	assert.ErrorContains(t, err, "invalid calendar token", "rotating replaces the previous token")
+	_, err = svc.Calendar("agent-1", rotated)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// AvailabilityService manages the availability calendars of rental properties: the
+// dates blocked by their managers, the stays booked by rental deals and the minimum stay
+type AvailabilityService struct {
+	repo         repository.AvailabilityRepository
+	propertyRepo repository.PropertyRepository
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewAvailabilityService creates an availability service
+func NewAvailabilityService(repo repository.AvailabilityRepository, propertyRepo repository.PropertyRepository, logger *log.Logger) *AvailabilityService {
+	return &AvailabilityService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// GetCalendar returns the unavailable dates of a property between two dates. Notes and
+// deals are only shown to the listing's managers.
+func (s *AvailabilityService) GetCalendar(propertyID string, from, to time.Time, actor domain.Actor) (*domain.AvailabilityCalendar, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	period, err := domain.NewStayPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if period.Nights() > domain.MaxCalendarDays {
+		return nil, fmt.Errorf("invalid dates: calendars span at most %d days", domain.MaxCalendarDays)
+	}
+
+	settings, err := s.repo.GetSettings(property.ID)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.repo.ListBlocks(property.ID, period)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageListing(property, actor) {
+		for i := range blocks {
+			blocks[i] = blocks[i].Public()
+		}
+	}
+
+	return &domain.AvailabilityCalendar{
+		PropertyID:    property.ID,
+		From:          period.Start,
+		To:            period.End,
+		MinStayNights: settings.MinStayNights,
+		Blocks:        blocks,
+	}, nil
+}
+
+// CheckStay checks that a property can be rented for a stay
+func (s *AvailabilityService) CheckStay(propertyID string, period domain.StayPeriod) error {
+	settings, err := s.repo.GetSettings(propertyID)
+	if err != nil {
+		return err
+	}
+	blocks, err := s.repo.ListBlocks(propertyID, period)
+	if err != nil {
+		return err
+	}
+	return domain.CheckStay(period, settings, blocks)
+}
+
+// BookStay marks the dates of a rental deal as booked
+func (s *AvailabilityService) BookStay(propertyID, dealID string, period domain.StayPeriod, bookedBy string) error {
+	return s.repo.AddBlock(domain.NewBooking(propertyID, dealID, period, bookedBy, s.now()))
+}
+
+// UpdateSettings sets the minimum stay of a property
+func (s *AvailabilityService) UpdateSettings(propertyID string, minStayNights int, actor domain.Actor) (*domain.AvailabilitySettings, error) {
+	property, err := s.managedProperty(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	settings := &domain.AvailabilitySettings{
+		PropertyID:    property.ID,
+		MinStayNights: minStayNights,
+		UpdatedBy:     actor.UserID,
+		UpdatedAt:     &now,
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// BlockDates makes a range of dates unavailable
+func (s *AvailabilityService) BlockDates(propertyID string, period domain.StayPeriod, note string, actor domain.Actor) (*domain.AvailabilityBlock, error) {
+	property, err := s.managedProperty(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := domain.NewAvailabilityBlock(property.ID, period, note, actor.UserID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddBlock(block); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Property %s blocked from %s to %s by %s", property.ID,
+		period.Start.Format(domain.CalendarDateLayout), period.End.Format(domain.CalendarDateLayout), actor.UserID)
+	return block, nil
+}
+
+// UnblockDates makes the dates of a manual block available again. Booked dates belong to
+// their rental deal and cannot be released here.
+func (s *AvailabilityService) UnblockDates(propertyID, blockID string, actor domain.Actor) error {
+	property, err := s.managedProperty(propertyID, actor)
+	if err != nil {
+		return err
+	}
+
+	block, err := s.repo.GetBlock(blockID)
+	if err != nil {
+		return err
+	}
+	if block.PropertyID != property.ID {
+		return fmt.Errorf("availability block not found: %s", blockID)
+	}
+	if block.Kind == domain.AvailabilityBooked {
+		return fmt.Errorf("invalid block: booked dates belong to a rental deal")
+	}
+	return s.repo.DeleteBlock(block.ID)
+}
+
+// managedProperty returns a property the actor manages
+func (s *AvailabilityService) managedProperty(propertyID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can manage its availability")
+	}
+	return property, nil
+}
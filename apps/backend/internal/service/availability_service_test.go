@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryAvailability keeps availability settings and blocks in memory
+type memoryAvailability struct {
+	settings map[string]*domain.AvailabilitySettings
+	blocks   []domain.AvailabilityBlock
+}
+
+func newMemoryAvailability() *memoryAvailability {
+	return &memoryAvailability{settings: map[string]*domain.AvailabilitySettings{}}
+}
+
+func (m *memoryAvailability) GetSettings(propertyID string) (*domain.AvailabilitySettings, error) {
+	if settings, ok := m.settings[propertyID]; ok {
+		copied := *settings
+		return &copied, nil
+	}
+	return domain.DefaultAvailabilitySettings(propertyID), nil
+}
+
+func (m *memoryAvailability) SaveSettings(settings *domain.AvailabilitySettings) error {
+	copied := *settings
+	m.settings[settings.PropertyID] = &copied
+	return nil
+}
+
+func (m *memoryAvailability) ListBlocks(propertyID string, period domain.StayPeriod) ([]domain.AvailabilityBlock, error) {
+	blocks := []domain.AvailabilityBlock{}
+	for _, block := range m.blocks {
+		if block.PropertyID == propertyID && block.Overlaps(period) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (m *memoryAvailability) GetBlock(id string) (*domain.AvailabilityBlock, error) {
+	for _, block := range m.blocks {
+		if block.ID == id {
+			return &block, nil
+		}
+	}
+	return nil, fmt.Errorf("availability block not found: %s", id)
+}
+
+func (m *memoryAvailability) AddBlock(block *domain.AvailabilityBlock) error {
+	existing, _ := m.ListBlocks(block.PropertyID, block.StayPeriod)
+	if err := domain.CheckConflicts(block.StayPeriod, existing); err != nil {
+		return err
+	}
+	m.blocks = append(m.blocks, *block)
+	return nil
+}
+
+func (m *memoryAvailability) DeleteBlock(id string) error {
+	for i, block := range m.blocks {
+		if block.ID == id {
+			m.blocks = append(m.blocks[:i], m.blocks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("availability block not found: %s", id)
+}
+
+func calendarDay(t *testing.T, value string) time.Time {
+	date, err := domain.ParseCalendarDate(value)
+	require.NoError(t, err)
+	return date
+}
+
+func stay(t *testing.T, start, end string) domain.StayPeriod {
+	period, err := domain.NewStayPeriod(calendarDay(t, start), calendarDay(t, end))
+	require.NoError(t, err)
+	return period
+}
+
+func newTestAvailabilityService() (*AvailabilityService, *memoryAvailability, *MockPropertyRepository) {
+	property := domain.NewProperty("Suite", "Suite amoblada en Salinas", "Santa Elena", "Salinas", "apartment", 95000, "owner-1")
+	property.ID = "prop-1"
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+	repo := newMemoryAvailability()
+	var logs bytes.Buffer
+	return NewAvailabilityService(repo, propertyRepo, log.New(&logs, "", 0)), repo, propertyRepo
+}
+
+func TestAvailabilityService_Calendar(t *testing.T) {
+	svc, _, _ := newTestAvailabilityService()
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	visitor := domain.NewActor("buyer-1", string(domain.RoleBuyer), "")
+
+	_, err := svc.BlockDates("prop-1", stay(t, "2025-12-20", "2026-01-03"), "Feriados", visitor)
+	assert.ErrorContains(t, err, "permission denied")
+
+	block, err := svc.BlockDates("prop-1", stay(t, "2025-12-20", "2026-01-03"), "Feriados", owner)
+	require.NoError(t, err)
+	_, err = svc.BlockDates("prop-1", stay(t, "2025-12-30", "2026-01-05"), "", owner)
+	assert.ErrorContains(t, err, "dates not available")
+
+	settings, err := svc.UpdateSettings("prop-1", 3, owner)
+	require.NoError(t, err)
+	assert.Equal(t, 3, settings.MinStayNights)
+	_, err = svc.UpdateSettings("prop-1", 0, owner)
+	assert.ErrorContains(t, err, "invalid minimum stay")
+
+	calendar, err := svc.GetCalendar("prop-1", calendarDay(t, "2025-12-01"), calendarDay(t, "2026-01-01"), visitor)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calendar.MinStayNights)
+	require.Len(t, calendar.Blocks, 1)
+	assert.Empty(t, calendar.Blocks[0].Note, "notes are only shown to the listing's managers")
+
+	calendar, err = svc.GetCalendar("prop-1", calendarDay(t, "2025-12-01"), calendarDay(t, "2026-01-01"), owner)
+	require.NoError(t, err)
+	assert.Equal(t, "Feriados", calendar.Blocks[0].Note)
+
+	_, err = svc.GetCalendar("prop-1", calendarDay(t, "2025-01-01"), calendarDay(t, "2026-06-01"), owner)
+	assert.ErrorContains(t, err, "at most")
+
+	require.NoError(t, svc.UnblockDates("prop-1", block.ID, owner))
+	assert.NoError(t, svc.CheckStay("prop-1", stay(t, "2025-12-24", "2025-12-28")))
+}
+
+func TestDealService_RentalDates(t *testing.T) {
+	calendar, repo, propertyRepo := newTestAvailabilityService()
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	_, err := calendar.BlockDates("prop-1", stay(t, "2025-12-20", "2026-01-03"), "", owner)
+	require.NoError(t, err)
+
+	deals := &memoryDealRepository{}
+	svc := NewDealService(deals, propertyRepo, nil, log.New(&bytes.Buffer{}, "", 0))
+	svc.SetRentalCalendar(calendar)
+
+	start, end := calendarDay(t, "2025-12-28"), calendarDay(t, "2026-01-02")
+	rental := RecordDealRequest{PropertyID: "prop-1", Type: domain.DealTypeRent, FinalPrice: 600, RentalStart: &start, RentalEnd: &end}
+	_, err = svc.RecordDeal(rental, owner)
+	assert.ErrorContains(t, err, "dates not available")
+	assert.Empty(t, deals.deals)
+
+	start, end = calendarDay(t, "2026-01-03"), calendarDay(t, "2026-01-10")
+	deal, err := svc.RecordDeal(rental, owner)
+	require.NoError(t, err)
+
+	booked, err := repo.ListBlocks("prop-1", stay(t, "2026-01-03", "2026-01-10"))
+	require.NoError(t, err)
+	require.Len(t, booked, 1)
+	assert.Equal(t, domain.AvailabilityBooked, booked[0].Kind)
+	assert.Equal(t, deal.ID, *booked[0].DealID)
+	assert.ErrorContains(t, calendar.UnblockDates("prop-1", booked[0].ID, owner), "belong to a rental deal")
+
+	sale := RecordDealRequest{PropertyID: "prop-1", Type: domain.DealTypeSale, FinalPrice: 95000, RentalStart: &start, RentalEnd: &end}
+	_, err = svc.RecordDeal(sale, owner)
+	assert.ErrorContains(t, err, "only rentals have rental dates")
+}
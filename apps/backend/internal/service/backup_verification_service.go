@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/backup"
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// liveSchema is the schema dumps are taken from and compared against
+const liveSchema = "public"
+
+// BackupVerificationConfig configures the verification of database dumps
+type BackupVerificationConfig struct {
+	Dir           string                         // directory the dumps are written to
+	MaxAge        time.Duration                  // how long a passed verification vouches for the backups
+	MinRowRatio   float64                        // share of the live rows of a table a dump must hold
+	KeepSchema    bool                           // keep the restored schema for inspection
+	SampleQueries []repository.BackupSampleQuery // nil uses repository.DefaultBackupSampleQueries
+}
+
+// BackupVerificationService restores the latest dump into a temporary schema and checks
+// it against the live database, so broken backups are found before they are needed
+type BackupVerificationService struct {
+	repo     repository.BackupRepository
+	restorer backup.Restorer
+	config   BackupVerificationConfig
+	now      func() time.Time
+	logger   *log.Logger
+
+	mu      sync.Mutex
+	running bool
+}
+
+// NewBackupVerificationService creates a new backup verification service
+func NewBackupVerificationService(repo repository.BackupRepository, restorer backup.Restorer, config BackupVerificationConfig, logger *log.Logger) *BackupVerificationService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = domain.DefaultBackupMaxAge
+	}
+	if config.MinRowRatio <= 0 {
+		config.MinRowRatio = domain.DefaultBackupMinRowRatio
+	}
+	if config.SampleQueries == nil {
+		config.SampleQueries = repository.DefaultBackupSampleQueries
+	}
+	return &BackupVerificationService{
+		repo:     repo,
+		restorer: restorer,
+		config:   config,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+// Verify restores the latest dump and checks its tables, row counts, foreign keys and
+// sample queries. A dump failing to restore or any check is a failed verification, not
+// an error; errors are returned when there is no dump or the result cannot be saved.
+func (s *BackupVerificationService) Verify(ctx context.Context) (*domain.BackupVerification, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("backup verification is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	dump, err := backup.LatestDump(s.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	verification := domain.NewBackupVerification(*dump, s.now())
+	s.logger.Printf("Verifying backup %s in schema %s", dump.Name, verification.Schema)
+
+	if err := s.restore(ctx, verification); err == nil {
+		if err := s.check(verification); err != nil {
+			verification.Fail(err)
+		}
+	}
+	if !s.config.KeepSchema {
+		if err := s.repo.DropSchema(verification.Schema); err != nil {
+			s.logger.Printf("Failed to drop backup verification schema %s: %v", verification.Schema, err)
+		}
+	}
+	verification.FinishedAt = s.now()
+
+	if err := s.repo.SaveVerification(verification); err != nil {
+		return verification, err
+	}
+	if verification.Passed() {
+		s.logger.Printf("Backup %s verified: %d tables, %d rows", dump.Name, verification.Tables, verification.Rows)
+	} else {
+		reasons := verification.FailedChecks()
+		if verification.Error != "" {
+			reasons = append(reasons, verification.Error)
+		}
+		s.logger.Printf("Backup %s failed verification: %s", dump.Name, strings.Join(reasons, ", "))
+	}
+	return verification, nil
+}
+
+// restore restores the dump into the verification schema, recorded as the restore check
+func (s *BackupVerificationService) restore(ctx context.Context, verification *domain.BackupVerification) error {
+	started := s.now()
+	err := s.restorer.Restore(ctx, &verification.Dump, verification.Schema)
+	verification.RestoreSeconds = s.now().Sub(started).Seconds()
+
+	check := domain.BackupCheck{Kind: domain.BackupCheckRestore, Name: verification.Dump.Name, Passed: err == nil,
+		Detail: fmt.Sprintf("restored in %.1fs", verification.RestoreSeconds)}
+	if err != nil {
+		check.Detail = err.Error()
+	}
+	verification.AddCheck(check)
+	return err
+}
+
+// check runs the consistency checks of a restored dump
+func (s *BackupVerificationService) check(verification *domain.BackupVerification) error {
+	restored, err := s.repo.TableRowCounts(verification.Schema)
+	if err != nil {
+		return err
+	}
+	live, err := s.repo.TableRowCounts(liveSchema)
+	if err != nil {
+		return err
+	}
+	counts := make([]domain.BackupTableCount, 0, len(restored))
+	for table, rows := range restored {
+		counts = append(counts, domain.BackupTableCount{Table: table, Restored: rows, Live: live[table]})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Table < counts[j].Table })
+	liveTables := make([]string, 0, len(live))
+	for table := range live {
+		liveTables = append(liveTables, table)
+	}
+	sort.Strings(liveTables)
+	verification.CheckRowCounts(counts, liveTables, s.config.MinRowRatio)
+
+	keys, err := s.repo.ForeignKeys(verification.Schema)
+	if err != nil {
+		return err
+	}
+	for _, fk := range keys {
+		orphans, err := s.repo.CountOrphans(verification.Schema, fk)
+		check := domain.BackupCheck{Kind: domain.BackupCheckForeignKey, Name: fk.Name, Passed: err == nil && orphans == 0}
+		switch {
+		case err != nil:
+			check.Detail = err.Error()
+		case orphans > 0:
+			check.Detail = fmt.Sprintf("%d rows of %s without their %s row", orphans, fk.Table, fk.ParentTable)
+		}
+		verification.AddCheck(check)
+	}
+
+	for _, query := range s.config.SampleQueries {
+		rows, err := s.repo.RunSampleQuery(verification.Schema, query)
+		check := domain.BackupCheck{Kind: domain.BackupCheckSampleQuery, Name: query.Name, Passed: err == nil && rows >= query.MinRows}
+		switch {
+		case err != nil:
+			check.Detail = err.Error()
+		case rows < query.MinRows:
+			check.Detail = fmt.Sprintf("%d rows, expected at least %d", rows, query.MinRows)
+		default:
+			check.Detail = fmt.Sprintf("%d rows", rows)
+		}
+		verification.AddCheck(check)
+	}
+	return nil
+}
+
+// Status sums up the latest verifications for monitoring
+func (s *BackupVerificationService) Status(limit int) (*domain.BackupStatus, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	recent, err := s.repo.ListVerifications(limit)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewBackupStatus(recent, s.config.MaxAge, s.now()), nil
+}
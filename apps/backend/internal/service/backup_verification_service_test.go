@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// memoryBackups fakes the restored and live schemas
+type memoryBackups struct {
+	counts  map[string]map[string]int64
+	keys    []domain.BackupForeignKey
+	orphans map[string]int64
+	samples map[string]int
+	dropped []string
+	saved   []domain.BackupVerification
+}
+
+func (m *memoryBackups) TableRowCounts(schema string) (map[string]int64, error) {
+	return m.counts[schema], nil
+}
+
+func (m *memoryBackups) ForeignKeys(schema string) ([]domain.BackupForeignKey, error) {
+	return m.keys, nil
+}
+
+func (m *memoryBackups) CountOrphans(schema string, fk domain.BackupForeignKey) (int64, error) {
+	return m.orphans[fk.Name], nil
+}
+
+func (m *memoryBackups) RunSampleQuery(schema string, query repository.BackupSampleQuery) (int, error) {
+	rows, ok := m.samples[query.Name]
+	if !ok {
+		return 0, fmt.Errorf("failed to run %s: relation does not exist", query.Name)
+	}
+	return rows, nil
+}
+
+func (m *memoryBackups) DropSchema(schema string) error {
+	m.dropped = append(m.dropped, schema)
+	return nil
+}
+
+func (m *memoryBackups) SaveVerification(verification *domain.BackupVerification) error {
+	m.saved = append([]domain.BackupVerification{*verification}, m.saved...)
+	return nil
+}
+
+func (m *memoryBackups) ListVerifications(limit int) ([]domain.BackupVerification, error) {
+	if len(m.saved) > limit {
+		return m.saved[:limit], nil
+	}
+	return m.saved, nil
+}
+
+// fakeRestorer copies the live counts into the restored schema, or fails
+type fakeRestorer struct {
+	repo *memoryBackups
+	err  error
+}
+
+func (f *fakeRestorer) Restore(ctx context.Context, dump *domain.BackupDump, schema string) error {
+	if f.err != nil {
+		return f.err
+	}
+	restored := map[string]int64{}
+	for table, rows := range f.repo.counts["public"] {
+		restored[table] = rows
+	}
+	f.repo.counts[schema] = restored
+	return nil
+}
+
+func newBackupVerificationTest(t *testing.T) (*BackupVerificationService, *memoryBackups, *fakeRestorer) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "realty-20250916.sql.gz"), []byte("dump"), 0o600))
+
+	repo := &memoryBackups{
+		counts: map[string]map[string]int64{
+			"public": {"properties": 120, "images": 300, "users": 8},
+		},
+		keys: []domain.BackupForeignKey{{Name: "images_property_id_fkey", Table: "images", Columns: []string{"property_id"},
+			ParentTable: "properties", ParentColumns: []string{"id"}}},
+		orphans: map[string]int64{},
+		samples: map[string]int{"recent_properties": 10},
+	}
+	restorer := &fakeRestorer{repo: repo}
+	svc := NewBackupVerificationService(repo, restorer, BackupVerificationConfig{
+		Dir:           dir,
+		SampleQueries: []repository.BackupSampleQuery{{Name: "recent_properties", Query: "SELECT 1", MinRows: 1}},
+	}, log.New(&bytes.Buffer{}, "", 0))
+	now := time.Date(2025, 9, 16, 4, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, repo, restorer
+}
+
+func TestBackupVerificationService_Verify(t *testing.T) {
+	svc, repo, _ := newBackupVerificationTest(t)
+
+	verification, err := svc.Verify(context.Background())
+	require.NoError(t, err)
+	assert.True(t, verification.Passed(), verification.FailedChecks())
+	assert.Equal(t, "realty-20250916.sql.gz", verification.Dump.Name)
+	assert.Equal(t, 3, verification.Tables)
+	assert.Equal(t, int64(428), verification.Rows)
+	assert.Equal(t, []string{"backup_verify_20250916040000"}, repo.dropped)
+	require.Len(t, repo.saved, 1)
+
+	status, err := svc.Status(10)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BackupStatusOK, status.Status)
+}
+
+func TestBackupVerificationService_VerifyFailures(t *testing.T) {
+	t.Run("restore", func(t *testing.T) {
+		svc, repo, restorer := newBackupVerificationTest(t)
+		restorer.err = fmt.Errorf("failed to restore: psql: ERROR: syntax error at or near \"COPY\"")
+
+		verification, err := svc.Verify(context.Background())
+		require.NoError(t, err)
+		assert.False(t, verification.Passed())
+		assert.Equal(t, []string{"restore:realty-20250916.sql.gz"}, verification.FailedChecks())
+		assert.Len(t, repo.saved, 1, "failed verifications are reported too")
+		assert.Len(t, repo.dropped, 1)
+
+		status, err := svc.Status(10)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BackupStatusFailing, status.Status)
+	})
+
+	t.Run("consistency", func(t *testing.T) {
+		svc, repo, _ := newBackupVerificationTest(t)
+		repo.orphans["images_property_id_fkey"] = 4
+		svc.config.SampleQueries = append(svc.config.SampleQueries, repository.BackupSampleQuery{Name: "admin_users", MinRows: 1})
+
+		verification, err := svc.Verify(context.Background())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"foreign_key:images_property_id_fkey", "sample_query:admin_users"},
+			verification.FailedChecks())
+	})
+
+	t.Run("no dump", func(t *testing.T) {
+		svc, repo, _ := newBackupVerificationTest(t)
+		svc.config.Dir = t.TempDir()
+
+		_, err := svc.Verify(context.Background())
+		assert.Error(t, err)
+		assert.Empty(t, repo.saved)
+	})
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/gcal"
+	"realty-core/internal/repository"
+)
+
+// calendarSyncBatchSize is how many connections a scheduled sync goes through
+const calendarSyncBatchSize = 100
+
+// CalendarProvider is the external calendar agents connect, e.g. gcal.Client
+type CalendarProvider interface {
+	AuthorizationURL(state string) string
+	Exchange(code string) (*domain.CalendarToken, error)
+	Refresh(refreshToken string) (*domain.CalendarToken, error)
+	ListEvents(accessToken, calendarID, syncToken string, from time.Time) (*domain.CalendarEventPage, error)
+	PutEvent(accessToken, calendarID, eventID string, appointment *domain.Appointment) (string, error)
+	DeleteEvent(accessToken, calendarID, eventID string) error
+}
+
+// CalendarAppointments lists the agenda of agents, e.g. repository.AppointmentRepository
+type CalendarAppointments interface {
+	ListByAgent(agentID string, from, to time.Time) ([]domain.Appointment, error)
+}
+
+// CalendarSyncService syncs the agenda of agents with their Google Calendar both ways:
+// scheduled visits and open houses are pushed as events, and the agent's other events
+// are imported as busy times that appointments cannot overlap.
+//
+// The platform owns the events it pushes: when the agent edits or deletes one in
+// Google Calendar it is pushed again on the next sync. Busy times imported after an
+// appointment was scheduled do not move it; they are reported as conflicts for the
+// agent to resolve.
+type CalendarSyncService struct {
+	repo         repository.CalendarSyncRepository
+	provider     CalendarProvider
+	appointments CalendarAppointments
+	users        UserLookup
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewCalendarSyncService creates a new calendar sync service. Without a provider
+// calendars cannot be connected or synced.
+func NewCalendarSyncService(repo repository.CalendarSyncRepository, provider CalendarProvider, appointments CalendarAppointments, users UserLookup, logger *log.Logger) *CalendarSyncService {
+	return &CalendarSyncService{
+		repo:         repo,
+		provider:     provider,
+		appointments: appointments,
+		users:        users,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// Connect starts connecting the actor's Google Calendar and returns the consent page
+// to send them to. Only agents connect their own calendar.
+func (s *CalendarSyncService) Connect(agentID string, actor domain.Actor) (string, error) {
+	if s.provider == nil {
+		return "", fmt.Errorf("calendar sync unavailable: Google Calendar is not configured")
+	}
+	if actor.UserID != agentID || actor.Role != domain.RoleAgent {
+		return "", fmt.Errorf("permission denied: agents connect their own calendar")
+	}
+
+	now := s.now()
+	connection, err := s.repo.GetConnection(agentID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return "", err
+		}
+		connection = domain.NewCalendarConnection(uuid.New().String(), agentID, now)
+	}
+	state, err := connection.StartAuthorization(now)
+	if err != nil {
+		return "", err
+	}
+	if err := s.repo.SaveConnection(connection); err != nil {
+		return "", err
+	}
+	return s.provider.AuthorizationURL(state), nil
+}
+
+// CompleteAuthorization completes a connection with the code Google redirected the
+// agent back with, then imports their calendar
+func (s *CalendarSyncService) CompleteAuthorization(state, code string) (*domain.CalendarConnection, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("calendar sync unavailable: Google Calendar is not configured")
+	}
+	state, code = strings.TrimSpace(state), strings.TrimSpace(code)
+	if state == "" || code == "" {
+		return nil, fmt.Errorf("invalid authorization: state and code are required")
+	}
+
+	connection, err := s.repo.GetConnectionByState(domain.HashAPIKey(state))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("invalid authorization state")
+		}
+		return nil, err
+	}
+	token, err := s.provider.Exchange(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete calendar authorization: %w", err)
+	}
+	if err := connection.Authorize(token, s.now()); err != nil {
+		return nil, err
+	}
+	// A full import follows, possibly of another account
+	if err := s.repo.DeleteBusyBlocks(connection.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveConnection(connection); err != nil {
+		return nil, err
+	}
+	s.logger.Printf("Google Calendar connected for agent %s", connection.AgentID)
+
+	if _, err := s.syncConnection(connection); err != nil {
+		s.logger.Printf("Initial calendar sync failed for agent %s: %v", connection.AgentID, err)
+	}
+	return connection, nil
+}
+
+// GetConnection returns the calendar connection of an agent
+func (s *CalendarSyncService) GetConnection(agentID string, actor domain.Actor) (*domain.CalendarConnection, error) {
+	if err := s.checkAgent(agentID, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetConnection(agentID)
+}
+
+// Disconnect removes the calendar connection of an agent and its imported busy times.
+// Events already pushed stay in the calendar.
+func (s *CalendarSyncService) Disconnect(agentID string, actor domain.Actor) error {
+	if err := s.checkAgent(agentID, actor); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteConnection(agentID); err != nil {
+		return err
+	}
+	s.logger.Printf("Calendar disconnected for agent %s by %s", agentID, actor.UserID)
+	return nil
+}
+
+// Sync syncs the calendar of an agent now
+func (s *CalendarSyncService) Sync(agentID string, actor domain.Actor) (*domain.CalendarSyncReport, error) {
+	if err := s.checkAgent(agentID, actor); err != nil {
+		return nil, err
+	}
+	if s.provider == nil {
+		return nil, fmt.Errorf("calendar sync unavailable: Google Calendar is not configured")
+	}
+	connection, err := s.repo.GetConnection(agentID)
+	if err != nil {
+		return nil, err
+	}
+	if connection.Status != domain.CalendarConnectionActive {
+		return nil, fmt.Errorf("invalid calendar connection: it is %s, connect the calendar again", connection.Status)
+	}
+	return s.syncConnection(connection)
+}
+
+// SyncAll syncs the least recently synced connected calendars and returns how many
+// synced. A failing calendar does not stop the others.
+func (s *CalendarSyncService) SyncAll() (int, error) {
+	if s.provider == nil {
+		return 0, nil
+	}
+	connections, err := s.repo.ListActiveConnections(calendarSyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for i := range connections {
+		if _, err := s.syncConnection(&connections[i]); err != nil {
+			s.logger.Printf("Calendar sync failed for agent %s: %v", connections[i].AgentID, err)
+			continue
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+// Conflicts lists the upcoming appointments of an agent overlapping busy times
+// imported from their calendar
+func (s *CalendarSyncService) Conflicts(agentID string, actor domain.Actor) ([]domain.CalendarConflict, error) {
+	if err := s.checkAgent(agentID, actor); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	to := now.Add(domain.CalendarSyncWindow)
+	appointments, err := s.appointments.ListByAgent(agentID, now, to)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.repo.ListBusyBlocks(agentID, now, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.CalendarConflicts(appointments, blocks), nil
+}
+
+// BusyBlocks lists the busy times imported for an agent overlapping from and to
+func (s *CalendarSyncService) BusyBlocks(agentID string, from, to time.Time) ([]domain.CalendarBusyBlock, error) {
+	return s.repo.ListBusyBlocks(agentID, from, to)
+}
+
+// PushAppointment pushes a scheduled or cancelled appointment to its agent's calendar
+// right away. Failures are logged; the next scheduled sync pushes it again.
+func (s *CalendarSyncService) PushAppointment(appointment *domain.Appointment) {
+	if s.provider == nil {
+		return
+	}
+	connection, err := s.repo.GetConnection(appointment.AgentID)
+	if err != nil || connection.Status != domain.CalendarConnectionActive {
+		return
+	}
+
+	now := s.now()
+	if err = s.authorize(connection, now); err == nil {
+		var links map[string]*domain.CalendarEventLink
+		if links, err = s.eventLinks(connection.ID); err == nil {
+			err = s.push(connection, appointment, links[appointment.ID], &domain.CalendarSyncReport{}, now)
+		}
+	}
+	if err != nil {
+		s.logger.Printf("Failed to push appointment %s to the calendar of agent %s: %v", appointment.ID, appointment.AgentID, err)
+	}
+}
+
+// syncConnection imports the changes of a calendar and pushes the agent's upcoming
+// appointments, recording the outcome on the connection
+func (s *CalendarSyncService) syncConnection(connection *domain.CalendarConnection) (*domain.CalendarSyncReport, error) {
+	now := s.now()
+	report := &domain.CalendarSyncReport{AgentID: connection.AgentID}
+
+	err := s.authorize(connection, now)
+	if err == nil {
+		var links map[string]*domain.CalendarEventLink
+		if links, err = s.eventLinks(connection.ID); err == nil {
+			if err = s.importEvents(connection, links, report, now); err == nil {
+				err = s.pushAppointments(connection, links, report, now)
+			}
+		}
+	}
+
+	if err != nil {
+		connection.LastError = err.Error()
+	} else {
+		connection.LastSyncedAt = &now
+		connection.LastError = ""
+	}
+	connection.UpdatedAt = now
+	if saveErr := s.repo.SaveConnection(connection); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// authorize refreshes the access token of a connection when it is about to expire.
+// Connections whose access was revoked are marked as such and lose their busy times.
+func (s *CalendarSyncService) authorize(connection *domain.CalendarConnection, now time.Time) error {
+	if !connection.NeedsRefresh(now) {
+		return nil
+	}
+
+	token, err := s.provider.Refresh(connection.RefreshToken)
+	if errors.Is(err, gcal.ErrAuthorizationRevoked) {
+		connection.Revoke("calendar access was revoked, connect the calendar again", now)
+		if err := s.repo.DeleteBusyBlocks(connection.ID); err != nil {
+			return err
+		}
+		if err := s.repo.SaveConnection(connection); err != nil {
+			return err
+		}
+		s.logger.Printf("Calendar access revoked for agent %s", connection.AgentID)
+		return fmt.Errorf("calendar access revoked: connect the calendar again")
+	}
+	if err != nil {
+		return err
+	}
+	connection.Refreshed(token, now)
+	return s.repo.SaveConnection(connection)
+}
+
+// eventLinks returns the events the appointments of a connection were pushed as, by
+// appointment ID
+func (s *CalendarSyncService) eventLinks(connectionID string) (map[string]*domain.CalendarEventLink, error) {
+	links, err := s.repo.ListEventLinks(connectionID)
+	if err != nil {
+		return nil, err
+	}
+	byAppointment := make(map[string]*domain.CalendarEventLink, len(links))
+	for i := range links {
+		byAppointment[links[i].AppointmentID] = &links[i]
+	}
+	return byAppointment, nil
+}
+
+// importEvents imports the events changed since the last sync as busy times. Pushed
+// events edited in the calendar are flagged to be pushed again.
+func (s *CalendarSyncService) importEvents(connection *domain.CalendarConnection, links map[string]*domain.CalendarEventLink, report *domain.CalendarSyncReport, now time.Time) error {
+	page, err := s.listEvents(connection, report, now)
+	if err != nil {
+		return err
+	}
+
+	horizon := now.Add(domain.CalendarSyncWindow)
+	for _, event := range page.Events {
+		if event.AppointmentID != "" {
+			if link, ok := links[event.AppointmentID]; ok && event.ETag != link.ETag {
+				link.Sequence = -1
+				report.Overwritten++
+			}
+			continue
+		}
+
+		if event.IsBusy() && event.StartsAt.Before(horizon) {
+			block := &domain.CalendarBusyBlock{
+				ConnectionID: connection.ID,
+				AgentID:      connection.AgentID,
+				ExternalID:   event.ExternalID,
+				StartsAt:     event.StartsAt,
+				EndsAt:       event.EndsAt,
+				UpdatedAt:    now,
+			}
+			if err := s.repo.SaveBusyBlock(block); err != nil {
+				return err
+			}
+			report.Imported++
+			continue
+		}
+		removed, err := s.repo.DeleteBusyBlock(connection.ID, event.ExternalID)
+		if err != nil {
+			return err
+		}
+		if removed {
+			report.Removed++
+		}
+	}
+
+	if page.CalendarName != "" {
+		connection.CalendarName = page.CalendarName
+	}
+	connection.SyncToken = page.NextSyncToken
+	return nil
+}
+
+// listEvents lists the events changed since the sync token of a connection. Without a
+// token, or when it expired, the calendar is imported from scratch and the busy times
+// imported before are replaced.
+func (s *CalendarSyncService) listEvents(connection *domain.CalendarConnection, report *domain.CalendarSyncReport, now time.Time) (*domain.CalendarEventPage, error) {
+	if connection.SyncToken != "" {
+		page, err := s.provider.ListEvents(connection.AccessToken, connection.CalendarID, connection.SyncToken, now)
+		if !errors.Is(err, gcal.ErrSyncTokenExpired) {
+			return page, err
+		}
+		s.logger.Printf("Calendar sync token expired for agent %s, importing the calendar again", connection.AgentID)
+	}
+
+	page, err := s.provider.ListEvents(connection.AccessToken, connection.CalendarID, "", now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteBusyBlocks(connection.ID); err != nil {
+		return nil, err
+	}
+	report.FullImport = true
+	return page, nil
+}
+
+// pushAppointments pushes the upcoming appointments of an agent that changed since
+// they were last pushed
+func (s *CalendarSyncService) pushAppointments(connection *domain.CalendarConnection, links map[string]*domain.CalendarEventLink, report *domain.CalendarSyncReport, now time.Time) error {
+	appointments, err := s.appointments.ListByAgent(connection.AgentID, now, now.Add(domain.CalendarSyncWindow))
+	if err != nil {
+		return err
+	}
+	for i := range appointments {
+		if err := s.push(connection, &appointments[i], links[appointments[i].ID], report, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// push creates or updates the event of a scheduled appointment, or deletes the event
+// of a cancelled one
+func (s *CalendarSyncService) push(connection *domain.CalendarConnection, appointment *domain.Appointment, link *domain.CalendarEventLink, report *domain.CalendarSyncReport, now time.Time) error {
+	if appointment.Status == domain.AppointmentCancelled {
+		if link == nil {
+			return nil
+		}
+		if err := s.provider.DeleteEvent(connection.AccessToken, connection.CalendarID, link.ExternalID); err != nil {
+			return err
+		}
+		report.Deleted++
+		return s.repo.DeleteEventLink(connection.ID, appointment.ID)
+	}
+	if !link.NeedsPush(appointment) {
+		return nil
+	}
+
+	eventID := domain.CalendarEventID(appointment.ID)
+	etag, err := s.provider.PutEvent(connection.AccessToken, connection.CalendarID, eventID, appointment)
+	if err != nil {
+		return err
+	}
+	report.Pushed++
+	return s.repo.SaveEventLink(&domain.CalendarEventLink{
+		ConnectionID:  connection.ID,
+		AppointmentID: appointment.ID,
+		ExternalID:    eventID,
+		ETag:          etag,
+		Sequence:      appointment.Sequence,
+		PushedAt:      now,
+	})
+}
+
+// checkAgent checks that the actor can see and manage the calendar sync of an agent:
+// the agent themselves, an administrator of the agent's agency or an admin
+func (s *CalendarSyncService) checkAgent(agentID string, actor domain.Actor) error {
+	if actor.UserID == "" {
+		return fmt.Errorf("permission denied: sign in to manage calendar sync")
+	}
+	agent, err := s.users.GetByID(agentID)
+	if err != nil {
+		return fmt.Errorf("agent not found: %s", agentID)
+	}
+	if actor.UserID == agentID || actor.Role == domain.RoleAdmin {
+		return nil
+	}
+	if agent.AgencyID != nil && actor.CanAdministerAgency(*agent.AgencyID) {
+		return nil
+	}
+	return fmt.Errorf("permission denied: cannot manage the calendar of this agent")
+}
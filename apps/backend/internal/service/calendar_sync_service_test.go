@@ -0,0 +1,263 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/gcal"
+)
+
+// memoryCalendarSync keeps calendar connections, event links and busy times in memory
+type memoryCalendarSync struct {
+	connections map[string]domain.CalendarConnection
+	links       map[string]domain.CalendarEventLink
+	blocks      map[string]domain.CalendarBusyBlock
+}
+
+func newMemoryCalendarSync() *memoryCalendarSync {
+	return &memoryCalendarSync{
+		connections: map[string]domain.CalendarConnection{},
+		links:       map[string]domain.CalendarEventLink{},
+		blocks:      map[string]domain.CalendarBusyBlock{},
+	}
+}
+
+func (m *memoryCalendarSync) SaveConnection(connection *domain.CalendarConnection) error {
+	m.connections[connection.AgentID] = *connection
+	return nil
+}
+
+func (m *memoryCalendarSync) GetConnection(agentID string) (*domain.CalendarConnection, error) {
+	connection, ok := m.connections[agentID]
+	if !ok {
+		return nil, fmt.Errorf("calendar connection not found: %s", agentID)
+	}
+	return &connection, nil
+}
+
+func (m *memoryCalendarSync) GetConnectionByState(stateHash string) (*domain.CalendarConnection, error) {
+	for _, connection := range m.connections {
+		if connection.StateHash == stateHash {
+			return &connection, nil
+		}
+	}
+	return nil, fmt.Errorf("calendar authorization not found")
+}
+
+func (m *memoryCalendarSync) ListActiveConnections(limit int) ([]domain.CalendarConnection, error) {
+	connections := []domain.CalendarConnection{}
+	for _, connection := range m.connections {
+		if connection.Status == domain.CalendarConnectionActive {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (m *memoryCalendarSync) DeleteConnection(agentID string) error {
+	delete(m.connections, agentID)
+	return nil
+}
+
+func (m *memoryCalendarSync) ListEventLinks(connectionID string) ([]domain.CalendarEventLink, error) {
+	links := []domain.CalendarEventLink{}
+	for _, link := range m.links {
+		if link.ConnectionID == connectionID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (m *memoryCalendarSync) SaveEventLink(link *domain.CalendarEventLink) error {
+	m.links[link.AppointmentID] = *link
+	return nil
+}
+
+func (m *memoryCalendarSync) DeleteEventLink(connectionID, appointmentID string) error {
+	delete(m.links, appointmentID)
+	return nil
+}
+
+func (m *memoryCalendarSync) SaveBusyBlock(block *domain.CalendarBusyBlock) error {
+	m.blocks[block.ExternalID] = *block
+	return nil
+}
+
+func (m *memoryCalendarSync) DeleteBusyBlock(connectionID, externalID string) (bool, error) {
+	_, ok := m.blocks[externalID]
+	delete(m.blocks, externalID)
+	return ok, nil
+}
+
+func (m *memoryCalendarSync) DeleteBusyBlocks(connectionID string) error {
+	m.blocks = map[string]domain.CalendarBusyBlock{}
+	return nil
+}
+
+func (m *memoryCalendarSync) ListBusyBlocks(agentID string, from, to time.Time) ([]domain.CalendarBusyBlock, error) {
+	blocks := []domain.CalendarBusyBlock{}
+	for _, block := range m.blocks {
+		if block.AgentID == agentID && block.StartsAt.Before(to) && block.EndsAt.After(from) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// fakeCalendar is a Google Calendar answering imports with the events of each sync token
+type fakeCalendar struct {
+	pages    map[string]*domain.CalendarEventPage // by sync token, "" for full imports
+	events   map[string]*domain.Appointment       // pushed, by event ID
+	revoked  bool
+	refreshs int
+}
+
+func (c *fakeCalendar) AuthorizationURL(state string) string {
+	return "https://accounts.google.com/o/oauth2/v2/auth?state=" + url.QueryEscape(state)
+}
+
+func (c *fakeCalendar) Exchange(code string) (*domain.CalendarToken, error) {
+	return &domain.CalendarToken{AccessToken: "access-" + code, RefreshToken: "refresh", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (c *fakeCalendar) Refresh(refreshToken string) (*domain.CalendarToken, error) {
+	c.refreshs++
+	if c.revoked {
+		return nil, gcal.ErrAuthorizationRevoked
+	}
+	return &domain.CalendarToken{AccessToken: "refreshed", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (c *fakeCalendar) ListEvents(accessToken, calendarID, syncToken string, from time.Time) (*domain.CalendarEventPage, error) {
+	page, ok := c.pages[syncToken]
+	if !ok {
+		return nil, gcal.ErrSyncTokenExpired
+	}
+	return page, nil
+}
+
+func (c *fakeCalendar) PutEvent(accessToken, calendarID, eventID string, appointment *domain.Appointment) (string, error) {
+	c.events[eventID] = appointment
+	return fmt.Sprintf(`"%d"`, len(c.events)), nil
+}
+
+func (c *fakeCalendar) DeleteEvent(accessToken, calendarID, eventID string) error {
+	delete(c.events, eventID)
+	return nil
+}
+
+func TestCalendarSyncService_ConnectAndSync(t *testing.T) {
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	appointments := newTestAppointmentService(now)
+	agent := domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1")
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+
+	galapagos := domain.AppointmentLocation(domain.TimeZoneGalapagos)
+	busy := domain.CalendarEvent{ExternalID: "dentist", ETag: `"1"`,
+		StartsAt: time.Date(2025, 9, 20, 10, 0, 0, 0, galapagos), EndsAt: time.Date(2025, 9, 20, 11, 0, 0, 0, galapagos)}
+	calendar := &fakeCalendar{
+		pages: map[string]*domain.CalendarEventPage{
+			"": {Events: []domain.CalendarEvent{busy}, NextSyncToken: "sync-1", CalendarName: "maria@example.com"},
+		},
+		events: map[string]*domain.Appointment{},
+	}
+	repo := newMemoryCalendarSync()
+	svc := NewCalendarSyncService(repo, calendar, appointments.repo, appointments.users, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+	appointments.SetCalendarSync(svc)
+
+	visit, err := appointments.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "visit", StartsAt: "2025-09-21T10:00"}, agent)
+	require.NoError(t, err)
+
+	_, err = svc.Connect("agent-1", agency)
+	assert.ErrorContains(t, err, "permission denied", "agencies cannot connect the calendar of their agents")
+	consent, err := svc.Connect("agent-1", agent)
+	require.NoError(t, err)
+	consentURL, err := url.Parse(consent)
+	require.NoError(t, err)
+
+	_, err = svc.CompleteAuthorization("cst_forged", "code")
+	assert.ErrorContains(t, err, "invalid authorization state")
+	connection, err := svc.CompleteAuthorization(consentURL.Query().Get("state"), "code")
+	require.NoError(t, err)
+	assert.Equal(t, domain.CalendarConnectionActive, connection.Status)
+
+	// The initial sync imports busy times and pushes the agenda
+	saved, err := svc.GetConnection("agent-1", agency)
+	require.NoError(t, err)
+	assert.Equal(t, "sync-1", saved.SyncToken)
+	assert.Equal(t, "maria@example.com", saved.CalendarName)
+	assert.Contains(t, calendar.events, domain.CalendarEventID(visit.ID))
+
+	_, err = appointments.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "visit", StartsAt: "2025-09-20T10:30"}, agent)
+	assert.ErrorContains(t, err, "conflicts with a busy time")
+	openHouse, err := appointments.Schedule("agent-1", ScheduleAppointmentRequest{PropertyID: "property-1", Kind: "open_house", StartsAt: "2025-09-22T10:00"}, agent)
+	require.NoError(t, err)
+	assert.Contains(t, calendar.events, domain.CalendarEventID(openHouse.ID), "appointments are pushed when scheduled")
+
+	// The agent moved the visit's event and booked a meeting over the open house
+	dentistCancelled := busy
+	dentistCancelled.Cancelled = true
+	calendar.pages["sync-1"] = &domain.CalendarEventPage{NextSyncToken: "sync-2", Events: []domain.CalendarEvent{
+		dentistCancelled,
+		{ExternalID: domain.CalendarEventID(visit.ID), ETag: `"edited"`, AppointmentID: visit.ID},
+		{ExternalID: "meeting", StartsAt: openHouse.StartsAt.Add(30 * time.Minute), EndsAt: openHouse.EndsAt.Add(time.Hour)},
+	}}
+	report, err := svc.Sync("agent-1", agent)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CalendarSyncReport{AgentID: "agent-1", Imported: 1, Removed: 1, Pushed: 1, Overwritten: 1}, *report)
+
+	conflicts, err := svc.Conflicts("agent-1", agent)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, openHouse.ID, conflicts[0].AppointmentID)
+
+	_, err = appointments.Cancel(openHouse.ID, agent)
+	require.NoError(t, err)
+	assert.NotContains(t, calendar.events, domain.CalendarEventID(openHouse.ID), "cancelled appointments are removed")
+
+	// An expired sync token imports the calendar again
+	report, err = svc.Sync("agent-1", agent)
+	require.NoError(t, err)
+	assert.True(t, report.FullImport)
+	assert.Equal(t, "sync-1", repo.connections["agent-1"].SyncToken)
+}
+
+func TestCalendarSyncService_RevokedAccess(t *testing.T) {
+	now := time.Date(2025, 9, 13, 12, 0, 0, 0, time.UTC)
+	appointments := newTestAppointmentService(now)
+	calendar := &fakeCalendar{pages: map[string]*domain.CalendarEventPage{"": {}}, events: map[string]*domain.Appointment{}, revoked: true}
+	repo := newMemoryCalendarSync()
+	svc := NewCalendarSyncService(repo, calendar, appointments.repo, appointments.users, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+
+	expired := now.Add(-time.Minute)
+	connection := domain.NewCalendarConnection("connection-1", "agent-1", now)
+	connection.Status = domain.CalendarConnectionActive
+	connection.AccessToken, connection.RefreshToken, connection.TokenExpiresAt = "access", "refresh", &expired
+	require.NoError(t, repo.SaveConnection(connection))
+	require.NoError(t, repo.SaveBusyBlock(&domain.CalendarBusyBlock{AgentID: "agent-1", ExternalID: "e1",
+		StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)}))
+
+	synced, err := svc.SyncAll()
+	require.NoError(t, err)
+	assert.Equal(t, 0, synced)
+	assert.Equal(t, 1, calendar.refreshs)
+
+	revoked := repo.connections["agent-1"]
+	assert.Equal(t, domain.CalendarConnectionRevoked, revoked.Status)
+	assert.Empty(t, revoked.RefreshToken)
+	assert.Empty(t, repo.blocks, "busy times of revoked calendars no longer block the agent")
+
+	_, err = svc.Sync("agent-1", domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "connect the calendar again")
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/voice"
+)
+
+// callUnavailableMessage is read to callers the proxy number no longer connects
+const callUnavailableMessage = "Este número ya no está disponible. Comunícate con tu contacto desde la plataforma."
+
+// CallApplications retrieves the inquiries behind leads, e.g. repository.RentalApplicationRepository
+type CallApplications interface {
+	GetByID(id string) (*domain.RentalApplication, error)
+}
+
+// CallMaskingService lets the buyer and agent of a lead call each other without
+// exposing their numbers. Each lead borrows a proxy number from a pool of Twilio
+// numbers; Twilio asks where to forward calls to it, and the caller's number tells
+// which party is calling. Calls are recorded for the lead timeline.
+type CallMaskingService struct {
+	repo         repository.CallMaskingRepository
+	leads        repository.LeadRoutingRepository
+	applications CallApplications
+	users        UserLookup
+	numbers      []string
+	sessionTTL   time.Duration
+	webhookURL   string
+	authToken    string
+	sla          LeadResponseTracker
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewCallMaskingService creates a new call masking service. Calls are unavailable until
+// proxy numbers and the Twilio webhook are set.
+func NewCallMaskingService(repo repository.CallMaskingRepository, leads repository.LeadRoutingRepository, applications CallApplications, users UserLookup, logger *log.Logger) *CallMaskingService {
+	return &CallMaskingService{
+		repo:         repo,
+		leads:        leads,
+		applications: applications,
+		users:        users,
+		sessionTTL:   domain.DefaultCallSessionTTL,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetProxyNumbers sets the pool of numbers lent to leads, in E.164 format, and how long
+// a lead keeps its number
+func (s *CallMaskingService) SetProxyNumbers(numbers []string, ttl time.Duration) {
+	s.numbers = numbers
+	if ttl > 0 {
+		s.sessionTTL = ttl
+	}
+}
+
+// SetTwilioWebhook sets the public URL of the /api/calls endpoints Twilio posts calls
+// to, and the auth token their requests are signed with
+func (s *CallMaskingService) SetTwilioWebhook(webhookURL, authToken string) {
+	s.webhookURL = strings.TrimRight(webhookURL, "/")
+	s.authToken = authToken
+}
+
+// SetResponseTracker counts calls between the buyer and agent of a lead as answering it:
+// calls the agent places, and buyer calls the agent picks up
+func (s *CallMaskingService) SetResponseTracker(sla LeadResponseTracker) {
+	s.sla = sla
+}
+
+// GetCallSession returns the proxy number the buyer and agent of a lead call each other
+// through, lending the lead a number when it has none
+func (s *CallMaskingService) GetCallSession(leadID string, actor domain.Actor) (*domain.CallSession, error) {
+	if len(s.numbers) == 0 || s.webhookURL == "" {
+		return nil, fmt.Errorf("call masking unavailable: no proxy numbers are configured")
+	}
+
+	lead, err := s.leads.GetAssignment(leadID)
+	if err != nil {
+		return nil, err
+	}
+	application, err := s.applications.GetByID(lead.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID != application.ApplicantID && (lead.AgentID == nil || actor.UserID != *lead.AgentID) {
+		return nil, fmt.Errorf("permission denied: only the lead's buyer and agent can call each other")
+	}
+	if lead.AgentID == nil {
+		return nil, fmt.Errorf("invalid call: the lead has no agent yet")
+	}
+
+	now := s.now()
+	current, err := s.repo.GetActiveSession(leadID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+	if current != nil {
+		if current.IsActive(now) {
+			return current, nil
+		}
+		current.Close(now)
+		if err := s.repo.CloseSession(current); err != nil {
+			return nil, err
+		}
+	}
+
+	buyer, err := s.users.GetByID(application.ApplicantID)
+	if err != nil {
+		return nil, err
+	}
+	agent, err := s.users.GetByID(*lead.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := domain.NewCallSession(lead, buyer, agent, "", s.sessionTTL, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid call: %w", err)
+	}
+	busy, err := s.repo.ListBusyNumbers([]string{session.BuyerPhone, session.AgentPhone}, now)
+	if err != nil {
+		return nil, err
+	}
+	if session.ProxyNumber, err = domain.PickProxyNumber(s.numbers, busy); err != nil {
+		return nil, fmt.Errorf("call masking unavailable: %w", err)
+	}
+
+	created, err := s.repo.CreateSession(session)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Another request lent the lead a number meanwhile
+		return s.repo.GetActiveSession(leadID)
+	}
+
+	s.logger.Printf("Lead %s lent proxy number %s until %s", leadID, session.ProxyNumber, session.ExpiresAt.Format(time.RFC3339))
+	return session, nil
+}
+
+// AnswerCall handles Twilio's request for instructions on a call to a proxy number and
+// returns the TwiML forwarding it to the other party of the caller's session
+func (s *CallMaskingService) AnswerCall(params url.Values, signature string) ([]byte, error) {
+	if err := s.verifyWebhook("/voice", params, signature); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	proxyNumber, caller, callSID := params.Get("To"), params.Get("From"), params.Get("CallSid")
+	if callSID == "" {
+		return nil, fmt.Errorf("invalid call: CallSid is required")
+	}
+	session, err := s.repo.FindSession(proxyNumber, caller, now)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return voice.RejectResponse(callUnavailableMessage)
+		}
+		return nil, err
+	}
+	callerRole, callee, err := session.Forward(caller, now)
+	if err != nil {
+		return voice.RejectResponse(callUnavailableMessage)
+	}
+
+	if err := s.repo.CreateCall(domain.NewLeadCall(session, callSID, callerRole, now)); err != nil {
+		return nil, err
+	}
+	if callerRole == domain.CallerAgent {
+		s.recordResponse(session.LeadID)
+	}
+	return voice.DialResponse(session.ProxyNumber, callee, s.webhookURL+"/status", domain.MaxMaskedCallDuration)
+}
+
+// RecordCallStatus handles Twilio reporting how a forwarded call ended
+func (s *CallMaskingService) RecordCallStatus(params url.Values, signature string) ([]byte, error) {
+	if err := s.verifyWebhook("/status", params, signature); err != nil {
+		return nil, err
+	}
+
+	call, err := s.repo.GetCallBySID(params.Get("CallSid"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.logger.Printf("Status of unknown call %s ignored", params.Get("CallSid"))
+			return voice.EmptyResponse()
+		}
+		return nil, err
+	}
+	duration, _ := strconv.Atoi(params.Get("DialCallDuration"))
+	if err := call.Finish(params.Get("DialCallStatus"), duration, s.now()); err != nil {
+		return nil, fmt.Errorf("invalid call status: %w", err)
+	}
+	if err := s.repo.FinishCall(call); err != nil {
+		return nil, err
+	}
+	if call.CallerRole == domain.CallerBuyer && call.Status == domain.LeadCallCompleted {
+		s.recordResponse(call.LeadID)
+	}
+	return voice.EmptyResponse()
+}
+
+// ListLeadCalls lists the calls of a lead, oldest first
+func (s *CallMaskingService) ListLeadCalls(leadID string) ([]domain.LeadCall, error) {
+	return s.repo.ListLeadCalls(leadID)
+}
+
+// recordResponse counts a call as the agent answering its lead. Errors are logged so
+// Twilio still gets its instructions.
+func (s *CallMaskingService) recordResponse(leadID string) {
+	if s.sla == nil {
+		return
+	}
+	lead, err := s.leads.GetAssignment(leadID)
+	if err == nil && lead.AgentID != nil {
+		err = s.sla.RecordLeadResponse(lead, domain.LeadResponseCall, *lead.AgentID)
+	}
+	if err != nil {
+		s.logger.Printf("Error recording call response to lead %s: %v", leadID, err)
+	}
+}
+
+// verifyWebhook checks that a request to the endpoint under the webhook URL comes from Twilio
+func (s *CallMaskingService) verifyWebhook(endpoint string, params url.Values, signature string) error {
+	if s.webhookURL == "" || s.authToken == "" {
+		return fmt.Errorf("call masking unavailable: the Twilio webhook is not configured")
+	}
+	if !voice.ValidateSignature(s.authToken, s.webhookURL+endpoint, params, signature) {
+		return fmt.Errorf("permission denied: invalid Twilio signature")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/voice"
+)
+
+// memoryCallMasking keeps call sessions and calls in memory
+type memoryCallMasking struct {
+	sessions map[string]*domain.CallSession
+	calls    map[string]*domain.LeadCall
+}
+
+func (m *memoryCallMasking) CreateSession(session *domain.CallSession) (bool, error) {
+	if _, err := m.GetActiveSession(session.LeadID); err == nil {
+		return false, nil
+	}
+	copied := *session
+	m.sessions[session.ID] = &copied
+	return true, nil
+}
+
+func (m *memoryCallMasking) GetActiveSession(leadID string) (*domain.CallSession, error) {
+	for _, session := range m.sessions {
+		if session.LeadID == leadID && session.Status == domain.CallSessionActive {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("call session not found for lead: %s", leadID)
+}
+
+func (m *memoryCallMasking) FindSession(proxyNumber, caller string, now time.Time) (*domain.CallSession, error) {
+	for _, session := range m.sessions {
+		if session.ProxyNumber == proxyNumber && session.Status == domain.CallSessionActive && now.Before(session.ExpiresAt) &&
+			(session.BuyerPhone == caller || session.AgentPhone == caller) {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("call session not found")
+}
+
+func (m *memoryCallMasking) ListBusyNumbers(phones []string, now time.Time) ([]string, error) {
+	numbers := []string{}
+	for _, session := range m.sessions {
+		if session.Status != domain.CallSessionActive || !now.Before(session.ExpiresAt) {
+			continue
+		}
+		for _, phone := range phones {
+			if session.BuyerPhone == phone || session.AgentPhone == phone {
+				numbers = append(numbers, session.ProxyNumber)
+			}
+		}
+	}
+	return numbers, nil
+}
+
+func (m *memoryCallMasking) CloseSession(session *domain.CallSession) error {
+	copied := *session
+	m.sessions[session.ID] = &copied
+	return nil
+}
+
+func (m *memoryCallMasking) CreateCall(call *domain.LeadCall) error {
+	if _, ok := m.calls[call.CallSID]; !ok {
+		copied := *call
+		m.calls[call.CallSID] = &copied
+	}
+	return nil
+}
+
+func (m *memoryCallMasking) GetCallBySID(callSID string) (*domain.LeadCall, error) {
+	call, ok := m.calls[callSID]
+	if !ok {
+		return nil, fmt.Errorf("lead call not found: %s", callSID)
+	}
+	copied := *call
+	return &copied, nil
+}
+
+func (m *memoryCallMasking) FinishCall(call *domain.LeadCall) error {
+	copied := *call
+	m.calls[call.CallSID] = &copied
+	return nil
+}
+
+func (m *memoryCallMasking) ListLeadCalls(leadID string) ([]domain.LeadCall, error) {
+	calls := []domain.LeadCall{}
+	for _, call := range m.calls {
+		if call.LeadID == leadID {
+			calls = append(calls, *call)
+		}
+	}
+	return calls, nil
+}
+
+type memoryApplications map[string]*domain.RentalApplication
+
+func (a memoryApplications) GetByID(id string) (*domain.RentalApplication, error) {
+	if application, ok := a[id]; ok {
+		return application, nil
+	}
+	return nil, fmt.Errorf("rental application not found: %s", id)
+}
+
+const testCallWebhook = "https://api.example.com/api/calls"
+
+func newCallMaskingFixture(t *testing.T, now time.Time) (*CallMaskingService, *memoryCallMasking, *memoryLeadRouting) {
+	leads := newMemoryLeadRouting()
+	applications := memoryApplications{}
+	for i, buyerID := range []string{"buyer-1", "buyer-1", "buyer-2"} {
+		applicationID := fmt.Sprintf("app-%d", i)
+		applications[applicationID] = &domain.RentalApplication{ID: applicationID, ApplicantID: buyerID}
+		lead := domain.NewLeadAssignment(applicationID, "prop-1", "agency-1", nil, "agent-1", domain.LeadScore{}, now)
+		lead.ID = fmt.Sprintf("lead-%d", i)
+		require.NoError(t, leads.CreateAssignment(lead))
+	}
+	phone := func(number string) *string { return &number }
+	users := memoryUsers{
+		"buyer-1": {ID: "buyer-1", Phone: phone("0991234567")},
+		"buyer-2": {ID: "buyer-2"},
+		"agent-1": {ID: "agent-1", Phone: phone("0987654321")},
+	}
+
+	repo := &memoryCallMasking{sessions: map[string]*domain.CallSession{}, calls: map[string]*domain.LeadCall{}}
+	svc := NewCallMaskingService(repo, leads, applications, users, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+	svc.SetProxyNumbers([]string{"+593991110000", "+593991110001"}, 0)
+	svc.SetTwilioWebhook(testCallWebhook+"/", "twilio-token")
+	return svc, repo, leads
+}
+
+func TestCallMaskingService_GetCallSession(t *testing.T) {
+	now := time.Date(2025, 9, 24, 10, 0, 0, 0, time.UTC)
+	svc, repo, _ := newCallMaskingFixture(t, now)
+	buyer := domain.NewActor("buyer-1", string(domain.RoleBuyer), "")
+	agent := domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1")
+
+	_, err := svc.GetCallSession("lead-0", domain.NewActor("buyer-2", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.GetCallSession("lead-2", agent)
+	assert.ErrorContains(t, err, "invalid call: the buyer has no phone number")
+
+	session, err := svc.GetCallSession("lead-0", buyer)
+	require.NoError(t, err)
+	assert.Equal(t, "+593991110000", session.ProxyNumber)
+	again, err := svc.GetCallSession("lead-0", agent)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, again.ID, "both parties share the lead's session")
+
+	// Another lead of the same buyer and agent needs another number to tell them apart
+	other, err := svc.GetCallSession("lead-1", buyer)
+	require.NoError(t, err)
+	assert.Equal(t, "+593991110001", other.ProxyNumber)
+
+	// Expired sessions are closed and their number lent again
+	svc.now = func() time.Time { return now.Add(domain.DefaultCallSessionTTL) }
+	renewed, err := svc.GetCallSession("lead-0", buyer)
+	require.NoError(t, err)
+	assert.NotEqual(t, session.ID, renewed.ID)
+	assert.Equal(t, domain.CallSessionClosed, repo.sessions[session.ID].Status)
+}
+
+func TestCallMaskingService_ForwardCalls(t *testing.T) {
+	now := time.Date(2025, 9, 24, 10, 0, 0, 0, time.UTC)
+	svc, _, leads := newCallMaskingFixture(t, now)
+	_, err := svc.GetCallSession("lead-0", domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	require.NoError(t, err)
+
+	incoming := url.Values{"CallSid": {"CA1"}, "From": {"+593991234567"}, "To": {"+593991110000"}}
+	_, err = svc.AnswerCall(incoming, "forged")
+	assert.ErrorContains(t, err, "permission denied")
+
+	twiml, err := svc.AnswerCall(incoming, voice.Sign("twilio-token", testCallWebhook+"/voice", incoming))
+	require.NoError(t, err)
+	assert.Contains(t, string(twiml), `<Dial callerId="+593991110000" action="https://api.example.com/api/calls/status"`)
+	assert.Contains(t, string(twiml), `<Number>+593987654321</Number>`, "the buyer reaches the agent")
+
+	stranger := url.Values{"CallSid": {"CA2"}, "From": {"+593990000000"}, "To": {"+593991110000"}}
+	twiml, err = svc.AnswerCall(stranger, voice.Sign("twilio-token", testCallWebhook+"/voice", stranger))
+	require.NoError(t, err)
+	assert.Contains(t, string(twiml), "<Hangup>")
+
+	status := url.Values{"CallSid": {"CA1"}, "DialCallStatus": {"completed"}, "DialCallDuration": {"95"}}
+	_, err = svc.RecordCallStatus(status, voice.Sign("twilio-token", testCallWebhook+"/status", status))
+	require.NoError(t, err)
+
+	// The call shows on the lead timeline
+	pipelines := &memoryLeadPipelines{pipelines: map[string]*domain.LeadPipeline{}, leads: leads}
+	pipelineService := NewLeadPipelineService(pipelines, leads, log.New(&bytes.Buffer{}, "", 0))
+	pipelineService.SetCallLog(svc)
+	timeline, err := pipelineService.GetLeadTimeline("lead-0", domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	require.NoError(t, err)
+	require.Len(t, timeline, 2)
+	assert.Equal(t, domain.LeadTimelineCall, timeline[1].Kind)
+	assert.Equal(t, domain.CallerBuyer, timeline[1].Call.CallerRole)
+	assert.Equal(t, domain.LeadCallCompleted, timeline[1].Call.Status)
+	assert.Equal(t, 95, timeline[1].Call.DurationSeconds)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/routing"
+)
+
+// MaxCommuteSortProperties caps how many listings of a result page are routed when
+// sorting by commute, keeping provider quotas predictable
+const MaxCommuteSortProperties = 50
+
+// CommuteService estimates commutes from listings to a destination, such as the
+// buyer's office, with the configured routing provider
+type CommuteService struct {
+	propertyRepo repository.PropertyRepository
+	router       routing.Router
+	logger       *log.Logger
+}
+
+// NewCommuteService creates a commute service. Without a router, typically when
+// ROUTING_PROVIDER is none, commutes are unavailable.
+func NewCommuteService(propertyRepo repository.PropertyRepository, router routing.Router, logger *log.Logger) *CommuteService {
+	return &CommuteService{
+		propertyRepo: propertyRepo,
+		router:       router,
+		logger:       logger,
+	}
+}
+
+// GetCommute estimates the commute from a property to a destination by car and public
+// transport, or only in mode when set. Modes the provider does not route have no
+// estimate.
+func (s *CommuteService) GetCommute(propertyID string, to domain.GeoPoint, mode string) (*domain.Commute, error) {
+	if s.router == nil {
+		return nil, fmt.Errorf("commute unavailable: no routing provider is configured")
+	}
+	if mode != "" && !domain.IsValidCommuteMode(mode) {
+		return nil, fmt.Errorf("invalid mode: must be driving or transit")
+	}
+	if err := to.Validate(); err != nil {
+		return nil, err
+	}
+
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	from, ok := domain.PropertyLocation(property)
+	if !ok {
+		return nil, fmt.Errorf("commute unavailable: property %s has no coordinates", propertyID)
+	}
+
+	commute, failures := s.estimate(property.ID, from, to, mode)
+	if failures > 0 && commute.Driving == nil && commute.Transit == nil {
+		return nil, fmt.Errorf("failed to estimate commute with %s", s.router.Name())
+	}
+	return commute, nil
+}
+
+// SortByCommute orders properties by their commute to a destination, fastest first,
+// in mode or the fastest mode when empty. It is meant for a page of search results:
+// only the first MaxCommuteSortProperties are routed, and listings without an
+// estimate keep their order after the others.
+func (s *CommuteService) SortByCommute(properties []domain.Property, to domain.GeoPoint, mode string) []domain.Property {
+	if s.router == nil || len(properties) == 0 {
+		return properties
+	}
+
+	minutes := make(map[string]int, len(properties))
+	for i := range properties {
+		if i >= MaxCommuteSortProperties {
+			break
+		}
+		from, ok := domain.PropertyLocation(&properties[i])
+		if !ok {
+			continue
+		}
+		commute, _ := s.estimate(properties[i].ID, from, to, mode)
+		if value, ok := commute.Minutes(mode); ok {
+			minutes[properties[i].ID] = value
+		}
+	}
+
+	sorted := append([]domain.Property{}, properties...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, hasA := minutes[sorted[i].ID]
+		b, hasB := minutes[sorted[j].ID]
+		if hasA != hasB {
+			return hasA
+		}
+		return hasA && a < b
+	})
+	return sorted
+}
+
+// estimate routes a property's commute in mode, or by car and public transport when
+// empty. Modes the router cannot route have no estimate; it returns how many modes
+// failed, which are logged.
+func (s *CommuteService) estimate(propertyID string, from, to domain.GeoPoint, mode string) (*domain.Commute, int) {
+	commute := &domain.Commute{PropertyID: propertyID, From: from, To: to}
+	failures := 0
+	for _, m := range []string{domain.CommuteModeDriving, domain.CommuteModeTransit} {
+		if mode != "" && m != mode {
+			continue
+		}
+
+		estimate, err := s.router.Route(from, to, m)
+		if err == routing.ErrModeNotSupported || err == routing.ErrNoRoute {
+			continue
+		}
+		if err != nil {
+			s.logger.Printf("Error routing %s commute from property %s: %v", m, propertyID, err)
+			failures++
+			continue
+		}
+
+		if m == domain.CommuteModeDriving {
+			commute.Driving = estimate
+		} else {
+			commute.Transit = estimate
+		}
+	}
+	return commute, failures
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/routing"
+)
+
+// distanceRouter drives one minute per 0.01 degrees of latitude and routes no transit
+type distanceRouter struct {
+	fail bool
+}
+
+func (r *distanceRouter) Name() string { return "distance" }
+
+func (r *distanceRouter) Route(from, to domain.GeoPoint, mode string) (*domain.RouteEstimate, error) {
+	if r.fail {
+		return nil, fmt.Errorf("provider unavailable")
+	}
+	if mode == domain.CommuteModeTransit {
+		return nil, routing.ErrModeNotSupported
+	}
+	minutes := (from.Lat - to.Lat) * 100
+	if minutes < 0 {
+		minutes = -minutes
+	}
+	return domain.NewRouteEstimate(mode, minutes*60, minutes*500, r.Name()), nil
+}
+
+func commuteProperty(id string, lat float64) *domain.Property {
+	lng := -78.48
+	return &domain.Property{ID: id, Latitude: &lat, Longitude: &lng}
+}
+
+func TestCommuteService(t *testing.T) {
+	properties := &MockPropertyRepository{}
+	properties.On("GetByID", "prop-1").Return(commuteProperty("prop-1", -0.10), nil)
+	properties.On("GetByID", "prop-2").Return(&domain.Property{ID: "prop-2"}, nil)
+	router := &distanceRouter{}
+	var logs bytes.Buffer
+	svc := NewCommuteService(properties, router, log.New(&logs, "", 0))
+	office := domain.GeoPoint{Lat: -0.20, Lng: -78.48}
+
+	commute, err := svc.GetCommute("prop-1", office, "")
+	require.NoError(t, err)
+	require.NotNil(t, commute.Driving)
+	assert.Equal(t, 10, commute.Driving.DurationMinutes)
+	assert.Nil(t, commute.Transit)
+	minutes, ok := commute.Minutes("")
+	assert.True(t, ok)
+	assert.Equal(t, 10, minutes)
+
+	_, err = svc.GetCommute("prop-2", office, "")
+	assert.ErrorContains(t, err, "no coordinates")
+
+	_, err = svc.GetCommute("prop-1", office, "walking")
+	assert.ErrorContains(t, err, "invalid mode")
+
+	router.fail = true
+	_, err = svc.GetCommute("prop-1", office, "")
+	assert.ErrorContains(t, err, "failed to estimate commute")
+
+	_, err = NewCommuteService(properties, nil, log.New(&logs, "", 0)).GetCommute("prop-1", office, "")
+	assert.ErrorContains(t, err, "no routing provider")
+}
+
+func TestCommuteService_SortByCommute(t *testing.T) {
+	var logs bytes.Buffer
+	svc := NewCommuteService(&MockPropertyRepository{}, &distanceRouter{}, log.New(&logs, "", 0))
+
+	sorted := svc.SortByCommute([]domain.Property{
+		*commuteProperty("far", -0.50),
+		{ID: "unlocated"},
+		*commuteProperty("near", -0.22),
+	}, domain.GeoPoint{Lat: -0.20, Lng: -78.48}, "")
+
+	ids := []string{}
+	for _, property := range sorted {
+		ids = append(ids, property.ID)
+	}
+	assert.Equal(t, []string{"near", "far", "unlocated"}, ids)
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// SaveContractTemplateRequest describes a new version of a contract template
+type SaveContractTemplateRequest struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Activate bool   `json:"activate"`
+}
+
+// GenerateContractRequest describes a contract to generate for a property
+type GenerateContractRequest struct {
+	// TemplateName defaults to the first active template of Type
+	TemplateName string `json:"template_name,omitempty"`
+	Type         string `json:"type"`
+	// Owner defaults to the property's owner
+	Owner *domain.ContractParty `json:"owner,omitempty"`
+	// Tenant is the tenant or buyer; TenantID fills it from a registered user
+	Tenant   domain.ContractParty `json:"tenant"`
+	TenantID string               `json:"tenant_id,omitempty"`
+	// Terms default to the listing's rent or price
+	Terms domain.ContractTerms `json:"terms"`
+}
+
+// ContractService manages versioned contract templates and generates contracts for
+// properties as private PDF documents
+type ContractService struct {
+	templateRepo repository.ContractTemplateRepository
+	documentRepo repository.PropertyDocumentRepository
+	propertyRepo repository.PropertyRepository
+	userRepo     *repository.UserRepository
+	storage      storage.ImageStorage
+	renderer     processors.PDFRenderer
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewContractService creates a new contract service. Generated contracts are written
+// through the same storage as property documents. userRepo may be nil, in which case
+// the parties must be given in each request.
+func NewContractService(
+	templateRepo repository.ContractTemplateRepository,
+	documentRepo repository.PropertyDocumentRepository,
+	propertyRepo repository.PropertyRepository,
+	userRepo *repository.UserRepository,
+	storage storage.ImageStorage,
+	renderer processors.PDFRenderer,
+	logger *log.Logger,
+) *ContractService {
+	if renderer == nil {
+		renderer = processors.NewTextPDFRenderer()
+	}
+
+	return &ContractService{
+		templateRepo: templateRepo,
+		documentRepo: documentRepo,
+		propertyRepo: propertyRepo,
+		userRepo:     userRepo,
+		storage:      storage,
+		renderer:     renderer,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// ListTemplates lists the active version of every template, optionally of one type
+func (s *ContractService) ListTemplates(contractType string) ([]domain.ContractTemplate, error) {
+	return s.templateRepo.ListActive(contractType)
+}
+
+// ListTemplateVersions lists every version of a template for admins
+func (s *ContractService) ListTemplateVersions(name string, actor domain.Actor) ([]domain.ContractTemplate, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can manage contract templates")
+	}
+	return s.templateRepo.ListVersions(name)
+}
+
+// SaveTemplate validates a template and saves it as the next version of its name
+func (s *ContractService) SaveTemplate(req SaveContractTemplateRequest, actor domain.Actor) (*domain.ContractTemplate, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can manage contract templates")
+	}
+
+	tmpl, err := domain.NewContractTemplate(req.Name, req.Type, req.Title, req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	tmpl.CreatedBy = actor.UserID
+
+	versions, err := s.templateRepo.ListVersions(tmpl.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) > 0 && versions[0].Type != tmpl.Type {
+		return nil, fmt.Errorf("invalid template: %s is a %s template", tmpl.Name, versions[0].Type)
+	}
+
+	if err := s.templateRepo.CreateVersion(tmpl); err != nil {
+		return nil, err
+	}
+	if req.Activate || len(versions) == 0 {
+		if err := s.templateRepo.Activate(tmpl.ID); err != nil {
+			return nil, err
+		}
+		tmpl.Active = true
+	}
+
+	s.logger.Printf("Contract template %s v%d saved by %s", tmpl.Name, tmpl.Version, actor.UserID)
+	return tmpl, nil
+}
+
+// ActivateTemplate makes a version the active one of its template, e.g. to roll back
+func (s *ContractService) ActivateTemplate(id string, actor domain.Actor) (*domain.ContractTemplate, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can manage contract templates")
+	}
+
+	if err := s.templateRepo.Activate(id); err != nil {
+		return nil, err
+	}
+	tmpl, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Contract template %s v%d activated by %s", tmpl.Name, tmpl.Version, actor.UserID)
+	return tmpl, nil
+}
+
+// GenerateContract renders a contract for a property managed by the actor and stores it
+// as a private contract document of the property
+func (s *ContractService) GenerateContract(propertyID string, req GenerateContractRequest, actor domain.Actor) (*domain.PropertyDocument, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can generate contracts")
+	}
+
+	tmpl, err := s.template(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.contractData(property, tmpl.Type, req)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.documentRepo.CountByProperty(property.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxDocumentsPerProperty {
+		return nil, fmt.Errorf("maximum documents per property exceeded: %d", domain.MaxDocumentsPerProperty)
+	}
+
+	text, err := tmpl.Render(*data)
+	if err != nil {
+		return nil, err
+	}
+	pdf, err := s.renderer.Render(tmpl.Title, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render contract: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s-v%d-%s.pdf", tmpl.Name, tmpl.Version, data.Date.Format("20060102"))
+	document, err := domain.NewPropertyDocument(property.ID, domain.DocumentTypeContract, domain.DocumentVisibilityPrivate,
+		domain.GeneratedContractTitle(tmpl, data.Tenant), fileName, "application/pdf", int64(len(pdf)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	storedPath, err := s.storage.Store(pdf, document.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store contract: %w", err)
+	}
+	document.StoragePath = storedPath
+	document.UploadedBy = actor.UserID
+	document.TemplateID = &tmpl.ID
+
+	if err := s.documentRepo.Create(document); err != nil {
+		if delErr := s.storage.Delete(storedPath); delErr != nil {
+			s.logger.Printf("Error deleting contract file %s: %v", storedPath, delErr)
+		}
+		return nil, err
+	}
+
+	s.logger.Printf("Contract %s generated for property %s from template %s v%d", document.ID, property.ID, tmpl.Name, tmpl.Version)
+	return document, nil
+}
+
+// template returns the active template requested, or the first active one of the type
+func (s *ContractService) template(req GenerateContractRequest) (*domain.ContractTemplate, error) {
+	if req.TemplateName != "" {
+		tmpl, err := s.templateRepo.GetActive(req.TemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if req.Type != "" && tmpl.Type != req.Type {
+			return nil, fmt.Errorf("invalid contract type: %s is a %s template", tmpl.Name, tmpl.Type)
+		}
+		return tmpl, nil
+	}
+
+	if req.Type != domain.ContractTypeRental && req.Type != domain.ContractTypeSaleReservation {
+		return nil, fmt.Errorf("invalid contract type: must be rental or sale_reservation")
+	}
+	templates, err := s.templateRepo.ListActive(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("template not found for contract type: %s", req.Type)
+	}
+	return &templates[0], nil
+}
+
+// contractData fills the template variables, defaulting terms to the listing
+func (s *ContractService) contractData(property *domain.Property, contractType string, req GenerateContractRequest) (*domain.ContractData, error) {
+	now := s.now()
+	data := &domain.ContractData{Property: property, Terms: req.Terms, Date: now}
+
+	switch {
+	case req.Owner != nil:
+		data.Owner = *req.Owner
+	case property.OwnerID != nil:
+		owner, err := s.party(*property.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		data.Owner = *owner
+	}
+
+	data.Tenant = req.Tenant
+	if req.TenantID != "" {
+		tenant, err := s.party(req.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		data.Tenant = *tenant
+	}
+	if strings.TrimSpace(data.Owner.Name) == "" || strings.TrimSpace(data.Tenant.Name) == "" {
+		return nil, fmt.Errorf("invalid contract: owner and tenant names are required")
+	}
+
+	if data.Terms.StartDate.IsZero() {
+		data.Terms.StartDate = now
+	}
+	switch contractType {
+	case domain.ContractTypeRental:
+		if data.Terms.MonthlyRent <= 0 {
+			data.Terms.MonthlyRent = monthlyRent(property)
+		}
+		if data.Terms.MonthlyRent <= 0 {
+			return nil, fmt.Errorf("invalid contract: monthly rent is required for properties not listed for rent")
+		}
+		if data.Terms.Deposit <= 0 {
+			data.Terms.Deposit = data.Terms.MonthlyRent
+		}
+		if data.Terms.DurationMonths <= 0 {
+			data.Terms.DurationMonths = 12
+		}
+	case domain.ContractTypeSaleReservation:
+		if data.Terms.SalePrice <= 0 {
+			data.Terms.SalePrice = property.Price
+		}
+		if data.Terms.ReservationAmount <= 0 || data.Terms.ReservationAmount > data.Terms.SalePrice {
+			return nil, fmt.Errorf("invalid contract: reservation amount must be positive and at most the sale price")
+		}
+	}
+
+	return data, nil
+}
+
+// party loads a contract party from a registered user
+func (s *ContractService) party(userID string) (*domain.ContractParty, error) {
+	if s.userRepo == nil {
+		return nil, fmt.Errorf("invalid contract: party details are required")
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	party := &domain.ContractParty{
+		Name:  strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Email: user.Email,
+	}
+	if user.Cedula != nil {
+		party.Cedula = *user.Cedula
+	}
+	if user.Phone != nil {
+		party.Phone = *user.Phone
+	}
+	return party, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/storage"
+)
+
+// memoryTemplateRepository is an in-memory ContractTemplateRepository
+type memoryTemplateRepository struct {
+	templates map[string]domain.ContractTemplate
+}
+
+func (r *memoryTemplateRepository) CreateVersion(tmpl *domain.ContractTemplate) error {
+	versions, _ := r.ListVersions(tmpl.Name)
+	tmpl.Version = len(versions) + 1
+	tmpl.Active = false
+	r.templates[tmpl.ID] = *tmpl
+	return nil
+}
+
+func (r *memoryTemplateRepository) GetByID(id string) (*domain.ContractTemplate, error) {
+	tmpl, ok := r.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("template not found: %s", id)
+	}
+	return &tmpl, nil
+}
+
+func (r *memoryTemplateRepository) GetActive(name string) (*domain.ContractTemplate, error) {
+	for _, tmpl := range r.templates {
+		if tmpl.Name == name && tmpl.Active {
+			return &tmpl, nil
+		}
+	}
+	return nil, fmt.Errorf("template not found or not active: %s", name)
+}
+
+func (r *memoryTemplateRepository) ListActive(contractType string) ([]domain.ContractTemplate, error) {
+	templates := []domain.ContractTemplate{}
+	for _, tmpl := range r.templates {
+		if tmpl.Active && (contractType == "" || tmpl.Type == contractType) {
+			templates = append(templates, tmpl)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+func (r *memoryTemplateRepository) ListVersions(name string) ([]domain.ContractTemplate, error) {
+	templates := []domain.ContractTemplate{}
+	for _, tmpl := range r.templates {
+		if tmpl.Name == name {
+			templates = append(templates, tmpl)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Version > templates[j].Version })
+	return templates, nil
+}
+
+func (r *memoryTemplateRepository) Activate(id string) error {
+	target, ok := r.templates[id]
+	if !ok {
+		return fmt.Errorf("template not found: %s", id)
+	}
+	for key, tmpl := range r.templates {
+		if tmpl.Name == target.Name {
+			tmpl.Active = key == id
+			r.templates[key] = tmpl
+		}
+	}
+	return nil
+}
+
+func newTestContractService(t *testing.T) (*ContractService, *memoryDocumentRepository, storage.ImageStorage) {
+	documentStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/documents", domain.MaxDocumentUploadSize)
+	require.NoError(t, err)
+
+	rent := 900.0
+	property := domain.NewProperty("Suite amoblada", "Suite en Urdesa", "Guayas", "Guayaquil", "apartment", 95000, "owner-1")
+	property.ID = "prop-1"
+	property.RentPrice = &rent
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	templates := &memoryTemplateRepository{templates: map[string]domain.ContractTemplate{}}
+	documents := &memoryDocumentRepository{documents: map[string]domain.PropertyDocument{}}
+	service := NewContractService(templates, documents, propertyRepo, nil, documentStorage, processors.NewTextPDFRenderer(), log.New(os.Stderr, "", 0))
+	return service, documents, documentStorage
+}
+
+func TestContractService_TemplateVersions(t *testing.T) {
+	service, _, _ := newTestContractService(t)
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+	req := SaveContractTemplateRequest{Name: "arriendo", Type: domain.ContractTypeRental, Title: "Contrato de arriendo", Body: "Arrendatario: {{.Tenant.Name}}"}
+
+	_, err := service.SaveTemplate(req, domain.NewActor("owner-1", string(domain.RoleOwner), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	first, err := service.SaveTemplate(req, admin)
+	require.NoError(t, err)
+	assert.True(t, first.Active, "the first version is active")
+
+	req.Body = "Inquilino: {{.Tenant.Name}}"
+	second, err := service.SaveTemplate(req, admin)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Version)
+	assert.False(t, second.Active, "new versions are drafts unless activated")
+
+	req.Type = domain.ContractTypeSaleReservation
+	_, err = service.SaveTemplate(req, admin)
+	assert.ErrorContains(t, err, "is a rental template")
+
+	_, err = service.ActivateTemplate(second.ID, admin)
+	require.NoError(t, err)
+	active, err := service.ListTemplates(domain.ContractTypeRental)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, 2, active[0].Version)
+
+	versions, err := service.ListTemplateVersions("arriendo", admin)
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+}
+
+func TestContractService_GenerateContract(t *testing.T) {
+	service, documents, documentStorage := newTestContractService(t)
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+
+	_, err := service.SaveTemplate(SaveContractTemplateRequest{
+		Name: "arriendo", Type: domain.ContractTypeRental, Title: "Contrato de arrendamiento",
+		Body: "Arrendador: {{.Owner.Name}}\nArrendatario: {{.Tenant.Name}}\nCanon: {{money .Terms.MonthlyRent}} por {{.Terms.DurationMonths}} meses",
+	}, admin)
+	require.NoError(t, err)
+
+	req := GenerateContractRequest{
+		Type:   domain.ContractTypeRental,
+		Owner:  &domain.ContractParty{Name: "Luis Andrade"},
+		Tenant: domain.ContractParty{Name: "Carla Vega", Cedula: "0912345678"},
+	}
+
+	_, err = service.GenerateContract("prop-1", req, domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	document, err := service.GenerateContract("prop-1", req, owner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DocumentTypeContract, document.Type)
+	assert.Equal(t, domain.DocumentVisibilityPrivate, document.Visibility)
+	assert.Equal(t, "Contrato de arrendamiento (v1) - Carla Vega", document.Title)
+	require.NotNil(t, document.TemplateID)
+	assert.Len(t, documents.documents, 1)
+
+	pdf, err := documentStorage.Retrieve(document.StoragePath)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+	assert.Contains(t, string(pdf), "Canon: $900.00 por 12 meses", "terms default to the listing")
+
+	req.Tenant = domain.ContractParty{}
+	_, err = service.GenerateContract("prop-1", req, owner)
+	assert.ErrorContains(t, err, "tenant names are required")
+
+	req.Tenant = domain.ContractParty{Name: "Carla Vega"}
+	req.Type = domain.ContractTypeSaleReservation
+	_, err = service.GenerateContract("prop-1", req, owner)
+	assert.ErrorContains(t, err, "template not found")
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// CSP report defaults: how often counted reports are written, how many distinct
+// violations are held between writes, and the period and size of violation lists
+const (
+	defaultCSPFlushInterval = 30 * time.Second
+	maxPendingCSPViolations = 1000
+	defaultCSPReportPeriod  = 7 * 24 * time.Hour
+	defaultCSPReportLimit   = 100
+	maxCSPReportLimit       = 500
+)
+
+// CSPReportService collects the Content-Security-Policy violations browsers report.
+// A misconfigured policy is reported by every page view, so reports are counted in
+// memory by violation and written every flush interval; past maxPendingCSPViolations
+// distinct violations, new ones are dropped until the next flush.
+type CSPReportService struct {
+	repo          repository.CSPReportRepository
+	flushInterval time.Duration
+	logger        *log.Logger
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*domain.CSPViolation // by fingerprint
+	dropped int64
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewCSPReportService creates a new CSP report service
+func NewCSPReportService(repo repository.CSPReportRepository, flushInterval time.Duration, logger *log.Logger) *CSPReportService {
+	if flushInterval <= 0 {
+		flushInterval = defaultCSPFlushInterval
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &CSPReportService{
+		repo:          repo,
+		flushInterval: flushInterval,
+		logger:        logger,
+		now:           time.Now,
+		pending:       make(map[string]*domain.CSPViolation),
+	}
+}
+
+// Record counts the reports a browser sent
+func (s *CSPReportService) Record(reports []domain.CSPReport, userAgent string) {
+	if len(reports) == 0 {
+		return
+	}
+	now := s.now().UTC()
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, report := range reports {
+		s.addLocked(domain.CSPViolation{
+			Fingerprint: report.Fingerprint(),
+			CSPReport:   report,
+			Count:       1,
+			UserAgent:   userAgent,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		})
+	}
+}
+
+// addLocked adds a violation to those not written yet. s.mu must be held.
+func (s *CSPReportService) addLocked(violation domain.CSPViolation) {
+	pending, ok := s.pending[violation.Fingerprint]
+	if !ok {
+		if len(s.pending) >= maxPendingCSPViolations {
+			s.dropped += violation.Count
+			return
+		}
+		s.pending[violation.Fingerprint] = &violation
+		return
+	}
+	pending.Count += violation.Count
+	if violation.FirstSeenAt.Before(pending.FirstSeenAt) {
+		pending.FirstSeenAt = violation.FirstSeenAt
+	}
+	if violation.LastSeenAt.After(pending.LastSeenAt) {
+		pending.LastSeenAt = violation.LastSeenAt
+		pending.UserAgent = violation.UserAgent
+	}
+}
+
+// Flush writes the violations counted since the last flush
+func (s *CSPReportService) Flush() error {
+	s.mu.Lock()
+	pending, dropped := s.pending, s.dropped
+	s.pending = make(map[string]*domain.CSPViolation)
+	s.dropped = 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.logger.Printf("Dropped %d CSP reports of new violations past %d pending", dropped, maxPendingCSPViolations)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	violations := make([]domain.CSPViolation, 0, len(pending))
+	for _, violation := range pending {
+		violations = append(violations, *violation)
+	}
+	if err := s.repo.AddViolations(violations); err != nil {
+		// Keep the counts for the next flush
+		s.mu.Lock()
+		for _, violation := range violations {
+			s.addLocked(violation)
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Violations returns the violations reported within period, 7 days by default, most
+// reported first
+func (s *CSPReportService) Violations(period time.Duration, limit int, actor domain.Actor) ([]domain.CSPViolation, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can view CSP reports")
+	}
+	if period < 0 {
+		return nil, fmt.Errorf("invalid period: must be positive")
+	}
+	if period == 0 {
+		period = defaultCSPReportPeriod
+	}
+	if limit <= 0 {
+		limit = defaultCSPReportLimit
+	}
+	if limit > maxCSPReportLimit {
+		limit = maxCSPReportLimit
+	}
+	return s.repo.ListViolations(s.now().UTC().Add(-period), limit)
+}
+
+// Start writes the reports counted on the flush interval until Stop is called
+func (s *CSPReportService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Flush(); err != nil {
+					s.logger.Printf("Failed to write CSP reports: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the flushes and writes the reports counted since the last one
+func (s *CSPReportService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	if err := s.Flush(); err != nil {
+		s.logger.Printf("Failed to write CSP reports: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryCSPReports keeps CSP violations in memory
+type memoryCSPReports struct {
+	violations map[string]domain.CSPViolation
+	err        error
+}
+
+func (m *memoryCSPReports) AddViolations(violations []domain.CSPViolation) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, v := range violations {
+		if stored, ok := m.violations[v.Fingerprint]; ok {
+			v.Count += stored.Count
+			v.FirstSeenAt = stored.FirstSeenAt
+		}
+		m.violations[v.Fingerprint] = v
+	}
+	return nil
+}
+
+func (m *memoryCSPReports) ListViolations(since time.Time, limit int) ([]domain.CSPViolation, error) {
+	violations := []domain.CSPViolation{}
+	for _, v := range m.violations {
+		if !v.LastSeenAt.Before(since) {
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+func TestCSPReportService_CountsAndFlushes(t *testing.T) {
+	repo := &memoryCSPReports{violations: map[string]domain.CSPViolation{}}
+	service := NewCSPReportService(repo, time.Minute, log.New(&bytes.Buffer{}, "", 0))
+	now := time.Date(2025, 9, 17, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	inline := domain.CSPReport{DocumentURI: "https://realty.ec/", EffectiveDirective: "script-src", BlockedURI: "inline", Disposition: domain.CSPDispositionEnforce}
+	image := domain.CSPReport{DocumentURI: "https://realty.ec/", EffectiveDirective: "img-src", BlockedURI: "https://cdn.example", Disposition: domain.CSPDispositionReport}
+	service.Record([]domain.CSPReport{inline, image}, "Firefox")
+	now = now.Add(time.Second)
+	service.Record([]domain.CSPReport{inline}, "Chrome")
+
+	// Failed writes keep the counts for the next flush
+	repo.err = errors.New("database unavailable")
+	assert.Error(t, service.Flush())
+	assert.Empty(t, repo.violations)
+
+	repo.err = nil
+	require.NoError(t, service.Flush())
+	require.Len(t, repo.violations, 2)
+	stored := repo.violations[inline.Fingerprint()]
+	assert.Equal(t, int64(2), stored.Count)
+	assert.Equal(t, "Chrome", stored.UserAgent)
+	assert.Equal(t, now.Add(-time.Second), stored.FirstSeenAt)
+	assert.Equal(t, now, stored.LastSeenAt)
+
+	// Nothing is written twice
+	require.NoError(t, service.Flush())
+	assert.Equal(t, int64(2), repo.violations[inline.Fingerprint()].Count)
+
+	admin := domain.NewActor("admin-1", "admin", "")
+	violations, err := service.Violations(0, 0, admin)
+	require.NoError(t, err)
+	assert.Len(t, violations, 2)
+
+	_, err = service.Violations(0, 0, domain.NewActor("agent-1", "agent", "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestCSPReportService_BoundsPendingViolations(t *testing.T) {
+	repo := &memoryCSPReports{violations: map[string]domain.CSPViolation{}}
+	logs := &bytes.Buffer{}
+	service := NewCSPReportService(repo, time.Minute, log.New(logs, "", 0))
+
+	for i := 0; i < maxPendingCSPViolations+5; i++ {
+		service.Record([]domain.CSPReport{{EffectiveDirective: "img-src", LineNumber: i, Disposition: domain.CSPDispositionEnforce}}, "")
+	}
+	require.NoError(t, service.Flush())
+	assert.Len(t, repo.violations, maxPendingCSPViolations)
+	assert.Contains(t, logs.String(), "Dropped 5 CSP reports")
+}
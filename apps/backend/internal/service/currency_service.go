@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// ExchangeRateSource provides the current USD exchange rates, e.g. a currency.RateCache
+type ExchangeRateSource interface {
+	// Rates returns the latest rates from USD to other currencies
+	Rates() (*domain.ExchangeRates, error)
+}
+
+// CurrencyService annotates property responses with prices converted for display.
+// Stored prices are never changed: the conversion is added next to the USD price.
+type CurrencyService struct {
+	rates  ExchangeRateSource
+	logger *log.Logger
+}
+
+// NewCurrencyService creates a new currency service
+func NewCurrencyService(rates ExchangeRateSource, logger *log.Logger) *CurrencyService {
+	return &CurrencyService{
+		rates:  rates,
+		logger: logger,
+	}
+}
+
+// GetRates returns the current exchange rates of the supported currencies
+func (s *CurrencyService) GetRates() (*domain.ExchangeRates, error) {
+	rates, err := s.rates.Rates()
+	if err != nil {
+		return nil, err
+	}
+
+	supported := &domain.ExchangeRates{
+		Base:      rates.Base,
+		Rates:     map[string]float64{},
+		Date:      rates.Date,
+		Provider:  rates.Provider,
+		FetchedAt: rates.FetchedAt,
+	}
+	for _, currency := range domain.SupportedCurrencies() {
+		if rate, err := rates.Rate(currency); err == nil {
+			supported.Rates[currency] = rate
+		}
+	}
+	return supported, nil
+}
+
+// Annotate returns a copy of a property response whose properties carry their price in
+// currency. It understands the same responses as PropertyTranslationService.Localize.
+// USD responses are returned as is, and when rates are unavailable the response is
+// served without display prices rather than failing.
+func (s *CurrencyService) Annotate(data interface{}, currency string) (interface{}, error) {
+	normalized := domain.NormalizeCurrency(currency)
+	if normalized == "" {
+		return nil, fmt.Errorf("invalid currency: %s", currency)
+	}
+	if normalized == domain.BaseCurrency {
+		return data, nil
+	}
+
+	rates, err := s.rates.Rates()
+	if err != nil {
+		s.logger.Printf("Error loading exchange rates: %v", err)
+		return data, nil
+	}
+
+	return s.annotate(data, normalized, rates), nil
+}
+
+// annotate converts the prices of the properties in data
+func (s *CurrencyService) annotate(data interface{}, currency string, rates *domain.ExchangeRates) interface{} {
+	switch value := data.(type) {
+	case *domain.Property:
+		if value == nil {
+			return data
+		}
+		property := *value
+		property.DisplayPrice = s.convert(property.Price, currency, rates)
+		return &property
+	case []domain.Property:
+		properties := make([]domain.Property, len(value))
+		for i := range value {
+			properties[i] = value[i]
+			properties[i].DisplayPrice = s.convert(value[i].Price, currency, rates)
+		}
+		return properties
+	case []repository.PropertySearchResult:
+		results := make([]repository.PropertySearchResult, len(value))
+		for i := range value {
+			results[i] = value[i]
+			results[i].Property.DisplayPrice = s.convert(value[i].Property.Price, currency, rates)
+		}
+		return results
+	case *domain.PaginatedResponse:
+		if value == nil {
+			return data
+		}
+		page := *value
+		page.Data = s.annotate(value.Data, currency, rates)
+		return &page
+	case *domain.FacetedSearchResponse:
+		if value == nil {
+			return data
+		}
+		page := *value
+		page.Data = s.annotate(value.Data, currency, rates)
+		return &page
+	default:
+		return data
+	}
+}
+
+// convert returns the display price, or nil when the provider has no rate for currency
+func (s *CurrencyService) convert(price float64, currency string, rates *domain.ExchangeRates) *domain.DisplayPrice {
+	displayPrice, err := domain.ConvertPrice(price, currency, rates)
+	if err != nil {
+		s.logger.Printf("Error converting price to %s: %v", currency, err)
+		return nil
+	}
+	return displayPrice
+}
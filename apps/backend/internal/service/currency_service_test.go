@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// staticRates is an ExchangeRateSource with fixed rates
+type staticRates struct {
+	rates *domain.ExchangeRates
+	err   error
+}
+
+func (s *staticRates) Rates() (*domain.ExchangeRates, error) {
+	return s.rates, s.err
+}
+
+func TestCurrencyService_Annotate(t *testing.T) {
+	source := &staticRates{rates: &domain.ExchangeRates{
+		Base:  domain.CurrencyUSD,
+		Rates: map[string]float64{"COP": 4000, "PEN": 3.5, "JPY": 147},
+	}}
+	service := NewCurrencyService(source, log.New(os.Stderr, "", 0))
+
+	property := *domain.NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	page := &domain.PaginatedResponse{Data: []repository.PropertySearchResult{{Property: property}}}
+
+	annotated, err := service.Annotate(page, "cop")
+	require.NoError(t, err)
+	results := annotated.(*domain.PaginatedResponse).Data.([]repository.PropertySearchResult)
+	require.NotNil(t, results[0].Property.DisplayPrice)
+	assert.Equal(t, 1000000000.0, results[0].Property.DisplayPrice.Amount)
+	assert.Equal(t, 250000.0, results[0].Property.Price, "stored prices stay in USD")
+	assert.Nil(t, page.Data.([]repository.PropertySearchResult)[0].Property.DisplayPrice, "the input must not be modified")
+
+	annotated, err = service.Annotate(&property, "USD")
+	require.NoError(t, err)
+	assert.Nil(t, annotated.(*domain.Property).DisplayPrice)
+
+	_, err = service.Annotate(&property, "XYZ")
+	assert.ErrorContains(t, err, "invalid currency")
+
+	source.err = fmt.Errorf("provider down")
+	annotated, err = service.Annotate([]domain.Property{property}, "PEN")
+	require.NoError(t, err, "responses are served without display prices when rates are unavailable")
+	assert.Nil(t, annotated.([]domain.Property)[0].DisplayPrice)
+}
+
+func TestCurrencyService_GetRates(t *testing.T) {
+	source := &staticRates{rates: &domain.ExchangeRates{
+		Base:  domain.CurrencyUSD,
+		Rates: map[string]float64{"COP": 4000, "PEN": 3.5, "JPY": 147},
+	}}
+	service := NewCurrencyService(source, log.New(os.Stderr, "", 0))
+
+	rates, err := service.GetRates()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USD": 1, "COP": 4000, "PEN": 3.5}, rates.Rates)
+}
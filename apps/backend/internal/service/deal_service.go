@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// RecordDealRequest describes a closed sale or rental
+type RecordDealRequest struct {
+	PropertyID  string    `json:"property_id"`
+	Type        string    `json:"type"`
+	FinalPrice  float64   `json:"final_price"`
+	ClosingDate time.Time `json:"closing_date"`
+	// CommissionPercent defaults to the commission of the property's agency
+	CommissionPercent *float64 `json:"commission_percent,omitempty"`
+	// AgentID defaults to the agent assigned to the property
+	AgentID string `json:"agent_id,omitempty"`
+	// ClientID is the buyer or tenant; recording it lets them review the agent
+	ClientID string `json:"client_id,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+	// RentalStart and RentalEnd (exclusive) are the dates a rental deal books, checked
+	// against the property's availability calendar
+	RentalStart *time.Time `json:"rental_start,omitempty"`
+	RentalEnd   *time.Time `json:"rental_end,omitempty"`
+}
+
+// DealService records closed deals and reports the commissions they earned
+type DealService struct {
+	dealRepo     repository.DealRepository
+	propertyRepo repository.PropertyRepository
+	agencyRepo   *repository.AgencyRepository
+	listener     PropertyChangeListener
+	events       EventPublisher
+	outbox       DealOutboxWriter
+	calendar     RentalCalendar
+	logger       *log.Logger
+}
+
+// RentalCalendar checks and books the dates of rental deals
+type RentalCalendar interface {
+	CheckStay(propertyID string, period domain.StayPeriod) error
+	BookStay(propertyID, dealID string, period domain.StayPeriod, bookedBy string) error
+}
+
+// DealOutboxWriter saves deals together with their outbox events
+type DealOutboxWriter interface {
+	CreateWithEvents(deal *domain.Deal, events ...*domain.OutboxEvent) error
+}
+
+// NewDealService creates a new deal service
+func NewDealService(
+	dealRepo repository.DealRepository,
+	propertyRepo repository.PropertyRepository,
+	agencyRepo *repository.AgencyRepository,
+	logger *log.Logger,
+) *DealService {
+	return &DealService{
+		dealRepo:     dealRepo,
+		propertyRepo: propertyRepo,
+		agencyRepo:   agencyRepo,
+		logger:       logger,
+	}
+}
+
+// SetChangeListener registers a listener for properties whose status changes when a
+// deal is recorded
+func (s *DealService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// SetEventPublisher publishes property.sold and property.rented events when deals are
+// recorded
+func (s *DealService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
+// SetOutbox records property.sold and property.rented in the transactional outbox
+// instead of publishing them after the deal is stored
+func (s *DealService) SetOutbox(outbox DealOutboxWriter) {
+	s.outbox = outbox
+}
+
+// SetRentalCalendar rejects rental deals whose dates are unavailable and books the dates
+// of recorded ones, typically the AvailabilityService
+func (s *DealService) SetRentalCalendar(calendar RentalCalendar) {
+	s.calendar = calendar
+}
+
+// RecordDeal records the sale or rental of a property and marks the property as sold or
+// rented. Agency properties can be closed by the agency's administrators or the assigned
+// agent; other properties by their owner.
+func (s *DealService) RecordDeal(req RecordDealRequest, actor domain.Actor) (*domain.Deal, error) {
+	property, err := s.propertyRepo.GetByID(req.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can record deals")
+	}
+	if property.Status == domain.StatusSold {
+		return nil, fmt.Errorf("property is already sold")
+	}
+
+	commission := 0.0
+	if req.CommissionPercent != nil {
+		commission = *req.CommissionPercent
+	} else if property.AgencyID != nil {
+		agency, err := s.agencyRepo.GetByID(*property.AgencyID)
+		if err != nil {
+			return nil, fmt.Errorf("agency not found: %w", err)
+		}
+		commission = agency.Commission
+	}
+
+	deal, err := domain.NewDeal(property.ID, req.Type, req.FinalPrice, commission, req.ClosingDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deal: %w", err)
+	}
+	if !domain.CanTransitionStatus(property.Status, deal.PropertyStatus()) {
+		return nil, fmt.Errorf("invalid status transition: cannot change from %s to %s", property.Status, deal.PropertyStatus())
+	}
+
+	deal.AgencyID = property.AgencyID
+	deal.AgentID = property.AgentID
+	if req.AgentID != "" {
+		if property.AgencyID == nil {
+			return nil, fmt.Errorf("invalid deal: only agency properties have agents")
+		}
+		deal.AgentID = &req.AgentID
+	}
+	if req.ClientID != "" {
+		deal.ClientID = &req.ClientID
+	}
+	deal.Notes = req.Notes
+	deal.RecordedBy = actor.UserID
+
+	stay, err := rentalPeriod(req, deal)
+	if err != nil {
+		return nil, err
+	}
+	if stay != nil && s.calendar != nil {
+		if err := s.calendar.CheckStay(property.ID, *stay); err != nil {
+			return nil, err
+		}
+	}
+
+	eventType := domain.EventPropertySold
+	if deal.PropertyStatus() == domain.StatusRented {
+		eventType = domain.EventPropertyRented
+	}
+	agencyID := propertyAgencyID(property)
+
+	if s.outbox != nil {
+		event, err := domain.NewOutboxEvent(eventType, domain.AggregateDeal, deal.ID, agencyID, deal)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.outbox.CreateWithEvents(deal, event); err != nil {
+			return nil, err
+		}
+	} else if err := s.dealRepo.Create(deal); err != nil {
+		return nil, err
+	}
+
+	if stay != nil && s.calendar != nil {
+		if err := s.calendar.BookStay(property.ID, deal.ID, *stay, actor.UserID); err != nil {
+			s.logger.Printf("Warning: failed to book rental dates of deal %s: %v", deal.ID, err)
+		}
+	}
+	if s.listener != nil {
+		s.listener.InvalidateProperties(property.ID)
+	}
+	if s.events != nil && s.outbox == nil {
+		if err := s.events.Publish(eventType, agencyID, deal); err != nil {
+			s.logger.Printf("Error publishing %s for property %s: %v", eventType, property.ID, err)
+		}
+	}
+
+	s.logger.Printf("Deal recorded for property %s: %s of %.2f, commission %.2f", property.ID, deal.Type, deal.FinalPrice, deal.CommissionAmount)
+	return deal, nil
+}
+
+// rentalPeriod returns the dates a rental deal books, nil when it sets none
+func rentalPeriod(req RecordDealRequest, deal *domain.Deal) (*domain.StayPeriod, error) {
+	if req.RentalStart == nil && req.RentalEnd == nil {
+		return nil, nil
+	}
+	if deal.Type != domain.DealTypeRent {
+		return nil, fmt.Errorf("invalid deal: only rentals have rental dates")
+	}
+	if req.RentalStart == nil || req.RentalEnd == nil {
+		return nil, fmt.Errorf("invalid deal: rental start and end are both required")
+	}
+	period, err := domain.NewStayPeriod(*req.RentalStart, *req.RentalEnd)
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// GetDeal retrieves a deal visible to the actor
+func (s *DealService) GetDeal(id string, actor domain.Actor) (*domain.Deal, error) {
+	deal, err := s.dealRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case actor.Role == domain.RoleAdmin, deal.RecordedBy == actor.UserID:
+	case deal.AgencyID != nil && actor.CanAdministerAgency(*deal.AgencyID):
+	case deal.AgentID != nil && *deal.AgentID == actor.UserID:
+	default:
+		return nil, fmt.Errorf("permission denied: deal belongs to another agency")
+	}
+
+	return deal, nil
+}
+
+// ListDeals lists deals, latest closing first. Admins see every deal, agency
+// administrators those of their agency and other users the deals they closed as agent.
+func (s *DealService) ListDeals(filter domain.DealFilter, actor domain.Actor) ([]domain.Deal, int, error) {
+	if filter.Type != "" && filter.Type != domain.DealTypeSale && filter.Type != domain.DealTypeRent {
+		return nil, 0, fmt.Errorf("invalid deal type: %s", filter.Type)
+	}
+
+	switch {
+	case actor.Role == domain.RoleAdmin:
+	case filter.AgencyID != "" && actor.CanAdministerAgency(filter.AgencyID):
+	case filter.AgencyID == "" && actor.Role == domain.RoleAgency:
+		filter.AgencyID = actor.AgencyID
+		if filter.AgencyID == "" {
+			filter.AgencyID = actor.UserID
+		}
+	default:
+		filter.AgentID = actor.UserID
+	}
+
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.dealRepo.List(filter)
+}
+
+// GetAgencyDealSummary aggregates the deals of an agency closed in a period, with the
+// commission earned by each agent
+func (s *DealService) GetAgencyDealSummary(agencyID string, from, to *time.Time, actor domain.Actor) (*domain.DealSummary, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can view deal reports")
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("invalid period: from must be before to")
+	}
+
+	return s.dealRepo.GetAgencySummary(agencyID, from, to)
+}
+
+// canManageListing reports whether the actor manages a listing: admins, administrators
+// and the assigned agent of its agency, or the owner of a property without agency
+func canManageListing(property *domain.Property, actor domain.Actor) bool {
+	if actor.Role == domain.RoleAdmin {
+		return true
+	}
+	if property.AgencyID != nil {
+		return actor.CanAdministerAgency(*property.AgencyID) || property.IsAssignedToAgent(actor.UserID)
+	}
+	return property.IsOwnedBy(actor.UserID)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+func TestDealService_Permissions(t *testing.T) {
+	agencyProperty := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	agencyProperty.ID = "prop-agency"
+	agencyProperty.SetAgency("agency-1")
+	soldProperty := domain.NewProperty("Depto", "Departamento en Cumbayá", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	soldProperty.ID = "prop-sold"
+	soldProperty.Status = domain.StatusSold
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-agency").Return(agencyProperty, nil)
+	propertyRepo.On("GetByID", "prop-sold").Return(soldProperty, nil)
+
+	service := NewDealService(nil, propertyRepo, nil, log.New(os.Stdout, "", 0))
+	sale := RecordDealRequest{PropertyID: "prop-agency", Type: domain.DealTypeSale, FinalPrice: 240000}
+
+	t.Run("other agencies cannot record deals", func(t *testing.T) {
+		_, err := service.RecordDeal(sale, domain.NewActor("user-2", "agency", "agency-2"))
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("unassigned agents cannot record deals", func(t *testing.T) {
+		_, err := service.RecordDeal(sale, domain.NewActor("agent-1", "agent", "agency-1"))
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("sold properties cannot close again", func(t *testing.T) {
+		_, err := service.RecordDeal(RecordDealRequest{PropertyID: "prop-sold", Type: domain.DealTypeSale, FinalPrice: 1},
+			domain.NewActor("owner-1", "seller", ""))
+		assert.ErrorContains(t, err, "already sold")
+	})
+
+	t.Run("summary requires agency administrator", func(t *testing.T) {
+		_, err := service.GetAgencyDealSummary("agency-1", nil, nil, domain.NewActor("agent-1", "agent", "agency-1"))
+		assert.ErrorContains(t, err, "permission denied")
+	})
+}
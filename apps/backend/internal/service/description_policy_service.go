@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// DescriptionWriter rewrites the description of a listing as decided by an
+// administrator, without the description policy
+type DescriptionWriter interface {
+	RewriteDescription(propertyID, description, changedBy string) error
+}
+
+// DescriptionPolicyService enforces the deployment's policy on contact information and
+// profanity in listing descriptions, and keeps the queue of descriptions it flagged or
+// masked for administrators, who can override it for a listing.
+type DescriptionPolicyService struct {
+	repo   repository.DescriptionReviewRepository
+	policy domain.DescriptionPolicy
+	writer DescriptionWriter
+	logger *log.Logger
+	now    func() time.Time
+}
+
+// NewDescriptionPolicyService creates a description policy service
+func NewDescriptionPolicyService(repo repository.DescriptionReviewRepository, policy domain.DescriptionPolicy, logger *log.Logger) *DescriptionPolicyService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &DescriptionPolicyService{
+		repo:   repo,
+		policy: policy,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// SetWriter registers what rewrites descriptions decided by administrators
+func (s *DescriptionPolicyService) SetWriter(writer DescriptionWriter) {
+	s.writer = writer
+}
+
+// Screen applies the policy to the description of a listing about to be saved, masking
+// what it obfuscates. It returns the review to record once the listing is saved, nil
+// when the description is clean or the listing exempt, and an error when the policy
+// rejects the description.
+func (s *DescriptionPolicyService) Screen(property *domain.Property) (*domain.DescriptionReview, error) {
+	review, err := s.repo.GetByPropertyID(property.ID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		// The policy still applies when exemptions cannot be read
+		s.logger.Printf("Failed to read description review of property %s: %v", property.ID, err)
+	}
+	if err == nil && review.Exempt() {
+		return nil, nil
+	}
+
+	property.NormalizeDescription()
+	original := property.DescriptionRaw
+	masked, findings, err := s.policy.Apply(original)
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	if masked != original {
+		property.Description = masked
+		property.NormalizeDescription()
+	}
+	return domain.NewDescriptionReview(property.ID, original, findings, s.now()), nil
+}
+
+// Record queues the review Screen returned for a saved listing; without a review, the
+// listing's pending review is dropped. Failures are logged so reviews never block
+// listing writes.
+func (s *DescriptionPolicyService) Record(propertyID string, review *domain.DescriptionReview) {
+	var err error
+	if review != nil {
+		err = s.repo.Save(review)
+	} else {
+		err = s.repo.DeletePending(propertyID)
+	}
+	if err != nil {
+		s.logger.Printf("Failed to record description review of property %s: %v", propertyID, err)
+	}
+}
+
+// ListQueue returns description reviews, pending by default, oldest first. Only
+// administrators review descriptions.
+func (s *DescriptionPolicyService) ListQueue(filter domain.DescriptionReviewFilter, actor domain.Actor) ([]domain.DescriptionReview, int, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, 0, fmt.Errorf("permission denied: only administrators can review descriptions")
+	}
+	if filter.Status == "" {
+		filter.Status = domain.DescriptionReviewPending
+	}
+	if !domain.IsValidDescriptionReviewStatus(filter.Status) {
+		return nil, 0, fmt.Errorf("invalid description review status: %s", filter.Status)
+	}
+
+	filter.Limit, filter.Offset = reviewPage(filter.Limit, filter.Offset)
+	return s.repo.List(filter)
+}
+
+// Decide upholds or overrides the policy on a listing's pending review. Upholding masks
+// everything the policy found, flagged findings included; overriding restores the
+// description as written and exempts the listing from the policy.
+func (s *DescriptionPolicyService) Decide(propertyID string, override bool, actor domain.Actor) (*domain.DescriptionReview, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can review descriptions")
+	}
+
+	review, err := s.repo.GetByPropertyID(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := review.Decide(override, actor.UserID, s.now()); err != nil {
+		return nil, err
+	}
+
+	// Rewrite first: a failed rewrite leaves the review pending so it can be retried
+	description := s.policy.Mask(review.Original)
+	if override {
+		description = review.Original
+	}
+	if s.writer != nil {
+		if err := s.writer.RewriteDescription(propertyID, description, actor.UserID); err != nil {
+			return nil, fmt.Errorf("failed to rewrite description of property %s: %w", propertyID, err)
+		}
+	}
+	if err := s.repo.Decide(review); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Description review of property %s %s by %s", propertyID, review.Status, actor.UserID)
+	return review, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryDescriptionReviewRepository is an in-memory DescriptionReviewRepository
+type memoryDescriptionReviewRepository struct {
+	reviews map[string]domain.DescriptionReview
+}
+
+func (r *memoryDescriptionReviewRepository) Save(review *domain.DescriptionReview) error {
+	if r.reviews[review.PropertyID].Status == domain.DescriptionReviewOverridden {
+		return nil
+	}
+	r.reviews[review.PropertyID] = *review
+	return nil
+}
+
+func (r *memoryDescriptionReviewRepository) GetByPropertyID(propertyID string) (*domain.DescriptionReview, error) {
+	review, ok := r.reviews[propertyID]
+	if !ok {
+		return nil, fmt.Errorf("description review not found: %s", propertyID)
+	}
+	return &review, nil
+}
+
+func (r *memoryDescriptionReviewRepository) DeletePending(propertyID string) error {
+	if r.reviews[propertyID].Status == domain.DescriptionReviewPending {
+		delete(r.reviews, propertyID)
+	}
+	return nil
+}
+
+func (r *memoryDescriptionReviewRepository) List(filter domain.DescriptionReviewFilter) ([]domain.DescriptionReview, int, error) {
+	reviews := []domain.DescriptionReview{}
+	for _, review := range r.reviews {
+		if review.Status == filter.Status {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].CreatedAt.Before(reviews[j].CreatedAt) })
+	return reviews, len(reviews), nil
+}
+
+func (r *memoryDescriptionReviewRepository) Decide(review *domain.DescriptionReview) error {
+	if r.reviews[review.PropertyID].Status != domain.DescriptionReviewPending {
+		return fmt.Errorf("description review not found or already decided: %s", review.PropertyID)
+	}
+	r.reviews[review.PropertyID] = *review
+	return nil
+}
+
+func newTestDescriptionPolicy(t *testing.T, policy domain.DescriptionPolicy) (*PropertyService, *DescriptionPolicyService, *MockPropertyRepository) {
+	t.Helper()
+
+	property := domain.NewProperty("Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.UpdateSlug()
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+	propertyRepo.On("Update", mock.AnythingOfType("*domain.Property")).Return(nil)
+
+	properties := NewPropertyService(propertyRepo, newEmptyImageRepository())
+	descriptions := NewDescriptionPolicyService(&memoryDescriptionReviewRepository{reviews: map[string]domain.DescriptionReview{}},
+		policy, log.New(os.Stderr, "", 0))
+	properties.SetDescriptionPolicy(descriptions)
+	return properties, descriptions, propertyRepo
+}
+
+func TestDescriptionPolicyService_MasksAndOverrides(t *testing.T) {
+	properties, descriptions, _ := newTestDescriptionPolicy(t, domain.DefaultDescriptionPolicy())
+	admin := domain.NewActor("admin-1", "admin", "")
+	written := "Casa amplia. Llame al 099 123 4567"
+
+	updated, err := properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", written, "Guayas", "Samborondón", "house", 250000)
+	require.NoError(t, err)
+	assert.Equal(t, "Casa amplia. Llame al [contacto oculto]", updated.Description)
+
+	_, _, err = descriptions.ListQueue(domain.DescriptionReviewFilter{}, domain.NewActor("agent-1", "agent", ""))
+	assert.ErrorContains(t, err, "permission denied")
+	queue, total, err := descriptions.ListQueue(domain.DescriptionReviewFilter{}, admin)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, written, queue[0].Original)
+	assert.Equal(t, []domain.DescriptionFinding{
+		{Kind: domain.DescriptionFindingPhone, Match: "099 123 4567", Action: domain.DescriptionPolicyObfuscate},
+	}, queue[0].Findings)
+
+	// Overriding restores the description as written and exempts the listing
+	review, err := descriptions.Decide("prop-1", true, admin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DescriptionReviewOverridden, review.Status)
+	assert.Equal(t, written, updated.Description)
+
+	updated, err = properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", written+" o 0987654321", "Guayas", "Samborondón", "house", 250000)
+	require.NoError(t, err)
+	assert.Equal(t, written+" o 0987654321", updated.Description)
+
+	_, err = descriptions.Decide("prop-1", false, admin)
+	assert.ErrorContains(t, err, "invalid description review")
+}
+
+func TestDescriptionPolicyService_FlagsAndUpholds(t *testing.T) {
+	policy := domain.DefaultDescriptionPolicy()
+	policy.ContactInfo = domain.DescriptionPolicyFlag
+	properties, descriptions, _ := newTestDescriptionPolicy(t, policy)
+	admin := domain.NewActor("admin-1", "admin", "")
+
+	updated, err := properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", "Escriba a ventas@realty.ec", "Guayas", "Samborondón", "house", 250000)
+	require.NoError(t, err)
+	assert.Equal(t, "Escriba a ventas@realty.ec", updated.Description, "flagged descriptions are kept as written")
+
+	_, err = descriptions.Decide("prop-1", false, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "Escriba a [contacto oculto]", updated.Description, "upholding masks flagged findings")
+
+	// A clean edit leaves nothing pending
+	_, err = properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000)
+	require.NoError(t, err)
+	_, total, err := descriptions.ListQueue(domain.DescriptionReviewFilter{}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestDescriptionPolicyService_Rejects(t *testing.T) {
+	policy := domain.DefaultDescriptionPolicy()
+	policy.ContactInfo = domain.DescriptionPolicyReject
+	properties, _, propertyRepo := newTestDescriptionPolicy(t, policy)
+
+	_, err := properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", "Info: https://wa.me/593991234567", "Guayas", "Samborondón", "house", 250000)
+	assert.ErrorContains(t, err, "invalid description: whatsapp not allowed")
+	propertyRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/einvoice"
+	"realty-core/internal/processors"
+	"realty-core/internal/repository"
+)
+
+// invoiceBatchSize bounds how many invoices a run of the invoicing job submits
+const invoiceBatchSize = 100
+
+// FeeInvoicer issues the electronic invoices of the fees agencies pay, e.g.
+// ElectronicInvoiceService
+type FeeInvoicer interface {
+	InvoiceFee(agencyID, source, sourceRef string, lines []domain.InvoiceLine) (*domain.ElectronicInvoice, error)
+}
+
+// ElectronicInvoiceService issues SRI electronic invoices for the subscription plans and
+// featured listings agencies pay for. Invoices are generated at once and submitted to
+// the SRI through the provider by the invoicing job, which then follows their
+// authorization.
+type ElectronicInvoiceService struct {
+	repo     repository.ElectronicInvoiceRepository
+	agencies AgencyLookup
+	provider einvoice.Provider
+	issuer   domain.InvoiceIssuer
+	renderer processors.PDFRenderer
+	now      func() time.Time
+	logger   *log.Logger
+}
+
+// NewElectronicInvoiceService creates an electronic invoice service. Without a provider
+// invoices are generated but stay pending.
+func NewElectronicInvoiceService(
+	repo repository.ElectronicInvoiceRepository,
+	agencies AgencyLookup,
+	provider einvoice.Provider,
+	issuer domain.InvoiceIssuer,
+	renderer processors.PDFRenderer,
+	logger *log.Logger,
+) *ElectronicInvoiceService {
+	if renderer == nil {
+		renderer = processors.NewTextPDFRenderer()
+	}
+
+	return &ElectronicInvoiceService{
+		repo:     repo,
+		agencies: agencies,
+		provider: provider,
+		issuer:   issuer,
+		renderer: renderer,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+// InvoiceFee generates the invoice of a fee paid by an agency, identified by its RUC
+func (s *ElectronicInvoiceService) InvoiceFee(agencyID, source, sourceRef string, lines []domain.InvoiceLine) (*domain.ElectronicInvoice, error) {
+	agency, err := s.agencies.GetByID(agencyID)
+	if err != nil {
+		return nil, fmt.Errorf("agency not found: %w", err)
+	}
+	buyer, err := domain.InvoiceBuyerFromAgency(agency)
+	if err != nil {
+		return nil, err
+	}
+
+	sequential, err := s.repo.NextSequential(s.issuer.Establishment, s.issuer.EmissionPoint)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := domain.NewElectronicInvoice(s.issuer, agencyID, source, sourceRef, buyer, lines, sequential, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(invoice); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Invoice %s (%s) of %s issued to agency %s for %s %s",
+		invoice.Number(), invoice.AccessKey, domain.FormatMoney(invoice.Total), agencyID, source, sourceRef)
+	return invoice, nil
+}
+
+// ListAgencyInvoices returns the invoices of an agency to its administrators
+func (s *ElectronicInvoiceService) ListAgencyInvoices(agencyID string, actor domain.Actor) ([]domain.ElectronicInvoice, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can see its invoices")
+	}
+	return s.repo.ListByAgency(agencyID)
+}
+
+// GetInvoice returns an invoice to the administrators of its agency
+func (s *ElectronicInvoiceService) GetInvoice(id string, actor domain.Actor) (*domain.ElectronicInvoice, error) {
+	invoice, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAdministerAgency(invoice.AgencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can see its invoices")
+	}
+	return invoice, nil
+}
+
+// InvoiceXML returns the XML document of an invoice: the authorized one once the SRI
+// authorizes it
+func (s *ElectronicInvoiceService) InvoiceXML(id string, actor domain.Actor) (*domain.ElectronicInvoice, []byte, error) {
+	invoice, err := s.GetInvoice(id, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invoice, []byte(invoice.Document()), nil
+}
+
+// InvoiceRIDE renders the printed representation (RIDE) of an invoice as a PDF
+func (s *ElectronicInvoiceService) InvoiceRIDE(id string, actor domain.Actor) (*domain.ElectronicInvoice, []byte, error) {
+	invoice, err := s.GetInvoice(id, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdf, err := s.renderer.Render("Factura "+invoice.Number(), domain.InvoiceRIDEText(s.issuer, invoice))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return invoice, pdf, nil
+}
+
+// ProcessPending submits the pending invoices to the SRI and checks the authorization of
+// the received ones, returning how many were authorized
+func (s *ElectronicInvoiceService) ProcessPending() (int, error) {
+	if s.provider == nil {
+		return 0, nil
+	}
+
+	invoices, err := s.repo.ListUnauthorized(domain.MaxInvoiceAttempts, invoiceBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	authorized := 0
+	for i := range invoices {
+		invoice := &invoices[i]
+		if err := s.process(invoice); err != nil {
+			s.logger.Printf("Error processing invoice %s: %v", invoice.AccessKey, err)
+		}
+		if invoice.Status == domain.InvoiceAuthorized {
+			authorized++
+		}
+	}
+	return authorized, nil
+}
+
+// process submits a pending invoice and queries the authorization of a received one,
+// saving the outcome. Provider failures count as attempts.
+func (s *ElectronicInvoiceService) process(invoice *domain.ElectronicInvoice) error {
+	err := s.exchange(invoice)
+	if err != nil {
+		invoice.Attempts++
+		invoice.Messages = []string{err.Error()}
+		invoice.UpdatedAt = s.now()
+	}
+	if saveErr := s.repo.UpdateStatus(invoice); saveErr != nil {
+		return saveErr
+	}
+	if err == nil && invoice.Status == domain.InvoiceRejected {
+		s.logger.Printf("Invoice %s rejected by the SRI: %s", invoice.AccessKey, strings.Join(invoice.Messages, "; "))
+	}
+	return err
+}
+
+// exchange talks to the provider for an invoice
+func (s *ElectronicInvoiceService) exchange(invoice *domain.ElectronicInvoice) error {
+	if invoice.Status == domain.InvoicePending {
+		reception, err := s.provider.Submit(invoice.AccessKey, []byte(invoice.XML))
+		if err != nil {
+			return err
+		}
+		invoice.RecordReception(reception.Received, reception.Messages, s.now())
+		if invoice.Status != domain.InvoiceReceived {
+			return nil
+		}
+	}
+
+	authorization, err := s.provider.Authorize(invoice.AccessKey)
+	if err != nil {
+		return err
+	}
+	invoice.RecordAuthorization(
+		authorization.Status == einvoice.StatusAuthorized,
+		authorization.Status == einvoice.StatusNotAuthorized,
+		authorization.Number, authorization.AuthorizedAt, authorization.Document, authorization.Messages, s.now())
+	return nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/einvoice"
+)
+
+// memoryInvoices keeps electronic invoices in memory
+type memoryInvoices struct {
+	invoices   map[string]domain.ElectronicInvoice
+	sequential int
+}
+
+func (m *memoryInvoices) NextSequential(establishment, emissionPoint string) (int, error) {
+	m.sequential++
+	return m.sequential, nil
+}
+
+func (m *memoryInvoices) Create(invoice *domain.ElectronicInvoice) error {
+	m.invoices[invoice.ID] = *invoice
+	return nil
+}
+
+func (m *memoryInvoices) GetByID(id string) (*domain.ElectronicInvoice, error) {
+	invoice, ok := m.invoices[id]
+	if !ok {
+		return nil, fmt.Errorf("electronic invoice not found: %s", id)
+	}
+	return &invoice, nil
+}
+
+func (m *memoryInvoices) ListByAgency(agencyID string) ([]domain.ElectronicInvoice, error) {
+	invoices := []domain.ElectronicInvoice{}
+	for _, invoice := range m.invoices {
+		if invoice.AgencyID == agencyID {
+			invoices = append(invoices, invoice)
+		}
+	}
+	return invoices, nil
+}
+
+func (m *memoryInvoices) ListUnauthorized(maxAttempts, limit int) ([]domain.ElectronicInvoice, error) {
+	invoices := []domain.ElectronicInvoice{}
+	for _, invoice := range m.invoices {
+		if !invoice.IsFinal() && invoice.Attempts < maxAttempts {
+			invoices = append(invoices, invoice)
+		}
+	}
+	return invoices, nil
+}
+
+func (m *memoryInvoices) UpdateStatus(invoice *domain.ElectronicInvoice) error {
+	m.invoices[invoice.ID] = *invoice
+	return nil
+}
+
+// scriptedInvoiceProvider answers submissions and authorizations with fixed statuses
+type scriptedInvoiceProvider struct {
+	received      bool
+	authorization string
+	err           error
+	submitted     []string
+}
+
+func (p *scriptedInvoiceProvider) Name() string { return "scripted" }
+
+func (p *scriptedInvoiceProvider) Submit(accessKey string, document []byte) (*einvoice.Reception, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.submitted = append(p.submitted, accessKey)
+	return &einvoice.Reception{Received: p.received}, nil
+}
+
+func (p *scriptedInvoiceProvider) Authorize(accessKey string) (*einvoice.Authorization, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &einvoice.Authorization{Status: p.authorization, Number: accessKey, Document: []byte("<autorizacion/>")}, nil
+}
+
+func newTestInvoiceService(provider einvoice.Provider) (*ElectronicInvoiceService, *memoryInvoices) {
+	repo := &memoryInvoices{invoices: map[string]domain.ElectronicInvoice{}}
+	agencies := memoryAgencies{
+		"agency-1": {ID: "agency-1", Name: "Costa Inmobiliaria", RUC: "0991234567001", City: "Guayaquil"},
+		"agency-2": {ID: "agency-2", Name: "Sin RUC"},
+	}
+	issuer := domain.InvoiceIssuer{
+		RUC: "1792146739001", BusinessName: "Realty Core S.A.", Address: "Quito",
+		Establishment: "001", EmissionPoint: "001", Environment: domain.SRIEnvironmentTest, IVARate: 15,
+	}
+	service := NewElectronicInvoiceService(repo, agencies, provider, issuer, nil, log.New(&bytes.Buffer{}, "", 0))
+	service.now = func() time.Time { return time.Date(2025, 9, 8, 15, 0, 0, 0, time.UTC) }
+	return service, repo
+}
+
+func TestElectronicInvoiceService_InvoiceFeeAndAuthorize(t *testing.T) {
+	provider := &scriptedInvoiceProvider{received: true, authorization: einvoice.StatusProcessing}
+	service, repo := newTestInvoiceService(provider)
+
+	line := domain.InvoiceLine{Code: "PLAN-PRO", Description: "Suscripción mensual plan pro", Quantity: 1, UnitPrice: 49}
+	invoice, err := service.InvoiceFee("agency-1", domain.InvoiceSourceSubscription, "pro", []domain.InvoiceLine{line})
+	require.NoError(t, err)
+	assert.Equal(t, 1, invoice.Sequential)
+	assert.Equal(t, 56.35, invoice.Total)
+	assert.Equal(t, domain.InvoicePending, repo.invoices[invoice.ID].Status)
+
+	_, err = service.InvoiceFee("agency-2", domain.InvoiceSourceSubscription, "pro", []domain.InvoiceLine{line})
+	assert.Error(t, err)
+
+	// The SRI receives the invoice but is still processing it
+	authorized, err := service.ProcessPending()
+	require.NoError(t, err)
+	assert.Equal(t, 0, authorized)
+	assert.Equal(t, domain.InvoiceReceived, repo.invoices[invoice.ID].Status)
+	assert.Equal(t, []string{invoice.AccessKey}, provider.submitted)
+
+	// The next run only queries the authorization
+	provider.authorization = einvoice.StatusAuthorized
+	authorized, err = service.ProcessPending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, authorized)
+	assert.Len(t, provider.submitted, 1)
+
+	stored := repo.invoices[invoice.ID]
+	assert.Equal(t, domain.InvoiceAuthorized, stored.Status)
+	assert.Equal(t, invoice.AccessKey, stored.AuthorizationNumber)
+
+	admin := domain.NewActor("agency-user", "agency", "agency-1")
+	_, document, err := service.InvoiceXML(invoice.ID, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "<autorizacion/>", string(document))
+
+	_, pdf, err := service.InvoiceRIDE(invoice.ID, admin)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+
+	_, err = service.GetInvoice(invoice.ID, domain.NewActor("other", "agency", "agency-3"))
+	assert.Contains(t, err.Error(), "permission denied")
+	_, err = service.ListAgencyInvoices("agency-1", domain.NewActor("buyer", "buyer", ""))
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestElectronicInvoiceService_ProcessPendingFailures(t *testing.T) {
+	provider := &scriptedInvoiceProvider{err: fmt.Errorf("SRI unavailable")}
+	service, repo := newTestInvoiceService(provider)
+
+	line := domain.InvoiceLine{Code: "DESTACADO", Description: "Anuncio destacado", Quantity: 7, UnitPrice: 2}
+	invoice, err := service.InvoiceFee("agency-1", domain.InvoiceSourceFeatured, "prop-1", []domain.InvoiceLine{line})
+	require.NoError(t, err)
+
+	// Provider failures count as attempts and keep the invoice pending
+	_, err = service.ProcessPending()
+	require.NoError(t, err)
+	stored := repo.invoices[invoice.ID]
+	assert.Equal(t, domain.InvoicePending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, []string{"SRI unavailable"}, stored.Messages)
+
+	// Returned documents are rejected and no longer retried
+	provider.err = nil
+	provider.received = false
+	_, err = service.ProcessPending()
+	require.NoError(t, err)
+	assert.Equal(t, domain.InvoiceRejected, repo.invoices[invoice.ID].Status)
+
+	pending, _ := repo.ListUnauthorized(domain.MaxInvoiceAttempts, invoiceBatchSize)
+	assert.Empty(t, pending)
+}
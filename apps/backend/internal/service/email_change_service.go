@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// EmailChangeNotifier delivers the links of an email change
+type EmailChangeNotifier interface {
+	// SendEmailChangeConfirmation asks the new address to confirm the change
+	SendEmailChangeConfirmation(change *domain.EmailChange, confirmURL string) error
+
+	// SendEmailChangeNotice warns the current address, with a link to cancel the change
+	SendEmailChangeNotice(change *domain.EmailChange, cancelURL string) error
+}
+
+// LogEmailChangeNotifier writes email change links to the log. It is used until an
+// email provider is configured.
+type LogEmailChangeNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogEmailChangeNotifier creates a notifier that logs email change links
+func NewLogEmailChangeNotifier(logger *log.Logger) *LogEmailChangeNotifier {
+	return &LogEmailChangeNotifier{logger: logger}
+}
+
+// SendEmailChangeConfirmation logs the confirmation link
+func (n *LogEmailChangeNotifier) SendEmailChangeConfirmation(change *domain.EmailChange, confirmURL string) error {
+	n.logger.Printf("Email change confirmation for %s: %s", change.NewEmail, confirmURL)
+	return nil
+}
+
+// SendEmailChangeNotice logs the cancellation link
+func (n *LogEmailChangeNotifier) SendEmailChangeNotice(change *domain.EmailChange, cancelURL string) error {
+	n.logger.Printf("Email change to %s requested for %s; cancel: %s", change.NewEmail, change.OldEmail, cancelURL)
+	return nil
+}
+
+// EmailChangeUsers is the part of the user repository email changes use
+type EmailChangeUsers interface {
+	GetByID(id string) (*domain.User, error)
+	GetByEmail(email string) (*domain.User, error)
+	Update(user *domain.User) error
+}
+
+// EmailChangeService changes account emails in two steps: the user requests the
+// change with their password, and the email is swapped once the new address
+// confirms it. The current address is told about the change and can cancel it.
+type EmailChangeService struct {
+	repo       repository.EmailChangeRepository
+	users      EmailChangeUsers
+	auditRepo  repository.AuditRepository
+	notifier   EmailChangeNotifier
+	ttl        time.Duration
+	confirmURL string
+	cancelURL  string
+	now        func() time.Time
+	logger     *log.Logger
+}
+
+// NewEmailChangeService creates an email change service. confirmURL and cancelURL are
+// the pages that receive the tokens, e.g. https://app.example.com/account/email/confirm
+func NewEmailChangeService(
+	repo repository.EmailChangeRepository,
+	users EmailChangeUsers,
+	auditRepo repository.AuditRepository,
+	notifier EmailChangeNotifier,
+	ttl time.Duration,
+	confirmURL, cancelURL string,
+	logger *log.Logger,
+) *EmailChangeService {
+	if notifier == nil {
+		notifier = NewLogEmailChangeNotifier(logger)
+	}
+	if ttl <= 0 {
+		ttl = domain.DefaultEmailChangeTTL
+	}
+
+	return &EmailChangeService{
+		repo:       repo,
+		users:      users,
+		auditRepo:  auditRepo,
+		notifier:   notifier,
+		ttl:        ttl,
+		confirmURL: confirmURL,
+		cancelURL:  cancelURL,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// RequestChange starts changing the email of a user after checking their password. A
+// new request replaces an open one.
+func (s *EmailChangeService) RequestChange(userID, password, newEmail string) (*domain.EmailChange, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, fmt.Errorf("current password is incorrect")
+	}
+
+	change, confirmToken, cancelToken, err := domain.NewEmailChange(user.ID, user.Email, newEmail, s.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email change: %w", err)
+	}
+	if existing, _ := s.users.GetByEmail(change.NewEmail); existing != nil {
+		return nil, fmt.Errorf("email already in use")
+	}
+
+	now := s.now()
+	if pending, _ := s.repo.GetPendingByUser(user.ID); pending != nil {
+		if err := pending.Cancel(now); err == nil {
+			if err := s.repo.Update(pending); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.repo.Create(change); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.SendEmailChangeConfirmation(change, tokenURL(s.confirmURL, confirmToken)); err != nil {
+		s.logger.Printf("Error sending email change confirmation %s: %v", change.ID, err)
+	}
+	if err := s.notifier.SendEmailChangeNotice(change, tokenURL(s.cancelURL, cancelToken)); err != nil {
+		s.logger.Printf("Error sending email change notice %s: %v", change.ID, err)
+	}
+
+	s.audit(user, domain.AuditActionEmailChangeRequested, change)
+	return change, nil
+}
+
+// Confirm swaps the email of the user once the new address confirms the change. The
+// new address counts as verified.
+func (s *EmailChangeService) Confirm(token string) (*domain.User, error) {
+	change, err := s.getPending(token, s.repo.GetByConfirmTokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(change.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !strings.EqualFold(user.Email, change.OldEmail) {
+		return nil, fmt.Errorf("email change is outdated: the account email changed since it was requested")
+	}
+	if existing, _ := s.users.GetByEmail(change.NewEmail); existing != nil {
+		return nil, fmt.Errorf("email already in use")
+	}
+
+	now := s.now()
+	if err := change.Confirm(now); err != nil {
+		return nil, err
+	}
+	user.Email = change.NewEmail
+	user.EmailVerified = true
+	user.UpdatedAt = now
+
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(change); err != nil {
+		return nil, err
+	}
+
+	s.audit(user, domain.AuditActionEmailChanged, change)
+	s.logger.Printf("Email of user %s changed from %s to %s", user.ID, change.OldEmail, change.NewEmail)
+	return user, nil
+}
+
+// Cancel cancels a pending email change from the link sent to the current address
+func (s *EmailChangeService) Cancel(token string) (*domain.EmailChange, error) {
+	change, err := s.getPending(token, s.repo.GetByCancelTokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change.Cancel(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(change); err != nil {
+		return nil, err
+	}
+
+	if user, err := s.users.GetByID(change.UserID); err == nil {
+		s.audit(user, domain.AuditActionEmailChangeCancelled, change)
+	}
+	return change, nil
+}
+
+// getPending finds the change of a token, expiring it when past its expiry
+func (s *EmailChangeService) getPending(token string, find func(tokenHash string) (*domain.EmailChange, error)) (*domain.EmailChange, error) {
+	if token == "" {
+		return nil, fmt.Errorf("invalid token: token is required")
+	}
+
+	change, err := find(domain.HashInvitationToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if change.IsExpired(now) {
+		change.RefreshStatus(now)
+		if err := s.repo.Update(change); err != nil {
+			s.logger.Printf("Error expiring email change %s: %v", change.ID, err)
+		}
+		return nil, fmt.Errorf("email change is expired")
+	}
+	if change.Status != domain.EmailChangeStatusPending {
+		return nil, fmt.Errorf("email change is %s", change.Status)
+	}
+	return change, nil
+}
+
+// audit records a step of an email change; failures are logged
+func (s *EmailChangeService) audit(user *domain.User, action string, change *domain.EmailChange) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	agencyID := ""
+	if user.AgencyID != nil {
+		agencyID = *user.AgencyID
+	}
+	entry := domain.NewAuditEntry(domain.NewActor(user.ID, string(user.Role), agencyID), action,
+		domain.AuditEntityUser, user.ID, user.AgencyID, map[string]interface{}{
+			"email_change_id": change.ID,
+			"old_email":       change.OldEmail,
+			"new_email":       change.NewEmail,
+		})
+	if err := s.auditRepo.Create(entry); err != nil {
+		s.logger.Printf("Error recording audit entry %s for user %s: %v", action, user.ID, err)
+	}
+}
+
+// tokenURL adds a token to the page receiving it
+func tokenURL(page, token string) string {
+	if page == "" {
+		return token
+	}
+
+	separator := "?"
+	if strings.Contains(page, "?") {
+		separator = "&"
+	}
+	return page + separator + "token=" + url.QueryEscape(token)
+}
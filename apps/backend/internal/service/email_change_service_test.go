@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"realty-core/internal/domain"
+)
+
+// memoryEmailChangeRepository keeps email changes in memory
+type memoryEmailChangeRepository struct {
+	changes map[string]*domain.EmailChange
+}
+
+func (r *memoryEmailChangeRepository) Create(change *domain.EmailChange) error {
+	copied := *change
+	r.changes[change.ID] = &copied
+	return nil
+}
+
+func (r *memoryEmailChangeRepository) find(match func(*domain.EmailChange) bool) (*domain.EmailChange, error) {
+	for _, change := range r.changes {
+		if match(change) {
+			copied := *change
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("email change not found")
+}
+
+func (r *memoryEmailChangeRepository) GetByConfirmTokenHash(tokenHash string) (*domain.EmailChange, error) {
+	return r.find(func(c *domain.EmailChange) bool { return c.ConfirmTokenHash == tokenHash })
+}
+
+func (r *memoryEmailChangeRepository) GetByCancelTokenHash(tokenHash string) (*domain.EmailChange, error) {
+	return r.find(func(c *domain.EmailChange) bool { return c.CancelTokenHash == tokenHash })
+}
+
+func (r *memoryEmailChangeRepository) GetPendingByUser(userID string) (*domain.EmailChange, error) {
+	return r.find(func(c *domain.EmailChange) bool {
+		return c.UserID == userID && c.Status == domain.EmailChangeStatusPending
+	})
+}
+
+func (r *memoryEmailChangeRepository) Update(change *domain.EmailChange) error {
+	copied := *change
+	r.changes[change.ID] = &copied
+	return nil
+}
+
+// memoryEmailChangeUsers keeps users in memory by ID
+type memoryEmailChangeUsers struct {
+	users map[string]*domain.User
+}
+
+func (u *memoryEmailChangeUsers) GetByID(id string) (*domain.User, error) {
+	if user, ok := u.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("user not found: %s", id)
+}
+
+func (u *memoryEmailChangeUsers) GetByEmail(email string) (*domain.User, error) {
+	for _, user := range u.users {
+		if strings.EqualFold(user.Email, email) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found: %s", email)
+}
+
+func (u *memoryEmailChangeUsers) Update(user *domain.User) error {
+	copied := *user
+	u.users[user.ID] = &copied
+	return nil
+}
+
+// recordingEmailChangeNotifier keeps the links it was asked to send
+type recordingEmailChangeNotifier struct {
+	confirmURLs, cancelURLs []string
+}
+
+func (n *recordingEmailChangeNotifier) SendEmailChangeConfirmation(change *domain.EmailChange, confirmURL string) error {
+	n.confirmURLs = append(n.confirmURLs, confirmURL)
+	return nil
+}
+
+func (n *recordingEmailChangeNotifier) SendEmailChangeNotice(change *domain.EmailChange, cancelURL string) error {
+	n.cancelURLs = append(n.cancelURLs, cancelURL)
+	return nil
+}
+
+func newTestEmailChangeService(t *testing.T) (*EmailChangeService, *memoryEmailChangeUsers, *recordingEmailChangeNotifier, *fakeAuditRepository) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	users := &memoryEmailChangeUsers{users: map[string]*domain.User{
+		"user-1": {ID: "user-1", Email: "ana@example.com", Role: domain.RoleBuyer, PasswordHash: string(hash)},
+		"user-2": {ID: "user-2", Email: "luis@example.com", Role: domain.RoleBuyer},
+	}}
+	notifier := &recordingEmailChangeNotifier{}
+	audit := &fakeAuditRepository{}
+	var logs bytes.Buffer
+	svc := NewEmailChangeService(&memoryEmailChangeRepository{changes: map[string]*domain.EmailChange{}}, users, audit, notifier,
+		time.Hour, "https://app.example.com/account/email/confirm", "https://app.example.com/account/email/cancel", log.New(&logs, "", 0))
+	return svc, users, notifier, audit
+}
+
+// linkToken extracts the token of an emailed link
+func linkToken(t *testing.T, link string) string {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestEmailChangeService_Confirm(t *testing.T) {
+	svc, users, notifier, audit := newTestEmailChangeService(t)
+
+	_, err := svc.RequestChange("user-1", "wrong", "ana.new@example.com")
+	assert.ErrorContains(t, err, "password is incorrect")
+	_, err = svc.RequestChange("user-1", "secret123", "luis@example.com")
+	assert.ErrorContains(t, err, "already in use")
+
+	change, err := svc.RequestChange("user-1", "secret123", " Ana.New@Example.com ")
+	require.NoError(t, err)
+	assert.Equal(t, "ana.new@example.com", change.NewEmail)
+	assert.Equal(t, "ana@example.com", users.users["user-1"].Email, "the email does not change until confirmed")
+	require.Len(t, notifier.confirmURLs, 1)
+	require.Len(t, notifier.cancelURLs, 1)
+
+	// The cancel token cannot confirm
+	_, err = svc.Confirm(linkToken(t, notifier.cancelURLs[0]))
+	assert.ErrorContains(t, err, "not found")
+
+	user, err := svc.Confirm(linkToken(t, notifier.confirmURLs[0]))
+	require.NoError(t, err)
+	assert.Equal(t, "ana.new@example.com", user.Email)
+	assert.True(t, users.users["user-1"].EmailVerified)
+
+	_, err = svc.Confirm(linkToken(t, notifier.confirmURLs[0]))
+	assert.ErrorContains(t, err, "email change is confirmed")
+
+	require.Len(t, audit.entries, 2)
+	assert.Equal(t, domain.AuditActionEmailChangeRequested, audit.entries[0].Action)
+	assert.Equal(t, domain.AuditActionEmailChanged, audit.entries[1].Action)
+	assert.Equal(t, "ana@example.com", audit.entries[1].Details["old_email"])
+}
+
+func TestEmailChangeService_CancelAndExpire(t *testing.T) {
+	svc, users, notifier, audit := newTestEmailChangeService(t)
+
+	_, err := svc.RequestChange("user-1", "secret123", "first@example.com")
+	require.NoError(t, err)
+
+	// A new request replaces the open one
+	_, err = svc.RequestChange("user-1", "secret123", "second@example.com")
+	require.NoError(t, err)
+	_, err = svc.Confirm(linkToken(t, notifier.confirmURLs[0]))
+	assert.ErrorContains(t, err, "email change is cancelled")
+
+	change, err := svc.Cancel(linkToken(t, notifier.cancelURLs[1]))
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailChangeStatusCancelled, change.Status)
+	_, err = svc.Confirm(linkToken(t, notifier.confirmURLs[1]))
+	assert.ErrorContains(t, err, "email change is cancelled")
+	assert.Equal(t, domain.AuditActionEmailChangeCancelled, audit.entries[len(audit.entries)-1].Action)
+
+	_, err = svc.RequestChange("user-1", "secret123", "third@example.com")
+	require.NoError(t, err)
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = svc.Confirm(linkToken(t, notifier.confirmURLs[2]))
+	assert.ErrorContains(t, err, "expired")
+	assert.Equal(t, "ana@example.com", users.users["user-1"].Email)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+)
+
+// FeaturedConfig configures timed featured promotions
+type FeaturedConfig struct {
+	ExpiryInterval  time.Duration // time between expiration runs
+	DefaultDuration time.Duration // promotion length when none is requested
+	DailyPrice      float64       // price of a featured day invoiced to agencies, before IVA
+}
+
+// FeaturedService features listings for a limited time and unfeatures them once their
+// promotion ends
+type FeaturedService struct {
+	repo         repository.FeaturedRepository
+	propertyRepo repository.PropertyRepository
+	limiter      ListingLimiter
+	listener     PropertyChangeListener
+	invoicer     FeeInvoicer
+	config       FeaturedConfig
+	now          func() time.Time
+	logger       *log.Logger
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewFeaturedService creates a new featured promotion service
+func NewFeaturedService(repo repository.FeaturedRepository, propertyRepo repository.PropertyRepository, config FeaturedConfig, logger *log.Logger) *FeaturedService {
+	if config.ExpiryInterval <= 0 {
+		config.ExpiryInterval = 15 * time.Minute
+	}
+	if config.DefaultDuration <= 0 {
+		config.DefaultDuration = domain.DefaultFeaturedDuration
+	}
+
+	return &FeaturedService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		config:       config,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetListingLimiter enforces the featured slots of agency plans on new promotions
+func (s *FeaturedService) SetListingLimiter(limiter ListingLimiter) {
+	s.limiter = limiter
+}
+
+// SetChangeListener registers a listener for featured changes, typically the
+// PropertyService so cached listings and searches reflect them
+func (s *FeaturedService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// SetFeeInvoicer issues an electronic invoice of the featured days agencies buy, at
+// the configured daily price
+func (s *FeaturedService) SetFeeInvoicer(invoicer FeeInvoicer) {
+	s.invoicer = invoicer
+}
+
+// GetStatus returns whether a property is featured and until when
+func (s *FeaturedService) GetStatus(propertyID string) (*domain.FeaturedStatus, error) {
+	return s.repo.GetStatus(propertyID)
+}
+
+// ExtendFeatured features a property for duration, extending a running promotion or
+// renewing an expired one. A zero duration uses the configured default.
+func (s *FeaturedService) ExtendFeatured(propertyID string, duration time.Duration, actor domain.Actor) (*domain.FeaturedStatus, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can feature it")
+	}
+	if property.Status != domain.StatusAvailable {
+		return nil, fmt.Errorf("invalid property: only available listings can be featured")
+	}
+
+	status, err := s.repo.GetStatus(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if !status.IsActive(now) && s.limiter != nil && property.AgencyID != nil {
+		if err := s.limiter.CheckFeaturedLimit(*property.AgencyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if duration == 0 {
+		duration = s.config.DefaultDuration
+	}
+	current := status.FeaturedUntil
+	if !status.Featured {
+		current = nil
+	}
+	until, err := domain.ExtendFeaturedUntil(current, now, duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+
+	if err := s.repo.SetFeaturedUntil(propertyID, until); err != nil {
+		return nil, err
+	}
+	s.changed(propertyID)
+	s.invoice(property, duration)
+
+	s.logger.Printf("Property %s featured until %s", propertyID, until.Format(time.RFC3339))
+	return &domain.FeaturedStatus{PropertyID: propertyID, Featured: true, FeaturedUntil: &until}, nil
+}
+
+// invoice issues the invoice of the featured days bought by the property's agency,
+// rounding partial days up
+func (s *FeaturedService) invoice(property *domain.Property, duration time.Duration) {
+	if s.invoicer == nil || s.config.DailyPrice <= 0 || property.AgencyID == nil {
+		return
+	}
+	days := math.Ceil(duration.Hours() / 24)
+	line := domain.InvoiceLine{
+		Code:        "DESTACADO",
+		Description: fmt.Sprintf("Anuncio destacado: %s (días)", property.Title),
+		Quantity:    days,
+		UnitPrice:   s.config.DailyPrice,
+	}
+	if _, err := s.invoicer.InvoiceFee(*property.AgencyID, domain.InvoiceSourceFeatured, property.ID, []domain.InvoiceLine{line}); err != nil {
+		s.logger.Printf("Error invoicing featured property %s: %v", property.ID, err)
+	}
+}
+
+// ExpireFeatured unfeatures every listing whose promotion ended
+func (s *FeaturedService) ExpireFeatured() ([]string, error) {
+	ids, err := s.repo.ExpireFeatured(s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) > 0 {
+		s.changed(ids...)
+		s.logger.Printf("Featured promotion ended for %d properties", len(ids))
+	}
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter("featured_expired_total", "Total number of featured promotions ended").Add(int64(len(ids)))
+	}
+
+	return ids, nil
+}
+
+// Start runs the expiration on its interval until Stop is called
+func (s *FeaturedService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.ExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.ExpireFeatured(); err != nil {
+					s.logger.Printf("Featured expiration failed: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	s.logger.Printf("Featured expiration scheduled every %s", s.config.ExpiryInterval)
+}
+
+// Stop stops the scheduled expiration and waits for a running one to finish
+func (s *FeaturedService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// changed notifies the listener of modified properties
+func (s *FeaturedService) changed(ids ...string) {
+	if s.listener != nil && len(ids) > 0 {
+		s.listener.InvalidateProperties(ids...)
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// fakeFeaturedRepository keeps featured statuses in memory
+type fakeFeaturedRepository struct {
+	statuses map[string]*domain.FeaturedStatus
+}
+
+func (r *fakeFeaturedRepository) GetStatus(propertyID string) (*domain.FeaturedStatus, error) {
+	status, ok := r.statuses[propertyID]
+	if !ok {
+		return &domain.FeaturedStatus{PropertyID: propertyID}, nil
+	}
+	copied := *status
+	return &copied, nil
+}
+
+func (r *fakeFeaturedRepository) SetFeaturedUntil(propertyID string, until time.Time) error {
+	r.statuses[propertyID] = &domain.FeaturedStatus{PropertyID: propertyID, Featured: true, FeaturedUntil: &until}
+	return nil
+}
+
+func (r *fakeFeaturedRepository) ExpireFeatured(now time.Time) ([]string, error) {
+	ids := []string{}
+	for id, status := range r.statuses {
+		if status.Featured && status.FeaturedUntil != nil && !status.FeaturedUntil.After(now) {
+			status.Featured = false
+			status.FeaturedUntil = nil
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// recordingListener records invalidated property IDs
+type recordingListener struct {
+	ids []string
+}
+
+func (l *recordingListener) InvalidateProperties(ids ...string) {
+	l.ids = append(l.ids, ids...)
+}
+
+func TestFeaturedService_ExtendAndExpire(t *testing.T) {
+	property := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.SetAgency("agency-1")
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	repo := &fakeFeaturedRepository{statuses: map[string]*domain.FeaturedStatus{}}
+	listener := &recordingListener{}
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	service := NewFeaturedService(repo, propertyRepo, FeaturedConfig{DefaultDuration: 7 * 24 * time.Hour}, log.New(os.Stdout, "", 0))
+	service.SetChangeListener(listener)
+	service.now = func() time.Time { return now }
+	agency := domain.NewActor("user-1", "agency", "agency-1")
+
+	_, err := service.ExtendFeatured("prop-1", 0, domain.NewActor("agent-2", "agent", "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	status, err := service.ExtendFeatured("prop-1", 0, agency)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(7*24*time.Hour), *status.FeaturedUntil)
+
+	status, err = service.ExtendFeatured("prop-1", 3*24*time.Hour, agency)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*24*time.Hour), *status.FeaturedUntil)
+
+	now = now.Add(11 * 24 * time.Hour)
+	ids, err := service.ExpireFeatured()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prop-1"}, ids)
+	assert.Equal(t, []string{"prop-1", "prop-1", "prop-1"}, listener.ids)
+
+	t.Run("renewal counts against the plan", func(t *testing.T) {
+		plans := &fakeSubscriptionRepository{plan: domain.PlanFree}
+		service.SetListingLimiter(NewSubscriptionService(plans, &fakeAuditRepository{}, log.New(os.Stdout, "", 0)))
+
+		_, err := service.ExtendFeatured("prop-1", 0, agency)
+		assert.ErrorContains(t, err, "plan limit reached")
+	})
+}
+
+// recordingInvoicer records the fees it is asked to invoice
+type recordingInvoicer struct {
+	fees []domain.InvoiceLine
+}
+
+func (r *recordingInvoicer) InvoiceFee(agencyID, source, sourceRef string, lines []domain.InvoiceLine) (*domain.ElectronicInvoice, error) {
+	r.fees = append(r.fees, lines...)
+	return &domain.ElectronicInvoice{}, nil
+}
+
+func TestFeaturedService_InvoicesFeaturedDays(t *testing.T) {
+	agencyID := "agency-1"
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(&domain.Property{ID: "prop-1", Title: "Casa en Samborondón", AgencyID: &agencyID, Status: domain.StatusAvailable}, nil)
+
+	featured := NewFeaturedService(&fakeFeaturedRepository{statuses: map[string]*domain.FeaturedStatus{}}, propertyRepo, FeaturedConfig{DailyPrice: 2}, log.New(os.Stdout, "", 0))
+	invoicer := &recordingInvoicer{}
+	featured.SetFeeInvoicer(invoicer)
+
+	_, err := featured.ExtendFeatured("prop-1", 36*time.Hour, domain.NewActor("agency-user", "agency", agencyID))
+	require.NoError(t, err)
+
+	require.Len(t, invoicer.fees, 1)
+	assert.Equal(t, 2.0, invoicer.fees[0].Quantity)
+	assert.Equal(t, 2.0, invoicer.fees[0].UnitPrice)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// FeedService builds the RSS and Atom feeds of new listings aggregators and users
+// subscribe to
+type FeedService struct {
+	properties  PropertyServiceInterface
+	honeytokens *HoneytokenService
+	propertyURL string
+	logger      *log.Logger
+}
+
+// NewFeedService creates a new feed service. propertyURL is the property page items
+// link to, with the slug appended, e.g. https://inmo.ec/propiedades
+func NewFeedService(properties PropertyServiceInterface, propertyURL string, logger *log.Logger) *FeedService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &FeedService{
+		properties:  properties,
+		propertyURL: strings.TrimSuffix(propertyURL, "/"),
+		logger:      logger,
+	}
+}
+
+// SetHoneytokens plants the feed canaries in the feeds their filters fit
+func (s *FeedService) SetHoneytokens(honeytokens *HoneytokenService) {
+	s.honeytokens = honeytokens
+}
+
+// PropertyFeed returns the newest available listings matching the filters, at most
+// limit of them. selfURL is the URL the feed was requested at.
+func (s *FeedService) PropertyFeed(filters *domain.PropertySearchFilters, limit int, selfURL string) (*domain.PropertyFeed, error) {
+	if limit <= 0 {
+		limit = domain.DefaultFeedItems
+	}
+	if limit > domain.MaxFeedItems {
+		limit = domain.MaxFeedItems
+	}
+	for _, province := range filters.Provinces {
+		if !domain.IsValidProvince(province) {
+			return nil, fmt.Errorf("invalid province: %s", province)
+		}
+	}
+	for _, propertyType := range filters.PropertyTypes {
+		if !domain.IsValidPropertyType(propertyType) {
+			return nil, fmt.Errorf("invalid property type: %s", propertyType)
+		}
+	}
+	filters.Status = []string{domain.StatusAvailable}
+
+	pagination := &domain.PaginationParams{Page: 1, PageSize: limit, SortBy: "created_at", SortDesc: true}
+	result, err := s.properties.FilterPropertiesPaginated(filters, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed listings: %w", err)
+	}
+	properties, _ := result.Data.([]domain.Property)
+	if s.honeytokens != nil {
+		properties = s.honeytokens.Plant(domain.HoneytokenSourceFeed, "", filters, properties, limit)
+	}
+
+	feed := &domain.PropertyFeed{
+		Title:       feedTitle(filters),
+		Description: "Propiedades publicadas recientemente",
+		Link:        s.propertyURL + feedSearchQuery(filters),
+		SelfURL:     selfURL,
+		Items:       make([]domain.PropertyFeedItem, 0, len(properties)),
+	}
+	for i := range properties {
+		property := &properties[i]
+		link := s.propertyURL + "/" + url.PathEscape(property.Slug)
+		feed.Items = append(feed.Items, domain.NewPropertyFeedItem(property, link))
+		if property.CreatedAt.After(feed.Updated) {
+			feed.Updated = property.CreatedAt
+		}
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Unix(0, 0)
+	}
+	return feed, nil
+}
+
+// feedTitle names a feed after its filters, e.g. "Nuevas propiedades: casa en Cuenca, Azuay"
+func feedTitle(filters *domain.PropertySearchFilters) string {
+	title := "Nuevas propiedades"
+	var parts []string
+	if len(filters.PropertyTypes) > 0 {
+		labels := make([]string, 0, len(filters.PropertyTypes))
+		for _, propertyType := range filters.PropertyTypes {
+			labels = append(labels, strings.ToLower(domain.FeedPropertyTypeLabel(propertyType)))
+		}
+		parts = append(parts, strings.Join(labels, ", "))
+	}
+	places := append(append([]string{}, filters.Cities...), filters.Provinces...)
+	if len(places) > 0 {
+		parts = append(parts, "en "+strings.Join(places, ", "))
+	}
+	if len(parts) > 0 {
+		title += ": " + strings.Join(parts, " ")
+	}
+	return title
+}
+
+// feedSearchQuery returns the query string of the site's search for the filters of a feed
+func feedSearchQuery(filters *domain.PropertySearchFilters) string {
+	query := url.Values{}
+	for _, province := range filters.Provinces {
+		query.Add("province", province)
+	}
+	for _, city := range filters.Cities {
+		query.Add("city", city)
+	}
+	for _, propertyType := range filters.PropertyTypes {
+		query.Add("type", propertyType)
+	}
+	if filters.MinPrice != nil {
+		query.Set("min_price", fmt.Sprintf("%g", *filters.MinPrice))
+	}
+	if filters.MaxPrice != nil {
+		query.Set("max_price", fmt.Sprintf("%g", *filters.MaxPrice))
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
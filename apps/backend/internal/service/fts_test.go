@@ -9,6 +9,7 @@ import (
 
 	"realty-core/internal/domain"
 	"realty-core/internal/repository"
+	"realty-core/internal/search"
 )
 
 // MockFTSPropertyRepository is a mock for FTS-specific functionality
@@ -46,6 +47,11 @@ func (m *MockFTSPropertyRepository) Delete(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockFTSPropertyRepository) IncrementViewCount(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockFTSPropertyRepository) GetByProvince(province string) ([]domain.Property, error) {
 	args := m.Called(province)
 	return args.Get(0).([]domain.Property), args.Error(1)
@@ -87,6 +93,27 @@ func (m *MockFTSPropertyRepository) GetByProvincePaginated(province string, pagi
 	return args.Get(0).([]domain.Property), args.Int(1), args.Error(2)
 }
 
+func (m *MockFTSPropertyRepository) GetByFilters(filters *domain.PropertySearchFilters) ([]domain.Property, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]domain.Property), args.Error(1)
+}
+
+func (m *MockFTSPropertyRepository) StreamByFilters(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error {
+	args := m.Called(filters)
+	properties := args.Get(0).([]domain.Property)
+	for i := range properties {
+		if err := fn(&properties[i]); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockFTSPropertyRepository) GetByFiltersPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]domain.Property), args.Int(1), args.Error(2)
+}
+
 func (m *MockFTSPropertyRepository) GetByPriceRangePaginated(minPrice, maxPrice float64, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
 	args := m.Called(minPrice, maxPrice, pagination)
 	return args.Get(0).([]domain.Property), args.Int(1), args.Error(2)
@@ -107,6 +134,18 @@ func (m *MockFTSPropertyRepository) AdvancedSearchPaginated(params repository.Ad
 	return args.Get(0).([]repository.PropertySearchResult), args.Int(1), args.Error(2)
 }
 
+func (m *MockFTSPropertyRepository) GetSearchFacets(params repository.AdvancedSearchParams) (*domain.SearchFacets, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchFacets), args.Error(1)
+}
+
+func (m *MockFTSPropertyRepository) ForTenant(tenantID string) repository.PropertyRepository {
+	return m
+}
+
 // MockFTSImageRepository is a minimal mock for the ImageRepository
 type MockFTSImageRepository struct {
 	mock.Mock
@@ -120,6 +159,10 @@ func (m *MockFTSImageRepository) GetByID(id string) (*domain.ImageInfo, error) {
 	return nil, nil
 }
 
+func (m *MockFTSImageRepository) GetVisibleByID(id string) (*domain.ImageInfo, error) {
+	return nil, nil
+}
+
 func (m *MockFTSImageRepository) GetByPropertyID(propertyID string) ([]domain.ImageInfo, error) {
 	return nil, nil
 }
@@ -160,6 +203,10 @@ func (m *MockFTSImageRepository) GetImageStats() (map[string]interface{}, error)
 	return nil, nil
 }
 
+func (m *MockFTSImageRepository) ListImageFiles() ([]domain.ImageInfo, error) {
+	return nil, nil
+}
+
 func TestPropertyService_SearchProperties_FTS(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -544,6 +591,45 @@ func TestPropertyService_AdvancedSearch_ParameterNormalization(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestPropertyService_AdvancedSearchFaceted(t *testing.T) {
+	mockRepo := &MockFTSPropertyRepository{}
+	results := []repository.PropertySearchResult{{Property: *createTestProperty(), Rank: 0.7}}
+	facets := domain.NewSearchFacets()
+	facets.Add(domain.FacetProvince, "Guayas", 1)
+
+	mockRepo.On("AdvancedSearchPaginated", mock.AnythingOfType("repository.AdvancedSearchParams"), mock.Anything).Return(results, 1, nil)
+	mockRepo.On("GetSearchFacets", mock.MatchedBy(func(params repository.AdvancedSearchParams) bool {
+		return params.Query == "casa" && params.Province == "Guayas" && params.Limit == 0
+	})).Return(facets, nil).Once()
+
+	service := NewPropertyService(mockRepo, &MockFTSImageRepository{})
+	params := repository.AdvancedSearchParams{Query: " casa ", Province: "Guayas"}
+
+	response, err := service.AdvancedSearchFaceted(params, domain.NewPaginationParams())
+	assert.NoError(t, err)
+	assert.Equal(t, results, response.Data)
+	assert.Equal(t, 1, response.Pagination.TotalRecords)
+	assert.Equal(t, "Guayas", response.Facets.Provinces[0].Value)
+
+	// Second page reuses the cached facets
+	page2 := domain.NewPaginationParams()
+	page2.Page = 2
+	response, err = service.AdvancedSearchFaceted(params, page2)
+	assert.NoError(t, err)
+	assert.Same(t, facets, response.Facets)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPropertyService_AdvancedSearchFaceted_ValidationError(t *testing.T) {
+	mockRepo := &MockFTSPropertyRepository{}
+	service := NewPropertyService(mockRepo, &MockFTSImageRepository{})
+
+	_, err := service.AdvancedSearchFaceted(repository.AdvancedSearchParams{MinPrice: -1}, nil)
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetSearchFacets", mock.Anything)
+}
+
 func TestPropertyService_SearchPropertiesRanked_LimitNormalization(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -584,4 +670,35 @@ func TestPropertyService_SearchPropertiesRanked_LimitNormalization(t *testing.T)
 			mockRepo.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+// recordingIndex is a search backend that records the queries it serves
+type recordingIndex struct {
+	search.Index
+	queries []string
+}
+
+func (i *recordingIndex) Search(query string, limit int) ([]repository.PropertySearchResult, error) {
+	i.queries = append(i.queries, query)
+	return []repository.PropertySearchResult{}, nil
+}
+
+func TestPropertyService_ForTenant_SearchesTenantRows(t *testing.T) {
+	mockRepo := &MockFTSPropertyRepository{}
+	mockRepo.On("SearchPropertiesRanked", "casa tenant", 10).Return([]repository.PropertySearchResult{
+		{Property: *createTestProperty(), Rank: 0.9},
+	}, nil)
+	index := &recordingIndex{}
+
+	service := NewPropertyService(mockRepo, &MockFTSImageRepository{})
+	service.SetSearchIndexer(search.NewIndexer(index, 1))
+
+	_, err := service.SearchPropertiesRanked("casa global", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"casa global"}, index.queries)
+
+	results, err := service.ForTenant("tenant-a").SearchPropertiesRanked("casa tenant", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, []string{"casa global"}, index.queries, "the shared index spans every tenant")
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+)
+
+// Honeytoken defaults: how long the active canaries are cached by every instance, how
+// often the same suspect is alerted about, and the period reports cover by default
+const (
+	defaultHoneytokenCacheTTL      = time.Minute
+	defaultHoneytokenAlertCooldown = time.Hour
+	defaultHoneytokenReportPeriod  = 30 * 24 * time.Hour
+)
+
+// CreateHoneytokenRequest describes a canary listing
+type CreateHoneytokenRequest struct {
+	Source   string          `json:"source"`
+	Label    string          `json:"label"`
+	APIKeyID string          `json:"api_key_id,omitempty"`
+	Listing  domain.Property `json:"listing"`
+}
+
+// HoneytokenService manages canary listings: synthetic listings planted in the feeds and
+// the public API, whose detail is never linked from the site. Requests for their detail
+// are recorded, alerted on and reported per suspected source.
+type HoneytokenService struct {
+	repo          repository.HoneytokenRepository
+	notifiers     []monitoring.AlertNotifier
+	cacheTTL      time.Duration
+	alertCooldown time.Duration
+
+	mutex    sync.RWMutex
+	active   []domain.Honeytoken
+	byKey    map[string]*domain.Honeytoken
+	loadedAt time.Time
+	alerted  map[string]time.Time
+
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewHoneytokenService creates a new honeytoken service
+func NewHoneytokenService(repo repository.HoneytokenRepository, logger *log.Logger) *HoneytokenService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &HoneytokenService{
+		repo:          repo,
+		cacheTTL:      defaultHoneytokenCacheTTL,
+		alertCooldown: defaultHoneytokenAlertCooldown,
+		alerted:       map[string]time.Time{},
+		now:           time.Now,
+		logger:        logger,
+	}
+}
+
+// SetAlertNotifiers sets the channels hits are alerted on
+func (s *HoneytokenService) SetAlertNotifiers(notifiers []monitoring.AlertNotifier) {
+	s.notifiers = notifiers
+}
+
+// Create creates a canary listing (admin)
+func (s *HoneytokenService) Create(req CreateHoneytokenRequest, actor domain.Actor) (*domain.Honeytoken, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can create honeytokens")
+	}
+	if len(s.activeTokens()) >= domain.MaxActiveHoneytokens {
+		return nil, fmt.Errorf("invalid honeytoken: at most %d can be active", domain.MaxActiveHoneytokens)
+	}
+
+	var apiKeyID *string
+	if id := strings.TrimSpace(req.APIKeyID); id != "" {
+		apiKeyID = &id
+	}
+	token, err := domain.NewHoneytoken(req.Source, req.Label, req.Listing, apiKeyID, actor.UserID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(token); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Printf("Honeytoken %s (%s) created for %s by %s", token.ID, token.Label, token.Source, actor.UserID)
+	return token, nil
+}
+
+// List returns every canary, retired ones included (admin)
+func (s *HoneytokenService) List(actor domain.Actor) ([]domain.Honeytoken, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can list honeytokens")
+	}
+	return s.repo.List()
+}
+
+// Retire stops planting a canary and alerting on it (admin)
+func (s *HoneytokenService) Retire(id string, actor domain.Actor) (*domain.Honeytoken, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can retire honeytokens")
+	}
+
+	tokens, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens {
+		token := &tokens[i]
+		if token.ID != id {
+			continue
+		}
+		if !token.Active {
+			return token, nil
+		}
+		token.Retire(s.now())
+		if err := s.repo.Retire(token); err != nil {
+			return nil, err
+		}
+		s.invalidate()
+		s.logger.Printf("Honeytoken %s (%s) retired by %s", token.ID, token.Label, actor.UserID)
+		return token, nil
+	}
+	return nil, fmt.Errorf("honeytoken not found: %s", id)
+}
+
+// Report sums up the hits on canaries between from and to per source and suspect
+// (admin). Zero times default to the last 30 days.
+func (s *HoneytokenService) Report(from, to time.Time, actor domain.Actor) (*domain.HoneytokenReport, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can view honeytoken reports")
+	}
+	if to.IsZero() {
+		to = s.now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultHoneytokenReportPeriod)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid period: from must be before to")
+	}
+
+	tokens, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	hits, err := s.repo.HitsBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewHoneytokenReport(tokens, hits, from, to), nil
+}
+
+// Lookup returns the active canary with the given listing id or slug
+func (s *HoneytokenService) Lookup(idOrSlug string) (*domain.Honeytoken, bool) {
+	if idOrSlug == "" {
+		return nil, false
+	}
+	s.activeTokens()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	token, ok := s.byKey[idOrSlug]
+	return token, ok
+}
+
+// Plant inserts the canaries of a source that fit the filters into results, at
+// domain.HoneytokenPlantPosition and dated like their neighbour. Results shorter than
+// that position get none; results of limit listings keep that length.
+func (s *HoneytokenService) Plant(source, apiKeyID string, filters *domain.PropertySearchFilters, properties []domain.Property, limit int) []domain.Property {
+	if len(properties) < domain.HoneytokenPlantPosition {
+		return properties
+	}
+
+	var canaries []domain.Property
+	for _, token := range s.activeTokens() {
+		if token.PlantableIn(source, apiKeyID, filters) {
+			listing := token.Listing
+			neighbour := properties[domain.HoneytokenPlantPosition-1]
+			listing.CreatedAt, listing.UpdatedAt = neighbour.CreatedAt, neighbour.UpdatedAt
+			canaries = append(canaries, listing)
+		}
+	}
+	if len(canaries) == 0 {
+		return properties
+	}
+
+	planted := make([]domain.Property, 0, len(properties)+len(canaries))
+	planted = append(planted, properties[:domain.HoneytokenPlantPosition]...)
+	planted = append(planted, canaries...)
+	planted = append(planted, properties[domain.HoneytokenPlantPosition:]...)
+	if limit > 0 && len(planted) > limit && len(properties) <= limit {
+		planted = planted[:limit]
+	}
+	return planted
+}
+
+// RecordHit records a request for the detail of a canary and alerts on it, once per
+// canary and suspect within the alert cooldown
+func (s *HoneytokenService) RecordHit(token *domain.Honeytoken, hit domain.HoneytokenHit) {
+	now := s.now()
+	recorded := domain.NewHoneytokenHit(token.ID, hit, now)
+	if err := s.repo.RecordHit(recorded); err != nil {
+		s.logger.Printf("Failed to record hit on honeytoken %s: %v", token.ID, err)
+	}
+
+	suspect := recorded.Suspect()
+	s.logger.Printf("Honeytoken %s (%s, %s) requested at %s by %s from %s (%s)",
+		token.ID, token.Label, token.Source, recorded.Path, suspect, recorded.ClientIP, recorded.UserAgent)
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter("honeytoken_hits_total", "Requests for the detail of canary listings").Inc()
+	}
+
+	alertKey := token.ID + "|" + suspect
+	s.mutex.Lock()
+	last, alerted := s.alerted[alertKey]
+	if alerted && now.Sub(last) < s.alertCooldown {
+		s.mutex.Unlock()
+		return
+	}
+	s.alerted[alertKey] = now
+	for key, at := range s.alerted {
+		if now.Sub(at) >= s.alertCooldown {
+			delete(s.alerted, key)
+		}
+	}
+	s.mutex.Unlock()
+
+	alert := &monitoring.Alert{
+		ID:      fmt.Sprintf("honeytoken_hit_%s_%d", token.ID, now.Unix()),
+		Name:    "honeytoken_hit",
+		Level:   monitoring.AlertLevelWarning,
+		Message: fmt.Sprintf("Canary listing %q (%s) requested by %s", token.Label, token.Source, suspect),
+		Description: "A synthetic listing that is never shown by the site was requested: the catalog is " +
+			"being scraped through the " + token.Source + " channel, or a copy of it leaked",
+		Timestamp: now,
+		Tags:      map[string]string{"source": token.Source, "honeytoken": token.ID},
+		Metadata: map[string]interface{}{
+			"suspect":     suspect,
+			"path":        recorded.Path,
+			"client_ip":   recorded.ClientIP,
+			"user_agent":  recorded.UserAgent,
+			"referer":     recorded.Referer,
+			"fingerprint": recorded.Fingerprint,
+			"api_key_id":  recorded.APIKeyID,
+		},
+	}
+	for _, notifier := range s.notifiers {
+		if err := notifier.Notify(alert); err != nil {
+			s.logger.Printf("Failed to send honeytoken alert via %s: %v", notifier.GetType(), err)
+		}
+	}
+}
+
+// activeTokens returns the active canaries, reloading them when the cache expired
+func (s *HoneytokenService) activeTokens() []domain.Honeytoken {
+	now := s.now()
+	s.mutex.RLock()
+	if s.byKey != nil && now.Sub(s.loadedAt) < s.cacheTTL {
+		active := s.active
+		s.mutex.RUnlock()
+		return active
+	}
+	s.mutex.RUnlock()
+
+	tokens, err := s.repo.List()
+	if err != nil {
+		s.logger.Printf("Failed to load honeytokens: %v", err)
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return s.active
+	}
+	active := make([]domain.Honeytoken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.Active {
+			active = append(active, token)
+		}
+	}
+	byKey := make(map[string]*domain.Honeytoken, 2*len(active))
+	for i := range active {
+		byKey[active[i].Listing.ID] = &active[i]
+		byKey[active[i].Listing.Slug] = &active[i]
+	}
+
+	s.mutex.Lock()
+	s.active, s.byKey, s.loadedAt = active, byKey, now
+	s.mutex.Unlock()
+	return active
+}
+
+// invalidate makes the next use of the active canaries reload them
+func (s *HoneytokenService) invalidate() {
+	s.mutex.Lock()
+	s.byKey = nil
+	s.mutex.Unlock()
+}
+
+// HoneytokenPropertyService serves canary listings from the detail methods of a property
+// service, so their detail endpoints answer as if they were real listings
+type HoneytokenPropertyService struct {
+	PropertyServiceInterface
+	honeytokens *HoneytokenService
+}
+
+// NewHoneytokenPropertyService wraps a property service to serve the active canaries
+func NewHoneytokenPropertyService(properties PropertyServiceInterface, honeytokens *HoneytokenService) *HoneytokenPropertyService {
+	return &HoneytokenPropertyService{PropertyServiceInterface: properties, honeytokens: honeytokens}
+}
+
+// GetProperty returns the canary or the property with the given id
+func (s *HoneytokenPropertyService) GetProperty(id string) (*domain.Property, error) {
+	if token, ok := s.honeytokens.Lookup(id); ok && token.Listing.ID == id {
+		listing := token.Listing
+		return &listing, nil
+	}
+	return s.PropertyServiceInterface.GetProperty(id)
+}
+
+// GetPropertyBySlug returns the canary or the property with the given slug
+func (s *HoneytokenPropertyService) GetPropertyBySlug(slug string) (*domain.Property, error) {
+	if token, ok := s.honeytokens.Lookup(slug); ok && token.Listing.Slug == slug {
+		listing := token.Listing
+		return &listing, nil
+	}
+	return s.PropertyServiceInterface.GetPropertyBySlug(slug)
+}
+
+// ForTenant scopes the wrapped property service to a tenant, still serving the canaries
+func (s *HoneytokenPropertyService) ForTenant(tenantID string) PropertyServiceInterface {
+	if tenantID == "" {
+		return s
+	}
+	return &HoneytokenPropertyService{PropertyServiceInterface: s.PropertyServiceInterface.ForTenant(tenantID), honeytokens: s.honeytokens}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+)
+
+// memoryHoneytokens keeps honeytokens and their hits in memory
+type memoryHoneytokens struct {
+	tokens []domain.Honeytoken
+	hits   []domain.HoneytokenHit
+	lists  int
+}
+
+func (m *memoryHoneytokens) Create(token *domain.Honeytoken) error {
+	m.tokens = append(m.tokens, *token)
+	return nil
+}
+
+func (m *memoryHoneytokens) List() ([]domain.Honeytoken, error) {
+	m.lists++
+	return append([]domain.Honeytoken{}, m.tokens...), nil
+}
+
+func (m *memoryHoneytokens) Retire(token *domain.Honeytoken) error {
+	for i := range m.tokens {
+		if m.tokens[i].ID == token.ID {
+			m.tokens[i] = *token
+			return nil
+		}
+	}
+	return fmt.Errorf("honeytoken not found: %s", token.ID)
+}
+
+func (m *memoryHoneytokens) RecordHit(hit *domain.HoneytokenHit) error {
+	m.hits = append(m.hits, *hit)
+	return nil
+}
+
+func (m *memoryHoneytokens) HitsBetween(from, to time.Time) ([]domain.HoneytokenHit, error) {
+	return append([]domain.HoneytokenHit{}, m.hits...), nil
+}
+
+// recordingNotifier keeps the alerts it is sent
+type recordingNotifier struct {
+	alerts []*monitoring.Alert
+}
+
+func (n *recordingNotifier) Notify(alert *monitoring.Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func (n *recordingNotifier) GetType() string { return "recording" }
+
+// detailOnlyProperties serves the detail of the properties it holds and nothing else
+type detailOnlyProperties struct {
+	PropertyServiceInterface
+	properties map[string]*domain.Property
+}
+
+func (d detailOnlyProperties) GetProperty(id string) (*domain.Property, error) {
+	if property, ok := d.properties[id]; ok {
+		return property, nil
+	}
+	return nil, fmt.Errorf("property not found: %s", id)
+}
+
+func (d detailOnlyProperties) GetPropertyBySlug(slug string) (*domain.Property, error) {
+	for _, property := range d.properties {
+		if property.Slug == slug {
+			return property, nil
+		}
+	}
+	return nil, fmt.Errorf("property not found: %s", slug)
+}
+
+func newTestHoneytokenService(now *time.Time) (*HoneytokenService, *memoryHoneytokens, *recordingNotifier) {
+	repo := &memoryHoneytokens{}
+	notifier := &recordingNotifier{}
+	s := NewHoneytokenService(repo, log.New(&bytes.Buffer{}, "", 0))
+	s.SetAlertNotifiers([]monitoring.AlertNotifier{notifier})
+	s.now = func() time.Time { return *now }
+	return s, repo, notifier
+}
+
+func honeytokenRequest(source string) CreateHoneytokenRequest {
+	return CreateHoneytokenRequest{
+		Source: source,
+		Label:  "Canary " + source,
+		Listing: domain.Property{
+			Title: "Departamento con vista al río", Price: 120000, Province: "Guayas", City: "Guayaquil", Type: "apartment",
+		},
+	}
+}
+
+func TestHoneytokenService_CreateListRetire(t *testing.T) {
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+	s, _, _ := newTestHoneytokenService(&now)
+	admin := domain.NewActor("admin-1", "admin", "")
+
+	_, err := s.Create(honeytokenRequest(domain.HoneytokenSourceFeed), domain.NewActor("agent-1", "agent", "agency-1"))
+	assert.Contains(t, err.Error(), "permission denied")
+
+	token, err := s.Create(honeytokenRequest(domain.HoneytokenSourceFeed), admin)
+	require.NoError(t, err)
+	found, ok := s.Lookup(token.Listing.Slug)
+	require.True(t, ok, "new canaries are watched at once")
+	assert.Equal(t, token.ID, found.ID)
+
+	tokens, err := s.List(admin)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	retired, err := s.Retire(token.ID, admin)
+	require.NoError(t, err)
+	assert.False(t, retired.Active)
+	_, ok = s.Lookup(token.Listing.ID)
+	assert.False(t, ok, "retired canaries are not watched")
+
+	_, err = s.Retire("missing", admin)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestHoneytokenService_Plant(t *testing.T) {
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+	s, _, _ := newTestHoneytokenService(&now)
+	token, err := s.Create(honeytokenRequest(domain.HoneytokenSourceFeed), domain.NewActor("admin-1", "admin", ""))
+	require.NoError(t, err)
+
+	listings := []domain.Property{
+		{ID: "p1", CreatedAt: now}, {ID: "p2", CreatedAt: now.Add(-time.Hour)}, {ID: "p3", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	planted := s.Plant(domain.HoneytokenSourceFeed, "", &domain.PropertySearchFilters{Cities: []string{"Guayaquil"}}, listings, 3)
+	require.Len(t, planted, 3, "full pages keep their size")
+	assert.Equal(t, []string{"p1", "p2", token.Listing.ID}, []string{planted[0].ID, planted[1].ID, planted[2].ID})
+	assert.Equal(t, now.Add(-time.Hour), planted[2].CreatedAt, "canaries are dated like their neighbour")
+
+	assert.Len(t, s.Plant(domain.HoneytokenSourceFeed, "", nil, listings, 10), 4)
+	assert.Equal(t, listings, s.Plant(domain.HoneytokenSourcePublicAPI, "", nil, listings, 3), "canaries stay in their channel")
+	assert.Equal(t, listings, s.Plant(domain.HoneytokenSourceFeed, "", &domain.PropertySearchFilters{Cities: []string{"Quito"}}, listings, 3))
+	assert.Len(t, s.Plant(domain.HoneytokenSourceFeed, "", nil, listings[:1], 3), 1, "short results get no canary")
+}
+
+func TestHoneytokenService_RecordHitAndReport(t *testing.T) {
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+	s, repo, notifier := newTestHoneytokenService(&now)
+	admin := domain.NewActor("admin-1", "admin", "")
+	token, err := s.Create(honeytokenRequest(domain.HoneytokenSourcePublicAPI), admin)
+	require.NoError(t, err)
+
+	hit := domain.HoneytokenHit{Path: "/api/public/v1/properties/" + token.Listing.ID, APIKeyID: "key-1", ClientIP: "203.0.113.7"}
+	s.RecordHit(token, hit)
+	s.RecordHit(token, hit)
+	require.Len(t, repo.hits, 2, "every hit is recorded")
+	require.Len(t, notifier.alerts, 1, "the same suspect is alerted about once per cooldown")
+	assert.Equal(t, "api_key:key-1", notifier.alerts[0].Metadata["suspect"])
+	assert.Equal(t, monitoring.AlertLevelWarning, notifier.alerts[0].Level)
+
+	s.RecordHit(token, domain.HoneytokenHit{Path: hit.Path, Fingerprint: "fp-9"})
+	assert.Len(t, notifier.alerts, 2, "other suspects are alerted about")
+	now = now.Add(2 * time.Hour)
+	s.RecordHit(token, hit)
+	assert.Len(t, notifier.alerts, 3, "alerts repeat after the cooldown")
+
+	now = now.Add(time.Minute)
+	_, err = s.Report(time.Time{}, time.Time{}, domain.NewActor("buyer-1", "buyer", ""))
+	assert.Contains(t, err.Error(), "permission denied")
+	report, err := s.Report(time.Time{}, time.Time{}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Hits)
+	require.Len(t, report.Suspects, 2)
+	assert.Equal(t, "api_key:key-1", report.Suspects[0].Suspect)
+	assert.Equal(t, 3, report.Suspects[0].Hits)
+}
+
+func TestHoneytokenPropertyService_ServesCanaries(t *testing.T) {
+	now := time.Date(2025, 9, 14, 10, 0, 0, 0, time.UTC)
+	s, repo, _ := newTestHoneytokenService(&now)
+	token, err := s.Create(honeytokenRequest(domain.HoneytokenSourceManual), domain.NewActor("admin-1", "admin", ""))
+	require.NoError(t, err)
+
+	properties := NewHoneytokenPropertyService(detailOnlyProperties{
+		properties: map[string]*domain.Property{"real-1": {ID: "real-1", Slug: "real-1-slug"}},
+	}, s)
+
+	listing, err := properties.GetProperty(token.Listing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, token.Listing.Title, listing.Title)
+	listing, err = properties.GetPropertyBySlug(token.Listing.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, token.Listing.ID, listing.ID)
+	_, err = properties.GetProperty(token.Listing.Slug)
+	assert.Error(t, err, "ids and slugs are not interchangeable")
+
+	listing, err = properties.GetProperty("real-1")
+	require.NoError(t, err)
+	assert.Equal(t, "real-1", listing.ID)
+	assert.Empty(t, repo.hits, "serving a canary records nothing by itself")
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+	"realty-core/internal/vision"
+)
+
+// ImageAnalysisConfig configures the attribute extraction of listing photos
+type ImageAnalysisConfig struct {
+	BatchSize     int     // photos analyzed per job run
+	MinConfidence float64 // labels below it suggest nothing
+}
+
+// ImageAttributeService analyzes listing photos with an image analysis provider to
+// suggest features, catalog amenities and the main image to agents. Photos are queued
+// on upload and analyzed in batches by the image-analysis job; suggestions are never
+// applied automatically.
+type ImageAttributeService struct {
+	repo         repository.ImageAnalysisRepository
+	imageRepo    repository.ImageRepository
+	propertyRepo repository.PropertyRepository
+	amenityRepo  repository.AmenityRepository
+	storage      storage.ImageStorage
+	provider     vision.Provider
+	config       ImageAnalysisConfig
+	logger       *log.Logger
+	now          func() time.Time
+}
+
+// NewImageAttributeService creates an image attribute service. Without a provider,
+// typically when IMAGE_ANALYSIS_PROVIDER is none, photos are not analyzed.
+func NewImageAttributeService(
+	repo repository.ImageAnalysisRepository,
+	imageRepo repository.ImageRepository,
+	propertyRepo repository.PropertyRepository,
+	amenityRepo repository.AmenityRepository,
+	storage storage.ImageStorage,
+	provider vision.Provider,
+	config ImageAnalysisConfig,
+	logger *log.Logger,
+) *ImageAttributeService {
+	if config.BatchSize <= 0 {
+		config.BatchSize = domain.DefaultImageAnalysisBatchSize
+	}
+	if config.MinConfidence <= 0 {
+		config.MinConfidence = domain.DefaultImageSuggestionConfidence
+	}
+	return &ImageAttributeService{
+		repo:         repo,
+		imageRepo:    imageRepo,
+		propertyRepo: propertyRepo,
+		amenityRepo:  amenityRepo,
+		storage:      storage,
+		provider:     provider,
+		config:       config,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// QueueImage queues a stored photo for analysis. Failures are logged so analysis never
+// blocks uploads.
+func (s *ImageAttributeService) QueueImage(image *domain.ImageInfo) {
+	if s.provider == nil {
+		return
+	}
+	if err := s.repo.Enqueue(domain.NewImageAnalysis(image.ID, image.PropertyID, s.now())); err != nil {
+		s.logger.Printf("Error queueing analysis of image %s: %v", image.ID, err)
+	}
+}
+
+// QueueProperty queues every photo of a listing the actor manages for analysis, such
+// as photos uploaded before analysis was enabled, and returns how many were queued
+func (s *ImageAttributeService) QueueProperty(propertyID string, actor domain.Actor) (int, error) {
+	if s.provider == nil {
+		return 0, fmt.Errorf("image analysis unavailable: no image analysis provider is configured")
+	}
+	if _, err := s.checkManager(propertyID, actor); err != nil {
+		return 0, err
+	}
+
+	images, err := s.imageRepo.GetByPropertyID(propertyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get property images: %w", err)
+	}
+	for i := range images {
+		if err := s.repo.Enqueue(domain.NewImageAnalysis(images[i].ID, propertyID, s.now())); err != nil {
+			return 0, err
+		}
+	}
+	return len(images), nil
+}
+
+// ProcessPending analyzes a batch of queued photos and returns how many were analyzed.
+// Photos the provider fails on are retried by later runs up to
+// domain.MaxImageAnalysisAttempts times.
+func (s *ImageAttributeService) ProcessPending() (int, error) {
+	if s.provider == nil {
+		return 0, nil
+	}
+
+	pending, err := s.repo.ListPending(s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	analyzed := 0
+	for i := range pending {
+		analysis := &pending[i]
+		labels, err := s.detect(analysis.ImageID)
+		if err != nil {
+			analysis.Fail(s.provider.Name(), err, s.now())
+			s.logger.Printf("Error analyzing image %s (attempt %d): %v", analysis.ImageID, analysis.Attempts, err)
+		} else {
+			analysis.Complete(s.provider.Name(), labels, s.now())
+			analyzed++
+		}
+		if err := s.repo.Save(analysis); err != nil {
+			return analyzed, err
+		}
+	}
+	return analyzed, nil
+}
+
+// detect loads a stored photo and sends it to the provider
+func (s *ImageAttributeService) detect(imageID string) ([]domain.ImageLabel, error) {
+	image, err := s.imageRepo.GetByID(imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image: %w", err)
+	}
+	data, err := s.storage.Retrieve(storagePathFromURL(s.storage.GetStorageInfo(), image.OriginalURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve image: %w", err)
+	}
+	return s.provider.Detect(data, http.DetectContentType(data))
+}
+
+// Suggest returns the features, amenities and main image suggested by the photos of a
+// listing the actor manages
+func (s *ImageAttributeService) Suggest(propertyID string, actor domain.Actor) (*domain.ImageSuggestions, error) {
+	property, err := s.checkManager(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	analyses, err := s.repo.ListByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	amenities, err := s.amenityRepo.ListByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, len(amenities))
+	for i := range amenities {
+		codes[i] = amenities[i].Code
+	}
+	mainImageID := ""
+	if main, err := s.imageRepo.GetMainImage(propertyID); err == nil && main != nil {
+		mainImageID = main.ID
+	}
+
+	return domain.SuggestImageAttributes(property, codes, mainImageID, analyses, s.config.MinConfidence), nil
+}
+
+// checkManager verifies the actor manages the property and returns it
+func (s *ImageAttributeService) checkManager(propertyID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can see its photo suggestions")
+	}
+	return property, nil
+}
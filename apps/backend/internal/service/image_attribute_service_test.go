@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/storage"
+)
+
+// memoryImageAnalysisRepository is an in-memory ImageAnalysisRepository
+type memoryImageAnalysisRepository struct {
+	analyses map[string]domain.ImageAnalysis
+}
+
+func (r *memoryImageAnalysisRepository) Enqueue(analysis *domain.ImageAnalysis) error {
+	r.analyses[analysis.ImageID] = *analysis
+	return nil
+}
+
+func (r *memoryImageAnalysisRepository) Save(analysis *domain.ImageAnalysis) error {
+	if _, ok := r.analyses[analysis.ImageID]; !ok {
+		return fmt.Errorf("image analysis not found: %s", analysis.ImageID)
+	}
+	r.analyses[analysis.ImageID] = *analysis
+	return nil
+}
+
+func (r *memoryImageAnalysisRepository) ListPending(limit int) ([]domain.ImageAnalysis, error) {
+	pending := []domain.ImageAnalysis{}
+	for _, analysis := range r.sorted() {
+		if analysis.Status == domain.ImageAnalysisPending && len(pending) < limit {
+			pending = append(pending, analysis)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memoryImageAnalysisRepository) ListByProperty(propertyID string) ([]domain.ImageAnalysis, error) {
+	analyses := []domain.ImageAnalysis{}
+	for _, analysis := range r.sorted() {
+		if analysis.PropertyID == propertyID {
+			analyses = append(analyses, analysis)
+		}
+	}
+	return analyses, nil
+}
+
+func (r *memoryImageAnalysisRepository) sorted() []domain.ImageAnalysis {
+	analyses := []domain.ImageAnalysis{}
+	for _, analysis := range r.analyses {
+		analyses = append(analyses, analysis)
+	}
+	sort.Slice(analyses, func(i, j int) bool { return analyses[i].ImageID < analyses[j].ImageID })
+	return analyses
+}
+
+// stubVisionProvider labels photos by their content
+type stubVisionProvider struct {
+	labels map[string][]domain.ImageLabel
+}
+
+func (p *stubVisionProvider) Name() string {
+	return "stub"
+}
+
+func (p *stubVisionProvider) Detect(data []byte, contentType string) ([]domain.ImageLabel, error) {
+	labels, ok := p.labels[string(data)]
+	if !ok {
+		return nil, fmt.Errorf("model unavailable")
+	}
+	return labels, nil
+}
+
+func TestImageAttributeService_ProcessAndSuggest(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/images", domain.MaxUploadSize)
+	require.NoError(t, err)
+
+	property := domain.NewProperty("Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	imageRepo := new(MockImageRepository)
+	images := []domain.ImageInfo{}
+	for _, name := range []string{"pool", "facade", "broken"} {
+		path, err := imageStorage.Store([]byte(name), name+".jpg")
+		require.NoError(t, err)
+		image := domain.ImageInfo{ID: "img-" + name, PropertyID: "prop-1", OriginalURL: imageStorage.GetURL(path)}
+		images = append(images, image)
+		imageRepo.On("GetByID", image.ID).Return(&image, nil)
+	}
+	imageRepo.On("GetByPropertyID", "prop-1").Return(images, nil)
+	imageRepo.On("GetMainImage", "prop-1").Return(&images[0], nil)
+
+	provider := &stubVisionProvider{labels: map[string][]domain.ImageLabel{
+		"pool":   {{Name: "swimming pool", Confidence: 0.92}, {Name: "garden", Confidence: 0.7}},
+		"facade": {{Name: "facade", Confidence: 0.88}},
+	}}
+	analyses := &memoryImageAnalysisRepository{analyses: map[string]domain.ImageAnalysis{}}
+	amenities := &memoryAmenities{properties: map[string][]domain.PropertyAmenity{}}
+	service := NewImageAttributeService(analyses, imageRepo, propertyRepo, amenities, imageStorage, provider,
+		ImageAnalysisConfig{BatchSize: 2}, log.New(os.Stderr, "", 0))
+	service.now = func() time.Time { return time.Date(2025, 9, 20, 10, 0, 0, 0, time.UTC) }
+
+	_, err = service.QueueProperty("prop-1", domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	queued, err := service.QueueProperty("prop-1", owner)
+	require.NoError(t, err)
+	assert.Equal(t, 3, queued)
+
+	// Batches are bounded; failed photos are retried by later runs
+	analyzed, err := service.ProcessPending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, analyzed)
+	analyzed, err = service.ProcessPending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, analyzed)
+	assert.Equal(t, 2, analyses.analyses["img-broken"].Attempts)
+
+	suggestions, err := service.Suggest("prop-1", owner)
+	require.NoError(t, err)
+	assert.Equal(t, 2, suggestions.Analyzed)
+	assert.Equal(t, 1, suggestions.Pending)
+	require.Len(t, suggestions.Attributes, 2)
+	assert.Equal(t, "pool", suggestions.Attributes[0].Attribute)
+	assert.Equal(t, []string{"img-pool"}, suggestions.Attributes[0].ImageIDs)
+	require.NotNil(t, suggestions.MainImage)
+	assert.Equal(t, "img-facade", suggestions.MainImage.ImageID)
+	assert.False(t, property.Pool, "suggestions are never applied")
+}
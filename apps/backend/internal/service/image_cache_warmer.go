@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// ImageCacheWarmerConfig configures the image cache warmer
+type ImageCacheWarmerConfig struct {
+	Interval          time.Duration // time between runs after the one at startup
+	FeaturedLimit     int           // featured properties warmed, newest first
+	TopViewed         int           // most viewed properties warmed
+	ImagesPerProperty int           // images warmed per property, in display order
+	ThumbnailSizes    []int
+	Presets           []string // names of the image presets warmed
+}
+
+// ImageRenditions generates the cached thumbnails and preset renditions of images;
+// ImageService implements it
+type ImageRenditions interface {
+	GetImagesByProperty(propertyID string) ([]domain.ImageInfo, error)
+	GenerateThumbnail(imageID string, size int) ([]byte, error)
+	GetImagePreset(imageID, presetName string) ([]byte, string, error)
+}
+
+// ImageCacheWarmer pre-generates the thumbnails and preset renditions of featured and most viewed
+// properties, which the home page shows, so their images are served from the cache
+// right after a deploy instead of being generated by the first visitors.
+//
+// The image cache is held in memory by each instance, so the warmer runs on every
+// instance with its own loop rather than as a scheduled job, which would run on one.
+type ImageCacheWarmer struct {
+	images       ImageRenditions
+	propertyRepo repository.PropertyRepository
+	config       ImageCacheWarmerConfig
+	logger       *log.Logger
+	now          func() time.Time
+
+	mu      sync.Mutex
+	running bool
+	last    *domain.ImageCacheWarmReport
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// DefaultImageCacheWarmPresets are the presets the home page shows
+var DefaultImageCacheWarmPresets = []string{"thumb", "card", "hero"}
+
+// NewImageCacheWarmer creates an image cache warmer
+func NewImageCacheWarmer(images ImageRenditions, propertyRepo repository.PropertyRepository, config ImageCacheWarmerConfig, logger *log.Logger) *ImageCacheWarmer {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.ImagesPerProperty <= 0 {
+		config.ImagesPerProperty = 3
+	}
+	if config.ThumbnailSizes == nil {
+		config.ThumbnailSizes = []int{domain.ThumbnailSize}
+	}
+	if config.Presets == nil {
+		config.Presets = DefaultImageCacheWarmPresets
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &ImageCacheWarmer{
+		images:       images,
+		propertyRepo: propertyRepo,
+		config:       config,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Start warms the cache now and then on its interval until Stop is called
+func (w *ImageCacheWarmer) Start() {
+	w.mu.Lock()
+	if w.stop != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	stop, done := w.stop, w.done
+	w.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			w.runLogged()
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	w.logger.Printf("Image cache warming scheduled every %s", w.config.Interval)
+}
+
+// Stop stops the scheduled runs and waits for a running one to finish
+func (w *ImageCacheWarmer) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// LastReport returns the report of the most recent run, or nil
+func (w *ImageCacheWarmer) LastReport() *domain.ImageCacheWarmReport {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
+
+// runLogged runs the warmer and logs its outcome
+func (w *ImageCacheWarmer) runLogged() {
+	report, err := w.Run()
+	if err != nil {
+		w.logger.Printf("Image cache warming failed: %v", err)
+		return
+	}
+	w.logger.Printf("Image cache warmed with %d renditions of %d images of %d properties in %s (%d failed)",
+		report.Renditions, report.Images, report.Properties, report.Duration().Round(time.Millisecond), report.Failed)
+}
+
+// Run loads the renditions of the featured and most viewed properties into the cache
+// once. Renditions already cached are not generated again, so runs after the first
+// only load what was evicted or is new.
+func (w *ImageCacheWarmer) Run() (*domain.ImageCacheWarmReport, error) {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("image cache warming is already running")
+	}
+	w.running = true
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		w.running = false
+		w.mu.Unlock()
+	}()
+
+	report := &domain.ImageCacheWarmReport{StartedAt: w.now()}
+	properties, err := w.warmedProperties()
+	if err != nil {
+		return nil, err
+	}
+	report.Properties = len(properties)
+
+	for _, propertyID := range properties {
+		images, err := w.images.GetImagesByProperty(propertyID)
+		if err != nil {
+			report.AddError(fmt.Errorf("property %s: %w", propertyID, err))
+			continue
+		}
+		if len(images) > w.config.ImagesPerProperty {
+			images = images[:w.config.ImagesPerProperty]
+		}
+		for _, image := range images {
+			report.Images++
+			w.warmImage(image.ID, report)
+		}
+	}
+
+	report.FinishedAt = w.now()
+	w.mu.Lock()
+	w.last = report
+	w.mu.Unlock()
+	return report, nil
+}
+
+// warmImage loads the thumbnails and preset renditions of an image into the cache
+func (w *ImageCacheWarmer) warmImage(imageID string, report *domain.ImageCacheWarmReport) {
+	for _, size := range w.config.ThumbnailSizes {
+		if _, err := w.images.GenerateThumbnail(imageID, size); err != nil {
+			report.AddError(fmt.Errorf("thumbnail %d of image %s: %w", size, imageID, err))
+			continue
+		}
+		report.Renditions++
+	}
+	for _, preset := range w.config.Presets {
+		if _, _, err := w.images.GetImagePreset(imageID, preset); err != nil {
+			report.AddError(fmt.Errorf("%s rendition of image %s: %w", preset, imageID, err))
+			continue
+		}
+		report.Renditions++
+	}
+}
+
+// warmedProperties returns the IDs of the available featured properties followed by the
+// most viewed ones, without duplicates
+func (w *ImageCacheWarmer) warmedProperties() ([]string, error) {
+	featured := true
+	available := []string{domain.StatusAvailable}
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(properties []domain.Property) {
+		for _, property := range properties {
+			if !seen[property.ID] {
+				seen[property.ID] = true
+				ids = append(ids, property.ID)
+			}
+		}
+	}
+
+	if w.config.FeaturedLimit > 0 {
+		properties, _, err := w.propertyRepo.GetByFiltersPaginated(
+			&domain.PropertySearchFilters{Featured: &featured, Status: available},
+			&domain.PaginationParams{Page: 1, PageSize: w.config.FeaturedLimit, SortBy: "created_at", SortDesc: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list featured properties: %w", err)
+		}
+		add(properties)
+	}
+	if w.config.TopViewed > 0 {
+		properties, _, err := w.propertyRepo.GetByFiltersPaginated(
+			&domain.PropertySearchFilters{Status: available},
+			&domain.PaginationParams{Page: 1, PageSize: w.config.TopViewed, SortBy: "view_count", SortDesc: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list most viewed properties: %w", err)
+		}
+		add(properties)
+	}
+	return ids, nil
+}
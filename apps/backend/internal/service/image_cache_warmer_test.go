@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// fakeImageRenditions records the renditions requested per image
+type fakeImageRenditions struct {
+	images   map[string][]domain.ImageInfo
+	failing  string // image whose renditions fail
+	rendered map[string][]string
+}
+
+func (f *fakeImageRenditions) GetImagesByProperty(propertyID string) ([]domain.ImageInfo, error) {
+	return f.images[propertyID], nil
+}
+
+func (f *fakeImageRenditions) GenerateThumbnail(imageID string, size int) ([]byte, error) {
+	return f.render(imageID, "thumb")
+}
+
+func (f *fakeImageRenditions) GetImagePreset(imageID, presetName string) ([]byte, string, error) {
+	data, err := f.render(imageID, presetName)
+	return data, "image/jpeg", err
+}
+
+func (f *fakeImageRenditions) render(imageID, kind string) ([]byte, error) {
+	if imageID == f.failing {
+		return nil, errors.New("original not found")
+	}
+	f.rendered[imageID] = append(f.rendered[imageID], kind)
+	return []byte(kind), nil
+}
+
+func TestImageCacheWarmer_WarmsFeaturedAndMostViewed(t *testing.T) {
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByFiltersPaginated", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+		return f.Featured != nil && *f.Featured
+	}), mock.Anything).Return([]domain.Property{{ID: "featured-1"}, {ID: "popular-1"}}, 2, nil)
+	propertyRepo.On("GetByFiltersPaginated", mock.MatchedBy(func(f *domain.PropertySearchFilters) bool {
+		return f.Featured == nil
+	}), mock.MatchedBy(func(p *domain.PaginationParams) bool {
+		return p.SortBy == "view_count" && p.SortDesc && p.PageSize == 10
+	})).Return([]domain.Property{{ID: "popular-1"}, {ID: "popular-2"}}, 2, nil)
+
+	images := &fakeImageRenditions{
+		images: map[string][]domain.ImageInfo{
+			"featured-1": {{ID: "img-1"}, {ID: "img-2"}, {ID: "img-3"}},
+			"popular-1":  {{ID: "img-4"}},
+			"popular-2":  {{ID: "img-broken"}},
+		},
+		failing:  "img-broken",
+		rendered: map[string][]string{},
+	}
+	warmer := NewImageCacheWarmer(images, propertyRepo, ImageCacheWarmerConfig{
+		FeaturedLimit: 10, TopViewed: 10, ImagesPerProperty: 2, ThumbnailSizes: []int{150, 300}, Presets: []string{"card"},
+	}, nil)
+
+	report, err := warmer.Run()
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Properties)
+	assert.Equal(t, 4, report.Images)
+	assert.Equal(t, 9, report.Renditions)
+	assert.Equal(t, 3, report.Failed)
+	assert.Len(t, report.Errors, 3)
+	assert.Equal(t, []string{"thumb", "thumb", "card"}, images.rendered["img-1"])
+	assert.NotContains(t, images.rendered, "img-3")
+	assert.Same(t, report, warmer.LastReport())
+	propertyRepo.AssertExpectations(t)
+}
+
+func TestImageCacheWarmer_PropertyListFailure(t *testing.T) {
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByFiltersPaginated", mock.Anything, mock.Anything).
+		Return([]domain.Property{}, 0, errors.New("connection refused"))
+
+	warmer := NewImageCacheWarmer(&fakeImageRenditions{}, propertyRepo, ImageCacheWarmerConfig{FeaturedLimit: 5}, nil)
+	_, err := warmer.Run()
+	assert.ErrorContains(t, err, "failed to list featured properties")
+	assert.Nil(t, warmer.LastReport())
+}
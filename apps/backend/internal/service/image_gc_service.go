@@ -0,0 +1,261 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// ImageGCConfig configures the orphaned image collector
+type ImageGCConfig struct {
+	Interval     time.Duration // time between scheduled runs
+	Retention    time.Duration // minimum age before an orphan is removed
+	DryRun       bool          // report orphans without deleting anything
+	SkipPrefixes []string      // storage prefixes owned by other features
+}
+
+// DefaultImageGCSkipPrefixes are storage areas not managed through image records:
+// pending direct uploads, temporary files and video renditions.
+var DefaultImageGCSkipPrefixes = []string{"uploads/", "temp/", "videos/", "originals/videos/"}
+
+// ImageGCService reconciles image storage with the images table. Files without a
+// record and records without a file are reported, and removed once they are older
+// than the retention window.
+type ImageGCService struct {
+	imageRepo repository.ImageRepository
+	storage   storage.ImageStorage
+	metrics   *monitoring.MetricsCollector
+	config    ImageGCConfig
+	now       func() time.Time
+
+	mu      sync.Mutex
+	running bool
+	last    *domain.ImageGCReport
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewImageGCService creates a new orphaned image collector. metrics may be nil.
+func NewImageGCService(imageRepo repository.ImageRepository, storage storage.ImageStorage, metrics *monitoring.MetricsCollector, config ImageGCConfig) *ImageGCService {
+	if config.Interval <= 0 {
+		config.Interval = 24 * time.Hour
+	}
+	if config.Retention <= 0 {
+		config.Retention = domain.DefaultImageGCRetention
+	}
+	if config.SkipPrefixes == nil {
+		config.SkipPrefixes = DefaultImageGCSkipPrefixes
+	}
+
+	return &ImageGCService{
+		imageRepo: imageRepo,
+		storage:   storage,
+		metrics:   metrics,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Start runs the collector on its interval until Stop is called
+func (s *ImageGCService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.Run(s.config.DryRun); err != nil {
+					log.Printf("Image GC run failed: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	log.Printf("Image GC scheduled every %s (retention %s)", s.config.Interval, s.config.Retention)
+}
+
+// Stop stops the scheduled runs and waits for a running one to finish
+func (s *ImageGCService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// LastReport returns the report of the most recent run, or nil
+func (s *ImageGCService) LastReport() *domain.ImageGCReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Run reconciles storage with the database once
+func (s *ImageGCService) Run(dryRun bool) (*domain.ImageGCReport, error) {
+	lister, ok := s.storage.(storage.ObjectLister)
+	if !ok {
+		return nil, fmt.Errorf("image storage does not support listing files")
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("image GC is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	report := &domain.ImageGCReport{StartedAt: s.now(), DryRun: dryRun}
+	cutoff := report.StartedAt.Add(-s.config.Retention)
+
+	images, err := s.imageRepo.ListImageFiles()
+	if err != nil {
+		return nil, err
+	}
+	objects, err := lister.ListObjects("")
+	if err != nil {
+		return nil, err
+	}
+	report.RecordsScanned = len(images)
+
+	storageInfo := s.storage.GetStorageInfo()
+	referenced := make(map[string]bool, len(images))
+	baseNames := make(map[string]bool, len(images))
+	for _, image := range images {
+		if storedPath := storagePathFromURL(storageInfo, image.OriginalURL); storedPath != "" {
+			referenced[filepath.ToSlash(storedPath)] = true
+		}
+		if image.FileName != "" {
+			baseNames[imageBaseName(image.FileName)] = true
+		}
+	}
+
+	// Files without a record
+	existing := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		key := filepath.ToSlash(object.Key)
+		if s.skipped(key) {
+			continue
+		}
+		report.FilesScanned++
+		existing[key] = true
+
+		if referenced[key] || baseNames[imageBaseName(filepath.Base(key))] {
+			continue
+		}
+
+		report.OrphanFiles++
+		if dryRun || object.LastModified.After(cutoff) {
+			continue
+		}
+		if err := s.storage.Delete(object.Key); err != nil {
+			report.AddError(fmt.Errorf("failed to delete orphan file %s: %w", object.Key, err))
+			continue
+		}
+		report.OrphanFilesDeleted++
+		report.BytesReclaimed += object.Size
+	}
+
+	// Records without a file
+	for _, image := range images {
+		storedPath := filepath.ToSlash(storagePathFromURL(storageInfo, image.OriginalURL))
+		if storedPath == "" || existing[storedPath] {
+			continue
+		}
+
+		report.MissingFiles++
+		if dryRun || image.CreatedAt.After(cutoff) {
+			continue
+		}
+		if err := s.imageRepo.Delete(image.ID); err != nil {
+			report.AddError(fmt.Errorf("failed to delete image %s without file: %w", image.ID, err))
+			continue
+		}
+		report.RecordsDeleted++
+	}
+
+	report.FinishedAt = s.now()
+	s.record(report)
+
+	log.Printf("Image GC finished in %s: %d orphan files (%d deleted, %d bytes), %d records without files (%d deleted), %d errors",
+		report.Duration(), report.OrphanFiles, report.OrphanFilesDeleted, report.BytesReclaimed,
+		report.MissingFiles, report.RecordsDeleted, len(report.Errors))
+
+	return report, nil
+}
+
+// skipped reports whether a storage key belongs to an area the collector leaves alone
+func (s *ImageGCService) skipped(key string) bool {
+	for _, prefix := range s.config.SkipPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// record stores the report and publishes it to the monitoring module
+func (s *ImageGCService) record(report *domain.ImageGCReport) {
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+
+	metrics := s.metrics
+	if metrics == nil {
+		metrics = monitoring.GetGlobalMetrics()
+	}
+	if metrics == nil {
+		return
+	}
+
+	metrics.GetOrCreateCounter("image_gc_runs_total", "Total number of image GC runs").Inc()
+	metrics.GetOrCreateCounter("image_gc_files_deleted_total", "Total number of orphaned image files deleted").Add(int64(report.OrphanFilesDeleted))
+	metrics.GetOrCreateCounter("image_gc_bytes_reclaimed_total", "Total bytes reclaimed by image GC").Add(report.BytesReclaimed)
+	metrics.GetOrCreateCounter("image_gc_records_deleted_total", "Total number of image records without files deleted").Add(int64(report.RecordsDeleted))
+	metrics.GetOrCreateCounter("image_gc_errors_total", "Total number of image GC errors").Add(int64(len(report.Errors)))
+	metrics.GetOrCreateGauge("image_gc_orphan_files", "Orphaned image files found by the last GC run").Set(float64(report.OrphanFiles))
+	metrics.GetOrCreateGauge("image_gc_missing_files", "Image records without files found by the last GC run").Set(float64(report.MissingFiles))
+	metrics.GetOrCreateGauge("image_gc_last_run_timestamp", "Unix time of the last image GC run").Set(float64(report.FinishedAt.Unix()))
+	metrics.GetOrCreateHistogram("image_gc_duration", "Image GC run duration in milliseconds").Observe(float64(report.Duration().Milliseconds()))
+}
+
+// imageBaseName returns the "{propertyID}_{id}" stem shared by an image file and its
+// thumbnails and variants, e.g. "prop_1a2b3c4d" for "prop_1a2b3c4d_thumb.jpg"
+func imageBaseName(fileName string) string {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	parts := strings.SplitN(name, "_", 3)
+	if len(parts) < 2 {
+		return name
+	}
+	return parts[0] + "_" + parts[1]
+}
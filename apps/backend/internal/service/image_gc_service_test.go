@@ -0,0 +1,86 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/storage"
+)
+
+func TestImageGCService_Run(t *testing.T) {
+	basePath := t.TempDir()
+	imageStorage, err := storage.NewLocalImageStorage(basePath, "/uploads/images", 0)
+	require.NoError(t, err)
+
+	old := time.Now().Add(-96 * time.Hour)
+	writeFile := func(relPath string, modTime time.Time) {
+		fullPath := filepath.Join(basePath, relPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		require.NoError(t, os.WriteFile(fullPath, []byte("data"), 0644))
+		require.NoError(t, os.Chtimes(fullPath, modTime, modTime))
+	}
+	writeFile("originals/prop-1_aaaa1111.jpg", old)             // referenced
+	writeFile("thumbnails/prop-1_aaaa1111_thumb.jpg", old)      // thumbnail of a referenced image
+	writeFile("originals/prop-1_bbbb2222.jpg", old)             // orphan past retention
+	writeFile("variants/prop-1_bbbb2222_800x600_q85.webp", old) // orphan variant
+	writeFile("originals/prop-1_cccc3333.jpg", time.Now())      // recent orphan, kept
+	writeFile("uploads/prop-1/session.jpg", old)                // pending direct upload, skipped
+
+	imageRepo := new(MockImageRepository)
+	imageRepo.On("ListImageFiles").Return([]domain.ImageInfo{
+		{ID: "img-1", FileName: "prop-1_aaaa1111.jpg", OriginalURL: "/uploads/images/originals/prop-1_aaaa1111.jpg", CreatedAt: old},
+		{ID: "img-2", FileName: "prop-1_dddd4444.jpg", OriginalURL: "/uploads/images/originals/prop-1_dddd4444.jpg", CreatedAt: old},
+		{ID: "img-3", FileName: "prop-1_eeee5555.jpg", OriginalURL: "/uploads/images/originals/prop-1_eeee5555.jpg", CreatedAt: time.Now()},
+	}, nil)
+	imageRepo.On("Delete", "img-2").Return(nil)
+
+	metrics := monitoring.NewMetricsCollector()
+	gc := NewImageGCService(imageRepo, imageStorage, metrics, ImageGCConfig{})
+
+	t.Run("dry run only reports", func(t *testing.T) {
+		report, err := gc.Run(true)
+		require.NoError(t, err)
+		assert.Equal(t, 5, report.FilesScanned)
+		assert.Equal(t, 3, report.OrphanFiles)
+		assert.Equal(t, 0, report.OrphanFilesDeleted)
+		assert.Equal(t, 2, report.MissingFiles)
+		assert.Equal(t, 0, report.RecordsDeleted)
+		assert.FileExists(t, filepath.Join(basePath, "originals/prop-1_bbbb2222.jpg"))
+	})
+
+	t.Run("deletes orphans past retention", func(t *testing.T) {
+		report, err := gc.Run(false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.OrphanFilesDeleted)
+		assert.Equal(t, int64(8), report.BytesReclaimed)
+		assert.Equal(t, 1, report.RecordsDeleted)
+		assert.Empty(t, report.Errors)
+
+		assert.NoFileExists(t, filepath.Join(basePath, "originals/prop-1_bbbb2222.jpg"))
+		assert.NoFileExists(t, filepath.Join(basePath, "variants/prop-1_bbbb2222_800x600_q85.webp"))
+		assert.FileExists(t, filepath.Join(basePath, "originals/prop-1_cccc3333.jpg"))
+		assert.FileExists(t, filepath.Join(basePath, "thumbnails/prop-1_aaaa1111_thumb.jpg"))
+		assert.FileExists(t, filepath.Join(basePath, "uploads/prop-1/session.jpg"))
+		imageRepo.AssertExpectations(t)
+
+		assert.Same(t, report, gc.LastReport())
+		custom := metrics.GetMetricsSnapshot().Custom
+		assert.Equal(t, 2.0, custom["image_gc_runs_total"])
+		assert.Equal(t, 2.0, custom["image_gc_files_deleted_total"])
+		assert.Equal(t, 2.0, custom["image_gc_missing_files"])
+	})
+}
+
+func TestImageBaseName(t *testing.T) {
+	assert.Equal(t, "prop-1_aaaa1111", imageBaseName("prop-1_aaaa1111.jpg"))
+	assert.Equal(t, "prop-1_aaaa1111", imageBaseName("prop-1_aaaa1111_thumb.jpg"))
+	assert.Equal(t, "prop-1_aaaa1111", imageBaseName("prop-1_aaaa1111_800x600_q85.webp"))
+	assert.Equal(t, "random", imageBaseName("random.png"))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// ModerationQueueItem is a queued moderation record with its image
+type ModerationQueueItem struct {
+	Moderation domain.ImageModeration `json:"moderation"`
+	Image      *domain.ImageInfo      `json:"image,omitempty"`
+}
+
+// ImageModerationService manages the admin review queue of moderated images
+type ImageModerationService struct {
+	repo         repository.ImageModerationRepository
+	imageRepo    repository.ImageRepository
+	imageService ImageServiceInterface
+	logger       *log.Logger
+}
+
+// NewImageModerationService creates a new image moderation service
+func NewImageModerationService(repo repository.ImageModerationRepository, imageRepo repository.ImageRepository, imageService ImageServiceInterface, logger *log.Logger) *ImageModerationService {
+	return &ImageModerationService{
+		repo:         repo,
+		imageRepo:    imageRepo,
+		imageService: imageService,
+		logger:       logger,
+	}
+}
+
+// ListQueue returns images awaiting review, most severe first. An empty status lists the whole queue.
+func (s *ImageModerationService) ListQueue(status string, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error) {
+	statuses := domain.ModerationQueueStatuses
+	if status != "" {
+		if !domain.IsValidModerationStatus(status) || status == domain.ModerationStatusApproved {
+			return nil, fmt.Errorf("invalid queue status: %s", status)
+		}
+		statuses = []string{status}
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pagination: %w", err)
+	}
+
+	records, total, err := s.repo.ListByStatus(statuses, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]ModerationQueueItem, 0, len(records))
+	for _, record := range records {
+		item := ModerationQueueItem{Moderation: record}
+		if image, err := s.imageRepo.GetByID(record.ImageID); err == nil {
+			item.Image = image
+		}
+		items = append(items, item)
+	}
+
+	return &domain.PaginatedResponse{
+		Data:       items,
+		Pagination: domain.NewPagination(pagination.Page, pagination.PageSize, total),
+	}, nil
+}
+
+// Approve releases an image from the queue, making quarantined images visible again
+func (s *ImageModerationService) Approve(imageID, reviewerID, note string) (*domain.ImageModeration, error) {
+	record, err := s.repo.GetByImageID(imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := record.Approve(reviewerID, note); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(record); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Image %s approved by %s", imageID, reviewerID)
+	return record, nil
+}
+
+// Reject deletes an image that failed review along with its files
+func (s *ImageModerationService) Reject(imageID, reviewerID, note string) error {
+	record, err := s.repo.GetByImageID(imageID)
+	if err != nil {
+		return err
+	}
+
+	if !record.InQueue() {
+		return fmt.Errorf("image is not awaiting review: %s", record.Status)
+	}
+
+	if err := s.imageService.DeleteImage(imageID); err != nil {
+		return fmt.Errorf("failed to delete rejected image: %w", err)
+	}
+
+	s.logger.Printf("Image %s rejected by %s (score %.2f, labels %v): %s", imageID, reviewerID, record.Score, record.Labels, note)
+	return nil
+}
@@ -1,15 +1,23 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
+	"net/http"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"realty-core/internal/cache"
 	"realty-core/internal/domain"
+	"realty-core/internal/moderation"
+	"realty-core/internal/monitoring"
 	"realty-core/internal/processors"
 	"realty-core/internal/repository"
 	"realty-core/internal/storage"
@@ -20,6 +28,21 @@ type ImageServiceInterface interface {
 	// Upload uploads and processes a new image
 	Upload(propertyID string, file multipart.File, header *multipart.FileHeader, altText string) (*domain.ImageInfo, error)
 	
+	// UploadBatch uploads and processes several images for a property concurrently
+	UploadBatch(propertyID string, uploads []domain.ImageUpload, altText string) (*domain.BatchUploadResult, error)
+	
+	// ExtractArchive reads the image files contained in a zip archive
+	ExtractArchive(archive []byte) ([]domain.ImageUpload, error)
+	
+	// CreateUploadURL issues a presigned URL for uploading an image directly to storage
+	CreateUploadURL(propertyID, fileName, contentType, altText string, size int64) (*domain.UploadSession, error)
+	
+	// ConfirmUpload validates a direct upload and schedules its processing
+	ConfirmUpload(sessionID string) (*domain.UploadSession, error)
+	
+	// GetUploadSession retrieves a direct upload session by ID
+	GetUploadSession(id string) (*domain.UploadSession, error)
+	
 	// GetImage retrieves image metadata by ID
 	GetImage(id string) (*domain.ImageInfo, error)
 	
@@ -32,6 +55,9 @@ type ImageServiceInterface interface {
 	// DeleteImage deletes image and its files
 	DeleteImage(id string) error
 	
+	// ReplaceImage replaces the file of an existing image, keeping its ID and metadata
+	ReplaceImage(id, originalName string, data []byte) (*domain.ImageInfo, error)
+	
 	// ReorderImages reorders images for a property
 	ReorderImages(propertyID string, imageIDs []string) error
 	
@@ -44,6 +70,12 @@ type ImageServiceInterface interface {
 	// GetImageVariant generates and returns an image variant
 	GetImageVariant(imageID string, width, height int, format string, quality int) ([]byte, error)
 	
+	// GetImagePreset returns the rendition of an image in a named preset and its content type
+	GetImagePreset(imageID, presetName string) ([]byte, string, error)
+	
+	// ImagePresets returns the presets images can be requested in
+	ImagePresets() []domain.ImagePreset
+	
 	// GetImageStats returns image statistics
 	GetImageStats() (map[string]interface{}, error)
 	
@@ -58,6 +90,9 @@ type ImageServiceInterface interface {
 	
 	// GetCacheStats returns cache statistics
 	GetCacheStats() cache.ImageCacheStats
+	
+	// GetAgencyQuota returns the image plan and storage usage of an agency
+	GetAgencyQuota(agencyID string) (*domain.ImageQuota, error)
 }
 
 // ImageService implements ImageServiceInterface
@@ -70,6 +105,19 @@ type ImageService struct {
 	maxFileSize   int64
 	maxImages     int
 	allowedTypes  map[string]string
+	concurrency   int
+	sessions      repository.UploadSessionRepository
+	uploadURLTTL  time.Duration
+	uploadSlots   chan struct{}
+	moderator     *moderation.Moderator
+	moderations   repository.ImageModerationRepository
+	analysis      ImageAnalysisQueue
+	cdn           storage.CDN
+	quotas        repository.ImageQuotaRepository
+	quotaPlans    map[string]domain.ImageQuotaPlan
+	defaultPlan   string
+	events        EventPublisher
+	presets       map[string]domain.ImagePreset
 }
 
 // NewImageService creates a new image service
@@ -80,6 +128,7 @@ func NewImageService(
 	processor *processors.ImageProcessor,
 	cache cache.ImageCacheInterface,
 ) *ImageService {
+	presets, _ := domain.ParseImagePresets(domain.DefaultImagePresetsSpec)
 	return &ImageService{
 		imageRepo:    imageRepo,
 		propertyRepo: propertyRepo,
@@ -89,13 +138,82 @@ func NewImageService(
 		maxFileSize:  domain.MaxUploadSize,
 		maxImages:    domain.MaxImagesPerProperty,
 		allowedTypes: domain.SupportedMimeTypes,
+		concurrency:  domain.DefaultUploadConcurrency,
+		presets:      presets,
+	}
+}
+
+// SetUploadConcurrency sets how many images of a batch are processed in parallel
+func (s *ImageService) SetUploadConcurrency(concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	s.concurrency = concurrency
+}
+
+// SetDirectUploads enables presigned direct-to-storage uploads. The storage
+// backend must implement storage.PresignedUploader.
+func (s *ImageService) SetDirectUploads(sessions repository.UploadSessionRepository, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = domain.DefaultUploadURLTTL
+	}
+	s.sessions = sessions
+	s.uploadURLTTL = ttl
+	s.uploadSlots = make(chan struct{}, s.concurrency)
+}
+
+// SetModeration enables content moderation of uploaded images
+func (s *ImageService) SetModeration(moderator *moderation.Moderator, moderations repository.ImageModerationRepository) {
+	s.moderator = moderator
+	s.moderations = moderations
+}
+
+// ImageAnalysisQueue queues stored photos for attribute extraction
+type ImageAnalysisQueue interface {
+	QueueImage(image *domain.ImageInfo)
+}
+
+// SetAnalysisQueue queues uploaded and replaced photos for attribute extraction,
+// typically the ImageAttributeService
+func (s *ImageService) SetAnalysisQueue(analysis ImageAnalysisQueue) {
+	s.analysis = analysis
+}
+
+// SetMaxImagesPerProperty sets the image limit of properties not managed by an agency
+func (s *ImageService) SetMaxImagesPerProperty(maxImages int) {
+	if maxImages > 0 {
+		s.maxImages = maxImages
 	}
 }
 
+// SetQuotas enables per-agency image plans. Properties managed by an agency are limited
+// by the images per property and total storage of the agency's plan.
+func (s *ImageService) SetQuotas(quotas repository.ImageQuotaRepository, plans map[string]domain.ImageQuotaPlan, defaultPlan string) {
+	if len(plans) == 0 {
+		plans = domain.DefaultImageQuotaPlans
+	}
+	if defaultPlan == "" {
+		defaultPlan = domain.DefaultImageQuotaPlan
+	}
+	s.quotas = quotas
+	s.quotaPlans = plans
+	s.defaultPlan = defaultPlan
+}
+
+// SetCDN serves image URLs through a CDN and purges it when image files change
+func (s *ImageService) SetCDN(cdn storage.CDN) {
+	s.cdn = cdn
+}
+
+// SetEventPublisher publishes an image.processed event for each stored image
+func (s *ImageService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
 // Upload uploads and processes a new image
 func (s *ImageService) Upload(propertyID string, file multipart.File, header *multipart.FileHeader, altText string) (*domain.ImageInfo, error) {
 	// Validate property exists
-	_, err := s.propertyRepo.GetByID(propertyID)
+	property, err := s.propertyRepo.GetByID(propertyID)
 	if err != nil {
 		return nil, fmt.Errorf("property not found: %w", err)
 	}
@@ -105,14 +223,15 @@ func (s *ImageService) Upload(propertyID string, file multipart.File, header *mu
 		return nil, fmt.Errorf("upload validation failed: %w", err)
 	}
 	
-	// Check image limit
+	// Check image limit and agency storage quota
 	count, err := s.imageRepo.GetImageCount(propertyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get image count: %w", err)
 	}
 	
-	if count >= s.maxImages {
-		return nil, fmt.Errorf("maximum images per property exceeded: %d", s.maxImages)
+	reservation, err := s.checkLimits(property, count)
+	if err != nil {
+		return nil, err
 	}
 	
 	// Read file data
@@ -121,11 +240,26 @@ func (s *ImageService) Upload(propertyID string, file multipart.File, header *mu
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 	
+	return s.storeImage(propertyID, header.Filename, fileData, altText, count, reservation)
+}
+
+// storeImage validates, optimizes and stores image data, then saves its metadata. The
+// optimized file is what is stored, so it is what counts against the storage quota.
+func (s *ImageService) storeImage(propertyID, originalName string, fileData []byte, altText string, sortOrder int, reservation *storageReservation) (*domain.ImageInfo, error) {
 	// Validate image data
 	if err := s.processor.ValidateImageData(fileData, s.maxFileSize); err != nil {
 		return nil, fmt.Errorf("image validation failed: %w", err)
 	}
 	
+	// Remove EXIF/GPS metadata before anything is stored, keeping capture data
+	fileData, exif, err := s.processor.ScrubMetadata(fileData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to strip image metadata: %w", err)
+	}
+	if exif.HadLocation {
+		log.Printf("Removed GPS location from upload %s for property %s", originalName, propertyID)
+	}
+	
 	// Get original dimensions
 	width, height, format, err := s.processor.GetImageDimensions(fileData)
 	if err != nil {
@@ -133,20 +267,25 @@ func (s *ImageService) Upload(propertyID string, file multipart.File, header *mu
 	}
 	
 	// Create image info
-	fileName := domain.GenerateImageFileName(propertyID, header.Filename)
+	fileName := domain.GenerateImageFileName(propertyID, originalName)
 	imageInfo := domain.NewImageInfo(propertyID, fileName)
 	imageInfo.AltText = altText
-	imageInfo.SortOrder = count // Add at the end
+	imageInfo.SortOrder = sortOrder
+	imageInfo.SetCaptureData(exif)
 	
 	// Process image for optimized storage
 	optimizedData, stats, err := s.processor.OptimizeForSize(fileData, 1200) // 1.2MB target
 	if err != nil {
 		return nil, fmt.Errorf("failed to optimize image: %w", err)
 	}
+	if err := reservation.reserve(stats.OptimizedSize); err != nil {
+		return nil, err
+	}
 	
 	// Store optimized image
 	storedPath, err := s.storage.Store(optimizedData, fileName)
 	if err != nil {
+		reservation.release(stats.OptimizedSize)
 		return nil, fmt.Errorf("failed to store image: %w", err)
 	}
 	
@@ -158,13 +297,450 @@ func (s *ImageService) Upload(propertyID string, file multipart.File, header *mu
 	if err := s.imageRepo.Create(imageInfo); err != nil {
 		// Clean up stored file on database error
 		s.storage.Delete(storedPath)
+		reservation.release(stats.OptimizedSize)
 		return nil, fmt.Errorf("failed to save image metadata: %w", err)
 	}
 	
 	log.Printf("Image uploaded successfully: %s, size: %d -> %d bytes (%.1f%% compression)",
 		imageInfo.ID, stats.OriginalSize, stats.OptimizedSize, (1-stats.CompressionRatio)*100)
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter(monitoring.MetricImagesUploaded, "Property images uploaded").Inc()
+	}
+	
+	s.moderateImage(imageInfo, optimizedData)
+	s.queueAnalysis(imageInfo)
+	s.publishImageProcessed(imageInfo)
+	
+	return s.withCDNURL(imageInfo), nil
+}
+
+// publishImageProcessed announces a stored image to downstream consumers such as the
+// search indexer; failures are logged so consumers never block uploads
+func (s *ImageService) publishImageProcessed(imageInfo *domain.ImageInfo) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(domain.EventImageProcessed, "", imageInfo); err != nil {
+		log.Printf("Error publishing %s for image %s: %v", domain.EventImageProcessed, imageInfo.ID, err)
+	}
+}
+
+// moderateImage scores a stored image and records the outcome. Quarantined images are
+// hidden from listings and public reads by the repository; flagged ones wait in the
+// admin review queue.
+func (s *ImageService) moderateImage(imageInfo *domain.ImageInfo, data []byte) {
+	if s.moderator == nil || s.moderations == nil {
+		return
+	}
+	
+	record := s.moderator.Moderate(imageInfo.ID, imageInfo.PropertyID, data, http.DetectContentType(data))
+	if err := s.moderations.Save(record); err != nil {
+		log.Printf("Error saving moderation result for image %s: %v", imageInfo.ID, err)
+		return
+	}
+	
+	if record.IsHidden() {
+		// Renditions cached before moderation must not outlive the quarantine
+		s.cache.InvalidateImage(imageInfo.ID)
+	}
+	
+	switch {
+	case record.Error != "":
+		log.Printf("Image %s queued for manual moderation: %s", imageInfo.ID, record.Error)
+	case record.Status != domain.ModerationStatusApproved:
+		log.Printf("Image %s %s by moderation (score %.2f)", imageInfo.ID, record.Status, record.Score)
+	}
+}
+
+// queueAnalysis queues a stored image for attribute extraction when enabled
+func (s *ImageService) queueAnalysis(imageInfo *domain.ImageInfo) {
+	if s.analysis != nil {
+		s.analysis.QueueImage(imageInfo)
+	}
+}
+
+// UploadBatch uploads and processes several images for a property concurrently.
+// Files are validated individually; a failing file does not stop the others.
+// Sort orders follow the order of the uploads after the existing images.
+func (s *ImageService) UploadBatch(propertyID string, uploads []domain.ImageUpload, altText string) (*domain.BatchUploadResult, error) {
+	if len(uploads) == 0 {
+		return nil, fmt.Errorf("no files provided")
+	}
+	if len(uploads) > domain.MaxBatchUploadFiles {
+		return nil, fmt.Errorf("too many files: %d, max: %d", len(uploads), domain.MaxBatchUploadFiles)
+	}
+	
+	// Validate property exists
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	
+	count, err := s.imageRepo.GetImageCount(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image count: %w", err)
+	}
+	
+	maxImages, quota, err := s.uploadLimits(property)
+	if err != nil {
+		return nil, err
+	}
+	reservation := &storageReservation{quota: quota}
+	
+	start := time.Now()
+	result := &domain.BatchUploadResult{
+		PropertyID: propertyID,
+		Total:      len(uploads),
+		Results:    make([]domain.ImageUploadResult, len(uploads)),
+	}
+	
+	// Validate every file and reserve sort orders before processing in parallel
+	type uploadJob struct {
+		index     int
+		sortOrder int
+	}
+	var jobs []uploadJob
+	nextSortOrder := count
+	for i, upload := range uploads {
+		result.Results[i] = domain.ImageUploadResult{
+			Index:    i,
+			FileName: upload.FileName,
+			Size:     int64(len(upload.Data)),
+		}
+		
+		if err := s.validateUploadFile(upload.FileName, upload.ContentType, int64(len(upload.Data))); err != nil {
+			result.Results[i].Error = fmt.Sprintf("upload validation failed: %v", err)
+			continue
+		}
+		if nextSortOrder >= maxImages {
+			result.Results[i].Error = fmt.Sprintf("maximum images per property exceeded: %d", maxImages)
+			continue
+		}
+		if err := checkStorageQuota(quota, 1); err != nil {
+			result.Results[i].Error = err.Error()
+			continue
+		}
+		
+		jobs = append(jobs, uploadJob{index: i, sortOrder: nextSortOrder})
+		nextSortOrder++
+	}
+	
+	workers := s.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	
+	queue := make(chan uploadJob)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				upload := uploads[job.index]
+				fileStart := time.Now()
+				
+				imageInfo, err := s.storeImage(propertyID, upload.FileName, upload.Data, altText, job.sortOrder, reservation)
+				
+				fileResult := &result.Results[job.index]
+				fileResult.DurationMs = time.Since(fileStart).Milliseconds()
+				if err != nil {
+					fileResult.Error = err.Error()
+					continue
+				}
+				fileResult.Success = true
+				fileResult.Image = imageInfo
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	
+	for _, fileResult := range result.Results {
+		if fileResult.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	
+	log.Printf("Batch upload for property %s: %d succeeded, %d failed in %dms",
+		propertyID, result.Succeeded, result.Failed, result.DurationMs)
+	
+	return result, nil
+}
+
+// ExtractArchive reads the image files contained in a zip archive.
+// Directories and hidden or macOS metadata entries are skipped.
+func (s *ImageService) ExtractArchive(archive []byte) ([]domain.ImageUpload, error) {
+	return extractZipImages(archive, domain.MaxBatchUploadFiles, s.maxFileSize)
+}
+
+// extractZipImages extracts up to maxFiles entries of at most maxFileSize bytes each.
+// Oversized entries are returned truncated to maxFileSize+1 bytes so validation rejects them
+// without inflating the whole entry in memory.
+func extractZipImages(archive []byte, maxFiles int, maxFileSize int64) ([]domain.ImageUpload, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+	
+	var uploads []domain.ImageUpload
+	for _, entry := range reader.File {
+		name := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		
+		if len(uploads) >= maxFiles {
+			return nil, fmt.Errorf("archive contains more than %d files", maxFiles)
+		}
+		
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in archive: %w", entry.Name, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in archive: %w", entry.Name, err)
+		}
+		
+		contentType := domain.GetMimeTypeFromFilename(name)
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		
+		uploads = append(uploads, domain.ImageUpload{
+			FileName:    name,
+			ContentType: contentType,
+			Data:        data,
+		})
+	}
+	
+	if len(uploads) == 0 {
+		return nil, fmt.Errorf("archive contains no files")
+	}
+	
+	return uploads, nil
+}
+
+// CreateUploadURL issues a presigned URL for uploading an image directly to storage.
+// The declared file is validated up front; the stored object is checked again on confirmation.
+func (s *ImageService) CreateUploadURL(propertyID, fileName, contentType, altText string, size int64) (*domain.UploadSession, error) {
+	uploader, err := s.directUploader()
+	if err != nil {
+		return nil, err
+	}
+	
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	
+	if err := s.validateUploadFile(fileName, contentType, size); err != nil {
+		return nil, fmt.Errorf("upload validation failed: %w", err)
+	}
+	
+	// Pending uploads count towards the image limit so parallel URLs cannot exceed it
+	count, err := s.imageRepo.GetImageCount(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image count: %w", err)
+	}
+	pending, err := s.sessions.CountPending(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checkLimits(property, count+pending); err != nil {
+		return nil, err
+	}
+	
+	session := domain.NewUploadSession(propertyID, fileName, contentType, altText, size, s.uploadURLTTL)
+	uploadURL, err := uploader.PresignUpload(session.ObjectKey, contentType, s.uploadURLTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+	
+	if err := s.sessions.Create(session); err != nil {
+		return nil, err
+	}
+	
+	session.UploadURL = uploadURL
+	return session, nil
+}
+
+// ConfirmUpload checks that the object was uploaded with the declared content type and
+// an allowed size, then processes it in the background. Poll GetUploadSession for the result.
+func (s *ImageService) ConfirmUpload(sessionID string) (*domain.UploadSession, error) {
+	uploader, err := s.directUploader()
+	if err != nil {
+		return nil, err
+	}
+	
+	session, err := s.sessions.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	
+	if session.Status != domain.UploadStatusPending {
+		return nil, fmt.Errorf("upload session is %s", session.Status)
+	}
+	
+	// Every transition out of pending is claimed atomically: only the request that
+	// claims the session may remove its object or process it
+	if session.IsExpired() {
+		session.Status = domain.UploadStatusExpired
+		session.UpdatedAt = time.Now()
+		if claimed, _ := s.sessions.UpdatePending(session); claimed {
+			s.storage.Delete(session.ObjectKey)
+		}
+		return nil, fmt.Errorf("upload session expired")
+	}
+	
+	info, err := uploader.Stat(session.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("uploaded file not found: %w", err)
+	}
+	
+	if err := s.validateStoredUpload(session, info); err != nil {
+		session.MarkFailed(err.Error())
+		if claimed, _ := s.sessions.UpdatePending(session); claimed {
+			s.storage.Delete(session.ObjectKey)
+		}
+		return nil, fmt.Errorf("upload validation failed: %w", err)
+	}
+	
+	session.MarkProcessing(info.Size)
+	claimed, err := s.sessions.UpdatePending(session)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("upload session is no longer pending")
+	}
+	
+	go s.processDirectUpload(*session)
+	
+	return session, nil
+}
+
+// GetUploadSession retrieves a direct upload session by ID
+func (s *ImageService) GetUploadSession(id string) (*domain.UploadSession, error) {
+	if s.sessions == nil {
+		return nil, fmt.Errorf("direct uploads are not enabled")
+	}
+	return s.sessions.GetByID(id)
+}
+
+// ExpireUploadSessions expires pending sessions that were never confirmed and removes
+// any objects uploaded for them
+func (s *ImageService) ExpireUploadSessions() (int, error) {
+	if s.sessions == nil {
+		return 0, nil
+	}
+	
+	expired, err := s.sessions.ExpirePending(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	
+	for _, session := range expired {
+		if s.storage.Exists(session.ObjectKey) {
+			s.storage.Delete(session.ObjectKey)
+		}
+	}
+	
+	return len(expired), nil
+}
+
+// directUploader returns the storage as a presigned uploader when direct uploads are enabled
+func (s *ImageService) directUploader() (storage.PresignedUploader, error) {
+	if s.sessions == nil {
+		return nil, fmt.Errorf("direct uploads are not enabled")
+	}
+	uploader, ok := s.storage.(storage.PresignedUploader)
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s does not support direct uploads", s.storage.GetStorageInfo().Type)
+	}
+	return uploader, nil
+}
+
+// validateStoredUpload compares the stored object with what the session declared
+func (s *ImageService) validateStoredUpload(session *domain.UploadSession, info storage.ObjectInfo) error {
+	contentType := strings.TrimSpace(strings.Split(info.ContentType, ";")[0])
+	if !strings.EqualFold(contentType, session.ContentType) {
+		return fmt.Errorf("content type mismatch: declared %s, uploaded %s", session.ContentType, contentType)
+	}
+	return s.validateUploadFile(session.FileName, contentType, info.Size)
+}
+
+// processDirectUpload optimizes an uploaded object into a property image and removes the raw upload.
+// At most s.concurrency uploads are processed at the same time.
+func (s *ImageService) processDirectUpload(session domain.UploadSession) {
+	s.uploadSlots <- struct{}{}
+	defer func() { <-s.uploadSlots }()
+	
+	imageInfo, err := s.storeDirectUpload(&session)
+	if err != nil {
+		log.Printf("Direct upload %s failed: %v", session.ID, err)
+		session.MarkFailed(err.Error())
+	} else {
+		session.MarkCompleted(imageInfo.ID)
+	}
+	
+	if err := s.sessions.Update(&session); err != nil {
+		log.Printf("Error updating upload session %s: %v", session.ID, err)
+	}
+	
+	if err := s.storage.Delete(session.ObjectKey); err != nil {
+		log.Printf("Error removing raw upload %s: %v", session.ObjectKey, err)
+	}
+}
+
+// storeDirectUpload reads the raw upload and stores it as a property image. The object
+// can be replaced after ConfirmUpload checked its size, so no more than the size limit
+// is ever read.
+func (s *ImageService) storeDirectUpload(session *domain.UploadSession) (*domain.ImageInfo, error) {
+	uploader, err := s.directUploader()
+	if err != nil {
+		return nil, err
+	}
+	
+	body, err := uploader.Open(session.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, s.maxFileSize+1))
+	body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	if int64(len(data)) > s.maxFileSize {
+		return nil, fmt.Errorf("file too large: more than %d bytes", s.maxFileSize)
+	}
+	
+	property, err := s.propertyRepo.GetByID(session.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	
+	count, err := s.imageRepo.GetImageCount(session.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image count: %w", err)
+	}
+	reservation, err := s.checkLimits(property, count)
+	if err != nil {
+		return nil, err
+	}
 	
-	return imageInfo, nil
+	return s.storeImage(session.PropertyID, session.FileName, data, session.AltText, count, reservation)
 }
 
 // GetImage retrieves image metadata by ID
@@ -173,7 +749,12 @@ func (s *ImageService) GetImage(id string) (*domain.ImageInfo, error) {
 		return nil, fmt.Errorf("image ID cannot be empty")
 	}
 	
-	return s.imageRepo.GetByID(id)
+	image, err := s.imageRepo.GetVisibleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.withCDNURL(image), nil
 }
 
 // GetImagesByProperty retrieves all images for a property
@@ -182,7 +763,12 @@ func (s *ImageService) GetImagesByProperty(propertyID string) ([]domain.ImageInf
 		return nil, fmt.Errorf("property ID cannot be empty")
 	}
 	
-	return s.imageRepo.GetByPropertyID(propertyID)
+	images, err := s.imageRepo.GetByPropertyID(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.withCDNURLs(images), nil
 }
 
 // UpdateImageMetadata updates image metadata
@@ -240,10 +826,233 @@ func (s *ImageService) DeleteImage(id string) error {
 		return fmt.Errorf("failed to delete image from database: %w", err)
 	}
 	
+	s.purgeCDN(storedPath, image.FileName)
+	
 	log.Printf("Image deleted successfully: %s", id)
 	return nil
 }
 
+// ReplaceImage replaces the file of an existing image. The new file is processed like
+// an upload; the old file and its thumbnails are deleted and purged from the CDN.
+func (s *ImageService) ReplaceImage(id, originalName string, data []byte) (*domain.ImageInfo, error) {
+	if id == "" {
+		return nil, fmt.Errorf("image ID cannot be empty")
+	}
+	
+	image, err := s.imageRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image: %w", err)
+	}
+	
+	if err := s.processor.ValidateImageData(data, s.maxFileSize); err != nil {
+		return nil, fmt.Errorf("image validation failed: %w", err)
+	}
+	
+	data, exif, err := s.processor.ScrubMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to strip image metadata: %w", err)
+	}
+	
+	width, height, format, err := s.processor.GetImageDimensions(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image dimensions: %w", err)
+	}
+	
+	optimizedData, stats, err := s.processor.OptimizeForSize(data, 1200) // 1.2MB target
+	if err != nil {
+		return nil, fmt.Errorf("failed to optimize image: %w", err)
+	}
+	
+	// Only the growth of the stored file counts against the agency storage quota
+	if growth := stats.OptimizedSize - image.Size; growth > 0 && s.quotas != nil {
+		property, err := s.propertyRepo.GetByID(image.PropertyID)
+		if err != nil {
+			return nil, fmt.Errorf("property not found: %w", err)
+		}
+		_, quota, err := s.uploadLimits(property)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkStorageQuota(quota, growth); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Store under a new name so stale cached copies can never be served for the new file
+	fileName := domain.GenerateImageFileName(image.PropertyID, originalName)
+	storedPath, err := s.storage.Store(optimizedData, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	
+	oldPath := s.extractPathFromURL(image.OriginalURL)
+	oldFileName := image.FileName
+	
+	image.FileName = fileName
+	image.OriginalURL = s.storage.GetURL(storedPath)
+	image.SetCaptureData(exif)
+	image.SetProcessingResults(width, height, stats.OptimizedSize, format, 85, true)
+	
+	if err := s.imageRepo.Update(image); err != nil {
+		s.storage.Delete(storedPath)
+		return nil, fmt.Errorf("failed to update image: %w", err)
+	}
+	
+	if oldPath != "" {
+		if err := s.storage.Delete(oldPath); err != nil {
+			log.Printf("Warning: failed to delete replaced image file %s: %v", oldPath, err)
+		}
+	}
+	s.deleteImageVariants(oldFileName)
+	s.cache.InvalidateImage(id)
+	s.purgeCDN(oldPath, oldFileName)
+	
+	s.moderateImage(image, optimizedData)
+	s.queueAnalysis(image)
+	
+	log.Printf("Image replaced successfully: %s", id)
+	return s.withCDNURL(image), nil
+}
+
+// GetAgencyQuota returns the image plan and storage usage of an agency
+func (s *ImageService) GetAgencyQuota(agencyID string) (*domain.ImageQuota, error) {
+	if s.quotas == nil {
+		return nil, fmt.Errorf("image quotas are not enabled")
+	}
+	if agencyID == "" {
+		return nil, fmt.Errorf("agency ID cannot be empty")
+	}
+	
+	planName, err := s.quotas.GetAgencyPlan(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	plan, ok := s.quotaPlans[planName]
+	if !ok {
+		if plan, ok = s.quotaPlans[s.defaultPlan]; !ok {
+			return nil, fmt.Errorf("default image quota plan %s is not configured", s.defaultPlan)
+		}
+	}
+	
+	usage, err := s.quotas.GetAgencyUsage(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	
+	return domain.NewImageQuota(agencyID, plan, *usage), nil
+}
+
+// uploadLimits returns the image limit of a property and, for agency properties when
+// quotas are enabled, the agency quota
+func (s *ImageService) uploadLimits(property *domain.Property) (int, *domain.ImageQuota, error) {
+	if s.quotas == nil || property == nil || property.GetAgencyID() == nil {
+		return s.maxImages, nil, nil
+	}
+	
+	quota, err := s.GetAgencyQuota(*property.GetAgencyID())
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get agency quota: %w", err)
+	}
+	return quota.MaxImagesPerProperty, quota, nil
+}
+
+// checkLimits verifies a property can take one more image and returns the reservation
+// its optimized file is checked against once its size is known. Agencies whose storage
+// is already full are refused up front.
+func (s *ImageService) checkLimits(property *domain.Property, count int) (*storageReservation, error) {
+	maxImages, quota, err := s.uploadLimits(property)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxImages {
+		return nil, fmt.Errorf("maximum images per property exceeded: %d", maxImages)
+	}
+	if err := checkStorageQuota(quota, 1); err != nil {
+		return nil, err
+	}
+	return &storageReservation{quota: quota}, nil
+}
+
+// storageReservation counts the files stored against an agency quota by one request, so
+// the files of a batch cannot overrun it together
+type storageReservation struct {
+	mu       sync.Mutex
+	quota    *domain.ImageQuota
+	reserved int64
+}
+
+// reserve claims room for a stored file of the given size
+func (r *storageReservation) reserve(bytes int64) error {
+	if r == nil || r.quota == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := checkStorageQuota(r.quota, r.reserved+bytes); err != nil {
+		return err
+	}
+	r.reserved += bytes
+	return nil
+}
+
+// release returns the room of a file that was not stored after all
+func (r *storageReservation) release(bytes int64) {
+	if r == nil || r.quota == nil {
+		return
+	}
+	r.mu.Lock()
+	r.reserved -= bytes
+	r.mu.Unlock()
+}
+
+// checkStorageQuota verifies additional bytes fit in an agency quota (nil means unlimited)
+func checkStorageQuota(quota *domain.ImageQuota, bytes int64) error {
+	if quota == nil || quota.Allows(bytes) {
+		return nil
+	}
+	return fmt.Errorf("storage quota exceeded: %s plan allows %d bytes, %d used", quota.Plan, quota.MaxStorageBytes, quota.UsedBytes)
+}
+
+// withCDNURL rewrites the image URL to the CDN host when a CDN is configured
+func (s *ImageService) withCDNURL(image *domain.ImageInfo) *domain.ImageInfo {
+	if s.cdn == nil || image == nil {
+		return image
+	}
+	
+	if storedPath := s.extractPathFromURL(image.OriginalURL); storedPath != "" {
+		image.OriginalURL = s.cdn.URL(storedPath)
+	}
+	return image
+}
+
+// withCDNURLs rewrites the URLs of several images
+func (s *ImageService) withCDNURLs(images []domain.ImageInfo) []domain.ImageInfo {
+	for i := range images {
+		s.withCDNURL(&images[i])
+	}
+	return images
+}
+
+// purgeCDN invalidates the cached copies of an image file and its thumbnail.
+// Purge failures are logged; the CDN eventually expires the objects on its own.
+func (s *ImageService) purgeCDN(storedPath, fileName string) {
+	if s.cdn == nil {
+		return
+	}
+	
+	paths := []string{}
+	if storedPath != "" {
+		paths = append(paths, storedPath)
+	}
+	if fileName != "" {
+		paths = append(paths, s.thumbnailPath(fileName))
+	}
+	
+	if err := s.cdn.Purge(paths); err != nil {
+		log.Printf("Warning: failed to purge %s CDN for %v: %v", s.cdn.Name(), paths, err)
+	}
+}
+
 // ReorderImages reorders images for a property
 func (s *ImageService) ReorderImages(propertyID string, imageIDs []string) error {
 	if propertyID == "" {
@@ -289,7 +1098,12 @@ func (s *ImageService) GetMainImage(propertyID string) (*domain.ImageInfo, error
 		return nil, fmt.Errorf("property ID cannot be empty")
 	}
 	
-	return s.imageRepo.GetMainImage(propertyID)
+	image, err := s.imageRepo.GetMainImage(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.withCDNURL(image), nil
 }
 
 // GetImageVariant generates and returns an image variant
@@ -304,7 +1118,7 @@ func (s *ImageService) GetImageVariant(imageID string, width, height int, format
 	}
 	
 	// Get image info
-	image, err := s.imageRepo.GetByID(imageID)
+	image, err := s.imageRepo.GetVisibleByID(imageID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get image: %w", err)
 	}
@@ -357,6 +1171,71 @@ func (s *ImageService) GetImageVariant(imageID string, width, height int, format
 	return variantData, nil
 }
 
+// SetImagePresets sets the presets images can be requested in
+func (s *ImageService) SetImagePresets(presets map[string]domain.ImagePreset) {
+	if len(presets) > 0 {
+		s.presets = presets
+	}
+}
+
+// ImagePresets returns the presets images can be requested in, ordered by name
+func (s *ImageService) ImagePresets() []domain.ImagePreset {
+	return domain.SortedImagePresets(s.presets)
+}
+
+// GetImagePreset returns the rendition of an image in a preset. Renditions are kept in
+// the cache and, on local storage, next to the variants.
+func (s *ImageService) GetImagePreset(imageID, presetName string) ([]byte, string, error) {
+	if imageID == "" {
+		return nil, "", fmt.Errorf("image ID cannot be empty")
+	}
+	preset, ok := s.presets[strings.ToLower(presetName)]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown image preset: %s", presetName)
+	}
+	contentType := preset.ContentType()
+	
+	if cachedData, _, found := s.cache.GetPreset(imageID, preset.Key()); found {
+		return cachedData, contentType, nil
+	}
+	
+	image, err := s.imageRepo.GetVisibleByID(imageID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get image: %w", err)
+	}
+	
+	renditionName := strings.TrimSuffix(image.FileName, filepath.Ext(image.FileName)) + "_" + preset.Key()
+	renditionPath := filepath.Join("variants", renditionName)
+	if s.storage.Exists(renditionPath) {
+		if data, err := s.storage.Retrieve(renditionPath); err == nil {
+			s.cache.SetPreset(imageID, preset.Key(), data, contentType)
+			return data, contentType, nil
+		}
+	}
+	
+	originalData, err := s.storage.Retrieve(s.extractPathFromURL(image.OriginalURL))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve original image: %w", err)
+	}
+	
+	var data []byte
+	if preset.Crop == domain.ImageCropFill {
+		data, err = s.processor.GenerateFilledVariant(originalData, preset.Width, preset.Height, preset.Quality, preset.Format)
+	} else {
+		data, err = s.processor.GenerateImageVariant(originalData, preset.Width, preset.Height, preset.Quality, preset.Format)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate %s rendition: %w", preset.Name, err)
+	}
+	
+	if localStorage, ok := s.storage.(*storage.LocalImageStorage); ok {
+		localStorage.StoreVariant(data, renditionName, "variants")
+	}
+	s.cache.SetPreset(imageID, preset.Key(), data, contentType)
+	
+	return data, contentType, nil
+}
+
 // GetImageStats returns image statistics
 func (s *ImageService) GetImageStats() (map[string]interface{}, error) {
 	stats, err := s.imageRepo.GetImageStats()
@@ -383,16 +1262,20 @@ func (s *ImageService) ValidateUpload(header *multipart.FileHeader) error {
 		return fmt.Errorf("file header cannot be nil")
 	}
 	
-	if header.Size == 0 {
+	return s.validateUploadFile(header.Filename, header.Header.Get("Content-Type"), header.Size)
+}
+
+// validateUploadFile validates file name, content type and size of an upload
+func (s *ImageService) validateUploadFile(fileName, contentType string, size int64) error {
+	if size == 0 {
 		return fmt.Errorf("file is empty")
 	}
 	
-	if header.Size > s.maxFileSize {
-		return fmt.Errorf("file too large: %d bytes, max: %d bytes", header.Size, s.maxFileSize)
+	if size > s.maxFileSize {
+		return fmt.Errorf("file too large: %d bytes, max: %d bytes", size, s.maxFileSize)
 	}
 	
 	// Check content type
-	contentType := header.Header.Get("Content-Type")
 	if contentType == "" {
 		return fmt.Errorf("content type not specified")
 	}
@@ -402,8 +1285,8 @@ func (s *ImageService) ValidateUpload(header *multipart.FileHeader) error {
 	}
 	
 	// Check file extension
-	ext := strings.ToLower(filepath.Ext(header.Filename))
-	format := domain.GetImageFormatFromFilename(header.Filename)
+	ext := strings.ToLower(filepath.Ext(fileName))
+	format := domain.GetImageFormatFromFilename(fileName)
 	if format == "" {
 		return fmt.Errorf("unsupported file extension: %s", ext)
 	}
@@ -426,9 +1309,17 @@ func (s *ImageService) extractPathFromURL(url string) string {
 		return ""
 	}
 	
+	return storagePathFromURL(s.storage.GetStorageInfo(), url)
+}
+
+// storagePathFromURL extracts the storage path from an image URL
+func storagePathFromURL(storageInfo storage.StorageInfo, url string) string {
+	if url == "" {
+		return ""
+	}
+	
 	// For local storage, URL format is: /path/to/file or baseURL/path/to/file
 	// Extract the relative path part
-	storageInfo := s.storage.GetStorageInfo()
 	if storageInfo.BaseURL != "" {
 		// Remove base URL prefix
 		url = strings.TrimPrefix(url, storageInfo.BaseURL)
@@ -444,11 +1335,8 @@ func (s *ImageService) deleteImageVariants(fileName string) {
 		return
 	}
 	
-	baseName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
-	
 	// Delete thumbnails
-	thumbnailPath := filepath.Join("thumbnails", baseName+"_thumb.jpg")
-	s.storage.Delete(thumbnailPath)
+	s.storage.Delete(s.thumbnailPath(fileName))
 	
 	// Note: For a full implementation, you might want to:
 	// 1. Keep track of generated variants in a cache/database
@@ -458,6 +1346,12 @@ func (s *ImageService) deleteImageVariants(fileName string) {
 	log.Printf("TODO: Clean up variants for image: %s", fileName)
 }
 
+// thumbnailPath returns the storage path of the thumbnail of an image file
+func (s *ImageService) thumbnailPath(fileName string) string {
+	baseName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	return filepath.Join("thumbnails", baseName+"_thumb.jpg")
+}
+
 // GenerateThumbnail generates a thumbnail for an image
 func (s *ImageService) GenerateThumbnail(imageID string, size int) ([]byte, error) {
 	if imageID == "" {
@@ -470,7 +1364,7 @@ func (s *ImageService) GenerateThumbnail(imageID string, size int) ([]byte, erro
 	}
 	
 	// Get image info
-	image, err := s.imageRepo.GetByID(imageID)
+	image, err := s.imageRepo.GetVisibleByID(imageID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get image: %w", err)
 	}
@@ -550,7 +1444,7 @@ func (s *ImageService) GetPaginatedImages(pagination *domain.PaginationParams) (
 		end = len(allImages)
 	}
 	
-	return allImages[offset:end], nil
+	return s.withCDNURLs(allImages[offset:end]), nil
 }
 
 // CountImages returns the total count of images
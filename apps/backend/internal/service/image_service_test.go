@@ -0,0 +1,324 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/cache"
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// buildZip creates an in-memory zip archive with the given entries
+func buildZip(t *testing.T, entries map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for name, data := range entries {
+		fileWriter, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = fileWriter.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestExtractZipImages(t *testing.T) {
+	archive := buildZip(t, map[string][]byte{
+		"fotos/sala.jpg":          []byte("jpeg-data"),
+		"fotos/piscina.PNG":       []byte("png-data"),
+		"fotos/notas.txt":         []byte("text"),
+		"fotos/.DS_Store":         []byte("hidden"),
+		"__MACOSX/fotos/sala.jpg": []byte("metadata"),
+	})
+
+	uploads, err := extractZipImages(archive, 10, 1024)
+	require.NoError(t, err)
+	require.Len(t, uploads, 3)
+
+	byName := make(map[string]string)
+	for _, upload := range uploads {
+		byName[upload.FileName] = upload.ContentType
+	}
+	assert.Equal(t, "image/jpeg", byName["sala.jpg"])
+	assert.Equal(t, "image/png", byName["piscina.PNG"])
+	// Unsupported files are returned so validation can report them per file
+	assert.Equal(t, "application/octet-stream", byName["notas.txt"])
+}
+
+func TestExtractZipImages_Limits(t *testing.T) {
+	_, err := extractZipImages([]byte("not a zip"), 10, 1024)
+	assert.Error(t, err)
+
+	archive := buildZip(t, map[string][]byte{"a.jpg": []byte("a"), "b.jpg": []byte("b")})
+	_, err = extractZipImages(archive, 1, 1024)
+	assert.Error(t, err)
+
+	_, err = extractZipImages(buildZip(t, map[string][]byte{"fotos/": nil}), 10, 1024)
+	assert.Error(t, err)
+
+	// Oversized entries are truncated to just above the limit
+	archive = buildZip(t, map[string][]byte{"big.jpg": bytes.Repeat([]byte("x"), 100)})
+	uploads, err := extractZipImages(archive, 10, 10)
+	require.NoError(t, err)
+	assert.Len(t, uploads[0].Data, 11)
+}
+
+func TestImageService_ValidateUploadFile(t *testing.T) {
+	service := NewImageService(nil, nil, nil, nil, nil)
+
+	assert.NoError(t, service.validateUploadFile("casa.jpg", "image/jpeg", 2048))
+	assert.EqualError(t, service.validateUploadFile("casa.jpg", "image/jpeg", 0), "file is empty")
+	assert.Error(t, service.validateUploadFile("casa.jpg", "image/jpeg", service.maxFileSize+1))
+	assert.Error(t, service.validateUploadFile("casa.gif", "image/gif", 2048))
+	assert.Error(t, service.validateUploadFile("casa.txt", "image/jpeg", 2048))
+}
+
+// recordingCDN is a CDN that records purges
+type recordingCDN struct {
+	purged []string
+}
+
+func (c *recordingCDN) Name() string { return "recording" }
+
+func (c *recordingCDN) URL(filePath string) string {
+	return "https://cdn.example.com/" + filePath
+}
+
+func (c *recordingCDN) Purge(filePaths []string) error {
+	c.purged = append(c.purged, filePaths...)
+	return nil
+}
+
+func TestImageService_StoreDirectUploadReadsAtMostTheLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The object was replaced with a larger one after ConfirmUpload checked its size
+		w.Write(bytes.Repeat([]byte("x"), 64))
+	}))
+	defer server.Close()
+
+	s3, err := storage.NewS3ImageStorage(storage.S3Config{Endpoint: server.URL, Region: "us-east-1", Bucket: "images",
+		AccessKeyID: "key", SecretAccessKey: "secret", UsePathStyle: true})
+	require.NoError(t, err)
+
+	service := NewImageService(nil, nil, s3, nil, nil)
+	service.SetDirectUploads(repository.NewPostgreSQLUploadSessionRepository(nil), time.Minute)
+	service.maxFileSize = 16
+
+	_, err = service.storeDirectUpload(&domain.UploadSession{ID: "upload-1", PropertyID: "property-1", ObjectKey: "uploads/a.jpg"})
+	assert.EqualError(t, err, "file too large: more than 16 bytes")
+}
+
+func TestImageService_CDN(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/images", 0)
+	require.NoError(t, err)
+
+	imageRepo := new(MockImageRepository)
+	image := domain.ImageInfo{ID: "img-1", PropertyID: "prop-1", FileName: "prop-1_photo.jpg", OriginalURL: "/uploads/images/originals/prop-1_photo.jpg"}
+	first, second := image, image
+	imageRepo.On("GetVisibleByID", "img-1").Return(&first, nil).Once()
+	imageRepo.On("GetByID", "img-1").Return(&second, nil).Once()
+	imageRepo.On("Delete", "img-1").Return(nil)
+
+	cdn := &recordingCDN{}
+	service := NewImageService(imageRepo, nil, imageStorage, nil, cache.NewDisabledImageCache())
+	service.SetCDN(cdn)
+
+	found, err := service.GetImage("img-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/originals/prop-1_photo.jpg", found.OriginalURL)
+
+	require.NoError(t, service.DeleteImage("img-1"))
+	assert.Equal(t, []string{"originals/prop-1_photo.jpg", filepath.Join("thumbnails", "prop-1_photo_thumb.jpg")}, cdn.purged)
+}
+
+// fakeImageQuotaRepository returns a fixed plan and usage for every agency
+type fakeImageQuotaRepository struct {
+	plan  string
+	usage domain.ImageStorageUsage
+}
+
+func (r *fakeImageQuotaRepository) GetAgencyPlan(agencyID string) (string, error) {
+	return r.plan, nil
+}
+
+func (r *fakeImageQuotaRepository) GetAgencyUsage(agencyID string) (*domain.ImageStorageUsage, error) {
+	usage := r.usage
+	return &usage, nil
+}
+
+func TestImageService_Quotas(t *testing.T) {
+	header := &multipart.FileHeader{
+		Filename: "photo.jpg",
+		Size:     500,
+		Header:   textproto.MIMEHeader{"Content-Type": []string{"image/jpeg"}},
+	}
+
+	agencyProperty := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	agencyProperty.ID = "prop-agency"
+	agencyProperty.SetAgency("agency-1")
+	ownerProperty := domain.NewProperty("Depto", "Departamento en Cumbayá", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	ownerProperty.ID = "prop-owner"
+
+	newService := func(maxImages int, usage domain.ImageStorageUsage) *ImageService {
+		propertyRepo := new(MockPropertyRepository)
+		propertyRepo.On("GetByID", "prop-agency").Return(agencyProperty, nil)
+		propertyRepo.On("GetByID", "prop-owner").Return(ownerProperty, nil)
+		imageRepo := new(MockImageRepository)
+		imageRepo.On("GetImageCount", mock.Anything).Return(3, nil)
+
+		plans := map[string]domain.ImageQuotaPlan{
+			"basic": {Name: "basic", MaxImagesPerProperty: maxImages, MaxStorageBytes: 1000},
+		}
+		service := NewImageService(imageRepo, propertyRepo, nil, nil, cache.NewDisabledImageCache())
+		service.SetMaxImagesPerProperty(3)
+		service.SetQuotas(&fakeImageQuotaRepository{plan: "unknown", usage: usage}, plans, "basic")
+		return service
+	}
+
+	t.Run("plan limits images per agency property", func(t *testing.T) {
+		_, err := newService(3, domain.ImageStorageUsage{}).Upload("prop-agency", nil, header, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maximum images per property exceeded: 3")
+	})
+
+	t.Run("full storage refuses uploads up front", func(t *testing.T) {
+		_, err := newService(10, domain.ImageStorageUsage{ImageCount: 2, UsedBytes: 1000}).Upload("prop-agency", nil, header, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "storage quota exceeded: basic plan allows 1000 bytes, 1000 used")
+	})
+
+	t.Run("properties without agency use the global limit", func(t *testing.T) {
+		_, err := newService(10, domain.ImageStorageUsage{}).Upload("prop-owner", nil, header, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maximum images per property exceeded: 3")
+	})
+
+	t.Run("unknown plans fall back to the default plan", func(t *testing.T) {
+		quota, err := newService(10, domain.ImageStorageUsage{ImageCount: 2, UsedBytes: 250}).GetAgencyQuota("agency-1")
+		require.NoError(t, err)
+		assert.Equal(t, "basic", quota.Plan)
+		assert.Equal(t, int64(750), quota.RemainingBytes)
+		assert.Equal(t, 25.0, quota.UsedPercent)
+	})
+
+	t.Run("a missing default plan is an error", func(t *testing.T) {
+		service := newService(10, domain.ImageStorageUsage{})
+		service.defaultPlan = "premium"
+		_, err := service.GetAgencyQuota("agency-1")
+		assert.EqualError(t, err, "default image quota plan premium is not configured")
+	})
+
+	t.Run("quotas disabled", func(t *testing.T) {
+		_, err := NewImageService(nil, nil, nil, nil, nil).GetAgencyQuota("agency-1")
+		assert.Error(t, err)
+	})
+}
+
+func TestImageService_QuotaCountsTheStoredFile(t *testing.T) {
+	processor := processors.NewImageProcessor(0, 0)
+	var original bytes.Buffer
+	require.NoError(t, png.Encode(&original, image.NewRGBA(image.Rect(0, 0, 400, 300))))
+	_, stats, err := processor.OptimizeForSize(original.Bytes(), 1200)
+	require.NoError(t, err)
+	stored := stats.OptimizedSize
+
+	store := func(t *testing.T, remaining int64) error {
+		imageStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/images", 0)
+		require.NoError(t, err)
+		imageRepo := new(MockImageRepository)
+		imageRepo.On("Create", mock.Anything).Return(nil).Maybe()
+		service := NewImageService(imageRepo, nil, imageStorage, processor, nil)
+
+		quota := &domain.ImageQuota{Plan: "basic", MaxStorageBytes: 10 * stored, UsedBytes: 10*stored - remaining}
+		_, err = service.storeImage("prop-1", "photo.png", original.Bytes(), "", 0, &storageReservation{quota: quota})
+		return err
+	}
+
+	assert.NoError(t, store(t, stored), "the optimized file fits")
+	err = store(t, stored-1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "storage quota exceeded")
+}
+
+func TestImageService_GetImagePreset(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/images", 0)
+	require.NoError(t, err)
+	var original bytes.Buffer
+	require.NoError(t, jpeg.Encode(&original, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil))
+	_, err = imageStorage.Store(original.Bytes(), "prop-1_photo.jpg")
+	require.NoError(t, err)
+
+	imageRepo := new(MockImageRepository)
+	imageRepo.On("GetVisibleByID", "img-1").Return(&domain.ImageInfo{
+		ID: "img-1", FileName: "prop-1_photo.jpg", OriginalURL: "/uploads/images/originals/prop-1_photo.jpg",
+	}, nil).Once()
+
+	imageCache := cache.NewImageCache(cache.DefaultImageCacheConfig())
+	service := NewImageService(imageRepo, nil, imageStorage, processors.NewImageProcessor(0, 0), imageCache)
+	service.SetImagePresets(map[string]domain.ImagePreset{
+		"og-image": {Name: "og-image", Width: 380, Height: 200, Quality: 80, Crop: domain.ImageCropFill, Format: "png"},
+	})
+
+	data, contentType, err := service.GetImagePreset("img-1", "OG-Image")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	rendition, err := png.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 380, rendition.Width)
+	assert.Equal(t, 200, rendition.Height)
+
+	// Served from the cache and the storage afterwards
+	cached, _, err := service.GetImagePreset("img-1", "og-image")
+	require.NoError(t, err)
+	assert.Equal(t, data, cached)
+	assert.True(t, imageStorage.Exists(filepath.Join("variants", "prop-1_photo_og-image_380x200_q80_fill.png")))
+
+	_, _, err = service.GetImagePreset("img-1", "card")
+	assert.EqualError(t, err, "unknown image preset: card")
+	imageRepo.AssertExpectations(t)
+}
+
+func TestImageService_QuarantinedImagesAreNotServed(t *testing.T) {
+	imageStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/images", 0)
+	require.NoError(t, err)
+	var original bytes.Buffer
+	require.NoError(t, jpeg.Encode(&original, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil))
+	_, err = imageStorage.Store(original.Bytes(), "prop-1_photo.jpg")
+	require.NoError(t, err)
+
+	imageRepo := new(MockImageRepository)
+	imageRepo.On("GetVisibleByID", "img-q").Return((*domain.ImageInfo)(nil), fmt.Errorf("image not found: img-q"))
+
+	imageCache := cache.NewImageCache(cache.DefaultImageCacheConfig())
+	service := NewImageService(imageRepo, nil, imageStorage, processors.NewImageProcessor(0, 0), imageCache)
+	service.SetImagePresets(map[string]domain.ImagePreset{
+		"card": {Name: "card", Width: 380, Height: 200, Quality: 80, Crop: domain.ImageCropFill, Format: "jpg"},
+	})
+
+	_, err = service.GetImage("img-q")
+	assert.ErrorContains(t, err, "not found", "by ID")
+	_, _, err = service.GetImagePreset("img-q", "card")
+	assert.ErrorContains(t, err, "not found", "by variant")
+	_, err = service.GetImageVariant("img-q", 380, 200, "jpg", 80)
+	assert.ErrorContains(t, err, "not found")
+	_, err = service.GenerateThumbnail("img-q", 150)
+	assert.ErrorContains(t, err, "not found", "as thumbnail")
+}
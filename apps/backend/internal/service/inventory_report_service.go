@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// InventoryReportService serves the inventory trends of agencies from snapshots that
+// a scheduled job aggregates into a reporting table
+type InventoryReportService struct {
+	repo   repository.InventoryReportRepository
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewInventoryReportService creates an inventory report service
+func NewInventoryReportService(repo repository.InventoryReportRepository, logger *log.Logger) *InventoryReportService {
+	return &InventoryReportService{
+		repo:   repo,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// ComputeSnapshots aggregates the weekly and monthly snapshots of every agency for the
+// current period and the previous one, which is completed by its last run. It returns
+// the number of snapshots stored.
+func (s *InventoryReportService) ComputeSnapshots() (int, error) {
+	now := s.now().UTC()
+	stored := 0
+
+	for _, period := range []string{domain.InventoryPeriodWeekly, domain.InventoryPeriodMonthly} {
+		start, end, err := domain.InventoryPeriodBounds(period, now)
+		if err != nil {
+			return stored, err
+		}
+		previousStart, _, err := domain.InventoryPeriodBounds(period, start.Add(-time.Nanosecond))
+		if err != nil {
+			return stored, err
+		}
+
+		for _, bounds := range [][2]time.Time{{previousStart, start}, {start, end}} {
+			snapshots, err := s.repo.ComputeSnapshots(period, bounds[0], bounds[1], now)
+			if err != nil {
+				return stored, err
+			}
+			if err := s.repo.UpsertSnapshots(snapshots); err != nil {
+				return stored, err
+			}
+			stored += len(snapshots)
+		}
+	}
+
+	s.logger.Printf("%d inventory snapshots computed", stored)
+	return stored, nil
+}
+
+// GetInventoryReport lists the latest snapshots of an agency, newest first. The agency
+// account and admins can read it.
+func (s *InventoryReportService) GetInventoryReport(agencyID, period string, limit int, actor domain.Actor) ([]domain.InventorySnapshot, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can view its inventory reports")
+	}
+	if period == "" {
+		period = domain.InventoryPeriodMonthly
+	}
+	if !domain.IsValidInventoryPeriod(period) {
+		return nil, fmt.Errorf("invalid period: must be weekly or monthly")
+	}
+	if limit <= 0 {
+		limit = domain.DefaultInventorySnapshots
+	}
+	if limit > domain.MaxInventorySnapshots {
+		limit = domain.MaxInventorySnapshots
+	}
+
+	return s.repo.ListSnapshots(agencyID, period, limit)
+}
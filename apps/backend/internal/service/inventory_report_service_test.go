@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryInventoryReports records computed periods and stores snapshots in memory
+type memoryInventoryReports struct {
+	computed  []string
+	snapshots []domain.InventorySnapshot
+}
+
+func (r *memoryInventoryReports) ComputeSnapshots(period string, start, end, computedAt time.Time) ([]domain.InventorySnapshot, error) {
+	r.computed = append(r.computed, period+" "+start.Format("2006-01-02"))
+	return []domain.InventorySnapshot{{AgencyID: "agency-1", Period: period, PeriodStart: start, PeriodEnd: end, ComputedAt: computedAt}}, nil
+}
+
+func (r *memoryInventoryReports) UpsertSnapshots(snapshots []domain.InventorySnapshot) error {
+	r.snapshots = append(r.snapshots, snapshots...)
+	return nil
+}
+
+func (r *memoryInventoryReports) ListSnapshots(agencyID, period string, limit int) ([]domain.InventorySnapshot, error) {
+	snapshots := []domain.InventorySnapshot{}
+	for _, snapshot := range r.snapshots {
+		if snapshot.AgencyID == agencyID && snapshot.Period == period && len(snapshots) < limit {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+func TestInventoryReportService(t *testing.T) {
+	repo := &memoryInventoryReports{}
+	var logs bytes.Buffer
+	svc := NewInventoryReportService(repo, log.New(&logs, "", 0))
+	svc.now = func() time.Time { return time.Date(2025, 9, 2, 1, 10, 0, 0, time.UTC) }
+
+	stored, err := svc.ComputeSnapshots()
+	require.NoError(t, err)
+	assert.Equal(t, 4, stored)
+	assert.Equal(t, []string{"weekly 2025-08-25", "weekly 2025-09-01", "monthly 2025-08-01", "monthly 2025-09-01"}, repo.computed)
+
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+	snapshots, err := svc.GetInventoryReport("agency-1", "", 0, agency)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	_, err = svc.GetInventoryReport("agency-1", "daily", 0, agency)
+	assert.ErrorContains(t, err, "invalid period")
+
+	_, err = svc.GetInventoryReport("agency-1", "", 0, domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+
+	"realty-core/internal/scheduler"
+)
+
+// Names of the built-in scheduled jobs
+const (
+	JobListingExpiry        = "listing-expiry"
+	JobFeaturedExpiry       = "featured-expiry"
+	JobImageGC              = "image-gc"
+	JobUploadSessionExpiry  = "upload-session-expiry"
+	JobDraftExpiry          = "draft-expiry"
+	JobPIIReEncryption      = "pii-reencryption"
+	JobSessionCleanup       = "session-cleanup"
+	JobReservationExpiry    = "reservation-expiry"
+	JobInventorySnapshots   = "inventory-snapshots"
+	JobSectorGuideStats     = "sector-guide-stats"
+	JobRentOverdue          = "rent-overdue"
+	JobInvoiceAuthorization = "invoice-authorization"
+	JobRetentionPruning     = "retention-pruning"
+	JobImageAnalysis        = "image-analysis"
+	JobCalendarSync         = "calendar-sync"
+	JobLeadSLA              = "lead-sla-reassign"
+)
+
+// JobServices holds the services whose maintenance runs as scheduled jobs; nil
+// services are not registered
+type JobServices struct {
+	Listings *ListingLifecycleService
+	Featured *FeaturedService
+	ImageGC  *ImageGCService
+	Images   *ImageService
+	Drafts   *PropertyDraftService
+	PII      *PIIRotationService
+	Sessions *SessionService
+
+	Reservations *ReservationService
+	Inventory    *InventoryReportService
+	SectorGuides *SectorGuideService
+	RentLedger   *RentLedgerService
+	Invoices     *ElectronicInvoiceService
+	// Retention prunes old records, stale drafts included, replacing the draft expiry job
+	Retention *RetentionService
+	// ImageAnalysis extracts attributes from queued listing photos
+	ImageAnalysis *ImageAttributeService
+	// CalendarSync pushes appointments to and imports busy times from agents' calendars
+	CalendarSync *CalendarSyncService
+	// LeadSLA moves leads nobody answered in time to another agent
+	LeadSLA *LeadSLAService
+}
+
+// RegisterJobs registers the built-in jobs with their default schedules. Services
+// registered here should not also be started with their own Start loop. Webhook
+// delivery and outbox dispatch keep their own loops: they poll every few seconds and
+// already coordinate instances through row locks.
+func RegisterJobs(s *scheduler.Scheduler, services JobServices) error {
+	type builtinJob struct {
+		name, schedule, description string
+		run                         scheduler.JobFunc
+	}
+	jobs := []builtinJob{}
+
+	if services.Listings != nil {
+		jobs = append(jobs, builtinJob{JobListingExpiry, "0 * * * *", "Expires listings past their expiry date",
+			func() (string, error) {
+				expired, err := services.Listings.ExpireStale()
+				return fmt.Sprintf("%d listings expired", len(expired)), err
+			}})
+	}
+	if services.Featured != nil {
+		jobs = append(jobs, builtinJob{JobFeaturedExpiry, "*/15 * * * *", "Ends featured promotions past their end date",
+			func() (string, error) {
+				ended, err := services.Featured.ExpireFeatured()
+				return fmt.Sprintf("%d featured promotions ended", len(ended)), err
+			}})
+	}
+	if services.ImageGC != nil {
+		jobs = append(jobs, builtinJob{JobImageGC, "30 3 * * *", "Removes orphaned image files and records",
+			func() (string, error) {
+				report, err := services.ImageGC.Run(services.ImageGC.config.DryRun)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d orphan files (%d deleted), %d records without files (%d deleted)",
+					report.OrphanFiles, report.OrphanFilesDeleted, report.MissingFiles, report.RecordsDeleted), nil
+			}})
+	}
+	if services.Images != nil {
+		jobs = append(jobs, builtinJob{JobUploadSessionExpiry, "*/10 * * * *", "Expires direct upload sessions never confirmed",
+			func() (string, error) {
+				expired, err := services.Images.ExpireUploadSessions()
+				return fmt.Sprintf("%d upload sessions expired", expired), err
+			}})
+	}
+	if services.Drafts != nil && services.Retention == nil {
+		jobs = append(jobs, builtinJob{JobDraftExpiry, "15 4 * * *", "Deletes listing drafts not saved for 30 days",
+			func() (string, error) {
+				deleted, err := services.Drafts.ExpireStale()
+				return fmt.Sprintf("%d drafts deleted", deleted), err
+			}})
+	}
+	if services.PII != nil {
+		jobs = append(jobs, builtinJob{JobPIIReEncryption, "45 2 * * *", "Re-encrypts PII columns with the active key",
+			func() (string, error) {
+				rewritten, err := services.PII.ReEncrypt()
+				return fmt.Sprintf("%d values re-encrypted", rewritten), err
+			}})
+	}
+	if services.Sessions != nil {
+		jobs = append(jobs, builtinJob{JobSessionCleanup, "20 4 * * *", "Deletes sessions expired or revoked for 30 days",
+			func() (string, error) {
+				deleted, err := services.Sessions.DeleteInactive()
+				return fmt.Sprintf("%d sessions deleted", deleted), err
+			}})
+	}
+	if services.Reservations != nil {
+		jobs = append(jobs, builtinJob{JobReservationExpiry, "*/15 * * * *", "Reminds agents of expiring reservations and releases expired ones",
+			func() (string, error) {
+				report, err := services.Reservations.ProcessExpiring()
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d reservations expired, %d agents reminded", report.Expired, report.Reminded), nil
+			}})
+	}
+	if services.Inventory != nil {
+		jobs = append(jobs, builtinJob{JobInventorySnapshots, "10 1 * * *", "Aggregates weekly and monthly agency inventory snapshots",
+			func() (string, error) {
+				stored, err := services.Inventory.ComputeSnapshots()
+				return fmt.Sprintf("%d inventory snapshots computed", stored), err
+			}})
+	}
+	if services.SectorGuides != nil {
+		jobs = append(jobs, builtinJob{JobSectorGuideStats, "40 1 * * *", "Refreshes the average prices of sector guides",
+			func() (string, error) {
+				refreshed, err := services.SectorGuides.RefreshStats()
+				return fmt.Sprintf("%d sector guides refreshed", refreshed), err
+			}})
+	}
+	if services.RentLedger != nil {
+		jobs = append(jobs, builtinJob{JobRentOverdue, "0 7 * * *", "Marks unpaid rent overdue and notifies tenants and landlords",
+			func() (string, error) {
+				overdue, err := services.RentLedger.ProcessOverdue()
+				return fmt.Sprintf("%d rent charges overdue", overdue), err
+			}})
+	}
+	if services.Invoices != nil {
+		jobs = append(jobs, builtinJob{JobInvoiceAuthorization, "*/5 * * * *", "Submits electronic invoices to the SRI and follows their authorization",
+			func() (string, error) {
+				authorized, err := services.Invoices.ProcessPending()
+				return fmt.Sprintf("%d invoices authorized", authorized), err
+			}})
+	}
+	if services.Retention != nil {
+		jobs = append(jobs, builtinJob{JobRetentionPruning, "50 3 * * *", "Deletes logs, audit entries, events and drafts past their retention",
+			func() (string, error) {
+				report, err := services.Retention.Run(services.Retention.config.DryRun)
+				if report == nil {
+					return "", err
+				}
+				if report.DryRun {
+					return fmt.Sprintf("%d expired records (dry run)", report.Expired), err
+				}
+				return fmt.Sprintf("%d of %d expired records deleted", report.Deleted, report.Expired), err
+			}})
+	}
+	if services.ImageAnalysis != nil {
+		jobs = append(jobs, builtinJob{JobImageAnalysis, "*/5 * * * *", "Analyzes queued listing photos to suggest amenities and main images",
+			func() (string, error) {
+				analyzed, err := services.ImageAnalysis.ProcessPending()
+				return fmt.Sprintf("%d photos analyzed", analyzed), err
+			}})
+	}
+	if services.CalendarSync != nil {
+		jobs = append(jobs, builtinJob{JobCalendarSync, "*/10 * * * *", "Syncs appointments and busy times with agents' Google Calendars",
+			func() (string, error) {
+				synced, err := services.CalendarSync.SyncAll()
+				return fmt.Sprintf("%d calendars synced", synced), err
+			}})
+	}
+	if services.LeadSLA != nil {
+		jobs = append(jobs, builtinJob{JobLeadSLA, "*/5 * * * *", "Reassigns leads left unanswered past their agency's response policy",
+			func() (string, error) {
+				reassigned, err := services.LeadSLA.ReassignOverdue()
+				return fmt.Sprintf("%d leads reassigned", reassigned), err
+			}})
+	}
+	for _, job := range jobs {
+		if err := s.Register(job.name, job.schedule, job.description, job.run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
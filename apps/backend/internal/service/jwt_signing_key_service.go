@@ -0,0 +1,254 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/auth"
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// JWTSigningKeyConfig configures asymmetric JWT signing
+type JWTSigningKeyConfig struct {
+	Algorithm       string        // HS256 keeps signing with the shared secret; EdDSA or RS256 sign with stored key pairs
+	GracePeriod     time.Duration // how long a retired key keeps verifying the tokens it signed
+	EncryptionKey   string        // seals private keys at rest
+	RefreshInterval time.Duration // how often keys rotated by other instances are loaded
+}
+
+// JWTSigningKeyService versions the key pairs JWTManager signs tokens with, rotates
+// them on demand and publishes their public keys, so other services can verify tokens
+// without the HMAC secret. Keys live in the database so every API instance signs with
+// the same current key.
+type JWTSigningKeyService struct {
+	repo       repository.JWTSigningKeyRepository
+	jwtManager *auth.JWTManager
+	config     JWTSigningKeyConfig
+	logger     *log.Logger
+	now        func() time.Time
+
+	rotating sync.Mutex
+	mu       sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewJWTSigningKeyService creates a new JWT signing key service
+func NewJWTSigningKeyService(
+	repo repository.JWTSigningKeyRepository,
+	jwtManager *auth.JWTManager,
+	config JWTSigningKeyConfig,
+	logger *log.Logger,
+) *JWTSigningKeyService {
+	if config.GracePeriod <= 0 {
+		config.GracePeriod = 7 * 24 * time.Hour
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Minute
+	}
+
+	return &JWTSigningKeyService{
+		repo:       repo,
+		jwtManager: jwtManager,
+		config:     config,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Enabled verifies if tokens are signed with stored key pairs
+func (s *JWTSigningKeyService) Enabled() bool {
+	return auth.IsAsymmetricAlgorithm(s.config.Algorithm)
+}
+
+// Load deletes expired keys and hands the valid ones to the JWT manager, creating the
+// first key when asymmetric signing is enabled and none exists. While signing with
+// HS256, stored keys only verify the tokens they signed until they expire.
+func (s *JWTSigningKeyService) Load() error {
+	now := s.now()
+	if deleted, err := s.repo.DeleteExpired(now); err != nil {
+		s.logger.Printf("Error deleting expired JWT signing keys: %v", err)
+	} else if deleted > 0 {
+		s.logger.Printf("Deleted %d expired JWT signing keys", deleted)
+	}
+
+	records, err := s.repo.ListValid(now)
+	if err != nil {
+		return err
+	}
+
+	if s.Enabled() && !hasCurrentKey(records) {
+		_, err := s.rotate()
+		return err
+	}
+
+	keys := make([]*auth.SigningKey, 0, len(records))
+	for i := range records {
+		key, err := s.open(&records[i])
+		if err != nil {
+			return err
+		}
+		if !s.Enabled() && key.RetiredAt == nil {
+			key.RetiredAt = &now
+		}
+		keys = append(keys, key)
+	}
+	return s.jwtManager.SetSigningKeys(keys)
+}
+
+// Rotate creates a new current key. The previous key stops signing but keeps verifying
+// tokens for the grace period. Only administrators can rotate keys.
+func (s *JWTSigningKeyService) Rotate(actor domain.Actor) (*domain.JWTSigningKey, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can rotate signing keys")
+	}
+	if !s.Enabled() {
+		return nil, fmt.Errorf("invalid operation: tokens are signed with the %s shared secret, rotate it in the secrets manager", auth.AlgorithmHS256)
+	}
+
+	key, err := s.rotate()
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Printf("JWT signing key rotated to %s by %s", key.ID, actor.UserID)
+	return key, nil
+}
+
+// ListKeys lists the keys that verify tokens, newest first. Only administrators can
+// list keys; private keys are never returned.
+func (s *JWTSigningKeyService) ListKeys(actor domain.Actor) ([]domain.JWTSigningKey, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can list signing keys")
+	}
+	return s.repo.ListValid(s.now())
+}
+
+// JWKS returns the public keys that verify tokens
+func (s *JWTSigningKeyService) JWKS() auth.JWKSet {
+	return s.jwtManager.JWKS()
+}
+
+// Start reloads the keys on the refresh interval until Stop is called, picking up
+// rotations done by other instances
+func (s *JWTSigningKeyService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Load(); err != nil {
+					s.logger.Printf("Error loading JWT signing keys: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops reloading the keys
+func (s *JWTSigningKeyService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// rotate generates and stores a new current key, then reloads the keys
+func (s *JWTSigningKeyService) rotate() (*domain.JWTSigningKey, error) {
+	s.rotating.Lock()
+	defer s.rotating.Unlock()
+
+	now := s.now()
+	key, err := auth.GenerateSigningKey(s.config.Algorithm, now)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := auth.SealPrivateKey(key.PrivateKey, s.config.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal signing key: %w", err)
+	}
+	publicKey, err := key.PublicKeyPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &domain.JWTSigningKey{
+		ID:               key.ID,
+		Algorithm:        key.Algorithm,
+		PublicKey:        publicKey,
+		SealedPrivateKey: sealed,
+		CreatedAt:        now,
+	}
+	if err := s.repo.Rotate(record, now, now.Add(s.config.GracePeriod)); err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.ListValid(now)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*auth.SigningKey, 0, len(records))
+	for i := range records {
+		if records[i].ID == key.ID {
+			keys = append(keys, key)
+			continue
+		}
+		opened, err := s.open(&records[i])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, opened)
+	}
+	if err := s.jwtManager.SetSigningKeys(keys); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// open decrypts a stored key
+func (s *JWTSigningKeyService) open(record *domain.JWTSigningKey) (*auth.SigningKey, error) {
+	privateKey, err := auth.OpenPrivateKey(record.SealedPrivateKey, s.config.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signing key %s: %w", record.ID, err)
+	}
+	return &auth.SigningKey{
+		ID:         record.ID,
+		Algorithm:  record.Algorithm,
+		PrivateKey: privateKey,
+		CreatedAt:  record.CreatedAt,
+		RetiredAt:  record.RetiredAt,
+		ExpiresAt:  record.ExpiresAt,
+	}, nil
+}
+
+// hasCurrentKey verifies if a stored key signs new tokens
+func hasCurrentKey(records []domain.JWTSigningKey) bool {
+	for i := range records {
+		if records[i].IsCurrent() {
+			return true
+		}
+	}
+	return false
+}
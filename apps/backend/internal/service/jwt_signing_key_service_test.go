@@ -0,0 +1,141 @@
+package service
+
+import (
+	"io"
+	"log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/auth"
+	"realty-core/internal/domain"
+)
+
+// memoryJWTSigningKeyRepository keeps signing keys in memory
+type memoryJWTSigningKeyRepository struct {
+	keys []domain.JWTSigningKey
+}
+
+func (r *memoryJWTSigningKeyRepository) ListValid(now time.Time) ([]domain.JWTSigningKey, error) {
+	valid := []domain.JWTSigningKey{}
+	for _, key := range r.keys {
+		if key.ExpiresAt == nil || key.ExpiresAt.After(now) {
+			valid = append(valid, key)
+		}
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].CreatedAt.After(valid[j].CreatedAt) })
+	return valid, nil
+}
+
+func (r *memoryJWTSigningKeyRepository) Rotate(key *domain.JWTSigningKey, retiredAt, expiresAt time.Time) error {
+	for i := range r.keys {
+		if r.keys[i].RetiredAt == nil {
+			r.keys[i].RetiredAt, r.keys[i].ExpiresAt = &retiredAt, &expiresAt
+		}
+	}
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *memoryJWTSigningKeyRepository) DeleteExpired(before time.Time) (int64, error) {
+	kept := r.keys[:0]
+	for _, key := range r.keys {
+		if key.ExpiresAt == nil || key.ExpiresAt.After(before) {
+			kept = append(kept, key)
+		}
+	}
+	deleted := int64(len(r.keys) - len(kept))
+	r.keys = kept
+	return deleted, nil
+}
+
+func newTestJWTSigningKeyService(repo *memoryJWTSigningKeyRepository, manager *auth.JWTManager, algorithm string) *JWTSigningKeyService {
+	return NewJWTSigningKeyService(repo, manager, JWTSigningKeyConfig{
+		Algorithm:     algorithm,
+		GracePeriod:   24 * time.Hour,
+		EncryptionKey: "0123456789abcdef0123456789abcdef",
+	}, log.New(io.Discard, "", 0))
+}
+
+func TestJWTSigningKeyService_LoadCreatesFirstKey(t *testing.T) {
+	repo := &memoryJWTSigningKeyRepository{}
+	manager := auth.NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	service := newTestJWTSigningKeyService(repo, manager, auth.AlgorithmEdDSA)
+
+	require.NoError(t, service.Load())
+	require.Len(t, repo.keys, 1)
+	assert.Equal(t, repo.keys[0].ID, manager.SigningKeyID())
+	assert.Contains(t, repo.keys[0].PublicKey, "BEGIN PUBLIC KEY")
+	assert.NotContains(t, repo.keys[0].SealedPrivateKey, "PRIVATE KEY", "private keys are sealed")
+
+	// Another instance loads the same key
+	other := auth.NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	require.NoError(t, newTestJWTSigningKeyService(repo, other, auth.AlgorithmEdDSA).Load())
+	assert.Equal(t, repo.keys[0].ID, other.SigningKeyID())
+
+	pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+	_, err = other.ValidateAccessToken(pair.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTSigningKeyService_Rotate(t *testing.T) {
+	repo := &memoryJWTSigningKeyRepository{}
+	manager := auth.NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	service := newTestJWTSigningKeyService(repo, manager, auth.AlgorithmEdDSA)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+	require.NoError(t, service.Load())
+	oldKeyID := manager.SigningKeyID()
+
+	oldPair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+
+	_, err = service.Rotate(domain.NewActor("agent-1", string(domain.RoleAgent), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	now = now.Add(time.Second)
+	key, err := service.Rotate(domain.NewActor("admin-1", string(domain.RoleAdmin), ""))
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKeyID, key.ID)
+	assert.Equal(t, key.ID, manager.SigningKeyID())
+
+	_, err = manager.ValidateAccessToken(oldPair.AccessToken)
+	assert.NoError(t, err, "the retired key verifies during its grace period")
+	assert.Len(t, service.JWKS().Keys, 2)
+
+	keys, err := service.ListKeys(domain.NewActor("admin-1", string(domain.RoleAdmin), ""))
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.True(t, keys[0].IsCurrent())
+	assert.Equal(t, now.Add(24*time.Hour), *keys[1].ExpiresAt)
+
+	// After the grace period the retired key is deleted
+	now = now.Add(25 * time.Hour)
+	require.NoError(t, service.Load())
+	assert.Len(t, repo.keys, 1)
+	assert.Len(t, service.JWKS().Keys, 1)
+}
+
+func TestJWTSigningKeyService_HS256(t *testing.T) {
+	repo := &memoryJWTSigningKeyRepository{}
+	manager := auth.NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	require.NoError(t, newTestJWTSigningKeyService(repo, manager, auth.AlgorithmEdDSA).Load())
+	edPair, err := manager.GenerateTokenPair("user-1", "user@example.com", "agent", "", "")
+	require.NoError(t, err)
+
+	// Switching back to HS256 keeps verifying tokens signed with stored keys
+	hmacManager := auth.NewJWTManager("hmac-secret", time.Minute, time.Hour, "test")
+	service := newTestJWTSigningKeyService(repo, hmacManager, auth.AlgorithmHS256)
+	require.NoError(t, service.Load())
+	assert.Equal(t, auth.DefaultKeyID, hmacManager.SigningKeyID())
+
+	_, err = hmacManager.ValidateAccessToken(edPair.AccessToken)
+	assert.NoError(t, err)
+
+	_, err = service.Rotate(domain.NewActor("admin-1", string(domain.RoleAdmin), ""))
+	assert.ErrorContains(t, err, "invalid operation")
+}
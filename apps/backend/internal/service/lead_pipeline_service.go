@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// LeadCallLog lists the masked calls of leads, e.g. CallMaskingService
+type LeadCallLog interface {
+	ListLeadCalls(leadID string) ([]domain.LeadCall, error)
+}
+
+// LeadPipelineService manages the CRM pipeline of agencies: their stages, moving
+// routed leads between stages, the kanban board of leads and how long leads stay in
+// each stage. Agents work the leads routed to them; the agency works all of them.
+type LeadPipelineService struct {
+	repo   repository.LeadPipelineRepository
+	leads  repository.LeadRoutingRepository
+	calls  LeadCallLog
+	sla    LeadResponseTracker
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewLeadPipelineService creates a new lead pipeline service
+func NewLeadPipelineService(repo repository.LeadPipelineRepository, leads repository.LeadRoutingRepository, logger *log.Logger) *LeadPipelineService {
+	return &LeadPipelineService{
+		repo:   repo,
+		leads:  leads,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// SetCallLog adds the masked calls of leads to their timeline
+func (s *LeadPipelineService) SetCallLog(calls LeadCallLog) {
+	s.calls = calls
+}
+
+// SetResponseTracker counts moving a lead in the pipeline as answering it
+func (s *LeadPipelineService) SetResponseTracker(sla LeadResponseTracker) {
+	s.sla = sla
+}
+
+// GetPipeline retrieves the pipeline of an agency for its members
+func (s *LeadPipelineService) GetPipeline(agencyID string, actor domain.Actor) (*domain.LeadPipeline, error) {
+	if !actor.CanAdministerAgency(agencyID) && actor.AgencyID != agencyID {
+		return nil, fmt.Errorf("permission denied: only members of the agency can see its pipeline")
+	}
+	return s.repo.GetPipeline(agencyID)
+}
+
+// UpdatePipeline replaces the stages of an agency's pipeline. Stages that still have
+// leads cannot be removed.
+func (s *LeadPipelineService) UpdatePipeline(agencyID string, stages []domain.PipelineStage, actor domain.Actor) (*domain.LeadPipeline, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can change its pipeline")
+	}
+
+	pipeline, err := domain.NewLeadPipeline(agencyID, stages, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %w", err)
+	}
+
+	current, err := s.repo.GetPipeline(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.leads.CountByStage(agencyID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, stage := range current.Stages {
+		if _, kept := pipeline.Stage(stage.Key); !kept && counts[stage.Key] > 0 {
+			return nil, fmt.Errorf("invalid pipeline: %d leads are in stage %s; move them before removing it", counts[stage.Key], stage.Key)
+		}
+	}
+
+	if err := s.repo.SavePipeline(pipeline); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Lead pipeline of agency %s updated by %s (%d stages)", agencyID, actor.UserID, len(pipeline.Stages))
+	return pipeline, nil
+}
+
+// MoveLead moves a lead to a stage of its agency's pipeline, recording when it entered
+// the stage and how long it spent in the previous one
+func (s *LeadPipelineService) MoveLead(leadID, stage string, actor domain.Actor) (*domain.LeadAssignment, *domain.LeadStageTransition, error) {
+	lead, err := s.workableLead(leadID, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pipeline, err := s.repo.GetPipeline(lead.AgencyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	transition, err := lead.MoveToStage(pipeline, stage, actor.UserID, s.now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid stage change: %w", err)
+	}
+	if err := s.repo.MoveStage(lead, transition); err != nil {
+		return nil, nil, err
+	}
+	if s.sla != nil {
+		if err := s.sla.RecordLeadResponse(lead, domain.LeadResponseStageChange, actor.UserID); err != nil {
+			s.logger.Printf("Error recording response to lead %s: %v", lead.ID, err)
+		}
+	}
+
+	return lead, transition, nil
+}
+
+// GetLeadHistory lists the stage transitions of a lead, oldest first
+func (s *LeadPipelineService) GetLeadHistory(leadID string, actor domain.Actor) ([]domain.LeadStageTransition, error) {
+	if _, err := s.workableLead(leadID, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.ListLeadTransitions(leadID)
+}
+
+// GetLeadTimeline lists what happened to a lead, oldest first: its routing, stage
+// changes and the calls between its buyer and agent
+func (s *LeadPipelineService) GetLeadTimeline(leadID string, actor domain.Actor) ([]domain.LeadTimelineEntry, error) {
+	lead, err := s.workableLead(leadID, actor)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := s.repo.ListLeadTransitions(leadID)
+	if err != nil {
+		return nil, err
+	}
+	var calls []domain.LeadCall
+	if s.calls != nil {
+		if calls, err = s.calls.ListLeadCalls(leadID); err != nil {
+			return nil, err
+		}
+	}
+	return domain.BuildLeadTimeline(lead, transitions, calls), nil
+}
+
+// GetBoard groups the most recent leads of an agency by pipeline stage for a kanban
+// board. Agents only see the leads routed to them.
+func (s *LeadPipelineService) GetBoard(agencyID, agentID string, actor domain.Actor) (*domain.LeadBoard, error) {
+	agentID, err := s.boardAgent(agencyID, agentID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := s.repo.GetPipeline(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	leads, _, err := s.leads.ListAssignments(domain.LeadAssignmentFilter{
+		AgencyID: agencyID,
+		AgentID:  agentID,
+		Limit:    domain.MaxLeadBoardLeads,
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.leads.CountByStage(agencyID, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildLeadBoard(pipeline, leads, counts), nil
+}
+
+// GetStageAnalytics reports how long leads stayed in each stage between from and to,
+// the last DefaultStageAnalyticsDays days by default. Agents only see their own leads.
+func (s *LeadPipelineService) GetStageAnalytics(agencyID, agentID string, from, to *time.Time, actor domain.Actor) (*domain.LeadStageAnalytics, error) {
+	agentID, err := s.boardAgent(agencyID, agentID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	end := s.now()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -domain.DefaultStageAnalyticsDays)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("invalid period: from must be before to")
+	}
+
+	pipeline, err := s.repo.GetPipeline(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := s.repo.ListAgencyTransitions(agencyID, agentID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.leads.CountByStage(agencyID, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LeadStageAnalytics{
+		AgencyID: agencyID,
+		AgentID:  agentID,
+		From:     start,
+		To:       end,
+		Stages:   domain.StageDurationAnalytics(pipeline, transitions, counts),
+	}, nil
+}
+
+// workableLead retrieves a lead the actor works: the agent it was routed to or its agency
+func (s *LeadPipelineService) workableLead(leadID string, actor domain.Actor) (*domain.LeadAssignment, error) {
+	lead, err := s.leads.GetAssignment(leadID)
+	if err != nil {
+		return nil, err
+	}
+	assigned := lead.AgentID != nil && *lead.AgentID == actor.UserID
+	if !assigned && !actor.CanAdministerAgency(lead.AgencyID) {
+		return nil, fmt.Errorf("permission denied: only the lead's agent or agency can work it")
+	}
+	return lead, nil
+}
+
+// boardAgent returns the agent whose leads the actor may see on an agency's board:
+// agents see their own, the agency any agent's or all of them
+func (s *LeadPipelineService) boardAgent(agencyID, agentID string, actor domain.Actor) (string, error) {
+	if actor.CanAdministerAgency(agencyID) {
+		return agentID, nil
+	}
+	if actor.Role == domain.RoleAgent && actor.AgencyID == agencyID {
+		return actor.UserID, nil
+	}
+	return "", fmt.Errorf("permission denied: only members of the agency can see its leads")
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryLeadPipelines is an in-memory LeadPipelineRepository over memoryLeadRouting leads
+type memoryLeadPipelines struct {
+	pipelines   map[string]*domain.LeadPipeline
+	leads       *memoryLeadRouting
+	transitions []domain.LeadStageTransition
+}
+
+func (r *memoryLeadPipelines) GetPipeline(agencyID string) (*domain.LeadPipeline, error) {
+	if pipeline, ok := r.pipelines[agencyID]; ok {
+		return pipeline, nil
+	}
+	return domain.DefaultLeadPipeline(agencyID), nil
+}
+
+func (r *memoryLeadPipelines) SavePipeline(pipeline *domain.LeadPipeline) error {
+	r.pipelines[pipeline.AgencyID] = pipeline
+	return nil
+}
+
+func (r *memoryLeadPipelines) MoveStage(lead *domain.LeadAssignment, transition *domain.LeadStageTransition) error {
+	for i := range r.leads.assignments {
+		if r.leads.assignments[i].ID == lead.ID {
+			if r.leads.assignments[i].Stage != transition.FromStage {
+				return fmt.Errorf("lead %s was moved meanwhile", lead.ID)
+			}
+			r.leads.assignments[i] = *lead
+		}
+	}
+	r.transitions = append(r.transitions, *transition)
+	return nil
+}
+
+func (r *memoryLeadPipelines) ListLeadTransitions(leadID string) ([]domain.LeadStageTransition, error) {
+	transitions := []domain.LeadStageTransition{}
+	for _, transition := range r.transitions {
+		if transition.LeadID == leadID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
+
+func (r *memoryLeadPipelines) ListAgencyTransitions(agencyID, agentID string, from, to time.Time) ([]domain.LeadStageTransition, error) {
+	return r.transitions, nil
+}
+
+func TestLeadPipelineService_MoveLeadAndBoard(t *testing.T) {
+	start := time.Date(2025, 9, 22, 9, 0, 0, 0, time.UTC)
+	leads := newMemoryLeadRouting()
+	for i, agentID := range []string{"agent-a", "agent-a", "agent-b"} {
+		lead := domain.NewLeadAssignment(fmt.Sprintf("app-%d", i), "prop-1", "agency-1", nil, agentID, domain.LeadScore{}, start)
+		lead.ID = fmt.Sprintf("lead-%d", i)
+		require.NoError(t, leads.CreateAssignment(lead))
+	}
+	pipelines := &memoryLeadPipelines{pipelines: map[string]*domain.LeadPipeline{}, leads: leads}
+	service := NewLeadPipelineService(pipelines, leads, log.New(os.Stderr, "", 0))
+	now := start.Add(30 * time.Hour)
+	service.now = func() time.Time { return now }
+
+	agentA := domain.NewActor("agent-a", string(domain.RoleAgent), "agency-1")
+	agentB := domain.NewActor("agent-b", string(domain.RoleAgent), "agency-1")
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+
+	_, _, err := service.MoveLead("lead-0", domain.LeadStageContacted, agentB)
+	assert.ErrorContains(t, err, "permission denied")
+	_, _, err = service.MoveLead("lead-0", "closing", agentA)
+	assert.ErrorContains(t, err, "invalid stage change")
+
+	lead, transition, err := service.MoveLead("lead-0", domain.LeadStageContacted, agentA)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LeadStageContacted, lead.Stage)
+	assert.Equal(t, now, lead.StageEnteredAt)
+	assert.Equal(t, int64(30*3600), transition.DurationSeconds)
+
+	history, err := service.GetLeadHistory("lead-0", agency)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	// Agents only see their own leads, whatever agent they ask for
+	board, err := service.GetBoard("agency-1", "agent-b", agentA)
+	require.NoError(t, err)
+	assert.Len(t, board.Columns[0].Leads, 1)
+	assert.Equal(t, 1, board.Columns[1].Total)
+
+	board, err = service.GetBoard("agency-1", "", agency)
+	require.NoError(t, err)
+	assert.Equal(t, 2, board.Columns[0].Total)
+
+	_, err = service.GetBoard("agency-1", "", domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	analytics, err := service.GetStageAnalytics("agency-1", "", nil, nil, agency)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -domain.DefaultStageAnalyticsDays), analytics.From)
+	assert.Equal(t, 30.0, analytics.Stages[0].AverageHours)
+	assert.Equal(t, 2, analytics.Stages[0].Current)
+}
+
+func TestLeadPipelineService_UpdatePipeline(t *testing.T) {
+	leads := newMemoryLeadRouting()
+	lead := domain.NewLeadAssignment("app-1", "prop-1", "agency-1", nil, "agent-a", domain.LeadScore{}, time.Now())
+	lead.Stage = domain.LeadStageNegotiating
+	require.NoError(t, leads.CreateAssignment(lead))
+	pipelines := &memoryLeadPipelines{pipelines: map[string]*domain.LeadPipeline{}, leads: leads}
+	service := NewLeadPipelineService(pipelines, leads, log.New(os.Stderr, "", 0))
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+
+	_, err := service.UpdatePipeline("agency-1", []domain.PipelineStage{{Name: "Nuevo"}, {Name: "Cerrado", Kind: domain.PipelineStageWon}},
+		domain.NewActor("agent-a", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.UpdatePipeline("agency-1", []domain.PipelineStage{{Key: "new", Name: "Nuevo"}, {Name: "Cerrado", Kind: domain.PipelineStageWon}}, agency)
+	assert.ErrorContains(t, err, "1 leads are in stage negotiating")
+
+	pipeline, err := service.UpdatePipeline("agency-1", []domain.PipelineStage{
+		{Key: "new", Name: "Nuevo"},
+		{Key: "negotiating", Name: "Negociando"},
+		{Name: "Cerrado", Kind: domain.PipelineStageWon},
+	}, agency)
+	require.NoError(t, err)
+	assert.Equal(t, "cerrado", pipeline.Stages[2].Key)
+
+	saved, err := service.GetPipeline("agency-1", domain.NewActor("agent-a", string(domain.RoleAgent), "agency-1"))
+	require.NoError(t, err)
+	assert.True(t, saved.Custom)
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// LeadRoutingUsers is the part of the user repository lead routing uses
+type LeadRoutingUsers interface {
+	GetByID(id string) (*domain.User, error)
+	GetByAgency(agencyID string) ([]*domain.User, error)
+}
+
+// LeadLocales tells the language of an inquirer, e.g. UserPreferencesService
+type LeadLocales interface {
+	PreferredLocale(userID string) string
+}
+
+// LeadPipelines retrieves the CRM pipeline of agencies, e.g. repository.LeadPipelineRepository
+type LeadPipelines interface {
+	GetPipeline(agencyID string) (*domain.LeadPipeline, error)
+}
+
+// LeadRoutingService scores inquiries on agency listings and routes them to the
+// agency's agents by the rules the agency configures. Inquiries no rule takes stay
+// with the listing's agent.
+type LeadRoutingService struct {
+	repo      repository.LeadRoutingRepository
+	users     LeadRoutingUsers
+	locales   LeadLocales
+	pipelines LeadPipelines
+	now       func() time.Time
+	logger    *log.Logger
+}
+
+// NewLeadRoutingService creates a new lead routing service
+func NewLeadRoutingService(repo repository.LeadRoutingRepository, users LeadRoutingUsers, logger *log.Logger) *LeadRoutingService {
+	return &LeadRoutingService{
+		repo:   repo,
+		users:  users,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// SetLocales matches language rules on the locale inquirers saved in their
+// preferences. Without it every inquirer counts as writing in the default locale.
+func (s *LeadRoutingService) SetLocales(locales LeadLocales) {
+	s.locales = locales
+}
+
+// SetPipelines places routed leads in the first stage of their agency's pipeline.
+// Without it leads start in the new stage of the default pipeline.
+func (s *LeadRoutingService) SetPipelines(pipelines LeadPipelines) {
+	s.pipelines = pipelines
+}
+
+// ListRules lists the routing rules of an agency by priority
+func (s *LeadRoutingService) ListRules(agencyID string, actor domain.Actor) ([]domain.LeadRoutingRule, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can manage lead routing")
+	}
+	return s.repo.ListRules(agencyID)
+}
+
+// CreateRule adds a routing rule to an agency
+func (s *LeadRoutingService) CreateRule(agencyID string, req domain.LeadRoutingRuleRequest, actor domain.Actor) (*domain.LeadRoutingRule, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can manage lead routing")
+	}
+
+	rules, err := s.repo.ListRules(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) >= domain.MaxLeadRoutingRulesPerAgency {
+		return nil, fmt.Errorf("invalid routing rule: at most %d rules per agency are allowed", domain.MaxLeadRoutingRulesPerAgency)
+	}
+
+	rule, err := domain.NewLeadRoutingRule(agencyID, req, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing rule: %w", err)
+	}
+	if err := s.checkAgents(agencyID, rule.AgentIDs); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Lead routing rule %s (%s) created for agency %s by %s", rule.ID, rule.Strategy, agencyID, actor.UserID)
+	return rule, nil
+}
+
+// UpdateRule replaces the settings of a routing rule of an agency
+func (s *LeadRoutingService) UpdateRule(agencyID, ruleID string, req domain.LeadRoutingRuleRequest, actor domain.Actor) (*domain.LeadRoutingRule, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can manage lead routing")
+	}
+
+	rule, err := s.repo.GetRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.AgencyID != agencyID {
+		return nil, fmt.Errorf("routing rule not found: %s", ruleID)
+	}
+	if err := rule.Apply(req, s.now()); err != nil {
+		return nil, fmt.Errorf("invalid routing rule: %w", err)
+	}
+	if err := s.checkAgents(agencyID, rule.AgentIDs); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a routing rule of an agency. Past routing outcomes keep their
+// agent.
+func (s *LeadRoutingService) DeleteRule(agencyID, ruleID string, actor domain.Actor) error {
+	if !actor.CanAdministerAgency(agencyID) {
+		return fmt.Errorf("permission denied: only the agency can manage lead routing")
+	}
+	return s.repo.DeleteRule(agencyID, ruleID)
+}
+
+// ListAssignments lists the routing outcomes of an agency's leads, newest first,
+// optionally of one agent or outcome
+func (s *LeadRoutingService) ListAssignments(filter domain.LeadAssignmentFilter, actor domain.Actor) ([]domain.LeadAssignment, int, error) {
+	if !actor.CanAdministerAgency(filter.AgencyID) {
+		return nil, 0, fmt.Errorf("permission denied: only the agency can see lead routing outcomes")
+	}
+	filter.Limit, filter.Offset = reviewPage(filter.Limit, filter.Offset)
+	return s.repo.ListAssignments(filter)
+}
+
+// ListMyLeads lists the leads routed to the actor, newest first
+func (s *LeadRoutingService) ListMyLeads(limit, offset int, actor domain.Actor) ([]domain.LeadAssignment, int, error) {
+	if actor.Role != domain.RoleAgent {
+		return nil, 0, fmt.Errorf("permission denied: only agents receive leads")
+	}
+	limit, offset = reviewPage(limit, offset)
+	return s.repo.ListAssignments(domain.LeadAssignmentFilter{AgentID: actor.UserID, Limit: limit, Offset: offset})
+}
+
+// RouteLead scores an inquiry on an agency listing and assigns it to an agent by the
+// agency's rules: the first matching rule with an active agent takes it, rotating its
+// leads among its agents. Listings outside agencies are not routed.
+func (s *LeadRoutingService) RouteLead(application *domain.RentalApplication, property *domain.Property) (*domain.LeadAssignment, error) {
+	if property.AgencyID == nil {
+		return nil, nil
+	}
+	agencyID := *property.AgencyID
+
+	phoneVerified := false
+	if applicant, err := s.users.GetByID(application.ApplicantID); err == nil {
+		phoneVerified = applicant.IsPhoneVerified()
+	}
+	score := domain.ScoreLead(domain.LeadSignals{
+		MonthlyIncome: application.MonthlyIncome,
+		MonthlyRent:   monthlyRent(property),
+		PhoneVerified: phoneVerified,
+		Message:       application.Message,
+	})
+
+	lead := domain.LeadContext{Language: domain.DefaultLocale, Score: score.Total}
+	if property.Sector != nil {
+		lead.Sector = *property.Sector
+	}
+	if s.locales != nil {
+		if locale := s.locales.PreferredLocale(application.ApplicantID); locale != "" {
+			lead.Language = locale
+		}
+	}
+
+	rule, agentID, err := s.pickAgent(agencyID, lead)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil && property.AgentID != nil {
+		agentID = *property.AgentID
+	}
+
+	assignment := domain.NewLeadAssignment(application.ID, property.ID, agencyID, rule, agentID, score, s.now())
+	if s.pipelines != nil {
+		pipeline, err := s.pipelines.GetPipeline(agencyID)
+		if err != nil {
+			return nil, err
+		}
+		assignment.Stage = pipeline.EntryStage()
+	}
+	if err := s.repo.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Lead %s (score %d, %s) routed to %q: %s", application.ID, score.Total, score.Grade, agentID, assignment.Outcome)
+	return assignment, nil
+}
+
+// pickAgent returns the first rule matching a lead that has an active agent, and the
+// agent whose turn it is. A nil rule means no rule took the lead.
+func (s *LeadRoutingService) pickAgent(agencyID string, lead domain.LeadContext) (*domain.LeadRoutingRule, string, error) {
+	rules, err := s.repo.ListRules(agencyID)
+	if err != nil {
+		return nil, "", err
+	}
+	domain.SortLeadRoutingRules(rules)
+
+	var agents map[string]bool
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Matches(lead) {
+			continue
+		}
+		if agents == nil {
+			if agents, err = s.agencyAgents(agencyID); err != nil {
+				return nil, "", err
+			}
+		}
+
+		candidates := []string{}
+		if len(rule.AgentIDs) == 0 {
+			for id := range agents {
+				candidates = append(candidates, id)
+			}
+			sort.Strings(candidates)
+		}
+		for _, id := range rule.AgentIDs {
+			if agents[id] {
+				candidates = append(candidates, id)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		cursor, err := s.repo.NextAgent(rule.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		return rule, candidates[cursor%len(candidates)], nil
+	}
+	return nil, "", nil
+}
+
+// agencyAgents returns the IDs of the active agents of an agency
+func (s *LeadRoutingService) agencyAgents(agencyID string) (map[string]bool, error) {
+	users, err := s.users.GetByAgency(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	agents := map[string]bool{}
+	for _, user := range users {
+		if user.IsAgent() && user.Active {
+			agents[user.ID] = true
+		}
+	}
+	return agents, nil
+}
+
+// checkAgents verifies every agent of a rule is an agent of the agency
+func (s *LeadRoutingService) checkAgents(agencyID string, agentIDs []string) error {
+	for _, id := range agentIDs {
+		user, err := s.users.GetByID(id)
+		if err != nil {
+			return fmt.Errorf("agent not found: %s", id)
+		}
+		if !user.IsAgent() || user.AgencyID == nil || *user.AgencyID != agencyID {
+			return fmt.Errorf("invalid routing rule: %s is not an agent of the agency", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryLeadRouting is an in-memory LeadRoutingRepository
+type memoryLeadRouting struct {
+	rules       map[string]*domain.LeadRoutingRule
+	cursors     map[string]int
+	assignments []domain.LeadAssignment
+}
+
+func newMemoryLeadRouting() *memoryLeadRouting {
+	return &memoryLeadRouting{rules: map[string]*domain.LeadRoutingRule{}, cursors: map[string]int{}}
+}
+
+func (r *memoryLeadRouting) CreateRule(rule *domain.LeadRoutingRule) error {
+	copied := *rule
+	r.rules[rule.ID] = &copied
+	return nil
+}
+
+func (r *memoryLeadRouting) UpdateRule(rule *domain.LeadRoutingRule) error {
+	return r.CreateRule(rule)
+}
+
+func (r *memoryLeadRouting) DeleteRule(agencyID, id string) error {
+	delete(r.rules, id)
+	return nil
+}
+
+func (r *memoryLeadRouting) GetRule(id string) (*domain.LeadRoutingRule, error) {
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, fmt.Errorf("routing rule not found: %s", id)
+	}
+	copied := *rule
+	return &copied, nil
+}
+
+func (r *memoryLeadRouting) ListRules(agencyID string) ([]domain.LeadRoutingRule, error) {
+	rules := []domain.LeadRoutingRule{}
+	for _, rule := range r.rules {
+		if rule.AgencyID == agencyID {
+			rules = append(rules, *rule)
+		}
+	}
+	domain.SortLeadRoutingRules(rules)
+	return rules, nil
+}
+
+func (r *memoryLeadRouting) NextAgent(ruleID string) (int, error) {
+	cursor := r.cursors[ruleID]
+	r.cursors[ruleID]++
+	return cursor, nil
+}
+
+func (r *memoryLeadRouting) CreateAssignment(assignment *domain.LeadAssignment) error {
+	r.assignments = append(r.assignments, *assignment)
+	return nil
+}
+
+func (r *memoryLeadRouting) GetAssignment(id string) (*domain.LeadAssignment, error) {
+	for i := range r.assignments {
+		if r.assignments[i].ID == id {
+			assignment := r.assignments[i]
+			return &assignment, nil
+		}
+	}
+	return nil, fmt.Errorf("lead not found: %s", id)
+}
+
+func (r *memoryLeadRouting) ListAssignments(filter domain.LeadAssignmentFilter) ([]domain.LeadAssignment, int, error) {
+	assignments := []domain.LeadAssignment{}
+	for _, assignment := range r.assignments {
+		if r.matches(assignment, filter.AgencyID, filter.AgentID) {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, len(assignments), nil
+}
+
+func (r *memoryLeadRouting) CountByStage(agencyID, agentID string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, assignment := range r.assignments {
+		if r.matches(assignment, agencyID, agentID) {
+			counts[assignment.Stage]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryLeadRouting) matches(assignment domain.LeadAssignment, agencyID, agentID string) bool {
+	if agencyID != "" && assignment.AgencyID != agencyID {
+		return false
+	}
+	return agentID == "" || (assignment.AgentID != nil && *assignment.AgentID == agentID)
+}
+
+// memoryLeadUsers serves the users of lead routing tests
+type memoryLeadUsers map[string]*domain.User
+
+func (u memoryLeadUsers) GetByID(id string) (*domain.User, error) {
+	user, ok := u[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+func (u memoryLeadUsers) GetByAgency(agencyID string) ([]*domain.User, error) {
+	users := []*domain.User{}
+	for _, user := range u {
+		if user.AgencyID != nil && *user.AgencyID == agencyID && user.Active {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// fixedLocales answers the preferred locale of inquirers
+type fixedLocales map[string]string
+
+func (l fixedLocales) PreferredLocale(userID string) string {
+	return l[userID]
+}
+
+func newLeadRoutingFixture(t *testing.T) (*LeadRoutingService, *memoryLeadRouting) {
+	agencyID := "agency-1"
+	otherAgency := "agency-2"
+	users := memoryLeadUsers{
+		"agent-a": {ID: "agent-a", Role: domain.RoleAgent, AgencyID: &agencyID, Active: true},
+		"agent-b": {ID: "agent-b", Role: domain.RoleAgent, AgencyID: &agencyID, Active: true},
+		"agent-c": {ID: "agent-c", Role: domain.RoleAgent, AgencyID: &agencyID, Active: true},
+		"agent-x": {ID: "agent-x", Role: domain.RoleAgent, AgencyID: &otherAgency, Active: true},
+		"buyer-1": {ID: "buyer-1", Role: domain.RoleBuyer, Active: true},
+		"buyer-2": {ID: "buyer-2", Role: domain.RoleBuyer, Active: true},
+	}
+	repo := newMemoryLeadRouting()
+	service := NewLeadRoutingService(repo, users, log.New(os.Stderr, "", 0))
+	service.SetLocales(fixedLocales{"buyer-2": "en"})
+	service.now = func() time.Time { return time.Date(2025, 9, 21, 10, 0, 0, 0, time.UTC) }
+	return service, repo
+}
+
+func TestLeadRoutingService_ManageRules(t *testing.T) {
+	service, _ := newLeadRoutingFixture(t)
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+
+	_, err := service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "Everyone", Strategy: domain.LeadRoutingRoundRobin},
+		domain.NewActor("agent-a", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "Poached", Strategy: domain.LeadRoutingRoundRobin,
+		AgentIDs: []string{"agent-x"}}, agency)
+	assert.ErrorContains(t, err, "not an agent of the agency")
+
+	_, err = service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "Valle", Strategy: domain.LeadRoutingSector}, agency)
+	assert.ErrorContains(t, err, "invalid routing rule")
+
+	rule, err := service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "Everyone", Strategy: domain.LeadRoutingRoundRobin}, agency)
+	require.NoError(t, err)
+
+	inactive := false
+	updated, err := service.UpdateRule("agency-1", rule.ID, domain.LeadRoutingRuleRequest{Name: "Everyone", Strategy: domain.LeadRoutingRoundRobin, Active: &inactive}, agency)
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+
+	_, err = service.UpdateRule("agency-2", rule.ID, domain.LeadRoutingRuleRequest{Name: "Everyone", Strategy: domain.LeadRoutingRoundRobin},
+		domain.NewActor("agency-2", string(domain.RoleAgency), "agency-2"))
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestLeadRoutingService_RouteLead(t *testing.T) {
+	service, repo := newLeadRoutingFixture(t)
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+
+	property := domain.NewProperty("Departamento en Cumbayá", "Departamento amueblado", "Pichincha", "Quito", "apartment", 0, "owner-1")
+	property.ID = "prop-1"
+	agencyID, listingAgent, sector, rent := "agency-1", "agent-c", "Cumbayá", 1000.0
+	property.AgencyID = &agencyID
+	property.AgentID = &listingAgent
+	property.Sector = &sector
+	property.RentPrice = &rent
+
+	application := &domain.RentalApplication{ID: "app-1", ApplicantID: "buyer-1", MonthlyIncome: 3500,
+		Message: "Buenas tardes, ¿el departamento sigue disponible para arrendar desde noviembre?"}
+
+	// Without rules the listing's agent keeps the lead
+	assignment, err := service.RouteLead(application, property)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LeadAssignedListingAgent, assignment.Outcome)
+	assert.Equal(t, "agent-c", *assignment.AgentID)
+	assert.Equal(t, domain.LeadScoreBudgetWeight, assignment.Score.BudgetMatch)
+
+	_, err = service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "Everyone", Strategy: domain.LeadRoutingRoundRobin, Priority: 100}, agency)
+	require.NoError(t, err)
+	_, err = service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "English", Strategy: domain.LeadRoutingLanguage,
+		Languages: []string{"en"}, AgentIDs: []string{"agent-b"}}, agency)
+	require.NoError(t, err)
+	_, err = service.CreateRule("agency-1", domain.LeadRoutingRuleRequest{Name: "Valle, hot leads", Strategy: domain.LeadRoutingSector,
+		Sectors: []string{"Cumbaya"}, AgentIDs: []string{"agent-a"}, MinScore: 90, Priority: 1}, agency)
+	require.NoError(t, err)
+
+	routed := []string{}
+	for i := 0; i < 3; i++ {
+		assignment, err := service.RouteLead(application, property)
+		require.NoError(t, err)
+		assert.Equal(t, domain.LeadRoutingRoundRobin, assignment.Outcome, "the lead scores below the sector rule")
+		routed = append(routed, *assignment.AgentID)
+	}
+	assert.Equal(t, []string{"agent-a", "agent-b", "agent-c"}, routed)
+
+	english := &domain.RentalApplication{ID: "app-2", ApplicantID: "buyer-2", MonthlyIncome: 800}
+	assignment, err = service.RouteLead(english, property)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LeadRoutingLanguage, assignment.Outcome)
+	assert.Equal(t, "agent-b", *assignment.AgentID)
+
+	leads, total, err := service.ListMyLeads(0, 0, domain.NewActor("agent-b", string(domain.RoleAgent), "agency-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, leads, 2)
+	assert.Len(t, repo.assignments, 5)
+
+	owned := domain.NewProperty("Casa en Samborondón", "Casa", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	assignment, err = service.RouteLead(application, owned)
+	require.NoError(t, err)
+	assert.Nil(t, assignment, "listings outside agencies are not routed")
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// overdueLeadBatch bounds the leads reassigned per run of the lead SLA job
+const overdueLeadBatch = 100
+
+// LeadResponseTracker records the first response to a lead, e.g. LeadSLAService
+type LeadResponseTracker interface {
+	RecordLeadResponse(lead *domain.LeadAssignment, kind, responderID string) error
+}
+
+// ApplicationResponseTracker records reviewing an inquiry as the first response to the
+// lead it was routed as, e.g. LeadSLAService
+type ApplicationResponseTracker interface {
+	RecordApplicationResponse(applicationID, responderID string) error
+}
+
+// LeadSLAService tracks how fast agents answer the leads routed to them against the
+// response policy of their agency, and moves leads nobody answered in time to another
+// agent. A lead is answered by a masked call, moving it in the pipeline or reviewing
+// the inquiry behind it.
+type LeadSLAService struct {
+	repo   repository.LeadSLARepository
+	leads  repository.LeadRoutingRepository
+	users  LeadRoutingUsers
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewLeadSLAService creates a new lead SLA service
+func NewLeadSLAService(repo repository.LeadSLARepository, leads repository.LeadRoutingRepository, users LeadRoutingUsers, logger *log.Logger) *LeadSLAService {
+	return &LeadSLAService{
+		repo:   repo,
+		leads:  leads,
+		users:  users,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// GetPolicy retrieves the response policy of an agency for its members
+func (s *LeadSLAService) GetPolicy(agencyID string, actor domain.Actor) (*domain.LeadResponsePolicy, error) {
+	if !actor.CanAdministerAgency(agencyID) && actor.AgencyID != agencyID {
+		return nil, fmt.Errorf("permission denied: only members of the agency can see its response policy")
+	}
+	return s.repo.GetPolicy(agencyID)
+}
+
+// UpdatePolicy sets how fast an agency expects leads answered and when unanswered leads
+// are reassigned. Leads routed before the change are not reassigned.
+func (s *LeadSLAService) UpdatePolicy(agencyID string, targetMinutes, reassignAfterMinutes int, actor domain.Actor) (*domain.LeadResponsePolicy, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can manage its response policy")
+	}
+	policy, err := domain.NewLeadResponsePolicy(agencyID, targetMinutes, reassignAfterMinutes, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if err := s.repo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Agency %s set its lead response target to %d minutes (reassign after %d)", agencyID, targetMinutes, reassignAfterMinutes)
+	return policy, nil
+}
+
+// GetReport reports how fast the leads routed between from and to were answered, the
+// last DefaultResponseReportDays days by default. Agents only see their own leads.
+func (s *LeadSLAService) GetReport(agencyID, agentID string, from, to *time.Time, actor domain.Actor) (*domain.LeadResponseReport, error) {
+	switch {
+	case actor.CanAdministerAgency(agencyID):
+		// The agency sees any agent's leads or all of them
+	case actor.Role == domain.RoleAgent && actor.AgencyID == agencyID:
+		agentID = actor.UserID
+	default:
+		return nil, fmt.Errorf("permission denied: only members of the agency can see its response times")
+	}
+
+	now := s.now()
+	end := now
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -domain.DefaultResponseReportDays)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("invalid period: from must be before to")
+	}
+
+	policy, err := s.repo.GetPolicy(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := s.repo.ListResponseSamples(agencyID, agentID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildLeadResponseReport(policy, samples, start, end, now), nil
+}
+
+// RecordLeadResponse records the first response to a lead; later responses are ignored
+func (s *LeadSLAService) RecordLeadResponse(lead *domain.LeadAssignment, kind, responderID string) error {
+	assignedAt := lead.CreatedAt
+	reassignedAt, err := s.repo.LastReassignedAt(lead.ID)
+	if err != nil {
+		return err
+	}
+	if reassignedAt != nil {
+		assignedAt = *reassignedAt
+	}
+
+	response := domain.NewLeadResponse(lead, assignedAt, kind, responderID, s.now())
+	first, err := s.repo.SaveFirstResponse(response)
+	if err != nil {
+		return err
+	}
+	if first {
+		s.logger.Printf("Lead %s answered by %s (%s) after %ds", lead.ID, responderID, kind, response.ResponseSeconds)
+	}
+	return nil
+}
+
+// RecordApplicationResponse records reviewing an inquiry as the first response to its
+// lead. Inquiries on listings outside agencies are not leads and are ignored.
+func (s *LeadSLAService) RecordApplicationResponse(applicationID, responderID string) error {
+	leadID, err := s.repo.GetLeadIDByApplication(applicationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+	lead, err := s.leads.GetAssignment(leadID)
+	if err != nil {
+		return err
+	}
+	return s.RecordLeadResponse(lead, domain.LeadResponseApplicationStatus, responderID)
+}
+
+// ReassignOverdue moves the leads left unanswered past their agency's reassignment
+// window to the next agent: among the agents of the rule that routed the lead, or the
+// agency's active agents. It returns how many leads were reassigned.
+func (s *LeadSLAService) ReassignOverdue() (int, error) {
+	now := s.now()
+	overdue, err := s.repo.ListOverdueLeads(now, overdueLeadBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	reassigned := 0
+	for _, item := range overdue {
+		lead, err := s.leads.GetAssignment(item.LeadID)
+		if err != nil {
+			return reassigned, err
+		}
+		candidates, err := s.candidateAgents(lead)
+		if err != nil {
+			return reassigned, err
+		}
+		agentID := domain.NextLeadAgent(candidates, *lead.AgentID)
+		if agentID == "" {
+			continue
+		}
+
+		reassignment, err := lead.Reassign(agentID, now)
+		if err != nil {
+			return reassigned, err
+		}
+		if err := s.repo.Reassign(reassignment); err != nil {
+			// Answered or reassigned since it was listed
+			s.logger.Printf("Lead %s not reassigned: %v", lead.ID, err)
+			continue
+		}
+		reassigned++
+		s.logger.Printf("Lead %s unanswered since %s reassigned from %s to %s",
+			lead.ID, item.AssignedAt.Format(time.RFC3339), reassignment.FromAgentID, reassignment.ToAgentID)
+	}
+	return reassigned, nil
+}
+
+// candidateAgents returns the active agents a lead may move to: those of the rule that
+// routed it, or every active agent of its agency
+func (s *LeadSLAService) candidateAgents(lead *domain.LeadAssignment) ([]string, error) {
+	users, err := s.users.GetByAgency(lead.AgencyID)
+	if err != nil {
+		return nil, err
+	}
+	active := map[string]bool{}
+	for _, user := range users {
+		if user.IsAgent() && user.Active {
+			active[user.ID] = true
+		}
+	}
+
+	candidates := []string{}
+	if lead.RuleID != nil {
+		rule, err := s.leads.GetRule(*lead.RuleID)
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		if rule != nil {
+			for _, id := range rule.AgentIDs {
+				if active[id] {
+					candidates = append(candidates, id)
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		for id := range active {
+			candidates = append(candidates, id)
+		}
+		sort.Strings(candidates)
+	}
+	return candidates, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryLeadSLA keeps response policies, responses and reassignments in memory, moving
+// the leads of a memoryLeadRouting on reassignment
+type memoryLeadSLA struct {
+	leads         *memoryLeadRouting
+	policies      map[string]*domain.LeadResponsePolicy
+	responses     map[string]*domain.LeadResponse
+	reassignments []domain.LeadReassignment
+}
+
+func (m *memoryLeadSLA) GetPolicy(agencyID string) (*domain.LeadResponsePolicy, error) {
+	if policy, ok := m.policies[agencyID]; ok {
+		copied := *policy
+		return &copied, nil
+	}
+	return domain.DefaultLeadResponsePolicy(agencyID), nil
+}
+
+func (m *memoryLeadSLA) SavePolicy(policy *domain.LeadResponsePolicy) error {
+	copied := *policy
+	m.policies[policy.AgencyID] = &copied
+	return nil
+}
+
+func (m *memoryLeadSLA) GetLeadIDByApplication(applicationID string) (string, error) {
+	for _, lead := range m.leads.assignments {
+		if lead.ApplicationID == applicationID {
+			return lead.ID, nil
+		}
+	}
+	return "", fmt.Errorf("lead not found for application: %s", applicationID)
+}
+
+func (m *memoryLeadSLA) LastReassignedAt(leadID string) (*time.Time, error) {
+	var last *time.Time
+	for i := range m.reassignments {
+		if m.reassignments[i].LeadID == leadID {
+			last = &m.reassignments[i].CreatedAt
+		}
+	}
+	return last, nil
+}
+
+func (m *memoryLeadSLA) SaveFirstResponse(response *domain.LeadResponse) (bool, error) {
+	if _, ok := m.responses[response.LeadID]; ok {
+		return false, nil
+	}
+	copied := *response
+	m.responses[response.LeadID] = &copied
+	return true, nil
+}
+
+func (m *memoryLeadSLA) ListOverdueLeads(now time.Time, limit int) ([]domain.OverdueLead, error) {
+	overdue := []domain.OverdueLead{}
+	for _, lead := range m.leads.assignments {
+		policy, ok := m.policies[lead.AgencyID]
+		if !ok || policy.ReassignAfterMinutes == 0 || lead.CreatedAt.Before(*policy.UpdatedAt) {
+			continue
+		}
+		if _, answered := m.responses[lead.ID]; answered {
+			continue
+		}
+		item := domain.OverdueLead{LeadID: lead.ID, AssignedAt: lead.CreatedAt}
+		for _, reassignment := range m.reassignments {
+			if reassignment.LeadID == lead.ID {
+				item.AssignedAt = reassignment.CreatedAt
+				item.Reassignments++
+			}
+		}
+		window := time.Duration(policy.ReassignAfterMinutes) * time.Minute
+		if item.Reassignments < domain.MaxLeadReassignments && !now.Before(item.AssignedAt.Add(window)) {
+			overdue = append(overdue, item)
+		}
+	}
+	return overdue, nil
+}
+
+func (m *memoryLeadSLA) Reassign(reassignment *domain.LeadReassignment) error {
+	for i := range m.leads.assignments {
+		lead := &m.leads.assignments[i]
+		if lead.ID == reassignment.LeadID && *lead.AgentID == reassignment.FromAgentID {
+			agentID := reassignment.ToAgentID
+			lead.AgentID = &agentID
+			m.reassignments = append(m.reassignments, *reassignment)
+			return nil
+		}
+	}
+	return fmt.Errorf("lead %s was answered or reassigned meanwhile", reassignment.LeadID)
+}
+
+func (m *memoryLeadSLA) ListResponseSamples(agencyID, agentID string, from, to time.Time) ([]domain.LeadResponseSample, error) {
+	samples := []domain.LeadResponseSample{}
+	for _, lead := range m.leads.assignments {
+		if lead.AgencyID != agencyID || lead.CreatedAt.Before(from) || !lead.CreatedAt.Before(to) {
+			continue
+		}
+		sample := domain.LeadResponseSample{LeadID: lead.ID, AgentID: *lead.AgentID, AssignedAt: lead.CreatedAt,
+			Response: m.responses[lead.ID]}
+		if sample.Response != nil {
+			sample.AgentID = sample.Response.AgentID
+		}
+		if agentID == "" || sample.AgentID == agentID {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+func newLeadSLAFixture(t *testing.T, now time.Time) (*LeadSLAService, *memoryLeadSLA, *memoryLeadRouting) {
+	agencyID := "agency-1"
+	users := memoryLeadUsers{
+		"agent-a": {ID: "agent-a", Role: domain.RoleAgent, AgencyID: &agencyID, Active: true},
+		"agent-b": {ID: "agent-b", Role: domain.RoleAgent, AgencyID: &agencyID, Active: true},
+		"agent-c": {ID: "agent-c", Role: domain.RoleAgent, AgencyID: &agencyID, Active: true},
+	}
+	leads := newMemoryLeadRouting()
+	require.NoError(t, leads.CreateRule(&domain.LeadRoutingRule{ID: "rule-1", AgencyID: agencyID, AgentIDs: []string{"agent-a", "agent-b"}}))
+	for i, ruleID := range []string{"rule-1", ""} {
+		var rule *domain.LeadRoutingRule
+		if ruleID != "" {
+			rule, _ = leads.GetRule(ruleID)
+		}
+		lead := domain.NewLeadAssignment(fmt.Sprintf("app-%d", i), "prop-1", agencyID, rule, "agent-a", domain.LeadScore{}, now)
+		lead.ID = fmt.Sprintf("lead-%d", i)
+		require.NoError(t, leads.CreateAssignment(lead))
+	}
+
+	repo := &memoryLeadSLA{leads: leads, policies: map[string]*domain.LeadResponsePolicy{},
+		responses: map[string]*domain.LeadResponse{}}
+	svc := NewLeadSLAService(repo, leads, users, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+	return svc, repo, leads
+}
+
+func TestLeadSLAService_RecordResponses(t *testing.T) {
+	now := time.Date(2025, 9, 25, 9, 0, 0, 0, time.UTC)
+	svc, repo, leads := newLeadSLAFixture(t, now)
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+	agentA := domain.NewActor("agent-a", string(domain.RoleAgent), "agency-1")
+
+	_, err := svc.UpdatePolicy("agency-1", 30, 0, agentA)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.UpdatePolicy("agency-1", 30, 10, agency)
+	assert.ErrorContains(t, err, "invalid policy")
+	_, err = svc.UpdatePolicy("agency-1", 30, 0, agency)
+	require.NoError(t, err)
+
+	// Moving a lead in the pipeline answers it; later responses are ignored
+	pipelines := NewLeadPipelineService(&memoryLeadPipelines{pipelines: map[string]*domain.LeadPipeline{}, leads: leads},
+		leads, log.New(&bytes.Buffer{}, "", 0))
+	pipelines.SetResponseTracker(svc)
+	pipelines.now = func() time.Time { return now.Add(20 * time.Minute) }
+	svc.now = pipelines.now
+	_, _, err = pipelines.MoveLead("lead-0", domain.LeadStageContacted, agentA)
+	require.NoError(t, err)
+	require.NoError(t, svc.RecordApplicationResponse("app-0", "agency-1"))
+	require.NoError(t, svc.RecordApplicationResponse("app-unrouted", "owner-1"), "inquiries outside agencies are not leads")
+	require.Contains(t, repo.responses, "lead-0")
+	assert.Equal(t, domain.LeadResponseStageChange, repo.responses["lead-0"].Kind)
+	assert.Equal(t, int64(20*60), repo.responses["lead-0"].ResponseSeconds)
+
+	svc.now = func() time.Time { return now.Add(2 * time.Hour) }
+	report, err := svc.GetReport("agency-1", "agent-b", nil, nil, agentA)
+	require.NoError(t, err)
+	require.Len(t, report.Agents, 1, "agents only see their own leads")
+	assert.Equal(t, domain.LeadResponseStats{AgentID: "agent-a", Leads: 2, Responded: 1, WithinTarget: 1, Overdue: 1,
+		ComplianceRate: 50, AverageMinutes: 20, MedianMinutes: 20}, report.Agents[0])
+	_, err = svc.GetReport("agency-1", "", nil, nil, domain.NewActor("agent-x", string(domain.RoleAgent), "agency-2"))
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestLeadSLAService_ReassignOverdue(t *testing.T) {
+	now := time.Date(2025, 9, 25, 9, 0, 0, 0, time.UTC)
+	svc, repo, leads := newLeadSLAFixture(t, now)
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+	svc.now = func() time.Time { return now.Add(-time.Hour) }
+	_, err := svc.UpdatePolicy("agency-1", 30, 60, agency)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return now.Add(30 * time.Minute) }
+	reassigned, err := svc.ReassignOverdue()
+	require.NoError(t, err)
+	assert.Equal(t, 0, reassigned, "leads are reassigned once the window passes")
+
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	reassigned, err = svc.ReassignOverdue()
+	require.NoError(t, err)
+	assert.Equal(t, 2, reassigned)
+	lead, _ := leads.GetAssignment("lead-0")
+	assert.Equal(t, "agent-b", *lead.AgentID, "the rule's agents take turns")
+	lead, _ = leads.GetAssignment("lead-1")
+	assert.Equal(t, "agent-b", *lead.AgentID)
+
+	// The new agent's time counts from the reassignment
+	require.NoError(t, svc.RecordLeadResponse(lead, domain.LeadResponseCall, "agent-b"))
+	assert.Equal(t, int64(0), repo.responses["lead-1"].ResponseSeconds)
+
+	svc.now = func() time.Time { return now.Add(2 * time.Hour) }
+	reassigned, err = svc.ReassignOverdue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, reassigned, "answered leads stay")
+	lead, _ = leads.GetAssignment("lead-0")
+	assert.Equal(t, "agent-a", *lead.AgentID, "the rotation wraps around")
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+	"realty-core/internal/search"
+)
+
+// ListingNotifier tells owners and agents that a listing expired
+type ListingNotifier interface {
+	NotifyListingExpired(listing domain.ExpiredListing) error
+}
+
+// LogListingNotifier writes expiration notices to the log. It is used until an email
+// provider is configured.
+type LogListingNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogListingNotifier creates a notifier that logs expiration notices
+func NewLogListingNotifier(logger *log.Logger) *LogListingNotifier {
+	return &LogListingNotifier{logger: logger}
+}
+
+// NotifyListingExpired logs the expiration notice
+func (n *LogListingNotifier) NotifyListingExpired(listing domain.ExpiredListing) error {
+	recipients := []string{}
+	for _, id := range []*string{listing.OwnerID, listing.AgentID} {
+		if id != nil {
+			recipients = append(recipients, *id)
+		}
+	}
+	n.logger.Printf("Listing %q (%s) expired; notifying %v", listing.Title, listing.PropertyID, recipients)
+	return nil
+}
+
+// ListingLifecycleConfig configures listing expiration
+type ListingLifecycleConfig struct {
+	Interval   time.Duration // time between expiration runs
+	ExpiryDays int           // expiry of listings without an agency plan
+}
+
+// ListingLifecycleService expires listings that were not renewed within their plan's
+// window and republishes them on renewal
+type ListingLifecycleService struct {
+	repo             repository.ListingLifecycleRepository
+	propertyRepo     repository.PropertyRepository
+	subscriptionRepo repository.SubscriptionRepository
+	notifier         ListingNotifier
+	preferences      NotificationPreferences
+	plans            map[string]domain.SubscriptionPlan
+	config           ListingLifecycleConfig
+	limiter          ListingLimiter
+	listener         PropertyChangeListener
+	indexer          *search.Indexer
+	now              func() time.Time
+	logger           *log.Logger
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewListingLifecycleService creates a new listing lifecycle service. subscriptionRepo
+// may be nil, in which case every listing uses the configured expiry.
+func NewListingLifecycleService(
+	repo repository.ListingLifecycleRepository,
+	propertyRepo repository.PropertyRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	notifier ListingNotifier,
+	config ListingLifecycleConfig,
+	logger *log.Logger,
+) *ListingLifecycleService {
+	if notifier == nil {
+		notifier = NewLogListingNotifier(logger)
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.ExpiryDays <= 0 {
+		config.ExpiryDays = domain.DefaultListingExpiryDays
+	}
+
+	return &ListingLifecycleService{
+		repo:             repo,
+		propertyRepo:     propertyRepo,
+		subscriptionRepo: subscriptionRepo,
+		notifier:         notifier,
+		plans:            domain.DefaultSubscriptionPlans,
+		config:           config,
+		now:              time.Now,
+		logger:           logger,
+	}
+}
+
+// SetListingLimiter enforces agency plan limits when expired listings are republished
+func (s *ListingLifecycleService) SetListingLimiter(limiter ListingLimiter) {
+	s.limiter = limiter
+}
+
+// SetChangeListener registers a listener for expired and renewed listings, typically the
+// PropertyService so cached listings and searches reflect them
+func (s *ListingLifecycleService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// SetNotificationPreferences skips expiration notices to owners and agents who turned
+// off email notifications
+func (s *ListingLifecycleService) SetNotificationPreferences(preferences NotificationPreferences) {
+	s.preferences = preferences
+}
+
+// SetSearchIndexer removes expired listings from the search backend and restores renewed ones
+func (s *ListingLifecycleService) SetSearchIndexer(indexer *search.Indexer) {
+	s.indexer = indexer
+}
+
+// ExpireStale marks stale listings expired and notifies their owners and agents
+func (s *ListingLifecycleService) ExpireStale() ([]domain.ExpiredListing, error) {
+	planDays := make(map[string]int, len(s.plans))
+	for name, plan := range s.plans {
+		planDays[name] = domain.ListingExpiryDays(&plan, s.config.ExpiryDays)
+	}
+
+	expired, err := s.repo.ExpireStale(s.now(), planDays, s.config.ExpiryDays)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(expired))
+	for _, listing := range expired {
+		ids = append(ids, listing.PropertyID)
+		if s.indexer != nil {
+			s.indexer.EnqueueDelete(listing.PropertyID)
+		}
+		if notice, ok := s.expirationNotice(listing); ok {
+			if err := s.notifier.NotifyListingExpired(notice); err != nil {
+				s.logger.Printf("Error notifying expiration of listing %s: %v", listing.PropertyID, err)
+			}
+		}
+	}
+
+	if len(ids) > 0 {
+		s.changed(ids...)
+		s.logger.Printf("%d stale listings expired", len(ids))
+	}
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter("listings_expired_total", "Total number of stale listings expired").Add(int64(len(ids)))
+	}
+
+	return expired, nil
+}
+
+// expirationNotice drops the owner and agent of an expired listing who do not accept
+// email notifications; it reports false when nobody is left to notify
+func (s *ListingLifecycleService) expirationNotice(listing domain.ExpiredListing) (domain.ExpiredListing, bool) {
+	if s.preferences == nil {
+		return listing, true
+	}
+
+	if listing.OwnerID != nil && !s.preferences.AllowsNotification(*listing.OwnerID, domain.ChannelEmail) {
+		listing.OwnerID = nil
+	}
+	if listing.AgentID != nil && !s.preferences.AllowsNotification(*listing.AgentID, domain.ChannelEmail) {
+		listing.AgentID = nil
+	}
+	return listing, listing.OwnerID != nil || listing.AgentID != nil
+}
+
+// RenewListing republishes an expired or available listing, restarting its expiry window
+// and its recency in search ranking
+func (s *ListingLifecycleService) RenewListing(propertyID string, actor domain.Actor) (*domain.ListingRenewal, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can renew it")
+	}
+	if property.Status != domain.StatusExpired && property.Status != domain.StatusAvailable {
+		return nil, fmt.Errorf("invalid property: %s listings cannot be renewed", property.Status)
+	}
+
+	var plan *domain.SubscriptionPlan
+	if property.AgencyID != nil {
+		if property.Status == domain.StatusExpired && s.limiter != nil {
+			if err := s.limiter.CheckListingLimit(*property.AgencyID); err != nil {
+				return nil, err
+			}
+		}
+		plan = s.agencyPlan(*property.AgencyID)
+	}
+
+	now := s.now()
+	count, err := s.repo.Renew(propertyID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.indexer != nil {
+		property.Status = domain.StatusAvailable
+		property.CreatedAt = now
+		s.indexer.EnqueueIndex(property)
+	}
+	s.changed(propertyID)
+
+	days := domain.ListingExpiryDays(plan, s.config.ExpiryDays)
+	s.logger.Printf("Listing %s renewed for %d days", propertyID, days)
+	return &domain.ListingRenewal{
+		PropertyID:   propertyID,
+		Status:       domain.StatusAvailable,
+		RenewedAt:    now,
+		ExpiresAt:    now.AddDate(0, 0, days),
+		RenewalCount: count,
+	}, nil
+}
+
+// Start runs the expiration on its interval until Stop is called
+func (s *ListingLifecycleService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.ExpireStale(); err != nil {
+					s.logger.Printf("Listing expiration failed: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	s.logger.Printf("Listing expiration scheduled every %s (default expiry %d days)", s.config.Interval, s.config.ExpiryDays)
+}
+
+// Stop stops the scheduled expiration and waits for a running one to finish
+func (s *ListingLifecycleService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// agencyPlan returns the subscription plan of an agency, or nil when unknown
+func (s *ListingLifecycleService) agencyPlan(agencyID string) *domain.SubscriptionPlan {
+	if s.subscriptionRepo == nil {
+		return nil
+	}
+	name, _, err := s.subscriptionRepo.GetAgencyPlan(agencyID)
+	if err != nil {
+		s.logger.Printf("Error getting plan of agency %s: %v", agencyID, err)
+		return nil
+	}
+	if plan, ok := s.plans[name]; ok {
+		return &plan
+	}
+	return nil
+}
+
+// changed notifies the listener of modified properties
+func (s *ListingLifecycleService) changed(ids ...string) {
+	if s.listener != nil && len(ids) > 0 {
+		s.listener.InvalidateProperties(ids...)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// fakeListingLifecycleRepository records expiration runs and renewals
+type fakeListingLifecycleRepository struct {
+	planDays    map[string]int
+	defaultDays int
+	expired     []domain.ExpiredListing
+	renewals    int
+}
+
+func (r *fakeListingLifecycleRepository) ExpireStale(now time.Time, planDays map[string]int, defaultDays int) ([]domain.ExpiredListing, error) {
+	r.planDays = planDays
+	r.defaultDays = defaultDays
+	return r.expired, nil
+}
+
+func (r *fakeListingLifecycleRepository) Renew(propertyID string, now time.Time) (int, error) {
+	r.renewals++
+	return r.renewals, nil
+}
+
+// recordingListingNotifier records expiration notices
+type recordingListingNotifier struct {
+	notified []string
+}
+
+func (n *recordingListingNotifier) NotifyListingExpired(listing domain.ExpiredListing) error {
+	n.notified = append(n.notified, listing.PropertyID)
+	return nil
+}
+
+func TestListingLifecycleService_ExpireStale(t *testing.T) {
+	repo := &fakeListingLifecycleRepository{expired: []domain.ExpiredListing{{PropertyID: "prop-1"}, {PropertyID: "prop-2"}}}
+	notifier := &recordingListingNotifier{}
+	listener := &recordingListener{}
+
+	service := NewListingLifecycleService(repo, nil, nil, notifier, ListingLifecycleConfig{ExpiryDays: 45}, log.New(os.Stdout, "", 0))
+	service.SetChangeListener(listener)
+
+	expired, err := service.ExpireStale()
+	require.NoError(t, err)
+	assert.Len(t, expired, 2)
+	assert.Equal(t, 45, repo.defaultDays)
+	assert.Equal(t, 60, repo.planDays[domain.PlanFree])
+	assert.Equal(t, 180, repo.planDays[domain.PlanPremium])
+	assert.Equal(t, []string{"prop-1", "prop-2"}, notifier.notified)
+	assert.Equal(t, []string{"prop-1", "prop-2"}, listener.ids)
+}
+
+func TestListingLifecycleService_RenewListing(t *testing.T) {
+	expired := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	expired.ID = "prop-expired"
+	expired.Status = domain.StatusExpired
+	expired.SetAgency("agency-1")
+	sold := domain.NewProperty("Depto", "Departamento en Cumbayá", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	sold.ID = "prop-sold"
+	sold.Status = domain.StatusSold
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-expired").Return(expired, nil)
+	propertyRepo.On("GetByID", "prop-sold").Return(sold, nil)
+
+	plans := &fakeSubscriptionRepository{plan: domain.PlanPro}
+	service := NewListingLifecycleService(&fakeListingLifecycleRepository{}, propertyRepo, plans, nil, ListingLifecycleConfig{}, log.New(os.Stdout, "", 0))
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	agency := domain.NewActor("user-1", "agency", "agency-1")
+
+	_, err := service.RenewListing("prop-expired", domain.NewActor("user-2", "agency", "agency-2"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.RenewListing("prop-sold", domain.NewActor("owner-1", "seller", ""))
+	assert.ErrorContains(t, err, "cannot be renewed")
+
+	renewal, err := service.RenewListing("prop-expired", agency)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusAvailable, renewal.Status)
+	assert.Equal(t, now.AddDate(0, 0, 90), renewal.ExpiresAt, "pro plan listings last 90 days")
+	assert.Equal(t, 1, renewal.RenewalCount)
+
+	plans.plan = domain.PlanFree
+	plans.usage.ActiveListings = 10
+	service.SetListingLimiter(NewSubscriptionService(plans, &fakeAuditRepository{}, log.New(os.Stdout, "", 0)))
+	_, err = service.RenewListing("prop-expired", agency)
+	assert.ErrorContains(t, err, "plan limit reached")
+}
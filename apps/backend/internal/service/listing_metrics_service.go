@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// DefaultMarketPeriodDays is how far back market days on market counts closed listings
+const DefaultMarketPeriodDays = 365
+
+// MaxStaleListings caps the listings of a stale listing report
+const MaxStaleListings = 200
+
+// ListingShareCounter counts the shares of properties, e.g. repository.PropertyShareRepository
+type ListingShareCounter interface {
+	CountByProperty(propertyID string) (*domain.ShareCounts, error)
+}
+
+// ListingMetricsService reports days on market and listing freshness: per property for
+// the listing's managers, per market segment for everyone, and the stale listings of
+// each agency
+type ListingMetricsService struct {
+	repo         repository.ListingMetricsRepository
+	propertyRepo repository.PropertyRepository
+	staleDays    int
+	shares       ListingShareCounter
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewListingMetricsService creates a listing metrics service. Listings published
+// staleDays ago or longer are reported as stale; zero uses domain.DefaultStaleListingDays.
+func NewListingMetricsService(
+	repo repository.ListingMetricsRepository,
+	propertyRepo repository.PropertyRepository,
+	staleDays int,
+	logger *log.Logger,
+) *ListingMetricsService {
+	if staleDays <= 0 {
+		staleDays = domain.DefaultStaleListingDays
+	}
+	return &ListingMetricsService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		staleDays:    staleDays,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetShareCounter adds the shares of a listing by channel to its analytics
+func (s *ListingMetricsService) SetShareCounter(shares ListingShareCounter) {
+	s.shares = shares
+}
+
+// GetPropertyMetrics returns the days on market of a listing. Listings still on the
+// market count the days since they were published or last renewed.
+func (s *ListingMetricsService) GetPropertyMetrics(propertyID string, actor domain.Actor) (*domain.ListingMetrics, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can see its analytics")
+	}
+
+	metrics, err := s.repo.GetPropertyMetrics(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if metrics.IsOnMarket() {
+		metrics.DaysOnMarket = domain.DaysBetween(metrics.PublishedAt, s.now())
+		metrics.Stale = metrics.DaysOnMarket >= s.staleDays
+	}
+	if s.shares != nil {
+		if metrics.Shares, err = s.shares.CountByProperty(propertyID); err != nil {
+			return nil, err
+		}
+	}
+	return metrics, nil
+}
+
+// GetMarketDaysOnMarket summarizes days on market of a province and property type,
+// either empty for all, over listings closed in the last periodDays
+func (s *ListingMetricsService) GetMarketDaysOnMarket(province, propertyType string, periodDays int) (*domain.MarketDaysOnMarket, error) {
+	if province != "" && !domain.IsValidProvince(province) {
+		return nil, fmt.Errorf("invalid province: %s", province)
+	}
+	if propertyType != "" && !domain.IsValidPropertyType(propertyType) {
+		return nil, fmt.Errorf("invalid property type: %s", propertyType)
+	}
+	if periodDays <= 0 {
+		periodDays = DefaultMarketPeriodDays
+	}
+
+	now := s.now()
+	return s.repo.GetMarketDaysOnMarket(province, propertyType, now.AddDate(0, 0, -periodDays), now)
+}
+
+// GetStaleListings lists the available and reserved listings of an agency published
+// at least days ago, oldest first; zero days uses the configured threshold. The agency
+// account and admins can read it.
+func (s *ListingMetricsService) GetStaleListings(agencyID string, days, limit int, actor domain.Actor) ([]domain.StaleListing, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can view its stale listings")
+	}
+	if days <= 0 {
+		days = s.staleDays
+	}
+	if limit <= 0 || limit > MaxStaleListings {
+		limit = MaxStaleListings
+	}
+
+	now := s.now()
+	listings, err := s.repo.ListStaleListings(agencyID, now.AddDate(0, 0, -days), limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range listings {
+		listings[i].DaysListed = domain.DaysBetween(listings[i].PublishedAt, now)
+	}
+	return listings, nil
+}
+
+// StaleDays returns the configured stale listing threshold in days
+func (s *ListingMetricsService) StaleDays() int {
+	return s.staleDays
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryListingMetrics serves fixed listing metrics and records stale report cutoffs
+type memoryListingMetrics struct {
+	metrics map[string]domain.ListingMetrics
+	stale   []domain.StaleListing
+	cutoffs []time.Time
+	since   time.Time
+}
+
+func (r *memoryListingMetrics) GetPropertyMetrics(propertyID string) (*domain.ListingMetrics, error) {
+	metrics := r.metrics[propertyID]
+	return &metrics, nil
+}
+
+func (r *memoryListingMetrics) GetMarketDaysOnMarket(province, propertyType string, since, now time.Time) (*domain.MarketDaysOnMarket, error) {
+	r.since = since
+	return &domain.MarketDaysOnMarket{Province: province, PropertyType: propertyType, Since: since}, nil
+}
+
+func (r *memoryListingMetrics) ListStaleListings(agencyID string, publishedBefore time.Time, limit int) ([]domain.StaleListing, error) {
+	r.cutoffs = append(r.cutoffs, publishedBefore)
+	return append([]domain.StaleListing{}, r.stale...), nil
+}
+
+func TestListingMetricsService(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	closedAt := now.AddDate(0, 0, -5)
+	repo := &memoryListingMetrics{
+		metrics: map[string]domain.ListingMetrics{
+			"prop-open": {PropertyID: "prop-open", Status: domain.StatusAvailable, PublishedAt: now.AddDate(0, 0, -75)},
+			"prop-sold": {PropertyID: "prop-sold", Status: domain.StatusSold, PublishedAt: now.AddDate(0, 0, -40), ClosedAt: &closedAt, DaysOnMarket: 35},
+		},
+		stale: []domain.StaleListing{{PropertyID: "prop-open", PublishedAt: now.AddDate(0, 0, -75)}},
+	}
+	agencyID := "agency-1"
+	properties := &MockPropertyRepository{}
+	properties.On("GetByID", "prop-open").Return(&domain.Property{ID: "prop-open", AgencyID: &agencyID}, nil)
+	properties.On("GetByID", "prop-sold").Return(&domain.Property{ID: "prop-sold", AgencyID: &agencyID}, nil)
+
+	var logs bytes.Buffer
+	svc := NewListingMetricsService(repo, properties, 0, log.New(&logs, "", 0))
+	svc.now = func() time.Time { return now }
+	agency := domain.NewActor(agencyID, string(domain.RoleAgency), agencyID)
+
+	// Open listings count their days since publication; closed ones keep the stored days
+	open, err := svc.GetPropertyMetrics("prop-open", agency)
+	require.NoError(t, err)
+	assert.Equal(t, 75, open.DaysOnMarket)
+	assert.True(t, open.Stale)
+
+	sold, err := svc.GetPropertyMetrics("prop-sold", agency)
+	require.NoError(t, err)
+	assert.Equal(t, 35, sold.DaysOnMarket)
+	assert.False(t, sold.Stale)
+
+	_, err = svc.GetPropertyMetrics("prop-open", domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = svc.GetMarketDaysOnMarket("Pichincha", domain.TypeHouse, 0)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -DefaultMarketPeriodDays), repo.since)
+
+	_, err = svc.GetMarketDaysOnMarket("Atlantis", "", 0)
+	assert.ErrorContains(t, err, "invalid province")
+
+	// The stale report uses the configured threshold unless the request sets one
+	listings, err := svc.GetStaleListings(agencyID, 0, 0, agency)
+	require.NoError(t, err)
+	require.Len(t, listings, 1)
+	assert.Equal(t, 75, listings[0].DaysListed)
+
+	_, err = svc.GetStaleListings(agencyID, 90, 0, agency)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{now.AddDate(0, 0, -domain.DefaultStaleListingDays), now.AddDate(0, 0, -90)}, repo.cutoffs)
+
+	_, err = svc.GetStaleListings(agencyID, 0, 0, domain.NewActor("agent-1", string(domain.RoleAgent), agencyID))
+	assert.ErrorContains(t, err, "permission denied")
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// LoginAlertNotifier warns users about logins from locations they never logged in from
+type LoginAlertNotifier interface {
+	NotifyNewLoginLocation(user *domain.User, event *domain.LoginEvent) error
+}
+
+// LogLoginAlertNotifier writes new location alerts to the log. It is used until an
+// email provider is configured.
+type LogLoginAlertNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogLoginAlertNotifier creates a notifier that logs new location alerts
+func NewLogLoginAlertNotifier(logger *log.Logger) *LogLoginAlertNotifier {
+	return &LogLoginAlertNotifier{logger: logger}
+}
+
+// NotifyNewLoginLocation logs the alert email
+func (n *LogLoginAlertNotifier) NotifyNewLoginLocation(user *domain.User, event *domain.LoginEvent) error {
+	n.logger.Printf("New login location for %s: %s from %s at %s; emailing %s",
+		user.ID, event.Location(), event.IPAddress, event.CreatedAt.Format("2006-01-02 15:04 MST"), user.Email)
+	return nil
+}
+
+// LoginLocationService records where every login comes from and alerts users when a
+// login comes from a new country or city
+type LoginLocationService struct {
+	repo     repository.LoginEventRepository
+	notifier LoginAlertNotifier
+	logger   *log.Logger
+}
+
+// NewLoginLocationService creates a login location service
+func NewLoginLocationService(repo repository.LoginEventRepository, notifier LoginAlertNotifier, logger *log.Logger) *LoginLocationService {
+	return &LoginLocationService{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// RecordLogin stores the session a login started with its location. A located login
+// from a country and city the user never logged in from is flagged and the user is
+// alerted; the first located login only sets the baseline.
+func (s *LoginLocationService) RecordLogin(user *domain.User, sessionID, ipAddress, userAgent string, location domain.GeoLocation) (*domain.LoginEvent, error) {
+	if user == nil || user.ID == "" {
+		return nil, fmt.Errorf("invalid login: user is required")
+	}
+
+	event := domain.NewLoginEvent(user.ID, sessionID, ipAddress, userAgent, location)
+	if location.IsKnown() {
+		total, fromLocation, err := s.repo.CountLogins(user.ID, location)
+		if err != nil {
+			return nil, err
+		}
+		event.NewLocation = total > 0 && fromLocation == 0
+	}
+
+	if err := s.repo.Create(event); err != nil {
+		return nil, err
+	}
+
+	if event.NewLocation && s.notifier != nil {
+		if err := s.notifier.NotifyNewLoginLocation(user, event); err != nil {
+			s.logger.Printf("Failed to alert user %s of login from %s: %v", user.ID, location, err)
+		}
+	}
+	return event, nil
+}
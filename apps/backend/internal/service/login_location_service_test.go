@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryLoginEventRepository keeps login events in memory
+type memoryLoginEventRepository struct {
+	events []*domain.LoginEvent
+}
+
+func (r *memoryLoginEventRepository) Create(event *domain.LoginEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryLoginEventRepository) CountLogins(userID string, location domain.GeoLocation) (int, int, error) {
+	total, fromLocation := 0, 0
+	for _, event := range r.events {
+		if event.UserID != userID || event.CountryCode == "" {
+			continue
+		}
+		total++
+		if event.CountryCode == location.CountryCode && event.City == location.City {
+			fromLocation++
+		}
+	}
+	return total, fromLocation, nil
+}
+
+// recordingLoginAlertNotifier records the alerted logins
+type recordingLoginAlertNotifier struct {
+	alerts []*domain.LoginEvent
+	err    error
+}
+
+func (n *recordingLoginAlertNotifier) NotifyNewLoginLocation(user *domain.User, event *domain.LoginEvent) error {
+	n.alerts = append(n.alerts, event)
+	return n.err
+}
+
+func TestLoginLocationService_RecordLogin(t *testing.T) {
+	repo := &memoryLoginEventRepository{}
+	notifier := &recordingLoginAlertNotifier{}
+	service := NewLoginLocationService(repo, notifier, log.New(io.Discard, "", 0))
+	user := &domain.User{ID: "user-1", Email: "ana@example.com"}
+	quito := domain.GeoLocation{CountryCode: "EC", CountryName: "Ecuador", City: "Quito"}
+	lima := domain.GeoLocation{CountryCode: "PE", CountryName: "Perú", City: "Lima"}
+
+	// The first located login sets the baseline
+	event, err := service.RecordLogin(user, "session-1", "190.152.10.4", "Mozilla/5.0", quito)
+	require.NoError(t, err)
+	assert.False(t, event.NewLocation)
+	assert.Equal(t, "session-1", event.SessionID)
+	assert.Equal(t, "Quito", event.City)
+
+	// Unlocated logins never alert
+	event, err = service.RecordLogin(user, "session-2", "10.0.0.5", "", domain.GeoLocation{})
+	require.NoError(t, err)
+	assert.False(t, event.NewLocation)
+
+	event, err = service.RecordLogin(user, "session-3", "190.152.10.8", "", quito)
+	require.NoError(t, err)
+	assert.False(t, event.NewLocation)
+	assert.Empty(t, notifier.alerts)
+
+	event, err = service.RecordLogin(user, "session-4", "200.48.1.1", "", lima)
+	require.NoError(t, err)
+	assert.True(t, event.NewLocation)
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, "Lima", notifier.alerts[0].City)
+
+	event, err = service.RecordLogin(user, "session-5", "200.48.1.2", "", lima)
+	require.NoError(t, err)
+	assert.False(t, event.NewLocation, "Lima is known now")
+
+	// Alert failures do not fail the login
+	notifier.err = fmt.Errorf("smtp down")
+	event, err = service.RecordLogin(user, "session-6", "190.95.1.1", "",
+		domain.GeoLocation{CountryCode: "EC", CountryName: "Ecuador", City: "Guayaquil"})
+	require.NoError(t, err)
+	assert.True(t, event.NewLocation)
+	assert.Len(t, notifier.alerts, 2)
+	assert.Len(t, repo.events, 6)
+
+	_, err = service.RecordLogin(nil, "session-7", "190.152.10.4", "", quito)
+	assert.ErrorContains(t, err, "invalid login")
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// MaintenanceNotifier tells landlords about new maintenance requests and both sides
+// about their progress
+type MaintenanceNotifier interface {
+	// NotifyMaintenanceOpened notifies the owner or agent of the property
+	NotifyMaintenanceOpened(request *domain.MaintenanceRequest, property *domain.Property) error
+
+	// NotifyMaintenanceStatusChanged notifies the tenant of a change made by the
+	// property's managers, and the managers of a change made by the tenant
+	NotifyMaintenanceStatusChanged(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate, property *domain.Property) error
+}
+
+// LogMaintenanceNotifier writes maintenance notices to the log. It is used until an
+// email provider is configured.
+type LogMaintenanceNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogMaintenanceNotifier creates a notifier that logs maintenance notices
+func NewLogMaintenanceNotifier(logger *log.Logger) *LogMaintenanceNotifier {
+	return &LogMaintenanceNotifier{logger: logger}
+}
+
+// NotifyMaintenanceOpened logs the new request
+func (n *LogMaintenanceNotifier) NotifyMaintenanceOpened(request *domain.MaintenanceRequest, property *domain.Property) error {
+	n.logger.Printf("Maintenance request %s (%s, %s) opened for %q (%s); notifying %v",
+		request.ID, request.Category, request.Priority, property.Title, property.ID, property.GetManagers())
+	return nil
+}
+
+// NotifyMaintenanceStatusChanged logs the change
+func (n *LogMaintenanceNotifier) NotifyMaintenanceStatusChanged(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate, property *domain.Property) error {
+	recipients := []string{request.TenantID}
+	if update.ChangedBy == request.TenantID {
+		recipients = property.GetManagers()
+	}
+	n.logger.Printf("Maintenance request %s for %q is now %s; notifying %v",
+		request.ID, property.Title, request.Status, recipients)
+	return nil
+}
+
+// OpenMaintenanceRequest describes a maintenance request
+type OpenMaintenanceRequest struct {
+	PropertyID  string `json:"property_id"`
+	Category    string `json:"category"`
+	Priority    string `json:"priority,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// MaintenanceRequestService tracks maintenance requests of rented properties. Tenants
+// open requests on the property they rent and the property's managers work on them;
+// requests are only visible to the tenant who opened them and to the managers.
+type MaintenanceRequestService struct {
+	repo         repository.MaintenanceRequestRepository
+	propertyRepo repository.PropertyRepository
+	dealRepo     repository.DealRepository
+	storage      storage.ImageStorage
+	scanner      DocumentScanner
+	notifier     MaintenanceNotifier
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewMaintenanceRequestService creates a maintenance request service. The tenant of a
+// property is the client of its latest rent deal; photos are written through storage
+// under a maintenance/ prefix.
+func NewMaintenanceRequestService(
+	repo repository.MaintenanceRequestRepository,
+	propertyRepo repository.PropertyRepository,
+	dealRepo repository.DealRepository,
+	storage storage.ImageStorage,
+	notifier MaintenanceNotifier,
+	logger *log.Logger,
+) *MaintenanceRequestService {
+	if notifier == nil {
+		notifier = NewLogMaintenanceNotifier(logger)
+	}
+
+	return &MaintenanceRequestService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		dealRepo:     dealRepo,
+		storage:      storage,
+		notifier:     notifier,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetDocumentScanner scans photos before they are stored
+func (s *MaintenanceRequestService) SetDocumentScanner(scanner DocumentScanner) {
+	s.scanner = scanner
+}
+
+// Open opens a maintenance request on the property the actor rents
+func (s *MaintenanceRequestService) Open(req OpenMaintenanceRequest, actor domain.Actor) (*domain.MaintenanceRequest, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to request maintenance")
+	}
+
+	property, err := s.propertyRepo.GetByID(req.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	tenant, err := s.isTenant(property, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !tenant {
+		return nil, fmt.Errorf("permission denied: only the current tenant can request maintenance")
+	}
+
+	request, update, err := domain.NewMaintenanceRequest(property.ID, actor.UserID, req.Category, req.Priority,
+		req.Title, req.Description, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(request, update); err != nil {
+		return nil, err
+	}
+	request.History = []domain.MaintenanceUpdate{*update}
+
+	if err := s.notifier.NotifyMaintenanceOpened(request, property); err != nil {
+		s.logger.Printf("Error notifying maintenance request %s: %v", request.ID, err)
+	}
+	return request, nil
+}
+
+// GetRequest returns a request with its history to its tenant or the property's managers
+func (s *MaintenanceRequestService) GetRequest(id string, actor domain.Actor) (*domain.MaintenanceRequest, error) {
+	request, property, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.TenantID != actor.UserID && !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: cannot view this maintenance request")
+	}
+
+	history, err := s.repo.ListUpdates(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	request.History = history
+	return request, nil
+}
+
+// ListMyRequests returns the requests opened by the actor
+func (s *MaintenanceRequestService) ListMyRequests(actor domain.Actor) ([]domain.MaintenanceRequest, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to see your maintenance requests")
+	}
+	return s.repo.ListByTenant(actor.UserID)
+}
+
+// ListPropertyHistory returns the maintenance history of a property, optionally by
+// status. Managers see every request; the current tenant sees the ones they opened.
+func (s *MaintenanceRequestService) ListPropertyHistory(propertyID, status string, actor domain.Actor) ([]domain.MaintenanceRequest, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	manager := canManageListing(property, actor)
+	if !manager {
+		tenant, err := s.isTenant(property, actor)
+		if err != nil {
+			return nil, err
+		}
+		if !tenant {
+			return nil, fmt.Errorf("permission denied: only the property's managers and tenant can see its maintenance")
+		}
+	}
+
+	requests, err := s.repo.ListByProperty(property.ID, status)
+	if err != nil {
+		return nil, err
+	}
+	if !manager {
+		own := []domain.MaintenanceRequest{}
+		for _, request := range requests {
+			if request.TenantID == actor.UserID {
+				own = append(own, request)
+			}
+		}
+		requests = own
+	}
+
+	if err := s.attachHistory(requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ChangeStatus moves a request to another status. The property's managers start and
+// resolve requests; the tenant cancels open requests and confirms or reopens resolved ones.
+func (s *MaintenanceRequestService) ChangeStatus(id, status, comment string, actor domain.Actor) (*domain.MaintenanceRequest, error) {
+	request, property, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	byTenant := false
+	if !canManageListing(property, actor) {
+		if request.TenantID != actor.UserID {
+			return nil, fmt.Errorf("permission denied: only the property's managers and tenant can update this request")
+		}
+		byTenant = true
+	}
+
+	update, err := request.ChangeStatus(status, comment, actor.UserID, byTenant, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateStatus(request, update); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.NotifyMaintenanceStatusChanged(request, update, property); err != nil {
+		s.logger.Printf("Error notifying maintenance request %s: %v", request.ID, err)
+	}
+
+	history, err := s.repo.ListUpdates(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	request.History = history
+	return request, nil
+}
+
+// AddPhoto attaches a photo of the problem, or of the fix, to an active request
+func (s *MaintenanceRequestService) AddPhoto(id, fileName string, content io.Reader, actor domain.Actor) (*domain.MaintenancePhoto, error) {
+	request, property, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.TenantID != actor.UserID && !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: cannot add photos to this maintenance request")
+	}
+	if !request.IsActive() {
+		return nil, fmt.Errorf("invalid request: %s requests cannot be changed", request.Status)
+	}
+	if len(request.Photos) >= domain.MaxMaintenancePhotos {
+		return nil, fmt.Errorf("maximum photos per request exceeded: %d", domain.MaxMaintenancePhotos)
+	}
+
+	// The declared size cannot be used to bypass the limit
+	data, err := io.ReadAll(io.LimitReader(content, domain.MaxMaintenancePhotoSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read photo: %w", err)
+	}
+	photo, err := domain.NewMaintenancePhoto(request.ID, data, actor.UserID, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	threat, err := scanDocument(s.scanner, data, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if threat != "" {
+		s.logger.Printf("Photo %q for maintenance request %s rejected: %s", fileName, request.ID, threat)
+		return nil, fmt.Errorf("upload validation failed: file is infected (%s)", threat)
+	}
+
+	storedPath, err := s.storage.Store(data, photo.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store photo: %w", err)
+	}
+	photo.StoragePath = storedPath
+
+	if err := s.repo.AddPhoto(request.ID, *photo); err != nil {
+		if delErr := s.storage.Delete(storedPath); delErr != nil {
+			s.logger.Printf("Error deleting photo file %s: %v", storedPath, delErr)
+		}
+		return nil, err
+	}
+
+	return photo, nil
+}
+
+// GetPhoto retrieves a photo and its content for the tenant or the property's managers
+func (s *MaintenanceRequestService) GetPhoto(id, photoID string, actor domain.Actor) (*domain.MaintenancePhoto, []byte, error) {
+	request, err := s.GetRequest(id, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	photo, ok := request.Photo(photoID)
+	if !ok {
+		return nil, nil, fmt.Errorf("photo not found: %s", photoID)
+	}
+
+	data, err := s.storage.Retrieve(photo.StoragePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve photo: %w", err)
+	}
+	return photo, data, nil
+}
+
+// isTenant reports whether the actor is the client of the rent deal of a rented property
+func (s *MaintenanceRequestService) isTenant(property *domain.Property, actor domain.Actor) (bool, error) {
+	if actor.UserID == "" || property.Status != domain.StatusRented {
+		return false, nil
+	}
+
+	deals, _, err := s.dealRepo.List(domain.DealFilter{PropertyID: property.ID, Type: domain.DealTypeRent, Limit: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(deals) > 0 && deals[0].ClientID != nil && *deals[0].ClientID == actor.UserID, nil
+}
+
+// attachHistory sets the history of each request in place
+func (s *MaintenanceRequestService) attachHistory(requests []domain.MaintenanceRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+	updates, err := s.repo.ListUpdates(ids...)
+	if err != nil {
+		return err
+	}
+
+	history := map[string][]domain.MaintenanceUpdate{}
+	for _, update := range updates {
+		history[update.RequestID] = append(history[update.RequestID], update)
+	}
+	for i := range requests {
+		requests[i].History = history[requests[i].ID]
+	}
+	return nil
+}
+
+// load retrieves a request and its property
+func (s *MaintenanceRequestService) load(id string) (*domain.MaintenanceRequest, *domain.Property, error) {
+	request, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	property, err := s.propertyRepo.GetByID(request.PropertyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("property not found: %w", err)
+	}
+	return request, property, nil
+}
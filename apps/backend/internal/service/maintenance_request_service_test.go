@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/storage"
+)
+
+// memoryMaintenanceRequests keeps maintenance requests and their history in memory
+type memoryMaintenanceRequests struct {
+	requests map[string]domain.MaintenanceRequest
+	updates  []domain.MaintenanceUpdate
+}
+
+func (m *memoryMaintenanceRequests) Create(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate) error {
+	m.requests[request.ID] = *request
+	m.updates = append(m.updates, *update)
+	return nil
+}
+
+func (m *memoryMaintenanceRequests) GetByID(id string) (*domain.MaintenanceRequest, error) {
+	request, ok := m.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("maintenance request not found: %s", id)
+	}
+	request.Photos = append([]domain.MaintenancePhoto{}, request.Photos...)
+	return &request, nil
+}
+
+func (m *memoryMaintenanceRequests) ListByProperty(propertyID, status string) ([]domain.MaintenanceRequest, error) {
+	return m.list(func(request domain.MaintenanceRequest) bool {
+		return request.PropertyID == propertyID && (status == "" || request.Status == status)
+	}), nil
+}
+
+func (m *memoryMaintenanceRequests) ListByTenant(tenantID string) ([]domain.MaintenanceRequest, error) {
+	return m.list(func(request domain.MaintenanceRequest) bool { return request.TenantID == tenantID }), nil
+}
+
+func (m *memoryMaintenanceRequests) UpdateStatus(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate) error {
+	stored, ok := m.requests[request.ID]
+	if !ok || stored.Status != update.FromStatus {
+		return fmt.Errorf("maintenance request no longer %s: %s", update.FromStatus, request.ID)
+	}
+	m.requests[request.ID] = *request
+	m.updates = append(m.updates, *update)
+	return nil
+}
+
+func (m *memoryMaintenanceRequests) AddPhoto(requestID string, photo domain.MaintenancePhoto) error {
+	stored, ok := m.requests[requestID]
+	if !ok {
+		return fmt.Errorf("maintenance request not found: %s", requestID)
+	}
+	stored.Photos = append(stored.Photos, photo)
+	m.requests[requestID] = stored
+	return nil
+}
+
+func (m *memoryMaintenanceRequests) ListUpdates(requestIDs ...string) ([]domain.MaintenanceUpdate, error) {
+	updates := []domain.MaintenanceUpdate{}
+	for _, update := range m.updates {
+		for _, id := range requestIDs {
+			if update.RequestID == id {
+				updates = append(updates, update)
+			}
+		}
+	}
+	return updates, nil
+}
+
+func (m *memoryMaintenanceRequests) list(match func(domain.MaintenanceRequest) bool) []domain.MaintenanceRequest {
+	requests := []domain.MaintenanceRequest{}
+	for _, request := range m.requests {
+		if match(request) {
+			requests = append(requests, request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].ID < requests[j].ID })
+	return requests
+}
+
+// recordingMaintenanceNotifier records the notified maintenance statuses
+type recordingMaintenanceNotifier struct {
+	events []string
+}
+
+func (n *recordingMaintenanceNotifier) NotifyMaintenanceOpened(request *domain.MaintenanceRequest, property *domain.Property) error {
+	n.events = append(n.events, "opened")
+	return nil
+}
+
+func (n *recordingMaintenanceNotifier) NotifyMaintenanceStatusChanged(request *domain.MaintenanceRequest, update *domain.MaintenanceUpdate, property *domain.Property) error {
+	n.events = append(n.events, request.Status)
+	return nil
+}
+
+func newTestMaintenanceService(t *testing.T) (*MaintenanceRequestService, *recordingMaintenanceNotifier) {
+	photoStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/maintenance", domain.MaxMaintenancePhotoSize)
+	require.NoError(t, err)
+
+	rented := domain.NewProperty("Departamento", "Departamento en La Carolina", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	rented.ID = "rented-1"
+	rented.Status = domain.StatusRented
+	available := domain.NewProperty("Casa", "Casa en Cumbayá", "Pichincha", "Quito", "house", 180000, "owner-1")
+	available.ID = "available-1"
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "rented-1").Return(rented, nil)
+	propertyRepo.On("GetByID", "available-1").Return(available, nil)
+
+	tenantID := "tenant-1"
+	deals := &memoryDealRepository{deals: []domain.Deal{
+		{ID: "deal-1", PropertyID: "rented-1", Type: domain.DealTypeRent, ClientID: &tenantID},
+	}}
+
+	notifier := &recordingMaintenanceNotifier{}
+	repo := &memoryMaintenanceRequests{requests: map[string]domain.MaintenanceRequest{}}
+	return NewMaintenanceRequestService(repo, propertyRepo, deals, photoStorage, notifier, log.New(&bytes.Buffer{}, "", 0)), notifier
+}
+
+func TestMaintenanceRequestService_Lifecycle(t *testing.T) {
+	svc, notifier := newTestMaintenanceService(t)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	tenant := domain.NewActor("tenant-1", string(domain.RoleBuyer), "")
+	stranger := domain.NewActor("buyer-2", string(domain.RoleBuyer), "")
+	req := OpenMaintenanceRequest{PropertyID: "rented-1", Category: "plumbing", Priority: "urgent", Title: "Fuga en el baño"}
+
+	_, err := svc.Open(req, stranger)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.Open(OpenMaintenanceRequest{PropertyID: "available-1", Category: "plumbing", Title: "Fuga"}, tenant)
+	assert.ErrorContains(t, err, "permission denied", "only rented properties have a tenant")
+
+	request, err := svc.Open(req, tenant)
+	require.NoError(t, err)
+	assert.Equal(t, domain.MaintenanceOpen, request.Status)
+
+	_, err = svc.GetRequest(request.ID, stranger)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.ChangeStatus(request.ID, domain.MaintenanceInProgress, "", stranger)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.ChangeStatus(request.ID, domain.MaintenanceResolved, "", tenant)
+	assert.ErrorContains(t, err, "invalid status transition")
+
+	_, err = svc.ChangeStatus(request.ID, domain.MaintenanceInProgress, "Plomero el lunes", owner)
+	require.NoError(t, err)
+	_, err = svc.ChangeStatus(request.ID, domain.MaintenanceResolved, "Tubería cambiada", owner)
+	require.NoError(t, err)
+	closed, err := svc.ChangeStatus(request.ID, domain.MaintenanceClosed, "", tenant)
+	require.NoError(t, err)
+	require.Len(t, closed.History, 4)
+	assert.Equal(t, "Tubería cambiada", closed.History[2].Comment)
+	assert.Equal(t, []string{"opened", "in_progress", "resolved", "closed"}, notifier.events)
+
+	history, err := svc.ListPropertyHistory("rented-1", "", owner)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Len(t, history[0].History, 4)
+	_, err = svc.ListPropertyHistory("rented-1", "", stranger)
+	assert.ErrorContains(t, err, "permission denied")
+
+	mine, err := svc.ListMyRequests(tenant)
+	require.NoError(t, err)
+	assert.Len(t, mine, 1)
+}
+
+func TestMaintenanceRequestService_Photos(t *testing.T) {
+	svc, _ := newTestMaintenanceService(t)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	tenant := domain.NewActor("tenant-1", string(domain.RoleBuyer), "")
+	stranger := domain.NewActor("buyer-2", string(domain.RoleBuyer), "")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	request, err := svc.Open(OpenMaintenanceRequest{PropertyID: "rented-1", Category: "pest", Title: "Termitas"}, tenant)
+	require.NoError(t, err)
+
+	_, err = svc.AddPhoto(request.ID, "termitas.png", bytes.NewReader(png), stranger)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.AddPhoto(request.ID, "factura.pdf", strings.NewReader("%PDF-1.4"), tenant)
+	assert.ErrorContains(t, err, "invalid photo")
+
+	photo, err := svc.AddPhoto(request.ID, "termitas.png", bytes.NewReader(png), tenant)
+	require.NoError(t, err)
+
+	stored, data, err := svc.GetPhoto(request.ID, photo.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", stored.ContentType)
+	assert.Equal(t, png, data)
+
+	_, err = svc.ChangeStatus(request.ID, domain.MaintenanceCancelled, "", tenant)
+	require.NoError(t, err)
+	_, err = svc.AddPhoto(request.ID, "termitas.png", bytes.NewReader(png), tenant)
+	assert.ErrorContains(t, err, "cannot be changed")
+}
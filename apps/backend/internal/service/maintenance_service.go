@@ -0,0 +1,186 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/security"
+)
+
+// AdminUserCreator creates user accounts, e.g. UserServiceSimple
+type AdminUserCreator interface {
+	CreateUser(firstName, lastName, email, phone, cedula, password string, role domain.UserRole) (*domain.User, error)
+}
+
+// MaintenanceService runs the data maintenance tasks of the admin CLI, so operators go
+// through the same validation as the API instead of editing the database by hand
+type MaintenanceService struct {
+	repo       repository.MaintenanceRepository
+	properties *PropertyService
+	users      AdminUserCreator
+	passwords  *security.PasswordValidator
+	logger     *log.Logger
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(
+	repo repository.MaintenanceRepository,
+	properties *PropertyService,
+	users AdminUserCreator,
+	logger *log.Logger,
+) *MaintenanceService {
+	return &MaintenanceService{
+		repo:       repo,
+		properties: properties,
+		users:      users,
+		passwords:  security.NewPasswordValidator(),
+		logger:     logger,
+	}
+}
+
+// CreateAdminUser creates an active administrator. Admin passwords must pass the
+// password strength policy.
+func (s *MaintenanceService) CreateAdminUser(firstName, lastName, email, phone, cedula, password string) (*domain.User, error) {
+	if ok, problems := s.passwords.ValidatePassword(password); !ok {
+		return nil, fmt.Errorf("invalid password: %s", strings.Join(problems, "; "))
+	}
+
+	user, err := s.users.CreateUser(firstName, lastName, email, phone, cedula, password, domain.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Admin user created from the admin CLI: %s", user.Email)
+	return user, nil
+}
+
+// ReindexSearch rebuilds the search index: the configured indexer's backend when there
+// is one, the PostgreSQL search vectors otherwise. It returns the properties reindexed.
+func (s *MaintenanceService) ReindexSearch() (int, error) {
+	if s.properties != nil && s.properties.indexer != nil {
+		return s.properties.ReindexAll()
+	}
+
+	count, err := s.repo.RefreshSearchVectors()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// RecalculateSlugs regenerates the slug of every property whose slug no longer matches
+// its title, e.g. after slug rules change
+func (s *MaintenanceService) RecalculateSlugs(dryRun bool) (*domain.SlugReport, error) {
+	slugs, err := s.repo.ListPropertySlugs()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.SlugReport{DryRun: dryRun, Scanned: len(slugs)}
+	for _, property := range slugs {
+		slug := domain.GenerateSlug(property.Title, property.ID)
+		if slug == property.Slug {
+			continue
+		}
+
+		if !dryRun {
+			if err := s.repo.UpdatePropertySlug(property.ID, slug); err != nil {
+				return report, err
+			}
+			s.logger.Printf("Property %s slug changed from %q to %q", property.ID, property.Slug, slug)
+		}
+		report.Updated++
+	}
+
+	if report.Updated > 0 && !dryRun {
+		s.invalidateProperties()
+	}
+	return report, nil
+}
+
+// SanitizeDescriptions sanitizes the descriptions stored before descriptions were
+// sanitized on write, or under older sanitization rules
+func (s *MaintenanceService) SanitizeDescriptions(dryRun bool) (*domain.DescriptionReport, error) {
+	descriptions, err := s.repo.ListPropertyDescriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DescriptionReport{DryRun: dryRun, Scanned: len(descriptions)}
+	for _, description := range descriptions {
+		property := domain.Property{
+			Description:     description.Description,
+			DescriptionHTML: description.DescriptionHTML,
+			DescriptionRaw:  description.DescriptionRaw,
+		}
+		property.NormalizeDescription()
+		if property.Description == description.Description && property.DescriptionHTML == description.DescriptionHTML &&
+			property.DescriptionRaw == description.DescriptionRaw {
+			continue
+		}
+
+		if !dryRun {
+			err := s.repo.UpdatePropertyDescription(domain.PropertyDescription{
+				ID:              description.ID,
+				Description:     property.Description,
+				DescriptionHTML: property.DescriptionHTML,
+				DescriptionRaw:  property.DescriptionRaw,
+			})
+			if err != nil {
+				return report, err
+			}
+		}
+		report.Updated++
+	}
+
+	if report.Updated > 0 && !dryRun {
+		s.logger.Printf("Sanitized the descriptions of %d properties", report.Updated)
+		s.invalidateProperties()
+	}
+	return report, nil
+}
+
+// BackfillPricePerM2 fills the price per m² of properties with an area and without one
+func (s *MaintenanceService) BackfillPricePerM2() (int64, error) {
+	count, err := s.repo.BackfillPricePerM2()
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		s.invalidateProperties()
+	}
+	return count, nil
+}
+
+// PurgeDeactivated permanently deletes users and agencies deactivated longer than
+// retention ago
+func (s *MaintenanceService) PurgeDeactivated(retention time.Duration, dryRun bool) (*domain.PurgeReport, error) {
+	if retention < 24*time.Hour {
+		return nil, fmt.Errorf("invalid retention: deactivated accounts must be kept at least 24h")
+	}
+
+	report := &domain.PurgeReport{DryRun: dryRun, DeactivatedBefore: time.Now().Add(-retention)}
+	users, agencies, err := s.repo.PurgeDeactivated(report.DeactivatedBefore, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	report.Users = users
+	report.Agencies = agencies
+
+	if !dryRun {
+		s.logger.Printf("Purged %d users and %d agencies deactivated before %s", users, agencies, report.DeactivatedBefore.Format(time.RFC3339))
+	}
+	return report, nil
+}
+
+// invalidateProperties drops cached properties changed in bulk
+func (s *MaintenanceService) invalidateProperties() {
+	if s.properties != nil {
+		s.properties.ClearCache()
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryMaintenanceRepository is an in-memory MaintenanceRepository
+type memoryMaintenanceRepository struct {
+	slugs        []domain.PropertySlug
+	descriptions []domain.PropertyDescription
+	purgeBefore  time.Time
+}
+
+func (r *memoryMaintenanceRepository) ListPropertySlugs() ([]domain.PropertySlug, error) {
+	return r.slugs, nil
+}
+
+func (r *memoryMaintenanceRepository) UpdatePropertySlug(propertyID, slug string) error {
+	for i := range r.slugs {
+		if r.slugs[i].ID == propertyID {
+			r.slugs[i].Slug = slug
+			return nil
+		}
+	}
+	return fmt.Errorf("property not found: %s", propertyID)
+}
+
+func (r *memoryMaintenanceRepository) ListPropertyDescriptions() ([]domain.PropertyDescription, error) {
+	return append([]domain.PropertyDescription(nil), r.descriptions...), nil
+}
+
+func (r *memoryMaintenanceRepository) UpdatePropertyDescription(description domain.PropertyDescription) error {
+	for i := range r.descriptions {
+		if r.descriptions[i].ID == description.ID {
+			r.descriptions[i] = description
+			return nil
+		}
+	}
+	return fmt.Errorf("property not found: %s", description.ID)
+}
+
+func (r *memoryMaintenanceRepository) BackfillPricePerM2() (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryMaintenanceRepository) RefreshSearchVectors() (int64, error) {
+	return int64(len(r.slugs)), nil
+}
+
+func (r *memoryMaintenanceRepository) PurgeDeactivated(before time.Time, dryRun bool) (int64, int64, error) {
+	r.purgeBefore = before
+	return 2, 1, nil
+}
+
+func TestMaintenanceService_SanitizeDescriptions(t *testing.T) {
+	repo := &memoryMaintenanceRepository{descriptions: []domain.PropertyDescription{
+		{ID: "clean", Description: "Casa amplia", DescriptionHTML: "<p>Casa amplia</p>", DescriptionRaw: "Casa amplia"},
+		{ID: "legacy", Description: `Casa <b>amplia</b><img src=x onerror="alert(1)">`},
+	}}
+	service := NewMaintenanceService(repo, nil, &recordingUserCreator{}, log.New(os.Stderr, "", 0))
+
+	report, err := service.SanitizeDescriptions(true)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.DescriptionReport{DryRun: true, Scanned: 2, Updated: 1}, report)
+	assert.Empty(t, repo.descriptions[1].DescriptionHTML, "a dry run changes nothing")
+
+	report, err = service.SanitizeDescriptions(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, domain.PropertyDescription{
+		ID:              "legacy",
+		Description:     "Casa amplia",
+		DescriptionHTML: "Casa <strong>amplia</strong>",
+		DescriptionRaw:  `Casa <b>amplia</b><img src=x onerror="alert(1)">`,
+	}, repo.descriptions[1])
+
+	report, err = service.SanitizeDescriptions(false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Updated, "sanitized descriptions are left alone")
+}
+
+// recordingUserCreator records the users it is asked to create
+type recordingUserCreator struct {
+	role domain.UserRole
+}
+
+func (c *recordingUserCreator) CreateUser(firstName, lastName, email, phone, cedula, password string, role domain.UserRole) (*domain.User, error) {
+	c.role = role
+	return domain.NewUser(email, firstName, lastName, role)
+}
+
+func TestMaintenanceService_RecalculateSlugs(t *testing.T) {
+	repo := &memoryMaintenanceRepository{slugs: []domain.PropertySlug{
+		{ID: "1a2b3c4d-0000", Title: "Casa en Cumbayá", Slug: domain.GenerateSlug("Casa en Cumbayá", "1a2b3c4d-0000")},
+		{ID: "5e6f7a8b-0000", Title: "Suite con vista al mar", Slug: "suite-antigua-5e6f7a8b"},
+	}}
+	service := NewMaintenanceService(repo, nil, &recordingUserCreator{}, log.New(os.Stderr, "", 0))
+
+	report, err := service.RecalculateSlugs(true)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.SlugReport{DryRun: true, Scanned: 2, Updated: 1}, report)
+	assert.Equal(t, "suite-antigua-5e6f7a8b", repo.slugs[1].Slug, "a dry run changes nothing")
+
+	report, err = service.RecalculateSlugs(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, "suite-con-vista-al-mar-5e6f7a8b", repo.slugs[1].Slug)
+
+	count, err := service.ReindexSearch()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "without an indexer the PostgreSQL search vectors are refreshed")
+}
+
+func TestMaintenanceService_CreateAdminUser(t *testing.T) {
+	users := &recordingUserCreator{}
+	service := NewMaintenanceService(&memoryMaintenanceRepository{}, nil, users, log.New(os.Stderr, "", 0))
+
+	_, err := service.CreateAdminUser("Ana", "Torres", "ana@inmo.ec", "0991234567", "1710034065", "password")
+	assert.ErrorContains(t, err, "invalid password")
+
+	user, err := service.CreateAdminUser("Ana", "Torres", "ana@inmo.ec", "0991234567", "1710034065", "Quito#2025-seguro")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, users.role)
+	assert.Equal(t, "ana@inmo.ec", user.Email)
+}
+
+func TestMaintenanceService_PurgeDeactivated(t *testing.T) {
+	repo := &memoryMaintenanceRepository{}
+	service := NewMaintenanceService(repo, nil, &recordingUserCreator{}, log.New(os.Stderr, "", 0))
+
+	_, err := service.PurgeDeactivated(time.Hour, false)
+	assert.ErrorContains(t, err, "invalid retention")
+
+	report, err := service.PurgeDeactivated(domain.DefaultPurgeRetention, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, int64(2), report.Users)
+	assert.Equal(t, int64(1), report.Agencies)
+	assert.WithinDuration(t, time.Now().Add(-domain.DefaultPurgeRetention), repo.purgeBefore, time.Minute)
+}
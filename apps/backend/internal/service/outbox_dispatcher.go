@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// OutboxHandler processes dispatched outbox events. Events are handed over at least
+// once, so handlers must ignore events they already processed, e.g. by event ID.
+type OutboxHandler interface {
+	HandleOutboxEvent(event *domain.OutboxEvent) error
+}
+
+// OutboxConfig configures the outbox dispatcher
+type OutboxConfig struct {
+	PollInterval time.Duration // time between runs dispatching pending events
+	BatchSize    int           // events dispatched per run
+	MaxAttempts  int           // attempts before an event is marked failed
+	Lease        time.Duration // how long a claimed event is hidden from other instances
+	Retention    time.Duration // how long published events are kept
+}
+
+// OutboxDispatcher publishes the events stored in the transactional outbox to the
+// registered handlers, such as webhooks, retrying failures with backoff. Instances
+// claim events with a lease, so each event is normally dispatched by one of them.
+type OutboxDispatcher struct {
+	repo     repository.OutboxRepository
+	handlers []OutboxHandler
+	config   OutboxConfig
+	logger   *log.Logger
+	now      func() time.Time
+
+	mu          sync.Mutex
+	stop        chan struct{}
+	done        chan struct{}
+	lastCleanup time.Time
+}
+
+// NewOutboxDispatcher creates a new outbox dispatcher
+func NewOutboxDispatcher(repo repository.OutboxRepository, config OutboxConfig, logger *log.Logger) *OutboxDispatcher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = domain.DefaultOutboxMaxAttempts
+	}
+	if config.Lease <= 0 {
+		config.Lease = 5 * time.Minute
+	}
+	if config.Retention <= 0 {
+		config.Retention = 7 * 24 * time.Hour
+	}
+
+	return &OutboxDispatcher{
+		repo:   repo,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// AddHandler registers a handler receiving every dispatched event
+func (d *OutboxDispatcher) AddHandler(handler OutboxHandler) {
+	d.handlers = append(d.handlers, handler)
+}
+
+// DispatchPending hands the pending events to the handlers and returns how many were
+// published. An event failing in any handler is retried for every handler.
+func (d *OutboxDispatcher) DispatchPending() (int, error) {
+	events, err := d.repo.ClaimPending(d.now(), d.config.Lease, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for i := range events {
+		event := &events[i]
+		if err := d.dispatch(event); err != nil {
+			event.RecordFailure(err, d.now(), d.config.MaxAttempts)
+			if event.Status == domain.OutboxFailed {
+				d.logger.Printf("Outbox event %s (%s) failed after %d attempts: %s",
+					event.ID, event.EventType, event.Attempts, event.LastError)
+			}
+		} else {
+			event.MarkPublished(d.now())
+			published++
+		}
+
+		if err := d.repo.Update(event); err != nil {
+			d.logger.Printf("Error saving outbox event %s: %v", event.ID, err)
+		}
+	}
+
+	return published, nil
+}
+
+// Cleanup deletes the published events older than the retention period
+func (d *OutboxDispatcher) Cleanup() (int64, error) {
+	return d.repo.DeletePublishedBefore(d.now().Add(-d.config.Retention))
+}
+
+// Start dispatches pending events on the poll interval until Stop is called, and
+// cleans up published events hourly
+func (d *OutboxDispatcher) Start() {
+	d.mu.Lock()
+	if d.stop != nil {
+		d.mu.Unlock()
+		return
+	}
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	stop, done := d.stop, d.done
+	d.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(d.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.run()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops dispatching events
+func (d *OutboxDispatcher) Stop() {
+	d.mu.Lock()
+	stop, done := d.stop, d.done
+	d.stop, d.done = nil, nil
+	d.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// run dispatches pending events, draining the backlog one batch after another
+func (d *OutboxDispatcher) run() {
+	for {
+		published, err := d.DispatchPending()
+		if err != nil {
+			d.logger.Printf("Error dispatching outbox events: %v", err)
+			return
+		}
+		if published < d.config.BatchSize {
+			break
+		}
+	}
+
+	if d.now().Sub(d.lastCleanup) >= time.Hour {
+		d.lastCleanup = d.now()
+		if deleted, err := d.Cleanup(); err != nil {
+			d.logger.Printf("Error cleaning up outbox events: %v", err)
+		} else if deleted > 0 {
+			d.logger.Printf("%d published outbox events cleaned up", deleted)
+		}
+	}
+}
+
+// dispatch hands an event to every handler, stopping at the first failure
+func (d *OutboxDispatcher) dispatch(event *domain.OutboxEvent) error {
+	for _, handler := range d.handlers {
+		if err := handler.HandleOutboxEvent(event); err != nil {
+			return fmt.Errorf("%T: %w", handler, err)
+		}
+	}
+	return nil
+}
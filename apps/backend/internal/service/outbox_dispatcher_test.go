@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryOutboxRepository keeps outbox events in memory
+type memoryOutboxRepository struct {
+	events []domain.OutboxEvent
+}
+
+func (r *memoryOutboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	claimed := []domain.OutboxEvent{}
+	for i := range r.events {
+		event := &r.events[i]
+		if event.Status == domain.OutboxPending && !event.AvailableAt.After(now) && len(claimed) < limit {
+			event.AvailableAt = now.Add(lease)
+			claimed = append(claimed, *event)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memoryOutboxRepository) Update(event *domain.OutboxEvent) error {
+	for i := range r.events {
+		if r.events[i].ID == event.ID {
+			r.events[i] = *event
+		}
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) DeletePublishedBefore(before time.Time) (int64, error) {
+	kept := r.events[:0]
+	var deleted int64
+	for _, event := range r.events {
+		if event.Status == domain.OutboxPublished && event.PublishedAt.Before(before) {
+			deleted++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	r.events = kept
+	return deleted, nil
+}
+
+// flakyOutboxHandler fails the first failures events it receives
+type flakyOutboxHandler struct {
+	failures int
+	handled  []string
+}
+
+func (h *flakyOutboxHandler) HandleOutboxEvent(event *domain.OutboxEvent) error {
+	if h.failures > 0 {
+		h.failures--
+		return fmt.Errorf("queue unavailable")
+	}
+	h.handled = append(h.handled, event.ID)
+	return nil
+}
+
+// outboxPropertyRepository records the events stored with property writes
+type outboxPropertyRepository struct {
+	*MockPropertyRepository
+	events []*domain.OutboxEvent
+}
+
+func (r *outboxPropertyRepository) CreateWithEvents(property *domain.Property, events ...*domain.OutboxEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *outboxPropertyRepository) UpdateWithEvents(property *domain.Property, events ...*domain.OutboxEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func TestOutboxDispatcher_DispatchPending(t *testing.T) {
+	repo := &memoryOutboxRepository{}
+	for _, eventType := range []string{domain.EventPropertyCreated, domain.EventPropertySold} {
+		event, err := domain.NewOutboxEvent(eventType, domain.AggregateProperty, "prop-1", "agency-1", map[string]string{"id": "prop-1"})
+		require.NoError(t, err)
+		repo.events = append(repo.events, *event)
+	}
+
+	dispatcher := NewOutboxDispatcher(repo, OutboxConfig{MaxAttempts: 2}, log.New(io.Discard, "", 0))
+	now := time.Now()
+	dispatcher.now = func() time.Time { return now }
+	handler := &flakyOutboxHandler{failures: 1}
+	dispatcher.AddHandler(handler)
+
+	published, err := dispatcher.DispatchPending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, domain.OutboxPending, repo.events[0].Status)
+	assert.Contains(t, repo.events[0].LastError, "queue unavailable")
+	assert.Equal(t, domain.OutboxPublished, repo.events[1].Status)
+
+	// The failed event is retried after the backoff
+	published, err = dispatcher.DispatchPending()
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	now = now.Add(time.Minute)
+	published, err = dispatcher.DispatchPending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{repo.events[1].ID, repo.events[0].ID}, handler.handled)
+
+	now = now.Add(8 * 24 * time.Hour)
+	deleted, err := dispatcher.Cleanup()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
+
+func TestWebhookService_HandleOutboxEvent(t *testing.T) {
+	webhooks := &memoryWebhookRepository{}
+	service := NewWebhookService(webhooks, WebhookConfig{}, log.New(io.Discard, "", 0))
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+	_, err := service.CreateSubscription(SaveWebhookRequest{URL: "https://crm.example.com", EventTypes: []string{domain.EventPropertyUpdated}}, admin)
+	require.NoError(t, err)
+
+	event, err := domain.NewOutboxEvent(domain.EventPropertyUpdated, domain.AggregateProperty, "prop-1", "", map[string]string{"id": "prop-1"})
+	require.NoError(t, err)
+
+	require.NoError(t, service.HandleOutboxEvent(event))
+	require.NoError(t, service.HandleOutboxEvent(event), "redispatched events are queued once")
+	require.Len(t, webhooks.deliveries, 1)
+	assert.Equal(t, event.ID, webhooks.deliveries[0].EventID)
+	assert.Contains(t, string(webhooks.deliveries[0].Payload), `"data":{"id":"prop-1"}`)
+
+	other, err := domain.NewOutboxEvent("property.viewed", domain.AggregateProperty, "prop-1", "", nil)
+	require.NoError(t, err)
+	assert.NoError(t, service.HandleOutboxEvent(other), "events webhooks do not carry are ignored")
+	assert.Len(t, webhooks.deliveries, 1)
+}
+
+func TestPropertyService_Outbox(t *testing.T) {
+	repo := &outboxPropertyRepository{MockPropertyRepository: &MockPropertyRepository{}}
+	service := NewPropertyService(repo, nil)
+	service.SetOutbox(repo)
+	publisher := &recordingEventPublisher{}
+	service.SetEventPublisher(publisher)
+
+	property, err := service.CreateProperty("Departamento en Cumbayá", "Departamento moderno con vista", "Pichincha", "Quito", "apartment", 180000, 1)
+	require.NoError(t, err)
+
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, domain.EventPropertyCreated, repo.events[0].EventType)
+	assert.Equal(t, property.ID, repo.events[0].AggregateID)
+	assert.Empty(t, publisher.published, "events stored in the outbox are not published inline")
+}
+
+// recordingEventPublisher records published event types
+type recordingEventPublisher struct {
+	published []string
+}
+
+func (p *recordingEventPublisher) Publish(eventType, agencyID string, data interface{}) error {
+	p.published = append(p.published, eventType)
+	return nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/sms"
+)
+
+// PhoneVerificationUsers is the part of the user repository phone verification uses
+type PhoneVerificationUsers interface {
+	GetByID(id string) (*domain.User, error)
+	Update(user *domain.User) error
+}
+
+// PhoneVerificationService verifies the phone numbers of agents and owners with codes
+// sent by SMS. Listings are only published for users with a verified phone.
+type PhoneVerificationService struct {
+	repo      repository.PhoneVerificationRepository
+	users     PhoneVerificationUsers
+	auditRepo repository.AuditRepository
+	sender    sms.Sender
+	now       func() time.Time
+	logger    *log.Logger
+}
+
+// NewPhoneVerificationService creates a phone verification service. Without a sender
+// codes cannot be requested.
+func NewPhoneVerificationService(
+	repo repository.PhoneVerificationRepository,
+	users PhoneVerificationUsers,
+	auditRepo repository.AuditRepository,
+	sender sms.Sender,
+	logger *log.Logger,
+) *PhoneVerificationService {
+	return &PhoneVerificationService{
+		repo:      repo,
+		users:     users,
+		auditRepo: auditRepo,
+		sender:    sender,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// RequestCode sends a verification code to the phone number of a user
+func (s *PhoneVerificationService) RequestCode(userID string) (*domain.PhoneVerification, error) {
+	if s.sender == nil {
+		return nil, fmt.Errorf("SMS provider is not configured")
+	}
+
+	user, err := s.verifiableUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsPhoneVerified() {
+		return nil, fmt.Errorf("phone already verified")
+	}
+
+	now := s.now()
+	if latest, err := s.repo.GetLatestByUser(user.ID); err == nil && !latest.CanResend(now) {
+		wait := latest.CreatedAt.Add(domain.PhoneVerificationResendInterval).Sub(now)
+		return nil, fmt.Errorf("too many requests: wait %d seconds before requesting a new code", int(wait.Seconds())+1)
+	}
+
+	verification, code, err := domain.NewPhoneVerification(user.ID, *user.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number: %w", err)
+	}
+	verification.CreatedAt = now
+	verification.ExpiresAt = now.Add(domain.PhoneVerificationTTL)
+
+	if err := s.repo.Create(verification); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Tu código de verificación es %s. Vence en %d minutos.", code, int(domain.PhoneVerificationTTL.Minutes()))
+	if err := s.sender.Send(verification.Phone, message); err != nil {
+		s.logger.Printf("Error sending phone verification %s with %s: %v", verification.ID, s.sender.Name(), err)
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	return verification, nil
+}
+
+// VerifyCode checks a code sent to a user and marks their phone as verified
+func (s *PhoneVerificationService) VerifyCode(userID, code string) (*domain.User, error) {
+	if strings.TrimSpace(code) == "" {
+		return nil, fmt.Errorf("invalid code: code is required")
+	}
+
+	user, err := s.verifiableUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	verification, err := s.repo.GetLatestByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if phone, err := domain.PhoneE164(*user.Phone); err != nil || phone != verification.Phone {
+		return nil, fmt.Errorf("verification code expired: the phone number changed, request a new code")
+	}
+
+	now := s.now()
+	verifyErr := verification.Verify(code, now)
+	if err := s.repo.Update(verification); err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	user.PhoneVerifiedAt = &now
+	user.UpdatedAt = now
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.audit(user, verification)
+	return user, nil
+}
+
+// IsPhoneVerified reports whether a user can publish listings: users that do not need
+// a verified phone always can
+func (s *PhoneVerificationService) IsPhoneVerified(userID string) (bool, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+	return !user.RequiresPhoneVerification() || user.IsPhoneVerified(), nil
+}
+
+// verifiableUser loads a user that has to verify a phone number
+func (s *PhoneVerificationService) verifiableUser(userID string) (*domain.User, error) {
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !user.RequiresPhoneVerification() {
+		return nil, fmt.Errorf("phone verification is only available to agents and owners")
+	}
+	if user.Phone == nil || strings.TrimSpace(*user.Phone) == "" {
+		return nil, fmt.Errorf("invalid phone number: add a phone number to your profile first")
+	}
+	return user, nil
+}
+
+// audit records a verified phone; failures are logged
+func (s *PhoneVerificationService) audit(user *domain.User, verification *domain.PhoneVerification) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	agencyID := ""
+	if user.AgencyID != nil {
+		agencyID = *user.AgencyID
+	}
+	entry := domain.NewAuditEntry(domain.NewActor(user.ID, string(user.Role), agencyID), domain.AuditActionPhoneVerified,
+		domain.AuditEntityUser, user.ID, user.AgencyID, map[string]interface{}{
+			"phone_verification_id": verification.ID,
+			"phone":                 verification.Phone,
+		})
+	if err := s.auditRepo.Create(entry); err != nil {
+		s.logger.Printf("Error recording audit entry %s for user %s: %v", domain.AuditActionPhoneVerified, user.ID, err)
+	}
+}
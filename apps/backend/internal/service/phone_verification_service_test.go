@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryPhoneVerificationRepository keeps verification codes in memory
+type memoryPhoneVerificationRepository struct {
+	verifications []*domain.PhoneVerification
+}
+
+func (r *memoryPhoneVerificationRepository) Create(verification *domain.PhoneVerification) error {
+	copied := *verification
+	r.verifications = append(r.verifications, &copied)
+	return nil
+}
+
+func (r *memoryPhoneVerificationRepository) GetLatestByUser(userID string) (*domain.PhoneVerification, error) {
+	for i := len(r.verifications) - 1; i >= 0; i-- {
+		if r.verifications[i].UserID == userID {
+			copied := *r.verifications[i]
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("phone verification not found: %s", userID)
+}
+
+func (r *memoryPhoneVerificationRepository) Update(verification *domain.PhoneVerification) error {
+	for i := range r.verifications {
+		if r.verifications[i].ID == verification.ID {
+			copied := *verification
+			r.verifications[i] = &copied
+			return nil
+		}
+	}
+	return fmt.Errorf("phone verification not found: %s", verification.ID)
+}
+
+// recordingSender keeps the messages it was asked to send
+type recordingSender struct {
+	to, bodies []string
+}
+
+func (s *recordingSender) Name() string { return "test" }
+
+func (s *recordingSender) Send(to, body string) error {
+	s.to = append(s.to, to)
+	s.bodies = append(s.bodies, body)
+	return nil
+}
+
+// sentCode extracts the code of the last message sent
+func (s *recordingSender) sentCode(t *testing.T) string {
+	require.NotEmpty(t, s.bodies)
+	code := regexp.MustCompile(`\d{6}`).FindString(s.bodies[len(s.bodies)-1])
+	require.NotEmpty(t, code)
+	return code
+}
+
+func newTestPhoneVerificationService(now *time.Time) (*PhoneVerificationService, *memoryEmailChangeUsers, *recordingSender, *fakeAuditRepository) {
+	phone := "099 123 4567"
+	users := &memoryEmailChangeUsers{users: map[string]*domain.User{
+		"owner-1": {ID: "owner-1", Role: domain.RoleOwner, Phone: &phone},
+		"buyer-1": {ID: "buyer-1", Role: domain.RoleBuyer, Phone: &phone},
+	}}
+	sender := &recordingSender{}
+	audit := &fakeAuditRepository{}
+	var logs bytes.Buffer
+	svc := NewPhoneVerificationService(&memoryPhoneVerificationRepository{}, users, audit, sender, log.New(&logs, "", 0))
+	svc.now = func() time.Time { return *now }
+	return svc, users, sender, audit
+}
+
+func TestPhoneVerificationService_RequestAndVerify(t *testing.T) {
+	now := time.Date(2025, 8, 27, 10, 0, 0, 0, time.UTC)
+	svc, users, sender, audit := newTestPhoneVerificationService(&now)
+
+	verified, err := svc.IsPhoneVerified("owner-1")
+	require.NoError(t, err)
+	assert.False(t, verified)
+
+	verification, err := svc.RequestCode("owner-1")
+	require.NoError(t, err)
+	assert.Equal(t, "+593991234567", verification.Phone)
+	assert.Equal(t, []string{"+593991234567"}, sender.to)
+
+	_, err = svc.RequestCode("owner-1")
+	assert.ErrorContains(t, err, "too many requests")
+
+	user, err := svc.VerifyCode("owner-1", sender.sentCode(t))
+	require.NoError(t, err)
+	require.NotNil(t, user.PhoneVerifiedAt)
+	assert.True(t, users.users["owner-1"].IsPhoneVerified())
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, domain.AuditActionPhoneVerified, audit.entries[0].Action)
+
+	verified, err = svc.IsPhoneVerified("owner-1")
+	require.NoError(t, err)
+	assert.True(t, verified)
+
+	_, err = svc.RequestCode("owner-1")
+	assert.ErrorContains(t, err, "already verified")
+}
+
+func TestPhoneVerificationService_Limits(t *testing.T) {
+	now := time.Date(2025, 8, 27, 10, 0, 0, 0, time.UTC)
+	svc, _, sender, _ := newTestPhoneVerificationService(&now)
+
+	_, err := svc.RequestCode("buyer-1")
+	assert.ErrorContains(t, err, "only available to agents and owners")
+	verified, err := svc.IsPhoneVerified("buyer-1")
+	require.NoError(t, err)
+	assert.True(t, verified, "buyers do not need a verified phone")
+
+	_, err = svc.RequestCode("owner-1")
+	require.NoError(t, err)
+	code := sender.sentCode(t)
+	wrong := "111111"
+	if code == wrong {
+		wrong = "222222"
+	}
+	for i := 0; i < domain.MaxPhoneVerificationAttempts; i++ {
+		_, err = svc.VerifyCode("owner-1", wrong)
+		assert.ErrorContains(t, err, "invalid verification code")
+	}
+	_, err = svc.VerifyCode("owner-1", code)
+	assert.ErrorContains(t, err, "too many attempts")
+
+	now = now.Add(domain.PhoneVerificationResendInterval)
+	_, err = svc.RequestCode("owner-1")
+	require.NoError(t, err)
+
+	now = now.Add(domain.PhoneVerificationTTL + time.Second)
+	_, err = svc.VerifyCode("owner-1", sender.sentCode(t))
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestPropertyService_RequiresVerifiedPublisher(t *testing.T) {
+	now := time.Date(2025, 8, 27, 10, 0, 0, 0, time.UTC)
+	verifier, _, sender, _ := newTestPhoneVerificationService(&now)
+
+	propertyRepo := &MockPropertyRepository{}
+	propertyRepo.On("Create", mock.Anything).Return(nil)
+	propertyService := NewPropertyService(propertyRepo, newEmptyImageRepository())
+	propertyService.SetPublisherVerifier(verifier)
+
+	ownerID := "owner-1"
+	req := CreatePropertyFullRequest{
+		Title: "Casa en Samborondón con piscina", Description: "Casa familiar", Price: 285000,
+		Type: "house", Province: "Guayas", City: "Samborondón", AreaM2: 320, OwnerID: &ownerID,
+	}
+
+	_, err := propertyService.CreatePropertyComplete(req)
+	assert.ErrorContains(t, err, "phone verification required")
+	propertyRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	_, err = verifier.RequestCode(ownerID)
+	require.NoError(t, err)
+	_, err = verifier.VerifyCode(ownerID, sender.sentCode(t))
+	require.NoError(t, err)
+
+	property, err := propertyService.CreatePropertyComplete(req)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusAvailable, property.Status)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/pii"
+	"realty-core/internal/repository"
+)
+
+// piiRotationBatchSize is how many values are re-encrypted per query
+const piiRotationBatchSize = 200
+
+// PIIRotationService re-encrypts PII columns with the active key. It encrypts the
+// plaintext written before encryption was enabled and rewrites values encrypted with
+// rotated keys, after which those keys can be removed from the key set.
+type PIIRotationService struct {
+	repo    repository.PIIRepository
+	cipher  *pii.Cipher
+	columns []pii.Column
+	logger  *log.Logger
+}
+
+// NewPIIRotationService creates a PII rotation service for the encrypted columns
+func NewPIIRotationService(repo repository.PIIRepository, cipher *pii.Cipher, logger *log.Logger) (*PIIRotationService, error) {
+	if cipher == nil {
+		return nil, fmt.Errorf("PII cipher is required")
+	}
+	return &PIIRotationService{
+		repo:    repo,
+		cipher:  cipher,
+		columns: pii.Columns,
+		logger:  logger,
+	}, nil
+}
+
+// ReEncrypt rewrites every value not encrypted with the active key and returns how
+// many were rewritten. Values that cannot be decrypted are logged and left as they
+// are; values changed meanwhile were already written with the active key.
+func (s *PIIRotationService) ReEncrypt() (int, error) {
+	rewritten := 0
+	for _, column := range s.columns {
+		count, err := s.reEncryptColumn(column)
+		rewritten += count
+		if err != nil {
+			return rewritten, err
+		}
+	}
+	return rewritten, nil
+}
+
+// reEncryptColumn re-encrypts the stale values of one column
+func (s *PIIRotationService) reEncryptColumn(column pii.Column) (int, error) {
+	rewritten := 0
+	afterID := ""
+	for {
+		values, err := s.repo.ListStale(column, s.cipher.ActivePrefix(), afterID, piiRotationBatchSize)
+		if err != nil {
+			return rewritten, err
+		}
+
+		for _, value := range values {
+			afterID = value.ID
+
+			plaintext, err := s.cipher.Decrypt(column, value.ID, value.Value)
+			if err != nil {
+				s.logger.Printf("Failed to decrypt %s of %s: %v", column.Name(), value.ID, err)
+				continue
+			}
+			encrypted, err := s.cipher.Encrypt(column, value.ID, plaintext)
+			if err != nil {
+				return rewritten, fmt.Errorf("failed to encrypt %s: %w", column.Name(), err)
+			}
+
+			index := ""
+			if column.IndexColumn != "" {
+				index = s.cipher.BlindIndex(plaintext)
+			}
+			ok, err := s.repo.Rewrite(column, value.ID, value.Value, encrypted, index)
+			if err != nil {
+				return rewritten, err
+			}
+			if ok {
+				rewritten++
+			}
+		}
+
+		if len(values) < piiRotationBatchSize {
+			return rewritten, nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/pii"
+)
+
+// memoryPIIRepository keeps column values in memory by column name and row ID
+type memoryPIIRepository struct {
+	values map[string]map[string]string
+	index  map[string]string
+}
+
+func (r *memoryPIIRepository) ListStale(column pii.Column, activePrefix, afterID string, limit int) ([]pii.Value, error) {
+	ids := []string{}
+	for id, value := range r.values[column.Name()] {
+		if value != "" && !strings.HasPrefix(value, activePrefix) && id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	values := []pii.Value{}
+	for _, id := range ids {
+		values = append(values, pii.Value{ID: id, Value: r.values[column.Name()][id]})
+	}
+	return values, nil
+}
+
+func (r *memoryPIIRepository) Rewrite(column pii.Column, id, oldValue, newValue, index string) (bool, error) {
+	if r.values[column.Name()][id] != oldValue {
+		return false, nil
+	}
+	r.values[column.Name()][id] = newValue
+	if column.IndexColumn != "" {
+		r.index[id] = index
+	}
+	return true, nil
+}
+
+func TestPIIRotationService_ReEncrypt(t *testing.T) {
+	const oldKey = "old-key-0123456789abcdef0123456789"
+	const newKey = "new-key-0123456789abcdef0123456789"
+
+	oldCipher, err := pii.NewCipher(pii.KeySet{ActiveKeyID: "2025-05", Keys: map[string]string{"2025-05": oldKey}, IndexKey: oldKey})
+	require.NoError(t, err)
+	oldPhone, err := oldCipher.Encrypt(pii.UserPhone, "user-1", "0987654321")
+	require.NoError(t, err)
+
+	cipher, err := pii.NewCipher(pii.KeySet{
+		ActiveKeyID: "2025-08",
+		Keys:        map[string]string{"2025-08": newKey, "2025-05": oldKey},
+		IndexKey:    oldKey,
+	})
+	require.NoError(t, err)
+	current, err := cipher.Encrypt(pii.UserPhone, "user-3", "0991234567")
+	require.NoError(t, err)
+
+	repo := &memoryPIIRepository{
+		values: map[string]map[string]string{
+			"users.phone":       {"user-1": oldPhone, "user-2": "0998887777", "user-3": current, "user-4": ""},
+			"users.national_id": {"user-1": "1712345678", "user-2": "enc:v2:retired:AAAA"},
+			"deals.notes":       {"deal-1": "Comprador: 0987654321"},
+		},
+		index: map[string]string{},
+	}
+	var logs bytes.Buffer
+	svc, err := NewPIIRotationService(repo, cipher, log.New(&logs, "", 0))
+	require.NoError(t, err)
+
+	rewritten, err := svc.ReEncrypt()
+	require.NoError(t, err)
+	assert.Equal(t, 4, rewritten)
+
+	for column, values := range repo.values {
+		for id, value := range values {
+			if column == "users.national_id" && id == "user-2" {
+				continue
+			}
+			assert.True(t, cipher.IsCurrent(value), "%s of %s", column, id)
+		}
+	}
+	assert.Equal(t, current, repo.values["users.phone"]["user-3"], "current values are left alone")
+
+	phone, err := cipher.Decrypt(pii.UserPhone, "user-1", repo.values["users.phone"]["user-1"])
+	require.NoError(t, err)
+	assert.Equal(t, "0987654321", phone)
+	assert.Equal(t, cipher.BlindIndex("1712345678"), repo.index["user-1"])
+
+	// Values encrypted with a key no longer in the set are logged and skipped
+	assert.Equal(t, "enc:v2:retired:AAAA", repo.values["users.national_id"]["user-2"])
+	assert.Contains(t, logs.String(), "unknown PII key retired")
+
+	rewritten, err = svc.ReEncrypt()
+	require.NoError(t, err)
+	assert.Equal(t, 0, rewritten)
+
+	_, err = NewPIIRotationService(repo, nil, log.New(&logs, "", 0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// PortalService serves the tenant and landlord portal: the leases a user holds as the
+// client of a rent deal or as the owner of the rented property, with their payments,
+// maintenance requests and documents. Each party only sees the properties they
+// contracted.
+type PortalService struct {
+	repo            repository.PortalRepository
+	maintenanceRepo repository.MaintenanceRequestRepository
+	documentRepo    repository.PropertyDocumentRepository
+	storage         storage.ImageStorage
+	ledger          repository.RentLedgerRepository
+	now             func() time.Time
+	logger          *log.Logger
+}
+
+// NewPortalService creates a portal service. Documents are read through the same
+// storage as property documents.
+func NewPortalService(
+	repo repository.PortalRepository,
+	maintenanceRepo repository.MaintenanceRequestRepository,
+	documentRepo repository.PropertyDocumentRepository,
+	storage storage.ImageStorage,
+	logger *log.Logger,
+) *PortalService {
+	return &PortalService{
+		repo:            repo,
+		maintenanceRepo: maintenanceRepo,
+		documentRepo:    documentRepo,
+		storage:         storage,
+		now:             time.Now,
+		logger:          logger,
+	}
+}
+
+// SetRentLedger lists the paid rent charges of leases as their payments and adds the
+// overdue rent to the dashboard
+func (s *PortalService) SetRentLedger(ledger repository.RentLedgerRepository) {
+	s.ledger = ledger
+}
+
+// Dashboard summarizes the actor's current leases, maintenance and documents
+func (s *PortalService) Dashboard(actor domain.Actor) (*domain.PortalDashboard, error) {
+	rentals, err := s.rentals(actor)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &domain.PortalDashboard{
+		UserID:         actor.UserID,
+		Roles:          domain.PortalRoles(rentals),
+		CurrentRentals: []domain.PortalRental{},
+	}
+	for _, rental := range rentals {
+		if !rental.Current {
+			dashboard.PastRentals++
+			continue
+		}
+		dashboard.CurrentRentals = append(dashboard.CurrentRentals, rental)
+		if rental.Role == domain.PortalTenant {
+			dashboard.MonthlyRentPaid += rental.MonthlyRent
+		} else {
+			dashboard.MonthlyRentCharged += rental.MonthlyRent
+		}
+	}
+
+	requests, err := s.maintenance(rentals, "", actor)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.IsActive() {
+			dashboard.ActiveMaintenance++
+			if request.Priority == domain.MaintenancePriorityUrgent {
+				dashboard.UrgentMaintenance++
+			}
+		}
+	}
+
+	documents, err := s.documents(rentals)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range documents {
+		dashboard.Documents += len(group.Documents)
+	}
+
+	charges, err := s.charges(dashboard.CurrentRentals)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	for _, charge := range charges {
+		if charge.IsOverdue(now) {
+			dashboard.RentOverdue += charge.Amount
+		}
+	}
+
+	return dashboard, nil
+}
+
+// ListRentals returns the actor's leases, newest first, optionally only those held in
+// a portal role
+func (s *PortalService) ListRentals(role string, actor domain.Actor) ([]domain.PortalRental, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role != "" && role != domain.PortalTenant && role != domain.PortalLandlord {
+		return nil, fmt.Errorf("invalid role: must be tenant or landlord")
+	}
+
+	rentals, err := s.rentals(actor)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return rentals, nil
+	}
+
+	filtered := []domain.PortalRental{}
+	for _, rental := range rentals {
+		if rental.Role == role {
+			filtered = append(filtered, rental)
+		}
+	}
+	return filtered, nil
+}
+
+// ListPayments returns the rent settled on the actor's leases, newest first: what
+// tenants paid and what landlords received. Leases without a rent ledger list their
+// rent deal, with the commission retained.
+func (s *PortalService) ListPayments(actor domain.Actor) ([]domain.PortalPayment, error) {
+	rentals, err := s.rentals(actor)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := s.charges(rentals)
+	if err != nil {
+		return nil, err
+	}
+	byDeal := map[string][]domain.RentCharge{}
+	for _, charge := range charges {
+		byDeal[charge.DealID] = append(byDeal[charge.DealID], charge)
+	}
+
+	payments := []domain.PortalPayment{}
+	for _, rental := range rentals {
+		ledger, ok := byDeal[rental.DealID]
+		if !ok {
+			payments = append(payments, domain.PortalPayment{
+				DealID:     rental.DealID,
+				PropertyID: rental.PropertyID,
+				Title:      rental.Title,
+				Role:       rental.Role,
+				Amount:     rental.MonthlyRent,
+				Commission: rental.Commission,
+				PaidAt:     rental.StartedAt,
+			})
+			continue
+		}
+		for _, charge := range ledger {
+			if !charge.IsSettled() || charge.PaidAmount == nil || charge.PaidAt == nil {
+				continue
+			}
+			period := charge.Period
+			payments = append(payments, domain.PortalPayment{
+				DealID:     rental.DealID,
+				ChargeID:   charge.ID,
+				PropertyID: rental.PropertyID,
+				Title:      rental.Title,
+				Role:       rental.Role,
+				Period:     &period,
+				Amount:     *charge.PaidAmount,
+				PaidAt:     *charge.PaidAt,
+			})
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].PaidAt.After(payments[j].PaidAt) })
+	return payments, nil
+}
+
+// ListMaintenance returns the maintenance requests the actor opened as tenant and those
+// of the properties they let, newest first, optionally by status
+func (s *PortalService) ListMaintenance(status string, actor domain.Actor) ([]domain.MaintenanceRequest, error) {
+	rentals, err := s.rentals(actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.maintenance(rentals, status, actor)
+}
+
+// ListDocuments returns the documents of the actor's leased properties, grouped by property
+func (s *PortalService) ListDocuments(actor domain.Actor) ([]domain.PortalDocuments, error) {
+	rentals, err := s.rentals(actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.documents(rentals)
+}
+
+// GetDocument retrieves a document of one of the actor's leased properties and its content
+func (s *PortalService) GetDocument(id string, actor domain.Actor) (*domain.PropertyDocument, []byte, error) {
+	rentals, err := s.rentals(actor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document, err := s.documentRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	covered := false
+	for _, rental := range rentals {
+		if rental.PropertyID == document.PropertyID && rental.CoversDocument(*document) {
+			covered = true
+		}
+	}
+	if !covered {
+		return nil, nil, fmt.Errorf("document not found: %s", id)
+	}
+
+	data, err := s.storage.Retrieve(document.StoragePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve document: %w", err)
+	}
+	return document, data, nil
+}
+
+// rentals returns the leases of the signed-in actor
+func (s *PortalService) rentals(actor domain.Actor) ([]domain.PortalRental, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to see your rentals")
+	}
+	return s.repo.ListRentals(actor.UserID)
+}
+
+// charges returns the rent charges of the leases, none without a rent ledger
+func (s *PortalService) charges(rentals []domain.PortalRental) ([]domain.RentCharge, error) {
+	if s.ledger == nil || len(rentals) == 0 {
+		return []domain.RentCharge{}, nil
+	}
+	dealIDs := make([]string, 0, len(rentals))
+	for _, rental := range rentals {
+		dealIDs = append(dealIDs, rental.DealID)
+	}
+	return s.ledger.ListByDeals(dealIDs...)
+}
+
+// maintenance returns the requests the actor opened and those of the properties they let
+func (s *PortalService) maintenance(rentals []domain.PortalRental, status string, actor domain.Actor) ([]domain.MaintenanceRequest, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+
+	requests, err := s.maintenanceRepo.ListByTenant(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, rental := range rentals {
+		if rental.Role != domain.PortalLandlord || seen[rental.PropertyID] {
+			continue
+		}
+		seen[rental.PropertyID] = true
+
+		let, err := s.maintenanceRepo.ListByProperty(rental.PropertyID, "")
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, let...)
+	}
+
+	filtered := []domain.MaintenanceRequest{}
+	for _, request := range requests {
+		if status == "" || request.Status == status {
+			filtered = append(filtered, request)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].CreatedAt.After(filtered[j].CreatedAt) })
+	return filtered, nil
+}
+
+// documents groups the documents each lease covers by property
+func (s *PortalService) documents(rentals []domain.PortalRental) ([]domain.PortalDocuments, error) {
+	groups := []domain.PortalDocuments{}
+	index := map[string]int{}
+	byProperty := map[string][]domain.PropertyDocument{}
+	listed := map[string]bool{}
+	for _, rental := range rentals {
+		i, ok := index[rental.PropertyID]
+		if !ok {
+			documents, err := s.documentRepo.GetByPropertyID(rental.PropertyID, false)
+			if err != nil {
+				return nil, err
+			}
+			byProperty[rental.PropertyID] = documents
+
+			i = len(groups)
+			index[rental.PropertyID] = i
+			groups = append(groups, domain.PortalDocuments{
+				PropertyID: rental.PropertyID,
+				Title:      rental.Title,
+				Role:       rental.Role,
+				Documents:  []domain.PropertyDocument{},
+			})
+		}
+
+		for _, document := range byProperty[rental.PropertyID] {
+			if listed[document.ID] || !rental.CoversDocument(document) {
+				continue
+			}
+			listed[document.ID] = true
+			groups[i].Documents = append(groups[i].Documents, document)
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/storage"
+)
+
+// memoryPortal returns fixed leases, keeping those of the requested user
+type memoryPortal struct {
+	rentals []domain.PortalRental
+}
+
+func (m *memoryPortal) ListRentals(userID string) ([]domain.PortalRental, error) {
+	rentals := []domain.PortalRental{}
+	for _, rental := range m.rentals {
+		switch {
+		case rental.OwnerID == userID:
+			rental.Role = domain.PortalLandlord
+		case rental.TenantID == userID:
+			rental.Role = domain.PortalTenant
+		default:
+			continue
+		}
+		rentals = append(rentals, rental)
+	}
+	return rentals, nil
+}
+
+func TestPortalService_ScopesEachParty(t *testing.T) {
+	documentStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/documents", domain.MaxDocumentUploadSize)
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	previous, ended := day(1), day(15)
+
+	portal := &memoryPortal{rentals: []domain.PortalRental{
+		{DealID: "deal-2", PropertyID: "prop-1", TenantID: "tenant-2", OwnerID: "owner-1", MonthlyRent: 850, StartedAt: day(15), PreviousDealAt: &previous, Current: true},
+		{DealID: "deal-1", PropertyID: "prop-1", TenantID: "tenant-1", OwnerID: "owner-1", MonthlyRent: 800, StartedAt: day(1), EndedAt: &ended},
+	}}
+	documents := &memoryDocumentRepository{documents: map[string]domain.PropertyDocument{}}
+	for id, document := range map[string]domain.PropertyDocument{
+		"old-contract": {Type: domain.DocumentTypeContract, Visibility: domain.DocumentVisibilityPrivate, CreatedAt: day(1)},
+		"new-contract": {Type: domain.DocumentTypeContract, Visibility: domain.DocumentVisibilityPrivate, CreatedAt: day(14)},
+		"deed":         {Type: domain.DocumentTypeDeed, Visibility: domain.DocumentVisibilityPrivate, CreatedAt: day(1)},
+	} {
+		document.ID, document.PropertyID = id, "prop-1"
+		documents.documents[id] = document
+	}
+	storedPath, err := documentStorage.Store([]byte("%PDF-1.4 contrato"), "documents/prop-1/new.pdf")
+	require.NoError(t, err)
+	contract := documents.documents["new-contract"]
+	contract.StoragePath = storedPath
+	documents.documents["new-contract"] = contract
+
+	tenantID := "tenant-2"
+	maintenance := &memoryMaintenanceRequests{requests: map[string]domain.MaintenanceRequest{
+		"req-1": {ID: "req-1", PropertyID: "prop-1", TenantID: tenantID, Status: domain.MaintenanceOpen, Priority: domain.MaintenancePriorityUrgent},
+		"req-2": {ID: "req-2", PropertyID: "prop-1", TenantID: "tenant-1", Status: domain.MaintenanceClosed},
+	}}
+
+	svc := NewPortalService(portal, maintenance, documents, documentStorage, log.New(&bytes.Buffer{}, "", 0))
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	tenant := domain.NewActor(tenantID, string(domain.RoleBuyer), "")
+	stranger := domain.NewActor("buyer-9", string(domain.RoleBuyer), "")
+
+	_, err = svc.Dashboard(domain.Actor{})
+	assert.ErrorContains(t, err, "permission denied")
+
+	dashboard, err := svc.Dashboard(tenant)
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.PortalTenant}, dashboard.Roles)
+	require.Len(t, dashboard.CurrentRentals, 1)
+	assert.Equal(t, 850.0, dashboard.MonthlyRentPaid)
+	assert.Equal(t, 1, dashboard.UrgentMaintenance)
+	assert.Equal(t, 1, dashboard.Documents, "only the contract of the current lease")
+
+	landlord, err := svc.Dashboard(owner)
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.PortalLandlord}, landlord.Roles)
+	assert.Equal(t, 1, landlord.PastRentals)
+	assert.Equal(t, 3, landlord.Documents)
+
+	requests, err := svc.ListMaintenance("", tenant)
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+	requests, err = svc.ListMaintenance("", owner)
+	require.NoError(t, err)
+	assert.Len(t, requests, 2)
+
+	payments, err := svc.ListPayments(tenant)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, "deal-2", payments[0].DealID)
+
+	_, data, err := svc.GetDocument("new-contract", tenant)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-1.4 contrato"), data)
+	_, _, err = svc.GetDocument("old-contract", tenant)
+	assert.ErrorContains(t, err, "not found")
+	_, _, err = svc.GetDocument("new-contract", stranger)
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = svc.ListRentals("agent", owner)
+	assert.ErrorContains(t, err, "invalid role")
+}
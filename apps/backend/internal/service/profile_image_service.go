@@ -0,0 +1,251 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/storage"
+)
+
+// ProfileImageUsers is the part of the user repository avatars use
+type ProfileImageUsers interface {
+	GetByID(id string) (*domain.User, error)
+	Update(user *domain.User) error
+}
+
+// ProfileImageAgencies is the part of the agency repository logos use
+type ProfileImageAgencies interface {
+	GetByID(id string) (*domain.Agency, error)
+	Update(agency *domain.Agency) error
+}
+
+// ProfileImageService stores user avatars and agency logos through the image pipeline:
+// uploads are validated and stripped of metadata like property images, then cropped
+// to squares in every size of domain.ProfileImageSizes.
+type ProfileImageService struct {
+	users     ProfileImageUsers
+	agencies  ProfileImageAgencies
+	storage   storage.ImageStorage
+	processor *processors.ImageProcessor
+	cdn       storage.CDN
+	now       func() time.Time
+	logger    *log.Logger
+}
+
+// NewProfileImageService creates a profile image service
+func NewProfileImageService(
+	users ProfileImageUsers,
+	agencies ProfileImageAgencies,
+	imageStorage storage.ImageStorage,
+	processor *processors.ImageProcessor,
+	logger *log.Logger,
+) *ProfileImageService {
+	return &ProfileImageService{
+		users:     users,
+		agencies:  agencies,
+		storage:   imageStorage,
+		processor: processor,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// SetCDN purges replaced avatars and logos from the CDN
+func (s *ProfileImageService) SetCDN(cdn storage.CDN) {
+	s.cdn = cdn
+}
+
+// UploadAvatar replaces the avatar of a user. Users change their own avatar and
+// admins any.
+func (s *ProfileImageService) UploadAvatar(userID string, data []byte, actor domain.Actor) (*domain.ProfileImage, error) {
+	if actor.UserID != userID && actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: users can only change their own avatar")
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	image, paths, err := s.store(domain.ProfileImageAvatar, user.ID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := domain.ProfileImageVariants(user.AvatarURL)
+	user.SetAvatar(image.URL)
+	if err := s.users.Update(user); err != nil {
+		s.deletePaths(paths)
+		return nil, err
+	}
+
+	s.deleteVariants(previous)
+	return image, nil
+}
+
+// DeleteAvatar removes the avatar of a user
+func (s *ProfileImageService) DeleteAvatar(userID string, actor domain.Actor) error {
+	if actor.UserID != userID && actor.Role != domain.RoleAdmin {
+		return fmt.Errorf("permission denied: users can only change their own avatar")
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if user.AvatarURL == nil {
+		return nil
+	}
+
+	previous := domain.ProfileImageVariants(user.AvatarURL)
+	user.SetAvatar("")
+	if err := s.users.Update(user); err != nil {
+		return err
+	}
+
+	s.deleteVariants(previous)
+	return nil
+}
+
+// UploadAgencyLogo replaces the logo of an agency. Agency accounts change their own
+// logo and admins any.
+func (s *ProfileImageService) UploadAgencyLogo(agencyID string, data []byte, actor domain.Actor) (*domain.ProfileImage, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only the agency can change its logo")
+	}
+
+	agency, err := s.agencies.GetByID(agencyID)
+	if err != nil {
+		return nil, fmt.Errorf("agency not found: %w", err)
+	}
+
+	image, paths, err := s.store(domain.ProfileImageLogo, agency.ID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := domain.ProfileImageVariants(agency.LogoURL)
+	agency.SetLogo(image.URL)
+	if err := s.agencies.Update(agency); err != nil {
+		s.deletePaths(paths)
+		return nil, err
+	}
+
+	s.deleteVariants(previous)
+	return image, nil
+}
+
+// DeleteAgencyLogo removes the logo of an agency
+func (s *ProfileImageService) DeleteAgencyLogo(agencyID string, actor domain.Actor) error {
+	if !actor.CanAdministerAgency(agencyID) {
+		return fmt.Errorf("permission denied: only the agency can change its logo")
+	}
+
+	agency, err := s.agencies.GetByID(agencyID)
+	if err != nil {
+		return fmt.Errorf("agency not found: %w", err)
+	}
+	if agency.LogoURL == nil {
+		return nil
+	}
+
+	previous := domain.ProfileImageVariants(agency.LogoURL)
+	agency.SetLogo("")
+	if err := s.agencies.Update(agency); err != nil {
+		return err
+	}
+
+	s.deleteVariants(previous)
+	return nil
+}
+
+// store validates an upload and stores its square variants under a new version. It
+// returns the image with the storage paths of its files.
+func (s *ProfileImageService) store(kind, ownerID string, data []byte) (*domain.ProfileImage, []string, error) {
+	if int64(len(data)) > domain.MaxProfileImageUploadSize {
+		return nil, nil, fmt.Errorf("invalid image: %d bytes exceeds the %d bytes limit", len(data), domain.MaxProfileImageUploadSize)
+	}
+	if err := s.processor.ValidateImageData(data, domain.MaxProfileImageUploadSize); err != nil {
+		return nil, nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	data, _, err := s.processor.ScrubMetadata(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid image: failed to strip metadata: %w", err)
+	}
+	width, height, _, err := s.processor.GetImageDimensions(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if width < domain.MinProfileImageDimension || height < domain.MinProfileImageDimension {
+		return nil, nil, fmt.Errorf("invalid image: must be at least %dx%d pixels", domain.MinProfileImageDimension, domain.MinProfileImageDimension)
+	}
+
+	format := "jpg"
+	if kind == domain.ProfileImageLogo {
+		format = "png"
+	}
+
+	image := &domain.ProfileImage{
+		Kind:      kind,
+		OwnerID:   ownerID,
+		Version:   domain.NewProfileImageVersion(),
+		Variants:  make(map[string]string, len(domain.ProfileImageSizes)),
+		UpdatedAt: s.now(),
+	}
+	paths := make([]string, 0, len(domain.ProfileImageSizes))
+	for _, size := range domain.ProfileImageSizes {
+		variant, err := s.processor.GenerateSquareVariant(data, size, domain.DefaultQuality, format)
+		if err != nil {
+			s.deletePaths(paths)
+			return nil, nil, fmt.Errorf("failed to generate %dpx %s: %w", size, kind, err)
+		}
+
+		path, err := s.storage.Store(variant, domain.ProfileImageFileName(kind, ownerID, image.Version, size))
+		if err != nil {
+			s.deletePaths(paths)
+			return nil, nil, fmt.Errorf("failed to store %s: %w", kind, err)
+		}
+		paths = append(paths, path)
+		image.Variants[strconv.Itoa(size)] = s.storage.GetURL(path)
+		image.URL = s.storage.GetURL(path)
+	}
+
+	return image, paths, nil
+}
+
+// deleteVariants deletes the files of a replaced image and purges them from the CDN;
+// failures are logged since the new image is already saved
+func (s *ProfileImageService) deleteVariants(variants map[string]string) {
+	if len(variants) == 0 {
+		return
+	}
+
+	info := s.storage.GetStorageInfo()
+	paths := make([]string, 0, len(variants))
+	for _, url := range variants {
+		if path := storagePathFromURL(info, url); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	s.deletePaths(paths)
+
+	if s.cdn != nil && len(paths) > 0 {
+		if err := s.cdn.Purge(paths); err != nil {
+			s.logger.Printf("Warning: failed to purge %s CDN for %v: %v", s.cdn.Name(), paths, err)
+		}
+	}
+}
+
+// deletePaths deletes stored files, logging failures
+func (s *ProfileImageService) deletePaths(paths []string) {
+	for _, path := range paths {
+		if err := s.storage.Delete(path); err != nil {
+			s.logger.Printf("Warning: failed to delete profile image %s: %v", path, err)
+		}
+	}
+}
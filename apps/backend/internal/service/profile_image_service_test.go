@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/storage"
+)
+
+// memoryProfileImageAgencies keeps agencies in memory by ID
+type memoryProfileImageAgencies struct {
+	agencies map[string]*domain.Agency
+}
+
+func (a *memoryProfileImageAgencies) GetByID(id string) (*domain.Agency, error) {
+	if agency, ok := a.agencies[id]; ok {
+		copied := *agency
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("agency not found with id: %s", id)
+}
+
+func (a *memoryProfileImageAgencies) Update(agency *domain.Agency) error {
+	copied := *agency
+	a.agencies[agency.ID] = &copied
+	return nil
+}
+
+// testJPEG encodes a solid image of the given size
+func testJPEG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 120, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}))
+	return buf.Bytes()
+}
+
+func newTestProfileImageService(t *testing.T) (*ProfileImageService, *memoryEmailChangeUsers, *memoryProfileImageAgencies, string, *recordingCDN) {
+	basePath := t.TempDir()
+	imageStorage, err := storage.NewLocalImageStorage(basePath, "/uploads/images", 0)
+	require.NoError(t, err)
+
+	users := &memoryEmailChangeUsers{users: map[string]*domain.User{
+		"user-1": {ID: "user-1", Role: domain.RoleBuyer},
+	}}
+	agencies := &memoryProfileImageAgencies{agencies: map[string]*domain.Agency{
+		"agency-1": {ID: "agency-1", Name: "Inmobiliaria Sierra"},
+	}}
+	cdn := &recordingCDN{}
+	var logs bytes.Buffer
+	svc := NewProfileImageService(users, agencies, imageStorage, processors.NewImageProcessor(0, 0), log.New(&logs, "", 0))
+	svc.SetCDN(cdn)
+	return svc, users, agencies, basePath, cdn
+}
+
+func TestProfileImageService_UploadAvatar(t *testing.T) {
+	svc, users, _, basePath, cdn := newTestProfileImageService(t)
+	owner := domain.NewActor("user-1", string(domain.RoleBuyer), "")
+
+	first, err := svc.UploadAvatar("user-1", testJPEG(t, 600, 400), owner)
+	require.NoError(t, err)
+	require.Len(t, first.Variants, len(domain.ProfileImageSizes))
+	assert.Equal(t, first.Variants["512"], first.URL)
+	assert.Equal(t, first.URL, *users.users["user-1"].AvatarURL)
+	assert.Equal(t, first.Variants, users.users["user-1"].AvatarVariants)
+	assert.FileExists(t, filepath.Join(basePath, "originals", "avatars", "user-1", first.Version+"_64.jpg"))
+
+	// Replacing the avatar changes its URLs and removes the previous files
+	second, err := svc.UploadAvatar("user-1", testJPEG(t, 300, 300), owner)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.URL, second.URL)
+	assert.NoFileExists(t, filepath.Join(basePath, "originals", "avatars", "user-1", first.Version+"_64.jpg"))
+	assert.Len(t, cdn.purged, len(domain.ProfileImageSizes))
+
+	_, err = svc.UploadAvatar("user-1", testJPEG(t, 300, 300), domain.NewActor("user-2", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = svc.UploadAvatar("user-1", testJPEG(t, 100, 100), owner)
+	assert.ErrorContains(t, err, "at least 128x128")
+
+	require.NoError(t, svc.DeleteAvatar("user-1", owner))
+	assert.Nil(t, users.users["user-1"].AvatarURL)
+	assert.NoFileExists(t, filepath.Join(basePath, "originals", "avatars", "user-1", second.Version+"_512.jpg"))
+}
+
+func TestProfileImageService_UploadAgencyLogo(t *testing.T) {
+	svc, _, agencies, basePath, _ := newTestProfileImageService(t)
+
+	_, err := svc.UploadAgencyLogo("agency-1", testJPEG(t, 400, 400), domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	logo, err := svc.UploadAgencyLogo("agency-1", testJPEG(t, 400, 400), domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1"))
+	require.NoError(t, err)
+	assert.Equal(t, logo.URL, *agencies.agencies["agency-1"].LogoURL)
+	assert.Equal(t, logo.Variants, agencies.agencies["agency-1"].LogoVariants)
+	assert.FileExists(t, filepath.Join(basePath, "originals", "logos", "agency-1", logo.Version+"_256.png"))
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"realty-core/internal/cache"
 	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
 	"realty-core/internal/repository"
+	"realty-core/internal/search"
 )
 
 // CreatePropertyFullRequest represents a complete property creation request
@@ -69,11 +72,16 @@ type CreatePropertyFullRequest struct {
 	OwnerID             *string `json:"owner_id,omitempty"`
 	AgentID             *string `json:"agent_id,omitempty"`
 	AgencyID            *string `json:"agency_id,omitempty"`
+	// TenantID is the tenant the request was resolved to, empty in single-tenant installs
+	TenantID            string  `json:"-"`
 	
 	// Contact Information (temporary until user system)
 	ContactPhone  string `json:"contact_phone"`
 	ContactEmail  string `json:"contact_email"`
 	Notes         string `json:"notes,omitempty"`
+
+	// Website is a honeypot: the form hides it from people, so only bots fill it in
+	Website string `json:"website,omitempty"`
 }
 
 // PropertyServiceInterface defines the business logic operations for properties
@@ -84,9 +92,12 @@ type PropertyServiceInterface interface {
 	GetPropertyBySlug(slug string) (*domain.Property, error)
 	ListProperties() ([]domain.Property, error)
 	UpdateProperty(id, title, description, province, city, propertyType string, price float64) (*domain.Property, error)
+	UpdatePropertyBy(changedBy, id, title, description, province, city, propertyType string, price float64) (*domain.Property, error)
 	DeleteProperty(id string) error
 	FilterByProvince(province string) ([]domain.Property, error)
 	FilterByPriceRange(minPrice, maxPrice float64) ([]domain.Property, error)
+	FilterProperties(filters *domain.PropertySearchFilters) ([]domain.Property, error)
+	StreamProperties(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error
 	GetStatistics() (map[string]interface{}, error)
 	SetPropertyLocation(id string, latitude, longitude float64, precision string) error
 	SetPropertyFeatured(id string, featured bool) error
@@ -101,16 +112,67 @@ type PropertyServiceInterface interface {
 	ListPropertiesPaginated(pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
 	FilterByProvincePaginated(province string, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
 	FilterByPriceRangePaginated(minPrice, maxPrice float64, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
+	FilterPropertiesPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
+	ListManagedProperties(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams, actor domain.Actor) (*domain.PaginatedResponse, error)
 	SearchPropertiesPaginated(query string, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
 	SearchPropertiesRankedPaginated(query string, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
 	AdvancedSearchPaginated(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error)
+	AdvancedSearchFaceted(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) (*domain.FacetedSearchResponse, error)
+	// ForTenant returns the service scoped to a tenant, unscoped when it is empty
+	ForTenant(tenantID string) PropertyServiceInterface
 }
 
 // PropertyService handles business logic for properties
 type PropertyService struct {
-	repo      repository.PropertyRepository
-	imageRepo repository.ImageRepository
-	cache     *cache.PropertyCache
+	repo         repository.PropertyRepository
+	imageRepo    repository.ImageRepository
+	cache        *cache.PropertyCache
+	indexer      *search.Indexer
+	tenantScoped bool // set by ForTenant, searches the tenant's rows in PostgreSQL
+	queryProc    *search.QueryPreprocessor
+	suggester    *search.SuggestionEngine
+	limiter      ListingLimiter
+	events       EventPublisher
+	outbox       PropertyOutboxWriter
+	versions     PropertyVersionRecorder
+	spam         *SpamService
+	descriptions *DescriptionPolicyService
+	publishers   PublisherVerifier
+	risks        RiskAssessor
+	translator   ListingTranslator
+}
+
+// PropertyOutboxWriter saves property changes together with their outbox events
+type PropertyOutboxWriter interface {
+	CreateWithEvents(property *domain.Property, events ...*domain.OutboxEvent) error
+	UpdateWithEvents(property *domain.Property, events ...*domain.OutboxEvent) error
+}
+
+// PropertyVersionRecorder records a version of a property after each edit. A nil
+// before records the creation of the property.
+type PropertyVersionRecorder interface {
+	RecordVersion(before, after *domain.Property, changedBy string)
+}
+
+// ListingLimiter enforces the listing limits of agency subscription plans
+type ListingLimiter interface {
+	CheckListingLimit(agencyID string) error
+	CheckFeaturedLimit(agencyID string) error
+}
+
+// RiskAssessor flags the hazard zones a property lies in
+type RiskAssessor interface {
+	AssessProperty(property *domain.Property) error
+}
+
+// ListingTranslator machine-translates the content of published listings
+type ListingTranslator interface {
+	TranslateListing(property domain.Property)
+}
+
+// PublisherVerifier checks that the users publishing a listing verified their phone
+type PublisherVerifier interface {
+	IsPhoneVerified(userID string) (bool, error)
 }
 
 // NewPropertyService creates a new instance of the service
@@ -141,6 +203,327 @@ func NewPropertyServiceWithCache(repo repository.PropertyRepository, imageRepo r
 	}
 }
 
+// SetSearchIndexer routes ranked and advanced search through the indexer's backend
+// and keeps that backend in sync on every property write
+func (s *PropertyService) SetSearchIndexer(indexer *search.Indexer) {
+	s.indexer = indexer
+}
+
+// searchIndex returns the configured search backend, defaulting to PostgreSQL FTS.
+// Services scoped to a tenant search with PostgreSQL FTS too, since the backend
+// indexes the listings of every tenant.
+func (s *PropertyService) searchIndex() search.Index {
+	if s.indexer != nil && !s.tenantScoped {
+		return s.indexer.Index()
+	}
+	return search.NewPostgresIndex(s.repo)
+}
+
+// SetQueryPreprocessor applies the synonym and stop-word dictionary to search queries
+func (s *PropertyService) SetQueryPreprocessor(preprocessor *search.QueryPreprocessor) {
+	s.queryProc = preprocessor
+}
+
+// preprocessQuery rewrites a validated search query with the configured dictionary
+func (s *PropertyService) preprocessQuery(query string) string {
+	if s.queryProc == nil || query == "" {
+		return query
+	}
+	return s.queryProc.Process(query)
+}
+
+// SetSuggestionEngine blends popular queries, locations and agencies into autocomplete
+// suggestions and records searched queries for popularity
+func (s *PropertyService) SetSuggestionEngine(engine *search.SuggestionEngine) {
+	s.suggester = engine
+}
+
+// SetListingLimiter enforces agency plan limits when listings are published or featured
+func (s *PropertyService) SetListingLimiter(limiter ListingLimiter) {
+	s.limiter = limiter
+}
+
+// SetEventPublisher publishes property.created and property.updated events to
+// integrations such as webhooks
+func (s *PropertyService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
+// SetOutbox records property.created and property.updated in the transactional
+// outbox instead of publishing them after the write, so they survive crashes
+func (s *PropertyService) SetOutbox(outbox PropertyOutboxWriter) {
+	s.outbox = outbox
+}
+
+// ForTenant returns the service scoped to a tenant: it reads and writes the properties
+// of the tenant only and caches them apart from those of other tenants. Searches use
+// the tenant's PostgreSQL FTS rather than the search backend, which spans every tenant,
+// and suggestions come from the tenant's engine. Writes still keep the backend in sync.
+// An empty tenant, as in single-tenant installs, returns the service itself.
+func (s *PropertyService) ForTenant(tenantID string) PropertyServiceInterface {
+	if tenantID == "" {
+		return s
+	}
+
+	scoped := *s
+	scoped.repo = s.repo.ForTenant(tenantID)
+	if s.outbox != nil {
+		outbox, _ := scoped.repo.(PropertyOutboxWriter)
+		scoped.outbox = outbox
+	}
+	if s.cache != nil {
+		scoped.cache = s.cache.ForTenant(tenantID)
+	}
+	scoped.tenantScoped = true
+	if s.suggester != nil {
+		scoped.suggester = s.suggester.ForTenant(tenantID)
+	}
+	return &scoped
+}
+
+// SetVersionRecorder records a version with a field diff on every create and edit,
+// typically the PropertyHistoryService
+func (s *PropertyService) SetVersionRecorder(recorder PropertyVersionRecorder) {
+	s.versions = recorder
+}
+
+// SetPublisherVerifier requires the owner and agent of published listings to have a
+// verified phone
+func (s *PropertyService) SetPublisherVerifier(publishers PublisherVerifier) {
+	s.publishers = publishers
+}
+
+// SetRiskAssessor flags the hazard zones of properties when they are created, edited
+// or located, typically the RiskZoneService
+func (s *PropertyService) SetRiskAssessor(risks RiskAssessor) {
+	s.risks = risks
+}
+
+// assessRisk refreshes the risk flags of a property; failures are logged so hazard
+// layers never block listing writes
+func (s *PropertyService) assessRisk(property *domain.Property) {
+	if s.risks == nil {
+		return
+	}
+	if err := s.risks.AssessProperty(property); err != nil {
+		log.Printf("Error assessing risk zones of property %s: %v", property.ID, err)
+	}
+}
+
+// SetListingTranslator machine-translates listings when they are published or their
+// Spanish content changes, typically the PropertyTranslationService
+func (s *PropertyService) SetListingTranslator(translator ListingTranslator) {
+	s.translator = translator
+}
+
+// translateListing machine-translates a saved listing in the background, so
+// translation providers never slow down listing writes
+func (s *PropertyService) translateListing(property *domain.Property) {
+	if s.translator == nil || property.Status != domain.StatusAvailable {
+		return
+	}
+	go s.translator.TranslateListing(*property)
+}
+
+// checkPublishers rejects publishing a listing whose owner or agent has not verified
+// their phone
+func (s *PropertyService) checkPublishers(property *domain.Property) error {
+	if s.publishers == nil || property.Status != domain.StatusAvailable {
+		return nil
+	}
+
+	for _, userID := range []*string{property.OwnerID, property.AgentID} {
+		if userID == nil || *userID == "" {
+			continue
+		}
+		verified, err := s.publishers.IsPhoneVerified(*userID)
+		if err != nil {
+			return fmt.Errorf("error checking phone verification: %w", err)
+		}
+		if !verified {
+			return fmt.Errorf("permission denied: phone verification required to publish listings")
+		}
+	}
+	return nil
+}
+
+// SetSpamService screens created listings for spam. Quarantined listings are hidden
+// from public search until an administrator approves them.
+func (s *PropertyService) SetSpamService(spam *SpamService) {
+	s.spam = spam
+	spam.SetReleaser(domain.SpamEntityListing, s)
+}
+
+// SetDescriptionPolicy enforces the description policy on the contact information and
+// profanity of created and edited listings
+func (s *PropertyService) SetDescriptionPolicy(descriptions *DescriptionPolicyService) {
+	s.descriptions = descriptions
+	descriptions.SetWriter(s)
+}
+
+// screenDescription applies the description policy to a listing about to be saved
+func (s *PropertyService) screenDescription(property *domain.Property) (*domain.DescriptionReview, error) {
+	if s.descriptions == nil {
+		return nil, nil
+	}
+	return s.descriptions.Screen(property)
+}
+
+// recordDescriptionReview queues the review of a saved listing's description. New
+// listings without review have no pending review to drop.
+func (s *PropertyService) recordDescriptionReview(property *domain.Property, review *domain.DescriptionReview, created bool) {
+	if s.descriptions == nil || (created && review == nil) {
+		return
+	}
+	s.descriptions.Record(property.ID, review)
+}
+
+// RewriteDescription replaces the description of a listing as decided by an
+// administrator reviewing it, without the description policy
+func (s *PropertyService) RewriteDescription(propertyID, description, changedBy string) error {
+	property, err := s.repo.GetByID(propertyID)
+	if err != nil {
+		return err
+	}
+
+	before := *property
+	property.Description = description
+	if err := s.saveEdit(property); err != nil {
+		return fmt.Errorf("error updating property: %w", err)
+	}
+	s.recordVersion(&before, property, changedBy)
+	return nil
+}
+
+// ReleaseQuarantined publishes a listing approved by spam review. Blocked listings
+// stay quarantined.
+func (s *PropertyService) ReleaseQuarantined(id string, approved bool) error {
+	if !approved {
+		return nil
+	}
+
+	property, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if property.Status != domain.StatusQuarantined {
+		return nil
+	}
+	property.Status = domain.StatusAvailable
+	return s.saveEdit(property)
+}
+
+// createProperty inserts a property, with its property.created event when the outbox is
+// set, and counts it for the new listings anomaly alert
+func (s *PropertyService) createProperty(property *domain.Property) error {
+	if err := s.insertProperty(property); err != nil {
+		return err
+	}
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter(monitoring.MetricPropertiesCreated, "Properties created").Inc()
+	}
+	return nil
+}
+
+func (s *PropertyService) insertProperty(property *domain.Property) error {
+	if s.outbox == nil {
+		return s.repo.Create(property)
+	}
+	event, err := domain.NewOutboxEvent(domain.EventPropertyCreated, domain.AggregateProperty, property.ID, propertyAgencyID(property), property)
+	if err != nil {
+		return err
+	}
+	return s.outbox.CreateWithEvents(property, event)
+}
+
+// updateProperty saves a property edit, with its property.updated event when the outbox is set
+func (s *PropertyService) updateProperty(property *domain.Property) error {
+	if s.outbox == nil {
+		return s.repo.Update(property)
+	}
+	event, err := domain.NewOutboxEvent(domain.EventPropertyUpdated, domain.AggregateProperty, property.ID, propertyAgencyID(property), property)
+	if err != nil {
+		return err
+	}
+	return s.outbox.UpdateWithEvents(property, event)
+}
+
+// saveEdit saves an edited property, drops its cached data, reindexes it and publishes
+// property.updated
+func (s *PropertyService) saveEdit(property *domain.Property) error {
+	if err := s.updateProperty(property); err != nil {
+		return err
+	}
+
+	s.cache.InvalidateProperty(property.ID)
+	s.cache.InvalidateSearchResults()
+	s.cache.InvalidateStatistics()
+	s.assessRisk(property)
+	s.translateListing(property)
+	s.syncSearchIndex(property)
+	s.publishPropertyEvent(domain.EventPropertyUpdated, property)
+	return nil
+}
+
+// recordVersion records a version of an edited property when a recorder is set
+func (s *PropertyService) recordVersion(before, after *domain.Property, changedBy string) {
+	if s.versions != nil {
+		s.versions.RecordVersion(before, after, changedBy)
+	}
+}
+
+// publishPropertyEvent queues an event about a property; failures are logged so
+// integrations never block listing writes. Events recorded in the outbox are skipped.
+func (s *PropertyService) publishPropertyEvent(eventType string, property *domain.Property) {
+	if s.events == nil || s.outbox != nil {
+		return
+	}
+	agencyID := propertyAgencyID(property)
+	if err := s.events.Publish(eventType, agencyID, property); err != nil {
+		log.Printf("Error publishing %s for property %s: %v", eventType, property.ID, err)
+	}
+}
+
+// propertyAgencyID returns the agency of a listing, empty for independent listings
+func propertyAgencyID(property *domain.Property) string {
+	if property.AgencyID != nil {
+		return *property.AgencyID
+	}
+	return ""
+}
+
+// recordSearchQuery counts a validated query towards popular searches
+func (s *PropertyService) recordSearchQuery(query string) {
+	if s.suggester != nil {
+		s.suggester.RecordQuery(query)
+	}
+}
+
+// syncSearchIndex queues a property for reindexing in the configured search backend
+func (s *PropertyService) syncSearchIndex(property *domain.Property) {
+	if s.indexer != nil {
+		s.indexer.EnqueueIndex(property)
+	}
+}
+
+// ReindexAll pushes every property to the configured search backend
+func (s *PropertyService) ReindexAll() (int, error) {
+	if s.indexer == nil {
+		return 0, fmt.Errorf("no search indexer configured")
+	}
+
+	properties, err := s.repo.GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("error loading properties for reindex: %w", err)
+	}
+
+	if err := s.indexer.Reindex(properties); err != nil {
+		return 0, fmt.Errorf("error reindexing properties: %w", err)
+	}
+
+	return len(properties), nil
+}
+
 // CreateProperty creates a new property with validations
 func (s *PropertyService) CreateProperty(title, description, province, city, propertyType string, price float64, parkingSpaces int) (*domain.Property, error) {
 	// Validate input data
@@ -173,14 +556,38 @@ func (s *PropertyService) CreateProperty(title, description, province, city, pro
 		return nil, fmt.Errorf("invalid property data")
 	}
 
+	review, err := s.screenDescription(property)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agency listings count towards the agency's plan limits
+	if s.limiter != nil && property.AgencyID != nil {
+		if property.Status == domain.StatusAvailable {
+			if err := s.limiter.CheckListingLimit(*property.AgencyID); err != nil {
+				return nil, err
+			}
+		}
+		if property.Featured {
+			if err := s.limiter.CheckFeaturedLimit(*property.AgencyID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Save to database
-	if err := s.repo.Create(property); err != nil {
+	if err := s.createProperty(property); err != nil {
 		return nil, fmt.Errorf("error creating property: %w", err)
 	}
+	s.recordDescriptionReview(property, review, true)
 
 	// Invalidate caches since we added a new property
 	s.cache.InvalidateSearchResults()
 	s.cache.InvalidateStatistics()
+	s.assessRisk(property)
+	s.translateListing(property)
+	s.syncSearchIndex(property)
+	s.publishPropertyEvent(domain.EventPropertyCreated, property)
 
 	return property, nil
 }
@@ -325,6 +732,7 @@ func (s *PropertyService) CreatePropertyComplete(req CreatePropertyFullRequest)
 	if req.AgencyID != nil && *req.AgencyID != "" {
 		property.AgencyID = req.AgencyID
 	}
+	property.TenantID = req.TenantID
 
 	// Store contact information in notes field (temporary solution)
 	contactInfo := fmt.Sprintf("Contacto: %s | Email: %s", req.ContactPhone, req.ContactEmail)
@@ -338,14 +746,60 @@ func (s *PropertyService) CreatePropertyComplete(req CreatePropertyFullRequest)
 		return nil, fmt.Errorf("invalid property data")
 	}
 
+	if err := s.checkPublishers(property); err != nil {
+		return nil, err
+	}
+
+	review, err := s.screenDescription(property)
+	if err != nil {
+		return nil, err
+	}
+
+	// Screen for spam; quarantined listings are stored hidden and without events
+	submitterID := ""
+	if property.OwnerID != nil {
+		submitterID = *property.OwnerID
+	}
+	var assessment domain.SpamAssessment
+	if s.spam != nil {
+		assessment = s.spam.Screen(domain.SpamEntityListing, submitterID, domain.SpamCheck{
+			Honeypot: req.Website,
+			Email:    req.ContactEmail,
+			Phone:    req.ContactPhone,
+			Text:     strings.Join([]string{req.Title, req.Description, req.Notes}, "\n"),
+		})
+	}
+	if assessment.Quarantine() {
+		property.Status = domain.StatusQuarantined
+		if err := s.repo.Create(property); err != nil {
+			return nil, fmt.Errorf("error creating property: %w", err)
+		}
+		if _, err := s.spam.Quarantine(domain.SpamEntityListing, property.ID, submitterID, assessment); err != nil {
+			log.Printf("Error quarantining property %s: %v", property.ID, err)
+		}
+		s.recordDescriptionReview(property, review, true)
+		s.recordVersion(nil, property, submitterID)
+		return property, nil
+	}
+
 	// Save to database
-	if err := s.repo.Create(property); err != nil {
+	if err := s.createProperty(property); err != nil {
 		return nil, fmt.Errorf("error creating property: %w", err)
 	}
+	s.recordDescriptionReview(property, review, true)
 
 	// Invalidate caches since we added a new property
 	s.cache.InvalidateSearchResults()
 	s.cache.InvalidateStatistics()
+	s.assessRisk(property)
+	s.translateListing(property)
+	s.syncSearchIndex(property)
+	s.publishPropertyEvent(domain.EventPropertyCreated, property)
+	createdBy := ""
+	if property.CreatedBy != nil {
+		createdBy = *property.CreatedBy
+	}
+	s.recordVersion(nil, property, createdBy)
 
 	return property, nil
 }
@@ -372,9 +826,8 @@ func (s *PropertyService) GetProperty(id string) (*domain.Property, error) {
 	// Enrich property with image data
 	s.enrichPropertyWithImages(property)
 
-	// Increment view count and update database
-	property.IncrementViews()
-	s.repo.Update(property)
+	// Count the view
+	s.countView(property)
 
 	// Cache the property for future requests
 	s.cache.SetProperty(property)
@@ -401,13 +854,22 @@ func (s *PropertyService) GetPropertyBySlug(slug string) (*domain.Property, erro
 	// Enrich property with image data
 	s.enrichPropertyWithImages(property)
 
-	// Increment view count
-	property.IncrementViews()
-	s.repo.Update(property)
+	// Count the view
+	s.countView(property)
 
 	return property, nil
 }
 
+// countView counts a view of a property in the database and in the returned property.
+// A failed count is logged rather than failing the read.
+func (s *PropertyService) countView(property *domain.Property) {
+	if err := s.repo.IncrementViewCount(property.ID); err != nil {
+		log.Printf("Failed to count view of property %s: %v", property.ID, err)
+		return
+	}
+	property.IncrementViews()
+}
+
 // ListProperties retrieves all properties
 func (s *PropertyService) ListProperties() ([]domain.Property, error) {
 	properties, err := s.repo.GetAll()
@@ -423,6 +885,12 @@ func (s *PropertyService) ListProperties() ([]domain.Property, error) {
 
 // UpdateProperty modifies an existing property
 func (s *PropertyService) UpdateProperty(id, title, description, province, city, propertyType string, price float64) (*domain.Property, error) {
+	return s.UpdatePropertyBy("", id, title, description, province, city, propertyType, price)
+}
+
+// UpdatePropertyBy modifies an existing property on behalf of a user, who is recorded
+// in the property's version history
+func (s *PropertyService) UpdatePropertyBy(changedBy, id, title, description, province, city, propertyType string, price float64) (*domain.Property, error) {
 	// Check if property exists
 	property, err := s.repo.GetByID(id)
 	if err != nil {
@@ -434,6 +902,8 @@ func (s *PropertyService) UpdateProperty(id, title, description, province, city,
 		return nil, err
 	}
 
+	before := *property
+
 	// Update fields
 	property.Title = strings.TrimSpace(title)
 	property.Description = strings.TrimSpace(description)
@@ -450,15 +920,17 @@ func (s *PropertyService) UpdateProperty(id, title, description, province, city,
 		return nil, fmt.Errorf("invalid updated property data")
 	}
 
-	// Save changes
-	if err := s.repo.Update(property); err != nil {
-		return nil, fmt.Errorf("error updating property: %w", err)
+	review, err := s.screenDescription(property)
+	if err != nil {
+		return nil, err
 	}
 
-	// Invalidate caches since property was modified
-	s.cache.InvalidateProperty(id)
-	s.cache.InvalidateSearchResults()
-	s.cache.InvalidateStatistics()
+	// Save changes and invalidate caches since property was modified
+	if err := s.saveEdit(property); err != nil {
+		return nil, fmt.Errorf("error updating property: %w", err)
+	}
+	s.recordDescriptionReview(property, review, false)
+	s.recordVersion(&before, property, changedBy)
 
 	return property, nil
 }
@@ -484,6 +956,9 @@ func (s *PropertyService) DeleteProperty(id string) error {
 	s.cache.InvalidateProperty(id)
 	s.cache.InvalidateSearchResults()
 	s.cache.InvalidateStatistics()
+	if s.indexer != nil {
+		s.indexer.EnqueueDelete(id)
+	}
 
 	return nil
 }
@@ -531,6 +1006,55 @@ func (s *PropertyService) FilterByPriceRange(minPrice, maxPrice float64) ([]doma
 	return properties, nil
 }
 
+// FilterProperties returns properties matching all of the given filters combined
+func (s *PropertyService) FilterProperties(filters *domain.PropertySearchFilters) ([]domain.Property, error) {
+	if err := s.validateFilters(filters); err != nil {
+		return nil, err
+	}
+
+	properties, err := s.repo.GetByFilters(filters)
+	if err != nil {
+		return nil, fmt.Errorf("error filtering properties: %w", err)
+	}
+
+	// Enrich properties with image data
+	s.enrichPropertiesWithImages(properties)
+
+	return properties, nil
+}
+
+// StreamProperties calls fn with each property matching the filters, enriched with its
+// images, as it is read so exports of the whole catalog never hold it in memory. Nil
+// filters stream every property.
+func (s *PropertyService) StreamProperties(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error {
+	if filters == nil {
+		filters = domain.NewPropertySearchFilters()
+	}
+	if err := s.validateFilters(filters); err != nil {
+		return err
+	}
+
+	return s.repo.StreamByFilters(filters, func(property *domain.Property) error {
+		s.enrichPropertyWithImages(property)
+		return fn(property)
+	})
+}
+
+// validateFilters normalizes and validates combined property filters
+func (s *PropertyService) validateFilters(filters *domain.PropertySearchFilters) error {
+	if filters == nil {
+		return fmt.Errorf("filters are required")
+	}
+
+	filters.Query = strings.TrimSpace(filters.Query)
+	if filters.Query != "" && len(filters.Query) < 2 {
+		return fmt.Errorf("search query must be at least 2 characters")
+	}
+	filters.Query = s.preprocessQuery(filters.Query)
+
+	return filters.Validate()
+}
+
 // GetStatistics returns basic property statistics
 func (s *PropertyService) GetStatistics() (map[string]interface{}, error) {
 	// Try to get from cache first
@@ -595,6 +1119,9 @@ func (s *PropertyService) SetPropertyLocation(id string, latitude, longitude flo
 		return fmt.Errorf("error updating property location: %w", err)
 	}
 
+	s.assessRisk(property)
+	s.syncSearchIndex(property)
+
 	return nil
 }
 
@@ -605,12 +1132,20 @@ func (s *PropertyService) SetPropertyFeatured(id string, featured bool) error {
 		return fmt.Errorf("property not found: %w", err)
 	}
 
+	if s.limiter != nil && featured && !property.Featured && property.AgencyID != nil {
+		if err := s.limiter.CheckFeaturedLimit(*property.AgencyID); err != nil {
+			return err
+		}
+	}
+
 	property.SetFeatured(featured)
 
 	if err := s.repo.Update(property); err != nil {
 		return fmt.Errorf("error updating property featured status: %w", err)
 	}
 
+	s.syncSearchIndex(property)
+
 	return nil
 }
 
@@ -627,6 +1162,8 @@ func (s *PropertyService) AddPropertyTag(id, tag string) error {
 		return fmt.Errorf("error adding tag to property: %w", err)
 	}
 
+	s.syncSearchIndex(property)
+
 	return nil
 }
 
@@ -649,6 +1186,8 @@ func (s *PropertyService) SetPropertyParkingSpaces(id string, parkingSpaces int)
 		return fmt.Errorf("error updating property parking spaces: %w", err)
 	}
 
+	s.syncSearchIndex(property)
+
 	return nil
 }
 
@@ -663,6 +1202,8 @@ func (s *PropertyService) SearchProperties(query string) ([]domain.Property, err
 	if len(query) < 2 {
 		return nil, fmt.Errorf("search query must be at least 2 characters")
 	}
+	s.recordSearchQuery(query)
+	query = s.preprocessQuery(query)
 
 	// Use PostgreSQL FTS for efficient search
 	properties, err := s.repo.SearchProperties(query, 50)
@@ -683,6 +1224,8 @@ func (s *PropertyService) SearchPropertiesRanked(query string, limit int) ([]rep
 	if len(query) < 2 {
 		return nil, fmt.Errorf("search query must be at least 2 characters")
 	}
+	s.recordSearchQuery(query)
+	query = s.preprocessQuery(query)
 
 	if limit <= 0 || limit > 100 {
 		limit = 50
@@ -694,7 +1237,7 @@ func (s *PropertyService) SearchPropertiesRanked(query string, limit int) ([]rep
 	}
 
 	// Cache miss - perform search
-	results, err := s.repo.SearchPropertiesRanked(query, limit)
+	results, err := s.searchIndex().Search(query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error performing ranked search: %w", err)
 	}
@@ -720,11 +1263,21 @@ func (s *PropertyService) GetSearchSuggestions(query string, limit int) ([]repos
 		limit = 10
 	}
 
+	if s.suggester != nil {
+		if cached, found := s.suggester.Cached(query, limit); found {
+			return cached, nil
+		}
+	}
+
 	suggestions, err := s.repo.GetSearchSuggestions(query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error getting search suggestions: %w", err)
 	}
 
+	if s.suggester != nil {
+		suggestions = s.suggester.Blend(query, suggestions, limit)
+	}
+
 	return suggestions, nil
 }
 
@@ -767,6 +1320,7 @@ func (s *PropertyService) AdvancedSearch(params repository.AdvancedSearchParams)
 		if len(params.Query) < 2 {
 			return nil, fmt.Errorf("search query must be at least 2 characters")
 		}
+		params.Query = s.preprocessQuery(params.Query)
 	}
 
 	// Set reasonable limits
@@ -774,7 +1328,7 @@ func (s *PropertyService) AdvancedSearch(params repository.AdvancedSearchParams)
 		params.Limit = 50
 	}
 
-	results, err := s.repo.AdvancedSearch(params)
+	results, err := s.searchIndex().AdvancedSearch(params)
 	if err != nil {
 		return nil, fmt.Errorf("error performing advanced search: %w", err)
 	}
@@ -799,7 +1353,7 @@ func (s *PropertyService) ListPropertiesPaginated(pagination *domain.PaginationP
 		return nil, fmt.Errorf("error listing paginated properties: %w", err)
 	}
 
-	paginationMeta := domain.NewPagination(pagination.Page, pagination.PageSize, totalCount)
+	paginationMeta := pagination.Paginate(totalCount)
 	
 	return &domain.PaginatedResponse{
 		Data:       properties,
@@ -830,7 +1384,7 @@ func (s *PropertyService) FilterByProvincePaginated(province string, pagination
 		return nil, fmt.Errorf("error filtering paginated properties by province: %w", err)
 	}
 
-	paginationMeta := domain.NewPagination(pagination.Page, pagination.PageSize, totalCount)
+	paginationMeta := pagination.Paginate(totalCount)
 	
 	return &domain.PaginatedResponse{
 		Data:       properties,
@@ -861,7 +1415,7 @@ func (s *PropertyService) FilterByPriceRangePaginated(minPrice, maxPrice float64
 		return nil, fmt.Errorf("error filtering paginated properties by price range: %w", err)
 	}
 
-	paginationMeta := domain.NewPagination(pagination.Page, pagination.PageSize, totalCount)
+	paginationMeta := pagination.Paginate(totalCount)
 	
 	return &domain.PaginatedResponse{
 		Data:       properties,
@@ -869,6 +1423,48 @@ func (s *PropertyService) FilterByPriceRangePaginated(minPrice, maxPrice float64
 	}, nil
 }
 
+// FilterPropertiesPaginated returns a page of properties matching all of the given filters combined
+func (s *PropertyService) FilterPropertiesPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error) {
+	if err := s.validateFilters(filters); err != nil {
+		return nil, err
+	}
+
+	if pagination == nil {
+		pagination = domain.NewPaginationParams()
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pagination parameters: %w", err)
+	}
+
+	properties, totalCount, err := s.repo.GetByFiltersPaginated(filters, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("error filtering paginated properties: %w", err)
+	}
+
+	paginationMeta := pagination.Paginate(totalCount)
+
+	return &domain.PaginatedResponse{
+		Data:       properties,
+		Pagination: paginationMeta,
+	}, nil
+}
+
+// ListManagedProperties returns the paginated listings an actor manages, filtered in
+// SQL by the actor's role (see PropertySearchFilters.ScopeToActor) so agents and
+// agencies never load other users' listings. Expired listings are included.
+func (s *PropertyService) ListManagedProperties(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams, actor domain.Actor) (*domain.PaginatedResponse, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: authentication required")
+	}
+	if filters == nil {
+		filters = domain.NewPropertySearchFilters()
+	}
+	filters.ScopeToActor(actor)
+
+	return s.FilterPropertiesPaginated(filters, pagination)
+}
+
 // SearchPropertiesPaginated performs paginated full-text search
 func (s *PropertyService) SearchPropertiesPaginated(query string, pagination *domain.PaginationParams) (*domain.PaginatedResponse, error) {
 	if query == "" {
@@ -879,6 +1475,8 @@ func (s *PropertyService) SearchPropertiesPaginated(query string, pagination *do
 	if len(query) < 2 {
 		return nil, fmt.Errorf("search query must be at least 2 characters")
 	}
+	s.recordSearchQuery(query)
+	query = s.preprocessQuery(query)
 
 	if pagination == nil {
 		pagination = domain.NewPaginationParams()
@@ -893,7 +1491,7 @@ func (s *PropertyService) SearchPropertiesPaginated(query string, pagination *do
 		return nil, fmt.Errorf("error performing paginated search: %w", err)
 	}
 
-	paginationMeta := domain.NewPagination(pagination.Page, pagination.PageSize, totalCount)
+	paginationMeta := pagination.Paginate(totalCount)
 	
 	return &domain.PaginatedResponse{
 		Data:       properties,
@@ -911,6 +1509,8 @@ func (s *PropertyService) SearchPropertiesRankedPaginated(query string, paginati
 	if len(query) < 2 {
 		return nil, fmt.Errorf("search query must be at least 2 characters")
 	}
+	s.recordSearchQuery(query)
+	query = s.preprocessQuery(query)
 
 	if pagination == nil {
 		pagination = domain.NewPaginationParams()
@@ -920,12 +1520,12 @@ func (s *PropertyService) SearchPropertiesRankedPaginated(query string, paginati
 		return nil, fmt.Errorf("invalid pagination parameters: %w", err)
 	}
 
-	results, totalCount, err := s.repo.SearchPropertiesRankedPaginated(query, pagination)
+	results, totalCount, err := s.searchIndex().SearchPaginated(query, pagination)
 	if err != nil {
 		return nil, fmt.Errorf("error performing paginated ranked search: %w", err)
 	}
 
-	paginationMeta := domain.NewPagination(pagination.Page, pagination.PageSize, totalCount)
+	paginationMeta := pagination.Paginate(totalCount)
 	
 	return &domain.PaginatedResponse{
 		Data:       results,
@@ -972,6 +1572,7 @@ func (s *PropertyService) AdvancedSearchPaginated(params repository.AdvancedSear
 		if len(params.Query) < 2 {
 			return nil, fmt.Errorf("search query must be at least 2 characters")
 		}
+		params.Query = s.preprocessQuery(params.Query)
 	}
 
 	if pagination == nil {
@@ -982,12 +1583,12 @@ func (s *PropertyService) AdvancedSearchPaginated(params repository.AdvancedSear
 		return nil, fmt.Errorf("invalid pagination parameters: %w", err)
 	}
 
-	results, totalCount, err := s.repo.AdvancedSearchPaginated(params, pagination)
+	results, totalCount, err := s.searchIndex().AdvancedSearchPaginated(params, pagination)
 	if err != nil {
 		return nil, fmt.Errorf("error performing paginated advanced search: %w", err)
 	}
 
-	paginationMeta := domain.NewPagination(pagination.Page, pagination.PageSize, totalCount)
+	paginationMeta := pagination.Paginate(totalCount)
 	
 	return &domain.PaginatedResponse{
 		Data:       results,
@@ -995,6 +1596,46 @@ func (s *PropertyService) AdvancedSearchPaginated(params repository.AdvancedSear
 	}, nil
 }
 
+// AdvancedSearchFaceted performs paginated advanced search and returns facet counts
+// (province, city, type, bedrooms, price range, amenities) for every matching property
+func (s *PropertyService) AdvancedSearchFaceted(params repository.AdvancedSearchParams, pagination *domain.PaginationParams) (*domain.FacetedSearchResponse, error) {
+	page, err := s.AdvancedSearchPaginated(params, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Query = s.preprocessQuery(strings.TrimSpace(params.Query))
+	facets, err := s.getSearchFacets(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.FacetedSearchResponse{
+		Data:       page.Data,
+		Pagination: page.Pagination,
+		Facets:     facets,
+	}, nil
+}
+
+// getSearchFacets returns cached facet counts or computes them in the repository
+func (s *PropertyService) getSearchFacets(params repository.AdvancedSearchParams) (*domain.SearchFacets, error) {
+	// Facets do not depend on the page being requested
+	params.Limit = 0
+	key := fmt.Sprintf("%+v", params)
+
+	if facets, found := s.cache.GetFacets(key); found {
+		return facets, nil
+	}
+
+	facets, err := s.repo.GetSearchFacets(params)
+	if err != nil {
+		return nil, fmt.Errorf("error computing search facets: %w", err)
+	}
+
+	s.cache.SetFacets(key, facets)
+	return facets, nil
+}
+
 // validatePropertyData validates basic property creation/update data
 func (s *PropertyService) validatePropertyData(title, province, city, propertyType string, price float64) error {
 	// Validate required fields
@@ -1095,4 +1736,18 @@ func (s *PropertyService) GetCacheStats() cache.PropertyCacheStats {
 // ClearCache clears all cached data
 func (s *PropertyService) ClearCache() {
 	s.cache.Clear()
-}
\ No newline at end of file
+}
+
+// SetCacheTTLs changes the cache TTLs at runtime, e.g. on a configuration reload
+func (s *PropertyService) SetCacheTTLs(propertyTTL, searchTTL, statisticsTTL time.Duration) {
+	s.cache.SetTTLs(propertyTTL, searchTTL, statisticsTTL)
+}
+// InvalidateProperties drops cached data of properties changed outside this service,
+// e.g. by agent assignments
+func (s *PropertyService) InvalidateProperties(ids ...string) {
+	for _, id := range ids {
+		s.cache.InvalidateProperty(id)
+	}
+	s.cache.InvalidateSearchResults()
+	s.cache.InvalidateStatistics()
+}
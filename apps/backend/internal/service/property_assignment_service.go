@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// PropertyChangeListener is notified of properties modified outside PropertyService
+type PropertyChangeListener interface {
+	InvalidateProperties(ids ...string)
+}
+
+// PropertyAssignmentService assigns agency properties to agents and transfers listings
+// between agents. Every change is recorded in the audit log.
+type PropertyAssignmentService struct {
+	propertyRepo repository.PropertyRepository
+	userRepo     *repository.UserRepository
+	agencyRepo   *repository.AgencyRepository
+	auditRepo    repository.AuditRepository
+	listener     PropertyChangeListener
+	logger       *log.Logger
+}
+
+// NewPropertyAssignmentService creates a new property assignment service
+func NewPropertyAssignmentService(
+	propertyRepo repository.PropertyRepository,
+	userRepo *repository.UserRepository,
+	agencyRepo *repository.AgencyRepository,
+	auditRepo repository.AuditRepository,
+	logger *log.Logger,
+) *PropertyAssignmentService {
+	return &PropertyAssignmentService{
+		propertyRepo: propertyRepo,
+		userRepo:     userRepo,
+		agencyRepo:   agencyRepo,
+		auditRepo:    auditRepo,
+		logger:       logger,
+	}
+}
+
+// SetChangeListener registers a listener for changed properties, typically the
+// PropertyService so its cache does not serve stale assignments
+func (s *PropertyAssignmentService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// AssignAgent assigns an agent of the property's agency to the property
+func (s *PropertyAssignmentService) AssignAgent(propertyID, agentID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.agencyProperty(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := s.userRepo.GetByID(agentID)
+	if err != nil {
+		return nil, fmt.Errorf("agent not found: %w", err)
+	}
+	if !isActiveAgentOf(agent, *property.AgencyID) {
+		return nil, fmt.Errorf("invalid agent: must be an active agent of the property's agency")
+	}
+
+	previous := property.AgentID
+	if err := property.AssignToAgency(*property.AgencyID, &agentID, actor.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.propertyRepo.Update(property); err != nil {
+		return nil, fmt.Errorf("failed to assign agent: %w", err)
+	}
+
+	s.changed(property.ID)
+	s.audit(domain.NewAuditEntry(actor, domain.AuditActionAgentAssigned, domain.AuditEntityProperty, property.ID, property.AgencyID,
+		map[string]interface{}{"agent_id": agentID, "previous_agent_id": previous}))
+
+	s.logger.Printf("Agent %s assigned to property %s", agentID, property.ID)
+	return property, nil
+}
+
+// UnassignAgent removes the agent of a property; the property stays with its agency
+func (s *PropertyAssignmentService) UnassignAgent(propertyID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.agencyProperty(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if property.AgentID == nil {
+		return property, nil
+	}
+
+	previous := *property.AgentID
+	if err := property.AssignToAgency(*property.AgencyID, nil, actor.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.propertyRepo.Update(property); err != nil {
+		return nil, fmt.Errorf("failed to unassign agent: %w", err)
+	}
+
+	s.changed(property.ID)
+	s.audit(domain.NewAuditEntry(actor, domain.AuditActionAgentUnassigned, domain.AuditEntityProperty, property.ID, property.AgencyID,
+		map[string]interface{}{"previous_agent_id": previous}))
+
+	s.logger.Printf("Agent %s unassigned from property %s", previous, property.ID)
+	return property, nil
+}
+
+// TransferListings moves every listing of an agency from one agent to another, e.g.
+// when an agent leaves. Only administrators of the agency can transfer its listings.
+func (s *PropertyAssignmentService) TransferListings(agencyID, fromAgentID, toAgentID string, actor domain.Actor) ([]string, error) {
+	if fromAgentID == "" || toAgentID == "" {
+		return nil, fmt.Errorf("invalid transfer: source and target agents are required")
+	}
+	if fromAgentID == toAgentID {
+		return nil, fmt.Errorf("invalid transfer: source and target agents must differ")
+	}
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can transfer listings")
+	}
+
+	if _, err := s.agencyRepo.GetByID(agencyID); err != nil {
+		return nil, fmt.Errorf("agency not found: %w", err)
+	}
+
+	// The source agent may be deactivated already but must still belong to the agency
+	from, err := s.userRepo.GetByID(fromAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("source agent not found: %w", err)
+	}
+	if from.AgencyID == nil || *from.AgencyID != agencyID {
+		return nil, fmt.Errorf("invalid transfer: source agent does not belong to the agency")
+	}
+
+	to, err := s.userRepo.GetByID(toAgentID)
+	if err != nil {
+		return nil, fmt.Errorf("target agent not found: %w", err)
+	}
+	if !isActiveAgentOf(to, agencyID) {
+		return nil, fmt.Errorf("invalid transfer: target must be an active agent of the agency")
+	}
+
+	propertyIDs, err := s.agencyRepo.TransferAgentProperties(agencyID, fromAgentID, toAgentID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.changed(propertyIDs...)
+	s.audit(domain.NewAuditEntry(actor, domain.AuditActionListingsTransferred, domain.AuditEntityAgency, agencyID, &agencyID,
+		map[string]interface{}{"from_agent_id": fromAgentID, "to_agent_id": toAgentID, "property_ids": propertyIDs}))
+
+	s.logger.Printf("%d listings transferred from agent %s to %s in agency %s", len(propertyIDs), fromAgentID, toAgentID, agencyID)
+	return propertyIDs, nil
+}
+
+// GetAgencyAuditLog lists the audit entries of an agency, newest first
+func (s *PropertyAssignmentService) GetAgencyAuditLog(agencyID string, limit, offset int, actor domain.Actor) ([]domain.AuditEntry, int, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, 0, fmt.Errorf("permission denied: only agency administrators can view the audit log")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.auditRepo.ListByAgency(agencyID, limit, offset)
+}
+
+// agencyProperty loads a property managed by an agency the actor administers
+func (s *PropertyAssignmentService) agencyProperty(propertyID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if property.AgencyID == nil {
+		return nil, fmt.Errorf("invalid property: it is not managed by an agency")
+	}
+	if !actor.CanAdministerAgency(*property.AgencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can assign agents")
+	}
+
+	return property, nil
+}
+
+// changed notifies the listener of modified properties
+func (s *PropertyAssignmentService) changed(ids ...string) {
+	if s.listener != nil && len(ids) > 0 {
+		s.listener.InvalidateProperties(ids...)
+	}
+}
+
+// audit records an entry; failures are logged without undoing the change
+func (s *PropertyAssignmentService) audit(entry *domain.AuditEntry) {
+	if err := s.auditRepo.Create(entry); err != nil {
+		s.logger.Printf("Error recording audit entry %s for %s %s: %v", entry.Action, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+// isActiveAgentOf reports whether a user is an active agent of an agency
+func isActiveAgentOf(user *domain.User, agencyID string) bool {
+	return user.IsAgent() && user.Active && user.AgencyID != nil && *user.AgencyID == agencyID
+}
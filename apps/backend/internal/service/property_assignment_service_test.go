@@ -0,0 +1,55 @@
+package service
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"realty-core/internal/domain"
+)
+
+func TestPropertyAssignmentService_Permissions(t *testing.T) {
+	agencyProperty := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	agencyProperty.ID = "prop-agency"
+	agencyProperty.SetAgency("agency-1")
+	ownerProperty := domain.NewProperty("Depto", "Departamento en Cumbayá", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	ownerProperty.ID = "prop-owner"
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-agency").Return(agencyProperty, nil)
+	propertyRepo.On("GetByID", "prop-owner").Return(ownerProperty, nil)
+
+	service := NewPropertyAssignmentService(propertyRepo, nil, nil, nil, log.New(os.Stdout, "", 0))
+	otherAgency := domain.NewActor("user-2", "agency", "agency-2")
+	agent := domain.NewActor("agent-1", "agent", "agency-1")
+
+	t.Run("agents cannot assign", func(t *testing.T) {
+		_, err := service.AssignAgent("prop-agency", "agent-2", agent)
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("other agencies cannot unassign", func(t *testing.T) {
+		_, err := service.UnassignAgent("prop-agency", otherAgency)
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("property without agency", func(t *testing.T) {
+		_, err := service.AssignAgent("prop-owner", "agent-2", domain.NewActor("admin-1", "admin", ""))
+		assert.ErrorContains(t, err, "not managed by an agency")
+	})
+
+	t.Run("transfer requires agency administrator", func(t *testing.T) {
+		_, err := service.TransferListings("agency-1", "agent-1", "agent-2", otherAgency)
+		assert.ErrorContains(t, err, "permission denied")
+
+		_, err = service.TransferListings("agency-1", "agent-1", "agent-1", domain.NewActor("admin-1", "admin", ""))
+		assert.ErrorContains(t, err, "must differ")
+	})
+
+	t.Run("audit log requires agency administrator", func(t *testing.T) {
+		_, _, err := service.GetAgencyAuditLog("agency-1", 10, 0, agent)
+		assert.ErrorContains(t, err, "permission denied")
+	})
+}
@@ -41,7 +41,7 @@ func TestPropertyService_CacheIntegration(t *testing.T) {
 
 		// Setup mocks - repo should be called only once
 		mockRepo.On("GetByID", "test-1").Return(testProperty, nil).Once()
-		mockRepo.On("Update", testProperty).Return(nil).Once()
+		mockRepo.On("IncrementViewCount", "test-1").Return(nil).Once()
 		mockImageRepo.On("GetByPropertyID", "test-1").Return([]domain.ImageInfo{}, nil).Times(2)
 
 		// First call should go to repo
@@ -219,7 +219,7 @@ func TestPropertyService_CacheIntegration(t *testing.T) {
 
 		// Setup mocks - repo should be called every time since cache is disabled
 		mockRepo.On("GetByID", "test-disabled").Return(testProperty, nil).Times(2)
-		mockRepo.On("Update", testProperty).Return(nil).Times(2)
+		mockRepo.On("IncrementViewCount", "test-disabled").Return(nil).Times(2)
 		mockImageRepo.On("GetByPropertyID", "test-disabled").Return([]domain.ImageInfo{}, nil).Times(2)
 
 		// Both calls should go to repo
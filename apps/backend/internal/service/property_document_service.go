@@ -0,0 +1,237 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// DocumentScanner checks uploaded documents for malware before they are stored.
+// Implementations may call a ClamAV daemon or a cloud scanning service.
+type DocumentScanner interface {
+	// Scan returns the name of the threat found in the file, or "" when it is clean
+	Scan(data []byte, fileName string) (string, error)
+}
+
+// DocumentConfig configures property document uploads
+type DocumentConfig struct {
+	MaxFileSize    int64
+	MaxPerProperty int
+}
+
+// UploadDocumentRequest describes a document attached to a property
+type UploadDocumentRequest struct {
+	PropertyID  string
+	Type        string
+	Visibility  string // defaults to the visibility of the type
+	Title       string
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+// PropertyDocumentService handles deeds, floor plans and contracts attached to properties.
+// Files are written through the same storage abstraction as images. Private documents
+// are only visible to whoever manages the listing and are never given a public URL.
+type PropertyDocumentService struct {
+	repo         repository.PropertyDocumentRepository
+	propertyRepo repository.PropertyRepository
+	storage      storage.ImageStorage
+	scanner      DocumentScanner
+	config       DocumentConfig
+	logger       *log.Logger
+}
+
+// NewPropertyDocumentService creates a new property document service
+func NewPropertyDocumentService(repo repository.PropertyDocumentRepository, propertyRepo repository.PropertyRepository, storage storage.ImageStorage, config DocumentConfig, logger *log.Logger) *PropertyDocumentService {
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = domain.MaxDocumentUploadSize
+	}
+	if config.MaxPerProperty <= 0 {
+		config.MaxPerProperty = domain.MaxDocumentsPerProperty
+	}
+
+	return &PropertyDocumentService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		storage:      storage,
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// SetDocumentScanner scans every upload before it is stored. Without a scanner uploads
+// are only validated by type and size.
+func (s *PropertyDocumentService) SetDocumentScanner(scanner DocumentScanner) {
+	s.scanner = scanner
+}
+
+// Upload validates, scans and stores a document of a property managed by the actor
+func (s *PropertyDocumentService) Upload(req UploadDocumentRequest, content io.Reader, actor domain.Actor) (*domain.PropertyDocument, error) {
+	property, err := s.propertyRepo.GetByID(req.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can attach documents")
+	}
+
+	if err := domain.ValidateDocumentUpload(req.FileName, req.ContentType, req.Size, s.config.MaxFileSize); err != nil {
+		return nil, fmt.Errorf("upload validation failed: %w", err)
+	}
+
+	document, err := domain.NewPropertyDocument(property.ID, req.Type, req.Visibility, req.Title, req.FileName, req.ContentType, req.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	count, err := s.repo.CountByProperty(property.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.config.MaxPerProperty {
+		return nil, fmt.Errorf("maximum documents per property exceeded: %d", s.config.MaxPerProperty)
+	}
+
+	// The declared size cannot be used to bypass the limit
+	data, err := io.ReadAll(io.LimitReader(content, s.config.MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxFileSize {
+		return nil, fmt.Errorf("file too large: max %d bytes", s.config.MaxFileSize)
+	}
+	document.Size = int64(len(data))
+
+	if err := s.scan(data, document); err != nil {
+		return nil, err
+	}
+
+	storedPath, err := s.storage.Store(data, document.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	document.StoragePath = storedPath
+	if document.IsPublic() {
+		document.URL = s.storage.GetURL(storedPath)
+	}
+	document.UploadedBy = actor.UserID
+
+	if err := s.repo.Create(document); err != nil {
+		if delErr := s.storage.Delete(storedPath); delErr != nil {
+			s.logger.Printf("Error deleting document file %s: %v", storedPath, delErr)
+		}
+		return nil, err
+	}
+
+	s.logger.Printf("Document %s (%s, %s) attached to property %s", document.ID, document.Type, document.Visibility, property.ID)
+	return document, nil
+}
+
+// ListDocuments lists the documents of a property. Whoever manages the listing sees every
+// document; everyone else only the public ones.
+func (s *PropertyDocumentService) ListDocuments(propertyID string, actor domain.Actor) ([]domain.PropertyDocument, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	return s.repo.GetByPropertyID(property.ID, !canManageListing(property, actor))
+}
+
+// GetDocument retrieves a document and its content. Private documents are reported as
+// not found to actors who cannot see them.
+func (s *PropertyDocumentService) GetDocument(id string, actor domain.Actor) (*domain.PropertyDocument, []byte, error) {
+	document, err := s.visibleDocument(id, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := s.storage.Retrieve(document.StoragePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve document: %w", err)
+	}
+
+	return document, data, nil
+}
+
+// DeleteDocument deletes a document of a property managed by the actor
+func (s *PropertyDocumentService) DeleteDocument(id string, actor domain.Actor) error {
+	document, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	property, err := s.propertyRepo.GetByID(document.PropertyID)
+	if err != nil {
+		return fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return fmt.Errorf("permission denied: only the property's agency, agent or owner can delete documents")
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	if err := s.storage.Delete(document.StoragePath); err != nil {
+		s.logger.Printf("Error deleting document file %s: %v", document.StoragePath, err)
+	}
+
+	s.logger.Printf("Document deleted: %s", id)
+	return nil
+}
+
+// visibleDocument retrieves a document the actor is allowed to see
+func (s *PropertyDocumentService) visibleDocument(id string, actor domain.Actor) (*domain.PropertyDocument, error) {
+	document, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if document.IsPublic() {
+		return document, nil
+	}
+
+	property, err := s.propertyRepo.GetByID(document.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("document not found: %s", id)
+	}
+	return document, nil
+}
+
+// scan rejects documents the scanner reports as infected
+func (s *PropertyDocumentService) scan(data []byte, document *domain.PropertyDocument) error {
+	threat, err := scanDocument(s.scanner, data, document.FileName)
+	if err != nil {
+		return err
+	}
+	if threat != "" {
+		s.logger.Printf("Document %q for property %s rejected: %s", document.FileName, document.PropertyID, threat)
+		return fmt.Errorf("upload validation failed: file is infected (%s)", threat)
+	}
+	return nil
+}
+
+// scanDocument runs an optional scanner over an upload and returns the threat it found
+func scanDocument(scanner DocumentScanner, data []byte, fileName string) (string, error) {
+	if scanner == nil {
+		return "", nil
+	}
+
+	threat, err := scanner.Scan(data, fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to scan document: %w", err)
+	}
+	if threat != "" {
+		if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+			metrics.GetOrCreateCounter("documents_rejected_total", "Total number of document uploads rejected by the malware scanner").Inc()
+		}
+	}
+	return threat, nil
+}
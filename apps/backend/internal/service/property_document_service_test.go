@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/storage"
+)
+
+// memoryDocumentRepository is an in-memory PropertyDocumentRepository
+type memoryDocumentRepository struct {
+	documents map[string]domain.PropertyDocument
+}
+
+func (r *memoryDocumentRepository) Create(document *domain.PropertyDocument) error {
+	r.documents[document.ID] = *document
+	return nil
+}
+
+func (r *memoryDocumentRepository) GetByID(id string) (*domain.PropertyDocument, error) {
+	document, ok := r.documents[id]
+	if !ok {
+		return nil, fmt.Errorf("document not found: %s", id)
+	}
+	return &document, nil
+}
+
+func (r *memoryDocumentRepository) GetByPropertyID(propertyID string, publicOnly bool) ([]domain.PropertyDocument, error) {
+	documents := []domain.PropertyDocument{}
+	for _, document := range r.documents {
+		if document.PropertyID == propertyID && (!publicOnly || document.IsPublic()) {
+			documents = append(documents, document)
+		}
+	}
+	sort.Slice(documents, func(i, j int) bool { return documents[i].Type < documents[j].Type })
+	return documents, nil
+}
+
+func (r *memoryDocumentRepository) Delete(id string) error {
+	if _, ok := r.documents[id]; !ok {
+		return fmt.Errorf("document not found: %s", id)
+	}
+	delete(r.documents, id)
+	return nil
+}
+
+func (r *memoryDocumentRepository) CountByProperty(propertyID string) (int, error) {
+	documents, _ := r.GetByPropertyID(propertyID, false)
+	return len(documents), nil
+}
+
+// signatureScanner reports files containing a known signature as infected
+type signatureScanner struct {
+	signature string
+}
+
+func (s *signatureScanner) Scan(data []byte, fileName string) (string, error) {
+	if bytes.Contains(data, []byte(s.signature)) {
+		return "EICAR-Test-File", nil
+	}
+	return "", nil
+}
+
+func newTestDocumentService(t *testing.T) (*PropertyDocumentService, *memoryDocumentRepository) {
+	documentStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/documents", domain.MaxDocumentUploadSize)
+	require.NoError(t, err)
+
+	property := domain.NewProperty("Casa", "Casa en Cumbayá", "Pichincha", "Quito", "house", 180000, "owner-1")
+	property.ID = "prop-1"
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	repo := &memoryDocumentRepository{documents: map[string]domain.PropertyDocument{}}
+	service := NewPropertyDocumentService(repo, propertyRepo, documentStorage, DocumentConfig{MaxFileSize: 1024}, log.New(os.Stderr, "", 0))
+	return service, repo
+}
+
+func TestPropertyDocumentService_UploadAndVisibility(t *testing.T) {
+	service, _ := newTestDocumentService(t)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	visitor := domain.NewActor("buyer-1", string(domain.RoleBuyer), "")
+
+	plan, err := service.Upload(UploadDocumentRequest{
+		PropertyID: "prop-1", Type: domain.DocumentTypeFloorPlan,
+		FileName: "planta.pdf", ContentType: "application/pdf", Size: 9,
+	}, bytes.NewReader([]byte("%PDF-plan")), owner)
+	require.NoError(t, err)
+	assert.Contains(t, plan.URL, "/uploads/documents/")
+	assert.Equal(t, "owner-1", plan.UploadedBy)
+
+	deed, err := service.Upload(UploadDocumentRequest{
+		PropertyID: "prop-1", Type: domain.DocumentTypeDeed,
+		FileName: "escritura.pdf", ContentType: "application/pdf", Size: 9,
+	}, bytes.NewReader([]byte("%PDF-deed")), owner)
+	require.NoError(t, err)
+	assert.Empty(t, deed.URL, "private documents have no public URL")
+
+	documents, err := service.ListDocuments("prop-1", owner)
+	require.NoError(t, err)
+	assert.Len(t, documents, 2)
+
+	documents, err = service.ListDocuments("prop-1", visitor)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.Equal(t, domain.DocumentTypeFloorPlan, documents[0].Type)
+
+	_, data, err := service.GetDocument(deed.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-deed", string(data))
+
+	_, _, err = service.GetDocument(deed.ID, visitor)
+	assert.ErrorContains(t, err, "document not found")
+
+	_, _, err = service.GetDocument(plan.ID, visitor)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, service.DeleteDocument(plan.ID, visitor), "permission denied")
+	require.NoError(t, service.DeleteDocument(plan.ID, owner))
+	_, _, err = service.GetDocument(plan.ID, owner)
+	assert.ErrorContains(t, err, "document not found")
+}
+
+func TestPropertyDocumentService_UploadValidation(t *testing.T) {
+	service, repo := newTestDocumentService(t)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	req := UploadDocumentRequest{PropertyID: "prop-1", Type: domain.DocumentTypeContract, FileName: "contrato.pdf", ContentType: "application/pdf", Size: 5}
+
+	_, err := service.Upload(req, bytes.NewReader([]byte("%PDF-")), domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	invalid := req
+	invalid.Type = "invoice"
+	_, err = service.Upload(invalid, bytes.NewReader([]byte("%PDF-")), owner)
+	assert.ErrorContains(t, err, "invalid document")
+
+	executable := req
+	executable.FileName = "contrato.exe"
+	_, err = service.Upload(executable, bytes.NewReader([]byte("%PDF-")), owner)
+	assert.ErrorContains(t, err, "validation failed")
+
+	// The declared size cannot be used to bypass the limit
+	_, err = service.Upload(req, bytes.NewReader(make([]byte, 2048)), owner)
+	assert.ErrorContains(t, err, "too large")
+
+	service.SetDocumentScanner(&signatureScanner{signature: "EICAR"})
+	_, err = service.Upload(req, bytes.NewReader([]byte("EICAR")), owner)
+	assert.ErrorContains(t, err, "infected")
+	assert.Empty(t, repo.documents)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// PropertyCreator creates complete properties, e.g. PropertyService
+type PropertyCreator interface {
+	CreatePropertyComplete(req CreatePropertyFullRequest) (*domain.Property, error)
+}
+
+// PropertyDraftService autosaves the listing creation wizard so partially filled forms
+// survive refreshes. Drafts are private to the user who saved them and are validated
+// only when published.
+type PropertyDraftService struct {
+	repo    repository.PropertyDraftRepository
+	creator PropertyCreator
+	logger  *log.Logger
+	now     func() time.Time
+}
+
+// NewPropertyDraftService creates a new property draft service
+func NewPropertyDraftService(repo repository.PropertyDraftRepository, creator PropertyCreator, logger *log.Logger) *PropertyDraftService {
+	return &PropertyDraftService{
+		repo:    repo,
+		creator: creator,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// SaveDraft creates or replaces a draft of the actor with the given client-generated ID
+func (s *PropertyDraftService) SaveDraft(id string, payload json.RawMessage, actor domain.Actor) (*domain.PropertyDraft, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to save drafts")
+	}
+
+	existing, err := s.repo.GetByID(strings.ToLower(id))
+	switch {
+	case err == nil:
+		if existing.UserID != actor.UserID {
+			return nil, fmt.Errorf("property draft not found: %s", id)
+		}
+		if err := existing.SetPayload(payload); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Save(existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	case !strings.Contains(err.Error(), "not found"):
+		return nil, err
+	}
+
+	count, err := s.repo.CountByUser(actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxPropertyDraftsPerUser {
+		return nil, fmt.Errorf("invalid draft: at most %d drafts per user, publish or delete one first", domain.MaxPropertyDraftsPerUser)
+	}
+
+	draft, err := domain.NewPropertyDraft(id, actor.UserID, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// ListDrafts returns the drafts of the actor, most recently saved first
+func (s *PropertyDraftService) ListDrafts(actor domain.Actor) ([]domain.PropertyDraft, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to list drafts")
+	}
+	return s.repo.ListByUser(actor.UserID)
+}
+
+// GetDraft returns a draft of the actor
+func (s *PropertyDraftService) GetDraft(id string, actor domain.Actor) (*domain.PropertyDraft, error) {
+	draft, err := s.repo.GetByID(strings.ToLower(id))
+	if err != nil {
+		return nil, err
+	}
+	// Drafts of other users are reported as missing so their IDs do not leak
+	if actor.UserID == "" || draft.UserID != actor.UserID {
+		return nil, fmt.Errorf("property draft not found: %s", id)
+	}
+	return draft, nil
+}
+
+// DeleteDraft discards a draft of the actor
+func (s *PropertyDraftService) DeleteDraft(id string, actor domain.Actor) error {
+	draft, err := s.GetDraft(id, actor)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(draft.ID)
+}
+
+// PublishDraft creates a property from a draft of the actor through the regular
+// creation validations, then deletes the draft. Listings without an owner are owned by
+// the actor. A draft that fails validation is kept so the wizard can fix it.
+func (s *PropertyDraftService) PublishDraft(id, tenantID string, actor domain.Actor) (*domain.Property, error) {
+	draft, err := s.GetDraft(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	var req CreatePropertyFullRequest
+	if err := json.Unmarshal(draft.Payload, &req); err != nil {
+		return nil, fmt.Errorf("invalid draft: %v", err)
+	}
+	req.TenantID = tenantID
+	if req.OwnerID == nil || *req.OwnerID == "" {
+		req.OwnerID = &actor.UserID
+	}
+
+	property, err := s.creator.CreatePropertyComplete(req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid draft: %w", err)
+	}
+
+	if err := s.repo.Delete(draft.ID); err != nil {
+		s.logger.Printf("Error deleting published draft %s: %v", draft.ID, err)
+	}
+	s.logger.Printf("Draft %s published as property %s by %s", draft.ID, property.ID, actor.UserID)
+	return property, nil
+}
+
+// ExpireStale deletes drafts not saved within the draft retention and returns how many
+// were deleted
+func (s *PropertyDraftService) ExpireStale() (int64, error) {
+	return s.repo.DeleteUpdatedBefore(s.now().Add(-domain.PropertyDraftRetention))
+}
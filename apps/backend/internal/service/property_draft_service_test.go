@@ -0,0 +1,146 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+type memoryPropertyDraftRepository struct {
+	drafts map[string]domain.PropertyDraft
+}
+
+func newMemoryPropertyDraftRepository() *memoryPropertyDraftRepository {
+	return &memoryPropertyDraftRepository{drafts: map[string]domain.PropertyDraft{}}
+}
+
+func (r *memoryPropertyDraftRepository) Save(draft *domain.PropertyDraft) error {
+	if existing, ok := r.drafts[draft.ID]; ok && existing.UserID != draft.UserID {
+		return fmt.Errorf("property draft not found: %s", draft.ID)
+	}
+	r.drafts[draft.ID] = *draft
+	return nil
+}
+
+func (r *memoryPropertyDraftRepository) GetByID(id string) (*domain.PropertyDraft, error) {
+	draft, ok := r.drafts[id]
+	if !ok {
+		return nil, fmt.Errorf("property draft not found: %s", id)
+	}
+	return &draft, nil
+}
+
+func (r *memoryPropertyDraftRepository) ListByUser(userID string) ([]domain.PropertyDraft, error) {
+	drafts := []domain.PropertyDraft{}
+	for _, draft := range r.drafts {
+		if draft.UserID == userID {
+			drafts = append(drafts, draft)
+		}
+	}
+	return drafts, nil
+}
+
+func (r *memoryPropertyDraftRepository) CountByUser(userID string) (int, error) {
+	drafts, _ := r.ListByUser(userID)
+	return len(drafts), nil
+}
+
+func (r *memoryPropertyDraftRepository) Delete(id string) error {
+	delete(r.drafts, id)
+	return nil
+}
+
+func (r *memoryPropertyDraftRepository) DeleteUpdatedBefore(before time.Time) (int64, error) {
+	var deleted int64
+	for id, draft := range r.drafts {
+		if draft.UpdatedAt.Before(before) {
+			delete(r.drafts, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type recordingPropertyCreator struct {
+	requests []CreatePropertyFullRequest
+	err      error
+}
+
+func (c *recordingPropertyCreator) CreatePropertyComplete(req CreatePropertyFullRequest) (*domain.Property, error) {
+	c.requests = append(c.requests, req)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &domain.Property{ID: "prop-1", Title: req.Title}, nil
+}
+
+func TestPropertyDraftService_AutosaveAndPublish(t *testing.T) {
+	repo := newMemoryPropertyDraftRepository()
+	creator := &recordingPropertyCreator{}
+	service := NewPropertyDraftService(repo, creator, log.New(os.Stdout, "", 0))
+	owner := domain.NewActor("user-1", "owner", "")
+	other := domain.NewActor("user-2", "owner", "")
+	draftID := "6f1c1b9e-3d4b-4c55-9a57-1c2b3d4e5f60"
+
+	_, err := service.SaveDraft(draftID, json.RawMessage(`{"title":"Casa"}`), domain.Actor{})
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.SaveDraft(draftID, json.RawMessage(`{"title":"Casa"}`), owner)
+	require.NoError(t, err)
+	draft, err := service.SaveDraft(draftID, json.RawMessage(`{"title":"Casa en Cumbayá","province":"Pichincha","bedrooms":3}`), owner)
+	require.NoError(t, err)
+	assert.Equal(t, "Casa en Cumbayá", draft.Title)
+
+	drafts, err := service.ListDrafts(owner)
+	require.NoError(t, err)
+	assert.Len(t, drafts, 1)
+
+	_, err = service.SaveDraft(draftID, json.RawMessage(`{}`), other)
+	assert.ErrorContains(t, err, "not found", "drafts of other users cannot be overwritten")
+	_, err = service.PublishDraft(draftID, "", other)
+	assert.ErrorContains(t, err, "not found")
+
+	creator.err = errors.New("title is required")
+	_, err = service.PublishDraft(draftID, "", owner)
+	assert.ErrorContains(t, err, "invalid draft: title is required")
+	assert.Len(t, repo.drafts, 1, "drafts failing validation are kept")
+
+	creator.err = nil
+	property, err := service.PublishDraft(draftID, "tenant-1", owner)
+	require.NoError(t, err)
+	assert.Equal(t, "prop-1", property.ID)
+	last := creator.requests[len(creator.requests)-1]
+	assert.Equal(t, 3, last.Bedrooms)
+	assert.Equal(t, "tenant-1", last.TenantID)
+	assert.Equal(t, "user-1", *last.OwnerID)
+	assert.Empty(t, repo.drafts)
+}
+
+func TestPropertyDraftService_Limits(t *testing.T) {
+	repo := newMemoryPropertyDraftRepository()
+	service := NewPropertyDraftService(repo, &recordingPropertyCreator{}, log.New(os.Stdout, "", 0))
+	owner := domain.NewActor("user-1", "owner", "")
+
+	for i := 0; i < domain.MaxPropertyDraftsPerUser; i++ {
+		_, err := service.SaveDraft(fmt.Sprintf("00000000-0000-4000-8000-%012d", i), json.RawMessage(`{}`), owner)
+		require.NoError(t, err)
+	}
+	_, err := service.SaveDraft("00000000-0000-4000-8000-999999999999", json.RawMessage(`{}`), owner)
+	assert.ErrorContains(t, err, "at most")
+
+	stale := repo.drafts["00000000-0000-4000-8000-000000000000"]
+	stale.UpdatedAt = time.Now().Add(-domain.PropertyDraftRetention - time.Hour)
+	repo.drafts[stale.ID] = stale
+	deleted, err := service.ExpireStale()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// MaxPropertyHistoryLimit caps how many versions a history request returns
+const MaxPropertyHistoryLimit = 200
+
+// PropertyHistoryService keeps a numbered snapshot of every property edit with the
+// fields it changed and who changed them, and lets administrators revert a property
+// to an earlier version. Register it on the PropertyService with SetVersionRecorder.
+type PropertyHistoryService struct {
+	repo       repository.PropertyVersionRepository
+	properties *PropertyService
+	auditRepo  repository.AuditRepository
+	logger     *log.Logger
+}
+
+// NewPropertyHistoryService creates a new property history service
+func NewPropertyHistoryService(
+	repo repository.PropertyVersionRepository,
+	properties *PropertyService,
+	auditRepo repository.AuditRepository,
+	logger *log.Logger,
+) *PropertyHistoryService {
+	return &PropertyHistoryService{
+		repo:       repo,
+		properties: properties,
+		auditRepo:  auditRepo,
+		logger:     logger,
+	}
+}
+
+// RecordVersion stores a version of an edited property. Edits that change no versioned
+// field are skipped, and failures are logged so history never blocks listing writes.
+func (s *PropertyHistoryService) RecordVersion(before, after *domain.Property, changedBy string) {
+	changeType := domain.PropertyChangeEdit
+	if before == nil {
+		changeType = domain.PropertyChangeCreate
+	}
+	s.record(before, after, changeType, changedBy, nil)
+}
+
+// ListHistory returns the latest versions of a property, newest first. Only users who
+// can manage the listing can see its history.
+func (s *PropertyHistoryService) ListHistory(propertyID string, limit int, actor domain.Actor) ([]domain.PropertyVersion, error) {
+	property, err := s.properties.repo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: cannot view the history of this property")
+	}
+
+	if limit <= 0 {
+		limit = domain.DefaultPropertyHistoryLimit
+	}
+	if limit > MaxPropertyHistoryLimit {
+		limit = MaxPropertyHistoryLimit
+	}
+	return s.repo.ListByProperty(propertyID, limit)
+}
+
+// Revert restores the versioned fields of a property to an earlier version, recording
+// the revert as a new version. Only administrators can revert properties.
+func (s *PropertyHistoryService) Revert(propertyID string, number int, actor domain.Actor) (*domain.Property, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can revert properties")
+	}
+	if number <= 0 {
+		return nil, fmt.Errorf("invalid version: %d", number)
+	}
+
+	current, err := s.properties.repo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	version, err := s.repo.GetByVersion(propertyID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := version.Restore(current)
+	if err != nil {
+		return nil, err
+	}
+	restored.UpdatedBy = &actor.UserID
+	if !restored.IsValid() {
+		return nil, fmt.Errorf("invalid version: version %d no longer makes a valid property", number)
+	}
+
+	if err := s.properties.saveEdit(restored); err != nil {
+		return nil, fmt.Errorf("failed to revert property: %w", err)
+	}
+	s.record(current, restored, domain.PropertyChangeRevert, actor.UserID, &number)
+	s.audit(domain.NewAuditEntry(actor, domain.AuditActionPropertyReverted, domain.AuditEntityProperty, restored.ID, restored.AgencyID,
+		map[string]interface{}{"version": number}))
+
+	s.logger.Printf("Property %s reverted to version %d by %s", restored.ID, number, actor.UserID)
+	return restored, nil
+}
+
+// record stores a version, logging failures
+func (s *PropertyHistoryService) record(before, after *domain.Property, changeType, changedBy string, revertedFrom *int) {
+	version, err := domain.NewPropertyVersion(before, after, changeType, changedBy)
+	if err != nil {
+		s.logger.Printf("Error recording version of property %s: %v", after.ID, err)
+		return
+	}
+	if changeType == domain.PropertyChangeEdit && len(version.Changes) == 0 {
+		return
+	}
+	version.RevertedFrom = revertedFrom
+
+	if err := s.repo.Create(version); err != nil {
+		s.logger.Printf("Error recording version of property %s: %v", after.ID, err)
+	}
+}
+
+// audit records an entry; failures are logged without undoing the change
+func (s *PropertyHistoryService) audit(entry *domain.AuditEntry) {
+	if s.auditRepo == nil {
+		return
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		s.logger.Printf("Error recording audit entry %s: %v", entry.Action, err)
+	}
+}
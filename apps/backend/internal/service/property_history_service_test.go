@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+type memoryPropertyVersionRepository struct {
+	versions []domain.PropertyVersion
+}
+
+func (r *memoryPropertyVersionRepository) Create(version *domain.PropertyVersion) error {
+	version.Version = len(r.versions) + 1
+	r.versions = append(r.versions, *version)
+	return nil
+}
+
+func (r *memoryPropertyVersionRepository) ListByProperty(propertyID string, limit int) ([]domain.PropertyVersion, error) {
+	versions := []domain.PropertyVersion{}
+	for i := len(r.versions) - 1; i >= 0 && len(versions) < limit; i-- {
+		if r.versions[i].PropertyID == propertyID {
+			versions = append(versions, r.versions[i])
+		}
+	}
+	return versions, nil
+}
+
+func (r *memoryPropertyVersionRepository) GetByVersion(propertyID string, number int) (*domain.PropertyVersion, error) {
+	for _, version := range r.versions {
+		if version.PropertyID == propertyID && version.Version == number {
+			return &version, nil
+		}
+	}
+	return nil, fmt.Errorf("property version not found: %s v%d", propertyID, number)
+}
+
+func TestPropertyHistoryService_RecordsEditsAndReverts(t *testing.T) {
+	property := domain.NewProperty("Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.UpdateSlug()
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+	propertyRepo.On("Update", mock.AnythingOfType("*domain.Property")).Return(nil)
+
+	properties := NewPropertyService(propertyRepo, newEmptyImageRepository())
+	versions := &memoryPropertyVersionRepository{}
+	history := NewPropertyHistoryService(versions, properties, nil, log.New(os.Stdout, "", 0))
+	properties.SetVersionRecorder(history)
+
+	original := *property
+	history.RecordVersion(nil, &original, "owner-1")
+
+	_, err := properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 1)
+	require.NoError(t, err)
+	_, err = properties.UpdatePropertyBy("agent-1", "prop-1", "Casa en Samborondón", "Casa amplia", "Guayas", "Samborondón", "house", 1)
+	require.NoError(t, err)
+	require.Len(t, versions.versions, 2, "edits without changes are not recorded")
+	assert.Equal(t, domain.PropertyChangeCreate, versions.versions[0].ChangeType)
+	assert.Equal(t, "agent-1", versions.versions[1].ChangedBy)
+	assert.Equal(t, []domain.PropertyFieldChange{{Field: "price", From: float64(250000), To: float64(1)}}, versions.versions[1].Changes)
+
+	admin := domain.NewActor("admin-1", "admin", "")
+	agent := domain.NewActor("agent-1", "agent", "")
+
+	_, err = history.Revert("prop-1", 1, agent)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = history.ListHistory("prop-1", 10, agent)
+	assert.ErrorContains(t, err, "permission denied")
+
+	reverted, err := history.Revert("prop-1", 1, admin)
+	require.NoError(t, err)
+	assert.Equal(t, float64(250000), reverted.Price)
+
+	list, err := history.ListHistory("prop-1", 0, admin)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, domain.PropertyChangeRevert, list[0].ChangeType)
+	assert.Equal(t, 1, *list[0].RevertedFrom)
+	assert.Equal(t, "admin-1", list[0].ChangedBy)
+
+	_, err = history.Revert("prop-1", 7, admin)
+	assert.ErrorContains(t, err, "property version not found")
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+)
+
+// PropertyShareService records properties being shared from their page, attributed to
+// the signed-in user or the visitor's browser session, and reports the conversion
+// funnel sharing opens: visits through shared links, inquiries and scheduled visits
+type PropertyShareService struct {
+	repo         repository.PropertyShareRepository
+	propertyRepo repository.PropertyRepository
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewPropertyShareService creates a new property share service
+func NewPropertyShareService(repo repository.PropertyShareRepository, propertyRepo repository.PropertyRepository, logger *log.Logger) *PropertyShareService {
+	return &PropertyShareService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// RecordShare records a share of a property by anyone, reporting false when it repeats
+// a share of the same user or session within domain.ShareDedupWindow
+func (s *PropertyShareService) RecordShare(propertyID, channel, sessionID string, actor domain.Actor) (*domain.PropertyShare, bool, error) {
+	if _, err := s.propertyRepo.GetByID(propertyID); err != nil {
+		return nil, false, fmt.Errorf("property not found: %w", err)
+	}
+	share, err := domain.NewPropertyShare(propertyID, channel, actor.UserID, sessionID, s.now())
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid share: %w", err)
+	}
+
+	recorded, err := s.repo.Create(share)
+	if err != nil {
+		return nil, false, err
+	}
+	if recorded {
+		if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+			metrics.GetOrCreateCounter("property_shares_total", "Total number of property shares from property pages").Inc()
+		}
+	}
+	return share, recorded, nil
+}
+
+// GetConversionFunnel reports the conversion funnel of the whole site between from and
+// to, the last DefaultFunnelDays days by default, to administrators
+func (s *PropertyShareService) GetConversionFunnel(from, to *time.Time, actor domain.Actor) (*domain.ConversionFunnel, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can see the conversion funnel")
+	}
+
+	end := s.now()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -domain.DefaultFunnelDays)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("invalid period: from must be before to")
+	}
+
+	counts, err := s.repo.GetFunnelCounts(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildConversionFunnel(counts, start, end), nil
+}
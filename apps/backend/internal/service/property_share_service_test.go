@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryPropertyShares keeps share events in memory
+type memoryPropertyShares struct {
+	shares []domain.PropertyShare
+}
+
+func (m *memoryPropertyShares) Create(share *domain.PropertyShare) (bool, error) {
+	for _, other := range m.shares {
+		sameSharer := (share.UserID != "" && other.UserID == share.UserID) ||
+			(share.SessionID != "" && other.SessionID == share.SessionID)
+		if other.PropertyID == share.PropertyID && other.Channel == share.Channel && sameSharer &&
+			other.CreatedAt.After(share.CreatedAt.Add(-domain.ShareDedupWindow)) {
+			return false, nil
+		}
+	}
+	m.shares = append(m.shares, *share)
+	return true, nil
+}
+
+func (m *memoryPropertyShares) CountByProperty(propertyID string) (*domain.ShareCounts, error) {
+	counts := domain.NewShareCounts()
+	for _, share := range m.shares {
+		if share.PropertyID == propertyID {
+			counts.Add(share.Channel, 1)
+		}
+	}
+	return counts, nil
+}
+
+func (m *memoryPropertyShares) GetFunnelCounts(from, to time.Time) (*domain.ConversionFunnelCounts, error) {
+	counts := &domain.ConversionFunnelCounts{Shares: *domain.NewShareCounts(), ShareVisits: 3, Inquiries: 1}
+	for _, share := range m.shares {
+		if !share.CreatedAt.Before(from) && share.CreatedAt.Before(to) {
+			counts.Shares.Add(share.Channel, 1)
+		}
+	}
+	return counts, nil
+}
+
+func TestPropertyShareService(t *testing.T) {
+	now := time.Date(2025, 9, 26, 10, 0, 0, 0, time.UTC)
+	agencyID := "agency-1"
+	properties := &MockPropertyRepository{}
+	properties.On("GetByID", "prop-1").Return(&domain.Property{ID: "prop-1", AgencyID: &agencyID}, nil)
+	properties.On("GetByID", mock.Anything).Return((*domain.Property)(nil), fmt.Errorf("property not found"))
+
+	repo := &memoryPropertyShares{}
+	svc := NewPropertyShareService(repo, properties, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+	visitor := domain.Actor{}
+	buyer := domain.NewActor("buyer-1", string(domain.RoleBuyer), "")
+
+	_, _, err := svc.RecordShare("prop-404", domain.ShareChannelWhatsApp, "session-123", visitor)
+	assert.ErrorContains(t, err, "not found")
+	_, _, err = svc.RecordShare("prop-1", domain.ShareChannelWhatsApp, "", visitor)
+	assert.ErrorContains(t, err, "invalid share")
+
+	_, recorded, err := svc.RecordShare("prop-1", domain.ShareChannelWhatsApp, "session-123", visitor)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	_, recorded, err = svc.RecordShare("prop-1", domain.ShareChannelWhatsApp, "session-123", visitor)
+	require.NoError(t, err)
+	assert.False(t, recorded, "repeated shares within the window count once")
+	share, recorded, err := svc.RecordShare("prop-1", domain.ShareChannelLink, "", buyer)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Equal(t, "buyer-1", share.UserID)
+
+	// Shares show in the property analytics of its managers
+	metrics := NewListingMetricsService(&memoryListingMetrics{metrics: map[string]domain.ListingMetrics{
+		"prop-1": {PropertyID: "prop-1", Status: domain.StatusAvailable, PublishedAt: now},
+	}}, properties, 0, log.New(&bytes.Buffer{}, "", 0))
+	metrics.SetShareCounter(repo)
+	analytics, err := metrics.GetPropertyMetrics("prop-1", domain.NewActor(agencyID, string(domain.RoleAgency), agencyID))
+	require.NoError(t, err)
+	require.NotNil(t, analytics.Shares)
+	assert.Equal(t, 2, analytics.Shares.Total)
+	assert.Equal(t, 1, analytics.Shares.ByChannel[domain.ShareChannelLink])
+
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = svc.GetConversionFunnel(nil, nil, buyer)
+	assert.ErrorContains(t, err, "permission denied")
+	funnel, err := svc.GetConversionFunnel(nil, nil, domain.NewActor("admin-1", string(domain.RoleAdmin), ""))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour).AddDate(0, 0, -domain.DefaultFunnelDays), funnel.From)
+	require.Len(t, funnel.Steps, 4)
+	assert.Equal(t, domain.FunnelStep{Key: domain.FunnelStepShareVisits, Count: 3, ConversionRate: 150}, funnel.Steps[1])
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/search"
+)
+
+// ChangeStatusRequest asks to move a listing to another status. FinalPrice is required
+// for sold and rented, and ClosingDate defaults to now; Reason is required to re-list a
+// sold property.
+type ChangeStatusRequest struct {
+	Status      string     `json:"status"`
+	FinalPrice  *float64   `json:"final_price,omitempty"`
+	ClosingDate *time.Time `json:"closing_date,omitempty"`
+	ClientID    string     `json:"client_id,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	// RentalStart and RentalEnd are the dates booked when renting
+	RentalStart *time.Time `json:"rental_start,omitempty"`
+	RentalEnd   *time.Time `json:"rental_end,omitempty"`
+}
+
+// DealRecorder records the deal that closes a listing as sold or rented
+type DealRecorder interface {
+	RecordDeal(req RecordDealRequest, actor domain.Actor) (*domain.Deal, error)
+}
+
+// ReservationCloser ends the active reservation of a listing that leaves the reserved status
+type ReservationCloser interface {
+	CloseReservation(propertyID, listingStatus string) error
+}
+
+// PropertyStatusService moves listings through their status state machine
+// (available → reserved → sold / rented, with re-listing) and publishes
+// property.status_changed for analytics. Sales and rentals are recorded as deals.
+type PropertyStatusService struct {
+	repo         repository.PropertyStatusRepository
+	propertyRepo repository.PropertyRepository
+	deals        DealRecorder
+	reservations ReservationCloser
+	listener     PropertyChangeListener
+	events       EventPublisher
+	outbox       bool
+	indexer      *search.Indexer
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewPropertyStatusService creates a property status service
+func NewPropertyStatusService(
+	repo repository.PropertyStatusRepository,
+	propertyRepo repository.PropertyRepository,
+	deals DealRecorder,
+	logger *log.Logger,
+) *PropertyStatusService {
+	return &PropertyStatusService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		deals:        deals,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetChangeListener registers a listener for properties whose status changes, typically
+// the PropertyService so cached listings and searches reflect them
+func (s *PropertyStatusService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// SetReservations makes listings reserved only through reservations for a lead, and
+// ends the active reservation when a reserved listing is sold, rented or made available
+func (s *PropertyStatusService) SetReservations(reservations ReservationCloser) {
+	s.reservations = reservations
+}
+
+// SetEventPublisher publishes property.status_changed after each transition
+func (s *PropertyStatusService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
+// SetOutbox records property.status_changed in the transactional outbox with the
+// change instead of publishing it afterwards
+func (s *PropertyStatusService) SetOutbox(enabled bool) {
+	s.outbox = enabled
+}
+
+// SetSearchIndexer reindexes listings with their new status
+func (s *PropertyStatusService) SetSearchIndexer(indexer *search.Indexer) {
+	s.indexer = indexer
+}
+
+// ChangeStatus moves a listing to another status. The property's agency, assigned agent
+// or owner can change it; only admins re-list sold properties.
+func (s *PropertyStatusService) ChangeStatus(propertyID string, req ChangeStatusRequest, actor domain.Actor) (*domain.PropertyStatusChange, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can change its status")
+	}
+
+	change, err := domain.NewPropertyStatusChange(property.ID, property.Status, req.Status, req.FinalPrice, req.Reason, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if change.FromStatus == domain.StatusSold && actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can re-list sold properties")
+	}
+	if change.ToStatus == domain.StatusReserved && s.reservations != nil {
+		return nil, fmt.Errorf("invalid status: reserve properties for a lead through POST /api/properties/%s/reservations", property.ID)
+	}
+	change.ChangedAt = s.now()
+
+	var events []*domain.OutboxEvent
+	if s.outbox {
+		event, err := domain.NewOutboxEvent(domain.EventPropertyStatusChanged, domain.AggregateProperty, property.ID, propertyAgencyID(property), change)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if change.ToStatus == domain.StatusSold || change.ToStatus == domain.StatusRented {
+		if err := s.recordDeal(property, change, req, actor); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Record(change, events...); err != nil {
+			s.logger.Printf("Warning: failed to record status change of property %s: %v", property.ID, err)
+		}
+	} else if err := s.repo.ChangeStatus(change, events...); err != nil {
+		return nil, err
+	}
+
+	if change.FromStatus == domain.StatusReserved && s.reservations != nil {
+		if err := s.reservations.CloseReservation(property.ID, change.ToStatus); err != nil {
+			s.logger.Printf("Warning: failed to close reservation of property %s: %v", property.ID, err)
+		}
+	}
+
+	property.Status = change.ToStatus
+	if s.indexer != nil {
+		s.indexer.EnqueueIndex(property)
+	}
+	if s.listener != nil {
+		s.listener.InvalidateProperties(property.ID)
+	}
+	if s.events != nil && !s.outbox {
+		if err := s.events.Publish(domain.EventPropertyStatusChanged, propertyAgencyID(property), change); err != nil {
+			s.logger.Printf("Error publishing %s for property %s: %v", domain.EventPropertyStatusChanged, property.ID, err)
+		}
+	}
+
+	s.logger.Printf("Property %s status changed from %s to %s", property.ID, change.FromStatus, change.ToStatus)
+	return change, nil
+}
+
+// GetStatusHistory lists the status changes of a listing, latest first
+func (s *PropertyStatusService) GetStatusHistory(propertyID string, actor domain.Actor) ([]domain.PropertyStatusChange, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can view its status history")
+	}
+	return s.repo.ListByProperty(property.ID)
+}
+
+// recordDeal closes a listing as sold or rented through the deal service, which also
+// updates its status
+func (s *PropertyStatusService) recordDeal(property *domain.Property, change *domain.PropertyStatusChange, req ChangeStatusRequest, actor domain.Actor) error {
+	if s.deals == nil {
+		return fmt.Errorf("deals are not configured: cannot mark property %s", change.ToStatus)
+	}
+
+	dealType := domain.DealTypeSale
+	if change.ToStatus == domain.StatusRented {
+		dealType = domain.DealTypeRent
+	}
+	closingDate := change.ChangedAt
+	if req.ClosingDate != nil {
+		closingDate = *req.ClosingDate
+	}
+
+	_, err := s.deals.RecordDeal(RecordDealRequest{
+		PropertyID:  property.ID,
+		Type:        dealType,
+		FinalPrice:  *change.FinalPrice,
+		ClosingDate: closingDate,
+		ClientID:    req.ClientID,
+		Notes:       change.Reason,
+		RentalStart: req.RentalStart,
+		RentalEnd:   req.RentalEnd,
+	}, actor)
+	return err
+}
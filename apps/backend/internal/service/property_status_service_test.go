@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryPropertyStatusRepository applies status changes to in-memory properties
+type memoryPropertyStatusRepository struct {
+	properties map[string]*domain.Property
+	changes    []domain.PropertyStatusChange
+	events     []*domain.OutboxEvent
+}
+
+func (r *memoryPropertyStatusRepository) ChangeStatus(change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	property := r.properties[change.PropertyID]
+	if property.Status != change.FromStatus {
+		return fmt.Errorf("invalid status transition: property %s is no longer %s", change.PropertyID, change.FromStatus)
+	}
+	property.Status = change.ToStatus
+	return r.Record(change, events...)
+}
+
+func (r *memoryPropertyStatusRepository) Record(change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	r.changes = append([]domain.PropertyStatusChange{*change}, r.changes...)
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memoryPropertyStatusRepository) ListByProperty(propertyID string) ([]domain.PropertyStatusChange, error) {
+	return r.changes, nil
+}
+
+// recordingDeals records deals and applies their status like the deal repository
+type recordingDeals struct {
+	properties map[string]*domain.Property
+	requests   []RecordDealRequest
+}
+
+func (d *recordingDeals) RecordDeal(req RecordDealRequest, actor domain.Actor) (*domain.Deal, error) {
+	deal, err := domain.NewDeal(req.PropertyID, req.Type, req.FinalPrice, 0, req.ClosingDate)
+	if err != nil {
+		return nil, err
+	}
+	d.requests = append(d.requests, req)
+	d.properties[req.PropertyID].Status = deal.PropertyStatus()
+	return deal, nil
+}
+
+func TestPropertyStatusService_ChangeStatus(t *testing.T) {
+	property := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.SetAgency("agency-1")
+	properties := map[string]*domain.Property{property.ID: property}
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+	repo := &memoryPropertyStatusRepository{properties: properties}
+	deals := &recordingDeals{properties: properties}
+	publisher := &recordingEventPublisher{}
+	listener := &recordingListener{}
+
+	svc := NewPropertyStatusService(repo, propertyRepo, deals, log.New(os.Stdout, "", 0))
+	svc.SetEventPublisher(publisher)
+	svc.SetChangeListener(listener)
+	svc.now = func() time.Time { return time.Date(2025, 8, 29, 12, 0, 0, 0, time.UTC) }
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+
+	_, err := svc.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusReserved}, domain.NewActor("user-2", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	change, err := svc.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusReserved}, agency)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusAvailable, change.FromStatus)
+	assert.Equal(t, domain.StatusReserved, property.Status)
+
+	_, err = svc.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusSold}, agency)
+	assert.ErrorContains(t, err, "final price is required")
+
+	finalPrice := 235000.0
+	_, err = svc.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusSold, FinalPrice: &finalPrice}, agency)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusSold, property.Status)
+	require.Len(t, deals.requests, 1)
+	assert.Equal(t, domain.DealTypeSale, deals.requests[0].Type)
+	assert.Equal(t, svc.now(), deals.requests[0].ClosingDate)
+
+	_, err = svc.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusReserved}, agency)
+	assert.ErrorContains(t, err, "invalid status transition")
+
+	_, err = svc.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusAvailable, Reason: "Financing fell through"}, agency)
+	assert.ErrorContains(t, err, "only admins")
+
+	history, err := svc.GetStatusHistory("prop-1", agency)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.StatusSold, history[0].ToStatus)
+	assert.Equal(t, []string{domain.EventPropertyStatusChanged, domain.EventPropertyStatusChanged}, publisher.published)
+	assert.Equal(t, []string{"prop-1", "prop-1"}, listener.ids)
+}
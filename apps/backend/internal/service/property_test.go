@@ -46,6 +46,11 @@ func (m *MockPropertyRepository) Delete(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockPropertyRepository) IncrementViewCount(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockPropertyRepository) GetByProvince(province string) ([]domain.Property, error) {
 	args := m.Called(province)
 	return args.Get(0).([]domain.Property), args.Error(1)
@@ -88,6 +93,27 @@ func (m *MockPropertyRepository) GetByProvincePaginated(province string, paginat
 	return args.Get(0).([]domain.Property), args.Int(1), args.Error(2)
 }
 
+func (m *MockPropertyRepository) GetByFilters(filters *domain.PropertySearchFilters) ([]domain.Property, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]domain.Property), args.Error(1)
+}
+
+func (m *MockPropertyRepository) StreamByFilters(filters *domain.PropertySearchFilters, fn func(*domain.Property) error) error {
+	args := m.Called(filters)
+	properties := args.Get(0).([]domain.Property)
+	for i := range properties {
+		if err := fn(&properties[i]); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockPropertyRepository) GetByFiltersPaginated(filters *domain.PropertySearchFilters, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]domain.Property), args.Int(1), args.Error(2)
+}
+
 func (m *MockPropertyRepository) GetByPriceRangePaginated(minPrice, maxPrice float64, pagination *domain.PaginationParams) ([]domain.Property, int, error) {
 	args := m.Called(minPrice, maxPrice, pagination)
 	return args.Get(0).([]domain.Property), args.Int(1), args.Error(2)
@@ -108,11 +134,30 @@ func (m *MockPropertyRepository) AdvancedSearchPaginated(params repository.Advan
 	return args.Get(0).([]repository.PropertySearchResult), args.Int(1), args.Error(2)
 }
 
+func (m *MockPropertyRepository) GetSearchFacets(params repository.AdvancedSearchParams) (*domain.SearchFacets, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchFacets), args.Error(1)
+}
+
+func (m *MockPropertyRepository) ForTenant(tenantID string) repository.PropertyRepository {
+	return m
+}
+
 // MockImageRepository is a mock implementation of ImageRepository
 type MockImageRepository struct {
 	mock.Mock
 }
 
+// newEmptyImageRepository returns an image repository mock with no images for any property
+func newEmptyImageRepository() *MockImageRepository {
+	m := &MockImageRepository{}
+	m.On("GetByPropertyID", mock.AnythingOfType("string")).Return([]domain.ImageInfo{}, nil).Maybe()
+	return m
+}
+
 func (m *MockImageRepository) Create(imageInfo *domain.ImageInfo) error {
 	args := m.Called(imageInfo)
 	return args.Error(0)
@@ -123,6 +168,11 @@ func (m *MockImageRepository) GetByID(id string) (*domain.ImageInfo, error) {
 	return args.Get(0).(*domain.ImageInfo), args.Error(1)
 }
 
+func (m *MockImageRepository) GetVisibleByID(id string) (*domain.ImageInfo, error) {
+	args := m.Called(id)
+	return args.Get(0).(*domain.ImageInfo), args.Error(1)
+}
+
 func (m *MockImageRepository) GetByPropertyID(propertyID string) ([]domain.ImageInfo, error) {
 	args := m.Called(propertyID)
 	return args.Get(0).([]domain.ImageInfo), args.Error(1)
@@ -173,6 +223,11 @@ func (m *MockImageRepository) GetImageStats() (map[string]interface{}, error) {
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
+func (m *MockImageRepository) ListImageFiles() ([]domain.ImageInfo, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.ImageInfo), args.Error(1)
+}
+
 // Helper function to create a test property
 func createTestProperty() *domain.Property {
 	return domain.NewProperty(
@@ -301,7 +356,7 @@ func TestPropertyService_CreateProperty(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			property, err := service.CreateProperty(
 				tt.title,
@@ -347,7 +402,7 @@ func TestPropertyService_GetProperty(t *testing.T) {
 			mockSetup: func(m *MockPropertyRepository) {
 				property := createTestProperty()
 				m.On("GetByID", "test-id").Return(property, nil)
-				m.On("Update", mock.AnythingOfType("*domain.Property")).Return(nil)
+				m.On("IncrementViewCount", property.ID).Return(nil)
 			},
 			wantError: false,
 		},
@@ -398,6 +453,23 @@ func TestPropertyService_GetProperty(t *testing.T) {
 	}
 }
 
+func TestPropertyService_GetProperty_ViewCountFailureDoesNotFailRead(t *testing.T) {
+	mockRepo := &MockPropertyRepository{}
+	mockImageRepo := &MockImageRepository{}
+	property := createTestProperty()
+	mockRepo.On("GetByID", "test-id").Return(property, nil)
+	mockRepo.On("IncrementViewCount", property.ID).Return(errors.New("database error"))
+	mockImageRepo.On("GetByPropertyID", mock.AnythingOfType("string")).Return([]domain.ImageInfo{}, nil)
+
+	service := NewPropertyService(mockRepo, mockImageRepo)
+	result, err := service.GetProperty("test-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.ViewCount)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestPropertyService_GetPropertyBySlug(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -412,7 +484,7 @@ func TestPropertyService_GetPropertyBySlug(t *testing.T) {
 			mockSetup: func(m *MockPropertyRepository) {
 				property := createTestProperty()
 				m.On("GetBySlug", "beautiful-house-12345678").Return(property, nil)
-				m.On("Update", mock.AnythingOfType("*domain.Property")).Return(nil)
+				m.On("IncrementViewCount", property.ID).Return(nil)
 			},
 			wantError: false,
 		},
@@ -448,7 +520,7 @@ func TestPropertyService_GetPropertyBySlug(t *testing.T) {
 			tt.mockSetup(mockRepo)
 			
 			// Configure image repository mock to return empty slice for all properties
-			mockImageRepo.On("GetByPropertyID", mock.AnythingOfType("string")).Return([]domain.ImageInfo{}, nil)
+			mockImageRepo.On("GetByPropertyID", mock.AnythingOfType("string")).Return([]domain.ImageInfo{}, nil).Maybe()
 			
 			service := NewPropertyService(mockRepo, mockImageRepo)
 
@@ -513,7 +585,7 @@ func TestPropertyService_ListProperties(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			properties, err := service.ListProperties()
 
@@ -599,7 +671,7 @@ func TestPropertyService_UpdateProperty(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			property, err := service.UpdateProperty(
 				tt.id,
@@ -682,7 +754,7 @@ func TestPropertyService_DeleteProperty(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			err := service.DeleteProperty(tt.id)
 
@@ -748,7 +820,7 @@ func TestPropertyService_FilterByProvince(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			properties, err := service.FilterByProvince(tt.province)
 
@@ -830,7 +902,7 @@ func TestPropertyService_FilterByPriceRange(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			properties, err := service.FilterByPriceRange(tt.minPrice, tt.maxPrice)
 
@@ -849,6 +921,104 @@ func TestPropertyService_FilterByPriceRange(t *testing.T) {
 	}
 }
 
+func TestPropertyService_FilterProperties(t *testing.T) {
+	minPrice := 100000.0
+	maxPrice := 50000.0
+	minBedrooms := 2
+
+	tests := []struct {
+		name          string
+		filters       func() *domain.PropertySearchFilters
+		mockSetup     func(*MockPropertyRepository)
+		wantError     bool
+		errorContains string
+		expectedCount int
+	}{
+		{
+			name: "combined filters",
+			filters: func() *domain.PropertySearchFilters {
+				f := domain.NewPropertySearchFilters()
+				f.Provinces = []string{"Guayas"}
+				f.PropertyTypes = []string{"house"}
+				f.MinBedrooms = &minBedrooms
+				return f
+			},
+			mockSetup: func(m *MockPropertyRepository) {
+				m.On("GetByFilters", mock.AnythingOfType("*domain.PropertySearchFilters")).Return([]domain.Property{*createTestProperty()}, nil)
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "invalid price range",
+			filters: func() *domain.PropertySearchFilters {
+				f := domain.NewPropertySearchFilters()
+				f.MinPrice = &minPrice
+				f.MaxPrice = &maxPrice
+				return f
+			},
+			mockSetup:     func(m *MockPropertyRepository) {},
+			wantError:     true,
+			errorContains: "minimum price cannot be greater than maximum price",
+		},
+		{
+			name: "invalid property type",
+			filters: func() *domain.PropertySearchFilters {
+				f := domain.NewPropertySearchFilters()
+				f.PropertyTypes = []string{"castle"}
+				return f
+			},
+			mockSetup:     func(m *MockPropertyRepository) {},
+			wantError:     true,
+			errorContains: "invalid property type",
+		},
+		{
+			name: "query too short",
+			filters: func() *domain.PropertySearchFilters {
+				f := domain.NewPropertySearchFilters()
+				f.Query = " a "
+				return f
+			},
+			mockSetup:     func(m *MockPropertyRepository) {},
+			wantError:     true,
+			errorContains: "search query must be at least 2 characters",
+		},
+		{
+			name: "repository error",
+			filters: func() *domain.PropertySearchFilters {
+				f := domain.NewPropertySearchFilters()
+				f.Cities = []string{"Quito"}
+				return f
+			},
+			mockSetup: func(m *MockPropertyRepository) {
+				m.On("GetByFilters", mock.AnythingOfType("*domain.PropertySearchFilters")).Return([]domain.Property{}, errors.New("database error"))
+			},
+			wantError:     true,
+			errorContains: "error filtering properties",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockPropertyRepository{}
+			tt.mockSetup(mockRepo)
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
+
+			properties, err := service.FilterProperties(tt.filters())
+
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.Nil(t, properties)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, properties, tt.expectedCount)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPropertyService_GetStatistics(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -919,7 +1089,7 @@ func TestPropertyService_GetStatistics(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			stats, err := service.GetStatistics()
 
@@ -995,7 +1165,7 @@ func TestPropertyService_SetPropertyLocation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			err := service.SetPropertyLocation(tt.id, tt.latitude, tt.longitude, tt.precision)
 
@@ -1059,7 +1229,7 @@ func TestPropertyService_SetPropertyFeatured(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			err := service.SetPropertyFeatured(tt.id, tt.featured)
 
@@ -1123,7 +1293,7 @@ func TestPropertyService_AddPropertyTag(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			err := service.AddPropertyTag(tt.id, tt.tag)
 
@@ -1195,7 +1365,7 @@ func TestPropertyService_SearchProperties_Legacy(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockPropertyRepository{}
 			tt.mockSetup(mockRepo)
-			service := NewPropertyService(mockRepo, &MockImageRepository{})
+			service := NewPropertyService(mockRepo, newEmptyImageRepository())
 
 			properties, err := service.SearchProperties(tt.query)
 
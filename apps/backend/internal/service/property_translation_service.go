@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/translation"
+)
+
+// PropertyTranslationService manages the English (and future) titles and descriptions
+// of properties and localizes property responses, falling back to Spanish. With a
+// translator, missing translations can be machine-translated from Spanish.
+type PropertyTranslationService struct {
+	repo         repository.PropertyTranslationRepository
+	propertyRepo repository.PropertyRepository
+	translator   translation.Translator
+	logger       *log.Logger
+}
+
+// NewPropertyTranslationService creates a new property translation service
+func NewPropertyTranslationService(
+	repo repository.PropertyTranslationRepository,
+	propertyRepo repository.PropertyRepository,
+	logger *log.Logger,
+) *PropertyTranslationService {
+	return &PropertyTranslationService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		logger:       logger,
+	}
+}
+
+// SetTranslator machine-translates listings with a translation provider, typically a
+// translation.CachedTranslator
+func (s *PropertyTranslationService) SetTranslator(translator translation.Translator) {
+	s.translator = translator
+}
+
+// GetTranslations returns the translations of a property
+func (s *PropertyTranslationService) GetTranslations(propertyID string) (domain.PropertyTranslations, error) {
+	return s.repo.GetTranslations(propertyID)
+}
+
+// SetTranslation adds or replaces the translation of a property the actor manages
+func (s *PropertyTranslationService) SetTranslation(propertyID, locale, title, description string, actor domain.Actor) (*domain.PropertyTranslation, error) {
+	if _, err := s.checkManager(propertyID, actor); err != nil {
+		return nil, err
+	}
+
+	translation, err := domain.NewPropertyTranslation(locale, title, description)
+	if err != nil {
+		return nil, fmt.Errorf("invalid translation: %w", err)
+	}
+	locale = domain.NormalizeLocale(locale)
+
+	if err := s.repo.SetTranslation(propertyID, locale, translation); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Property %s translated to %s by %s", propertyID, locale, actor.UserID)
+	return &translation, nil
+}
+
+// DeleteTranslation removes the translation of a property the actor manages
+func (s *PropertyTranslationService) DeleteTranslation(propertyID, locale string, actor domain.Actor) error {
+	if _, err := s.checkManager(propertyID, actor); err != nil {
+		return err
+	}
+
+	normalized := domain.NormalizeLocale(locale)
+	if normalized == "" || normalized == domain.DefaultLocale {
+		return fmt.Errorf("invalid locale: %s", locale)
+	}
+
+	return s.repo.DeleteTranslation(propertyID, normalized)
+}
+
+// AutoTranslate machine-translates the Spanish content of a property the actor manages
+// into locale. Translations edited by hand are only replaced with overwrite.
+func (s *PropertyTranslationService) AutoTranslate(propertyID, locale string, overwrite bool, actor domain.Actor) (*domain.PropertyTranslation, error) {
+	if s.translator == nil {
+		return nil, fmt.Errorf("translation unavailable: no translation provider is configured")
+	}
+	property, err := s.checkManager(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := domain.NormalizeLocale(locale)
+	if normalized == "" || normalized == domain.DefaultLocale {
+		return nil, fmt.Errorf("invalid locale: %s", locale)
+	}
+
+	translations, err := s.repo.GetTranslations(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := translations[normalized]; ok && existing.Title != "" && !existing.Machine && !overwrite {
+		return nil, fmt.Errorf("translation already edited: set overwrite to replace it")
+	}
+
+	translated, err := s.machineTranslate(property, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetTranslation(propertyID, normalized, translated); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Property %s machine-translated to %s with %s by %s", propertyID, normalized, s.translator.Name(), actor.UserID)
+	return &translated, nil
+}
+
+// TranslateListing machine-translates a published listing into every locale it has no
+// translation for, or whose machine translation is of older Spanish content. It runs
+// when listings are saved; failures are logged.
+func (s *PropertyTranslationService) TranslateListing(property domain.Property) {
+	if s.translator == nil || property.Status != domain.StatusAvailable {
+		return
+	}
+
+	translations, err := s.repo.GetTranslations(property.ID)
+	if err != nil {
+		s.logger.Printf("Error loading translations of property %s: %v", property.ID, err)
+		return
+	}
+
+	_, _, hash := property.TranslationSource()
+	for _, locale := range domain.TranslationLocales() {
+		if !translations.NeedsMachineTranslation(locale, hash) {
+			continue
+		}
+		translated, err := s.machineTranslate(&property, locale)
+		if err == nil {
+			err = s.repo.SetTranslation(property.ID, locale, translated)
+		}
+		if err != nil {
+			s.logger.Printf("Error machine-translating property %s to %s: %v", property.ID, locale, err)
+			continue
+		}
+		s.logger.Printf("Property %s machine-translated to %s with %s", property.ID, locale, s.translator.Name())
+	}
+}
+
+// machineTranslate translates the Spanish title and description of a property into locale
+func (s *PropertyTranslationService) machineTranslate(property *domain.Property, locale string) (domain.PropertyTranslation, error) {
+	title, description, hash := property.TranslationSource()
+
+	translatedTitle, err := s.translator.Translate(title, domain.DefaultLocale, locale, false)
+	if err != nil {
+		return domain.PropertyTranslation{}, fmt.Errorf("failed to translate title: %w", err)
+	}
+	translatedDescription := ""
+	if description != "" {
+		translatedDescription, err = s.translator.Translate(description, domain.DefaultLocale, locale, true)
+		if err != nil {
+			return domain.PropertyTranslation{}, fmt.Errorf("failed to translate description: %w", err)
+		}
+	}
+
+	translated, err := domain.NewMachineTranslation(locale, translatedTitle, translatedDescription, hash)
+	if err != nil {
+		return domain.PropertyTranslation{}, fmt.Errorf("invalid translation: %w", err)
+	}
+	return translated, nil
+}
+
+// Localize returns a copy of a property response with titles and descriptions in locale,
+// and the locale the response is served in. It understands properties, search results
+// and their paginated and faceted pages; anything else is returned as is. Properties
+// without a translation keep their Spanish content.
+func (s *PropertyTranslationService) Localize(data interface{}, locale string) (interface{}, string) {
+	locale = domain.NormalizeLocale(locale)
+	if locale == "" || locale == domain.DefaultLocale {
+		return data, domain.DefaultLocale
+	}
+
+	switch value := data.(type) {
+	case *domain.Property:
+		if value == nil {
+			return data, domain.DefaultLocale
+		}
+		properties := s.localizeProperties([]domain.Property{*value}, locale)
+		return &properties[0], properties[0].Locale
+	case []domain.Property:
+		properties := s.localizeProperties(value, locale)
+		return properties, locale
+	case []repository.PropertySearchResult:
+		return s.localizeResults(value, locale), locale
+	case *domain.PaginatedResponse:
+		if value == nil {
+			return data, domain.DefaultLocale
+		}
+		page := *value
+		page.Data, _ = s.Localize(value.Data, locale)
+		return &page, locale
+	case *domain.FacetedSearchResponse:
+		if value == nil {
+			return data, domain.DefaultLocale
+		}
+		page := *value
+		page.Data, _ = s.Localize(value.Data, locale)
+		return &page, locale
+	default:
+		return data, domain.DefaultLocale
+	}
+}
+
+// localizeProperties returns localized copies of properties
+func (s *PropertyTranslationService) localizeProperties(properties []domain.Property, locale string) []domain.Property {
+	ids := make([]string, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+	translations := s.translations(ids)
+
+	localized := make([]domain.Property, len(properties))
+	for i := range properties {
+		localized[i] = properties[i]
+		localized[i].Locale = localized[i].Localize(translations[properties[i].ID], locale)
+	}
+	return localized
+}
+
+// localizeResults returns localized copies of search results
+func (s *PropertyTranslationService) localizeResults(results []repository.PropertySearchResult, locale string) []repository.PropertySearchResult {
+	ids := make([]string, len(results))
+	for i := range results {
+		ids[i] = results[i].Property.ID
+	}
+	translations := s.translations(ids)
+
+	localized := make([]repository.PropertySearchResult, len(results))
+	for i := range results {
+		localized[i] = results[i]
+		localized[i].Property.Locale = localized[i].Property.Localize(translations[results[i].Property.ID], locale)
+	}
+	return localized
+}
+
+// translations loads the translations of properties; on failure the content is served in Spanish
+func (s *PropertyTranslationService) translations(ids []string) map[string]domain.PropertyTranslations {
+	translations, err := s.repo.GetTranslationsByIDs(ids)
+	if err != nil {
+		s.logger.Printf("Error loading property translations: %v", err)
+		return map[string]domain.PropertyTranslations{}
+	}
+	return translations
+}
+
+// checkManager verifies the actor manages the property and returns it
+func (s *PropertyTranslationService) checkManager(propertyID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can translate it")
+	}
+	return property, nil
+}
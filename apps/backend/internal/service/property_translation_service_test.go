@@ -0,0 +1,200 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// memoryTranslationRepository is an in-memory PropertyTranslationRepository
+type memoryTranslationRepository struct {
+	translations map[string]domain.PropertyTranslations
+	err          error
+}
+
+func (r *memoryTranslationRepository) GetTranslations(propertyID string) (domain.PropertyTranslations, error) {
+	return r.translations[propertyID], r.err
+}
+
+func (r *memoryTranslationRepository) GetTranslationsByIDs(propertyIDs []string) (map[string]domain.PropertyTranslations, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	result := map[string]domain.PropertyTranslations{}
+	for _, id := range propertyIDs {
+		if translations, ok := r.translations[id]; ok {
+			result[id] = translations
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryTranslationRepository) SetTranslation(propertyID, locale string, translation domain.PropertyTranslation) error {
+	if r.translations[propertyID] == nil {
+		r.translations[propertyID] = domain.PropertyTranslations{}
+	}
+	r.translations[propertyID][locale] = translation
+	return nil
+}
+
+func (r *memoryTranslationRepository) DeleteTranslation(propertyID, locale string) error {
+	if _, ok := r.translations[propertyID][locale]; !ok {
+		return fmt.Errorf("translation not found: %s/%s", propertyID, locale)
+	}
+	delete(r.translations[propertyID], locale)
+	return nil
+}
+
+func TestPropertyTranslationService_SetTranslation(t *testing.T) {
+	property := domain.NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	repo := &memoryTranslationRepository{translations: map[string]domain.PropertyTranslations{}}
+	service := NewPropertyTranslationService(repo, propertyRepo, log.New(os.Stderr, "", 0))
+
+	_, err := service.SetTranslation("prop-1", "en", "House in Cumbayá", "", domain.NewActor("buyer-1", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	_, err = service.SetTranslation("prop-1", "es", "Casa", "", owner)
+	assert.ErrorContains(t, err, "invalid translation")
+
+	translation, err := service.SetTranslation("prop-1", "en-GB", "House in Cumbayá", "House with garden", owner)
+	require.NoError(t, err)
+	assert.Equal(t, "House in Cumbayá", translation.Title)
+	assert.Equal(t, "House with garden", repo.translations["prop-1"]["en"].Description)
+
+	assert.ErrorContains(t, service.DeleteTranslation("prop-1", "es", owner), "invalid locale")
+	require.NoError(t, service.DeleteTranslation("prop-1", "en", owner))
+	assert.ErrorContains(t, service.DeleteTranslation("prop-1", "en", owner), "not found")
+}
+
+func TestPropertyTranslationService_Localize(t *testing.T) {
+	translated := *domain.NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	translated.ID = "prop-1"
+	untranslated := *domain.NewProperty("Suite en Salinas", "Vista al mar", "Santa Elena", "Salinas", "apartment", 90000, "owner-2")
+	untranslated.ID = "prop-2"
+
+	repo := &memoryTranslationRepository{translations: map[string]domain.PropertyTranslations{
+		"prop-1": {domain.LocaleEnglish: {Title: "House in Cumbayá", Description: "House with garden"}},
+	}}
+	service := NewPropertyTranslationService(repo, new(MockPropertyRepository), log.New(os.Stderr, "", 0))
+
+	properties := []domain.Property{translated, untranslated}
+	localized, locale := service.Localize(properties, "en")
+	assert.Equal(t, domain.LocaleEnglish, locale)
+	result := localized.([]domain.Property)
+	assert.Equal(t, "House in Cumbayá", result[0].Title)
+	assert.Equal(t, domain.LocaleEnglish, result[0].Locale)
+	assert.Equal(t, "Suite en Salinas", result[1].Title)
+	assert.Equal(t, domain.LocaleSpanish, result[1].Locale)
+	assert.Equal(t, "Casa en Cumbayá", properties[0].Title, "the input must not be modified")
+
+	page := &domain.PaginatedResponse{Data: []repository.PropertySearchResult{{Property: translated, Rank: 0.5}}}
+	localized, _ = service.Localize(page, "en")
+	results := localized.(*domain.PaginatedResponse).Data.([]repository.PropertySearchResult)
+	assert.Equal(t, "House in Cumbayá", results[0].Property.Title)
+	assert.Equal(t, "Casa en Cumbayá", page.Data.([]repository.PropertySearchResult)[0].Property.Title)
+
+	single, locale := service.Localize(&untranslated, "en")
+	assert.Equal(t, domain.LocaleSpanish, locale)
+	assert.Equal(t, "Suite en Salinas", single.(*domain.Property).Title)
+
+	repo.err = fmt.Errorf("connection refused")
+	single, locale = service.Localize(&translated, "en")
+	assert.Equal(t, domain.LocaleSpanish, locale, "translation failures fall back to Spanish")
+	assert.Equal(t, "Casa en Cumbayá", single.(*domain.Property).Title)
+}
+
+// stubTranslator prefixes text with the target locale and counts requests
+type stubTranslator struct {
+	calls int
+}
+
+func (t *stubTranslator) Name() string {
+	return "stub"
+}
+
+func (t *stubTranslator) Translate(text, source, target string, html bool) (string, error) {
+	t.calls++
+	return "[" + target + "] " + text, nil
+}
+
+func TestPropertyTranslationService_AutoTranslate(t *testing.T) {
+	property := domain.NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	repo := &memoryTranslationRepository{translations: map[string]domain.PropertyTranslations{}}
+	service := NewPropertyTranslationService(repo, propertyRepo, log.New(os.Stderr, "", 0))
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+
+	_, err := service.AutoTranslate("prop-1", "en", false, owner)
+	assert.ErrorContains(t, err, "translation unavailable")
+
+	translator := &stubTranslator{}
+	service.SetTranslator(translator)
+	_, err = service.AutoTranslate("prop-1", "es", false, owner)
+	assert.ErrorContains(t, err, "invalid locale")
+
+	translated, err := service.AutoTranslate("prop-1", "en", false, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "[en] Casa en Cumbayá", translated.Title)
+	assert.Equal(t, "[en] <p>Casa con jardín</p>", translated.Description)
+	assert.True(t, repo.translations["prop-1"]["en"].Machine)
+
+	// Editing a machine translation makes it the agency's
+	_, err = service.SetTranslation("prop-1", "en", "House in Cumbayá", "House with garden", owner)
+	require.NoError(t, err)
+	_, err = service.AutoTranslate("prop-1", "en", false, owner)
+	assert.ErrorContains(t, err, "already edited")
+
+	_, err = service.AutoTranslate("prop-1", "en", true, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "[en] Casa en Cumbayá", repo.translations["prop-1"]["en"].Title)
+}
+
+func TestPropertyTranslationService_TranslateListing(t *testing.T) {
+	property := *domain.NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.Status = domain.StatusAvailable
+
+	repo := &memoryTranslationRepository{translations: map[string]domain.PropertyTranslations{}}
+	service := NewPropertyTranslationService(repo, new(MockPropertyRepository), log.New(os.Stderr, "", 0))
+	translator := &stubTranslator{}
+	service.SetTranslator(translator)
+
+	service.TranslateListing(property)
+	assert.Equal(t, "[en] Casa en Cumbayá", repo.translations["prop-1"]["en"].Title)
+	assert.Equal(t, 2, translator.calls)
+
+	// Republishing unchanged content keeps the translation
+	service.TranslateListing(property)
+	assert.Equal(t, 2, translator.calls)
+
+	property.Title = "Casa con piscina en Cumbayá"
+	service.TranslateListing(property)
+	assert.Equal(t, "[en] Casa con piscina en Cumbayá", repo.translations["prop-1"]["en"].Title)
+
+	// Translations edited by hand are never replaced
+	repo.translations["prop-1"]["en"] = domain.PropertyTranslation{Title: "Pool house in Cumbayá"}
+	property.Title = "Casa en venta en Cumbayá"
+	service.TranslateListing(property)
+	assert.Equal(t, "Pool house in Cumbayá", repo.translations["prop-1"]["en"].Title)
+
+	quarantined := property
+	quarantined.ID = "prop-2"
+	quarantined.Status = domain.StatusQuarantined
+	service.TranslateListing(quarantined)
+	assert.Empty(t, repo.translations["prop-2"], "unpublished listings are not translated")
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/repository"
+)
+
+// rentGatewayTolerance is how far the timestamp of a gateway confirmation can be from now
+const rentGatewayTolerance = 5 * time.Minute
+
+// RentNotifier tells the parties of a lease about overdue and settled rent
+type RentNotifier interface {
+	// NotifyRentOverdue notifies the tenant and the landlord of an unpaid charge
+	NotifyRentOverdue(charge *domain.RentCharge, property *domain.Property) error
+
+	// NotifyRentPaid notifies the tenant and the landlord that a charge was paid
+	NotifyRentPaid(charge *domain.RentCharge, property *domain.Property) error
+}
+
+// LogRentNotifier writes rent notices to the log. It is used until an email provider
+// is configured.
+type LogRentNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogRentNotifier creates a notifier that logs rent notices
+func NewLogRentNotifier(logger *log.Logger) *LogRentNotifier {
+	return &LogRentNotifier{logger: logger}
+}
+
+// NotifyRentOverdue logs the overdue notice
+func (n *LogRentNotifier) NotifyRentOverdue(charge *domain.RentCharge, property *domain.Property) error {
+	n.logger.Printf("Rent of %s for %q (%s) due %s is overdue; notifying %v",
+		charge.Period.Format("2006-01"), property.Title, property.ID, charge.DueDate.Format(domain.CalendarDateLayout),
+		append([]string{charge.TenantID}, property.GetManagers()...))
+	return nil
+}
+
+// NotifyRentPaid logs the payment
+func (n *LogRentNotifier) NotifyRentPaid(charge *domain.RentCharge, property *domain.Property) error {
+	n.logger.Printf("Rent of %s for %q (%s) paid (%s); notifying %v",
+		charge.Period.Format("2006-01"), property.Title, property.ID, charge.PaymentMethod,
+		append([]string{charge.TenantID}, property.GetManagers()...))
+	return nil
+}
+
+// ScheduleRentRequest schedules the monthly charges of a lease
+type ScheduleRentRequest struct {
+	FirstDueDate time.Time `json:"first_due_date"`
+	Months       int       `json:"months"`
+	// Amount defaults to the rent of the deal
+	Amount float64 `json:"amount,omitempty"`
+}
+
+// RecordRentPaymentRequest records a payment received outside the gateway
+type RecordRentPaymentRequest struct {
+	Amount    float64   `json:"amount"`
+	Reference string    `json:"reference,omitempty"`
+	PaidAt    time.Time `json:"paid_at,omitempty"`
+}
+
+// RentGatewayConfirmation is the body a payment gateway posts when a rent payment clears
+type RentGatewayConfirmation struct {
+	ChargeID  string    `json:"charge_id"`
+	Amount    float64   `json:"amount"`
+	Reference string    `json:"reference"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// RentLedgerService keeps the rent ledger of leases, the rent deals of rented
+// properties. The property's managers schedule charges and record payments; the
+// tenant, the client of the deal, can follow the ledger and download receipts.
+type RentLedgerService struct {
+	repo          repository.RentLedgerRepository
+	dealRepo      repository.DealRepository
+	propertyRepo  repository.PropertyRepository
+	renderer      processors.PDFRenderer
+	notifier      RentNotifier
+	gatewaySecret string
+	now           func() time.Time
+	logger        *log.Logger
+}
+
+// NewRentLedgerService creates a rent ledger service. Receipts are laid out by renderer.
+func NewRentLedgerService(
+	repo repository.RentLedgerRepository,
+	dealRepo repository.DealRepository,
+	propertyRepo repository.PropertyRepository,
+	renderer processors.PDFRenderer,
+	notifier RentNotifier,
+	logger *log.Logger,
+) *RentLedgerService {
+	if notifier == nil {
+		notifier = NewLogRentNotifier(logger)
+	}
+
+	return &RentLedgerService{
+		repo:         repo,
+		dealRepo:     dealRepo,
+		propertyRepo: propertyRepo,
+		renderer:     renderer,
+		notifier:     notifier,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetGatewaySecret enables payment confirmations from a gateway signing its requests
+// with the secret, as webhook deliveries are signed
+func (s *RentLedgerService) SetGatewaySecret(secret string) {
+	s.gatewaySecret = secret
+}
+
+// ScheduleCharges schedules the monthly charges of a lease managed by the actor
+func (s *RentLedgerService) ScheduleCharges(dealID string, req ScheduleRentRequest, actor domain.Actor) (*domain.RentLedger, error) {
+	deal, property, err := s.lease(dealID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can schedule rent")
+	}
+	if req.FirstDueDate.IsZero() {
+		return nil, fmt.Errorf("invalid schedule: first due date is required")
+	}
+
+	charges, err := domain.ScheduleRentCharges(deal, req.FirstDueDate, req.Months, req.Amount, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateCharges(charges); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("%d rent charges scheduled for lease %s by %s", len(charges), deal.ID, actor.UserID)
+	return s.ledger(deal)
+}
+
+// GetLedger returns the rent ledger of a lease to its tenant or the property's managers
+func (s *RentLedgerService) GetLedger(dealID string, actor domain.Actor) (*domain.RentLedger, error) {
+	deal, property, err := s.lease(dealID)
+	if err != nil {
+		return nil, err
+	}
+	if !isLeaseParty(deal, property, actor) {
+		return nil, fmt.Errorf("permission denied: only the tenant and the property's managers can see this lease")
+	}
+	return s.ledger(deal)
+}
+
+// RecordPayment records a payment received by the property's managers
+func (s *RentLedgerService) RecordPayment(chargeID string, req RecordRentPaymentRequest, actor domain.Actor) (*domain.RentCharge, error) {
+	charge, err := s.repo.GetCharge(chargeID)
+	if err != nil {
+		return nil, err
+	}
+	_, property, err := s.lease(charge.DealID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can record payments")
+	}
+
+	if err := charge.RecordPayment(req.Amount, domain.RentPaymentManual, req.Reference, actor.UserID, req.PaidAt, s.now()); err != nil {
+		return nil, err
+	}
+	return charge, s.settle(charge, property)
+}
+
+// ConfirmGatewayPayment settles a charge paid through the payment gateway. The body
+// must be signed with the gateway secret; confirmations repeated by the gateway with
+// the same reference are acknowledged without changes.
+func (s *RentLedgerService) ConfirmGatewayPayment(body []byte, signature string) (*domain.RentCharge, error) {
+	if s.gatewaySecret == "" {
+		return nil, fmt.Errorf("payment gateway not configured")
+	}
+	if err := domain.VerifyWebhookSignature(s.gatewaySecret, signature, body, s.now(), rentGatewayTolerance); err != nil {
+		return nil, fmt.Errorf("permission denied: %w", err)
+	}
+
+	var confirmation RentGatewayConfirmation
+	if err := json.Unmarshal(body, &confirmation); err != nil {
+		return nil, fmt.Errorf("invalid confirmation: %w", err)
+	}
+	if confirmation.Reference == "" {
+		return nil, fmt.Errorf("invalid confirmation: reference is required")
+	}
+
+	charge, err := s.repo.GetCharge(confirmation.ChargeID)
+	if err != nil {
+		return nil, err
+	}
+	if charge.IsSettled() && charge.PaymentMethod == domain.RentPaymentGateway && charge.PaymentReference == confirmation.Reference {
+		return charge, nil
+	}
+	_, property, err := s.lease(charge.DealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := charge.RecordPayment(confirmation.Amount, domain.RentPaymentGateway, confirmation.Reference, "", confirmation.PaidAt, s.now()); err != nil {
+		return nil, err
+	}
+	return charge, s.settle(charge, property)
+}
+
+// Receipt renders the PDF receipt of a paid charge for the tenant or the property's managers
+func (s *RentLedgerService) Receipt(chargeID string, actor domain.Actor) (*domain.RentCharge, []byte, error) {
+	charge, err := s.repo.GetCharge(chargeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	deal, property, err := s.lease(charge.DealID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isLeaseParty(deal, property, actor) {
+		return nil, nil, fmt.Errorf("permission denied: only the tenant and the property's managers can download receipts")
+	}
+	if !charge.IsSettled() {
+		return nil, nil, fmt.Errorf("invalid receipt: rent of %s is not paid", charge.Period.Format("2006-01"))
+	}
+
+	pdf, err := s.renderer.Render("Recibo de pago de arriendo", domain.RentReceiptText(charge, property, s.now()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+	return charge, pdf, nil
+}
+
+// ProcessOverdue marks the pending charges past their due date overdue and notifies
+// the parties of each lease once, returning how many charges became overdue
+func (s *RentLedgerService) ProcessOverdue() (int, error) {
+	now := s.now()
+	charges, err := s.repo.ListPendingDueBefore(domain.CalendarDate(now))
+	if err != nil {
+		return 0, err
+	}
+
+	overdue := 0
+	for i := range charges {
+		charge := &charges[i]
+		property, err := s.propertyRepo.GetByID(charge.PropertyID)
+		if err != nil {
+			s.logger.Printf("Error loading property of rent charge %s: %v", charge.ID, err)
+			continue
+		}
+		if err := s.repo.MarkOverdue(charge.ID, now); err != nil {
+			s.logger.Printf("Error marking rent charge %s overdue: %v", charge.ID, err)
+			continue
+		}
+		charge.Status = domain.RentChargeOverdue
+		if err := s.notifier.NotifyRentOverdue(charge, property); err != nil {
+			s.logger.Printf("Error notifying overdue rent charge %s: %v", charge.ID, err)
+		}
+		overdue++
+	}
+	return overdue, nil
+}
+
+// settle saves a payment recorded on a charge and notifies the parties
+func (s *RentLedgerService) settle(charge *domain.RentCharge, property *domain.Property) error {
+	if err := s.repo.RecordPayment(charge); err != nil {
+		return err
+	}
+	if err := s.notifier.NotifyRentPaid(charge, property); err != nil {
+		s.logger.Printf("Error notifying rent payment %s: %v", charge.ID, err)
+	}
+	return nil
+}
+
+// ledger totals the charges of a lease
+func (s *RentLedgerService) ledger(deal *domain.Deal) (*domain.RentLedger, error) {
+	charges, err := s.repo.ListByDeals(deal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewRentLedger(deal.ID, deal.PropertyID, charges, s.now()), nil
+}
+
+// lease retrieves a rent deal and its property
+func (s *RentLedgerService) lease(dealID string) (*domain.Deal, *domain.Property, error) {
+	deal, err := s.dealRepo.GetByID(dealID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if deal.Type != domain.DealTypeRent {
+		return nil, nil, fmt.Errorf("invalid lease: only rent deals have a rent ledger")
+	}
+	property, err := s.propertyRepo.GetByID(deal.PropertyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("property not found: %w", err)
+	}
+	return deal, property, nil
+}
+
+// isLeaseParty reports whether the actor is the tenant of a lease or manages its property
+func isLeaseParty(deal *domain.Deal, property *domain.Property, actor domain.Actor) bool {
+	if deal.ClientID != nil && *deal.ClientID == actor.UserID && actor.UserID != "" {
+		return true
+	}
+	return canManageListing(property, actor)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+)
+
+// memoryRentLedger keeps rent charges in memory
+type memoryRentLedger struct {
+	charges map[string]domain.RentCharge
+}
+
+func (m *memoryRentLedger) CreateCharges(charges []domain.RentCharge) error {
+	for _, charge := range charges {
+		for _, existing := range m.charges {
+			if existing.DealID == charge.DealID && existing.Period.Equal(charge.Period) {
+				return fmt.Errorf("rent already scheduled for %s", charge.Period.Format("2006-01"))
+			}
+		}
+	}
+	for _, charge := range charges {
+		m.charges[charge.ID] = charge
+	}
+	return nil
+}
+
+func (m *memoryRentLedger) GetCharge(id string) (*domain.RentCharge, error) {
+	charge, ok := m.charges[id]
+	if !ok {
+		return nil, fmt.Errorf("rent charge not found: %s", id)
+	}
+	return &charge, nil
+}
+
+func (m *memoryRentLedger) ListByDeals(dealIDs ...string) ([]domain.RentCharge, error) {
+	charges := []domain.RentCharge{}
+	for _, charge := range m.charges {
+		for _, id := range dealIDs {
+			if charge.DealID == id {
+				charges = append(charges, charge)
+			}
+		}
+	}
+	sort.Slice(charges, func(i, j int) bool { return charges[i].DueDate.Before(charges[j].DueDate) })
+	return charges, nil
+}
+
+func (m *memoryRentLedger) RecordPayment(charge *domain.RentCharge) error {
+	if stored := m.charges[charge.ID]; stored.IsSettled() {
+		return fmt.Errorf("rent charge already paid: %s", charge.ID)
+	}
+	m.charges[charge.ID] = *charge
+	return nil
+}
+
+func (m *memoryRentLedger) ListPendingDueBefore(date time.Time) ([]domain.RentCharge, error) {
+	charges := []domain.RentCharge{}
+	for _, charge := range m.charges {
+		if charge.Status == domain.RentChargePending && charge.DueDate.Before(date) {
+			charges = append(charges, charge)
+		}
+	}
+	return charges, nil
+}
+
+func (m *memoryRentLedger) MarkOverdue(id string, notifiedAt time.Time) error {
+	charge := m.charges[id]
+	charge.Status = domain.RentChargeOverdue
+	charge.OverdueNotifiedAt = &notifiedAt
+	m.charges[id] = charge
+	return nil
+}
+
+// recordingRentNotifier records the notified rent events
+type recordingRentNotifier struct {
+	events []string
+}
+
+func (n *recordingRentNotifier) NotifyRentOverdue(charge *domain.RentCharge, property *domain.Property) error {
+	n.events = append(n.events, "overdue "+charge.Period.Format("2006-01"))
+	return nil
+}
+
+func (n *recordingRentNotifier) NotifyRentPaid(charge *domain.RentCharge, property *domain.Property) error {
+	n.events = append(n.events, "paid "+charge.Period.Format("2006-01"))
+	return nil
+}
+
+func newTestRentLedgerService(now time.Time) (*RentLedgerService, *memoryRentLedger, *recordingRentNotifier) {
+	property := domain.NewProperty("Departamento", "Departamento en La Carolina", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	property.ID = "prop-1"
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	tenantID := "tenant-1"
+	deals := &memoryDealRepository{deals: []domain.Deal{
+		{ID: "lease-1", PropertyID: "prop-1", Type: domain.DealTypeRent, FinalPrice: 800, ClientID: &tenantID},
+		{ID: "sale-1", PropertyID: "prop-1", Type: domain.DealTypeSale, FinalPrice: 120000},
+	}}
+
+	repo := &memoryRentLedger{charges: map[string]domain.RentCharge{}}
+	notifier := &recordingRentNotifier{}
+	svc := NewRentLedgerService(repo, deals, propertyRepo, processors.NewTextPDFRenderer(), notifier, log.New(&bytes.Buffer{}, "", 0))
+	svc.now = func() time.Time { return now }
+	return svc, repo, notifier
+}
+
+func TestRentLedgerService_ScheduleAndPay(t *testing.T) {
+	now := time.Date(2025, 10, 8, 10, 0, 0, 0, time.UTC)
+	svc, _, notifier := newTestRentLedgerService(now)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	tenant := domain.NewActor("tenant-1", string(domain.RoleBuyer), "")
+	stranger := domain.NewActor("buyer-2", string(domain.RoleBuyer), "")
+	schedule := ScheduleRentRequest{FirstDueDate: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC), Months: 12}
+
+	_, err := svc.ScheduleCharges("lease-1", schedule, tenant)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = svc.ScheduleCharges("sale-1", schedule, owner)
+	assert.ErrorContains(t, err, "only rent deals")
+
+	ledger, err := svc.ScheduleCharges("lease-1", schedule, owner)
+	require.NoError(t, err)
+	require.Len(t, ledger.Charges, 12)
+	assert.Equal(t, 800.0, ledger.Outstanding)
+	_, err = svc.ScheduleCharges("lease-1", schedule, owner)
+	assert.ErrorContains(t, err, "already scheduled")
+
+	_, err = svc.GetLedger("lease-1", stranger)
+	assert.ErrorContains(t, err, "permission denied")
+	ledger, err = svc.GetLedger("lease-1", tenant)
+	require.NoError(t, err)
+	first := ledger.Charges[0]
+
+	_, _, err = svc.Receipt(first.ID, tenant)
+	assert.ErrorContains(t, err, "not paid")
+	_, err = svc.RecordPayment(first.ID, RecordRentPaymentRequest{Amount: 800}, tenant)
+	assert.ErrorContains(t, err, "permission denied", "tenants cannot mark their own rent paid")
+
+	paid, err := svc.RecordPayment(first.ID, RecordRentPaymentRequest{Amount: 800, Reference: "Transferencia 123"}, owner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RentPaymentManual, paid.PaymentMethod)
+	assert.Equal(t, []string{"paid 2025-10"}, notifier.events)
+
+	charge, pdf, err := svc.Receipt(first.ID, tenant)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF", string(pdf[:4]))
+	assert.Contains(t, charge.ReceiptNumber(), "REC-202510-")
+}
+
+func TestRentLedgerService_GatewayAndOverdue(t *testing.T) {
+	now := time.Date(2025, 11, 8, 10, 0, 0, 0, time.UTC)
+	svc, repo, notifier := newTestRentLedgerService(now)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	ledger, err := svc.ScheduleCharges("lease-1", ScheduleRentRequest{FirstDueDate: time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC), Months: 3}, owner)
+	require.NoError(t, err)
+	october, november := ledger.Charges[0], ledger.Charges[1]
+
+	body := []byte(fmt.Sprintf(`{"charge_id": %q, "amount": 800, "reference": "pay_123", "paid_at": "2025-11-08T09:00:00Z"}`, october.ID))
+	_, err = svc.ConfirmGatewayPayment(body, domain.SignWebhookPayload("gw_secret", now, body))
+	assert.ErrorContains(t, err, "not configured")
+
+	svc.SetGatewaySecret("gw_secret")
+	_, err = svc.ConfirmGatewayPayment(body, domain.SignWebhookPayload("other", now, body))
+	assert.ErrorContains(t, err, "permission denied")
+
+	charge, err := svc.ConfirmGatewayPayment(body, domain.SignWebhookPayload("gw_secret", now, body))
+	require.NoError(t, err)
+	assert.Equal(t, domain.RentPaymentGateway, charge.PaymentMethod)
+	_, err = svc.ConfirmGatewayPayment(body, domain.SignWebhookPayload("gw_secret", now, body))
+	assert.NoError(t, err, "repeated confirmations are acknowledged")
+
+	overdue, err := svc.ProcessOverdue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, overdue, "only november is unpaid past its due date")
+	assert.Equal(t, domain.RentChargeOverdue, repo.charges[november.ID].Status)
+	assert.Equal(t, []string{"paid 2025-10", "overdue 2025-11"}, notifier.events)
+
+	overdue, err = svc.ProcessOverdue()
+	require.NoError(t, err)
+	assert.Zero(t, overdue, "overdue charges are notified once")
+}
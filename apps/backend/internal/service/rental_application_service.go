@@ -0,0 +1,453 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// ApplicationNotifier tells landlords about new applications and applicants about decisions
+type ApplicationNotifier interface {
+	// NotifyApplicationSubmitted notifies the owner or agent of the property
+	NotifyApplicationSubmitted(application *domain.RentalApplication, property *domain.Property) error
+
+	// NotifyApplicationStatusChanged notifies the applicant, or the landlord when the
+	// applicant withdrew
+	NotifyApplicationStatusChanged(application *domain.RentalApplication, property *domain.Property) error
+}
+
+// LogApplicationNotifier writes application notices to the log. It is used until an email
+// provider is configured.
+type LogApplicationNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogApplicationNotifier creates a notifier that logs application notices
+func NewLogApplicationNotifier(logger *log.Logger) *LogApplicationNotifier {
+	return &LogApplicationNotifier{logger: logger}
+}
+
+// NotifyApplicationSubmitted logs the new application
+func (n *LogApplicationNotifier) NotifyApplicationSubmitted(application *domain.RentalApplication, property *domain.Property) error {
+	n.logger.Printf("Rental application %s submitted for %q (%s); notifying %v",
+		application.ID, property.Title, property.ID, property.GetManagers())
+	return nil
+}
+
+// NotifyApplicationStatusChanged logs the decision
+func (n *LogApplicationNotifier) NotifyApplicationStatusChanged(application *domain.RentalApplication, property *domain.Property) error {
+	recipients := []string{application.ApplicantID}
+	if application.Status == domain.ApplicationStatusWithdrawn {
+		recipients = property.GetManagers()
+	}
+	n.logger.Printf("Rental application %s for %q is now %s; notifying %v",
+		application.ID, property.Title, application.Status, recipients)
+	return nil
+}
+
+// SubmitApplicationRequest describes a rental application
+type SubmitApplicationRequest struct {
+	PropertyID    string                   `json:"property_id"`
+	MonthlyIncome float64                  `json:"monthly_income"`
+	Employer      string                   `json:"employer,omitempty"`
+	Occupation    string                   `json:"occupation,omitempty"`
+	Occupants     int                      `json:"occupants"`
+	HasPets       bool                     `json:"has_pets"`
+	MoveInDate    *time.Time               `json:"move_in_date,omitempty"`
+	Message       string                   `json:"message,omitempty"`
+	References    []domain.TenantReference `json:"references"`
+	// Website is a honeypot: the form hides it from people, so only bots fill it in
+	Website string `json:"website,omitempty"`
+}
+
+// RentalApplicationService handles applications of prospective tenants to rent listings.
+// Applications are only visible to the applicant and to whoever manages the listing.
+type RentalApplicationService struct {
+	repo         repository.RentalApplicationRepository
+	propertyRepo repository.PropertyRepository
+	storage      storage.ImageStorage
+	scanner      DocumentScanner
+	notifier     ApplicationNotifier
+	events       EventPublisher
+	outbox       ApplicationOutboxWriter
+	spam         *SpamService
+	router       LeadRouter
+	sla          ApplicationResponseTracker
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// ApplicationOutboxWriter saves rental applications together with their outbox events
+type ApplicationOutboxWriter interface {
+	CreateWithEvents(application *domain.RentalApplication, events ...*domain.OutboxEvent) error
+}
+
+// LeadRouter scores submitted applications and routes them to an agent of the
+// listing's agency, e.g. LeadRoutingService
+type LeadRouter interface {
+	RouteLead(application *domain.RentalApplication, property *domain.Property) (*domain.LeadAssignment, error)
+}
+
+// NewRentalApplicationService creates a new rental application service. Supporting
+// documents are written through storage under an applications/ prefix.
+func NewRentalApplicationService(
+	repo repository.RentalApplicationRepository,
+	propertyRepo repository.PropertyRepository,
+	storage storage.ImageStorage,
+	notifier ApplicationNotifier,
+	logger *log.Logger,
+) *RentalApplicationService {
+	if notifier == nil {
+		notifier = NewLogApplicationNotifier(logger)
+	}
+
+	return &RentalApplicationService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		storage:      storage,
+		notifier:     notifier,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetDocumentScanner scans supporting documents before they are stored
+func (s *RentalApplicationService) SetDocumentScanner(scanner DocumentScanner) {
+	s.scanner = scanner
+}
+
+// SetEventPublisher publishes an inquiry.created event for each submitted application.
+// The event carries the application without the applicant's references.
+func (s *RentalApplicationService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
+// SetOutbox records inquiry.created in the transactional outbox instead of publishing
+// it after the application is stored
+func (s *RentalApplicationService) SetOutbox(outbox ApplicationOutboxWriter) {
+	s.outbox = outbox
+}
+
+// SetSpamService screens submitted applications for spam. Quarantined applications
+// are hidden from the landlord, who is only notified once an administrator approves
+// them.
+func (s *RentalApplicationService) SetSpamService(spam *SpamService) {
+	s.spam = spam
+	spam.SetReleaser(domain.SpamEntityInquiry, s)
+}
+
+// SetLeadRouter routes submitted applications on agency listings to agents. Quarantined
+// applications are routed once spam review approves them.
+func (s *RentalApplicationService) SetLeadRouter(router LeadRouter) {
+	s.router = router
+}
+
+// SetResponseTracker counts reviewing an application as answering the lead it was
+// routed as
+func (s *RentalApplicationService) SetResponseTracker(sla ApplicationResponseTracker) {
+	s.sla = sla
+}
+
+// Submit applies to rent an available rent listing on behalf of the actor
+func (s *RentalApplicationService) Submit(req SubmitApplicationRequest, actor domain.Actor) (*domain.RentalApplication, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to apply")
+	}
+
+	property, err := s.propertyRepo.GetByID(req.PropertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !property.IsForRent() || property.Status != domain.StatusAvailable {
+		return nil, fmt.Errorf("invalid property: only available rent listings accept applications")
+	}
+	if canManageListing(property, actor) {
+		return nil, fmt.Errorf("invalid application: you manage this listing")
+	}
+
+	open, err := s.repo.HasOpenApplication(property.ID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, fmt.Errorf("application already submitted for this property")
+	}
+
+	application, err := domain.NewRentalApplication(property.ID, actor.UserID, req.MonthlyIncome, req.Occupants, req.References)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application: %w", err)
+	}
+	application.Employer = req.Employer
+	application.Occupation = req.Occupation
+	application.HasPets = req.HasPets
+	application.MoveInDate = req.MoveInDate
+	application.Message = req.Message
+
+	var assessment domain.SpamAssessment
+	if s.spam != nil {
+		assessment = s.spam.Screen(domain.SpamEntityInquiry, actor.UserID, domain.SpamCheck{
+			Honeypot: req.Website,
+			Text:     strings.Join([]string{req.Message, req.Employer, req.Occupation}, "\n"),
+		})
+	}
+	if assessment.Quarantine() {
+		// Quarantined applications are created without events; the applicant is not told
+		if err := s.repo.Create(application); err != nil {
+			return nil, err
+		}
+		if _, err := s.spam.Quarantine(domain.SpamEntityInquiry, application.ID, actor.UserID, assessment); err != nil {
+			s.logger.Printf("Error quarantining application %s: %v", application.ID, err)
+		}
+		return application, nil
+	}
+
+	if s.outbox != nil {
+		event, err := domain.NewOutboxEvent(domain.EventInquiryCreated, domain.AggregateRentalApplication,
+			application.ID, propertyAgencyID(property), application.InquiryEventData())
+		if err != nil {
+			return nil, err
+		}
+		if err := s.outbox.CreateWithEvents(application, event); err != nil {
+			return nil, err
+		}
+	} else if err := s.repo.Create(application); err != nil {
+		return nil, err
+	}
+
+	s.routeLead(application, property)
+	if err := s.notifier.NotifyApplicationSubmitted(application, property); err != nil {
+		s.logger.Printf("Error notifying application %s: %v", application.ID, err)
+	}
+	if s.events != nil && s.outbox == nil {
+		if err := s.events.Publish(domain.EventInquiryCreated, propertyAgencyID(property), application.InquiryEventData()); err != nil {
+			s.logger.Printf("Error publishing %s for application %s: %v", domain.EventInquiryCreated, application.ID, err)
+		}
+	}
+
+	s.logger.Printf("Rental application %s submitted for property %s", application.ID, property.ID)
+	return application, nil
+}
+
+// ReleaseQuarantined notifies the landlord of an application approved by spam review
+// and publishes its inquiry.created event. Blocked applications stay hidden.
+func (s *RentalApplicationService) ReleaseQuarantined(id string, approved bool) error {
+	if !approved {
+		return nil
+	}
+
+	application, property, err := s.load(id)
+	if err != nil {
+		return err
+	}
+	s.routeLead(application, property)
+	if err := s.notifier.NotifyApplicationSubmitted(application, property); err != nil {
+		s.logger.Printf("Error notifying application %s: %v", application.ID, err)
+	}
+	if s.events != nil {
+		if err := s.events.Publish(domain.EventInquiryCreated, propertyAgencyID(property), application.InquiryEventData()); err != nil {
+			s.logger.Printf("Error publishing %s for application %s: %v", domain.EventInquiryCreated, application.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetApplication retrieves an application visible to the actor. Whoever manages the
+// listing also gets the tenant screening.
+func (s *RentalApplicationService) GetApplication(id string, actor domain.Actor) (*domain.RentalApplication, error) {
+	application, property, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case canManageListing(property, actor):
+		application.Screening = domain.ScreenApplication(application, monthlyRent(property))
+	case application.ApplicantID == actor.UserID:
+	default:
+		return nil, fmt.Errorf("permission denied: application belongs to another user")
+	}
+
+	return application, nil
+}
+
+// ListMyApplications lists the applications submitted by the actor
+func (s *RentalApplicationService) ListMyApplications(actor domain.Actor) ([]domain.RentalApplication, error) {
+	return s.repo.ListByApplicant(actor.UserID)
+}
+
+// ListPropertyApplications lists the applications to a listing managed by the actor,
+// each with its tenant screening
+func (s *RentalApplicationService) ListPropertyApplications(propertyID, status string, actor domain.Actor) ([]domain.RentalApplication, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can review applications")
+	}
+	if status != "" && !domain.IsValidApplicationStatus(status) {
+		return nil, fmt.Errorf("invalid application status: %s", status)
+	}
+
+	applications, err := s.repo.ListByProperty(property.ID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	rent := monthlyRent(property)
+	for i := range applications {
+		applications[i].Screening = domain.ScreenApplication(&applications[i], rent)
+	}
+	return applications, nil
+}
+
+// UpdateStatus moves an application through review. The landlord reviews, accepts or
+// rejects it; only the applicant can withdraw it.
+func (s *RentalApplicationService) UpdateStatus(id, status, reason string, actor domain.Actor) (*domain.RentalApplication, error) {
+	application, property, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == domain.ApplicationStatusWithdrawn {
+		if application.ApplicantID != actor.UserID {
+			return nil, fmt.Errorf("permission denied: only the applicant can withdraw an application")
+		}
+	} else if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can review applications")
+	}
+
+	fromStatus := application.Status
+	if err := application.Transition(status, reason, actor.UserID, s.now()); err != nil {
+		return nil, fmt.Errorf("invalid status change: %w", err)
+	}
+	if err := s.repo.UpdateStatus(application, fromStatus); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.NotifyApplicationStatusChanged(application, property); err != nil {
+		s.logger.Printf("Error notifying application %s: %v", application.ID, err)
+	}
+	if s.sla != nil && status != domain.ApplicationStatusWithdrawn {
+		if err := s.sla.RecordApplicationResponse(application.ID, actor.UserID); err != nil {
+			s.logger.Printf("Error recording response to application %s: %v", application.ID, err)
+		}
+	}
+
+	s.logger.Printf("Rental application %s changed from %s to %s", application.ID, fromStatus, application.Status)
+	return application, nil
+}
+
+// AddDocument attaches a supporting document, such as pay slips, to an open application
+// of the actor
+func (s *RentalApplicationService) AddDocument(id, name, fileName, contentType string, size int64, content io.Reader, actor domain.Actor) (*domain.ApplicationDocument, error) {
+	application, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if application.ApplicantID != actor.UserID {
+		return nil, fmt.Errorf("permission denied: only the applicant can add documents")
+	}
+	if !application.IsOpen() {
+		return nil, fmt.Errorf("invalid application: %s applications cannot be changed", application.Status)
+	}
+	if len(application.Documents) >= domain.MaxApplicationDocuments {
+		return nil, fmt.Errorf("maximum documents per application exceeded: %d", domain.MaxApplicationDocuments)
+	}
+
+	if err := domain.ValidateDocumentUpload(fileName, contentType, size, domain.MaxDocumentUploadSize); err != nil {
+		return nil, fmt.Errorf("upload validation failed: %w", err)
+	}
+
+	// The declared size cannot be used to bypass the limit
+	data, err := io.ReadAll(io.LimitReader(content, domain.MaxDocumentUploadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if int64(len(data)) > domain.MaxDocumentUploadSize {
+		return nil, fmt.Errorf("file too large: max %d bytes", domain.MaxDocumentUploadSize)
+	}
+
+	threat, err := scanDocument(s.scanner, data, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if threat != "" {
+		s.logger.Printf("Document %q for application %s rejected: %s", fileName, application.ID, threat)
+		return nil, fmt.Errorf("upload validation failed: file is infected (%s)", threat)
+	}
+
+	document := domain.NewApplicationDocument(application.ID, name, fileName, contentType, int64(len(data)))
+	storedPath, err := s.storage.Store(data, document.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	document.StoragePath = storedPath
+
+	if err := s.repo.AddDocument(application.ID, document); err != nil {
+		if delErr := s.storage.Delete(storedPath); delErr != nil {
+			s.logger.Printf("Error deleting document file %s: %v", storedPath, delErr)
+		}
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// GetDocument retrieves a supporting document and its content for the applicant or
+// whoever manages the listing
+func (s *RentalApplicationService) GetDocument(id, documentID string, actor domain.Actor) (*domain.ApplicationDocument, []byte, error) {
+	application, err := s.GetApplication(id, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document, ok := application.Document(documentID)
+	if !ok {
+		return nil, nil, fmt.Errorf("document not found: %s", documentID)
+	}
+
+	data, err := s.storage.Retrieve(document.StoragePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve document: %w", err)
+	}
+	return document, data, nil
+}
+
+// routeLead routes an application to an agent when a router is set. Routing failures
+// are logged so they never block applications.
+func (s *RentalApplicationService) routeLead(application *domain.RentalApplication, property *domain.Property) {
+	if s.router == nil {
+		return
+	}
+	if _, err := s.router.RouteLead(application, property); err != nil {
+		s.logger.Printf("Error routing application %s: %v", application.ID, err)
+	}
+}
+
+// load retrieves an application and its property
+func (s *RentalApplicationService) load(id string) (*domain.RentalApplication, *domain.Property, error) {
+	application, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	property, err := s.propertyRepo.GetByID(application.PropertyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("property not found: %w", err)
+	}
+	return application, property, nil
+}
+
+// monthlyRent returns the rent a property is listed for, or 0 when it is not for rent
+func monthlyRent(property *domain.Property) float64 {
+	if property.RentPrice == nil {
+		return 0
+	}
+	return *property.RentPrice
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/storage"
+)
+
+// memoryApplicationRepository is an in-memory RentalApplicationRepository
+type memoryApplicationRepository struct {
+	applications map[string]domain.RentalApplication
+}
+
+func (r *memoryApplicationRepository) Create(application *domain.RentalApplication) error {
+	r.applications[application.ID] = *application
+	return nil
+}
+
+func (r *memoryApplicationRepository) GetByID(id string) (*domain.RentalApplication, error) {
+	application, ok := r.applications[id]
+	if !ok {
+		return nil, fmt.Errorf("application not found: %s", id)
+	}
+	return &application, nil
+}
+
+func (r *memoryApplicationRepository) ListByProperty(propertyID, status string) ([]domain.RentalApplication, error) {
+	applications := []domain.RentalApplication{}
+	for _, application := range r.applications {
+		if application.PropertyID == propertyID && (status == "" || application.Status == status) {
+			applications = append(applications, application)
+		}
+	}
+	return applications, nil
+}
+
+func (r *memoryApplicationRepository) ListByApplicant(applicantID string) ([]domain.RentalApplication, error) {
+	applications := []domain.RentalApplication{}
+	for _, application := range r.applications {
+		if application.ApplicantID == applicantID {
+			applications = append(applications, application)
+		}
+	}
+	return applications, nil
+}
+
+func (r *memoryApplicationRepository) HasOpenApplication(propertyID, applicantID string) (bool, error) {
+	for _, application := range r.applications {
+		if application.PropertyID == propertyID && application.ApplicantID == applicantID && application.IsOpen() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryApplicationRepository) UpdateStatus(application *domain.RentalApplication, fromStatus string) error {
+	stored, ok := r.applications[application.ID]
+	if !ok || stored.Status != fromStatus {
+		return fmt.Errorf("application not found or no longer %s: %s", fromStatus, application.ID)
+	}
+	stored.Status = application.Status
+	stored.DecisionReason = application.DecisionReason
+	r.applications[application.ID] = stored
+	return nil
+}
+
+func (r *memoryApplicationRepository) AddDocument(applicationID string, document domain.ApplicationDocument) error {
+	stored, ok := r.applications[applicationID]
+	if !ok {
+		return fmt.Errorf("application not found: %s", applicationID)
+	}
+	stored.Documents = append(stored.Documents, document)
+	r.applications[applicationID] = stored
+	return nil
+}
+
+// recordingApplicationNotifier records the notified application statuses
+type recordingApplicationNotifier struct {
+	events []string
+}
+
+func (n *recordingApplicationNotifier) NotifyApplicationSubmitted(application *domain.RentalApplication, property *domain.Property) error {
+	n.events = append(n.events, "submitted")
+	return nil
+}
+
+func (n *recordingApplicationNotifier) NotifyApplicationStatusChanged(application *domain.RentalApplication, property *domain.Property) error {
+	n.events = append(n.events, application.Status)
+	return nil
+}
+
+func newTestApplicationService(t *testing.T) (*RentalApplicationService, *recordingApplicationNotifier) {
+	applicationStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/applications", domain.MaxDocumentUploadSize)
+	require.NoError(t, err)
+
+	rent := 800.0
+	rental := domain.NewProperty("Departamento", "Departamento en La Carolina", "Pichincha", "Quito", "apartment", 120000, "owner-1")
+	rental.ID = "rental-1"
+	rental.RentPrice = &rent
+	sale := domain.NewProperty("Casa", "Casa en Cumbayá", "Pichincha", "Quito", "house", 180000, "owner-1")
+	sale.ID = "sale-1"
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "rental-1").Return(rental, nil)
+	propertyRepo.On("GetByID", "sale-1").Return(sale, nil)
+
+	notifier := &recordingApplicationNotifier{}
+	repo := &memoryApplicationRepository{applications: map[string]domain.RentalApplication{}}
+	return NewRentalApplicationService(repo, propertyRepo, applicationStorage, notifier, log.New(os.Stderr, "", 0)), notifier
+}
+
+func TestRentalApplicationService_SubmitAndReview(t *testing.T) {
+	service, notifier := newTestApplicationService(t)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	tenant := domain.NewActor("tenant-1", string(domain.RoleBuyer), "")
+	stranger := domain.NewActor("buyer-2", string(domain.RoleBuyer), "")
+	req := SubmitApplicationRequest{PropertyID: "rental-1", MonthlyIncome: 2600, Occupants: 2}
+
+	application, err := service.Submit(req, tenant)
+	require.NoError(t, err)
+
+	_, err = service.Submit(req, tenant)
+	assert.ErrorContains(t, err, "already submitted")
+
+	_, err = service.Submit(SubmitApplicationRequest{PropertyID: "sale-1", MonthlyIncome: 2600}, tenant)
+	assert.ErrorContains(t, err, "only available rent listings")
+
+	_, err = service.Submit(req, owner)
+	assert.ErrorContains(t, err, "you manage this listing")
+
+	_, err = service.GetApplication(application.ID, stranger)
+	assert.ErrorContains(t, err, "permission denied")
+
+	own, err := service.GetApplication(application.ID, tenant)
+	require.NoError(t, err)
+	assert.Nil(t, own.Screening, "applicants do not see the screening")
+
+	applications, err := service.ListPropertyApplications("rental-1", "", owner)
+	require.NoError(t, err)
+	require.Len(t, applications, 1)
+	require.NotNil(t, applications[0].Screening)
+	assert.Equal(t, 3.25, applications[0].Screening.IncomeToRentRatio)
+
+	_, err = service.ListPropertyApplications("rental-1", "", tenant)
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.UpdateStatus(application.ID, domain.ApplicationStatusAccepted, "", tenant)
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = service.UpdateStatus(application.ID, domain.ApplicationStatusWithdrawn, "", owner)
+	assert.ErrorContains(t, err, "only the applicant can withdraw")
+
+	accepted, err := service.UpdateStatus(application.ID, domain.ApplicationStatusAccepted, "", owner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ApplicationStatusAccepted, accepted.Status)
+
+	_, err = service.UpdateStatus(application.ID, domain.ApplicationStatusWithdrawn, "", tenant)
+	assert.ErrorContains(t, err, "invalid status change")
+	assert.Equal(t, []string{"submitted", "accepted"}, notifier.events)
+}
+
+func TestRentalApplicationService_Documents(t *testing.T) {
+	service, _ := newTestApplicationService(t)
+	owner := domain.NewActor("owner-1", string(domain.RoleOwner), "")
+	tenant := domain.NewActor("tenant-1", string(domain.RoleBuyer), "")
+
+	application, err := service.Submit(SubmitApplicationRequest{PropertyID: "rental-1", MonthlyIncome: 2600}, tenant)
+	require.NoError(t, err)
+
+	_, err = service.AddDocument(application.ID, "Rol de pagos", "rol.pdf", "application/pdf", 8, bytes.NewReader([]byte("%PDF-rol")), owner)
+	assert.ErrorContains(t, err, "only the applicant")
+
+	document, err := service.AddDocument(application.ID, "Rol de pagos", "rol.pdf", "application/pdf", 8, bytes.NewReader([]byte("%PDF-rol")), tenant)
+	require.NoError(t, err)
+
+	_, data, err := service.GetDocument(application.ID, document.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-rol", string(data))
+
+	service.SetDocumentScanner(&signatureScanner{signature: "EICAR"})
+	_, err = service.AddDocument(application.ID, "", "cedula.pdf", "application/pdf", 5, bytes.NewReader([]byte("EICAR")), tenant)
+	assert.ErrorContains(t, err, "infected")
+
+	withdrawn, err := service.UpdateStatus(application.ID, domain.ApplicationStatusWithdrawn, "", tenant)
+	require.NoError(t, err)
+	assert.Nil(t, withdrawn.ReviewedBy)
+
+	_, err = service.AddDocument(application.ID, "", "cedula.pdf", "application/pdf", 5, bytes.NewReader([]byte("%PDF-")), tenant)
+	assert.ErrorContains(t, err, "withdrawn applications cannot be changed")
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// ReservationNotifier tells agents about reservations that expire
+type ReservationNotifier interface {
+	// NotifyReservationExpiring reminds the agent that a reservation expires soon
+	NotifyReservationExpiring(reservation *domain.PropertyReservation, property *domain.Property) error
+
+	// NotifyReservationExpired tells the agent that a reservation expired and the
+	// listing is available again
+	NotifyReservationExpired(reservation *domain.PropertyReservation, property *domain.Property) error
+}
+
+// LogReservationNotifier writes reservation notices to the log. It is used until an
+// email provider is configured.
+type LogReservationNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogReservationNotifier creates a notifier that logs reservation notices
+func NewLogReservationNotifier(logger *log.Logger) *LogReservationNotifier {
+	return &LogReservationNotifier{logger: logger}
+}
+
+// NotifyReservationExpiring logs the reminder
+func (n *LogReservationNotifier) NotifyReservationExpiring(reservation *domain.PropertyReservation, property *domain.Property) error {
+	n.logger.Printf("Reservation %s of property %s for %s expires at %s",
+		reservation.ID, property.ID, reservation.Lead.Name, reservation.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// NotifyReservationExpired logs the expiration notice
+func (n *LogReservationNotifier) NotifyReservationExpired(reservation *domain.PropertyReservation, property *domain.Property) error {
+	n.logger.Printf("Reservation %s of property %s for %s expired; the property is available again",
+		reservation.ID, property.ID, reservation.Lead.Name)
+	return nil
+}
+
+// ReserveRequest asks to reserve a listing for a lead during Days days
+type ReserveRequest struct {
+	Lead  domain.ReservationLead `json:"lead"`
+	Days  int                    `json:"days"`
+	Notes string                 `json:"notes,omitempty"`
+}
+
+// ReservationExpiryReport summarizes a run of the reservation expiry job
+type ReservationExpiryReport struct {
+	Reminded int `json:"reminded"`
+	Expired  int `json:"expired"`
+}
+
+// ReservationService reserves listings for leads. Reservations end when the listing is
+// sold, rented or made available through the status endpoint, or expire on their own;
+// agents are reminded a day before.
+type ReservationService struct {
+	repo         repository.ReservationRepository
+	propertyRepo repository.PropertyRepository
+	notifier     ReservationNotifier
+	listener     PropertyChangeListener
+	events       EventPublisher
+	outbox       bool
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewReservationService creates a reservation service
+func NewReservationService(
+	repo repository.ReservationRepository,
+	propertyRepo repository.PropertyRepository,
+	notifier ReservationNotifier,
+	logger *log.Logger,
+) *ReservationService {
+	if notifier == nil {
+		notifier = NewLogReservationNotifier(logger)
+	}
+	return &ReservationService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		notifier:     notifier,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetChangeListener registers a listener for properties reserved or made available
+func (s *ReservationService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// SetEventPublisher publishes property.status_changed when reservations start or end
+func (s *ReservationService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
+// SetOutbox records property.status_changed in the transactional outbox with the
+// reservation instead of publishing it afterwards
+func (s *ReservationService) SetOutbox(enabled bool) {
+	s.outbox = enabled
+}
+
+// Reserve holds an available listing as reserved for a lead. The property's agency,
+// assigned agent or owner can reserve it.
+func (s *ReservationService) Reserve(propertyID string, req ReserveRequest, actor domain.Actor) (*domain.PropertyReservation, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can reserve it")
+	}
+
+	now := s.now()
+	reservation, err := domain.NewPropertyReservation(property.ID, req.Lead, req.Days, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reservation: %w", err)
+	}
+	reservation.AgencyID = property.AgencyID
+	reservation.AgentID = property.AgentID
+	reservation.Notes = strings.TrimSpace(req.Notes)
+	reservation.CreatedBy = actor.UserID
+
+	change, err := domain.NewPropertyStatusChange(property.ID, property.Status, domain.StatusReserved, nil,
+		fmt.Sprintf("Reserved for %s until %s", reservation.Lead.Name, reservation.ExpiresAt.Format("2006-01-02")), actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	change.ChangedAt = now
+
+	events, err := s.outboxEvents(property, change)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(reservation, change, events...); err != nil {
+		return nil, err
+	}
+
+	s.changed(property, change)
+	return reservation, nil
+}
+
+// ListReservations lists the reservations of a listing, latest first
+func (s *ReservationService) ListReservations(propertyID string, actor domain.Actor) ([]domain.PropertyReservation, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can view its reservations")
+	}
+	return s.repo.ListByProperty(property.ID)
+}
+
+// CloseReservation ends the active reservation of a listing that left the reserved
+// status: converted when it was sold or rented, released otherwise. The status change
+// is already applied by the PropertyStatusService.
+func (s *ReservationService) CloseReservation(propertyID, listingStatus string) error {
+	reservation, err := s.repo.GetActiveByProperty(propertyID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+
+	if err := reservation.Resolve(domain.ReservationStatusFor(listingStatus), s.now()); err != nil {
+		return err
+	}
+	return s.repo.Resolve(reservation, nil)
+}
+
+// ProcessExpiring reminds agents of reservations expiring within a day and returns the
+// listings of expired ones to available
+func (s *ReservationService) ProcessExpiring() (*ReservationExpiryReport, error) {
+	now := s.now()
+	reservations, err := s.repo.ListActiveExpiringBefore(now.Add(domain.ReservationReminderBefore))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReservationExpiryReport{}
+	for i := range reservations {
+		reservation := &reservations[i]
+		property, err := s.propertyRepo.GetByID(reservation.PropertyID)
+		if err != nil {
+			s.logger.Printf("Error loading property of reservation %s: %v", reservation.ID, err)
+			continue
+		}
+
+		switch {
+		case reservation.IsExpired(now):
+			if err := s.expire(reservation, property, now); err != nil {
+				s.logger.Printf("Error expiring reservation %s: %v", reservation.ID, err)
+				continue
+			}
+			report.Expired++
+		case reservation.NeedsReminder(now):
+			if err := s.notifier.NotifyReservationExpiring(reservation, property); err != nil {
+				s.logger.Printf("Error reminding expiry of reservation %s: %v", reservation.ID, err)
+				continue
+			}
+			if err := s.repo.MarkReminded(reservation.ID, now); err != nil {
+				s.logger.Printf("Error marking reservation %s reminded: %v", reservation.ID, err)
+				continue
+			}
+			report.Reminded++
+		}
+	}
+
+	if report.Expired > 0 {
+		s.logger.Printf("%d reservations expired", report.Expired)
+	}
+	return report, nil
+}
+
+// expire ends a reservation that ran out and makes its listing available again
+func (s *ReservationService) expire(reservation *domain.PropertyReservation, property *domain.Property, now time.Time) error {
+	if err := reservation.Resolve(domain.ReservationExpired, now); err != nil {
+		return err
+	}
+
+	// A listing that already left reserved only needs its reservation closed
+	var change *domain.PropertyStatusChange
+	if property.Status == domain.StatusReserved {
+		var err error
+		change, err = domain.NewPropertyStatusChange(property.ID, property.Status, domain.StatusAvailable, nil,
+			fmt.Sprintf("Reservation for %s expired", reservation.Lead.Name), "")
+		if err != nil {
+			return err
+		}
+		change.ChangedAt = now
+	}
+
+	var events []*domain.OutboxEvent
+	if change != nil {
+		var err error
+		if events, err = s.outboxEvents(property, change); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Resolve(reservation, change, events...); err != nil {
+		return err
+	}
+
+	if change != nil {
+		s.changed(property, change)
+	}
+	if err := s.notifier.NotifyReservationExpired(reservation, property); err != nil {
+		s.logger.Printf("Error notifying expiry of reservation %s: %v", reservation.ID, err)
+	}
+	return nil
+}
+
+// outboxEvents returns the property.status_changed event of a change when the outbox is enabled
+func (s *ReservationService) outboxEvents(property *domain.Property, change *domain.PropertyStatusChange) ([]*domain.OutboxEvent, error) {
+	if !s.outbox {
+		return nil, nil
+	}
+	event, err := domain.NewOutboxEvent(domain.EventPropertyStatusChanged, domain.AggregateProperty, property.ID, propertyAgencyID(property), change)
+	if err != nil {
+		return nil, err
+	}
+	return []*domain.OutboxEvent{event}, nil
+}
+
+// changed invalidates a listing whose status changed and publishes the change
+func (s *ReservationService) changed(property *domain.Property, change *domain.PropertyStatusChange) {
+	if s.listener != nil {
+		s.listener.InvalidateProperties(property.ID)
+	}
+	if s.events != nil && !s.outbox {
+		if err := s.events.Publish(domain.EventPropertyStatusChanged, propertyAgencyID(property), change); err != nil {
+			s.logger.Printf("Error publishing %s for property %s: %v", domain.EventPropertyStatusChanged, property.ID, err)
+		}
+	}
+}
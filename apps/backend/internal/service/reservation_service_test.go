@@ -0,0 +1,189 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryReservationRepository keeps reservations in memory and applies their status
+// changes to the properties of a memoryPropertyStatusRepository
+type memoryReservationRepository struct {
+	statuses     *memoryPropertyStatusRepository
+	reservations []*domain.PropertyReservation
+}
+
+func (r *memoryReservationRepository) Create(reservation *domain.PropertyReservation, change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	if err := r.statuses.ChangeStatus(change, events...); err != nil {
+		return err
+	}
+	copied := *reservation
+	r.reservations = append(r.reservations, &copied)
+	return nil
+}
+
+func (r *memoryReservationRepository) GetByID(id string) (*domain.PropertyReservation, error) {
+	for _, reservation := range r.reservations {
+		if reservation.ID == id {
+			copied := *reservation
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("reservation not found: %s", id)
+}
+
+func (r *memoryReservationRepository) GetActiveByProperty(propertyID string) (*domain.PropertyReservation, error) {
+	for _, reservation := range r.reservations {
+		if reservation.PropertyID == propertyID && reservation.IsActive() {
+			copied := *reservation
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("active reservation not found for property: %s", propertyID)
+}
+
+func (r *memoryReservationRepository) ListByProperty(propertyID string) ([]domain.PropertyReservation, error) {
+	reservations := []domain.PropertyReservation{}
+	for _, reservation := range r.reservations {
+		if reservation.PropertyID == propertyID {
+			reservations = append(reservations, *reservation)
+		}
+	}
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) Resolve(reservation *domain.PropertyReservation, change *domain.PropertyStatusChange, events ...*domain.OutboxEvent) error {
+	if change != nil {
+		if err := r.statuses.ChangeStatus(change, events...); err != nil {
+			return err
+		}
+	}
+	for i, stored := range r.reservations {
+		if stored.ID == reservation.ID {
+			copied := *reservation
+			r.reservations[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (r *memoryReservationRepository) ListActiveExpiringBefore(before time.Time) ([]domain.PropertyReservation, error) {
+	reservations := []domain.PropertyReservation{}
+	for _, reservation := range r.reservations {
+		if reservation.IsActive() && !reservation.ExpiresAt.After(before) {
+			reservations = append(reservations, *reservation)
+		}
+	}
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) MarkReminded(id string, at time.Time) error {
+	for _, reservation := range r.reservations {
+		if reservation.ID == id {
+			reservation.ReminderSentAt = &at
+			return nil
+		}
+	}
+	return fmt.Errorf("reservation not found: %s", id)
+}
+
+// recordingReservationNotifier records reminded and expired reservations
+type recordingReservationNotifier struct {
+	reminded, expired []string
+}
+
+func (n *recordingReservationNotifier) NotifyReservationExpiring(reservation *domain.PropertyReservation, property *domain.Property) error {
+	n.reminded = append(n.reminded, reservation.ID)
+	return nil
+}
+
+func (n *recordingReservationNotifier) NotifyReservationExpired(reservation *domain.PropertyReservation, property *domain.Property) error {
+	n.expired = append(n.expired, reservation.ID)
+	return nil
+}
+
+func newTestReservationService(t *testing.T) (*ReservationService, *PropertyStatusService, *domain.Property, *recordingReservationNotifier) {
+	property := domain.NewProperty("Casa", "Casa en Samborondón", "Guayas", "Samborondón", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.SetAgency("agency-1")
+	properties := map[string]*domain.Property{property.ID: property}
+
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+	statuses := &memoryPropertyStatusRepository{properties: properties}
+	notifier := &recordingReservationNotifier{}
+	logger := log.New(os.Stdout, "", 0)
+
+	reservations := NewReservationService(&memoryReservationRepository{statuses: statuses}, propertyRepo, notifier, logger)
+	statusService := NewPropertyStatusService(statuses, propertyRepo, &recordingDeals{properties: properties}, logger)
+	statusService.SetReservations(reservations)
+	return reservations, statusService, property, notifier
+}
+
+func TestReservationService_ReserveAndConvert(t *testing.T) {
+	reservations, statusService, property, _ := newTestReservationService(t)
+	agency := domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1")
+	lead := domain.ReservationLead{Name: "María Torres", Phone: "0991234567"}
+
+	_, err := reservations.Reserve("prop-1", ReserveRequest{Lead: lead}, domain.NewActor("user-2", string(domain.RoleBuyer), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	// Listings are reserved for a lead, not through the status endpoint
+	_, err = statusService.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusReserved}, agency)
+	assert.ErrorContains(t, err, "reservations")
+
+	reservation, err := reservations.Reserve("prop-1", ReserveRequest{Lead: lead, Days: 5}, agency)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusReserved, property.Status)
+	assert.Equal(t, "agency-1", *reservation.AgencyID)
+
+	_, err = reservations.Reserve("prop-1", ReserveRequest{Lead: lead}, agency)
+	assert.ErrorContains(t, err, "invalid status transition")
+
+	finalPrice := 245000.0
+	_, err = statusService.ChangeStatus("prop-1", ChangeStatusRequest{Status: domain.StatusSold, FinalPrice: &finalPrice}, agency)
+	require.NoError(t, err)
+
+	list, err := reservations.ListReservations("prop-1", agency)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, domain.ReservationConverted, list[0].Status)
+}
+
+func TestReservationService_ProcessExpiring(t *testing.T) {
+	reservations, _, property, notifier := newTestReservationService(t)
+	start := time.Date(2025, 8, 30, 10, 0, 0, 0, time.UTC)
+	reservations.now = func() time.Time { return start }
+
+	reservation, err := reservations.Reserve("prop-1", ReserveRequest{Lead: domain.ReservationLead{Name: "María", Email: "maria@example.com"}, Days: 2},
+		domain.NewActor("agency-1", string(domain.RoleAgency), "agency-1"))
+	require.NoError(t, err)
+
+	report, err := reservations.ProcessExpiring()
+	require.NoError(t, err)
+	assert.Equal(t, ReservationExpiryReport{}, *report)
+
+	// A day before expiry the agent is reminded once
+	reservations.now = func() time.Time { return reservation.ExpiresAt.Add(-12 * time.Hour) }
+	report, err = reservations.ProcessExpiring()
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Reminded)
+	report, err = reservations.ProcessExpiring()
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Reminded)
+
+	reservations.now = func() time.Time { return reservation.ExpiresAt }
+	report, err = reservations.ProcessExpiring()
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Expired)
+	assert.Equal(t, domain.StatusAvailable, property.Status)
+	assert.Equal(t, []string{reservation.ID}, notifier.reminded)
+	assert.Equal(t, []string{reservation.ID}, notifier.expired)
+}
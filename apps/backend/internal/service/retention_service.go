@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// maxRetentionBatches bounds the batches deleted per target and run, so one target with
+// a huge backlog does not hold the job; the rest is pruned by the next runs
+const maxRetentionBatches = 200
+
+// RetentionConfig configures the pruning of old records
+type RetentionConfig struct {
+	Policies  []domain.RetentionPolicy // nil uses domain.DefaultRetentionPolicies
+	BatchSize int                      // rows deleted per statement
+	DryRun    bool                     // scheduled runs only report what they would delete
+}
+
+// RetentionService prunes logs, audit entries, view and search events and stale drafts
+// once older than their retention, keeping the database from growing unbounded
+type RetentionService struct {
+	repo   repository.RetentionRepository
+	config RetentionConfig
+	now    func() time.Time
+	logger *log.Logger
+
+	mu      sync.Mutex
+	running bool
+	last    *domain.RetentionReport
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(repo repository.RetentionRepository, config RetentionConfig, logger *log.Logger) *RetentionService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if config.Policies == nil {
+		config.Policies = domain.DefaultRetentionPolicies()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = domain.DefaultRetentionBatchSize
+	}
+	return &RetentionService{
+		repo:   repo,
+		config: config,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// Policies returns the retention of every target
+func (s *RetentionService) Policies() []domain.RetentionPolicy {
+	return s.config.Policies
+}
+
+// LastReport returns the report of the most recent run, or nil
+func (s *RetentionService) LastReport() *domain.RetentionReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Run prunes every target once, or only counts what it would prune on a dry run. A
+// target that fails is reported and the others are still pruned.
+func (s *RetentionService) Run(dryRun bool) (*domain.RetentionReport, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("retention pruning is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	report := &domain.RetentionReport{StartedAt: s.now(), DryRun: dryRun, Targets: []domain.RetentionTargetReport{}}
+	for _, policy := range s.config.Policies {
+		report.Add(s.prune(policy, report.StartedAt, dryRun))
+	}
+	report.FinishedAt = s.now()
+
+	if !dryRun {
+		s.mu.Lock()
+		s.last = report
+		s.mu.Unlock()
+		s.logger.Printf("Retention pruning deleted %d of %d expired records", report.Deleted, report.Expired)
+	}
+	if failed := report.Failed(); len(failed) > 0 {
+		return report, fmt.Errorf("failed to prune %s", strings.Join(failed, ", "))
+	}
+	return report, nil
+}
+
+// prune counts and, unless on a dry run, deletes the expired records of a target
+func (s *RetentionService) prune(policy domain.RetentionPolicy, now time.Time, dryRun bool) domain.RetentionTargetReport {
+	target := domain.RetentionTargetReport{
+		Target:        policy.Target,
+		Category:      policy.Category,
+		RetentionDays: policy.RetentionDays(),
+	}
+	if !policy.Enabled() {
+		return target
+	}
+	cutoff := policy.Cutoff(now)
+	target.Cutoff = &cutoff
+
+	expired, err := s.repo.CountExpired(policy.Target, cutoff)
+	if err != nil {
+		target.Error = err.Error()
+		s.logger.Printf("Retention of %s failed: %v", policy.Target, err)
+		return target
+	}
+	target.Expired = expired
+	if dryRun || expired == 0 {
+		return target
+	}
+
+	for batch := 0; batch < maxRetentionBatches; batch++ {
+		deleted, err := s.repo.DeleteExpired(policy.Target, cutoff, s.config.BatchSize)
+		if err != nil {
+			target.Error = err.Error()
+			s.logger.Printf("Retention of %s failed after %d deletions: %v", policy.Target, target.Deleted, err)
+			break
+		}
+		target.Deleted += deleted
+		if deleted < int64(s.config.BatchSize) {
+			break
+		}
+	}
+	return target
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryRetention holds the ages of the records of each target
+type memoryRetention struct {
+	records map[string][]time.Time
+	failing map[string]bool
+	deletes int
+}
+
+func (m *memoryRetention) CountExpired(target string, cutoff time.Time) (int64, error) {
+	if m.failing[target] {
+		return 0, fmt.Errorf("failed to count expired %s: connection reset", target)
+	}
+	var count int64
+	for _, at := range m.records[target] {
+		if at.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryRetention) DeleteExpired(target string, cutoff time.Time, limit int) (int64, error) {
+	m.deletes++
+	var kept []time.Time
+	var deleted int64
+	for _, at := range m.records[target] {
+		if at.Before(cutoff) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	m.records[target] = kept
+	return deleted, nil
+}
+
+func TestRetentionService_Run(t *testing.T) {
+	now := time.Date(2025, 9, 15, 3, 50, 0, 0, time.UTC)
+	old, recent := now.AddDate(0, 0, -400), now.AddDate(0, 0, -1)
+	repo := &memoryRetention{
+		records: map[string][]time.Time{
+			domain.RetentionAuditLog:        {old, old, old, old, old, recent},
+			domain.RetentionPropertyDrafts:  {old, recent},
+			domain.RetentionShortLinkClicks: {old},
+		},
+		failing: map[string]bool{},
+	}
+	policies, err := domain.RetentionPolicies(map[string]int{domain.RetentionShortLinkClicks: 0})
+	require.NoError(t, err)
+	s := NewRetentionService(repo, RetentionConfig{Policies: policies, BatchSize: 2}, log.New(&bytes.Buffer{}, "", 0))
+	s.now = func() time.Time { return now }
+
+	dry, err := s.Run(true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), dry.Expired)
+	assert.Zero(t, dry.Deleted)
+	assert.Zero(t, repo.deletes, "dry runs delete nothing")
+	assert.Nil(t, s.LastReport(), "dry runs are not the last run")
+
+	report, err := s.Run(false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), report.Deleted)
+	assert.Len(t, repo.records[domain.RetentionAuditLog], 1)
+	assert.Equal(t, 4, repo.deletes, "deletes in batches until one comes short")
+	assert.Len(t, repo.records[domain.RetentionShortLinkClicks], 1, "targets kept forever are not pruned")
+	for _, target := range report.Targets {
+		if target.Target == domain.RetentionShortLinkClicks {
+			assert.Nil(t, target.Cutoff)
+		}
+	}
+	assert.Same(t, report, s.LastReport())
+
+	repo.failing[domain.RetentionAuditLog] = true
+	repo.records[domain.RetentionPropertyDrafts] = append(repo.records[domain.RetentionPropertyDrafts], old)
+	report, err = s.Run(false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), domain.RetentionAuditLog)
+	assert.Equal(t, int64(1), report.Deleted, "other targets are still pruned")
+}
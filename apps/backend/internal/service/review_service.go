@@ -0,0 +1,203 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// ReviewService moderates the reviews of every subject, handles abuse reports on
+// published reviews and keeps the reputation scores of agencies up to date.
+type ReviewService struct {
+	reviewRepo repository.ReviewRepository
+	logger     *log.Logger
+	now        func() time.Time
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(reviewRepo repository.ReviewRepository, logger *log.Logger) *ReviewService {
+	return &ReviewService{
+		reviewRepo: reviewRepo,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// DeleteReview removes a review. Reviewers can delete their own reviews and
+// administrators any review.
+func (s *ReviewService) DeleteReview(reviewID string, actor domain.Actor) error {
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != domain.RoleAdmin && review.ReviewerID != actor.UserID {
+		return fmt.Errorf("permission denied: only the reviewer or an administrator can delete a review")
+	}
+	if err := s.reviewRepo.Delete(review.ID); err != nil {
+		return err
+	}
+
+	s.refreshReputation(review.SubjectType, review.SubjectID)
+	return nil
+}
+
+// ListModerationQueue returns reviews in a status, pending by default, newest first.
+// Only administrators moderate reviews.
+func (s *ReviewService) ListModerationQueue(status string, limit, offset int, actor domain.Actor) ([]domain.Review, int, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, 0, fmt.Errorf("permission denied: only administrators can moderate reviews")
+	}
+	if status == "" {
+		status = domain.ReviewPending
+	}
+	if !domain.IsValidReviewStatus(status) {
+		return nil, 0, fmt.Errorf("invalid review status: %s", status)
+	}
+
+	limit, offset = reviewPage(limit, offset)
+	return s.reviewRepo.List(domain.ReviewFilter{Status: status, Limit: limit, Offset: offset})
+}
+
+// ModerateReview publishes or rejects a review. Open reports on the review are closed
+// with the decision: dismissed when it is published, upheld when it is rejected.
+// Only administrators moderate reviews.
+func (s *ReviewService) ModerateReview(reviewID string, approve bool, note string, actor domain.Actor) (*domain.Review, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can moderate reviews")
+	}
+
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	return s.moderate(review, approve, note, actor)
+}
+
+// ReportReview records an abuse report on a published review. A review reaching
+// domain.ReviewReportThreshold open reports is hidden until an administrator
+// resolves them.
+func (s *ReviewService) ReportReview(reviewID, reason, details string, actor domain.Actor) (*domain.ReviewReport, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to report reviews")
+	}
+
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != domain.ReviewPublished {
+		return nil, fmt.Errorf("review not found: %s", reviewID)
+	}
+	if review.ReviewerID == actor.UserID {
+		return nil, fmt.Errorf("invalid report: reviewers cannot report their own review")
+	}
+
+	report, err := domain.NewReviewReport(review.ID, actor.UserID, reason, details)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.CreateReport(report); err != nil {
+		return nil, err
+	}
+
+	open, err := s.reviewRepo.CountOpenReports(review.ID)
+	if err != nil {
+		s.logger.Printf("Error counting reports of review %s: %v", review.ID, err)
+		return report, nil
+	}
+	if open >= domain.ReviewReportThreshold {
+		review.Status = domain.ReviewPending
+		review.UpdatedAt = s.now()
+		if err := s.reviewRepo.Update(review); err != nil {
+			s.logger.Printf("Error hiding reported review %s: %v", review.ID, err)
+			return report, nil
+		}
+		s.refreshReputation(review.SubjectType, review.SubjectID)
+		s.logger.Printf("Review %s hidden after %d reports", review.ID, open)
+	}
+
+	return report, nil
+}
+
+// ListReports returns reports in a status, open by default, oldest first. Only
+// administrators handle reports.
+func (s *ReviewService) ListReports(status string, limit, offset int, actor domain.Actor) ([]domain.ReviewReport, int, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, 0, fmt.Errorf("permission denied: only administrators can handle review reports")
+	}
+	if status == "" {
+		status = domain.ReviewReportOpen
+	}
+	if !domain.IsValidReviewReportStatus(status) {
+		return nil, 0, fmt.Errorf("invalid report status: %s", status)
+	}
+
+	limit, offset = reviewPage(limit, offset)
+	return s.reviewRepo.ListReports(status, limit, offset)
+}
+
+// ResolveReport decides the open reports of a review. Upholding rejects the review
+// with the note; dismissing publishes it again if the reports had hidden it. Only
+// administrators handle reports.
+func (s *ReviewService) ResolveReport(reportID string, uphold bool, note string, actor domain.Actor) (*domain.Review, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can handle review reports")
+	}
+
+	report, err := s.reviewRepo.GetReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != domain.ReviewReportOpen {
+		return nil, fmt.Errorf("invalid report: already %s", report.Status)
+	}
+
+	review, err := s.reviewRepo.GetByID(report.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	return s.moderate(review, !uphold, note, actor)
+}
+
+// moderate publishes or rejects a review, closes its open reports and refreshes the
+// reputation of its subject
+func (s *ReviewService) moderate(review *domain.Review, approve bool, note string, actor domain.Actor) (*domain.Review, error) {
+	now := s.now()
+	if err := review.Moderate(approve, note, actor.UserID, now); err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, err
+	}
+
+	resolution := domain.ReviewReportUpheld
+	if approve {
+		resolution = domain.ReviewReportDismissed
+	}
+	if _, err := s.reviewRepo.ResolveReports(review.ID, resolution, actor.UserID, now); err != nil {
+		s.logger.Printf("Error resolving reports of review %s: %v", review.ID, err)
+	}
+
+	s.refreshReputation(review.SubjectType, review.SubjectID)
+	s.logger.Printf("Review %s %s by %s", review.ID, review.Status, actor.UserID)
+	return review, nil
+}
+
+// refreshReputation recomputes the reputation of an agency after its reviews change;
+// failures are logged and the score is fixed by the next change
+func (s *ReviewService) refreshReputation(subjectType, subjectID string) {
+	if subjectType != domain.ReviewSubjectAgency {
+		return
+	}
+
+	summary, err := s.reviewRepo.Summary(subjectType, subjectID)
+	if err == nil {
+		err = s.reviewRepo.SaveReputation(domain.NewReputation(subjectType, subjectID, *summary, s.now()))
+	}
+	if err != nil {
+		s.logger.Printf("Error refreshing reputation of %s %s: %v", subjectType, subjectID, err)
+	}
+}
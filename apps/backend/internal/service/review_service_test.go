@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+func TestReviewService_Reports(t *testing.T) {
+	service, reviews := newTestAgencyReviewService(t)
+	admin := domain.NewActor("admin-1", "admin", "")
+
+	review, err := service.SubmitReview("agency-1", SubmitReviewRequest{Rating: 1, Comment: "Estafadores"}, domain.NewActor("client-1", "buyer", ""))
+	require.NoError(t, err)
+
+	_, err = reviews.ReportReview(review.ID, domain.ReviewReportOffensive, "", domain.NewActor("user-1", "buyer", ""))
+	assert.ErrorContains(t, err, "review not found", "only published reviews can be reported")
+
+	_, err = reviews.ModerateReview(review.ID, true, "", admin)
+	require.NoError(t, err)
+
+	_, err = reviews.ReportReview(review.ID, domain.ReviewReportSpam, "", domain.NewActor("client-1", "buyer", ""))
+	assert.ErrorContains(t, err, "invalid report", "reviewers cannot report their own review")
+
+	var report *domain.ReviewReport
+	for i := 1; i <= domain.ReviewReportThreshold; i++ {
+		report, err = reviews.ReportReview(review.ID, domain.ReviewReportOffensive, "", domain.NewActor(fmt.Sprintf("user-%d", i), "buyer", ""))
+		require.NoError(t, err)
+	}
+	_, err = reviews.ReportReview(review.ID, domain.ReviewReportOffensive, "", domain.NewActor("user-1", "buyer", ""))
+	assert.Error(t, err, "a user reports a review once")
+
+	hidden, err := reviews.reviewRepo.GetByID(review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewPending, hidden.Status, "reviews reaching the threshold are hidden")
+	reputation, err := service.GetReputation("agency-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, reputation.ReviewCount)
+
+	_, err = reviews.ResolveReport(report.ID, true, "", domain.NewActor("user-1", "buyer", ""))
+	assert.ErrorContains(t, err, "permission denied")
+	open, total, err := reviews.ListReports("", 0, 0, admin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewReportThreshold, total)
+	assert.Len(t, open, total)
+
+	resolved, err := reviews.ResolveReport(report.ID, true, "Lenguaje ofensivo", admin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewRejected, resolved.Status)
+	assert.Equal(t, "Lenguaje ofensivo", resolved.ModerationNote)
+
+	_, total, err = reviews.ListReports(domain.ReviewReportUpheld, 0, 0, admin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewReportThreshold, total, "resolving one report closes every open report of the review")
+	_, err = reviews.ResolveReport(report.ID, false, "", admin)
+	assert.ErrorContains(t, err, "invalid report")
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// RiskImportResult reports an imported hazard layer and the properties reassessed with it
+type RiskImportResult struct {
+	Layer              domain.RiskLayerSummary `json:"layer"`
+	PropertiesAssessed int                     `json:"properties_assessed"`
+	PropertiesFlagged  int                     `json:"properties_flagged"`
+}
+
+// RiskZoneService imports hazard layers and flags the properties located in their
+// zones. Flags are refreshed when a property is saved and when the layers around it change.
+type RiskZoneService struct {
+	repo     repository.RiskZoneRepository
+	listener PropertyChangeListener
+	now      func() time.Time
+	logger   *log.Logger
+}
+
+// NewRiskZoneService creates a risk zone service
+func NewRiskZoneService(repo repository.RiskZoneRepository, logger *log.Logger) *RiskZoneService {
+	return &RiskZoneService{
+		repo:   repo,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// SetChangeListener registers a listener for properties whose risk flags change after
+// an import, typically the PropertyService so searches excluding hazards reflect them
+func (s *RiskZoneService) SetChangeListener(listener PropertyChangeListener) {
+	s.listener = listener
+}
+
+// ImportLayer replaces a hazard layer with the zones of a GeoJSON FeatureCollection and
+// reassesses the properties inside the old and new layer
+func (s *RiskZoneService) ImportLayer(layer domain.RiskLayer, data []byte, actor domain.Actor) (*RiskImportResult, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only admins can import risk layers")
+	}
+
+	layer.Normalize()
+	if err := layer.Validate(); err != nil {
+		return nil, err
+	}
+	zones, err := domain.ParseRiskLayer(data, layer, actor.UserID, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := zones[0].Bounds
+	for _, zone := range zones[1:] {
+		bounds = bounds.Union(zone.Bounds)
+	}
+	if previous, err := s.repo.GetLayer(layer.Layer); err == nil {
+		bounds = bounds.Union(previous.Bounds)
+	}
+
+	if err := s.repo.ReplaceLayer(layer.Layer, zones); err != nil {
+		return nil, err
+	}
+	summary, err := s.repo.GetLayer(layer.Layer)
+	if err != nil {
+		return nil, err
+	}
+
+	assessed, flagged, err := s.reassess(bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Risk layer %s imported by %s: %d zones, %d of %d properties flagged",
+		layer.Layer, actor.UserID, len(zones), flagged, assessed)
+	return &RiskImportResult{Layer: *summary, PropertiesAssessed: assessed, PropertiesFlagged: flagged}, nil
+}
+
+// DeleteLayer removes a hazard layer and reassesses the properties it covered
+func (s *RiskZoneService) DeleteLayer(layer string, actor domain.Actor) error {
+	if actor.Role != domain.RoleAdmin {
+		return fmt.Errorf("permission denied: only admins can delete risk layers")
+	}
+
+	summary, err := s.repo.GetLayer(layer)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteLayer(layer); err != nil {
+		return err
+	}
+	if _, _, err := s.reassess(summary.Bounds); err != nil {
+		return err
+	}
+
+	s.logger.Printf("Risk layer %s deleted by %s", layer, actor.UserID)
+	return nil
+}
+
+// ListLayers summarizes the imported hazard layers
+func (s *RiskZoneService) ListLayers() ([]domain.RiskLayerSummary, error) {
+	return s.repo.ListLayers()
+}
+
+// GetPropertyRisk returns the risk flags of a property
+func (s *RiskZoneService) GetPropertyRisk(propertyID string) ([]domain.PropertyRiskFlag, error) {
+	return s.repo.ListFlags(propertyID)
+}
+
+// AssessProperty flags the hazard zones a property lies in, clearing the flags of
+// properties without coordinates
+func (s *RiskZoneService) AssessProperty(property *domain.Property) error {
+	location, ok := domain.PropertyLocation(property)
+	if !ok {
+		return s.repo.ReplaceFlags(property.ID, []domain.PropertyRiskFlag{})
+	}
+	_, err := s.assess(property.ID, location)
+	return err
+}
+
+// assess stores the risk flags of a located property, returning them
+func (s *RiskZoneService) assess(propertyID string, location domain.GeoPoint) ([]domain.PropertyRiskFlag, error) {
+	zones, err := s.repo.FindCandidates(location)
+	if err != nil {
+		return nil, err
+	}
+	flags := domain.AssessRisk(location, zones, s.now())
+	if err := s.repo.ReplaceFlags(propertyID, flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// reassess refreshes the risk flags of the properties inside a box, returning how many
+// were assessed and how many were flagged
+func (s *RiskZoneService) reassess(bounds domain.GeoBounds) (int, int, error) {
+	properties, err := s.repo.ListLocatedProperties(bounds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	flagged := 0
+	ids := make([]string, 0, len(properties))
+	for _, property := range properties {
+		flags, err := s.assess(property.ID, property.Location)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to assess property %s: %w", property.ID, err)
+		}
+		if len(flags) > 0 {
+			flagged++
+		}
+		ids = append(ids, property.ID)
+	}
+
+	if s.listener != nil && len(ids) > 0 {
+		s.listener.InvalidateProperties(ids...)
+	}
+	return len(properties), flagged, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// memoryRiskZones keeps hazard zones, located properties and risk flags in memory
+type memoryRiskZones struct {
+	zones      []domain.RiskZone
+	properties []repository.LocatedProperty
+	flags      map[string][]domain.PropertyRiskFlag
+}
+
+func (m *memoryRiskZones) ReplaceLayer(layer string, zones []domain.RiskZone) error {
+	kept := []domain.RiskZone{}
+	for _, zone := range m.zones {
+		if zone.Layer != layer {
+			kept = append(kept, zone)
+		}
+	}
+	m.zones = append(kept, zones...)
+	return nil
+}
+
+func (m *memoryRiskZones) DeleteLayer(layer string) error {
+	if _, err := m.GetLayer(layer); err != nil {
+		return err
+	}
+	return m.ReplaceLayer(layer, nil)
+}
+
+func (m *memoryRiskZones) GetLayer(layer string) (*domain.RiskLayerSummary, error) {
+	var summary *domain.RiskLayerSummary
+	for _, zone := range m.zones {
+		if zone.Layer != layer {
+			continue
+		}
+		if summary == nil {
+			summary = &domain.RiskLayerSummary{Layer: layer, Hazard: zone.Hazard, Source: zone.Source, Bounds: zone.Bounds}
+		}
+		summary.Zones++
+		summary.Bounds = summary.Bounds.Union(zone.Bounds)
+	}
+	if summary == nil {
+		return nil, fmt.Errorf("risk layer not found: %s", layer)
+	}
+	return summary, nil
+}
+
+func (m *memoryRiskZones) ListLayers() ([]domain.RiskLayerSummary, error) {
+	return nil, nil
+}
+
+func (m *memoryRiskZones) FindCandidates(point domain.GeoPoint) ([]domain.RiskZone, error) {
+	zones := []domain.RiskZone{}
+	for _, zone := range m.zones {
+		if zone.Bounds.Contains(point) {
+			zones = append(zones, zone)
+		}
+	}
+	return zones, nil
+}
+
+func (m *memoryRiskZones) ListLocatedProperties(bounds domain.GeoBounds) ([]repository.LocatedProperty, error) {
+	properties := []repository.LocatedProperty{}
+	for _, property := range m.properties {
+		if bounds.Contains(property.Location) {
+			properties = append(properties, property)
+		}
+	}
+	return properties, nil
+}
+
+func (m *memoryRiskZones) ReplaceFlags(propertyID string, flags []domain.PropertyRiskFlag) error {
+	m.flags[propertyID] = flags
+	return nil
+}
+
+func (m *memoryRiskZones) ListFlags(propertyID string) ([]domain.PropertyRiskFlag, error) {
+	return m.flags[propertyID], nil
+}
+
+// floodLayer is a high flood zone around the Estero Salado in Guayaquil
+const floodLayer = `{"type": "FeatureCollection", "features": [{"type": "Feature",
+	"properties": {"nombre": "Estero Salado"},
+	"geometry": {"type": "Polygon", "coordinates": [[[-79.95, -2.25], [-79.85, -2.25], [-79.85, -2.15], [-79.95, -2.15], [-79.95, -2.25]]]}}]}`
+
+func newTestRiskZoneService() (*RiskZoneService, *memoryRiskZones, *recordingListener) {
+	repo := &memoryRiskZones{
+		properties: []repository.LocatedProperty{
+			{ID: "prop-inside", Location: domain.GeoPoint{Lat: -2.2, Lng: -79.9}},
+			{ID: "prop-outside", Location: domain.GeoPoint{Lat: -2.14, Lng: -79.9}},
+		},
+		flags: map[string][]domain.PropertyRiskFlag{},
+	}
+	listener := &recordingListener{}
+	var logs bytes.Buffer
+	svc := NewRiskZoneService(repo, log.New(&logs, "", 0))
+	svc.SetChangeListener(listener)
+	return svc, repo, listener
+}
+
+func TestRiskZoneService_ImportLayer(t *testing.T) {
+	svc, repo, listener := newTestRiskZoneService()
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+	layer := domain.RiskLayer{Layer: "sngre-inundaciones", Hazard: "flood", DefaultLevel: "alto", Source: "SNGRE"}
+
+	_, err := svc.ImportLayer(layer, []byte(floodLayer), domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	result, err := svc.ImportLayer(layer, []byte(floodLayer), admin)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Layer.Zones)
+	assert.Equal(t, 1, result.PropertiesAssessed, "only properties inside the layer are reassessed")
+	assert.Equal(t, 1, result.PropertiesFlagged)
+	require.Len(t, repo.flags["prop-inside"], 1)
+	assert.Equal(t, domain.RiskLevelHigh, repo.flags["prop-inside"][0].Level)
+	assert.Contains(t, listener.ids, "prop-inside")
+
+	// Deleting the layer clears the flags of the properties it covered
+	require.NoError(t, svc.DeleteLayer("sngre-inundaciones", admin))
+	assert.Empty(t, repo.flags["prop-inside"])
+	assert.ErrorContains(t, svc.DeleteLayer("sngre-inundaciones", admin), "not found")
+}
+
+func TestRiskZoneService_AssessProperty(t *testing.T) {
+	svc, repo, _ := newTestRiskZoneService()
+	_, err := svc.ImportLayer(domain.RiskLayer{Layer: "sngre-inundaciones", Hazard: "flood", DefaultLevel: "medium"},
+		[]byte(floodLayer), domain.NewActor("admin-1", string(domain.RoleAdmin), ""))
+	require.NoError(t, err)
+
+	lat, lng := -2.2, -79.9
+	property := &domain.Property{ID: "prop-new", Latitude: &lat, Longitude: &lng}
+	require.NoError(t, svc.AssessProperty(property))
+	require.Len(t, repo.flags["prop-new"], 1)
+	assert.Equal(t, domain.RiskLevelMedium, repo.flags["prop-new"][0].Level)
+
+	// Moving the property away clears its flags
+	property.Latitude, property.Longitude = nil, nil
+	require.NoError(t, svc.AssessProperty(property))
+	assert.Empty(t, repo.flags["prop-new"])
+}
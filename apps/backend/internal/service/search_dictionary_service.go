@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/search"
+)
+
+// maxAffectedDocuments limits how many properties are re-indexed after a single dictionary change
+const maxAffectedDocuments = 500
+
+// SearchDictionaryService manages the synonym and stop-word dictionary used by property search
+type SearchDictionaryService struct {
+	repo         *repository.SearchDictionaryRepository
+	propertyRepo repository.PropertyRepository
+	preprocessor *search.QueryPreprocessor
+	indexer      *search.Indexer
+	logger       *log.Logger
+}
+
+// NewSearchDictionaryService creates a new search dictionary service.
+// The indexer is optional; without it dictionary changes only affect query preprocessing.
+func NewSearchDictionaryService(repo *repository.SearchDictionaryRepository, propertyRepo repository.PropertyRepository, preprocessor *search.QueryPreprocessor, indexer *search.Indexer, logger *log.Logger) *SearchDictionaryService {
+	return &SearchDictionaryService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		preprocessor: preprocessor,
+		indexer:      indexer,
+		logger:       logger,
+	}
+}
+
+// Reload loads the dictionary from the database into the query preprocessor
+// and pushes it to the search backend when it supports index-time synonyms
+func (s *SearchDictionaryService) Reload() error {
+	synonyms, err := s.repo.ListSynonyms()
+	if err != nil {
+		return fmt.Errorf("failed to load synonyms: %w", err)
+	}
+
+	stopWords, err := s.repo.ListStopWords()
+	if err != nil {
+		return fmt.Errorf("failed to load stop words: %w", err)
+	}
+
+	s.preprocessor.Load(synonyms, stopWords)
+
+	if s.indexer != nil {
+		if dictionaryIndex, ok := s.indexer.Index().(search.DictionaryIndex); ok {
+			if err := dictionaryIndex.UpdateDictionary(s.preprocessor.Synonyms(), s.preprocessor.StopWords()); err != nil {
+				return fmt.Errorf("failed to update search backend dictionary: %w", err)
+			}
+		}
+	}
+
+	s.logger.Printf("Search dictionary loaded: %d synonyms, %d stop words", len(synonyms), len(stopWords))
+	return nil
+}
+
+// ListSynonyms returns all synonym entries
+func (s *SearchDictionaryService) ListSynonyms() ([]domain.SearchSynonym, error) {
+	synonyms, err := s.repo.ListSynonyms()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list synonyms: %w", err)
+	}
+	return synonyms, nil
+}
+
+// AddSynonym creates or updates a synonym and re-indexes the documents it affects
+func (s *SearchDictionaryService) AddSynonym(term, canonical string) (*domain.SearchSynonym, int, error) {
+	synonym, err := domain.NewSearchSynonym(term, canonical)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.repo.SaveSynonym(synonym); err != nil {
+		return nil, 0, fmt.Errorf("failed to save synonym: %w", err)
+	}
+
+	if err := s.Reload(); err != nil {
+		return nil, 0, err
+	}
+
+	reindexed := s.reindexAffected(synonym.Term, synonym.Canonical)
+	s.logger.Printf("Search synonym saved: %s -> %s (%d documents re-indexed)", synonym.Term, synonym.Canonical, reindexed)
+	return synonym, reindexed, nil
+}
+
+// DeleteSynonym removes a synonym and re-indexes the documents it affected
+func (s *SearchDictionaryService) DeleteSynonym(id string) (int, error) {
+	synonym, err := s.repo.GetSynonymByID(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get synonym: %w", err)
+	}
+
+	if err := s.repo.DeleteSynonym(id); err != nil {
+		return 0, fmt.Errorf("failed to delete synonym: %w", err)
+	}
+
+	if err := s.Reload(); err != nil {
+		return 0, err
+	}
+
+	reindexed := s.reindexAffected(synonym.Term, synonym.Canonical)
+	s.logger.Printf("Search synonym deleted: %s -> %s (%d documents re-indexed)", synonym.Term, synonym.Canonical, reindexed)
+	return reindexed, nil
+}
+
+// ListStopWords returns all stop words
+func (s *SearchDictionaryService) ListStopWords() ([]domain.SearchStopWord, error) {
+	stopWords, err := s.repo.ListStopWords()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stop words: %w", err)
+	}
+	return stopWords, nil
+}
+
+// AddStopWord adds a stop word to the dictionary
+func (s *SearchDictionaryService) AddStopWord(word string) (*domain.SearchStopWord, error) {
+	stopWord, err := domain.NewSearchStopWord(word)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddStopWord(stopWord); err != nil {
+		return nil, fmt.Errorf("failed to add stop word: %w", err)
+	}
+
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Search stop word added: %s", stopWord.Word)
+	return stopWord, nil
+}
+
+// DeleteStopWord removes a stop word from the dictionary
+func (s *SearchDictionaryService) DeleteStopWord(word string) error {
+	word = domain.NormalizeSearchTerm(word)
+	if word == "" {
+		return fmt.Errorf("stop word required")
+	}
+
+	if err := s.repo.DeleteStopWord(word); err != nil {
+		return fmt.Errorf("failed to delete stop word: %w", err)
+	}
+
+	if err := s.Reload(); err != nil {
+		return err
+	}
+
+	s.logger.Printf("Search stop word deleted: %s", word)
+	return nil
+}
+
+// reindexAffected queues the properties matching any of the given terms for re-indexing
+// and returns how many were queued
+func (s *SearchDictionaryService) reindexAffected(terms ...string) int {
+	if s.indexer == nil {
+		return 0
+	}
+
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		properties, err := s.propertyRepo.SearchProperties(term, maxAffectedDocuments)
+		if err != nil {
+			s.logger.Printf("Error finding properties affected by %q: %v", term, err)
+			continue
+		}
+		for i := range properties {
+			if seen[properties[i].ID] {
+				continue
+			}
+			seen[properties[i].ID] = true
+			s.indexer.EnqueueIndex(&properties[i])
+		}
+	}
+
+	return len(seen)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// sectorGuideLinksTTL is how long the published guides linked from property responses
+// are kept before reloading; edits through the service reload them immediately
+const sectorGuideLinksTTL = 10 * time.Minute
+
+// SectorGuideService manages the editorial guides of city sectors. Admins edit them;
+// published guides are public and linked from the properties of their sector.
+type SectorGuideService struct {
+	repo   repository.SectorGuideRepository
+	now    func() time.Time
+	logger *log.Logger
+
+	mu       sync.Mutex
+	links    map[string]*domain.SectorGuideLink
+	loadedAt time.Time
+}
+
+// NewSectorGuideService creates a sector guide service
+func NewSectorGuideService(repo repository.SectorGuideRepository, logger *log.Logger) *SectorGuideService {
+	return &SectorGuideService{
+		repo:   repo,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// CreateGuide creates a guide and fills its average prices from current listings
+func (s *SectorGuideService) CreateGuide(content *domain.SectorGuideContent, actor domain.Actor) (*domain.SectorGuide, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only content editors can manage sector guides")
+	}
+
+	content.Normalize()
+	if err := content.Validate(); err != nil {
+		return nil, err
+	}
+
+	guide := domain.NewSectorGuide(content, actor.UserID, s.now())
+	if err := s.repo.Create(guide); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Sector guide %s created by %s", guide.Slug, actor.UserID)
+	return s.refreshed(guide)
+}
+
+// UpdateGuide replaces the content of a guide
+func (s *SectorGuideService) UpdateGuide(id string, content *domain.SectorGuideContent, actor domain.Actor) (*domain.SectorGuide, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only content editors can manage sector guides")
+	}
+
+	guide, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	content.Normalize()
+	if err := content.Validate(); err != nil {
+		return nil, err
+	}
+
+	guide.Apply(content, actor.UserID, s.now())
+	if err := s.repo.Update(guide); err != nil {
+		return nil, err
+	}
+	return s.refreshed(guide)
+}
+
+// DeleteGuide removes a guide
+func (s *SectorGuideService) DeleteGuide(id string, actor domain.Actor) error {
+	if actor.Role != domain.RoleAdmin {
+		return fmt.Errorf("permission denied: only content editors can manage sector guides")
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	s.logger.Printf("Sector guide %s deleted by %s", id, actor.UserID)
+	s.invalidateLinks()
+	return nil
+}
+
+// GetGuide returns a guide by slug; drafts only to editors
+func (s *SectorGuideService) GetGuide(slug string, actor domain.Actor) (*domain.SectorGuide, error) {
+	guide, err := s.repo.GetBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if !guide.IsPublished() && actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("sector guide not found: %s", slug)
+	}
+	return guide, nil
+}
+
+// ListGuides lists the guides of a province and city, either empty for all. Drafts
+// are listed to editors who ask for them.
+func (s *SectorGuideService) ListGuides(province, city string, includeDrafts bool, actor domain.Actor) ([]domain.SectorGuide, error) {
+	return s.repo.List(province, city, includeDrafts && actor.Role == domain.RoleAdmin)
+}
+
+// RefreshStats recomputes the average prices of every guide from current listings
+func (s *SectorGuideService) RefreshStats() (int, error) {
+	return s.repo.RefreshStats(s.now())
+}
+
+// Link returns a copy of a property response whose properties link to the published
+// guide of their sector. It understands the same responses as CurrencyService.Annotate;
+// when guides cannot be loaded the response is served without links.
+func (s *SectorGuideService) Link(data interface{}) interface{} {
+	links, err := s.publishedLinks()
+	if err != nil {
+		s.logger.Printf("Error loading sector guides: %v", err)
+		return data
+	}
+	if len(links) == 0 {
+		return data
+	}
+	return linkSectorGuides(data, links)
+}
+
+// linkSectorGuides sets the guide link of the properties in data
+func linkSectorGuides(data interface{}, links map[string]*domain.SectorGuideLink) interface{} {
+	linkFor := func(property *domain.Property) *domain.SectorGuideLink {
+		if property.Sector == nil || *property.Sector == "" {
+			return nil
+		}
+		return links[domain.SectorKey(property.Province, property.City, *property.Sector)]
+	}
+
+	switch value := data.(type) {
+	case *domain.Property:
+		if value == nil {
+			return data
+		}
+		property := *value
+		property.SectorGuide = linkFor(&property)
+		return &property
+	case []domain.Property:
+		properties := make([]domain.Property, len(value))
+		for i := range value {
+			properties[i] = value[i]
+			properties[i].SectorGuide = linkFor(&properties[i])
+		}
+		return properties
+	case []repository.PropertySearchResult:
+		results := make([]repository.PropertySearchResult, len(value))
+		for i := range value {
+			results[i] = value[i]
+			results[i].Property.SectorGuide = linkFor(&results[i].Property)
+		}
+		return results
+	case *domain.PaginatedResponse:
+		if value == nil {
+			return data
+		}
+		page := *value
+		page.Data = linkSectorGuides(value.Data, links)
+		return &page
+	case *domain.FacetedSearchResponse:
+		if value == nil {
+			return data
+		}
+		page := *value
+		page.Data = linkSectorGuides(value.Data, links)
+		return &page
+	default:
+		return data
+	}
+}
+
+// publishedLinks returns the links of the published guides by sector, reloading them
+// once they are older than sectorGuideLinksTTL
+func (s *SectorGuideService) publishedLinks() (map[string]*domain.SectorGuideLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.links != nil && s.now().Sub(s.loadedAt) < sectorGuideLinksTTL {
+		return s.links, nil
+	}
+
+	guides, err := s.repo.List("", "", false)
+	if err != nil {
+		return nil, err
+	}
+	links := make(map[string]*domain.SectorGuideLink, len(guides))
+	for i := range guides {
+		links[domain.SectorKey(guides[i].Province, guides[i].City, guides[i].Sector)] = guides[i].Link()
+	}
+	s.links = links
+	s.loadedAt = s.now()
+	return links, nil
+}
+
+// invalidateLinks makes the next response reload the published guides
+func (s *SectorGuideService) invalidateLinks() {
+	s.mu.Lock()
+	s.links = nil
+	s.mu.Unlock()
+}
+
+// refreshed reloads the links after an edit and returns the guide with its stats
+func (s *SectorGuideService) refreshed(guide *domain.SectorGuide) (*domain.SectorGuide, error) {
+	s.invalidateLinks()
+	if _, err := s.repo.RefreshStats(s.now()); err != nil {
+		s.logger.Printf("Error refreshing sector guide stats: %v", err)
+		return guide, nil
+	}
+	return s.repo.GetByID(guide.ID)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// memorySectorGuides keeps sector guides in memory by ID
+type memorySectorGuides struct {
+	guides map[string]*domain.SectorGuide
+	lists  int
+}
+
+func (m *memorySectorGuides) Create(guide *domain.SectorGuide) error {
+	for _, existing := range m.guides {
+		if existing.Slug == guide.Slug {
+			return fmt.Errorf("sector guide already exists: %s", guide.Slug)
+		}
+	}
+	copied := *guide
+	m.guides[guide.ID] = &copied
+	return nil
+}
+
+func (m *memorySectorGuides) GetByID(id string) (*domain.SectorGuide, error) {
+	if guide, ok := m.guides[id]; ok {
+		copied := *guide
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("sector guide not found: %s", id)
+}
+
+func (m *memorySectorGuides) GetBySlug(slug string) (*domain.SectorGuide, error) {
+	for _, guide := range m.guides {
+		if guide.Slug == slug {
+			copied := *guide
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("sector guide not found: %s", slug)
+}
+
+func (m *memorySectorGuides) Update(guide *domain.SectorGuide) error {
+	copied := *guide
+	m.guides[guide.ID] = &copied
+	return nil
+}
+
+func (m *memorySectorGuides) Delete(id string) error {
+	if _, ok := m.guides[id]; !ok {
+		return fmt.Errorf("sector guide not found: %s", id)
+	}
+	delete(m.guides, id)
+	return nil
+}
+
+func (m *memorySectorGuides) List(province, city string, includeDrafts bool) ([]domain.SectorGuide, error) {
+	m.lists++
+	guides := []domain.SectorGuide{}
+	for _, guide := range m.guides {
+		if (province == "" || guide.Province == province) && (city == "" || guide.City == city) &&
+			(includeDrafts || guide.IsPublished()) {
+			guides = append(guides, *guide)
+		}
+	}
+	return guides, nil
+}
+
+func (m *memorySectorGuides) RefreshStats(now time.Time) (int, error) {
+	for _, guide := range m.guides {
+		guide.Stats = domain.SectorPriceStats{ActiveListings: 12, AvgSalePrice: 245000, UpdatedAt: &now}
+	}
+	return len(m.guides), nil
+}
+
+func newTestSectorGuideService() (*SectorGuideService, *memorySectorGuides) {
+	repo := &memorySectorGuides{guides: map[string]*domain.SectorGuide{}}
+	var logs bytes.Buffer
+	return NewSectorGuideService(repo, log.New(&logs, "", 0)), repo
+}
+
+func cumbayaGuide(status string) *domain.SectorGuideContent {
+	return &domain.SectorGuideContent{
+		Province: "Pichincha",
+		City:     "Quito",
+		Sector:   "Cumbayá",
+		Title:    "Vivir en Cumbayá",
+		Status:   status,
+	}
+}
+
+func TestSectorGuideService_Editing(t *testing.T) {
+	svc, _ := newTestSectorGuideService()
+	editor := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+	visitor := domain.NewActor("buyer-1", string(domain.RoleBuyer), "")
+
+	_, err := svc.CreateGuide(cumbayaGuide(""), domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	guide, err := svc.CreateGuide(cumbayaGuide(""), editor)
+	require.NoError(t, err)
+	assert.Equal(t, "cumbaya-quito", guide.Slug)
+	assert.Equal(t, 12, guide.Stats.ActiveListings, "average prices are filled on creation")
+
+	// Drafts are hidden from visitors
+	_, err = svc.GetGuide("cumbaya-quito", visitor)
+	assert.ErrorContains(t, err, "not found")
+	drafts, err := svc.ListGuides("Pichincha", "", true, visitor)
+	require.NoError(t, err)
+	assert.Empty(t, drafts)
+
+	_, err = svc.UpdateGuide(guide.ID, cumbayaGuide(domain.SectorGuidePublished), editor)
+	require.NoError(t, err)
+	published, err := svc.GetGuide("cumbaya-quito", visitor)
+	require.NoError(t, err)
+	assert.NotNil(t, published.PublishedAt)
+
+	_, err = svc.CreateGuide(cumbayaGuide(""), editor)
+	assert.ErrorContains(t, err, "already exists")
+
+	assert.ErrorContains(t, svc.DeleteGuide(guide.ID, visitor), "permission denied")
+	require.NoError(t, svc.DeleteGuide(guide.ID, editor))
+}
+
+func TestSectorGuideService_Link(t *testing.T) {
+	svc, repo := newTestSectorGuideService()
+	editor := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+	_, err := svc.CreateGuide(cumbayaGuide(domain.SectorGuidePublished), editor)
+	require.NoError(t, err)
+
+	cumbaya, tumbaco := "cumbaya", "Tumbaco"
+	properties := []domain.Property{
+		{ID: "prop-1", Province: "Pichincha", City: "Quito", Sector: &cumbaya},
+		{ID: "prop-2", Province: "Pichincha", City: "Quito", Sector: &tumbaco},
+		{ID: "prop-3", Province: "Pichincha", City: "Quito"},
+	}
+
+	linked := svc.Link(properties).([]domain.Property)
+	require.NotNil(t, linked[0].SectorGuide)
+	assert.Equal(t, "cumbaya-quito", linked[0].SectorGuide.Slug)
+	assert.Nil(t, linked[1].SectorGuide)
+	assert.Nil(t, linked[2].SectorGuide)
+	assert.Nil(t, properties[0].SectorGuide, "the original response is not modified")
+
+	page := svc.Link(&domain.PaginatedResponse{Data: []repository.PropertySearchResult{{Property: properties[0]}}}).(*domain.PaginatedResponse)
+	assert.Equal(t, "cumbaya-quito", page.Data.([]repository.PropertySearchResult)[0].Property.SectorGuide.Slug)
+
+	single := svc.Link(&properties[0]).(*domain.Property)
+	assert.NotNil(t, single.SectorGuide)
+
+	// Published guides are loaded once per TTL
+	assert.Equal(t, 1, repo.lists)
+}
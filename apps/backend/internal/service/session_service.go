@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// Session maintenance. Checks of access tokens are cached for sessionCheckInterval,
+// so a revoked session stops working on other instances within that time; inactive
+// sessions are kept for sessionRetention before being deleted.
+const (
+	sessionCheckInterval = time.Minute
+	sessionRetention     = 30 * 24 * time.Hour
+)
+
+// SessionService manages the sessions users are logged in with. Sessions persist the
+// latest refresh token of each login, so users can list the devices they are logged
+// in on and revoke them.
+type SessionService struct {
+	repo   repository.SessionRepository
+	logger *log.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	checked map[string]time.Time // active sessions by when they were last checked
+}
+
+// NewSessionService creates a session service
+func NewSessionService(repo repository.SessionRepository, logger *log.Logger) *SessionService {
+	return &SessionService{
+		repo:    repo,
+		logger:  logger,
+		now:     time.Now,
+		checked: map[string]time.Time{},
+	}
+}
+
+// Start stores the session a login started with its refresh token
+func (s *SessionService) Start(userID, sessionID, refreshToken, ipAddress, userAgent string, expiresAt time.Time) (*domain.Session, error) {
+	if userID == "" || sessionID == "" || refreshToken == "" {
+		return nil, fmt.Errorf("invalid session: user, session ID and refresh token are required")
+	}
+
+	session := domain.NewSession(sessionID, userID, refreshToken, ipAddress, userAgent, expiresAt)
+	if err := s.repo.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Rotate replaces the refresh token of a session after a refresh. A refresh token
+// that was already rotated is being replayed, possibly by someone who stole it, so
+// the session is revoked. Refresh tokens issued before sessions were stored start a
+// session.
+func (s *SessionService) Rotate(userID, sessionID, oldToken, newToken, ipAddress, userAgent string, expiresAt time.Time) error {
+	now := s.now()
+	rotated, err := s.repo.Rotate(sessionID, domain.HashRefreshToken(oldToken), domain.HashRefreshToken(newToken), ipAddress, now, expiresAt)
+	if err != nil {
+		return err
+	}
+	if rotated {
+		return nil
+	}
+
+	session, err := s.repo.GetByID(sessionID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			_, err = s.Start(userID, sessionID, newToken, ipAddress, userAgent, expiresAt)
+			return err
+		}
+		return err
+	}
+	if session.UserID != userID || !session.IsActive(now) {
+		return fmt.Errorf("session revoked: %s", sessionID)
+	}
+
+	if _, err := s.repo.Revoke(session.UserID, session.ID, now); err != nil {
+		return err
+	}
+	s.forget(session.ID)
+	s.logger.Printf("Refresh token reuse on session %s of user %s from %s; session revoked", session.ID, session.UserID, ipAddress)
+	return fmt.Errorf("session revoked: refresh token reuse detected")
+}
+
+// List returns the active sessions of a user, flagging the one the request came from
+func (s *SessionService) List(userID, currentSessionID string) ([]domain.Session, error) {
+	sessions, err := s.repo.ListActive(userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// Revoke revokes a session of a user, or with domain.SessionRevokeOthers every
+// session but the current one, and returns how many were revoked
+func (s *SessionService) Revoke(userID, sessionID, currentSessionID string) (int, error) {
+	now := s.now()
+	if sessionID == domain.SessionRevokeOthers {
+		if currentSessionID == "" {
+			return 0, fmt.Errorf("invalid request: the current session is unknown, log in again")
+		}
+		revoked, err := s.repo.RevokeOthers(userID, currentSessionID, now)
+		if err != nil {
+			return 0, err
+		}
+		s.mu.Lock()
+		s.checked = map[string]time.Time{}
+		s.mu.Unlock()
+		return revoked, nil
+	}
+
+	revoked, err := s.repo.Revoke(userID, sessionID, now)
+	if err != nil {
+		return 0, err
+	}
+	if !revoked {
+		return 0, fmt.Errorf("session not found: %s", sessionID)
+	}
+	s.forget(sessionID)
+	return 1, nil
+}
+
+// IsActive reports whether the session of an access token is still active and records
+// the activity. Active sessions are checked again after sessionCheckInterval.
+func (s *SessionService) IsActive(sessionID, ipAddress string) (bool, error) {
+	now := s.now()
+	s.mu.Lock()
+	checkedAt, ok := s.checked[sessionID]
+	s.mu.Unlock()
+	if ok && now.Sub(checkedAt) < sessionCheckInterval {
+		return true, nil
+	}
+
+	active, err := s.repo.Touch(sessionID, ipAddress, now)
+	if err != nil {
+		return false, err
+	}
+	if !active {
+		// Sessions whose login failed to store them have nothing to revoke
+		_, err := s.repo.GetByID(sessionID)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			s.forget(sessionID)
+			return false, err
+		}
+	}
+
+	s.mu.Lock()
+	s.checked[sessionID] = now
+	s.mu.Unlock()
+	return true, nil
+}
+
+// DeleteInactive deletes sessions expired or revoked more than sessionRetention ago
+func (s *SessionService) DeleteInactive() (int, error) {
+	return s.repo.DeleteInactive(s.now().Add(-sessionRetention))
+}
+
+// forget drops a session from the check cache
+func (s *SessionService) forget(sessionID string) {
+	s.mu.Lock()
+	delete(s.checked, sessionID)
+	s.mu.Unlock()
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memorySessionRepository keeps sessions in memory and counts touches
+type memorySessionRepository struct {
+	sessions map[string]*domain.Session
+	touches  int
+}
+
+func newMemorySessionRepository() *memorySessionRepository {
+	return &memorySessionRepository{sessions: map[string]*domain.Session{}}
+}
+
+func (r *memorySessionRepository) Create(session *domain.Session) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memorySessionRepository) GetByID(id string) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *memorySessionRepository) ListActive(userID string, now time.Time) ([]domain.Session, error) {
+	sessions := []domain.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive(now) {
+			sessions = append(sessions, *session)
+		}
+	}
+	sort.Slice(sessions, func(a, b int) bool { return sessions[a].LastSeenAt.After(sessions[b].LastSeenAt) })
+	return sessions, nil
+}
+
+func (r *memorySessionRepository) Rotate(id, oldHash, newHash, ipAddress string, seenAt, expiresAt time.Time) (bool, error) {
+	session, ok := r.sessions[id]
+	if !ok || session.RefreshTokenHash != oldHash || !session.IsActive(seenAt) {
+		return false, nil
+	}
+	session.RefreshTokenHash, session.IPAddress, session.LastSeenAt, session.ExpiresAt = newHash, ipAddress, seenAt, expiresAt
+	return true, nil
+}
+
+func (r *memorySessionRepository) Touch(id, ipAddress string, seenAt time.Time) (bool, error) {
+	r.touches++
+	session, ok := r.sessions[id]
+	if !ok || !session.IsActive(seenAt) {
+		return false, nil
+	}
+	session.IPAddress, session.LastSeenAt = ipAddress, seenAt
+	return true, nil
+}
+
+func (r *memorySessionRepository) Revoke(userID, id string, at time.Time) (bool, error) {
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return false, nil
+	}
+	session.RevokedAt = &at
+	return true, nil
+}
+
+func (r *memorySessionRepository) RevokeOthers(userID, keepID string, at time.Time) (int, error) {
+	revoked := 0
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.ID != keepID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *memorySessionRepository) DeleteInactive(before time.Time) (int, error) {
+	deleted := 0
+	for id, session := range r.sessions {
+		if session.ExpiresAt.Before(before) || (session.RevokedAt != nil && session.RevokedAt.Before(before)) {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newTestSessionService(repo *memorySessionRepository) (*SessionService, *bytes.Buffer) {
+	var logs bytes.Buffer
+	return NewSessionService(repo, log.New(&logs, "", 0)), &logs
+}
+
+func TestSessionService_ListAndRevoke(t *testing.T) {
+	repo := newMemorySessionRepository()
+	svc, _ := newTestSessionService(repo)
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := svc.Start("user-1", "laptop", "token-1", "190.152.10.4", "Mozilla/5.0 (Windows NT 10.0) Chrome/139.0 Safari/537.36", expiresAt)
+	require.NoError(t, err)
+	_, err = svc.Start("user-1", "phone", "token-2", "181.39.1.2", "okhttp/4.12.0", expiresAt)
+	require.NoError(t, err)
+	_, err = svc.Start("user-1", "tablet", "token-3", "181.39.1.3", "", expiresAt)
+	require.NoError(t, err)
+	_, err = svc.Start("user-2", "other", "token-4", "181.39.1.4", "", expiresAt)
+	require.NoError(t, err)
+
+	sessions, err := svc.List("user-1", "laptop")
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	for _, session := range sessions {
+		assert.Equal(t, session.ID == "laptop", session.Current, session.ID)
+	}
+
+	revoked, err := svc.Revoke("user-1", "phone", "laptop")
+	require.NoError(t, err)
+	assert.Equal(t, 1, revoked)
+
+	// Sessions of other users and revoked sessions are not found
+	_, err = svc.Revoke("user-1", "other", "laptop")
+	assert.ErrorContains(t, err, "not found")
+	_, err = svc.Revoke("user-1", "phone", "laptop")
+	assert.ErrorContains(t, err, "not found")
+
+	revoked, err = svc.Revoke("user-1", domain.SessionRevokeOthers, "laptop")
+	require.NoError(t, err)
+	assert.Equal(t, 1, revoked)
+
+	sessions, err = svc.List("user-1", "laptop")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Current)
+
+	_, err = svc.Revoke("user-1", domain.SessionRevokeOthers, "")
+	assert.ErrorContains(t, err, "invalid")
+}
+
+func TestSessionService_Rotate(t *testing.T) {
+	repo := newMemorySessionRepository()
+	svc, logs := newTestSessionService(repo)
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := svc.Start("user-1", "session-1", "token-1", "190.152.10.4", "", expiresAt)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Rotate("user-1", "session-1", "token-1", "token-2", "190.152.10.5", "", expiresAt))
+	assert.Equal(t, "190.152.10.5", repo.sessions["session-1"].IPAddress)
+
+	// Replaying a rotated refresh token revokes the session
+	err = svc.Rotate("user-1", "session-1", "token-1", "token-3", "203.0.113.9", "", expiresAt)
+	assert.ErrorContains(t, err, "session revoked")
+	assert.NotNil(t, repo.sessions["session-1"].RevokedAt)
+	assert.Contains(t, logs.String(), "Refresh token reuse")
+
+	err = svc.Rotate("user-1", "session-1", "token-2", "token-3", "190.152.10.5", "", expiresAt)
+	assert.ErrorContains(t, err, "session revoked")
+
+	// Refresh tokens issued before sessions were stored start one
+	require.NoError(t, svc.Rotate("user-1", "legacy", "old-token", "new-token", "190.152.10.4", "okhttp/4.12.0", expiresAt))
+	assert.Equal(t, domain.HashRefreshToken("new-token"), repo.sessions["legacy"].RefreshTokenHash)
+}
+
+func TestSessionService_IsActive(t *testing.T) {
+	repo := newMemorySessionRepository()
+	svc, _ := newTestSessionService(repo)
+	now := time.Date(2025, 8, 26, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	repo.sessions["session-1"] = &domain.Session{ID: "session-1", UserID: "user-1", ExpiresAt: now.Add(time.Hour)}
+
+	active, err := svc.IsActive("session-1", "190.152.10.4")
+	require.NoError(t, err)
+	assert.True(t, active)
+	_, _ = svc.IsActive("session-1", "190.152.10.4")
+	assert.Equal(t, 1, repo.touches, "checks are cached")
+	assert.Equal(t, now, repo.sessions["session-1"].LastSeenAt)
+
+	// Revoking through this instance takes effect immediately
+	_, err = svc.Revoke("user-1", "session-1", "")
+	require.NoError(t, err)
+	active, err = svc.IsActive("session-1", "190.152.10.4")
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	// Sessions that were never stored are accepted
+	active, err = svc.IsActive("unknown", "190.152.10.4")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	// Inactive sessions are deleted after the retention period
+	now = now.Add(sessionRetention + 2*time.Hour)
+	deleted, err := svc.DeleteInactive()
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
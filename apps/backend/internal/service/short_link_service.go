@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
+	"realty-core/internal/repository"
+)
+
+// maxShortLinkAttempts bounds the retries when a generated code is already taken
+const maxShortLinkAttempts = 3
+
+// ShortLinkService creates short links for sharing properties, resolves them with click
+// tracking and reports the clicks together with the property's views
+type ShortLinkService struct {
+	repo         repository.ShortLinkRepository
+	propertyRepo repository.PropertyRepository
+	shareURL     string
+	propertyURL  string
+	now          func() time.Time
+	logger       *log.Logger
+}
+
+// NewShortLinkService creates a new short link service. shareURL is the public host
+// serving /l/{code}, e.g. https://inmo.ec; propertyURL is the property page the links
+// redirect to, with the slug appended, e.g. https://inmo.ec/propiedades
+func NewShortLinkService(
+	repo repository.ShortLinkRepository,
+	propertyRepo repository.PropertyRepository,
+	shareURL string,
+	propertyURL string,
+	logger *log.Logger,
+) *ShortLinkService {
+	return &ShortLinkService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		shareURL:     strings.TrimSuffix(shareURL, "/"),
+		propertyURL:  strings.TrimSuffix(propertyURL, "/"),
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// CreateLink creates a short link to a property for any signed-in user, optionally
+// tagged with a campaign
+func (s *ShortLinkService) CreateLink(propertyID, campaign string, actor domain.Actor) (*domain.ShortLink, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to create short links")
+	}
+	if _, err := s.propertyRepo.GetByID(propertyID); err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	var err error
+	for attempt := 0; attempt < maxShortLinkAttempts; attempt++ {
+		var link *domain.ShortLink
+		link, err = domain.NewShortLink(propertyID, campaign, actor.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid short link: %w", err)
+		}
+
+		err = s.repo.Create(link)
+		if err == nil {
+			link.URL = s.linkURL(link.Code)
+			s.logger.Printf("Short link %s created for property %s by %s", link.Code, propertyID, actor.UserID)
+			return link, nil
+		}
+		if !strings.Contains(err.Error(), "already exists") {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// ListLinks lists the short links of a property for whoever manages it
+func (s *ShortLinkService) ListLinks(propertyID string, actor domain.Actor) ([]domain.ShortLink, error) {
+	if _, err := s.managedProperty(propertyID, actor); err != nil {
+		return nil, err
+	}
+
+	links, err := s.repo.ListByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		links[i].URL = s.linkURL(links[i].Code)
+	}
+	return links, nil
+}
+
+// Resolve records a click through a short link and returns the property page to redirect
+// to. A failure to record the click is logged and does not break the redirect.
+func (s *ShortLinkService) Resolve(code, sourceTag, referer string) (string, error) {
+	if !domain.IsValidShortLinkCode(code) {
+		return "", fmt.Errorf("short link not found: %s", code)
+	}
+
+	link, err := s.repo.GetByCode(code)
+	if err != nil {
+		return "", err
+	}
+	property, err := s.propertyRepo.GetByID(link.PropertyID)
+	if err != nil {
+		return "", fmt.Errorf("property not found: %w", err)
+	}
+
+	click := &domain.ShortLinkClick{
+		LinkID:    link.ID,
+		Source:    domain.ClickSource(sourceTag, referer),
+		ClickedAt: s.now(),
+	}
+	if err := s.repo.RecordClick(click); err != nil {
+		s.logger.Printf("Error recording click on short link %s: %v", code, err)
+	} else if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter("short_link_clicks_total", "Total number of visits through property short links").Inc()
+	}
+
+	return s.propertyPageURL(property, link.Campaign), nil
+}
+
+// GetViewStats returns the views of a property together with the clicks through its
+// short links, for whoever manages it
+func (s *ShortLinkService) GetViewStats(propertyID string, actor domain.Actor) (*domain.PropertyViewStats, error) {
+	property, err := s.managedProperty(propertyID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.repo.StatsByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PropertyViewStats{
+		PropertyID: property.ID,
+		Views:      property.ViewCount,
+		ShortLinks: *links,
+	}, nil
+}
+
+// managedProperty loads a property the actor manages
+func (s *ShortLinkService) managedProperty(propertyID string, actor domain.Actor) (*domain.Property, error) {
+	property, err := s.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !canManageListing(property, actor) {
+		return nil, fmt.Errorf("permission denied: only the property's agency, agent or owner can see its link analytics")
+	}
+	return property, nil
+}
+
+// linkURL returns the public URL of a short link
+func (s *ShortLinkService) linkURL(code string) string {
+	return s.shareURL + "/l/" + code
+}
+
+// propertyPageURL returns the page of a property, keeping the link's campaign for the
+// site's own analytics
+func (s *ShortLinkService) propertyPageURL(property *domain.Property, campaign string) string {
+	target := s.propertyURL + "/" + url.PathEscape(property.Slug)
+	if campaign != "" {
+		target += "?utm_campaign=" + url.QueryEscape(campaign)
+	}
+	return target
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryShortLinkRepository is an in-memory ShortLinkRepository
+type memoryShortLinkRepository struct {
+	links  map[string]domain.ShortLink
+	clicks []domain.ShortLinkClick
+}
+
+func (r *memoryShortLinkRepository) Create(link *domain.ShortLink) error {
+	if _, ok := r.links[link.Code]; ok {
+		return fmt.Errorf("short link code already exists: %s", link.Code)
+	}
+	r.links[link.Code] = *link
+	return nil
+}
+
+func (r *memoryShortLinkRepository) GetByCode(code string) (*domain.ShortLink, error) {
+	link, ok := r.links[code]
+	if !ok {
+		return nil, fmt.Errorf("short link not found: %s", code)
+	}
+	return &link, nil
+}
+
+func (r *memoryShortLinkRepository) ListByProperty(propertyID string) ([]domain.ShortLink, error) {
+	links := []domain.ShortLink{}
+	for _, link := range r.links {
+		if link.PropertyID == propertyID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (r *memoryShortLinkRepository) RecordClick(click *domain.ShortLinkClick) error {
+	for code, link := range r.links {
+		if link.ID == click.LinkID {
+			link.ClickCount++
+			r.links[code] = link
+		}
+	}
+	r.clicks = append(r.clicks, *click)
+	return nil
+}
+
+func (r *memoryShortLinkRepository) StatsByProperty(propertyID string) (*domain.ShortLinkStats, error) {
+	stats := &domain.ShortLinkStats{BySource: map[string]int{}, ByCampaign: map[string]int{}}
+	for _, link := range r.links {
+		if link.PropertyID != propertyID {
+			continue
+		}
+		stats.Links++
+		stats.Clicks += link.ClickCount
+		for _, click := range r.clicks {
+			if click.LinkID != link.ID {
+				continue
+			}
+			stats.BySource[click.Source]++
+			campaign := link.Campaign
+			if campaign == "" {
+				campaign = "none"
+			}
+			stats.ByCampaign[campaign]++
+		}
+	}
+	return stats, nil
+}
+
+func TestShortLinkService_CreateAndResolve(t *testing.T) {
+	property := domain.NewProperty("Casa en Cumbayá", "Casa con jardín", "Pichincha", "Quito", "house", 250000, "owner-1")
+	property.ID = "prop-1"
+	property.ViewCount = 40
+	propertyRepo := new(MockPropertyRepository)
+	propertyRepo.On("GetByID", "prop-1").Return(property, nil)
+
+	repo := &memoryShortLinkRepository{links: map[string]domain.ShortLink{}}
+	service := NewShortLinkService(repo, propertyRepo, "https://inmo.ec/", "https://inmo.ec/propiedades", log.New(os.Stderr, "", 0))
+
+	_, err := service.CreateLink("prop-1", "", domain.Actor{})
+	assert.ErrorContains(t, err, "permission denied")
+
+	buyer := domain.NewActor("buyer-1", string(domain.RoleBuyer), "")
+	link, err := service.CreateLink("prop-1", "Verano", buyer)
+	require.NoError(t, err)
+	assert.Equal(t, "https://inmo.ec/l/"+link.Code, link.URL)
+	assert.Equal(t, "verano", link.Campaign)
+
+	target, err := service.Resolve(link.Code, "", "https://l.facebook.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://inmo.ec/propiedades/"+property.Slug+"?utm_campaign=verano", target)
+	_, err = service.Resolve(link.Code, "whatsapp", "")
+	require.NoError(t, err)
+
+	_, err = service.Resolve("missing", "", "")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = service.GetViewStats("prop-1", buyer)
+	assert.ErrorContains(t, err, "permission denied")
+
+	stats, err := service.GetViewStats("prop-1", domain.NewActor("owner-1", string(domain.RoleOwner), ""))
+	require.NoError(t, err)
+	assert.Equal(t, 40, stats.Views)
+	assert.Equal(t, 1, stats.ShortLinks.Links)
+	assert.Equal(t, 2, stats.ShortLinks.Clicks)
+	assert.Equal(t, map[string]int{"facebook": 1, "whatsapp": 1}, stats.ShortLinks.BySource)
+	assert.Equal(t, map[string]int{"verano": 2}, stats.ShortLinks.ByCampaign)
+}
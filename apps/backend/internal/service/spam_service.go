@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// spamRateMaxKeys is the number of tracked submitters above which stale ones are dropped
+const spamRateMaxKeys = 10000
+
+// SpamReleaser publishes or discards the quarantined submissions of one entity type
+type SpamReleaser interface {
+	// ReleaseQuarantined publishes an approved submission; blocked submissions stay hidden
+	ReleaseQuarantined(entityID string, approved bool) error
+}
+
+// SpamService scores inquiries and listings for spam when they are submitted and
+// keeps the queue of quarantined submissions for administrators. The rate signal
+// counts submissions in memory, per instance.
+type SpamService struct {
+	repo      repository.SpamCaseRepository
+	users     UserLookup
+	rules     domain.SpamRules
+	releasers map[string]SpamReleaser
+	logger    *log.Logger
+	now       func() time.Time
+
+	mu     sync.Mutex
+	recent map[string][]time.Time
+}
+
+// NewSpamService creates a spam service with the default rules. users provides the
+// email and phone of submitters when a submission has none of its own.
+func NewSpamService(repo repository.SpamCaseRepository, users UserLookup, logger *log.Logger) *SpamService {
+	return &SpamService{
+		repo:      repo,
+		users:     users,
+		rules:     domain.DefaultSpamRules(),
+		releasers: map[string]SpamReleaser{},
+		logger:    logger,
+		now:       time.Now,
+		recent:    map[string][]time.Time{},
+	}
+}
+
+// SetRules replaces the disposable email domains and keywords
+func (s *SpamService) SetRules(rules domain.SpamRules) {
+	s.rules = rules
+}
+
+// SetReleaser registers what publishes approved submissions of an entity type
+func (s *SpamService) SetReleaser(entityType string, releaser SpamReleaser) {
+	s.releasers[entityType] = releaser
+}
+
+// Screen scores a submission and counts it towards the submitter's rate
+func (s *SpamService) Screen(entityType, submitterID string, check domain.SpamCheck) domain.SpamAssessment {
+	if submitterID != "" {
+		if (check.Email == "" || check.Phone == "") && s.users != nil {
+			if user, err := s.users.GetByID(submitterID); err == nil && user != nil {
+				if check.Email == "" {
+					check.Email = user.Email
+				}
+				if check.Phone == "" && user.Phone != nil {
+					check.Phone = *user.Phone
+				}
+			}
+		}
+		check.RecentSubmissions = s.countSubmission(entityType + "/" + submitterID)
+	}
+	if check.RateLimit == 0 {
+		check.RateLimit = spamRateLimit(entityType)
+	}
+
+	return s.rules.Assess(check)
+}
+
+// Quarantine opens a case for a submission held by Screen
+func (s *SpamService) Quarantine(entityType, entityID, submitterID string, assessment domain.SpamAssessment) (*domain.SpamCase, error) {
+	spamCase := domain.NewSpamCase(entityType, entityID, submitterID, assessment)
+	if err := s.repo.Create(spamCase); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Quarantined %s %s with spam score %d", entityType, entityID, assessment.Score)
+	return spamCase, nil
+}
+
+// ListQueue returns spam cases, quarantined by default, oldest first. Only
+// administrators review spam.
+func (s *SpamService) ListQueue(filter domain.SpamCaseFilter, actor domain.Actor) ([]domain.SpamCase, int, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, 0, fmt.Errorf("permission denied: only administrators can review spam")
+	}
+	if filter.Status == "" {
+		filter.Status = domain.SpamCaseQuarantined
+	}
+	if !domain.IsValidSpamCaseStatus(filter.Status) {
+		return nil, 0, fmt.Errorf("invalid spam case status: %s", filter.Status)
+	}
+	if filter.EntityType != "" && !domain.IsValidSpamEntityType(filter.EntityType) {
+		return nil, 0, fmt.Errorf("invalid spam entity type: %s", filter.EntityType)
+	}
+
+	filter.Limit, filter.Offset = reviewPage(filter.Limit, filter.Offset)
+	return s.repo.List(filter)
+}
+
+// Decide approves or blocks a quarantined submission. Approved submissions are
+// published by the releaser of their entity type; blocked ones stay hidden.
+func (s *SpamService) Decide(caseID string, approve bool, actor domain.Actor) (*domain.SpamCase, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can review spam")
+	}
+
+	spamCase, err := s.repo.GetByID(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := spamCase.Decide(approve, actor.UserID, s.now()); err != nil {
+		return nil, err
+	}
+
+	// Release first: a failed release leaves the case quarantined so it can be retried
+	if releaser, ok := s.releasers[spamCase.EntityType]; ok {
+		if err := releaser.ReleaseQuarantined(spamCase.EntityID, approve); err != nil {
+			return nil, fmt.Errorf("failed to release %s %s: %w", spamCase.EntityType, spamCase.EntityID, err)
+		}
+	}
+	if err := s.repo.Decide(spamCase); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Spam case %s (%s %s) %s by %s", spamCase.ID, spamCase.EntityType, spamCase.EntityID,
+		spamCase.Status, actor.UserID)
+	return spamCase, nil
+}
+
+// countSubmission records a submission and returns how many the key made within
+// domain.SpamRateWindow
+func (s *SpamService) countSubmission(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	cutoff := now.Add(-domain.SpamRateWindow)
+	recent := []time.Time{now} // newest first
+	for _, at := range s.recent[key] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	s.recent[key] = recent
+
+	// Submitters who stopped submitting are dropped once the map grows
+	if len(s.recent) > spamRateMaxKeys {
+		for other, times := range s.recent {
+			if !times[0].After(cutoff) {
+				delete(s.recent, other)
+			}
+		}
+	}
+	return len(recent)
+}
+
+// spamRateLimit returns the hourly submission limit of an entity type
+func spamRateLimit(entityType string) int {
+	if entityType == domain.SpamEntityListing {
+		return domain.SpamListingRateLimit
+	}
+	return domain.SpamInquiryRateLimit
+}
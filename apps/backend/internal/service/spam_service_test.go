@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+type memorySpamCaseRepository struct {
+	cases map[string]domain.SpamCase
+}
+
+func (r *memorySpamCaseRepository) Create(spamCase *domain.SpamCase) error {
+	r.cases[spamCase.ID] = *spamCase
+	return nil
+}
+
+func (r *memorySpamCaseRepository) GetByID(id string) (*domain.SpamCase, error) {
+	spamCase, ok := r.cases[id]
+	if !ok {
+		return nil, fmt.Errorf("spam case not found: %s", id)
+	}
+	return &spamCase, nil
+}
+
+func (r *memorySpamCaseRepository) List(filter domain.SpamCaseFilter) ([]domain.SpamCase, int, error) {
+	cases := []domain.SpamCase{}
+	for _, spamCase := range r.cases {
+		if spamCase.Status == filter.Status && (filter.EntityType == "" || spamCase.EntityType == filter.EntityType) {
+			cases = append(cases, spamCase)
+		}
+	}
+	return cases, len(cases), nil
+}
+
+func (r *memorySpamCaseRepository) Decide(spamCase *domain.SpamCase) error {
+	r.cases[spamCase.ID] = *spamCase
+	return nil
+}
+
+func newTestSpamService() *SpamService {
+	phone := "0991234567"
+	users := memoryUsers{
+		"tenant-1": {ID: "tenant-1", Email: "ana@gmail.com", Phone: &phone},
+		"tenant-2": {ID: "tenant-2", Email: "bot@mailinator.com"},
+	}
+	return NewSpamService(&memorySpamCaseRepository{cases: map[string]domain.SpamCase{}}, users, log.New(os.Stdout, "", 0))
+}
+
+func TestSpamService_Screen(t *testing.T) {
+	spam := newTestSpamService()
+
+	assessment := spam.Screen(domain.SpamEntityInquiry, "tenant-2", domain.SpamCheck{})
+	require.Len(t, assessment.Signals, 1)
+	assert.Equal(t, domain.SpamSignalDisposableEmail, assessment.Signals[0].Name, "the submitter's email is screened")
+
+	for i := 0; i < domain.SpamInquiryRateLimit; i++ {
+		assert.Zero(t, spam.Screen(domain.SpamEntityInquiry, "tenant-1", domain.SpamCheck{}).Score)
+	}
+	limited := spam.Screen(domain.SpamEntityInquiry, "tenant-1", domain.SpamCheck{})
+	require.Len(t, limited.Signals, 1)
+	assert.Equal(t, domain.SpamSignalRate, limited.Signals[0].Name)
+	assert.Zero(t, spam.Screen(domain.SpamEntityListing, "tenant-1", domain.SpamCheck{}).Score, "rates are counted per entity type")
+}
+
+func TestSpamService_QuarantinedApplication(t *testing.T) {
+	applications, notifier := newTestApplicationService(t)
+	spam := newTestSpamService()
+	applications.SetSpamService(spam)
+	admin := domain.NewActor("admin-1", "admin", "")
+
+	application, err := applications.Submit(SubmitApplicationRequest{
+		PropertyID: "rental-1", MonthlyIncome: 2600, Occupants: 1, Website: "http://cheap-pills.example",
+	}, domain.NewActor("tenant-1", string(domain.RoleBuyer), ""))
+	require.NoError(t, err, "bots are not told their submission was quarantined")
+	assert.Empty(t, notifier.events, "the landlord is not notified of quarantined applications")
+
+	_, _, err = spam.ListQueue(domain.SpamCaseFilter{}, domain.NewActor("tenant-1", "buyer", ""))
+	assert.ErrorContains(t, err, "permission denied")
+	queue, total, err := spam.ListQueue(domain.SpamCaseFilter{EntityType: domain.SpamEntityInquiry}, admin)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, application.ID, queue[0].EntityID)
+	assert.Equal(t, domain.MaxSpamScore, queue[0].Score)
+
+	decided, err := spam.Decide(queue[0].ID, true, admin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SpamCaseApproved, decided.Status)
+	assert.Equal(t, []string{"submitted"}, notifier.events, "approval notifies the landlord")
+
+	_, err = spam.Decide(queue[0].ID, false, admin)
+	assert.ErrorContains(t, err, "invalid spam case")
+}
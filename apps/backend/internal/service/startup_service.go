@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"realty-core/internal/cache"
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+	"realty-core/internal/search"
+	"realty-core/internal/storage"
+)
+
+// StartupConfig configures the checks gating the readiness of an instance
+type StartupConfig struct {
+	GracePeriod  time.Duration // how long failing checks are retried before startup fails
+	Interval     time.Duration // wait between attempts
+	CheckTimeout time.Duration // how long a single check may take
+}
+
+// StartupCheck verifies a dependency an instance needs before it takes traffic
+type StartupCheck struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// StartupService runs the startup checks of an instance. Its readiness stays off until
+// they all pass, so load balancers never route to an instance that cannot serve yet,
+// e.g. the green deployment of a blue/green release whose migrations have not run.
+type StartupService struct {
+	checks []StartupCheck
+	config StartupConfig
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	logger *log.Logger
+
+	mu     sync.RWMutex
+	report domain.StartupReport
+}
+
+// NewStartupService creates a new startup service running the given checks
+func NewStartupService(config StartupConfig, logger *log.Logger, checks ...StartupCheck) *StartupService {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if config.GracePeriod <= 0 {
+		config.GracePeriod = 2 * time.Minute
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	if config.CheckTimeout <= 0 {
+		config.CheckTimeout = 10 * time.Second
+	}
+
+	s := &StartupService{
+		checks: checks,
+		config: config,
+		now:    time.Now,
+		sleep:  sleepContext,
+		logger: logger,
+	}
+	s.report = domain.StartupReport{
+		Phase:       domain.StartupPhaseStarting,
+		StartedAt:   s.now(),
+		GracePeriod: config.GracePeriod.String(),
+		Checks:      make([]domain.StartupCheckResult, len(checks)),
+	}
+	for i, check := range checks {
+		s.report.Checks[i] = domain.StartupCheckResult{Name: check.Name}
+	}
+	return s
+}
+
+// Run runs the checks until they all pass, retrying the failing ones, or the grace
+// period runs out. Checks that passed are not run again.
+func (s *StartupService) Run(ctx context.Context) error {
+	s.mu.Lock()
+	startedAt := s.now()
+	s.report.StartedAt = startedAt
+	s.mu.Unlock()
+	deadline := startedAt.Add(s.config.GracePeriod)
+
+	for {
+		passed := true
+		for i, check := range s.checks {
+			if s.result(i).Passed {
+				continue
+			}
+			err := s.runCheck(ctx, check)
+			s.record(i, err)
+			if err != nil {
+				passed = false
+			}
+		}
+
+		if passed {
+			s.mu.Lock()
+			readyAt := s.now()
+			s.report.Phase = domain.StartupPhaseReady
+			s.report.ReadyAt = &readyAt
+			s.mu.Unlock()
+			s.logger.Printf("Startup checks passed in %s; instance is ready", readyAt.Sub(startedAt).Round(time.Millisecond))
+			return nil
+		}
+
+		if !s.now().Before(deadline) {
+			s.mu.Lock()
+			s.report.Phase = domain.StartupPhaseFailed
+			failed := s.report.FailedChecks()
+			s.mu.Unlock()
+			return fmt.Errorf("startup checks failed after %s: %s", s.config.GracePeriod, strings.Join(failed, ", "))
+		}
+		if err := s.sleep(ctx, s.config.Interval); err != nil {
+			return err
+		}
+	}
+}
+
+// runCheck runs a check within the check timeout
+func (s *StartupService) runCheck(ctx context.Context, check StartupCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.CheckTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", s.config.CheckTimeout)
+	}
+}
+
+func (s *StartupService) result(i int) domain.StartupCheckResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.report.Checks[i]
+}
+
+func (s *StartupService) record(i int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := &s.report.Checks[i]
+	result.Attempts++
+	result.CheckedAt = s.now()
+	result.Passed = err == nil
+	result.Error = ""
+	if err != nil {
+		result.Error = err.Error()
+		if result.Attempts == 1 {
+			s.logger.Printf("Startup check %s failed, retrying: %v", result.Name, err)
+		}
+	}
+}
+
+// Ready reports whether every startup check passed
+func (s *StartupService) Ready() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.report.Ready()
+}
+
+// Report returns the progress of the startup phase
+func (s *StartupService) Report() domain.StartupReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	report := s.report
+	report.Checks = append([]domain.StartupCheckResult(nil), s.report.Checks...)
+	return report
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SchemaVersionCheck checks the database has at least the migrations this build relies
+// on. A newer schema passes, so the previous deployment keeps serving while the next
+// one migrates.
+func SchemaVersionCheck(repo repository.SchemaRepository, required int) StartupCheck {
+	return StartupCheck{Name: "schema_version", Run: func(ctx context.Context) error {
+		version, err := repo.CurrentVersion()
+		if err != nil {
+			return err
+		}
+		if version < required {
+			return fmt.Errorf("database schema is at migration %03d, this build requires %03d", version, required)
+		}
+		return nil
+	}}
+}
+
+// StorageWriteCheck checks image storage accepts writes by storing and deleting a probe
+// object
+func StorageWriteCheck(imageStorage storage.ImageStorage) StartupCheck {
+	return StartupCheck{Name: "storage_write", Run: func(ctx context.Context) error {
+		path, err := imageStorage.Store([]byte("startup check"), "startup-checks/"+uuid.New().String()+".txt")
+		if err != nil {
+			return fmt.Errorf("failed to write to storage: %w", err)
+		}
+		if err := imageStorage.Delete(path); err != nil {
+			return fmt.Errorf("failed to delete from storage: %w", err)
+		}
+		return nil
+	}}
+}
+
+// CacheCheck checks the shared response cache answers
+func CacheCheck(store cache.ResponseStore) StartupCheck {
+	return StartupCheck{Name: "cache", Run: func(ctx context.Context) error {
+		if _, _, err := store.Get("startup-check"); err != nil {
+			return fmt.Errorf("failed to reach cache: %w", err)
+		}
+		return nil
+	}}
+}
+
+// SearchIndexCheck checks the search backend answers queries
+func SearchIndexCheck(index search.Index) StartupCheck {
+	return StartupCheck{Name: "search_index", Run: func(ctx context.Context) error {
+		if checker, ok := index.(search.HealthChecker); ok {
+			return checker.Health()
+		}
+		if _, err := index.Search("casa", 1); err != nil {
+			return fmt.Errorf("failed to query search index: %w", err)
+		}
+		return nil
+	}}
+}
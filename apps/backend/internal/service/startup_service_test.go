@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// schemaAt reports a fixed schema version
+type schemaAt int
+
+func (v schemaAt) CurrentVersion() (int, error) {
+	return int(v), nil
+}
+
+// newStartupTest runs startup on a fake clock advanced by each sleep
+func newStartupTest(config StartupConfig, checks ...StartupCheck) *StartupService {
+	svc := NewStartupService(config, log.New(&bytes.Buffer{}, "", 0), checks...)
+	now := time.Date(2025, 9, 17, 8, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	svc.sleep = func(ctx context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return nil
+	}
+	return svc
+}
+
+func TestStartupService_Run(t *testing.T) {
+	cacheAttempts := 0
+	schemaAttempts := 0
+	svc := newStartupTest(StartupConfig{GracePeriod: time.Minute, Interval: 5 * time.Second},
+		StartupCheck{Name: "schema_version", Run: func(ctx context.Context) error {
+			schemaAttempts++
+			return nil
+		}},
+		StartupCheck{Name: "cache", Run: func(ctx context.Context) error {
+			cacheAttempts++
+			if cacheAttempts < 3 {
+				return fmt.Errorf("failed to reach cache: connection refused")
+			}
+			return nil
+		}},
+	)
+	assert.False(t, svc.Ready())
+	assert.Equal(t, domain.StartupPhaseStarting, svc.Report().Phase)
+
+	require.NoError(t, svc.Run(context.Background()))
+	assert.True(t, svc.Ready())
+	report := svc.Report()
+	require.NotNil(t, report.ReadyAt)
+	assert.Equal(t, 10*time.Second, report.ReadyAt.Sub(report.StartedAt))
+	assert.Equal(t, 1, schemaAttempts, "passed checks are not run again")
+	assert.Equal(t, 3, report.Checks[1].Attempts)
+	assert.Empty(t, report.Checks[1].Error)
+}
+
+func TestStartupService_RunFailsAfterGracePeriod(t *testing.T) {
+	svc := newStartupTest(StartupConfig{GracePeriod: 30 * time.Second, Interval: 10 * time.Second},
+		SchemaVersionCheck(schemaAt(77), 78))
+
+	err := svc.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema_version")
+	report := svc.Report()
+	assert.Equal(t, domain.StartupPhaseFailed, report.Phase)
+	assert.Equal(t, 4, report.Checks[0].Attempts)
+	assert.Contains(t, report.Checks[0].Error, "migration 077, this build requires 078")
+}
+
+func TestStartupService_CheckTimeout(t *testing.T) {
+	svc := NewStartupService(StartupConfig{GracePeriod: time.Millisecond, CheckTimeout: 10 * time.Millisecond},
+		log.New(&bytes.Buffer{}, "", 0),
+		StartupCheck{Name: "search_index", Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}})
+
+	err := svc.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, svc.Report().Checks[0].Error, "timed out")
+}
+
+func TestSchemaVersionCheck_NewerSchemaPasses(t *testing.T) {
+	assert.NoError(t, SchemaVersionCheck(schemaAt(80), 78).Run(context.Background()))
+}
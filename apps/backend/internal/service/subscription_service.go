@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// SubscriptionService manages agency subscription plans and enforces their limits
+type SubscriptionService struct {
+	repo       repository.SubscriptionRepository
+	auditRepo  repository.AuditRepository
+	plans      map[string]domain.SubscriptionPlan
+	imagePlans map[string]domain.ImageQuotaPlan
+	invoicer   FeeInvoicer
+	now        func() time.Time
+	logger     *log.Logger
+}
+
+// NewSubscriptionService creates a new subscription service with the default plans
+func NewSubscriptionService(repo repository.SubscriptionRepository, auditRepo repository.AuditRepository, logger *log.Logger) *SubscriptionService {
+	return &SubscriptionService{
+		repo:       repo,
+		auditRepo:  auditRepo,
+		plans:      domain.DefaultSubscriptionPlans,
+		imagePlans: domain.DefaultImageQuotaPlans,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// SetImageQuotaPlans sets the image quota plans that define each plan's storage, so
+// reported limits match those enforced by the ImageService
+func (s *SubscriptionService) SetImageQuotaPlans(plans map[string]domain.ImageQuotaPlan) {
+	if len(plans) > 0 {
+		s.imagePlans = plans
+	}
+}
+
+// SetFeeInvoicer issues an electronic invoice of the monthly price when an agency
+// changes to a paid plan
+func (s *SubscriptionService) SetFeeInvoicer(invoicer FeeInvoicer) {
+	s.invoicer = invoicer
+}
+
+// ListPlans returns the available plans from smallest to largest
+func (s *SubscriptionService) ListPlans() []domain.SubscriptionPlan {
+	plans := make([]domain.SubscriptionPlan, 0, len(s.plans))
+	for _, plan := range s.plans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Rank < plans[j].Rank })
+	return plans
+}
+
+// GetAgencySubscription returns the plan of an agency with its limits and usage
+func (s *SubscriptionService) GetAgencySubscription(agencyID string) (*domain.AgencySubscription, error) {
+	name, changedAt, err := s.repo.GetAgencyPlan(agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.repo.GetAgencyUsage(agencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := s.plan(name)
+	return &domain.AgencySubscription{
+		AgencyID:        agencyID,
+		Plan:            plan,
+		MaxStorageBytes: s.imagePlans[plan.ImageQuotaPlan].MaxStorageBytes,
+		Usage:           *usage,
+		ChangedAt:       changedAt,
+	}, nil
+}
+
+// ChangePlan upgrades or downgrades the plan of an agency. A downgrade is refused while
+// the agency uses more than the smaller plan allows.
+func (s *SubscriptionService) ChangePlan(agencyID, planName string, actor domain.Actor) (*domain.AgencySubscription, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can change the plan")
+	}
+
+	target, ok := s.plans[strings.ToLower(strings.TrimSpace(planName))]
+	if !ok {
+		return nil, fmt.Errorf("invalid plan: %s", planName)
+	}
+
+	current, err := s.GetAgencySubscription(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Plan.Name == target.Name {
+		return current, nil
+	}
+
+	maxStorage := s.imagePlans[target.ImageQuotaPlan].MaxStorageBytes
+	if !target.IsUpgradeFrom(current.Plan) {
+		if err := current.Usage.CheckDowngrade(target, maxStorage); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	if err := s.repo.SetAgencyPlan(agencyID, target.Name, target.ImageQuotaPlan, now); err != nil {
+		return nil, err
+	}
+
+	entry := domain.NewAuditEntry(actor, domain.AuditActionPlanChanged, domain.AuditEntityAgency, agencyID, &agencyID,
+		map[string]interface{}{"from_plan": current.Plan.Name, "to_plan": target.Name})
+	if err := s.auditRepo.Create(entry); err != nil {
+		s.logger.Printf("Error recording plan change of agency %s: %v", agencyID, err)
+	}
+
+	s.logger.Printf("Agency %s changed plan from %s to %s", agencyID, current.Plan.Name, target.Name)
+
+	if s.invoicer != nil && target.MonthlyPrice > 0 {
+		line := domain.InvoiceLine{
+			Code:        "PLAN-" + strings.ToUpper(target.Name),
+			Description: fmt.Sprintf("Suscripción mensual plan %s", target.Name),
+			Quantity:    1,
+			UnitPrice:   target.MonthlyPrice,
+		}
+		if _, err := s.invoicer.InvoiceFee(agencyID, domain.InvoiceSourceSubscription, target.Name, []domain.InvoiceLine{line}); err != nil {
+			s.logger.Printf("Error invoicing plan change of agency %s: %v", agencyID, err)
+		}
+	}
+
+	current.Plan = target
+	current.MaxStorageBytes = maxStorage
+	current.ChangedAt = &now
+	return current, nil
+}
+
+// CheckListingLimit verifies the agency's plan allows one more active listing
+func (s *SubscriptionService) CheckListingLimit(agencyID string) error {
+	subscription, err := s.GetAgencySubscription(agencyID)
+	if err != nil {
+		return err
+	}
+	if !subscription.Plan.AllowsListings(subscription.Usage.ActiveListings + 1) {
+		return fmt.Errorf("plan limit reached: the %s plan allows %d active listings", subscription.Plan.Name, subscription.Plan.MaxActiveListings)
+	}
+	return nil
+}
+
+// CheckFeaturedLimit verifies the agency's plan allows one more featured listing
+func (s *SubscriptionService) CheckFeaturedLimit(agencyID string) error {
+	subscription, err := s.GetAgencySubscription(agencyID)
+	if err != nil {
+		return err
+	}
+	if !subscription.Plan.AllowsFeatured(subscription.Usage.FeaturedListings + 1) {
+		return fmt.Errorf("plan limit reached: the %s plan allows %d featured listings", subscription.Plan.Name, subscription.Plan.MaxFeatured)
+	}
+	return nil
+}
+
+// plan resolves a stored plan name, falling back to the default plan
+func (s *SubscriptionService) plan(name string) domain.SubscriptionPlan {
+	if plan, ok := s.plans[name]; ok {
+		return plan
+	}
+	return s.plans[domain.DefaultSubscriptionPlan]
+}
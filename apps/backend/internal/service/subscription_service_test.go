@@ -0,0 +1,109 @@
+package service
+
+import (
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// fakeSubscriptionRepository keeps one agency's plan and usage in memory
+type fakeSubscriptionRepository struct {
+	plan           string
+	imageQuotaPlan string
+	usage          domain.PlanUsage
+}
+
+func (r *fakeSubscriptionRepository) GetAgencyPlan(agencyID string) (string, *time.Time, error) {
+	return r.plan, nil, nil
+}
+
+func (r *fakeSubscriptionRepository) SetAgencyPlan(agencyID, plan, imageQuotaPlan string, changedAt time.Time) error {
+	r.plan = plan
+	r.imageQuotaPlan = imageQuotaPlan
+	return nil
+}
+
+func (r *fakeSubscriptionRepository) GetAgencyUsage(agencyID string) (*domain.PlanUsage, error) {
+	usage := r.usage
+	return &usage, nil
+}
+
+// fakeAuditRepository records created entries
+type fakeAuditRepository struct {
+	entries []domain.AuditEntry
+}
+
+func (r *fakeAuditRepository) Create(entry *domain.AuditEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *fakeAuditRepository) ListByEntity(entityType, entityID string, limit int) ([]domain.AuditEntry, error) {
+	return r.entries, nil
+}
+
+func (r *fakeAuditRepository) ListByAgency(agencyID string, limit, offset int) ([]domain.AuditEntry, int, error) {
+	return r.entries, len(r.entries), nil
+}
+
+func TestSubscriptionService_ChangePlan(t *testing.T) {
+	repo := &fakeSubscriptionRepository{plan: domain.PlanFree, usage: domain.PlanUsage{ActiveListings: 40, FeaturedListings: 3}}
+	audit := &fakeAuditRepository{}
+	service := NewSubscriptionService(repo, audit, log.New(os.Stdout, "", 0))
+	owner := domain.NewActor("user-1", "agency", "agency-1")
+
+	_, err := service.ChangePlan("agency-1", domain.PlanPro, domain.NewActor("agent-1", "agent", "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.ChangePlan("agency-1", "gold", owner)
+	assert.ErrorContains(t, err, "invalid plan")
+
+	subscription, err := service.ChangePlan("agency-1", "PRO", owner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PlanPro, subscription.Plan.Name)
+	assert.Equal(t, "professional", repo.imageQuotaPlan)
+	assert.Equal(t, domain.DefaultImageQuotaPlans["professional"].MaxStorageBytes, subscription.MaxStorageBytes)
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, domain.AuditActionPlanChanged, audit.entries[0].Action)
+
+	_, err = service.ChangePlan("agency-1", domain.PlanFree, owner)
+	assert.ErrorContains(t, err, "cannot downgrade: 40 active listings")
+	assert.Equal(t, domain.PlanPro, repo.plan)
+}
+
+func TestSubscriptionService_ListingLimits(t *testing.T) {
+	repo := &fakeSubscriptionRepository{plan: domain.PlanFree, usage: domain.PlanUsage{ActiveListings: 9}}
+	service := NewSubscriptionService(repo, &fakeAuditRepository{}, log.New(os.Stdout, "", 0))
+
+	assert.NoError(t, service.CheckListingLimit("agency-1"))
+	assert.ErrorContains(t, service.CheckFeaturedLimit("agency-1"), "plan limit reached")
+
+	repo.usage.ActiveListings = 10
+	assert.ErrorContains(t, service.CheckListingLimit("agency-1"), "allows 10 active listings")
+
+	repo.plan = domain.PlanPremium
+	repo.usage.ActiveListings = 5000
+	assert.NoError(t, service.CheckListingLimit("agency-1"))
+
+	t.Run("property service enforces featured slots", func(t *testing.T) {
+		repo.plan = domain.PlanFree
+		property := createTestProperty()
+		property.SetAgency("agency-1")
+
+		propertyRepo := &MockPropertyRepository{}
+		propertyRepo.On("GetByID", "test-id").Return(property, nil)
+
+		propertyService := NewPropertyService(propertyRepo, newEmptyImageRepository())
+		propertyService.SetListingLimiter(service)
+
+		err := propertyService.SetPropertyFeatured("test-id", true)
+		assert.ErrorContains(t, err, "plan limit reached")
+		assert.False(t, property.Featured)
+	})
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// TenantConfig configures multi-tenancy
+type TenantConfig struct {
+	Enabled         bool
+	DefaultTenantID string        // serves hosts no tenant claims; empty rejects them
+	RefreshInterval time.Duration // how often tenants changed by other instances are loaded
+}
+
+// CreateTenantRequest holds the fields of a new tenant
+type CreateTenantRequest struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Domains  []string               `json:"domains"`
+	Branding *domain.TenantBranding `json:"branding,omitempty"`
+	Settings map[string]string      `json:"settings,omitempty"`
+}
+
+// UpdateTenantRequest holds the tenant fields to change; nil fields are kept
+type UpdateTenantRequest struct {
+	Name     *string                `json:"name,omitempty"`
+	Domains  []string               `json:"domains,omitempty"`
+	Branding *domain.TenantBranding `json:"branding,omitempty"`
+	Settings map[string]string      `json:"settings,omitempty"`
+	Active   *bool                  `json:"active,omitempty"`
+}
+
+// TenantService resolves the tenant serving each request and lets administrators
+// manage tenants. Tenants are cached in memory and reloaded on the refresh interval,
+// so resolution does not query the database per request.
+type TenantService struct {
+	repo   repository.TenantRepository
+	config TenantConfig
+	logger *log.Logger
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	byID     map[string]*domain.Tenant
+	byHost   map[string]*domain.Tenant
+	loadedAt time.Time
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(repo repository.TenantRepository, config TenantConfig, logger *log.Logger) *TenantService {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Minute
+	}
+
+	return &TenantService{
+		repo:   repo,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+		byID:   map[string]*domain.Tenant{},
+		byHost: map[string]*domain.Tenant{},
+	}
+}
+
+// Enabled verifies if requests are resolved to tenants
+func (s *TenantService) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Load reads every tenant into the resolution cache
+func (s *TenantService) Load() error {
+	tenants, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*domain.Tenant, len(tenants))
+	byHost := make(map[string]*domain.Tenant)
+	for i := range tenants {
+		tenant := &tenants[i]
+		byID[tenant.ID] = tenant
+		for _, host := range tenant.Domains {
+			byHost[host] = tenant
+		}
+	}
+
+	s.mu.Lock()
+	s.byID, s.byHost, s.loadedAt = byID, byHost, s.now()
+	s.mu.Unlock()
+	return nil
+}
+
+// ResolveTenant returns the active tenant with the given ID or, when id is empty, the
+// one serving host. Hosts no tenant claims fall back to the default tenant.
+func (s *TenantService) ResolveTenant(host, id string) (*domain.Tenant, error) {
+	s.refreshIfStale()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tenant *domain.Tenant
+	if id != "" {
+		tenant = s.byID[strings.ToLower(id)]
+	} else if tenant = s.byHost[domain.NormalizeHost(host)]; tenant == nil && s.config.DefaultTenantID != "" {
+		tenant = s.byID[s.config.DefaultTenantID]
+	}
+
+	if tenant == nil || !tenant.Active {
+		if id != "" {
+			return nil, fmt.Errorf("tenant not found: %s", id)
+		}
+		return nil, fmt.Errorf("tenant not found for host: %s", domain.NormalizeHost(host))
+	}
+	copied := *tenant
+	return &copied, nil
+}
+
+// GetTenant returns an active tenant for its public branding and settings
+func (s *TenantService) GetTenant(id string) (*domain.Tenant, error) {
+	return s.ResolveTenant("", id)
+}
+
+// ListTenants lists every tenant. Only administrators can list tenants.
+func (s *TenantService) ListTenants(actor domain.Actor) ([]domain.Tenant, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can list tenants")
+	}
+	return s.repo.List()
+}
+
+// CreateTenant creates a tenant. Only administrators can create tenants.
+func (s *TenantService) CreateTenant(actor domain.Actor, req CreateTenantRequest) (*domain.Tenant, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can create tenants")
+	}
+
+	tenant, err := domain.NewTenant(req.ID, req.Name, req.Domains)
+	if err != nil {
+		return nil, err
+	}
+	if req.Branding != nil {
+		tenant.Branding = *req.Branding
+	}
+	if req.Settings != nil {
+		tenant.Settings = req.Settings
+	}
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkDomainsAvailable(tenant); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(tenant); err != nil {
+		return nil, err
+	}
+	s.reload()
+	s.logger.Printf("Tenant %s created by %s", tenant.ID, actor.UserID)
+	return tenant, nil
+}
+
+// UpdateTenant changes a tenant. Only administrators can update tenants, and the
+// default tenant cannot be deactivated.
+func (s *TenantService) UpdateTenant(actor domain.Actor, id string, req UpdateTenantRequest) (*domain.Tenant, error) {
+	if actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: only administrators can update tenants")
+	}
+
+	tenant, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tenant.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Domains != nil {
+		tenant.Domains = domain.NormalizeTenantDomains(req.Domains)
+	}
+	if req.Branding != nil {
+		tenant.Branding = *req.Branding
+	}
+	if req.Settings != nil {
+		tenant.Settings = req.Settings
+	}
+	if req.Active != nil {
+		if !*req.Active && (tenant.ID == domain.DefaultTenantID || tenant.ID == s.config.DefaultTenantID) {
+			return nil, fmt.Errorf("invalid operation: the default tenant cannot be deactivated")
+		}
+		tenant.Active = *req.Active
+	}
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkDomainsAvailable(tenant); err != nil {
+		return nil, err
+	}
+
+	tenant.UpdatedAt = s.now()
+	if err := s.repo.Update(tenant); err != nil {
+		return nil, err
+	}
+	s.reload()
+	return tenant, nil
+}
+
+// checkDomainsAvailable verifies no other tenant claims the domains of tenant
+func (s *TenantService) checkDomainsAvailable(tenant *domain.Tenant) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, host := range tenant.Domains {
+		if owner, ok := s.byHost[host]; ok && owner.ID != tenant.ID {
+			return fmt.Errorf("invalid tenant domain: %s already belongs to tenant %s", host, owner.ID)
+		}
+	}
+	return nil
+}
+
+// refreshIfStale reloads the cache once the refresh interval passed, serving the
+// cached tenants when the database fails
+func (s *TenantService) refreshIfStale() {
+	s.mu.RLock()
+	stale := s.now().Sub(s.loadedAt) >= s.config.RefreshInterval
+	s.mu.RUnlock()
+
+	if stale {
+		s.reload()
+	}
+}
+
+// reload loads the tenants, logging failures
+func (s *TenantService) reload() {
+	if err := s.Load(); err != nil {
+		s.logger.Printf("Error loading tenants: %v", err)
+		// Retry on the next interval instead of on every request
+		s.mu.Lock()
+		s.loadedAt = s.now()
+		s.mu.Unlock()
+	}
+}
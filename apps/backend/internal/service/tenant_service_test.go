@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryTenantRepository keeps tenants in memory
+type memoryTenantRepository struct {
+	tenants map[string]domain.Tenant
+	lists   int
+}
+
+func newMemoryTenantRepository(tenants ...*domain.Tenant) *memoryTenantRepository {
+	repo := &memoryTenantRepository{tenants: map[string]domain.Tenant{}}
+	for _, tenant := range tenants {
+		repo.tenants[tenant.ID] = *tenant
+	}
+	return repo
+}
+
+func (r *memoryTenantRepository) Create(tenant *domain.Tenant) error {
+	if _, ok := r.tenants[tenant.ID]; ok {
+		return fmt.Errorf("tenant already exists: %s", tenant.ID)
+	}
+	r.tenants[tenant.ID] = *tenant
+	return nil
+}
+
+func (r *memoryTenantRepository) GetByID(id string) (*domain.Tenant, error) {
+	tenant, ok := r.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("tenant not found: %s", id)
+	}
+	return &tenant, nil
+}
+
+func (r *memoryTenantRepository) List() ([]domain.Tenant, error) {
+	r.lists++
+	tenants := []domain.Tenant{}
+	for _, tenant := range r.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants, nil
+}
+
+func (r *memoryTenantRepository) Update(tenant *domain.Tenant) error {
+	if _, ok := r.tenants[tenant.ID]; !ok {
+		return fmt.Errorf("tenant not found: %s", tenant.ID)
+	}
+	r.tenants[tenant.ID] = *tenant
+	return nil
+}
+
+func newTestTenantService(t *testing.T) (*TenantService, *memoryTenantRepository) {
+	defaultTenant, err := domain.NewTenant(domain.DefaultTenantID, "Realty Core", []string{"realty-core.ec"})
+	require.NoError(t, err)
+	redNorte, err := domain.NewTenant("red-norte", "Red Norte", []string{"rednorte.ec"})
+	require.NoError(t, err)
+
+	repo := newMemoryTenantRepository(defaultTenant, redNorte)
+	service := NewTenantService(repo, TenantConfig{Enabled: true, DefaultTenantID: domain.DefaultTenantID}, log.New(io.Discard, "", 0))
+	require.NoError(t, service.Load())
+	return service, repo
+}
+
+func TestTenantService_ResolveTenant(t *testing.T) {
+	service, repo := newTestTenantService(t)
+
+	tenant, err := service.ResolveTenant("RedNorte.ec:8443", "")
+	require.NoError(t, err)
+	assert.Equal(t, "red-norte", tenant.ID)
+
+	tenant, err = service.ResolveTenant("rednorte.ec", "default")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultTenantID, tenant.ID, "the header wins over the host")
+
+	tenant, err = service.ResolveTenant("unknown.example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultTenantID, tenant.ID)
+
+	_, err = service.ResolveTenant("rednorte.ec", "missing")
+	assert.ErrorContains(t, err, "tenant not found")
+	assert.Equal(t, 1, repo.lists, "resolution is served from the cache")
+
+	service.config.DefaultTenantID = ""
+	_, err = service.ResolveTenant("unknown.example.com", "")
+	assert.ErrorContains(t, err, "tenant not found for host")
+}
+
+func TestTenantService_CreateAndUpdate(t *testing.T) {
+	service, _ := newTestTenantService(t)
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+
+	_, err := service.CreateTenant(domain.NewActor("agent-1", string(domain.RoleAgent), ""), CreateTenantRequest{ID: "costa", Name: "Costa"})
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = service.CreateTenant(admin, CreateTenantRequest{ID: "costa", Name: "Costa", Domains: []string{"rednorte.ec"}})
+	assert.ErrorContains(t, err, "already belongs to tenant red-norte")
+
+	tenant, err := service.CreateTenant(admin, CreateTenantRequest{
+		ID:       "costa",
+		Name:     "Costa Inmobiliaria",
+		Domains:  []string{"costa.ec"},
+		Branding: &domain.TenantBranding{DisplayName: "Costa", PrimaryColor: "#0f9d58"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "#0f9d58", tenant.Branding.PrimaryColor)
+
+	resolved, err := service.ResolveTenant("costa.ec", "")
+	require.NoError(t, err)
+	assert.Equal(t, "costa", resolved.ID, "new tenants resolve immediately")
+
+	inactive := false
+	_, err = service.UpdateTenant(admin, "costa", UpdateTenantRequest{Active: &inactive})
+	require.NoError(t, err)
+	_, err = service.ResolveTenant("", "costa")
+	assert.ErrorContains(t, err, "tenant not found")
+
+	_, err = service.UpdateTenant(admin, domain.DefaultTenantID, UpdateTenantRequest{Active: &inactive})
+	assert.ErrorContains(t, err, "invalid operation")
+}
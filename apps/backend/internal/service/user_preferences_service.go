@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// UserPreferencesUsers is the part of the user repository preferences use
+type UserPreferencesUsers interface {
+	GetByID(id string) (*domain.User, error)
+}
+
+// NotificationPreferences tells notification senders whether a user accepts
+// notifications on a channel
+type NotificationPreferences interface {
+	AllowsNotification(userID, channel string) bool
+}
+
+// UserPreferencesService manages the preference center of users. The notification
+// subsystems ask it whether a user accepts a channel, and searches take the user's
+// locale and default filters from it.
+type UserPreferencesService struct {
+	repo   repository.UserPreferencesRepository
+	users  UserPreferencesUsers
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewUserPreferencesService creates a user preferences service
+func NewUserPreferencesService(repo repository.UserPreferencesRepository, users UserPreferencesUsers, logger *log.Logger) *UserPreferencesService {
+	return &UserPreferencesService{
+		repo:   repo,
+		users:  users,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// GetPreferences returns the preferences of a user, or the defaults taken from their
+// profile when they never saved any. Users read their own preferences and admins any.
+func (s *UserPreferencesService) GetPreferences(userID string, actor domain.Actor) (*domain.UserPreferences, error) {
+	if actor.UserID != userID && actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: users can only access their own preferences")
+	}
+	return s.preferences(userID)
+}
+
+// UpdatePreferences replaces the preferences of a user
+func (s *UserPreferencesService) UpdatePreferences(userID string, prefs *domain.UserPreferences, actor domain.Actor) (*domain.UserPreferences, error) {
+	if actor.UserID != userID && actor.Role != domain.RoleAdmin {
+		return nil, fmt.Errorf("permission denied: users can only change their own preferences")
+	}
+	if prefs == nil {
+		return nil, fmt.Errorf("invalid preferences: preferences cannot be nil")
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	prefs.UserID = user.ID
+	prefs.Normalize()
+	if err := prefs.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid preferences: %w", err)
+	}
+	if prefs.Channels.SMS && (user.Phone == nil || *user.Phone == "") {
+		return nil, fmt.Errorf("invalid preferences: add a phone number to receive SMS notifications")
+	}
+
+	prefs.UpdatedAt = s.now()
+	if err := s.repo.Upsert(prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// AllowsNotification reports whether a user accepts notifications on a channel. When the
+// preferences cannot be loaded the notification is allowed, so a failing lookup does
+// not silence notices users rely on.
+func (s *UserPreferencesService) AllowsNotification(userID, channel string) bool {
+	prefs, err := s.preferences(userID)
+	if err != nil {
+		s.logger.Printf("Warning: failed to load preferences of user %s: %v", userID, err)
+		return true
+	}
+	return prefs.AllowsChannel(channel)
+}
+
+// ApplySearchDefaults fills the filters a user's search left unset with their defaults
+func (s *UserPreferencesService) ApplySearchDefaults(userID string, filters *domain.PropertySearchFilters) {
+	prefs, err := s.preferences(userID)
+	if err != nil {
+		s.logger.Printf("Warning: failed to load preferences of user %s: %v", userID, err)
+		return
+	}
+	prefs.ApplySearchDefaults(filters)
+}
+
+// PreferredLocale returns the locale a user saved, or "" when they never saved one so
+// the request's Accept-Language applies
+func (s *UserPreferencesService) PreferredLocale(userID string) string {
+	prefs, err := s.repo.GetByUser(userID)
+	if err != nil {
+		return ""
+	}
+	return prefs.Locale
+}
+
+// preferences loads the saved preferences of a user, falling back to the defaults of
+// their profile
+func (s *UserPreferencesService) preferences(userID string) (*domain.UserPreferences, error) {
+	prefs, err := s.repo.GetByUser(userID)
+	if err == nil {
+		return prefs, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	return domain.DefaultUserPreferences(user), nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryUserPreferences keeps saved preferences in memory by user
+type memoryUserPreferences struct {
+	prefs map[string]*domain.UserPreferences
+}
+
+func (m *memoryUserPreferences) GetByUser(userID string) (*domain.UserPreferences, error) {
+	if prefs, ok := m.prefs[userID]; ok {
+		copied := *prefs
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("user preferences not found: %s", userID)
+}
+
+func (m *memoryUserPreferences) Upsert(prefs *domain.UserPreferences) error {
+	copied := *prefs
+	m.prefs[prefs.UserID] = &copied
+	return nil
+}
+
+func newTestUserPreferencesService() (*UserPreferencesService, *memoryUserPreferences) {
+	minBudget := 50000.0
+	repo := &memoryUserPreferences{prefs: map[string]*domain.UserPreferences{}}
+	users := &memoryEmailChangeUsers{users: map[string]*domain.User{
+		"user-1": {ID: "user-1", Role: domain.RoleBuyer, ReceiveNotifications: true, PreferredProvinces: []string{"Guayas"}, MinBudget: &minBudget},
+		"user-2": {ID: "user-2", Role: domain.RoleAgent},
+	}}
+	var logs bytes.Buffer
+	svc := NewUserPreferencesService(repo, users, log.New(&logs, "", 0))
+	svc.now = func() time.Time { return time.Date(2025, 8, 28, 10, 0, 0, 0, time.UTC) }
+	return svc, repo
+}
+
+func TestUserPreferencesService_Preferences(t *testing.T) {
+	svc, repo := newTestUserPreferencesService()
+	owner := domain.NewActor("user-1", string(domain.RoleBuyer), "")
+
+	// Without saved preferences the profile's fields are the defaults
+	prefs, err := svc.GetPreferences("user-1", owner)
+	require.NoError(t, err)
+	assert.True(t, prefs.Channels.Email)
+	assert.Equal(t, domain.EmailFrequencyInstant, prefs.EmailFrequency)
+	assert.Equal(t, []string{"Guayas"}, prefs.SearchDefaults.Provinces)
+	assert.Equal(t, "", svc.PreferredLocale("user-1"))
+
+	_, err = svc.GetPreferences("user-1", domain.NewActor("user-2", string(domain.RoleAgent), ""))
+	assert.ErrorContains(t, err, "permission denied")
+
+	maxPrice := 150000.0
+	updated, err := svc.UpdatePreferences("user-1", &domain.UserPreferences{
+		Channels:       domain.NotificationChannels{Email: true, Push: true},
+		EmailFrequency: "Weekly",
+		Locale:         "en-US",
+		SearchDefaults: domain.SearchDefaults{Provinces: []string{"Pichincha"}, PropertyTypes: []string{"Apartment"}, MaxPrice: &maxPrice},
+	}, owner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EmailFrequencyWeekly, updated.EmailFrequency)
+	assert.Equal(t, domain.LocaleEnglish, svc.PreferredLocale("user-1"))
+	assert.Equal(t, []string{"apartment"}, repo.prefs["user-1"].SearchDefaults.PropertyTypes)
+
+	_, err = svc.UpdatePreferences("user-1", &domain.UserPreferences{Channels: domain.NotificationChannels{SMS: true}}, owner)
+	assert.ErrorContains(t, err, "phone number")
+
+	_, err = svc.UpdatePreferences("user-1", &domain.UserPreferences{EmailFrequency: "hourly"}, owner)
+	assert.ErrorContains(t, err, "invalid preferences")
+}
+
+func TestUserPreferencesService_Consumers(t *testing.T) {
+	svc, repo := newTestUserPreferencesService()
+
+	filters := domain.NewPropertySearchFilters()
+	svc.ApplySearchDefaults("user-1", filters)
+	assert.Equal(t, []string{"Guayas"}, filters.Provinces)
+	require.NotNil(t, filters.MinPrice)
+	assert.Equal(t, 50000.0, *filters.MinPrice)
+
+	// A search that sets a filter keeps it
+	filters = domain.NewPropertySearchFilters()
+	filters.Provinces = []string{"Azuay"}
+	svc.ApplySearchDefaults("user-1", filters)
+	assert.Equal(t, []string{"Azuay"}, filters.Provinces)
+
+	assert.True(t, svc.AllowsNotification("user-1", domain.ChannelEmail))
+	assert.False(t, svc.AllowsNotification("user-2", domain.ChannelEmail))
+	assert.True(t, svc.AllowsNotification("missing", domain.ChannelEmail))
+
+	repo.prefs["user-1"] = &domain.UserPreferences{UserID: "user-1", Channels: domain.NotificationChannels{Email: true}, EmailFrequency: domain.EmailFrequencyNever}
+	assert.False(t, svc.AllowsNotification("user-1", domain.ChannelEmail))
+
+	// Expiration notices skip recipients who turned email off
+	ownerID, agentID := "user-1", "user-3"
+	lifecycleRepo := &fakeListingLifecycleRepository{expired: []domain.ExpiredListing{
+		{PropertyID: "prop-1", OwnerID: &ownerID},
+		{PropertyID: "prop-2", OwnerID: &ownerID, AgentID: &agentID},
+	}}
+	notifier := &recordingListingNotifier{}
+	lifecycle := NewListingLifecycleService(lifecycleRepo, nil, nil, notifier, ListingLifecycleConfig{}, svc.logger)
+	lifecycle.SetNotificationPreferences(svc)
+	_, err := lifecycle.ExpireStale()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prop-2"}, notifier.notified)
+}
@@ -7,6 +7,7 @@ import (
 
 	"golang.org/x/crypto/bcrypt"
 	"realty-core/internal/domain"
+	"realty-core/internal/monitoring"
 	"realty-core/internal/repository"
 )
 
@@ -26,6 +27,19 @@ func NewUserService(userRepo *repository.UserRepository, agencyRepo *repository.
 	}
 }
 
+// ForTenant returns the service scoped to a tenant: it finds and changes the users and
+// agencies of the tenant only and creates new users in it. An empty tenant returns the
+// service itself.
+func (s *UserServiceSimple) ForTenant(tenantID string) *UserServiceSimple {
+	if tenantID == "" {
+		return s
+	}
+	scoped := *s
+	scoped.userRepo = s.userRepo.ForTenant(tenantID)
+	scoped.agencyRepo = s.agencyRepo.ForTenant(tenantID)
+	return &scoped
+}
+
 // CreateUser creates a new user with validation
 func (s *UserServiceSimple) CreateUser(firstName, lastName, email, phone, cedula, password string, role domain.UserRole) (*domain.User, error) {
 	// Validate basic data
@@ -143,6 +157,7 @@ func (s *UserServiceSimple) AuthenticateUser(email, password string) (*domain.Us
 
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
+		countLoginFailure()
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
@@ -156,6 +171,7 @@ func (s *UserServiceSimple) AuthenticateUser(email, password string) (*domain.Us
 
 	// Compare password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		countLoginFailure()
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
@@ -169,6 +185,13 @@ func (s *UserServiceSimple) AuthenticateUser(email, password string) (*domain.Us
 	return user, nil
 }
 
+// countLoginFailure counts a login with wrong credentials for the failed logins alert
+func countLoginFailure() {
+	if metrics := monitoring.GetGlobalMetrics(); metrics != nil {
+		metrics.GetOrCreateCounter(monitoring.MetricLoginFailures, "Logins rejected for invalid credentials").Inc()
+	}
+}
+
 // ChangePassword changes user password
 func (s *UserServiceSimple) ChangePassword(userID, oldPassword, newPassword string) error {
 	if userID == "" || oldPassword == "" || newPassword == "" {
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// ValuationService suggests listing prices from comparable sales and listings, so
+// agents see the market range while creating a listing
+type ValuationService struct {
+	repo   repository.ValuationRepository
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewValuationService creates a valuation service
+func NewValuationService(repo repository.ValuationRepository, logger *log.Logger) *ValuationService {
+	return &ValuationService{
+		repo:   repo,
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+// SuggestPrice suggests a price range for a listing. Comparables are searched in the
+// listing's sector, then its city and finally its province until enough are found.
+func (s *ValuationService) SuggestPrice(request *domain.PriceSuggestionRequest, actor domain.Actor) (*domain.PriceSuggestion, error) {
+	if actor.UserID == "" {
+		return nil, fmt.Errorf("permission denied: sign in to get price suggestions")
+	}
+
+	request.Normalize()
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	scopes := []string{domain.ComparableScopeCity, domain.ComparableScopeProvince}
+	if request.Sector != "" {
+		scopes = append([]string{domain.ComparableScopeSector}, scopes...)
+	}
+
+	now := s.now()
+	found := 0
+	for _, scope := range scopes {
+		comparables, err := s.repo.FindComparables(request.CriteriaFor(scope, now))
+		if err != nil {
+			return nil, err
+		}
+		found = len(comparables)
+		if found >= domain.MinComparables {
+			return domain.NewPriceSuggestion(request, scope, comparables)
+		}
+	}
+
+	s.logger.Printf("No price suggestion for %s in %s, %s: %d comparables", request.Type, request.City, request.Province, found)
+	return nil, fmt.Errorf("not enough comparables: found %d in %s, need %d", found, request.Province, domain.MinComparables)
+}
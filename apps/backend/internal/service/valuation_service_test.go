@@ -0,0 +1,55 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// scopedComparables returns a fixed number of comparables per city and sector
+type scopedComparables struct {
+	counts   map[string]int
+	searched []domain.ComparableCriteria
+}
+
+func (r *scopedComparables) FindComparables(criteria domain.ComparableCriteria) ([]domain.Comparable, error) {
+	r.searched = append(r.searched, criteria)
+	comparables := []domain.Comparable{}
+	for i := 0; i < r.counts[criteria.City+"/"+criteria.Sector]; i++ {
+		comparables = append(comparables, domain.Comparable{PricePerM2: 1500})
+	}
+	return comparables, nil
+}
+
+func TestValuationService_SuggestPrice(t *testing.T) {
+	repo := &scopedComparables{counts: map[string]int{"Quito/Cumbayá": 2, "Quito/": 6}}
+	var logs bytes.Buffer
+	svc := NewValuationService(repo, log.New(&logs, "", 0))
+	agent := domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1")
+
+	// Too few comparables in the sector widen the search to the city
+	suggestion, err := svc.SuggestPrice(&domain.PriceSuggestionRequest{
+		Province: "Pichincha", City: "Quito", Sector: "Cumbayá", Type: "House", AreaM2: 200,
+	}, agent)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ComparableScopeCity, suggestion.Scope)
+	assert.Equal(t, 300000.0, suggestion.SuggestedPrice)
+	require.Len(t, repo.searched, 2)
+	assert.Equal(t, domain.TypeHouse, repo.searched[0].Type)
+	assert.InDelta(t, 140, repo.searched[0].MinArea, 0.001)
+
+	_, err = svc.SuggestPrice(&domain.PriceSuggestionRequest{
+		Province: "Azuay", City: "Cuenca", Type: domain.TypeHouse, AreaM2: 200,
+	}, agent)
+	assert.ErrorContains(t, err, "not enough comparables")
+
+	_, err = svc.SuggestPrice(&domain.PriceSuggestionRequest{
+		Province: "Pichincha", City: "Quito", Type: domain.TypeHouse, AreaM2: 200,
+	}, domain.Actor{})
+	assert.ErrorContains(t, err, "permission denied")
+}
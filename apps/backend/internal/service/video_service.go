@@ -0,0 +1,280 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/repository"
+	"realty-core/internal/storage"
+)
+
+// VideoConfig configures video uploads and transcoding
+type VideoConfig struct {
+	MaxFileSize    int64
+	MaxPerProperty int
+	TempDir        string
+	Workers        int
+	QueueSize      int
+	Renditions     []domain.VideoRenditionPreset
+}
+
+// videoJob is a queued transcoding job
+type videoJob struct {
+	video     *domain.PropertyVideo
+	inputPath string
+}
+
+// VideoService handles property video uploads and background transcoding.
+// Transcoded files are written through the same storage abstraction as images.
+type VideoService struct {
+	repo         repository.VideoRepository
+	propertyRepo repository.PropertyRepository
+	storage      storage.ImageStorage
+	transcoder   processors.VideoTranscoder
+	config       VideoConfig
+	jobs         chan videoJob
+	wg           sync.WaitGroup
+	logger       *log.Logger
+}
+
+// NewVideoService creates a new video service. Call Start to begin processing uploads.
+func NewVideoService(repo repository.VideoRepository, propertyRepo repository.PropertyRepository, storage storage.ImageStorage, transcoder processors.VideoTranscoder, config VideoConfig, logger *log.Logger) *VideoService {
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = domain.MaxVideoUploadSize
+	}
+	if config.MaxPerProperty <= 0 {
+		config.MaxPerProperty = domain.MaxVideosPerProperty
+	}
+	if config.TempDir == "" {
+		config.TempDir = os.TempDir()
+	}
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 50
+	}
+	if len(config.Renditions) == 0 {
+		config.Renditions = domain.DefaultVideoRenditions
+	}
+
+	return &VideoService{
+		repo:         repo,
+		propertyRepo: propertyRepo,
+		storage:      storage,
+		transcoder:   transcoder,
+		config:       config,
+		jobs:         make(chan videoJob, config.QueueSize),
+		logger:       logger,
+	}
+}
+
+// Start launches the transcoding workers
+func (s *VideoService) Start() {
+	for i := 0; i < s.config.Workers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for job := range s.jobs {
+				s.process(job)
+			}
+		}()
+	}
+	s.logger.Printf("Video transcoding started with %d workers", s.config.Workers)
+}
+
+// Stop waits for queued videos to finish transcoding
+func (s *VideoService) Stop() {
+	close(s.jobs)
+	s.wg.Wait()
+}
+
+// Upload validates a video, spools it to disk and queues it for transcoding
+func (s *VideoService) Upload(propertyID, fileName, contentType string, size int64, content io.Reader) (*domain.PropertyVideo, error) {
+	if _, err := s.propertyRepo.GetByID(propertyID); err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	if err := domain.ValidateVideoUpload(fileName, contentType, size, s.config.MaxFileSize); err != nil {
+		return nil, fmt.Errorf("upload validation failed: %w", err)
+	}
+
+	count, err := s.repo.CountByProperty(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.config.MaxPerProperty {
+		return nil, fmt.Errorf("maximum videos per property exceeded: %d", s.config.MaxPerProperty)
+	}
+
+	video := domain.NewPropertyVideo(propertyID, fileName, contentType, size)
+	inputPath, err := s.spool(video, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(video); err != nil {
+		os.Remove(inputPath)
+		return nil, err
+	}
+
+	select {
+	case s.jobs <- videoJob{video: video, inputPath: inputPath}:
+	default:
+		os.Remove(inputPath)
+		video.MarkFailed("transcoding queue is full")
+		s.repo.Update(video)
+		return nil, fmt.Errorf("transcoding queue is full, try again later")
+	}
+
+	s.logger.Printf("Video %s queued for transcoding (%s, %d bytes)", video.ID, fileName, size)
+	return video, nil
+}
+
+// GetVideo retrieves a video by ID
+func (s *VideoService) GetVideo(id string) (*domain.PropertyVideo, error) {
+	return s.repo.GetByID(id)
+}
+
+// GetVideosByProperty retrieves all videos of a property
+func (s *VideoService) GetVideosByProperty(propertyID string) ([]domain.PropertyVideo, error) {
+	return s.repo.GetByPropertyID(propertyID)
+}
+
+// GetManifest returns the HLS master playlist of a ready video
+func (s *VideoService) GetManifest(id string) (string, error) {
+	video, err := s.repo.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	if video.Status != domain.VideoStatusReady {
+		return "", fmt.Errorf("video is not ready: %s", video.Status)
+	}
+
+	return processors.BuildMasterPlaylist(video.HLSRenditions()), nil
+}
+
+// DeleteVideo deletes a video and its transcoded files
+func (s *VideoService) DeleteVideo(id string) error {
+	video, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	s.deleteFiles(video.StoredPaths)
+	s.logger.Printf("Video deleted: %s", id)
+	return nil
+}
+
+// spool copies the upload to a temporary file, enforcing the size limit
+func (s *VideoService) spool(video *domain.PropertyVideo, content io.Reader) (string, error) {
+	inputPath := filepath.Join(s.config.TempDir, video.ID+filepath.Ext(video.FileName))
+	file, err := os.Create(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer file.Close()
+
+	written, err := io.Copy(file, io.LimitReader(content, s.config.MaxFileSize+1))
+	if err == nil && written > s.config.MaxFileSize {
+		err = fmt.Errorf("file too large: max %d bytes", s.config.MaxFileSize)
+	}
+	if err != nil {
+		os.Remove(inputPath)
+		return "", fmt.Errorf("failed to save upload: %w", err)
+	}
+
+	return inputPath, nil
+}
+
+// process transcodes a video, stores its renditions and thumbnail and records the result
+func (s *VideoService) process(job videoJob) {
+	defer os.Remove(job.inputPath)
+	video := job.video
+
+	if err := s.transcode(video, job.inputPath); err != nil {
+		s.logger.Printf("Video %s transcoding failed: %v", video.ID, err)
+		s.deleteFiles(video.StoredPaths)
+		video.StoredPaths = []string{}
+		video.MarkFailed(err.Error())
+	} else {
+		s.logger.Printf("Video %s ready with %d renditions", video.ID, len(video.Renditions))
+	}
+
+	if err := s.repo.Update(video); err != nil {
+		s.logger.Printf("Error updating video %s: %v", video.ID, err)
+	}
+}
+
+// transcode runs the transcoder into a work directory and uploads the results
+func (s *VideoService) transcode(video *domain.PropertyVideo, inputPath string) error {
+	probe, err := s.transcoder.Probe(inputPath)
+	if err != nil {
+		return err
+	}
+	if probe.Duration > domain.MaxVideoDuration.Seconds() {
+		return fmt.Errorf("video too long: %.0fs, max: %.0fs", probe.Duration, domain.MaxVideoDuration.Seconds())
+	}
+
+	workDir, err := os.MkdirTemp(s.config.TempDir, "video-"+video.ID+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	output, err := s.transcoder.Transcode(inputPath, workDir, s.config.Renditions, probe)
+	if err != nil {
+		return err
+	}
+
+	thumbnailName := "thumbnail.jpg"
+	if err := s.transcoder.ExtractThumbnail(inputPath, filepath.Join(workDir, thumbnailName), probe.Duration/3); err != nil {
+		return err
+	}
+
+	storedURLs := make(map[string]string, len(output.Files)+1)
+	for _, file := range append(output.Files, thumbnailName) {
+		if _, done := storedURLs[file]; done {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(workDir, filepath.FromSlash(file)))
+		if err != nil {
+			return fmt.Errorf("failed to read transcoded file: %w", err)
+		}
+		storedPath, err := s.storage.Store(data, path.Join(video.StoragePrefix, file))
+		if err != nil {
+			return fmt.Errorf("failed to store %s: %w", file, err)
+		}
+		video.StoredPaths = append(video.StoredPaths, storedPath)
+		storedURLs[file] = s.storage.GetURL(storedPath)
+	}
+
+	renditions := make([]domain.VideoRendition, 0, len(output.Renditions))
+	for _, rendition := range output.Renditions {
+		rendition.URL = storedURLs[rendition.Path]
+		renditions = append(renditions, rendition)
+	}
+
+	video.MarkReady(probe.Duration, renditions, storedURLs[thumbnailName])
+	return nil
+}
+
+// deleteFiles removes stored video files, logging failures
+func (s *VideoService) deleteFiles(paths []string) {
+	for _, storedPath := range paths {
+		if err := s.storage.Delete(storedPath); err != nil {
+			s.logger.Printf("Error deleting video file %s: %v", storedPath, err)
+		}
+	}
+}
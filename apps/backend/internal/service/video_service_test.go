@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/processors"
+	"realty-core/internal/storage"
+)
+
+// memoryVideoRepository is an in-memory VideoRepository
+type memoryVideoRepository struct {
+	mu     sync.Mutex
+	videos map[string]domain.PropertyVideo
+}
+
+func newMemoryVideoRepository() *memoryVideoRepository {
+	return &memoryVideoRepository{videos: map[string]domain.PropertyVideo{}}
+}
+
+func (r *memoryVideoRepository) Create(video *domain.PropertyVideo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.videos[video.ID] = *video
+	return nil
+}
+
+func (r *memoryVideoRepository) GetByID(id string) (*domain.PropertyVideo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	video, ok := r.videos[id]
+	if !ok {
+		return nil, fmt.Errorf("video not found: %s", id)
+	}
+	return &video, nil
+}
+
+func (r *memoryVideoRepository) GetByPropertyID(propertyID string) ([]domain.PropertyVideo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	videos := []domain.PropertyVideo{}
+	for _, video := range r.videos {
+		if video.PropertyID == propertyID {
+			videos = append(videos, video)
+		}
+	}
+	return videos, nil
+}
+
+func (r *memoryVideoRepository) Update(video *domain.PropertyVideo) error {
+	return r.Create(video)
+}
+
+func (r *memoryVideoRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.videos, id)
+	return nil
+}
+
+func (r *memoryVideoRepository) CountByProperty(propertyID string) (int, error) {
+	videos, _ := r.GetByPropertyID(propertyID)
+	return len(videos), nil
+}
+
+// fakeTranscoder writes placeholder outputs instead of running ffmpeg
+type fakeTranscoder struct {
+	probe *processors.VideoProbe
+	err   error
+}
+
+func (t *fakeTranscoder) Probe(inputPath string) (*processors.VideoProbe, error) {
+	return t.probe, nil
+}
+
+func (t *fakeTranscoder) Transcode(inputPath, outputDir string, presets []domain.VideoRenditionPreset, source *processors.VideoProbe) (*processors.TranscodeOutput, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	output := &processors.TranscodeOutput{}
+	for _, preset := range processors.SelectRenditionPresets(presets, source.Height) {
+		for _, file := range []string{preset.Name + ".m3u8", preset.Name + "_000.ts"} {
+			if err := os.WriteFile(filepath.Join(outputDir, file), []byte(file), 0644); err != nil {
+				return nil, err
+			}
+			output.Files = append(output.Files, file)
+		}
+		output.Renditions = append(output.Renditions, domain.VideoRendition{
+			Name: preset.Name, Format: "hls", Height: preset.Height, Path: preset.Name + ".m3u8",
+		})
+	}
+	return output, nil
+}
+
+func (t *fakeTranscoder) ExtractThumbnail(inputPath, outputPath string, at float64) error {
+	return os.WriteFile(outputPath, []byte("jpeg"), 0644)
+}
+
+func newTestVideoService(t *testing.T, transcoder processors.VideoTranscoder) (*VideoService, *memoryVideoRepository, *MockPropertyRepository) {
+	videoStorage, err := storage.NewLocalImageStorage(t.TempDir(), "/uploads/videos", domain.MaxVideoUploadSize)
+	require.NoError(t, err)
+
+	repo := newMemoryVideoRepository()
+	propertyRepo := new(MockPropertyRepository)
+	service := NewVideoService(repo, propertyRepo, videoStorage, transcoder,
+		VideoConfig{TempDir: t.TempDir(), MaxFileSize: 1024}, log.New(os.Stderr, "", 0))
+	return service, repo, propertyRepo
+}
+
+func TestVideoService_UploadAndTranscode(t *testing.T) {
+	service, _, propertyRepo := newTestVideoService(t, &fakeTranscoder{probe: &processors.VideoProbe{Duration: 30, Width: 1280, Height: 720}})
+	propertyRepo.On("GetByID", "prop-1").Return(&domain.Property{ID: "prop-1"}, nil)
+
+	video, err := service.Upload("prop-1", "tour.mp4", "video/mp4", 5, bytes.NewReader([]byte("video")))
+	require.NoError(t, err)
+	assert.Equal(t, domain.VideoStatusProcessing, video.Status)
+
+	service.Start()
+	service.Stop()
+
+	stored, err := service.GetVideo(video.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.VideoStatusReady, stored.Status, stored.Error)
+	assert.Equal(t, 30.0, stored.DurationSeconds)
+	assert.Len(t, stored.Renditions, 2)
+	assert.Len(t, stored.StoredPaths, 5)
+	assert.Contains(t, stored.ThumbnailURL, "thumbnail.jpg")
+
+	manifest, err := service.GetManifest(video.ID)
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "/uploads/videos/originals/videos/prop-1/"+video.ID+"/720p.m3u8")
+
+	require.NoError(t, service.DeleteVideo(video.ID))
+	_, err = service.GetVideo(video.ID)
+	assert.Error(t, err)
+}
+
+func TestVideoService_UploadValidation(t *testing.T) {
+	service, _, propertyRepo := newTestVideoService(t, &fakeTranscoder{})
+	propertyRepo.On("GetByID", "prop-1").Return(&domain.Property{ID: "prop-1"}, nil)
+	propertyRepo.On("GetByID", "missing").Return((*domain.Property)(nil), fmt.Errorf("property not found"))
+
+	_, err := service.Upload("missing", "tour.mp4", "video/mp4", 5, bytes.NewReader([]byte("video")))
+	assert.Contains(t, err.Error(), "property not found")
+
+	_, err = service.Upload("prop-1", "tour.gif", "image/gif", 5, bytes.NewReader([]byte("video")))
+	assert.Contains(t, err.Error(), "validation failed")
+
+	_, err = service.Upload("prop-1", "tour.mp4", "video/mp4", 2048, bytes.NewReader(make([]byte, 2048)))
+	assert.Contains(t, err.Error(), "too large")
+
+	// The declared size cannot be used to bypass the limit
+	_, err = service.Upload("prop-1", "tour.mp4", "video/mp4", 5, bytes.NewReader(make([]byte, 2048)))
+	assert.Contains(t, err.Error(), "too large")
+	propertyRepo.AssertExpectations(t)
+}
+
+func TestVideoService_TranscodeFailure(t *testing.T) {
+	service, _, propertyRepo := newTestVideoService(t, &fakeTranscoder{
+		probe: &processors.VideoProbe{Duration: 30, Width: 1280, Height: 720},
+		err:   fmt.Errorf("ffmpeg: exit status 1"),
+	})
+	propertyRepo.On("GetByID", mock.Anything).Return(&domain.Property{ID: "prop-1"}, nil)
+
+	video, err := service.Upload("prop-1", "tour.mov", "video/quicktime", 5, bytes.NewReader([]byte("video")))
+	require.NoError(t, err)
+
+	service.Start()
+	service.Stop()
+
+	stored, err := service.GetVideo(video.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VideoStatusFailed, stored.Status)
+	assert.Contains(t, stored.Error, "exit status 1")
+
+	_, err = service.GetManifest(video.ID)
+	assert.Contains(t, err.Error(), "not ready")
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"realty-core/internal/domain"
+	"realty-core/internal/repository"
+)
+
+// EventPublisher queues domain events for integrations. agencyID is the agency of the
+// listing the event is about, empty for independent listings.
+type EventPublisher interface {
+	Publish(eventType, agencyID string, data interface{}) error
+}
+
+// EventPublishers publishes each event to several publishers, e.g. webhooks and the
+// event bus, returning the errors of all that failed
+type EventPublishers []EventPublisher
+
+// Publish publishes the event to every publisher
+func (p EventPublishers) Publish(eventType, agencyID string, data interface{}) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(eventType, agencyID, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookConfig configures webhook delivery
+type WebhookConfig struct {
+	Timeout      time.Duration // per delivery attempt
+	PollInterval time.Duration // time between runs delivering due events
+	BatchSize    int           // deliveries attempted per run
+	MaxAttempts  int           // attempts before a delivery is marked failed
+}
+
+// SaveWebhookRequest holds the fields of a new or updated subscription. AgencyID is
+// only read on creation and defaults to the caller's agency.
+type SaveWebhookRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description,omitempty"`
+	AgencyID    string   `json:"agency_id,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// WebhookService manages webhook subscriptions and pushes events to them. Events are
+// queued by Publish or, when the outbox is used, by the outbox dispatcher; a
+// background loop signs and sends them, retrying failures with exponential backoff.
+type WebhookService struct {
+	repo     repository.WebhookRepository
+	client   *http.Client
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+	config   WebhookConfig
+	logger   *log.Logger
+	now      func() time.Time
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repository.WebhookRepository, config WebhookConfig, logger *log.Logger) *WebhookService {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 15 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = domain.DefaultWebhookMaxAttempts
+	}
+
+	return &WebhookService{
+		repo:     repo,
+		client:   newWebhookClient(config.Timeout),
+		lookupIP: net.DefaultResolver.LookupIPAddr,
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// newWebhookClient creates the client deliveries are sent with. It only connects to
+// public addresses, checked after the host is resolved, so agency administrators
+// cannot reach the internal network through a webhook, not even by rebinding the DNS
+// of a host that was public when the subscription was saved.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: refuseInternalAddress}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Receivers answer the request themselves; redirects could point anywhere
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// refuseInternalAddress stops the webhook client from connecting to internal addresses
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || domain.IsInternalIP(ip) {
+		return errors.New("webhook endpoint resolves to an internal address")
+	}
+	return nil
+}
+
+// checkWebhookHost refuses URLs whose host resolves to an internal address. Hosts that
+// cannot be resolved yet are accepted; deliveries check the addresses they connect to.
+func (s *WebhookService) checkWebhookHost(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: must be an absolute https:// URL")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+	addresses, err := s.lookupIP(ctx, parsed.Hostname())
+	if err != nil {
+		return nil
+	}
+	for _, address := range addresses {
+		if domain.IsInternalIP(address.IP) {
+			return fmt.Errorf("invalid webhook URL: internal addresses are not allowed")
+		}
+	}
+	return nil
+}
+
+// CreateSubscription subscribes a URL to events. Agency administrators subscribe
+// their agency; admins can also create subscriptions without an agency, which receive
+// every event. The secret is only returned here.
+func (s *WebhookService) CreateSubscription(req SaveWebhookRequest, actor domain.Actor) (*domain.WebhookSubscription, error) {
+	agencyID := req.AgencyID
+	if agencyID == "" && actor.Role == domain.RoleAgency {
+		agencyID = actor.AgencyID
+		if agencyID == "" {
+			agencyID = actor.UserID
+		}
+	}
+	switch {
+	case agencyID == "" && actor.Role == domain.RoleAdmin:
+	case agencyID != "" && actor.CanAdministerAgency(agencyID):
+	default:
+		return nil, fmt.Errorf("permission denied: only agency administrators can manage webhooks")
+	}
+
+	var agency *string
+	if agencyID != "" {
+		agency = &agencyID
+	}
+	subscription, err := domain.NewWebhookSubscription(req.URL, req.EventTypes, agency, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWebhookHost(subscription.URL); err != nil {
+		return nil, err
+	}
+	subscription.Description = strings.TrimSpace(req.Description)
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	if err := s.repo.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Webhook subscription %s created by %s for %v", subscription.ID, actor.UserID, subscription.EventTypes)
+	return subscription, nil
+}
+
+// ListSubscriptions lists the subscriptions of an agency; admins without an agency
+// filter list every subscription
+func (s *WebhookService) ListSubscriptions(agencyID string, actor domain.Actor) ([]domain.WebhookSubscription, error) {
+	if agencyID == "" && actor.Role != domain.RoleAdmin {
+		agencyID = actor.AgencyID
+		if agencyID == "" {
+			agencyID = actor.UserID
+		}
+	}
+	if agencyID != "" && !actor.CanAdministerAgency(agencyID) {
+		return nil, fmt.Errorf("permission denied: only agency administrators can manage webhooks")
+	}
+
+	return s.repo.ListSubscriptions(agencyID)
+}
+
+// GetSubscription retrieves a subscription managed by the actor
+func (s *WebhookService) GetSubscription(id string, actor domain.Actor) (*domain.WebhookSubscription, error) {
+	subscription, err := s.repo.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageWebhook(subscription, actor) {
+		return nil, fmt.Errorf("permission denied: webhook belongs to another agency")
+	}
+	return subscription, nil
+}
+
+// UpdateSubscription changes the URL, events, description or status of a subscription
+func (s *WebhookService) UpdateSubscription(id string, req SaveWebhookRequest, actor domain.Actor) (*domain.WebhookSubscription, error) {
+	subscription, err := s.GetSubscription(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		subscription.URL = strings.TrimSpace(req.URL)
+	}
+	if req.EventTypes != nil {
+		subscription.EventTypes = domain.NormalizeWebhookEventTypes(req.EventTypes)
+	}
+	if req.Description != "" {
+		subscription.Description = strings.TrimSpace(req.Description)
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+	if req.URL != "" {
+		if err := s.checkWebhookHost(subscription.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription.UpdatedAt = s.now()
+	if err := s.repo.UpdateSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a subscription and its delivery log
+func (s *WebhookService) DeleteSubscription(id string, actor domain.Actor) error {
+	if _, err := s.GetSubscription(id, actor); err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(id)
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first
+func (s *WebhookService) ListDeliveries(id string, limit int, actor domain.Actor) ([]domain.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(id, actor); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.repo.ListDeliveries(id, limit)
+}
+
+// Publish queues an event for every active subscription receiving it. Events
+// webhooks do not carry, such as image.processed, are ignored.
+func (s *WebhookService) Publish(eventType, agencyID string, data interface{}) error {
+	if !domain.IsValidWebhookEventType(eventType) {
+		return nil
+	}
+
+	subscriptions, err := s.repo.ListSubscriptionsForEvent(eventType, agencyID)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	deliveries, err := domain.NewWebhookDeliveries(eventType, data, subscriptions)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateDeliveries(deliveries)
+}
+
+// HandleOutboxEvent queues an outbox event for the subscriptions receiving it. The
+// outbox event ID becomes the webhook event ID, so redispatched events are not
+// delivered twice.
+func (s *WebhookService) HandleOutboxEvent(event *domain.OutboxEvent) error {
+	if !domain.IsValidWebhookEventType(event.EventType) {
+		return nil
+	}
+
+	subscriptions, err := s.repo.ListSubscriptionsForEvent(event.EventType, event.AgencyID)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	webhookEvent := domain.WebhookEvent{ID: event.ID, Type: event.EventType, CreatedAt: event.CreatedAt, Data: event.Payload}
+	deliveries, err := domain.NewWebhookEventDeliveries(webhookEvent, subscriptions)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateDeliveries(deliveries)
+}
+
+// DeliverDue attempts the deliveries that are due and returns how many were delivered
+func (s *WebhookService) DeliverDue() (int, error) {
+	now := s.now()
+	// Lease claimed deliveries for longer than a run can take
+	lease := time.Duration(s.config.BatchSize+1) * s.config.Timeout
+	deliveries, err := s.repo.ClaimDueDeliveries(now, lease, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := map[string]*domain.WebhookSubscription{}
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			if subscription, err = s.repo.GetSubscription(delivery.SubscriptionID); err != nil {
+				s.logger.Printf("Error loading webhook subscription %s: %v", delivery.SubscriptionID, err)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if !subscription.Active {
+			delivery.RecordAttempt(0, fmt.Errorf("subscription is inactive"), s.now(), delivery.Attempts+1)
+		} else {
+			statusCode, err := s.send(subscription, delivery)
+			delivery.RecordAttempt(statusCode, err, s.now(), s.config.MaxAttempts)
+		}
+
+		if err := s.repo.UpdateDelivery(delivery); err != nil {
+			s.logger.Printf("Error saving webhook delivery %s: %v", delivery.ID, err)
+			continue
+		}
+		switch delivery.Status {
+		case domain.WebhookDeliveryDelivered:
+			delivered++
+		case domain.WebhookDeliveryFailed:
+			s.logger.Printf("Webhook delivery %s to %s failed after %d attempts: %s",
+				delivery.ID, subscription.URL, delivery.Attempts, delivery.LastError)
+		}
+	}
+
+	return delivered, nil
+}
+
+// Start delivers due events on the poll interval until Stop is called
+func (s *WebhookService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.DeliverDue(); err != nil {
+					s.logger.Printf("Error delivering webhooks: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops delivering events
+func (s *WebhookService) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// send posts a signed delivery and returns the response status code
+func (s *WebhookService) send(subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "realty-core-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set(domain.WebhookSignatureHeader, domain.SignWebhookPayload(subscription.Secret, s.now(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error contacting webhook endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
+
+// canManageWebhook verifies if the actor administers the subscription's agency;
+// subscriptions without an agency belong to admins
+func canManageWebhook(subscription *domain.WebhookSubscription, actor domain.Actor) bool {
+	if subscription.AgencyID == nil {
+		return actor.Role == domain.RoleAdmin
+	}
+	return actor.CanAdministerAgency(*subscription.AgencyID)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"realty-core/internal/domain"
+)
+
+// memoryWebhookRepository keeps subscriptions and deliveries in memory
+type memoryWebhookRepository struct {
+	subscriptions []domain.WebhookSubscription
+	deliveries    []domain.WebhookDelivery
+}
+
+func (r *memoryWebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	r.subscriptions = append(r.subscriptions, *subscription)
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetSubscription(id string) (*domain.WebhookSubscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == id {
+			return &subscription, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook subscription not found: %s", id)
+}
+
+func (r *memoryWebhookRepository) ListSubscriptions(agencyID string) ([]domain.WebhookSubscription, error) {
+	subscriptions := []domain.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if agencyID == "" || (subscription.AgencyID != nil && *subscription.AgencyID == agencyID) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *memoryWebhookRepository) ListSubscriptionsForEvent(eventType, agencyID string) ([]domain.WebhookSubscription, error) {
+	subscriptions := []domain.WebhookSubscription{}
+	for _, subscription := range r.subscriptions {
+		if subscription.Active && subscription.Receives(eventType) &&
+			(subscription.AgencyID == nil || *subscription.AgencyID == agencyID) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *memoryWebhookRepository) UpdateSubscription(subscription *domain.WebhookSubscription) error {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == subscription.ID {
+			r.subscriptions[i] = *subscription
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook subscription not found: %s", subscription.ID)
+}
+
+func (r *memoryWebhookRepository) DeleteSubscription(id string) error {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == id {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook subscription not found: %s", id)
+}
+
+func (r *memoryWebhookRepository) CreateDeliveries(deliveries []domain.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		queued := false
+		for _, existing := range r.deliveries {
+			queued = queued || (existing.SubscriptionID == delivery.SubscriptionID && existing.EventID == delivery.EventID)
+		}
+		if !queued {
+			r.deliveries = append(r.deliveries, delivery)
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	due := []domain.WebhookDelivery{}
+	for i := range r.deliveries {
+		delivery := &r.deliveries[i]
+		if delivery.Status == domain.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			next := now.Add(lease)
+			delivery.NextAttemptAt = &next
+			due = append(due, *delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(delivery *domain.WebhookDelivery) error {
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			r.deliveries[i] = *delivery
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepository) ListDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	deliveries := []domain.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func TestWebhookService_Subscriptions(t *testing.T) {
+	repo := &memoryWebhookRepository{}
+	service := NewWebhookService(repo, WebhookConfig{}, log.New(io.Discard, "", 0))
+	agency := domain.NewActor("agency-user", string(domain.RoleAgency), "agency-1")
+
+	_, err := service.CreateSubscription(SaveWebhookRequest{URL: "https://crm.example.com", EventTypes: []string{domain.EventPropertyCreated}},
+		domain.NewActor("agent-1", string(domain.RoleAgent), "agency-1"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	subscription, err := service.CreateSubscription(SaveWebhookRequest{URL: "https://crm.example.com", EventTypes: []string{domain.EventPropertyCreated}}, agency)
+	require.NoError(t, err)
+	assert.Equal(t, "agency-1", *subscription.AgencyID, "agency accounts subscribe their own agency")
+
+	_, err = service.GetSubscription(subscription.ID, domain.NewActor("other", string(domain.RoleAgency), "agency-2"))
+	assert.ErrorContains(t, err, "permission denied")
+
+	inactive := false
+	updated, err := service.UpdateSubscription(subscription.ID, SaveWebhookRequest{Active: &inactive}, agency)
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+
+	_, err = service.UpdateSubscription(subscription.ID, SaveWebhookRequest{EventTypes: []string{"property.deleted"}}, agency)
+	assert.ErrorContains(t, err, "invalid event type")
+
+	subscriptions, err := service.ListSubscriptions("", agency)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+}
+
+// routedClient returns a client of a TLS test server that connects to the server
+// whatever host is requested; its certificate is valid for example.com
+func routedClient(server *httptest.Server) *http.Client {
+	client := server.Client()
+	transport := client.Transport.(*http.Transport)
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	return client
+}
+
+// publicLookup resolves every host to a public address
+func publicLookup(context.Context, string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP("93.184.215.14")}}, nil
+}
+
+func TestWebhookService_RefusesInternalEndpoints(t *testing.T) {
+	var received int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{}
+	service := NewWebhookService(repo, WebhookConfig{MaxAttempts: 1}, log.New(io.Discard, "", 0))
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+
+	_, err := service.CreateSubscription(SaveWebhookRequest{URL: server.URL, EventTypes: []string{domain.EventPropertySold}}, admin)
+	assert.ErrorContains(t, err, "internal addresses")
+
+	service.lookupIP = func(context.Context, string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("169.254.169.254")}}, nil
+	}
+	_, err = service.CreateSubscription(SaveWebhookRequest{URL: "https://metadata.example.com", EventTypes: []string{domain.EventPropertySold}}, admin)
+	assert.ErrorContains(t, err, "internal addresses", "hosts resolving to internal addresses are refused when saved")
+
+	// A host rebound to an internal address after it was saved is refused on delivery
+	service.lookupIP = publicLookup
+	subscription, err := service.CreateSubscription(SaveWebhookRequest{URL: "https://example.com/hooks", EventTypes: []string{domain.EventPropertySold}}, admin)
+	require.NoError(t, err)
+	subscription.URL = server.URL
+	require.NoError(t, repo.UpdateSubscription(subscription))
+
+	require.NoError(t, service.Publish(domain.EventPropertySold, "", map[string]string{"property_id": "p-1"}))
+	delivered, err := service.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 0, received)
+	require.Len(t, repo.deliveries, 1)
+	assert.Contains(t, repo.deliveries[0].LastError, "internal address")
+}
+
+func TestWebhookService_PublishAndDeliver(t *testing.T) {
+	var received []*http.Request
+	status := http.StatusInternalServerError
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	repo := &memoryWebhookRepository{}
+	service := NewWebhookService(repo, WebhookConfig{MaxAttempts: 3}, log.New(io.Discard, "", 0))
+	service.client = routedClient(server)
+	service.lookupIP = publicLookup
+	now := time.Now()
+	service.now = func() time.Time { return now }
+	admin := domain.NewActor("admin-1", string(domain.RoleAdmin), "")
+
+	global, err := service.CreateSubscription(SaveWebhookRequest{URL: "https://example.com/hooks", EventTypes: []string{domain.EventPropertySold}}, admin)
+	require.NoError(t, err)
+	_, err = service.CreateSubscription(SaveWebhookRequest{URL: "https://example.com/hooks", EventTypes: []string{domain.EventPropertySold}, AgencyID: "agency-2"}, admin)
+	require.NoError(t, err)
+
+	require.NoError(t, service.Publish(domain.EventPropertySold, "agency-1", map[string]string{"property_id": "p-1"}))
+	require.Len(t, repo.deliveries, 1, "other agencies' subscriptions do not receive the event")
+	assert.NoError(t, service.Publish(domain.EventImageProcessed, "agency-1", nil), "events webhooks do not carry are ignored")
+	require.Len(t, repo.deliveries, 1)
+
+	now = time.Now()
+
+	delivered, err := service.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	require.Len(t, received, 1)
+	assert.Equal(t, domain.EventPropertySold, received[0].Header.Get("X-Webhook-Event"))
+	assert.Equal(t, domain.SignWebhookPayload(global.Secret, now, repo.deliveries[0].Payload), received[0].Header.Get(domain.WebhookSignatureHeader))
+	assert.Equal(t, 1, repo.deliveries[0].Attempts)
+
+	// Not due until the backoff passes
+	_, err = service.DeliverDue()
+	require.NoError(t, err)
+	assert.Len(t, received, 1)
+
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	delivered, err = service.DeliverDue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	deliveries, err := service.ListDeliveries(global.ID, 0, admin)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, domain.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+}
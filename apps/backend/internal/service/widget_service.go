@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+// WidgetConfig configures the listing widgets agencies embed in their own sites
+type WidgetConfig struct {
+	SigningSecret string
+	TokenTTL      time.Duration
+}
+
+// WidgetService issues embed tokens to agencies and serves the listings of their widgets.
+// Widgets are read with a token instead of a session, so an agency's site can load them
+// from the browsers of its visitors; a token only opens the listings of the agency it was
+// issued to.
+type WidgetService struct {
+	properties PropertyServiceInterface
+	agencies   *AgencyService
+	config     WidgetConfig
+	logger     *log.Logger
+	now        func() time.Time
+}
+
+// NewWidgetService creates a new widget service
+func NewWidgetService(properties PropertyServiceInterface, agencies *AgencyService, config WidgetConfig, logger *log.Logger) *WidgetService {
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = 365 * 24 * time.Hour
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &WidgetService{
+		properties: properties,
+		agencies:   agencies,
+		config:     config,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// IssueToken issues an embed token for the widget of an agency, usable from the sites of
+// the given origins, or from any site when none are given
+func (s *WidgetService) IssueToken(actor domain.Actor, agencyID string, origins []string) (string, *domain.WidgetTokenClaims, error) {
+	if !actor.CanAdministerAgency(agencyID) {
+		return "", nil, fmt.Errorf("permission denied: cannot issue widget tokens for this agency")
+	}
+	if s.config.SigningSecret == "" {
+		return "", nil, fmt.Errorf("widget tokens are not configured")
+	}
+
+	origins, err := domain.NormalizeWidgetOrigins(origins)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := s.agencies.GetAgency(agencyID); err != nil {
+		return "", nil, err
+	}
+
+	now := s.now()
+	claims := &domain.WidgetTokenClaims{
+		AgencyID:  agencyID,
+		Origins:   origins,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.TokenTTL).Unix(),
+	}
+	token, err := domain.SignWidgetToken(s.config.SigningSecret, *claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	s.logger.Printf("Widget token issued for agency %s by %s (origins: %s)", agencyID, actor.UserID, strings.Join(origins, ", "))
+	return token, claims, nil
+}
+
+// VerifyToken checks that an embed token is valid for the widget of an agency requested
+// from origin, the Origin header of the request
+func (s *WidgetService) VerifyToken(token, agencyID, origin string) (*domain.WidgetTokenClaims, error) {
+	if s.config.SigningSecret == "" {
+		return nil, fmt.Errorf("widget tokens are not configured")
+	}
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("invalid widget token: a token is required")
+	}
+
+	claims, err := domain.ParseWidgetToken(s.config.SigningSecret, token, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.AgencyID != agencyID {
+		return nil, fmt.Errorf("permission denied: the widget token was issued for another agency")
+	}
+	if !claims.AllowsOrigin(origin) {
+		return nil, fmt.Errorf("permission denied: the widget token is not valid for origin %s", origin)
+	}
+	return claims, nil
+}
+
+// AgencyListings returns the newest available listings of an active agency for its
+// widget, with the featured ones first
+func (s *WidgetService) AgencyListings(agencyID string, limit int) (*domain.WidgetPayload, error) {
+	if limit <= 0 {
+		limit = domain.DefaultWidgetListings
+	}
+	if limit > domain.MaxWidgetListings {
+		limit = domain.MaxWidgetListings
+	}
+
+	agency, err := s.agencies.GetAgency(agencyID)
+	if err != nil {
+		return nil, err
+	}
+	if agency.Status != domain.AgencyStatusActive {
+		return nil, fmt.Errorf("agency not found: %s", agencyID)
+	}
+
+	filters := &domain.PropertySearchFilters{
+		AgencyID: &agencyID,
+		Status:   []string{domain.StatusAvailable},
+	}
+	pagination := &domain.PaginationParams{Page: 1, PageSize: limit, SortBy: "created_at", SortDesc: true}
+	result, err := s.properties.FilterPropertiesPaginated(filters, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get widget listings: %w", err)
+	}
+
+	properties, _ := result.Data.([]domain.Property)
+	payload := &domain.WidgetPayload{
+		Agency:   domain.WidgetAgency{ID: agency.ID, Name: agency.Name, LogoURL: agency.LogoURL},
+		Listings: make([]domain.WidgetListing, 0, len(properties)),
+		Total:    len(properties),
+	}
+	if result.Pagination != nil {
+		payload.Total = result.Pagination.TotalRecords
+	}
+	for i := range properties {
+		if properties[i].Featured {
+			payload.Listings = append(payload.Listings, domain.NewWidgetListing(&properties[i]))
+		}
+	}
+	for i := range properties {
+		if !properties[i].Featured {
+			payload.Listings = append(payload.Listings, domain.NewWidgetListing(&properties[i]))
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,154 @@
+package sms
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// Supported providers
+const (
+	ProviderNone   = "none"
+	ProviderLog    = "log"
+	ProviderTwilio = "twilio"
+)
+
+// twilioBaseURL is the Twilio REST API the messages are created on
+const twilioBaseURL = "https://api.twilio.com/2010-04-01"
+
+// Sender delivers text messages to phone numbers
+type Sender interface {
+	// Name identifies the provider
+	Name() string
+
+	// Send delivers a message to a phone number in E.164 format, e.g. +593991234567
+	Send(to, body string) error
+}
+
+// IsValidProvider verifies if a provider is supported
+func IsValidProvider(provider string) bool {
+	switch provider {
+	case ProviderNone, ProviderLog, ProviderTwilio:
+		return true
+	}
+	return false
+}
+
+// LogSender writes messages to the log instead of sending them. It is used in local
+// development, where codes are read from the log.
+type LogSender struct {
+	logger *log.Logger
+}
+
+// NewLogSender creates a sender that logs messages
+func NewLogSender(logger *log.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Name identifies the provider
+func (s *LogSender) Name() string {
+	return ProviderLog
+}
+
+// Send logs the message
+func (s *LogSender) Send(to, body string) error {
+	s.logger.Printf("SMS to %s: %s", to, body)
+	return nil
+}
+
+// TwilioSender sends messages with the Twilio Messages API: the recipient, sender and
+// body are POSTed as a form to /Accounts/{AccountSid}/Messages.json, authenticated
+// with the account SID and auth token.
+type TwilioSender struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// NewTwilioSender creates a Twilio sender. from is a Twilio number or messaging
+// service SID (MG...).
+func NewTwilioSender(accountSID, authToken, from string, timeout time.Duration) (*TwilioSender, error) {
+	if accountSID == "" || authToken == "" {
+		return nil, fmt.Errorf("Twilio account SID and auth token are required")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("Twilio sender number is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &TwilioSender{
+		baseURL:    twilioBaseURL,
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (s *TwilioSender) Name() string {
+	return ProviderTwilio
+}
+
+// Send creates a message on Twilio
+func (s *TwilioSender) Send(to, body string) error {
+	form := url.Values{"To": {to}, "Body": {body}}
+	if strings.HasPrefix(s.from, "MG") {
+		form.Set("MessagingServiceSid", s.from)
+	} else {
+		form.Set("From", s.from)
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.accountSID))
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating Twilio request: %w", err)
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting Twilio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	// Twilio errors come as {"code": 21211, "message": "The 'To' number ... is not valid."}
+	var failure struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(raw, &failure) == nil && failure.Message != "" {
+		return fmt.Errorf("Twilio returned status %d: %s (code %d)", resp.StatusCode, failure.Message, failure.Code)
+	}
+	return fmt.Errorf("Twilio returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+}
+
+// NewSender creates the sender of a provider. The none provider has no sender.
+func NewSender(provider, accountSID, authToken, from string, timeout time.Duration, logger *log.Logger) (Sender, error) {
+	switch provider {
+	case ProviderNone, "":
+		return nil, nil
+	case ProviderLog:
+		return NewLogSender(logger), nil
+	case ProviderTwilio:
+		return NewTwilioSender(accountSID, authToken, from, timeout)
+	}
+	return nil, fmt.Errorf("unsupported SMS provider: %s", provider)
+}
@@ -0,0 +1,57 @@
+package sms
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilioSender_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/Accounts/AC123/Messages.json", r.URL.Path)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", user)
+		assert.Equal(t, "token", password)
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "+15005550006", r.PostForm.Get("From"))
+		assert.Equal(t, "Your code is 123456", r.PostForm.Get("Body"))
+
+		if r.PostForm.Get("To") == "+593991234567" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"sid":"SM1","status":"queued"}`)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":21211,"message":"The 'To' number is not a valid phone number."}`)
+	}))
+	defer server.Close()
+
+	sender, err := NewTwilioSender("AC123", "token", "+15005550006", time.Second)
+	require.NoError(t, err)
+	sender.baseURL = server.URL
+
+	require.NoError(t, sender.Send("+593991234567", "Your code is 123456"))
+
+	err = sender.Send("+5930", "Your code is 123456")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "code 21211")
+}
+
+func TestNewSender(t *testing.T) {
+	sender, err := NewSender(ProviderNone, "", "", "", 0, nil)
+	require.NoError(t, err)
+	assert.Nil(t, sender)
+
+	_, err = NewSender(ProviderTwilio, "AC123", "", "+15005550006", 0, nil)
+	assert.Error(t, err)
+
+	_, err = NewSender("carrier-pigeon", "", "", "", 0, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,391 @@
+package storage
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CDN serves stored objects from an edge network in front of the storage backend
+type CDN interface {
+	// Name identifies the CDN provider
+	Name() string
+
+	// URL returns the CDN URL of a stored object, signed when URL signing is enabled
+	URL(filePath string) string
+
+	// Purge invalidates the cached copies of stored objects
+	Purge(filePaths []string) error
+}
+
+// CDNConfig holds the settings for a CDN in front of image storage
+type CDNConfig struct {
+	Provider     string // cloudfront, cloudflare
+	BaseURL      string // e.g. https://cdn.example.com or https://d111111abcdef8.cloudfront.net
+	SignURLs     bool
+	SignedURLTTL time.Duration
+
+	// CloudFront
+	DistributionID  string
+	KeyPairID       string // public key ID used to verify signed URLs
+	PrivateKeyPath  string // PEM RSA key matching KeyPairID
+	AccessKeyID     string // credentials for CreateInvalidation
+	SecretAccessKey string
+
+	// Cloudflare
+	ZoneID     string
+	APIToken   string // token with Cache Purge permission
+	SigningKey string // secret of the is_timed_hmac_valid_v0 token authentication rule
+}
+
+const (
+	cdnDefaultSignedURLTTL = time.Hour
+	cloudFrontAPIEndpoint  = "https://cloudfront.amazonaws.com"
+	cloudFrontAPIVersion   = "2020-05-31"
+	cloudFrontRegion       = "us-east-1"
+	cloudFrontService      = "cloudfront"
+	cloudflareAPIEndpoint  = "https://api.cloudflare.com/client/v4"
+	cloudflareMaxPurge     = 30 // files per purge request
+)
+
+// NewCDN creates the CDN configured by config.Provider
+func NewCDN(config CDNConfig) (CDN, error) {
+	base, err := newCDNBase(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(config.Provider) {
+	case "cloudfront":
+		return newCloudFrontCDN(base, config)
+	case "cloudflare":
+		return newCloudflareCDN(base, config)
+	default:
+		return nil, fmt.Errorf("unsupported CDN provider: %s", config.Provider)
+	}
+}
+
+// cdnBase holds the behaviour shared by all providers
+type cdnBase struct {
+	baseURL  *url.URL
+	signURLs bool
+	ttl      time.Duration
+	client   *http.Client
+	now      func() time.Time
+}
+
+func newCDNBase(config CDNConfig) (*cdnBase, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("CDN base URL is required")
+	}
+	baseURL, err := url.Parse(strings.TrimRight(config.BaseURL, "/"))
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid CDN base URL: %s", config.BaseURL)
+	}
+
+	ttl := config.SignedURLTTL
+	if ttl <= 0 {
+		ttl = cdnDefaultSignedURLTTL
+	}
+
+	return &cdnBase{
+		baseURL:  baseURL,
+		signURLs: config.SignURLs,
+		ttl:      ttl,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+// objectURL returns the unsigned CDN URL of a stored object
+func (b *cdnBase) objectURL(filePath string) *url.URL {
+	objectURL := *b.baseURL
+	objectURL.Path = path.Join("/", b.baseURL.Path, cdnObjectPath(filePath))
+	return &objectURL
+}
+
+// cdnObjectPath converts a storage path to a URL path without a leading slash
+func cdnObjectPath(filePath string) string {
+	return strings.TrimLeft(strings.ReplaceAll(filePath, "\\", "/"), "/")
+}
+
+// send performs an API request and fails on error responses
+func (b *cdnBase) send(req *http.Request, provider string) error {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// CloudFrontCDN serves images through Amazon CloudFront. Signed URLs use a canned
+// policy and purges create distribution invalidations.
+type CloudFrontCDN struct {
+	*cdnBase
+	config     CDNConfig
+	privateKey *rsa.PrivateKey
+	apiURL     string
+}
+
+func newCloudFrontCDN(base *cdnBase, config CDNConfig) (*CloudFrontCDN, error) {
+	cdn := &CloudFrontCDN{cdnBase: base, config: config, apiURL: cloudFrontAPIEndpoint}
+
+	if config.SignURLs {
+		if config.KeyPairID == "" || config.PrivateKeyPath == "" {
+			return nil, fmt.Errorf("CloudFront key pair ID and private key are required for signed URLs")
+		}
+		pemData, err := os.ReadFile(config.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CloudFront private key: %w", err)
+		}
+		if cdn.privateKey, err = parseRSAPrivateKey(pemData); err != nil {
+			return nil, err
+		}
+	}
+
+	return cdn, nil
+}
+
+// Name identifies the CDN provider
+func (c *CloudFrontCDN) Name() string {
+	return "cloudfront"
+}
+
+// URL returns the CloudFront URL of a stored object
+func (c *CloudFrontCDN) URL(filePath string) string {
+	if filePath == "" {
+		return ""
+	}
+
+	objectURL := c.objectURL(filePath)
+	if !c.signURLs {
+		return objectURL.String()
+	}
+
+	expires := c.now().Add(c.ttl).Unix()
+	policy := fmt.Sprintf(`{"Statement":[{"Resource":"%s","Condition":{"DateLessThan":{"AWS:EpochTime":%d}}}]}`,
+		objectURL.String(), expires)
+
+	digest := sha1.Sum([]byte(policy))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.privateKey, crypto.SHA1, digest[:])
+	if err != nil {
+		return objectURL.String()
+	}
+
+	query := objectURL.Query()
+	query.Set("Expires", strconv.FormatInt(expires, 10))
+	query.Set("Signature", cloudFrontBase64(signature))
+	query.Set("Key-Pair-Id", c.config.KeyPairID)
+	objectURL.RawQuery = query.Encode()
+
+	return objectURL.String()
+}
+
+// Purge creates an invalidation for the given objects
+func (c *CloudFrontCDN) Purge(filePaths []string) error {
+	if len(filePaths) == 0 {
+		return nil
+	}
+	if c.config.DistributionID == "" || c.config.AccessKeyID == "" || c.config.SecretAccessKey == "" {
+		return fmt.Errorf("CloudFront distribution ID and credentials are required for purges")
+	}
+
+	type invalidationPaths struct {
+		Quantity int      `xml:"Quantity"`
+		Items    []string `xml:"Items>Path"`
+	}
+	batch := struct {
+		XMLName         xml.Name          `xml:"InvalidationBatch"`
+		Xmlns           string            `xml:"xmlns,attr"`
+		Paths           invalidationPaths `xml:"Paths"`
+		CallerReference string            `xml:"CallerReference"`
+	}{
+		Xmlns:           "http://cloudfront.amazonaws.com/doc/" + cloudFrontAPIVersion + "/",
+		CallerReference: strconv.FormatInt(c.now().UnixNano(), 10),
+	}
+	for _, filePath := range filePaths {
+		batch.Paths.Items = append(batch.Paths.Items, c.objectURL(filePath).Path)
+	}
+	batch.Paths.Quantity = len(batch.Paths.Items)
+
+	body, err := xml.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode invalidation: %w", err)
+	}
+	body = append([]byte(xml.Header), body...)
+
+	endpoint := fmt.Sprintf("%s/%s/distribution/%s/invalidation", c.apiURL, cloudFrontAPIVersion, url.PathEscape(c.config.DistributionID))
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	c.signRequest(req, body)
+
+	return c.send(req, "CloudFront")
+}
+
+// signRequest adds SigV4 authorization headers for the CloudFront API
+func (c *CloudFrontCDN) signRequest(req *http.Request, body []byte) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := strings.Join([]string{now.Format("20060102"), cloudFrontRegion, cloudFrontService, "aws4_request"}, "/")
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"content-type":         req.Header.Get("Content-Type"),
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	signedHeaders := signedHeaderNames(headers)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders(headers),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+	key := sigV4SigningKey(c.config.SecretAccessKey, now, cloudFrontRegion, cloudFrontService)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, c.config.AccessKeyID, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+// cloudFrontBase64 is base64 with the characters CloudFront requires in query strings
+func cloudFrontBase64(data []byte) string {
+	return strings.NewReplacer("+", "-", "=", "_", "/", "~").Replace(base64.StdEncoding.EncodeToString(data))
+}
+
+// parseRSAPrivateKey parses a PKCS#1 or PKCS#8 PEM encoded RSA key
+func parseRSAPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// CloudflareCDN serves images through Cloudflare. Signed URLs follow the
+// is_timed_hmac_valid_v0 token authentication format and purges use the zone purge API.
+type CloudflareCDN struct {
+	*cdnBase
+	config CDNConfig
+	apiURL string
+}
+
+func newCloudflareCDN(base *cdnBase, config CDNConfig) (*CloudflareCDN, error) {
+	if config.SignURLs && config.SigningKey == "" {
+		return nil, fmt.Errorf("Cloudflare signing key is required for signed URLs")
+	}
+	return &CloudflareCDN{cdnBase: base, config: config, apiURL: cloudflareAPIEndpoint}, nil
+}
+
+// Name identifies the CDN provider
+func (c *CloudflareCDN) Name() string {
+	return "cloudflare"
+}
+
+// URL returns the Cloudflare URL of a stored object. Signed URLs carry their issue
+// time; the WAF rule rejects them once the configured lifetime has passed.
+func (c *CloudflareCDN) URL(filePath string) string {
+	if filePath == "" {
+		return ""
+	}
+
+	objectURL := c.objectURL(filePath)
+	if !c.signURLs {
+		return objectURL.String()
+	}
+
+	issued := strconv.FormatInt(c.now().Unix(), 10)
+	mac := hmacSHA256([]byte(c.config.SigningKey), objectURL.Path+issued)
+
+	query := objectURL.Query()
+	query.Set("verify", issued+"-"+base64.StdEncoding.EncodeToString(mac))
+	objectURL.RawQuery = query.Encode()
+
+	return objectURL.String()
+}
+
+// Purge removes the given objects from the zone cache
+func (c *CloudflareCDN) Purge(filePaths []string) error {
+	if len(filePaths) == 0 {
+		return nil
+	}
+	if c.config.ZoneID == "" || c.config.APIToken == "" {
+		return fmt.Errorf("Cloudflare zone ID and API token are required for purges")
+	}
+
+	files := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		files = append(files, c.objectURL(filePath).String())
+	}
+
+	for start := 0; start < len(files); start += cloudflareMaxPurge {
+		end := start + cloudflareMaxPurge
+		if end > len(files) {
+			end = len(files)
+		}
+
+		body, err := json.Marshal(map[string][]string{"files": files[start:end]})
+		if err != nil {
+			return fmt.Errorf("failed to encode purge request: %w", err)
+		}
+
+		endpoint := fmt.Sprintf("%s/zones/%s/purge_cache", c.apiURL, url.PathEscape(c.config.ZoneID))
+		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+c.config.APIToken)
+
+		if err := c.send(req, "Cloudflare"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
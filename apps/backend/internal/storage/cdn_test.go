@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cdnTestTime = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestNewCDN(t *testing.T) {
+	_, err := NewCDN(CDNConfig{Provider: "cloudfront"})
+	assert.Error(t, err)
+
+	_, err = NewCDN(CDNConfig{Provider: "akamai", BaseURL: "https://cdn.example.com"})
+	assert.Error(t, err)
+
+	_, err = NewCDN(CDNConfig{Provider: "cloudfront", BaseURL: "https://cdn.example.com", SignURLs: true})
+	assert.Error(t, err)
+
+	_, err = NewCDN(CDNConfig{Provider: "cloudflare", BaseURL: "https://cdn.example.com", SignURLs: true})
+	assert.Error(t, err)
+
+	cdn, err := NewCDN(CDNConfig{Provider: "Cloudflare", BaseURL: "https://cdn.example.com/media/"})
+	require.NoError(t, err)
+	assert.Equal(t, "cloudflare", cdn.Name())
+	assert.Equal(t, "https://cdn.example.com/media/originals/prop-1/photo.jpg", cdn.URL("originals/prop-1/photo.jpg"))
+	assert.Equal(t, "", cdn.URL(""))
+}
+
+func TestCloudFrontCDN_SignedURL(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "cloudfront.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(keyPath, pemData, 0600))
+
+	cdn, err := NewCDN(CDNConfig{
+		Provider:       "cloudfront",
+		BaseURL:        "https://d111111abcdef8.cloudfront.net",
+		SignURLs:       true,
+		SignedURLTTL:   10 * time.Minute,
+		KeyPairID:      "K2JCJMDEHXQW5F",
+		PrivateKeyPath: keyPath,
+	})
+	require.NoError(t, err)
+	cdn.(*CloudFrontCDN).now = func() time.Time { return cdnTestTime }
+
+	signed, err := url.Parse(cdn.URL("originals/photo.jpg"))
+	require.NoError(t, err)
+	query := signed.Query()
+	assert.Equal(t, "/originals/photo.jpg", signed.Path)
+	assert.Equal(t, fmt.Sprint(cdnTestTime.Add(10*time.Minute).Unix()), query.Get("Expires"))
+	assert.Equal(t, "K2JCJMDEHXQW5F", query.Get("Key-Pair-Id"))
+
+	policy := fmt.Sprintf(`{"Statement":[{"Resource":"https://d111111abcdef8.cloudfront.net/originals/photo.jpg","Condition":{"DateLessThan":{"AWS:EpochTime":%s}}}]}`,
+		query.Get("Expires"))
+	signature, err := base64.StdEncoding.DecodeString(strings.NewReplacer("-", "+", "_", "=", "~", "/").Replace(query.Get("Signature")))
+	require.NoError(t, err)
+	digest := sha1.Sum([]byte(policy))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, digest[:], signature))
+}
+
+func TestCloudFrontCDN_Purge(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if r.URL.Path != "/2020-05-31/distribution/E2EXAMPLE/invalidation" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20250301/us-east-1/cloudfront/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	cdn, err := NewCDN(CDNConfig{
+		Provider:        "cloudfront",
+		BaseURL:         "https://cdn.example.com",
+		DistributionID:  "E2EXAMPLE",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+	cloudFront := cdn.(*CloudFrontCDN)
+	cloudFront.apiURL = server.URL
+	cloudFront.now = func() time.Time { return cdnTestTime }
+
+	require.NoError(t, cdn.Purge([]string{"originals/photo.jpg", "thumbnails/photo_thumb.jpg"}))
+	assert.Contains(t, body, "<Quantity>2</Quantity>")
+	assert.Contains(t, body, "<Path>/originals/photo.jpg</Path>")
+	assert.Contains(t, body, "<Path>/thumbnails/photo_thumb.jpg</Path>")
+	assert.NoError(t, cdn.Purge(nil))
+
+	cloudFront.config.DistributionID = "other"
+	assert.Error(t, cdn.Purge([]string{"originals/photo.jpg"}))
+}
+
+func TestCloudflareCDN(t *testing.T) {
+	var purged [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones/zone-1/purge_cache" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req map[string][]string
+		json.NewDecoder(r.Body).Decode(&req)
+		purged = append(purged, req["files"])
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	cdn, err := NewCDN(CDNConfig{
+		Provider:   "cloudflare",
+		BaseURL:    "https://img.example.com",
+		SignURLs:   true,
+		ZoneID:     "zone-1",
+		APIToken:   "token",
+		SigningKey: "secret",
+	})
+	require.NoError(t, err)
+	cloudflare := cdn.(*CloudflareCDN)
+	cloudflare.apiURL = server.URL
+	cloudflare.now = func() time.Time { return cdnTestTime }
+
+	signed, err := url.Parse(cdn.URL("originals/photo.jpg"))
+	require.NoError(t, err)
+	issued := fmt.Sprint(cdnTestTime.Unix())
+	expected := base64.StdEncoding.EncodeToString(hmacSHA256([]byte("secret"), "/originals/photo.jpg"+issued))
+	assert.Equal(t, issued+"-"+expected, signed.Query().Get("verify"))
+
+	paths := make([]string, 35)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("originals/photo-%d.jpg", i)
+	}
+	require.NoError(t, cdn.Purge(paths))
+	require.Len(t, purged, 2)
+	assert.Len(t, purged[0], 30)
+	assert.Equal(t, "https://img.example.com/originals/photo-34.jpg", purged[1][4])
+}
@@ -32,6 +32,13 @@ type ImageStorage interface {
 	GetStorageInfo() StorageInfo
 }
 
+// FileOpener is implemented by storage backends on the local filesystem, whose files
+// can be served straight from disk instead of being read into memory
+type FileOpener interface {
+	// Open opens a stored file for reading; the caller closes it
+	Open(filePath string) (*os.File, error)
+}
+
 // StorageInfo contains storage backend information
 type StorageInfo struct {
 	Type         string `json:"type"`
@@ -213,6 +220,41 @@ func (ls *LocalImageStorage) Exists(filePath string) bool {
 	return err == nil
 }
 
+// Open opens a stored file for reading
+func (ls *LocalImageStorage) Open(filePath string) (*os.File, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file path cannot be empty")
+	}
+
+	filePath = filepath.Clean(filePath)
+	if filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file path cannot be absolute")
+	}
+
+	fullPath := filepath.Join(ls.basePath, filePath)
+	if !ls.isPathWithinBase(fullPath) {
+		return nil, fmt.Errorf("path outside base directory: %s", filePath)
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found: %s", filePath)
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, fmt.Errorf("file not found: %s", filePath)
+	}
+	return file, nil
+}
+
 // GetURL returns the public URL for the image
 func (ls *LocalImageStorage) GetURL(filePath string) string {
 	if filePath == "" {
@@ -339,6 +381,44 @@ func (ls *LocalImageStorage) CleanupTempFiles(olderThan time.Duration) error {
 	})
 }
 
+// ListObjects returns the files under prefix with paths relative to the base directory
+func (ls *LocalImageStorage) ListObjects(prefix string) ([]ObjectInfo, error) {
+	root := filepath.Join(ls.basePath, filepath.Clean(prefix))
+	if !ls.isPathWithinBase(root) {
+		return nil, fmt.Errorf("prefix outside base directory")
+	}
+	
+	var objects []ObjectInfo
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(ls.basePath, path)
+		if err != nil {
+			return err
+		}
+		
+		objects = append(objects, ObjectInfo{Key: relPath, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	
+	return objects, nil
+}
+
 // isPathWithinBase checks if path is within base directory (security check)
 func (ls *LocalImageStorage) isPathWithinBase(path string) bool {
 	absBase, err := filepath.Abs(ls.basePath)
@@ -304,6 +304,27 @@ func TestLocalImageStorage_Exists(t *testing.T) {
 	}
 }
 
+func TestLocalImageStorage_Open(t *testing.T) {
+	tempDir := t.TempDir()
+	storage, err := NewLocalImageStorage(tempDir, "", 1024*1024)
+	require.NoError(t, err)
+
+	path, err := storage.Store([]byte("test image data"), "test.jpg")
+	require.NoError(t, err)
+
+	file, err := storage.Open(path)
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), info.Size())
+	file.Close()
+
+	for _, filePath := range []string{"", "/etc/passwd", "../../../etc/passwd", "originals", "originals/missing.jpg"} {
+		_, err := storage.Open(filePath)
+		assert.Error(t, err, filePath)
+	}
+}
+
 func TestLocalImageStorage_GetURL(t *testing.T) {
 	tempDir := t.TempDir()
 	baseURL := "http://localhost:8080/images"
@@ -0,0 +1,468 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PresignedUploader is implemented by storage backends that accept direct client uploads
+type PresignedUploader interface {
+	// PresignUpload returns a URL the client can PUT the object to until it expires
+	PresignUpload(key, contentType string, expires time.Duration) (string, error)
+
+	// Stat returns the size and content type of a stored object
+	Stat(key string) (ObjectInfo, error)
+
+	// Open streams a stored object; the caller closes it
+	Open(key string) (io.ReadCloser, error)
+}
+
+// ObjectLister is implemented by storage backends that can enumerate stored objects
+type ObjectLister interface {
+	// ListObjects returns the objects whose path starts with prefix
+	ListObjects(prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// S3Config holds the settings for an S3-compatible storage backend
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or a MinIO URL
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicBaseURL   string // optional CDN or public bucket URL used by GetURL
+	UsePathStyle    bool   // bucket in the path instead of the host (MinIO, localstack)
+	MaxFileSize     int64
+}
+
+// S3ImageStorage implements ImageStorage on an S3-compatible object store
+// using AWS Signature Version 4
+type S3ImageStorage struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+const (
+	s3Service         = "s3"
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3MaxPresignTTL   = 7 * 24 * time.Hour
+)
+
+// NewS3ImageStorage creates a new S3-backed image storage
+func NewS3ImageStorage(config S3Config) (*S3ImageStorage, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	if config.Region == "" {
+		return nil, fmt.Errorf("S3 region is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials are required")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", config.Endpoint)
+	}
+
+	return &S3ImageStorage{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+// Store uploads image data under the given key
+func (s *S3ImageStorage) Store(data []byte, fileName string) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("image data cannot be empty")
+	}
+	if s.config.MaxFileSize > 0 && int64(len(data)) > s.config.MaxFileSize {
+		return "", fmt.Errorf("file too large: %d bytes, max: %d bytes", len(data), s.config.MaxFileSize)
+	}
+
+	key := strings.TrimLeft(fileName, "/")
+	contentType := http.DetectContentType(data)
+
+	resp, err := s.do(http.MethodPut, key, data, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return "", fmt.Errorf("failed to store object: %w", err)
+	}
+	resp.Body.Close()
+
+	return key, nil
+}
+
+// Retrieve downloads an object
+func (s *S3ImageStorage) Retrieve(filePath string) ([]byte, error) {
+	body, err := s.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, nil
+}
+
+// Open streams an object; the caller closes it
+func (s *S3ImageStorage) Open(key string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve object: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object
+func (s *S3ImageStorage) Delete(filePath string) error {
+	resp, err := s.do(http.MethodDelete, filePath, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Exists checks if an object exists
+func (s *S3ImageStorage) Exists(filePath string) bool {
+	_, err := s.Stat(filePath)
+	return err == nil
+}
+
+// Stat returns the size and content type of an object
+func (s *S3ImageStorage) Stat(key string) (ObjectInfo, error) {
+	resp, err := s.do(http.MethodHead, key, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
+	}
+	resp.Body.Close()
+
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	return ObjectInfo{
+		Key:         key,
+		Size:        size,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// ListObjects lists the objects whose key starts with prefix using ListObjectsV2
+func (s *S3ImageStorage) ListObjects(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	continuationToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if prefix != "" {
+			query.Set("prefix", strings.TrimLeft(prefix, "/"))
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		listURL := s.objectURL("")
+		listURL.RawQuery = canonicalQuery(query)
+
+		req, err := http.NewRequest(http.MethodGet, listURL.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		resp, err := s.send(req, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		var result struct {
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+			Contents              []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object listing: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			objects = append(objects, ObjectInfo{Key: object.Key, Size: object.Size, LastModified: object.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// GetURL returns the public URL of an object
+func (s *S3ImageStorage) GetURL(filePath string) string {
+	key := strings.TrimLeft(filePath, "/")
+	if s.config.PublicBaseURL != "" {
+		return strings.TrimRight(s.config.PublicBaseURL, "/") + "/" + key
+	}
+	return s.objectURL(key).String()
+}
+
+// GetStorageInfo returns storage information
+func (s *S3ImageStorage) GetStorageInfo() StorageInfo {
+	return StorageInfo{
+		Type:        "s3",
+		BasePath:    s.config.Bucket,
+		BaseURL:     s.GetURL(""),
+		MaxFileSize: s.config.MaxFileSize,
+	}
+}
+
+// PresignUpload returns a presigned PUT URL. The client must send the same Content-Type.
+func (s *S3ImageStorage) PresignUpload(key, contentType string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, contentType, expires)
+}
+
+// presign builds a query-signed URL for the given method and object
+func (s *S3ImageStorage) presign(method, key, contentType string, expires time.Duration) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("object key is required")
+	}
+	if expires < time.Second || expires > s3MaxPresignTTL {
+		return "", fmt.Errorf("expiration must be between 1s and %s", s3MaxPresignTTL)
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.credentialScope(now)
+	objectURL := s.objectURL(strings.TrimLeft(key, "/"))
+
+	headers := map[string]string{"host": objectURL.Host}
+	if contentType != "" {
+		headers["content-type"] = contentType
+	}
+	signedHeaders := signedHeaderNames(headers)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", signedHeaders)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(objectURL.Path),
+		canonicalQuery(query),
+		canonicalHeaders(headers),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(now, amzDate, scope, canonicalRequest))
+	objectURL.RawQuery = canonicalQuery(query)
+
+	return objectURL.String(), nil
+}
+
+// do sends a header-signed request for an object and checks the response status
+func (s *S3ImageStorage) do(method, key string, body []byte, extraHeaders map[string]string) (*http.Response, error) {
+	objectURL := s.objectURL(strings.TrimLeft(key, "/"))
+
+	req, err := http.NewRequest(method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	for name, value := range extraHeaders {
+		req.Header.Set(name, value)
+	}
+	return s.send(req, body)
+}
+
+// send signs a request and checks the response status
+func (s *S3ImageStorage) send(req *http.Request, body []byte) (*http.Response, error) {
+	s.signRequest(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting object storage: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("object storage returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// signRequest adds SigV4 authorization headers to a request
+func (s *S3ImageStorage) signRequest(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.credentialScope(now)
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+	signedHeaders := signedHeaderNames(headers)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders(headers),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKeyID, scope, signedHeaders, s.signature(now, amzDate, scope, canonicalRequest)))
+}
+
+// objectURL returns the URL of an object using path-style or virtual-hosted addressing
+func (s *S3ImageStorage) objectURL(key string) *url.URL {
+	objectURL := *s.endpoint
+	if s.config.UsePathStyle {
+		objectURL.Path = path.Join("/", s.endpoint.Path, s.config.Bucket, key)
+	} else {
+		objectURL.Host = s.config.Bucket + "." + s.endpoint.Host
+		objectURL.Path = path.Join("/", s.endpoint.Path, key)
+	}
+	return &objectURL
+}
+
+// credentialScope returns date/region/service/aws4_request
+func (s *S3ImageStorage) credentialScope(now time.Time) string {
+	return strings.Join([]string{now.Format("20060102"), s.config.Region, s3Service, "aws4_request"}, "/")
+}
+
+// signature computes the SigV4 signature of a canonical request
+func (s *S3ImageStorage) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := sigV4SigningKey(s.config.SecretAccessKey, now, s.config.Region, s3Service)
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// sigV4SigningKey derives the SigV4 signing key for a date, region and service
+func sigV4SigningKey(secretAccessKey string, now time.Time, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalURI encodes each path segment as required by SigV4
+func canonicalURI(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery sorts and encodes query parameters as required by SigV4
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		vals := append([]string{}, values[key]...)
+		sort.Strings(vals)
+		for _, value := range vals {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// canonicalHeaders returns lowercase name:value lines sorted by name
+func canonicalHeaders(headers map[string]string) string {
+	names := strings.Split(signedHeaderNames(headers), ";")
+	var builder strings.Builder
+	for _, name := range names {
+		builder.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return builder.String()
+}
+
+// signedHeaderNames returns the sorted header names joined by semicolons
+func signedHeaderNames(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+// uriEncode percent-encodes everything except unreserved characters (RFC 3986)
+func uriEncode(value string) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			builder.WriteByte(b)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
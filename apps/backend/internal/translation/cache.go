@@ -0,0 +1,104 @@
+package translation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache sizing. Machine translations of the same text do not change, so they are kept
+// for a month; the cache mostly saves provider quota when listings are republished or
+// share boilerplate such as agency disclaimers. Failures are not cached.
+const (
+	DefaultCacheTTL        = 30 * 24 * time.Hour
+	DefaultCacheMaxEntries = 20000
+)
+
+// cachedTranslation is a translation with when it was requested
+type cachedTranslation struct {
+	text string
+	at   time.Time
+}
+
+// CachedTranslator keeps recent translations in memory, so the provider is asked once
+// per text, language pair and TTL
+type CachedTranslator struct {
+	translator Translator
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedTranslation
+}
+
+// NewCachedTranslator creates a cache in front of a translator
+func NewCachedTranslator(translator Translator, ttl time.Duration, maxEntries int) (*CachedTranslator, error) {
+	if translator == nil {
+		return nil, fmt.Errorf("translator is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &CachedTranslator{
+		translator: translator,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    map[string]cachedTranslation{},
+	}, nil
+}
+
+// Name identifies the cached provider
+func (c *CachedTranslator) Name() string {
+	return c.translator.Name()
+}
+
+// Translate returns the cached translation of a text, requesting it when missing or expired
+func (c *CachedTranslator) Translate(text, source, target string, html bool) (string, error) {
+	key := cacheKey(text, source, target, html)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.at) < c.ttl {
+		return entry.text, nil
+	}
+
+	translated, err := c.translator.Translate(text, source, target, html)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cachedTranslation{text: translated, at: now}
+	return translated, nil
+}
+
+// evict drops expired entries, or every entry when none expired
+func (c *CachedTranslator) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if now.Sub(entry.at) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = map[string]cachedTranslation{}
+	}
+}
+
+// cacheKey identifies a text and language pair without keeping long descriptions as keys
+func cacheKey(text, source, target string, html bool) string {
+	sum := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("%s|%s|%t|%s", source, target, html, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,115 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultDeepLEndpoint is the DeepL API Free translate endpoint; Pro keys use
+// https://api.deepl.com/v2/translate
+const DefaultDeepLEndpoint = "https://api-free.deepl.com/v2/translate"
+
+// deeplLanguages maps content locales to DeepL language codes. English targets need a
+// variant; US English is closest to what international buyers expect.
+var deeplLanguages = map[string][2]string{ // locale: source, target
+	"es": {"ES", "ES"},
+	"en": {"EN", "EN-US"},
+}
+
+// DeepLTranslator translates with the DeepL API. The API answers with:
+//
+//	{"translations": [{"detected_source_language": "ES", "text": "House with garden"}]}
+type DeepLTranslator struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewDeepLTranslator creates a translator for a DeepL API key
+func NewDeepLTranslator(endpoint, apiKey string, timeout time.Duration) (*DeepLTranslator, error) {
+	if endpoint == "" {
+		endpoint = DefaultDeepLEndpoint
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid DeepL endpoint: %w", err)
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("DeepL API key is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &DeepLTranslator{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (t *DeepLTranslator) Name() string {
+	return ProviderDeepL
+}
+
+// Translate translates text between locales
+func (t *DeepLTranslator) Translate(text, source, target string, html bool) (string, error) {
+	sourceLang, ok := deeplLanguages[source]
+	if !ok {
+		return "", fmt.Errorf("unsupported source locale: %s", source)
+	}
+	targetLang, ok := deeplLanguages[target]
+	if !ok {
+		return "", fmt.Errorf("unsupported target locale: %s", target)
+	}
+
+	payload := map[string]interface{}{
+		"text":        []string{text},
+		"source_lang": sourceLang[0],
+		"target_lang": targetLang[1],
+	}
+	if html {
+		payload["tag_handling"] = "html"
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting DeepL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("DeepL returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding DeepL response: %w", err)
+	}
+	if len(result.Translations) == 0 {
+		return "", fmt.Errorf("DeepL returned no translation")
+	}
+	return result.Translations[0].Text, nil
+}
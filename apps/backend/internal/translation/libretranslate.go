@@ -0,0 +1,97 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultLibreTranslateEndpoint is the public LibreTranslate instance, which requires an
+// API key; self-hosted instances usually do not
+const DefaultLibreTranslateEndpoint = "https://libretranslate.com/translate"
+
+// LibreTranslator translates with a LibreTranslate server, which uses the content
+// locales as language codes. The server answers with:
+//
+//	{"translatedText": "House with garden"}
+type LibreTranslator struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewLibreTranslator creates a translator for a LibreTranslate server
+func NewLibreTranslator(endpoint, apiKey string, timeout time.Duration) (*LibreTranslator, error) {
+	if endpoint == "" {
+		endpoint = DefaultLibreTranslateEndpoint
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid LibreTranslate endpoint: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &LibreTranslator{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (t *LibreTranslator) Name() string {
+	return ProviderLibreTranslate
+}
+
+// Translate translates text between locales
+func (t *LibreTranslator) Translate(text, source, target string, html bool) (string, error) {
+	format := "text"
+	if html {
+		format = "html"
+	}
+	payload := map[string]string{
+		"q":      text,
+		"source": source,
+		"target": target,
+		"format": format,
+	}
+	if t.apiKey != "" {
+		payload["api_key"] = t.apiKey
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting LibreTranslate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("LibreTranslate returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("error decoding LibreTranslate response: %w", err)
+	}
+	return result.TranslatedText, nil
+}
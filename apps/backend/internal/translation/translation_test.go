@@ -0,0 +1,123 @@
+package translation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepLTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DeepL-Auth-Key key", r.Header.Get("Authorization"))
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "ES", body["source_lang"])
+		assert.Equal(t, "EN-US", body["target_lang"])
+		assert.Equal(t, "html", body["tag_handling"])
+		fmt.Fprint(w, `{"translations":[{"detected_source_language":"ES","text":"<p>House with <b>garden</b></p>"}]}`)
+	}))
+	defer server.Close()
+
+	_, err := NewDeepLTranslator(server.URL, "", time.Second)
+	assert.Error(t, err)
+
+	translator, err := NewDeepLTranslator(server.URL, "key", time.Second)
+	require.NoError(t, err)
+
+	text, err := translator.Translate("<p>Casa con <b>jardín</b></p>", "es", "en", true)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>House with <b>garden</b></p>", text)
+
+	_, err = translator.Translate("Casa", "es", "fr", false)
+	assert.ErrorContains(t, err, "unsupported target locale")
+}
+
+func TestLibreTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["q"] == "fail" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":"Slowdown"}`)
+			return
+		}
+		assert.Equal(t, "text", body["format"])
+		assert.Empty(t, body["api_key"])
+		fmt.Fprint(w, `{"translatedText":"House in Cumbayá"}`)
+	}))
+	defer server.Close()
+
+	translator, err := NewLibreTranslator(server.URL, "", time.Second)
+	require.NoError(t, err)
+
+	text, err := translator.Translate("Casa en Cumbayá", "es", "en", false)
+	require.NoError(t, err)
+	assert.Equal(t, "House in Cumbayá", text)
+
+	_, err = translator.Translate("fail", "es", "en", false)
+	assert.ErrorContains(t, err, "status 429")
+}
+
+// countingTranslator prefixes text with the target locale and counts provider requests
+type countingTranslator struct {
+	calls int
+}
+
+func (t *countingTranslator) Name() string {
+	return "counting"
+}
+
+func (t *countingTranslator) Translate(text, source, target string, html bool) (string, error) {
+	t.calls++
+	if text == "" {
+		return "", fmt.Errorf("empty text")
+	}
+	return target + ":" + text, nil
+}
+
+func TestCachedTranslator_Translate(t *testing.T) {
+	provider := &countingTranslator{}
+	cached, err := NewCachedTranslator(provider, time.Hour, 2)
+	require.NoError(t, err)
+	now := time.Date(2025, 9, 20, 10, 0, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		text, err := cached.Translate("Casa", "es", "en", false)
+		require.NoError(t, err)
+		assert.Equal(t, "en:Casa", text)
+	}
+	assert.Equal(t, 1, provider.calls)
+
+	_, err = cached.Translate("Casa", "es", "en", true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls, "HTML and plain text are cached apart")
+
+	// Failures are not cached
+	_, err = cached.Translate("", "es", "en", false)
+	assert.Error(t, err)
+	_, err = cached.Translate("", "es", "en", false)
+	assert.Error(t, err)
+	assert.Equal(t, 4, provider.calls)
+
+	now = now.Add(2 * time.Hour)
+	_, err = cached.Translate("Casa", "es", "en", false)
+	require.NoError(t, err)
+	assert.Equal(t, 5, provider.calls)
+}
+
+func TestNewTranslator(t *testing.T) {
+	translator, err := NewTranslator(ProviderNone, "", "", 0)
+	require.NoError(t, err)
+	assert.Nil(t, translator)
+
+	_, err = NewTranslator("babelfish", "", "", 0)
+	assert.Error(t, err)
+	assert.False(t, IsValidProvider("babelfish"))
+}
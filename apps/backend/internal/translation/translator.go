@@ -0,0 +1,48 @@
+package translation
+
+import (
+	"fmt"
+	"time"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// Supported providers
+const (
+	ProviderNone           = "none"
+	ProviderDeepL          = "deepl"
+	ProviderLibreTranslate = "libretranslate"
+)
+
+// Translator machine-translates listing content between content locales
+type Translator interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// Translate translates text from the source locale to the target locale. HTML text
+	// keeps its markup; only the text between tags is translated.
+	Translate(text, source, target string, html bool) (string, error)
+}
+
+// IsValidProvider verifies if a provider is supported
+func IsValidProvider(provider string) bool {
+	switch provider {
+	case ProviderNone, ProviderDeepL, ProviderLibreTranslate:
+		return true
+	}
+	return false
+}
+
+// NewTranslator creates the translator of a provider. The none provider has no
+// translator.
+func NewTranslator(provider, endpoint, apiKey string, timeout time.Duration) (Translator, error) {
+	switch provider {
+	case ProviderNone, "":
+		return nil, nil
+	case ProviderDeepL:
+		return NewDeepLTranslator(endpoint, apiKey, timeout)
+	case ProviderLibreTranslate:
+		return NewLibreTranslator(endpoint, apiKey, timeout)
+	}
+	return nil, fmt.Errorf("unsupported translation provider: %s", provider)
+}
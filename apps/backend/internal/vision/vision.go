@@ -0,0 +1,119 @@
+package vision
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"realty-core/internal/domain"
+)
+
+const defaultHTTPTimeout = 15 * time.Second
+
+// Supported providers
+const (
+	ProviderNone = "none"
+	ProviderHTTP = "http"
+)
+
+// Provider detects scenes and objects in listing photos, such as a pool, a garden or
+// the facade. Implementations may call a cloud service (e.g. Google Cloud Vision) or a
+// locally hosted scene classification model.
+type Provider interface {
+	// Name identifies the provider in analysis records
+	Name() string
+
+	// Detect returns the labels found in the image with their confidence
+	Detect(data []byte, contentType string) ([]domain.ImageLabel, error)
+}
+
+// IsValidProvider verifies if a provider is supported
+func IsValidProvider(provider string) bool {
+	switch provider {
+	case ProviderNone, ProviderHTTP:
+		return true
+	}
+	return false
+}
+
+// NewProvider creates the provider of a kind. The none provider has no provider, and
+// photos are not analyzed.
+func NewProvider(provider, endpoint string, timeout time.Duration) (Provider, error) {
+	switch provider {
+	case ProviderNone, "":
+		return nil, nil
+	case ProviderHTTP:
+		return NewHTTPModelProvider(endpoint, timeout)
+	}
+	return nil, fmt.Errorf("unsupported image analysis provider: %s", provider)
+}
+
+// HTTPModelProvider labels photos with a self-hosted scene classifier. The image is
+// POSTed as the request body and the server answers with a JSON object of label
+// probabilities:
+//
+//	{"pool": 0.93, "facade": 0.41, "garden": 0.12}
+type HTTPModelProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPModelProvider creates a provider for a classifier endpoint
+func NewHTTPModelProvider(endpoint string, timeout time.Duration) (*HTTPModelProvider, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("image analysis endpoint is required")
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid image analysis endpoint: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &HTTPModelProvider{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name identifies the provider
+func (p *HTTPModelProvider) Name() string {
+	return "http-model"
+}
+
+// Detect sends the image to the classifier and returns the labels it found
+func (p *HTTPModelProvider) Detect(data []byte, contentType string) ([]domain.ImageLabel, error) {
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting image analysis model: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("image analysis model returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var scores map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&scores); err != nil {
+		return nil, fmt.Errorf("error decoding image analysis response: %w", err)
+	}
+
+	labels := make([]domain.ImageLabel, 0, len(scores))
+	for name, confidence := range scores {
+		labels = append(labels, domain.ImageLabel{Name: name, Confidence: confidence})
+	}
+	return labels, nil
+}
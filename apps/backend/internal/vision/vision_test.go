@@ -0,0 +1,49 @@
+package vision
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPModelProvider_Detect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "image-bytes", string(body))
+		assert.Equal(t, "image/jpeg", r.Header.Get("Content-Type"))
+		w.Write([]byte(`{"pool": 0.93, "facade": 0.41}`))
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPModelProvider("", time.Second)
+	assert.Error(t, err)
+
+	provider, err := NewHTTPModelProvider(server.URL, time.Second)
+	require.NoError(t, err)
+
+	labels, err := provider.Detect([]byte("image-bytes"), "image/jpeg")
+	require.NoError(t, err)
+	assert.Len(t, labels, 2)
+}
+
+func TestHTTPModelProvider_DetectError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model loading", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(ProviderHTTP, server.URL, time.Second)
+	require.NoError(t, err)
+
+	_, err = provider.Detect([]byte("image-bytes"), "image/jpeg")
+	assert.ErrorContains(t, err, "status 503")
+
+	none, err := NewProvider(ProviderNone, "", 0)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
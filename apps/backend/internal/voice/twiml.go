@@ -0,0 +1,119 @@
+package voice
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the signature of Twilio webhook requests
+const SignatureHeader = "X-Twilio-Signature"
+
+// Twilio call statuses reported to the Dial action
+const (
+	StatusCompleted = "completed"
+	StatusBusy      = "busy"
+	StatusNoAnswer  = "no-answer"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// language of the messages read to callers
+const sayLanguage = "es-MX"
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// IsE164 reports whether a phone number is in E.164 format, e.g. +593991234567
+func IsE164(number string) bool {
+	return e164Pattern.MatchString(number)
+}
+
+// Sign computes the signature Twilio sends with webhook requests: the base64
+// HMAC-SHA1, keyed with the auth token, of the request URL followed by each POST
+// parameter, sorted by name, as its name and value.
+func Sign(authToken, requestURL string, params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var payload strings.Builder
+	payload.WriteString(requestURL)
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			payload.WriteString(name)
+			payload.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(payload.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateSignature checks the signature of a Twilio webhook request
+func ValidateSignature(authToken, requestURL string, params url.Values, signature string) bool {
+	if authToken == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(authToken, requestURL, params)), []byte(signature))
+}
+
+type response struct {
+	XMLName xml.Name  `xml:"Response"`
+	Say     *say      `xml:"Say,omitempty"`
+	Dial    *dial     `xml:"Dial,omitempty"`
+	Hangup  *struct{} `xml:"Hangup,omitempty"`
+}
+
+type say struct {
+	Language string `xml:"language,attr"`
+	Text     string `xml:",chardata"`
+}
+
+type dial struct {
+	CallerID       string `xml:"callerId,attr"`
+	Action         string `xml:"action,attr,omitempty"`
+	TimeLimit      int    `xml:"timeLimit,attr,omitempty"`
+	AnswerOnBridge bool   `xml:"answerOnBridge,attr"`
+	Number         string `xml:"Number"`
+}
+
+// DialResponse answers a call by connecting it to number. The callee sees callerID,
+// the proxy number, and Twilio posts the outcome of the call to actionURL.
+func DialResponse(callerID, number, actionURL string, timeLimit time.Duration) ([]byte, error) {
+	return render(response{Dial: &dial{
+		CallerID:       callerID,
+		Action:         actionURL,
+		TimeLimit:      int(timeLimit.Seconds()),
+		AnswerOnBridge: true,
+		Number:         number,
+	}})
+}
+
+// RejectResponse reads a message to the caller and hangs up
+func RejectResponse(message string) ([]byte, error) {
+	return render(response{Say: &say{Language: sayLanguage, Text: message}, Hangup: &struct{}{}})
+}
+
+// EmptyResponse acknowledges a webhook without further instructions, ending the call
+func EmptyResponse() ([]byte, error) {
+	return render(response{})
+}
+
+func render(r response) ([]byte, error) {
+	body, err := xml.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,44 @@
+package voice
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSignature(t *testing.T) {
+	// Example from Twilio's webhook security documentation
+	requestURL := "https://mycompany.com/myapp.php?foo=1&bar=2"
+	params := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+
+	assert.True(t, ValidateSignature("12345", requestURL, params, "0/KCTR6DLpKmkAf8muzZqo1nDgQ="))
+	assert.False(t, ValidateSignature("12345", requestURL, params, "tampered="))
+	assert.False(t, ValidateSignature("", requestURL, params, "0/KCTR6DLpKmkAf8muzZqo1nDgQ="))
+
+	params.Set("Digits", "9999")
+	assert.False(t, ValidateSignature("12345", requestURL, params, "0/KCTR6DLpKmkAf8muzZqo1nDgQ="))
+}
+
+func TestResponses(t *testing.T) {
+	body, err := DialResponse("+593991110000", "+593987654321", "https://api.example.com/api/calls/status", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<Response><Dial callerId="+593991110000" action="https://api.example.com/api/calls/status" timeLimit="3600" answerOnBridge="true">`+
+		`<Number>+593987654321</Number></Dial></Response>`, string(body))
+
+	body, err = RejectResponse("Este número ya no está disponible & <expiró>")
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `<Say language="es-MX">Este número ya no está disponible &amp; &lt;expiró&gt;</Say><Hangup></Hangup>`)
+
+	assert.True(t, IsE164("+593991234567"))
+	assert.False(t, IsE164("0991234567"))
+}
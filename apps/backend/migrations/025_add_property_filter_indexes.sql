@@ -0,0 +1,27 @@
+-- Migration: Add indexes for combined property filtering
+-- Date: 2025-07-20
+-- Description: Supports GET /api/properties/filter, where location, type, size,
+--              amenity, parking, status and featured filters can be combined
+
+-- Location filters (province, city, sector)
+CREATE INDEX IF NOT EXISTS idx_properties_province_city_sector ON properties(province, city, sector);
+CREATE INDEX IF NOT EXISTS idx_properties_city ON properties(city);
+
+-- Type/status combinations with the most common range filters
+CREATE INDEX IF NOT EXISTS idx_properties_type_status_price ON properties(type, status, price);
+CREATE INDEX IF NOT EXISTS idx_properties_bedrooms_bathrooms ON properties(bedrooms, bathrooms);
+CREATE INDEX IF NOT EXISTS idx_properties_area_m2 ON properties(area_m2);
+CREATE INDEX IF NOT EXISTS idx_properties_parking_spaces ON properties(parking_spaces) WHERE parking_spaces > 0;
+
+-- Featured listings are a small subset, keep the index partial
+CREATE INDEX IF NOT EXISTS idx_properties_featured_created ON properties(created_at DESC) WHERE featured = true;
+
+-- Amenity flags: one composite index covers any prefix combination used in filters
+CREATE INDEX IF NOT EXISTS idx_properties_amenities ON properties(pool, garden, terrace, balcony, security, elevator, air_conditioning, garage, furnished);
+
+-- Tags are stored as JSONB arrays
+CREATE INDEX IF NOT EXISTS idx_properties_tags ON properties USING gin(tags);
+
+COMMENT ON INDEX idx_properties_province_city_sector IS 'Optimizes combined location filters';
+COMMENT ON INDEX idx_properties_type_status_price IS 'Optimizes type/status filters combined with price ranges';
+COMMENT ON INDEX idx_properties_amenities IS 'Optimizes amenity flag filters';
@@ -0,0 +1,39 @@
+-- Migration: Create search dictionary tables
+-- Date: 2025-07-22
+-- Description: Admin-managed synonyms and stop words applied to property search
+--              queries and pushed to index-time settings of external backends
+
+CREATE TABLE IF NOT EXISTS search_synonyms (
+    id UUID PRIMARY KEY,
+    term VARCHAR(100) NOT NULL UNIQUE,
+    canonical VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_search_synonyms_distinct CHECK (term <> canonical)
+);
+
+CREATE TABLE IF NOT EXISTS search_stop_words (
+    id UUID PRIMARY KEY,
+    word VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Common Ecuadorian real estate shorthand
+INSERT INTO search_synonyms (id, term, canonical) VALUES
+    (gen_random_uuid(), 'depa', 'departamento'),
+    (gen_random_uuid(), 'dpto', 'departamento'),
+    (gen_random_uuid(), 'suit', 'suite'),
+    (gen_random_uuid(), 'urbanización', 'urbanizacion'),
+    (gen_random_uuid(), 'urb', 'urbanizacion')
+ON CONFLICT (term) DO NOTHING;
+
+INSERT INTO search_stop_words (id, word) VALUES
+    (gen_random_uuid(), 'de'),
+    (gen_random_uuid(), 'en'),
+    (gen_random_uuid(), 'la'),
+    (gen_random_uuid(), 'el'),
+    (gen_random_uuid(), 'con')
+ON CONFLICT (word) DO NOTHING;
+
+COMMENT ON TABLE search_synonyms IS 'One-way search synonyms: queries for term also match canonical';
+COMMENT ON TABLE search_stop_words IS 'Words removed from search queries before matching';
@@ -0,0 +1,16 @@
+-- Migration: Create search query statistics table
+-- Date: 2025-07-24
+-- Description: Tracks how often each normalized search query is used so popular
+--              recent queries can be blended into autocomplete suggestions
+
+CREATE TABLE IF NOT EXISTS search_query_stats (
+    query VARCHAR(200) PRIMARY KEY,
+    search_count INTEGER NOT NULL DEFAULT 1 CHECK (search_count > 0),
+    first_searched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_searched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Popular queries are read by recency window ordered by count
+CREATE INDEX IF NOT EXISTS idx_search_query_stats_recent ON search_query_stats(last_searched_at DESC, search_count DESC);
+
+COMMENT ON TABLE search_query_stats IS 'Normalized search queries with usage counts for autocomplete';
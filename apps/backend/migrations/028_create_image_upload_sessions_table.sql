@@ -0,0 +1,24 @@
+-- Migration: Create image upload sessions table
+-- Date: 2025-07-26
+-- Description: Tracks images uploaded by clients directly to object storage with
+--              presigned URLs, from URL issue through confirmation and processing
+
+CREATE TABLE IF NOT EXISTS image_upload_sessions (
+    id VARCHAR(36) PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL UNIQUE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0 CHECK (size >= 0),
+    alt_text TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'expired')),
+    image_id VARCHAR(36) REFERENCES images(id) ON DELETE SET NULL,
+    error TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_upload_sessions_property ON image_upload_sessions(property_id);
+CREATE INDEX IF NOT EXISTS idx_image_upload_sessions_pending ON image_upload_sessions(expires_at) WHERE status = 'pending';
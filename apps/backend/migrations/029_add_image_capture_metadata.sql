@@ -0,0 +1,8 @@
+-- Migration: Add capture metadata to images
+-- Date: 2025-07-28
+-- Description: EXIF data (including GPS) is stripped from uploaded photos before
+--              storage; the capture date and camera are kept in these columns instead
+
+ALTER TABLE images ADD COLUMN IF NOT EXISTS captured_at TIMESTAMP;
+ALTER TABLE images ADD COLUMN IF NOT EXISTS camera_make VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE images ADD COLUMN IF NOT EXISTS camera_model VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,24 @@
+-- Migration: Create image moderation table
+-- Date: 2025-07-30
+-- Description: Stores moderation scores for uploaded images. Flagged images stay
+--              visible and wait in the admin review queue; quarantined images are
+--              hidden from property listings until an admin approves them
+
+CREATE TABLE IF NOT EXISTS image_moderation (
+    image_id VARCHAR(36) PRIMARY KEY REFERENCES images(id) ON DELETE CASCADE,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('approved', 'pending', 'flagged', 'quarantined')),
+    score NUMERIC(5,4) NOT NULL DEFAULT 0 CHECK (score >= 0 AND score <= 1),
+    labels JSONB NOT NULL DEFAULT '[]',
+    provider VARCHAR(50) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    reviewed_by VARCHAR(36) NOT NULL DEFAULT '',
+    review_note TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_moderation_queue ON image_moderation(status, score DESC)
+    WHERE status IN ('pending', 'flagged', 'quarantined');
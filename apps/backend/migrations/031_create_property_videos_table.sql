@@ -0,0 +1,24 @@
+-- Migration: Create property videos table
+-- Date: 2025-08-01
+-- Description: Video tours uploaded for properties and transcoded into HLS
+--              renditions with an MP4 fallback and a thumbnail
+
+CREATE TABLE IF NOT EXISTS property_videos (
+    id VARCHAR(36) PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0 CHECK (size >= 0),
+    duration_seconds NUMERIC(10,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'processing'
+        CHECK (status IN ('processing', 'ready', 'failed')),
+    renditions JSONB NOT NULL DEFAULT '[]',
+    thumbnail_url TEXT NOT NULL DEFAULT '',
+    storage_prefix TEXT NOT NULL,
+    stored_paths JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_videos_property ON property_videos(property_id, created_at);
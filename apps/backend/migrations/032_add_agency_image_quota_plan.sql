@@ -0,0 +1,6 @@
+-- Migration: Add image quota plan to agencies
+-- Date: 2025-08-03
+-- Description: Storage quota plan limiting images per property and total image
+--              storage of the properties managed by an agency
+
+ALTER TABLE agencies ADD COLUMN IF NOT EXISTS image_quota_plan VARCHAR(30) NOT NULL DEFAULT 'basic';
@@ -0,0 +1,27 @@
+-- Migration: Create agency invitations table
+-- Date: 2025-08-04
+-- Description: Email invitations for agents to join an agency. Only the SHA-256
+--              hash of the invitation token is stored; pending invitations past
+--              expires_at are reported as expired
+
+CREATE TABLE IF NOT EXISTS agency_invitations (
+    id VARCHAR(36) PRIMARY KEY,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'agent' CHECK (role IN ('agent')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'revoked', 'expired')),
+    invited_by VARCHAR(36) NOT NULL DEFAULT '',
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_agency_invitations_agency ON agency_invitations(agency_id, status);
+
+-- One open invitation per email and agency
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agency_invitations_pending_email
+    ON agency_invitations(agency_id, LOWER(email)) WHERE status = 'pending';
@@ -0,0 +1,19 @@
+-- Migration: Create audit log table
+-- Date: 2025-08-05
+-- Description: Records who performed sensitive changes, starting with agent
+--              assignments and listing transfers between agents
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id VARCHAR(36) PRIMARY KEY,
+    actor_id VARCHAR(36) NOT NULL DEFAULT '',
+    actor_role VARCHAR(20) NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    agency_id UUID,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_agency ON audit_log(agency_id, created_at DESC) WHERE agency_id IS NOT NULL;
@@ -0,0 +1,23 @@
+-- Migration: Create deals table
+-- Date: 2025-08-06
+-- Description: Closed sales and rentals with final price, closing date, agent and
+--              commission, aggregated into agency performance reports
+
+CREATE TABLE IF NOT EXISTS deals (
+    id VARCHAR(36) PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE RESTRICT,
+    agency_id UUID REFERENCES agencies(id) ON DELETE SET NULL,
+    agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('sale', 'rent')),
+    final_price NUMERIC(15,2) NOT NULL CHECK (final_price > 0),
+    closing_date TIMESTAMP NOT NULL,
+    commission_percent NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (commission_percent >= 0 AND commission_percent <= 100),
+    commission_amount NUMERIC(15,2) NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    recorded_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_deals_agency_closing ON deals(agency_id, closing_date DESC) WHERE agency_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_deals_agent_closing ON deals(agent_id, closing_date DESC) WHERE agent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_deals_property ON deals(property_id);
@@ -0,0 +1,12 @@
+-- Migration: Add subscription plan to agencies
+-- Date: 2025-08-07
+-- Description: Subscription plan (free/pro/premium) limiting active listings, featured
+--              slots and image storage of an agency
+
+ALTER TABLE agencies ADD COLUMN IF NOT EXISTS subscription_plan VARCHAR(20) NOT NULL DEFAULT 'free';
+ALTER TABLE agencies ADD COLUMN IF NOT EXISTS plan_changed_at TIMESTAMP;
+
+ALTER TABLE agencies ADD CONSTRAINT agencies_subscription_plan_check
+    CHECK (subscription_plan IN ('free', 'pro', 'premium'));
+
+CREATE INDEX IF NOT EXISTS idx_agencies_subscription_plan ON agencies(subscription_plan);
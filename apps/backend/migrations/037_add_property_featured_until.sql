@@ -0,0 +1,26 @@
+-- Migration: Add featured expiration to properties
+-- Date: 2025-08-08
+-- Description: Paid featured promotions end at featured_until; a scheduled job
+--              unfeatures expired listings. NULL keeps a listing featured indefinitely.
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS featured_until TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_properties_featured_until ON properties(featured_until)
+    WHERE featured = TRUE AND featured_until IS NOT NULL;
+
+-- Unfeaturing a listing ends its promotion so a later permanent featuring does not expire
+CREATE OR REPLACE FUNCTION clear_featured_until()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.featured = FALSE THEN
+        NEW.featured_until := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_clear_featured_until ON properties;
+CREATE TRIGGER trigger_clear_featured_until
+    BEFORE UPDATE OF featured ON properties
+    FOR EACH ROW
+    EXECUTE FUNCTION clear_featured_until();
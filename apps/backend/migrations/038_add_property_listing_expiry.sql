@@ -0,0 +1,17 @@
+-- Migration: Add listing expiration and renewal to properties
+-- Date: 2025-08-09
+-- Description: Available listings not renewed within their plan's expiry window are
+--              marked expired and hidden from public search until renewed
+
+ALTER TABLE properties DROP CONSTRAINT IF EXISTS properties_status_check;
+ALTER TABLE properties ADD CONSTRAINT properties_status_check
+    CHECK (status IN ('available', 'sold', 'rented', 'reserved', 'expired'));
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS renewed_at TIMESTAMP;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS expired_at TIMESTAMP;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS renewal_count INTEGER NOT NULL DEFAULT 0;
+
+-- Stale listing scan: available listings by publication date
+CREATE INDEX IF NOT EXISTS idx_properties_available_published
+    ON properties(COALESCE(renewed_at, created_at))
+    WHERE status = 'available';
@@ -0,0 +1,24 @@
+-- Migration: Create property documents table
+-- Date: 2025-08-10
+-- Description: Deeds, floor plans, contracts and other files attached to properties.
+--              Public documents are listed with the property; private ones only to
+--              whoever manages the listing
+
+CREATE TABLE IF NOT EXISTS property_documents (
+    id VARCHAR(36) PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL
+        CHECK (type IN ('deed', 'floor_plan', 'contract', 'other')),
+    visibility VARCHAR(10) NOT NULL DEFAULT 'private'
+        CHECK (visibility IN ('public', 'private')),
+    title VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0 CHECK (size >= 0),
+    url TEXT NOT NULL,
+    storage_path TEXT NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_documents_property ON property_documents(property_id, created_at);
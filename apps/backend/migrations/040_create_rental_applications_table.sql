@@ -0,0 +1,34 @@
+-- Migration: Create rental applications table
+-- Date: 2025-08-11
+-- Description: Applications of prospective tenants to rent listings, with their income,
+--              references and supporting documents, reviewed by the property's owner or agent
+
+CREATE TABLE IF NOT EXISTS rental_applications (
+    id VARCHAR(36) PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    applicant_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted'
+        CHECK (status IN ('submitted', 'under_review', 'accepted', 'rejected', 'withdrawn')),
+    monthly_income NUMERIC(12,2) NOT NULL CHECK (monthly_income > 0),
+    employer VARCHAR(255) NOT NULL DEFAULT '',
+    occupation VARCHAR(255) NOT NULL DEFAULT '',
+    occupants INTEGER NOT NULL DEFAULT 1 CHECK (occupants > 0),
+    has_pets BOOLEAN NOT NULL DEFAULT FALSE,
+    move_in_date DATE,
+    message TEXT NOT NULL DEFAULT '',
+    references_list JSONB NOT NULL DEFAULT '[]',
+    documents JSONB NOT NULL DEFAULT '[]',
+    decision_reason TEXT NOT NULL DEFAULT '',
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rental_applications_property ON rental_applications(property_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rental_applications_applicant ON rental_applications(applicant_id, created_at DESC);
+
+-- An applicant has at most one open application per property
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rental_applications_open
+    ON rental_applications(property_id, applicant_id)
+    WHERE status IN ('submitted', 'under_review');
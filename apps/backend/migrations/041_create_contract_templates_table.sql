@@ -0,0 +1,70 @@
+-- Migration: Create contract templates table
+-- Date: 2025-08-12
+-- Description: Versioned Go text/template contract templates managed by admins. Contracts
+--              generated from them are stored as private property documents that keep
+--              the template version they were generated from
+
+CREATE TABLE IF NOT EXISTS contract_templates (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('rental', 'sale_reservation')),
+    version INTEGER NOT NULL CHECK (version > 0),
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name, version)
+);
+
+-- One active version per template
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contract_templates_active
+    ON contract_templates(name) WHERE active;
+
+CREATE INDEX IF NOT EXISTS idx_contract_templates_type ON contract_templates(type, active);
+
+ALTER TABLE property_documents ADD COLUMN IF NOT EXISTS template_id VARCHAR(36)
+    REFERENCES contract_templates(id) ON DELETE SET NULL;
+
+INSERT INTO contract_templates (id, name, type, version, title, body, active) VALUES
+('5a0f3c52-8d2e-4b1a-9f47-2c6d1e8b7a01', 'arriendo-estandar', 'rental', 1, 'Contrato de arrendamiento', $$En la ciudad de {{.Property.City}}, a {{date .Date}}, comparecen:
+
+ARRENDADOR: {{.Owner.Name}}, con cédula {{.Owner.Cedula}}.
+ARRENDATARIO: {{.Tenant.Name}}, con cédula {{.Tenant.Cedula}}.
+
+PRIMERA - OBJETO: El arrendador da en arriendo al arrendatario el inmueble "{{.Property.Title}}", ubicado en {{deref .Property.Address}}, {{deref .Property.Sector}}, {{.Property.City}}, provincia de {{.Property.Province}}.
+
+SEGUNDA - PLAZO: El plazo del arriendo es de {{.Terms.DurationMonths}} meses contados desde el {{date .Terms.StartDate}}.
+
+TERCERA - CANON: El canon mensual es de {{money .Terms.MonthlyRent}}, pagadero por mes adelantado dentro de los cinco primeros días de cada mes.
+
+CUARTA - GARANTÍA: El arrendatario entrega en garantía la suma de {{money .Terms.Deposit}}, que será devuelta al término del contrato previa verificación del estado del inmueble.
+
+QUINTA - USO: El inmueble se destinará exclusivamente a vivienda y no podrá subarrendarse sin autorización escrita del arrendador.
+
+SEXTA - JURISDICCIÓN: Las partes se someten a la Ley de Inquilinato y a los jueces competentes de {{.Property.City}}.
+
+
+
+______________________________          ______________________________
+{{.Owner.Name}}                          {{.Tenant.Name}}
+ARRENDADOR                               ARRENDATARIO$$, TRUE),
+('5a0f3c52-8d2e-4b1a-9f47-2c6d1e8b7a02', 'reserva-compraventa', 'sale_reservation', 1, 'Contrato de reserva de compraventa', $$En la ciudad de {{.Property.City}}, a {{date .Date}}, comparecen:
+
+PROMITENTE VENDEDOR: {{.Owner.Name}}, con cédula {{.Owner.Cedula}}.
+PROMITENTE COMPRADOR: {{.Tenant.Name}}, con cédula {{.Tenant.Cedula}}.
+
+PRIMERA - OBJETO: El vendedor reserva a favor del comprador el inmueble "{{.Property.Title}}", ubicado en {{deref .Property.Address}}, {{deref .Property.Sector}}, {{.Property.City}}, provincia de {{.Property.Province}}.
+
+SEGUNDA - PRECIO: El precio de venta acordado es de {{money .Terms.SalePrice}}.
+
+TERCERA - RESERVA: El comprador entrega en este acto {{money .Terms.ReservationAmount}} en concepto de reserva, que se imputará al precio de venta.
+
+CUARTA - PLAZO: La escritura de compraventa se otorgará a más tardar el {{date .Terms.StartDate}}. Si el comprador desiste, perderá el valor de la reserva; si desiste el vendedor, lo devolverá duplicado.
+
+
+
+______________________________          ______________________________
+{{.Owner.Name}}                          {{.Tenant.Name}}
+VENDEDOR                                 COMPRADOR$$, TRUE)
+ON CONFLICT (id) DO NOTHING;
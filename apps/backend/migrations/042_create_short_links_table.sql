@@ -0,0 +1,25 @@
+-- Migration: Create short links tables
+-- Date: 2025-08-13
+-- Description: Short /l/{code} links for sharing properties, optionally tagged with a
+--              campaign, and the clicks received through them by source
+
+CREATE TABLE IF NOT EXISTS short_links (
+    id VARCHAR(36) PRIMARY KEY,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    campaign VARCHAR(50),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    click_count INTEGER NOT NULL DEFAULT 0 CHECK (click_count >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_links_property ON short_links(property_id, created_at);
+
+CREATE TABLE IF NOT EXISTS short_link_clicks (
+    id VARCHAR(36) PRIMARY KEY,
+    link_id VARCHAR(36) NOT NULL REFERENCES short_links(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL DEFAULT 'direct',
+    clicked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_short_link_clicks_link ON short_link_clicks(link_id, clicked_at);
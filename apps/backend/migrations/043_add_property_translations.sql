@@ -0,0 +1,45 @@
+-- Migration: Add property translations
+-- Date: 2025-08-14
+-- Description: Per-locale titles and descriptions of properties ({"en": {"title": ...,
+--              "description": ...}}) and an English full-text search vector so buyers
+--              can search in English. Spanish stays in title and description.
+
+ALTER TABLE properties
+    ADD COLUMN IF NOT EXISTS translations JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS search_vector_en tsvector;
+
+CREATE INDEX IF NOT EXISTS idx_properties_search_vector_en ON properties USING gin(search_vector_en);
+
+-- English vector: the English translation when there is one, the Spanish content otherwise,
+-- plus the location so place names match in both languages
+CREATE OR REPLACE FUNCTION update_property_search_vector_en()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector_en := to_tsvector('english',
+        COALESCE(NULLIF(NEW.translations->'en'->>'title', ''), NEW.title, '') || ' ' ||
+        COALESCE(NULLIF(NEW.translations->'en'->>'description', ''), NEW.description, '') || ' ' ||
+        COALESCE(NEW.province, '') || ' ' ||
+        COALESCE(NEW.city, '') || ' ' ||
+        COALESCE(NEW.sector, '')
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_property_search_vector_en ON properties;
+CREATE TRIGGER trigger_update_property_search_vector_en
+    BEFORE INSERT OR UPDATE ON properties
+    FOR EACH ROW
+    EXECUTE FUNCTION update_property_search_vector_en();
+
+UPDATE properties
+SET search_vector_en = to_tsvector('english',
+    COALESCE(title, '') || ' ' ||
+    COALESCE(description, '') || ' ' ||
+    COALESCE(province, '') || ' ' ||
+    COALESCE(city, '') || ' ' ||
+    COALESCE(sector, '')
+);
+
+COMMENT ON COLUMN properties.translations IS 'Titles and descriptions by locale other than Spanish';
+COMMENT ON COLUMN properties.search_vector_en IS 'English full-text search vector, maintained by trigger';
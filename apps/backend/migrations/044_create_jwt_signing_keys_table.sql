@@ -0,0 +1,22 @@
+-- Migration: Create JWT signing keys table
+-- Date: 2025-08-15
+-- Description: Versioned EdDSA/RS256 key pairs that sign access and refresh tokens.
+--              Private keys are sealed with JWT_KEY_ENCRYPTION_KEY; retired keys keep
+--              verifying tokens until expires_at and are then deleted.
+
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    id VARCHAR(36) PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL CHECK (algorithm IN ('EdDSA', 'RS256')),
+    public_key TEXT NOT NULL,
+    sealed_private_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    CHECK (retired_at IS NULL OR expires_at IS NOT NULL)
+);
+
+-- At most one key signs new tokens
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jwt_signing_keys_current
+    ON jwt_signing_keys((retired_at IS NULL)) WHERE retired_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jwt_signing_keys_expires ON jwt_signing_keys(expires_at);
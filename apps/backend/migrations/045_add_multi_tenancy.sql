@@ -0,0 +1,31 @@
+-- Migration: Add multi-tenancy
+-- Date: 2025-08-16
+-- Description: Tenants white-label the platform for separate real estate networks.
+--              Core tables get a tenant_id; existing rows and single-tenant installs
+--              belong to the 'default' tenant, so nothing changes until
+--              MULTI_TENANCY_ENABLED is set.
+
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(63) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    domains TEXT[] NOT NULL DEFAULT '{}',
+    branding JSONB NOT NULL DEFAULT '{}',
+    settings JSONB NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenants_domains ON tenants USING GIN(domains);
+
+INSERT INTO tenants (id, name, branding)
+VALUES ('default', 'Realty Core', '{"display_name": "Realty Core"}')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE agencies ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+CREATE INDEX IF NOT EXISTS idx_properties_tenant_status ON properties(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_users_tenant ON users(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_agencies_tenant ON agencies(tenant_id);
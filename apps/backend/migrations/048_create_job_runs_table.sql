@@ -0,0 +1,22 @@
+-- Migration: Create scheduled job run history
+-- Date: 2025-08-19
+-- Description: Every run of a scheduled or manually triggered job is recorded with the
+--              instance that ran it. Instances coordinate through advisory locks, so
+--              no lock table is needed.
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    triggered_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    instance VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    summary VARCHAR(1000) NOT NULL DEFAULT '',
+    error VARCHAR(1000) NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job ON job_runs(job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_job_runs_started ON job_runs(started_at DESC);
@@ -0,0 +1,20 @@
+-- Migration: Create property version history
+-- Date: 2025-08-20
+-- Description: Every property edit stores a numbered snapshot of the listing with the
+--              fields it changed and who changed them, so administrators can review
+--              the history of a listing and revert accidental edits.
+
+CREATE TABLE IF NOT EXISTS property_versions (
+    id UUID PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    change_type VARCHAR(20) NOT NULL CHECK (change_type IN ('create', 'edit', 'revert')),
+    snapshot JSONB NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reverted_from INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (property_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_versions_changed_by ON property_versions(changed_by);
@@ -0,0 +1,17 @@
+-- Migration: Create property drafts
+-- Date: 2025-08-21
+-- Description: Autosaved state of the listing creation wizard. Payloads are stored
+--              unvalidated until the draft is published as a property, and drafts not
+--              saved for 30 days are deleted by the draft-expiry job.
+
+CREATE TABLE IF NOT EXISTS property_drafts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_drafts_user ON property_drafts(user_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_property_drafts_updated ON property_drafts(updated_at);
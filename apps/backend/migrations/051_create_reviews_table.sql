@@ -0,0 +1,28 @@
+-- Migration: Create reviews and deal clients
+-- Date: 2025-08-22
+-- Description: Deals record their buyer or tenant, who can then review the agent that
+--              closed the deal. Reviews are published after moderation and shown on
+--              agent profiles.
+
+ALTER TABLE deals ADD COLUMN IF NOT EXISTS client_id UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_deals_client ON deals(client_id, agent_id) WHERE client_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY,
+    subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('agent')),
+    subject_id UUID NOT NULL,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    deal_id VARCHAR(36) REFERENCES deals(id) ON DELETE SET NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'rejected')),
+    moderation_note VARCHAR(500) NOT NULL DEFAULT '',
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (subject_type, subject_id, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_subject ON reviews(subject_type, subject_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status, created_at);
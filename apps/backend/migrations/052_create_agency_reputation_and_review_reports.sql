@@ -0,0 +1,38 @@
+-- Migration: Agency reviews, reputation scores and review reports
+-- Date: 2025-08-23
+-- Description: Agencies can be reviewed by clients of their deals and by applicants
+--              whose rental application they decided. The reputation score of a
+--              subject is recomputed whenever its reviews change, and users can
+--              report abusive reviews for administrators to resolve.
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_subject_type_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_subject_type_check CHECK (subject_type IN ('agent', 'agency'));
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS application_id VARCHAR(36) REFERENCES rental_applications(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS reputation_scores (
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id UUID NOT NULL,
+    review_count INTEGER NOT NULL DEFAULT 0,
+    average_rating NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    score NUMERIC(4, 1) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subject_type, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reputation_scores_rank ON reputation_scores(subject_type, score DESC);
+
+CREATE TABLE IF NOT EXISTS review_reports (
+    id UUID PRIMARY KEY,
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('spam', 'offensive', 'fake', 'personal_information', 'other')),
+    details VARCHAR(1000) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'upheld', 'dismissed')),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (review_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_reports_status ON review_reports(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_review_reports_open ON review_reports(review_id) WHERE status = 'open';
@@ -0,0 +1,26 @@
+-- Migration: Create spam cases
+-- Date: 2025-08-24
+-- Description: Inquiries and listings are scored for spam when submitted. High scoring
+--              submissions are quarantined: listings get the quarantined status and
+--              inquiries are hidden from landlords until an administrator approves
+--              or blocks them.
+
+ALTER TABLE properties DROP CONSTRAINT IF EXISTS properties_status_check;
+ALTER TABLE properties ADD CONSTRAINT properties_status_check
+    CHECK (status IN ('available', 'sold', 'rented', 'reserved', 'expired', 'quarantined'));
+
+CREATE TABLE IF NOT EXISTS spam_cases (
+    id UUID PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('inquiry', 'listing')),
+    entity_id VARCHAR(36) NOT NULL,
+    submitter_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 0 AND 100),
+    signals JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'quarantined' CHECK (status IN ('quarantined', 'approved', 'blocked')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_spam_cases_queue ON spam_cases(status, entity_type, created_at);
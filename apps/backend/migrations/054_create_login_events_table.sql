@@ -0,0 +1,22 @@
+-- Migration: Create login events
+-- Date: 2025-08-25
+-- Description: Every login records the session it started with the client IP and,
+--              when GeoIP is enabled, its country and city. Users are alerted when a
+--              login comes from a location they never logged in from.
+
+CREATE TABLE IF NOT EXISTS login_events (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_id VARCHAR(36) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    country_code CHAR(2) NOT NULL DEFAULT '',
+    country_name VARCHAR(100) NOT NULL DEFAULT '',
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    new_location BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_events_user ON login_events(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_events_location ON login_events(user_id, country_code, city);
+CREATE INDEX IF NOT EXISTS idx_login_events_session ON login_events(session_id);
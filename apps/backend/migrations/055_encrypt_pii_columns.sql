@@ -0,0 +1,27 @@
+-- Migration: Encrypt PII columns
+-- Date: 2025-08-26
+-- Description: Phone numbers and national IDs of users, deal notes and rental
+--              application messages are encrypted by the application with AES-GCM.
+--              Encrypted values ("enc:v1:<key id>:<payload>") are longer than the
+--              plaintext and no longer match the format checks, so the columns become
+--              TEXT. National IDs are looked up and kept unique through a keyed blind
+--              index. Existing rows are encrypted by the pii-reencryption job.
+
+-- Views selecting the columns would block the type change; none of them is used
+DROP VIEW IF EXISTS agent_profiles;
+DROP VIEW IF EXISTS users_with_agency;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_phone_format;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_national_id_format;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_national_id_valid;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_national_id_key;
+
+ALTER TABLE users ALTER COLUMN phone TYPE TEXT;
+ALTER TABLE users ALTER COLUMN national_id TYPE TEXT;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS national_id_index VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_national_id_index
+    ON users(national_id_index) WHERE national_id_index IS NOT NULL;
+
+COMMENT ON COLUMN users.national_id_index IS 'HMAC-SHA256 blind index of the encrypted national ID';
@@ -0,0 +1,23 @@
+-- Migration: Create sessions
+-- Date: 2025-08-26
+-- Description: Persisted refresh tokens. Each login starts a session whose ID is the
+--              ID (jti) of its refresh token; refreshing rotates the stored token hash.
+--              Users list their sessions by device and revoke them, which stops their
+--              refresh tokens and access tokens.
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash CHAR(64) NOT NULL,
+    device VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_active ON sessions(user_id, last_seen_at DESC)
+    WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_expires ON sessions(expires_at);
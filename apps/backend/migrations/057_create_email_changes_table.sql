@@ -0,0 +1,24 @@
+-- Migration: Create email changes
+-- Date: 2025-08-27
+-- Description: Changing the email of an account is requested with the password and
+--              confirmed from a link sent to the new address; the current address gets
+--              a link that cancels the change. Only token hashes are stored.
+
+CREATE TABLE IF NOT EXISTS email_changes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_email VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    confirm_token_hash CHAR(64) NOT NULL UNIQUE,
+    cancel_token_hash CHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'confirmed', 'cancelled', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user has at most one pending change
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_changes_pending_user
+    ON email_changes(user_id) WHERE status = 'pending';
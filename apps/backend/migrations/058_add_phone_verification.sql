@@ -0,0 +1,20 @@
+-- Migration: Add phone verification
+-- Date: 2025-08-27
+-- Description: Agents and owners verify their phone number with a code sent by SMS
+--              before publishing listings. Only code hashes are stored; changing the
+--              phone number clears the verification.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS phone_verifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    verified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_phone_verifications_user ON phone_verifications(user_id, created_at DESC);
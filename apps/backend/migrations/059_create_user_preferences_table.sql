@@ -0,0 +1,20 @@
+-- Migration: Create user preferences table
+-- Date: 2025-08-28
+-- Description: Preference center of each user: notification channels, email frequency,
+--              preferred locale and the default filters of their searches. Users
+--              without a row use defaults taken from their profile.
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    notify_email BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_sms BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_push BOOLEAN NOT NULL DEFAULT FALSE,
+    email_frequency VARCHAR(10) NOT NULL DEFAULT 'instant'
+        CHECK (email_frequency IN ('instant', 'daily', 'weekly', 'never')),
+    locale VARCHAR(5) NOT NULL DEFAULT 'es',
+    search_provinces TEXT[] NOT NULL DEFAULT '{}',
+    search_property_types TEXT[] NOT NULL DEFAULT '{}',
+    search_min_price DECIMAL(15,2),
+    search_max_price DECIMAL(15,2),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,18 @@
+-- Migration: Create property status changes table
+-- Date: 2025-08-29
+-- Description: History of listing status transitions (available, reserved, sold,
+--              rented) made through the status endpoint, for analytics and audit.
+
+CREATE TABLE IF NOT EXISTS property_status_changes (
+    id UUID PRIMARY KEY,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    final_price DECIMAL(15,2),
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by VARCHAR(36) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_status_changes_property ON property_status_changes(property_id, changed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_property_status_changes_to_status ON property_status_changes(to_status, changed_at);
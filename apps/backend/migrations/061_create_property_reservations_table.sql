@@ -0,0 +1,27 @@
+-- Migration: Create property reservations table
+-- Date: 2025-08-30
+-- Description: Reservations hold a listing as reserved for a lead during a number of
+--              days. Active reservations not converted into a sale or rental expire
+--              and return the listing to available; the agent is reminded first.
+
+CREATE TABLE IF NOT EXISTS property_reservations (
+    id UUID PRIMARY KEY,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    agency_id UUID,
+    agent_id UUID,
+    lead_name VARCHAR(255) NOT NULL,
+    lead_email VARCHAR(255) NOT NULL DEFAULT '',
+    lead_phone VARCHAR(20) NOT NULL DEFAULT '',
+    lead_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'converted', 'released', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    reminder_sent_at TIMESTAMP,
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_property_reservations_active ON property_reservations(property_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_property_reservations_expiry ON property_reservations(expires_at) WHERE status = 'active';
@@ -0,0 +1,20 @@
+-- Migration: Create inventory snapshots table
+-- Date: 2025-08-31
+-- Description: Weekly and monthly inventory figures of each agency, computed by a
+--              scheduled aggregation job so reports read them without scanning
+--              properties and deals.
+
+CREATE TABLE IF NOT EXISTS inventory_snapshots (
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('weekly', 'monthly')),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    active_listings INTEGER NOT NULL DEFAULT 0,
+    new_listings INTEGER NOT NULL DEFAULT 0,
+    sold_listings INTEGER NOT NULL DEFAULT 0,
+    rented_listings INTEGER NOT NULL DEFAULT 0,
+    avg_days_on_market DECIMAL(10,2) NOT NULL DEFAULT 0,
+    avg_discount_percent DECIMAL(6,2) NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (agency_id, period, period_start)
+);
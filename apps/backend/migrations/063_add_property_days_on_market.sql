@@ -0,0 +1,38 @@
+-- Migration: Add days on market to properties
+-- Date: 2025-09-01
+-- Description: Listings store how many days they were on the market, from publication
+--              to being sold, rented or expired. A trigger keeps it current for every
+--              status change; listings back on the market clear it.
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS days_on_market INTEGER;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+CREATE OR REPLACE FUNCTION track_days_on_market()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status IN ('sold', 'rented', 'expired') THEN
+        IF OLD.status IS DISTINCT FROM NEW.status THEN
+            NEW.closed_at := CURRENT_TIMESTAMP;
+            NEW.days_on_market := GREATEST(EXTRACT(DAY FROM NEW.closed_at - NEW.created_at)::INTEGER, 0);
+        END IF;
+    ELSE
+        NEW.closed_at := NULL;
+        NEW.days_on_market := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_track_days_on_market ON properties;
+CREATE TRIGGER trigger_track_days_on_market
+    BEFORE UPDATE OF status ON properties
+    FOR EACH ROW
+    EXECUTE FUNCTION track_days_on_market();
+
+-- Listings closed before this migration use their last update as closing date
+UPDATE properties
+SET closed_at = updated_at,
+    days_on_market = GREATEST(EXTRACT(DAY FROM updated_at - created_at)::INTEGER, 0)
+WHERE status IN ('sold', 'rented', 'expired') AND days_on_market IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_properties_closed_at ON properties(closed_at) WHERE closed_at IS NOT NULL;
@@ -0,0 +1,47 @@
+-- Migration: Create amenities catalog
+-- Date: 2025-09-02
+-- Description: Admin-managed catalog of amenities beyond the boolean amenity columns of
+--              properties (generator, cistern, pet friendly, BBQ area...) and the
+--              amenities of each property, with optional structured details such as a
+--              cistern's capacity.
+
+CREATE TABLE IF NOT EXISTS amenities (
+    code VARCHAR(40) PRIMARY KEY,
+    name_es VARCHAR(100) NOT NULL,
+    name_en VARCHAR(100) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('utilities', 'outdoor', 'building', 'interior', 'policy')),
+    icon VARCHAR(50) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS property_amenities (
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    amenity_code VARCHAR(40) NOT NULL REFERENCES amenities(code) ON UPDATE CASCADE,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (property_id, amenity_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_amenities_code ON property_amenities(amenity_code, property_id);
+
+INSERT INTO amenities (code, name_es, name_en, category, icon, sort_order) VALUES
+    ('generator', 'Generador eléctrico', 'Power generator', 'utilities', 'bolt', 10),
+    ('cistern', 'Cisterna', 'Water cistern', 'utilities', 'droplet', 20),
+    ('water_heater', 'Calefón o termotanque', 'Water heater', 'utilities', 'flame', 30),
+    ('solar_panels', 'Paneles solares', 'Solar panels', 'utilities', 'sun', 40),
+    ('fiber_internet', 'Internet por fibra', 'Fiber internet', 'utilities', 'wifi', 50),
+    ('bbq_area', 'Área de BBQ', 'BBQ area', 'outdoor', 'grill', 10),
+    ('playground', 'Área de juegos infantiles', 'Playground', 'outdoor', 'child', 20),
+    ('green_areas', 'Áreas verdes', 'Green areas', 'outdoor', 'tree', 30),
+    ('gym', 'Gimnasio', 'Gym', 'building', 'dumbbell', 10),
+    ('party_room', 'Salón comunal', 'Party room', 'building', 'users', 20),
+    ('guard_house', 'Guardianía 24/7', '24/7 guard house', 'building', 'shield', 30),
+    ('visitor_parking', 'Parqueadero de visitas', 'Visitor parking', 'building', 'car', 40),
+    ('laundry_area', 'Área de lavado', 'Laundry area', 'interior', 'shirt', 10),
+    ('storage_room', 'Bodega', 'Storage room', 'interior', 'box', 20),
+    ('fireplace', 'Chimenea', 'Fireplace', 'interior', 'fire', 30),
+    ('pet_friendly', 'Se aceptan mascotas', 'Pet friendly', 'policy', 'paw', 10)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,33 @@
+-- Migration: Create sector guides table
+-- Date: 2025-09-03
+-- Description: Editorial guides of the sectors of each city (description, photos,
+--              safety notes) with average prices of their listings, refreshed by a
+--              scheduled job. Published guides are linked from property responses.
+
+CREATE TABLE IF NOT EXISTS sector_guides (
+    id UUID PRIMARY KEY,
+    slug VARCHAR(120) NOT NULL UNIQUE,
+    province VARCHAR(100) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    sector VARCHAR(100) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    summary VARCHAR(500) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    photos JSONB NOT NULL DEFAULT '[]',
+    safety_notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published')),
+    active_listings INTEGER NOT NULL DEFAULT 0,
+    avg_sale_price DECIMAL(15,2) NOT NULL DEFAULT 0,
+    avg_price_per_m2 DECIMAL(12,2) NOT NULL DEFAULT 0,
+    avg_rent_price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    stats_updated_at TIMESTAMP,
+    created_by VARCHAR(36) NOT NULL DEFAULT '',
+    updated_by VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sector_guides_location
+    ON sector_guides(province, LOWER(city), LOWER(sector));
+CREATE INDEX IF NOT EXISTS idx_sector_guides_published ON sector_guides(province, city) WHERE status = 'published';
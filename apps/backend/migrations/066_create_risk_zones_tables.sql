@@ -0,0 +1,38 @@
+-- Migration: Create risk zone tables
+-- Date: 2025-09-04
+-- Description: Hazard zones imported from GeoJSON layers (flood, landslide, tsunami,
+--              volcanic) and the risk flags of the properties located inside them.
+--              Polygons are kept as GeoJSON coordinates with their bounding box, which
+--              narrows the zones checked for each property without PostGIS.
+
+CREATE TABLE IF NOT EXISTS risk_zones (
+    id UUID PRIMARY KEY,
+    layer VARCHAR(100) NOT NULL,
+    hazard VARCHAR(20) NOT NULL CHECK (hazard IN ('flood', 'landslide', 'tsunami', 'volcanic')),
+    level VARCHAR(10) NOT NULL CHECK (level IN ('low', 'medium', 'high')),
+    name VARCHAR(200) NOT NULL DEFAULT '',
+    source VARCHAR(200) NOT NULL DEFAULT '',
+    polygons JSONB NOT NULL,
+    min_lat DOUBLE PRECISION NOT NULL,
+    min_lng DOUBLE PRECISION NOT NULL,
+    max_lat DOUBLE PRECISION NOT NULL,
+    max_lng DOUBLE PRECISION NOT NULL,
+    imported_by UUID,
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_zones_layer ON risk_zones(layer);
+CREATE INDEX IF NOT EXISTS idx_risk_zones_bbox ON risk_zones(min_lat, max_lat, min_lng, max_lng);
+
+CREATE TABLE IF NOT EXISTS property_risk_flags (
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    hazard VARCHAR(20) NOT NULL,
+    level VARCHAR(10) NOT NULL,
+    zone_id UUID NOT NULL REFERENCES risk_zones(id) ON DELETE CASCADE,
+    zone_name VARCHAR(200) NOT NULL DEFAULT '',
+    source VARCHAR(200) NOT NULL DEFAULT '',
+    assessed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (property_id, hazard)
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_risk_flags_hazard ON property_risk_flags(hazard, property_id);
@@ -0,0 +1,28 @@
+-- Migration: Create property availability tables
+-- Date: 2025-09-05
+-- Description: Availability calendar of rental properties: the minimum stay of each
+--              property and its unavailable date ranges, either blocked by the listing's
+--              managers or booked by rental deals. End dates are exclusive (the
+--              check-out day is free for the next stay).
+
+CREATE TABLE IF NOT EXISTS property_availability_settings (
+    property_id UUID PRIMARY KEY REFERENCES properties(id) ON DELETE CASCADE,
+    min_stay_nights INTEGER NOT NULL DEFAULT 1 CHECK (min_stay_nights >= 1),
+    updated_by UUID,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS property_availability_blocks (
+    id UUID PRIMARY KEY,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('blocked', 'booked')),
+    deal_id UUID REFERENCES deals(id) ON DELETE CASCADE,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_blocks_property ON property_availability_blocks(property_id, start_date, end_date);
@@ -0,0 +1,36 @@
+-- Migration: Create maintenance request tables
+-- Date: 2025-09-06
+-- Description: Maintenance tickets opened by the tenants of rented properties, with
+--              their photos, and the status history of each ticket as the owner or
+--              agent works on it.
+
+CREATE TABLE IF NOT EXISTS maintenance_requests (
+    id UUID PRIMARY KEY,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('plumbing', 'electrical', 'appliance', 'structural', 'pest', 'other')),
+    priority VARCHAR(10) NOT NULL CHECK (priority IN ('low', 'normal', 'urgent')),
+    title VARCHAR(150) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('open', 'in_progress', 'resolved', 'closed', 'cancelled')),
+    photos JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_requests_property ON maintenance_requests(property_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_maintenance_requests_tenant ON maintenance_requests(tenant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS maintenance_request_updates (
+    id UUID PRIMARY KEY,
+    request_id UUID NOT NULL REFERENCES maintenance_requests(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    changed_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_request_updates_request ON maintenance_request_updates(request_id, created_at);
@@ -0,0 +1,27 @@
+-- Migration: Create rent ledger table
+-- Date: 2025-09-07
+-- Description: Monthly rent charges scheduled for the leases recorded as rent deals,
+--              with the manual or gateway-confirmed payment settling each one.
+
+CREATE TABLE IF NOT EXISTS rent_charges (
+    id UUID PRIMARY KEY,
+    deal_id UUID NOT NULL REFERENCES deals(id) ON DELETE CASCADE,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    tenant_id UUID,
+    period DATE NOT NULL,
+    amount NUMERIC(15,2) NOT NULL CHECK (amount > 0),
+    due_date DATE NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('pending', 'overdue', 'paid')),
+    paid_amount NUMERIC(15,2),
+    paid_at TIMESTAMP,
+    payment_method VARCHAR(10) CHECK (payment_method IN ('manual', 'gateway')),
+    payment_reference VARCHAR(100) NOT NULL DEFAULT '',
+    recorded_by UUID,
+    overdue_notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (deal_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rent_charges_due ON rent_charges(due_date) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_rent_charges_tenant ON rent_charges(tenant_id, due_date DESC) WHERE tenant_id IS NOT NULL;
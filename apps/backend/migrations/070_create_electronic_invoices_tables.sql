@@ -0,0 +1,44 @@
+-- Migration: Create electronic invoice tables
+-- Date: 2025-09-08
+-- Description: SRI electronic invoices issued to agencies for their subscription plans
+--              and featured listings, with their access keys and authorization numbers,
+--              and the sequential numbers of each emission point.
+
+CREATE TABLE IF NOT EXISTS einvoice_sequences (
+    establishment CHAR(3) NOT NULL,
+    emission_point CHAR(3) NOT NULL,
+    last_sequential INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (establishment, emission_point)
+);
+
+CREATE TABLE IF NOT EXISTS electronic_invoices (
+    id UUID PRIMARY KEY,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE RESTRICT,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('subscription', 'featured')),
+    source_ref VARCHAR(100) NOT NULL DEFAULT '',
+    establishment CHAR(3) NOT NULL,
+    emission_point CHAR(3) NOT NULL,
+    sequential INTEGER NOT NULL,
+    access_key CHAR(49) NOT NULL UNIQUE,
+    environment CHAR(1) NOT NULL CHECK (environment IN ('1', '2')),
+    issue_date DATE NOT NULL,
+    buyer JSONB NOT NULL,
+    lines JSONB NOT NULL,
+    subtotal NUMERIC(15,2) NOT NULL,
+    iva_rate NUMERIC(5,2) NOT NULL,
+    iva NUMERIC(15,2) NOT NULL,
+    total NUMERIC(15,2) NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('pending', 'received', 'authorized', 'rejected')),
+    authorization_number VARCHAR(49) NOT NULL DEFAULT '',
+    authorized_at TIMESTAMP,
+    messages JSONB NOT NULL DEFAULT '[]',
+    xml TEXT NOT NULL,
+    authorized_xml TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (establishment, emission_point, sequential)
+);
+
+CREATE INDEX IF NOT EXISTS idx_electronic_invoices_agency ON electronic_invoices(agency_id, issue_date DESC);
+CREATE INDEX IF NOT EXISTS idx_electronic_invoices_unauthorized ON electronic_invoices(created_at) WHERE status IN ('pending', 'received');
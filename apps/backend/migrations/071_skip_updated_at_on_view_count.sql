@@ -0,0 +1,12 @@
+-- Migration: Keep updated_at on view count updates
+-- Date: 2025-09-09
+-- Description: Property views bump view_count atomically on every read. A view is not an
+--              edit of the listing, so updates changing nothing but view_count no longer
+--              touch updated_at.
+
+DROP TRIGGER IF EXISTS trigger_properties_updated_at ON properties;
+CREATE TRIGGER trigger_properties_updated_at
+    BEFORE UPDATE ON properties
+    FOR EACH ROW
+    WHEN ((to_jsonb(OLD) - 'view_count' - 'updated_at') IS DISTINCT FROM (to_jsonb(NEW) - 'view_count' - 'updated_at'))
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,61 @@
+-- Migration: Notify property changes
+-- Date: 2025-09-09
+-- Description: Every statement changing properties sends a notification on the
+--              property_changes channel, so every API instance invalidates the
+--              properties it cached, whether the change came from another instance or
+--              from a script. Payloads are {"op": "update", "ids": [...]}, or
+--              {"op": "update", "all": true} for statements changing more than 100
+--              properties. View count updates are not changes of the listing and are
+--              not notified.
+
+CREATE OR REPLACE FUNCTION notify_property_changes()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed_ids TEXT[];
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        SELECT array_agg(id::text) INTO changed_ids FROM (SELECT id FROM new_rows LIMIT 101) changed;
+    ELSIF TG_OP = 'DELETE' THEN
+        SELECT array_agg(id::text) INTO changed_ids FROM (SELECT id FROM old_rows LIMIT 101) changed;
+    ELSE
+        SELECT array_agg(id::text) INTO changed_ids FROM (
+            SELECT n.id FROM new_rows n JOIN old_rows o ON o.id = n.id
+            WHERE (to_jsonb(n) - 'view_count' - 'updated_at') IS DISTINCT FROM (to_jsonb(o) - 'view_count' - 'updated_at')
+            LIMIT 101
+        ) changed;
+    END IF;
+
+    IF changed_ids IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF array_length(changed_ids, 1) > 100 THEN
+        PERFORM pg_notify('property_changes', json_build_object('op', lower(TG_OP), 'all', true)::text);
+    ELSE
+        PERFORM pg_notify('property_changes', json_build_object('op', lower(TG_OP), 'ids', changed_ids)::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Transition tables allow a single event per trigger
+DROP TRIGGER IF EXISTS trigger_notify_property_inserts ON properties;
+CREATE TRIGGER trigger_notify_property_inserts
+    AFTER INSERT ON properties
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION notify_property_changes();
+
+DROP TRIGGER IF EXISTS trigger_notify_property_updates ON properties;
+CREATE TRIGGER trigger_notify_property_updates
+    AFTER UPDATE ON properties
+    REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION notify_property_changes();
+
+DROP TRIGGER IF EXISTS trigger_notify_property_deletes ON properties;
+CREATE TRIGGER trigger_notify_property_deletes
+    AFTER DELETE ON properties
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION notify_property_changes();
@@ -0,0 +1,30 @@
+-- Migration: Create API key tables
+-- Date: 2025-09-10
+-- Description: Keys of the consumers of the public read-only API (/api/public/v1),
+--              such as partner portals and researchers, and the requests each key
+--              made per day. Only the SHA-256 hash of a key is stored; its prefix
+--              identifies it in listings. The tier sets the requests allowed per
+--              minute and per day.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    contact_email VARCHAR(255) NOT NULL,
+    agency_id UUID REFERENCES agencies(id) ON DELETE SET NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    tier VARCHAR(50) NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_agency ON api_keys(agency_id) WHERE agency_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, day)
+);
@@ -0,0 +1,11 @@
+-- Migration: Add usage metering to API keys
+-- Date: 2025-09-12
+-- Description: Meters the public API per key and day beyond the requests served:
+--              requests rejected over a quota, for abuse detection, and bytes sent
+--              in responses, for billing. Reports across keys read a range of days,
+--              per agency through api_keys.agency_id.
+
+ALTER TABLE api_key_usage ADD COLUMN IF NOT EXISTS rejected_requests BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE api_key_usage ADD COLUMN IF NOT EXISTS bytes_sent BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_api_key_usage_day ON api_key_usage(day);
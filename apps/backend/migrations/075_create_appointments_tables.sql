@@ -0,0 +1,34 @@
+-- Migration: Create appointment tables
+-- Date: 2025-09-13
+-- Description: Property visits and open houses on the agenda of each agent, and the
+--              secret token agents subscribe to their calendar feed with.
+
+CREATE TABLE IF NOT EXISTS appointments (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('visit', 'open_house')),
+    title VARCHAR(150) NOT NULL,
+    location TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    client_name VARCHAR(150) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL CHECK (ends_at > starts_at),
+    time_zone VARCHAR(40) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('scheduled', 'cancelled')),
+    sequence INTEGER NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointments_agent ON appointments(agent_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_property ON appointments(property_id, starts_at);
+
+-- Only the SHA-256 of the token is kept; rotating it replaces the row and breaks
+-- existing subscriptions
+CREATE TABLE IF NOT EXISTS agent_calendar_feeds (
+    agent_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,39 @@
+-- Migration: Create honeytoken tables
+-- Date: 2025-09-14
+-- Description: Synthetic "canary" listings planted in feeds and public API results,
+--              and the requests for their detail, which reveal catalog scraping and
+--              leaked feeds.
+
+-- The listing is a snapshot of the synthetic property; it is never a row of properties,
+-- so it cannot show up in the site's own searches
+CREATE TABLE IF NOT EXISTS honeytokens (
+    id UUID PRIMARY KEY,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('feed', 'public_api', 'manual')),
+    label VARCHAR(120) NOT NULL,
+    listing JSONB NOT NULL,
+    property_id UUID NOT NULL UNIQUE,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    api_key_id UUID REFERENCES api_keys(id) ON DELETE SET NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_honeytokens_active ON honeytokens(active, source);
+
+CREATE TABLE IF NOT EXISTS honeytoken_hits (
+    id UUID PRIMARY KEY,
+    honeytoken_id UUID NOT NULL REFERENCES honeytokens(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    client_ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    referer TEXT NOT NULL DEFAULT '',
+    fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    api_key_id VARCHAR(64) NOT NULL DEFAULT '',
+    user_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_honeytoken_hits_created ON honeytoken_hits(created_at);
+CREATE INDEX IF NOT EXISTS idx_honeytoken_hits_token ON honeytoken_hits(honeytoken_id, created_at);
@@ -0,0 +1,9 @@
+-- Migration: Add retention indexes
+-- Date: 2025-09-15
+-- Description: Indexes on the time the retention job measures the age of logs and events
+--              from, so counting and pruning expired rows does not scan whole tables.
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_login_events_created ON login_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created ON webhook_deliveries(created_at) WHERE status <> 'pending';
+CREATE INDEX IF NOT EXISTS idx_short_link_clicks_clicked ON short_link_clicks(clicked_at);
@@ -0,0 +1,23 @@
+-- Migration: Create backup verifications table
+-- Date: 2025-09-16
+-- Description: Results of restoring the latest database dump into a temporary schema
+--              and checking it, reported by /api/monitoring/backups.
+
+CREATE TABLE IF NOT EXISTS backup_verifications (
+    id UUID PRIMARY KEY,
+    dump_name TEXT NOT NULL,
+    dump_format VARCHAR(10) NOT NULL CHECK (dump_format IN ('plain', 'gzip', 'custom')),
+    dump_size_bytes BIGINT NOT NULL DEFAULT 0,
+    dump_modified_at TIMESTAMP NOT NULL,
+    schema_name VARCHAR(63) NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('passed', 'failed')),
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    restore_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    tables INTEGER NOT NULL DEFAULT 0,
+    rows_restored BIGINT NOT NULL DEFAULT 0,
+    checks JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_backup_verifications_started ON backup_verifications(started_at DESC);
@@ -0,0 +1,20 @@
+-- Migration: Create CSP violations table
+-- Date: 2025-09-17
+-- Description: Content-Security-Policy violations reported by browsers to
+--              /api/security/csp-report, counted by violation.
+
+CREATE TABLE IF NOT EXISTS csp_violations (
+    fingerprint VARCHAR(32) PRIMARY KEY,
+    document_uri TEXT NOT NULL DEFAULT '',
+    effective_directive VARCHAR(512) NOT NULL,
+    blocked_uri TEXT NOT NULL DEFAULT '',
+    source_file TEXT NOT NULL DEFAULT '',
+    line_number INTEGER NOT NULL DEFAULT 0,
+    disposition VARCHAR(10) NOT NULL CHECK (disposition IN ('enforce', 'report')),
+    count BIGINT NOT NULL DEFAULT 0,
+    user_agent TEXT NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_csp_violations_last_seen ON csp_violations(last_seen_at DESC);
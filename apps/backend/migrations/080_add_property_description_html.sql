@@ -0,0 +1,10 @@
+-- Migration: Add sanitized HTML descriptions to properties
+-- Date: 2025-09-18
+-- Description: Agents paste formatted descriptions. The description as written is kept
+--              in description_raw and never rendered, description_html holds it
+--              sanitized to allow-listed tags and description its plain text.
+--              Existing rows are sanitized when read and by the admin command
+--              sanitize-descriptions.
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS description_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS description_raw TEXT NOT NULL DEFAULT '';
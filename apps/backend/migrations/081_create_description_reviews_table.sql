@@ -0,0 +1,17 @@
+-- Migration: Create description reviews table
+-- Date: 2025-09-19
+-- Description: Listing descriptions in which the description policy found contact
+--              information or profanity, with the description as written, awaiting
+--              an administrator. Overridden reviews exempt the listing from the policy.
+
+CREATE TABLE IF NOT EXISTS description_reviews (
+    property_id UUID PRIMARY KEY REFERENCES properties(id) ON DELETE CASCADE,
+    findings JSONB NOT NULL DEFAULT '[]',
+    original TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'upheld', 'overridden')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_description_reviews_queue ON description_reviews(status, created_at);
@@ -0,0 +1,23 @@
+-- Migration: Create image analyses table
+-- Date: 2025-09-20
+-- Description: Queues uploaded photos for attribute extraction and stores the labels
+--              detected in them, from which amenities and the main image are
+--              suggested to agents. Pending analyses are processed in batches by the
+--              image-analysis job
+
+CREATE TABLE IF NOT EXISTS image_analyses (
+    image_id VARCHAR(36) PRIMARY KEY REFERENCES images(id) ON DELETE CASCADE,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'analyzed', 'failed')),
+    labels JSONB NOT NULL DEFAULT '[]',
+    provider VARCHAR(50) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    analyzed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_analyses_pending ON image_analyses(created_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_image_analyses_property ON image_analyses(property_id);
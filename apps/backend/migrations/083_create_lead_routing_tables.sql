@@ -0,0 +1,40 @@
+-- Migration: Create lead routing tables
+-- Date: 2025-09-21
+-- Description: Routing rules agencies use to hand inquiries on their listings to
+--              agents (round-robin, by sector or by the inquirer's language), and the
+--              score and assignment each routed inquiry got. next_agent is the
+--              round-robin cursor of a rule, advanced atomically per routed lead.
+
+CREATE TABLE IF NOT EXISTS lead_routing_rules (
+    id VARCHAR(36) PRIMARY KEY,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    strategy VARCHAR(20) NOT NULL CHECK (strategy IN ('round_robin', 'sector', 'language')),
+    priority INTEGER NOT NULL DEFAULT 0,
+    sectors TEXT[] NOT NULL DEFAULT '{}',
+    languages TEXT[] NOT NULL DEFAULT '{}',
+    agent_ids TEXT[] NOT NULL DEFAULT '{}',
+    min_score INTEGER NOT NULL DEFAULT 0 CHECK (min_score BETWEEN 0 AND 100),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_agent INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_routing_rules_agency ON lead_routing_rules(agency_id, priority);
+
+CREATE TABLE IF NOT EXISTS lead_assignments (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL UNIQUE REFERENCES rental_applications(id) ON DELETE CASCADE,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    rule_id VARCHAR(36) REFERENCES lead_routing_rules(id) ON DELETE SET NULL,
+    outcome VARCHAR(20) NOT NULL,
+    score INTEGER NOT NULL DEFAULT 0,
+    score_details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_agency ON lead_assignments(agency_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_agent ON lead_assignments(agent_id, created_at DESC);
@@ -0,0 +1,34 @@
+-- Migration: Create lead pipeline tables
+-- Date: 2025-09-22
+-- Description: Customizable CRM pipeline stages per agency, the current stage of each
+--              routed lead and the history of stage transitions with the time spent
+--              in each stage. Agencies without a row in lead_pipelines use the default
+--              pipeline (new, contacted, visit_scheduled, negotiating, won, lost).
+
+CREATE TABLE IF NOT EXISTS lead_pipelines (
+    agency_id UUID PRIMARY KEY REFERENCES agencies(id) ON DELETE CASCADE,
+    stages JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE lead_assignments ADD COLUMN IF NOT EXISTS stage VARCHAR(50) NOT NULL DEFAULT 'new';
+ALTER TABLE lead_assignments ADD COLUMN IF NOT EXISTS stage_entered_at TIMESTAMP;
+UPDATE lead_assignments SET stage_entered_at = created_at WHERE stage_entered_at IS NULL;
+ALTER TABLE lead_assignments ALTER COLUMN stage_entered_at SET NOT NULL;
+ALTER TABLE lead_assignments ALTER COLUMN stage_entered_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_stage ON lead_assignments(agency_id, stage);
+
+CREATE TABLE IF NOT EXISTS lead_stage_transitions (
+    id VARCHAR(36) PRIMARY KEY,
+    lead_id VARCHAR(36) NOT NULL REFERENCES lead_assignments(id) ON DELETE CASCADE,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    from_stage VARCHAR(50) NOT NULL,
+    to_stage VARCHAR(50) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    duration_seconds BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_stage_transitions_lead ON lead_stage_transitions(lead_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_lead_stage_transitions_agency ON lead_stage_transitions(agency_id, created_at);
@@ -0,0 +1,52 @@
+-- Migration: Create calendar sync tables
+-- Date: 2025-09-23
+-- Description: Google Calendar connections of agents with their OAuth tokens and
+--              incremental sync token, the events appointments were pushed as, and
+--              the busy times imported from the connected calendar to prevent
+--              double-booking. Tokens are encrypted like other PII columns when
+--              PII_ENCRYPTION_KEY is set.
+
+CREATE TABLE IF NOT EXISTS calendar_connections (
+    id UUID PRIMARY KEY,
+    agent_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('google')),
+    calendar_id VARCHAR(255) NOT NULL DEFAULT 'primary',
+    calendar_name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'active', 'revoked')),
+    access_token TEXT NOT NULL DEFAULT '',
+    refresh_token TEXT NOT NULL DEFAULT '',
+    token_expires_at TIMESTAMP WITH TIME ZONE,
+    sync_token TEXT NOT NULL DEFAULT '',
+    -- Only the SHA-256 of the OAuth state is kept while the agent grants access
+    state_hash VARCHAR(64) UNIQUE,
+    state_expires_at TIMESTAMP WITH TIME ZONE,
+    last_synced_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_connections_status ON calendar_connections(status);
+
+CREATE TABLE IF NOT EXISTS calendar_event_links (
+    connection_id UUID NOT NULL REFERENCES calendar_connections(id) ON DELETE CASCADE,
+    appointment_id UUID NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+    external_id VARCHAR(1024) NOT NULL,
+    etag VARCHAR(255) NOT NULL DEFAULT '',
+    sequence INTEGER NOT NULL,
+    pushed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (connection_id, appointment_id)
+);
+
+CREATE TABLE IF NOT EXISTS calendar_busy_blocks (
+    id UUID PRIMARY KEY,
+    connection_id UUID NOT NULL REFERENCES calendar_connections(id) ON DELETE CASCADE,
+    agent_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_id VARCHAR(1024) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL CHECK (ends_at > starts_at),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (connection_id, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_busy_blocks_agent ON calendar_busy_blocks(agent_id, starts_at);
@@ -0,0 +1,42 @@
+-- Migration: Create call masking tables
+-- Date: 2025-09-24
+-- Description: Proxy numbers lent to leads so buyers and agents can call each other
+--              without exposing their numbers, and the calls placed through them for
+--              the lead timeline. A proxy number is used by one active session per
+--              participant, so an incoming call is routed by the number called and
+--              the caller's number. Participant numbers are encrypted like other PII
+--              columns when PII_ENCRYPTION_KEY is set and looked up by blind index.
+
+CREATE TABLE IF NOT EXISTS call_sessions (
+    id VARCHAR(36) PRIMARY KEY,
+    lead_id VARCHAR(36) NOT NULL REFERENCES lead_assignments(id) ON DELETE CASCADE,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    proxy_number VARCHAR(20) NOT NULL,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    buyer_phone TEXT NOT NULL,
+    buyer_phone_index VARCHAR(64),
+    agent_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    agent_phone TEXT NOT NULL,
+    agent_phone_index VARCHAR(64),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'closed')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_call_sessions_active_lead ON call_sessions(lead_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_call_sessions_proxy ON call_sessions(proxy_number) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS lead_calls (
+    id VARCHAR(36) PRIMARY KEY,
+    session_id VARCHAR(36) NOT NULL REFERENCES call_sessions(id) ON DELETE CASCADE,
+    lead_id VARCHAR(36) NOT NULL REFERENCES lead_assignments(id) ON DELETE CASCADE,
+    call_sid VARCHAR(64) NOT NULL UNIQUE,
+    caller_role VARCHAR(10) NOT NULL CHECK (caller_role IN ('buyer', 'agent')),
+    status VARCHAR(20) NOT NULL,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_calls_lead ON lead_calls(lead_id, started_at);
@@ -0,0 +1,37 @@
+-- Migration: Create lead response SLA tables
+-- Date: 2025-09-25
+-- Description: How fast each agency expects its agents to answer new leads and when
+--              unanswered leads move to another agent, the first response to each
+--              lead (a masked call, a pipeline stage change or a review of the
+--              inquiry) and the reassignments of unanswered leads. Response times
+--              are counted from the lead's routing or its last reassignment.
+
+CREATE TABLE IF NOT EXISTS lead_response_policies (
+    agency_id UUID PRIMARY KEY REFERENCES agencies(id) ON DELETE CASCADE,
+    target_minutes INTEGER NOT NULL CHECK (target_minutes > 0),
+    reassign_after_minutes INTEGER NOT NULL DEFAULT 0 CHECK (reassign_after_minutes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS lead_responses (
+    lead_id VARCHAR(36) PRIMARY KEY REFERENCES lead_assignments(id) ON DELETE CASCADE,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    kind VARCHAR(30) NOT NULL,
+    responder_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    response_seconds BIGINT NOT NULL DEFAULT 0,
+    responded_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS lead_reassignments (
+    id VARCHAR(36) PRIMARY KEY,
+    lead_id VARCHAR(36) NOT NULL REFERENCES lead_assignments(id) ON DELETE CASCADE,
+    agency_id UUID NOT NULL REFERENCES agencies(id) ON DELETE CASCADE,
+    from_agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    to_agent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_reassignments_lead ON lead_reassignments(lead_id, created_at);
+-- Finds unanswered leads of agencies that reassign them
+CREATE INDEX IF NOT EXISTS idx_lead_assignments_agency_created ON lead_assignments(agency_id, created_at);
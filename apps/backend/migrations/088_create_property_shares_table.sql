@@ -0,0 +1,19 @@
+-- Migration: Create property shares table
+-- Date: 2025-09-26
+-- Description: Shares of properties from their page through WhatsApp, Facebook or a
+--              copied link, attributed to the signed-in user or to the browser
+--              session of anonymous visitors. Feeds the share counts of property
+--              analytics and the admin conversion funnel.
+
+CREATE TABLE IF NOT EXISTS property_shares (
+    id VARCHAR(36) PRIMARY KEY,
+    property_id VARCHAR(36) NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('whatsapp', 'facebook', 'link')),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    session_id VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (user_id IS NOT NULL OR session_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_shares_property ON property_shares(property_id, channel);
+CREATE INDEX IF NOT EXISTS idx_property_shares_created ON property_shares(created_at);
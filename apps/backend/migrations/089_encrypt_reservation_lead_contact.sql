@@ -0,0 +1,10 @@
+-- Migration: Encrypt the contact details of reservation leads
+-- Date: 2025-10-03
+-- Description: The email and phone of the lead a property is reserved for are
+--              encrypted by the application like other PII. Encrypted values are
+--              longer than the plaintext, so the columns become TEXT. They are never
+--              looked up by value, so they need no blind index. Existing rows are
+--              encrypted by the pii-reencryption job.
+
+ALTER TABLE property_reservations ALTER COLUMN lead_email TYPE TEXT;
+ALTER TABLE property_reservations ALTER COLUMN lead_phone TYPE TEXT;
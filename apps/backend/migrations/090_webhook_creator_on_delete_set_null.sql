@@ -0,0 +1,12 @@
+-- Migration: Keep webhook subscriptions when their creator is purged
+-- Date: 2025-10-05
+-- Description: created_by referenced users without an ON DELETE action, so purging a
+--              deactivated user who had created a webhook failed, and with it the
+--              whole purge batch. Subscriptions belong to their agency rather than to
+--              their creator, so they are kept and the creator is cleared.
+
+ALTER TABLE webhook_subscriptions ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE webhook_subscriptions DROP CONSTRAINT IF EXISTS webhook_subscriptions_created_by_fkey;
+ALTER TABLE webhook_subscriptions
+    ADD CONSTRAINT webhook_subscriptions_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
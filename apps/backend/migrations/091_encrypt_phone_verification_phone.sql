@@ -0,0 +1,12 @@
+-- Migration: Encrypt the phone of phone verifications
+-- Date: 2025-10-06
+-- Description: The phone number a verification code was sent to is encrypted by the
+--              application like the phone of its user. Encrypted values are longer
+--              than the plaintext, so the column becomes TEXT, and like other encrypted
+--              phone numbers it gets a blind index to be looked up by value. Existing
+--              rows are encrypted and indexed by the pii-reencryption job.
+
+ALTER TABLE phone_verifications ALTER COLUMN phone TYPE TEXT;
+ALTER TABLE phone_verifications ADD COLUMN IF NOT EXISTS phone_index VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_phone_verifications_phone_index ON phone_verifications(phone_index) WHERE phone_index IS NOT NULL;
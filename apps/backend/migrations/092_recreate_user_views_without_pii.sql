@@ -0,0 +1,61 @@
+-- Migration: Recreate the user views dropped to encrypt PII
+-- Date: 2025-10-06
+-- Description: 055 dropped agent_profiles and users_with_agency because they selected
+--              the phone and national ID columns it converted to TEXT. They are
+--              recreated without the encrypted columns, which would only show
+--              ciphertext, and users_with_agency lists its columns instead of u.* so
+--              the blind index and password hash are not exposed either.
+
+CREATE OR REPLACE VIEW agent_profiles AS
+SELECT
+    u.id,
+    u.first_name,
+    u.last_name,
+    (u.first_name || ' ' || u.last_name) as full_name,
+    u.email,
+    u.bio,
+    u.avatar_url,
+    u.active,
+    u.created_at,
+    rec.id as company_id,
+    rec.name as company_name,
+    rec.phone as company_phone,
+    rec.email as company_email,
+    rec.website as company_website,
+    rec.active as company_active
+FROM users u
+INNER JOIN real_estate_companies rec ON u.real_estate_company_id = rec.id
+WHERE u.user_type = 'agent' AND u.active = TRUE;
+
+CREATE OR REPLACE VIEW users_with_agency AS
+SELECT
+    u.id,
+    u.first_name,
+    u.last_name,
+    u.email,
+    u.date_of_birth,
+    u.user_type,
+    u.active,
+    u.min_budget,
+    u.max_budget,
+    u.preferred_provinces,
+    u.preferred_property_types,
+    u.avatar_url,
+    u.bio,
+    u.real_estate_company_id,
+    u.receive_notifications,
+    u.receive_newsletter,
+    u.agency_id,
+    u.phone_verified_at,
+    u.tenant_id,
+    u.created_at,
+    u.updated_at,
+    a.name as agency_name,
+    a.phone as agency_phone,
+    a.email as agency_email
+FROM users u
+LEFT JOIN agencies a ON u.agency_id = a.id
+WHERE u.active = TRUE;
+
+COMMENT ON VIEW agent_profiles IS 'Agent profiles with their company information, without encrypted PII';
+COMMENT ON VIEW users_with_agency IS 'Active users with their agency, without encrypted PII';
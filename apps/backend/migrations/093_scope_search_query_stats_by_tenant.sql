@@ -0,0 +1,13 @@
+-- Migration: Scope search query statistics by tenant
+-- Date: 2025-10-07
+-- Description: Popular queries are suggested to everyone searching the catalog, so the
+--              queries of one tenant must not be suggested in another. Statistics are
+--              counted per tenant; existing rows belong to the 'default' tenant.
+
+ALTER TABLE search_query_stats ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+ALTER TABLE search_query_stats DROP CONSTRAINT IF EXISTS search_query_stats_pkey;
+ALTER TABLE search_query_stats ADD PRIMARY KEY (tenant_id, query);
+
+DROP INDEX IF EXISTS idx_search_query_stats_recent;
+CREATE INDEX IF NOT EXISTS idx_search_query_stats_recent ON search_query_stats(tenant_id, last_searched_at DESC, search_count DESC);
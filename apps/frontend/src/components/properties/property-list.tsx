@@ -18,7 +18,7 @@ import {formatArea, formatDate, formatPrice} from '@/lib/utils';
 import {ApiResponse, Property} from '@shared/types/property';
 import {useMutation, useQuery, useQueryClient} from '@tanstack/react-query';
 import {Bath, Bed, Calendar, Car, Edit, Eye, ImageIcon, MapPin, Square, Star, Trash2} from 'lucide-react';
-import {useState} from 'react';
+import {useRef, useState} from 'react';
 
 interface PropertyListProps {
     searchTerm: string;
@@ -35,9 +35,23 @@ interface PropertyListProps {
     viewMode: 'grid' | 'list';
 }
 
+// A page of the catalog as returned by the paginated endpoints
+interface PropertyPage {
+    data: Property[];
+    pagination: {
+        current_page: number;
+        page_size: number;
+        total_pages: number;
+        total_records: number;
+    };
+}
+
 export function PropertyList({searchTerm, filters, viewMode}: PropertyListProps) {
     const [page, setPage] = useState(1);
     const [limit] = useState(12);
+    // Deep pages of the catalog must be requested with the token handed out with the
+    // page before them
+    const pageTokens = useRef(new Map<number, string>());
     const [selectedProperty, setSelectedProperty] = useState<Property | null>(null);
     const [isDeleteDialogOpen, setIsDeleteDialogOpen] = useState(false);
     const [isEditDialogOpen, setIsEditDialogOpen] = useState(false);
@@ -59,13 +73,21 @@ export function PropertyList({searchTerm, filters, viewMode}: PropertyListProps)
                 if (filters.bathrooms) params.append('bathrooms', filters.bathrooms);
                 if (filters.minPrice) params.append('min_price', filters.minPrice);
                 if (filters.maxPrice) params.append('max_price', filters.maxPrice);
+                params.append('page', String(page));
+                params.append('page_size', String(limit));
 
                 // Use filter endpoint for search, regular endpoint for list
                 const endpoint = searchTerm || Object.values(filters).some(Boolean)
                     ? `/api/properties/filter?${params.toString()}`
-                    : '/api/properties';
-
-                const response = await apiClient.get<ApiResponse<Property[]>>(endpoint);
+                    : `/api/properties?${params.toString()}`;
+
+                const pageToken = pageTokens.current.get(page);
+                const response = await apiClient.get<ApiResponse<PropertyPage>>(
+                    endpoint, undefined, pageToken ? {'X-Page-Token': pageToken} : undefined);
+                const nextPageToken = response.headers.get('X-Next-Page-Token');
+                if (nextPageToken) {
+                    pageTokens.current.set(page + 1, nextPageToken);
+                }
                 return response.data;
             } catch (error: any) {
                 console.error('Error fetching properties:', error);
@@ -81,7 +103,8 @@ export function PropertyList({searchTerm, filters, viewMode}: PropertyListProps)
         },
     });
 
-    const properties = propertiesResponse?.data || [];
+    const properties = propertiesResponse?.data;
+    const totalRecords = properties?.pagination?.total_records ?? 0;
 
     // Delete property mutation
     const deletePropertyMutation = useMutation({
@@ -533,7 +556,7 @@ export function PropertyList({searchTerm, filters, viewMode}: PropertyListProps)
     return (
         <div className="space-y-6">
             <div className={viewMode === 'grid' ? 'grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-6' : 'space-y-4'}>
-                {properties.data.map((property: Property) => (
+                {properties?.data.map((property: Property) => (
                     <div key={property.id}>
                         {viewMode === 'grid' ? (
                             <PropertyCard property={property}/>
@@ -545,7 +568,7 @@ export function PropertyList({searchTerm, filters, viewMode}: PropertyListProps)
             </div>
 
             {/* Pagination */}
-            {properties.total > limit && (
+            {totalRecords > limit && (
                 <div className="flex justify-center gap-2">
                     <Button
                         variant="outline"
@@ -555,12 +578,12 @@ export function PropertyList({searchTerm, filters, viewMode}: PropertyListProps)
                         Anterior
                     </Button>
                     <span className="flex items-center px-4 py-2 text-sm text-gray-600">
-            Página {page} de {Math.ceil(properties.total / limit)}
+            Página {page} de {Math.ceil(totalRecords / limit)}
           </span>
                     <Button
                         variant="outline"
                         onClick={() => setPage(page + 1)}
-                        disabled={page >= Math.ceil(properties.total / limit)}
+                        disabled={page >= Math.ceil(totalRecords / limit)}
                     >
                         Siguiente
                     </Button>
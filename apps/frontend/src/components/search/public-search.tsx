@@ -106,7 +106,9 @@ export function PublicSearch({
         
         setResults(mappedResults);
       } else {
-        // Fallback to filter endpoint
+        // Fallback to the first page of the filter endpoint
+        searchParams.append('page', '1');
+        searchParams.append('page_size', '20');
         response = await fetch(`${baseUrl}/api/properties/filter?${searchParams.toString()}`);
         
         if (!response.ok) {
@@ -120,8 +122,8 @@ export function PublicSearch({
 
         const filterData = await response.json();
         
-        if (filterData.success && filterData.data) {
-          const mappedResults = filterData.data.map((item: any) => ({
+        if (filterData.success && filterData.data?.data) {
+          const mappedResults = filterData.data.data.map((item: any) => ({
             ...item,
             created_at: item.created_at || new Date().toISOString(),
             main_image: item.main_image || undefined,
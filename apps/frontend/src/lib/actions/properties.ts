@@ -402,6 +402,9 @@ export async function getPropertiesAction(searchParams?: {
     if (searchParams?.minPrice) params.append('min_price', searchParams.minPrice);
     if (searchParams?.maxPrice) params.append('max_price', searchParams.maxPrice);
     if (searchParams?.province) params.append('province', searchParams.province);
+    // The catalog is served a page at a time
+    params.append('page', '1');
+    params.append('page_size', '50');
 
     const url = `${process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080'}/api/properties/filter?${params.toString()}`;
     
@@ -417,11 +420,12 @@ export async function getPropertiesAction(searchParams?: {
     }
 
     const result = await response.json();
-    console.log('✅ Properties fetched successfully:', result.data?.length || 0, 'properties');
+    const properties = result.data?.data || [];
+    console.log('✅ Properties fetched successfully:', properties.length, 'properties');
     
     return {
       success: true,
-      data: result.data || result,
+      data: properties,
     };
 
   } catch (error) {
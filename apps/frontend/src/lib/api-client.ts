@@ -241,8 +241,8 @@ class ApiClient {
   /**
    * GET request
    */
-  async get<T>(endpoint: string, signal?: AbortSignal): Promise<ApiResponse<T>> {
-    return this.request<T>(endpoint, { method: 'GET', signal });
+  async get<T>(endpoint: string, signal?: AbortSignal, headers?: Record<string, string>): Promise<ApiResponse<T>> {
+    return this.request<T>(endpoint, { method: 'GET', signal, headers });
   }
 
   /**